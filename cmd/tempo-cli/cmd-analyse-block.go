@@ -20,6 +20,7 @@ import (
 	"github.com/grafana/tempo/tempodb/encoding/vparquet2"
 	"github.com/grafana/tempo/tempodb/encoding/vparquet3"
 
+	"github.com/cespare/xxhash/v2"
 	"github.com/dustin/go-humanize"
 	"github.com/google/uuid"
 	"github.com/grafana/tempo/tempodb/backend"
@@ -105,15 +106,7 @@ func processBlock(r backend.Reader, tenantID, blockID string, maxStartTime, minS
 	if err != nil {
 		return nil, err
 	}
-	if meta.CompactionLevel < minCompactionLvl {
-		return nil, nil
-	}
-	if !maxStartTime.IsZero() && meta.StartTime.After(maxStartTime) {
-		// Block is newer than maxStartTime
-		return nil, nil
-	}
-	if !minStartTime.IsZero() && meta.StartTime.Before(minStartTime) {
-		// Block is older than minStartTime
+	if !includeBlock(meta, maxStartTime, minStartTime, minCompactionLvl) {
 		return nil, nil
 	}
 
@@ -124,7 +117,7 @@ func processBlock(r backend.Reader, tenantID, blockID string, maxStartTime, minS
 	case vparquet3.VersionString:
 		reader = vparquet3.NewBackendReaderAt(context.Background(), r, vparquet3.DataFileName, meta)
 	default:
-		fmt.Println("Unsupported block version:", meta.Version)
+		fmt.Fprintln(os.Stderr, "Unsupported block version:", meta.Version)
 		return nil, nil
 	}
 
@@ -135,7 +128,8 @@ func processBlock(r backend.Reader, tenantID, blockID string, maxStartTime, minS
 		return nil, err
 	}
 
-	fmt.Println("Scanning block contents.  Press CRTL+C to quit ...")
+	// progress goes to stderr so it doesn't mix with machine-readable output
+	fmt.Fprintln(os.Stderr, "Scanning block contents.  Press CRTL+C to quit ...")
 
 	// Aggregate span attributes
 	spanKey, spanVals := spanPathsForVersion(meta.Version)
@@ -143,6 +137,10 @@ func processBlock(r backend.Reader, tenantID, blockID string, maxStartTime, minS
 	if err != nil {
 		return nil, err
 	}
+	spanAttrsSummary.cardinality, err = attributeCardinality(pf, spanKey, spanVals[0])
+	if err != nil {
+		return nil, err
+	}
 
 	// add up dedicated span attribute columns
 	spanDedicatedSummary, err := aggregateDedicatedColumns(pf, backend.DedicatedColumnScopeSpan, meta)
@@ -153,6 +151,9 @@ func processBlock(r backend.Reader, tenantID, blockID string, maxStartTime, minS
 	for k, v := range spanDedicatedSummary.attributes {
 		spanAttrsSummary.attributes[k] = v
 	}
+	for k, v := range spanDedicatedSummary.cardinality {
+		spanAttrsSummary.cardinality[k] = v
+	}
 	spanAttrsSummary.totalBytes += spanDedicatedSummary.totalBytes
 
 	// Aggregate resource attributes
@@ -161,6 +162,10 @@ func processBlock(r backend.Reader, tenantID, blockID string, maxStartTime, minS
 	if err != nil {
 		return nil, err
 	}
+	resourceAttrsSummary.cardinality, err = attributeCardinality(pf, resourceKey, resourceVals[0])
+	if err != nil {
+		return nil, err
+	}
 
 	// add up dedicated resource attribute columns
 	resourceDedicatedSummary, err := aggregateDedicatedColumns(pf, backend.DedicatedColumnScopeResource, meta)
@@ -171,7 +176,10 @@ func processBlock(r backend.Reader, tenantID, blockID string, maxStartTime, minS
 	for k, v := range resourceDedicatedSummary.attributes {
 		resourceAttrsSummary.attributes[k] = v
 	}
-	resourceAttrsSummary.totalBytes += spanDedicatedSummary.totalBytes
+	for k, v := range resourceDedicatedSummary.cardinality {
+		resourceAttrsSummary.cardinality[k] = v
+	}
+	resourceAttrsSummary.totalBytes += resourceDedicatedSummary.totalBytes
 
 	return &blockSummary{
 		spanSummary:     spanAttrsSummary,
@@ -179,6 +187,22 @@ func processBlock(r backend.Reader, tenantID, blockID string, maxStartTime, minS
 	}, nil
 }
 
+// includeBlock returns true if the block matches the compaction level and start time filters.
+func includeBlock(meta *backend.BlockMeta, maxStartTime, minStartTime time.Time, minCompactionLvl uint8) bool {
+	if meta.CompactionLevel < minCompactionLvl {
+		return false
+	}
+	if !maxStartTime.IsZero() && meta.StartTime.After(maxStartTime) {
+		// Block is newer than maxStartTime
+		return false
+	}
+	if !minStartTime.IsZero() && meta.StartTime.Before(minStartTime) {
+		// Block is older than minStartTime
+		return false
+	}
+	return true
+}
+
 type blockSummary struct {
 	spanSummary, resourceSummary genericAttrSummary
 }
@@ -200,8 +224,45 @@ func (s *blockSummary) print(maxAttr int, generateJsonnet bool) error {
 }
 
 type genericAttrSummary struct {
-	totalBytes uint64
-	attributes map[string]uint64 // key: attribute name, value: total bytes
+	totalBytes  uint64
+	attributes  map[string]uint64         // key: attribute name, value: total bytes
+	cardinality map[string]distinctValues // key: attribute name, value: distinct string values
+}
+
+// merge adds the attributes of other to s. Sizes are summed and distinct values are combined.
+func (s *genericAttrSummary) merge(other genericAttrSummary) {
+	if s.attributes == nil {
+		s.attributes = make(map[string]uint64, len(other.attributes))
+	}
+	if s.cardinality == nil {
+		s.cardinality = make(map[string]distinctValues, len(other.cardinality))
+	}
+
+	s.totalBytes += other.totalBytes
+	for k, v := range other.attributes {
+		s.attributes[k] += v
+	}
+	for k, v := range other.cardinality {
+		if existing, ok := s.cardinality[k]; ok {
+			existing.merge(v)
+			continue
+		}
+		s.cardinality[k] = v
+	}
+}
+
+// distinctValues holds the hashes of all distinct values of an attribute. Hashes keep memory bounded for
+// long values and can be merged across blocks.
+type distinctValues map[uint64]struct{}
+
+func (d distinctValues) add(b []byte) {
+	d[xxhash.Sum64(b)] = struct{}{}
+}
+
+func (d distinctValues) merge(other distinctValues) {
+	for h := range other {
+		d[h] = struct{}{}
+	}
 }
 
 type attribute struct {
@@ -254,6 +315,7 @@ func aggregateAttributes(pf *parquet.File, keyPath string, valuePaths []string)
 
 func aggregateDedicatedColumns(pf *parquet.File, scope backend.DedicatedColumnScope, meta *backend.BlockMeta) (genericAttrSummary, error) {
 	attrMap := make(map[string]uint64)
+	cardinalityMap := make(map[string]distinctValues)
 	totalBytes := uint64(0)
 
 	i := 0
@@ -267,15 +329,22 @@ func aggregateDedicatedColumns(pf *parquet.File, scope backend.DedicatedColumnSc
 		if err != nil {
 			return genericAttrSummary{}, err
 		}
+
+		distinct, err := columnCardinality(pf, path)
+		if err != nil {
+			return genericAttrSummary{}, err
+		}
 		i++
 
 		attrMap["dedicated: "+dedColumn.Name] = sz
+		cardinalityMap["dedicated: "+dedColumn.Name] = distinct
 		totalBytes += sz
 	}
 
 	return genericAttrSummary{
-		totalBytes: totalBytes,
-		attributes: attrMap,
+		totalBytes:  totalBytes,
+		attributes:  attrMap,
+		cardinality: cardinalityMap,
 	}, nil
 }
 
@@ -307,6 +376,138 @@ func aggregateColumn(pf *parquet.File, colName string) (uint64, error) {
 	return totalBytes, nil
 }
 
+// attributeCardinality counts the distinct string values per attribute name. The key and value columns belong to
+// the same repeated group so they hold exactly one entry per attribute and can be read in lockstep.
+func attributeCardinality(pf *parquet.File, keyPath, valuePath string) (map[string]distinctValues, error) {
+	keyIdx, _ := pq.GetColumnIndexByPath(pf, keyPath)
+	valueIdx, _ := pq.GetColumnIndexByPath(pf, valuePath)
+
+	keys := newColumnValueIter(pf, keyIdx)
+	values := newColumnValueIter(pf, valueIdx)
+
+	cardinality := make(map[string]distinctValues)
+	for {
+		k, err := keys.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := values.next()
+		if err != nil {
+			return nil, fmt.Errorf("attribute key and value columns are not aligned: %w", err)
+		}
+
+		// null keys are empty attribute lists, null values are attributes that are not strings
+		if k.IsNull() || v.IsNull() {
+			continue
+		}
+
+		distinct, ok := cardinality[string(k.ByteArray())]
+		if !ok {
+			distinct = distinctValues{}
+			cardinality[string(k.ByteArray())] = distinct
+		}
+		distinct.add(v.ByteArray())
+	}
+
+	return cardinality, nil
+}
+
+// columnCardinality counts the distinct values of a single column.
+func columnCardinality(pf *parquet.File, path string) (distinctValues, error) {
+	idx, _ := pq.GetColumnIndexByPath(pf, path)
+	values := newColumnValueIter(pf, idx)
+
+	distinct := distinctValues{}
+	for {
+		v, err := values.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if !v.IsNull() {
+			distinct.add(v.ByteArray())
+		}
+	}
+
+	return distinct, nil
+}
+
+// columnValueIter iterates all values of a column across row groups and pages, including nulls.
+type columnValueIter struct {
+	rowGroups []parquet.RowGroup
+	column    int
+
+	nextRowGroup int
+	pages        parquet.Pages
+	values       parquet.ValueReader
+
+	buffer []parquet.Value
+	pos    int
+}
+
+func newColumnValueIter(pf *parquet.File, column int) *columnValueIter {
+	return &columnValueIter{
+		rowGroups: pf.RowGroups(),
+		column:    column,
+		buffer:    make([]parquet.Value, 0, 1024),
+	}
+}
+
+func (it *columnValueIter) next() (parquet.Value, error) {
+	for it.pos >= len(it.buffer) {
+		if err := it.fill(); err != nil {
+			return parquet.Value{}, err
+		}
+	}
+
+	v := it.buffer[it.pos]
+	it.pos++
+	return v, nil
+}
+
+// fill reads the next batch of values into the buffer. it returns io.EOF once all row groups are exhausted.
+func (it *columnValueIter) fill() error {
+	it.buffer, it.pos = it.buffer[:0], 0
+
+	switch {
+	case it.values != nil:
+		n, err := it.values.ReadValues(it.buffer[:cap(it.buffer)])
+		it.buffer = it.buffer[:n]
+		if errors.Is(err, io.EOF) {
+			it.values = nil
+			return nil
+		}
+		return err
+
+	case it.pages != nil:
+		page, err := it.pages.ReadPage()
+		if errors.Is(err, io.EOF) {
+			err = it.pages.Close()
+			it.pages = nil
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		it.values = page.Values()
+		return nil
+
+	case it.nextRowGroup < len(it.rowGroups):
+		it.pages = it.rowGroups[it.nextRowGroup].ColumnChunks()[it.column].Pages()
+		it.nextRowGroup++
+		return nil
+	}
+
+	return io.EOF
+}
+
 func printSummary(scope string, max int, summary genericAttrSummary) error {
 	// TODO: Support more output formats
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
@@ -319,7 +520,7 @@ func printSummary(scope string, max int, summary genericAttrSummary) error {
 	attrList := topN(max, summary.attributes)
 	for _, a := range attrList {
 		percentage := float64(a.bytes) / float64(summary.totalBytes) * 100
-		_, err := fmt.Fprintf(w, "name: %s\t size: %s\t (%s%%)\n", a.name, humanize.Bytes(a.bytes), strconv.FormatFloat(percentage, 'f', 2, 64))
+		_, err := fmt.Fprintf(w, "name: %s\t size: %s\t (%s%%)\t cardinality: %d\n", a.name, humanize.Bytes(a.bytes), strconv.FormatFloat(percentage, 'f', 2, 64), len(summary.cardinality[a.name]))
 		if err != nil {
 			return err
 		}
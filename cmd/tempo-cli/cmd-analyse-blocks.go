@@ -2,7 +2,14 @@ package main
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,6 +17,12 @@ import (
 	"github.com/grafana/tempo/tempodb/backend"
 )
 
+const (
+	analyseFormatText = "text"
+	analyseFormatCSV  = "csv"
+	analyseFormatJSON = "json"
+)
+
 type analyseBlocksCmd struct {
 	backendOptions
 
@@ -19,6 +32,8 @@ type analyseBlocksCmd struct {
 	NumAttr            int    `help:"Number of attributes to display" default:"15"`
 	MaxStartTime       string `help:"Oldest start time for a block to be processed. RFC3339 format '2006-01-02T15:04:05Z07:00'" default:""`
 	MinStartTime       string `help:"Newest start time for a block to be processed. RFC3339 format '2006-01-02T15:04:05Z07:00'" default:""`
+	Sample             bool   `help:"Sample max-blocks blocks evenly across the time range instead of analysing the first blocks found"`
+	Format             string `help:"Output format (text/csv/json)" enum:"text,csv,json" default:"text"`
 }
 
 func (cmd *analyseBlocksCmd) Run(ctx *globalOptions) error {
@@ -27,16 +42,6 @@ func (cmd *analyseBlocksCmd) Run(ctx *globalOptions) error {
 		return err
 	}
 
-	// TODO: Parallelize this
-	blocks, _, err := r.Blocks(context.Background(), cmd.TenantID)
-	if err != nil {
-		return err
-	}
-
-	processedBlocks := map[uuid.UUID]struct{}{}
-	topSpanAttrs, topResourceAttrs := make(map[string]uint64), make(map[string]uint64)
-	totalSpanBytes, totalResourceBytes := uint64(0), uint64(0)
-
 	var maxStartTime, minStartTime time.Time
 	if cmd.MaxStartTime != "" {
 		maxStartTime, err = time.Parse(time.RFC3339, cmd.MaxStartTime)
@@ -51,6 +56,22 @@ func (cmd *analyseBlocksCmd) Run(ctx *globalOptions) error {
 		}
 	}
 
+	// TODO: Parallelize this
+	blocks, _, err := r.Blocks(context.Background(), cmd.TenantID)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Sample {
+		blocks, err = sampleBlocks(r, cmd.TenantID, blocks, cmd.MaxBlocks, maxStartTime, minStartTime, uint8(cmd.MinCompactionLevel))
+		if err != nil {
+			return err
+		}
+	}
+
+	processedBlocks := map[uuid.UUID]struct{}{}
+	summary := &blockSummary{}
+
 	for i := 0; i < len(blocks) && len(processedBlocks) < cmd.MaxBlocks; i++ {
 		block := blocks[i]
 		if _, ok := processedBlocks[block]; ok {
@@ -63,6 +84,11 @@ func (cmd *analyseBlocksCmd) Run(ctx *globalOptions) error {
 				return err
 			}
 
+			// sampled blocks are fixed up front, skip blocks that were compacted in the meantime
+			if cmd.Sample {
+				continue
+			}
+
 			// the block was already compacted and blocks might be outdated: refreshing blocks
 			blocks, _, err = r.Blocks(context.Background(), cmd.TenantID)
 			if err != nil {
@@ -77,28 +103,144 @@ func (cmd *analyseBlocksCmd) Run(ctx *globalOptions) error {
 			continue
 		}
 
-		for k, v := range blockSum.spanSummary.attributes {
-			topSpanAttrs[k] += v
+		summary.spanSummary.merge(blockSum.spanSummary)
+		summary.resourceSummary.merge(blockSum.resourceSummary)
+
+		processedBlocks[block] = struct{}{}
+	}
+
+	switch cmd.Format {
+	case analyseFormatCSV:
+		return writeAnalyseCSV(os.Stdout, summary, cmd.NumAttr)
+	case analyseFormatJSON:
+		return writeAnalyseJSON(os.Stdout, summary, processedBlocks, cmd.NumAttr)
+	}
+
+	// Get top N attributes from map
+	return summary.print(cmd.NumAttr, false)
+}
+
+// sampleBlocks returns up to n blocks that pass the filters, spread evenly over the time range covered by the
+// blocks. Blocks are returned newest first.
+func sampleBlocks(r backend.Reader, tenantID string, blocks []uuid.UUID, n int, maxStartTime, minStartTime time.Time, minCompactionLvl uint8) ([]uuid.UUID, error) {
+	metas := make([]*backend.BlockMeta, 0, len(blocks))
+	for _, id := range blocks {
+		meta, err := r.BlockMeta(context.Background(), id, tenantID)
+		if err != nil {
+			if errors.Is(err, backend.ErrDoesNotExist) {
+				// the block was compacted
+				continue
+			}
+			return nil, err
+		}
+
+		if includeBlock(meta, maxStartTime, minStartTime, minCompactionLvl) {
+			metas = append(metas, meta)
 		}
-		totalSpanBytes += blockSum.spanSummary.totalBytes
+	}
 
-		for k, v := range blockSum.resourceSummary.attributes {
-			topResourceAttrs[k] += v
+	sort.Slice(metas, func(i, j int) bool {
+		return metas[i].StartTime.After(metas[j].StartTime)
+	})
+
+	if n <= 0 || n >= len(metas) {
+		sampled := make([]uuid.UUID, 0, len(metas))
+		for _, m := range metas {
+			sampled = append(sampled, m.BlockID)
 		}
-		totalResourceBytes += blockSum.resourceSummary.totalBytes
+		return sampled, nil
+	}
 
-		processedBlocks[block] = struct{}{}
+	sampled := make([]uuid.UUID, 0, n)
+	step := float64(len(metas)) / float64(n)
+	for i := 0; i < n; i++ {
+		sampled = append(sampled, metas[int(float64(i)*step)].BlockID)
 	}
+	return sampled, nil
+}
 
-	// Get top N attributes from map
-	return (&blockSummary{
-		spanSummary: genericAttrSummary{
-			totalBytes: totalSpanBytes,
-			attributes: topSpanAttrs,
-		},
-		resourceSummary: genericAttrSummary{
-			totalBytes: totalResourceBytes,
-			attributes: topResourceAttrs,
-		},
-	}).print(cmd.NumAttr, false)
+type attributeReport struct {
+	Scope       string  `json:"scope"`
+	Name        string  `json:"name"`
+	Bytes       uint64  `json:"bytes"`
+	Percentage  float64 `json:"percentage"`
+	Cardinality int     `json:"cardinality"`
+}
+
+type blocksReport struct {
+	BlockIDs           []string          `json:"blockIDs"`
+	TotalSpanBytes     uint64            `json:"totalSpanBytes"`
+	TotalResourceBytes uint64            `json:"totalResourceBytes"`
+	Attributes         []attributeReport `json:"attributes"`
+}
+
+// attributeReports returns the top n span and resource attributes by size.
+func attributeReports(s *blockSummary, n int) []attributeReport {
+	reports := make([]attributeReport, 0, 2*n)
+	for _, scope := range []struct {
+		name    string
+		summary genericAttrSummary
+	}{
+		{"span", s.spanSummary},
+		{"resource", s.resourceSummary},
+	} {
+		for _, a := range topN(n, scope.summary.attributes) {
+			var percentage float64
+			if scope.summary.totalBytes > 0 {
+				percentage = float64(a.bytes) / float64(scope.summary.totalBytes) * 100
+			}
+
+			reports = append(reports, attributeReport{
+				Scope:       scope.name,
+				Name:        a.name,
+				Bytes:       a.bytes,
+				Percentage:  percentage,
+				Cardinality: len(scope.summary.cardinality[a.name]),
+			})
+		}
+	}
+	return reports
+}
+
+func writeAnalyseCSV(w io.Writer, s *blockSummary, n int) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"scope", "name", "bytes", "percentage", "cardinality"}); err != nil {
+		return err
+	}
+	for _, a := range attributeReports(s, n) {
+		err := cw.Write([]string{
+			a.Scope,
+			a.Name,
+			strconv.FormatUint(a.Bytes, 10),
+			strconv.FormatFloat(a.Percentage, 'f', 2, 64),
+			strconv.Itoa(a.Cardinality),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeAnalyseJSON(w io.Writer, s *blockSummary, blocks map[uuid.UUID]struct{}, n int) error {
+	report := blocksReport{
+		BlockIDs:           make([]string, 0, len(blocks)),
+		TotalSpanBytes:     s.spanSummary.totalBytes,
+		TotalResourceBytes: s.resourceSummary.totalBytes,
+		Attributes:         attributeReports(s, n),
+	}
+	for id := range blocks {
+		report.BlockIDs = append(report.BlockIDs, id.String())
+	}
+	sort.Strings(report.BlockIDs)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	return nil
 }
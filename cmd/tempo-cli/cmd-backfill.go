@@ -0,0 +1,475 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/google/uuid"
+	jaeger "github.com/jaegertracing/jaeger/model"
+	jaegerjson "github.com/jaegertracing/jaeger/model/json"
+	ot_jaeger "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/jaeger"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+const (
+	backfillFormatAuto       = "auto"
+	backfillFormatOTLPProto  = "otlp-proto"
+	backfillFormatOTLPJSON   = "otlp-json"
+	backfillFormatJaegerJSON = "jaeger-json"
+)
+
+type backfillCmd struct {
+	backendOptions
+
+	TenantID string `arg:"" help:"tenant-id to write the blocks for"`
+	In       string `arg:"" help:"directory of trace dumps to import"`
+
+	Format            string `help:"format of the trace dumps. auto detects OTLP protobuf, OTLP JSON and Jaeger JSON per file" enum:"auto,otlp-proto,otlp-json,jaeger-json" default:"auto"`
+	Start             string `help:"only import traces ending at or after this time, in ISO8601 format"`
+	End               string `help:"only import traces starting before this time, in ISO8601 format"`
+	Version           string `help:"block version to write, defaults to the block version of the config file"`
+	MaxTracesPerBlock int    `help:"maximum number of traces per block" default:"100000"`
+}
+
+func (cmd *backfillCmd) Run(opts *globalOptions) error {
+	ctx := context.Background()
+
+	start, end, err := cmd.timeRange()
+	if err != nil {
+		return err
+	}
+	if cmd.MaxTracesPerBlock <= 0 {
+		return errors.New("max-traces-per-block must be greater than 0")
+	}
+
+	cfg, err := loadConfig(opts)
+	if err != nil {
+		return err
+	}
+	blockCfg := *cfg.StorageConfig.Trace.Block
+	if cmd.Version != "" {
+		blockCfg.Version = cmd.Version
+	}
+	enc, err := encoding.FromVersion(blockCfg.Version)
+	if err != nil {
+		return err
+	}
+
+	r, w, _, err := loadBackend(&cmd.backendOptions, opts)
+	if err != nil {
+		return err
+	}
+
+	traces := map[string]*trace.Combiner{}
+	err = filepath.WalkDir(cmd.In, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		tr, err := readTraceDump(path, cmd.Format)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+
+		split := splitByTraceID(tr)
+		for id, t := range split {
+			c := traces[id]
+			if c == nil {
+				c = trace.NewCombiner(0)
+				traces[id] = c
+			}
+			if _, err := c.Consume(t); err != nil {
+				return err
+			}
+		}
+
+		fmt.Printf("Read %d spans of %d traces from %s\n", spanCount(tr), len(split), path)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// blocks are written in trace ID order
+	ids := make([]string, 0, len(traces))
+	for id := range traces {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	iter := &backfillIterator{}
+	skipped := 0
+	for _, id := range ids {
+		tr, _ := traces[id].Result()
+		delete(traces, id)
+
+		traceStart, traceEnd := traceTimeRange(tr)
+		if (!start.IsZero() && traceEnd.Before(start)) || (!end.IsZero() && !traceStart.Before(end)) {
+			skipped++
+			continue
+		}
+
+		iter.ids = append(iter.ids, common.ID(id))
+		iter.traces = append(iter.traces, tr)
+		iter.start = append(iter.start, traceStart)
+		iter.end = append(iter.end, traceEnd)
+	}
+	fmt.Printf("Importing %d traces, skipped %d traces outside of the time range\n", len(iter.ids), skipped)
+
+	for len(iter.ids) > 0 {
+		n := min(len(iter.ids), cmd.MaxTracesPerBlock)
+		blockIter := &backfillIterator{
+			ids:    iter.ids[:n],
+			traces: iter.traces[:n],
+			start:  iter.start[:n],
+			end:    iter.end[:n],
+		}
+		iter.ids, iter.traces, iter.start, iter.end = iter.ids[n:], iter.traces[n:], iter.start[n:], iter.end[n:]
+
+		meta := backend.NewBlockMetaWithDedicatedColumns(cmd.TenantID, uuid.New(), blockCfg.Version, backend.EncNone, "", blockCfg.DedicatedColumns)
+		meta.TotalObjects = n
+		meta.StartTime, meta.EndTime = blockIter.timeRange()
+
+		outMeta, err := enc.CreateBlock(ctx, &blockCfg, meta, blockIter, r, w)
+		if err != nil {
+			return fmt.Errorf("creating block: %w", err)
+		}
+
+		fmt.Printf("Created block %s with %d traces from %s to %s, size %s\n", outMeta.BlockID, outMeta.TotalObjects,
+			outMeta.StartTime.Format(time.RFC3339), outMeta.EndTime.Format(time.RFC3339), humanize.Bytes(outMeta.Size))
+	}
+
+	return nil
+}
+
+func (cmd *backfillCmd) timeRange() (start, end time.Time, err error) {
+	if cmd.Start != "" {
+		start, err = time.Parse(time.RFC3339, cmd.Start)
+		if err != nil {
+			return start, end, fmt.Errorf("invalid start: %w", err)
+		}
+	}
+	if cmd.End != "" {
+		end, err = time.Parse(time.RFC3339, cmd.End)
+		if err != nil {
+			return start, end, fmt.Errorf("invalid end: %w", err)
+		}
+	}
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		return start, end, errors.New("start must be before end")
+	}
+	return start, end, nil
+}
+
+// readTraceDump reads the spans of a file. Files in JSON format can hold one document or a stream of documents,
+// like the output of the file exporter of the OpenTelemetry Collector.
+func readTraceDump(path, format string) (*tempopb.Trace, error) {
+	buff, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == backfillFormatAuto {
+		format = backfillFormatOTLPProto
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			format = backfillFormatOTLPJSON
+		}
+	}
+
+	if format == backfillFormatOTLPProto {
+		tr := &tempopb.Trace{}
+		if err := tr.Unmarshal(buff); err != nil {
+			return nil, err
+		}
+		return tr, nil
+	}
+
+	tr := &tempopb.Trace{}
+	dec := json.NewDecoder(bytes.NewReader(buff))
+	dec.UseNumber()
+	for {
+		var doc json.RawMessage
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var td ptrace.Traces
+		if format == backfillFormatJaegerJSON || isJaegerJSON(doc) {
+			td, err = jaegerJSONToTraces(doc)
+		} else {
+			td, err = (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(doc)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(td)
+		if err != nil {
+			return nil, err
+		}
+		t := &tempopb.Trace{}
+		if err := t.Unmarshal(b); err != nil {
+			return nil, err
+		}
+		tr.Batches = append(tr.Batches, t.Batches...)
+	}
+
+	return tr, nil
+}
+
+// jaegerDocument is the format of the api of the Jaeger query service, which is used to export traces from the
+// Jaeger UI.
+type jaegerDocument struct {
+	Data []jaegerjson.Trace `json:"data"`
+}
+
+func isJaegerJSON(doc json.RawMessage) bool {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &fields); err != nil {
+		return false
+	}
+	_, ok := fields["data"]
+	return ok
+}
+
+func jaegerJSONToTraces(doc json.RawMessage) (ptrace.Traces, error) {
+	var d jaegerDocument
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	if err := dec.Decode(&d); err != nil {
+		return ptrace.Traces{}, err
+	}
+
+	batch := &jaeger.Batch{}
+	for _, t := range d.Data {
+		for _, s := range t.Spans {
+			process := s.Process
+			if process == nil {
+				p, ok := t.Processes[s.ProcessID]
+				if !ok {
+					return ptrace.Traces{}, fmt.Errorf("span %s references unknown process %s", s.SpanID, s.ProcessID)
+				}
+				process = &p
+			}
+
+			span, err := jaegerJSONSpan(s, process)
+			if err != nil {
+				return ptrace.Traces{}, err
+			}
+			batch.Spans = append(batch.Spans, span)
+		}
+	}
+
+	return ot_jaeger.ProtoToTraces([]*jaeger.Batch{batch})
+}
+
+func jaegerJSONSpan(s jaegerjson.Span, p *jaegerjson.Process) (*jaeger.Span, error) {
+	traceID, err := jaeger.TraceIDFromString(string(s.TraceID))
+	if err != nil {
+		return nil, err
+	}
+	spanID, err := jaeger.SpanIDFromString(string(s.SpanID))
+	if err != nil {
+		return nil, err
+	}
+
+	span := &jaeger.Span{
+		TraceID:       traceID,
+		SpanID:        spanID,
+		OperationName: s.OperationName,
+		Flags:         jaeger.Flags(s.Flags),
+		StartTime:     time.UnixMicro(int64(s.StartTime)).UTC(),
+		Duration:      time.Duration(s.Duration) * time.Microsecond,
+	}
+
+	for _, ref := range s.References {
+		refTraceID, err := jaeger.TraceIDFromString(string(ref.TraceID))
+		if err != nil {
+			return nil, err
+		}
+		refSpanID, err := jaeger.SpanIDFromString(string(ref.SpanID))
+		if err != nil {
+			return nil, err
+		}
+
+		refType := jaeger.SpanRefType_CHILD_OF
+		if ref.RefType == jaegerjson.FollowsFrom {
+			refType = jaeger.SpanRefType_FOLLOWS_FROM
+		}
+		span.References = append(span.References, jaeger.SpanRef{TraceID: refTraceID, SpanID: refSpanID, RefType: refType})
+	}
+	if len(span.References) == 0 && s.ParentSpanID != "" {
+		parentSpanID, err := jaeger.SpanIDFromString(string(s.ParentSpanID))
+		if err != nil {
+			return nil, err
+		}
+		span.References = append(span.References, jaeger.NewChildOfRef(traceID, parentSpanID))
+	}
+
+	if span.Tags, err = jaegerJSONKeyValues(s.Tags); err != nil {
+		return nil, err
+	}
+	for _, l := range s.Logs {
+		fields, err := jaegerJSONKeyValues(l.Fields)
+		if err != nil {
+			return nil, err
+		}
+		span.Logs = append(span.Logs, jaeger.Log{Timestamp: time.UnixMicro(int64(l.Timestamp)).UTC(), Fields: fields})
+	}
+
+	span.Process = &jaeger.Process{ServiceName: p.ServiceName}
+	if span.Process.Tags, err = jaegerJSONKeyValues(p.Tags); err != nil {
+		return nil, err
+	}
+
+	return span, nil
+}
+
+func jaegerJSONKeyValues(kvs []jaegerjson.KeyValue) ([]jaeger.KeyValue, error) {
+	res := make([]jaeger.KeyValue, 0, len(kvs))
+	for _, kv := range kvs {
+		var err error
+		switch kv.Type {
+		case jaegerjson.BoolType:
+			v, ok := kv.Value.(bool)
+			if !ok {
+				err = fmt.Errorf("invalid bool value of tag %s", kv.Key)
+			}
+			res = append(res, jaeger.Bool(kv.Key, v))
+		case jaegerjson.Int64Type:
+			var v int64
+			v, err = json.Number(fmt.Sprint(kv.Value)).Int64()
+			res = append(res, jaeger.Int64(kv.Key, v))
+		case jaegerjson.Float64Type:
+			var v float64
+			v, err = json.Number(fmt.Sprint(kv.Value)).Float64()
+			res = append(res, jaeger.Float64(kv.Key, v))
+		case jaegerjson.BinaryType:
+			var v []byte
+			v, err = base64.StdEncoding.DecodeString(fmt.Sprint(kv.Value))
+			res = append(res, jaeger.Binary(kv.Key, v))
+		default:
+			res = append(res, jaeger.String(kv.Key, fmt.Sprint(kv.Value)))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("tag %s: %w", kv.Key, err)
+		}
+	}
+	return res, nil
+}
+
+// splitByTraceID splits the spans of the dump into traces. Resources and scopes are shared between the traces.
+func splitByTraceID(tr *tempopb.Trace) map[string]*tempopb.Trace {
+	traces := map[string]*tempopb.Trace{}
+	for _, rs := range tr.Batches {
+		resourceSpans := map[string]*v1.ResourceSpans{}
+		for _, ss := range rs.ScopeSpans {
+			scopeSpans := map[string]*v1.ScopeSpans{}
+			for _, s := range ss.Spans {
+				id := string(s.TraceId)
+
+				scope := scopeSpans[id]
+				if scope == nil {
+					resource := resourceSpans[id]
+					if resource == nil {
+						resource = &v1.ResourceSpans{Resource: rs.Resource, SchemaUrl: rs.SchemaUrl}
+						resourceSpans[id] = resource
+
+						t := traces[id]
+						if t == nil {
+							t = &tempopb.Trace{}
+							traces[id] = t
+						}
+						t.Batches = append(t.Batches, resource)
+					}
+
+					scope = &v1.ScopeSpans{Scope: ss.Scope, SchemaUrl: ss.SchemaUrl}
+					scopeSpans[id] = scope
+					resource.ScopeSpans = append(resource.ScopeSpans, scope)
+				}
+				scope.Spans = append(scope.Spans, s)
+			}
+		}
+	}
+	return traces
+}
+
+func spanCount(tr *tempopb.Trace) int {
+	n := 0
+	for _, rs := range tr.Batches {
+		for _, ss := range rs.ScopeSpans {
+			n += len(ss.Spans)
+		}
+	}
+	return n
+}
+
+func traceTimeRange(tr *tempopb.Trace) (start, end time.Time) {
+	var minStart, maxEnd uint64
+	for _, rs := range tr.Batches {
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				if minStart == 0 || s.StartTimeUnixNano < minStart {
+					minStart = s.StartTimeUnixNano
+				}
+				if s.EndTimeUnixNano > maxEnd {
+					maxEnd = s.EndTimeUnixNano
+				}
+			}
+		}
+	}
+	return time.Unix(0, int64(minStart)), time.Unix(0, int64(maxEnd))
+}
+
+// backfillIterator iterates the traces of a block, sorted by ID.
+type backfillIterator struct {
+	ids    []common.ID
+	traces []*tempopb.Trace
+	start  []time.Time
+	end    []time.Time
+	i      int
+}
+
+func (i *backfillIterator) Next(context.Context) (common.ID, *tempopb.Trace, error) {
+	if i.i >= len(i.ids) {
+		return nil, nil, io.EOF
+	}
+	i.i++
+	return i.ids[i.i-1], i.traces[i.i-1], nil
+}
+
+func (i *backfillIterator) Close() {}
+
+func (i *backfillIterator) timeRange() (start, end time.Time) {
+	for j := range i.ids {
+		if start.IsZero() || i.start[j].Before(start) {
+			start = i.start[j]
+		}
+		if i.end[j].After(end) {
+			end = i.end[j]
+		}
+	}
+	return start, end
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/pkg/boundedwaitgroup"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+type listTenantIndexCmd struct {
+	backendOptions
+
+	TenantID string `arg:"" help:"tenant-id within the bucket"`
+	Diff     bool   `help:"compare the tenant index against a listing of the bucket and print the discrepancies"`
+	Rebuild  bool   `help:"rewrite the tenant index from the listing of the bucket if they differ"`
+}
+
+func (cmd *listTenantIndexCmd) Run(ctx *globalOptions) error {
+	r, w, c, err := loadBackend(&cmd.backendOptions, ctx)
+	if err != nil {
+		return err
+	}
+
+	index, err := r.TenantIndex(context.Background(), cmd.TenantID)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		fmt.Println("tenant index does not exist")
+		index = nil
+	} else if err != nil {
+		return err
+	} else {
+		fmt.Println("created at:", index.CreatedAt)
+		fmt.Println("blocks:", len(index.Meta))
+		fmt.Println("compacted blocks:", len(index.CompactedMeta))
+	}
+
+	if !cmd.Diff && !cmd.Rebuild {
+		return nil
+	}
+
+	metas, compactedMetas, err := listTenantBlocks(r, c, cmd.TenantID)
+	if err != nil {
+		return err
+	}
+
+	diff := diffTenantIndex(index, metas, compactedMetas)
+	diff.print()
+
+	if !cmd.Rebuild {
+		return nil
+	}
+	if diff.empty() && index != nil {
+		fmt.Println("tenant index is up to date, not rewriting it")
+		return nil
+	}
+
+	// the index is a single object, it's replaced at once
+	err = w.WriteTenantIndex(context.Background(), cmd.TenantID, metas, compactedMetas)
+	if err != nil {
+		return fmt.Errorf("failed to write tenant index: %w", err)
+	}
+
+	fmt.Println("rewrote tenant index with", len(metas), "blocks and", len(compactedMetas), "compacted blocks")
+	return nil
+}
+
+// listTenantBlocks reads the metas of all blocks of the tenant in the bucket
+func listTenantBlocks(r backend.Reader, c backend.Compactor, tenantID string) ([]*backend.BlockMeta, []*backend.CompactedBlockMeta, error) {
+	blockIDs, compactedBlockIDs, err := r.Blocks(context.Background(), tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		mtx            sync.Mutex
+		errs           []error
+		metas          = make([]*backend.BlockMeta, 0, len(blockIDs))
+		compactedMetas = make([]*backend.CompactedBlockMeta, 0, len(compactedBlockIDs))
+		wg             = boundedwaitgroup.New(20)
+	)
+
+	for _, id := range blockIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			meta, err := r.BlockMeta(context.Background(), id, tenantID)
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			switch {
+			case errors.Is(err, backend.ErrDoesNotExist):
+				// the block was compacted or deleted since the listing
+			case err != nil:
+				errs = append(errs, fmt.Errorf("failed to read meta of block %s: %w", id, err))
+			default:
+				metas = append(metas, meta)
+			}
+		}()
+	}
+
+	for _, id := range compactedBlockIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			meta, err := c.CompactedBlockMeta(id, tenantID)
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			switch {
+			case errors.Is(err, backend.ErrDoesNotExist):
+			case err != nil:
+				errs = append(errs, fmt.Errorf("failed to read compacted meta of block %s: %w", id, err))
+			default:
+				compactedMetas = append(compactedMetas, meta)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	sort.Slice(metas, func(i, j int) bool { return metas[i].StartTime.Before(metas[j].StartTime) })
+	sort.Slice(compactedMetas, func(i, j int) bool { return compactedMetas[i].StartTime.Before(compactedMetas[j].StartTime) })
+
+	return metas, compactedMetas, nil
+}
+
+// tenantIndexDiff are the discrepancies between a tenant index and the bucket
+type tenantIndexDiff struct {
+	// blocks in the bucket that are missing from the index
+	missing []uuid.UUID
+	// blocks in the index that are no longer in the bucket
+	stale []uuid.UUID
+	// blocks that are compacted in the bucket but live in the index, and the other way around
+	compactedInBucket []uuid.UUID
+	compactedInIndex  []uuid.UUID
+}
+
+func diffTenantIndex(index *backend.TenantIndex, metas []*backend.BlockMeta, compactedMetas []*backend.CompactedBlockMeta) tenantIndexDiff {
+	indexLive := map[uuid.UUID]struct{}{}
+	indexCompacted := map[uuid.UUID]struct{}{}
+	if index != nil {
+		for _, m := range index.Meta {
+			indexLive[m.BlockID] = struct{}{}
+		}
+		for _, m := range index.CompactedMeta {
+			indexCompacted[m.BlockID] = struct{}{}
+		}
+	}
+
+	bucketLive := map[uuid.UUID]struct{}{}
+	bucketCompacted := map[uuid.UUID]struct{}{}
+	for _, m := range metas {
+		bucketLive[m.BlockID] = struct{}{}
+	}
+	for _, m := range compactedMetas {
+		bucketCompacted[m.BlockID] = struct{}{}
+	}
+
+	var diff tenantIndexDiff
+	for id := range bucketLive {
+		if _, ok := indexCompacted[id]; ok {
+			diff.compactedInIndex = append(diff.compactedInIndex, id)
+		} else if _, ok := indexLive[id]; !ok {
+			diff.missing = append(diff.missing, id)
+		}
+	}
+	for id := range bucketCompacted {
+		if _, ok := indexLive[id]; ok {
+			diff.compactedInBucket = append(diff.compactedInBucket, id)
+		} else if _, ok := indexCompacted[id]; !ok {
+			diff.missing = append(diff.missing, id)
+		}
+	}
+	for _, ids := range []map[uuid.UUID]struct{}{indexLive, indexCompacted} {
+		for id := range ids {
+			_, live := bucketLive[id]
+			_, compacted := bucketCompacted[id]
+			if !live && !compacted {
+				diff.stale = append(diff.stale, id)
+			}
+		}
+	}
+
+	for _, ids := range [][]uuid.UUID{diff.missing, diff.stale, diff.compactedInBucket, diff.compactedInIndex} {
+		sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	}
+
+	return diff
+}
+
+func (d tenantIndexDiff) empty() bool {
+	return len(d.missing) == 0 && len(d.stale) == 0 && len(d.compactedInBucket) == 0 && len(d.compactedInIndex) == 0
+}
+
+func (d tenantIndexDiff) print() {
+	if d.empty() {
+		fmt.Println("tenant index matches the bucket")
+		return
+	}
+
+	printIDs := func(msg string, ids []uuid.UUID) {
+		if len(ids) == 0 {
+			return
+		}
+		fmt.Println(msg, len(ids))
+		for _, id := range ids {
+			fmt.Println(" ", id)
+		}
+	}
+
+	printIDs("blocks missing from the index:", d.missing)
+	printIDs("blocks in the index but not in the bucket:", d.stale)
+	printIDs("blocks compacted in the bucket but not in the index:", d.compactedInBucket)
+	printIDs("blocks compacted in the index but not in the bucket:", d.compactedInIndex)
+}
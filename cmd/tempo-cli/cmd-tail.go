@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/grafana/dskit/user"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+)
+
+type tailCmd struct {
+	HostPort string `arg:"" help:"tempo host and port. scheme and path will be provided based on query type. e.g. localhost:3200"`
+	TraceQL  string `arg:"" help:"traceql query"`
+
+	OrgID      string        `help:"optional orgID"`
+	UseGRPC    bool          `help:"search over GRPC"`
+	PathPrefix string        `help:"string to prefix all http paths with"`
+	Interval   time.Duration `help:"how often to search for new spans" default:"2s"`
+	Lookback   time.Duration `help:"time range searched for new spans on every poll. spans ingested later than this after they ended are missed" default:"1m"`
+	SPSS       int           `help:"spans per spanset" default:"100"`
+	Limit      int           `help:"limit number of traces per poll" default:"1000"`
+	JSON       bool          `help:"print spans as json"`
+}
+
+// tailSpan is a span matching the tailed query together with the trace it belongs to.
+type tailSpan struct {
+	TraceID         string        `json:"traceID"`
+	RootServiceName string        `json:"rootServiceName,omitempty"`
+	RootTraceName   string        `json:"rootTraceName,omitempty"`
+	Span            *tempopb.Span `json:"span"`
+}
+
+func (cmd *tailCmd) Run(_ *globalOptions) error {
+	if cmd.Interval <= 0 {
+		return errors.New("interval must be greater than 0")
+	}
+	if cmd.Lookback < cmd.Interval {
+		return errors.New("lookback must be greater than or equal to interval")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	search := cmd.searchHTTP
+	if cmd.UseGRPC {
+		var err error
+		search, err = cmd.searchGRPC(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	t := newTailer(search, cmd.Lookback)
+
+	ticker := time.NewTicker(cmd.Interval)
+	defer ticker.Stop()
+
+	for {
+		spans, err := t.poll(ctx, cmd.request(time.Now()))
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			fmt.Fprintln(os.Stderr, "error searching:", err)
+		}
+
+		for _, s := range spans {
+			if err := cmd.print(s); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (cmd *tailCmd) request(now time.Time) *tempopb.SearchRequest {
+	return &tempopb.SearchRequest{
+		Query:           cmd.TraceQL,
+		Start:           uint32(now.Add(-cmd.Lookback).Unix()),
+		End:             uint32(now.Unix()) + 1, // end is exclusive and in seconds
+		SpansPerSpanSet: uint32(cmd.SPSS),
+		Limit:           uint32(cmd.Limit),
+	}
+}
+
+func (cmd *tailCmd) print(s tailSpan) error {
+	if cmd.JSON {
+		return printAsJSON(s)
+	}
+
+	attrs := make([]string, 0, len(s.Span.Attributes))
+	for _, a := range s.Span.Attributes {
+		attrs = append(attrs, a.Key+"="+anyValueString(a.Value))
+	}
+
+	fmt.Printf("%s %s %s %s %q %s %s\n",
+		time.Unix(0, int64(s.Span.StartTimeUnixNano)).Format(time.RFC3339Nano),
+		s.TraceID,
+		s.Span.SpanID,
+		s.RootServiceName,
+		s.Span.Name,
+		time.Duration(s.Span.DurationNanos),
+		strings.Join(attrs, " "),
+	)
+	return nil
+}
+
+type tailSearchFunc func(ctx context.Context, req *tempopb.SearchRequest) (*tempopb.SearchResponse, error)
+
+// tailer repeatedly searches a sliding time range and returns the matching spans that weren't returned before.
+type tailer struct {
+	search   tailSearchFunc
+	lookback time.Duration
+	seen     map[string]time.Time
+}
+
+func newTailer(search tailSearchFunc, lookback time.Duration) *tailer {
+	return &tailer{
+		search:   search,
+		lookback: lookback,
+		seen:     map[string]time.Time{},
+	}
+}
+
+// poll searches once and returns the new spans ordered by start time. Spans are remembered for twice the lookback
+// so they are only returned once while they are in the searched time range.
+func (t *tailer) poll(ctx context.Context, req *tempopb.SearchRequest) ([]tailSpan, error) {
+	resp, err := t.search(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var spans []tailSpan
+	for _, tr := range resp.Traces {
+		spanSets := tr.SpanSets
+		if len(spanSets) == 0 && tr.SpanSet != nil {
+			spanSets = []*tempopb.SpanSet{tr.SpanSet}
+		}
+
+		for _, ss := range spanSets {
+			for _, s := range ss.Spans {
+				key := tr.TraceID + "/" + s.SpanID
+				if _, ok := t.seen[key]; ok {
+					continue
+				}
+				t.seen[key] = now
+
+				spans = append(spans, tailSpan{
+					TraceID:         tr.TraceID,
+					RootServiceName: tr.RootServiceName,
+					RootTraceName:   tr.RootTraceName,
+					Span:            s,
+				})
+			}
+		}
+	}
+
+	for key, seenAt := range t.seen {
+		if now.Sub(seenAt) > 2*t.lookback {
+			delete(t.seen, key)
+		}
+	}
+
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].Span.StartTimeUnixNano < spans[j].Span.StartTimeUnixNano
+	})
+	return spans, nil
+}
+
+// nolint: goconst // goconst wants us to make http:// a const
+func (cmd *tailCmd) searchHTTP(ctx context.Context, req *tempopb.SearchRequest) (*tempopb.SearchResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", "http://"+path.Join(cmd.HostPort, cmd.PathPrefix, api.PathSearch), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err = api.BuildSearchRequest(httpReq, req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq.Header = http.Header{}
+	err = user.InjectOrgIDIntoHTTPRequest(user.InjectOrgID(ctx, cmd.OrgID), httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	httpResp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(httpResp.Body)
+		return nil, errors.New("failed to query. body: " + string(body) + " status: " + httpResp.Status)
+	}
+
+	resp := &tempopb.SearchResponse{}
+	if err := jsonpb.Unmarshal(httpResp.Body, resp); err != nil {
+		return nil, fmt.Errorf("failed to parse resp: %w", err)
+	}
+	return resp, nil
+}
+
+// searchGRPC returns a search func using the streaming querier. The last response of a stream holds all results.
+func (cmd *tailCmd) searchGRPC(ctx context.Context) (tailSearchFunc, error) {
+	clientConn, err := grpc.DialContext(ctx, cmd.HostPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	client := tempopb.NewStreamingQuerierClient(clientConn)
+
+	return func(ctx context.Context, req *tempopb.SearchRequest) (*tempopb.SearchResponse, error) {
+		ctx, err := user.InjectIntoGRPCRequest(user.InjectOrgID(ctx, cmd.OrgID))
+		if err != nil {
+			return nil, err
+		}
+
+		stream, err := client.Search(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := &tempopb.SearchResponse{}
+		for {
+			searchResp, err := stream.Recv()
+			if searchResp != nil {
+				resp = searchResp
+			}
+			if errors.Is(err, io.EOF) {
+				return resp, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}, nil
+}
+
+func anyValueString(v *v1.AnyValue) string {
+	if v == nil {
+		return ""
+	}
+
+	switch v := v.Value.(type) {
+	case *v1.AnyValue_StringValue:
+		return strconv.Quote(v.StringValue)
+	case *v1.AnyValue_IntValue:
+		return strconv.FormatInt(v.IntValue, 10)
+	case *v1.AnyValue_DoubleValue:
+		return strconv.FormatFloat(v.DoubleValue, 'g', -1, 64)
+	case *v1.AnyValue_BoolValue:
+		return strconv.FormatBool(v.BoolValue)
+	}
+	return v.String()
+}
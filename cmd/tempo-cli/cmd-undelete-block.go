@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+type undeleteBlockCmd struct {
+	backendOptions
+
+	TenantID string `arg:"" help:"tenant-id within the bucket"`
+	BlockID  string `arg:"" help:"block ID to undelete"`
+}
+
+func (cmd *undeleteBlockCmd) Run(ctx *globalOptions) error {
+	blockID, err := uuid.Parse(cmd.BlockID)
+	if err != nil {
+		return fmt.Errorf("invalid block ID %s: %w", cmd.BlockID, err)
+	}
+
+	_, _, c, err := loadBackend(&cmd.backendOptions, ctx)
+	if err != nil {
+		return err
+	}
+
+	tagger, ok := c.(backend.DeletionTagger)
+	if !ok {
+		return fmt.Errorf("the backend does not support deletion tagging")
+	}
+
+	err = tagger.UndeleteBlock(blockID, cmd.TenantID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("restored block", blockID, "of tenant", cmd.TenantID)
+	return nil
+}
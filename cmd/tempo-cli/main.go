@@ -11,8 +11,10 @@ import (
 	"github.com/grafana/tempo/cmd/tempo/app"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/backend/azure"
+	"github.com/grafana/tempo/tempodb/backend/cos"
 	"github.com/grafana/tempo/tempodb/backend/gcs"
 	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/backend/oss"
 	"github.com/grafana/tempo/tempodb/backend/s3"
 )
 
@@ -27,7 +29,7 @@ type globalOptions struct {
 }
 
 type backendOptions struct {
-	Backend string `help:"backend to connect to (s3/gcs/local/azure), optional, overrides backend in config file" enum:",s3,gcs,local,azure" default:""`
+	Backend string `help:"backend to connect to (s3/gcs/local/azure/oss/cos), optional, overrides backend in config file" enum:",s3,gcs,local,azure,oss,cos" default:""`
 	Bucket  string `help:"bucket (or path on local backend) to scan, optional, overrides bucket in config file"`
 
 	S3Endpoint string `name:"s3-endpoint" help:"s3 endpoint (s3.dualstack.us-east-2.amazonaws.com), optional, overrides endpoint in config file"`
@@ -44,6 +46,7 @@ var cli struct {
 		CompactionSummary listCompactionSummaryCmd `cmd:"" help:"List summary of data by compaction level"`
 		CacheSummary      listCacheSummaryCmd      `cmd:"" help:"List summary of bloom sizes per day per compaction level"`
 		Index             listIndexCmd             `cmd:"" help:"List information about a block index"`
+		TenantIndex       listTenantIndexCmd       `cmd:"" help:"List information about a tenant index, compare it against the bucket and rebuild it"`
 		Column            listColumnCmd            `cmd:"" help:"List values in a given column"`
 	} `cmd:""`
 
@@ -74,6 +77,10 @@ var cli struct {
 		TraceSummary queryTraceSummaryCmd `cmd:"" help:"query summary for a traceid directly from backend blocks"`
 	} `cmd:""`
 
+	Tail tailCmd `cmd:"" help:"print spans matching a traceql query as they are ingested"`
+
+	Backfill backfillCmd `cmd:"" help:"convert OTLP or Jaeger JSON trace dumps into blocks and upload them to the backend"`
+
 	Search struct {
 		Blocks searchBlocksCmd `cmd:"" help:"search for a traceid directly from backend blocks"`
 	} `cmd:""`
@@ -83,6 +90,10 @@ var cli struct {
 		Convert3to4 convertParquet3to4 `cmd:"" help:"convert an existing vParquet3 file to vParquet4 block"`
 	} `cmd:""`
 
+	Undelete struct {
+		Block undeleteBlockCmd `cmd:"" help:"restore a block that was tagged for deletion"`
+	} `cmd:""`
+
 	Migrate struct {
 		Tenant          migrateTenantCmd          `cmd:"" help:"migrate tenant between two backends"`
 		OverridesConfig migrateOverridesConfigCmd `cmd:"" help:"migrate overrides config"`
@@ -100,24 +111,33 @@ func main() {
 	ctx.FatalIfErrorf(err)
 }
 
-func loadBackend(b *backendOptions, g *globalOptions) (backend.Reader, backend.Writer, backend.Compactor, error) {
+func loadConfig(g *globalOptions) (*app.Config, error) {
 	// Defaults
-	cfg := app.Config{}
+	cfg := &app.Config{}
 	cfg.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
 
 	// Existing config
 	if g.ConfigFile != "" {
 		buff, err := os.ReadFile(g.ConfigFile)
 		if err != nil {
-			return nil, nil, nil, fmt.Errorf("failed to read configFile %s: %w", g.ConfigFile, err)
+			return nil, fmt.Errorf("failed to read configFile %s: %w", g.ConfigFile, err)
 		}
 
-		err = yaml.UnmarshalStrict(buff, &cfg)
+		err = yaml.UnmarshalStrict(buff, cfg)
 		if err != nil {
-			return nil, nil, nil, fmt.Errorf("failed to parse configFile %s: %w", g.ConfigFile, err)
+			return nil, fmt.Errorf("failed to parse configFile %s: %w", g.ConfigFile, err)
 		}
 	}
 
+	return cfg, nil
+}
+
+func loadBackend(b *backendOptions, g *globalOptions) (backend.Reader, backend.Writer, backend.Compactor, error) {
+	cfg, err := loadConfig(g)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	// cli overrides
 	if b.Backend != "" {
 		cfg.StorageConfig.Trace.Backend = b.Backend
@@ -128,13 +148,14 @@ func loadBackend(b *backendOptions, g *globalOptions) (backend.Reader, backend.W
 		cfg.StorageConfig.Trace.GCS.BucketName = b.Bucket
 		cfg.StorageConfig.Trace.S3.Bucket = b.Bucket
 		cfg.StorageConfig.Trace.Azure.ContainerName = b.Bucket
+		cfg.StorageConfig.Trace.OSS.Bucket = b.Bucket
+		cfg.StorageConfig.Trace.COS.Bucket = b.Bucket
 	}
 
 	if b.S3Endpoint != "" {
 		cfg.StorageConfig.Trace.S3.Endpoint = b.S3Endpoint
 	}
 
-	var err error
 	var r backend.RawReader
 	var w backend.RawWriter
 	var c backend.Compactor
@@ -148,6 +169,10 @@ func loadBackend(b *backendOptions, g *globalOptions) (backend.Reader, backend.W
 		r, w, c, err = s3.New(cfg.StorageConfig.Trace.S3)
 	case backend.Azure:
 		r, w, c, err = azure.New(cfg.StorageConfig.Trace.Azure)
+	case backend.OSS:
+		r, w, c, err = oss.New(cfg.StorageConfig.Trace.OSS)
+	case backend.COS:
+		r, w, c, err = cos.New(cfg.StorageConfig.Trace.COS)
 	default:
 		err = fmt.Errorf("unknown backend %s", cfg.StorageConfig.Trace.Backend)
 	}
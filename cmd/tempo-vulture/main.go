@@ -44,6 +44,12 @@ var (
 	tempoRetentionDuration        time.Duration
 	tempoPushTLS                  bool
 
+	tempoMetricsBackoffDuration time.Duration
+	tempoMetricsWindow          time.Duration
+	tempoMetricsDelay           time.Duration
+	tempoMetricsTolerance       float64
+	prometheusQueryURL          string
+
 	logger *zap.Logger
 )
 
@@ -71,6 +77,11 @@ func init() {
 	flag.DurationVar(&tempoReadBackoffDuration, "tempo-read-backoff-duration", 30*time.Second, "The amount of time to pause between read Tempo calls")
 	flag.DurationVar(&tempoSearchBackoffDuration, "tempo-search-backoff-duration", 60*time.Second, "The amount of time to pause between search Tempo calls.  Set to 0s to disable search.")
 	flag.DurationVar(&tempoRetentionDuration, "tempo-retention-duration", 336*time.Hour, "The block retention that Tempo is using")
+	flag.DurationVar(&tempoMetricsBackoffDuration, "tempo-metrics-backoff-duration", 0, "The amount of time to pause between metrics checks. Set to 0s to disable metrics checks.")
+	flag.DurationVar(&tempoMetricsWindow, "tempo-metrics-window", 5*time.Minute, "The window of written spans compared with the metrics derived from them.")
+	flag.DurationVar(&tempoMetricsDelay, "tempo-metrics-delay", 5*time.Minute, "How long before now the metrics window ends, to give the metrics-generator and the remote write time to process the spans.")
+	flag.Float64Var(&tempoMetricsTolerance, "tempo-metrics-tolerance", 0.2, "The relative difference allowed between the written spans and the metrics derived from them.")
+	flag.StringVar(&prometheusQueryURL, "prometheus-query-url", "", "The URL (scheme://hostname) of the Prometheus API the metrics-generator writes to. If set the spanmetrics of the written spans are checked.")
 }
 
 func main() {
@@ -219,6 +230,35 @@ func main() {
 		}()
 	}
 
+	// Metrics
+	if tempoMetricsBackoffDuration > 0 {
+		tickerMetrics := time.NewTicker(tempoMetricsBackoffDuration)
+
+		go func() {
+			for now := range tickerMetrics.C {
+				// the window is aligned to its length so TraceQL metrics returns it in a single step
+				end := now.Add(-tempoMetricsDelay).Truncate(tempoMetricsWindow)
+				start := end.Add(-tempoMetricsWindow)
+
+				// Don't check a window the vulture wasn't writing in for its whole length
+				if start.Before(actualStartTime.Add(2 * tempoWriteBackoffDuration)) {
+					continue
+				}
+
+				client := httpclient.New(tempoQueryURL, tempoOrgID)
+
+				check, err := checkMetrics(client, start, end)
+				if err != nil {
+					metricErrorTotal.Inc()
+					logger.Error("metrics check failed",
+						zap.Error(err),
+					)
+				}
+				pushMetricsCheck(check)
+			}
+		}()
+	}
+
 	http.Handle(prometheusPath, promhttp.Handler())
 	log.Fatal(http.ListenAndServe(prometheusListenAddress, nil))
 }
@@ -271,6 +311,13 @@ func pushMetrics(metrics traceMetrics) {
 	metricTracesErrors.WithLabelValues("notfound_search_attribute").Add(float64(metrics.notFoundSearchAttribute))
 }
 
+func pushMetricsCheck(check metricsCheck) {
+	metricMetricsChecks.Add(float64(check.requested))
+	metricMetricsErrors.WithLabelValues("requestfailed").Add(float64(check.requestFailed))
+	metricMetricsErrors.WithLabelValues("traceql_mismatch").Add(float64(check.traceQLMismatch))
+	metricMetricsErrors.WithLabelValues("spanmetrics_mismatch").Add(float64(check.spanMetricsMismatch))
+}
+
 func selectPastTimestamp(start, stop time.Time, interval, retention time.Duration, r *rand.Rand) (newStart, ts time.Time) {
 	oldest := stop.Add(-retention)
 
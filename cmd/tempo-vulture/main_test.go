@@ -3,6 +3,8 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -111,3 +113,61 @@ func TestEqualTraces(t *testing.T) {
 
 	require.True(t, equalTraces(a, b))
 }
+
+func TestExpectedSpanCount(t *testing.T) {
+	interval := 15 * time.Second
+	start := time.Unix(1636729665, 0).Truncate(time.Minute)
+
+	spans := func(ts time.Time) int {
+		trace, err := util.NewTraceInfo(ts, "").ConstructTraceFromEpoch()
+		require.NoError(t, err)
+
+		count := 0
+		for _, b := range trace.Batches {
+			for _, ss := range b.ScopeSpans {
+				count += len(ss.Spans)
+			}
+		}
+		return count
+	}
+
+	expected := 0
+	for i := 0; i < 4; i++ {
+		expected += spans(start.Add(time.Duration(i) * interval))
+	}
+
+	actual, err := expectedSpanCount(start, start.Add(time.Minute), interval)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	// timestamps before the start of the window aren't counted
+	actual, err = expectedSpanCount(start.Add(time.Second), start.Add(time.Minute), interval)
+	require.NoError(t, err)
+	require.Equal(t, expected-spans(start), actual)
+}
+
+func TestWithinTolerance(t *testing.T) {
+	require.True(t, withinTolerance(100, 100, 0))
+	require.True(t, withinTolerance(100, 110, 0.1))
+	require.True(t, withinTolerance(100, 90, 0.1))
+	require.False(t, withinTolerance(100, 89, 0.1))
+	require.True(t, withinTolerance(0, 0, 0.1))
+	require.False(t, withinTolerance(0, 1, 0.1))
+}
+
+func TestQuerySpanMetricsCalls(t *testing.T) {
+	end := time.Unix(1636729665, 0)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/query", r.URL.Path)
+		require.Equal(t, `sum(increase(traces_spanmetrics_calls_total{service="tempo-vulture"}[300s]))`, r.URL.Query().Get("query"))
+		require.Equal(t, "1636729665", r.URL.Query().Get("time"))
+
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1636729665,"123.5"]}]}}`))
+	}))
+	defer srv.Close()
+
+	calls, err := querySpanMetricsCalls(srv.URL, end.Add(-5*time.Minute), end)
+	require.NoError(t, err)
+	require.Equal(t, 123.5, calls)
+}
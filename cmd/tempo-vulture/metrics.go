@@ -34,10 +34,32 @@ var (
 		},
 		[]string{"error"},
 	)
+
+	// metricMetricsChecks is a prometheus counter that indicates the number of metrics queries checked.
+	metricMetricsChecks = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "metrics_check_total",
+			Help:      "total number of metrics queries checked by tempo vulture",
+		},
+	)
+
+	// metricMetricsErrors is a prometheus counter that indicates the number of metrics that don't match the
+	// written spans.
+	metricMetricsErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "metrics_error_total",
+			Help:      "total number of issues with metrics derived from the written spans",
+		},
+		[]string{"error"},
+	)
 )
 
 func init() {
 	prometheus.MustRegister(metricErrorTotal)
 	prometheus.MustRegister(metricTracesInspected)
 	prometheus.MustRegister(metricTracesErrors)
+	prometheus.MustRegister(metricMetricsChecks)
+	prometheus.MustRegister(metricMetricsErrors)
 }
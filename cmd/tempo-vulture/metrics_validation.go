@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/grafana/tempo/pkg/httpclient"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+// vultureServiceName is the service name of the spans written by the vulture
+const vultureServiceName = "tempo-vulture"
+
+// metricsCheck are the results of comparing the spans written by the vulture in a window with the metrics
+// derived from them
+type metricsCheck struct {
+	requested           int
+	requestFailed       int
+	traceQLMismatch     int
+	spanMetricsMismatch int
+}
+
+// checkMetrics compares the number of spans the vulture wrote in [start, end) with the span count returned by the
+// TraceQL metrics API and, if a Prometheus query URL is configured, with the calls of the spanmetrics processor.
+func checkMetrics(client *httpclient.Client, start, end time.Time) (metricsCheck, error) {
+	mc := metricsCheck{}
+
+	logger := logger.With(
+		zap.Time("start", start),
+		zap.Time("end", end),
+	)
+
+	expected, err := expectedSpanCount(start, end, tempoWriteBackoffDuration)
+	if err != nil {
+		return mc, fmt.Errorf("unable to construct expected spans: %w", err)
+	}
+
+	logger.Info("querying Tempo via TraceQL metrics", zap.Int("expected", expected))
+	mc.requested++
+	actual, err := queryTraceQLSpanCount(client, start, end)
+	if err != nil {
+		mc.requestFailed++
+		return mc, fmt.Errorf("failed to query TraceQL metrics: %w", err)
+	}
+	if !withinTolerance(float64(expected), actual, tempoMetricsTolerance) {
+		mc.traceQLMismatch++
+		logger.Error("TraceQL metrics span count doesn't match", zap.Int("expected", expected), zap.Float64("actual", actual))
+	}
+
+	if prometheusQueryURL == "" {
+		return mc, nil
+	}
+
+	logger.Info("querying spanmetrics", zap.Int("expected", expected))
+	mc.requested++
+	actual, err = querySpanMetricsCalls(prometheusQueryURL, start, end)
+	if err != nil {
+		mc.requestFailed++
+		return mc, fmt.Errorf("failed to query spanmetrics: %w", err)
+	}
+	// spanmetrics are counted when the spans are received and increase() extrapolates, the tolerance absorbs both
+	if !withinTolerance(float64(expected), actual, tempoMetricsTolerance) {
+		mc.spanMetricsMismatch++
+		logger.Error("spanmetrics calls don't match", zap.Int("expected", expected), zap.Float64("actual", actual))
+	}
+
+	return mc, nil
+}
+
+// expectedSpanCount returns the number of spans of the traces the vulture wrote with a timestamp in [start, end).
+// Traces are written every interval, with the timestamp rounded to the interval.
+func expectedSpanCount(start, end time.Time, interval time.Duration) (int, error) {
+	count := 0
+	for ts := start.Truncate(interval); ts.Before(end); ts = ts.Add(interval) {
+		if ts.Before(start) {
+			continue
+		}
+
+		trace, err := util.NewTraceInfo(ts, tempoOrgID).ConstructTraceFromEpoch()
+		if err != nil {
+			return 0, err
+		}
+		for _, b := range trace.Batches {
+			for _, ss := range b.ScopeSpans {
+				count += len(ss.Spans)
+			}
+		}
+	}
+
+	return count, nil
+}
+
+// queryTraceQLSpanCount returns the number of spans of the vulture in [start, end) according to the TraceQL
+// metrics API. A single step covers the whole range.
+func queryTraceQLSpanCount(client *httpclient.Client, start, end time.Time) (float64, error) {
+	resp, err := client.MetricsQueryRange(&tempopb.QueryRangeRequest{
+		Query: fmt.Sprintf(`{ resource.service.name = "%s" } | count_over_time()`, vultureServiceName),
+		Start: uint64(start.UnixNano()),
+		End:   uint64(end.UnixNano()),
+		Step:  uint64(end.Sub(start)),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0.0
+	for _, series := range resp.Series {
+		for _, sample := range series.Samples {
+			total += sample.Value
+		}
+	}
+
+	return total, nil
+}
+
+// prometheusResponse is the part of a response of the Prometheus instant query API used by the vulture
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Value [2]interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// querySpanMetricsCalls returns the increase of the calls of the vulture service recorded by the spanmetrics
+// processor in [start, end)
+func querySpanMetricsCalls(baseURL string, start, end time.Time) (float64, error) {
+	query := fmt.Sprintf(`sum(increase(traces_spanmetrics_calls_total{service="%s"}[%s]))`, vultureServiceName, promDuration(end.Sub(start)))
+
+	u, err := url.Parse(baseURL + "/api/v1/query")
+	if err != nil {
+		return 0, err
+	}
+	q := u.Query()
+	q.Set("query", query)
+	q.Set("time", strconv.FormatInt(end.Unix(), 10))
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return 0, err
+	}
+	if tempoOrgID != "" {
+		req.Header.Set("X-Scope-OrgID", tempoOrgID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	pr := prometheusResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return 0, fmt.Errorf("error decoding response with status %d: %w", resp.StatusCode, err)
+	}
+	if pr.Status != "success" {
+		return 0, fmt.Errorf("query %s failed: %s", query, pr.Error)
+	}
+	if pr.Data.ResultType != "vector" {
+		return 0, fmt.Errorf("unexpected result type %s", pr.Data.ResultType)
+	}
+
+	// no series means the generator didn't produce any spanmetrics for the vulture
+	if len(pr.Data.Result) == 0 {
+		return 0, nil
+	}
+
+	s, ok := pr.Data.Result[0].Value[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected sample value %v", pr.Data.Result[0].Value[1])
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// promDuration formats a duration in the syntax of PromQL range selectors
+func promDuration(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+}
+
+// withinTolerance returns true if actual is within a relative tolerance of expected
+func withinTolerance(expected, actual, tolerance float64) bool {
+	if expected == 0 {
+		return actual == 0
+	}
+	return math.Abs(actual-expected)/expected <= tolerance
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/grafana/tempo/modules/compactor"
 	"github.com/grafana/tempo/modules/distributor"
 	"github.com/grafana/tempo/modules/distributor/receiver"
+	"github.com/grafana/tempo/modules/distributor/usage"
 	frontend_v1 "github.com/grafana/tempo/modules/frontend/v1"
 	"github.com/grafana/tempo/modules/generator"
 	"github.com/grafana/tempo/modules/ingester"
@@ -41,15 +42,20 @@ import (
 	"github.com/grafana/tempo/modules/storage"
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/rbac"
+	"github.com/grafana/tempo/pkg/tlsauth"
 	"github.com/grafana/tempo/pkg/usagestats"
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/log"
 	util_log "github.com/grafana/tempo/pkg/util/log"
+	"github.com/grafana/tempo/tempodb"
 )
 
 const (
 	metricsNamespace = "tempo"
 	apiDocs          = "https://grafana.com/docs/tempo/latest/api_docs/"
+
+	streamingQuerierPrefix = "/tempopb.StreamingQuerier/"
 )
 
 var (
@@ -82,11 +88,16 @@ type App struct {
 	store         storage.Store
 	usageReport   *usagestats.Reporter
 	cacheProvider cache.Provider
+	usageMetrics  *prometheus.Registry // nil unless the usage tracker is enabled
 	MemberlistKV  *memberlist.KVInitService
 
 	HTTPAuthMiddleware       middleware.Interface
 	TracesConsumerMiddleware receiver.Middleware
 
+	// HTTPReadMiddleware and HTTPAdminMiddleware limit the query and admin APIs to the roles of the RBAC config
+	HTTPReadMiddleware  middleware.Interface
+	HTTPAdminMiddleware middleware.Interface
+
 	ModuleManager *modules.Manager
 	serviceMap    map[string]services.Service
 	deps          map[string][]string
@@ -100,6 +111,10 @@ func New(cfg Config) (*App, error) {
 		Server:    newTempoServer(),
 	}
 
+	if cfg.Distributor.Usage.CostAttribution.Enabled {
+		app.usageMetrics = prometheus.NewRegistry()
+	}
+
 	usagestats.Edition("oss")
 
 	statFeatureEnabledAuth.Set(0)
@@ -112,7 +127,13 @@ func New(cfg Config) (*App, error) {
 		statFeatureEnabledMultitenancy.Set(1)
 	}
 
-	app.setupAuthMiddleware()
+	if err := app.setupAuthMiddleware(); err != nil {
+		return nil, fmt.Errorf("failed to setup auth middleware: %w", err)
+	}
+
+	if err := app.setupRBACMiddleware(); err != nil {
+		return nil, fmt.Errorf("failed to setup rbac middleware: %w", err)
+	}
 
 	if err := app.setupModuleManager(); err != nil {
 		return nil, fmt.Errorf("failed to setup module manager: %w", err)
@@ -121,7 +142,7 @@ func New(cfg Config) (*App, error) {
 	return app, nil
 }
 
-func (t *App) setupAuthMiddleware() {
+func (t *App) setupAuthMiddleware() error {
 	if t.cfg.MultitenancyIsEnabled() {
 
 		// don't check auth for these gRPC methods, since single call is used for multiple users
@@ -152,6 +173,18 @@ func (t *App) setupAuthMiddleware() {
 		}
 		t.HTTPAuthMiddleware = middleware.AuthenticateUser
 		t.TracesConsumerMiddleware = receiver.MultiTenancyMiddleware()
+
+		if t.cfg.TLSTenantAuth.Enabled {
+			auth, err := tlsauth.New(t.cfg.TLSTenantAuth)
+			if err != nil {
+				return fmt.Errorf("invalid tls tenant auth config: %w", err)
+			}
+
+			t.cfg.Server.GRPCMiddleware[0] = auth.UnaryServerInterceptor(t.cfg.Server.GRPCMiddleware[0])
+			t.cfg.Server.GRPCStreamMiddleware[0] = auth.StreamServerInterceptor(t.cfg.Server.GRPCStreamMiddleware[0])
+			t.HTTPAuthMiddleware = auth.HTTPMiddleware(t.HTTPAuthMiddleware)
+			t.TracesConsumerMiddleware = receiver.TLSTenantMiddleware(auth, t.TracesConsumerMiddleware)
+		}
 	} else {
 		t.cfg.Server.GRPCMiddleware = []grpc.UnaryServerInterceptor{
 			fakeGRPCAuthUniaryMiddleware,
@@ -162,6 +195,27 @@ func (t *App) setupAuthMiddleware() {
 		t.HTTPAuthMiddleware = fakeHTTPAuthMiddleware
 		t.TracesConsumerMiddleware = receiver.FakeTenantMiddleware()
 	}
+
+	return nil
+}
+
+func (t *App) setupRBACMiddleware() error {
+	if !t.cfg.RBAC.Enabled {
+		t.HTTPReadMiddleware = middleware.Merge()
+		t.HTTPAdminMiddleware = middleware.Merge()
+		return nil
+	}
+
+	authorizer, err := rbac.New(t.cfg.RBAC)
+	if err != nil {
+		return fmt.Errorf("invalid rbac config: %w", err)
+	}
+
+	t.HTTPReadMiddleware = authorizer.Middleware(rbac.RoleRead)
+	t.HTTPAdminMiddleware = authorizer.Middleware(rbac.RoleAdmin)
+	// the streaming query APIs of the query frontend are served over gRPC
+	t.cfg.Server.GRPCStreamMiddleware = append(t.cfg.Server.GRPCStreamMiddleware, authorizer.StreamServerInterceptor(streamingQuerierPrefix, rbac.RoleRead))
+	return nil
 }
 
 // Run starts, and blocks until a signal is received.
@@ -194,6 +248,9 @@ func (t *App) Run() error {
 	}
 
 	t.Server.HTTPRouter().Path(addHTTPAPIPrefix(&t.cfg, api.PathBuildInfo)).Handler(t.buildinfoHandler()).Methods("GET")
+	if t.usageMetrics != nil {
+		t.Server.HTTPRouter().Path(addHTTPAPIPrefix(&t.cfg, api.PathUsageMetrics)).Handler(usage.Handler(t.usageMetrics)).Methods("GET")
+	}
 
 	t.Server.HTTPRouter().Path("/ready").Handler(t.readyHandler(sm, shutdownRequested))
 	t.Server.HTTPRouter().Path("/status").Handler(t.statusHandler()).Methods("GET")
@@ -382,6 +439,7 @@ func (t *App) statusHandler() http.HandlerFunc {
 			"version":   t.writeStatusVersion,
 			"services":  t.writeStatusServices,
 			"endpoints": t.writeStatusEndpoints,
+			"blocklist": t.writeStatusBlocklist,
 		}
 
 		wrapStatus := func(endpoint string) {
@@ -414,6 +472,7 @@ func (t *App) statusHandler() http.HandlerFunc {
 			wrapStatus("version")
 			wrapStatus("services")
 			wrapStatus("endpoints")
+			wrapStatus("blocklist")
 			wrapStatus("runtime_config")
 			wrapStatus("config")
 		}
@@ -537,6 +596,36 @@ func (t *App) writeStatusEndpoints(w io.Writer) error {
 	return nil
 }
 
+// writeStatusBlocklist writes when the blocklist was polled successfully for the last time and how stale it is.
+func (t *App) writeStatusBlocklist(w io.Writer) error {
+	if t.store == nil {
+		_, err := w.Write([]byte("the blocklist is not polled by this target\n\n"))
+		return err
+	}
+
+	interval := t.cfg.StorageConfig.Trace.BlocklistPoll
+	if interval == 0 {
+		interval = tempodb.DefaultBlocklistPoll
+	}
+
+	lastPoll, staleness := "never", "-"
+	if last := t.store.LastBlocklistPoll(); !last.IsZero() {
+		lastPoll = last.UTC().Format(time.RFC3339)
+		staleness = time.Since(last).Truncate(time.Second).String()
+	}
+
+	x := table.NewWriter()
+	x.SetOutputMirror(w)
+	x.AppendHeader(table.Row{"last successful poll", "staleness", "poll interval"})
+	x.AppendRows([]table.Row{
+		{lastPoll, staleness, interval},
+	})
+	x.AppendSeparator()
+	x.Render()
+
+	return nil
+}
+
 func (t *App) buildinfoHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
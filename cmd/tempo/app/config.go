@@ -21,7 +21,9 @@ import (
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/modules/querier"
 	"github.com/grafana/tempo/modules/storage"
+	"github.com/grafana/tempo/pkg/rbac"
 	internalserver "github.com/grafana/tempo/pkg/server"
+	"github.com/grafana/tempo/pkg/tlsauth"
 	"github.com/grafana/tempo/pkg/usagestats"
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/tempodb"
@@ -40,6 +42,9 @@ type Config struct {
 	UseOTelTracer          bool          `yaml:"use_otel_tracer,omitempty"`
 	EnableGoRuntimeMetrics bool          `yaml:"enable_go_runtime_metrics,omitempty"`
 
+	TLSTenantAuth tlsauth.Config `yaml:"tls_tenant_auth,omitempty"`
+	RBAC          rbac.Config    `yaml:"rbac,omitempty"`
+
 	Server          server.Config           `yaml:"server,omitempty"`
 	InternalServer  internalserver.Config   `yaml:"internal_server,omitempty"`
 	Distributor     distributor.Config      `yaml:"distributor,omitempty"`
@@ -72,6 +77,8 @@ func (c *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	f.StringVar(&c.Target, "target", SingleBinary, "target module")
 	f.BoolVar(&c.AuthEnabled, "auth.enabled", false, "Set to true to enable auth (deprecated: use multitenancy.enabled)")
 	f.BoolVar(&c.MultitenancyEnabled, "multitenancy.enabled", false, "Set to true to enable multitenancy.")
+	c.TLSTenantAuth.RegisterFlagsAndApplyDefaults(prefix, f)
+	c.RBAC.RegisterFlagsAndApplyDefaults(prefix, f)
 	f.StringVar(&c.HTTPAPIPrefix, "http-api-prefix", "", "String prefix for all http api endpoints.")
 	f.BoolVar(&c.UseOTelTracer, "use-otel-tracer", false, "Set to true to replace the OpenTracing tracer with the OpenTelemetry tracer")
 	f.BoolVar(&c.EnableGoRuntimeMetrics, "enable-go-runtime-metrics", false, "Set to true to enable all Go runtime metrics")
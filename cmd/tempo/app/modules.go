@@ -25,6 +25,7 @@ import (
 	"github.com/grafana/tempo/modules/cache"
 	"github.com/grafana/tempo/modules/compactor"
 	"github.com/grafana/tempo/modules/distributor"
+	"github.com/grafana/tempo/modules/distributor/usage"
 	"github.com/grafana/tempo/modules/frontend"
 	"github.com/grafana/tempo/modules/frontend/interceptor"
 	frontend_v1pb "github.com/grafana/tempo/modules/frontend/v1/frontendv1pb"
@@ -32,9 +33,11 @@ import (
 	"github.com/grafana/tempo/modules/ingester"
 	"github.com/grafana/tempo/modules/overrides"
 	userconfigurableoverridesapi "github.com/grafana/tempo/modules/overrides/userconfigurable/api"
+	userconfigurableoverridesclient "github.com/grafana/tempo/modules/overrides/userconfigurable/client"
 	"github.com/grafana/tempo/modules/querier"
 	tempo_storage "github.com/grafana/tempo/modules/storage"
 	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/rbac"
 	tempo_ring "github.com/grafana/tempo/pkg/ring"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/usagestats"
@@ -42,8 +45,10 @@ import (
 	util_log "github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/backend/azure"
+	"github.com/grafana/tempo/tempodb/backend/cos"
 	"github.com/grafana/tempo/tempodb/backend/gcs"
 	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/backend/oss"
 	"github.com/grafana/tempo/tempodb/backend/s3"
 )
 
@@ -192,8 +197,14 @@ func (t *App) initOverrides() (services.Service, error) {
 		prometheus.MustRegister(t.Overrides)
 	}
 
-	t.Server.HTTPRouter().Path("/status/overrides").HandlerFunc(overrides.TenantsHandler(t.Overrides)).Methods("GET")
-	t.Server.HTTPRouter().Path("/status/overrides/{tenant}").HandlerFunc(overrides.TenantStatusHandler(t.Overrides)).Methods("GET")
+	if t.cfg.Overrides.EffectiveLimitsMetrics {
+		prometheus.MustRegister(overrides.NewEffectiveLimitsCollector(t.Overrides))
+	}
+
+	t.Server.HTTPRouter().Path("/status/overrides").Handler(t.HTTPAdminMiddleware.Wrap(overrides.TenantsHandler(t.Overrides))).Methods("GET")
+	t.Server.HTTPRouter().Path("/status/overrides/{tenant}").Handler(t.HTTPAdminMiddleware.Wrap(overrides.TenantStatusHandler(t.Overrides))).Methods("GET")
+	t.Server.HTTPRouter().Path("/status/overrides/{tenant}/effective").Handler(t.HTTPAdminMiddleware.Wrap(overrides.EffectiveOverridesHandler(t.Overrides))).Methods("GET")
+	t.Server.HTTPRouter().Path("/status/runtime_config/diff").Handler(t.HTTPAdminMiddleware.Wrap(overrides.RuntimeConfigDiffHandler(t.Overrides, &t.cfg.Overrides.Defaults))).Methods("GET")
 
 	return t.Overrides, nil
 }
@@ -212,7 +223,7 @@ func (t *App) initOverridesAPI() (services.Service, error) {
 
 	overridesPath := addHTTPAPIPrefix(&t.cfg, api.PathOverrides)
 	wrapHandler := func(h http.HandlerFunc) http.Handler {
-		return t.HTTPAuthMiddleware.Wrap(h)
+		return middleware.Merge(t.HTTPAdminMiddleware, t.HTTPAuthMiddleware).Wrap(h)
 	}
 
 	t.Server.HTTPRouter().Path(overridesPath).Methods(http.MethodGet).Handler(wrapHandler(userConfigOverridesAPI.GetHandler))
@@ -238,10 +249,18 @@ func (t *App) initDistributor() (services.Service, error) {
 	}
 	t.distributor = distributor
 
+	tempopb.RegisterStreamingPusherServer(t.Server.GRPC(), t.distributor)
+
 	if distributor.DistributorRing != nil {
 		t.Server.HTTPRouter().Handle("/distributor/ring", distributor.DistributorRing)
 	}
 
+	if tracker := distributor.UsageTracker(); tracker != nil && t.usageMetrics != nil {
+		if err := t.usageMetrics.Register(tracker); err != nil {
+			return nil, fmt.Errorf("failed to register usage tracker: %w", err)
+		}
+	}
+
 	return t.distributor, nil
 }
 
@@ -256,8 +275,17 @@ func (t *App) initIngester() (services.Service, error) {
 
 	tempopb.RegisterPusherServer(t.Server.GRPC(), t.ingester)
 	tempopb.RegisterQuerierServer(t.Server.GRPC(), t.ingester)
-	t.Server.HTTPRouter().Path("/flush").Handler(http.HandlerFunc(t.ingester.FlushHandler))
-	t.Server.HTTPRouter().Path("/shutdown").Handler(http.HandlerFunc(t.ingester.ShutdownHandler))
+	t.Server.HTTPRouter().Path("/flush").Handler(t.HTTPAdminMiddleware.Wrap(http.HandlerFunc(t.ingester.FlushHandler)))
+	t.Server.HTTPRouter().Path("/shutdown").Handler(t.HTTPAdminMiddleware.Wrap(http.HandlerFunc(t.ingester.ShutdownHandler)))
+	t.Server.HTTPRouter().Path("/snapshot").Methods(http.MethodPost).Handler(t.HTTPAdminMiddleware.Wrap(http.HandlerFunc(t.ingester.SnapshotHandler)))
+
+	if t.usageMetrics != nil {
+		err = t.usageMetrics.Register(usage.NewTenantGauge("tempo_usage_tracker_live_traces", "The number of live traces per tenant in the ingester.", t.ingester.LiveTracesPerTenant))
+		if err != nil {
+			return nil, fmt.Errorf("failed to register live traces usage: %w", err)
+		}
+	}
+
 	return t.ingester, nil
 }
 
@@ -278,12 +306,15 @@ func (t *App) initGenerator() (services.Service, error) {
 	}
 	t.generator = genSvc
 
-	spanStatsHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.generator.SpanMetricsHandler))
+	spanStatsHandler := middleware.Merge(t.HTTPReadMiddleware, t.HTTPAuthMiddleware).Wrap(http.HandlerFunc(t.generator.SpanMetricsHandler))
 	t.Server.HTTPRouter().Handle(path.Join(api.PathPrefixGenerator, addHTTPAPIPrefix(&t.cfg, api.PathSpanMetrics)), spanStatsHandler)
 
-	queryRangeHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.generator.QueryRangeHandler))
+	queryRangeHandler := middleware.Merge(t.HTTPReadMiddleware, t.HTTPAuthMiddleware).Wrap(http.HandlerFunc(t.generator.QueryRangeHandler))
 	t.Server.HTTPRouter().Handle(path.Join(api.PathPrefixGenerator, addHTTPAPIPrefix(&t.cfg, api.PathMetricsQueryRange)), queryRangeHandler)
 
+	deleteSeriesHandler := middleware.Merge(t.HTTPAdminMiddleware, t.HTTPAuthMiddleware).Wrap(http.HandlerFunc(t.generator.DeleteSeriesHandler))
+	t.Server.HTTPRouter().Handle(path.Join(api.PathPrefixGenerator, addHTTPAPIPrefix(&t.cfg, api.PathGeneratorSeriesDelete)), deleteSeriesHandler).Methods(http.MethodPost, http.MethodDelete)
+
 	tempopb.RegisterMetricsGeneratorServer(t.Server.GRPC(), t.generator)
 
 	return t.generator, nil
@@ -320,6 +351,7 @@ func (t *App) initQuerier() (services.Service, error) {
 		t.readRings[ringMetricsGenerator],
 		t.store,
 		t.Overrides,
+		t.cacheProvider,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create querier: %w", err)
@@ -327,40 +359,45 @@ func (t *App) initQuerier() (services.Service, error) {
 	t.querier = querier
 
 	middleware := middleware.Merge(
+		t.HTTPReadMiddleware,
 		t.HTTPAuthMiddleware,
 	)
 
 	tracesHandler := middleware.Wrap(http.HandlerFunc(t.querier.TraceByIDHandler))
 	t.Server.HTTPRouter().Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathTraces)), tracesHandler)
 
-	searchHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.querier.SearchHandler))
+	searchHandler := middleware.Wrap(http.HandlerFunc(t.querier.SearchHandler))
 	t.Server.HTTPRouter().Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathSearch)), searchHandler)
 
-	searchTagsHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.querier.SearchTagsHandler))
+	searchTagsHandler := middleware.Wrap(http.HandlerFunc(t.querier.SearchTagsHandler))
 	t.Server.HTTPRouter().Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathSearchTags)), searchTagsHandler)
 
-	searchTagsV2Handler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.querier.SearchTagsV2Handler))
+	searchTagsV2Handler := middleware.Wrap(http.HandlerFunc(t.querier.SearchTagsV2Handler))
 	t.Server.HTTPRouter().Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathSearchTagsV2)), searchTagsV2Handler)
 
-	searchTagValuesHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.querier.SearchTagValuesHandler))
+	searchTagValuesHandler := middleware.Wrap(http.HandlerFunc(t.querier.SearchTagValuesHandler))
 	t.Server.HTTPRouter().Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathSearchTagValues)), searchTagValuesHandler)
 
-	searchTagValuesV2Handler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.querier.SearchTagValuesV2Handler))
+	searchTagValuesV2Handler := middleware.Wrap(http.HandlerFunc(t.querier.SearchTagValuesV2Handler))
 	t.Server.HTTPRouter().Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathSearchTagValuesV2)), searchTagValuesV2Handler)
 
-	spanMetricsSummaryHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.querier.SpanMetricsSummaryHandler))
+	tagStatsHandler := middleware.Wrap(http.HandlerFunc(t.querier.TagStatsHandler))
+	t.Server.HTTPRouter().Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathSearchTagsStats)), tagStatsHandler)
+
+	spanMetricsSummaryHandler := middleware.Wrap(http.HandlerFunc(t.querier.SpanMetricsSummaryHandler))
 	t.Server.HTTPRouter().Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathSpanMetricsSummary)), spanMetricsSummaryHandler)
 
-	queryRangeHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.querier.QueryRangeHandler))
+	queryRangeHandler := middleware.Wrap(http.HandlerFunc(t.querier.QueryRangeHandler))
 	t.Server.HTTPRouter().Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathMetricsQueryRange)), queryRangeHandler)
 
-	return t.querier, t.querier.CreateAndRegisterWorker(t.Server.HTTPHandler())
+	// the query frontend checks the role of queries before it passes them to the workers
+	return t.querier, t.querier.CreateAndRegisterWorker(rbac.InternalHandler(t.Server.HTTPHandler()))
 }
 
 func (t *App) initQueryFrontend() (services.Service, error) {
 	// cortexTripper is a bridge between http and httpgrpc.
 	// It does the job of passing data to the cortex frontend code.
-	cortexTripper, v1, err := frontend.InitFrontend(t.cfg.Frontend.Config, frontend.CortexNoQuerierLimits{}, log.Logger, prometheus.DefaultRegisterer)
+	cortexTripper, v1, err := frontend.InitFrontend(t.cfg.Frontend.Config, frontend.QueueLimits{Overrides: t.Overrides}, log.Logger, prometheus.DefaultRegisterer)
 	if err != nil {
 		return nil, err
 	}
@@ -379,6 +416,7 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 	tempopb.RegisterStreamingQuerierServer(t.Server.GRPC(), queryFrontend)
 
 	httpAPIMiddleware := []middleware.Interface{
+		t.HTTPReadMiddleware,
 		t.HTTPAuthMiddleware,
 		httpGzipMiddleware(),
 	}
@@ -401,10 +439,19 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchTagsV2), base.Wrap(queryFrontend.SearchTagsV2Handler))
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchTagValues), base.Wrap(queryFrontend.SearchTagsValuesHandler))
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchTagValuesV2), base.Wrap(queryFrontend.SearchTagsValuesV2Handler))
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchTagsStats), base.Wrap(queryFrontend.SearchTagsStatsHandler))
 
 	// http metrics endpoints
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSpanMetricsSummary), base.Wrap(queryFrontend.MetricsSummaryHandler))
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathMetricsQueryRange), base.Wrap(queryFrontend.MetricsQueryRangeHandler))
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathMetricsQueryRangeDiff), base.Wrap(queryFrontend.MetricsQueryRangeDiffHandler))
+
+	// http v2 endpoints with the response envelope
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchV2), base.Wrap(queryFrontend.SearchV2Handler))
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathMetricsQueryRangeV2), base.Wrap(queryFrontend.MetricsQueryRangeV2Handler))
+
+	// http traceql lint endpoint
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathTraceQLLint), base.Wrap(queryFrontend.TraceQLLintHandler))
 
 	// the query frontend needs to have knowledge of the blocks so it can shard search jobs
 	if t.cfg.Target == QueryFrontend {
@@ -417,6 +464,9 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 	// http endpoint to see usage stats data
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathUsageStats), usageStatsHandler(t.cfg.UsageReport))
 
+	// flush pending audit records when the frontend shuts down
+	t.frontend.AddListener(services.NewListener(nil, nil, nil, func(services.State) { queryFrontend.Stop() }, nil))
+
 	// todo: queryFrontend should implement service.Service and take the cortex frontend a submodule
 	return t.frontend, nil
 }
@@ -426,7 +476,17 @@ func (t *App) initCompactor() (services.Service, error) {
 		t.cfg.Compactor.ShardingRing.KVStore.Store = "memberlist"
 	}
 
-	compactor, err := compactor.New(t.cfg.Compactor, t.store, t.Overrides, prometheus.DefaultRegisterer)
+	// the user-configurable overrides of deleted tenants are removed by the compactor
+	var overridesClient userconfigurableoverridesclient.Client
+	if t.cfg.Overrides.UserConfigurableOverridesConfig.Enabled {
+		var err error
+		overridesClient, err = userconfigurableoverridesclient.New(&t.cfg.Overrides.UserConfigurableOverridesConfig.Client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create user-configurable overrides client: %w", err)
+		}
+	}
+
+	compactor, err := compactor.New(t.cfg.Compactor, t.store, t.Overrides, overridesClient, prometheus.DefaultRegisterer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create compactor: %w", err)
 	}
@@ -436,6 +496,20 @@ func (t *App) initCompactor() (services.Service, error) {
 		t.Server.HTTPRouter().Handle("/compactor/ring", t.compactor.Ring)
 	}
 
+	t.Server.HTTPRouter().Path("/compactor/delete_tenant").Methods(http.MethodPost).Handler(middleware.Merge(t.HTTPAdminMiddleware, t.HTTPAuthMiddleware).Wrap(http.HandlerFunc(t.compactor.DeleteTenantHandler)))
+	t.Server.HTTPRouter().Path("/compactor/delete_tenant").Methods(http.MethodDelete).Handler(middleware.Merge(t.HTTPAdminMiddleware, t.HTTPAuthMiddleware).Wrap(http.HandlerFunc(t.compactor.CancelTenantDeletionHandler)))
+	t.Server.HTTPRouter().Path("/compactor/delete_tenant_status").Methods(http.MethodGet).Handler(middleware.Merge(t.HTTPAdminMiddleware, t.HTTPAuthMiddleware).Wrap(http.HandlerFunc(t.compactor.DeleteTenantStatusHandler)))
+	t.Server.HTTPRouter().Path("/compactor/plan").Methods(http.MethodGet).Handler(t.HTTPAdminMiddleware.Wrap(http.HandlerFunc(t.compactor.CompactionPlanHandler)))
+	t.Server.HTTPRouter().Path("/compactor/scrubber").Methods(http.MethodGet).Handler(t.HTTPAdminMiddleware.Wrap(http.HandlerFunc(t.compactor.ScrubberStatusHandler)))
+	t.Server.HTTPRouter().Path("/compactor/conversion").Methods(http.MethodGet).Handler(t.HTTPAdminMiddleware.Wrap(http.HandlerFunc(t.compactor.ConversionStatusHandler)))
+
+	if t.usageMetrics != nil {
+		err = t.usageMetrics.Register(usage.NewTenantGauge("tempo_usage_tracker_blocks", "The number of blocks per tenant in the backend.", t.compactor.BlocksPerTenant))
+		if err != nil {
+			return nil, fmt.Errorf("failed to register blocks usage: %w", err)
+		}
+	}
+
 	return t.compactor, nil
 }
 
@@ -513,6 +587,10 @@ func (t *App) initUsageReport() (services.Service, error) {
 		reader, writer, _, err = s3.New(t.cfg.StorageConfig.Trace.S3)
 	case backend.Azure:
 		reader, writer, _, err = azure.New(t.cfg.StorageConfig.Trace.Azure)
+	case backend.OSS:
+		reader, writer, _, err = oss.New(t.cfg.StorageConfig.Trace.OSS)
+	case backend.COS:
+		reader, writer, _, err = cos.New(t.cfg.StorageConfig.Trace.COS)
 	default:
 		err = fmt.Errorf("unknown backend %s", t.cfg.StorageConfig.Trace.Backend)
 	}
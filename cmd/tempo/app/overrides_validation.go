@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/prometheus/common/model"
 	"golang.org/x/exp/slices"
 
 	"github.com/grafana/tempo/modules/generator"
@@ -11,6 +12,7 @@ import (
 	"github.com/grafana/tempo/modules/overrides/userconfigurable/api"
 	"github.com/grafana/tempo/modules/overrides/userconfigurable/client"
 	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
+	"github.com/grafana/tempo/pkg/traceql"
 )
 
 type runtimeConfigValidator struct {
@@ -33,6 +35,28 @@ func (r *runtimeConfigValidator) Validate(config *overrides.Overrides) error {
 		}
 	}
 
+	switch config.MetricsGenerator.GenerateNativeHistograms {
+	case "", overrides.HistogramMethodClassic, overrides.HistogramMethodNative, overrides.HistogramMethodBoth:
+	default:
+		return fmt.Errorf("metrics_generator.generate_native_histograms \"%s\" is not valid, valid values: classic, native, both", config.MetricsGenerator.GenerateNativeHistograms)
+	}
+
+	if bucketFactor := config.MetricsGenerator.NativeHistogramBucketFactor; bucketFactor != 0 && bucketFactor <= 1 {
+		return fmt.Errorf("metrics_generator.native_histogram_bucket_factor must be greater than 1 (%g)", bucketFactor)
+	}
+
+	if err := validateTargetInfoMetricName(config.MetricsGenerator.Processor.SpanMetrics.TargetInfoMetricName); err != nil {
+		return err
+	}
+
+	if config.Read.MaxExemplars < 0 {
+		return fmt.Errorf("read.max_exemplars must not be negative (%d)", config.Read.MaxExemplars)
+	}
+
+	if _, err := traceql.ParseExemplarPolicy(config.Read.ExemplarPolicy); err != nil {
+		return fmt.Errorf("read.exemplar_policy is not valid: %w", err)
+	}
+
 	return nil
 }
 
@@ -82,11 +106,30 @@ func (v *overridesValidator) Validate(limits *client.Limits) error {
 		}
 	}
 
+	if name, ok := limits.GetMetricsGenerator().GetProcessor().GetSpanMetrics().GetTargetInfoMetricName(); ok {
+		if err := validateTargetInfoMetricName(name); err != nil {
+			return err
+		}
+	}
+
 	if collectionInterval, ok := limits.GetMetricsGenerator().GetCollectionInterval(); ok {
 		if collectionInterval < 15*time.Second || collectionInterval > 5*time.Minute {
 			return fmt.Errorf("metrics_generator.collection_interval \"%s\" is outside acceptable range of 15s to 5m", collectionInterval.String())
 		}
 	}
 
+	if dedicatedColumns, ok := limits.GetStorage().GetDedicatedColumns(); ok {
+		if err := dedicatedColumns.Validate(); err != nil {
+			return fmt.Errorf("storage.parquet_dedicated_columns: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func validateTargetInfoMetricName(name string) error {
+	if name != "" && !model.IsValidLegacyMetricName(model.LabelValue(name)) {
+		return fmt.Errorf("metrics_generator.processor.span_metrics.target_info_metric_name \"%s\" is not a valid metric name", name)
+	}
 	return nil
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/modules/overrides/userconfigurable/client"
 	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
+	"github.com/grafana/tempo/tempodb/backend"
 )
 
 func Test_runtimeOverridesValidator(t *testing.T) {
@@ -51,6 +52,47 @@ func Test_runtimeOverridesValidator(t *testing.T) {
 			},
 			overrides: overrides.Overrides{Ingestion: overrides.IngestionOverrides{TenantShardSize: 3}},
 		},
+		{
+			name:      "metrics_generator.generate_native_histograms valid",
+			overrides: overrides.Overrides{MetricsGenerator: overrides.MetricsGeneratorOverrides{GenerateNativeHistograms: overrides.HistogramMethodBoth}},
+		},
+		{
+			name:      "metrics_generator.generate_native_histograms invalid",
+			overrides: overrides.Overrides{MetricsGenerator: overrides.MetricsGeneratorOverrides{GenerateNativeHistograms: "sparse"}},
+			expErr:    "metrics_generator.generate_native_histograms \"sparse\" is not valid, valid values: classic, native, both",
+		},
+		{
+			name:      "metrics_generator.native_histogram_bucket_factor valid",
+			overrides: overrides.Overrides{MetricsGenerator: overrides.MetricsGeneratorOverrides{NativeHistogramBucketFactor: 1.05}},
+		},
+		{
+			name:      "metrics_generator.native_histogram_bucket_factor too small",
+			overrides: overrides.Overrides{MetricsGenerator: overrides.MetricsGeneratorOverrides{NativeHistogramBucketFactor: 1}},
+			expErr:    "metrics_generator.native_histogram_bucket_factor must be greater than 1 (1)",
+		},
+		{
+			name:      "metrics_generator.processor.span_metrics.target_info_metric_name valid",
+			overrides: overrides.Overrides{MetricsGenerator: overrides.MetricsGeneratorOverrides{Processor: overrides.ProcessorOverrides{SpanMetrics: overrides.SpanMetricsOverrides{TargetInfoMetricName: "target_info"}}}},
+		},
+		{
+			name:      "metrics_generator.processor.span_metrics.target_info_metric_name invalid",
+			overrides: overrides.Overrides{MetricsGenerator: overrides.MetricsGeneratorOverrides{Processor: overrides.ProcessorOverrides{SpanMetrics: overrides.SpanMetricsOverrides{TargetInfoMetricName: "target-info"}}}},
+			expErr:    "metrics_generator.processor.span_metrics.target_info_metric_name \"target-info\" is not a valid metric name",
+		},
+		{
+			name:      "read.exemplar_policy valid",
+			overrides: overrides.Overrides{Read: overrides.ReadOverrides{MaxExemplars: 5, ExemplarPolicy: "slowest"}},
+		},
+		{
+			name:      "read.exemplar_policy invalid",
+			overrides: overrides.Overrides{Read: overrides.ReadOverrides{ExemplarPolicy: "fastest"}},
+			expErr:    "read.exemplar_policy is not valid: unknown exemplar policy \"fastest\", must be one of any, errors or slowest",
+		},
+		{
+			name:      "read.max_exemplars negative",
+			overrides: overrides.Overrides{Read: overrides.ReadOverrides{MaxExemplars: -1}},
+			expErr:    "read.max_exemplars must not be negative (-1)",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -150,6 +192,16 @@ func Test_overridesValidator(t *testing.T) {
 			},
 			expErr: "invalid include policy: invalid match type: invalid",
 		},
+		{
+			name: "metrics_generator.processor.span_metrics.target_info_metric_name invalid",
+			cfg:  Config{},
+			limits: client.Limits{
+				MetricsGenerator: client.LimitsMetricsGenerator{Processor: client.LimitsMetricsGeneratorProcessor{SpanMetrics: client.LimitsMetricsGeneratorProcessorSpanMetrics{
+					TargetInfoMetricName: func() *string { s := "0target_info"; return &s }(),
+				}}},
+			},
+			expErr: "metrics_generator.processor.span_metrics.target_info_metric_name \"0target_info\" is not a valid metric name",
+		},
 		{
 			name: "metrics_generator.collection_interval valid",
 			cfg:  Config{},
@@ -179,6 +231,25 @@ func Test_overridesValidator(t *testing.T) {
 			},
 			expErr: "metrics_generator.collection_interval \"10m0s\" is outside acceptable range of 15s to 5m",
 		},
+		{
+			name: "storage.parquet_dedicated_columns valid",
+			cfg:  Config{},
+			limits: client.Limits{
+				Storage: &client.LimitsStorage{
+					DedicatedColumns: &backend.DedicatedColumns{{Scope: "span", Name: "http.user_agent", Type: "string"}},
+				},
+			},
+		},
+		{
+			name: "storage.parquet_dedicated_columns invalid",
+			cfg:  Config{},
+			limits: client.Limits{
+				Storage: &client.LimitsStorage{
+					DedicatedColumns: &backend.DedicatedColumns{{Scope: "span", Name: "", Type: "string"}},
+				},
+			},
+			expErr: "storage.parquet_dedicated_columns: dedicated column invalid: name must not be empty",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -83,8 +83,10 @@ func allRoles() map[cache.Role]struct{} {
 		cache.RoleParquetColumnIdx,
 		cache.RoleParquetOffsetIdx,
 		cache.RoleTraceIDIdx,
+		cache.RoleAttributeIdx,
 		cache.RoleFrontendSearch,
 		cache.RoleParquetPage,
+		cache.RoleTraceQLFetch,
 	}
 
 	roles := map[cache.Role]struct{}{}
@@ -0,0 +1,28 @@
+package compactor
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/grafana/tempo/tempodb"
+)
+
+const queryParamTenant = "tenant"
+
+// CompactionPlanHandler returns the compaction jobs that are pending and in progress as JSON. The optional
+// tenant query parameter limits the plan to a single tenant.
+func (c *Compactor) CompactionPlanHandler(w http.ResponseWriter, r *http.Request) {
+	plan, err := c.store.CompactionPlan(r.URL.Query().Get(queryParamTenant))
+	if errors.Is(err, tempodb.ErrCompactionNotEnabled) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(plan)
+}
@@ -2,6 +2,7 @@ package compactor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"hash/fnv"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/grafana/tempo/modules/overrides"
+	userconfigurableoverrides "github.com/grafana/tempo/modules/overrides/userconfigurable/client"
 	"github.com/grafana/tempo/modules/storage"
 	"github.com/grafana/tempo/pkg/model"
 	tempoUtil "github.com/grafana/tempo/pkg/util"
@@ -41,6 +43,9 @@ type Compactor struct {
 	cfg       *Config
 	store     storage.Store
 	overrides overrides.Interface
+	// overridesClient is used to delete the user-configurable overrides of deleted tenants and to apply
+	// dedicated columns, it's nil if user-configurable overrides are disabled.
+	overridesClient userconfigurableoverrides.Client
 
 	// Ring used for sharding compactions.
 	ringLifecycler *ring.BasicLifecycler
@@ -51,11 +56,12 @@ type Compactor struct {
 }
 
 // New makes a new Compactor.
-func New(cfg Config, store storage.Store, overrides overrides.Interface, reg prometheus.Registerer) (*Compactor, error) {
+func New(cfg Config, store storage.Store, overrides overrides.Interface, overridesClient userconfigurableoverrides.Client, reg prometheus.Registerer) (*Compactor, error) {
 	c := &Compactor{
-		cfg:       &cfg,
-		store:     store,
-		overrides: overrides,
+		cfg:             &cfg,
+		store:           store,
+		overrides:       overrides,
+		overridesClient: overridesClient,
 	}
 
 	if c.isSharded() {
@@ -163,6 +169,26 @@ func (c *Compactor) running(ctx context.Context) error {
 		}
 	}
 
+	if c.cfg.Scrubber.Enabled {
+		level.Info(log.Logger).Log("msg", "enabling scrubber")
+		err := c.store.EnableScrubbing(ctx, &c.cfg.Scrubber, c)
+		if err != nil {
+			return fmt.Errorf("failed to enable scrubber: %w", err)
+		}
+	}
+
+	if c.cfg.DedicatedColumns.Enabled {
+		if c.cfg.DedicatedColumns.AutoApply && c.overridesClient == nil {
+			return errors.New("auto applying dedicated columns requires user-configurable overrides")
+		}
+
+		level.Info(log.Logger).Log("msg", "enabling dedicated columns recommendations")
+		err := c.store.EnableDedicatedColumnsRecommendations(ctx, &c.cfg.DedicatedColumns, c, c)
+		if err != nil {
+			return fmt.Errorf("failed to enable dedicated columns recommendations: %w", err)
+		}
+	}
+
 	if c.subservices != nil {
 		select {
 		case <-ctx.Done():
@@ -262,6 +288,25 @@ func (c *Compactor) MaxCompactionRangeForTenant(tenantID string) time.Duration {
 	return c.overrides.MaxCompactionRange(tenantID)
 }
 
+func (c *Compactor) MaxCompactionBytesPerCycleForTenant(tenantID string) uint64 {
+	return c.overrides.MaxCompactionBytesPerCycle(tenantID)
+}
+
+func (c *Compactor) MaxCompactionJobsPerCycleForTenant(tenantID string) int {
+	return c.overrides.MaxCompactionJobsPerCycle(tenantID)
+}
+
+// BlocksPerTenant returns the number of blocks of each tenant in the blocklist.
+func (c *Compactor) BlocksPerTenant() map[string]float64 {
+	tenants := c.store.Tenants()
+
+	blocks := make(map[string]float64, len(tenants))
+	for _, tenant := range tenants {
+		blocks[tenant] = float64(len(c.store.BlockMetas(tenant)))
+	}
+	return blocks
+}
+
 func (c *Compactor) isSharded() bool {
 	return c.cfg.ShardingRing.KVStore.Store != ""
 }
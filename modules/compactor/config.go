@@ -14,10 +14,12 @@ import (
 )
 
 type Config struct {
-	Disabled        bool                    `yaml:"disabled,omitempty"`
-	ShardingRing    RingConfig              `yaml:"ring,omitempty"`
-	Compactor       tempodb.CompactorConfig `yaml:"compaction"`
-	OverrideRingKey string                  `yaml:"override_ring_key"`
+	Disabled         bool                           `yaml:"disabled,omitempty"`
+	ShardingRing     RingConfig                     `yaml:"ring,omitempty"`
+	Compactor        tempodb.CompactorConfig        `yaml:"compaction"`
+	Scrubber         tempodb.ScrubberConfig         `yaml:"scrubber"`
+	DedicatedColumns tempodb.DedicatedColumnsConfig `yaml:"dedicated_columns"`
+	OverrideRingKey  string                         `yaml:"override_ring_key"`
 }
 
 // RegisterFlagsAndApplyDefaults registers the flags.
@@ -39,7 +41,20 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	f.IntVar(&cfg.Compactor.MaxCompactionObjects, util.PrefixConfig(prefix, "compaction.max-objects-per-block"), 6000000, "Maximum number of traces in a compacted block.")
 	f.Uint64Var(&cfg.Compactor.MaxBlockBytes, util.PrefixConfig(prefix, "compaction.max-block-bytes"), 100*1024*1024*1024 /* 100GB */, "Maximum size of a compacted block.")
 	f.DurationVar(&cfg.Compactor.MaxCompactionRange, util.PrefixConfig(prefix, "compaction.compaction-window"), time.Hour, "Maximum time window across which to compact blocks.")
+	f.IntVar(&cfg.Compactor.JobConcurrency, util.PrefixConfig(prefix, "compaction.job-concurrency"), 1, "Number of goroutines combining the traces of a compaction job.")
+	f.IntVar(&cfg.Compactor.MemoryBudgetBytes, util.PrefixConfig(prefix, "compaction.memory-budget-bytes"), 0, "Estimated size of the copies of traces a compaction job buffers in memory before combining them. Copies that don't fit are spilled to disk. 0 disables spilling.")
+	f.StringVar(&cfg.Compactor.SpillPath, util.PrefixConfig(prefix, "compaction.spill-path"), "", "Directory of the traces spilled to disk during compaction. Defaults to the directory for temporary files.")
+	f.BoolVar(&cfg.Compactor.ConversionEnabled, util.PrefixConfig(prefix, "compaction.conversion-enabled"), false, "Convert blocks in older versions to the configured block version before compacting.")
 	f.BoolVar(&cfg.Disabled, util.PrefixConfig(prefix, "disabled"), false, "Disable compaction.")
+	f.BoolVar(&cfg.Scrubber.Enabled, util.PrefixConfig(prefix, "scrubber.enabled"), false, "Enable background verification of blocks.")
+	f.DurationVar(&cfg.Scrubber.Interval, util.PrefixConfig(prefix, "scrubber.interval"), tempodb.DefaultScrubberInterval, "How often a sample of blocks is verified.")
+	f.IntVar(&cfg.Scrubber.BlocksPerCycle, util.PrefixConfig(prefix, "scrubber.blocks-per-cycle"), tempodb.DefaultScrubberBlocksPerCycle, "Number of blocks verified per interval.")
+	f.BoolVar(&cfg.DedicatedColumns.Enabled, util.PrefixConfig(prefix, "dedicated-columns.enabled"), false, "Enable periodic dedicated columns recommendations.")
+	f.DurationVar(&cfg.DedicatedColumns.Interval, util.PrefixConfig(prefix, "dedicated-columns.interval"), tempodb.DefaultDedicatedColumnsInterval, "How often the recent blocks of each tenant are analyzed.")
+	f.IntVar(&cfg.DedicatedColumns.BlocksPerTenant, util.PrefixConfig(prefix, "dedicated-columns.blocks-per-tenant"), tempodb.DefaultDedicatedColumnsBlocksPerTenant, "Number of the most recent blocks of a tenant that are analyzed.")
+	f.Float64Var(&cfg.DedicatedColumns.MinShare, util.PrefixConfig(prefix, "dedicated-columns.min-share"), tempodb.DefaultDedicatedColumnsMinShare, "Fraction of the attribute bytes of its scope an attribute needs to be recommended.")
+	f.BoolVar(&cfg.DedicatedColumns.AutoApply, util.PrefixConfig(prefix, "dedicated-columns.auto-apply"), false, "Store the recommended dedicated columns in the user-configurable overrides of the tenant.")
+	f.IntVar(&cfg.DedicatedColumns.MaxChanges, util.PrefixConfig(prefix, "dedicated-columns.max-changes"), tempodb.DefaultDedicatedColumnsMaxChanges, "Maximum number of dedicated columns added or removed per tenant each interval when auto applying.")
 	cfg.OverrideRingKey = compactorRingKey
 }
 
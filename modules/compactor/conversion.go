@@ -0,0 +1,26 @@
+package compactor
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/grafana/tempo/tempodb"
+)
+
+// ConversionStatusHandler returns the progress of converting blocks in older versions to the configured block
+// version as JSON. The optional tenant query parameter limits the response to a single tenant.
+func (c *Compactor) ConversionStatusHandler(w http.ResponseWriter, r *http.Request) {
+	status, err := c.store.ConversionStatus(r.URL.Query().Get(queryParamTenant))
+	if errors.Is(err, tempodb.ErrConversionNotEnabled) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,37 @@
+package compactor
+
+import (
+	"context"
+	"errors"
+
+	userconfigurableoverrides "github.com/grafana/tempo/modules/overrides/userconfigurable/client"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// DedicatedColumns implements tempodb.DedicatedColumnsOverrides
+func (c *Compactor) DedicatedColumns(tenantID string) backend.DedicatedColumns {
+	return c.overrides.DedicatedColumns(tenantID)
+}
+
+// SetDedicatedColumns implements tempodb.DedicatedColumnsOverrides. The columns are stored in the
+// user-configurable overrides of the tenant, other user-configurable overrides are kept.
+func (c *Compactor) SetDedicatedColumns(ctx context.Context, tenantID string, dcs backend.DedicatedColumns) error {
+	if c.overridesClient == nil {
+		return errors.New("user-configurable overrides are not enabled")
+	}
+
+	limits, version, err := c.overridesClient.Get(ctx, tenantID)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		limits, version, err = &userconfigurableoverrides.Limits{}, backend.VersionNew, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if limits.Storage == nil {
+		limits.Storage = &userconfigurableoverrides.LimitsStorage{}
+	}
+	limits.Storage.DedicatedColumns = &dcs
+	_, err = c.overridesClient.Set(ctx, tenantID, limits, version)
+	return err
+}
@@ -0,0 +1,40 @@
+package compactor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides/userconfigurable/client"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestSetDedicatedColumns(t *testing.T) {
+	forwarders := []string{"fwd"}
+	overridesClient := &mockOverridesClient{
+		limits: map[string]*client.Limits{
+			"tenant-1": {Forwarders: &forwarders},
+		},
+	}
+	c := &Compactor{overridesClient: overridesClient}
+
+	dcs := backend.DedicatedColumns{{Scope: "span", Name: "http.user_agent", Type: "string"}}
+
+	// existing user-configurable overrides are kept
+	require.NoError(t, c.SetDedicatedColumns(context.Background(), "tenant-1", dcs))
+	require.Equal(t, &client.Limits{
+		Forwarders: &forwarders,
+		Storage:    &client.LimitsStorage{DedicatedColumns: &dcs},
+	}, overridesClient.limits["tenant-1"])
+
+	// user-configurable overrides are created
+	require.NoError(t, c.SetDedicatedColumns(context.Background(), "tenant-2", dcs))
+	require.Equal(t, &client.Limits{
+		Storage: &client.LimitsStorage{DedicatedColumns: &dcs},
+	}, overridesClient.limits["tenant-2"])
+
+	// user-configurable overrides are disabled
+	c.overridesClient = nil
+	require.Error(t, c.SetDedicatedColumns(context.Background(), "tenant-1", dcs))
+}
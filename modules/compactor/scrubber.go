@@ -0,0 +1,26 @@
+package compactor
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/grafana/tempo/tempodb"
+)
+
+// ScrubberStatusHandler returns the blocks the scrubber found to be corrupted as JSON. The optional tenant
+// query parameter limits the response to a single tenant.
+func (c *Compactor) ScrubberStatusHandler(w http.ResponseWriter, r *http.Request) {
+	status, err := c.store.ScrubberStatus(r.URL.Query().Get(queryParamTenant))
+	if errors.Is(err, tempodb.ErrScrubberNotEnabled) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(status)
+}
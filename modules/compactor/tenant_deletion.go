@@ -0,0 +1,195 @@
+package compactor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/user"
+
+	"github.com/grafana/tempo/pkg/util/log"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+const queryParamDryRun = "dry_run"
+
+// TenantDeletionStatus reports the data that is left of a tenant. For a dry run it reports the data that
+// would be deleted.
+type TenantDeletionStatus struct {
+	TenantID                  string     `json:"tenant_id"`
+	DryRun                    bool       `json:"dry_run,omitempty"`
+	DeletionRequested         bool       `json:"deletion_requested"`
+	RequestedTime             *time.Time `json:"requested_time,omitempty"`
+	Blocks                    int        `json:"blocks"`
+	CompactedBlocks           int        `json:"compacted_blocks"`
+	Bytes                     uint64     `json:"bytes"`
+	UserConfigurableOverrides bool       `json:"user_configurable_overrides"`
+	// Finished is true once no blocks are left in the backend. Ingesters and metrics-generators purge
+	// their data independently once they find the deletion mark.
+	Finished bool `json:"finished"`
+}
+
+// DeleteTenantHandler marks all data of the tenant for deletion and removes its user-configurable overrides.
+// Blocks are purged by the compactors during the next retention cycle. With dry_run=true nothing is deleted
+// and the data that would be deleted is returned.
+func (c *Compactor) DeleteTenantHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	tenantID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	dryRun := false
+	if v := r.URL.Query().Get(queryParamDryRun); v != "" {
+		dryRun, err = strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "could not parse dry_run, must be a boolean value", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if !dryRun {
+		_, err = c.store.MarkTenantDeleted(ctx, tenantID)
+		if err != nil {
+			level.Error(log.Logger).Log("msg", "failed to mark tenant for deletion", "tenant", tenantID, "err", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		err = c.deleteUserConfigurableOverrides(ctx, tenantID)
+		if err != nil {
+			level.Error(log.Logger).Log("msg", "failed to delete user-configurable overrides of deleted tenant", "tenant", tenantID, "err", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		level.Info(log.Logger).Log("msg", "tenant deletion requested", "tenant", tenantID)
+	}
+
+	status, err := c.tenantDeletionStatus(ctx, tenantID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	status.DryRun = dryRun
+
+	statusCode := http.StatusAccepted
+	if dryRun {
+		statusCode = http.StatusOK
+	}
+	writeTenantDeletionStatus(w, statusCode, status)
+}
+
+// CancelTenantDeletionHandler removes the deletion mark of the tenant so its ID can be used again. Data that was
+// already purged and the removed user-configurable overrides are not restored.
+func (c *Compactor) CancelTenantDeletionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	tenantID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = c.store.UnmarkTenantDeleted(ctx, tenantID)
+	if err != nil {
+		level.Error(log.Logger).Log("msg", "failed to remove tenant deletion mark", "tenant", tenantID, "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	level.Info(log.Logger).Log("msg", "tenant deletion canceled", "tenant", tenantID)
+
+	status, err := c.tenantDeletionStatus(ctx, tenantID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeTenantDeletionStatus(w, http.StatusOK, status)
+}
+
+// DeleteTenantStatusHandler reports the progress of a tenant deletion.
+func (c *Compactor) DeleteTenantStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	tenantID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	status, err := c.tenantDeletionStatus(ctx, tenantID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeTenantDeletionStatus(w, http.StatusOK, status)
+}
+
+// tenantDeletionStatus builds the status from the blocklist, it's updated every poll cycle.
+func (c *Compactor) tenantDeletionStatus(ctx context.Context, tenantID string) (*TenantDeletionStatus, error) {
+	mark, err := c.store.TenantDeletionMark(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	metas := c.store.BlockMetas(tenantID)
+	compactedMetas := c.store.CompactedBlockMetas(tenantID)
+
+	status := &TenantDeletionStatus{
+		TenantID:          tenantID,
+		DeletionRequested: mark != nil,
+		Blocks:            len(metas),
+		CompactedBlocks:   len(compactedMetas),
+	}
+	if mark != nil {
+		status.RequestedTime = &mark.RequestedTime
+		status.Finished = len(metas) == 0 && len(compactedMetas) == 0
+	}
+	for _, m := range metas {
+		status.Bytes += m.Size
+	}
+	for _, m := range compactedMetas {
+		status.Bytes += m.Size
+	}
+
+	if c.overridesClient != nil {
+		_, _, err = c.overridesClient.Get(ctx, tenantID)
+		switch {
+		case err == nil:
+			status.UserConfigurableOverrides = true
+		case !errors.Is(err, backend.ErrDoesNotExist):
+			return nil, err
+		}
+	}
+
+	return status, nil
+}
+
+func (c *Compactor) deleteUserConfigurableOverrides(ctx context.Context, tenantID string) error {
+	if c.overridesClient == nil {
+		return nil
+	}
+
+	_, version, err := c.overridesClient.Get(ctx, tenantID)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.overridesClient.Delete(ctx, tenantID, version)
+}
+
+func writeTenantDeletionStatus(w http.ResponseWriter, statusCode int, status *TenantDeletionStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(status)
+}
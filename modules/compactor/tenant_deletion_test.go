@@ -0,0 +1,153 @@
+package compactor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides/userconfigurable/client"
+	"github.com/grafana/tempo/modules/storage"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+type mockDeletionStore struct {
+	storage.Store
+
+	marks          map[string]*backend.TenantDeletionMark
+	metas          []*backend.BlockMeta
+	compactedMetas []*backend.CompactedBlockMeta
+}
+
+func (m *mockDeletionStore) MarkTenantDeleted(_ context.Context, tenantID string) (*backend.TenantDeletionMark, error) {
+	if m.marks[tenantID] == nil {
+		m.marks[tenantID] = backend.NewTenantDeletionMark()
+	}
+	return m.marks[tenantID], nil
+}
+
+func (m *mockDeletionStore) UnmarkTenantDeleted(_ context.Context, tenantID string) error {
+	delete(m.marks, tenantID)
+	return nil
+}
+
+func (m *mockDeletionStore) TenantDeletionMark(_ context.Context, tenantID string) (*backend.TenantDeletionMark, error) {
+	return m.marks[tenantID], nil
+}
+
+func (m *mockDeletionStore) BlockMetas(string) []*backend.BlockMeta {
+	return m.metas
+}
+
+func (m *mockDeletionStore) CompactedBlockMetas(string) []*backend.CompactedBlockMeta {
+	return m.compactedMetas
+}
+
+type mockOverridesClient struct {
+	client.Client
+
+	limits map[string]*client.Limits
+}
+
+func (m *mockOverridesClient) Get(_ context.Context, tenantID string) (*client.Limits, backend.Version, error) {
+	if l, ok := m.limits[tenantID]; ok {
+		return l, "1", nil
+	}
+	return nil, backend.VersionNew, backend.ErrDoesNotExist
+}
+
+func (m *mockOverridesClient) Set(_ context.Context, tenantID string, limits *client.Limits, _ backend.Version) (backend.Version, error) {
+	m.limits[tenantID] = limits
+	return "1", nil
+}
+
+func (m *mockOverridesClient) Delete(_ context.Context, tenantID string, _ backend.Version) error {
+	delete(m.limits, tenantID)
+	return nil
+}
+
+func TestDeleteTenantHandler(t *testing.T) {
+	store := &mockDeletionStore{
+		marks: map[string]*backend.TenantDeletionMark{},
+		metas: []*backend.BlockMeta{
+			{Size: 100},
+			{Size: 200},
+		},
+		compactedMetas: []*backend.CompactedBlockMeta{
+			{BlockMeta: backend.BlockMeta{Size: 50}},
+		},
+	}
+	overridesClient := &mockOverridesClient{
+		limits: map[string]*client.Limits{"test": {}},
+	}
+	c := &Compactor{
+		store:           store,
+		overridesClient: overridesClient,
+	}
+
+	do := func(handler http.HandlerFunc, method, target string) (int, *TenantDeletionStatus) {
+		req := httptest.NewRequest(method, target, nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), "test"))
+		rec := httptest.NewRecorder()
+
+		handler(rec, req)
+
+		status := &TenantDeletionStatus{}
+		if rec.Code < 300 {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), status))
+		}
+		return rec.Code, status
+	}
+
+	// dry run doesn't delete anything
+	code, status := do(c.DeleteTenantHandler, http.MethodPost, "/compactor/delete_tenant?dry_run=true")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, &TenantDeletionStatus{
+		TenantID:                  "test",
+		DryRun:                    true,
+		Blocks:                    2,
+		CompactedBlocks:           1,
+		Bytes:                     350,
+		UserConfigurableOverrides: true,
+	}, status)
+	require.Empty(t, store.marks)
+	require.Len(t, overridesClient.limits, 1)
+
+	code, _ = do(c.DeleteTenantHandler, http.MethodPost, "/compactor/delete_tenant?dry_run=foo")
+	require.Equal(t, http.StatusBadRequest, code)
+
+	// delete marks the tenant and removes the overrides
+	code, status = do(c.DeleteTenantHandler, http.MethodPost, "/compactor/delete_tenant")
+	require.Equal(t, http.StatusAccepted, code)
+	require.True(t, status.DeletionRequested)
+	require.NotNil(t, status.RequestedTime)
+	require.False(t, status.Finished)
+	require.False(t, status.UserConfigurableOverrides)
+	require.NotNil(t, store.marks["test"])
+	require.Empty(t, overridesClient.limits)
+
+	// finished once all blocks are gone
+	store.metas = nil
+	store.compactedMetas = nil
+	code, status = do(c.DeleteTenantStatusHandler, http.MethodGet, "/compactor/delete_tenant_status")
+	require.Equal(t, http.StatusOK, code)
+	require.True(t, status.DeletionRequested)
+	require.True(t, status.Finished)
+	require.Equal(t, 0, status.Blocks)
+
+	// canceling removes the mark
+	code, status = do(c.CancelTenantDeletionHandler, http.MethodDelete, "/compactor/delete_tenant")
+	require.Equal(t, http.StatusOK, code)
+	require.False(t, status.DeletionRequested)
+	require.Nil(t, status.RequestedTime)
+	require.Empty(t, store.marks)
+
+	// requests without a tenant are rejected
+	rec := httptest.NewRecorder()
+	c.DeleteTenantStatusHandler(rec, httptest.NewRequest(http.MethodGet, "/compactor/delete_tenant_status", nil))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
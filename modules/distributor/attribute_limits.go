@@ -0,0 +1,114 @@
+package distributor
+
+import (
+	"unicode/utf8"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+// TruncatedAttributesAttribute is the number of attributes of a span that were dropped or truncated by the
+// distributor because they exceeded the per tenant attribute limits.
+const TruncatedAttributesAttribute = "tempo.truncated_attributes"
+
+const (
+	// reasonAttributeCount indicates attributes dropped because the span has too many attributes
+	reasonAttributeCount = "attribute_count"
+	// reasonAttributeBytes indicates attribute values truncated because they are too long
+	reasonAttributeBytes = "attribute_bytes"
+)
+
+var metricAttributesTruncated = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "distributor_attributes_truncated_total",
+	Help:      "The total number of attributes dropped or truncated because they exceeded the attribute limits per tenant",
+}, []string{"tenant", "reason"})
+
+type attributeLimits struct {
+	maxAttributesPerSpan int
+	maxAttributeBytes    int
+}
+
+func (l attributeLimits) enabled() bool {
+	return l.maxAttributesPerSpan > 0 || l.maxAttributeBytes > 0
+}
+
+// applyAttributeLimits drops the span attributes beyond the attribute count limit and truncates resource and span
+// attribute values longer than the size limit. Spans with dropped or truncated attributes are marked with the
+// number of affected attributes.
+func applyAttributeLimits(batches []*v1.ResourceSpans, userID string, limits attributeLimits) {
+	if !limits.enabled() {
+		return
+	}
+
+	dropped, truncated := 0, 0
+	for _, b := range batches {
+		if b.Resource != nil {
+			truncated += truncateAttributeValues(b.Resource.Attributes, limits.maxAttributeBytes)
+		}
+
+		for _, ss := range b.ScopeSpans {
+			for _, s := range ss.Spans {
+				spanDropped := 0
+				if limits.maxAttributesPerSpan > 0 && len(s.Attributes) > limits.maxAttributesPerSpan {
+					spanDropped = len(s.Attributes) - limits.maxAttributesPerSpan
+					s.Attributes = s.Attributes[:limits.maxAttributesPerSpan]
+					s.DroppedAttributesCount += uint32(spanDropped)
+				}
+				spanTruncated := truncateAttributeValues(s.Attributes, limits.maxAttributeBytes)
+
+				if spanDropped+spanTruncated > 0 {
+					s.Attributes = append(s.Attributes, &v1_common.KeyValue{
+						Key:   TruncatedAttributesAttribute,
+						Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_IntValue{IntValue: int64(spanDropped + spanTruncated)}},
+					})
+				}
+				dropped += spanDropped
+				truncated += spanTruncated
+			}
+		}
+	}
+
+	if dropped > 0 {
+		metricAttributesTruncated.WithLabelValues(userID, reasonAttributeCount).Add(float64(dropped))
+	}
+	if truncated > 0 {
+		metricAttributesTruncated.WithLabelValues(userID, reasonAttributeBytes).Add(float64(truncated))
+	}
+}
+
+// truncateAttributeValues truncates the string and bytes values longer than maxBytes and returns the number of
+// truncated values. Strings are cut at a rune boundary.
+func truncateAttributeValues(attrs []*v1_common.KeyValue, maxBytes int) int {
+	if maxBytes <= 0 {
+		return 0
+	}
+
+	truncated := 0
+	for _, a := range attrs {
+		if a == nil || a.Value == nil {
+			continue
+		}
+
+		switch v := a.Value.Value.(type) {
+		case *v1_common.AnyValue_StringValue:
+			if len(v.StringValue) > maxBytes {
+				n := maxBytes
+				for n > 0 && !utf8.RuneStart(v.StringValue[n]) {
+					n--
+				}
+				v.StringValue = v.StringValue[:n]
+				truncated++
+			}
+		case *v1_common.AnyValue_BytesValue:
+			if len(v.BytesValue) > maxBytes {
+				v.BytesValue = v.BytesValue[:maxBytes]
+				truncated++
+			}
+		}
+	}
+	return truncated
+}
@@ -7,7 +7,9 @@ import (
 	"github.com/grafana/dskit/flagext"
 	ring_client "github.com/grafana/dskit/ring/client"
 
+	"github.com/grafana/tempo/modules/distributor/enrichment"
 	"github.com/grafana/tempo/modules/distributor/forwarder"
+	"github.com/grafana/tempo/modules/distributor/usage"
 	"github.com/grafana/tempo/pkg/util"
 )
 
@@ -38,6 +40,8 @@ type Config struct {
 	MetricReceivedSpans MetricReceivedSpansConfig `yaml:"metric_received_spans,omitempty"`
 
 	Forwarders forwarder.ConfigList `yaml:"forwarders"`
+	Usage      usage.Config         `yaml:"usage,omitempty"`
+	Enrichment enrichment.Config    `yaml:"enrichment,omitempty"`
 
 	// disables write extension with inactive ingesters. Use this along with ingester.lifecycler.unregister_on_shutdown = true
 	//  note that setting these two config values reduces tolerance to failures on rollout b/c there is always one guaranteed to be failing replica
@@ -47,10 +51,24 @@ type Config struct {
 	// provided duration
 	RetryAfterOnResourceExhausted time.Duration `yaml:"retry_after_on_resource_exhausted"`
 
+	LoadShedding LoadSheddingConfig `yaml:"load_shedding"`
+
 	// For testing.
 	factory ring_client.PoolAddrFunc `yaml:"-"`
 }
 
+// LoadSheddingConfig configures the rejection of pushes while the distributor is overloaded.
+type LoadSheddingConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// MaxInflightBytes is the most proto bytes of pushes processed at the same time.
+	MaxInflightBytes uint64 `yaml:"max_inflight_bytes"`
+	// TargetLatency of the pushes to the ingesters. The inflight bytes limit is lowered while the latency is
+	// above the target. 0 disables the adaptive limit.
+	TargetLatency time.Duration `yaml:"target_latency"`
+	// RetryAfter is the delay clients are asked to wait before retrying a shed push.
+	RetryAfter time.Duration `yaml:"retry_after"`
+}
+
 type LogReceivedSpansConfig struct {
 	Enabled              bool `yaml:"enabled"`
 	IncludeAllAttributes bool `yaml:"include_all_attributes"`
@@ -75,4 +93,11 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	f.BoolVar(&cfg.LogReceivedSpans.Enabled, util.PrefixConfig(prefix, "log-received-spans.enabled"), false, "Enable to log every received span to help debug ingestion or calculate span error distributions using the logs.")
 	f.BoolVar(&cfg.LogReceivedSpans.IncludeAllAttributes, util.PrefixConfig(prefix, "log-received-spans.include-attributes"), false, "Enable to include span attributes in the logs.")
 	f.BoolVar(&cfg.LogReceivedSpans.FilterByStatusError, util.PrefixConfig(prefix, "log-received-spans.filter-by-status-error"), false, "Enable to filter out spans without status error.")
+
+	f.BoolVar(&cfg.LoadShedding.Enabled, util.PrefixConfig(prefix, "load-shedding.enabled"), false, "Enable to reject pushes while the distributor is overloaded.")
+	f.Uint64Var(&cfg.LoadShedding.MaxInflightBytes, util.PrefixConfig(prefix, "load-shedding.max-inflight-bytes"), 512*1024*1024, "Maximum proto bytes of pushes processed at the same time.")
+	f.DurationVar(&cfg.LoadShedding.TargetLatency, util.PrefixConfig(prefix, "load-shedding.target-latency"), time.Second, "Target latency of pushes to the ingesters. The inflight bytes limit is lowered while the latency is above the target. 0 disables the adaptive limit.")
+	f.DurationVar(&cfg.LoadShedding.RetryAfter, util.PrefixConfig(prefix, "load-shedding.retry-after"), 5*time.Second, "Delay clients are asked to wait before retrying a shed push.")
+
+	cfg.Usage.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "usage"), f)
 }
@@ -26,8 +26,10 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/health/grpc_health_v1"
 
+	"github.com/grafana/tempo/modules/distributor/enrichment"
 	"github.com/grafana/tempo/modules/distributor/forwarder"
 	"github.com/grafana/tempo/modules/distributor/receiver"
+	"github.com/grafana/tempo/modules/distributor/usage"
 	generator_client "github.com/grafana/tempo/modules/generator/client"
 	ingester_client "github.com/grafana/tempo/modules/ingester/client"
 	"github.com/grafana/tempo/modules/overrides"
@@ -50,6 +52,8 @@ const (
 	reasonInternalError = "internal_error"
 	// reasonUnknown indicates a pushByte error at the ingester level not related to GRPC
 	reasonUnknown = "unknown_error"
+	// reasonLoadShed indicates that the distributor was overloaded
+	reasonLoadShed = "load_shed"
 
 	distributorRingKey = "distributor"
 )
@@ -90,6 +94,26 @@ var (
 		Name:      "distributor_bytes_received_total",
 		Help:      "The total number of proto bytes received per tenant",
 	}, []string{"tenant"})
+	metricDryRunSpans = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_dry_run_spans_total",
+		Help:      "The total number of spans accepted but not written because the tenant is in dry run mode",
+	}, []string{"tenant"})
+	metricDryRunBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_dry_run_bytes_total",
+		Help:      "The total number of proto bytes accepted but not written because the tenant is in dry run mode",
+	}, []string{"tenant"})
+	metricDryRunTraces = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_dry_run_traces_total",
+		Help:      "The total number of traces in pushes accepted but not written because the tenant is in dry run mode",
+	}, []string{"tenant"})
+	metricDryRunAttributes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_dry_run_attributes_total",
+		Help:      "The total number of resource and span attributes accepted but not written because the tenant is in dry run mode",
+	}, []string{"tenant", "scope"})
 	metricTracesPerBatch = promauto.NewHistogram(prometheus.HistogramOpts{
 		Namespace: "tempo",
 		Name:      "distributor_traces_per_batch",
@@ -138,8 +162,17 @@ type Distributor struct {
 	// Generic Forwarder
 	forwardersManager *forwarder.Manager
 
-	// Per-user rate limiter.
+	// Cost attribution usage tracker, nil if disabled
+	usage *usage.Tracker
+
+	// Span enrichment from lookup tables, nil if no tables are configured
+	enricher *enrichment.Enricher
+
+	// Per-user rate limiters.
 	ingestionRateLimiter *limiter.RateLimiter
+	spanRateLimiter      *limiter.RateLimiter
+
+	loadShedder *loadShedder
 
 	// Manager for subservices
 	subservices        *services.Manager
@@ -159,8 +192,8 @@ func New(cfg Config, clientCfg ingester_client.Config, ingestersRing ring.ReadRi
 
 	subservices := []services.Service(nil)
 
-	// Create the configured ingestion rate limit strategy (local or global).
-	var ingestionRateStrategy limiter.RateLimiterStrategy
+	// Create the configured ingestion rate limit strategies (local or global).
+	var ingestionRateStrategy, spanRateStrategy limiter.RateLimiterStrategy
 	var distributorRing *ring.Ring
 
 	if o.IngestionRateStrategy() == overrides.GlobalIngestionRateStrategy {
@@ -171,6 +204,7 @@ func New(cfg Config, clientCfg ingester_client.Config, ingestersRing ring.ReadRi
 		}
 		subservices = append(subservices, lifecycler)
 		ingestionRateStrategy = newGlobalIngestionRateStrategy(o, lifecycler)
+		spanRateStrategy = newGlobalSpanRateStrategy(o, lifecycler)
 
 		ring, err := ring.New(lifecyclerCfg.RingConfig, "distributor", cfg.OverrideRingKey, logger, prometheus.WrapRegistererWithPrefix("tempo_", reg))
 		if err != nil {
@@ -180,6 +214,7 @@ func New(cfg Config, clientCfg ingester_client.Config, ingestersRing ring.ReadRi
 		subservices = append(subservices, distributorRing)
 	} else {
 		ingestionRateStrategy = newLocalIngestionRateStrategy(o)
+		spanRateStrategy = newLocalSpanRateStrategy(o)
 	}
 
 	pool := ring_client.NewPool("distributor_pool",
@@ -198,6 +233,8 @@ func New(cfg Config, clientCfg ingester_client.Config, ingestersRing ring.ReadRi
 		pool:                 pool,
 		DistributorRing:      distributorRing,
 		ingestionRateLimiter: limiter.NewRateLimiter(ingestionRateStrategy, 10*time.Second),
+		spanRateLimiter:      limiter.NewRateLimiter(spanRateStrategy, 10*time.Second),
+		loadShedder:          newLoadShedder(cfg.LoadShedding),
 		generatorClientCfg:   generatorClientCfg,
 		generatorsRing:       generatorsRing,
 		overrides:            o,
@@ -235,6 +272,18 @@ func New(cfg Config, clientCfg ingester_client.Config, ingestersRing ring.ReadRi
 		cfgReceivers = defaultReceivers
 	}
 
+	if cfg.Usage.CostAttribution.Enabled {
+		d.usage = usage.NewTracker(cfg.Usage.CostAttribution, o.CostAttributionDimensions)
+	}
+
+	if len(cfg.Enrichment.Tables) > 0 {
+		d.enricher, err = enrichment.New(cfg.Enrichment, o.EnrichmentLookups, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create span enricher: %w", err)
+		}
+		subservices = append(subservices, d.enricher)
+	}
+
 	receivers, err := receiver.New(cfgReceivers, d, middleware, cfg.RetryAfterOnResourceExhausted, loggingLevel)
 	if err != nil {
 		return nil, err
@@ -276,6 +325,11 @@ func (d *Distributor) stopping(_ error) error {
 	return services.StopManagerAndAwaitStopped(context.Background(), d.subservices)
 }
 
+// UsageTracker returns the usage tracker or nil if the tracker is disabled.
+func (d *Distributor) UsageTracker() *usage.Tracker {
+	return d.usage
+}
+
 func (d *Distributor) checkForRateLimits(tracesSize, spanCount int, userID string) error {
 	now := time.Now()
 	if !d.ingestionRateLimiter.AllowN(now, userID, tracesSize) {
@@ -293,6 +347,22 @@ func (d *Distributor) checkForRateLimits(tracesSize, spanCount int, userID strin
 			tracesSize, userID)
 	}
 
+	// the span rate limit is disabled by default
+	if d.overrides.IngestionRateLimitSpans(userID) > 0 && !d.spanRateLimiter.AllowN(now, userID, spanCount) {
+		overrides.RecordDiscardedSpans(spanCount, reasonRateLimited, userID)
+		limit := int(d.spanRateLimiter.Limit(now, userID))
+		var globalLimit int
+		if d.overrides.IngestionRateStrategy() == overrides.GlobalIngestionRateStrategy {
+			globalLimit = limit * d.DistributorRing.InstancesCount()
+		}
+		return status.Errorf(codes.ResourceExhausted,
+			"%s: ingestion rate limit (local: %d spans, global: %d spans) exceeded while adding %d spans for user %s",
+			overrides.ErrorPrefixRateLimited,
+			limit,
+			globalLimit,
+			spanCount, userID)
+	}
+
 	return nil
 }
 
@@ -318,6 +388,13 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 	if spanCount == 0 {
 		return &tempopb.PushResponse{}, nil
 	}
+
+	if !d.loadShedder.admit(userID, uint64(size)) {
+		overrides.RecordDiscardedSpans(spanCount, reasonLoadShed, userID)
+		return nil, d.loadShedder.shedError(userID, uint64(size))
+	}
+	defer d.loadShedder.release(userID, uint64(size))
+
 	// check limits
 	err = d.checkForRateLimits(size, spanCount, userID)
 	if err != nil {
@@ -341,6 +418,15 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 
 	batches := trace.Batches
 
+	applyAttributeLimits(batches, userID, attributeLimits{
+		maxAttributesPerSpan: d.overrides.IngestionMaxAttributesPerSpan(userID),
+		maxAttributeBytes:    d.overrides.IngestionMaxAttributeBytes(userID),
+	})
+
+	if d.enricher != nil {
+		d.enricher.Enrich(userID, batches)
+	}
+
 	if d.cfg.LogReceivedSpans.Enabled {
 		logSpans(batches, &d.cfg.LogReceivedSpans, d.logger)
 	}
@@ -351,12 +437,22 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 	metricBytesIngested.WithLabelValues(userID).Add(float64(size))
 	metricSpansIngested.WithLabelValues(userID).Add(float64(spanCount))
 
+	if d.usage != nil {
+		d.usage.Observe(userID, batches)
+	}
+
 	keys, rebatchedTraces, err := requestsByTraceID(batches, userID, spanCount)
 	if err != nil {
 		overrides.RecordDiscardedSpans(spanCount, reasonInternalError, userID)
 		return nil, err
 	}
 
+	// the push is validated and counted, but not written for tenants in dry run mode
+	if d.overrides.IngestionDryRun(userID) {
+		recordDryRun(batches, userID, spanCount, size, len(rebatchedTraces))
+		return nil, nil
+	}
+
 	err = d.sendToIngestersViaBytes(ctx, userID, spanCount, rebatchedTraces, keys)
 	if err != nil {
 		return nil, err
@@ -373,6 +469,26 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 	return nil, nil // PushRequest is ignored, so no reason to create one
 }
 
+func recordDryRun(batches []*v1.ResourceSpans, userID string, spanCount, size, traceCount int) {
+	metricDryRunSpans.WithLabelValues(userID).Add(float64(spanCount))
+	metricDryRunBytes.WithLabelValues(userID).Add(float64(size))
+	metricDryRunTraces.WithLabelValues(userID).Add(float64(traceCount))
+
+	resourceAttrs, spanAttrs := 0, 0
+	for _, b := range batches {
+		if b.Resource != nil {
+			resourceAttrs += len(b.Resource.Attributes)
+		}
+		for _, ss := range b.ScopeSpans {
+			for _, s := range ss.Spans {
+				spanAttrs += len(s.Attributes)
+			}
+		}
+	}
+	metricDryRunAttributes.WithLabelValues(userID, "resource").Add(float64(resourceAttrs))
+	metricDryRunAttributes.WithLabelValues(userID, "span").Add(float64(spanAttrs))
+}
+
 func (d *Distributor) sendToIngestersViaBytes(ctx context.Context, userID string, totalSpanCount int, traces []*rebatchedTrace, keys []uint32) error {
 	marshalledTraces := make([][]byte, len(traces))
 	for i, t := range traces {
@@ -396,6 +512,7 @@ func (d *Distributor) sendToIngestersViaBytes(ctx context.Context, userID string
 
 	writeRing := d.ingestersRing.ShuffleShard(userID, d.overrides.IngestionTenantShardSize(userID))
 
+	start := time.Now()
 	err := ring.DoBatch(ctx, op, writeRing, keys, func(ingester ring.InstanceDesc, indexes []int) error {
 		localCtx, cancel := context.WithTimeout(ctx, d.clientCfg.RemoteTimeout)
 		defer cancel()
@@ -432,6 +549,8 @@ func (d *Distributor) sendToIngestersViaBytes(ctx context.Context, userID string
 
 		return nil
 	}, func() {})
+	d.loadShedder.observeLatency(time.Since(start))
+
 	// if err != nil, we discarded everything because of an internal error
 	if err != nil {
 		overrides.RecordDiscardedSpans(totalSpanCount, reasonInternalError, userID)
@@ -22,6 +22,7 @@ import (
 	ring_client "github.com/grafana/dskit/ring/client"
 	"github.com/grafana/dskit/user"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -783,6 +784,103 @@ func TestDistributor(t *testing.T) {
 	}
 }
 
+func TestDryRun(t *testing.T) {
+	limits := overrides.Config{}
+	limits.RegisterFlagsAndApplyDefaults(&flag.FlagSet{})
+	limits.Defaults.Ingestion.DryRun = true
+
+	d := prepare(t, limits, nil)
+
+	batches := []*v1.ResourceSpans{
+		makeResourceSpans("test-service", []*v1.ScopeSpans{
+			makeScope(
+				makeSpan("0a0102030405060708090a0b0c0d0e0f", "dad44adc9a83b370", "Test Span1", nil,
+					makeAttribute("tag1", "value1")),
+				makeSpan("e3210a2b38097332d1fe43083ea93d29", "6c21c48da4dbd1a7", "Test Span2", nil,
+					makeAttribute("tag1", "value1"),
+					makeAttribute("tag2", "value2"))),
+		}, makeAttribute("resource_attribute1", "value1")),
+	}
+	resourceAttrs := len(batches[0].Resource.Attributes)
+
+	appendsBefore := 0.0
+	for i := 0; i < numIngesters; i++ {
+		appendsBefore += testutil.ToFloat64(metricIngesterAppends.WithLabelValues(fmt.Sprintf("ingester%d", i)))
+	}
+
+	response, err := d.PushTraces(ctx, batchesToTraces(t, batches))
+	require.NoError(t, err)
+	require.Nil(t, response)
+
+	appendsAfter := 0.0
+	for i := 0; i < numIngesters; i++ {
+		appendsAfter += testutil.ToFloat64(metricIngesterAppends.WithLabelValues(fmt.Sprintf("ingester%d", i)))
+	}
+	require.Equal(t, appendsBefore, appendsAfter, "dry run pushes must not be sent to the ingesters")
+
+	require.Equal(t, 2.0, testutil.ToFloat64(metricDryRunSpans.WithLabelValues("test")))
+	require.Equal(t, 2.0, testutil.ToFloat64(metricDryRunTraces.WithLabelValues("test")))
+	require.Greater(t, testutil.ToFloat64(metricDryRunBytes.WithLabelValues("test")), 0.0)
+	require.Equal(t, float64(resourceAttrs), testutil.ToFloat64(metricDryRunAttributes.WithLabelValues("test", "resource")))
+	require.Equal(t, 3.0, testutil.ToFloat64(metricDryRunAttributes.WithLabelValues("test", "span")))
+}
+
+func TestAttributeLimits(t *testing.T) {
+	limits := overrides.Config{}
+	limits.RegisterFlagsAndApplyDefaults(&flag.FlagSet{})
+	limits.Defaults.Ingestion.MaxAttributesPerSpan = 2
+	limits.Defaults.Ingestion.MaxAttributeBytes = 5
+
+	d := prepare(t, limits, nil)
+
+	batches := []*v1.ResourceSpans{
+		makeResourceSpans("test-service", []*v1.ScopeSpans{
+			makeScope(
+				makeSpan("0a0102030405060708090a0b0c0d0e0f", "dad44adc9a83b370", "Test Span1", nil,
+					makeAttribute("tag1", "value1"),
+					makeAttribute("tag2", "v2"),
+					makeAttribute("tag3", "value3")),
+				makeSpan("e3210a2b38097332d1fe43083ea93d29", "6c21c48da4dbd1a7", "Test Span2", nil,
+					makeAttribute("tag1", "v1"),
+					makeAttribute("tag2", "héllo"))),
+		}, makeAttribute("resource_attribute1", "value1")),
+	}
+
+	_, err := d.PushTraces(ctx, batchesToTraces(t, batches))
+	require.NoError(t, err)
+
+	// the push isn't refused, the attributes are dropped and truncated in place
+	require.Equal(t, 1.0, testutil.ToFloat64(metricAttributesTruncated.WithLabelValues("test", reasonAttributeCount)))
+	require.Equal(t, 4.0, testutil.ToFloat64(metricAttributesTruncated.WithLabelValues("test", reasonAttributeBytes)))
+}
+
+func TestApplyAttributeLimits(t *testing.T) {
+	span := makeSpan("0a0102030405060708090a0b0c0d0e0f", "dad44adc9a83b370", "Test Span", nil,
+		makeAttribute("tag1", "value1"),
+		makeAttribute("tag2", "héllo"),
+		makeAttribute("tag3", "v3"))
+	untouched := makeSpan("e3210a2b38097332d1fe43083ea93d29", "6c21c48da4dbd1a7", "Test Span", nil,
+		makeAttribute("tag1", "v1"))
+	batches := []*v1.ResourceSpans{
+		makeResourceSpans("test-service", []*v1.ScopeSpans{makeScope(span, untouched)}),
+	}
+
+	applyAttributeLimits(batches, "attribute-limits", attributeLimits{maxAttributesPerSpan: 2, maxAttributeBytes: 2})
+
+	// the resource attribute service.name is truncated, é is cut at the rune boundary
+	assert.Equal(t, "te", batches[0].Resource.Attributes[0].Value.GetStringValue())
+	assert.Equal(t, []*v1_common.KeyValue{
+		makeAttribute("tag1", "va"),
+		makeAttribute("tag2", "h"),
+		{Key: TruncatedAttributesAttribute, Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_IntValue{IntValue: 3}}},
+	}, span.Attributes)
+	assert.Equal(t, uint32(1), span.DroppedAttributesCount)
+	assert.Equal(t, []*v1_common.KeyValue{makeAttribute("tag1", "v1")}, untouched.Attributes)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metricAttributesTruncated.WithLabelValues("attribute-limits", reasonAttributeCount)))
+	assert.Equal(t, 3.0, testutil.ToFloat64(metricAttributesTruncated.WithLabelValues("attribute-limits", reasonAttributeBytes)))
+}
+
 func TestLogSpans(t *testing.T) {
 	for i, tc := range []struct {
 		LogReceivedSpansEnabled bool
@@ -1026,6 +1124,45 @@ func TestRateLimitRespected(t *testing.T) {
 	assert.True(t, status.Code() == codes.ResourceExhausted, "Wrong status code")
 }
 
+func TestSpanRateLimitRespected(t *testing.T) {
+	overridesConfig := overrides.Config{
+		Defaults: overrides.Overrides{
+			Ingestion: overrides.IngestionOverrides{
+				RateStrategy:   overrides.LocalIngestionRateStrategy,
+				RateLimitBytes: 15e6,
+				BurstSizeBytes: 20e6,
+				RateLimitSpans: 1,
+				BurstSizeSpans: 3,
+			},
+		},
+	}
+	d := prepare(t, overridesConfig, kitlog.NewNopLogger())
+
+	// 2 spans fit into the burst
+	_, err := d.PushTraces(ctx, batchesToTraces(t, []*v1.ResourceSpans{
+		makeResourceSpans("test-service", []*v1.ScopeSpans{
+			makeScope(
+				makeSpan("0a0102030405060708090a0b0c0d0e0f", "dad44adc9a83b370", "Test Span1", nil),
+				makeSpan("e3210a2b38097332d1fe43083ea93d29", "6c21c48da4dbd1a7", "Test Span2", nil)),
+		}),
+	}))
+	require.NoError(t, err)
+
+	// 2 more spans exceed it
+	_, err = d.PushTraces(ctx, batchesToTraces(t, []*v1.ResourceSpans{
+		makeResourceSpans("test-service", []*v1.ScopeSpans{
+			makeScope(
+				makeSpan("bb42ec04df789ff04b10ea5274491685", "1b3a296034f4031e", "Test Span3", nil),
+				makeSpan("b1c792dea27d511c145df8402bdd793a", "56afb9fe18b6c2d6", "Test Span4", nil)),
+		}),
+	}))
+	require.Error(t, err)
+	status, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.ResourceExhausted, status.Code())
+	require.Contains(t, status.Message(), "spans")
+}
+
 func TestDiscardCountReplicationFactor(t *testing.T) {
 	tt := []struct {
 		name                                string
@@ -0,0 +1,85 @@
+package enrichment
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/tempo/tempodb/backend/s3"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+
+	defaultRefreshInterval = 5 * time.Minute
+	defaultRequestTimeout  = 30 * time.Second
+)
+
+type Config struct {
+	// Tables are the lookup tables tenants can join their spans against.
+	Tables []TableConfig `yaml:"tables,omitempty"`
+}
+
+// TableConfig configures a lookup table and where it's loaded from. Exactly one of File, URL and S3 has to be set.
+type TableConfig struct {
+	Name string `yaml:"name"`
+
+	File string    `yaml:"file,omitempty"`
+	URL  string    `yaml:"url,omitempty"`
+	S3   *S3Source `yaml:"s3,omitempty"`
+
+	// Format of the table, csv or json. CSV tables have a header row naming the attributes, the first column is the
+	// key. JSON tables are an object of keys to objects of attribute names and values.
+	Format          string        `yaml:"format,omitempty"`
+	RefreshInterval time.Duration `yaml:"refresh_interval,omitempty"`
+	RequestTimeout  time.Duration `yaml:"request_timeout,omitempty"`
+}
+
+// S3Source reads the table from an object in an S3 bucket.
+type S3Source struct {
+	s3.Config `yaml:",inline"`
+
+	// Object is the name of the object in the bucket, relative to the prefix.
+	Object string `yaml:"object"`
+}
+
+func (cfg *TableConfig) applyDefaultsAndValidate() error {
+	if cfg.Name == "" {
+		return errors.New("lookup table name is required")
+	}
+
+	sources := 0
+	if cfg.File != "" {
+		sources++
+	}
+	if cfg.URL != "" {
+		sources++
+	}
+	if cfg.S3 != nil {
+		sources++
+		if cfg.S3.Object == "" {
+			return fmt.Errorf("lookup table %s: s3 object is required", cfg.Name)
+		}
+	}
+	if sources != 1 {
+		return fmt.Errorf("lookup table %s: exactly one of file, url and s3 must be set", cfg.Name)
+	}
+
+	switch cfg.Format {
+	case "":
+		cfg.Format = FormatCSV
+	case FormatCSV, FormatJSON:
+	default:
+		return fmt.Errorf("lookup table %s: unknown format %s, must be %s or %s", cfg.Name, cfg.Format, FormatCSV, FormatJSON)
+	}
+
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = defaultRefreshInterval
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = defaultRequestTimeout
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package enrichment
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/modules/overrides"
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+const (
+	ScopeResource = "resource"
+	ScopeSpan     = "span"
+)
+
+var (
+	metricTableRefreshes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_enrichment_table_refreshes_total",
+		Help:      "The total number of lookup table refreshes by result.",
+	}, []string{"table", "result"})
+	metricTableRows = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "distributor_enrichment_table_rows",
+		Help:      "The number of rows of the lookup table after the last successful refresh.",
+	}, []string{"table"})
+	metricEnrichedSpans = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_enriched_spans_total",
+		Help:      "The total number of spans attributes were injected into from a lookup table.",
+	}, []string{"tenant", "table"})
+)
+
+// LookupsFunc returns the lookups of a tenant
+type LookupsFunc func(tenant string) []overrides.EnrichmentLookup
+
+// Enricher injects attributes from lookup tables into spans. The tables are refreshed periodically and the lookups
+// are configured per tenant.
+type Enricher struct {
+	services.Service
+
+	tables  map[string]*table
+	lookups LookupsFunc
+	logger  log.Logger
+}
+
+func New(cfg Config, lookups LookupsFunc, logger log.Logger) (*Enricher, error) {
+	e := &Enricher{
+		tables:  make(map[string]*table, len(cfg.Tables)),
+		lookups: lookups,
+		logger:  logger,
+	}
+
+	for _, tableCfg := range cfg.Tables {
+		if err := tableCfg.applyDefaultsAndValidate(); err != nil {
+			return nil, err
+		}
+		if _, ok := e.tables[tableCfg.Name]; ok {
+			return nil, fmt.Errorf("duplicate lookup table %s", tableCfg.Name)
+		}
+
+		t, err := newTable(tableCfg)
+		if err != nil {
+			return nil, err
+		}
+		e.tables[tableCfg.Name] = t
+	}
+
+	e.Service = services.NewBasicService(e.starting, e.running, nil)
+	return e, nil
+}
+
+// starting loads all tables once. A table that fails to load is empty until a refresh succeeds, it doesn't
+// prevent the distributor from starting.
+func (e *Enricher) starting(ctx context.Context) error {
+	for _, t := range e.tables {
+		e.refresh(ctx, t)
+	}
+	return nil
+}
+
+func (e *Enricher) running(ctx context.Context) error {
+	wg := sync.WaitGroup{}
+	for _, t := range e.tables {
+		wg.Add(1)
+		go func(t *table) {
+			defer wg.Done()
+
+			ticker := time.NewTicker(t.cfg.RefreshInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					e.refresh(ctx, t)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(t)
+	}
+
+	wg.Wait()
+	return nil
+}
+
+func (e *Enricher) refresh(ctx context.Context, t *table) {
+	n, err := t.refresh(ctx)
+	if err != nil {
+		level.Error(e.logger).Log("msg", "failed to refresh lookup table", "table", t.cfg.Name, "err", err)
+		metricTableRefreshes.WithLabelValues(t.cfg.Name, "error").Inc()
+		return
+	}
+
+	metricTableRefreshes.WithLabelValues(t.cfg.Name, "success").Inc()
+	metricTableRows.WithLabelValues(t.cfg.Name).Set(float64(n))
+}
+
+// Enrich injects the attributes of the matching rows of the lookup tables of the tenant into the batches.
+func (e *Enricher) Enrich(tenant string, batches []*v1.ResourceSpans) {
+	for _, l := range e.lookups(tenant) {
+		t, ok := e.tables[l.Table]
+		if !ok {
+			continue
+		}
+
+		enriched := 0
+		for _, b := range batches {
+			if l.Scope == ScopeSpan {
+				for _, ss := range b.ScopeSpans {
+					for _, s := range ss.Spans {
+						if attrs := t.lookup(stringAttribute(s.Attributes, l.KeyAttribute)); len(attrs) > 0 {
+							s.Attributes = inject(s.Attributes, attrs, l.Overwrite)
+							enriched++
+						}
+					}
+				}
+				continue
+			}
+
+			if b.Resource == nil {
+				continue
+			}
+			if attrs := t.lookup(stringAttribute(b.Resource.Attributes, l.KeyAttribute)); len(attrs) > 0 {
+				b.Resource.Attributes = inject(b.Resource.Attributes, attrs, l.Overwrite)
+				for _, ss := range b.ScopeSpans {
+					enriched += len(ss.Spans)
+				}
+			}
+		}
+
+		if enriched > 0 {
+			metricEnrichedSpans.WithLabelValues(tenant, l.Table).Add(float64(enriched))
+		}
+	}
+}
+
+// stringAttribute returns the value of the string attribute or "" if it's not set
+func stringAttribute(kvs []*common_v1.KeyValue, name string) string {
+	for _, kv := range kvs {
+		if kv.Key == name {
+			return kv.GetValue().GetStringValue()
+		}
+	}
+	return ""
+}
+
+// inject adds the attributes to kvs. Attributes that are already set are only replaced if overwrite is set.
+func inject(kvs []*common_v1.KeyValue, attrs []attribute, overwrite bool) []*common_v1.KeyValue {
+outer:
+	for _, a := range attrs {
+		for _, kv := range kvs {
+			if kv.Key != a.name {
+				continue
+			}
+			if overwrite {
+				kv.Value = &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: a.value}}
+			}
+			continue outer
+		}
+
+		kvs = append(kvs, &common_v1.KeyValue{
+			Key:   a.name,
+			Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: a.value}},
+		})
+	}
+
+	return kvs
+}
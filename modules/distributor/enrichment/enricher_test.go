@@ -0,0 +1,128 @@
+package enrichment
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/services"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides"
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	resource_v1 "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestEnrich(t *testing.T) {
+	csvPath := filepath.Join(t.TempDir(), "services.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte("service,team,tier\nfrontend,web,1\nbackend,platform,\n"), 0o600))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"/checkout": {"owner": "payments"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	lookups := map[string][]overrides.EnrichmentLookup{
+		"test": {
+			{Table: "services", KeyAttribute: "service.name"},
+			{Table: "routes", KeyAttribute: "http.route", Scope: ScopeSpan, Overwrite: true},
+			{Table: "unknown", KeyAttribute: "service.name"},
+		},
+	}
+
+	e, err := New(Config{
+		Tables: []TableConfig{
+			{Name: "services", File: csvPath},
+			{Name: "routes", URL: srv.URL, Format: FormatJSON},
+		},
+	}, func(tenant string) []overrides.EnrichmentLookup { return lookups[tenant] }, log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), e))
+	t.Cleanup(func() { require.NoError(t, services.StopAndAwaitTerminated(context.Background(), e)) })
+
+	batches := []*v1.ResourceSpans{
+		{
+			Resource: &resource_v1.Resource{Attributes: []*common_v1.KeyValue{stringKV("service.name", "frontend"), stringKV("team", "keep")}},
+			ScopeSpans: []*v1.ScopeSpans{{Spans: []*v1.Span{
+				{Attributes: []*common_v1.KeyValue{stringKV("http.route", "/checkout"), stringKV("owner", "replace")}},
+				{Attributes: []*common_v1.KeyValue{stringKV("http.route", "/other")}},
+			}}},
+		},
+		{
+			Resource:   &resource_v1.Resource{Attributes: []*common_v1.KeyValue{stringKV("service.name", "backend")}},
+			ScopeSpans: []*v1.ScopeSpans{{Spans: []*v1.Span{{}}}},
+		},
+		{
+			Resource:   &resource_v1.Resource{Attributes: []*common_v1.KeyValue{stringKV("service.name", "unknown")}},
+			ScopeSpans: []*v1.ScopeSpans{{Spans: []*v1.Span{{}}}},
+		},
+	}
+
+	e.Enrich("test", batches)
+
+	// existing resource attributes are kept unless overwrite is set
+	require.Equal(t, []*common_v1.KeyValue{stringKV("service.name", "frontend"), stringKV("team", "keep"), stringKV("tier", "1")}, batches[0].Resource.Attributes)
+	require.Equal(t, []*common_v1.KeyValue{stringKV("http.route", "/checkout"), stringKV("owner", "payments")}, batches[0].ScopeSpans[0].Spans[0].Attributes)
+	require.Equal(t, []*common_v1.KeyValue{stringKV("http.route", "/other")}, batches[0].ScopeSpans[0].Spans[1].Attributes)
+	// empty values are skipped
+	require.Equal(t, []*common_v1.KeyValue{stringKV("service.name", "backend"), stringKV("team", "platform")}, batches[1].Resource.Attributes)
+	require.Equal(t, []*common_v1.KeyValue{stringKV("service.name", "unknown")}, batches[2].Resource.Attributes)
+
+	// tenants without lookups are untouched
+	other := []*v1.ResourceSpans{{Resource: &resource_v1.Resource{Attributes: []*common_v1.KeyValue{stringKV("service.name", "frontend")}}}}
+	e.Enrich("other", other)
+	require.Len(t, other[0].Resource.Attributes, 1)
+}
+
+func TestTableRefreshKeepsRowsOnError(t *testing.T) {
+	csvPath := filepath.Join(t.TempDir(), "services.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte("service,team\nfrontend,web\n"), 0o600))
+
+	cfg := TableConfig{Name: "services", File: csvPath}
+	require.NoError(t, cfg.applyDefaultsAndValidate())
+	tbl, err := newTable(cfg)
+	require.NoError(t, err)
+
+	n, err := tbl.refresh(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	require.NoError(t, os.WriteFile(csvPath, []byte("service,team\nfrontend\n"), 0o600))
+	_, err = tbl.refresh(context.Background())
+	require.Error(t, err)
+	require.Equal(t, []attribute{{name: "team", value: "web"}}, tbl.lookup("frontend"))
+}
+
+func TestTableConfigValidate(t *testing.T) {
+	tcs := []struct {
+		cfg TableConfig
+		err string
+	}{
+		{cfg: TableConfig{File: "f"}, err: "lookup table name is required"},
+		{cfg: TableConfig{Name: "t"}, err: "lookup table t: exactly one of file, url and s3 must be set"},
+		{cfg: TableConfig{Name: "t", File: "f", URL: "u"}, err: "lookup table t: exactly one of file, url and s3 must be set"},
+		{cfg: TableConfig{Name: "t", S3: &S3Source{}}, err: "lookup table t: s3 object is required"},
+		{cfg: TableConfig{Name: "t", File: "f", Format: "xml"}, err: "lookup table t: unknown format xml, must be csv or json"},
+		{cfg: TableConfig{Name: "t", File: "f"}},
+	}
+
+	for _, tc := range tcs {
+		err := tc.cfg.applyDefaultsAndValidate()
+		if tc.err == "" {
+			require.NoError(t, err)
+			require.Equal(t, FormatCSV, tc.cfg.Format)
+			require.Equal(t, defaultRefreshInterval, tc.cfg.RefreshInterval)
+			continue
+		}
+		require.EqualError(t, err, tc.err)
+	}
+}
+
+func stringKV(k, v string) *common_v1.KeyValue {
+	return &common_v1.KeyValue{Key: k, Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: v}}}
+}
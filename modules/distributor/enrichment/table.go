@@ -0,0 +1,181 @@
+package enrichment
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/s3"
+)
+
+// attribute is injected into the spans matching a row of the table
+type attribute struct {
+	name  string
+	value string
+}
+
+// rows maps the keys of a table to the attributes of the row
+type rows map[string][]attribute
+
+type loadFunc func(ctx context.Context) ([]byte, error)
+
+// table is a lookup table that is periodically reloaded from its source
+type table struct {
+	cfg  TableConfig
+	load loadFunc
+
+	rows atomic.Pointer[rows]
+}
+
+func newTable(cfg TableConfig) (*table, error) {
+	t := &table{cfg: cfg}
+
+	switch {
+	case cfg.File != "":
+		t.load = func(context.Context) ([]byte, error) {
+			return os.ReadFile(cfg.File)
+		}
+	case cfg.URL != "":
+		client := &http.Client{Timeout: cfg.RequestTimeout}
+		t.load = func(ctx context.Context) ([]byte, error) {
+			return httpGet(ctx, client, cfg.URL)
+		}
+	case cfg.S3 != nil:
+		r, _, _, err := s3.NewNoConfirm(&cfg.S3.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create s3 client for lookup table %s: %w", cfg.Name, err)
+		}
+		dir, name := path.Split(cfg.S3.Object)
+		keypath := backend.KeyPath(strings.Split(strings.Trim(dir, "/"), "/"))
+		if dir == "" {
+			keypath = nil
+		}
+		t.load = func(ctx context.Context) ([]byte, error) {
+			rc, _, err := r.Read(ctx, name, keypath, nil)
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		}
+	}
+
+	empty := rows{}
+	t.rows.Store(&empty)
+
+	return t, nil
+}
+
+// refresh loads and parses the table. The previous rows are kept if this fails.
+func (t *table) refresh(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.cfg.RequestTimeout)
+	defer cancel()
+
+	b, err := t.load(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var r rows
+	switch t.cfg.Format {
+	case FormatJSON:
+		r, err = parseJSON(b)
+	default:
+		r, err = parseCSV(b)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	t.rows.Store(&r)
+	return len(r), nil
+}
+
+func (t *table) lookup(key string) []attribute {
+	if key == "" {
+		return nil
+	}
+	return (*t.rows.Load())[key]
+}
+
+func httpGet(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// parseCSV parses a table with a header row. The first column is the key and the other columns are the attributes.
+// Empty values are skipped.
+func parseCSV(b []byte) (rows, error) {
+	records, err := csv.NewReader(bytes.NewReader(b)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("csv table has no header row")
+	}
+
+	header := records[0]
+	if len(header) < 2 {
+		return nil, errors.New("csv table needs a key column and at least one attribute column")
+	}
+
+	r := make(rows, len(records)-1)
+	for _, record := range records[1:] {
+		attrs := make([]attribute, 0, len(header)-1)
+		for i := 1; i < len(header); i++ {
+			if record[i] == "" {
+				continue
+			}
+			attrs = append(attrs, attribute{name: header[i], value: record[i]})
+		}
+		r[record[0]] = attrs
+	}
+
+	return r, nil
+}
+
+// parseJSON parses a table that is an object of keys to objects of attribute names and values
+func parseJSON(b []byte) (rows, error) {
+	var raw map[string]map[string]string
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	r := make(rows, len(raw))
+	for key, values := range raw {
+		attrs := make([]attribute, 0, len(values))
+		for name, value := range values {
+			attrs = append(attrs, attribute{name: name, value: value})
+		}
+		// map order is random, keep the injected attributes stable
+		sort.Slice(attrs, func(i, j int) bool { return attrs[i].name < attrs[j].name })
+		r[key] = attrs
+	}
+
+	return r, nil
+}
@@ -16,6 +16,43 @@ type Config struct {
 	Backend  string          `yaml:"backend"`
 	OTLPGRPC otlpgrpc.Config `yaml:"otlpgrpc"`
 	Filter   FilterConfig    `yaml:"filter"`
+	Queue    QueueConfig     `yaml:"queue"`
+}
+
+// QueueConfig configures the per-tenant queues in front of the forwarder.
+type QueueConfig struct {
+	// Size is the number of batches queued per tenant. Batches pushed to a full queue are dropped.
+	Size int `yaml:"size"`
+	// Workers is the number of workers forwarding the batches of a tenant.
+	Workers int `yaml:"workers"`
+}
+
+func (cfg *QueueConfig) Validate() error {
+	if cfg.Size < 0 {
+		return errors.New("queue size must not be negative")
+	}
+
+	if cfg.Workers < 0 {
+		return errors.New("queue workers must not be negative")
+	}
+
+	return nil
+}
+
+// sizeOrDefault returns the queue size, or the default if it isn't set.
+func (cfg *QueueConfig) sizeOrDefault() int {
+	if cfg.Size == 0 {
+		return defaultQueueSize
+	}
+	return cfg.Size
+}
+
+// workersOrDefault returns the worker count, or the default if it isn't set.
+func (cfg *QueueConfig) workersOrDefault() int {
+	if cfg.Workers == 0 {
+		return defaultWorkerCount
+	}
+	return cfg.Workers
 }
 
 type FilterConfig struct {
@@ -32,6 +69,10 @@ func (cfg *Config) Validate() error {
 		return errors.New("name is empty")
 	}
 
+	if err := cfg.Queue.Validate(); err != nil {
+		return err
+	}
+
 	switch cfg.Backend {
 	case OTLPGRPCBackend:
 		return cfg.OTLPGRPC.Validate()
@@ -13,6 +13,7 @@ func TestConfig_Validate(t *testing.T) {
 		Name     string
 		Backend  string
 		OTLPGRPC otlpgrpc.Config
+		Queue    QueueConfig
 	}
 	tests := []struct {
 		name    string
@@ -62,6 +63,22 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "ReturnsErrorWithNegativeQueueSize",
+			fields: fields{
+				Name:    "test",
+				Backend: OTLPGRPCBackend,
+				OTLPGRPC: otlpgrpc.Config{
+					TLS: otlpgrpc.TLSConfig{
+						Insecure: true,
+					},
+				},
+				Queue: QueueConfig{
+					Size: -1,
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -69,6 +86,7 @@ func TestConfig_Validate(t *testing.T) {
 				Name:     tt.fields.Name,
 				Backend:  tt.fields.Backend,
 				OTLPGRPC: tt.fields.OTLPGRPC,
+				Queue:    tt.fields.Queue,
 			}
 
 			err := cfg.Validate()
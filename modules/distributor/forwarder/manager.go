@@ -35,6 +35,8 @@ type Manager struct {
 
 	// forwarderNameToForwarder is static throughout lifecycle of the manager and read-only
 	forwarderNameToForwarder map[string]Forwarder
+	// forwarderNameToQueueConfig is static throughout lifecycle of the manager and read-only
+	forwarderNameToQueueConfig map[string]QueueConfig
 
 	tenantToQueueList   map[string]*queueList
 	tenantToQueueListMu *sync.RWMutex
@@ -46,6 +48,7 @@ func NewManager(cfgs ConfigList, logger log.Logger, overrides Overrides, logLeve
 	}
 
 	forwarderNameToForwarder := make(map[string]Forwarder, len(cfgs))
+	forwarderNameToQueueConfig := make(map[string]QueueConfig, len(cfgs))
 	for i, cfg := range cfgs {
 		forwarder, err := New(cfg, logger, logLevel)
 		if err != nil {
@@ -53,14 +56,16 @@ func NewManager(cfgs ConfigList, logger log.Logger, overrides Overrides, logLeve
 		}
 
 		forwarderNameToForwarder[cfg.Name] = forwarder
+		forwarderNameToQueueConfig[cfg.Name] = cfg.Queue
 	}
 
 	m := &Manager{
-		logger:                   logger,
-		overrides:                overrides,
-		forwarderNameToForwarder: forwarderNameToForwarder,
-		tenantToQueueList:        make(map[string]*queueList),
-		tenantToQueueListMu:      &sync.RWMutex{},
+		logger:                     logger,
+		overrides:                  overrides,
+		forwarderNameToForwarder:   forwarderNameToForwarder,
+		forwarderNameToQueueConfig: forwarderNameToQueueConfig,
+		tenantToQueueList:          make(map[string]*queueList),
+		tenantToQueueListMu:        &sync.RWMutex{},
 	}
 
 	m.Service = services.NewBasicService(m.start, m.run, m.stop)
@@ -91,7 +96,7 @@ func (m *Manager) getOrCreateQueueList(tenantID string) (*queueList, bool) {
 	defer m.tenantToQueueListMu.Unlock()
 
 	forwarderNames := m.overrides.Forwarders(tenantID)
-	ql, err := newQueueList(m.logger, tenantID, forwarderNames, m.forwarderNameToForwarder)
+	ql, err := newQueueList(m.logger, tenantID, forwarderNames, m.forwarderNameToForwarder, m.forwarderNameToQueueConfig)
 	if err != nil {
 		_ = level.Warn(m.logger).Log("msg", "failed to create queue list", "err", err)
 
@@ -159,7 +164,7 @@ func (m *Manager) updateQueueLists() {
 			go m.shutdownQueueList(tenantID, ql)
 			delete(m.tenantToQueueList, tenantID)
 
-			newQl, err := newQueueList(m.logger, tenantID, forwarderNames, m.forwarderNameToForwarder)
+			newQl, err := newQueueList(m.logger, tenantID, forwarderNames, m.forwarderNameToForwarder, m.forwarderNameToQueueConfig)
 			if err != nil {
 				_ = level.Warn(m.logger).Log("msg", "failed to create queue list", "err", err)
 
@@ -215,7 +220,7 @@ type queueList struct {
 	list                 List
 }
 
-func newQueueList(logger log.Logger, tenantID string, forwarderNames []string, forwarderNameToForwarder map[string]Forwarder) (*queueList, error) {
+func newQueueList(logger log.Logger, tenantID string, forwarderNames []string, forwarderNameToForwarder map[string]Forwarder, forwarderNameToQueueConfig map[string]QueueConfig) (*queueList, error) {
 	forwarderNameToQueue := make(map[string]*queue.Queue[ptrace.Traces], len(forwarderNames))
 	list := make(List, 0, len(forwarderNames))
 	for _, forwarderName := range forwarderNames {
@@ -224,11 +229,12 @@ func newQueueList(logger log.Logger, tenantID string, forwarderNames []string, f
 			return nil, fmt.Errorf("failed to find forwarder by name: forwarderName=%s, tenantID=%s", forwarderName, tenantID)
 		}
 
+		forwarderQueueCfg := forwarderNameToQueueConfig[forwarderName]
 		queueCfg := queue.Config{
 			Name:        forwarderName,
 			TenantID:    tenantID,
-			Size:        defaultQueueSize,
-			WorkerCount: defaultWorkerCount,
+			Size:        forwarderQueueCfg.sizeOrDefault(),
+			WorkerCount: forwarderQueueCfg.workersOrDefault(),
 		}
 
 		processFunc := func(ctx context.Context, traces ptrace.Traces) {
@@ -2,16 +2,36 @@ package otlpgrpc
 
 import (
 	"errors"
+	"time"
 
+	"github.com/grafana/dskit/backoff"
 	"github.com/grafana/dskit/flagext"
 )
 
+const (
+	defaultMinBackoff = 100 * time.Millisecond
+	defaultMaxBackoff = 5 * time.Second
+)
+
 type Config struct {
 	Endpoints flagext.StringSlice `yaml:"endpoints"`
 	TLS       TLSConfig           `yaml:"tls"`
+	// Headers are sent with every export request, e.g. to authenticate with the endpoints.
+	Headers map[string]string `yaml:"headers"`
+	// Timeout of a single export request to an endpoint. 0 means no timeout.
+	Timeout time.Duration `yaml:"timeout"`
+	Retry   RetryConfig   `yaml:"retry"`
 }
 
 func (cfg *Config) Validate() error {
+	if cfg.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+
+	if err := cfg.Retry.Validate(); err != nil {
+		return err
+	}
+
 	// TODO: Validate if endpoints are in form host:port?
 	return cfg.TLS.Validate()
 }
@@ -32,3 +52,47 @@ func (cfg *TLSConfig) Validate() error {
 
 	return nil
 }
+
+// RetryConfig configures the retries of exports that failed with a retryable error.
+type RetryConfig struct {
+	// MaxRetries is the number of times a failed export is retried. 0 disables retries.
+	MaxRetries int           `yaml:"max_retries"`
+	MinBackoff time.Duration `yaml:"min_backoff"`
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+}
+
+func (cfg *RetryConfig) Validate() error {
+	if cfg.MaxRetries < 0 {
+		return errors.New("retry max_retries must not be negative")
+	}
+
+	if cfg.MinBackoff < 0 || cfg.MaxBackoff < 0 {
+		return errors.New("retry backoff must not be negative")
+	}
+
+	if cfg.MinBackoff > 0 && cfg.MaxBackoff > 0 && cfg.MinBackoff > cfg.MaxBackoff {
+		return errors.New("retry min_backoff must not be greater than max_backoff")
+	}
+
+	return nil
+}
+
+// backoffConfig returns the backoff between retries. The number of retries is bounded by the forwarder.
+func (cfg *RetryConfig) backoffConfig() backoff.Config {
+	b := backoff.Config{
+		MinBackoff: cfg.MinBackoff,
+		MaxBackoff: cfg.MaxBackoff,
+	}
+
+	if b.MinBackoff == 0 {
+		b.MinBackoff = defaultMinBackoff
+	}
+	if b.MaxBackoff == 0 {
+		b.MaxBackoff = defaultMaxBackoff
+	}
+	if b.MinBackoff > b.MaxBackoff {
+		b.MaxBackoff = b.MinBackoff
+	}
+
+	return b
+}
@@ -2,6 +2,7 @@ package otlpgrpc
 
 import (
 	"testing"
+	"time"
 
 	"github.com/grafana/dskit/flagext"
 	"github.com/stretchr/testify/require"
@@ -11,6 +12,7 @@ func TestConfig_Validate(t *testing.T) {
 	type fields struct {
 		Endpoints flagext.StringSlice
 		TLS       TLSConfig
+		Retry     RetryConfig
 	}
 	tests := []struct {
 		name    string
@@ -49,12 +51,53 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "ReturnsNoErrorWithValidRetryConfig",
+			fields: fields{
+				TLS: TLSConfig{
+					Insecure: true,
+				},
+				Retry: RetryConfig{
+					MaxRetries: 3,
+					MinBackoff: time.Second,
+					MaxBackoff: time.Minute,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ReturnsErrorWithNegativeMaxRetries",
+			fields: fields{
+				TLS: TLSConfig{
+					Insecure: true,
+				},
+				Retry: RetryConfig{
+					MaxRetries: -1,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ReturnsErrorWithMinBackoffGreaterThanMaxBackoff",
+			fields: fields{
+				TLS: TLSConfig{
+					Insecure: true,
+				},
+				Retry: RetryConfig{
+					MaxRetries: 3,
+					MinBackoff: time.Minute,
+					MaxBackoff: time.Second,
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := &Config{
 				Endpoints: tt.fields.Endpoints,
 				TLS:       tt.fields.TLS,
+				Retry:     tt.fields.Retry,
 			}
 
 			err := cfg.Validate()
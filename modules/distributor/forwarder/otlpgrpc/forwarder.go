@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/go-kit/log"
+	"github.com/grafana/dskit/backoff"
 	"github.com/grafana/dskit/middleware"
 	grpcmw "github.com/grpc-ecosystem/go-grpc-middleware"
 	"github.com/grpc-ecosystem/grpc-opentracing/go/otgrpc"
@@ -15,8 +16,11 @@ import (
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 	"go.uber.org/multierr"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type Forwarder struct {
@@ -75,10 +79,14 @@ func (f *Forwarder) Dial(ctx context.Context, opts ...grpc.DialOption) error {
 func (f *Forwarder) ForwardTraces(ctx context.Context, traces ptrace.Traces) error {
 	req := ptraceotlp.NewExportRequestFromTraces(traces)
 
+	for k, v := range f.cfg.Headers {
+		ctx = metadata.AppendToOutgoingContext(ctx, k, v)
+	}
+
 	var errs []error
 	f.mu.RLock()
 	for endpoint, client := range f.clients {
-		if _, err := client.Export(ctx, req); err != nil {
+		if err := f.export(ctx, client, req); err != nil {
 			errs = append(errs, fmt.Errorf("failed to export trace to endpoint=%s: %w", endpoint, err))
 		}
 	}
@@ -87,6 +95,45 @@ func (f *Forwarder) ForwardTraces(ctx context.Context, traces ptrace.Traces) err
 	return multierr.Combine(errs...)
 }
 
+// export exports the request to a single endpoint. Exports that fail with a retryable error are retried with
+// backoff up to the configured number of retries.
+func (f *Forwarder) export(ctx context.Context, client ptraceotlp.GRPCClient, req ptraceotlp.ExportRequest) error {
+	b := backoff.New(ctx, f.cfg.Retry.backoffConfig())
+
+	for retries := 0; ; retries++ {
+		err := f.exportOnce(ctx, client, req)
+		if err == nil || retries >= f.cfg.Retry.MaxRetries || !isRetryable(err) {
+			return err
+		}
+
+		b.Wait()
+		if b.Err() != nil {
+			return err
+		}
+	}
+}
+
+func (f *Forwarder) exportOnce(ctx context.Context, client ptraceotlp.GRPCClient, req ptraceotlp.ExportRequest) error {
+	if f.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.cfg.Timeout)
+		defer cancel()
+	}
+
+	_, err := client.Export(ctx, req)
+	return err
+}
+
+// isRetryable returns true if the export failed with one of the codes the OTLP specification lists as retryable.
+func isRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Canceled, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.OutOfRange, codes.Unavailable, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
+
 func (f *Forwarder) Shutdown(_ context.Context) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/grafana/dskit/user"
@@ -12,6 +13,9 @@ import (
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 	"go.uber.org/atomic"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 )
 
@@ -25,6 +29,23 @@ func (m *mockGRPCServer) Export(_ context.Context, req ptraceotlp.ExportRequest)
 	return ptraceotlp.NewExportResponse(), nil
 }
 
+// flakyGRPCServer fails the first exports with err and records the metadata of the requests.
+type flakyGRPCServer struct {
+	ptraceotlp.UnimplementedGRPCServer
+	failures int
+	err      error
+	calls    atomic.Int32
+	md       metadata.MD
+}
+
+func (m *flakyGRPCServer) Export(ctx context.Context, _ ptraceotlp.ExportRequest) (ptraceotlp.ExportResponse, error) {
+	m.md, _ = metadata.FromIncomingContext(ctx)
+	if int(m.calls.Inc()) <= m.failures {
+		return ptraceotlp.NewExportResponse(), m.err
+	}
+	return ptraceotlp.NewExportResponse(), nil
+}
+
 func newForwarder(t *testing.T, cfg Config, logger log.Logger) *Forwarder {
 	t.Helper()
 
@@ -310,3 +331,87 @@ func Test_Forwarder_Shutdown_CallsCloseOnConnection(t *testing.T) {
 	require.NoError(t, err)
 	require.GreaterOrEqual(t, conn.closeCount.Load(), int32(1))
 }
+
+func Test_Forwarder_ForwardTraces_RetriesRetryableErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxRetries    int
+		failures      int
+		err           error
+		expectedCalls int32
+		expectErr     bool
+	}{
+		{
+			name:          "SucceedsAfterRetries",
+			maxRetries:    3,
+			failures:      2,
+			err:           status.Error(codes.Unavailable, "unavailable"),
+			expectedCalls: 3,
+		},
+		{
+			name:          "FailsWhenRetriesExhausted",
+			maxRetries:    2,
+			failures:      5,
+			err:           status.Error(codes.Unavailable, "unavailable"),
+			expectedCalls: 3,
+			expectErr:     true,
+		},
+		{
+			name:          "DoesNotRetryNonRetryableErrors",
+			maxRetries:    3,
+			failures:      1,
+			err:           status.Error(codes.InvalidArgument, "invalid"),
+			expectedCalls: 1,
+			expectErr:     true,
+		},
+		{
+			name:          "DoesNotRetryWhenDisabled",
+			failures:      1,
+			err:           status.Error(codes.Unavailable, "unavailable"),
+			expectedCalls: 1,
+			expectErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{
+				Endpoints: []string{"test:1234"},
+				TLS:       TLSConfig{Insecure: true},
+				Retry: RetryConfig{
+					MaxRetries: tt.maxRetries,
+					MinBackoff: time.Millisecond,
+					MaxBackoff: time.Millisecond,
+				},
+			}
+			f := newForwarder(t, cfg, log.NewNopLogger())
+			srv := &flakyGRPCServer{failures: tt.failures, err: tt.err}
+			d := newContextDialer(newListener(t, srv))
+			require.NoError(t, f.Dial(context.Background(), grpc.WithContextDialer(d), grpc.WithBlock()))
+
+			err := f.ForwardTraces(user.InjectOrgID(context.Background(), "123"), ptrace.NewTraces())
+			if tt.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.expectedCalls, srv.calls.Load())
+		})
+	}
+}
+
+func Test_Forwarder_ForwardTraces_SendsHeaders(t *testing.T) {
+	cfg := Config{
+		Endpoints: []string{"test:1234"},
+		TLS:       TLSConfig{Insecure: true},
+		Headers:   map[string]string{"Authorization": "Bearer token"},
+	}
+	f := newForwarder(t, cfg, log.NewNopLogger())
+	srv := &flakyGRPCServer{}
+	d := newContextDialer(newListener(t, srv))
+	require.NoError(t, f.Dial(context.Background(), grpc.WithContextDialer(d), grpc.WithBlock()))
+
+	require.NoError(t, f.ForwardTraces(user.InjectOrgID(context.Background(), "123"), ptrace.NewTraces()))
+	require.Equal(t, []string{"Bearer token"}, srv.md.Get("authorization"))
+	require.Equal(t, []string{"123"}, srv.md.Get(user.OrgIDHeaderName))
+}
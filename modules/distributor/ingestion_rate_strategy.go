@@ -12,32 +12,51 @@ type ReadLifecycler interface {
 }
 
 type localStrategy struct {
-	limits overrides.Interface
+	limit func(userID string) float64
+	burst func(userID string) int
 }
 
 func newLocalIngestionRateStrategy(limits overrides.Interface) limiter.RateLimiterStrategy {
 	return &localStrategy{
-		limits: limits,
+		limit: limits.IngestionRateLimitBytes,
+		burst: limits.IngestionBurstSizeBytes,
+	}
+}
+
+func newLocalSpanRateStrategy(limits overrides.Interface) limiter.RateLimiterStrategy {
+	return &localStrategy{
+		limit: limits.IngestionRateLimitSpans,
+		burst: limits.IngestionBurstSizeSpans,
 	}
 }
 
 func (s *localStrategy) Limit(userID string) float64 {
-	return s.limits.IngestionRateLimitBytes(userID)
+	return s.limit(userID)
 }
 
 func (s *localStrategy) Burst(userID string) int {
-	return s.limits.IngestionBurstSizeBytes(userID)
+	return s.burst(userID)
 }
 
 type globalStrategy struct {
-	limits overrides.Interface
-	ring   ReadLifecycler
+	limit func(userID string) float64
+	burst func(userID string) int
+	ring  ReadLifecycler
 }
 
 func newGlobalIngestionRateStrategy(limits overrides.Interface, ring ReadLifecycler) limiter.RateLimiterStrategy {
 	return &globalStrategy{
-		limits: limits,
-		ring:   ring,
+		limit: limits.IngestionRateLimitBytes,
+		burst: limits.IngestionBurstSizeBytes,
+		ring:  ring,
+	}
+}
+
+func newGlobalSpanRateStrategy(limits overrides.Interface, ring ReadLifecycler) limiter.RateLimiterStrategy {
+	return &globalStrategy{
+		limit: limits.IngestionRateLimitSpans,
+		burst: limits.IngestionBurstSizeSpans,
+		ring:  ring,
 	}
 }
 
@@ -45,14 +64,14 @@ func (s *globalStrategy) Limit(userID string) float64 {
 	numDistributors := s.ring.HealthyInstancesCount()
 
 	if numDistributors == 0 {
-		return s.limits.IngestionRateLimitBytes(userID)
+		return s.limit(userID)
 	}
 
-	return s.limits.IngestionRateLimitBytes(userID) / float64(numDistributors)
+	return s.limit(userID) / float64(numDistributors)
 }
 
 func (s *globalStrategy) Burst(userID string) int {
 	// The meaning of burst doesn't change for the global strategy, in order
 	// to keep it easier to understand for users / operators.
-	return s.limits.IngestionBurstSizeBytes(userID)
+	return s.burst(userID)
 }
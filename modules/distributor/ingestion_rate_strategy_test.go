@@ -18,6 +18,9 @@ func TestIngestionRateStrategy(t *testing.T) {
 		ring          ReadLifecycler
 		expectedLimit float64
 		expectedBurst int
+
+		expectedSpanLimit float64
+		expectedSpanBurst int
 	}{
 		"local rate limiter should just return configured limits": {
 			limits: overrides.Overrides{
@@ -25,11 +28,15 @@ func TestIngestionRateStrategy(t *testing.T) {
 					RateStrategy:   overrides.LocalIngestionRateStrategy,
 					RateLimitBytes: 5,
 					BurstSizeBytes: 2,
+					RateLimitSpans: 10,
+					BurstSizeSpans: 4,
 				},
 			},
-			ring:          nil,
-			expectedLimit: 5,
-			expectedBurst: 2,
+			ring:              nil,
+			expectedLimit:     5,
+			expectedBurst:     2,
+			expectedSpanLimit: 10,
+			expectedSpanBurst: 4,
 		},
 		"global rate limiter should share the limit across the number of distributors": {
 			limits: overrides.Overrides{
@@ -37,6 +44,8 @@ func TestIngestionRateStrategy(t *testing.T) {
 					RateStrategy:   overrides.GlobalIngestionRateStrategy,
 					RateLimitBytes: 5,
 					BurstSizeBytes: 2,
+					RateLimitSpans: 10,
+					BurstSizeSpans: 4,
 				},
 			},
 			ring: func() ReadLifecycler {
@@ -44,8 +53,10 @@ func TestIngestionRateStrategy(t *testing.T) {
 				ring.On("HealthyInstancesCount").Return(2)
 				return ring
 			}(),
-			expectedLimit: 2.5,
-			expectedBurst: 2,
+			expectedLimit:     2.5,
+			expectedBurst:     2,
+			expectedSpanLimit: 5,
+			expectedSpanBurst: 4,
 		},
 	}
 
@@ -53,7 +64,7 @@ func TestIngestionRateStrategy(t *testing.T) {
 		testData := testData
 
 		t.Run(testName, func(t *testing.T) {
-			var strategy limiter.RateLimiterStrategy
+			var strategy, spanStrategy limiter.RateLimiterStrategy
 
 			// Init limits overrides
 			o, err := overrides.NewOverrides(overrides.Config{
@@ -65,14 +76,18 @@ func TestIngestionRateStrategy(t *testing.T) {
 			switch testData.limits.Ingestion.RateStrategy {
 			case overrides.LocalIngestionRateStrategy:
 				strategy = newLocalIngestionRateStrategy(o)
+				spanStrategy = newLocalSpanRateStrategy(o)
 			case overrides.GlobalIngestionRateStrategy:
 				strategy = newGlobalIngestionRateStrategy(o, testData.ring)
+				spanStrategy = newGlobalSpanRateStrategy(o, testData.ring)
 			default:
 				require.Fail(t, "Unknown strategy")
 			}
 
 			assert.Equal(t, testData.expectedLimit, strategy.Limit("test"))
 			assert.Equal(t, testData.expectedBurst, strategy.Burst("test"))
+			assert.Equal(t, testData.expectedSpanLimit, spanStrategy.Limit("test"))
+			assert.Equal(t, testData.expectedSpanBurst, spanStrategy.Burst("test"))
 		})
 	}
 }
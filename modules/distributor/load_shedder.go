@@ -0,0 +1,183 @@
+package distributor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/grafana/tempo/modules/overrides"
+)
+
+const (
+	// the limit is adjusted at most once per interval
+	shedderAdjustInterval = time.Second
+	// the limit is decreased by this factor while the latency is above the target
+	shedderDecreaseFactor = 0.8
+	// the limit is increased by max/shedderIncreaseSteps once the latency is below the target
+	shedderIncreaseSteps = 20
+	// the limit is never decreased below max/shedderMinLimitDivisor
+	shedderMinLimitDivisor = 10
+	// weight of the latest observation in the moving average of the latency
+	shedderLatencyAlpha = 0.2
+)
+
+var (
+	metricInflightPushRequests = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "distributor_inflight_push_requests",
+		Help:      "The current number of pushes being processed.",
+	})
+	metricInflightPushBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "distributor_inflight_push_bytes",
+		Help:      "The current number of proto bytes of the pushes being processed.",
+	})
+	metricInflightPushBytesLimit = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "distributor_inflight_push_bytes_limit",
+		Help:      "The current limit of inflight push bytes before pushes are shed.",
+	})
+	metricIngesterPushDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "tempo",
+		Name:      "distributor_ingester_push_duration_seconds",
+		Help:      "The duration of sending a push to the ingesters.",
+		Buckets:   prometheus.DefBuckets,
+	})
+)
+
+// loadShedder tracks the pushes in flight and rejects new pushes before the distributor runs out of memory.
+// The limit of inflight bytes adapts to the latency of the pushes to the ingesters: it is decreased while
+// the latency is above the target and increased again once it recovers. Under pressure, i.e. once half
+// of the limit is used, each tenant is limited to a fair share of the limit so that a single tenant can't
+// cause the pushes of all tenants to be shed.
+type loadShedder struct {
+	cfg LoadSheddingConfig
+	now func() time.Time
+
+	mtx        sync.Mutex
+	inflight   uint64
+	tenants    map[string]uint64 // inflight bytes by tenant
+	limit      uint64
+	latency    time.Duration // moving average of the push latency to the ingesters
+	lastAdjust time.Time
+}
+
+func newLoadShedder(cfg LoadSheddingConfig) *loadShedder {
+	if cfg.MaxInflightBytes == 0 {
+		cfg.Enabled = false
+	}
+
+	s := &loadShedder{
+		cfg:     cfg,
+		now:     time.Now,
+		tenants: map[string]uint64{},
+		limit:   cfg.MaxInflightBytes,
+	}
+	if cfg.Enabled {
+		metricInflightPushBytesLimit.Set(float64(s.limit))
+	}
+	return s
+}
+
+// admit reserves size bytes for a push of the tenant. It returns false if the push must be shed. Pushes
+// that are admitted must be released.
+func (s *loadShedder) admit(tenant string, size uint64) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.cfg.Enabled && s.inflight > 0 {
+		total := s.inflight + size
+		if total > s.limit {
+			return false
+		}
+
+		if total > s.limit/2 {
+			tenants := uint64(len(s.tenants))
+			if _, ok := s.tenants[tenant]; !ok {
+				tenants++
+			}
+			if s.tenants[tenant]+size > s.limit/tenants {
+				return false
+			}
+		}
+	}
+
+	s.inflight += size
+	s.tenants[tenant] += size
+
+	metricInflightPushRequests.Inc()
+	metricInflightPushBytes.Set(float64(s.inflight))
+	return true
+}
+
+// release frees the bytes reserved by admit.
+func (s *loadShedder) release(tenant string, size uint64) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.inflight -= size
+	s.tenants[tenant] -= size
+	if s.tenants[tenant] == 0 {
+		delete(s.tenants, tenant)
+	}
+
+	metricInflightPushRequests.Dec()
+	metricInflightPushBytes.Set(float64(s.inflight))
+}
+
+// observeLatency records the latency of a push to the ingesters and adjusts the limit.
+func (s *loadShedder) observeLatency(d time.Duration) {
+	metricIngesterPushDuration.Observe(d.Seconds())
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.latency == 0 {
+		s.latency = d
+	} else {
+		s.latency = time.Duration(shedderLatencyAlpha*float64(d) + (1-shedderLatencyAlpha)*float64(s.latency))
+	}
+
+	if !s.cfg.Enabled || s.cfg.TargetLatency <= 0 {
+		return
+	}
+
+	now := s.now()
+	if now.Sub(s.lastAdjust) < shedderAdjustInterval {
+		return
+	}
+	s.lastAdjust = now
+
+	if s.latency > s.cfg.TargetLatency {
+		s.limit = max(uint64(float64(s.limit)*shedderDecreaseFactor), s.cfg.MaxInflightBytes/shedderMinLimitDivisor)
+	} else {
+		s.limit = min(s.limit+s.cfg.MaxInflightBytes/shedderIncreaseSteps, s.cfg.MaxInflightBytes)
+	}
+	metricInflightPushBytesLimit.Set(float64(s.limit))
+}
+
+// shedError returns the error of a shed push. It is a resource exhausted error that tells the client when
+// to retry.
+func (s *loadShedder) shedError(tenant string, size uint64) error {
+	s.mtx.Lock()
+	inflight, limit := s.inflight, s.limit
+	s.mtx.Unlock()
+
+	st := status.Newf(codes.ResourceExhausted,
+		"%s: distributor is overloaded (inflight: %d bytes, limit: %d bytes) while adding %d bytes for user %s",
+		overrides.ErrorPrefixLoadShed, inflight, limit, size, tenant)
+
+	if s.cfg.RetryAfter > 0 {
+		if withDetails, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(s.cfg.RetryAfter)}); err == nil {
+			st = withDetails
+		}
+	}
+
+	return st.Err()
+}
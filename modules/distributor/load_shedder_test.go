@@ -0,0 +1,119 @@
+package distributor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/tempo/modules/overrides"
+)
+
+func TestLoadShedderDisabled(t *testing.T) {
+	s := newLoadShedder(LoadSheddingConfig{Enabled: false, MaxInflightBytes: 100})
+
+	require.True(t, s.admit("a", 100))
+	require.True(t, s.admit("a", 100))
+	require.Equal(t, uint64(200), s.inflight)
+
+	s.release("a", 100)
+	s.release("a", 100)
+	require.Equal(t, uint64(0), s.inflight)
+	require.Empty(t, s.tenants)
+
+	// no max inflight bytes disables shedding
+	s = newLoadShedder(LoadSheddingConfig{Enabled: true})
+	require.True(t, s.admit("a", 100))
+	require.True(t, s.admit("a", 100))
+}
+
+func TestLoadShedderAdmit(t *testing.T) {
+	s := newLoadShedder(LoadSheddingConfig{Enabled: true, MaxInflightBytes: 100})
+
+	// a push larger than the limit is admitted if nothing else is inflight
+	require.True(t, s.admit("a", 150))
+	require.False(t, s.admit("a", 1))
+	s.release("a", 150)
+
+	require.True(t, s.admit("a", 45))
+	require.True(t, s.admit("b", 5))
+	// over half the limit a tenant can only use its fair share
+	require.False(t, s.admit("a", 10))
+	require.True(t, s.admit("b", 40))
+	// over the limit nobody is admitted
+	require.False(t, s.admit("c", 20))
+	require.True(t, s.admit("c", 10))
+	require.Equal(t, uint64(100), s.inflight)
+
+	s.release("a", 45)
+	require.True(t, s.admit("c", 20))
+
+	s.release("b", 5)
+	s.release("b", 40)
+	s.release("c", 10)
+	s.release("c", 20)
+	require.Equal(t, uint64(0), s.inflight)
+	require.Empty(t, s.tenants)
+}
+
+func TestLoadShedderAdaptiveLimit(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := newLoadShedder(LoadSheddingConfig{Enabled: true, MaxInflightBytes: 1000, TargetLatency: time.Second})
+	s.now = func() time.Time { return now }
+
+	// the limit is decreased while the latency is above the target
+	s.observeLatency(2 * time.Second)
+	require.Equal(t, uint64(800), s.limit)
+
+	// at most once per interval
+	s.observeLatency(2 * time.Second)
+	require.Equal(t, uint64(800), s.limit)
+
+	// but never below the minimum
+	for i := 0; i < 20; i++ {
+		now = now.Add(shedderAdjustInterval)
+		s.observeLatency(2 * time.Second)
+	}
+	require.Equal(t, uint64(100), s.limit)
+
+	// the limit is increased again once the latency recovers
+	for s.latency > time.Second {
+		s.observeLatency(0)
+	}
+	now = now.Add(shedderAdjustInterval)
+	s.observeLatency(0)
+	require.Equal(t, uint64(150), s.limit)
+
+	// but never above the maximum
+	for i := 0; i < 40; i++ {
+		now = now.Add(shedderAdjustInterval)
+		s.observeLatency(0)
+	}
+	require.Equal(t, uint64(1000), s.limit)
+}
+
+func TestLoadShedderShedError(t *testing.T) {
+	s := newLoadShedder(LoadSheddingConfig{Enabled: true, MaxInflightBytes: 100, RetryAfter: 5 * time.Second})
+	require.True(t, s.admit("a", 80))
+	require.False(t, s.admit("b", 30))
+
+	st, ok := status.FromError(s.shedError("b", 30))
+	require.True(t, ok)
+	require.Equal(t, codes.ResourceExhausted, st.Code())
+	require.True(t, strings.HasPrefix(st.Message(), overrides.ErrorPrefixLoadShed))
+	require.Len(t, st.Details(), 1)
+	retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	require.Equal(t, 5*time.Second, retryInfo.RetryDelay.AsDuration())
+
+	// no retry info without retry after
+	s = newLoadShedder(LoadSheddingConfig{Enabled: true, MaxInflightBytes: 100})
+	st, ok = status.FromError(s.shedError("b", 30))
+	require.True(t, ok)
+	require.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Empty(t, st.Details())
+}
@@ -0,0 +1,59 @@
+package distributor
+
+import (
+	"errors"
+	"io"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+var metricPushStreams = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: "tempo",
+	Name:      "distributor_push_streams",
+	Help:      "The current number of open span push streams.",
+})
+
+var _ tempopb.StreamingPusherServer = (*Distributor)(nil)
+
+// PushStream implements tempopb.StreamingPusherServer. Every batch is pushed before the next one is received, so
+// agents pushing faster than the distributor can keep up are slowed down by the flow control of the stream. The
+// tenant of the stream is extracted by the gRPC auth middleware when the stream is opened.
+func (d *Distributor) PushStream(stream tempopb.StreamingPusher_PushStreamServer) error {
+	metricPushStreams.Inc()
+	defer metricPushStreams.Dec()
+
+	ctx := stream.Context()
+	resp := &tempopb.PushStreamResponse{}
+	unmarshaler := &ptrace.ProtoUnmarshaler{}
+
+	for {
+		batch, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(resp)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Convert to bytes and back, tempopb.Trace is wire-compatible with ExportTraceServiceRequest.
+		b, err := batch.Marshal()
+		if err != nil {
+			return err
+		}
+		traces, err := unmarshaler.UnmarshalTraces(b)
+		if err != nil {
+			return err
+		}
+
+		if _, err := d.PushTraces(ctx, traces); err != nil {
+			return err
+		}
+
+		resp.Batches++
+		resp.Spans += uint64(traces.SpanCount())
+	}
+}
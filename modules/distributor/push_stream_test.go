@@ -0,0 +1,85 @@
+package distributor
+
+import (
+	"context"
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+type mockPushStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	batches []*tempopb.Trace
+	resp    *tempopb.PushStreamResponse
+}
+
+func (s *mockPushStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *mockPushStream) Recv() (*tempopb.Trace, error) {
+	if len(s.batches) == 0 {
+		return nil, io.EOF
+	}
+	b := s.batches[0]
+	s.batches = s.batches[1:]
+	return b, nil
+}
+
+func (s *mockPushStream) SendAndClose(resp *tempopb.PushStreamResponse) error {
+	s.resp = resp
+	return nil
+}
+
+func TestPushStream(t *testing.T) {
+	limits := overrides.Config{}
+	limits.RegisterFlagsAndApplyDefaults(&flag.FlagSet{})
+	d := prepare(t, limits, nil)
+
+	stream := &mockPushStream{
+		ctx: ctx,
+		batches: []*tempopb.Trace{
+			{Batches: []*v1.ResourceSpans{test.MakeBatch(10, nil)}},
+			{Batches: []*v1.ResourceSpans{test.MakeBatch(5, nil), test.MakeBatch(5, nil)}},
+			{},
+		},
+	}
+
+	require.NoError(t, d.PushStream(stream))
+	require.Equal(t, &tempopb.PushStreamResponse{Batches: 3, Spans: 20}, stream.resp)
+}
+
+func TestPushStreamRejectedBatch(t *testing.T) {
+	d := prepare(t, overrides.Config{
+		Defaults: overrides.Overrides{
+			Ingestion: overrides.IngestionOverrides{
+				RateStrategy:   overrides.LocalIngestionRateStrategy,
+				RateLimitBytes: 400,
+				BurstSizeBytes: 200,
+			},
+		},
+	}, nil)
+
+	stream := &mockPushStream{
+		ctx: ctx,
+		batches: []*tempopb.Trace{
+			{Batches: []*v1.ResourceSpans{test.MakeBatch(100, nil)}},
+		},
+	}
+
+	// the stream ends with the error of the batch
+	err := d.PushStream(stream)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.Nil(t, stream.resp)
+}
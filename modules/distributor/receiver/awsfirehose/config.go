@@ -0,0 +1,44 @@
+package awsfirehose
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configopaque"
+)
+
+const (
+	// RecordEncodingOTLPProto expects every Firehose record to contain a single binary encoded
+	// ExportTraceServiceRequest.
+	RecordEncodingOTLPProto = "otlp_proto"
+	// RecordEncodingOTLPJSON expects every Firehose record to contain a single json encoded
+	// ExportTraceServiceRequest.
+	RecordEncodingOTLPJSON = "otlp_json"
+	// RecordEncodingOTLPv1 expects every Firehose record to contain one or more binary encoded
+	// ExportTraceServiceRequests, each prefixed with its varint encoded length. This is the
+	// format used by AWS for OTLP payloads (e.g. CloudWatch metric streams).
+	RecordEncodingOTLPv1 = "otlp_v1"
+)
+
+// Config defines configuration for the AWS Firehose receiver.
+type Config struct {
+	// Configures the receiver server protocol.
+	confighttp.ServerConfig `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+	// AccessKey is checked against the X-Amz-Firehose-Access-Key header if set.
+	AccessKey configopaque.String `mapstructure:"access_key"`
+	// RecordEncoding is the encoding of the data of every record in the Firehose request.
+	RecordEncoding string `mapstructure:"record_encoding"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks the receiver configuration is valid
+func (cfg *Config) Validate() error {
+	switch cfg.RecordEncoding {
+	case RecordEncodingOTLPProto, RecordEncodingOTLPJSON, RecordEncodingOTLPv1:
+	default:
+		return fmt.Errorf("unsupported record_encoding %q", cfg.RecordEncoding)
+	}
+	return nil
+}
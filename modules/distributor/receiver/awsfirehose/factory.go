@@ -0,0 +1,45 @@
+package awsfirehose
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+)
+
+const defaultBindEndpoint = "0.0.0.0:4433"
+
+// Type is the receiver type used in the receivers config block.
+var Type = component.MustNewType("awsfirehose")
+
+// NewFactory creates a new AWS Firehose receiver factory
+func NewFactory() receiver.Factory {
+	return receiver.NewFactory(
+		Type,
+		createDefaultConfig,
+		receiver.WithTraces(createTracesReceiver, component.StabilityLevelAlpha),
+	)
+}
+
+// createDefaultConfig creates the default configuration for the AWS Firehose receiver.
+func createDefaultConfig() component.Config {
+	return &Config{
+		ServerConfig: confighttp.ServerConfig{
+			Endpoint: defaultBindEndpoint,
+		},
+		RecordEncoding: RecordEncodingOTLPProto,
+	}
+}
+
+// createTracesReceiver creates a trace receiver based on provided config.
+func createTracesReceiver(
+	_ context.Context,
+	set receiver.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Traces,
+) (receiver.Traces, error) {
+	rCfg := cfg.(*Config)
+	return newReceiver(rCfg, nextConsumer, set), nil
+}
@@ -0,0 +1,238 @@
+package awsfirehose
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	headerRequestID = "X-Amz-Firehose-Request-Id"
+	headerAccessKey = "X-Amz-Firehose-Access-Key"
+)
+
+// firehoseRequest is the body of a Firehose HTTP endpoint delivery request.
+// https://docs.aws.amazon.com/firehose/latest/dev/httpdeliveryrequestresponse.html
+type firehoseRequest struct {
+	RequestID string           `json:"requestId"`
+	Timestamp int64            `json:"timestamp"`
+	Records   []firehoseRecord `json:"records"`
+}
+
+type firehoseRecord struct {
+	// Data is base64 encoded in the request. encoding/json decodes it for us.
+	Data []byte `json:"data"`
+}
+
+// firehoseResponse is the body Firehose expects in response to a delivery request.
+type firehoseResponse struct {
+	RequestID    string `json:"requestId"`
+	Timestamp    int64  `json:"timestamp"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
+
+type firehoseReceiver struct {
+	config       *Config
+	nextConsumer consumer.Traces
+	settings     receiver.CreateSettings
+	unmarshal    func([]byte) (ptrace.Traces, error)
+
+	server     *http.Server
+	shutdownWG sync.WaitGroup
+}
+
+var (
+	_ receiver.Traces = (*firehoseReceiver)(nil)
+	_ http.Handler    = (*firehoseReceiver)(nil)
+)
+
+func newReceiver(config *Config, nextConsumer consumer.Traces, settings receiver.CreateSettings) *firehoseReceiver {
+	r := &firehoseReceiver{
+		config:       config,
+		nextConsumer: nextConsumer,
+		settings:     settings,
+	}
+
+	switch config.RecordEncoding {
+	case RecordEncodingOTLPJSON:
+		u := &ptrace.JSONUnmarshaler{}
+		r.unmarshal = u.UnmarshalTraces
+	case RecordEncodingOTLPv1:
+		r.unmarshal = unmarshalDelimited
+	default:
+		u := &ptrace.ProtoUnmarshaler{}
+		r.unmarshal = u.UnmarshalTraces
+	}
+
+	return r
+}
+
+// Start spins up the receiver's HTTP server.
+func (fr *firehoseReceiver) Start(ctx context.Context, host component.Host) error {
+	if host == nil {
+		return errors.New("nil host")
+	}
+
+	var err error
+	fr.server, err = fr.config.ServerConfig.ToServer(ctx, host, fr.settings.TelemetrySettings, fr)
+	if err != nil {
+		return err
+	}
+
+	var listener net.Listener
+	listener, err = fr.config.ServerConfig.ToListener(ctx)
+	if err != nil {
+		return err
+	}
+	fr.shutdownWG.Add(1)
+	go func() {
+		defer fr.shutdownWG.Done()
+
+		if errHTTP := fr.server.Serve(listener); !errors.Is(errHTTP, http.ErrServerClosed) && errHTTP != nil {
+			fr.settings.TelemetrySettings.ReportStatus(component.NewFatalErrorEvent(errHTTP))
+		}
+	}()
+
+	return nil
+}
+
+// Shutdown stops the receiver's HTTP server.
+func (fr *firehoseReceiver) Shutdown(context.Context) error {
+	var err error
+	if fr.server != nil {
+		err = fr.server.Close()
+	}
+	fr.shutdownWG.Wait()
+	return err
+}
+
+// ServeHTTP unwraps the Firehose envelope and passes all spans of all records to the next consumer as a single batch.
+func (fr *firehoseReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Header.Get(headerRequestID)
+
+	if r.Method != http.MethodPost {
+		fr.writeResponse(w, requestID, http.StatusMethodNotAllowed, errors.New("only POST is supported"))
+		return
+	}
+
+	if fr.config.AccessKey != "" {
+		key := r.Header.Get(headerAccessKey)
+		if subtle.ConstantTimeCompare([]byte(key), []byte(fr.config.AccessKey)) != 1 {
+			fr.writeResponse(w, requestID, http.StatusUnauthorized, errors.New("invalid access key"))
+			return
+		}
+	}
+
+	req := firehoseRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		fr.writeResponse(w, requestID, http.StatusBadRequest, fmt.Errorf("failed to decode firehose request: %w", err))
+		return
+	}
+	if requestID == "" {
+		requestID = req.RequestID
+	}
+
+	td, err := fr.unmarshalRecords(req.Records)
+	if err != nil {
+		fr.writeResponse(w, requestID, http.StatusBadRequest, err)
+		return
+	}
+
+	if td.SpanCount() > 0 {
+		err = fr.nextConsumer.ConsumeTraces(r.Context(), td)
+		if err != nil {
+			fr.writeResponse(w, requestID, httpStatusFromError(err), err)
+			return
+		}
+	}
+
+	fr.writeResponse(w, requestID, http.StatusOK, nil)
+}
+
+func (fr *firehoseReceiver) unmarshalRecords(records []firehoseRecord) (ptrace.Traces, error) {
+	td := ptrace.NewTraces()
+	for i, record := range records {
+		recordTraces, err := fr.unmarshal(record.Data)
+		if err != nil {
+			return ptrace.Traces{}, fmt.Errorf("failed to unmarshal record %d: %w", i, err)
+		}
+		recordTraces.ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	}
+	return td, nil
+}
+
+func (fr *firehoseReceiver) writeResponse(w http.ResponseWriter, requestID string, statusCode int, err error) {
+	resp := firehoseResponse{
+		RequestID: requestID,
+		Timestamp: time.Now().UnixMilli(),
+	}
+	if err != nil {
+		resp.ErrorMessage = err.Error()
+		fr.settings.Logger.Debug("failed to process firehose request", zap.String("requestId", requestID), zap.Error(err))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// unmarshalDelimited decodes a sequence of varint length prefixed ExportTraceServiceRequests.
+func unmarshalDelimited(buf []byte) (ptrace.Traces, error) {
+	u := &ptrace.ProtoUnmarshaler{}
+	td := ptrace.NewTraces()
+
+	for len(buf) > 0 {
+		length, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return ptrace.Traces{}, errors.New("invalid length prefix")
+		}
+		buf = buf[n:]
+		if uint64(len(buf)) < length {
+			return ptrace.Traces{}, fmt.Errorf("message length %d exceeds remaining record size %d", length, len(buf))
+		}
+
+		msg, err := u.UnmarshalTraces(buf[:length])
+		if err != nil {
+			return ptrace.Traces{}, err
+		}
+		msg.ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+		buf = buf[length:]
+	}
+
+	return td, nil
+}
+
+// httpStatusFromError translates errors returned by the distributor into status codes. Firehose retries
+// every non 200 response so the exact code is mainly informational.
+func httpStatusFromError(err error) int {
+	s, ok := status.FromError(err)
+	if !ok {
+		return http.StatusInternalServerError
+	}
+
+	switch s.Code() {
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
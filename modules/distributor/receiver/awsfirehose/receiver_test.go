@@ -0,0 +1,186 @@
+package awsfirehose
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFirehoseReceiver(t *testing.T) {
+	protoRecord := marshalProto(t, makeTraces(2))
+	jsonRecord, err := (&ptrace.JSONMarshaler{}).MarshalTraces(makeTraces(3))
+	require.NoError(t, err)
+
+	delimitedRecord := appendDelimited(nil, marshalProto(t, makeTraces(1)))
+	delimitedRecord = appendDelimited(delimitedRecord, marshalProto(t, makeTraces(4)))
+
+	tcs := []struct {
+		name           string
+		encoding       string
+		accessKey      string
+		reqAccessKey   string
+		records        [][]byte
+		body           []byte
+		consumerErr    error
+		expectedStatus int
+		expectedSpans  int
+	}{
+		{
+			name:           "proto",
+			encoding:       RecordEncodingOTLPProto,
+			records:        [][]byte{protoRecord, protoRecord},
+			expectedStatus: http.StatusOK,
+			expectedSpans:  4,
+		},
+		{
+			name:           "json",
+			encoding:       RecordEncodingOTLPJSON,
+			records:        [][]byte{jsonRecord},
+			expectedStatus: http.StatusOK,
+			expectedSpans:  3,
+		},
+		{
+			name:           "delimited",
+			encoding:       RecordEncodingOTLPv1,
+			records:        [][]byte{delimitedRecord},
+			expectedStatus: http.StatusOK,
+			expectedSpans:  5,
+		},
+		{
+			name:           "no records",
+			encoding:       RecordEncodingOTLPProto,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "access key",
+			encoding:       RecordEncodingOTLPProto,
+			accessKey:      "secret",
+			reqAccessKey:   "secret",
+			records:        [][]byte{protoRecord},
+			expectedStatus: http.StatusOK,
+			expectedSpans:  2,
+		},
+		{
+			name:           "wrong access key",
+			encoding:       RecordEncodingOTLPProto,
+			accessKey:      "secret",
+			reqAccessKey:   "nope",
+			records:        [][]byte{protoRecord},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid body",
+			encoding:       RecordEncodingOTLPProto,
+			body:           []byte("{"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid record",
+			encoding:       RecordEncodingOTLPJSON,
+			records:        [][]byte{[]byte("not json")},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "rate limited",
+			encoding:       RecordEncodingOTLPProto,
+			records:        [][]byte{protoRecord},
+			consumerErr:    status.Error(codes.ResourceExhausted, "slow down"),
+			expectedStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:           "consumer error",
+			encoding:       RecordEncodingOTLPProto,
+			records:        [][]byte{protoRecord},
+			consumerErr:    errors.New("boom"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.RecordEncoding = tc.encoding
+			cfg.AccessKey = configopaque.String(tc.accessKey)
+
+			sink := &consumertest.TracesSink{}
+			var next consumer.Traces = sink
+			if tc.consumerErr != nil {
+				next = consumertest.NewErr(tc.consumerErr)
+			}
+
+			r := newReceiver(cfg, next, receivertest.NewNopCreateSettings())
+
+			body := tc.body
+			if body == nil {
+				req := firehoseRequest{RequestID: "req-1", Timestamp: 1}
+				for _, rec := range tc.records {
+					req.Records = append(req.Records, firehoseRecord{Data: rec})
+				}
+				body, _ = json.Marshal(req)
+			}
+
+			httpReq := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+			httpReq.Header.Set(headerRequestID, "req-1")
+			if tc.reqAccessKey != "" {
+				httpReq.Header.Set(headerAccessKey, tc.reqAccessKey)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httpReq)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+
+			resp := firehoseResponse{}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			require.Equal(t, "req-1", resp.RequestID)
+			require.Equal(t, tc.expectedStatus != http.StatusOK, resp.ErrorMessage != "")
+
+			if tc.consumerErr == nil {
+				require.Equal(t, tc.expectedSpans, sink.SpanCount())
+			}
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	require.NoError(t, cfg.Validate())
+
+	cfg.RecordEncoding = "foo"
+	require.Error(t, cfg.Validate())
+}
+
+func makeTraces(spans int) ptrace.Traces {
+	td := ptrace.NewTraces()
+	ss := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty()
+	for i := 0; i < spans; i++ {
+		s := ss.Spans().AppendEmpty()
+		s.SetName("test")
+		s.SetTraceID([16]byte{1, 2, 3})
+		s.SetSpanID([8]byte{byte(i + 1)})
+	}
+	return td
+}
+
+func marshalProto(t *testing.T, td ptrace.Traces) []byte {
+	b, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(td)
+	require.NoError(t, err)
+	return b
+}
+
+func appendDelimited(buf []byte, msg []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(msg)))
+	return append(buf, msg...)
+}
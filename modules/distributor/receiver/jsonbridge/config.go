@@ -0,0 +1,55 @@
+package jsonbridge
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+const (
+	TransportTCP = "tcp"
+	TransportUDP = "udp"
+)
+
+// Config defines configuration for the JSON bridge receiver.
+type Config struct {
+	// HTTP configures the HTTP server accepting JSON batches. Set the endpoint to an empty string to disable it.
+	HTTP confighttp.ServerConfig `mapstructure:"http"`
+	// Syslog configures an optional syslog listener. Every syslog message contains a single JSON batch.
+	Syslog *SyslogConfig `mapstructure:"syslog"`
+}
+
+// SyslogConfig defines configuration for the syslog listener of the JSON bridge receiver.
+type SyslogConfig struct {
+	// Endpoint is the address to listen on.
+	Endpoint string `mapstructure:"endpoint"`
+	// Transport is either tcp or udp. Messages received over tcp are newline delimited.
+	Transport string `mapstructure:"transport"`
+	// Tenant is the tenant spans received over syslog are pushed to when multitenancy is enabled.
+	Tenant string `mapstructure:"tenant"`
+	// MaxMessageBytes is the maximum size of a single message received over tcp. Defaults to 1MiB.
+	MaxMessageBytes int `mapstructure:"max_message_bytes"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks the receiver configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.HTTP.Endpoint == "" && cfg.Syslog == nil {
+		return errors.New("at least one of http or syslog must be enabled")
+	}
+
+	if cfg.Syslog != nil {
+		if cfg.Syslog.Endpoint == "" {
+			return errors.New("syslog endpoint must be set")
+		}
+		switch cfg.Syslog.Transport {
+		case TransportTCP, TransportUDP:
+		default:
+			return fmt.Errorf("unsupported syslog transport %q", cfg.Syslog.Transport)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package jsonbridge
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+)
+
+const (
+	defaultBindEndpoint    = "0.0.0.0:9413"
+	defaultMaxMessageBytes = 1 << 20
+)
+
+// Type is the receiver type used in the receivers config block.
+var Type = component.MustNewType("jsonbridge")
+
+// NewFactory creates a new JSON bridge receiver factory
+func NewFactory() receiver.Factory {
+	return receiver.NewFactory(
+		Type,
+		createDefaultConfig,
+		receiver.WithTraces(createTracesReceiver, component.StabilityLevelAlpha),
+	)
+}
+
+// createDefaultConfig creates the default configuration for the JSON bridge receiver. The syslog listener
+// is disabled by default.
+func createDefaultConfig() component.Config {
+	return &Config{
+		HTTP: confighttp.ServerConfig{
+			Endpoint: defaultBindEndpoint,
+		},
+	}
+}
+
+// createTracesReceiver creates a trace receiver based on provided config.
+func createTracesReceiver(
+	_ context.Context,
+	set receiver.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Traces,
+) (receiver.Traces, error) {
+	rCfg := cfg.(*Config)
+	return newReceiver(rCfg, nextConsumer, set), nil
+}
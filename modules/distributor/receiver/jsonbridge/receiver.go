@@ -0,0 +1,314 @@
+package jsonbridge
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/grafana/dskit/user"
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const udpPacketSize = 64 * 1024
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type bridgeReceiver struct {
+	config       *Config
+	nextConsumer consumer.Traces
+	settings     receiver.CreateSettings
+
+	server       *http.Server
+	syslogTCP    net.Listener
+	syslogUDP    net.PacketConn
+	syslogConnMu sync.Mutex
+	syslogConns  map[net.Conn]struct{}
+	shutdownWG   sync.WaitGroup
+}
+
+var (
+	_ receiver.Traces = (*bridgeReceiver)(nil)
+	_ http.Handler    = (*bridgeReceiver)(nil)
+)
+
+func newReceiver(config *Config, nextConsumer consumer.Traces, settings receiver.CreateSettings) *bridgeReceiver {
+	return &bridgeReceiver{
+		config:       config,
+		nextConsumer: nextConsumer,
+		settings:     settings,
+		syslogConns:  map[net.Conn]struct{}{},
+	}
+}
+
+// Start spins up the receiver's HTTP server and syslog listener.
+func (br *bridgeReceiver) Start(ctx context.Context, host component.Host) error {
+	if host == nil {
+		return errors.New("nil host")
+	}
+
+	if br.config.HTTP.Endpoint != "" {
+		if err := br.startHTTP(ctx, host); err != nil {
+			return err
+		}
+	}
+
+	if br.config.Syslog != nil {
+		if err := br.startSyslog(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (br *bridgeReceiver) startHTTP(ctx context.Context, host component.Host) error {
+	var err error
+	br.server, err = br.config.HTTP.ToServer(ctx, host, br.settings.TelemetrySettings, br)
+	if err != nil {
+		return err
+	}
+
+	listener, err := br.config.HTTP.ToListener(ctx)
+	if err != nil {
+		return err
+	}
+	br.shutdownWG.Add(1)
+	go func() {
+		defer br.shutdownWG.Done()
+
+		if errHTTP := br.server.Serve(listener); !errors.Is(errHTTP, http.ErrServerClosed) && errHTTP != nil {
+			br.settings.TelemetrySettings.ReportStatus(component.NewFatalErrorEvent(errHTTP))
+		}
+	}()
+
+	return nil
+}
+
+func (br *bridgeReceiver) startSyslog() error {
+	var err error
+
+	switch br.config.Syslog.Transport {
+	case TransportUDP:
+		br.syslogUDP, err = net.ListenPacket("udp", br.config.Syslog.Endpoint)
+		if err != nil {
+			return fmt.Errorf("failed to listen on syslog endpoint: %w", err)
+		}
+		br.shutdownWG.Add(1)
+		go br.serveSyslogUDP()
+	default:
+		br.syslogTCP, err = net.Listen("tcp", br.config.Syslog.Endpoint)
+		if err != nil {
+			return fmt.Errorf("failed to listen on syslog endpoint: %w", err)
+		}
+		br.shutdownWG.Add(1)
+		go br.serveSyslogTCP()
+	}
+
+	return nil
+}
+
+// Shutdown stops the receiver's HTTP server and syslog listener.
+func (br *bridgeReceiver) Shutdown(context.Context) error {
+	var errs []error
+	if br.server != nil {
+		errs = append(errs, br.server.Close())
+	}
+	if br.syslogTCP != nil {
+		errs = append(errs, br.syslogTCP.Close())
+	}
+	if br.syslogUDP != nil {
+		errs = append(errs, br.syslogUDP.Close())
+	}
+
+	br.syslogConnMu.Lock()
+	for conn := range br.syslogConns {
+		_ = conn.Close()
+	}
+	br.syslogConnMu.Unlock()
+
+	br.shutdownWG.Wait()
+	return errors.Join(errs...)
+}
+
+// ServeHTTP translates the JSON batches in the request body and passes all spans to the next consumer as a
+// single batch.
+func (br *bridgeReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		br.writeError(w, http.StatusMethodNotAllowed, errors.New("only POST is supported"))
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		br.writeError(w, http.StatusBadRequest, fmt.Errorf("failed to read request: %w", err))
+		return
+	}
+
+	if err := br.consume(r.Context(), body); err != nil {
+		var decodeErr *decodeError
+		if errors.As(err, &decodeErr) {
+			br.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		br.writeError(w, httpStatusFromError(err), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (br *bridgeReceiver) writeError(w http.ResponseWriter, statusCode int, err error) {
+	br.settings.Logger.Debug("failed to process json bridge request", zap.Error(err))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(errorResponse{Error: err.Error()})
+}
+
+// decodeError is returned by consume if the payload is invalid.
+type decodeError struct {
+	err error
+}
+
+func (e *decodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *decodeError) Unwrap() error {
+	return e.err
+}
+
+func (br *bridgeReceiver) consume(ctx context.Context, payload []byte) error {
+	batches, err := unmarshalBatches(payload)
+	if err != nil {
+		return &decodeError{fmt.Errorf("failed to decode batches: %w", err)}
+	}
+
+	td, err := translate(batches)
+	if err != nil {
+		return &decodeError{err}
+	}
+
+	if td.SpanCount() == 0 {
+		return nil
+	}
+	return br.nextConsumer.ConsumeTraces(ctx, td)
+}
+
+func (br *bridgeReceiver) serveSyslogTCP() {
+	defer br.shutdownWG.Done()
+
+	for {
+		conn, err := br.syslogTCP.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				br.settings.TelemetrySettings.ReportStatus(component.NewFatalErrorEvent(err))
+			}
+			return
+		}
+
+		br.syslogConnMu.Lock()
+		br.syslogConns[conn] = struct{}{}
+		br.syslogConnMu.Unlock()
+
+		br.shutdownWG.Add(1)
+		go br.handleSyslogConn(conn)
+	}
+}
+
+func (br *bridgeReceiver) handleSyslogConn(conn net.Conn) {
+	defer br.shutdownWG.Done()
+	defer func() {
+		br.syslogConnMu.Lock()
+		delete(br.syslogConns, conn)
+		br.syslogConnMu.Unlock()
+		_ = conn.Close()
+	}()
+
+	maxMessageBytes := br.config.Syslog.MaxMessageBytes
+	if maxMessageBytes <= 0 {
+		maxMessageBytes = defaultMaxMessageBytes
+	}
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageBytes)
+	for scanner.Scan() {
+		br.consumeSyslogMessage(conn.RemoteAddr(), scanner.Bytes())
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, net.ErrClosed) {
+		br.settings.Logger.Debug("failed to read syslog connection", zap.Error(err))
+	}
+}
+
+func (br *bridgeReceiver) serveSyslogUDP() {
+	defer br.shutdownWG.Done()
+
+	buf := make([]byte, udpPacketSize)
+	for {
+		n, addr, err := br.syslogUDP.ReadFrom(buf)
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				br.settings.TelemetrySettings.ReportStatus(component.NewFatalErrorEvent(err))
+			}
+			return
+		}
+
+		br.consumeSyslogMessage(addr, buf[:n])
+	}
+}
+
+// consumeSyslogMessage consumes the JSON batch in the message part of a syslog message. The syslog header
+// is skipped by starting at the first '{' or '['.
+func (br *bridgeReceiver) consumeSyslogMessage(addr net.Addr, msg []byte) {
+	start := bytes.IndexAny(msg, "{[")
+	if start < 0 {
+		if len(bytes.TrimSpace(msg)) > 0 {
+			br.settings.Logger.Debug("dropping syslog message without json payload", zap.Stringer("client", addr))
+		}
+		return
+	}
+
+	info := client.Info{Addr: addr}
+	if br.config.Syslog.Tenant != "" {
+		info.Metadata = client.NewMetadata(map[string][]string{user.OrgIDHeaderName: {br.config.Syslog.Tenant}})
+	}
+
+	ctx := client.NewContext(context.Background(), info)
+	if err := br.consume(ctx, msg[start:]); err != nil {
+		br.settings.Logger.Debug("failed to process syslog message", zap.Stringer("client", addr), zap.Error(err))
+	}
+}
+
+// httpStatusFromError translates errors returned by the distributor into status codes.
+func httpStatusFromError(err error) int {
+	s, ok := status.FromError(err)
+	if !ok {
+		return http.StatusInternalServerError
+	}
+
+	switch s.Code() {
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,277 @@
+package jsonbridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testBatch = `{
+	"service": "checkout",
+	"attributes": {"host.name": "vm-12"},
+	"spans": [
+		{
+			"trace_id": "5b8aa5a2d2c872e8321cf37308d69df2",
+			"span_id": "051581bf3cb55c13",
+			"name": "GET /cart",
+			"kind": "server",
+			"start_time": 1700000000000000,
+			"duration": 1500,
+			"status": "error",
+			"status_message": "timeout",
+			"attributes": {"http.status_code": 504, "retry": true, "ratio": 0.5, "tags": ["a", "b"]},
+			"events": [{"time": 1700000000000500, "name": "retry", "attributes": {"attempt": 2}}]
+		},
+		{
+			"trace_id": "321cf37308d69df2",
+			"span_id": "1cf37308d69df205",
+			"parent_span_id": "051581bf3cb55c13",
+			"name": "SELECT",
+			"kind": "client",
+			"start_time": 1700000000000100,
+			"duration": 200
+		}
+	]
+}`
+
+func TestTranslate(t *testing.T) {
+	batches, err := unmarshalBatches([]byte(testBatch))
+	require.NoError(t, err)
+
+	td, err := translate(batches)
+	require.NoError(t, err)
+	require.Equal(t, 1, td.ResourceSpans().Len())
+
+	rs := td.ResourceSpans().At(0)
+	require.Equal(t, map[string]any{"service.name": "checkout", "host.name": "vm-12"}, rs.Resource().Attributes().AsRaw())
+
+	spans := rs.ScopeSpans().At(0).Spans()
+	require.Equal(t, 2, spans.Len())
+
+	span := spans.At(0)
+	require.Equal(t, "5b8aa5a2d2c872e8321cf37308d69df2", span.TraceID().String())
+	require.Equal(t, "051581bf3cb55c13", span.SpanID().String())
+	require.True(t, span.ParentSpanID().IsEmpty())
+	require.Equal(t, "GET /cart", span.Name())
+	require.Equal(t, ptrace.SpanKindServer, span.Kind())
+	require.Equal(t, time.UnixMicro(1700000000000000).UTC(), span.StartTimestamp().AsTime())
+	require.Equal(t, 1500*time.Microsecond, span.EndTimestamp().AsTime().Sub(span.StartTimestamp().AsTime()))
+	require.Equal(t, ptrace.StatusCodeError, span.Status().Code())
+	require.Equal(t, "timeout", span.Status().Message())
+	require.Equal(t, map[string]any{
+		"http.status_code": int64(504),
+		"retry":            true,
+		"ratio":            0.5,
+		"tags":             `["a","b"]`,
+	}, span.Attributes().AsRaw())
+	require.Equal(t, 1, span.Events().Len())
+	require.Equal(t, "retry", span.Events().At(0).Name())
+	require.Equal(t, map[string]any{"attempt": int64(2)}, span.Events().At(0).Attributes().AsRaw())
+
+	// 64 bit trace ids are zero padded
+	span = spans.At(1)
+	require.Equal(t, "0000000000000000321cf37308d69df2", span.TraceID().String())
+	require.Equal(t, "051581bf3cb55c13", span.ParentSpanID().String())
+	require.Equal(t, ptrace.SpanKindClient, span.Kind())
+	require.Equal(t, ptrace.StatusCodeUnset, span.Status().Code())
+}
+
+func TestTranslateInvalid(t *testing.T) {
+	tcs := map[string]string{
+		"trace id":       `{"spans": [{"trace_id": "xyz", "span_id": "051581bf3cb55c13"}]}`,
+		"short trace id": `{"spans": [{"trace_id": "5b8a", "span_id": "051581bf3cb55c13"}]}`,
+		"span id":        `{"spans": [{"trace_id": "321cf37308d69df2", "span_id": "0515"}]}`,
+		"parent span id": `{"spans": [{"trace_id": "321cf37308d69df2", "span_id": "051581bf3cb55c13", "parent_span_id": "zz"}]}`,
+		"kind":           `{"spans": [{"trace_id": "321cf37308d69df2", "span_id": "051581bf3cb55c13", "kind": "foo"}]}`,
+		"status":         `{"spans": [{"trace_id": "321cf37308d69df2", "span_id": "051581bf3cb55c13", "status": "foo"}]}`,
+	}
+
+	for name, payload := range tcs {
+		t.Run(name, func(t *testing.T) {
+			batches, err := unmarshalBatches([]byte(payload))
+			require.NoError(t, err)
+
+			_, err = translate(batches)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestBridgeReceiverHTTP(t *testing.T) {
+	tcs := []struct {
+		name           string
+		method         string
+		body           string
+		consumerErr    error
+		expectedStatus int
+		expectedSpans  int
+	}{
+		{
+			name:           "batch",
+			body:           testBatch,
+			expectedStatus: http.StatusAccepted,
+			expectedSpans:  2,
+		},
+		{
+			name:           "array of batches",
+			body:           "[" + testBatch + "," + testBatch + "]",
+			expectedStatus: http.StatusAccepted,
+			expectedSpans:  4,
+		},
+		{
+			name:           "no spans",
+			body:           `{"service": "checkout"}`,
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "wrong method",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "invalid json",
+			body:           "{",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid span",
+			body:           `{"spans": [{"trace_id": "xyz"}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "rate limited",
+			body:           testBatch,
+			consumerErr:    status.Error(codes.ResourceExhausted, "slow down"),
+			expectedStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:           "consumer error",
+			body:           testBatch,
+			consumerErr:    errors.New("boom"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			sink := &consumertest.TracesSink{}
+			var next consumer.Traces = sink
+			if tc.consumerErr != nil {
+				next = consumertest.NewErr(tc.consumerErr)
+			}
+
+			r := newReceiver(createDefaultConfig().(*Config), next, receivertest.NewNopCreateSettings())
+
+			method := tc.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(method, "/", strings.NewReader(tc.body)))
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			require.Equal(t, tc.expectedSpans, sink.SpanCount())
+		})
+	}
+}
+
+func TestBridgeReceiverSyslog(t *testing.T) {
+	for _, transport := range []string{TransportTCP, TransportUDP} {
+		t.Run(transport, func(t *testing.T) {
+			var (
+				mtx     sync.Mutex
+				spans   int
+				tenants []string
+			)
+			next, err := consumer.NewTraces(func(ctx context.Context, td ptrace.Traces) error {
+				mtx.Lock()
+				defer mtx.Unlock()
+
+				spans += td.SpanCount()
+				tenants = append(tenants, client.FromContext(ctx).Metadata.Get(user.OrgIDHeaderName)...)
+				return nil
+			})
+			require.NoError(t, err)
+
+			cfg := createDefaultConfig().(*Config)
+			cfg.HTTP.Endpoint = ""
+			cfg.Syslog = &SyslogConfig{
+				Endpoint:  "127.0.0.1:0",
+				Transport: transport,
+				Tenant:    "legacy",
+			}
+			require.NoError(t, cfg.Validate())
+
+			r := newReceiver(cfg, next, receivertest.NewNopCreateSettings())
+			require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+			t.Cleanup(func() {
+				require.NoError(t, r.Shutdown(context.Background()))
+			})
+
+			var addr net.Addr
+			if transport == TransportTCP {
+				addr = r.syslogTCP.Addr()
+			} else {
+				addr = r.syslogUDP.LocalAddr()
+			}
+			conn, err := net.Dial(transport, addr.String())
+			require.NoError(t, err)
+			defer conn.Close()
+
+			// rfc 5424 header followed by the batch
+			msg := "<134>1 2024-05-14T08:12:30Z vm-12 checkout - - - " + strings.ReplaceAll(testBatch, "\n", "")
+			for i := 0; i < 2; i++ {
+				_, err = fmt.Fprintln(conn, msg)
+				require.NoError(t, err)
+			}
+			// messages without a batch are ignored
+			_, err = fmt.Fprintln(conn, "<134>1 2024-05-14T08:12:30Z vm-12 checkout - - - hello")
+			require.NoError(t, err)
+
+			require.Eventually(t, func() bool {
+				mtx.Lock()
+				defer mtx.Unlock()
+				return spans == 4
+			}, 5*time.Second, 10*time.Millisecond)
+
+			mtx.Lock()
+			require.Equal(t, []string{"legacy", "legacy"}, tenants)
+			mtx.Unlock()
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	require.NoError(t, cfg.Validate())
+
+	cfg.HTTP.Endpoint = ""
+	require.Error(t, cfg.Validate())
+
+	cfg.Syslog = &SyslogConfig{Endpoint: "0.0.0.0:5514", Transport: TransportUDP}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Syslog.Transport = "foo"
+	require.Error(t, cfg.Validate())
+
+	cfg.Syslog = &SyslogConfig{Transport: TransportTCP}
+	require.Error(t, cfg.Validate())
+}
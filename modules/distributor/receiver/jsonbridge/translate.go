@@ -0,0 +1,218 @@
+package jsonbridge
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// jsonBatch is a batch of spans sent by a single service. Timestamps and durations are in microseconds,
+// like in Jaeger and Zipkin.
+type jsonBatch struct {
+	Service    string                 `json:"service"`
+	Attributes map[string]interface{} `json:"attributes"`
+	Spans      []jsonSpan             `json:"spans"`
+}
+
+type jsonSpan struct {
+	TraceID       string                 `json:"trace_id"`
+	SpanID        string                 `json:"span_id"`
+	ParentSpanID  string                 `json:"parent_span_id"`
+	Name          string                 `json:"name"`
+	Kind          string                 `json:"kind"`
+	StartTime     uint64                 `json:"start_time"`
+	Duration      uint64                 `json:"duration"`
+	Status        string                 `json:"status"`
+	StatusMessage string                 `json:"status_message"`
+	Attributes    map[string]interface{} `json:"attributes"`
+	Events        []jsonEvent            `json:"events"`
+}
+
+type jsonEvent struct {
+	Time       uint64                 `json:"time"`
+	Name       string                 `json:"name"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// unmarshalBatches decodes a single JSON batch or an array of batches.
+func unmarshalBatches(buf []byte) ([]jsonBatch, error) {
+	buf = bytes.TrimSpace(buf)
+	if len(buf) == 0 {
+		return nil, errors.New("empty payload")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.UseNumber()
+
+	if buf[0] == '[' {
+		var batches []jsonBatch
+		if err := dec.Decode(&batches); err != nil {
+			return nil, err
+		}
+		return batches, nil
+	}
+
+	var batch jsonBatch
+	if err := dec.Decode(&batch); err != nil {
+		return nil, err
+	}
+	return []jsonBatch{batch}, nil
+}
+
+// translate converts the batches into traces with one resource per batch.
+func translate(batches []jsonBatch) (ptrace.Traces, error) {
+	td := ptrace.NewTraces()
+
+	for _, b := range batches {
+		rs := td.ResourceSpans().AppendEmpty()
+		if b.Service != "" {
+			rs.Resource().Attributes().PutStr("service.name", b.Service)
+		}
+		putAttributes(rs.Resource().Attributes(), b.Attributes)
+
+		ss := rs.ScopeSpans().AppendEmpty()
+		for i, s := range b.Spans {
+			if err := translateSpan(s, ss.Spans().AppendEmpty()); err != nil {
+				return ptrace.Traces{}, fmt.Errorf("span %d of service %q: %w", i, b.Service, err)
+			}
+		}
+	}
+
+	return td, nil
+}
+
+func translateSpan(s jsonSpan, span ptrace.Span) error {
+	traceID, err := parseTraceID(s.TraceID)
+	if err != nil {
+		return err
+	}
+	span.SetTraceID(traceID)
+
+	spanID, err := parseSpanID(s.SpanID)
+	if err != nil {
+		return fmt.Errorf("invalid span_id: %w", err)
+	}
+	span.SetSpanID(spanID)
+
+	if s.ParentSpanID != "" {
+		parentSpanID, err := parseSpanID(s.ParentSpanID)
+		if err != nil {
+			return fmt.Errorf("invalid parent_span_id: %w", err)
+		}
+		span.SetParentSpanID(parentSpanID)
+	}
+
+	kind, err := parseKind(s.Kind)
+	if err != nil {
+		return err
+	}
+	span.SetKind(kind)
+
+	span.SetName(s.Name)
+	span.SetStartTimestamp(microsToTimestamp(s.StartTime))
+	span.SetEndTimestamp(microsToTimestamp(s.StartTime + s.Duration))
+	putAttributes(span.Attributes(), s.Attributes)
+
+	switch strings.ToLower(s.Status) {
+	case "":
+	case "ok":
+		span.Status().SetCode(ptrace.StatusCodeOk)
+	case "error":
+		span.Status().SetCode(ptrace.StatusCodeError)
+	default:
+		return fmt.Errorf("unsupported status %q", s.Status)
+	}
+	span.Status().SetMessage(s.StatusMessage)
+
+	for _, e := range s.Events {
+		event := span.Events().AppendEmpty()
+		event.SetName(e.Name)
+		event.SetTimestamp(microsToTimestamp(e.Time))
+		putAttributes(event.Attributes(), e.Attributes)
+	}
+
+	return nil
+}
+
+// parseTraceID accepts 128 bit and 64 bit hex encoded trace ids. 64 bit ids are zero padded.
+func parseTraceID(s string) (pcommon.TraceID, error) {
+	var id pcommon.TraceID
+	if len(s) != 32 && len(s) != 16 {
+		return id, fmt.Errorf("invalid trace_id %q: must be 16 or 32 hex characters", s)
+	}
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return id, fmt.Errorf("invalid trace_id %q: %w", s, err)
+	}
+	copy(id[len(id)-len(b):], b)
+	return id, nil
+}
+
+func parseSpanID(s string) (pcommon.SpanID, error) {
+	var id pcommon.SpanID
+	if len(s) != 16 {
+		return id, fmt.Errorf("%q must be 16 hex characters", s)
+	}
+
+	_, err := hex.Decode(id[:], []byte(s))
+	return id, err
+}
+
+func parseKind(s string) (ptrace.SpanKind, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return ptrace.SpanKindUnspecified, nil
+	case "internal":
+		return ptrace.SpanKindInternal, nil
+	case "server":
+		return ptrace.SpanKindServer, nil
+	case "client":
+		return ptrace.SpanKindClient, nil
+	case "producer":
+		return ptrace.SpanKindProducer, nil
+	case "consumer":
+		return ptrace.SpanKindConsumer, nil
+	default:
+		return ptrace.SpanKindUnspecified, fmt.Errorf("unsupported kind %q", s)
+	}
+}
+
+func microsToTimestamp(us uint64) pcommon.Timestamp {
+	return pcommon.NewTimestampFromTime(time.UnixMicro(int64(us)))
+}
+
+// putAttributes adds the attributes to m. Integral numbers become int attributes, other numbers double
+// attributes and nested objects and arrays are stored as their JSON encoding.
+func putAttributes(m pcommon.Map, attrs map[string]interface{}) {
+	for k, v := range attrs {
+		switch v := v.(type) {
+		case string:
+			m.PutStr(k, v)
+		case bool:
+			m.PutBool(k, v)
+		case json.Number:
+			if i, err := v.Int64(); err == nil {
+				m.PutInt(k, i)
+			} else if f, err := v.Float64(); err == nil {
+				m.PutDouble(k, f)
+			} else {
+				m.PutStr(k, v.String())
+			}
+		case nil:
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+			m.PutStr(k, string(b))
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 
+	"github.com/grafana/tempo/pkg/tlsauth"
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/log"
 )
@@ -80,3 +81,33 @@ func (m *multiTenancyMiddleware) Wrap(next consumer.Traces) consumer.Traces {
 		return next.ConsumeTraces(ctx, td)
 	})
 }
+
+type tlsTenantMiddleware struct {
+	auth     *tlsauth.Authenticator
+	fallback Middleware
+}
+
+// TLSTenantMiddleware uses the tenant of the TLS client certificate of gRPC requests. Requests without a
+// certificate that maps to a tenant are passed to fallback.
+func TLSTenantMiddleware(auth *tlsauth.Authenticator, fallback Middleware) Middleware {
+	return &tlsTenantMiddleware{
+		auth:     auth,
+		fallback: fallback,
+	}
+}
+
+func (m *tlsTenantMiddleware) Wrap(next consumer.Traces) consumer.Traces {
+	fallback := m.fallback.Wrap(next)
+
+	return ConsumeTracesFunc(func(ctx context.Context, td ptrace.Traces) error {
+		ctx, ok, err := m.auth.ExtractFromContext(ctx)
+		if err != nil {
+			log.Logger.Log("msg", "failed to extract org id from client certificate", "err", err)
+			return err
+		}
+		if !ok {
+			return fallback.ConsumeTraces(ctx, td)
+		}
+		return next.ConsumeTraces(ctx, td)
+	})
+}
@@ -2,6 +2,8 @@ package receiver
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"testing"
 
 	"github.com/grafana/dskit/user"
@@ -9,8 +11,11 @@ import (
 	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 
+	"github.com/grafana/tempo/pkg/tlsauth"
 	"github.com/grafana/tempo/pkg/util"
 )
 
@@ -97,3 +102,49 @@ func TestMultiTenancyMiddleware(t *testing.T) {
 		require.EqualError(t, m.Wrap(consumer).ConsumeTraces(ctx, ptrace.Traces{}), "no org id")
 	})
 }
+
+func TestTLSTenantMiddleware(t *testing.T) {
+	auth, err := tlsauth.New(tlsauth.Config{
+		Enabled:  true,
+		Mappings: []tlsauth.MappingConfig{{Regex: "(.*)\\.tenants\\.example\\.com"}},
+	})
+	require.NoError(t, err)
+
+	m := TLSTenantMiddleware(auth, MultiTenancyMiddleware())
+
+	cert := &x509.Certificate{DNSNames: []string{"foo.tenants.example.com"}}
+	certCtx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+		VerifiedChains:   [][]*x509.Certificate{{cert}},
+	}}})
+
+	t.Run("injects org id from certificate", func(t *testing.T) {
+		consumer := newAssertingConsumer(t, func(t *testing.T, ctx context.Context) {
+			orgID, err := user.ExtractOrgID(ctx)
+			require.NoError(t, err)
+			require.Equal(t, "foo", orgID)
+		})
+
+		require.NoError(t, m.Wrap(consumer).ConsumeTraces(certCtx, ptrace.Traces{}))
+	})
+
+	t.Run("rejects different org id", func(t *testing.T) {
+		consumer := newAssertingConsumer(t, func(t *testing.T, _ context.Context) {
+			require.Fail(t, "consumer should not be called")
+		})
+
+		ctx := metadata.NewIncomingContext(certCtx, metadata.Pairs("X-Scope-OrgID", "bar"))
+		require.Error(t, m.Wrap(consumer).ConsumeTraces(ctx, ptrace.Traces{}))
+	})
+
+	t.Run("falls back to header", func(t *testing.T) {
+		consumer := newAssertingConsumer(t, func(t *testing.T, ctx context.Context) {
+			orgID, err := user.ExtractOrgID(ctx)
+			require.NoError(t, err)
+			require.Equal(t, "bar", orgID)
+		})
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("X-Scope-OrgID", "bar"))
+		require.NoError(t, m.Wrap(consumer).ConsumeTraces(ctx, ptrace.Traces{}))
+	})
+}
@@ -0,0 +1,126 @@
+package otlpsocket
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+const (
+	TransportUnix = "unix"
+	TransportTCP  = "tcp"
+
+	defaultPermissions     = 0o660
+	defaultMaxMessageBytes = 4 << 20
+)
+
+// Config defines configuration for the OTLP socket receiver.
+type Config struct {
+	// GRPC configures the OTLP gRPC server. It is disabled if not set.
+	GRPC *GRPCConfig `mapstructure:"grpc"`
+	// HTTP configures the OTLP HTTP server. It is disabled if not set.
+	HTTP *ListenerConfig `mapstructure:"http"`
+}
+
+// ListenerConfig defines the listener of a server of the OTLP socket receiver.
+type ListenerConfig struct {
+	// Endpoint is the path of the socket, or the address to listen on if the transport is tcp.
+	Endpoint string `mapstructure:"endpoint"`
+	// Transport is either unix or tcp. Defaults to unix.
+	Transport string `mapstructure:"transport"`
+	// Permissions are the octal file permissions of the socket. Defaults to 0660.
+	Permissions string `mapstructure:"permissions"`
+	// MaxConnections is the maximum number of concurrent connections. Connections above the limit are closed
+	// right after they are accepted. 0 is unlimited. Only supported by the HTTP server.
+	MaxConnections int `mapstructure:"max_connections"`
+	// MaxMessageBytes is the maximum size of a request. Defaults to 4MiB.
+	MaxMessageBytes int `mapstructure:"max_message_bytes"`
+}
+
+// GRPCConfig defines the OTLP gRPC server of the OTLP socket receiver.
+type GRPCConfig struct {
+	ListenerConfig `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+	// MaxConcurrentStreams is the maximum number of concurrent streams of a connection. 0 uses the gRPC default.
+	MaxConcurrentStreams uint32 `mapstructure:"max_concurrent_streams"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks the receiver configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.GRPC == nil && cfg.HTTP == nil {
+		return errors.New("at least one of grpc or http must be enabled")
+	}
+
+	if cfg.GRPC != nil {
+		if err := cfg.GRPC.validate(); err != nil {
+			return fmt.Errorf("grpc: %w", err)
+		}
+		if cfg.GRPC.MaxConnections != 0 {
+			return errors.New("grpc: max_connections is not supported, use max_concurrent_streams")
+		}
+	}
+	if cfg.HTTP != nil {
+		if err := cfg.HTTP.validate(); err != nil {
+			return fmt.Errorf("http: %w", err)
+		}
+	}
+	if cfg.GRPC != nil && cfg.HTTP != nil && cfg.GRPC.Endpoint == cfg.HTTP.Endpoint {
+		return errors.New("grpc and http must use different endpoints")
+	}
+
+	return nil
+}
+
+func (cfg *ListenerConfig) validate() error {
+	if cfg.Endpoint == "" {
+		return errors.New("endpoint must be set")
+	}
+
+	switch cfg.transport() {
+	case TransportUnix, TransportTCP:
+	default:
+		return fmt.Errorf("unsupported transport %q", cfg.Transport)
+	}
+
+	if _, err := cfg.permissions(); err != nil {
+		return err
+	}
+	if cfg.MaxConnections < 0 {
+		return errors.New("max_connections must not be negative")
+	}
+	if cfg.MaxMessageBytes < 0 {
+		return errors.New("max_message_bytes must not be negative")
+	}
+
+	return nil
+}
+
+func (cfg *ListenerConfig) transport() string {
+	if cfg.Transport == "" {
+		return TransportUnix
+	}
+	return cfg.Transport
+}
+
+func (cfg *ListenerConfig) permissions() (os.FileMode, error) {
+	if cfg.Permissions == "" {
+		return defaultPermissions, nil
+	}
+
+	p, err := strconv.ParseUint(cfg.Permissions, 8, 32)
+	if err != nil || p > 0o777 {
+		return 0, fmt.Errorf("invalid permissions %q", cfg.Permissions)
+	}
+	return os.FileMode(p), nil
+}
+
+func (cfg *ListenerConfig) maxMessageBytes() int {
+	if cfg.MaxMessageBytes <= 0 {
+		return defaultMaxMessageBytes
+	}
+	return cfg.MaxMessageBytes
+}
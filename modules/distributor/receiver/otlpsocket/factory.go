@@ -0,0 +1,38 @@
+package otlpsocket
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+)
+
+// Type is the receiver type used in the receivers config block.
+var Type = component.MustNewType("otlpsocket")
+
+// NewFactory creates a new OTLP socket receiver factory
+func NewFactory() receiver.Factory {
+	return receiver.NewFactory(
+		Type,
+		createDefaultConfig,
+		receiver.WithTraces(createTracesReceiver, component.StabilityLevelAlpha),
+	)
+}
+
+// createDefaultConfig creates the default configuration for the OTLP socket receiver. Both servers are disabled
+// by default, their socket paths have to be configured.
+func createDefaultConfig() component.Config {
+	return &Config{}
+}
+
+// createTracesReceiver creates a trace receiver based on provided config.
+func createTracesReceiver(
+	_ context.Context,
+	set receiver.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Traces,
+) (receiver.Traces, error) {
+	rCfg := cfg.(*Config)
+	return newReceiver(rCfg, nextConsumer, set), nil
+}
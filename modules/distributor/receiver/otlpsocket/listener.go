@@ -0,0 +1,164 @@
+package otlpsocket
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricConnectionsActive = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "distributor_otlpsocket_connections_active",
+		Help:      "The number of open connections of the OTLP socket receiver.",
+	}, []string{"protocol"})
+	metricConnections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_otlpsocket_connections_total",
+		Help:      "The total number of connections accepted by the OTLP socket receiver.",
+	}, []string{"protocol"})
+	metricConnectionsRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_otlpsocket_connections_rejected_total",
+		Help:      "The total number of connections closed because the OTLP socket receiver was at max_connections.",
+	}, []string{"protocol"})
+	metricConnectionReceivedBytesRate = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tempo",
+		Name:      "distributor_otlpsocket_connection_received_bytes_per_second",
+		Help:      "The rate of bytes received over a connection of the OTLP socket receiver, observed when the connection is closed.",
+		Buckets:   prometheus.ExponentialBuckets(1024, 4, 10),
+	}, []string{"protocol"})
+)
+
+// listen creates the listener of a server. A stale socket of a previous process is removed first, the listener
+// removes the socket when it's closed.
+func listen(cfg *ListenerConfig) (net.Listener, error) {
+	if err := removeStaleSocket(cfg); err != nil {
+		return nil, err
+	}
+
+	l, err := net.Listen(cfg.transport(), cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := chmodSocket(cfg); err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// removeStaleSocket removes the socket of the endpoint if a previous process left it behind.
+func removeStaleSocket(cfg *ListenerConfig) error {
+	if cfg.transport() != TransportUnix {
+		return nil
+	}
+
+	if fi, err := os.Stat(cfg.Endpoint); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(cfg.Endpoint); err != nil {
+			return fmt.Errorf("failed to remove stale socket %s: %w", cfg.Endpoint, err)
+		}
+	}
+	return nil
+}
+
+// chmodSocket sets the permissions of the socket of the endpoint.
+func chmodSocket(cfg *ListenerConfig) error {
+	if cfg.transport() != TransportUnix {
+		return nil
+	}
+
+	perm, err := cfg.permissions()
+	if err == nil {
+		err = os.Chmod(cfg.Endpoint, perm)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set permissions of socket %s: %w", cfg.Endpoint, err)
+	}
+	return nil
+}
+
+// limitListener closes connections above maxConnections and tracks the connections it accepts.
+type limitListener struct {
+	net.Listener
+
+	protocol       string
+	maxConnections int
+	active         atomic.Int64
+}
+
+func newLimitListener(l net.Listener, protocol string, maxConnections int) *limitListener {
+	return &limitListener{
+		Listener:       l,
+		protocol:       protocol,
+		maxConnections: maxConnections,
+	}
+}
+
+// Accept implements net.Listener
+func (l *limitListener) Accept() (net.Conn, error) {
+	for {
+		c, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		// connections are only added here, so the limit can't be exceeded between the check and the add
+		if l.maxConnections > 0 && l.active.Load() >= int64(l.maxConnections) {
+			metricConnectionsRejected.WithLabelValues(l.protocol).Inc()
+			_ = c.Close()
+			continue
+		}
+
+		l.active.Add(1)
+		metricConnections.WithLabelValues(l.protocol).Inc()
+		metricConnectionsActive.WithLabelValues(l.protocol).Inc()
+
+		return &trackedConn{
+			Conn:     c,
+			listener: l,
+			opened:   time.Now(),
+		}, nil
+	}
+}
+
+// trackedConn counts the bytes received over the connection
+type trackedConn struct {
+	net.Conn
+
+	listener  *limitListener
+	opened    time.Time
+	received  atomic.Int64
+	closeOnce sync.Once
+}
+
+// Read implements net.Conn
+func (c *trackedConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.received.Add(int64(n))
+	return n, err
+}
+
+// Close implements net.Conn
+func (c *trackedConn) Close() error {
+	err := c.Conn.Close()
+
+	c.closeOnce.Do(func() {
+		c.listener.active.Add(-1)
+		metricConnectionsActive.WithLabelValues(c.listener.protocol).Dec()
+
+		if d := time.Since(c.opened).Seconds(); d > 0 {
+			metricConnectionReceivedBytesRate.WithLabelValues(c.listener.protocol).Observe(float64(c.received.Load()) / d)
+		}
+	})
+
+	return err
+}
@@ -0,0 +1,265 @@
+package otlpsocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/otlpreceiver"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	protocolHTTP = "http"
+
+	pathTraces = "/v1/traces"
+
+	contentTypeProtobuf = "application/x-protobuf"
+	contentTypeJSON     = "application/json"
+)
+
+// socketReceiver serves the OTLP gRPC and HTTP trace APIs on unix sockets or tcp listeners. The gRPC API is served
+// by the OTLP receiver, the HTTP API by a server with the middlewares of the OTLP receiver on a listener that limits
+// the number of concurrent connections.
+type socketReceiver struct {
+	config       *Config
+	nextConsumer consumer.Traces
+	settings     receiver.CreateSettings
+
+	grpcReceiver receiver.Traces
+	httpServer   *http.Server
+	shutdownWG   sync.WaitGroup
+}
+
+var (
+	_ receiver.Traces = (*socketReceiver)(nil)
+	_ http.Handler    = (*socketReceiver)(nil)
+)
+
+func newReceiver(config *Config, nextConsumer consumer.Traces, settings receiver.CreateSettings) *socketReceiver {
+	return &socketReceiver{
+		config:       config,
+		nextConsumer: nextConsumer,
+		settings:     settings,
+	}
+}
+
+// Start creates the listeners and starts the servers.
+func (r *socketReceiver) Start(ctx context.Context, host component.Host) error {
+	if host == nil {
+		return errors.New("nil host")
+	}
+
+	if r.config.GRPC != nil {
+		if err := r.startGRPC(ctx, host); err != nil {
+			return fmt.Errorf("failed to start grpc server: %w", err)
+		}
+	}
+
+	if r.config.HTTP != nil {
+		if err := r.startHTTP(ctx, host); err != nil {
+			if r.grpcReceiver != nil {
+				_ = r.grpcReceiver.Shutdown(ctx)
+			}
+			return fmt.Errorf("failed to start http server: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// startGRPC starts an OTLP receiver with only the gRPC protocol enabled, so requests pass the same limits and
+// interceptors as requests to the otlp receiver.
+func (r *socketReceiver) startGRPC(ctx context.Context, host component.Host) error {
+	cfg := r.config.GRPC
+
+	if err := removeStaleSocket(&cfg.ListenerConfig); err != nil {
+		return err
+	}
+
+	factory := otlpreceiver.NewFactory()
+	otlpCfg := factory.CreateDefaultConfig().(*otlpreceiver.Config)
+	otlpCfg.HTTP = nil
+	otlpCfg.GRPC = &configgrpc.ServerConfig{
+		NetAddr: confignet.AddrConfig{
+			Endpoint:  cfg.Endpoint,
+			Transport: confignet.TransportType(cfg.transport()),
+		},
+		// rounded up, the limit of the grpc server is set in MiB
+		MaxRecvMsgSizeMiB:    uint64((cfg.maxMessageBytes() + 1<<20 - 1) >> 20),
+		MaxConcurrentStreams: cfg.MaxConcurrentStreams,
+		ReadBufferSize:       otlpCfg.GRPC.ReadBufferSize,
+	}
+
+	rcv, err := factory.CreateTracesReceiver(ctx, r.settings, otlpCfg, r.nextConsumer)
+	if err != nil {
+		return err
+	}
+	if err := rcv.Start(ctx, host); err != nil {
+		return err
+	}
+	r.grpcReceiver = rcv
+
+	if err := chmodSocket(&cfg.ListenerConfig); err != nil {
+		_ = rcv.Shutdown(ctx)
+		r.grpcReceiver = nil
+		return err
+	}
+
+	return nil
+}
+
+func (r *socketReceiver) startHTTP(ctx context.Context, host component.Host) error {
+	cfg := r.config.HTTP
+
+	mux := http.NewServeMux()
+	mux.Handle(pathTraces, r)
+
+	// the server decompresses requests like the otlp receiver does
+	httpCfg := &confighttp.ServerConfig{
+		Endpoint:           cfg.Endpoint,
+		MaxRequestBodySize: int64(cfg.maxMessageBytes()),
+	}
+	server, err := httpCfg.ToServer(ctx, host, r.settings.TelemetrySettings, mux)
+	if err != nil {
+		return err
+	}
+
+	l, err := listen(cfg)
+	if err != nil {
+		return err
+	}
+
+	r.httpServer = server
+	// the tenant header is passed to the distributor in the client info, like the otlp receiver does
+	r.httpServer.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
+		return client.NewContext(ctx, client.Info{Addr: c.RemoteAddr()})
+	}
+
+	r.shutdownWG.Add(1)
+	go func() {
+		defer r.shutdownWG.Done()
+
+		if errHTTP := r.httpServer.Serve(newLimitListener(l, protocolHTTP, cfg.MaxConnections)); errHTTP != nil && !errors.Is(errHTTP, http.ErrServerClosed) {
+			r.settings.TelemetrySettings.ReportStatus(component.NewFatalErrorEvent(errHTTP))
+		}
+	}()
+
+	return nil
+}
+
+// Shutdown stops the servers. Closing the listeners removes the sockets.
+func (r *socketReceiver) Shutdown(ctx context.Context) error {
+	var errs []error
+
+	if r.grpcReceiver != nil {
+		errs = append(errs, r.grpcReceiver.Shutdown(ctx))
+	}
+	if r.httpServer != nil {
+		errs = append(errs, r.httpServer.Shutdown(ctx))
+	}
+
+	r.shutdownWG.Wait()
+	return errors.Join(errs...)
+}
+
+// ServeHTTP implements the OTLP HTTP trace API for protobuf and json encoded requests. Compressed requests are
+// decompressed by the middleware of the server.
+func (r *socketReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if contentType != contentTypeProtobuf && contentType != contentTypeJSON {
+		http.Error(w, fmt.Sprintf("unsupported content type %q", contentType), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, int64(r.config.HTTP.maxMessageBytes())))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, fmt.Sprintf("failed to read request: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	otlpReq := ptraceotlp.NewExportRequest()
+	if contentType == contentTypeJSON {
+		err = otlpReq.UnmarshalJSON(body)
+	} else {
+		err = otlpReq.UnmarshalProto(body)
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode request: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	info := client.FromContext(req.Context())
+	info.Metadata = client.NewMetadata(req.Header.Clone())
+	ctx := client.NewContext(req.Context(), info)
+
+	if td := otlpReq.Traces(); td.SpanCount() > 0 {
+		if err := r.nextConsumer.ConsumeTraces(ctx, td); err != nil {
+			r.settings.Logger.Debug("failed to consume otlp request", zap.Error(err))
+			http.Error(w, err.Error(), httpStatusFromError(err))
+			return
+		}
+	}
+
+	var resp []byte
+	if contentType == contentTypeJSON {
+		resp, err = ptraceotlp.NewExportResponse().MarshalJSON()
+	} else {
+		resp, err = ptraceotlp.NewExportResponse().MarshalProto()
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(resp)
+}
+
+// httpStatusFromError translates errors returned by the distributor into status codes. Codes the OTLP exporters
+// retry on are kept retryable.
+func httpStatusFromError(err error) int {
+	s, ok := status.FromError(err)
+	if !ok {
+		return http.StatusInternalServerError
+	}
+
+	switch s.Code() {
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,307 @@
+package otlpsocket
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/klauspost/compress/zstd"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tcs := []struct {
+		name   string
+		cfg    Config
+		expErr bool
+	}{
+		{
+			name:   "nothing enabled",
+			cfg:    Config{},
+			expErr: true,
+		},
+		{
+			name: "valid",
+			cfg: Config{
+				GRPC: &GRPCConfig{ListenerConfig: ListenerConfig{Endpoint: "/run/tempo/grpc.sock", Permissions: "0666"}},
+				HTTP: &ListenerConfig{Endpoint: "127.0.0.1:4318", Transport: TransportTCP, MaxConnections: 10},
+			},
+		},
+		{
+			name:   "missing endpoint",
+			cfg:    Config{HTTP: &ListenerConfig{}},
+			expErr: true,
+		},
+		{
+			name:   "unsupported transport",
+			cfg:    Config{HTTP: &ListenerConfig{Endpoint: "foo", Transport: "udp"}},
+			expErr: true,
+		},
+		{
+			name:   "invalid permissions",
+			cfg:    Config{HTTP: &ListenerConfig{Endpoint: "foo", Permissions: "rw"}},
+			expErr: true,
+		},
+		{
+			name:   "grpc max connections",
+			cfg:    Config{GRPC: &GRPCConfig{ListenerConfig: ListenerConfig{Endpoint: "foo", MaxConnections: 10}}},
+			expErr: true,
+		},
+		{
+			name: "same endpoint",
+			cfg: Config{
+				GRPC: &GRPCConfig{ListenerConfig: ListenerConfig{Endpoint: "/run/tempo/otlp.sock"}},
+				HTTP: &ListenerConfig{Endpoint: "/run/tempo/otlp.sock"},
+			},
+			expErr: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+// tenantSink records the spans and the tenants of the requests
+type tenantSink struct {
+	mtx     sync.Mutex
+	spans   int
+	tenants []string
+}
+
+func (s *tenantSink) consumer() consumer.Traces {
+	next, _ := consumer.NewTraces(func(ctx context.Context, td ptrace.Traces) error {
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+
+		s.spans += td.SpanCount()
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			s.tenants = append(s.tenants, md.Get(user.OrgIDHeaderName)...)
+		}
+		s.tenants = append(s.tenants, client.FromContext(ctx).Metadata.Get(user.OrgIDHeaderName)...)
+		return nil
+	})
+	return next
+}
+
+func testTraces() ptrace.Traces {
+	td := ptrace.NewTraces()
+	span := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("test")
+	span.SetTraceID([16]byte{1})
+	span.SetSpanID([8]byte{1})
+	return td
+}
+
+func TestReceiverUnixSockets(t *testing.T) {
+	dir := t.TempDir()
+	grpcSocket := filepath.Join(dir, "grpc.sock")
+	httpSocket := filepath.Join(dir, "http.sock")
+
+	// a socket left behind by a previous process is replaced
+	stale, err := net.Listen("unix", httpSocket)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	cfg := &Config{
+		GRPC: &GRPCConfig{ListenerConfig: ListenerConfig{Endpoint: grpcSocket}},
+		HTTP: &ListenerConfig{Endpoint: httpSocket, Permissions: "0600"},
+	}
+	require.NoError(t, cfg.Validate())
+
+	sink := &tenantSink{}
+	r := newReceiver(cfg, sink.consumer(), receivertest.NewNopCreateSettings())
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	fi, err := os.Stat(grpcSocket)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o660), fi.Mode().Perm())
+	fi, err = os.Stat(httpSocket)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+
+	// grpc
+	conn, err := grpc.NewClient("unix://"+grpcSocket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), user.OrgIDHeaderName, "grpc-tenant")
+	_, err = ptraceotlp.NewGRPCClient(conn).Export(ctx, ptraceotlp.NewExportRequestFromTraces(testTraces()))
+	require.NoError(t, err)
+
+	// http
+	httpClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", httpSocket)
+		},
+	}}
+	body, err := ptraceotlp.NewExportRequestFromTraces(testTraces()).MarshalProto()
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, "http://localhost/v1/traces", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentTypeProtobuf)
+	req.Header.Set(user.OrgIDHeaderName, "http-tenant")
+	resp, err := httpClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+
+	body, err = ptraceotlp.NewExportRequestFromTraces(testTraces()).MarshalJSON()
+	require.NoError(t, err)
+	resp, err = httpClient.Post("http://localhost/v1/traces", "application/json; charset=utf-8", bytes.NewReader(body))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+
+	httpClient.CloseIdleConnections()
+	require.NoError(t, conn.Close())
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	sink.mtx.Lock()
+	require.Equal(t, 3, sink.spans)
+	require.Equal(t, []string{"grpc-tenant", "http-tenant"}, sink.tenants)
+	sink.mtx.Unlock()
+
+	// the sockets are removed on shutdown
+	_, err = os.Stat(grpcSocket)
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(httpSocket)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestReceiverMaxConnections(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "http.sock")
+
+	cfg := &Config{
+		HTTP: &ListenerConfig{Endpoint: socket, MaxConnections: 1},
+	}
+	sink := &tenantSink{}
+	r := newReceiver(cfg, sink.consumer(), receivertest.NewNopCreateSettings())
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+	rejected := testutil.ToFloat64(metricConnectionsRejected.WithLabelValues(protocolHTTP))
+
+	body, err := ptraceotlp.NewExportRequestFromTraces(testTraces()).MarshalProto()
+	require.NoError(t, err)
+
+	// the first connection is kept open after its request
+	first, err := net.Dial("unix", socket)
+	require.NoError(t, err)
+	defer first.Close()
+
+	req, err := http.NewRequest(http.MethodPost, "http://localhost/v1/traces", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentTypeProtobuf)
+	require.NoError(t, req.Write(first))
+	resp, err := http.ReadResponse(bufio.NewReader(first), req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+
+	// the second connection is closed by the receiver
+	second, err := net.Dial("unix", socket)
+	require.NoError(t, err)
+	defer second.Close()
+
+	require.NoError(t, second.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = second.Read(make([]byte, 1))
+	require.Error(t, err)
+	require.False(t, os.IsTimeout(err))
+
+	require.Equal(t, rejected+1, testutil.ToFloat64(metricConnectionsRejected.WithLabelValues(protocolHTTP)))
+	require.Equal(t, 1, sink.spans)
+}
+
+func TestReceiverCompressedRequests(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "http.sock")
+
+	cfg := &Config{
+		HTTP: &ListenerConfig{Endpoint: socket},
+	}
+	sink := &tenantSink{}
+	r := newReceiver(cfg, sink.consumer(), receivertest.NewNopCreateSettings())
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+	httpClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	defer httpClient.CloseIdleConnections()
+
+	body, err := ptraceotlp.NewExportRequestFromTraces(testTraces()).MarshalProto()
+	require.NoError(t, err)
+
+	gzipped := &bytes.Buffer{}
+	gw := gzip.NewWriter(gzipped)
+	_, err = gw.Write(body)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	zw, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	zstded := zw.EncodeAll(body, nil)
+	require.NoError(t, zw.Close())
+
+	tcs := []struct {
+		encoding  string
+		body      []byte
+		expStatus int
+	}{
+		{encoding: "gzip", body: gzipped.Bytes(), expStatus: http.StatusOK},
+		{encoding: "zstd", body: zstded, expStatus: http.StatusOK},
+		{encoding: "br", body: body, expStatus: http.StatusBadRequest},
+	}
+
+	spans := 0
+	for _, tc := range tcs {
+		t.Run(tc.encoding, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "http://localhost/v1/traces", bytes.NewReader(tc.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", contentTypeProtobuf)
+			req.Header.Set("Content-Encoding", tc.encoding)
+
+			resp, err := httpClient.Do(req)
+			require.NoError(t, err)
+			require.Equal(t, tc.expStatus, resp.StatusCode)
+			require.NoError(t, resp.Body.Close())
+
+			if tc.expStatus == http.StatusOK {
+				spans++
+			}
+			sink.mtx.Lock()
+			require.Equal(t, spans, sink.spans)
+			sink.mtx.Unlock()
+		})
+	}
+}
@@ -38,6 +38,9 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/durationpb"
 
+	"github.com/grafana/tempo/modules/distributor/receiver/awsfirehose"
+	"github.com/grafana/tempo/modules/distributor/receiver/jsonbridge"
+	"github.com/grafana/tempo/modules/distributor/receiver/otlpsocket"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/usagestats"
 	"github.com/grafana/tempo/pkg/util/log"
@@ -60,6 +63,9 @@ var (
 	statReceiverZipkin     = usagestats.NewInt("receiver_enabled_zipkin")
 	statReceiverOpencensus = usagestats.NewInt("receiver_enabled_opencensus")
 	statReceiverKafka      = usagestats.NewInt("receiver_enabled_kafka")
+	statReceiverFirehose   = usagestats.NewInt("receiver_enabled_awsfirehose")
+	statReceiverJSONBridge = usagestats.NewInt("receiver_enabled_jsonbridge")
+	statReceiverOtlpSocket = usagestats.NewInt("receiver_enabled_otlpsocket")
 )
 
 type RetryableError struct {
@@ -93,6 +99,13 @@ func wrapErrorIfRetryable(err error, dur *durationpb.Duration) error {
 		return err
 	}
 
+	for _, detail := range s.Details() {
+		if _, ok := detail.(*errdetails.RetryInfo); ok {
+			// the error already tells the client when to retry
+			return err
+		}
+	}
+
 	// ignore error. code only errors if Code() == ok
 	s, _ = s.WithDetails(&errdetails.RetryInfo{
 		RetryDelay: dur,
@@ -166,6 +179,9 @@ func New(receiverCfg map[string]interface{}, pusher TracesPusher, middleware Mid
 		opencensusreceiver.NewFactory(),
 		otlpreceiver.NewFactory(),
 		kafkareceiver.NewFactory(),
+		awsfirehose.NewFactory(),
+		jsonbridge.NewFactory(),
+		otlpsocket.NewFactory(),
 	)
 	if err != nil {
 		return nil, err
@@ -183,6 +199,12 @@ func New(receiverCfg map[string]interface{}, pusher TracesPusher, middleware Mid
 			statReceiverOpencensus.Set(1)
 		case "kafka":
 			statReceiverKafka.Set(1)
+		case "awsfirehose":
+			statReceiverFirehose.Set(1)
+		case "jsonbridge":
+			statReceiverJSONBridge.Set(1)
+		case "otlpsocket":
+			statReceiverOtlpSocket.Set(1)
 		}
 	}
 
@@ -278,6 +300,18 @@ func New(receiverCfg map[string]interface{}, pusher TracesPusher, middleware Mid
 			}
 
 			cfg = jaegerRecvCfg
+
+		case "awsfirehose":
+			firehoseRecvCfg := cfg.(*awsfirehose.Config)
+
+			firehoseRecvCfg.ServerConfig.IncludeMetadata = true
+			cfg = firehoseRecvCfg
+
+		case "jsonbridge":
+			bridgeRecvCfg := cfg.(*jsonbridge.Config)
+
+			bridgeRecvCfg.HTTP.IncludeMetadata = true
+			cfg = bridgeRecvCfg
 		}
 
 		receiver, err := factoryBase.CreateTracesReceiver(ctx, params, cfg, middleware.Wrap(shim))
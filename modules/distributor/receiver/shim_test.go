@@ -37,6 +37,14 @@ func TestWrapRetryableError(t *testing.T) {
 	wrapped = wrapErrorIfRetryable(err, durationpb.New(time.Second))
 	require.NotEqual(t, err, wrapped)
 	require.True(t, isRetryable(wrapped))
+
+	// no wrapping b/c the error already has retry info
+	st, err := status.New(codes.ResourceExhausted, "res exhausted").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(5 * time.Second)})
+	require.NoError(t, err)
+	err = st.Err()
+	wrapped = wrapErrorIfRetryable(err, durationpb.New(time.Second))
+	require.Equal(t, err, wrapped)
+	require.True(t, isRetryable(wrapped))
 }
 
 func isRetryable(err error) bool {
@@ -0,0 +1,29 @@
+package usage
+
+import (
+	"flag"
+	"time"
+
+	"github.com/grafana/tempo/pkg/util"
+)
+
+const (
+	defaultMaxCardinality = uint64(10000)
+	defaultStaleDuration  = 15 * time.Minute
+)
+
+type Config struct {
+	CostAttribution PerTrackerConfig `yaml:"cost_attribution,omitempty"`
+}
+
+type PerTrackerConfig struct {
+	Enabled        bool          `yaml:"enabled,omitempty"`
+	MaxCardinality uint64        `yaml:"max_cardinality,omitempty"`
+	StaleDuration  time.Duration `yaml:"stale_duration,omitempty"`
+}
+
+func (c *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&c.CostAttribution.Enabled, util.PrefixConfig(prefix, "cost-attribution.enabled"), false, "Enable the cost attribution usage tracker.")
+	f.Uint64Var(&c.CostAttribution.MaxCardinality, util.PrefixConfig(prefix, "cost-attribution.max-cardinality"), defaultMaxCardinality, "Maximum number of series per tenant. Once reached, all new combinations of dimensions are tracked in a single overflow series.")
+	f.DurationVar(&c.CostAttribution.StaleDuration, util.PrefixConfig(prefix, "cost-attribution.stale-duration"), defaultStaleDuration, "Series that have not been updated for this long are removed.")
+}
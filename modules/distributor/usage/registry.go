@@ -0,0 +1,46 @@
+package usage
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Handler returns a http.Handler that exposes the usage metrics of the registry in OpenMetrics format.
+func Handler(g prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(g, promhttp.HandlerOpts{
+		EnableOpenMetrics: true,
+	})
+}
+
+// TenantGaugeFunc returns the current value per tenant.
+type TenantGaugeFunc func() map[string]float64
+
+// TenantGauge exposes a value per tenant that is owned by another module, like the number of live traces
+// of the ingester or the number of blocks in the backend. The values are read on every scrape.
+type TenantGauge struct {
+	desc   *prometheus.Desc
+	values TenantGaugeFunc
+}
+
+var _ prometheus.Collector = (*TenantGauge)(nil)
+
+func NewTenantGauge(name, help string, values TenantGaugeFunc) *TenantGauge {
+	return &TenantGauge{
+		desc:   prometheus.NewDesc(name, help, []string{tenantLabel}, nil),
+		values: values,
+	}
+}
+
+// Describe implements prometheus.Collector
+func (g *TenantGauge) Describe(ch chan<- *prometheus.Desc) {
+	ch <- g.desc
+}
+
+// Collect implements prometheus.Collector
+func (g *TenantGauge) Collect(ch chan<- prometheus.Metric) {
+	for tenant, v := range g.values() {
+		ch <- prometheus.MustNewConstMetric(g.desc, prometheus.GaugeValue, v, tenant)
+	}
+}
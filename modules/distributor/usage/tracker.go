@@ -0,0 +1,299 @@
+package usage
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+const (
+	tenantLabel   = "tenant"
+	overflowValue = "__overflow__"
+	missingValue  = "__missing__"
+
+	scopeResource = "resource."
+	scopeSpan     = "span."
+)
+
+// DimensionsFunc returns the per-tenant mapping of attribute name to label name.
+type DimensionsFunc func(tenant string) map[string]string
+
+// Tracker counts the spans and bytes received per tenant, broken down by the configured attributes of
+// the spans. It is registered with the usage registry so billing scrapers don't need to scrape the full
+// /metrics endpoint.
+type Tracker struct {
+	cfg        PerTrackerConfig
+	dimensions DimensionsFunc
+
+	mtx     sync.Mutex
+	tenants map[string]*tenantUsage
+}
+
+type tenantUsage struct {
+	// dimensions of the tenant sorted by label name, they are reset when the overrides change
+	dimensions []dimension
+	series     map[uint64]*usageSeries
+	overflow   *usageSeries
+}
+
+type dimension struct {
+	attribute string
+	scope     string // "", resource. or span.
+	label     string
+}
+
+type usageSeries struct {
+	values     []string
+	spans      float64
+	bytes      float64
+	lastUpdate time.Time
+}
+
+var (
+	_ prometheus.Collector = (*Tracker)(nil)
+
+	spansDescHelp = "The total number of spans received per tenant and cost attribution dimensions."
+	bytesDescHelp = "The total number of bytes received per tenant and cost attribution dimensions. Bytes of a batch are split between its spans."
+)
+
+func NewTracker(cfg PerTrackerConfig, dimensions DimensionsFunc) *Tracker {
+	if cfg.MaxCardinality == 0 {
+		cfg.MaxCardinality = defaultMaxCardinality
+	}
+	if cfg.StaleDuration == 0 {
+		cfg.StaleDuration = defaultStaleDuration
+	}
+
+	return &Tracker{
+		cfg:        cfg,
+		dimensions: dimensions,
+		tenants:    map[string]*tenantUsage{},
+	}
+}
+
+// Observe records the spans and bytes of the batches for the tenant.
+func (t *Tracker) Observe(tenant string, batches []*v1.ResourceSpans) {
+	dims := parseDimensions(t.dimensions(tenant))
+	now := time.Now()
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	u := t.getOrCreateTenant(tenant, dims)
+	values := make([]string, len(dims))
+
+	for _, b := range batches {
+		spanCount := 0
+		for _, ss := range b.ScopeSpans {
+			spanCount += len(ss.Spans)
+		}
+		if spanCount == 0 {
+			continue
+		}
+		bytesPerSpan := float64(b.Size()) / float64(spanCount)
+
+		for _, ss := range b.ScopeSpans {
+			for _, s := range ss.Spans {
+				for i, d := range dims {
+					values[i] = d.value(b.Resource.GetAttributes(), s.Attributes)
+				}
+
+				series := t.getOrCreateSeries(u, values)
+				series.spans++
+				series.bytes += bytesPerSpan
+				series.lastUpdate = now
+			}
+		}
+	}
+}
+
+// Describe implements prometheus.Collector. The tracker is an unchecked collector because the labels
+// depend on the dimensions configured for each tenant.
+func (t *Tracker) Describe(chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector
+func (t *Tracker) Collect(ch chan<- prometheus.Metric) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.purgeStale(time.Now().Add(-t.cfg.StaleDuration))
+
+	for tenant, u := range t.tenants {
+		labels := make([]string, 0, len(u.dimensions)+1)
+		labels = append(labels, tenantLabel)
+		for _, d := range u.dimensions {
+			labels = append(labels, d.label)
+		}
+
+		spansDesc := prometheus.NewDesc("tempo_usage_tracker_spans_received_total", spansDescHelp, labels, nil)
+		bytesDesc := prometheus.NewDesc("tempo_usage_tracker_bytes_received_total", bytesDescHelp, labels, nil)
+
+		collect := func(s *usageSeries) {
+			values := append([]string{tenant}, s.values...)
+			ch <- prometheus.MustNewConstMetric(spansDesc, prometheus.CounterValue, s.spans, values...)
+			ch <- prometheus.MustNewConstMetric(bytesDesc, prometheus.CounterValue, s.bytes, values...)
+		}
+
+		for _, s := range u.series {
+			collect(s)
+		}
+		if u.overflow != nil {
+			collect(u.overflow)
+		}
+	}
+}
+
+func (t *Tracker) getOrCreateTenant(tenant string, dims []dimension) *tenantUsage {
+	u, ok := t.tenants[tenant]
+	if ok && dimensionsEqual(u.dimensions, dims) {
+		return u
+	}
+
+	// new tenant or the dimensions changed. existing series can't be reused
+	u = &tenantUsage{
+		dimensions: dims,
+		series:     map[uint64]*usageSeries{},
+	}
+	t.tenants[tenant] = u
+	return u
+}
+
+func (t *Tracker) getOrCreateSeries(u *tenantUsage, values []string) *usageSeries {
+	h := hashValues(values)
+	if s, ok := u.series[h]; ok {
+		return s
+	}
+
+	if uint64(len(u.series)) >= t.cfg.MaxCardinality {
+		if u.overflow == nil {
+			overflowValues := make([]string, len(values))
+			for i := range overflowValues {
+				overflowValues[i] = overflowValue
+			}
+			u.overflow = &usageSeries{values: overflowValues}
+		}
+		return u.overflow
+	}
+
+	s := &usageSeries{values: append([]string(nil), values...)}
+	u.series[h] = s
+	return s
+}
+
+func (t *Tracker) purgeStale(before time.Time) {
+	for tenant, u := range t.tenants {
+		for h, s := range u.series {
+			if s.lastUpdate.Before(before) {
+				delete(u.series, h)
+			}
+		}
+		if u.overflow != nil && u.overflow.lastUpdate.Before(before) {
+			u.overflow = nil
+		}
+		if len(u.series) == 0 && u.overflow == nil {
+			delete(t.tenants, tenant)
+		}
+	}
+}
+
+// parseDimensions converts the attribute -> label mapping into a list sorted by label name. If no label
+// name is given, the attribute name is sanitized and used instead.
+func parseDimensions(m map[string]string) []dimension {
+	dims := make([]dimension, 0, len(m))
+	for attr, label := range m {
+		d := dimension{attribute: attr}
+
+		switch {
+		case strings.HasPrefix(attr, scopeResource):
+			d.scope = scopeResource
+			d.attribute = strings.TrimPrefix(attr, scopeResource)
+		case strings.HasPrefix(attr, scopeSpan):
+			d.scope = scopeSpan
+			d.attribute = strings.TrimPrefix(attr, scopeSpan)
+		}
+
+		if label == "" {
+			label = d.attribute
+		}
+		d.label = sanitizeLabelName(label)
+		if d.label == tenantLabel {
+			d.label = "_" + d.label
+		}
+
+		dims = append(dims, d)
+	}
+
+	sort.Slice(dims, func(i, j int) bool {
+		return dims[i].label < dims[j].label
+	})
+	return dims
+}
+
+// value returns the value of the dimension. Span attributes take precedence over resource attributes
+// unless the dimension is scoped.
+func (d dimension) value(resourceAttrs, spanAttrs []*common_v1.KeyValue) string {
+	if d.scope != scopeResource {
+		if v, ok := findAttribute(spanAttrs, d.attribute); ok {
+			return v
+		}
+	}
+	if d.scope != scopeSpan {
+		if v, ok := findAttribute(resourceAttrs, d.attribute); ok {
+			return v
+		}
+	}
+	return missingValue
+}
+
+func findAttribute(attrs []*common_v1.KeyValue, key string) (string, bool) {
+	for _, a := range attrs {
+		if a.Key == key {
+			return util.StringifyAnyValue(a.Value), true
+		}
+	}
+	return "", false
+}
+
+func dimensionsEqual(a, b []dimension) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func hashValues(values []string) uint64 {
+	h := xxhash.New()
+	for _, v := range values {
+		_, _ = h.WriteString(v)
+		_, _ = h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+func sanitizeLabelName(s string) string {
+	if model.LabelName(s).IsValid() {
+		return s
+	}
+
+	b := []byte(s)
+	for i, c := range b {
+		if !((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_' || (c >= '0' && c <= '9' && i > 0)) {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,183 @@
+package usage
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestTrackerObserve(t *testing.T) {
+	dims := map[string]string{
+		"resource.service.name": "service",
+		"team":                  "",
+	}
+	tracker, reg := newTestTracker(t, PerTrackerConfig{Enabled: true}, func(string) map[string]string { return dims })
+
+	batches := []*v1.ResourceSpans{
+		makeBatch("svc-a", map[string]string{"team": "tracing"}, 2),
+		makeBatch("svc-a", map[string]string{"team": "tracing"}, 1),
+		makeBatch("svc-b", nil, 1),
+	}
+	tracker.Observe("test", batches)
+
+	bytesA := float64(batches[0].Size() + batches[1].Size())
+	bytesB := float64(batches[2].Size())
+
+	expected := `
+# HELP tempo_usage_tracker_spans_received_total The total number of spans received per tenant and cost attribution dimensions.
+# TYPE tempo_usage_tracker_spans_received_total counter
+tempo_usage_tracker_spans_received_total{service="svc-a",team="tracing",tenant="test"} 3
+tempo_usage_tracker_spans_received_total{service="svc-b",team="__missing__",tenant="test"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "tempo_usage_tracker_spans_received_total"))
+
+	require.Equal(t, bytesA, tracker.tenants["test"].series[hashValues([]string{"svc-a", "tracing"})].bytes)
+	require.Equal(t, bytesB, tracker.tenants["test"].series[hashValues([]string{"svc-b", missingValue})].bytes)
+}
+
+func TestTrackerMaxCardinality(t *testing.T) {
+	tracker, reg := newTestTracker(t, PerTrackerConfig{Enabled: true, MaxCardinality: 2}, func(string) map[string]string {
+		return map[string]string{"service.name": "service"}
+	})
+
+	tracker.Observe("test", []*v1.ResourceSpans{
+		makeBatch("svc-a", nil, 1),
+		makeBatch("svc-b", nil, 1),
+		makeBatch("svc-c", nil, 1),
+		makeBatch("svc-d", nil, 2),
+	})
+
+	expected := `
+# HELP tempo_usage_tracker_spans_received_total The total number of spans received per tenant and cost attribution dimensions.
+# TYPE tempo_usage_tracker_spans_received_total counter
+tempo_usage_tracker_spans_received_total{service="__overflow__",tenant="test"} 3
+tempo_usage_tracker_spans_received_total{service="svc-a",tenant="test"} 1
+tempo_usage_tracker_spans_received_total{service="svc-b",tenant="test"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "tempo_usage_tracker_spans_received_total"))
+}
+
+func TestTrackerDimensionsChange(t *testing.T) {
+	dims := map[string]string{"service.name": "service"}
+	tracker, reg := newTestTracker(t, PerTrackerConfig{Enabled: true}, func(string) map[string]string { return dims })
+
+	tracker.Observe("test", []*v1.ResourceSpans{makeBatch("svc-a", nil, 1)})
+
+	// series are reset when the dimensions of the tenant change
+	dims = nil
+	tracker.Observe("test", []*v1.ResourceSpans{makeBatch("svc-a", nil, 2)})
+
+	expected := `
+# HELP tempo_usage_tracker_spans_received_total The total number of spans received per tenant and cost attribution dimensions.
+# TYPE tempo_usage_tracker_spans_received_total counter
+tempo_usage_tracker_spans_received_total{tenant="test"} 2
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "tempo_usage_tracker_spans_received_total"))
+}
+
+func TestTrackerPurgeStale(t *testing.T) {
+	tracker := NewTracker(PerTrackerConfig{Enabled: true, StaleDuration: time.Minute}, func(string) map[string]string { return nil })
+
+	tracker.Observe("test", []*v1.ResourceSpans{makeBatch("svc-a", nil, 1)})
+	require.Len(t, tracker.tenants, 1)
+
+	tracker.purgeStale(time.Now().Add(time.Minute))
+	require.Len(t, tracker.tenants, 0)
+}
+
+func TestTrackerHandler(t *testing.T) {
+	tracker, reg := newTestTracker(t, PerTrackerConfig{Enabled: true}, func(string) map[string]string { return nil })
+
+	tracker.Observe("test", []*v1.ResourceSpans{makeBatch("svc-a", nil, 1)})
+
+	req := httptest.NewRequest(http.MethodGet, "/usage_metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text")
+	rec := httptest.NewRecorder()
+	Handler(reg).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Header().Get("Content-Type"), "application/openmetrics-text")
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), `tempo_usage_tracker_spans_received_total{tenant="test"} 1`)
+	require.True(t, strings.HasSuffix(string(body), "# EOF\n"))
+}
+
+func TestTenantGauge(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(NewTenantGauge("tempo_usage_tracker_live_traces", "The number of live traces per tenant.", func() map[string]float64 {
+		return map[string]float64{"a": 3, "b": 1}
+	}))
+
+	expected := `
+# HELP tempo_usage_tracker_live_traces The number of live traces per tenant.
+# TYPE tempo_usage_tracker_live_traces gauge
+tempo_usage_tracker_live_traces{tenant="a"} 3
+tempo_usage_tracker_live_traces{tenant="b"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "tempo_usage_tracker_live_traces"))
+}
+
+func TestParseDimensions(t *testing.T) {
+	dims := parseDimensions(map[string]string{
+		"span.http.method":  "",
+		"resource.k8s.team": "team",
+		"tenant":            "",
+	})
+
+	require.Equal(t, []dimension{
+		{attribute: "tenant", label: "_tenant"},
+		{attribute: "http.method", scope: scopeSpan, label: "http_method"},
+		{attribute: "k8s.team", scope: scopeResource, label: "team"},
+	}, dims)
+}
+
+func makeBatch(service string, spanAttrs map[string]string, spans int) *v1.ResourceSpans {
+	attrs := make([]*v1_common.KeyValue, 0, len(spanAttrs))
+	for k, v := range spanAttrs {
+		attrs = append(attrs, stringKV(k, v))
+	}
+
+	ss := &v1.ScopeSpans{}
+	for i := 0; i < spans; i++ {
+		ss.Spans = append(ss.Spans, &v1.Span{
+			TraceId:    []byte{1, 2, 3},
+			SpanId:     []byte{byte(i)},
+			Name:       "test",
+			Attributes: attrs,
+		})
+	}
+
+	return &v1.ResourceSpans{
+		Resource: &v1_resource.Resource{
+			Attributes: []*v1_common.KeyValue{stringKV("service.name", service)},
+		},
+		ScopeSpans: []*v1.ScopeSpans{ss},
+	}
+}
+
+func stringKV(k, v string) *v1_common.KeyValue {
+	return &v1_common.KeyValue{
+		Key:   k,
+		Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: v}},
+	}
+}
+
+func newTestTracker(t *testing.T, cfg PerTrackerConfig, dimensions DimensionsFunc) (*Tracker, *prometheus.Registry) {
+	tracker := NewTracker(cfg, dimensions)
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(tracker))
+	return tracker, reg
+}
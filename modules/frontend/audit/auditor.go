@@ -0,0 +1,121 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc/metadata"
+)
+
+var metricRecords = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "query_frontend_audit_records_total",
+	Help:      "Total number of query audit records by result (written, failed or dropped).",
+}, []string{"result"})
+
+// Auditor buffers audit records and writes them to the configured sink in the background so that
+// a slow sink never delays a query. A nil Auditor discards all records.
+type Auditor struct {
+	sink       Sink
+	userHeader string
+	logger     log.Logger
+
+	records chan Record
+	wg      sync.WaitGroup
+}
+
+func New(cfg Config, logger log.Logger) (*Auditor, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	sink, err := newSink(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAuditor(cfg, sink, logger), nil
+}
+
+func newAuditor(cfg Config, sink Sink, logger log.Logger) *Auditor {
+	a := &Auditor{
+		sink:       sink,
+		userHeader: cfg.UserHeader,
+		logger:     logger,
+		records:    make(chan Record, cfg.QueueSize),
+	}
+
+	a.wg.Add(1)
+	go a.run()
+
+	return a
+}
+
+// Record queues a record to be written. If the queue is full the record is dropped.
+func (a *Auditor) Record(r Record) {
+	if a == nil {
+		return
+	}
+
+	select {
+	case a.records <- r:
+	default:
+		metricRecords.WithLabelValues("dropped").Inc()
+	}
+}
+
+// UserFromHeader returns the user identified by the configured header of an HTTP request.
+func (a *Auditor) UserFromHeader(h http.Header) string {
+	if a == nil || a.userHeader == "" {
+		return ""
+	}
+	return h.Get(a.userHeader)
+}
+
+// UserFromContext returns the user identified by the configured header of a gRPC request.
+func (a *Auditor) UserFromContext(ctx context.Context) string {
+	if a == nil || a.userHeader == "" {
+		return ""
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if v := md.Get(strings.ToLower(a.userHeader)); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// Stop writes all queued records and closes the sink.
+func (a *Auditor) Stop() {
+	if a == nil {
+		return
+	}
+
+	close(a.records)
+	a.wg.Wait()
+
+	if err := a.sink.Close(); err != nil {
+		level.Warn(a.logger).Log("msg", "failed to close audit sink", "err", err)
+	}
+}
+
+func (a *Auditor) run() {
+	defer a.wg.Done()
+
+	for r := range a.records {
+		if err := a.sink.Write(context.Background(), r); err != nil {
+			metricRecords.WithLabelValues("failed").Inc()
+			level.Warn(a.logger).Log("msg", "failed to write query audit record", "tenant", r.Tenant, "err", err)
+			continue
+		}
+		metricRecords.WithLabelValues("written").Inc()
+	}
+}
@@ -0,0 +1,165 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+type mockSink struct {
+	mtx     sync.Mutex
+	records []Record
+	block   chan struct{}
+	closed  bool
+}
+
+func (s *mockSink) Write(_ context.Context, r Record) error {
+	if s.block != nil {
+		<-s.block
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.records = append(s.records, r)
+	return nil
+}
+
+func (s *mockSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestAuditorWritesRecords(t *testing.T) {
+	sink := &mockSink{}
+	a := newAuditor(Config{QueueSize: 10}, sink, log.NewNopLogger())
+
+	a.Record(Record{Tenant: "a", Query: "{}"})
+	a.Record(Record{Tenant: "b", Query: "{ .foo = `bar` }"})
+	a.Stop()
+
+	require.True(t, sink.closed)
+	require.Equal(t, []Record{
+		{Tenant: "a", Query: "{}"},
+		{Tenant: "b", Query: "{ .foo = `bar` }"},
+	}, sink.records)
+}
+
+func TestAuditorDropsWhenFull(t *testing.T) {
+	sink := &mockSink{block: make(chan struct{})}
+	a := newAuditor(Config{QueueSize: 1}, sink, log.NewNopLogger())
+
+	// the first record is picked up by the worker and blocks in the sink, the second fills the
+	// queue and the rest are dropped
+	a.Record(Record{Tenant: "1"})
+	require.Eventually(t, func() bool { return len(a.records) == 0 }, time.Second, time.Millisecond)
+	for i := 0; i < 5; i++ {
+		a.Record(Record{Tenant: "2"})
+	}
+
+	close(sink.block)
+	a.Stop()
+
+	require.Len(t, sink.records, 2)
+}
+
+func TestNilAuditor(t *testing.T) {
+	var a *Auditor
+
+	a.Record(Record{Tenant: "a"})
+	require.Equal(t, "", a.UserFromHeader(http.Header{"X-Grafana-User": []string{"bob"}}))
+	require.Equal(t, "", a.UserFromContext(context.Background()))
+	a.Stop()
+}
+
+func TestAuditorUser(t *testing.T) {
+	a := newAuditor(Config{QueueSize: 1, UserHeader: "X-Grafana-User"}, &mockSink{}, log.NewNopLogger())
+	defer a.Stop()
+
+	require.Equal(t, "bob", a.UserFromHeader(http.Header{"X-Grafana-User": []string{"bob"}}))
+	require.Equal(t, "", a.UserFromHeader(http.Header{}))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-grafana-user", "alice"))
+	require.Equal(t, "alice", a.UserFromContext(ctx))
+	require.Equal(t, "", a.UserFromContext(context.Background()))
+}
+
+func TestNewRecord(t *testing.T) {
+	start := time.Now()
+
+	r := NewRecord("tenant", "bob", "search", start, &http.Response{StatusCode: http.StatusOK}, 100, nil)
+	require.Equal(t, StatusSuccess, r.Status)
+	require.Equal(t, http.StatusOK, r.StatusCode)
+	require.Equal(t, uint64(100), r.InspectedBytes)
+	require.Equal(t, "bob", r.User)
+
+	r = NewRecord("tenant", "", "search", start, &http.Response{StatusCode: http.StatusTooManyRequests}, 0, nil)
+	require.Equal(t, StatusError, r.Status)
+
+	r = NewRecord("tenant", "", "search", start, nil, 0, errors.New("boom"))
+	require.Equal(t, StatusError, r.Status)
+	require.Equal(t, "boom", r.Error)
+	require.Equal(t, 0, r.StatusCode)
+}
+
+func TestHTTPSink(t *testing.T) {
+	received := make(chan Record, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := Record{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&rec))
+		received <- rec
+	}))
+	defer srv.Close()
+
+	sink, err := newSink(Config{Sink: SinkHTTP, HTTP: HTTPConfig{Endpoint: srv.URL, Timeout: time.Second}}, log.NewNopLogger())
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Write(context.Background(), Record{Tenant: "a", Query: "{}", Status: StatusSuccess}))
+	require.Equal(t, Record{Tenant: "a", Query: "{}", Status: StatusSuccess}, <-received)
+	require.NoError(t, sink.Close())
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	sink, err = newSink(Config{Sink: SinkHTTP, HTTP: HTTPConfig{Endpoint: failing.URL, Timeout: time.Second}}, log.NewNopLogger())
+	require.NoError(t, err)
+	require.Error(t, sink.Write(context.Background(), Record{Tenant: "a"}))
+}
+
+func TestConfigValidate(t *testing.T) {
+	tcs := []struct {
+		name string
+		cfg  Config
+		err  bool
+	}{
+		{name: "disabled", cfg: Config{Sink: "nope"}},
+		{name: "log", cfg: Config{Enabled: true, Sink: SinkLog, QueueSize: 1}},
+		{name: "unknown sink", cfg: Config{Enabled: true, Sink: "nope", QueueSize: 1}, err: true},
+		{name: "no queue", cfg: Config{Enabled: true, Sink: SinkLog}, err: true},
+		{name: "http without endpoint", cfg: Config{Enabled: true, Sink: SinkHTTP, QueueSize: 1}, err: true},
+		{name: "http", cfg: Config{Enabled: true, Sink: SinkHTTP, QueueSize: 1, HTTP: HTTPConfig{Endpoint: "http://audit"}}},
+		{name: "kafka without topic", cfg: Config{Enabled: true, Sink: SinkKafka, QueueSize: 1, Kafka: KafkaConfig{Brokers: []string{"kafka:9092"}}}, err: true},
+		{name: "kafka", cfg: Config{Enabled: true, Sink: SinkKafka, QueueSize: 1, Kafka: KafkaConfig{Brokers: []string{"kafka:9092"}, Topic: "audit"}}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+
+	"github.com/grafana/tempo/pkg/util"
+)
+
+const (
+	SinkLog   = "log"
+	SinkHTTP  = "http"
+	SinkKafka = "kafka"
+
+	defaultUserHeader  = "X-Grafana-User"
+	defaultQueueSize   = 1000
+	defaultHTTPTimeout = 5 * time.Second
+)
+
+type Config struct {
+	Enabled bool `yaml:"enabled"`
+	// Sink is where audit records are written. One of log, http or kafka.
+	Sink string `yaml:"sink"`
+	// UserHeader is the request header that identifies the user issuing the query.
+	UserHeader string `yaml:"user_header"`
+	// QueueSize is the number of records buffered before new records are dropped.
+	QueueSize int `yaml:"queue_size"`
+
+	HTTP  HTTPConfig  `yaml:"http"`
+	Kafka KafkaConfig `yaml:"kafka"`
+}
+
+type HTTPConfig struct {
+	Endpoint string        `yaml:"endpoint"`
+	Timeout  time.Duration `yaml:"timeout"`
+}
+
+type KafkaConfig struct {
+	Brokers flagext.StringSliceCSV `yaml:"brokers"`
+	Topic   string                 `yaml:"topic"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, util.PrefixConfig(prefix, "enabled"), false, "Enable the query audit log.")
+	f.StringVar(&cfg.Sink, util.PrefixConfig(prefix, "sink"), SinkLog, "Where to write query audit records. One of log, http or kafka.")
+	f.StringVar(&cfg.UserHeader, util.PrefixConfig(prefix, "user-header"), defaultUserHeader, "Request header that identifies the user issuing the query.")
+	f.IntVar(&cfg.QueueSize, util.PrefixConfig(prefix, "queue-size"), defaultQueueSize, "Number of audit records buffered before new records are dropped.")
+	f.StringVar(&cfg.HTTP.Endpoint, util.PrefixConfig(prefix, "http.endpoint"), "", "Endpoint audit records are posted to when the sink is http.")
+	f.DurationVar(&cfg.HTTP.Timeout, util.PrefixConfig(prefix, "http.timeout"), defaultHTTPTimeout, "Timeout for posting an audit record.")
+	f.Var(&cfg.Kafka.Brokers, util.PrefixConfig(prefix, "kafka.brokers"), "Comma separated list of Kafka brokers when the sink is kafka.")
+	f.StringVar(&cfg.Kafka.Topic, util.PrefixConfig(prefix, "kafka.topic"), "", "Kafka topic audit records are produced to.")
+}
+
+func (cfg *Config) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.QueueSize <= 0 {
+		return errors.New("audit queue size must be greater than 0")
+	}
+
+	switch cfg.Sink {
+	case SinkLog:
+	case SinkHTTP:
+		if cfg.HTTP.Endpoint == "" {
+			return errors.New("audit http sink requires an endpoint")
+		}
+	case SinkKafka:
+		if len(cfg.Kafka.Brokers) == 0 || cfg.Kafka.Topic == "" {
+			return errors.New("audit kafka sink requires brokers and a topic")
+		}
+	default:
+		return fmt.Errorf("unknown audit sink %q", cfg.Sink)
+	}
+
+	return nil
+}
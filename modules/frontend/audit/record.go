@@ -0,0 +1,56 @@
+package audit
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	StatusSuccess = "success"
+	StatusError   = "error"
+)
+
+// Record describes a single query handled by the frontend.
+type Record struct {
+	Timestamp time.Time `json:"timestamp"`
+	Tenant    string    `json:"tenant"`
+	User      string    `json:"user,omitempty"`
+	Op        string    `json:"op"`
+	Query     string    `json:"query,omitempty"`
+	TraceID   string    `json:"traceID,omitempty"`
+	// Start and End are the requested time range in unix seconds
+	Start uint32 `json:"start,omitempty"`
+	End   uint32 `json:"end,omitempty"`
+
+	Status         string  `json:"status"`
+	StatusCode     int     `json:"statusCode,omitempty"`
+	Error          string  `json:"error,omitempty"`
+	InspectedBytes uint64  `json:"inspectedBytes"`
+	LatencySeconds float64 `json:"latencySeconds"`
+}
+
+// NewRecord builds a record for a finished query. resp is nil for gRPC requests.
+func NewRecord(tenant, user, op string, start time.Time, resp *http.Response, inspectedBytes uint64, err error) Record {
+	r := Record{
+		Timestamp:      start,
+		Tenant:         tenant,
+		User:           user,
+		Op:             op,
+		Status:         StatusSuccess,
+		InspectedBytes: inspectedBytes,
+		LatencySeconds: time.Since(start).Seconds(),
+	}
+
+	if resp != nil {
+		r.StatusCode = resp.StatusCode
+		if resp.StatusCode >= http.StatusBadRequest {
+			r.Status = StatusError
+		}
+	}
+	if err != nil {
+		r.Status = StatusError
+		r.Error = err.Error()
+	}
+
+	return r
+}
@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/IBM/sarama"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// Sink writes audit records to their destination.
+type Sink interface {
+	Write(ctx context.Context, r Record) error
+	Close() error
+}
+
+func newSink(cfg Config, logger log.Logger) (Sink, error) {
+	switch cfg.Sink {
+	case SinkLog:
+		return &logSink{logger: logger}, nil
+	case SinkHTTP:
+		return &httpSink{
+			client:   &http.Client{Timeout: cfg.HTTP.Timeout},
+			endpoint: cfg.HTTP.Endpoint,
+		}, nil
+	case SinkKafka:
+		return newKafkaSink(cfg.Kafka)
+	}
+
+	return nil, fmt.Errorf("unknown audit sink %q", cfg.Sink)
+}
+
+// logSink writes records to the process log.
+type logSink struct {
+	logger log.Logger
+}
+
+func (s *logSink) Write(_ context.Context, r Record) error {
+	return level.Info(s.logger).Log(
+		"msg", "query audit",
+		"tenant", r.Tenant,
+		"user", r.User,
+		"op", r.Op,
+		"query", r.Query,
+		"traceID", r.TraceID,
+		"start", r.Start,
+		"end", r.End,
+		"status", r.Status,
+		"status_code", r.StatusCode,
+		"error", r.Error,
+		"inspected_bytes", r.InspectedBytes,
+		"duration_seconds", r.LatencySeconds)
+}
+
+func (s *logSink) Close() error { return nil }
+
+// httpSink posts each record as JSON to an endpoint.
+type httpSink struct {
+	client   *http.Client
+	endpoint string
+}
+
+func (s *httpSink) Write(ctx context.Context, r Record) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("audit endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *httpSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// kafkaSink produces each record as JSON to a topic, keyed by tenant.
+type kafkaSink struct {
+	producer sarama.SyncProducer
+	topic    string
+}
+
+func newKafkaSink(cfg KafkaConfig) (*kafkaSink, error) {
+	sc := sarama.NewConfig()
+	sc.ClientID = "tempo-query-audit"
+	sc.Producer.Return.Successes = true // required by the sync producer
+	sc.Producer.RequiredAcks = sarama.WaitForLocal
+
+	producer, err := sarama.NewSyncProducer(cfg.Brokers, sc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit kafka producer: %w", err)
+	}
+
+	return &kafkaSink{
+		producer: producer,
+		topic:    cfg.Topic,
+	}, nil
+}
+
+func (s *kafkaSink) Write(_ context.Context, r Record) error {
+	value, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = s.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: s.topic,
+		Key:   sarama.StringEncoder(r.Tenant),
+		Value: sarama.ByteEncoder(value),
+	})
+	return err
+}
+
+func (s *kafkaSink) Close() error {
+	return s.producer.Close()
+}
@@ -3,6 +3,7 @@ package frontend
 import (
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/grafana/tempo/tempodb/backend"
 )
@@ -37,6 +38,37 @@ func cacheKey(prefix string, tenant string, queryHash uint64, start int64, end i
 		return ""
 	}
 
+	return blockCacheKey(prefix, tenant, queryHash, meta, startPage, pagesToSearch)
+}
+
+// tagCacheKey returns a cache key for a backend tag or tag values job. tag jobs search the whole block regardless of
+// the requested range so, unlike cacheKey, the key is valid for any range that overlaps the block.
+func tagCacheKey(prefix string, tenant string, queryHash uint64, meta *backend.BlockMeta, startPage, pagesToSearch int) string {
+	if queryHash == 0 {
+		return ""
+	}
+
+	return blockCacheKey(prefix, tenant, queryHash, meta, startPage, pagesToSearch)
+}
+
+// tagCacheTTL returns the TTL a tag job of a block is cached with. the TTL is the age of the block, bounded by
+// minTTL and maxTTL, so recent blocks, which are likely to be compacted soon, expire quickly while old blocks stay
+// cached for long. a maxTTL of 0 disables the upper bound. a TTL of 0 uses the configured expiration of the cache.
+func tagCacheTTL(meta *backend.BlockMeta, now time.Time, minTTL, maxTTL time.Duration) time.Duration {
+	ttl := now.Sub(meta.EndTime)
+	if ttl < minTTL {
+		ttl = minTTL
+	}
+	if maxTTL > 0 && ttl > maxTTL {
+		ttl = maxTTL
+	}
+	if ttl < 0 {
+		ttl = 0
+	}
+	return ttl
+}
+
+func blockCacheKey(prefix string, tenant string, queryHash uint64, meta *backend.BlockMeta, startPage, pagesToSearch int) string {
 	sb := strings.Builder{}
 	sb.Grow(len(prefix) +
 		len(tenant) +
@@ -163,3 +163,68 @@ func BenchmarkCacheKeyForJob(b *testing.B) {
 		}
 	}
 }
+
+func TestTagCacheKey(t *testing.T) {
+	meta := &backend.BlockMeta{
+		BlockID:   uuid.MustParse("00000000-0000-0000-0000-000000000123"),
+		StartTime: time.Unix(5, 0),
+		EndTime:   time.Unix(30, 0),
+	}
+
+	// tag jobs search the whole block, the key doesn't depend on the search range
+	require.Equal(t, "st:foo:42:00000000-0000-0000-0000-000000000123:1:2", tagCacheKey(cacheKeyPrefixSearchTag, "foo", 42, meta, 1, 2))
+	require.Equal(t, "stv:foo:42:00000000-0000-0000-0000-000000000123:1:2", tagCacheKey(cacheKeyPrefixSearchTagValues, "foo", 42, meta, 1, 2))
+	require.Equal(t, "", tagCacheKey(cacheKeyPrefixSearchTag, "foo", 0, meta, 1, 2))
+}
+
+func TestTagCacheTTL(t *testing.T) {
+	now := time.Unix(100000, 0)
+
+	tcs := []struct {
+		name     string
+		age      time.Duration
+		minTTL   time.Duration
+		maxTTL   time.Duration
+		expected time.Duration
+	}{
+		{
+			name:     "age",
+			age:      time.Hour,
+			minTTL:   5 * time.Minute,
+			maxTTL:   24 * time.Hour,
+			expected: time.Hour,
+		},
+		{
+			name:     "recent block uses min ttl",
+			age:      time.Minute,
+			minTTL:   5 * time.Minute,
+			maxTTL:   24 * time.Hour,
+			expected: 5 * time.Minute,
+		},
+		{
+			name:     "old block uses max ttl",
+			age:      48 * time.Hour,
+			minTTL:   5 * time.Minute,
+			maxTTL:   24 * time.Hour,
+			expected: 24 * time.Hour,
+		},
+		{
+			name:     "no max ttl",
+			age:      48 * time.Hour,
+			minTTL:   5 * time.Minute,
+			expected: 48 * time.Hour,
+		},
+		{
+			name:     "block ending in the future uses the cache expiration",
+			age:      -time.Minute,
+			expected: 0,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			meta := &backend.BlockMeta{EndTime: now.Add(-tc.age)}
+			require.Equal(t, tc.expected, tagCacheTTL(meta, now, tc.minTTL, tc.maxTTL))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package frontend
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/grafana/tempo/modules/frontend/canary"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+// searchFingerprint summarizes a search response by the IDs of the returned traces. Searches that hit the limit
+// may legitimately return different traces on each run.
+func searchFingerprint(body []byte) (string, error) {
+	resp := &tempopb.SearchResponse{}
+	if err := unmarshalCanaryJSON(body, resp); err != nil {
+		return "", err
+	}
+
+	ids := make([]string, 0, len(resp.Traces))
+	for _, t := range resp.Traces {
+		ids = append(ids, t.TraceID)
+	}
+	sort.Strings(ids)
+
+	return strings.Join(ids, ","), nil
+}
+
+// traceByIDFingerprint summarizes a trace by the number and IDs of its spans.
+func traceByIDFingerprint(marshallingFormat string) canary.Fingerprint {
+	return func(body []byte) (string, error) {
+		trace := &tempopb.Trace{}
+		var err error
+		if marshallingFormat == api.HeaderAcceptProtobuf {
+			err = proto.Unmarshal(body, trace)
+		} else {
+			err = unmarshalCanaryJSON(body, trace)
+		}
+		if err != nil {
+			return "", err
+		}
+
+		var ids []string
+		for _, rs := range trace.Batches {
+			for _, ss := range rs.ScopeSpans {
+				for _, s := range ss.Spans {
+					ids = append(ids, util.SpanIDToHexString(s.SpanId))
+				}
+			}
+		}
+		sort.Strings(ids)
+
+		h := fnv.New64a()
+		for _, id := range ids {
+			_, _ = h.Write([]byte(id))
+		}
+		return fmt.Sprintf("%d/%x", len(ids), h.Sum64()), nil
+	}
+}
+
+// queryRangeFingerprint summarizes a metrics response by its series and samples. Values are rounded so that
+// floating point differences caused by the order in which jobs are combined are ignored.
+func queryRangeFingerprint(body []byte) (string, error) {
+	resp := &tempopb.QueryRangeResponse{}
+	if err := unmarshalCanaryJSON(body, resp); err != nil {
+		return "", err
+	}
+
+	series := make([]string, 0, len(resp.Series))
+	for _, s := range resp.Series {
+		sb := strings.Builder{}
+		for _, l := range s.Labels {
+			sb.WriteString(l.Key)
+			sb.WriteString("=")
+			sb.WriteString(l.Value.String())
+			sb.WriteString(",")
+		}
+		for _, sample := range s.Samples {
+			sb.WriteString(strconv.FormatInt(sample.TimestampMs, 10))
+			sb.WriteString(":")
+			sb.WriteString(strconv.FormatFloat(sample.Value, 'g', 6, 64))
+			sb.WriteString(" ")
+		}
+		series = append(series, sb.String())
+	}
+	sort.Strings(series)
+
+	return strings.Join(series, "\n"), nil
+}
+
+func unmarshalCanaryJSON(body []byte, pb proto.Message) error {
+	return (&jsonpb.Unmarshaler{AllowUnknownFields: true}).Unmarshal(bytes.NewReader(body), pb)
+}
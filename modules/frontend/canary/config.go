@@ -0,0 +1,66 @@
+package canary
+
+import (
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/grafana/tempo/pkg/util"
+)
+
+const (
+	defaultFraction    = 0.01
+	defaultQueueSize   = 100
+	defaultConcurrency = 2
+	defaultTimeout     = 30 * time.Second
+)
+
+type Config struct {
+	Enabled bool `yaml:"enabled"`
+	// Endpoint is the base URL of the canary query path, i.e. a query frontend in front of a different querier pool.
+	Endpoint string `yaml:"endpoint"`
+	// Fraction is the share of queries that are replayed against the canary, between 0 and 1.
+	Fraction float64 `yaml:"fraction"`
+	// Delay is how long after the production query finished the replay is sent.
+	Delay time.Duration `yaml:"delay"`
+	// Timeout is the maximum time a replayed query may take.
+	Timeout time.Duration `yaml:"timeout"`
+	// QueueSize is the number of replays buffered before new replays are dropped.
+	QueueSize int `yaml:"queue_size"`
+	// Concurrency is the number of replays that are sent in parallel.
+	Concurrency int `yaml:"concurrency"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, util.PrefixConfig(prefix, "enabled"), false, "Replay a fraction of queries against a canary query path and record differences.")
+	f.StringVar(&cfg.Endpoint, util.PrefixConfig(prefix, "endpoint"), "", "Base URL of the canary query path.")
+	f.Float64Var(&cfg.Fraction, util.PrefixConfig(prefix, "fraction"), defaultFraction, "Fraction of queries replayed against the canary.")
+	f.DurationVar(&cfg.Delay, util.PrefixConfig(prefix, "delay"), 0, "Time to wait after a query finished before replaying it.")
+	f.DurationVar(&cfg.Timeout, util.PrefixConfig(prefix, "timeout"), defaultTimeout, "Timeout for a replayed query.")
+	f.IntVar(&cfg.QueueSize, util.PrefixConfig(prefix, "queue-size"), defaultQueueSize, "Number of replays buffered before new replays are dropped.")
+	f.IntVar(&cfg.Concurrency, util.PrefixConfig(prefix, "concurrency"), defaultConcurrency, "Number of replays sent in parallel.")
+}
+
+func (cfg *Config) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.Endpoint == "" {
+		return errors.New("canary endpoint is required")
+	}
+	if cfg.Fraction <= 0 || cfg.Fraction > 1 {
+		return errors.New("canary fraction must be greater than 0 and at most 1")
+	}
+	if cfg.Delay < 0 {
+		return errors.New("canary delay must not be negative")
+	}
+	if cfg.QueueSize <= 0 {
+		return errors.New("canary queue size must be greater than 0")
+	}
+	if cfg.Concurrency <= 0 {
+		return errors.New("canary concurrency must be greater than 0")
+	}
+
+	return nil
+}
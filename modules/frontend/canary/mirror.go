@@ -0,0 +1,265 @@
+package canary
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// HeaderCanary marks replayed queries so a canary that mirrors queries itself doesn't replay them again.
+const HeaderCanary = "X-Tempo-Canary"
+
+const (
+	resultMatch    = "match"
+	resultMismatch = "mismatch"
+	resultFailed   = "failed"
+	resultDropped  = "dropped"
+
+	targetPrimary = "primary"
+	targetCanary  = "canary"
+)
+
+var (
+	metricReplays = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "query_frontend_canary_replays_total",
+		Help:      "Total number of queries replayed against the canary by op and result (match, mismatch, failed or dropped).",
+	}, []string{"op", "result"})
+	metricDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tempo",
+		Name:      "query_frontend_canary_duration_seconds",
+		Help:      "Duration of replayed queries on the primary and canary query paths.",
+		Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+	}, []string{"op", "target"})
+)
+
+// Fingerprint summarizes a response body so production and canary results can be compared. Responses with equal
+// fingerprints are considered equal.
+type Fingerprint func(body []byte) (string, error)
+
+// Request is a sampled query waiting for its production response.
+type Request struct {
+	op          string
+	method      string
+	uri         string
+	header      http.Header
+	fingerprint Fingerprint
+}
+
+type replay struct {
+	*Request
+	at       time.Time
+	status   int
+	body     []byte
+	duration time.Duration
+}
+
+// Mirror replays a fraction of queries against a canary query path in the background and records whether the
+// results and latency differ from production. A nil Mirror replays nothing.
+type Mirror struct {
+	cfg      Config
+	endpoint string
+	client   *http.Client
+	logger   log.Logger
+	sample   func() bool
+
+	replays chan replay
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+func New(cfg Config, logger log.Logger) (*Mirror, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return newMirror(cfg, &http.Client{Timeout: cfg.Timeout}, logger), nil
+}
+
+func newMirror(cfg Config, client *http.Client, logger log.Logger) *Mirror {
+	m := &Mirror{
+		cfg:      cfg,
+		endpoint: strings.TrimSuffix(cfg.Endpoint, "/"),
+		client:   client,
+		logger:   logger,
+		sample:   func() bool { return rand.Float64() < cfg.Fraction },
+		replays:  make(chan replay, cfg.QueueSize),
+		done:     make(chan struct{}),
+	}
+
+	for i := 0; i < cfg.Concurrency; i++ {
+		m.wg.Add(1)
+		go m.run()
+	}
+
+	return m
+}
+
+// Sample decides whether a query is replayed against the canary. It must be called before the pipeline modifies
+// the request. Returns nil if the query is not replayed.
+func (m *Mirror) Sample(op string, req *http.Request, fingerprint Fingerprint) *Request {
+	if m == nil || req.Header.Get(HeaderCanary) != "" || !m.sample() {
+		return nil
+	}
+
+	header := req.Header.Clone()
+	if tenant, err := user.ExtractOrgID(req.Context()); err == nil {
+		header.Set(user.OrgIDHeaderName, tenant)
+	}
+
+	return &Request{
+		op:          op,
+		method:      req.Method,
+		uri:         req.URL.RequestURI(),
+		header:      header,
+		fingerprint: fingerprint,
+	}
+}
+
+// Replay queues a sampled query to be sent to the canary after the configured delay. The body of the production
+// response is read to compare it and replaced so it can still be returned to the caller. Failed production
+// queries are not replayed.
+func (m *Mirror) Replay(r *Request, resp *http.Response, duration time.Duration, err error) {
+	if m == nil || r == nil || err != nil || resp == nil {
+		return
+	}
+
+	var body []byte
+	if resp.Body != nil {
+		body, err = io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			// hand the error to the caller once the bytes read so far are consumed
+			resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), &errReader{err: err}))
+			metricReplays.WithLabelValues(r.op, resultFailed).Inc()
+			return
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	select {
+	case m.replays <- replay{
+		Request:  r,
+		at:       time.Now().Add(m.cfg.Delay),
+		status:   resp.StatusCode,
+		body:     body,
+		duration: duration,
+	}:
+	default:
+		metricReplays.WithLabelValues(r.op, resultDropped).Inc()
+	}
+}
+
+// Stop drops all queued replays and waits for in flight replays to finish.
+func (m *Mirror) Stop() {
+	if m == nil {
+		return
+	}
+
+	close(m.done)
+	close(m.replays)
+	m.wg.Wait()
+}
+
+func (m *Mirror) run() {
+	defer m.wg.Done()
+
+	for r := range m.replays {
+		select {
+		case <-m.done:
+			metricReplays.WithLabelValues(r.op, resultDropped).Inc()
+			continue
+		case <-time.After(time.Until(r.at)):
+		}
+
+		m.replay(r)
+	}
+}
+
+func (m *Mirror) replay(r replay) {
+	req, err := http.NewRequest(r.method, m.endpoint+r.uri, nil)
+	if err != nil {
+		metricReplays.WithLabelValues(r.op, resultFailed).Inc()
+		level.Warn(m.logger).Log("msg", "failed to create canary request", "op", r.op, "uri", r.uri, "err", err)
+		return
+	}
+	req.Header = r.header
+	req.Header.Set(HeaderCanary, "true")
+
+	start := time.Now()
+	resp, err := m.client.Do(req)
+	if err != nil {
+		metricReplays.WithLabelValues(r.op, resultFailed).Inc()
+		level.Warn(m.logger).Log("msg", "failed to replay query against canary", "op", r.op, "uri", r.uri, "err", err)
+		return
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	duration := time.Since(start)
+	if err != nil {
+		metricReplays.WithLabelValues(r.op, resultFailed).Inc()
+		level.Warn(m.logger).Log("msg", "failed to read canary response", "op", r.op, "uri", r.uri, "err", err)
+		return
+	}
+
+	metricDuration.WithLabelValues(r.op, targetPrimary).Observe(r.duration.Seconds())
+	metricDuration.WithLabelValues(r.op, targetCanary).Observe(duration.Seconds())
+
+	result, reason := compare(r, resp.StatusCode, body)
+	metricReplays.WithLabelValues(r.op, result).Inc()
+	if result != resultMatch {
+		level.Warn(m.logger).Log(
+			"msg", "canary result differs from production",
+			"op", r.op,
+			"uri", r.uri,
+			"result", result,
+			"reason", reason,
+			"primary_status", r.status,
+			"canary_status", resp.StatusCode,
+			"primary_duration_seconds", r.duration.Seconds(),
+			"canary_duration_seconds", duration.Seconds())
+	}
+}
+
+// compare returns the result of comparing the canary response to the production response and, if they don't
+// match, the reason.
+func compare(r replay, status int, body []byte) (string, string) {
+	if status != r.status {
+		return resultMismatch, "status code"
+	}
+	if status != http.StatusOK || r.fingerprint == nil {
+		return resultMatch, ""
+	}
+
+	primary, err := r.fingerprint(r.body)
+	if err != nil {
+		return resultFailed, "fingerprinting production response: " + err.Error()
+	}
+	canary, err := r.fingerprint(body)
+	if err != nil {
+		return resultMismatch, "fingerprinting canary response: " + err.Error()
+	}
+	if primary != canary {
+		return resultMismatch, "results"
+	}
+
+	return resultMatch, ""
+}
+
+type errReader struct {
+	err error
+}
+
+func (r *errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,193 @@
+package canary
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirrorReplay(t *testing.T) {
+	tests := []struct {
+		name           string
+		primaryStatus  int
+		primaryBody    string
+		canaryStatus   int
+		canaryBody     string
+		expectedResult string
+	}{
+		{
+			name:           "match",
+			primaryStatus:  http.StatusOK,
+			primaryBody:    "a,b",
+			canaryStatus:   http.StatusOK,
+			canaryBody:     "b,a",
+			expectedResult: resultMatch,
+		},
+		{
+			name:           "results differ",
+			primaryStatus:  http.StatusOK,
+			primaryBody:    "a,b",
+			canaryStatus:   http.StatusOK,
+			canaryBody:     "a",
+			expectedResult: resultMismatch,
+		},
+		{
+			name:           "status differs",
+			primaryStatus:  http.StatusOK,
+			primaryBody:    "a",
+			canaryStatus:   http.StatusInternalServerError,
+			expectedResult: resultMismatch,
+		},
+		{
+			name:           "same error",
+			primaryStatus:  http.StatusBadRequest,
+			canaryStatus:   http.StatusBadRequest,
+			expectedResult: resultMatch,
+		},
+		{
+			name:           "unreadable production response",
+			primaryStatus:  http.StatusOK,
+			primaryBody:    "boom",
+			canaryStatus:   http.StatusOK,
+			expectedResult: resultFailed,
+		},
+	}
+
+	// sorts the comma separated body
+	fingerprint := func(body []byte) (string, error) {
+		if string(body) == "boom" {
+			return "", errors.New("boom")
+		}
+		parts := strings.Split(string(body), ",")
+		if len(parts) == 2 && parts[0] > parts[1] {
+			parts[0], parts[1] = parts[1], parts[0]
+		}
+		return strings.Join(parts, ","), nil
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := make(chan *http.Request, 1)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests <- r
+				w.WriteHeader(tc.canaryStatus)
+				_, _ = w.Write([]byte(tc.canaryBody))
+			}))
+			defer srv.Close()
+
+			m := newTestMirror(srv.URL + "/")
+			defer m.Stop()
+
+			op := "test-" + tc.name
+			before := testutil.ToFloat64(metricReplays.WithLabelValues(op, tc.expectedResult))
+
+			ctx := user.InjectOrgID(context.Background(), "tenant")
+			req := httptest.NewRequest(http.MethodGet, "/api/search?q=%7B%7D", nil).WithContext(ctx)
+			req.Header.Set("Accept", "application/json")
+
+			r := m.Sample(op, req, fingerprint)
+			require.NotNil(t, r)
+
+			resp := &http.Response{StatusCode: tc.primaryStatus, Body: io.NopCloser(strings.NewReader(tc.primaryBody))}
+			m.Replay(r, resp, time.Second, nil)
+
+			// the production response is still readable
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, tc.primaryBody, string(body))
+
+			canaryReq := <-requests
+			require.Equal(t, "/api/search", canaryReq.URL.Path)
+			require.Equal(t, "{}", canaryReq.URL.Query().Get("q"))
+			require.Equal(t, "tenant", canaryReq.Header.Get(user.OrgIDHeaderName))
+			require.Equal(t, "application/json", canaryReq.Header.Get("Accept"))
+			require.Equal(t, "true", canaryReq.Header.Get(HeaderCanary))
+
+			require.Eventually(t, func() bool {
+				return testutil.ToFloat64(metricReplays.WithLabelValues(op, tc.expectedResult)) == before+1
+			}, time.Second, time.Millisecond)
+		})
+	}
+}
+
+func TestMirrorSkips(t *testing.T) {
+	m := newTestMirror("http://localhost")
+	defer m.Stop()
+
+	// replayed queries are never replayed again
+	req := httptest.NewRequest(http.MethodGet, "/api/search", nil)
+	req.Header.Set(HeaderCanary, "true")
+	require.Nil(t, m.Sample("search", req, nil))
+
+	// unsampled queries
+	m.sample = func() bool { return false }
+	require.Nil(t, m.Sample("search", httptest.NewRequest(http.MethodGet, "/api/search", nil), nil))
+
+	// failed production queries
+	before := len(m.replays)
+	m.Replay(&Request{op: "search"}, nil, time.Second, errors.New("failed"))
+	require.Equal(t, before, len(m.replays))
+}
+
+func TestMirrorDelay(t *testing.T) {
+	sent := make(chan time.Time, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		sent <- time.Now()
+	}))
+	defer srv.Close()
+
+	m := newTestMirror(srv.URL)
+	m.cfg.Delay = 100 * time.Millisecond
+	defer m.Stop()
+
+	r := m.Sample("search", httptest.NewRequest(http.MethodGet, "/api/search", nil), nil)
+	start := time.Now()
+	m.Replay(r, &http.Response{StatusCode: http.StatusOK}, time.Second, nil)
+
+	require.GreaterOrEqual(t, (<-sent).Sub(start), m.cfg.Delay)
+}
+
+func TestNilMirror(t *testing.T) {
+	var m *Mirror
+
+	r := m.Sample("search", httptest.NewRequest(http.MethodGet, "/api/search", nil), nil)
+	require.Nil(t, r)
+	m.Replay(r, &http.Response{StatusCode: http.StatusOK}, time.Second, nil)
+	m.Stop()
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := Config{}
+	require.NoError(t, cfg.Validate())
+
+	cfg = Config{Enabled: true, Endpoint: "http://canary", Fraction: 0.1, QueueSize: 1, Concurrency: 1}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Fraction = 0
+	require.Error(t, cfg.Validate())
+
+	cfg.Fraction = 1
+	cfg.Endpoint = ""
+	require.Error(t, cfg.Validate())
+}
+
+func newTestMirror(endpoint string) *Mirror {
+	m := newMirror(Config{
+		Endpoint:    endpoint,
+		Fraction:    1,
+		QueueSize:   10,
+		Concurrency: 1,
+	}, &http.Client{Timeout: time.Second}, log.NewNopLogger())
+	m.sample = func() bool { return true }
+	return m
+}
@@ -0,0 +1,98 @@
+package frontend
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestSearchFingerprint(t *testing.T) {
+	a := marshalCanaryJSON(t, &tempopb.SearchResponse{
+		Traces:  []*tempopb.TraceSearchMetadata{{TraceID: "1"}, {TraceID: "2"}},
+		Metrics: &tempopb.SearchMetrics{InspectedBytes: 10},
+	})
+	b := marshalCanaryJSON(t, &tempopb.SearchResponse{
+		Traces:  []*tempopb.TraceSearchMetadata{{TraceID: "2"}, {TraceID: "1"}},
+		Metrics: &tempopb.SearchMetrics{InspectedBytes: 20},
+	})
+	c := marshalCanaryJSON(t, &tempopb.SearchResponse{
+		Traces: []*tempopb.TraceSearchMetadata{{TraceID: "1"}},
+	})
+
+	// order and metrics are ignored
+	requireFingerprints(t, searchFingerprint, a, b, true)
+	requireFingerprints(t, searchFingerprint, a, c, false)
+
+	_, err := searchFingerprint([]byte("not json"))
+	require.Error(t, err)
+}
+
+func TestTraceByIDFingerprint(t *testing.T) {
+	trace := test.MakeTrace(5, []byte{0x01})
+	other := test.MakeTrace(5, []byte{0x02})
+
+	protoA, err := proto.Marshal(trace)
+	require.NoError(t, err)
+	protoB, err := proto.Marshal(other)
+	require.NoError(t, err)
+
+	requireFingerprints(t, traceByIDFingerprint(api.HeaderAcceptProtobuf), protoA, protoA, true)
+	requireFingerprints(t, traceByIDFingerprint(api.HeaderAcceptProtobuf), protoA, protoB, false)
+
+	jsonA := marshalCanaryJSON(t, trace)
+	jsonB := marshalCanaryJSON(t, other)
+
+	requireFingerprints(t, traceByIDFingerprint(api.HeaderAcceptJSON), jsonA, jsonA, true)
+	requireFingerprints(t, traceByIDFingerprint(api.HeaderAcceptJSON), jsonA, jsonB, false)
+}
+
+func TestQueryRangeFingerprint(t *testing.T) {
+	series := func(name string, value float64) *tempopb.TimeSeries {
+		return &tempopb.TimeSeries{
+			Labels:  []v1.KeyValue{{Key: "name", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: name}}}},
+			Samples: []tempopb.Sample{{TimestampMs: 1000, Value: value}},
+		}
+	}
+
+	a := marshalCanaryJSON(t, &tempopb.QueryRangeResponse{
+		Series: []*tempopb.TimeSeries{series("a", 0.3), series("b", 1)},
+	})
+	b := marshalCanaryJSON(t, &tempopb.QueryRangeResponse{
+		Series: []*tempopb.TimeSeries{series("b", 1), series("a", 0.1+0.2)},
+	})
+	c := marshalCanaryJSON(t, &tempopb.QueryRangeResponse{
+		Series: []*tempopb.TimeSeries{series("a", 0.3), series("b", 2)},
+	})
+
+	// series order and float rounding differences are ignored
+	requireFingerprints(t, queryRangeFingerprint, a, b, true)
+	requireFingerprints(t, queryRangeFingerprint, a, c, false)
+}
+
+func requireFingerprints(t *testing.T, fingerprint func([]byte) (string, error), a, b []byte, equal bool) {
+	t.Helper()
+
+	fpA, err := fingerprint(a)
+	require.NoError(t, err)
+	fpB, err := fingerprint(b)
+	require.NoError(t, err)
+
+	if equal {
+		require.Equal(t, fpA, fpB)
+	} else {
+		require.NotEqual(t, fpA, fpB)
+	}
+}
+
+func marshalCanaryJSON(t *testing.T, pb proto.Message) []byte {
+	s, err := new(jsonpb.Marshaler).MarshalToString(pb)
+	require.NoError(t, err)
+	return []byte(s)
+}
@@ -185,9 +185,12 @@ func (c *genericCombiner[T]) erroredResponse() (*http.Response, error) {
 		return nil, nil
 	}
 
-	// build grpc error and http response
+	// build grpc error and http response. the body is an api.QueryError if json errors are enabled
 	var grpcErr error
-	if c.httpStatusCode/100 == 5 {
+	if body := []byte(c.httpRespBody); api.IsQueryErrorBody(body) {
+		queryErr := api.ParseQueryError(c.httpStatusCode, body)
+		grpcErr = status.Error(queryErr.GRPCCode(), queryErr.Message)
+	} else if c.httpStatusCode/100 == 5 {
 		grpcErr = status.Error(codes.Internal, c.httpRespBody)
 	} else if c.httpStatusCode == http.StatusTooManyRequests {
 		grpcErr = status.Error(codes.ResourceExhausted, c.httpRespBody)
@@ -2,30 +2,57 @@ package combiner
 
 import (
 	"sort"
+	"time"
 
 	"github.com/grafana/tempo/pkg/search"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/traceql"
 )
 
+// maxShardErrors caps the shard errors kept in a combined search response. Partial is still set once the
+// cap is reached.
+const maxShardErrors = 25
+
 var _ GRPCCombiner[*tempopb.SearchResponse] = (*genericCombiner[*tempopb.SearchResponse])(nil)
 
-// NewSearch returns a search combiner
-func NewSearch(limit int) Combiner {
+// NewSearch returns a search combiner. If keepMostRecent is set the combiner keeps the limit most recent traces
+// instead of the first limit traces found. It then only quits early once all kept traces are newer than anything
+// the outstanding jobs can return. This requires the sharder to communicate search shards.
+func NewSearch(limit int, keepMostRecent bool) Combiner {
 	metadataCombiner := traceql.NewMetadataCombiner()
 	diffTraces := map[string]struct{}{}
+	shards := &shardTracker{}
 
 	return &genericCombiner[*tempopb.SearchResponse]{
 		httpStatusCode: 200,
 		new:            func() *tempopb.SearchResponse { return &tempopb.SearchResponse{} },
 		current:        &tempopb.SearchResponse{Metrics: &tempopb.SearchMetrics{}},
-		combine: func(partial *tempopb.SearchResponse, final *tempopb.SearchResponse, _ PipelineResponse) error {
+		combine: func(partial *tempopb.SearchResponse, final *tempopb.SearchResponse, resp PipelineResponse) error {
+			if keepMostRecent {
+				switch data := resp.AdditionalData().(type) {
+				case []SearchShard:
+					shards.addShards(data)
+				case SearchJobShard:
+					shards.addCompletedJob(data)
+				}
+			}
+
 			for _, t := range partial.Traces {
-				// if we've reached the limit and this is NOT a new trace then skip it
+				// if we've reached the limit and this is NOT a new trace then skip it. when keeping the
+				// most recent traces, replace the oldest trace if this one is newer
 				if limit > 0 &&
 					metadataCombiner.Count() >= limit &&
 					!metadataCombiner.Exists(t.TraceID) {
-					continue
+					if !keepMostRecent {
+						continue
+					}
+
+					oldest := metadataCombiner.Oldest()
+					if oldest.StartTimeUnixNano >= t.StartTimeUnixNano {
+						continue
+					}
+					metadataCombiner.Remove(oldest.TraceID)
+					delete(diffTraces, oldest.TraceID)
 				}
 
 				metadataCombiner.AddMetadata(t)
@@ -33,6 +60,16 @@ func NewSearch(limit int) Combiner {
 				diffTraces[t.TraceID] = struct{}{}
 			}
 
+			if partial.Partial {
+				final.Partial = true
+				for _, shardErr := range partial.ShardErrors {
+					if len(final.ShardErrors) >= maxShardErrors {
+						break
+					}
+					final.ShardErrors = append(final.ShardErrors, shardErr)
+				}
+			}
+
 			if partial.Metrics != nil {
 				// there is a coordination with the search sharder here. normal responses
 				// will never have total jobs set, but they will have valid Inspected* values
@@ -55,18 +92,23 @@ func NewSearch(limit int) Combiner {
 		finalize: func(final *tempopb.SearchResponse) (*tempopb.SearchResponse, error) {
 			// metrics are already combined on the passed in final
 			final.Traces = metadataCombiner.Metadata()
+			final.Groups = traceql.SearchGroups(final.Traces)
 
 			addRootSpanNotReceivedText(final.Traces)
 			return final, nil
 		},
 		diff: func(current *tempopb.SearchResponse) (*tempopb.SearchResponse, error) {
-			// wipe out any existing traces and recreate from the map
+			// wipe out any existing traces and recreate from the map. groups always cover all traces
+			allTraces := metadataCombiner.Metadata()
 			diff := &tempopb.SearchResponse{
-				Traces:  make([]*tempopb.TraceSearchMetadata, 0, len(diffTraces)),
-				Metrics: current.Metrics,
+				Traces:      make([]*tempopb.TraceSearchMetadata, 0, len(diffTraces)),
+				Metrics:     current.Metrics,
+				Partial:     current.Partial,
+				ShardErrors: current.ShardErrors,
+				Groups:      traceql.SearchGroups(allTraces),
 			}
 
-			for _, tr := range metadataCombiner.Metadata() {
+			for _, tr := range allTraces {
 				// if not in the map, skip. we haven't seen an update
 				if _, ok := diffTraces[tr.TraceID]; !ok {
 					continue
@@ -93,7 +135,20 @@ func NewSearch(limit int) Combiner {
 				return false
 			}
 
-			return metadataCombiner.Count() >= limit
+			if metadataCombiner.Count() < limit {
+				return false
+			}
+
+			if !keepMostRecent {
+				return true
+			}
+
+			// quit once the oldest trace we keep is newer than anything the outstanding jobs can find
+			completedThrough, ok := shards.completedThrough()
+			if !ok {
+				return false
+			}
+			return metadataCombiner.Oldest().StartTimeUnixNano > uint64(completedThrough)*uint64(time.Second)
 		},
 	}
 }
@@ -106,6 +161,6 @@ func addRootSpanNotReceivedText(results []*tempopb.TraceSearchMetadata) {
 	}
 }
 
-func NewTypedSearch(limit int) GRPCCombiner[*tempopb.SearchResponse] {
-	return NewSearch(limit).(GRPCCombiner[*tempopb.SearchResponse])
+func NewTypedSearch(limit int, keepMostRecent bool) GRPCCombiner[*tempopb.SearchResponse] {
+	return NewSearch(limit, keepMostRecent).(GRPCCombiner[*tempopb.SearchResponse])
 }
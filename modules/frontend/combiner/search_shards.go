@@ -0,0 +1,56 @@
+package combiner
+
+// SearchShard describes a group of jobs of a search request that is sharded most recent first. Once all jobs
+// of a shard and of all shards before it have completed, no trace that starts after CompletedThroughSeconds
+// can be found by the remaining jobs.
+type SearchShard struct {
+	TotalJobs               uint32
+	CompletedThroughSeconds uint32
+}
+
+// SearchJobShard is attached to the response of a search job and identifies the shard the job belongs to.
+type SearchJobShard int
+
+// shardTracker tracks completed jobs per shard to determine the time range for which results are final.
+type shardTracker struct {
+	shards          []SearchShard
+	completedJobs   []uint32
+	completedShards int
+}
+
+func (s *shardTracker) addShards(shards []SearchShard) {
+	s.shards = shards
+}
+
+func (s *shardTracker) addCompletedJob(shard SearchJobShard) {
+	if shard < 0 {
+		return
+	}
+
+	for int(shard) >= len(s.completedJobs) {
+		s.completedJobs = append(s.completedJobs, 0)
+	}
+	s.completedJobs[shard]++
+}
+
+// completedThrough returns the time in seconds after which all results have been found. false is returned if
+// the first shard hasn't completed yet.
+func (s *shardTracker) completedThrough() (uint32, bool) {
+	for s.completedShards < len(s.shards) {
+		completed := uint32(0)
+		if s.completedShards < len(s.completedJobs) {
+			completed = s.completedJobs[s.completedShards]
+		}
+
+		if completed < s.shards[s.completedShards].TotalJobs {
+			break
+		}
+		s.completedShards++
+	}
+
+	if s.completedShards == 0 {
+		return 0, false
+	}
+
+	return s.shards[s.completedShards-1].CompletedThroughSeconds, true
+}
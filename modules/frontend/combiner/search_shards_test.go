@@ -0,0 +1,92 @@
+package combiner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/stretchr/testify/require"
+)
+
+type shardedPipelineResponse struct {
+	PipelineResponse
+	additionalData any
+}
+
+func (p *shardedPipelineResponse) AdditionalData() any {
+	return p.additionalData
+}
+
+func TestShardTracker(t *testing.T) {
+	tracker := &shardTracker{}
+
+	// no shards, nothing completed
+	_, ok := tracker.completedThrough()
+	require.False(t, ok)
+
+	tracker.addShards([]SearchShard{
+		{TotalJobs: 1, CompletedThroughSeconds: 300},
+		{TotalJobs: 2, CompletedThroughSeconds: 200},
+		{TotalJobs: 1, CompletedThroughSeconds: 0},
+	})
+
+	// jobs of later shards don't count until the earlier shards are complete
+	tracker.addCompletedJob(1)
+	tracker.addCompletedJob(1)
+	_, ok = tracker.completedThrough()
+	require.False(t, ok)
+
+	tracker.addCompletedJob(0)
+	completedThrough, ok := tracker.completedThrough()
+	require.True(t, ok)
+	require.Equal(t, uint32(200), completedThrough)
+
+	tracker.addCompletedJob(2)
+	completedThrough, ok = tracker.completedThrough()
+	require.True(t, ok)
+	require.Equal(t, uint32(0), completedThrough)
+}
+
+func TestSearchKeepMostRecent(t *testing.T) {
+	traceAt := func(id string, seconds int64) *tempopb.TraceSearchMetadata {
+		return &tempopb.TraceSearchMetadata{
+			TraceID:           id,
+			RootServiceName:   "svc",
+			StartTimeUnixNano: uint64(time.Unix(seconds, 0).UnixNano()),
+		}
+	}
+	addResponse := func(c Combiner, data any, traces ...*tempopb.TraceSearchMetadata) {
+		resp := toHTTPResponse(t, &tempopb.SearchResponse{Traces: traces, Metrics: &tempopb.SearchMetrics{}}, 200)
+		require.NoError(t, c.AddResponse(&shardedPipelineResponse{PipelineResponse: resp, additionalData: data}))
+	}
+
+	c := NewTypedSearch(2, true)
+	addResponse(c, []SearchShard{
+		{TotalJobs: 1, CompletedThroughSeconds: 250},
+		{TotalJobs: 1, CompletedThroughSeconds: 150},
+		{TotalJobs: 1, CompletedThroughSeconds: 0},
+	})
+
+	// the limit is reached but the first shard isn't complete
+	addResponse(c, SearchJobShard(1), traceAt("1", 160), traceAt("2", 170))
+	require.False(t, c.ShouldQuit())
+
+	// newer traces replace the oldest, older ones are dropped
+	addResponse(c, SearchJobShard(0), traceAt("3", 300), traceAt("4", 100))
+	require.True(t, c.ShouldQuit())
+
+	resp, err := c.GRPCFinal()
+	require.NoError(t, err)
+	require.Len(t, resp.Traces, 2)
+	require.Equal(t, "3", resp.Traces[0].TraceID)
+	require.Equal(t, "2", resp.Traces[1].TraceID)
+
+	// the oldest trace may still be beaten by outstanding jobs
+	c = NewTypedSearch(1, true)
+	addResponse(c, []SearchShard{
+		{TotalJobs: 1, CompletedThroughSeconds: 250},
+		{TotalJobs: 1, CompletedThroughSeconds: 0},
+	})
+	addResponse(c, SearchJobShard(0), traceAt("1", 200))
+	require.False(t, c.ShouldQuit())
+}
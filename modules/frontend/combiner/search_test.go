@@ -13,39 +13,40 @@ import (
 	"github.com/gogo/status"
 	"github.com/grafana/tempo/pkg/search"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 )
 
 func TestSearchProgressShouldQuit(t *testing.T) {
 	// new combiner should not quit
-	c := NewSearch(0)
+	c := NewSearch(0, false)
 	should := c.ShouldQuit()
 	require.False(t, should)
 
 	// 500 response should quit
-	c = NewSearch(0)
+	c = NewSearch(0, false)
 	err := c.AddResponse(toHTTPResponse(t, &tempopb.SearchResponse{}, 500))
 	require.NoError(t, err)
 	should = c.ShouldQuit()
 	require.True(t, should)
 
 	// 429 response should quit
-	c = NewSearch(0)
+	c = NewSearch(0, false)
 	err = c.AddResponse(toHTTPResponse(t, &tempopb.SearchResponse{}, 429))
 	require.NoError(t, err)
 	should = c.ShouldQuit()
 	require.True(t, should)
 
 	// unparseable body should not quit, but should return an error
-	c = NewSearch(0)
+	c = NewSearch(0, false)
 	err = c.AddResponse(&pipelineResponse{&http.Response{Body: io.NopCloser(strings.NewReader("foo")), StatusCode: 200}})
 	require.Error(t, err)
 	should = c.ShouldQuit()
 	require.False(t, should)
 
 	// under limit should not quit
-	c = NewSearch(2)
+	c = NewSearch(2, false)
 	err = c.AddResponse(toHTTPResponse(t, &tempopb.SearchResponse{
 		Traces: []*tempopb.TraceSearchMetadata{
 			{
@@ -58,7 +59,7 @@ func TestSearchProgressShouldQuit(t *testing.T) {
 	require.False(t, should)
 
 	// over limit should quit
-	c = NewSearch(1)
+	c = NewSearch(1, false)
 	err = c.AddResponse(toHTTPResponse(t, &tempopb.SearchResponse{
 		Traces: []*tempopb.TraceSearchMetadata{
 			{
@@ -78,7 +79,7 @@ func TestSearchCombinesResults(t *testing.T) {
 	start := time.Date(1, 2, 3, 4, 5, 6, 7, time.UTC)
 	traceID := "traceID"
 
-	c := NewSearch(10)
+	c := NewSearch(10, false)
 	sr := toHTTPResponse(t, &tempopb.SearchResponse{
 		Traces: []*tempopb.TraceSearchMetadata{
 			{
@@ -125,6 +126,43 @@ func TestSearchCombinesResults(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+func TestSearchCombinesGroups(t *testing.T) {
+	byService := func(service string, matched uint32) *tempopb.SpanSet {
+		return &tempopb.SpanSet{
+			Matched: matched,
+			Attributes: []*v1.KeyValue{
+				{Key: "by(resource.service.name)", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: service}}},
+			},
+		}
+	}
+
+	c := NewSearch(10, false)
+	for _, traces := range [][]*tempopb.TraceSearchMetadata{
+		{
+			{TraceID: "1", RootServiceName: "a", SpanSets: []*tempopb.SpanSet{byService("a", 2), byService("b", 1)}},
+		},
+		{
+			// the same group of a trace found by another job isn't counted twice
+			{TraceID: "1", RootServiceName: "a", SpanSets: []*tempopb.SpanSet{byService("a", 3)}},
+			{TraceID: "2", RootServiceName: "a", SpanSets: []*tempopb.SpanSet{byService("a", 1)}},
+		},
+	} {
+		err := c.AddResponse(toHTTPResponse(t, &tempopb.SearchResponse{Traces: traces, Metrics: &tempopb.SearchMetrics{}}, 200))
+		require.NoError(t, err)
+	}
+
+	resp, err := c.HTTPFinal()
+	require.NoError(t, err)
+
+	actual := &tempopb.SearchResponse{}
+	fromHTTPResponse(t, resp, actual)
+
+	require.Equal(t, []*tempopb.SearchGroup{
+		{Attributes: byService("a", 0).Attributes, Traces: 2, Matched: 4},
+		{Attributes: byService("b", 0).Attributes, Traces: 1, Matched: 1},
+	}, actual.Groups)
+}
+
 func TestSearchResponseCombiner(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -287,11 +325,62 @@ func TestSearchResponseCombiner(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "200+200 partial",
+			response1: toHTTPResponse(t, &tempopb.SearchResponse{
+				Traces: []*tempopb.TraceSearchMetadata{
+					{
+						TraceID:           "1234",
+						StartTimeUnixNano: 1,
+					},
+				},
+				Metrics: &tempopb.SearchMetrics{
+					InspectedBytes: 3,
+				},
+				Partial: true,
+				ShardErrors: []*tempopb.SearchShardError{
+					{
+						BlockID:       "block",
+						StartPage:     1,
+						PagesToSearch: 2,
+						Error:         "context deadline exceeded",
+					},
+				},
+			}, 200),
+			response2: toHTTPResponse(t, &tempopb.SearchResponse{
+				Metrics: &tempopb.SearchMetrics{
+					InspectedBytes: 7,
+				},
+			}, 200),
+			expectedStatus: 200,
+			expectedResponse: &tempopb.SearchResponse{
+				Traces: []*tempopb.TraceSearchMetadata{
+					{
+						TraceID:           "1234",
+						StartTimeUnixNano: 1,
+						RootServiceName:   search.RootSpanNotYetReceivedText,
+					},
+				},
+				Metrics: &tempopb.SearchMetrics{
+					InspectedBytes: 10,
+					CompletedJobs:  2,
+				},
+				Partial: true,
+				ShardErrors: []*tempopb.SearchShardError{
+					{
+						BlockID:       "block",
+						StartPage:     1,
+						PagesToSearch: 2,
+						Error:         "context deadline exceeded",
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			combiner := NewTypedSearch(20)
+			combiner := NewTypedSearch(20, false)
 
 			err := combiner.AddResponse(tc.response1)
 			require.NoError(t, err)
@@ -312,7 +401,7 @@ func TestSearchResponseCombiner(t *testing.T) {
 func TestSearchDiffsResults(t *testing.T) {
 	traceID := "traceID"
 
-	c := NewTypedSearch(10)
+	c := NewTypedSearch(10, false)
 	sr := toHTTPResponse(t, &tempopb.SearchResponse{
 		Traces: []*tempopb.TraceSearchMetadata{
 			{
@@ -420,7 +509,7 @@ func fromHTTPResponse(t *testing.T, r *http.Response, pb proto.Message) {
 }
 
 func TestCombinerDiffs(t *testing.T) {
-	combiner := NewTypedSearch(100)
+	combiner := NewTypedSearch(100, false)
 
 	// first request should be empty
 	resp, err := combiner.GRPCDiff()
@@ -563,7 +652,7 @@ func TestSearchCombinerDoesNotRace(t *testing.T) {
 	}
 
 	traceID := "1234"
-	combiner := NewTypedSearch(10)
+	combiner := NewTypedSearch(10, false)
 	i := 0
 	go concurrent(func() {
 		i++
@@ -8,27 +8,47 @@ import (
 	"github.com/go-kit/log"
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/grafana/tempo/modules/frontend/audit"
+	"github.com/grafana/tempo/modules/frontend/canary"
+	"github.com/grafana/tempo/modules/frontend/federation"
 	"github.com/grafana/tempo/modules/frontend/transport"
 	v1 "github.com/grafana/tempo/modules/frontend/v1"
+	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/usagestats"
+	"github.com/grafana/tempo/pkg/util"
 )
 
 var statVersion = usagestats.NewString("frontend_version")
 
 type Config struct {
-	Config                    v1.Config       `yaml:",inline"`
-	MaxRetries                int             `yaml:"max_retries,omitempty"`
-	Search                    SearchConfig    `yaml:"search"`
-	TraceByID                 TraceByIDConfig `yaml:"trace_by_id"`
-	Metrics                   MetricsConfig   `yaml:"metrics"`
-	MultiTenantQueriesEnabled bool            `yaml:"multi_tenant_queries_enabled"`
-	ResponseConsumers         int             `yaml:"response_consumers"`
+	Config                    v1.Config           `yaml:",inline"`
+	MaxRetries                int                 `yaml:"max_retries,omitempty"`
+	RetryBudget               int                 `yaml:"retry_budget,omitempty"`
+	FailingBlocks             FailingBlocksConfig `yaml:"failing_blocks"`
+	Search                    SearchConfig        `yaml:"search"`
+	TraceByID                 TraceByIDConfig     `yaml:"trace_by_id"`
+	Metrics                   MetricsConfig       `yaml:"metrics"`
+	MultiTenantQueriesEnabled bool                `yaml:"multi_tenant_queries_enabled"`
+	ResponseConsumers         int                 `yaml:"response_consumers"`
+
+	// JSONErrors writes the errors of failed http queries as json objects with a machine-readable code instead of
+	// plain text
+	JSONErrors bool `yaml:"json_errors"`
 
 	// the maximum time limit that tempo will work on an api request. this includes both
 	// grpc and http requests and applies to all "api" frontend query endpoints such as
 	// traceql, tag search, tag value search, trace by id and all streaming gRPC endpoints.
 	// 0 disables
 	APITimeout time.Duration `yaml:"api_timeout,omitempty"`
+
+	// Audit records every search, metrics and trace by id query to a sink
+	Audit audit.Config `yaml:"audit"`
+
+	// Canary replays a fraction of search, metrics and trace by id queries against a canary query path
+	Canary canary.Config `yaml:"canary"`
+
+	// Federation sends search, trace by id and tag queries to remote Tempo clusters and merges their results
+	Federation federation.Config `yaml:"federation"`
 }
 
 type SearchConfig struct {
@@ -48,12 +68,21 @@ type MetricsConfig struct {
 	SLO     SLOConfig               `yaml:",inline"`
 }
 
+// FailingBlocksConfig controls skipping the blocks whose sub-requests keep failing. Queries return partial
+// results without the skipped blocks.
+type FailingBlocksConfig struct {
+	// MaxFailures is the number of failed sub-requests of a block within SkipFor before it is skipped. 0 disables
+	// skipping.
+	MaxFailures int           `yaml:"max_failures,omitempty"`
+	SkipFor     time.Duration `yaml:"skip_for,omitempty"`
+}
+
 type SLOConfig struct {
 	DurationSLO        time.Duration `yaml:"duration_slo,omitempty"`
 	ThroughputBytesSLO float64       `yaml:"throughput_bytes_slo,omitempty"`
 }
 
-func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	slo := SLOConfig{
 		DurationSLO:        0,
 		ThroughputBytesSLO: 0,
@@ -61,7 +90,14 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 
 	cfg.Config.MaxOutstandingPerTenant = 2000
 	cfg.Config.MaxBatchSize = 5
+	cfg.Config.QuerierCircuitBreaker = v1.QuerierCircuitBreakerConfig{
+		MaxFailureRatio: 0.5,
+		MinJobs:         20,
+		Window:          time.Minute,
+		EjectFor:        30 * time.Second,
+	}
 	cfg.MaxRetries = 2
+	cfg.FailingBlocks.SkipFor = 10 * time.Minute
 	cfg.ResponseConsumers = 10
 	cfg.Search = SearchConfig{
 		Sharder: SearchSharderConfig{
@@ -73,6 +109,8 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 			ConcurrentRequests:    defaultConcurrentRequests,
 			TargetBytesPerRequest: defaultTargetBytesPerRequest,
 			IngesterShards:        1,
+			TagCacheMinTTL:        5 * time.Minute,
+			TagCacheMaxTTL:        24 * time.Hour,
 		},
 		SLO: slo,
 	}
@@ -94,13 +132,30 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 
 	// enable multi tenant queries by default
 	cfg.MultiTenantQueriesEnabled = true
+
+	f.BoolVar(&cfg.JSONErrors, util.PrefixConfig(prefix, "json-errors"), false, "Return the errors of failed http queries as json objects with a machine-readable code.")
+
+	cfg.Audit.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "audit"), f)
+	cfg.Canary.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "canary"), f)
+	cfg.Federation.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "federation"), f)
+}
+
+// QueueLimits are the per tenant limits of the frontend queue. Shuffle sharding of queriers is disabled.
+type QueueLimits struct {
+	Overrides overrides.Interface
 }
 
-type CortexNoQuerierLimits struct{}
+var _ v1.Limits = (*QueueLimits)(nil)
 
-var _ v1.Limits = (*CortexNoQuerierLimits)(nil)
+func (QueueLimits) MaxQueriersPerUser(string) int { return 0 }
 
-func (CortexNoQuerierLimits) MaxQueriersPerUser(string) int { return 0 }
+func (l QueueLimits) MaxInflightRequestsPerUser(user string) int {
+	return l.Overrides.MaxInflightRequests(user)
+}
+
+func (l QueueLimits) QueueWeightPerUser(user string) int {
+	return l.Overrides.QueueWeight(user)
+}
 
 // InitFrontend initializes V1 frontend
 //
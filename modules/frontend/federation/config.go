@@ -0,0 +1,82 @@
+package federation
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/grafana/tempo/pkg/util"
+)
+
+const (
+	defaultTimeout          = 30 * time.Second
+	defaultMaxResponseBytes = 100 * 1024 * 1024
+)
+
+type Config struct {
+	Enabled bool `yaml:"enabled"`
+	// Remotes are the Tempo clusters queried in addition to the local backend.
+	Remotes []RemoteConfig `yaml:"remotes"`
+}
+
+type RemoteConfig struct {
+	// Name identifies the remote in logs and metrics.
+	Name string `yaml:"name"`
+	// Endpoint is the base URL of the query frontend of the remote cluster.
+	Endpoint string `yaml:"endpoint"`
+	// Tenants maps the tenants of queries to the tenants of the remote. Queries of tenants that aren't mapped keep
+	// their tenant.
+	Tenants map[string]string `yaml:"tenants,omitempty"`
+	// Headers are added to every request to the remote, e.g. for authentication.
+	Headers map[string]string `yaml:"headers,omitempty"`
+	// Timeout is the maximum time a query may take on the remote. Defaults to 30s.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// MaxResponseBytes is the maximum size of a response of the remote. Defaults to 100MiB.
+	MaxResponseBytes int `yaml:"max_response_bytes,omitempty"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, util.PrefixConfig(prefix, "enabled"), false, "Send search, trace by id and tag queries to remote Tempo clusters in addition to the local backend and merge the results.")
+}
+
+func (cfg *Config) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if len(cfg.Remotes) == 0 {
+		return errors.New("federation requires at least one remote")
+	}
+
+	names := make(map[string]struct{}, len(cfg.Remotes))
+	for _, r := range cfg.Remotes {
+		if r.Name == "" {
+			return errors.New("federation remote name is required")
+		}
+		if _, ok := names[r.Name]; ok {
+			return fmt.Errorf("federation remote %s is configured more than once", r.Name)
+		}
+		names[r.Name] = struct{}{}
+
+		// remotes are queried through the http api of their query frontends, grpc endpoints are not supported
+		u, err := url.Parse(r.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("federation remote %s endpoint must be an absolute http or https URL", r.Name)
+		}
+		if r.Timeout < 0 {
+			return fmt.Errorf("federation remote %s timeout must not be negative", r.Name)
+		}
+		if r.MaxResponseBytes < 0 {
+			return fmt.Errorf("federation remote %s max response bytes must not be negative", r.Name)
+		}
+		for local, remote := range r.Tenants {
+			if local == "" || remote == "" {
+				return fmt.Errorf("federation remote %s tenants must map a tenant to a tenant", r.Name)
+			}
+		}
+	}
+
+	return nil
+}
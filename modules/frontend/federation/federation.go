@@ -0,0 +1,398 @@
+package federation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/modules/frontend/combiner"
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+// HeaderFederated marks queries sent to remote clusters so a remote that federates queries itself doesn't send
+// them on.
+const HeaderFederated = "X-Tempo-Federated"
+
+// Ops that can be federated. The name of the op is used as a label of the metrics.
+const (
+	OpSearch    = "search"
+	OpTraceByID = "traces"
+	OpTags      = "tags"
+)
+
+const (
+	resultSuccess  = "success"
+	resultNotFound = "not_found"
+	resultFailed   = "failed"
+)
+
+var (
+	metricRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "query_frontend_federation_requests_total",
+		Help:      "Total number of queries sent to remote clusters by remote, op and result (success, not_found or failed).",
+	}, []string{"remote", "op", "result"})
+	metricDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tempo",
+		Name:      "query_frontend_federation_request_duration_seconds",
+		Help:      "Duration of queries sent to remote clusters.",
+		Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+	}, []string{"remote", "op"})
+)
+
+// Federator sends queries to remote Tempo clusters in addition to the local pipeline. The responses of the
+// remotes are translated into responses of the local queriers so the combiners of the local pipeline merge them.
+// A nil Federator federates nothing.
+type Federator struct {
+	remotes   []*remote
+	apiPrefix string
+	logger    log.Logger
+}
+
+type remote struct {
+	cfg              RemoteConfig
+	endpoint         string
+	client           *http.Client
+	maxResponseBytes int
+}
+
+func New(cfg Config, apiPrefix string, logger log.Logger) (*Federator, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	f := &Federator{
+		apiPrefix: apiPrefix,
+		logger:    logger,
+	}
+	for _, r := range cfg.Remotes {
+		timeout := r.Timeout
+		if timeout == 0 {
+			timeout = defaultTimeout
+		}
+
+		maxResponseBytes := r.MaxResponseBytes
+		if maxResponseBytes == 0 {
+			maxResponseBytes = defaultMaxResponseBytes
+		}
+
+		f.remotes = append(f.remotes, &remote{
+			cfg:              r,
+			endpoint:         strings.TrimSuffix(r.Endpoint, "/"),
+			client:           &http.Client{Timeout: timeout},
+			maxResponseBytes: maxResponseBytes,
+		})
+	}
+
+	return f, nil
+}
+
+// Middleware returns a middleware that sends the queries of the op to all remotes while the local pipeline runs
+// them. It must be the first middleware of the pipeline so the remotes receive the query as it was requested.
+func (f *Federator) Middleware(op string) pipeline.AsyncMiddleware[combiner.PipelineResponse] {
+	if f == nil {
+		return pipeline.NewNoopMiddleware()
+	}
+
+	var t translator
+	switch op {
+	case OpSearch:
+		t = searchTranslator{}
+	case OpTraceByID:
+		t = traceByIDTranslator{}
+	case OpTags:
+		t = tagsTranslator{}
+	default:
+		panic("unsupported federation op " + op)
+	}
+
+	return pipeline.AsyncMiddlewareFunc[combiner.PipelineResponse](func(next pipeline.AsyncRoundTripper[combiner.PipelineResponse]) pipeline.AsyncRoundTripper[combiner.PipelineResponse] {
+		return &federatedRoundTripper{
+			f:          f,
+			op:         op,
+			translator: t,
+			next:       next,
+		}
+	})
+}
+
+type federatedRoundTripper struct {
+	f          *Federator
+	op         string
+	translator translator
+	next       pipeline.AsyncRoundTripper[combiner.PipelineResponse]
+}
+
+func (rt *federatedRoundTripper) RoundTrip(req *http.Request) (pipeline.Responses[combiner.PipelineResponse], error) {
+	if req.Header.Get(HeaderFederated) != "" {
+		return rt.next.RoundTrip(req)
+	}
+
+	// the remote requests are built before the local pipeline modifies the request
+	reqs := make([]*http.Request, 0, len(rt.f.remotes)+1)
+	reqs = append(reqs, req)
+	remotes := make(map[*http.Request]*remote, len(rt.f.remotes))
+	for _, r := range rt.f.remotes {
+		remoteReq, err := rt.f.remoteRequest(r, req, rt.translator.accept())
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, remoteReq)
+		remotes[remoteReq] = r
+	}
+
+	return pipeline.NewAsyncSharderFunc(req.Context(), 0, len(reqs), func(i int) *http.Request {
+		return reqs[i]
+	}, pipeline.AsyncRoundTripperFunc[combiner.PipelineResponse](func(r *http.Request) (pipeline.Responses[combiner.PipelineResponse], error) {
+		if r == req {
+			return rt.next.RoundTrip(r)
+		}
+		return rt.roundTripRemote(remotes[r], r)
+	})), nil
+}
+
+// remoteRequest builds the request of the query for the remote. Only the tenant and the configured headers of
+// the remote are sent, the headers of the query are not.
+func (f *Federator) remoteRequest(r *remote, req *http.Request, accept string) (*http.Request, error) {
+	uri := strings.TrimPrefix(req.URL.Path, f.apiPrefix)
+	if req.URL.RawQuery != "" {
+		uri += "?" + req.URL.RawQuery
+	}
+
+	remoteReq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, r.endpoint+uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for federation remote %s: %w", r.cfg.Name, err)
+	}
+
+	tenant, _ := user.ExtractOrgID(req.Context())
+	if remoteTenant, ok := r.cfg.Tenants[tenant]; ok {
+		tenant = remoteTenant
+	}
+	if tenant != "" {
+		remoteReq.Header.Set(user.OrgIDHeaderName, tenant)
+	}
+	for k, v := range r.cfg.Headers {
+		remoteReq.Header.Set(k, v)
+	}
+	remoteReq.Header.Set(api.HeaderAccept, accept)
+	remoteReq.Header.Set(HeaderFederated, "true")
+
+	return remoteReq, nil
+}
+
+func (rt *federatedRoundTripper) roundTripRemote(r *remote, req *http.Request) (pipeline.Responses[combiner.PipelineResponse], error) {
+	start := time.Now()
+	status, body, err := r.do(req)
+	metricDuration.WithLabelValues(r.cfg.Name, rt.op).Observe(time.Since(start).Seconds())
+
+	// the query was canceled, there is nobody waiting for the response
+	if ctxErr := req.Context().Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+
+	var resps []*http.Response
+	switch {
+	case err != nil:
+	case status == http.StatusNotFound && rt.translator.allowNotFound():
+		metricRequests.WithLabelValues(r.cfg.Name, rt.op, resultNotFound).Inc()
+		return newResponses(nil), nil
+	case status != http.StatusOK:
+		err = fmt.Errorf("unexpected status code %d: %s", status, body)
+	default:
+		resps, err = rt.translator.translate(body)
+	}
+
+	if err != nil {
+		metricRequests.WithLabelValues(r.cfg.Name, rt.op, resultFailed).Inc()
+		level.Warn(rt.f.logger).Log("msg", "federated query failed", "remote", r.cfg.Name, "op", rt.op, "uri", req.URL.RequestURI(), "err", err)
+		return newResponses(rt.translator.failed(r.cfg.Name, err)), nil
+	}
+
+	metricRequests.WithLabelValues(r.cfg.Name, rt.op, resultSuccess).Inc()
+	return newResponses(resps), nil
+}
+
+func (r *remote) do(req *http.Request) (int, []byte, error) {
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(r.maxResponseBytes)+1))
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(body) > r.maxResponseBytes {
+		return 0, nil, fmt.Errorf("response exceeds the maximum size of %d bytes", r.maxResponseBytes)
+	}
+	return resp.StatusCode, body, nil
+}
+
+// translator turns the responses of remote query frontends into responses of the local queriers.
+type translator interface {
+	// accept is the format the remote is asked to respond in
+	accept() string
+	// allowNotFound returns true if a 404 of the remote is a valid empty result
+	allowNotFound() bool
+	translate(body []byte) ([]*http.Response, error)
+	// failed returns the responses that stand in for the results of a remote that failed
+	failed(remote string, err error) []*http.Response
+}
+
+// searchTranslator turns the search response of a remote into a single job. The first response carries the
+// totals of the job and the second one the results, the same way the search sharder and the queriers do.
+type searchTranslator struct{}
+
+func (searchTranslator) accept() string { return api.HeaderAcceptJSON }
+
+func (searchTranslator) allowNotFound() bool { return false }
+
+func (searchTranslator) translate(body []byte) ([]*http.Response, error) {
+	resp := &tempopb.SearchResponse{}
+	if err := (&jsonpb.Unmarshaler{AllowUnknownFields: true}).Unmarshal(bytes.NewReader(body), resp); err != nil {
+		return nil, fmt.Errorf("error unmarshalling search response: %w", err)
+	}
+
+	totals := &tempopb.SearchResponse{Metrics: &tempopb.SearchMetrics{TotalJobs: 1}}
+	results := &tempopb.SearchResponse{
+		Traces:      resp.Traces,
+		Partial:     resp.Partial,
+		ShardErrors: resp.ShardErrors,
+		Metrics:     &tempopb.SearchMetrics{},
+	}
+	if resp.Metrics != nil {
+		totals.Metrics.TotalBlocks = resp.Metrics.TotalBlocks
+		totals.Metrics.TotalBlockBytes = resp.Metrics.TotalBlockBytes
+		results.Metrics.InspectedTraces = resp.Metrics.InspectedTraces
+		results.Metrics.InspectedBytes = resp.Metrics.InspectedBytes
+	}
+
+	return jsonResponses(totals, results)
+}
+
+// failed marks the search partial, the results of the remote are missing
+func (searchTranslator) failed(remote string, err error) []*http.Response {
+	resps, _ := jsonResponses(
+		&tempopb.SearchResponse{Metrics: &tempopb.SearchMetrics{TotalJobs: 1}},
+		&tempopb.SearchResponse{
+			Partial:     true,
+			ShardErrors: []*tempopb.SearchShardError{{Error: fmt.Sprintf("federation remote %s: %s", remote, err)}},
+			Metrics:     &tempopb.SearchMetrics{},
+		},
+	)
+	return resps
+}
+
+// traceByIDTranslator wraps the trace returned by a remote like the queriers do.
+type traceByIDTranslator struct{}
+
+func (traceByIDTranslator) accept() string { return api.HeaderAcceptProtobuf }
+
+func (traceByIDTranslator) allowNotFound() bool { return true }
+
+func (traceByIDTranslator) translate(body []byte) ([]*http.Response, error) {
+	trace := &tempopb.Trace{}
+	if err := proto.Unmarshal(body, trace); err != nil {
+		return nil, fmt.Errorf("error unmarshalling trace: %w", err)
+	}
+
+	b, err := proto.Marshal(&tempopb.TraceByIDResponse{Trace: trace})
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Response{{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{api.HeaderContentType: {api.HeaderAcceptProtobuf}},
+		Body:       io.NopCloser(bytes.NewReader(b)),
+	}}, nil
+}
+
+// failed returns an error, the trace by id response has no way to mark the trace partial
+func (traceByIDTranslator) failed(remote string, err error) []*http.Response {
+	return []*http.Response{{
+		StatusCode: http.StatusInternalServerError,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(fmt.Sprintf("federation remote %s: %s", remote, err))),
+	}}
+}
+
+// tagsTranslator passes tag names and values through, they have the same format as the responses of the
+// queriers.
+type tagsTranslator struct{}
+
+func (tagsTranslator) accept() string { return api.HeaderAcceptJSON }
+
+func (tagsTranslator) allowNotFound() bool { return true }
+
+func (tagsTranslator) translate(body []byte) ([]*http.Response, error) {
+	return []*http.Response{{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{api.HeaderContentType: {api.HeaderAcceptJSON}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}}, nil
+}
+
+// failed returns nothing, the tags of the remote are missing
+func (tagsTranslator) failed(string, error) []*http.Response { return nil }
+
+func jsonResponses(msgs ...proto.Message) ([]*http.Response, error) {
+	m := &jsonpb.Marshaler{}
+	resps := make([]*http.Response, 0, len(msgs))
+	for _, msg := range msgs {
+		s, err := m.MarshalToString(msg)
+		if err != nil {
+			return nil, err
+		}
+		resps = append(resps, &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{api.HeaderContentType: {api.HeaderAcceptJSON}},
+			Body:       io.NopCloser(strings.NewReader(s)),
+		})
+	}
+	return resps, nil
+}
+
+// responses returns the http responses one after the other
+type responses []*http.Response
+
+func newResponses(resps []*http.Response) *responses {
+	r := responses(resps)
+	return &r
+}
+
+func (r *responses) Next(ctx context.Context) (combiner.PipelineResponse, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, true, err
+	}
+	if len(*r) == 0 {
+		return nil, true, nil
+	}
+
+	resp := (*r)[0]
+	*r = (*r)[1:]
+	return remoteResponse{r: resp}, len(*r) == 0, nil
+}
+
+type remoteResponse struct {
+	r *http.Response
+}
+
+func (r remoteResponse) HTTPResponse() *http.Response { return r.r }
+
+func (r remoteResponse) AdditionalData() any { return nil }
@@ -0,0 +1,356 @@
+package federation
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/frontend/combiner"
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    Config
+		expErr bool
+	}{
+		{
+			name: "disabled",
+			cfg:  Config{},
+		},
+		{
+			name:   "no remotes",
+			cfg:    Config{Enabled: true},
+			expErr: true,
+		},
+		{
+			name: "valid",
+			cfg:  Config{Enabled: true, Remotes: []RemoteConfig{{Name: "a", Endpoint: "http://a:3200"}, {Name: "b", Endpoint: "https://b/tempo"}}},
+		},
+		{
+			name:   "missing name",
+			cfg:    Config{Enabled: true, Remotes: []RemoteConfig{{Endpoint: "http://a:3200"}}},
+			expErr: true,
+		},
+		{
+			name:   "duplicate name",
+			cfg:    Config{Enabled: true, Remotes: []RemoteConfig{{Name: "a", Endpoint: "http://a:3200"}, {Name: "a", Endpoint: "http://b:3200"}}},
+			expErr: true,
+		},
+		{
+			name:   "relative endpoint",
+			cfg:    Config{Enabled: true, Remotes: []RemoteConfig{{Name: "a", Endpoint: "a:3200"}}},
+			expErr: true,
+		},
+		{
+			name:   "grpc endpoint",
+			cfg:    Config{Enabled: true, Remotes: []RemoteConfig{{Name: "a", Endpoint: "grpc://a:9095"}}},
+			expErr: true,
+		},
+		{
+			name:   "unsupported scheme",
+			cfg:    Config{Enabled: true, Remotes: []RemoteConfig{{Name: "a", Endpoint: "ftp://a"}}},
+			expErr: true,
+		},
+		{
+			name:   "empty remote tenant",
+			cfg:    Config{Enabled: true, Remotes: []RemoteConfig{{Name: "a", Endpoint: "http://a:3200", Tenants: map[string]string{"a": ""}}}},
+			expErr: true,
+		},
+		{
+			name:   "negative max response bytes",
+			cfg:    Config{Enabled: true, Remotes: []RemoteConfig{{Name: "a", Endpoint: "http://a:3200", MaxResponseBytes: -1}}},
+			expErr: true,
+		},
+		{
+			name:   "negative timeout",
+			cfg:    Config{Enabled: true, Remotes: []RemoteConfig{{Name: "a", Endpoint: "http://a:3200", Timeout: -1}}},
+			expErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSearchFederation(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- r
+		writeJSON(t, w, &tempopb.SearchResponse{
+			Traces: []*tempopb.TraceSearchMetadata{{TraceID: "remote", StartTimeUnixNano: 2}},
+			Metrics: &tempopb.SearchMetrics{
+				InspectedTraces: 10,
+				InspectedBytes:  100,
+				TotalBlocks:     3,
+				TotalJobs:       5,
+				CompletedJobs:   5,
+				TotalBlockBytes: 1000,
+			},
+		})
+	}))
+	defer ok.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	f, err := New(Config{Enabled: true, Remotes: []RemoteConfig{
+		{Name: "ok", Endpoint: ok.URL + "/remote/", Tenants: map[string]string{"local-tenant": "remote-tenant"}, Headers: map[string]string{"Authorization": "Bearer x"}},
+		{Name: "failing", Endpoint: failing.URL},
+	}}, "/tempo", log.NewNopLogger())
+	require.NoError(t, err)
+
+	local := pipeline.AsyncRoundTripperFunc[combiner.PipelineResponse](func(*http.Request) (pipeline.Responses[combiner.PipelineResponse], error) {
+		return pipeline.NewHTTPToAsyncResponse(jsonResponse(t, &tempopb.SearchResponse{
+			Traces:  []*tempopb.TraceSearchMetadata{{TraceID: "local", StartTimeUnixNano: 1}},
+			Metrics: &tempopb.SearchMetrics{InspectedTraces: 1, InspectedBytes: 10},
+		})), nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/tempo/api/search?q=%7B%7D&limit=10", nil)
+	req.Header.Set("Authorization", "Bearer local")
+	req = req.WithContext(user.InjectOrgID(req.Context(), "local-tenant"))
+
+	c := combiner.NewSearch(10, false)
+	roundTrip(t, f.Middleware(OpSearch).Wrap(local), req, c)
+
+	remoteReq := <-requests
+	require.Equal(t, "/remote/api/search", remoteReq.URL.Path)
+	require.Equal(t, "{}", remoteReq.URL.Query().Get("q"))
+	require.Equal(t, "remote-tenant", remoteReq.Header.Get(user.OrgIDHeaderName))
+	require.Equal(t, "Bearer x", remoteReq.Header.Get("Authorization"))
+	require.Equal(t, "true", remoteReq.Header.Get(HeaderFederated))
+
+	resp, err := c.HTTPFinal()
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	final := &tempopb.SearchResponse{}
+	require.NoError(t, jsonpb.Unmarshal(resp.Body, final))
+	require.Len(t, final.Traces, 2)
+	require.Equal(t, "remote", final.Traces[0].TraceID)
+	require.Equal(t, "local", final.Traces[1].TraceID)
+
+	// each remote is a single job, the failing remote makes the results partial
+	require.True(t, final.Partial)
+	require.Len(t, final.ShardErrors, 1)
+	require.Contains(t, final.ShardErrors[0].Error, "federation remote failing")
+	require.Equal(t, uint32(2), final.Metrics.TotalJobs)
+	require.Equal(t, uint32(3), final.Metrics.CompletedJobs)
+	require.Equal(t, uint32(3), final.Metrics.TotalBlocks)
+	require.Equal(t, uint64(1000), final.Metrics.TotalBlockBytes)
+	require.Equal(t, uint32(11), final.Metrics.InspectedTraces)
+	require.Equal(t, uint64(110), final.Metrics.InspectedBytes)
+}
+
+func TestTraceByIDFederation(t *testing.T) {
+	trace := test.MakeTrace(2, nil)
+
+	found := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(api.HeaderAccept) != api.HeaderAcceptProtobuf {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		b, _ := proto.Marshal(trace)
+		_, _ = w.Write(b)
+	}))
+	defer found.Close()
+
+	notFound := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer notFound.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	// the trace is only in the remote cluster
+	local := pipeline.AsyncRoundTripperFunc[combiner.PipelineResponse](func(*http.Request) (pipeline.Responses[combiner.PipelineResponse], error) {
+		return pipeline.NewHTTPToAsyncResponse(&http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}), nil
+	})
+
+	traceByID := func(remotes ...RemoteConfig) *http.Response {
+		f, err := New(Config{Enabled: true, Remotes: remotes}, "", log.NewNopLogger())
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/traces/1234", nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), "tenant"))
+
+		c := combiner.NewTraceByID(0, api.HeaderAcceptProtobuf)
+		roundTrip(t, f.Middleware(OpTraceByID).Wrap(local), req, c)
+
+		resp, err := c.HTTPFinal()
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := traceByID(
+		RemoteConfig{Name: "found", Endpoint: found.URL},
+		RemoteConfig{Name: "not-found", Endpoint: notFound.URL},
+	)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	actual := &tempopb.Trace{}
+	require.NoError(t, proto.Unmarshal(b, actual))
+	require.True(t, proto.Equal(trace, actual))
+
+	// the spans of a failing remote may be missing, the query fails instead of returning an incomplete trace
+	resp = traceByID(
+		RemoteConfig{Name: "found", Endpoint: found.URL},
+		RemoteConfig{Name: "failing", Endpoint: failing.URL},
+	)
+	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	b, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(b), "federation remote failing")
+}
+
+func TestRemoteTenants(t *testing.T) {
+	f, err := New(Config{Enabled: true, Remotes: []RemoteConfig{
+		{Name: "remote", Endpoint: "http://remote", Tenants: map[string]string{"a": "remote-a"}},
+	}}, "", log.NewNopLogger())
+	require.NoError(t, err)
+
+	for local, expected := range map[string]string{"a": "remote-a", "b": "b"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/search/tags", nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), local))
+
+		remoteReq, err := f.remoteRequest(f.remotes[0], req, api.HeaderAcceptJSON)
+		require.NoError(t, err)
+		require.Equal(t, expected, remoteReq.Header.Get(user.OrgIDHeaderName))
+	}
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(t, w, &tempopb.SearchResponse{
+			Traces: []*tempopb.TraceSearchMetadata{{TraceID: strings.Repeat("a", 100)}},
+		})
+	}))
+	defer remote.Close()
+
+	f, err := New(Config{Enabled: true, Remotes: []RemoteConfig{{Name: "remote", Endpoint: remote.URL, MaxResponseBytes: 50}}}, "", log.NewNopLogger())
+	require.NoError(t, err)
+
+	local := pipeline.AsyncRoundTripperFunc[combiner.PipelineResponse](func(*http.Request) (pipeline.Responses[combiner.PipelineResponse], error) {
+		return pipeline.NewHTTPToAsyncResponse(jsonResponse(t, &tempopb.SearchResponse{Metrics: &tempopb.SearchMetrics{}})), nil
+	})
+
+	c := combiner.NewSearch(10, false)
+	roundTrip(t, f.Middleware(OpSearch).Wrap(local), httptest.NewRequest(http.MethodGet, "/api/search", nil), c)
+
+	resp, err := c.HTTPFinal()
+	require.NoError(t, err)
+
+	final := &tempopb.SearchResponse{}
+	require.NoError(t, jsonpb.Unmarshal(resp.Body, final))
+	require.Empty(t, final.Traces)
+	require.True(t, final.Partial)
+	require.Contains(t, final.ShardErrors[0].Error, "exceeds the maximum size of 50 bytes")
+}
+
+func TestFederatedQueriesAreNotSentOn(t *testing.T) {
+	remote := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("federated query sent on")
+	}))
+	defer remote.Close()
+
+	f, err := New(Config{Enabled: true, Remotes: []RemoteConfig{{Name: "remote", Endpoint: remote.URL}}}, "", log.NewNopLogger())
+	require.NoError(t, err)
+
+	local := pipeline.AsyncRoundTripperFunc[combiner.PipelineResponse](func(*http.Request) (pipeline.Responses[combiner.PipelineResponse], error) {
+		return pipeline.NewHTTPToAsyncResponse(jsonResponse(t, &tempopb.SearchTagsResponse{TagNames: []string{"local"}})), nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search/tags", nil)
+	req.Header.Set(HeaderFederated, "true")
+
+	c := combiner.NewSearchTags(0)
+	roundTrip(t, f.Middleware(OpTags).Wrap(local), req, c)
+
+	resp, err := c.HTTPFinal()
+	require.NoError(t, err)
+
+	final := &tempopb.SearchTagsResponse{}
+	require.NoError(t, jsonpb.Unmarshal(resp.Body, final))
+	require.Equal(t, []string{"local"}, final.TagNames)
+}
+
+func TestNilFederator(t *testing.T) {
+	var f *Federator
+
+	called := false
+	local := pipeline.AsyncRoundTripperFunc[combiner.PipelineResponse](func(*http.Request) (pipeline.Responses[combiner.PipelineResponse], error) {
+		called = true
+		return pipeline.NewSuccessfulResponse(""), nil
+	})
+
+	_, err := f.Middleware(OpSearch).Wrap(local).RoundTrip(httptest.NewRequest(http.MethodGet, "/api/search", nil))
+	require.NoError(t, err)
+	require.True(t, called)
+}
+
+func roundTrip(t *testing.T, rt pipeline.AsyncRoundTripper[combiner.PipelineResponse], req *http.Request, c combiner.Combiner) {
+	resps, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+
+	for {
+		resp, done, err := resps.Next(context.Background())
+		require.NoError(t, err)
+		if resp != nil {
+			require.NoError(t, c.AddResponse(resp))
+		}
+		if done {
+			return
+		}
+	}
+}
+
+func jsonResponse(t *testing.T, msg proto.Message) *http.Response {
+	s, err := (&jsonpb.Marshaler{}).MarshalToString(msg)
+	require.NoError(t, err)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{api.HeaderContentType: {api.HeaderAcceptJSON}},
+		Body:       io.NopCloser(strings.NewReader(s)),
+	}
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, msg proto.Message) {
+	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+	if err := (&jsonpb.Marshaler{}).Marshal(w, msg); err != nil {
+		t.Error(err)
+	}
+}
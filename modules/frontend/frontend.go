@@ -14,7 +14,10 @@ import (
 	"github.com/grafana/dskit/user"
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/grafana/tempo/modules/frontend/audit"
+	"github.com/grafana/tempo/modules/frontend/canary"
 	"github.com/grafana/tempo/modules/frontend/combiner"
+	"github.com/grafana/tempo/modules/frontend/federation"
 	"github.com/grafana/tempo/modules/frontend/pipeline"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/api"
@@ -37,6 +40,8 @@ type (
 type QueryFrontend struct {
 	TraceByIDHandler, SearchHandler, MetricsSummaryHandler, MetricsQueryRangeHandler           http.Handler
 	SearchTagsHandler, SearchTagsV2Handler, SearchTagsValuesHandler, SearchTagsValuesV2Handler http.Handler
+	SearchV2Handler, MetricsQueryRangeV2Handler                                                http.Handler
+	TraceQLLintHandler, SearchTagsStatsHandler, MetricsQueryRangeDiffHandler                   http.Handler
 	cacheProvider                                                                              cache.Provider
 	streamingSearch                                                                            streamingSearchHandler
 	streamingTags                                                                              streamingTagsHandler
@@ -44,6 +49,8 @@ type QueryFrontend struct {
 	streamingTagValues                                                                         streamingTagValuesHandler
 	streamingTagValuesV2                                                                       streamingTagValuesV2Handler
 	streamingQueryRange                                                                        streamingQueryRangeHandler
+	auditor                                                                                    *audit.Auditor
+	mirror                                                                                     *canary.Mirror
 	logger                                                                                     log.Logger
 }
 
@@ -67,6 +74,10 @@ func New(cfg Config, next http.RoundTripper, o overrides.Interface, reader tempo
 		return nil, fmt.Errorf("query backend after should be less than or equal to query ingester until")
 	}
 
+	if cfg.Search.Sharder.TagCacheMaxTTL > 0 && cfg.Search.Sharder.TagCacheMaxTTL < cfg.Search.Sharder.TagCacheMinTTL {
+		return nil, fmt.Errorf("frontend search tag cache max ttl should be greater than or equal to tag cache min ttl")
+	}
+
 	if cfg.Metrics.Sharder.ConcurrentRequests <= 0 {
 		return nil, fmt.Errorf("frontend metrics concurrent requests should be greater than 0")
 	}
@@ -79,44 +90,76 @@ func New(cfg Config, next http.RoundTripper, o overrides.Interface, reader tempo
 		return nil, fmt.Errorf("frontend metrics interval should be greater than 0")
 	}
 
-	retryWare := pipeline.NewRetryWare(cfg.MaxRetries, registerer)
+	var auditor *audit.Auditor
+	if cfg.Audit.Enabled {
+		var err error
+		auditor, err = audit.New(cfg.Audit, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create query auditor: %w", err)
+		}
+	}
+
+	var mirror *canary.Mirror
+	if cfg.Canary.Enabled {
+		var err error
+		mirror, err = canary.New(cfg.Canary, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create canary mirror: %w", err)
+		}
+	}
+
+	var federator *federation.Federator
+	if cfg.Federation.Enabled {
+		var err error
+		federator, err = federation.New(cfg.Federation, apiPrefix, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create query federation: %w", err)
+		}
+	}
+
+	retryWare := pipeline.NewRetryWare(cfg.MaxRetries, cfg.RetryBudget, registerer)
+	failingBlocksWare := pipeline.NewFailingBlocksWare(cfg.FailingBlocks.MaxFailures, cfg.FailingBlocks.SkipFor, registerer)
 	cacheWare := pipeline.NewCachingWare(cacheProvider, cache.RoleFrontendSearch, logger)
 	statusCodeWare := pipeline.NewStatusCodeAdjustWare()
 	traceIDStatusCodeWare := pipeline.NewStatusCodeAdjustWareWithAllowedCode(http.StatusNotFound)
 
 	tracePipeline := pipeline.Build(
 		[]pipeline.AsyncMiddleware[combiner.PipelineResponse]{
+			federator.Middleware(federation.OpTraceByID),
 			multiTenantMiddleware(cfg, logger),
-			newAsyncTraceIDSharder(&cfg.TraceByID, logger),
+			newAsyncTraceIDSharder(reader, &cfg.TraceByID, logger),
 		},
 		[]pipeline.Middleware{traceIDStatusCodeWare, retryWare},
 		next)
 
 	searchPipeline := pipeline.Build(
 		[]pipeline.AsyncMiddleware[combiner.PipelineResponse]{
+			federator.Middleware(federation.OpSearch),
 			multiTenantMiddleware(cfg, logger),
 			newAsyncSearchSharder(reader, o, cfg.Search.Sharder, logger),
 		},
-		[]pipeline.Middleware{cacheWare, statusCodeWare, retryWare},
+		[]pipeline.Middleware{cacheWare, statusCodeWare, failingBlocksWare, retryWare},
 		next)
 
 	searchTagsPipeline := pipeline.Build(
 		[]pipeline.AsyncMiddleware[combiner.PipelineResponse]{
+			federator.Middleware(federation.OpTags),
 			multiTenantMiddleware(cfg, logger),
 			newAsyncTagSharder(reader, o, cfg.Search.Sharder, parseTagsRequest, logger),
 		},
-		[]pipeline.Middleware{cacheWare, statusCodeWare, retryWare},
+		[]pipeline.Middleware{cacheWare, statusCodeWare, failingBlocksWare, retryWare},
 		next)
 
 	searchTagValuesPipeline := pipeline.Build(
 		[]pipeline.AsyncMiddleware[combiner.PipelineResponse]{
+			federator.Middleware(federation.OpTags),
 			multiTenantMiddleware(cfg, logger),
 			newAsyncTagSharder(reader, o, cfg.Search.Sharder, parseTagValuesRequest, logger),
 		},
-		[]pipeline.Middleware{cacheWare, statusCodeWare, retryWare},
+		[]pipeline.Middleware{cacheWare, statusCodeWare, failingBlocksWare, retryWare},
 		next)
 
-	// metrics summary
+	// metrics summary and tag stats
 	metricsPipeline := pipeline.Build(
 		[]pipeline.AsyncMiddleware[combiner.PipelineResponse]{
 			multiTenantUnsupportedMiddleware(cfg, logger),
@@ -130,42 +173,58 @@ func New(cfg Config, next http.RoundTripper, o overrides.Interface, reader tempo
 			multiTenantMiddleware(cfg, logger),
 			newAsyncQueryRangeSharder(reader, o, cfg.Metrics.Sharder, logger),
 		},
-		[]pipeline.Middleware{cacheWare, statusCodeWare, retryWare},
+		[]pipeline.Middleware{cacheWare, statusCodeWare, failingBlocksWare, retryWare},
 		next)
 
-	traces := newTraceIDHandler(cfg, o, tracePipeline, logger)
-	search := newSearchHTTPHandler(cfg, searchPipeline, logger)
+	traces := newTraceIDHandler(cfg, o, tracePipeline, auditor, mirror, logger)
+	search := newSearchHTTPHandler(cfg, searchPipeline, auditor, mirror, logger)
 	searchTags := newTagHTTPHandler(cfg, searchTagsPipeline, o, combiner.NewSearchTags, logger)
 	searchTagsV2 := newTagHTTPHandler(cfg, searchTagsPipeline, o, combiner.NewSearchTagsV2, logger)
 	searchTagValues := newTagHTTPHandler(cfg, searchTagValuesPipeline, o, combiner.NewSearchTagValues, logger)
 	searchTagValuesV2 := newTagHTTPHandler(cfg, searchTagValuesPipeline, o, combiner.NewSearchTagValuesV2, logger)
 	metrics := newMetricsSummaryHandler(metricsPipeline, logger)
-	queryrange := newMetricsQueryRangeHTTPHandler(cfg, queryRangePipeline, logger)
+	tagStats := newTagStatsHandler(metricsPipeline, logger)
+	queryrange := newMetricsQueryRangeHTTPHandler(cfg, queryRangePipeline, o, auditor, mirror, logger)
 
 	return &QueryFrontend{
 		// http/discrete
-		TraceByIDHandler:          newHandler(cfg.Config.LogQueryRequestHeaders, traces, logger),
-		SearchHandler:             newHandler(cfg.Config.LogQueryRequestHeaders, search, logger),
-		SearchTagsHandler:         newHandler(cfg.Config.LogQueryRequestHeaders, searchTags, logger),
-		SearchTagsV2Handler:       newHandler(cfg.Config.LogQueryRequestHeaders, searchTagsV2, logger),
-		SearchTagsValuesHandler:   newHandler(cfg.Config.LogQueryRequestHeaders, searchTagValues, logger),
-		SearchTagsValuesV2Handler: newHandler(cfg.Config.LogQueryRequestHeaders, searchTagValuesV2, logger),
-		MetricsSummaryHandler:     newHandler(cfg.Config.LogQueryRequestHeaders, metrics, logger),
-		MetricsQueryRangeHandler:  newHandler(cfg.Config.LogQueryRequestHeaders, queryrange, logger),
+		TraceByIDHandler:          newHandler(cfg.Config.LogQueryRequestHeaders, cfg.JSONErrors, traces, logger),
+		SearchHandler:             newHandler(cfg.Config.LogQueryRequestHeaders, cfg.JSONErrors, search, logger),
+		SearchTagsHandler:         newHandler(cfg.Config.LogQueryRequestHeaders, cfg.JSONErrors, searchTags, logger),
+		SearchTagsV2Handler:       newHandler(cfg.Config.LogQueryRequestHeaders, cfg.JSONErrors, searchTagsV2, logger),
+		SearchTagsValuesHandler:   newHandler(cfg.Config.LogQueryRequestHeaders, cfg.JSONErrors, searchTagValues, logger),
+		SearchTagsValuesV2Handler: newHandler(cfg.Config.LogQueryRequestHeaders, cfg.JSONErrors, searchTagValuesV2, logger),
+		MetricsSummaryHandler:     newHandler(cfg.Config.LogQueryRequestHeaders, cfg.JSONErrors, metrics, logger),
+		MetricsQueryRangeHandler:  newHandler(cfg.Config.LogQueryRequestHeaders, cfg.JSONErrors, queryrange, logger),
+		SearchTagsStatsHandler:    newHandler(cfg.Config.LogQueryRequestHeaders, cfg.JSONErrors, tagStats, logger),
+
+		SearchV2Handler:            newHandler(cfg.Config.LogQueryRequestHeaders, cfg.JSONErrors, newSearchV2Handler(search), logger),
+		MetricsQueryRangeV2Handler: newHandler(cfg.Config.LogQueryRequestHeaders, cfg.JSONErrors, newMetricsQueryRangeV2Handler(queryrange), logger),
+		TraceQLLintHandler:         newHandler(cfg.Config.LogQueryRequestHeaders, cfg.JSONErrors, newTraceQLLintHandler(cfg, reader, o, logger), logger),
+
+		MetricsQueryRangeDiffHandler: newHandler(cfg.Config.LogQueryRequestHeaders, cfg.JSONErrors, newMetricsQueryRangeDiffHandler(queryrange, logger), logger),
 
 		// grpc/streaming
-		streamingSearch:      newSearchStreamingGRPCHandler(cfg, searchPipeline, apiPrefix, logger),
+		streamingSearch:      newSearchStreamingGRPCHandler(cfg, searchPipeline, apiPrefix, auditor, logger),
 		streamingTags:        newTagStreamingGRPCHandler(cfg, searchTagsPipeline, apiPrefix, o, logger),
 		streamingTagsV2:      newTagV2StreamingGRPCHandler(cfg, searchTagsPipeline, apiPrefix, o, logger),
 		streamingTagValues:   newTagValuesStreamingGRPCHandler(cfg, searchTagValuesPipeline, apiPrefix, o, logger),
 		streamingTagValuesV2: newTagValuesV2StreamingGRPCHandler(cfg, searchTagValuesPipeline, apiPrefix, o, logger),
-		streamingQueryRange:  newQueryRangeStreamingGRPCHandler(cfg, queryRangePipeline, apiPrefix, logger),
+		streamingQueryRange:  newQueryRangeStreamingGRPCHandler(cfg, queryRangePipeline, apiPrefix, o, auditor, logger),
 
 		cacheProvider: cacheProvider,
+		auditor:       auditor,
+		mirror:        mirror,
 		logger:        logger,
 	}, nil
 }
 
+// Stop flushes any queued audit records and drops pending canary replays
+func (q *QueryFrontend) Stop() {
+	q.auditor.Stop()
+	q.mirror.Stop()
+}
+
 // Search implements StreamingQuerierServer interface for streaming search
 func (q *QueryFrontend) Search(req *tempopb.SearchRequest, srv tempopb.StreamingQuerier_SearchServer) error {
 	return q.streamingSearch(req, srv)
@@ -193,10 +252,21 @@ func (q *QueryFrontend) MetricsQueryRange(req *tempopb.QueryRangeRequest, srv te
 
 // newSpanMetricsMiddleware creates a new frontend middleware to handle metrics-generator requests.
 func newMetricsSummaryHandler(next pipeline.AsyncRoundTripper[combiner.PipelineResponse], logger log.Logger) http.RoundTripper {
+	return newSingleQuerierHandler(next, "metrics summary", logger)
+}
+
+// newTagStatsHandler creates a new frontend middleware to handle tag stats requests. The stats of all blocks are
+// collected by a single querier so the distinct values of the tags don't have to be sent to the frontend.
+func newTagStatsHandler(next pipeline.AsyncRoundTripper[combiner.PipelineResponse], logger log.Logger) http.RoundTripper {
+	return newSingleQuerierHandler(next, "tag stats", logger)
+}
+
+// newSingleQuerierHandler passes the request to a single querier and returns its response
+func newSingleQuerierHandler(next pipeline.AsyncRoundTripper[combiner.PipelineResponse], op string, logger log.Logger) http.RoundTripper {
 	return pipeline.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		tenant, err := user.ExtractOrgID(req.Context())
 		if err != nil {
-			level.Error(logger).Log("msg", "metrics summary: failed to extract tenant id", "err", err)
+			level.Error(logger).Log("msg", op+": failed to extract tenant id", "err", err)
 			return &http.Response{
 				StatusCode: http.StatusBadRequest,
 				Status:     http.StatusText(http.StatusBadRequest),
@@ -206,7 +276,7 @@ func newMetricsSummaryHandler(next pipeline.AsyncRoundTripper[combiner.PipelineR
 		prepareRequestForQueriers(req, tenant, req.RequestURI, nil)
 
 		level.Info(logger).Log(
-			"msg", "metrics summary request",
+			"msg", op+" request",
 			"tenant", tenant,
 			"path", req.URL.Path)
 
@@ -215,10 +285,10 @@ func newMetricsSummaryHandler(next pipeline.AsyncRoundTripper[combiner.PipelineR
 			return nil, err
 		}
 
-		resp, _, err := resps.Next(req.Context()) // metrics path will only ever have one response
+		resp, _, err := resps.Next(req.Context()) // only ever has one response
 
 		level.Info(logger).Log(
-			"msg", "search tag response",
+			"msg", op+" response",
 			"tenant", tenant,
 			"path", req.URL.Path,
 			"err", err)
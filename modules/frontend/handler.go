@@ -1,6 +1,7 @@
 package frontend
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -17,6 +18,7 @@ import (
 	"github.com/grafana/dskit/user"
 	"github.com/opentracing/opentracing-go"
 
+	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/util/tracing"
 )
 
@@ -39,14 +41,16 @@ type handler struct {
 	roundTripper           http.RoundTripper
 	logger                 log.Logger
 	logQueryRequestHeaders flagext.StringSliceCSV
+	jsonErrors             bool
 }
 
 // newHandler creates a handler
-func newHandler(LogQueryRequestHeaders flagext.StringSliceCSV, rt http.RoundTripper, logger log.Logger) http.Handler {
+func newHandler(LogQueryRequestHeaders flagext.StringSliceCSV, jsonErrors bool, rt http.RoundTripper, logger log.Logger) http.Handler {
 	return &handler{
 		logQueryRequestHeaders: LogQueryRequestHeaders,
 		roundTripper:           rt,
 		logger:                 logger,
+		jsonErrors:             jsonErrors,
 	}
 }
 
@@ -67,6 +71,13 @@ func (f *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		span.SetTag("orgID", orgID)
 	}
 
+	// the queriers write their errors as json if the request asks for it
+	if f.jsonErrors {
+		r.Header.Set(api.HeaderJSONErrors, "true")
+	} else {
+		r.Header.Del(api.HeaderJSONErrors)
+	}
+
 	resp, err := f.roundTripper.RoundTrip(r)
 	elapsed := time.Since(start)
 
@@ -83,7 +94,7 @@ func (f *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		err = writeError(w, err)
+		err = writeError(w, err, f.jsonErrors)
 		logMessage = append(
 			logMessage,
 			"status", statusCode,
@@ -96,7 +107,7 @@ func (f *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	if resp == nil {
 		statusCode := http.StatusInternalServerError
-		err = writeError(w, errors.New(NilResponseError))
+		err = writeError(w, errors.New(NilResponseError), f.jsonErrors)
 		logMessage = append(
 			logMessage,
 			"status", statusCode,
@@ -107,6 +118,10 @@ func (f *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if f.jsonErrors && resp.StatusCode >= http.StatusBadRequest {
+		queryErrorResponse(resp)
+	}
+
 	// write headers, status code and body
 	copyHeader(w.Header(), resp.Header)
 	w.WriteHeader(resp.StatusCode)
@@ -153,9 +168,9 @@ func copyHeader(dst, src http.Header) {
 // writeError handles writing errors to the http.ResponseWriter. It uses dskit's
 // httpgrpc.WriteError() to handle httpgrc errors. The handler handles all incoming HTTP requests
 // to the query frontend which then distributes them via httpgrpc to the queriers. As a result
-// httpgrpc errors can bubble up to here and should be translated to http errors. It returns
-// httpgrpc error.
-func writeError(w http.ResponseWriter, err error) error {
+// httpgrpc errors can bubble up to here and should be translated to http errors. The error is written as
+// api.QueryError if json errors are enabled. It returns httpgrpc error.
+func writeError(w http.ResponseWriter, err error, jsonErrors bool) error {
 	if errors.Is(err, context.Canceled) {
 		err = errCanceled
 	} else if errors.Is(err, context.DeadlineExceeded) {
@@ -163,10 +178,49 @@ func writeError(w http.ResponseWriter, err error) error {
 	} else if isRequestBodyTooLarge(err) {
 		err = errRequestEntityTooLarge
 	}
-	httpgrpc.WriteError(w, err)
+
+	if !jsonErrors {
+		httpgrpc.WriteError(w, err)
+		return err
+	}
+
+	if httpResp, ok := httpgrpc.HTTPResponseFromError(err); ok {
+		api.WriteQueryErrorWithStatus(w, int(httpResp.Code), api.ParseQueryError(int(httpResp.Code), httpResp.Body))
+		return err
+	}
+
+	api.WriteQueryErrorWithStatus(w, http.StatusInternalServerError, api.AsQueryError(err))
 	return err
 }
 
+// queryErrorResponse replaces the body of a failed response with an api.QueryError. Bodies that are already
+// json are left alone.
+func queryErrorResponse(resp *http.Response) {
+	if resp.Body == nil {
+		resp.Body = io.NopCloser(strings.NewReader(""))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		body = []byte(err.Error())
+	}
+
+	if !api.IsQueryErrorBody(body) && resp.Header.Get(api.HeaderContentType) == api.HeaderAcceptJSON {
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return
+	}
+
+	queryErr := api.ParseQueryError(resp.StatusCode, body).Body()
+	if resp.Header == nil {
+		resp.Header = http.Header{}
+	}
+	resp.Header.Set(api.HeaderContentType, api.HeaderAcceptJSON)
+	resp.Header.Del("Content-Length")
+	resp.Body = io.NopCloser(strings.NewReader(queryErr))
+	resp.ContentLength = int64(len(queryErr))
+}
+
 // isRequestBodyTooLarge returns true if the error is "http: request body too large".
 func isRequestBodyTooLarge(err error) bool {
 	return err != nil && strings.Contains(err.Error(), "http: request body too large")
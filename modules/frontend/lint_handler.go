@@ -0,0 +1,214 @@
+package frontend
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level" //nolint:all //deprecated
+	"github.com/grafana/dskit/tenant"
+	"github.com/grafana/dskit/user"
+
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb"
+)
+
+const (
+	lintWarningUnscopedAttribute    = "unscoped_attribute"
+	lintWarningLeadingRegexWildcard = "leading_regex_wildcard"
+	lintWarningNoConditions         = "no_conditions"
+	lintWarningMaxDurationExceeded  = "max_duration_exceeded"
+)
+
+// lintResponse is returned by the traceql lint endpoint
+type lintResponse struct {
+	Valid      bool          `json:"valid"`
+	Error      string        `json:"error,omitempty"`
+	Normalized string        `json:"normalized,omitempty"`
+	Warnings   []lintWarning `json:"warnings"`
+	Estimate   *lintEstimate `json:"estimate,omitempty"`
+}
+
+type lintWarning struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// lintEstimate is the work the frontend would shard out to the queriers for the backend part of the query.
+// the bytes are an upper bound, the queriers only read the columns the query needs.
+type lintEstimate struct {
+	TotalBlocks     int    `json:"totalBlocks"`
+	TotalBlockBytes uint64 `json:"totalBlockBytes"`
+	TotalJobs       int    `json:"totalJobs"`
+}
+
+// newTraceQLLintHandler returns a handler that parses a TraceQL query and returns its normalized form, warnings
+// about constructs that are expensive to execute and an estimate of the blocks it would scan. the query is never
+// executed.
+func newTraceQLLintHandler(cfg Config, reader tempodb.Reader, o overrides.Interface, logger log.Logger) http.RoundTripper {
+	// reuse the search sharder to select blocks the same way a search would
+	sharder := &asyncSearchSharder{
+		reader:    reader,
+		overrides: o,
+		cfg:       cfg.Search.Sharder,
+		logger:    logger,
+	}
+
+	return pipeline.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		orgID, err := user.ExtractOrgID(req.Context())
+		if err != nil {
+			return lintBadRequest(err), nil
+		}
+
+		query, start, end, err := api.ParseTraceQLLintRequest(req)
+		if err != nil {
+			return lintBadRequest(err), nil
+		}
+
+		tenants, err := tenant.TenantIDsFromOrgID(orgID)
+		if err != nil {
+			return lintBadRequest(err), nil
+		}
+
+		resp := lintQuery(query)
+		if resp.Valid && start != 0 && end != 0 {
+			for _, tenantID := range tenants {
+				if maxDuration := sharder.maxDuration(tenantID); maxDuration != 0 && time.Duration(end-start)*time.Second > maxDuration {
+					resp.Warnings = append(resp.Warnings, lintWarning{
+						Type:    lintWarningMaxDurationExceeded,
+						Message: fmt.Sprintf("range specified by start and end exceeds %s for tenant %s and will be rejected", maxDuration, tenantID),
+					})
+				}
+			}
+			resp.Estimate = estimateSearchCost(sharder, tenants, start, end)
+		}
+
+		level.Info(logger).Log(
+			"msg", "traceql lint request",
+			"tenant", orgID,
+			"query", query,
+			"valid", resp.Valid,
+			"warnings", len(resp.Warnings))
+
+		body, err := json.Marshal(resp)
+		if err != nil {
+			return nil, err
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     http.StatusText(http.StatusOK),
+			Header:     http.Header{api.HeaderContentType: {api.HeaderAcceptJSON}},
+			Body:       io.NopCloser(strings.NewReader(string(body))),
+		}, nil
+	})
+}
+
+func lintBadRequest(err error) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Status:     http.StatusText(http.StatusBadRequest),
+		Body:       io.NopCloser(strings.NewReader(err.Error())),
+	}
+}
+
+// lintQuery compiles the query and inspects the conditions that would be pushed down to the storage layer
+func lintQuery(query string) *lintResponse {
+	expr, _, _, fetchReq, err := traceql.NewEngine().Compile(query)
+	if err != nil {
+		return &lintResponse{
+			Error:    err.Error(),
+			Warnings: []lintWarning{},
+		}
+	}
+
+	resp := &lintResponse{
+		Valid:      true,
+		Normalized: expr.String(),
+		Warnings:   []lintWarning{},
+	}
+
+	// conditions without an operator only select data. if there are no others every span is matched
+	filtered := false
+	for _, cond := range fetchReq.Conditions {
+		if cond.Op != traceql.OpNone {
+			filtered = true
+			break
+		}
+	}
+	if !filtered {
+		resp.Warnings = append(resp.Warnings, lintWarning{
+			Type:    lintWarningNoConditions,
+			Message: "query has no conditions and matches every span in the time range",
+		})
+	}
+
+	seen := map[string]struct{}{}
+	for _, cond := range fetchReq.Conditions {
+		attr := cond.Attribute
+		if attr.Scope == traceql.AttributeScopeNone && attr.Intrinsic == traceql.IntrinsicNone {
+			if _, ok := seen[attr.Name]; !ok {
+				seen[attr.Name] = struct{}{}
+				resp.Warnings = append(resp.Warnings, lintWarning{
+					Type:    lintWarningUnscopedAttribute,
+					Message: fmt.Sprintf("attribute %s is unscoped and is looked up in both the span and resource scopes. use span.%s or resource.%s if possible", attr, attr.Name, attr.Name),
+				})
+			}
+		}
+
+		if cond.Op != traceql.OpRegex && cond.Op != traceql.OpNotRegex {
+			continue
+		}
+		for _, operand := range cond.Operands {
+			if operand.Type == traceql.TypeString && hasLeadingRegexWildcard(operand.S) {
+				resp.Warnings = append(resp.Warnings, lintWarning{
+					Type:    lintWarningLeadingRegexWildcard,
+					Message: fmt.Sprintf("regex %q on %s starts with a wildcard and has to be evaluated against every value", operand.S, attr),
+				})
+			}
+		}
+	}
+
+	return resp
+}
+
+// hasLeadingRegexWildcard returns true if the regex starts with an unanchored wildcard like .* or .+
+func hasLeadingRegexWildcard(re string) bool {
+	re = strings.TrimPrefix(re, "^")
+	return strings.HasPrefix(re, ".*") || strings.HasPrefix(re, ".+")
+}
+
+// estimateSearchCost returns the blocks, bytes and jobs a search over start/end would shard out to the queriers
+func estimateSearchCost(sharder *asyncSearchSharder, tenants []string, start, end uint32) *lintEstimate {
+	estimate := &lintEstimate{}
+
+	start, end = backendRange(start, end, sharder.cfg.QueryBackendAfter)
+	if start == end {
+		return estimate
+	}
+
+	for _, tenantID := range tenants {
+		for _, m := range sharder.blockMetas(int64(start), int64(end), tenantID) {
+			estimate.TotalBlocks++
+			estimate.TotalBlockBytes += m.Size
+
+			p := pagesPerRequest(m, sharder.cfg.TargetBytesPerRequest)
+			if p == 0 {
+				continue
+			}
+			estimate.TotalJobs += int(m.TotalRecords) / p
+			if int(m.TotalRecords)%p != 0 {
+				estimate.TotalJobs++
+			}
+		}
+	}
+
+	return estimate
+}
@@ -0,0 +1,138 @@
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestLintQuery(t *testing.T) {
+	tcs := []struct {
+		query            string
+		expectedValid    bool
+		expectedWarnings []string
+	}{
+		{
+			query:            `{ span.foo = "bar" }`,
+			expectedValid:    true,
+			expectedWarnings: []string{},
+		},
+		{
+			query:            `{ .foo = "bar" && .foo != "baz" }`,
+			expectedValid:    true,
+			expectedWarnings: []string{lintWarningUnscopedAttribute},
+		},
+		{
+			query:            `{ resource.service.name =~ ".*api" }`,
+			expectedValid:    true,
+			expectedWarnings: []string{lintWarningLeadingRegexWildcard},
+		},
+		{
+			query:            `{ span.http.url !~ "^.+/health" }`,
+			expectedValid:    true,
+			expectedWarnings: []string{lintWarningLeadingRegexWildcard},
+		},
+		{
+			query:            `{ span.http.url =~ "/api/.*" }`,
+			expectedValid:    true,
+			expectedWarnings: []string{},
+		},
+		{
+			query:            `{ }`,
+			expectedValid:    true,
+			expectedWarnings: []string{lintWarningNoConditions},
+		},
+		{
+			query:         `{ span.foo = }`,
+			expectedValid: false,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			resp := lintQuery(tc.query)
+			require.Equal(t, tc.expectedValid, resp.Valid)
+			if !tc.expectedValid {
+				require.NotEmpty(t, resp.Error)
+				return
+			}
+
+			require.NotEmpty(t, resp.Normalized)
+			warnings := make([]string, 0, len(resp.Warnings))
+			for _, w := range resp.Warnings {
+				warnings = append(warnings, w.Type)
+			}
+			require.Equal(t, tc.expectedWarnings, warnings)
+		})
+	}
+}
+
+func TestTraceQLLintHandler(t *testing.T) {
+	now := time.Now()
+	reader := &mockReader{
+		metas: []*backend.BlockMeta{
+			{
+				StartTime:         now.Add(-2 * time.Hour),
+				EndTime:           now.Add(-time.Hour),
+				Size:              1000,
+				TotalRecords:      10,
+				ReplicationFactor: backend.DefaultReplicationFactor,
+			},
+			{ // outside of the range
+				StartTime:         now.Add(-10 * time.Hour),
+				EndTime:           now.Add(-9 * time.Hour),
+				Size:              1000,
+				TotalRecords:      10,
+				ReplicationFactor: backend.DefaultReplicationFactor,
+			},
+		},
+	}
+
+	o, err := overrides.NewOverrides(overrides.Config{}, nil, nil)
+	require.NoError(t, err)
+
+	cfg := Config{}
+	cfg.Search.Sharder.TargetBytesPerRequest = 500
+	cfg.Search.Sharder.MaxDuration = time.Hour
+
+	handler := newTraceQLLintHandler(cfg, reader, o, log.NewNopLogger())
+
+	params := url.Values{}
+	params.Set("q", `{ .foo = "bar" }`)
+	params.Set("start", strconv.FormatInt(now.Add(-3*time.Hour).Unix(), 10))
+	params.Set("end", strconv.FormatInt(now.Unix(), 10))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/traceql/lint?"+params.Encode(), nil)
+	req = req.WithContext(user.InjectOrgID(context.Background(), "test"))
+
+	httpResp, err := handler.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResp.StatusCode)
+
+	resp := &lintResponse{}
+	require.NoError(t, json.NewDecoder(httpResp.Body).Decode(resp))
+	require.True(t, resp.Valid)
+	require.Len(t, resp.Warnings, 2)
+	require.Equal(t, lintWarningUnscopedAttribute, resp.Warnings[0].Type)
+	require.Equal(t, lintWarningMaxDurationExceeded, resp.Warnings[1].Type)
+	require.Equal(t, &lintEstimate{TotalBlocks: 1, TotalBlockBytes: 1000, TotalJobs: 2}, resp.Estimate)
+
+	// missing query
+	req = httptest.NewRequest(http.MethodGet, "/api/v2/traceql/lint", nil)
+	req = req.WithContext(user.InjectOrgID(context.Background(), "test"))
+	httpResp, err = handler.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, httpResp.StatusCode)
+}
@@ -0,0 +1,235 @@
+package frontend
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level" //nolint:all //deprecated
+	"github.com/gogo/protobuf/jsonpb"
+
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+// queryRangeDiffResponse is returned by the query range diff endpoint. Baseline and Comparison are the query
+// range responses of both time ranges, Series the per series deltas between them.
+type queryRangeDiffResponse struct {
+	Baseline   json.RawMessage        `json:"baseline"`
+	Comparison json.RawMessage        `json:"comparison"`
+	Series     []queryRangeDiffSeries `json:"series"`
+}
+
+// queryRangeDiffSeries compares a series of the baseline range with the same series of the comparison range.
+// The averages only include the samples of the respective range, RelativeDelta is omitted if the baseline
+// average is zero.
+type queryRangeDiffSeries struct {
+	PromLabels    string                 `json:"promLabels"`
+	BaselineAvg   float64                `json:"baselineAvg"`
+	ComparisonAvg float64                `json:"comparisonAvg"`
+	Delta         float64                `json:"delta"`
+	RelativeDelta *float64               `json:"relativeDelta,omitempty"`
+	Samples       []queryRangeDiffSample `json:"samples"`
+}
+
+// queryRangeDiffSample is the delta of the samples at the same step of both ranges. TimestampMs is the timestamp
+// of the comparison sample.
+type queryRangeDiffSample struct {
+	TimestampMs int64   `json:"timestampMs"`
+	Baseline    float64 `json:"baseline"`
+	Comparison  float64 `json:"comparison"`
+	Delta       float64 `json:"delta"`
+}
+
+// newMetricsQueryRangeDiffHandler returns a handler that runs a metrics query over a baseline and a comparison time
+// range using the query range handler and returns both responses and the deltas per series.
+func newMetricsQueryRangeDiffHandler(next http.RoundTripper, logger log.Logger) http.RoundTripper {
+	return pipeline.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		queryRangeReq, baselineStart, baselineEnd, err := api.ParseQueryRangeDiffRequest(req)
+		if err != nil {
+			level.Error(logger).Log("msg", "query range diff: parse request failed", "err", err)
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Status:     http.StatusText(http.StatusBadRequest),
+				Body:       io.NopCloser(strings.NewReader(err.Error())),
+			}, nil
+		}
+
+		baselineReq := *queryRangeReq
+		baselineReq.Start = baselineStart
+		baselineReq.End = baselineEnd
+
+		var (
+			wg        sync.WaitGroup
+			ranges    = []*tempopb.QueryRangeRequest{&baselineReq, queryRangeReq}
+			responses = make([]*http.Response, len(ranges))
+			errs      = make([]error, len(ranges))
+		)
+		for i, r := range ranges {
+			wg.Add(1)
+			go func(i int, r *tempopb.QueryRangeRequest) {
+				defer wg.Done()
+				responses[i], errs[i] = next.RoundTrip(queryRangeDiffSubRequest(req, r))
+			}(i, r)
+		}
+		wg.Wait()
+
+		bodies := make([][]byte, len(ranges))
+		for i, resp := range responses {
+			if errs[i] != nil {
+				return nil, errs[i]
+			}
+			bodies[i], err = io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			if resp.StatusCode != http.StatusOK {
+				return &http.Response{
+					StatusCode: resp.StatusCode,
+					Status:     resp.Status,
+					Header:     resp.Header,
+					Body:       io.NopCloser(bytes.NewReader(bodies[i])),
+				}, nil
+			}
+		}
+
+		baseline := &tempopb.QueryRangeResponse{}
+		if err := jsonpb.Unmarshal(bytes.NewReader(bodies[0]), baseline); err != nil {
+			return nil, err
+		}
+		comparison := &tempopb.QueryRangeResponse{}
+		if err := jsonpb.Unmarshal(bytes.NewReader(bodies[1]), comparison); err != nil {
+			return nil, err
+		}
+
+		body, err := json.Marshal(queryRangeDiffResponse{
+			Baseline:   bodies[0],
+			Comparison: bodies[1],
+			Series:     diffQueryRangeSeries(baseline, comparison, alignedStart(&baselineReq), alignedStart(queryRangeReq), queryRangeReq.Step),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     http.StatusText(http.StatusOK),
+			Header:     http.Header{api.HeaderContentType: {api.HeaderAcceptJSON}},
+			Body:       io.NopCloser(bytes.NewReader(body)),
+		}, nil
+	})
+}
+
+// queryRangeDiffSubRequest returns the query range request of one of the ranges of a diff request.
+func queryRangeDiffSubRequest(req *http.Request, queryRangeReq *tempopb.QueryRangeRequest) *http.Request {
+	subReq := req.Clone(req.Context())
+	subReq.URL.Path = strings.TrimSuffix(req.URL.Path, api.PathMetricsQueryRangeDiff) + api.PathMetricsQueryRange
+	subReq.RequestURI = strings.Replace(req.RequestURI, api.PathMetricsQueryRangeDiff, api.PathMetricsQueryRange, 1)
+	subReq.Header.Set(api.HeaderAccept, api.HeaderAcceptJSON)
+	return api.BuildQueryRangeRequest(subReq, queryRangeReq)
+}
+
+// alignedStart is the start of the request aligned to the step the same way the sharder does.
+func alignedStart(req *tempopb.QueryRangeRequest) uint64 {
+	if req.Step == 0 {
+		return req.Start
+	}
+	return req.Start / req.Step * req.Step
+}
+
+// diffQueryRangeSeries matches the series of both responses by their labels and the samples by their step within
+// the range. Missing, NaN and infinite samples are left out of the averages and the sample deltas.
+func diffQueryRangeSeries(baseline, comparison *tempopb.QueryRangeResponse, baselineStart, comparisonStart, step uint64) []queryRangeDiffSeries {
+	if step == 0 {
+		return nil
+	}
+
+	intervalValues := func(series *tempopb.TimeSeries, start uint64) map[uint64]float64 {
+		values := make(map[uint64]float64, len(series.Samples))
+		for _, s := range series.Samples {
+			ts := uint64(time.Duration(s.TimestampMs) * time.Millisecond)
+			if ts < start || math.IsNaN(s.Value) || math.IsInf(s.Value, 0) {
+				continue
+			}
+			values[(ts-start)/step] = s.Value
+		}
+		return values
+	}
+
+	baselineSeries := make(map[string]map[uint64]float64, len(baseline.Series))
+	for _, s := range baseline.Series {
+		baselineSeries[s.PromLabels] = intervalValues(s, baselineStart)
+	}
+	comparisonSeries := make(map[string]map[uint64]float64, len(comparison.Series))
+	for _, s := range comparison.Series {
+		comparisonSeries[s.PromLabels] = intervalValues(s, comparisonStart)
+	}
+
+	labels := make(map[string]struct{}, len(baselineSeries)+len(comparisonSeries))
+	for l := range baselineSeries {
+		labels[l] = struct{}{}
+	}
+	for l := range comparisonSeries {
+		labels[l] = struct{}{}
+	}
+
+	result := make([]queryRangeDiffSeries, 0, len(labels))
+	for l := range labels {
+		b, c := baselineSeries[l], comparisonSeries[l]
+
+		series := queryRangeDiffSeries{
+			PromLabels:    l,
+			BaselineAvg:   avgValues(b),
+			ComparisonAvg: avgValues(c),
+			Samples:       []queryRangeDiffSample{},
+		}
+		series.Delta = series.ComparisonAvg - series.BaselineAvg
+		if series.BaselineAvg != 0 {
+			relative := series.Delta / series.BaselineAvg
+			series.RelativeDelta = &relative
+		}
+
+		for interval, cv := range c {
+			bv, ok := b[interval]
+			if !ok {
+				continue
+			}
+			series.Samples = append(series.Samples, queryRangeDiffSample{
+				TimestampMs: int64(time.Duration(comparisonStart+interval*step) / time.Millisecond),
+				Baseline:    bv,
+				Comparison:  cv,
+				Delta:       cv - bv,
+			})
+		}
+		sort.Slice(series.Samples, func(i, j int) bool {
+			return series.Samples[i].TimestampMs < series.Samples[j].TimestampMs
+		})
+
+		result = append(result, series)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].PromLabels < result[j].PromLabels
+	})
+	return result
+}
+
+func avgValues(values map[uint64]float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,126 @@
+package frontend
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+func TestMetricsQueryRangeDiffHandler(t *testing.T) {
+	const (
+		baselineStart   = 1000
+		comparisonStart = 5000
+		duration        = 30
+	)
+	ms := func(s int64) int64 { return s * 1000 }
+
+	responses := map[uint64]*tempopb.QueryRangeResponse{
+		baselineStart * uint64(time.Second): {Series: []*tempopb.TimeSeries{
+			{PromLabels: `{foo="a"}`, Samples: []tempopb.Sample{{TimestampMs: ms(1000), Value: 2}, {TimestampMs: ms(1010), Value: 4}, {TimestampMs: ms(1020), Value: math.NaN()}}},
+			{PromLabels: `{foo="b"}`, Samples: []tempopb.Sample{{TimestampMs: ms(1000), Value: 1}}},
+		}},
+		comparisonStart * uint64(time.Second): {Series: []*tempopb.TimeSeries{
+			{PromLabels: `{foo="a"}`, Samples: []tempopb.Sample{{TimestampMs: ms(5000), Value: 3}, {TimestampMs: ms(5010), Value: 9}, {TimestampMs: ms(5020), Value: 0}}},
+			{PromLabels: `{foo="c"}`, Samples: []tempopb.Sample{{TimestampMs: ms(5010), Value: 5}}},
+		}},
+	}
+
+	var (
+		mtx   sync.Mutex
+		paths []string
+	)
+	next := pipeline.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		queryRangeReq, err := api.ParseQueryRangeRequest(req)
+		require.NoError(t, err)
+		assert.Equal(t, "{} | rate()", queryRangeReq.Query)
+		assert.Equal(t, uint64(duration*time.Second), queryRangeReq.End-queryRangeReq.Start)
+
+		resp, ok := responses[queryRangeReq.Start]
+		require.True(t, ok)
+		body, err := new(jsonpb.Marshaler).MarshalToString(resp)
+		require.NoError(t, err)
+
+		mtx.Lock()
+		paths = append(paths, req.URL.Path)
+		mtx.Unlock()
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})
+
+	handler := newMetricsQueryRangeDiffHandler(next, log.NewNopLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/tempo"+api.PathMetricsQueryRangeDiff+"?q={}+|+rate()&start=5000&end=5030&step=10s&baselineStart=1000", nil)
+	resp, err := handler.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"/tempo" + api.PathMetricsQueryRange, "/tempo" + api.PathMetricsQueryRange}, paths)
+
+	var diff struct {
+		Baseline   *json.RawMessage       `json:"baseline"`
+		Comparison *json.RawMessage       `json:"comparison"`
+		Series     []queryRangeDiffSeries `json:"series"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&diff))
+	require.NotNil(t, diff.Baseline)
+	require.NotNil(t, diff.Comparison)
+
+	relative := func(f float64) *float64 { return &f }
+	assert.Equal(t, []queryRangeDiffSeries{
+		{
+			PromLabels:    `{foo="a"}`,
+			BaselineAvg:   3,
+			ComparisonAvg: 4,
+			Delta:         1,
+			RelativeDelta: relative(1.0 / 3),
+			Samples: []queryRangeDiffSample{
+				{TimestampMs: ms(5000), Baseline: 2, Comparison: 3, Delta: 1},
+				{TimestampMs: ms(5010), Baseline: 4, Comparison: 9, Delta: 5},
+			},
+		},
+		{
+			PromLabels:    `{foo="b"}`,
+			BaselineAvg:   1,
+			Delta:         -1,
+			RelativeDelta: relative(-1),
+			Samples:       []queryRangeDiffSample{},
+		},
+		{
+			PromLabels:    `{foo="c"}`,
+			ComparisonAvg: 5,
+			Delta:         5,
+			Samples:       []queryRangeDiffSample{},
+		},
+	}, diff.Series)
+}
+
+func TestMetricsQueryRangeDiffHandlerBadRequest(t *testing.T) {
+	next := pipeline.RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+		t.Fatal("no query range request expected")
+		return nil, nil
+	})
+	handler := newMetricsQueryRangeDiffHandler(next, log.NewNopLogger())
+
+	for _, query := range []string{
+		"q={}+|+rate()&start=5000&end=5030",
+		"q={}+|+rate()&start=5000&end=5030&baselineStart=1000&baselineEnd=1010",
+		"q={}+|+rate()&start=5000&end=5030&baselineStart=foo",
+	} {
+		resp, err := handler.RoundTrip(httptest.NewRequest(http.MethodGet, api.PathMetricsQueryRangeDiff+"?"+query, nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+}
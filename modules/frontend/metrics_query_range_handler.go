@@ -12,29 +12,34 @@ import (
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level" //nolint:all //deprecated
 	"github.com/grafana/dskit/user"
+	"github.com/grafana/tempo/modules/frontend/audit"
+	"github.com/grafana/tempo/modules/frontend/canary"
 	"github.com/grafana/tempo/modules/frontend/combiner"
 	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/modules/overrides"
 
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/tempopb"
 )
 
 // newQueryRangeStreamingGRPCHandler returns a handler that streams results from the HTTP handler
-func newQueryRangeStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, logger log.Logger) streamingQueryRangeHandler {
+func newQueryRangeStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, o overrides.Interface, auditor *audit.Auditor, logger log.Logger) streamingQueryRangeHandler {
 	postSLOHook := metricsSLOPostHook(cfg.Metrics.SLO)
 	downstreamPath := path.Join(apiPrefix, api.PathMetricsQueryRange)
 
 	return func(req *tempopb.QueryRangeRequest, srv tempopb.StreamingQuerier_MetricsQueryRangeServer) error {
+		ctx := srv.Context()
+		tenant, _ := user.ExtractOrgID(ctx)
+		start := time.Now()
+
+		applyExemplarLimits(req, o, tenant)
+
 		httpReq := api.BuildQueryRangeRequest(&http.Request{
 			URL:    &url.URL{Path: downstreamPath},
 			Header: http.Header{},
 			Body:   io.NopCloser(bytes.NewReader([]byte{})),
 		}, req)
-
-		ctx := srv.Context()
 		httpReq = httpReq.WithContext(ctx)
-		tenant, _ := user.ExtractOrgID(ctx)
-		start := time.Now()
 
 		var finalResponse *tempopb.QueryRangeResponse
 		c, err := combiner.NewTypedQueryRange(req)
@@ -57,12 +62,13 @@ func newQueryRangeStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripp
 		}
 		postSLOHook(nil, tenant, bytesProcessed, duration, err)
 		logQueryRangeResult(logger, tenant, duration.Seconds(), req, finalResponse, err)
+		auditQueryRange(auditor, tenant, auditor.UserFromContext(ctx), start, req, nil, bytesProcessed, err)
 		return err
 	}
 }
 
 // newMetricsQueryRangeHTTPHandler returns a handler that returns a single response from the HTTP handler
-func newMetricsQueryRangeHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], logger log.Logger) http.RoundTripper {
+func newMetricsQueryRangeHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], o overrides.Interface, auditor *audit.Auditor, mirror *canary.Mirror, logger log.Logger) http.RoundTripper {
 	postSLOHook := metricsSLOPostHook(cfg.Metrics.SLO)
 
 	return pipeline.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
@@ -81,6 +87,11 @@ func newMetricsQueryRangeHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper
 		}
 
 		logQueryRangeRequest(logger, tenant, queryRangeReq)
+		canaryReq := mirror.Sample(metricsOp, req, queryRangeFingerprint)
+
+		// the combiner and the sharder both need the exemplar settings of the tenant
+		applyExemplarLimits(queryRangeReq, o, tenant)
+		req = api.BuildQueryRangeRequest(req, queryRangeReq)
 
 		// build and use roundtripper
 		combiner, err := combiner.NewTypedQueryRange(queryRangeReq)
@@ -106,10 +117,32 @@ func newMetricsQueryRangeHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper
 		duration := time.Since(start)
 		postSLOHook(resp, tenant, bytesProcessed, duration, err)
 		logQueryRangeResult(logger, tenant, duration.Seconds(), queryRangeReq, queryRangeResp, err)
+		auditQueryRange(auditor, tenant, auditor.UserFromHeader(req.Header), start, queryRangeReq, resp, bytesProcessed, err)
+		mirror.Replay(canaryReq, resp, duration, err)
 		return resp, err
 	})
 }
 
+// applyExemplarLimits caps the number of exemplars per series at the tenant limit. Requests that don't
+// ask for a number of exemplars or a policy use the ones of the tenant.
+func applyExemplarLimits(req *tempopb.QueryRangeRequest, o overrides.Interface, tenantID string) {
+	maxExemplars := uint32(max(o.MaxExemplars(tenantID), 0))
+	if req.Exemplars == 0 || req.Exemplars > maxExemplars {
+		req.Exemplars = maxExemplars
+	}
+	if req.ExemplarPolicy == "" {
+		req.ExemplarPolicy = o.ExemplarPolicy(tenantID)
+	}
+}
+
+func auditQueryRange(auditor *audit.Auditor, tenant, user string, start time.Time, req *tempopb.QueryRangeRequest, resp *http.Response, bytesProcessed uint64, err error) {
+	r := audit.NewRecord(tenant, user, metricsOp, start, resp, bytesProcessed, err)
+	r.Query = req.Query
+	r.Start = uint32(req.Start / uint64(time.Second))
+	r.End = uint32(req.End / uint64(time.Second))
+	auditor.Record(r)
+}
+
 func logQueryRangeResult(logger log.Logger, tenantID string, durationSeconds float64, req *tempopb.QueryRangeRequest, resp *tempopb.QueryRangeResponse, err error) {
 	if resp == nil {
 		level.Info(logger).Log(
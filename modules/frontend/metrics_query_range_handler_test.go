@@ -8,9 +8,11 @@ import (
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/gogo/protobuf/proto"
 	"github.com/grafana/dskit/user"
+	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/tempopb"
 	v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 )
 
@@ -188,3 +190,40 @@ func TestQueryRangeHandlerRespectsSamplingRate(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expectedResp, actualResp)
 }
+
+func TestApplyExemplarLimits(t *testing.T) {
+	o, err := overrides.NewOverrides(overrides.Config{
+		Defaults: overrides.Overrides{
+			Read: overrides.ReadOverrides{
+				MaxExemplars:   10,
+				ExemplarPolicy: "slowest",
+			},
+		},
+	}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	// tenant defaults
+	req := &tempopb.QueryRangeRequest{}
+	applyExemplarLimits(req, o, "test")
+	require.Equal(t, uint32(10), req.Exemplars)
+	require.Equal(t, "slowest", req.ExemplarPolicy)
+
+	// requested values within the limit
+	req = &tempopb.QueryRangeRequest{Exemplars: 3, ExemplarPolicy: "errors"}
+	applyExemplarLimits(req, o, "test")
+	require.Equal(t, uint32(3), req.Exemplars)
+	require.Equal(t, "errors", req.ExemplarPolicy)
+
+	// capped at the limit
+	req = &tempopb.QueryRangeRequest{Exemplars: 100}
+	applyExemplarLimits(req, o, "test")
+	require.Equal(t, uint32(10), req.Exemplars)
+
+	// disabled by default
+	o, err = overrides.NewOverrides(overrides.Config{}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	req = &tempopb.QueryRangeRequest{Exemplars: 5}
+	applyExemplarLimits(req, o, "test")
+	require.Equal(t, uint32(0), req.Exemplars)
+}
@@ -90,13 +90,20 @@ func (s queryRangeSharder) RoundTrip(r *http.Request) (pipeline.Responses[combin
 	if req.Step == 0 {
 		return pipeline.NewBadRequest(errors.New("step must be greater than 0")), nil
 	}
-	alignTimeRange(req)
+
+	align, err := api.ParseQueryRangeAlign(r)
+	if err != nil {
+		return pipeline.NewBadRequest(err), nil
+	}
+	if align {
+		alignTimeRange(req)
+	}
 
 	// calculate and enforce max search duration
 	maxDuration := s.maxDuration(tenantID)
 	if maxDuration != 0 && time.Duration(req.End-req.Start)*time.Nanosecond > maxDuration {
-		err = fmt.Errorf(fmt.Sprintf("range specified by start and end (%s) exceeds %s. received start=%d end=%d", time.Duration(req.End-req.Start), maxDuration, req.Start, req.End))
-		return pipeline.NewBadRequest(err), nil
+		err = fmt.Errorf("range specified by start and end (%s) exceeds the max metrics duration of %s. received start=%d end=%d", time.Duration(req.End-req.Start), maxDuration, req.Start, req.End)
+		return pipeline.NewQueryError(r, api.NewQueryError(api.ErrorCodeLimitExceeded, err).WithLimit("max_metrics_duration")), nil
 	}
 
 	var (
@@ -180,9 +187,10 @@ func (s *queryRangeSharder) shardedBackendRequests(ctx context.Context, tenantID
 	}
 
 	// Make a copy and limit to backend time range.
+	// Samples evaluate the spans up to their timestamp minus the offset.
 	backendReq := searchReq
-	backendReq.Start, backendReq.End = s.backendRange(now, backendReq.Start, backendReq.End, s.cfg.QueryBackendAfter)
-	alignTimeRange(&backendReq)
+	backendReq.Start, backendReq.End = s.backendRange(now.Add(time.Duration(searchReq.Offset)), backendReq.Start, backendReq.End, s.cfg.QueryBackendAfter)
+	alignToRequest(&backendReq, &searchReq)
 
 	// If empty window then no need to search backend
 	if backendReq.Start == backendReq.End {
@@ -192,7 +200,8 @@ func (s *queryRangeSharder) shardedBackendRequests(ctx context.Context, tenantID
 
 	// Blocks within overall time range. This is just for instrumentation, more precise time
 	// range is checked for each window.
-	blocks := s.blockMetas(int64(backendReq.Start), int64(backendReq.End), tenantID)
+	spanStart, spanEnd := spanTimeRange(backendReq, backendReq.Start, backendReq.End)
+	blocks := s.blockMetas(spanStart, spanEnd, tenantID)
 	if len(blocks) == 0 {
 		// no need to search backend
 		close(reqCh)
@@ -219,7 +228,8 @@ func (s *queryRangeSharder) shardedBackendRequests(ctx context.Context, tenantID
 			thisEnd = end
 		}
 
-		blocks := s.blockMetas(int64(thisStart), int64(thisEnd), tenantID)
+		spanStart, spanEnd := spanTimeRange(backendReq, thisStart, thisEnd)
+		blocks := s.blockMetas(spanStart, spanEnd, tenantID)
 		if len(blocks) == 0 {
 			start = thisEnd
 			continue
@@ -262,7 +272,8 @@ func (s *queryRangeSharder) buildShardedBackendRequests(ctx context.Context, ten
 			thisEnd = end
 		}
 
-		blocks := s.blockMetas(int64(thisStart), int64(thisEnd), tenantID)
+		spanStart, spanEnd := spanTimeRange(searchReq, thisStart, thisEnd)
+		blocks := s.blockMetas(spanStart, spanEnd, tenantID)
 		if len(blocks) == 0 {
 			start = thisEnd
 			continue
@@ -312,9 +323,10 @@ func (s *queryRangeSharder) backendRequests(ctx context.Context, tenantID string
 	}
 
 	// Make a copy and limit to backend time range.
+	// Samples evaluate the spans up to their timestamp minus the offset.
 	backendReq := searchReq
-	backendReq.Start, backendReq.End = s.backendRange(now, backendReq.Start, backendReq.End, s.cfg.QueryBackendAfter)
-	alignTimeRange(&backendReq)
+	backendReq.Start, backendReq.End = s.backendRange(now.Add(time.Duration(searchReq.Offset)), backendReq.Start, backendReq.End, s.cfg.QueryBackendAfter)
+	alignToRequest(&backendReq, &searchReq)
 
 	// If empty window then no need to search backend
 	if backendReq.Start == backendReq.End {
@@ -324,7 +336,8 @@ func (s *queryRangeSharder) backendRequests(ctx context.Context, tenantID string
 
 	// Blocks within overall time range. This is just for instrumentation, more precise time
 	// range is checked for each window.
-	blocks := s.blockMetas(int64(backendReq.Start), int64(backendReq.End), tenantID)
+	spanStart, spanEnd := spanTimeRange(backendReq, backendReq.Start, backendReq.End)
+	blocks := s.blockMetas(spanStart, spanEnd, tenantID)
 	if len(blocks) == 0 {
 		// no need to search backend
 		close(reqCh)
@@ -370,7 +383,7 @@ func (s *queryRangeSharder) buildBackendRequests(ctx context.Context, tenantID s
 				continue
 			}
 
-			start, end := traceql.TrimToOverlap(searchReq.Start, searchReq.End, searchReq.Step, uint64(m.StartTime.UnixNano()), uint64(m.EndTime.UnixNano()))
+			start, end := traceql.TrimToBlockOverlap(&searchReq, uint64(m.StartTime.UnixNano()), uint64(m.EndTime.UnixNano()))
 
 			queryRangeReq := &tempopb.QueryRangeRequest{
 				Query: searchReq.Query,
@@ -379,7 +392,11 @@ func (s *queryRangeSharder) buildBackendRequests(ctx context.Context, tenantID s
 				Step:  searchReq.Step,
 				// ShardID:    uint32, // No sharding with RF=1
 				// ShardCount: uint32, // No sharding with RF=1
-				QueryMode: searchReq.QueryMode,
+				QueryMode:      searchReq.QueryMode,
+				Exemplars:      searchReq.Exemplars,
+				ExemplarPolicy: searchReq.ExemplarPolicy,
+				Offset:         searchReq.Offset,
+				Lookback:       searchReq.Lookback,
 				// New RF1 fields
 				BlockID:          m.BlockID.String(),
 				StartPage:        uint32(startPage),
@@ -426,7 +443,9 @@ func (s *queryRangeSharder) backendRange(now time.Time, start, end uint64, query
 }
 
 func (s *queryRangeSharder) generatorRequest(searchReq tempopb.QueryRangeRequest, parent *http.Request, tenantID string, now time.Time) *http.Request {
-	cutoff := uint64(now.Add(-s.cfg.QueryBackendAfter).UnixNano())
+	// Samples evaluate the spans up to their timestamp minus the offset.
+	cutoff := uint64(now.Add(-s.cfg.QueryBackendAfter).UnixNano()) + searchReq.Offset
+	origin := searchReq
 
 	// if there's no overlap between the query and ingester range just return nil
 	if searchReq.End < cutoff {
@@ -437,7 +456,7 @@ func (s *queryRangeSharder) generatorRequest(searchReq tempopb.QueryRangeRequest
 		searchReq.Start = cutoff
 	}
 
-	alignTimeRange(&searchReq)
+	alignToRequest(&searchReq, &origin)
 
 	// if start == end then we don't need to query it
 	if searchReq.Start == searchReq.End {
@@ -475,6 +494,27 @@ func alignTimeRange(req *tempopb.QueryRangeRequest) {
 	req.End = req.End / req.Step * req.Step
 }
 
+// alignToRequest shifts the start and end times of the sub-request onto the sample timestamps of the request, so
+// their samples line up when they are combined. Unlike alignTimeRange this also works for requests that aren't
+// aligned to the step.
+func alignToRequest(sub, req *tempopb.QueryRangeRequest) {
+	if sub.End-sub.Start == sub.Step {
+		// Instant query
+		return
+	}
+
+	sub.Start = req.Start + (max(sub.Start, req.Start)-req.Start)/req.Step*req.Step
+	sub.End = req.Start + (max(sub.End, req.Start)-req.Start)/req.Step*req.Step
+}
+
+// spanTimeRange returns the range of the span start times that contribute to the samples of the request between
+// start and end.
+func spanTimeRange(req tempopb.QueryRangeRequest, start, end uint64) (int64, int64) {
+	req.Start, req.End = start, end
+	spanStart, spanEnd := traceql.SpanTimeRange(&req)
+	return int64(spanStart), int64(spanEnd)
+}
+
 // maxDuration returns the max search duration allowed for this tenant.
 func (s *queryRangeSharder) maxDuration(tenantID string) time.Duration {
 	// check overrides first, if no overrides then grab from our config
@@ -548,5 +588,17 @@ func hashForQueryRangeRequest(req *tempopb.QueryRangeRequest) uint64 {
 	hash := fnv1a.HashString64(query)
 	hash = fnv1a.AddUint64(hash, req.Step)
 
+	// exemplars change the results
+	if req.Exemplars > 0 {
+		hash = fnv1a.AddUint64(hash, uint64(req.Exemplars))
+		hash = fnv1a.AddString64(hash, req.ExemplarPolicy)
+	}
+
+	// as do the windows of the samples
+	if req.Offset > 0 || req.Lookback > 0 {
+		hash = fnv1a.AddUint64(hash, req.Offset)
+		hash = fnv1a.AddUint64(hash, req.Lookback)
+	}
+
 	return hash
 }
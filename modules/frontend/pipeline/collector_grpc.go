@@ -3,11 +3,14 @@ package pipeline
 import (
 	"context"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gogo/status"
 	"github.com/grafana/tempo/modules/frontend/combiner"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 )
 
 type GRPCCollector[T combiner.TResponse] struct {
@@ -32,6 +35,7 @@ func (c GRPCCollector[T]) RoundTrip(req *http.Request) error {
 	ctx := req.Context()
 	ctx, cancel := context.WithCancel(ctx) // create a new context with a cancel function
 	defer cancel()
+	ctx, state := contextWithQueryState(ctx)
 
 	req = req.WithContext(ctx)
 	resps, err := c.next.RoundTrip(req)
@@ -68,6 +72,11 @@ func (c GRPCCollector[T]) RoundTrip(req *http.Request) error {
 	if err != nil {
 		return grpcError(err)
 	}
+
+	// the results are partial if blocks were skipped. the trailer is sent with the end of the stream.
+	if skipped := state.skipped(); len(skipped) > 0 {
+		_ = grpc.SetTrailer(ctx, metadata.Pairs(strings.ToLower(HeaderSkippedBlocks), strings.Join(skipped, ",")))
+	}
 	err = c.send(resp)
 	if err != nil {
 		return grpcError(err)
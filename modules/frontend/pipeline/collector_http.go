@@ -3,6 +3,7 @@ package pipeline
 import (
 	"context"
 	"net/http"
+	"strings"
 	"sync"
 
 	"github.com/grafana/tempo/modules/frontend/combiner"
@@ -32,6 +33,7 @@ func NewHTTPCollector(next AsyncRoundTripper[combiner.PipelineResponse], consume
 func (r httpCollector) RoundTrip(req *http.Request) (*http.Response, error) {
 	ctx, cancel := context.WithCancel(req.Context())
 	defer cancel()
+	ctx, state := contextWithQueryState(ctx)
 	req = req.WithContext(ctx)
 
 	resps, err := r.next.RoundTrip(req)
@@ -44,7 +46,20 @@ func (r httpCollector) RoundTrip(req *http.Request) (*http.Response, error) {
 		return nil, err
 	}
 
-	return r.combiner.HTTPFinal()
+	resp, err := r.combiner.HTTPFinal()
+	if err != nil {
+		return nil, err
+	}
+
+	// the results are partial if blocks were skipped
+	if skipped := state.skipped(); len(skipped) > 0 {
+		if resp.Header == nil {
+			resp.Header = http.Header{}
+		}
+		resp.Header.Set(HeaderSkippedBlocks, strings.Join(skipped, ","))
+	}
+
+	return resp, nil
 }
 
 func consumeAndCombineResponses(ctx context.Context, consumers int, resps Responses[combiner.PipelineResponse], c combiner.Combiner, callback func() error) error {
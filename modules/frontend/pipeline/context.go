@@ -3,21 +3,46 @@ package pipeline
 import (
 	"context"
 	"net/http"
+	"time"
 )
 
 // this file exists to consolidate and clearly document all context keys that are valid and recognized by the pipeline package
 
-// contextCacheKey is used by cachingWare to store the cache key in the request context. It stores a string value.
-var contextCacheKey = struct{}{}
+// contextKey gives every key below a distinct value. keys of the same type and value would overwrite each other.
+type contextKey int
+
+const (
+	// contextCacheKey is used by cachingWare to store the cache key in the request context. It stores a string value.
+	contextCacheKey contextKey = iota
+
+	// contextEchoAdditionalData is used to echo request specific data through the pipeline. It stores any value.
+	// see usage for samplingRate in modules/frontend/metrics_query_range_sharder.go and search shards in
+	// modules/frontend/search_sharder.go
+	contextEchoAdditionalData
+
+	// contextQueryState is used to share state between the sub-requests of a query. It stores a *queryState value.
+	// see NewHTTPCollector and NewGRPCCollector
+	contextQueryState
+
+	// contextCacheTTL is used by cachingWare to store a response with a TTL other than the configured expiration of
+	// the cache. It stores a time.Duration value.
+	contextCacheTTL
+)
 
 func ContextAddCacheKey(key string, req *http.Request) *http.Request {
 	return req.WithContext(context.WithValue(req.Context(), contextCacheKey, key))
 }
 
-// contextEchoData is used to echo request specific data through the pipeline. It stores any value.
-// see usage for samplingRate in modules/frontend/metrics_query_range_sharder.go
-var contextEchoAdditionalData = struct{}{}
+// ContextAddCacheTTL sets the TTL the response is cached with. It only has an effect if a cache key is set as well.
+func ContextAddCacheTTL(ttl time.Duration, req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), contextCacheTTL, ttl))
+}
 
 func ContextAddAdditionalData(val any, req *http.Request) *http.Request {
-	return req.WithContext(context.WithValue(req.Context(), contextEchoAdditionalData, val))
+	return req.WithContext(ContextWithAdditionalData(req.Context(), val))
+}
+
+// ContextWithAdditionalData returns a context that echoes val through the pipeline for all requests created from it.
+func ContextWithAdditionalData(ctx context.Context, val any) context.Context {
+	return context.WithValue(ctx, contextEchoAdditionalData, val)
 }
@@ -0,0 +1,67 @@
+package pipeline
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.uber.org/atomic"
+)
+
+// HeaderSkippedBlocks lists the blocks that were skipped because they keep failing. It is set on the response of a
+// query, or as a trailer of a streaming gRPC query, that returned partial results.
+const HeaderSkippedBlocks = "X-Tempo-Skipped-Blocks"
+
+// queryState is shared by all sub-requests of a query. It is created by the collectors.
+type queryState struct {
+	retries atomic.Int32
+
+	mtx           sync.Mutex
+	skippedBlocks map[string]struct{}
+}
+
+func contextWithQueryState(ctx context.Context) (context.Context, *queryState) {
+	s := &queryState{}
+	return context.WithValue(ctx, contextQueryState, s), s
+}
+
+// queryStateFromContext returns the state of the query of a sub-request, nil if the request wasn't made by a collector
+func queryStateFromContext(ctx context.Context) *queryState {
+	s, _ := ctx.Value(contextQueryState).(*queryState)
+	return s
+}
+
+// takeRetry returns true if the query has retries left in its budget. A budget of 0 is unlimited.
+func (s *queryState) takeRetry(budget int) bool {
+	if s == nil || budget <= 0 {
+		return true
+	}
+	return int(s.retries.Inc()) <= budget
+}
+
+func (s *queryState) skipBlock(blockID string) {
+	if s == nil {
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.skippedBlocks == nil {
+		s.skippedBlocks = map[string]struct{}{}
+	}
+	s.skippedBlocks[blockID] = struct{}{}
+}
+
+// skipped returns the sorted ids of the blocks skipped by the query
+func (s *queryState) skipped() []string {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	ids := make([]string, 0, len(s.skippedBlocks))
+	for id := range s.skippedBlocks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/grafana/tempo/modules/frontend/combiner"
+	"github.com/grafana/tempo/pkg/api"
 	"go.uber.org/atomic"
 )
 
@@ -63,6 +64,25 @@ func NewBadRequest(err error) Responses[combiner.PipelineResponse] {
 	})
 }
 
+// NewQueryError creates a new AsyncResponse that wraps a single http.Response with the status code of the error. The
+// error is serialized into the body if the request accepts json errors, the body is the error message otherwise.
+func NewQueryError(r *http.Request, queryErr *api.QueryError) Responses[combiner.PipelineResponse] {
+	if !api.AcceptsJSONErrors(r) {
+		return NewHTTPToAsyncResponse(&http.Response{
+			StatusCode: queryErr.HTTPStatus(),
+			Status:     http.StatusText(queryErr.HTTPStatus()),
+			Body:       io.NopCloser(strings.NewReader(queryErr.Error())),
+		})
+	}
+
+	return NewHTTPToAsyncResponse(&http.Response{
+		StatusCode: queryErr.HTTPStatus(),
+		Status:     http.StatusText(queryErr.HTTPStatus()),
+		Header:     http.Header{api.HeaderContentType: {api.HeaderAcceptJSON}},
+		Body:       io.NopCloser(strings.NewReader(queryErr.Body())),
+	})
+}
+
 // NewSuccessfulResponse creates a new AsyncResponse that wraps a single http.Response with a 200 status code and the provided body.
 func NewSuccessfulResponse(body string) Responses[combiner.PipelineResponse] {
 	return NewHTTPToAsyncResponse(&http.Response{
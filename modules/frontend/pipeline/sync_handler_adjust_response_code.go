@@ -1,7 +1,12 @@
 package pipeline
 
 import (
+	"bytes"
+	"io"
 	"net/http"
+	"strings"
+
+	"github.com/grafana/tempo/pkg/api"
 )
 
 type statusCodeAdjustWare struct {
@@ -45,10 +50,36 @@ func (c statusCodeAdjustWare) RoundTrip(req *http.Request) (*http.Response, erro
 	// internal error
 	// exceptions
 	//   429 - too many requests
+	//   errors classified by the queriers other than bad requests, e.g. exceeded limits
 	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != 429 {
+		var body []byte
+		if resp.Body != nil {
+			body, err = io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+		}
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		if !api.IsQueryErrorBody(body) {
+			// leave the body alone. it will preserve the original error message
+			resp.StatusCode = http.StatusInternalServerError
+			resp.Status = http.StatusText(http.StatusInternalServerError)
+			return resp, nil
+		}
+
+		queryErr := api.ParseQueryError(resp.StatusCode, body)
+		if queryErr.Code != api.ErrorCodeBadRequest {
+			return resp, nil
+		}
+
+		queryErr.Code = api.ErrorCodeInternal
+		queryErr.Retryable = true
 		resp.StatusCode = http.StatusInternalServerError
 		resp.Status = http.StatusText(http.StatusInternalServerError)
-		// leave the body alone. it will preserve the original error message
+		resp.Body = io.NopCloser(strings.NewReader(queryErr.Body()))
+		resp.ContentLength = -1
 	}
 
 	return resp, nil
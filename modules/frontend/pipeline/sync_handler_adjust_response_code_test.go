@@ -1,11 +1,16 @@
 package pipeline
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/api"
 )
 
 func TestAdjustsResponseCode(t *testing.T) {
@@ -37,6 +42,38 @@ func TestAdjustsResponseCode(t *testing.T) {
 	}
 }
 
+func TestAdjustsResponseCodeQueryError(t *testing.T) {
+	tcs := []struct {
+		queryErr     *api.QueryError
+		expectedCode int
+		expectedErr  *api.QueryError
+	}{
+		{
+			queryErr:     api.NewQueryError(api.ErrorCodeLimitExceeded, errors.New("trace too large")).WithLimit("max_bytes_per_trace"),
+			expectedCode: 400,
+			expectedErr:  &api.QueryError{Code: api.ErrorCodeLimitExceeded, Message: "trace too large", Limit: "max_bytes_per_trace"},
+		},
+		{
+			queryErr:     api.NewQueryError(api.ErrorCodeBadRequest, errors.New("bad block id")),
+			expectedCode: 500,
+			expectedErr:  &api.QueryError{Code: api.ErrorCodeInternal, Message: "bad block id", Retryable: true},
+		},
+	}
+
+	for _, tc := range tcs {
+		handler := NewStatusCodeAdjustWare().Wrap(RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: tc.queryErr.HTTPStatus(), Body: io.NopCloser(strings.NewReader(tc.queryErr.Body()))}, nil
+		}))
+		res, err := handler.RoundTrip(httptest.NewRequest("GET", "http://example.com", nil))
+		require.NoError(t, err)
+		require.Equal(t, tc.expectedCode, res.StatusCode)
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.Equal(t, tc.expectedErr.Body(), string(body))
+	}
+}
+
 func TestAdjustsResponseCodeTeapotAllowed(t *testing.T) {
 	nextFn := func(status int) http.RoundTripper {
 		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
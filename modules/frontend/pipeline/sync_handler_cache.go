@@ -5,6 +5,7 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
@@ -74,6 +75,11 @@ func (c cachingWare) RoundTrip(req *http.Request) (*http.Response, error) {
 		return resp, nil
 	}
 
+	// do not cache the empty response of a skipped block
+	if resp.Header.Get(headerSkippedBlock) != "" {
+		return resp, nil
+	}
+
 	if len(key) > 0 {
 		// cache the response
 		//  todo: currently this is blindly caching any 200 status codes. it would be a bug, but it's possible for a querier
@@ -89,7 +95,8 @@ func (c cachingWare) RoundTrip(req *http.Request) (*http.Response, error) {
 			return resp, nil
 		}
 
-		c.cache.store(req.Context(), key, b)
+		ttl, _ := req.Context().Value(contextCacheTTL).(time.Duration)
+		c.cache.store(req.Context(), key, b, ttl)
 	}
 
 	return resp, nil
@@ -120,8 +127,9 @@ func newFrontendCache(cacheProvider cache.Provider, role cache.Role, logger log.
 	}
 }
 
-// store stores the response body in the cache. the caller assumes the responsibility of closing the response body
-func (c *frontendCache) store(ctx context.Context, key string, buffer []byte) {
+// store stores the response body in the cache. a ttl of 0 uses the configured expiration of the cache. the caller
+// assumes the responsibility of closing the response body
+func (c *frontendCache) store(ctx context.Context, key string, buffer []byte, ttl time.Duration) {
 	if c.c == nil {
 		return
 	}
@@ -134,7 +142,7 @@ func (c *frontendCache) store(ctx context.Context, key string, buffer []byte) {
 		return
 	}
 
-	c.c.Store(ctx, []string{key}, [][]byte{buffer})
+	cache.StoreWithTTL(ctx, c.c, []string{key}, [][]byte{buffer}, ttl)
 }
 
 // fetch fetches the response body from the cache. the caller assumes the responsibility of closing the response body.
@@ -3,7 +3,12 @@ package pipeline
 import (
 	"bytes"
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/gogo/protobuf/jsonpb"
@@ -36,7 +41,7 @@ func TestCacheCaches(t *testing.T) {
 	require.NotNil(t, c)
 
 	// create response
-	c.store(context.Background(), testKey, testData)
+	c.store(context.Background(), testKey, testData, 0)
 
 	actual := &tempopb.SearchTagsResponse{}
 	found := c.fetch(testKey, actual)
@@ -44,3 +49,41 @@ func TestCacheCaches(t *testing.T) {
 	require.True(t, found)
 	require.Equal(t, expected, actual)
 }
+
+func TestCachingWareTTL(t *testing.T) {
+	c := &ttlCache{Cache: cache.NewMockCache(), ttls: map[string]time.Duration{}}
+	p := test.NewMockProvider()
+	require.NoError(t, p.AddCache(cache.RoleFrontendSearch, c))
+
+	next := RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+	})
+	rt := NewCachingWare(p, cache.RoleFrontendSearch, log.NewNopLogger()).Wrap(next)
+
+	req := ContextAddCacheKey("default", httptest.NewRequest(http.MethodGet, "/", nil))
+	_, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+
+	req = ContextAddCacheTTL(time.Hour, ContextAddCacheKey("ttl", httptest.NewRequest(http.MethodGet, "/", nil)))
+	_, err = rt.RoundTrip(req)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]time.Duration{"default": 0, "ttl": time.Hour}, c.ttls)
+}
+
+// ttlCache records the TTLs keys are stored with.
+type ttlCache struct {
+	cache.Cache
+	ttls map[string]time.Duration
+}
+
+func (c *ttlCache) Store(ctx context.Context, keys []string, bufs [][]byte) {
+	c.StoreWithTTL(ctx, keys, bufs, 0)
+}
+
+func (c *ttlCache) StoreWithTTL(ctx context.Context, keys []string, bufs [][]byte, ttl time.Duration) {
+	for _, k := range keys {
+		c.ttls[k] = ttl
+	}
+	c.Cache.Store(ctx, keys, bufs)
+}
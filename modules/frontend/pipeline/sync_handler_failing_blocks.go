@@ -0,0 +1,182 @@
+package pipeline
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/api"
+)
+
+const (
+	// paramBlockID is the query parameter of the sub-requests that read a single block
+	paramBlockID = "blockID"
+
+	// headerSkippedBlock marks the empty response of a skipped block so it isn't cached
+	headerSkippedBlock = "X-Tempo-Skipped-Block"
+)
+
+// NewFailingBlocksWare returns a middleware that skips the sub-requests of blocks that failed maxFailures times
+// within skipFor. A skipped block returns an empty response, so the query returns partial results instead of
+// failing, and is listed in HeaderSkippedBlocks. Blocks are skipped for skipFor. It is shared by all pipelines so
+// failures of a block are counted across queries. maxFailures of 0 disables skipping.
+func NewFailingBlocksWare(maxFailures int, skipFor time.Duration, registerer prometheus.Registerer) Middleware {
+	skippedCount := promauto.With(registerer).NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "query_frontend_skipped_block_requests_total",
+		Help:      "Total number of sub-requests skipped because their block keeps failing.",
+	})
+
+	blocks := &failingBlocks{
+		maxFailures: maxFailures,
+		skipFor:     skipFor,
+		blocks:      map[string]*failingBlock{},
+	}
+
+	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
+		if maxFailures <= 0 {
+			return next
+		}
+
+		return failingBlocksWare{
+			next:         next,
+			blocks:       blocks,
+			skippedCount: skippedCount,
+		}
+	})
+}
+
+type failingBlocksWare struct {
+	next         http.RoundTripper
+	blocks       *failingBlocks
+	skippedCount prometheus.Counter
+}
+
+// RoundTrip implements http.RoundTripper
+func (f failingBlocksWare) RoundTrip(req *http.Request) (*http.Response, error) {
+	blockID := req.URL.Query().Get(paramBlockID)
+	if blockID == "" {
+		return f.next.RoundTrip(req)
+	}
+
+	if f.blocks.skipping(blockID, time.Now()) {
+		return f.skip(req, blockID), nil
+	}
+
+	resp, err := f.next.RoundTrip(req)
+
+	if !isDataError(resp, err) {
+		if err == nil && resp != nil && resp.StatusCode == http.StatusOK {
+			f.blocks.succeeded(blockID, time.Now())
+		}
+		return resp, err
+	}
+
+	if f.blocks.failed(blockID, time.Now()) {
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+		return f.skip(req, blockID), nil
+	}
+
+	return resp, err
+}
+
+func (f failingBlocksWare) skip(req *http.Request, blockID string) *http.Response {
+	f.skippedCount.Inc()
+	queryStateFromContext(req.Context()).skipBlock(blockID)
+
+	if span := opentracing.SpanFromContext(req.Context()); span != nil {
+		span.SetTag("skipped_block", blockID)
+	}
+
+	// every response of the pipeline is a json encoded proto. an empty object is a valid empty result.
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     http.StatusText(http.StatusOK),
+		Header: http.Header{
+			api.HeaderContentType: {api.HeaderAcceptJSON},
+			headerSkippedBlock:    {blockID},
+		},
+		Body: io.NopCloser(strings.NewReader("{}")),
+	}
+}
+
+// isDataError returns true if a sub-request failed with an internal error of the querier, which is how failures to
+// read a block are returned. Errors that aren't specific to the block, like a full queue, aren't counted.
+func isDataError(resp *http.Response, err error) bool {
+	if err != nil {
+		httpResp, ok := httpgrpc.HTTPResponseFromError(err)
+		return ok && httpResp.Code == http.StatusInternalServerError
+	}
+	return resp != nil && resp.StatusCode == http.StatusInternalServerError
+}
+
+// failingBlocks counts the failures of blocks and which blocks are skipped
+type failingBlocks struct {
+	maxFailures int
+	skipFor     time.Duration
+
+	mtx    sync.Mutex
+	blocks map[string]*failingBlock
+}
+
+type failingBlock struct {
+	failures     int
+	firstFailure time.Time
+	skipUntil    time.Time
+}
+
+func (f *failingBlocks) skipping(blockID string, now time.Time) bool {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	b, ok := f.blocks[blockID]
+	return ok && now.Before(b.skipUntil)
+}
+
+// failed records a failure of the block and returns true if the block is skipped from now on
+func (f *failingBlocks) failed(blockID string, now time.Time) bool {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	// drop the blocks that haven't failed recently. failures are rare so the sweep is cheap.
+	for id, b := range f.blocks {
+		if now.Sub(b.firstFailure) > f.skipFor && !now.Before(b.skipUntil) {
+			delete(f.blocks, id)
+		}
+	}
+
+	b, ok := f.blocks[blockID]
+	if !ok {
+		b = &failingBlock{firstFailure: now}
+		f.blocks[blockID] = b
+	}
+
+	b.failures++
+	if b.failures < f.maxFailures {
+		return false
+	}
+
+	b.failures = 0
+	b.firstFailure = now
+	b.skipUntil = now.Add(f.skipFor)
+	return true
+}
+
+// succeeded forgets the failures of a block that isn't skipped
+func (f *failingBlocks) succeeded(blockID string, now time.Time) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if b, ok := f.blocks[blockID]; ok && !now.Before(b.skipUntil) {
+		delete(f.blocks, blockID)
+	}
+}
@@ -0,0 +1,90 @@
+package pipeline
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestFailingBlocksWare(t *testing.T) {
+	var tries atomic.Int32
+
+	handler := NewFailingBlocksWare(2, time.Minute, prometheus.NewRegistry()).Wrap(RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		tries.Inc()
+		if req.URL.Query().Get(paramBlockID) == "good" {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(nil)}, nil
+		}
+		return &http.Response{StatusCode: http.StatusInternalServerError, Body: io.NopCloser(nil)}, nil
+	}))
+
+	ctx, state := contextWithQueryState(context.Background())
+	roundTrip := func(blockID string) *http.Response {
+		req := httptest.NewRequest("GET", "http://example.com/querier/api/search?blockID="+blockID, nil).WithContext(ctx)
+		resp, err := handler.RoundTrip(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	// the first failure is returned
+	resp := roundTrip("bad")
+	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	require.Empty(t, state.skipped())
+
+	// the second failure skips the block
+	resp = roundTrip("bad")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "{}", string(body))
+	require.Equal(t, []string{"bad"}, state.skipped())
+	require.Equal(t, int32(2), tries.Load())
+
+	// the block is skipped without a request
+	resp = roundTrip("bad")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, int32(2), tries.Load())
+
+	// other blocks aren't affected
+	resp = roundTrip("good")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, int32(3), tries.Load())
+	require.Equal(t, []string{"bad"}, state.skipped())
+
+	// requests that don't read a block are never skipped
+	for i := 0; i < 3; i++ {
+		resp = roundTrip("")
+		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	}
+}
+
+func TestFailingBlocksExpire(t *testing.T) {
+	blocks := &failingBlocks{
+		maxFailures: 2,
+		skipFor:     time.Minute,
+		blocks:      map[string]*failingBlock{},
+	}
+	now := time.Now()
+
+	// failures further apart than skipFor don't add up
+	require.False(t, blocks.failed("a", now))
+	require.False(t, blocks.failed("a", now.Add(2*time.Minute)))
+	require.True(t, blocks.failed("a", now.Add(150*time.Second)))
+
+	// the block is skipped for skipFor
+	require.True(t, blocks.skipping("a", now.Add(3*time.Minute)))
+	blocks.succeeded("a", now.Add(3*time.Minute))
+	require.True(t, blocks.skipping("a", now.Add(3*time.Minute)))
+	require.False(t, blocks.skipping("a", now.Add(4*time.Minute)))
+
+	// a success forgets the failures
+	require.False(t, blocks.failed("b", now))
+	blocks.succeeded("b", now)
+	require.False(t, blocks.failed("b", now))
+}
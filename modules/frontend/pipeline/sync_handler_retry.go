@@ -16,27 +16,38 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
-func NewRetryWare(maxRetries int, registerer prometheus.Registerer) Middleware {
+// NewRetryWare returns a middleware that retries failed requests up to maxRetries times. retryBudget limits the
+// retries of all sub-requests of a query, so a query with many failing sub-requests fails fast. 0 is unlimited.
+func NewRetryWare(maxRetries, retryBudget int, registerer prometheus.Registerer) Middleware {
 	retriesCount := promauto.With(registerer).NewHistogram(prometheus.HistogramOpts{
 		Namespace: "tempo",
 		Name:      "query_frontend_retries",
 		Help:      "Number of times a request is retried.",
 		Buckets:   []float64{0, 1, 2, 3, 4, 5},
 	})
+	budgetExhausted := promauto.With(registerer).NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "query_frontend_retry_budget_exhausted_total",
+		Help:      "Total number of failed requests not retried because their query exhausted its retry budget.",
+	})
 
 	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
 		return retryWare{
-			next:         next,
-			maxRetries:   maxRetries,
-			retriesCount: retriesCount,
+			next:            next,
+			maxRetries:      maxRetries,
+			retryBudget:     retryBudget,
+			retriesCount:    retriesCount,
+			budgetExhausted: budgetExhausted,
 		}
 	})
 }
 
 type retryWare struct {
-	next         http.RoundTripper
-	maxRetries   int
-	retriesCount prometheus.Histogram
+	next            http.RoundTripper
+	maxRetries      int
+	retryBudget     int
+	retriesCount    prometheus.Histogram
+	budgetExhausted prometheus.Counter
 }
 
 // RoundTrip implements http.RoundTripper
@@ -94,6 +105,13 @@ func (r retryWare) RoundTrip(req *http.Request) (*http.Response, error) {
 			return resp, err
 		}
 
+		// the retries of the query are shared by all its sub-requests
+		if !queryStateFromContext(ctx).takeRetry(r.retryBudget) {
+			r.budgetExhausted.Inc()
+			span.LogFields(ot_log.String("msg", "retry budget of the query exhausted"))
+			return resp, err
+		}
+
 		statusCode := 0
 		if resp != nil {
 			statusCode = resp.StatusCode
@@ -109,7 +109,7 @@ func TestRetry(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			try.Store(0)
 
-			retryWare := NewRetryWare(tc.maxRetries, prometheus.NewRegistry())
+			retryWare := NewRetryWare(tc.maxRetries, 0, prometheus.NewRegistry())
 			handler := retryWare.Wrap(tc.handler)
 
 			req := httptest.NewRequest("GET", "http://example.com", nil)
@@ -133,7 +133,7 @@ func TestRetry_CancelledRequest(t *testing.T) {
 	req, err := http.NewRequestWithContext(ctx, "GET", "http://example.com", nil)
 	require.NoError(t, err)
 
-	_, err = NewRetryWare(5, prometheus.NewRegistry()).
+	_, err = NewRetryWare(5, 0, prometheus.NewRegistry()).
 		Wrap(RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 			try.Inc()
 			return nil, ctx.Err()
@@ -148,7 +148,7 @@ func TestRetry_CancelledRequest(t *testing.T) {
 	req, err = http.NewRequestWithContext(ctx, "GET", "http://example.com", nil)
 	require.NoError(t, err)
 
-	_, err = NewRetryWare(5, prometheus.NewRegistry()).
+	_, err = NewRetryWare(5, 0, prometheus.NewRegistry()).
 		Wrap(RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 			try.Inc()
 			cancel()
@@ -158,3 +158,37 @@ func TestRetry_CancelledRequest(t *testing.T) {
 	require.Equal(t, int32(1), try.Load())
 	require.Equal(t, ctx.Err(), err)
 }
+
+func TestRetry_Budget(t *testing.T) {
+	var try atomic.Int32
+
+	handler := NewRetryWare(5, 2, prometheus.NewRegistry()).
+		Wrap(RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			try.Inc()
+			return &http.Response{StatusCode: 503}, nil
+		}))
+
+	// all sub-requests of a query share the budget
+	ctx, _ := contextWithQueryState(context.Background())
+	req, err := http.NewRequestWithContext(ctx, "GET", "http://example.com", nil)
+	require.NoError(t, err)
+
+	res, err := handler.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, 503, res.StatusCode)
+	require.Equal(t, int32(3), try.Load())
+
+	_, err = handler.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, int32(4), try.Load())
+
+	// a new query has a new budget
+	try.Store(0)
+	ctx, _ = contextWithQueryState(context.Background())
+	req, err = http.NewRequestWithContext(ctx, "GET", "http://example.com", nil)
+	require.NoError(t, err)
+
+	_, err = handler.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), try.Load())
+}
@@ -25,6 +25,8 @@ var (
 // of RequestQueue.GetNextRequestForQuerier method.
 type UserIndex struct {
 	last int
+	// Number of consecutive batches pulled from the last user.
+	served int
 }
 
 // Modify index to start iteration on the same user, for which last queue was returned.
@@ -43,6 +45,18 @@ func FirstUser() UserIndex {
 // Request stored into the queue.
 type Request interface{}
 
+// UserLimits are the user-specific limits of the queue.
+type UserLimits struct {
+	// MaxQueriers is how many queriers this user can use (zero or negative = all queriers).
+	MaxQueriers int
+	// MaxInflight is how many requests of this user can be processed by queriers at once (zero or negative = no limit).
+	// Users at their limit are skipped and their queriers pull requests of other users instead.
+	MaxInflight int
+	// Weight is how many consecutive batches a querier pulls from this user before moving on to the next one
+	// (zero or negative = 1).
+	Weight int
+}
+
 // RequestQueue holds incoming requests in per-user queues. It also assigns each user specified number of queriers,
 // and when querier asks for next request to handle (using GetNextRequestForQuerier), it returns requests
 // in a fair fashion.
@@ -58,14 +72,16 @@ type RequestQueue struct {
 
 	queueLength       *prometheus.GaugeVec   // Per user and reason.
 	discardedRequests *prometheus.CounterVec // Per user.
+	inflightRequests  *prometheus.GaugeVec   // Per user.
 }
 
-func NewRequestQueue(maxOutstandingPerTenant int, forgetDelay time.Duration, queueLength *prometheus.GaugeVec, discardedRequests *prometheus.CounterVec) *RequestQueue {
+func NewRequestQueue(maxOutstandingPerTenant int, forgetDelay time.Duration, queueLength *prometheus.GaugeVec, discardedRequests *prometheus.CounterVec, inflightRequests *prometheus.GaugeVec) *RequestQueue {
 	q := &RequestQueue{
 		queues:                  newUserQueues(maxOutstandingPerTenant, forgetDelay),
 		connectedQuerierWorkers: atomic.NewInt32(0),
 		queueLength:             queueLength,
 		discardedRequests:       discardedRequests,
+		inflightRequests:        inflightRequests,
 	}
 
 	q.cond = contextCond{Cond: sync.NewCond(&q.mtx)}
@@ -74,12 +90,9 @@ func NewRequestQueue(maxOutstandingPerTenant int, forgetDelay time.Duration, que
 	return q
 }
 
-// EnqueueRequest puts the request into the queue. MaxQueries is user-specific value that specifies how many queriers can
-// this user use (zero or negative = all queriers). It is passed to each EnqueueRequest, because it can change
-// between calls.
-//
-// If request is successfully enqueued, successFn is called with the lock held, before any querier can receive the request.
-func (q *RequestQueue) EnqueueRequest(userID string, req Request, maxQueriers int) error {
+// EnqueueRequest puts the request into the queue. Limits are the user-specific limits of the queue. They are passed to
+// each EnqueueRequest, because they can change between calls.
+func (q *RequestQueue) EnqueueRequest(userID string, req Request, limits UserLimits) error {
 	q.mtx.RLock()
 	// don't defer a release. we won't know what we need to release until we call getQueueUnderRlock
 
@@ -89,7 +102,7 @@ func (q *RequestQueue) EnqueueRequest(userID string, req Request, maxQueriers in
 	}
 
 	// try to grab the user queue under read lock
-	queue, cleanup, err := q.getQueueUnderRlock(userID, maxQueriers)
+	queue, cleanup, err := q.getQueueUnderRlock(userID, limits)
 	defer cleanup()
 	if err != nil {
 		return err
@@ -109,13 +122,13 @@ func (q *RequestQueue) EnqueueRequest(userID string, req Request, maxQueriers in
 // getQueueUnderRlock attempts to get the queue for the given user under read lock. if it is not
 // possible it upgrades the RLock to a Lock. This method also returns a cleanup function that
 // will release whichever lock it had to acquire to get the queue.
-func (q *RequestQueue) getQueueUnderRlock(userID string, maxQueriers int) (chan Request, func(), error) {
+func (q *RequestQueue) getQueueUnderRlock(userID string, limits UserLimits) (chan Request, func(), error) {
 	cleanup := func() {
 		q.mtx.RUnlock()
 	}
 
 	uq := q.queues.userQueues[userID]
-	if uq != nil {
+	if uq != nil && uq.hasLimits(limits) {
 		return uq.ch, cleanup, nil
 	}
 
@@ -128,7 +141,7 @@ func (q *RequestQueue) getQueueUnderRlock(userID string, maxQueriers int) (chan
 		q.mtx.Unlock()
 	}
 
-	queue := q.queues.getOrAddQueue(userID, maxQueriers)
+	queue := q.queues.getOrAddQueue(userID, limits)
 	if queue == nil {
 		// This can only happen if userID is "".
 		return nil, cleanup, errors.New("no queue found")
@@ -138,7 +151,8 @@ func (q *RequestQueue) getQueueUnderRlock(userID string, maxQueriers int) (chan
 }
 
 // GetNextRequestForQuerier find next user queue and attempts to dequeue N requests as defined by the length of
-// batchBuffer. This slice is a reusable buffer to fill up with requests
+// batchBuffer. This slice is a reusable buffer to fill up with requests. The returned requests count as inflight for
+// the user until they are released with ReleaseRequests.
 func (q *RequestQueue) GetNextRequestForQuerier(ctx context.Context, last UserIndex, querierID string, batchBuffer []Request) ([]Request, UserIndex, error) {
 	requestedCount := len(batchBuffer)
 	if requestedCount == 0 {
@@ -165,26 +179,31 @@ FindQueue:
 		return nil, last, err
 	}
 
-	queue, userID, idx := q.queues.getNextQueueForQuerier(last.last, querierID)
-	last.last = idx
-	if queue != nil {
+	uq, userID, last := q.queues.getNextQueueForQuerier(last, querierID)
+	if uq != nil {
 		// this is all threadsafe b/c all users queues are blocked by q.mtx
-		if len(queue) < requestedCount {
-			requestedCount = len(queue)
+		if len(uq.ch) < requestedCount {
+			requestedCount = len(uq.ch)
+		}
+		if available := uq.availableInflight(q.queues.inflight[userID]); available < requestedCount {
+			requestedCount = available
 		}
 
 		// Pick next requests from the queue.
 		batchBuffer = batchBuffer[:requestedCount]
 		for i := 0; i < requestedCount; i++ {
-			batchBuffer[i] = <-queue
+			batchBuffer[i] = <-uq.ch
 		}
 
-		qLen := len(queue)
+		qLen := len(uq.ch)
 		if qLen == 0 {
 			q.queues.deleteQueue(userID)
 		}
 		q.queueLength.WithLabelValues(userID).Set(float64(qLen))
 
+		q.queues.inflight[userID] += requestedCount
+		q.inflightRequests.WithLabelValues(userID).Set(float64(q.queues.inflight[userID]))
+
 		// Tell close() we've processed a request.
 		q.cond.Broadcast()
 
@@ -197,6 +216,24 @@ FindQueue:
 	goto FindQueue
 }
 
+// ReleaseRequests marks count requests of the user returned by GetNextRequestForQuerier as processed.
+func (q *RequestQueue) ReleaseRequests(userID string, count int) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	inflight := q.queues.inflight[userID] - count
+	if inflight <= 0 {
+		delete(q.queues.inflight, userID)
+		inflight = 0
+	} else {
+		q.queues.inflight[userID] = inflight
+	}
+	q.inflightRequests.WithLabelValues(userID).Set(float64(inflight))
+
+	// Queriers waiting for requests may be able to handle this user again.
+	q.cond.Broadcast()
+}
+
 func (q *RequestQueue) forgetDisconnectedQueriers(_ context.Context) error {
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
@@ -253,6 +290,18 @@ func (q *RequestQueue) GetConnectedQuerierWorkersMetric() float64 {
 	return float64(q.connectedQuerierWorkers.Load())
 }
 
+// QueueLength returns the number of requests waiting in the queues of all users.
+func (q *RequestQueue) QueueLength() int {
+	q.mtx.RLock()
+	defer q.mtx.RUnlock()
+
+	length := 0
+	for _, uq := range q.queues.userQueues {
+		length += len(uq.ch)
+	}
+	return length
+}
+
 // contextCond is a *sync.Cond with Wait() method overridden to support context-based waiting.
 type contextCond struct {
 	*sync.Cond
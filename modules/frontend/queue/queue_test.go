@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/dskit/services"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
@@ -37,7 +38,7 @@ func TestGetNextForQuerierOneUser(t *testing.T) {
 	close(start)
 
 	for j := 0; j < messages; j++ {
-		err := q.EnqueueRequest("test", &mockRequest{}, 0)
+		err := q.EnqueueRequest("test", &mockRequest{}, UserLimits{})
 		require.NoError(t, err)
 	}
 
@@ -65,7 +66,7 @@ func TestGetNextForQuerierRandomUsers(t *testing.T) {
 	close(start)
 
 	for j := 0; j < messages; j++ {
-		err := q.EnqueueRequest(test.RandomString(), &mockRequest{}, 0)
+		err := q.EnqueueRequest(test.RandomString(), &mockRequest{}, UserLimits{})
 		require.NoError(t, err)
 	}
 
@@ -93,7 +94,7 @@ func TestGetNextBatches(t *testing.T) {
 	close(start)
 
 	for j := 0; j < messages; j++ {
-		err := q.EnqueueRequest("user", &mockRequest{}, 0)
+		err := q.EnqueueRequest("user", &mockRequest{}, UserLimits{})
 		require.NoError(t, err)
 	}
 
@@ -136,7 +137,7 @@ func benchmarkGetNextForQuerier(b *testing.B, listeners int, messages int) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		for j := 0; j < messages; j++ {
-			err := q.EnqueueRequest(user, req, 0)
+			err := q.EnqueueRequest(user, req, UserLimits{})
 			if err != nil {
 				panic(err)
 			}
@@ -160,7 +161,11 @@ func queueWithListeners(ctx context.Context, listeners int, batchSize int, liste
 		Name: "test_discarded",
 	}, []string{"user"})
 
-	q := NewRequestQueue(100_000, 0, g, c)
+	i := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "test_inflight",
+	}, []string{"user"})
+
+	q := NewRequestQueue(100_000, 0, g, c, i)
 	start := make(chan struct{})
 
 	for i := 0; i < listeners; i++ {
@@ -192,7 +197,8 @@ func TestRequestQueue_GetNextRequestForQuerier_ShouldGetRequestAfterReshardingBe
 
 	queue := NewRequestQueue(1, forgetDelay,
 		prometheus.NewGaugeVec(prometheus.GaugeOpts{}, []string{"user"}),
-		prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"user"}))
+		prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"user"}),
+		prometheus.NewGaugeVec(prometheus.GaugeOpts{}, []string{"user"}))
 
 	// Start the queue service.
 	ctx := context.Background()
@@ -219,7 +225,7 @@ func TestRequestQueue_GetNextRequestForQuerier_ShouldGetRequestAfterReshardingBe
 
 	// Enqueue a request from an user which would be assigned to querier-1.
 	// NOTE: "user-1" hash falls in the querier-1 shard.
-	require.NoError(t, queue.EnqueueRequest("user-1", "request", 1))
+	require.NoError(t, queue.EnqueueRequest("user-1", "request", UserLimits{MaxQueriers: 1}))
 
 	startTime := time.Now()
 	querier2wg.Wait()
@@ -229,6 +235,98 @@ func TestRequestQueue_GetNextRequestForQuerier_ShouldGetRequestAfterReshardingBe
 	assert.GreaterOrEqual(t, waitTime.Milliseconds(), forgetDelay.Milliseconds())
 }
 
+func TestRequestQueue_MaxInflightSpillsOverToOtherUsers(t *testing.T) {
+	inflight := prometheus.NewGaugeVec(prometheus.GaugeOpts{}, []string{"user"})
+	queue := NewRequestQueue(100, 0,
+		prometheus.NewGaugeVec(prometheus.GaugeOpts{}, []string{"user"}),
+		prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"user"}),
+		inflight)
+
+	ctx := context.Background()
+	queue.RegisterQuerierConnection("querier")
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, queue.EnqueueRequest("batch", "batch", UserLimits{MaxInflight: 3}))
+	}
+	require.NoError(t, queue.EnqueueRequest("interactive", "interactive", UserLimits{}))
+
+	// batches are capped at the inflight limit of the user
+	reqs, last, err := queue.GetNextRequestForQuerier(ctx, FirstUser(), "querier", make([]Request, 5))
+	require.NoError(t, err)
+	require.Equal(t, []Request{"batch", "batch", "batch"}, reqs)
+	require.Equal(t, 3.0, testutil.ToFloat64(inflight.WithLabelValues("batch")))
+
+	// the batch user is at its limit, its queue is skipped until requests are released
+	reqs, last, err = queue.GetNextRequestForQuerier(ctx, last, "querier", make([]Request, 5))
+	require.NoError(t, err)
+	require.Equal(t, []Request{"interactive"}, reqs)
+
+	waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, _, err = queue.GetNextRequestForQuerier(waitCtx, last, "querier", make([]Request, 5))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	queue.ReleaseRequests("batch", 2)
+	require.Equal(t, 1.0, testutil.ToFloat64(inflight.WithLabelValues("batch")))
+
+	reqs, _, err = queue.GetNextRequestForQuerier(ctx, last, "querier", make([]Request, 5))
+	require.NoError(t, err)
+	require.Len(t, reqs, 2)
+}
+
+func TestRequestQueue_WeightedScheduling(t *testing.T) {
+	queue := NewRequestQueue(100, 0,
+		prometheus.NewGaugeVec(prometheus.GaugeOpts{}, []string{"user"}),
+		prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"user"}),
+		prometheus.NewGaugeVec(prometheus.GaugeOpts{}, []string{"user"}))
+
+	ctx := context.Background()
+	queue.RegisterQuerierConnection("querier")
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, queue.EnqueueRequest("user-1", "user-1", UserLimits{Weight: 3}))
+		require.NoError(t, queue.EnqueueRequest("user-2", "user-2", UserLimits{}))
+	}
+
+	var served []Request
+	last := FirstUser()
+	for i := 0; i < 10; i++ {
+		var reqs []Request
+		var err error
+		reqs, last, err = queue.GetNextRequestForQuerier(ctx, last, "querier", make([]Request, 1))
+		require.NoError(t, err)
+		served = append(served, reqs...)
+	}
+
+	require.Equal(t, []Request{
+		"user-1", "user-1", "user-1", "user-2",
+		"user-1", "user-1", "user-2",
+		"user-2", "user-2", "user-2",
+	}, served)
+}
+
+func TestRequestQueue_QueueLength(t *testing.T) {
+	queue := NewRequestQueue(100, 0,
+		prometheus.NewGaugeVec(prometheus.GaugeOpts{}, []string{"user"}),
+		prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"user"}),
+		prometheus.NewGaugeVec(prometheus.GaugeOpts{}, []string{"user"}))
+
+	ctx := context.Background()
+	queue.RegisterQuerierConnection("querier")
+	require.Equal(t, 0, queue.QueueLength())
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, queue.EnqueueRequest("user-1", "user-1", UserLimits{}))
+		require.NoError(t, queue.EnqueueRequest("user-2", "user-2", UserLimits{}))
+	}
+	require.Equal(t, 6, queue.QueueLength())
+
+	reqs, _, err := queue.GetNextRequestForQuerier(ctx, FirstUser(), "querier", make([]Request, 2))
+	require.NoError(t, err)
+	require.Len(t, reqs, 2)
+	require.Equal(t, 4, queue.QueueLength())
+}
+
 func TestContextCond(t *testing.T) {
 	t.Run("wait until broadcast", func(t *testing.T) {
 		t.Parallel()
@@ -1,6 +1,7 @@
 package queue
 
 import (
+	"math"
 	"math/rand"
 	"sort"
 	"time"
@@ -41,6 +42,10 @@ type queues struct {
 
 	// Sorted list of querier names, used when creating per-user shard.
 	sortedQueriers []string
+
+	// Number of requests per user that are processed by queriers. Users are kept here after their queue is
+	// deleted until all their requests are released.
+	inflight map[string]int
 }
 
 type userQueue struct {
@@ -51,6 +56,12 @@ type userQueue struct {
 	queriers    map[string]struct{}
 	maxQueriers int
 
+	// Max number of inflight requests of the user, 0 is no limit.
+	maxInflight int
+
+	// Number of consecutive batches a querier pulls from this user before moving on to the next one.
+	weight int
+
 	// Seed for shuffle sharding of queriers. This seed is based on userID only and is therefore consistent
 	// between different frontends.
 	seed int64
@@ -67,7 +78,23 @@ func newUserQueues(maxUserQueueSize int, forgetDelay time.Duration) *queues {
 		forgetDelay:      forgetDelay,
 		queriers:         map[string]*querier{},
 		sortedQueriers:   nil,
+		inflight:         map[string]int{},
+	}
+}
+
+// hasLimits returns true if the queue is configured with the given limits.
+func (uq *userQueue) hasLimits(limits UserLimits) bool {
+	return uq.maxQueriers == max(limits.MaxQueriers, 0) &&
+		uq.maxInflight == max(limits.MaxInflight, 0) &&
+		uq.weight == max(limits.Weight, 1)
+}
+
+// availableInflight returns how many more requests of the user can be processed by queriers.
+func (uq *userQueue) availableInflight(inflight int) int {
+	if uq.maxInflight == 0 {
+		return math.MaxInt
 	}
+	return max(uq.maxInflight-inflight, 0)
 }
 
 func (q *queues) len() int {
@@ -93,15 +120,14 @@ func (q *queues) deleteQueue(userID string) {
 // MaxQueriers is used to compute which queriers should handle requests for this user.
 // If maxQueriers is <= 0, all queriers can handle this user's requests.
 // If maxQueriers has changed since the last call, queriers for this are recomputed.
-func (q *queues) getOrAddQueue(userID string, maxQueriers int) chan Request {
+// The inflight limit and weight of the queue are updated to the given limits.
+func (q *queues) getOrAddQueue(userID string, limits UserLimits) chan Request {
 	// Empty user is not allowed, as that would break our users list ("" is used for free spot).
 	if userID == "" {
 		return nil
 	}
 
-	if maxQueriers < 0 {
-		maxQueriers = 0
-	}
+	maxQueriers := max(limits.MaxQueriers, 0)
 
 	uq := q.userQueues[userID]
 
@@ -133,15 +159,23 @@ func (q *queues) getOrAddQueue(userID string, maxQueriers int) chan Request {
 		uq.maxQueriers = maxQueriers
 		uq.queriers = shuffleQueriersForUser(uq.seed, maxQueriers, q.sortedQueriers, nil)
 	}
+	uq.maxInflight = max(limits.MaxInflight, 0)
+	uq.weight = max(limits.Weight, 1)
 
 	return uq.ch
 }
 
 // Finds next queue for the querier. To support fair scheduling between users, client is expected
 // to pass last user index returned by this function as argument. Is there was no previous
-// last user index, use -1.
-func (q *queues) getNextQueueForQuerier(lastUserIndex int, querierID string) (chan Request, string, int) {
-	uid := lastUserIndex
+// last user index, use FirstUser(). The last user is served again until it used up its weight.
+// Users with the max number of inflight requests are skipped.
+func (q *queues) getNextQueueForQuerier(last UserIndex, querierID string) (*userQueue, string, UserIndex) {
+	uid := last.last
+	if uid >= 0 && uid < len(q.users) {
+		if uq := q.userQueues[q.users[uid]]; uq != nil && last.served < uq.weight {
+			uid--
+		}
+	}
 
 	for iters := 0; iters < len(q.users); iters++ {
 		uid = uid + 1
@@ -157,18 +191,27 @@ func (q *queues) getNextQueueForQuerier(lastUserIndex int, querierID string) (ch
 			continue
 		}
 
-		q := q.userQueues[u]
+		uq := q.userQueues[u]
 
-		if q.queriers != nil {
-			if _, ok := q.queriers[querierID]; !ok {
+		if uq.queriers != nil {
+			if _, ok := uq.queriers[querierID]; !ok {
 				// This querier is not handling the user.
 				continue
 			}
 		}
 
-		return q.ch, u, uid
+		if uq.availableInflight(q.inflight[u]) == 0 {
+			// The user is at its inflight limit, spill over to the next user.
+			continue
+		}
+
+		served := 1
+		if uid == last.last {
+			served = last.served + 1
+		}
+		return uq, u, UserIndex{last: uid, served: served}
 	}
-	return nil, "", uid
+	return nil, "", UserIndex{last: uid}
 }
 
 func (q *queues) addQuerierConnection(querierID string) {
@@ -13,7 +13,10 @@ import (
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level" //nolint:all //deprecated
 	"github.com/gogo/status"
+	"github.com/grafana/dskit/tenant"
 	"github.com/grafana/dskit/user"
+	"github.com/grafana/tempo/modules/frontend/audit"
+	"github.com/grafana/tempo/modules/frontend/canary"
 	"github.com/grafana/tempo/modules/frontend/combiner"
 	"github.com/grafana/tempo/modules/frontend/pipeline"
 	"google.golang.org/grpc/codes"
@@ -23,7 +26,7 @@ import (
 )
 
 // newSearchStreamingGRPCHandler returns a handler that streams results from the HTTP handler
-func newSearchStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, logger log.Logger) streamingSearchHandler {
+func newSearchStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, auditor *audit.Auditor, logger log.Logger) streamingSearchHandler {
 	postSLOHook := searchSLOPostHook(cfg.Search.SLO)
 	downstreamPath := path.Join(apiPrefix, api.PathSearch)
 
@@ -50,7 +53,7 @@ func newSearchStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[c
 		}
 
 		var finalResponse *tempopb.SearchResponse
-		c := combiner.NewTypedSearch(int(limit))
+		c := combiner.NewTypedSearch(int(limit), keepMostRecent(cfg, tenant))
 		collector := pipeline.NewGRPCCollector[*tempopb.SearchResponse](next, cfg.ResponseConsumers, c, func(sr *tempopb.SearchResponse) error {
 			finalResponse = sr // sadly we can't srv.Send directly into the collector. we need bytesProcessed for the SLO calculations
 			return srv.Send(sr)
@@ -66,12 +69,13 @@ func newSearchStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[c
 		}
 		postSLOHook(nil, tenant, bytesProcessed, duration, err)
 		logResult(logger, tenant, duration.Seconds(), req, finalResponse, nil, err)
+		auditSearch(auditor, tenant, auditor.UserFromContext(ctx), start, req, nil, bytesProcessed, err)
 		return err
 	}
 }
 
 // newSearchHTTPHandler returns a handler that returns a single response from the HTTP handler
-func newSearchHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], logger log.Logger) http.RoundTripper {
+func newSearchHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], auditor *audit.Auditor, mirror *canary.Mirror, logger log.Logger) http.RoundTripper {
 	postSLOHook := searchSLOPostHook(cfg.Search.SLO)
 
 	return pipeline.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
@@ -101,9 +105,10 @@ func newSearchHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.P
 		}
 
 		logRequest(logger, tenant, searchReq)
+		canaryReq := mirror.Sample(searchOp, req, searchFingerprint)
 
 		// build and use roundtripper
-		combiner := combiner.NewTypedSearch(int(limit))
+		combiner := combiner.NewTypedSearch(int(limit), keepMostRecent(cfg, tenant))
 		rt := pipeline.NewHTTPCollector(next, cfg.ResponseConsumers, combiner)
 
 		resp, err := rt.RoundTrip(req)
@@ -118,10 +123,32 @@ func newSearchHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.P
 		duration := time.Since(start)
 		postSLOHook(resp, tenant, bytesProcessed, duration, err)
 		logResult(logger, tenant, duration.Seconds(), searchReq, searchResp, resp, err)
+		auditSearch(auditor, tenant, auditor.UserFromHeader(req.Header), start, searchReq, resp, bytesProcessed, err)
+		mirror.Replay(canaryReq, resp, duration, err)
 		return resp, err
 	})
 }
 
+func auditSearch(auditor *audit.Auditor, tenant, user string, start time.Time, req *tempopb.SearchRequest, resp *http.Response, bytesProcessed uint64, err error) {
+	r := audit.NewRecord(tenant, user, searchOp, start, resp, bytesProcessed, err)
+	r.Query = req.Query
+	r.Start = req.Start
+	r.End = req.End
+	auditor.Record(r)
+}
+
+// keepMostRecent returns true if the search combiner should keep the most recent traces. the search shards
+// that allow the combiner to exit early are tracked per tenant so this is disabled for multi-tenant queries. it's
+// also disabled for federated queries, the shards of the remote clusters are not known.
+func keepMostRecent(cfg Config, orgID string) bool {
+	if !cfg.Search.Sharder.MostRecentFirst || cfg.Federation.Enabled {
+		return false
+	}
+
+	tenants, err := tenant.TenantIDsFromOrgID(orgID)
+	return err == nil && len(tenants) == 1
+}
+
 // adjusts the limit based on provided config
 func adjustLimit(limit, defaultLimit, maxLimit uint32) (uint32, error) {
 	if limit == 0 {
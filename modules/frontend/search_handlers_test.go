@@ -26,6 +26,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 
+	"github.com/grafana/tempo/modules/frontend/pipeline"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/cache"
@@ -196,8 +197,8 @@ func runnerRequests(t *testing.T, f *QueryFrontend) {
 				Limit: 10,
 			},
 			expectedStatusCode:    400,
-			expectedStatusMessage: "invalid TraceQL query: parse error at line 1, col 1: syntax error: unexpected IDENTIFIER",
-			expectedErr:           status.Error(codes.InvalidArgument, "invalid TraceQL query: parse error at line 1, col 1: syntax error: unexpected IDENTIFIER"),
+			expectedStatusMessage: "invalid TraceQL query: parse error at line 1, col 1: unknown identifier foo",
+			expectedErr:           status.Error(codes.InvalidArgument, "invalid TraceQL query: parse error at line 1, col 1: unknown identifier foo"),
 		},
 		{
 			name:   "multitenant - 4 jobs x 2 tenants = 8",
@@ -549,6 +550,88 @@ func TestSearchFailurePropagatesFromQueriers(t *testing.T) {
 	}
 }
 
+func TestSearchJSONErrors(t *testing.T) {
+	limitErr := api.NewQueryError(api.ErrorCodeLimitExceeded, errors.New("trace too large")).WithLimit("max_bytes_per_trace")
+
+	tcs := []struct {
+		name           string
+		querierCode    int
+		querierMessage string
+
+		expectedCode    int
+		expectedMessage string
+	}{
+		{
+			name:            "querier json error",
+			querierCode:     400,
+			querierMessage:  limitErr.Body(),
+			expectedCode:    400,
+			expectedMessage: limitErr.Body(),
+		},
+		{
+			name:            "querier plain text error",
+			querierCode:     500,
+			querierMessage:  "querier 500",
+			expectedCode:    500,
+			expectedMessage: `{"code":"internal","message":"querier 500","retryable":true}`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			next := &mockRoundTripper{
+				statusCode:    tc.querierCode,
+				statusMessage: tc.querierMessage,
+				responseFn: func() proto.Message {
+					return &tempopb.SearchResponse{
+						Traces:  []*tempopb.TraceSearchMetadata{},
+						Metrics: &tempopb.SearchMetrics{},
+					}
+				},
+			}
+			var jsonErrors atomic.Bool
+			f := frontendWithSettings(t, pipeline.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				jsonErrors.Store(api.AcceptsJSONErrors(r))
+				return next.RoundTrip(r)
+			}), nil, &Config{
+				MultiTenantQueriesEnabled: true,
+				JSONErrors:                true,
+				TraceByID: TraceByIDConfig{
+					QueryShards: minQueryShards,
+					SLO:         testSLOcfg,
+				},
+				Search: SearchConfig{
+					Sharder: SearchSharderConfig{
+						ConcurrentRequests:    defaultConcurrentRequests,
+						TargetBytesPerRequest: defaultTargetBytesPerRequest,
+					},
+					SLO: testSLOcfg,
+				},
+				Metrics: MetricsConfig{
+					Sharder: QueryRangeSharderConfig{
+						ConcurrentRequests:    defaultConcurrentRequests,
+						TargetBytesPerRequest: defaultTargetBytesPerRequest,
+						Interval:              time.Second,
+					},
+					SLO: testSLOcfg,
+				},
+			}, nil)
+
+			httpReq := httptest.NewRequest("GET", "/api/search?start=1&end=10000", nil)
+			httpReq = httpReq.WithContext(user.InjectOrgID(httpReq.Context(), "foo"))
+			httpResp := httptest.NewRecorder()
+
+			f.SearchHandler.ServeHTTP(httpResp, httpReq)
+			require.Equal(t, tc.expectedMessage, httpResp.Body.String())
+			require.Equal(t, tc.expectedCode, httpResp.Code)
+			require.Equal(t, api.HeaderAcceptJSON, httpResp.Header().Get(api.HeaderContentType))
+
+			// the queriers are asked for json errors
+			require.True(t, jsonErrors.Load())
+		})
+	}
+}
+
 func TestSearchAccessesCache(t *testing.T) {
 	tenant := "foo"
 	meta := &backend.BlockMeta{
@@ -3,7 +3,10 @@ package frontend
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-kit/log" //nolint:all deprecated
@@ -36,6 +39,9 @@ type SearchSharderConfig struct {
 	QueryBackendAfter     time.Duration `yaml:"query_backend_after,omitempty"`
 	QueryIngestersUntil   time.Duration `yaml:"query_ingesters_until,omitempty"`
 	IngesterShards        int           `yaml:"ingester_shards,omitempty"`
+	MostRecentFirst       bool          `yaml:"most_recent_first,omitempty"`
+	TagCacheMinTTL        time.Duration `yaml:"tag_cache_min_ttl,omitempty"`
+	TagCacheMaxTTL        time.Duration `yaml:"tag_cache_max_ttl,omitempty"`
 }
 
 type asyncSearchSharder struct {
@@ -87,15 +93,22 @@ func (s asyncSearchSharder) RoundTrip(r *http.Request) (pipeline.Responses[combi
 	// calculate and enforce max search duration
 	maxDuration := s.maxDuration(tenantID)
 	if maxDuration != 0 && time.Duration(searchReq.End-searchReq.Start)*time.Second > maxDuration {
-		return pipeline.NewBadRequest(fmt.Errorf("range specified by start and end exceeds %s. received start=%d end=%d", maxDuration, searchReq.Start, searchReq.End)), nil
+		err := fmt.Errorf("range specified by start and end exceeds the max search duration of %s. received start=%d end=%d", maxDuration, searchReq.Start, searchReq.End)
+		return pipeline.NewQueryError(r, api.NewQueryError(api.ErrorCodeLimitExceeded, err).WithLimit("max_search_duration")), nil
 	}
 
 	// buffer of shards+1 allows us to insert ingestReq and metrics
 	reqCh := make(chan *http.Request, s.cfg.IngesterShards+1)
 
+	// ingester jobs search the most recent data and make up the first shard
+	ingesterCtx := ctx
+	if s.cfg.MostRecentFirst {
+		ingesterCtx = pipeline.ContextWithAdditionalData(ctx, combiner.SearchJobShard(0))
+	}
+
 	// build request to search ingesters based on query_ingesters_until config and time range
 	// pass subCtx in requests so we can cancel and exit early
-	err = s.ingesterRequests(ctx, tenantID, r, *searchReq, reqCh)
+	err = s.ingesterRequests(ingesterCtx, tenantID, r, *searchReq, reqCh)
 	if err != nil {
 		return nil, err
 	}
@@ -105,7 +118,7 @@ func (s asyncSearchSharder) RoundTrip(r *http.Request) (pipeline.Responses[combi
 	ingesterJobs := len(reqCh)
 
 	// pass subCtx in requests so we can cancel and exit early
-	totalJobs, totalBlocks, totalBlockBytes := s.backendRequests(ctx, tenantID, r, searchReq, reqCh, func(err error) {
+	totalJobs, totalBlocks, totalBlockBytes, shards := s.backendRequests(ctx, tenantID, r, searchReq, reqCh, func(err error) {
 		// todo: actually find a way to return this error to the user
 		s.logger.Log("msg", "search: failed to build backend requests", "err", err)
 	})
@@ -129,6 +142,15 @@ func (s asyncSearchSharder) RoundTrip(r *http.Request) (pipeline.Responses[combi
 		}
 
 		jobMetricsResponse = pipeline.NewSuccessfulResponse(body)
+		if shards != nil {
+			// the combiner uses the shards to exit early once the most recent results are found
+			shards[0].TotalJobs = uint32(ingesterJobs)
+			jobMetricsResponse = pipeline.NewHTTPToAsyncResponseWithAdditionalData(&http.Response{
+				StatusCode: http.StatusOK,
+				Status:     http.StatusText(http.StatusOK),
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, shards)
+		}
 	}
 
 	// execute requests
@@ -139,6 +161,8 @@ func (s asyncSearchSharder) RoundTrip(r *http.Request) (pipeline.Responses[combi
 func (s *asyncSearchSharder) blockMetas(start, end int64, tenantID string) []*backend.BlockMeta {
 	// reduce metas to those in the requested range
 	allMetas := s.reader.BlockMetas(tenantID)
+	// archived blocks of the cold backend, if the range precedes the retention of the primary backend
+	allMetas = append(allMetas, s.reader.ColdBlockMetas(tenantID, time.Unix(start, 0))...)
 	metas := make([]*backend.BlockMeta, 0, len(allMetas)/50) // divide by 50 for luck
 	for _, m := range allMetas {
 		if m.StartTime.Unix() <= end &&
@@ -152,8 +176,13 @@ func (s *asyncSearchSharder) blockMetas(start, end int64, tenantID string) []*ba
 }
 
 // backendRequest builds backend requests to search backend blocks. backendRequest takes ownership of reqCh and closes it.
-// it returns 3 int values: totalBlocks, totalBlockBytes, and estimated jobs
-func (s *asyncSearchSharder) backendRequests(ctx context.Context, tenantID string, parent *http.Request, searchReq *tempopb.SearchRequest, reqCh chan<- *http.Request, errFn func(error)) (totalJobs, totalBlocks int, totalBlockBytes uint64) {
+// it returns 3 int values: totalBlocks, totalBlockBytes, and estimated jobs. if most_recent_first is set, blocks are searched
+// newest first and the search shards are returned as well. the first shard is reserved for the ingester jobs.
+func (s *asyncSearchSharder) backendRequests(ctx context.Context, tenantID string, parent *http.Request, searchReq *tempopb.SearchRequest, reqCh chan<- *http.Request, errFn func(error)) (totalJobs, totalBlocks int, totalBlockBytes uint64, shards []combiner.SearchShard) {
+	if s.cfg.MostRecentFirst {
+		shards = []combiner.SearchShard{{}}
+	}
+
 	var blocks []*backend.BlockMeta
 
 	// request without start or end, search only in ingester
@@ -174,22 +203,36 @@ func (s *asyncSearchSharder) backendRequests(ctx context.Context, tenantID strin
 	// get block metadata of blocks in start, end duration
 	blocks = s.blockMetas(int64(start), int64(end), tenantID)
 
+	if s.cfg.MostRecentFirst {
+		sort.Slice(blocks, func(i, j int) bool {
+			return blocks[i].EndTime.After(blocks[j].EndTime)
+		})
+	}
+
 	targetBytesPerRequest := s.cfg.TargetBytesPerRequest
 
 	// calculate metrics to return to the caller
 	totalBlocks = len(blocks)
-	for _, b := range blocks {
+	for i, b := range blocks {
 		p := pagesPerRequest(b, targetBytesPerRequest)
 
-		totalJobs += int(b.TotalRecords) / p
+		blockJobs := int(b.TotalRecords) / p
 		if int(b.TotalRecords)%p != 0 {
-			totalJobs++
+			blockJobs++
 		}
+		totalJobs += blockJobs
 		totalBlockBytes += b.Size
+
+		if shards != nil {
+			// once this shard and all before it are complete, only blocks that end at or before this
+			// block are left to search. round up so traces in the last partial second are included
+			shards[i].CompletedThroughSeconds = uint32(b.EndTime.Add(time.Second - 1).Unix())
+			shards = append(shards, combiner.SearchShard{TotalJobs: uint32(blockJobs)})
+		}
 	}
 
 	go func() {
-		buildBackendRequests(ctx, tenantID, parent, searchReq, blocks, targetBytesPerRequest, reqCh, errFn)
+		buildBackendRequests(ctx, tenantID, parent, searchReq, blocks, targetBytesPerRequest, reqCh, errFn, s.cfg.MostRecentFirst)
 	}()
 
 	return
@@ -296,13 +339,14 @@ func backendRange(start, end uint32, queryBackendAfter time.Duration) (uint32, u
 }
 
 // buildBackendRequests returns a slice of requests that cover all blocks in the store
-// that are covered by start/end.
-func buildBackendRequests(ctx context.Context, tenantID string, parent *http.Request, searchReq *tempopb.SearchRequest, metas []*backend.BlockMeta, bytesPerRequest int, reqCh chan<- *http.Request, errFn func(error)) {
+// that are covered by start/end. if withShards is set every request is tagged with the search shard
+// of its block, which is the index of the block + 1.
+func buildBackendRequests(ctx context.Context, tenantID string, parent *http.Request, searchReq *tempopb.SearchRequest, metas []*backend.BlockMeta, bytesPerRequest int, reqCh chan<- *http.Request, errFn func(error), withShards bool) {
 	defer close(reqCh)
 
 	queryHash := hashForSearchRequest(searchReq)
 
-	for _, m := range metas {
+	for i, m := range metas {
 		pages := pagesPerRequest(m, bytesPerRequest)
 		if pages == 0 {
 			continue
@@ -341,6 +385,9 @@ func buildBackendRequests(ctx context.Context, tenantID string, parent *http.Req
 			if len(key) > 0 {
 				subR = pipeline.ContextAddCacheKey(key, subR)
 			}
+			if withShards {
+				subR = pipeline.ContextAddAdditionalData(combiner.SearchJobShard(i+1), subR)
+			}
 
 			select {
 			case reqCh <- subR:
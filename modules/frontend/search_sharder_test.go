@@ -27,6 +27,7 @@ import (
 	"github.com/grafana/tempo/modules/frontend/pipeline"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/collector"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/tempodb"
@@ -39,7 +40,9 @@ var _ tempodb.Reader = (*mockReader)(nil)
 
 // implements tempodb.Reader interface
 type mockReader struct {
-	metas []*backend.BlockMeta
+	metas          []*backend.BlockMeta
+	coldMetas      []*backend.BlockMeta
+	compactedMetas []*backend.CompactedBlockMeta
 }
 
 func (m *mockReader) SearchTags(context.Context, *backend.BlockMeta, string, common.SearchOptions) (*tempopb.SearchTagsV2Response, error) {
@@ -54,6 +57,10 @@ func (m *mockReader) SearchTagValuesV2(context.Context, *backend.BlockMeta, *tem
 	return nil, nil
 }
 
+func (m *mockReader) TagStats(context.Context, *backend.BlockMeta, string, *collector.TagStats, common.SearchOptions) error {
+	return nil
+}
+
 func (m *mockReader) FetchTagValues(context.Context, *backend.BlockMeta, traceql.FetchTagValuesRequest, traceql.FetchTagValuesCallback, common.SearchOptions) error {
 	return nil
 }
@@ -62,10 +69,30 @@ func (m *mockReader) Find(context.Context, string, common.ID, string, string, in
 	return nil, nil, nil
 }
 
+func (m *mockReader) Tenants() []string {
+	return nil
+}
+
 func (m *mockReader) BlockMetas(string) []*backend.BlockMeta {
 	return m.metas
 }
 
+func (m *mockReader) ColdBlockMetas(string, time.Time) []*backend.BlockMeta {
+	return m.coldMetas
+}
+
+func (m *mockReader) CompactedBlockMetas(string) []*backend.CompactedBlockMeta {
+	return nil
+}
+
+func (m *mockReader) RecentlyCompactedBlockMetas(string) []*backend.CompactedBlockMeta {
+	return m.compactedMetas
+}
+
+func (m *mockReader) TenantDeletionMark(context.Context, string) (*backend.TenantDeletionMark, error) {
+	return nil, nil
+}
+
 func (m *mockReader) Search(context.Context, *backend.BlockMeta, *tempopb.SearchRequest, common.SearchOptions) (*tempopb.SearchResponse, error) {
 	return nil, nil
 }
@@ -75,6 +102,7 @@ func (m *mockReader) Fetch(context.Context, *backend.BlockMeta, traceql.FetchSpa
 }
 
 func (m *mockReader) EnablePolling(context.Context, blocklist.JobSharder) {}
+func (m *mockReader) LastBlocklistPoll() time.Time                        { return time.Time{} }
 func (m *mockReader) Shutdown()                                           {}
 
 //nolint:all deprecated
@@ -223,7 +251,7 @@ func TestBuildBackendRequests(t *testing.T) {
 		reqCh := make(chan *http.Request)
 
 		go func() {
-			buildBackendRequests(ctx, "test", req, searchReq, tc.metas, tc.targetBytesPerRequest, reqCh, cancelCause)
+			buildBackendRequests(ctx, "test", req, searchReq, tc.metas, tc.targetBytesPerRequest, reqCh, cancelCause, false)
 		}()
 
 		actualURIs := []string{}
@@ -313,7 +341,7 @@ func TestBackendRequests(t *testing.T) {
 
 			ctx, cancelCause := context.WithCancelCause(context.Background())
 
-			jobs, blocks, blockBytes := s.backendRequests(ctx, "test", r, searchReq, reqCh, cancelCause)
+			jobs, blocks, blockBytes, _ := s.backendRequests(ctx, "test", r, searchReq, reqCh, cancelCause)
 			require.Equal(t, tc.expectedJobs, jobs)
 			require.Equal(t, tc.expectedBlocks, blocks)
 			require.Equal(t, tc.expectedBlockBytes, blockBytes)
@@ -330,6 +358,25 @@ func TestBackendRequests(t *testing.T) {
 	}
 }
 
+func TestBlockMetasIncludesColdBlocks(t *testing.T) {
+	bm := backend.NewBlockMeta("test", uuid.New(), "wdwad", backend.EncGZIP, "asdf")
+	bm.StartTime = time.Unix(100, 0)
+	bm.EndTime = time.Unix(200, 0)
+	bm.ReplicationFactor = backend.DefaultReplicationFactor
+
+	cold := backend.NewBlockMeta("test", uuid.New(), "wdwad", backend.EncGZIP, "asdf")
+	cold.StartTime = time.Unix(10, 0)
+	cold.EndTime = time.Unix(50, 0)
+	cold.ReplicationFactor = backend.DefaultReplicationFactor
+
+	s := &asyncSearchSharder{
+		reader: &mockReader{metas: []*backend.BlockMeta{bm}, coldMetas: []*backend.BlockMeta{cold}},
+	}
+
+	require.Equal(t, []*backend.BlockMeta{bm, cold}, s.blockMetas(0, 300, "test"))
+	require.Equal(t, []*backend.BlockMeta{cold}, s.blockMetas(0, 60, "test"))
+}
+
 func TestIngesterRequests(t *testing.T) {
 	nownow := time.Now()
 
@@ -729,7 +776,7 @@ func TestSearchSharderRoundTripBadRequest(t *testing.T) {
 	req = httptest.NewRequest("GET", "/?start=1000&end=1500", nil)
 	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
 	resp, err = testRT.RoundTrip(req)
-	testBadRequestFromResponses(t, resp, err, "range specified by start and end exceeds 5m0s. received start=1000 end=1500")
+	testBadRequestFromResponses(t, resp, err, "range specified by start and end exceeds the max search duration of 5m0s. received start=1000 end=1500")
 
 	// bad request
 	req = httptest.NewRequest("GET", "/?start=asdf&end=1500", nil)
@@ -756,7 +803,14 @@ func TestSearchSharderRoundTripBadRequest(t *testing.T) {
 	req = httptest.NewRequest("GET", "/?start=1000&end=1500", nil)
 	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
 	resp, err = testRT.RoundTrip(req)
-	testBadRequestFromResponses(t, resp, err, "range specified by start and end exceeds 1m0s. received start=1000 end=1500")
+	testBadRequestFromResponses(t, resp, err, "range specified by start and end exceeds the max search duration of 1m0s. received start=1000 end=1500")
+
+	// json errors name the exceeded limit
+	req = httptest.NewRequest("GET", "/?start=1000&end=1500", nil)
+	req.Header.Set(api.HeaderJSONErrors, "true")
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err = testRT.RoundTrip(req)
+	testBadRequestFromResponses(t, resp, err, `{"code":"limit_exceeded","message":"range specified by start and end exceeds the max search duration of 1m0s. received start=1000 end=1500","retryable":false,"limit":"max_search_duration"}`)
 }
 
 func testBadRequestFromResponses(t *testing.T, resp pipeline.Responses[combiner.PipelineResponse], err error, expectedBody string) {
@@ -862,3 +916,52 @@ func TestHashTraceQLQuery(t *testing.T) {
 	h2 = hashForSearchRequest(&tempopb.SearchRequest{Query: "{ span.foo = `bar` }", SpansPerSpanSet: 2})
 	require.NotEqual(t, h1, h2)
 }
+
+func TestBackendRequestsMostRecentFirst(t *testing.T) {
+	newBlock := func(start, end int64, records uint32) *backend.BlockMeta {
+		bm := backend.NewBlockMeta("test", uuid.New(), "wdwad", backend.EncGZIP, "asdf")
+		bm.StartTime = time.Unix(start, 0)
+		bm.EndTime = time.Unix(end, 0)
+		bm.Size = defaultTargetBytesPerRequest * uint64(records)
+		bm.TotalRecords = records
+		return bm
+	}
+
+	oldest := newBlock(100, 200, 1)
+	newest := newBlock(250, 400, 2)
+	middle := newBlock(150, 300, 1)
+
+	s := &asyncSearchSharder{
+		cfg:    SearchSharderConfig{MostRecentFirst: true},
+		reader: &mockReader{metas: []*backend.BlockMeta{oldest, newest, middle}},
+	}
+
+	r := httptest.NewRequest("GET", "/?tags=foo%3Dbar&start=100&end=400", nil)
+	searchReq, err := api.ParseSearchRequest(r)
+	require.NoError(t, err)
+
+	reqCh := make(chan *http.Request)
+	ctx, cancelCause := context.WithCancelCause(context.Background())
+
+	jobs, blocks, _, shards := s.backendRequests(ctx, "test", r, searchReq, reqCh, cancelCause)
+	require.Equal(t, 4, jobs)
+	require.Equal(t, 3, blocks)
+	require.Equal(t, []combiner.SearchShard{
+		{TotalJobs: 0, CompletedThroughSeconds: 400}, // reserved for the ingesters
+		{TotalJobs: 2, CompletedThroughSeconds: 300},
+		{TotalJobs: 1, CompletedThroughSeconds: 200},
+		{TotalJobs: 1, CompletedThroughSeconds: 0},
+	}, shards)
+
+	actualBlockIDs := []string{}
+	for r := range reqCh {
+		actualBlockIDs = append(actualBlockIDs, r.URL.Query().Get("blockID"))
+	}
+	require.NoError(t, ctx.Err())
+	require.Equal(t, []string{
+		newest.BlockID.String(),
+		newest.BlockID.String(),
+		middle.BlockID.String(),
+		oldest.BlockID.String(),
+	}, actualBlockIDs)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/go-kit/log"
@@ -41,6 +42,10 @@ func (r *tagsSearchRequest) keyPrefix() string {
 	return cacheKeyPrefixSearchTag
 }
 
+func (r *tagsSearchRequest) maxBlocks(overrides.Interface, string) int {
+	return 0
+}
+
 func (r *tagsSearchRequest) newWithRange(start, end uint32) tagSearchReq {
 	newReq := r.request
 	newReq.Start = start
@@ -96,6 +101,10 @@ func (r *tagValueSearchRequest) keyPrefix() string {
 	return cacheKeyPrefixSearchTagValues
 }
 
+func (r *tagValueSearchRequest) maxBlocks(o overrides.Interface, tenantID string) int {
+	return o.MaxBackendBlocksPerTagValuesQuery(tenantID)
+}
+
 func (r *tagValueSearchRequest) newWithRange(start, end uint32) tagSearchReq {
 	newReq := r.request
 	newReq.Start = start
@@ -160,6 +169,9 @@ type tagSearchReq interface {
 	// should only be based on the content the request is searching for
 	hash() uint64
 	keyPrefix() string
+
+	// maxBlocks returns the maximum number of backend blocks searched for the tenant. 0 disables the limit
+	maxBlocks(o overrides.Interface, tenantID string) int
 }
 
 type searchTagSharder struct {
@@ -204,11 +216,12 @@ func (s searchTagSharder) RoundTrip(r *http.Request) (pipeline.Responses[combine
 	span, ctx := opentracing.StartSpanFromContext(requestCtx, "frontend.ShardSearchTags")
 	defer span.Finish()
 
-	// calculate and enforce max search duration
-	maxDuration := s.maxDuration(tenantID)
+	// calculate and enforce max tags duration
+	maxDuration, limit := s.maxDuration(tenantID)
 	if maxDuration != 0 && time.Duration(searchReq.end()-searchReq.start())*time.Second > maxDuration {
-		return pipeline.NewBadRequest(fmt.Errorf("range specified by start and end exceeds %s."+
-			" received start=%d end=%d", maxDuration, searchReq.start(), searchReq.end())), nil
+		err := fmt.Errorf("range specified by start and end exceeds the max tags duration of %s."+
+			" received start=%d end=%d", maxDuration, searchReq.start(), searchReq.end())
+		return pipeline.NewQueryError(r, api.NewQueryError(api.ErrorCodeLimitExceeded, err).WithLimit(limit)), nil
 	}
 
 	// build request to search ingester based on query_ingesters_until config and time range
@@ -270,6 +283,16 @@ func (s searchTagSharder) backendRequests(ctx context.Context, tenantID string,
 	// get block metadata of blocks in start, end duration
 	blocks = s.blockMetas(int64(start), int64(end), tenantID)
 
+	// search the most recent blocks first. the combiners stop once the response size limit is reached, so
+	// long ranges return the most recent values instead of whichever blocks happen to be searched first
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].EndTime.After(blocks[j].EndTime)
+	})
+
+	if maxBlocks := searchReq.maxBlocks(s.overrides, tenantID); maxBlocks > 0 && len(blocks) > maxBlocks {
+		blocks = blocks[:maxBlocks]
+	}
+
 	targetBytesPerRequest := s.cfg.TargetBytesPerRequest
 
 	go func() {
@@ -284,8 +307,11 @@ func (s searchTagSharder) buildBackendRequests(ctx context.Context, tenantID str
 
 	hash := searchReq.hash()
 	keyPrefix := searchReq.keyPrefix()
+	now := time.Now()
 
 	for _, m := range metas {
+		ttl := tagCacheTTL(m, now, s.cfg.TagCacheMinTTL, s.cfg.TagCacheMaxTTL)
+
 		pages := pagesPerRequest(m, bytesPerRequest)
 		if pages == 0 {
 			continue
@@ -302,9 +328,10 @@ func (s searchTagSharder) buildBackendRequests(ctx context.Context, tenantID str
 			subR.Header.Set(api.HeaderAccept, api.HeaderAcceptProtobuf)
 			prepareRequestForQueriers(subR, tenantID, parent.URL.Path, subR.URL.Query())
 
-			key := cacheKey(keyPrefix, tenantID, hash, int64(searchReq.start()), int64(searchReq.end()), m, startPage, pages)
+			key := tagCacheKey(keyPrefix, tenantID, hash, m, startPage, pages)
 			if len(key) > 0 {
 				subR = pipeline.ContextAddCacheKey(key, subR)
+				subR = pipeline.ContextAddCacheTTL(ttl, subR)
 			}
 
 			select {
@@ -362,13 +389,16 @@ func (s searchTagSharder) buildIngesterRequest(ctx context.Context, tenantID str
 	return subR, nil
 }
 
-// maxDuration returns the max search duration allowed for this tenant.
-func (s searchTagSharder) maxDuration(tenantID string) time.Duration {
+// maxDuration returns the max tags duration allowed for this tenant and the name of the limit it comes from.
+// Tenants without a max tags duration are limited to their max search duration.
+func (s searchTagSharder) maxDuration(tenantID string) (time.Duration, string) {
 	// check overrides first, if no overrides then grab from our config
-	maxDuration := s.overrides.MaxSearchDuration(tenantID)
-	if maxDuration != 0 {
-		return maxDuration
+	if maxDuration := s.overrides.MaxTagsDuration(tenantID); maxDuration != 0 {
+		return maxDuration, "max_tags_duration"
+	}
+	if maxDuration := s.overrides.MaxSearchDuration(tenantID); maxDuration != 0 {
+		return maxDuration, "max_search_duration"
 	}
 
-	return s.cfg.MaxDuration
+	return s.cfg.MaxDuration, "max_search_duration"
 }
@@ -27,8 +27,9 @@ import (
 )
 
 type fakeReq struct {
-	startValue uint32
-	endValue   uint32
+	startValue     uint32
+	endValue       uint32
+	maxBlocksValue int
 }
 
 func (r *fakeReq) start() uint32 {
@@ -46,6 +47,10 @@ func (r *fakeReq) newWithRange(start, end uint32) tagSearchReq {
 	}
 }
 
+func (r *fakeReq) maxBlocks(overrides.Interface, string) int {
+	return r.maxBlocksValue
+}
+
 func (r *fakeReq) hash() uint64 {
 	return 0
 }
@@ -174,6 +179,77 @@ func TestTagsBackendRequests(t *testing.T) {
 	}
 }
 
+func TestTagValuesMaxBlocks(t *testing.T) {
+	o, err := overrides.NewOverrides(overrides.Config{
+		Defaults: overrides.Overrides{
+			Read: overrides.ReadOverrides{
+				MaxBlocksPerTagValuesQuery:        1,
+				MaxBackendBlocksPerTagValuesQuery: 2,
+			},
+		},
+	}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	// the limit of the ingester blocks doesn't apply to the backend blocks
+	require.Equal(t, 2, (&tagValueSearchRequest{}).maxBlocks(o, "test"))
+	require.Equal(t, 0, (&tagsSearchRequest{}).maxBlocks(o, "test"))
+}
+
+func TestTagsBackendRequestsMostRecentBlocks(t *testing.T) {
+	metas := make([]*backend.BlockMeta, 0, 3)
+	for i := 0; i < 3; i++ {
+		bm := backend.NewBlockMeta("test", uuid.New(), "wdwad", backend.EncGZIP, "asdf")
+		bm.StartTime = time.Unix(int64(100+i*100), 0)
+		bm.EndTime = time.Unix(int64(200+i*100), 0)
+		bm.Size = defaultTargetBytesPerRequest
+		bm.TotalRecords = 1
+		metas = append(metas, bm)
+	}
+
+	s := &searchTagSharder{
+		cfg:    SearchSharderConfig{},
+		reader: &mockReader{metas: metas},
+	}
+
+	tests := []struct {
+		name             string
+		maxBlocks        int
+		expectedBlockIDs []string
+	}{
+		{
+			name:             "no limit",
+			expectedBlockIDs: []string{metas[2].BlockID.String(), metas[1].BlockID.String(), metas[0].BlockID.String()},
+		},
+		{
+			name:             "limit",
+			maxBlocks:        2,
+			expectedBlockIDs: []string{metas[2].BlockID.String(), metas[1].BlockID.String()},
+		},
+		{
+			name:             "limit exceeds blocks",
+			maxBlocks:        5,
+			expectedBlockIDs: []string{metas[2].BlockID.String(), metas[1].BlockID.String(), metas[0].BlockID.String()},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/?start=100&end=400", nil)
+			reqCh := make(chan *http.Request)
+			req := &fakeReq{startValue: 100, endValue: 400, maxBlocksValue: tc.maxBlocks}
+
+			s.backendRequests(context.TODO(), "test", r, req, reqCh, func(err error) {
+				require.NoError(t, err)
+			})
+
+			actualBlockIDs := []string{}
+			for r := range reqCh {
+				actualBlockIDs = append(actualBlockIDs, r.URL.Query().Get("blockID"))
+			}
+			require.Equal(t, tc.expectedBlockIDs, actualBlockIDs)
+		})
+	}
+}
+
 func TestTagsIngesterRequest(t *testing.T) {
 	now := int(time.Now().Unix())
 	tenMinutesAgo := int(time.Now().Add(-10 * time.Minute).Unix())
@@ -306,7 +382,7 @@ func TestTagsSearchSharderRoundTripBadRequest(t *testing.T) {
 	req = httptest.NewRequest("GET", "/?start=1000&end=1500", nil)
 	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
 	resp, err = testRT.RoundTrip(req)
-	testBadRequestFromResponses(t, resp, err, "range specified by start and end exceeds 5m0s. received start=1000 end=1500")
+	testBadRequestFromResponses(t, resp, err, "range specified by start and end exceeds the max tags duration of 5m0s. received start=1000 end=1500")
 
 	// bad request
 	req = httptest.NewRequest("GET", "/?start=asdf&end=1500", nil)
@@ -334,5 +410,28 @@ func TestTagsSearchSharderRoundTripBadRequest(t *testing.T) {
 	req = httptest.NewRequest("GET", "/?start=1000&end=1500", nil)
 	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
 	resp, err = testRT.RoundTrip(req)
-	testBadRequestFromResponses(t, resp, err, "range specified by start and end exceeds 1m0s. received start=1000 end=1500")
+	testBadRequestFromResponses(t, resp, err, "range specified by start and end exceeds the max tags duration of 1m0s. received start=1000 end=1500")
+
+	// the max tags duration takes precedence over the max search duration
+	o, err = overrides.NewOverrides(overrides.Config{
+		Defaults: overrides.Overrides{
+			Read: overrides.ReadOverrides{
+				MaxSearchDuration: model.Duration(time.Minute),
+				MaxTagsDuration:   model.Duration(2 * time.Minute),
+			},
+		},
+	}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	sharder = newAsyncTagSharder(&mockReader{}, o, SearchSharderConfig{
+		ConcurrentRequests:    defaultConcurrentRequests,
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+		MaxDuration:           5 * time.Minute,
+	}, parseTagsRequest, log.NewNopLogger())
+	testRT = sharder.Wrap(next)
+
+	req = httptest.NewRequest("GET", "/?start=1000&end=1500", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err = testRT.RoundTrip(req)
+	testBadRequestFromResponses(t, resp, err, "range specified by start and end exceeds the max tags duration of 2m0s. received start=1000 end=1500")
 }
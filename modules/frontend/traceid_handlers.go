@@ -9,14 +9,17 @@ import (
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level" //nolint:all //deprecated
 	"github.com/grafana/dskit/user"
+	"github.com/grafana/tempo/modules/frontend/audit"
+	"github.com/grafana/tempo/modules/frontend/canary"
 	"github.com/grafana/tempo/modules/frontend/combiner"
 	"github.com/grafana/tempo/modules/frontend/pipeline"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/util"
 )
 
 // newTraceIDHandler creates a http.handler for trace by id requests
-func newTraceIDHandler(cfg Config, o overrides.Interface, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], logger log.Logger) http.RoundTripper {
+func newTraceIDHandler(cfg Config, o overrides.Interface, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], auditor *audit.Auditor, mirror *canary.Mirror, logger log.Logger) http.RoundTripper {
 	postSLOHook := traceByIDSLOPostHook(cfg.TraceByID.SLO)
 
 	return pipeline.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
@@ -31,7 +34,7 @@ func newTraceIDHandler(cfg Config, o overrides.Interface, next pipeline.AsyncRou
 		}
 
 		// validate traceID
-		_, err = api.ParseTraceID(req)
+		traceID, err := api.ParseTraceID(req)
 		if err != nil {
 			return &http.Response{
 				StatusCode: http.StatusBadRequest,
@@ -50,12 +53,24 @@ func newTraceIDHandler(cfg Config, o overrides.Interface, next pipeline.AsyncRou
 			}, nil
 		}
 
+		// validate the optional span filter. it is passed on to the queriers as is
+		if _, err := api.ParseSpanFilter(req); err != nil {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       io.NopCloser(strings.NewReader(err.Error())),
+				Header:     http.Header{},
+			}, nil
+		}
+
 		// check marshalling format
 		marshallingFormat := api.HeaderAcceptJSON
 		if req.Header.Get(api.HeaderAccept) == api.HeaderAcceptProtobuf {
 			marshallingFormat = api.HeaderAcceptProtobuf
 		}
 
+		// sample before the request is prepared for the queriers
+		canaryReq := mirror.Sample(traceByIDOp, req, traceByIDFingerprint(marshallingFormat))
+
 		// enforce all communication internal to Tempo to be in protobuf bytes
 		req.Header.Set(api.HeaderAccept, api.HeaderAcceptProtobuf)
 		prepareRequestForQueriers(req, tenant, req.RequestURI, nil)
@@ -74,6 +89,11 @@ func newTraceIDHandler(cfg Config, o overrides.Interface, next pipeline.AsyncRou
 		elapsed := time.Since(start)
 		postSLOHook(resp, tenant, 0, elapsed, err)
 
+		auditRecord := audit.NewRecord(tenant, auditor.UserFromHeader(req.Header), traceByIDOp, start, resp, 0, err)
+		auditRecord.TraceID = util.TraceIDToHexString(traceID)
+		auditor.Record(auditRecord)
+		mirror.Replay(canaryReq, resp, elapsed, err)
+
 		level.Info(logger).Log(
 			"msg", "trace id response",
 			"tenant", tenant,
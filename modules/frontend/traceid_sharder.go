@@ -1,9 +1,11 @@
 package frontend
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"net/http"
+	"time"
 
 	"github.com/go-kit/log" //nolint:all //deprecated
 	"github.com/grafana/dskit/user"
@@ -12,7 +14,10 @@ import (
 	"github.com/grafana/tempo/modules/frontend/combiner"
 	"github.com/grafana/tempo/modules/frontend/pipeline"
 	"github.com/grafana/tempo/modules/querier"
+	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/blockboundary"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
 )
 
 const (
@@ -22,15 +27,17 @@ const (
 
 type asyncTraceSharder struct {
 	next            pipeline.AsyncRoundTripper[combiner.PipelineResponse]
+	reader          tempodb.Reader
 	cfg             *TraceByIDConfig
 	logger          log.Logger
 	blockBoundaries [][]byte
 }
 
-func newAsyncTraceIDSharder(cfg *TraceByIDConfig, logger log.Logger) pipeline.AsyncMiddleware[combiner.PipelineResponse] {
+func newAsyncTraceIDSharder(reader tempodb.Reader, cfg *TraceByIDConfig, logger log.Logger) pipeline.AsyncMiddleware[combiner.PipelineResponse] {
 	return pipeline.AsyncMiddlewareFunc[combiner.PipelineResponse](func(next pipeline.AsyncRoundTripper[combiner.PipelineResponse]) pipeline.AsyncRoundTripper[combiner.PipelineResponse] {
 		return asyncTraceSharder{
 			next:            next,
+			reader:          reader,
 			cfg:             cfg,
 			logger:          logger,
 			blockBoundaries: blockboundary.CreateBlockBoundaries(cfg.QueryShards - 1), // one shard will be used to query ingesters
@@ -44,10 +51,18 @@ func (s asyncTraceSharder) RoundTrip(r *http.Request) (pipeline.Responses[combin
 	defer span.Finish()
 	r = r.WithContext(ctx)
 
-	reqs, err := s.buildShardedRequests(ctx, r)
+	// start and end are optional hints of when the trace was ingested. they are validated here so
+	// that an invalid range is rejected before any shards are built
+	_, _, _, start, end, err := api.ValidateAndSanitizeRequest(r)
+	if err != nil {
+		return pipeline.NewBadRequest(err), nil
+	}
+
+	reqs, err := s.buildShardedRequests(ctx, r, start, end)
 	if err != nil {
 		return nil, err
 	}
+	span.SetTag("shards", len(reqs))
 
 	// execute requests
 	concurrentShards := uint(s.cfg.QueryShards)
@@ -61,19 +76,30 @@ func (s asyncTraceSharder) RoundTrip(r *http.Request) (pipeline.Responses[combin
 }
 
 // buildShardedRequests returns a slice of requests sharded on the precalculated
-// block boundaries
-func (s *asyncTraceSharder) buildShardedRequests(ctx context.Context, parent *http.Request) ([]*http.Request, error) {
+// block boundaries. if both start and end are set, block shards that do not contain
+// any block overlapping the time range are skipped.
+func (s *asyncTraceSharder) buildShardedRequests(ctx context.Context, parent *http.Request, start, end int64) ([]*http.Request, error) {
 	userID, err := user.ExtractOrgID(parent.Context())
 	if err != nil {
 		return nil, err
 	}
 
-	reqs := make([]*http.Request, s.cfg.QueryShards)
+	var blockIDs [][]byte
+	hasTimeRange := start != 0 && end != 0 && s.reader != nil
+	if hasTimeRange {
+		blockIDs = s.blockIDsInRange(userID, start, end)
+	}
+
+	reqs := make([]*http.Request, 0, s.cfg.QueryShards)
 	// build sharded block queries
 	for i := 0; i < len(s.blockBoundaries); i++ {
-		reqs[i] = parent.Clone(ctx)
+		if i > 0 && hasTimeRange && !anyBlockInShard(blockIDs, s.blockBoundaries[i-1], s.blockBoundaries[i]) {
+			continue
+		}
+
+		req := parent.Clone(ctx)
 
-		q := reqs[i].URL.Query()
+		q := req.URL.Query()
 		if i == 0 {
 			// ingester query
 			q.Add(querier.QueryModeKey, querier.QueryModeIngesters)
@@ -84,8 +110,47 @@ func (s *asyncTraceSharder) buildShardedRequests(ctx context.Context, parent *ht
 			q.Add(querier.QueryModeKey, querier.QueryModeBlocks)
 		}
 
-		prepareRequestForQueriers(reqs[i], userID, reqs[i].URL.Path, q)
+		prepareRequestForQueriers(req, userID, req.URL.Path, q)
+		reqs = append(reqs, req)
 	}
 
 	return reqs, nil
 }
+
+// blockIDsInRange returns the ids of all blocks of the tenant that overlap start and end. this
+// uses the same criteria as the queriers to include a block in a trace by id lookup.
+func (s *asyncTraceSharder) blockIDsInRange(tenantID string, start, end int64) [][]byte {
+	allMetas := s.reader.BlockMetas(tenantID)
+	allMetas = append(allMetas, s.reader.ColdBlockMetas(tenantID, time.Unix(start, 0))...)
+	// queriers keep searching compacted blocks until the blocks they were compacted into are polled
+	for _, c := range s.reader.RecentlyCompactedBlockMetas(tenantID) {
+		allMetas = append(allMetas, &c.BlockMeta)
+	}
+	ids := make([][]byte, 0, len(allMetas))
+	for _, m := range allMetas {
+		if m.StartTime.Unix() >= end || m.EndTime.Unix() <= start {
+			continue
+		}
+		if m.ReplicationFactor != backend.DefaultReplicationFactor { // This check skips generator blocks (RF=1)
+			continue
+		}
+
+		id, err := m.BlockID.MarshalBinary()
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// anyBlockInShard returns true if any of the block ids is within the shard boundaries: blockStart <= id <= blockEnd
+func anyBlockInShard(blockIDs [][]byte, blockStart, blockEnd []byte) bool {
+	for _, id := range blockIDs {
+		if bytes.Compare(id, blockStart) >= 0 && bytes.Compare(id, blockEnd) <= 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/grafana/dskit/user"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/tempo/pkg/blockboundary"
+	"github.com/grafana/tempo/tempodb/backend"
 )
 
 func TestBuildShardedRequests(t *testing.T) {
@@ -24,10 +27,113 @@ func TestBuildShardedRequests(t *testing.T) {
 	ctx := user.InjectOrgID(context.Background(), "blerg")
 	req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
 
-	shardedReqs, err := sharder.buildShardedRequests(ctx, req)
+	shardedReqs, err := sharder.buildShardedRequests(ctx, req, 0, 0)
 	require.NoError(t, err)
 	require.Len(t, shardedReqs, queryShards)
 
 	require.Equal(t, "/querier?mode=ingesters", shardedReqs[0].RequestURI)
 	require.Equal(t, "/querier?blockEnd=ffffffffffffffffffffffffffffffff&blockStart=00000000000000000000000000000000&mode=blocks", shardedReqs[1].RequestURI)
 }
+
+func TestBuildShardedRequestsWithTimeRange(t *testing.T) {
+	queryShards := 5
+	now := time.Now()
+
+	// block boundaries for 4 block shards are 00.., 40.., 80.., c0.., ff..
+	meta := func(id string, start, end time.Time, rf uint32) *backend.BlockMeta {
+		return &backend.BlockMeta{
+			BlockID:           uuid.MustParse(id),
+			StartTime:         start,
+			EndTime:           end,
+			ReplicationFactor: rf,
+		}
+	}
+	reader := &mockReader{
+		metas: []*backend.BlockMeta{
+			meta("10000000-0000-0000-0000-000000000000", now.Add(-time.Hour), now.Add(-50*time.Minute), backend.DefaultReplicationFactor),
+			meta("50000000-0000-0000-0000-000000000000", now.Add(-10*time.Hour), now.Add(-9*time.Hour), backend.DefaultReplicationFactor),
+			meta("90000000-0000-0000-0000-000000000000", now.Add(-time.Hour), now.Add(-50*time.Minute), 1),
+			meta("d0000000-0000-0000-0000-000000000000", now.Add(-70*time.Minute), now.Add(-55*time.Minute), backend.DefaultReplicationFactor),
+		},
+		compactedMetas: []*backend.CompactedBlockMeta{
+			{BlockMeta: *meta("a0000000-0000-0000-0000-000000000000", now.Add(-3*time.Hour), now.Add(-130*time.Minute), backend.DefaultReplicationFactor), CompactedTime: now},
+		},
+	}
+
+	sharder := &asyncTraceSharder{
+		reader: reader,
+		cfg: &TraceByIDConfig{
+			QueryShards: queryShards,
+		},
+		blockBoundaries: blockboundary.CreateBlockBoundaries(queryShards - 1),
+	}
+
+	ctx := user.InjectOrgID(context.Background(), "blerg")
+	req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+
+	tcs := []struct {
+		name       string
+		start, end int64
+		expected   []string
+	}{
+		{
+			name: "no time range",
+			expected: []string{
+				"/querier?mode=ingesters",
+				"/querier?blockEnd=40000000000000000000000000000000&blockStart=00000000000000000000000000000000&mode=blocks",
+				"/querier?blockEnd=80000000000000000000000000000000&blockStart=40000000000000000000000000000000&mode=blocks",
+				"/querier?blockEnd=c0000000000000000000000000000000&blockStart=80000000000000000000000000000000&mode=blocks",
+				"/querier?blockEnd=ffffffffffffffffffffffffffffffff&blockStart=c0000000000000000000000000000000&mode=blocks",
+			},
+		},
+		{
+			name:  "recent blocks",
+			start: now.Add(-2 * time.Hour).Unix(),
+			end:   now.Unix(),
+			expected: []string{
+				"/querier?mode=ingesters",
+				"/querier?blockEnd=40000000000000000000000000000000&blockStart=00000000000000000000000000000000&mode=blocks",
+				"/querier?blockEnd=ffffffffffffffffffffffffffffffff&blockStart=c0000000000000000000000000000000&mode=blocks",
+			},
+		},
+		{
+			name:  "old blocks",
+			start: now.Add(-12 * time.Hour).Unix(),
+			end:   now.Add(-8 * time.Hour).Unix(),
+			expected: []string{
+				"/querier?mode=ingesters",
+				"/querier?blockEnd=80000000000000000000000000000000&blockStart=40000000000000000000000000000000&mode=blocks",
+			},
+		},
+		{
+			name:  "compacted blocks",
+			start: now.Add(-4 * time.Hour).Unix(),
+			end:   now.Add(-135 * time.Minute).Unix(),
+			expected: []string{
+				"/querier?mode=ingesters",
+				"/querier?blockEnd=c0000000000000000000000000000000&blockStart=80000000000000000000000000000000&mode=blocks",
+			},
+		},
+		{
+			name:  "no blocks",
+			start: now.Add(-30 * time.Minute).Unix(),
+			end:   now.Unix(),
+			expected: []string{
+				"/querier?mode=ingesters",
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			shardedReqs, err := sharder.buildShardedRequests(ctx, req, tc.start, tc.end)
+			require.NoError(t, err)
+
+			actual := make([]string, 0, len(shardedReqs))
+			for _, r := range shardedReqs {
+				actual = append(actual, r.RequestURI)
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// QuerierCircuitBreakerConfig controls ejecting queriers that keep failing jobs from the assignment of jobs.
+type QuerierCircuitBreakerConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// DurationSLO fails the jobs of a batch that took longer to process. 0 disables the duration SLO and only
+	// 5xx responses and broken streams fail jobs.
+	DurationSLO time.Duration `yaml:"duration_slo"`
+	// MaxFailureRatio is the ratio of failed jobs of a querier within Window at which the querier is ejected.
+	MaxFailureRatio float64 `yaml:"max_failure_ratio"`
+	// MinJobs is the number of jobs a querier has to process within Window before it can be ejected.
+	MinJobs int           `yaml:"min_jobs"`
+	Window  time.Duration `yaml:"window"`
+	// EjectFor is how long an ejected querier receives no jobs. After that a single probe job is sent to it, which
+	// restores the querier on success and ejects it again on failure.
+	EjectFor time.Duration `yaml:"eject_for"`
+}
+
+func (cfg *QuerierCircuitBreakerConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.MaxFailureRatio <= 0 || cfg.MaxFailureRatio > 1 {
+		return errors.New("querier_circuit_breaker.max_failure_ratio must be greater than 0 and at most 1")
+	}
+	if cfg.MinJobs <= 0 {
+		return errors.New("querier_circuit_breaker.min_jobs must be positive")
+	}
+	if cfg.Window <= 0 || cfg.EjectFor <= 0 {
+		return errors.New("querier_circuit_breaker.window and querier_circuit_breaker.eject_for must be positive")
+	}
+	if cfg.DurationSLO < 0 {
+		return errors.New("querier_circuit_breaker.duration_slo must not be negative")
+	}
+	return nil
+}
+
+type breakerState int
+
+const (
+	// breakerClosed queriers are assigned jobs
+	breakerClosed breakerState = iota
+	// breakerOpen queriers are ejected
+	breakerOpen
+	// breakerHalfOpen queriers are assigned a single probe job
+	breakerHalfOpen
+)
+
+type querierBreaker struct {
+	state       breakerState
+	connections int
+
+	windowStart time.Time
+	jobs        int
+	failures    int
+
+	openUntil time.Time
+	probing   bool
+}
+
+// querierCircuitBreakers tracks the failed jobs of every connected querier and holds back jobs from the queriers
+// whose failure ratio exceeds the configured SLO.
+type querierCircuitBreakers struct {
+	cfg QuerierCircuitBreakerConfig
+	log log.Logger
+
+	mtx      sync.Mutex
+	queriers map[string]*querierBreaker
+
+	ejections prometheus.Counter
+
+	// for testing
+	now          func() time.Time
+	pollInterval time.Duration
+}
+
+func newQuerierCircuitBreakers(cfg QuerierCircuitBreakerConfig, logger log.Logger, registerer prometheus.Registerer) *querierCircuitBreakers {
+	cb := &querierCircuitBreakers{
+		cfg:      cfg,
+		log:      logger,
+		queriers: map[string]*querierBreaker{},
+		ejections: promauto.With(registerer).NewCounter(prometheus.CounterOpts{
+			Name: "tempo_query_frontend_querier_ejections_total",
+			Help: "Total number of queriers ejected from the assignment of jobs by the circuit breaker.",
+		}),
+		now:          time.Now,
+		pollInterval: time.Second,
+	}
+
+	promauto.With(registerer).NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "tempo_query_frontend_ejected_queriers",
+		Help: "Number of connected queriers currently ejected from the assignment of jobs by the circuit breaker.",
+	}, cb.ejected)
+
+	return cb
+}
+
+func (cb *querierCircuitBreakers) register(querierID string) {
+	cb.mtx.Lock()
+	defer cb.mtx.Unlock()
+
+	b := cb.queriers[querierID]
+	if b == nil {
+		b = &querierBreaker{windowStart: cb.now()}
+		cb.queriers[querierID] = b
+	}
+	b.connections++
+}
+
+func (cb *querierCircuitBreakers) unregister(querierID string) {
+	cb.mtx.Lock()
+	defer cb.mtx.Unlock()
+
+	b := cb.queriers[querierID]
+	if b == nil {
+		return
+	}
+	b.connections--
+	if b.connections <= 0 {
+		delete(cb.queriers, querierID)
+	}
+}
+
+// acquire blocks while the querier is ejected. It returns true if the next batch of the querier is a probe, which
+// must be passed to release.
+func (cb *querierCircuitBreakers) acquire(ctx context.Context, querierID string) (bool, error) {
+	if !cb.cfg.Enabled {
+		return false, nil
+	}
+
+	for {
+		probe, wait := cb.tryAcquire(querierID)
+		if wait == 0 {
+			return probe, nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// tryAcquire returns whether the next batch of the querier is a probe or how long to wait before trying again.
+func (cb *querierCircuitBreakers) tryAcquire(querierID string) (bool, time.Duration) {
+	cb.mtx.Lock()
+	defer cb.mtx.Unlock()
+
+	b := cb.queriers[querierID]
+	if b == nil {
+		return false, 0
+	}
+
+	if b.state == breakerOpen {
+		if remaining := b.openUntil.Sub(cb.now()); remaining > 0 {
+			return false, min(remaining, cb.pollInterval)
+		}
+		b.state = breakerHalfOpen
+	}
+
+	if b.state == breakerHalfOpen {
+		if b.probing {
+			return false, cb.pollInterval
+		}
+		b.probing = true
+		return true, 0
+	}
+
+	return false, 0
+}
+
+// abort releases the probe of a querier without a batch being sent to it.
+func (cb *querierCircuitBreakers) abort(querierID string, probe bool) {
+	if !probe {
+		return
+	}
+
+	cb.mtx.Lock()
+	defer cb.mtx.Unlock()
+
+	if b := cb.queriers[querierID]; b != nil {
+		b.probing = false
+	}
+}
+
+// release records the outcome of a batch sent to the querier. responses is nil if the batch was not processed.
+func (cb *querierCircuitBreakers) release(querierID string, probe bool, batch *requestBatch, responses []*httpgrpc.HTTPResponse, duration time.Duration) {
+	if !cb.cfg.Enabled {
+		return
+	}
+
+	jobs, failures := cb.outcome(batch, responses, duration)
+
+	cb.mtx.Lock()
+	defer cb.mtx.Unlock()
+
+	b := cb.queriers[querierID]
+	if b == nil {
+		return
+	}
+
+	now := cb.now()
+
+	if probe {
+		b.probing = false
+		switch {
+		case jobs == 0:
+			// nothing was learned, the next batch is probing again
+		case failures > 0:
+			b.state = breakerOpen
+			b.openUntil = now.Add(cb.cfg.EjectFor)
+			level.Warn(cb.log).Log("msg", "querier failed probe, keeping it ejected", "querier", querierID, "eject_for", cb.cfg.EjectFor)
+		default:
+			b.state = breakerClosed
+			b.windowStart, b.jobs, b.failures = now, 0, 0
+			level.Info(cb.log).Log("msg", "querier passed probe, restoring it", "querier", querierID)
+		}
+		return
+	}
+
+	// batches that were in flight when the querier got ejected are ignored
+	if b.state != breakerClosed || jobs == 0 {
+		return
+	}
+
+	if now.Sub(b.windowStart) >= cb.cfg.Window {
+		b.windowStart, b.jobs, b.failures = now, 0, 0
+	}
+	b.jobs += jobs
+	b.failures += failures
+
+	if b.jobs < cb.cfg.MinJobs || float64(b.failures)/float64(b.jobs) < cb.cfg.MaxFailureRatio {
+		return
+	}
+
+	// never eject the last healthy querier, jobs failing on it are better than no jobs processed at all
+	if !cb.otherQuerierClosed(querierID) {
+		return
+	}
+
+	level.Warn(cb.log).Log("msg", "ejecting querier that is failing jobs", "querier", querierID, "jobs", b.jobs, "failures", b.failures, "eject_for", cb.cfg.EjectFor)
+	b.state = breakerOpen
+	b.openUntil = now.Add(cb.cfg.EjectFor)
+	cb.ejections.Inc()
+}
+
+// outcome returns the number of jobs of the batch and how many of them failed. Batches cancelled upstream are not
+// counted.
+func (cb *querierCircuitBreakers) outcome(batch *requestBatch, responses []*httpgrpc.HTTPResponse, duration time.Duration) (int, int) {
+	if responses == nil {
+		if batch.contextError() != nil {
+			return 0, 0
+		}
+		// the stream to the querier broke
+		return batch.len(), batch.len()
+	}
+
+	if cb.cfg.DurationSLO > 0 && duration > cb.cfg.DurationSLO {
+		return len(responses), len(responses)
+	}
+
+	failures := 0
+	for _, resp := range responses {
+		if resp.Code/100 == 5 {
+			failures++
+		}
+	}
+	return len(responses), failures
+}
+
+func (cb *querierCircuitBreakers) otherQuerierClosed(querierID string) bool {
+	for id, b := range cb.queriers {
+		if id != querierID && b.state == breakerClosed {
+			return true
+		}
+	}
+	return false
+}
+
+func (cb *querierCircuitBreakers) ejected() float64 {
+	cb.mtx.Lock()
+	defer cb.mtx.Unlock()
+
+	ejected := 0
+	for _, b := range cb.queriers {
+		if b.state != breakerClosed {
+			ejected++
+		}
+	}
+	return float64(ejected)
+}
@@ -0,0 +1,152 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCircuitBreakers(t *testing.T) (*querierCircuitBreakers, *time.Time) {
+	cb := newQuerierCircuitBreakers(QuerierCircuitBreakerConfig{
+		Enabled:         true,
+		DurationSLO:     time.Second,
+		MaxFailureRatio: 0.5,
+		MinJobs:         4,
+		Window:          time.Minute,
+		EjectFor:        30 * time.Second,
+	}, log.NewNopLogger(), nil)
+	require.NoError(t, cb.cfg.Validate())
+
+	now := time.Unix(1000, 0)
+	cb.now = func() time.Time { return now }
+	cb.pollInterval = time.Millisecond
+
+	return cb, &now
+}
+
+func testBatch(jobs int) *requestBatch {
+	rb := &requestBatch{}
+	for i := 0; i < jobs; i++ {
+		rb.add(&request{originalCtx: context.Background()})
+	}
+	return rb
+}
+
+func testResponses(codes ...int32) []*httpgrpc.HTTPResponse {
+	responses := make([]*httpgrpc.HTTPResponse, 0, len(codes))
+	for _, code := range codes {
+		responses = append(responses, &httpgrpc.HTTPResponse{Code: code})
+	}
+	return responses
+}
+
+func requireEjected(t *testing.T, cb *querierCircuitBreakers, querierID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := cb.acquire(ctx, querierID)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestQuerierCircuitBreakers(t *testing.T) {
+	cb, now := newTestCircuitBreakers(t)
+	cb.register("a")
+	cb.register("b")
+
+	// failures below min jobs don't eject
+	cb.release("a", false, testBatch(2), testResponses(http.StatusInternalServerError, http.StatusInternalServerError), 0)
+	probe, err := cb.acquire(context.Background(), "a")
+	require.NoError(t, err)
+	require.False(t, probe)
+
+	// client errors don't fail jobs
+	cb.release("a", false, testBatch(3), testResponses(http.StatusBadRequest, http.StatusOK, http.StatusOK), 0)
+	probe, err = cb.acquire(context.Background(), "a")
+	require.NoError(t, err)
+	require.False(t, probe)
+	require.Equal(t, 0.0, cb.ejected())
+
+	// slow batches fail all of their jobs
+	cb.release("a", false, testBatch(2), testResponses(http.StatusOK, http.StatusOK), 2*time.Second)
+	require.Equal(t, 1.0, cb.ejected())
+	requireEjected(t, cb, "a")
+
+	// the last healthy querier is never ejected
+	cb.release("b", false, testBatch(4), nil, 0)
+	require.Equal(t, 1.0, cb.ejected())
+	probe, err = cb.acquire(context.Background(), "b")
+	require.NoError(t, err)
+	require.False(t, probe)
+
+	// after the eject period a single probe is sent
+	*now = now.Add(31 * time.Second)
+	probe, err = cb.acquire(context.Background(), "a")
+	require.NoError(t, err)
+	require.True(t, probe)
+	requireEjected(t, cb, "a")
+
+	// a failed probe ejects the querier again
+	cb.release("a", true, testBatch(1), testResponses(http.StatusServiceUnavailable), 0)
+	requireEjected(t, cb, "a")
+
+	// an aborted probe is retried
+	*now = now.Add(31 * time.Second)
+	probe, err = cb.acquire(context.Background(), "a")
+	require.NoError(t, err)
+	require.True(t, probe)
+	cb.abort("a", probe)
+
+	// a successful probe restores the querier
+	probe, err = cb.acquire(context.Background(), "a")
+	require.NoError(t, err)
+	require.True(t, probe)
+	cb.release("a", true, testBatch(1), testResponses(http.StatusOK), 0)
+	require.Equal(t, 0.0, cb.ejected())
+
+	probe, err = cb.acquire(context.Background(), "a")
+	require.NoError(t, err)
+	require.False(t, probe)
+}
+
+func TestQuerierCircuitBreakersWindow(t *testing.T) {
+	cb, now := newTestCircuitBreakers(t)
+	cb.register("a")
+	cb.register("b")
+
+	cb.release("a", false, testBatch(3), testResponses(http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError), 0)
+
+	// failures of the previous window are forgotten
+	*now = now.Add(time.Minute)
+	cb.release("a", false, testBatch(3), testResponses(http.StatusOK, http.StatusOK, http.StatusInternalServerError), 0)
+	require.Equal(t, 0.0, cb.ejected())
+
+	// cancelled batches are not counted
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cb.release("a", false, &requestBatch{pipelineRequests: []*request{{originalCtx: ctx}}}, nil, 0)
+	require.Equal(t, 0.0, cb.ejected())
+
+	// broken streams fail all jobs of the batch
+	cb.release("a", false, testBatch(1), nil, 0)
+	require.Equal(t, 1.0, cb.ejected())
+
+	// the state of a querier is forgotten once all of its connections are gone
+	cb.unregister("a")
+	require.Equal(t, 0.0, cb.ejected())
+}
+
+func TestQuerierCircuitBreakersDisabled(t *testing.T) {
+	cb := newQuerierCircuitBreakers(QuerierCircuitBreakerConfig{}, log.NewNopLogger(), nil)
+	cb.register("a")
+	cb.register("b")
+
+	for i := 0; i < 100; i++ {
+		cb.release("a", false, testBatch(1), nil, 0)
+	}
+	require.Equal(t, 0.0, cb.ejected())
+}
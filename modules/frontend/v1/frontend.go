@@ -31,6 +31,8 @@ type Config struct {
 	QuerierForgetDelay      time.Duration          `yaml:"querier_forget_delay"`
 	MaxBatchSize            int                    `yaml:"max_batch_size"`
 	LogQueryRequestHeaders  flagext.StringSliceCSV `yaml:"log_query_request_headers"`
+
+	QuerierCircuitBreaker QuerierCircuitBreakerConfig `yaml:"querier_circuit_breaker"`
 }
 
 // RegisterFlags adds the flags required to config this to the given FlagSet.
@@ -43,6 +45,10 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 type Limits interface {
 	// Returns max queriers to use per tenant, or 0 if shuffle sharding is disabled.
 	MaxQueriersPerUser(user string) int
+	// Returns max requests per tenant processed by queriers at once, or 0 if unlimited.
+	MaxInflightRequestsPerUser(user string) int
+	// Returns the number of consecutive batches queriers pull for the tenant before moving on to the next one.
+	QueueWeightPerUser(user string) int
 }
 
 // Frontend queues HTTP requests, dispatches them to backends, and handles retries
@@ -54,8 +60,9 @@ type Frontend struct {
 	log    log.Logger
 	limits Limits
 
-	requestQueue *queue.RequestQueue
-	activeUsers  *util.ActiveUsersCleanupService
+	requestQueue    *queue.RequestQueue
+	activeUsers     *util.ActiveUsersCleanupService
+	circuitBreakers *querierCircuitBreakers
 
 	// Subservices manager.
 	subservices        *services.Manager
@@ -64,12 +71,14 @@ type Frontend struct {
 	// Metrics.
 	queueLength       *prometheus.GaugeVec
 	discardedRequests *prometheus.CounterVec
+	inflightRequests  *prometheus.GaugeVec
 	numClients        prometheus.GaugeFunc
 	queueDuration     prometheus.Histogram
 	actualBatchSize   prometheus.Histogram
 }
 
 type request struct {
+	userID      string
 	enqueueTime time.Time
 	queueSpan   opentracing.Span
 	originalCtx context.Context
@@ -85,6 +94,9 @@ func New(cfg Config, limits Limits, log log.Logger, registerer prometheus.Regist
 	if cfg.MaxBatchSize <= 0 {
 		return nil, errors.New("max_batch_size must be positive")
 	}
+	if err := cfg.QuerierCircuitBreaker.Validate(); err != nil {
+		return nil, err
+	}
 	batchBucketSize := float64(cfg.MaxBatchSize) / float64(batchBucketCount)
 
 	f := &Frontend{
@@ -99,6 +111,10 @@ func New(cfg Config, limits Limits, log log.Logger, registerer prometheus.Regist
 			Name: "tempo_query_frontend_discarded_requests_total",
 			Help: "Total number of query requests discarded.",
 		}, []string{"user"}),
+		inflightRequests: promauto.With(registerer).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "tempo_query_frontend_inflight_requests",
+			Help: "Number of queries dequeued and being processed by queriers.",
+		}, []string{"user"}),
 		queueDuration: promauto.With(registerer).NewHistogram(prometheus.HistogramOpts{
 			Name:    "tempo_query_frontend_queue_duration_seconds",
 			Help:    "Time spend by requests queued.",
@@ -111,8 +127,9 @@ func New(cfg Config, limits Limits, log log.Logger, registerer prometheus.Regist
 		}),
 	}
 
-	f.requestQueue = queue.NewRequestQueue(cfg.MaxOutstandingPerTenant, cfg.QuerierForgetDelay, f.queueLength, f.discardedRequests)
+	f.requestQueue = queue.NewRequestQueue(cfg.MaxOutstandingPerTenant, cfg.QuerierForgetDelay, f.queueLength, f.discardedRequests, f.inflightRequests)
 	f.activeUsers = util.NewActiveUsersCleanupWithDefaultValues(f.cleanupInactiveUserMetrics)
+	f.circuitBreakers = newQuerierCircuitBreakers(cfg.QuerierCircuitBreaker, log, registerer)
 
 	var err error
 	f.subservices, err = services.NewManager(f.requestQueue, f.activeUsers)
@@ -159,6 +176,7 @@ func (f *Frontend) stopping(_ error) error {
 func (f *Frontend) cleanupInactiveUserMetrics(user string) {
 	f.queueLength.DeleteLabelValues(user)
 	f.discardedRequests.DeleteLabelValues(user)
+	f.inflightRequests.DeleteLabelValues(user)
 }
 
 // RoundTripGRPC round trips a proto (instead of a HTTP request).
@@ -209,6 +227,8 @@ func (f *Frontend) Process(server frontendv1pb.Frontend_ProcessServer) error {
 
 	f.requestQueue.RegisterQuerierConnection(querierID)
 	defer f.requestQueue.UnregisterQuerierConnection(querierID)
+	f.circuitBreakers.register(querierID)
+	defer f.circuitBreakers.unregister(querierID)
 
 	lastUserIndex := queue.FirstUser()
 
@@ -218,13 +238,23 @@ func (f *Frontend) Process(server frontendv1pb.Frontend_ProcessServer) error {
 		batchSize = f.cfg.MaxBatchSize
 	}
 	for {
+		// wait while the querier is ejected for failing jobs
+		probe, err := f.circuitBreakers.acquire(server.Context(), querierID)
+		if err != nil {
+			return err
+		}
+
 		reqSlice := make([]queue.Request, batchSize)
 		reqSlice, idx, err := f.requestQueue.GetNextRequestForQuerier(server.Context(), lastUserIndex, querierID, reqSlice)
 		if err != nil {
+			f.circuitBreakers.abort(querierID, probe)
 			return err
 		}
 		lastUserIndex = idx
 
+		// all requests of a batch belong to the same user
+		userID := reqSlice[0].(*request).userID
+
 		reqBatch.clear()
 		for _, reqWrapper := range reqSlice {
 			req := reqWrapper.(*request)
@@ -243,6 +273,8 @@ func (f *Frontend) Process(server frontendv1pb.Frontend_ProcessServer) error {
 		// if all requests are expired then continue requesting jobs for this user. this nicely
 		// drains a large expired query for a tenant and allows them to execute a real query
 		if reqBatch.len() == 0 {
+			f.circuitBreakers.abort(querierID, probe)
+			f.requestQueue.ReleaseRequests(userID, len(reqSlice))
 			lastUserIndex = lastUserIndex.ReuseLastUser()
 			continue
 		}
@@ -253,19 +285,23 @@ func (f *Frontend) Process(server frontendv1pb.Frontend_ProcessServer) error {
 		// monitoring the contexts in a select and cancel things appropriately.
 		resps := make(chan *frontendv1pb.ClientToFrontend, 1)
 		errs := make(chan error, 1)
+		start := time.Now()
 		go func() {
 			// todo: we are still sending the old Type_HTTP_REQUEST for backwards compat
 			// with queriers that don't support the new Type_HTTP_REQUEST_BATCH. this feature
 			// was introduced in 2.2. We should remove this in a few versions
+			queueLength := int32(f.requestQueue.QueueLength())
 			if reqBatch.len() == 1 {
 				err = server.Send(&frontendv1pb.FrontendToClient{
 					Type:        frontendv1pb.Type_HTTP_REQUEST,
 					HttpRequest: reqBatch.httpGrpcRequests()[0],
+					QueueLength: queueLength,
 				})
 			} else {
 				err = server.Send(&frontendv1pb.FrontendToClient{
 					Type:             frontendv1pb.Type_HTTP_REQUEST_BATCH,
 					HttpRequestBatch: reqBatch.httpGrpcRequests(),
+					QueueLength:      queueLength,
 				})
 			}
 			if err != nil {
@@ -282,14 +318,18 @@ func (f *Frontend) Process(server frontendv1pb.Frontend_ProcessServer) error {
 			resps <- resp
 		}()
 
-		err = reportResponseUpstream(reqBatch, errs, resps)
+		responses, err := reportResponseUpstream(reqBatch, errs, resps)
+		f.circuitBreakers.release(querierID, probe, reqBatch, responses, time.Since(start))
+		f.requestQueue.ReleaseRequests(userID, len(reqSlice))
 		if err != nil {
 			return err
 		}
 	}
 }
 
-func reportResponseUpstream(reqBatch *requestBatch, errs chan error, resps chan *frontendv1pb.ClientToFrontend) error {
+// reportResponseUpstream waits for the responses of the querier to the batch and reports them upstream. It returns
+// the responses, which are nil if the batch was cancelled or failed.
+func reportResponseUpstream(reqBatch *requestBatch, errs chan error, resps chan *frontendv1pb.ClientToFrontend) ([]*httpgrpc.HTTPResponse, error) {
 	stopCh := make(chan struct{})
 	defer close(stopCh)
 
@@ -298,7 +338,7 @@ func reportResponseUpstream(reqBatch *requestBatch, errs chan error, resps chan
 	// downstream req.  Only way we can do that is to close the stream.
 	// The worker client is expecting this semantics.
 	case <-reqBatch.doneChan(stopCh):
-		return reqBatch.contextError()
+		return nil, reqBatch.contextError()
 
 	// Is there was an error handling this request due to network IO,
 	// then error out this upstream request _and_ stream.
@@ -306,24 +346,21 @@ func reportResponseUpstream(reqBatch *requestBatch, errs chan error, resps chan
 	// of error.
 	case err := <-errs:
 		reqBatch.reportErrorToPipeline(err)
-		return err
+		return nil, err
 
 	// Happy path :D
 	case resp := <-resps:
 		// todo: like above support for batches and single requests
 		// can be removed in a few versions once all queriers support batching
-		var err error
-		if len(resp.HttpResponseBatch) == 0 {
-			err = reqBatch.reportResultsToPipeline([]*httpgrpc.HTTPResponse{resp.HttpResponse})
-		} else {
-			err = reqBatch.reportResultsToPipeline(resp.HttpResponseBatch)
+		responses := resp.HttpResponseBatch
+		if len(responses) == 0 {
+			responses = []*httpgrpc.HTTPResponse{resp.HttpResponse}
 		}
-		if err != nil {
-			return fmt.Errorf("unexpected error reporting results upstream: %w", err)
+		if err := reqBatch.reportResultsToPipeline(responses); err != nil {
+			return nil, fmt.Errorf("unexpected error reporting results upstream: %w", err)
 		}
+		return responses, nil
 	}
-
-	return nil
 }
 
 func (f *Frontend) NotifyClientShutdown(_ context.Context, req *frontendv1pb.NotifyClientShutdownRequest) (*frontendv1pb.NotifyClientShutdownResponse, error) {
@@ -368,13 +405,18 @@ func (f *Frontend) queueRequest(ctx context.Context, req *request) error {
 	req.enqueueTime = now
 	req.queueSpan, _ = opentracing.StartSpanFromContext(ctx, "queued")
 
-	// aggregate the max queriers limit in the case of a multi tenant query
-	maxQueriers := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, f.limits.MaxQueriersPerUser)
+	// aggregate the queue limits in the case of a multi tenant query
+	limits := queue.UserLimits{
+		MaxQueriers: validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, f.limits.MaxQueriersPerUser),
+		MaxInflight: validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, f.limits.MaxInflightRequestsPerUser),
+		Weight:      validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, f.limits.QueueWeightPerUser),
+	}
 
 	joinedTenantID := tenant.JoinTenantIDs(tenantIDs)
 	f.activeUsers.UpdateUserTimestamp(joinedTenantID, now)
+	req.userID = joinedTenantID
 
-	return f.requestQueue.EnqueueRequest(joinedTenantID, req, maxQueriers)
+	return f.requestQueue.EnqueueRequest(joinedTenantID, req, limits)
 }
 
 // CheckReady determines if the query frontend is ready.  Function parameters/return
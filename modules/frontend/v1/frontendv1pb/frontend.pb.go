@@ -89,6 +89,7 @@ type FrontendToClient struct {
 	Type        Type                  `protobuf:"varint,2,opt,name=type,proto3,enum=frontend.Type" json:"type,omitempty"`
 	// bool statsEnabled = 3; - removed in 2.2. reserved until we can cleanly reclaim it
 	HttpRequestBatch []*httpgrpc.HTTPRequest `protobuf:"bytes,4,rep,name=httpRequestBatch,proto3" json:"httpRequestBatch,omitempty"`
+	QueueLength      int32                   `protobuf:"varint,5,opt,name=queueLength,proto3" json:"queueLength,omitempty"`
 }
 
 func (m *FrontendToClient) Reset()         { *m = FrontendToClient{} }
@@ -145,6 +146,13 @@ func (m *FrontendToClient) GetHttpRequestBatch() []*httpgrpc.HTTPRequest {
 	return nil
 }
 
+func (m *FrontendToClient) GetQueueLength() int32 {
+	if m != nil {
+		return m.QueueLength
+	}
+	return 0
+}
+
 type ClientToFrontend struct {
 	HttpResponse *httpgrpc.HTTPResponse `protobuf:"bytes,1,opt,name=httpResponse,proto3" json:"httpResponse,omitempty"`
 	ClientID     string                 `protobuf:"bytes,2,opt,name=clientID,proto3" json:"clientID,omitempty"`
@@ -308,38 +316,38 @@ func init() {
 }
 
 var fileDescriptor_8e6c94795ed772cd = []byte{
-	// 483 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x53, 0xc1, 0x6e, 0xd3, 0x40,
-	0x10, 0xf5, 0x96, 0xb4, 0x0d, 0xd3, 0xa8, 0x5a, 0x46, 0xa1, 0x8a, 0x0c, 0xb2, 0x22, 0x4b, 0x54,
-	0xa1, 0x87, 0xb8, 0x0d, 0x07, 0x04, 0xe2, 0xd2, 0x36, 0x69, 0x9a, 0x4b, 0x28, 0xae, 0xb9, 0x70,
-	0x89, 0x1c, 0x67, 0xe3, 0x44, 0xb4, 0x5e, 0x63, 0xaf, 0x8b, 0xf2, 0x17, 0x7c, 0x0b, 0x47, 0xbe,
-	0x80, 0x03, 0x87, 0x1e, 0x39, 0xa2, 0xe4, 0x47, 0x90, 0xed, 0x64, 0xbb, 0x49, 0x1b, 0xb8, 0xcd,
-	0xec, 0x7b, 0x6f, 0xe6, 0xed, 0xcc, 0x2e, 0x58, 0xd7, 0x7c, 0x90, 0x5c, 0xb1, 0xd8, 0x1a, 0x46,
-	0x3c, 0x10, 0x2c, 0x18, 0x58, 0x37, 0x47, 0x32, 0xbe, 0x39, 0x0a, 0xfb, 0x32, 0xa9, 0x87, 0x11,
-	0x17, 0x1c, 0x8b, 0x8b, 0x5c, 0x2f, 0xfb, 0xdc, 0xe7, 0xd9, 0xa1, 0x95, 0x46, 0x39, 0xae, 0x1f,
-	0xfa, 0x63, 0x31, 0x4a, 0xfa, 0x75, 0x8f, 0x5f, 0x5b, 0x7e, 0xe4, 0x0e, 0xdd, 0xc0, 0xb5, 0x06,
-	0xf1, 0xe7, 0xb1, 0xb0, 0x46, 0x42, 0x84, 0x7e, 0x14, 0x7a, 0x32, 0xc8, 0x15, 0xe6, 0x77, 0x02,
-	0xf4, 0x6c, 0x5e, 0xd4, 0xe1, 0xa7, 0x57, 0x63, 0x16, 0x08, 0x7c, 0x0d, 0x3b, 0x29, 0xcd, 0x66,
-	0x5f, 0x12, 0x16, 0x8b, 0x0a, 0xa9, 0x92, 0xda, 0x4e, 0xe3, 0x69, 0x5d, 0x4a, 0xcf, 0x1d, 0xe7,
-	0x62, 0x0e, 0xda, 0x2a, 0x13, 0x4d, 0x28, 0x88, 0x49, 0xc8, 0x2a, 0x1b, 0x55, 0x52, 0xdb, 0x6d,
-	0xec, 0xd6, 0xa5, 0x7d, 0x67, 0x12, 0x32, 0x3b, 0xc3, 0xf0, 0x18, 0xa8, 0x22, 0x39, 0x71, 0x85,
-	0x37, 0xaa, 0x14, 0xaa, 0x8f, 0xd6, 0x77, 0xb8, 0x47, 0x37, 0x7f, 0x11, 0xa0, 0xb9, 0x55, 0x87,
-	0x2f, 0xcc, 0xe3, 0x5b, 0x28, 0xe5, 0xc4, 0x38, 0xe4, 0x41, 0xcc, 0xe6, 0xae, 0xf7, 0x56, 0x6b,
-	0xe6, 0xa8, 0xbd, 0xc4, 0x45, 0x1d, 0x8a, 0x5e, 0x56, 0xaf, 0xd3, 0xcc, 0xbc, 0x3f, 0xb6, 0x65,
-	0x8e, 0x4d, 0x78, 0xa2, 0x72, 0x55, 0xc3, 0xeb, 0x8a, 0xdf, 0x17, 0xa4, 0x1d, 0x86, 0xcc, 0x15,
-	0x49, 0xc4, 0xe2, 0xca, 0x66, 0x95, 0xd4, 0x36, 0x6d, 0x99, 0x9b, 0x6f, 0xe0, 0x59, 0x97, 0x8b,
-	0xf1, 0x70, 0x92, 0xdf, 0xe9, 0x72, 0x94, 0x88, 0x01, 0xff, 0x1a, 0x2c, 0x86, 0xaa, 0x9a, 0x23,
-	0xcb, 0xe6, 0x4c, 0x03, 0x9e, 0x3f, 0x2c, 0xcd, 0x7b, 0x1f, 0xbc, 0x83, 0x42, 0x3a, 0x7a, 0xa4,
-	0x50, 0x4a, 0x1d, 0xf6, 0xec, 0xd6, 0x87, 0x8f, 0xad, 0x4b, 0x87, 0x6a, 0x08, 0xb0, 0xd5, 0x6e,
-	0x39, 0xbd, 0x4e, 0x93, 0x12, 0xdc, 0x03, 0x54, 0xd1, 0xde, 0xc9, 0xb1, 0x73, 0x7a, 0x4e, 0x37,
-	0x0e, 0x5e, 0xc2, 0xf6, 0x59, 0x6e, 0x12, 0x8b, 0x50, 0xe8, 0xbe, 0xef, 0xb6, 0xa8, 0x86, 0x65,
-	0xa0, 0x4b, 0xbc, 0x4e, 0xb7, 0x4d, 0x49, 0xe3, 0x07, 0x81, 0xa2, 0x5c, 0x45, 0x1b, 0xb6, 0x2f,
-	0x22, 0xee, 0xb1, 0x38, 0x46, 0xfd, 0xee, 0x0d, 0xac, 0x6e, 0x4c, 0x57, 0xb0, 0xd5, 0x27, 0x68,
-	0x6a, 0x35, 0x72, 0x48, 0x90, 0x41, 0xf9, 0xa1, 0xeb, 0xe1, 0x8b, 0x3b, 0xe5, 0x3f, 0x26, 0xa7,
-	0xef, 0xff, 0x8f, 0x36, 0xdf, 0xd0, 0xfe, 0xcf, 0xa9, 0x41, 0x6e, 0xa7, 0x06, 0xf9, 0x33, 0x35,
-	0xc8, 0xb7, 0x99, 0xa1, 0xdd, 0xce, 0x0c, 0xed, 0xf7, 0xcc, 0xd0, 0x3e, 0x95, 0xd4, 0xdf, 0xd8,
-	0xdf, 0xca, 0xfe, 0xcc, 0xab, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x94, 0x02, 0xa2, 0x13, 0xb8,
-	0x03, 0x00, 0x00,
+	// 496 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x53, 0xcd, 0x6e, 0xd3, 0x40,
+	0x10, 0xf6, 0x96, 0xb4, 0x0d, 0x93, 0xa8, 0x5a, 0x46, 0xa1, 0x8a, 0x0c, 0xb2, 0x2c, 0x4b, 0x54,
+	0xa1, 0x87, 0xb8, 0x0d, 0x07, 0x04, 0xe2, 0xd2, 0x36, 0x69, 0x1a, 0x09, 0x85, 0xe2, 0x9a, 0x0b,
+	0x97, 0xc8, 0x71, 0x36, 0x76, 0x44, 0xeb, 0x75, 0xed, 0x75, 0x51, 0xde, 0x82, 0xe7, 0xe1, 0x09,
+	0x38, 0x70, 0xe8, 0x81, 0x03, 0x47, 0x94, 0xbc, 0x08, 0xf2, 0x4f, 0x5c, 0x27, 0x6d, 0xe0, 0x36,
+	0xb3, 0xdf, 0xf7, 0xcd, 0xcc, 0xb7, 0xb3, 0x0b, 0xfa, 0x15, 0x1f, 0x45, 0x97, 0x2c, 0xd4, 0xc7,
+	0x01, 0xf7, 0x04, 0xf3, 0x46, 0xfa, 0xcd, 0x61, 0x1e, 0xdf, 0x1c, 0xfa, 0xc3, 0x3c, 0x69, 0xfa,
+	0x01, 0x17, 0x1c, 0xcb, 0x8b, 0x5c, 0xae, 0x39, 0xdc, 0xe1, 0xc9, 0xa1, 0x1e, 0x47, 0x29, 0x2e,
+	0x1f, 0x38, 0x13, 0xe1, 0x46, 0xc3, 0xa6, 0xcd, 0xaf, 0x74, 0x27, 0xb0, 0xc6, 0x96, 0x67, 0xe9,
+	0xa3, 0xf0, 0xcb, 0x44, 0xe8, 0xae, 0x10, 0xbe, 0x13, 0xf8, 0x76, 0x1e, 0xa4, 0x0a, 0xed, 0x17,
+	0x01, 0x7a, 0x9a, 0x15, 0x35, 0xf9, 0xc9, 0xe5, 0x84, 0x79, 0x02, 0x5f, 0x43, 0x25, 0xa6, 0x19,
+	0xec, 0x3a, 0x62, 0xa1, 0xa8, 0x13, 0x95, 0x34, 0x2a, 0xad, 0xa7, 0xcd, 0x5c, 0x7a, 0x66, 0x9a,
+	0xe7, 0x19, 0x68, 0x14, 0x99, 0xa8, 0x41, 0x49, 0x4c, 0x7d, 0x56, 0xdf, 0x50, 0x49, 0x63, 0xa7,
+	0xb5, 0xd3, 0xcc, 0xc7, 0x37, 0xa7, 0x3e, 0x33, 0x12, 0x0c, 0x8f, 0x80, 0x16, 0x24, 0xc7, 0x96,
+	0xb0, 0xdd, 0x7a, 0x49, 0x7d, 0xb4, 0xbe, 0xc3, 0x3d, 0x3a, 0xaa, 0x50, 0xb9, 0x8e, 0x58, 0xc4,
+	0xde, 0x33, 0xcf, 0x11, 0x6e, 0x7d, 0x53, 0x25, 0x8d, 0x4d, 0xa3, 0x78, 0xa4, 0xfd, 0x24, 0x40,
+	0x53, 0x33, 0x26, 0x5f, 0xd8, 0xc3, 0xb7, 0x50, 0x4d, 0x4b, 0x85, 0x3e, 0xf7, 0x42, 0x96, 0xf9,
+	0xda, 0x5d, 0xed, 0x9a, 0xa2, 0xc6, 0x12, 0x17, 0x65, 0x28, 0xdb, 0x49, 0xbd, 0x5e, 0x3b, 0x71,
+	0xf7, 0xd8, 0xc8, 0x73, 0x6c, 0xc3, 0x93, 0x22, 0xb7, 0x68, 0x69, 0x5d, 0xf1, 0xfb, 0x82, 0xb8,
+	0xc3, 0x98, 0x59, 0x22, 0x0a, 0x58, 0x98, 0x39, 0xca, 0x73, 0xed, 0x0d, 0x3c, 0xeb, 0x73, 0x31,
+	0x19, 0x4f, 0x53, 0x4f, 0x17, 0x6e, 0x24, 0x46, 0xfc, 0xab, 0xb7, 0xb8, 0xf6, 0xe2, 0x70, 0x64,
+	0x79, 0x38, 0x4d, 0x81, 0xe7, 0x0f, 0x4b, 0xd3, 0xde, 0xfb, 0xef, 0xa0, 0x14, 0x2f, 0x07, 0x29,
+	0x54, 0xe3, 0x09, 0x07, 0x46, 0xe7, 0xe3, 0xa7, 0xce, 0x85, 0x49, 0x25, 0x04, 0xd8, 0xea, 0x76,
+	0xcc, 0x41, 0xaf, 0x4d, 0x09, 0xee, 0x02, 0x16, 0xd1, 0xc1, 0xf1, 0x91, 0x79, 0x72, 0x46, 0x37,
+	0xf6, 0x5f, 0xc2, 0xf6, 0x69, 0x3a, 0x24, 0x96, 0xa1, 0xd4, 0xff, 0xd0, 0xef, 0x50, 0x09, 0x6b,
+	0x40, 0x97, 0x78, 0xbd, 0x7e, 0x97, 0x92, 0xd6, 0x77, 0x02, 0xe5, 0x7c, 0x15, 0x5d, 0xd8, 0x3e,
+	0x0f, 0xb8, 0xcd, 0xc2, 0x10, 0xe5, 0xbb, 0x57, 0xb2, 0xba, 0x31, 0xb9, 0x80, 0xad, 0x3e, 0x52,
+	0x4d, 0x6a, 0x90, 0x03, 0x82, 0x0c, 0x6a, 0x0f, 0xd9, 0xc3, 0x17, 0x77, 0xca, 0x7f, 0xdc, 0x9c,
+	0xbc, 0xf7, 0x3f, 0x5a, 0xb6, 0xa1, 0xbd, 0x1f, 0x33, 0x85, 0xdc, 0xce, 0x14, 0xf2, 0x67, 0xa6,
+	0x90, 0x6f, 0x73, 0x45, 0xba, 0x9d, 0x2b, 0xd2, 0xef, 0xb9, 0x22, 0x7d, 0xae, 0x16, 0xff, 0xeb,
+	0x70, 0x2b, 0xf9, 0x55, 0xaf, 0xfe, 0x0e, 0x00, 0x0a, 0xb4, 0x6c, 0x64, 0xda, 0x03, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -517,6 +525,11 @@ func (m *FrontendToClient) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.QueueLength != 0 {
+		i = encodeVarintFrontend(dAtA, i, uint64(m.QueueLength))
+		i--
+		dAtA[i] = 0x28
+	}
 	if len(m.HttpRequestBatch) > 0 {
 		for iNdEx := len(m.HttpRequestBatch) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -695,6 +708,9 @@ func (m *FrontendToClient) Size() (n int) {
 			n += 1 + l + sovFrontend(uint64(l))
 		}
 	}
+	if m.QueueLength != 0 {
+		n += 1 + sovFrontend(uint64(m.QueueLength))
+	}
 	return n
 }
 
@@ -870,6 +886,25 @@ func (m *FrontendToClient) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QueueLength", wireType)
+			}
+			m.QueueLength = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFrontend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.QueueLength |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFrontend(dAtA[iNdEx:])
@@ -0,0 +1,191 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/grafana/dskit/httpgrpc"
+
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+const (
+	statusSuccess = "success"
+	statusError   = "error"
+
+	errorTypeBadRequest      = "bad_request"
+	errorTypeNotAcceptable   = "not_acceptable"
+	errorTypeTooManyRequests = "too_many_requests"
+	errorTypeCanceled        = "canceled"
+	errorTypeTimeout         = "timeout"
+	errorTypeInternal        = "internal"
+)
+
+// newSearchV2Handler serves the search endpoint in the v2 response envelope
+func newSearchV2Handler(next http.RoundTripper) http.RoundTripper {
+	return newV2Handler(next, api.PathSearchV2, api.PathSearch, func(body io.Reader, env *tempopb.QueryResponseV2) error {
+		resp := &tempopb.SearchResponse{}
+		if err := jsonpb.Unmarshal(body, resp); err != nil {
+			return err
+		}
+		env.Partial = resp.Partial
+		env.Data = &tempopb.QueryResponseV2_Search{Search: resp}
+		return nil
+	})
+}
+
+// newMetricsQueryRangeV2Handler serves the metrics query range endpoint in the v2 response envelope
+func newMetricsQueryRangeV2Handler(next http.RoundTripper) http.RoundTripper {
+	return newV2Handler(next, api.PathMetricsQueryRangeV2, api.PathMetricsQueryRange, func(body io.Reader, env *tempopb.QueryResponseV2) error {
+		resp := &tempopb.QueryRangeResponse{}
+		if err := jsonpb.Unmarshal(body, resp); err != nil {
+			return err
+		}
+		env.Data = &tempopb.QueryResponseV2_QueryRange{QueryRange: resp}
+		return nil
+	})
+}
+
+// newV2Handler wraps the handler of a v1 endpoint, which returns json, in the v2 response envelope. The envelope
+// is marshalled as protobuf or json depending on the Accept header. Errors of the v1 handler, either returned or
+// as a non 200 response, are converted to an error envelope with the same status code.
+func newV2Handler(next http.RoundTripper, v2Path, v1Path string, setData func(io.Reader, *tempopb.QueryResponseV2) error) http.RoundTripper {
+	return pipeline.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		format, ok := negotiateFormat(req.Header.Get(api.HeaderAccept))
+		if !ok {
+			return v2ErrorResponse(api.HeaderAcceptJSON, http.StatusNotAcceptable,
+				"unsupported Accept header, supported types are "+api.HeaderAcceptJSON+" and "+api.HeaderAcceptProtobuf)
+		}
+
+		// the sharders build the querier requests from the path of the request
+		req.URL.Path = strings.TrimSuffix(req.URL.Path, v2Path) + v1Path
+		req.RequestURI = strings.Replace(req.RequestURI, v2Path, v1Path, 1)
+		req.Header.Set(api.HeaderAccept, api.HeaderAcceptJSON)
+
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			code, msg := errorStatus(err)
+			return v2ErrorResponse(format, code, msg)
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusOK {
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, err
+			}
+			return v2ErrorResponse(format, resp.StatusCode, string(body))
+		}
+
+		env := &tempopb.QueryResponseV2{Status: statusSuccess}
+		if err := setData(resp.Body, env); err != nil {
+			return v2ErrorResponse(format, http.StatusInternalServerError, "error unmarshalling response body: "+err.Error())
+		}
+
+		return v2Response(format, http.StatusOK, env)
+	})
+}
+
+// negotiateFormat returns the first media type of the Accept header that is supported. No header or
+// a wildcard is json.
+func negotiateFormat(accept string) (string, bool) {
+	if accept == "" {
+		return api.HeaderAcceptJSON, true
+	}
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		switch mediaType {
+		case api.HeaderAcceptProtobuf:
+			return api.HeaderAcceptProtobuf, true
+		case api.HeaderAcceptJSON, "application/*", "*/*":
+			return api.HeaderAcceptJSON, true
+		}
+	}
+
+	return "", false
+}
+
+// errorStatus returns the status code and message of an error returned by a handler. It mirrors writeError.
+func errorStatus(err error) (int, string) {
+	if errors.Is(err, context.Canceled) {
+		err = errCanceled
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		err = errDeadlineExceeded
+	} else if isRequestBodyTooLarge(err) {
+		err = errRequestEntityTooLarge
+	}
+
+	if resp, ok := httpgrpc.HTTPResponseFromError(err); ok {
+		return int(resp.Code), string(resp.Body)
+	}
+	return http.StatusInternalServerError, err.Error()
+}
+
+func errorType(code int) string {
+	switch {
+	case code == http.StatusNotAcceptable:
+		return errorTypeNotAcceptable
+	case code == http.StatusTooManyRequests:
+		return errorTypeTooManyRequests
+	case code == StatusClientClosedRequest:
+		return errorTypeCanceled
+	case code == http.StatusGatewayTimeout || code == http.StatusRequestTimeout:
+		return errorTypeTimeout
+	case code/100 == 4:
+		return errorTypeBadRequest
+	default:
+		return errorTypeInternal
+	}
+}
+
+func v2ErrorResponse(format string, code int, msg string) (*http.Response, error) {
+	return v2Response(format, code, &tempopb.QueryResponseV2{
+		Status: statusError,
+		Error: &tempopb.QueryErrorV2{
+			Code:    int32(code),
+			Type:    errorType(code),
+			Message: strings.TrimSpace(msg),
+		},
+	})
+}
+
+func v2Response(format string, code int, env *tempopb.QueryResponseV2) (*http.Response, error) {
+	var (
+		body []byte
+		err  error
+	)
+	if format == api.HeaderAcceptProtobuf {
+		body, err = proto.Marshal(env)
+	} else {
+		var s string
+		s, err = new(jsonpb.Marshaler).MarshalToString(env)
+		body = []byte(s)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: code,
+		Status:     http.StatusText(code),
+		Header: http.Header{
+			api.HeaderContentType: {format},
+		},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}, nil
+}
@@ -0,0 +1,184 @@
+package frontend
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+func TestSearchV2Handler(t *testing.T) {
+	searchResp := &tempopb.SearchResponse{
+		Traces:  []*tempopb.TraceSearchMetadata{{TraceID: "1234", RootServiceName: "svc"}},
+		Metrics: &tempopb.SearchMetrics{InspectedTraces: 1},
+		Partial: true,
+	}
+	searchJSON, err := new(jsonpb.Marshaler).MarshalToString(searchResp)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name         string
+		accept       string
+		resp         *http.Response
+		err          error
+		expectedCode int
+		expectedType string
+		expected     *tempopb.QueryResponseV2
+	}{
+		{
+			name:         "json",
+			resp:         &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(searchJSON))},
+			expectedCode: http.StatusOK,
+			expectedType: api.HeaderAcceptJSON,
+			expected: &tempopb.QueryResponseV2{
+				Status:  statusSuccess,
+				Partial: true,
+				Data:    &tempopb.QueryResponseV2_Search{Search: searchResp},
+			},
+		},
+		{
+			name:         "protobuf",
+			accept:       "application/protobuf, application/json;q=0.5",
+			resp:         &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(searchJSON))},
+			expectedCode: http.StatusOK,
+			expectedType: api.HeaderAcceptProtobuf,
+			expected: &tempopb.QueryResponseV2{
+				Status:  statusSuccess,
+				Partial: true,
+				Data:    &tempopb.QueryResponseV2_Search{Search: searchResp},
+			},
+		},
+		{
+			name:         "wildcard",
+			accept:       "text/html, */*",
+			resp:         &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(searchJSON))},
+			expectedCode: http.StatusOK,
+			expectedType: api.HeaderAcceptJSON,
+			expected: &tempopb.QueryResponseV2{
+				Status:  statusSuccess,
+				Partial: true,
+				Data:    &tempopb.QueryResponseV2_Search{Search: searchResp},
+			},
+		},
+		{
+			name:         "not acceptable",
+			accept:       "text/html",
+			expectedCode: http.StatusNotAcceptable,
+			expectedType: api.HeaderAcceptJSON,
+			expected: &tempopb.QueryResponseV2{
+				Status: statusError,
+				Error: &tempopb.QueryErrorV2{
+					Code:    http.StatusNotAcceptable,
+					Type:    errorTypeNotAcceptable,
+					Message: "unsupported Accept header, supported types are application/json and application/protobuf",
+				},
+			},
+		},
+		{
+			name:         "bad request",
+			resp:         &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(strings.NewReader("invalid TraceQL query\n"))},
+			expectedCode: http.StatusBadRequest,
+			expectedType: api.HeaderAcceptJSON,
+			expected: &tempopb.QueryResponseV2{
+				Status: statusError,
+				Error: &tempopb.QueryErrorV2{
+					Code:    http.StatusBadRequest,
+					Type:    errorTypeBadRequest,
+					Message: "invalid TraceQL query",
+				},
+			},
+		},
+		{
+			name:         "canceled",
+			accept:       api.HeaderAcceptProtobuf,
+			err:          context.Canceled,
+			expectedCode: StatusClientClosedRequest,
+			expectedType: api.HeaderAcceptProtobuf,
+			expected: &tempopb.QueryResponseV2{
+				Status: statusError,
+				Error: &tempopb.QueryErrorV2{
+					Code:    StatusClientClosedRequest,
+					Type:    errorTypeCanceled,
+					Message: context.Canceled.Error(),
+				},
+			},
+		},
+		{
+			name:         "internal error",
+			err:          io.ErrUnexpectedEOF,
+			expectedCode: http.StatusInternalServerError,
+			expectedType: api.HeaderAcceptJSON,
+			expected: &tempopb.QueryResponseV2{
+				Status: statusError,
+				Error: &tempopb.QueryErrorV2{
+					Code:    http.StatusInternalServerError,
+					Type:    errorTypeInternal,
+					Message: io.ErrUnexpectedEOF.Error(),
+				},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var downstreamPath string
+			next := pipeline.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				downstreamPath = req.URL.Path
+				return tc.resp, tc.err
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/tempo"+api.PathSearchV2+"?q={}", nil)
+			if tc.accept != "" {
+				req.Header.Set(api.HeaderAccept, tc.accept)
+			}
+
+			resp, err := newSearchV2Handler(next).RoundTrip(req)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedCode, resp.StatusCode)
+
+			if tc.expectedCode != http.StatusNotAcceptable {
+				require.Equal(t, "/tempo"+api.PathSearch, downstreamPath)
+			}
+
+			require.Equal(t, tc.expectedType, resp.Header.Get(api.HeaderContentType))
+			actual := &tempopb.QueryResponseV2{}
+			if tc.expectedType == api.HeaderAcceptProtobuf {
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				require.NoError(t, proto.Unmarshal(body, actual))
+			} else {
+				require.NoError(t, jsonpb.Unmarshal(resp.Body, actual))
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestMetricsQueryRangeV2Handler(t *testing.T) {
+	next := pipeline.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		require.Equal(t, api.PathMetricsQueryRange, req.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"series":[{"promLabels":"{}","samples":[{"timestampMs":"1000","value":1}]}]}`)),
+		}, nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, api.PathMetricsQueryRangeV2+"?q={}|rate()", nil)
+	resp, err := newMetricsQueryRangeV2Handler(next).RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"status":"success","queryRange":{"series":[{"promLabels":"{}","samples":[{"timestampMs":"1000","value":1}]}]}}`, string(body))
+}
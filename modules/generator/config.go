@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/grafana/tempo/modules/generator/processor/linkmetrics"
 	"github.com/grafana/tempo/modules/generator/processor/localblocks"
 	"github.com/grafana/tempo/modules/generator/processor/servicegraphs"
+	"github.com/grafana/tempo/modules/generator/processor/spanevents"
 	"github.com/grafana/tempo/modules/generator/processor/spanmetrics"
 	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/modules/generator/storage"
@@ -34,6 +36,8 @@ type Config struct {
 	MetricsIngestionSlack time.Duration `yaml:"metrics_ingestion_time_range_slack"`
 	QueryTimeout          time.Duration `yaml:"query_timeout"`
 	OverrideRingKey       string        `yaml:"override_ring_key"`
+	// TenantDeletionCheckPeriod is how often the backend is checked for deleted tenants
+	TenantDeletionCheckPeriod time.Duration `yaml:"tenant_deletion_check_period"`
 }
 
 // RegisterFlagsAndApplyDefaults registers the flags.
@@ -49,17 +53,22 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	cfg.MetricsIngestionSlack = 30 * time.Second
 	cfg.QueryTimeout = 30 * time.Second
 	cfg.OverrideRingKey = generatorRingKey
+	cfg.TenantDeletionCheckPeriod = 5 * time.Minute
 }
 
 type ProcessorConfig struct {
 	ServiceGraphs servicegraphs.Config `yaml:"service_graphs"`
 	SpanMetrics   spanmetrics.Config   `yaml:"span_metrics"`
+	SpanEvents    spanevents.Config    `yaml:"span_events"`
+	LinkMetrics   linkmetrics.Config   `yaml:"link_metrics"`
 	LocalBlocks   localblocks.Config   `yaml:"local_blocks"`
 }
 
 func (cfg *ProcessorConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	cfg.ServiceGraphs.RegisterFlagsAndApplyDefaults(prefix, f)
 	cfg.SpanMetrics.RegisterFlagsAndApplyDefaults(prefix, f)
+	cfg.SpanEvents.RegisterFlagsAndApplyDefaults(prefix, f)
+	cfg.LinkMetrics.RegisterFlagsAndApplyDefaults(prefix, f)
 	cfg.LocalBlocks.RegisterFlagsAndApplyDefaults(prefix, f)
 }
 
@@ -70,12 +79,24 @@ func (cfg *ProcessorConfig) copyWithOverrides(o metricsGeneratorOverrides, userI
 	if buckets := o.MetricsGeneratorProcessorServiceGraphsHistogramBuckets(userID); buckets != nil {
 		copyCfg.ServiceGraphs.HistogramBuckets = buckets
 	}
+	if buckets := o.MetricsGeneratorProcessorServiceGraphsDatabaseHistogramBuckets(userID); buckets != nil {
+		copyCfg.ServiceGraphs.DatabaseHistogramBuckets = buckets
+	}
+	if buckets := o.MetricsGeneratorProcessorServiceGraphsMessagingSystemHistogramBuckets(userID); buckets != nil {
+		copyCfg.ServiceGraphs.MessagingSystemHistogramBuckets = buckets
+	}
 	if dimensions := o.MetricsGeneratorProcessorServiceGraphsDimensions(userID); dimensions != nil {
 		copyCfg.ServiceGraphs.Dimensions = dimensions
 	}
 	if peerAttrs := o.MetricsGeneratorProcessorServiceGraphsPeerAttributes(userID); peerAttrs != nil {
 		copyCfg.ServiceGraphs.PeerAttributes = peerAttrs
 	}
+	if dbNameAttrs := o.MetricsGeneratorProcessorServiceGraphsDatabaseNameAttributes(userID); dbNameAttrs != nil {
+		copyCfg.ServiceGraphs.DatabaseNameAttributes = dbNameAttrs
+	}
+	if template := o.MetricsGeneratorProcessorServiceGraphsVirtualNodeNameTemplate(userID); template != "" {
+		copyCfg.ServiceGraphs.VirtualNodeNameTemplate = template
+	}
 	if buckets := o.MetricsGeneratorProcessorSpanMetricsHistogramBuckets(userID); buckets != nil {
 		copyCfg.SpanMetrics.HistogramBuckets = buckets
 	}
@@ -88,10 +109,30 @@ func (cfg *ProcessorConfig) copyWithOverrides(o metricsGeneratorOverrides, userI
 			return ProcessorConfig{}, fmt.Errorf("fail to apply overrides: %w", err)
 		}
 	}
+	if dimensions := o.MetricsGeneratorProcessorSpanMetricsSubprocessorIntrinsicDimensions(userID); dimensions != nil {
+		err := copyCfg.SpanMetrics.ApplySubprocessorIntrinsicDimensions(dimensions)
+		if err != nil {
+			return ProcessorConfig{}, fmt.Errorf("fail to apply overrides: %w", err)
+		}
+	}
 	if filterPolicies := o.MetricsGeneratorProcessorSpanMetricsFilterPolicies(userID); filterPolicies != nil {
 		copyCfg.SpanMetrics.FilterPolicies = filterPolicies
 	}
 
+	if eventNames := o.MetricsGeneratorProcessorSpanEventsEventNames(userID); eventNames != nil {
+		copyCfg.SpanEvents.EventNames = eventNames
+	}
+	if dimensions := o.MetricsGeneratorProcessorSpanEventsDimensions(userID); dimensions != nil {
+		copyCfg.SpanEvents.Dimensions = dimensions
+	}
+	if mappings := o.MetricsGeneratorProcessorSpanEventsDimensionMappings(userID); mappings != nil {
+		copyCfg.SpanEvents.DimensionMappings = mappings
+	}
+
+	if dimensions := o.MetricsGeneratorProcessorLinkMetricsDimensions(userID); dimensions != nil {
+		copyCfg.LinkMetrics.Dimensions = dimensions
+	}
+
 	if max := o.MetricsGeneratorProcessorLocalBlocksMaxLiveTraces(userID); max > 0 {
 		copyCfg.LocalBlocks.MaxLiveTraces = max
 	}
@@ -122,6 +163,10 @@ func (cfg *ProcessorConfig) copyWithOverrides(o metricsGeneratorOverrides, userI
 
 	copyCfg.SpanMetrics.TargetInfoExcludedDimensions = o.MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions(userID)
 
+	if name := o.MetricsGeneratorProcessorSpanMetricsTargetInfoMetricName(userID); name != "" {
+		copyCfg.SpanMetrics.TargetInfoMetricName = name
+	}
+
 	copyCfg.ServiceGraphs.EnableClientServerPrefix = o.MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix(userID)
 
 	copyCfg.ServiceGraphs.EnableMessagingSystemLatencyHistogram = o.MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram(userID)
@@ -27,11 +27,12 @@ func TestProcessorConfig_copyWithOverrides(t *testing.T) {
 
 	t.Run("overrides buckets and dimension", func(t *testing.T) {
 		o := &mockOverrides{
-			serviceGraphsHistogramBuckets:  []float64{1, 2},
-			serviceGraphsDimensions:        []string{"namespace"},
-			spanMetricsHistogramBuckets:    []float64{1, 2, 3},
-			spanMetricsDimensions:          []string{"cluster", "namespace"},
-			spanMetricsIntrinsicDimensions: map[string]bool{"status_code": true},
+			serviceGraphsHistogramBuckets:         []float64{1, 2},
+			serviceGraphsDatabaseHistogramBuckets: []float64{0.001, 0.01},
+			serviceGraphsDimensions:               []string{"namespace"},
+			spanMetricsHistogramBuckets:           []float64{1, 2, 3},
+			spanMetricsDimensions:                 []string{"cluster", "namespace"},
+			spanMetricsIntrinsicDimensions:        map[string]bool{"status_code": true},
 		}
 
 		copied, err := original.copyWithOverrides(o, "tenant")
@@ -41,6 +42,7 @@ func TestProcessorConfig_copyWithOverrides(t *testing.T) {
 
 		// assert nothing changed
 		assert.Equal(t, []float64{1}, original.ServiceGraphs.HistogramBuckets)
+		assert.Nil(t, original.ServiceGraphs.DatabaseHistogramBuckets)
 		assert.Equal(t, []string{}, original.ServiceGraphs.Dimensions)
 		assert.Equal(t, []float64{1, 2}, original.SpanMetrics.HistogramBuckets)
 		assert.Equal(t, []string{"namespace"}, original.SpanMetrics.Dimensions)
@@ -48,6 +50,8 @@ func TestProcessorConfig_copyWithOverrides(t *testing.T) {
 
 		// assert overrides were applied
 		assert.Equal(t, []float64{1, 2}, copied.ServiceGraphs.HistogramBuckets)
+		assert.Equal(t, []float64{0.001, 0.01}, copied.ServiceGraphs.DatabaseHistogramBuckets)
+		assert.Nil(t, copied.ServiceGraphs.MessagingSystemHistogramBuckets)
 		assert.Equal(t, []string{"namespace"}, copied.ServiceGraphs.Dimensions)
 		assert.Equal(t, []float64{1, 2, 3}, copied.SpanMetrics.HistogramBuckets)
 		assert.Equal(t, []string{"cluster", "namespace"}, copied.SpanMetrics.Dimensions)
@@ -72,6 +76,34 @@ func TestProcessorConfig_copyWithOverrides(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("subprocessor intrinsic dimensions overrides", func(t *testing.T) {
+		o := &mockOverrides{
+			spanMetricsSubprocessorIntrinsicDimensions: map[string]map[string]bool{
+				spanmetrics.Latency.String(): {"trace_root_name": true},
+			},
+		}
+
+		copied, err := original.copyWithOverrides(o, "tenant")
+		require.NoError(t, err)
+
+		assert.Nil(t, original.SpanMetrics.SubprocessorIntrinsicDimensions)
+
+		dims, err := copied.SpanMetrics.IntrinsicDimensionsFor(spanmetrics.Latency)
+		require.NoError(t, err)
+		assert.Equal(t, spanmetrics.IntrinsicDimensions{Service: true, TraceRootName: true}, dims)
+	})
+
+	t.Run("invalid subprocessor intrinsic dimensions overrides", func(t *testing.T) {
+		o := &mockOverrides{
+			spanMetricsSubprocessorIntrinsicDimensions: map[string]map[string]bool{
+				"invalid": {"service": true},
+			},
+		}
+
+		_, err := original.copyWithOverrides(o, "tenant")
+		require.Error(t, err)
+	})
+
 	t.Run("nil policy overrides", func(t *testing.T) {
 		o := &mockOverrides{
 			spanMetricsFilterPolicies: nil,
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path"
 	"sync"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
@@ -16,6 +17,7 @@ import (
 	"github.com/grafana/dskit/user"
 	"github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
 	"go.uber.org/atomic"
 
 	"github.com/grafana/tempo/modules/generator/storage"
@@ -37,6 +39,8 @@ const (
 var (
 	ErrUnconfigured = errors.New("no metrics_generator.storage.path configured, metrics generator will be disabled")
 	ErrReadOnly     = errors.New("metrics-generator is shutting down")
+
+	errTenantDeleted = errors.New("tenant has been deleted")
 )
 
 type Generator struct {
@@ -49,6 +53,11 @@ type Generator struct {
 
 	instancesMtx sync.RWMutex
 	instances    map[string]*instance
+	// deletedTenants caches the tenants found marked for deletion in the backend. Tenants are added the first
+	// time the mark is found and removed by the periodic check once the mark is gone.
+	deletedTenants map[string]struct{}
+	// collectors are the registries of the instances as registered with reg
+	collectors map[string]prometheus.Collector
 
 	subservices        *services.Manager
 	subservicesWatcher *services.FailureWatcher
@@ -78,7 +87,9 @@ func New(cfg *Config, overrides metricsGeneratorOverrides, reg prometheus.Regist
 		cfg:       cfg,
 		overrides: overrides,
 
-		instances: map[string]*instance{},
+		instances:      map[string]*instance{},
+		deletedTenants: map[string]struct{}{},
+		collectors:     map[string]prometheus.Collector{},
 
 		store: store,
 
@@ -147,8 +158,18 @@ func (g *Generator) starting(ctx context.Context) (err error) {
 }
 
 func (g *Generator) running(ctx context.Context) error {
+	var deletionCheck <-chan time.Time
+	if g.store != nil && g.cfg.TenantDeletionCheckPeriod > 0 {
+		deletionTicker := time.NewTicker(g.cfg.TenantDeletionCheckPeriod)
+		defer deletionTicker.Stop()
+		deletionCheck = deletionTicker.C
+	}
+
 	for {
 		select {
+		case <-deletionCheck:
+			g.purgeDeletedTenants(ctx)
+
 		case <-ctx.Done():
 			return nil
 
@@ -219,6 +240,11 @@ func (g *Generator) getOrCreateInstance(instanceID string) (*instance, error) {
 		return inst, nil
 	}
 
+	// the backend is checked before creating an instance so deleted tenants are refused after a restart too
+	if g.isTenantDeleted(context.Background(), instanceID) {
+		return nil, errTenantDeleted
+	}
+
 	g.instancesMtx.Lock()
 	defer g.instancesMtx.Unlock()
 
@@ -280,15 +306,147 @@ func (g *Generator) createInstance(id string) (*instance, error) {
 		return nil, err
 	}
 
-	err = g.reg.Register(reg)
+	collector := newRegisteredCollector(reg)
+	err = g.reg.Register(collector)
 	if err != nil {
 		inst.shutdown()
 		return nil, err
 	}
+	g.collectors[id] = collector
 
 	return inst, nil
 }
 
+// isTenantDeleted returns true if the tenant is marked for deletion in the backend. If the mark can't be read
+// the tenant is considered not deleted, its data is purged by the next periodic check.
+func (g *Generator) isTenantDeleted(ctx context.Context, instanceID string) bool {
+	if g.store == nil {
+		return false
+	}
+
+	g.instancesMtx.RLock()
+	_, deleted := g.deletedTenants[instanceID]
+	g.instancesMtx.RUnlock()
+	if deleted {
+		return true
+	}
+
+	mark, err := g.store.TenantDeletionMark(ctx, instanceID)
+	if err != nil {
+		level.Error(g.logger).Log("msg", "failed to check tenant deletion mark", "tenant", instanceID, "err", err)
+		return false
+	}
+	if mark == nil {
+		return false
+	}
+
+	g.instancesMtx.Lock()
+	g.deletedTenants[instanceID] = struct{}{}
+	g.instancesMtx.Unlock()
+	return true
+}
+
+// purgeDeletedTenants shuts down the instances of all tenants that have been marked for deletion in the
+// backend, removes their local data and refuses further pushes. Pushes of tenants whose deletion was canceled
+// are accepted again.
+func (g *Generator) purgeDeletedTenants(ctx context.Context) {
+	g.instancesMtx.RLock()
+	ids := make([]string, 0, len(g.instances))
+	for id := range g.instances {
+		ids = append(ids, id)
+	}
+	deleted := make([]string, 0, len(g.deletedTenants))
+	for id := range g.deletedTenants {
+		deleted = append(deleted, id)
+	}
+	g.instancesMtx.RUnlock()
+
+	for _, id := range deleted {
+		mark, err := g.store.TenantDeletionMark(ctx, id)
+		if err != nil {
+			level.Error(g.logger).Log("msg", "failed to check tenant deletion mark", "tenant", id, "err", err)
+			continue
+		}
+		if mark == nil {
+			level.Info(g.logger).Log("msg", "tenant deletion canceled", "tenant", id)
+			g.instancesMtx.Lock()
+			delete(g.deletedTenants, id)
+			g.instancesMtx.Unlock()
+		}
+	}
+
+	for _, id := range ids {
+		mark, err := g.store.TenantDeletionMark(ctx, id)
+		if err != nil {
+			level.Error(g.logger).Log("msg", "failed to check tenant deletion mark", "tenant", id, "err", err)
+			continue
+		}
+		if mark == nil {
+			continue
+		}
+
+		g.purgeInstance(id)
+	}
+}
+
+func (g *Generator) purgeInstance(id string) {
+	g.instancesMtx.Lock()
+	inst, ok := g.instances[id]
+	collector := g.collectors[id]
+	delete(g.instances, id)
+	delete(g.collectors, id)
+	g.deletedTenants[id] = struct{}{}
+	g.instancesMtx.Unlock()
+
+	if !ok {
+		return
+	}
+
+	level.Info(g.logger).Log("msg", "purging deleted tenant", "tenant", id)
+
+	// shutting down the instance also removes the metrics wal
+	inst.shutdown()
+
+	if collector != nil {
+		g.reg.Unregister(collector)
+	}
+
+	if g.cfg.TracesWAL.Filepath != "" {
+		err := os.RemoveAll(path.Join(g.cfg.TracesWAL.Filepath, id))
+		if err != nil {
+			level.Error(g.logger).Log("msg", "failed to remove traces wal of deleted tenant", "tenant", id, "err", err)
+		}
+	}
+}
+
+// registeredCollector is a collector that describes the metrics its collector had when it was created. Metrics
+// are added to the registries of instances after they are registered, a collector can only be unregistered with
+// the descriptions it was registered with.
+type registeredCollector struct {
+	prometheus.Collector
+	descs []*prometheus.Desc
+}
+
+func newRegisteredCollector(c prometheus.Collector) *registeredCollector {
+	ch := make(chan *prometheus.Desc)
+	go func() {
+		c.Describe(ch)
+		close(ch)
+	}()
+
+	rc := &registeredCollector{Collector: c}
+	for desc := range ch {
+		rc.descs = append(rc.descs, desc)
+	}
+	return rc
+}
+
+func (c *registeredCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
 func (g *Generator) CheckReady(_ context.Context) error {
 	if !g.ringLifecycler.IsRegistered() {
 		return fmt.Errorf("metrics-generator check ready failed: not registered in the ring")
@@ -344,6 +502,26 @@ func (g *Generator) GetMetrics(ctx context.Context, req *tempopb.SpanMetricsRequ
 	return instance.GetMetrics(ctx, req)
 }
 
+// DeleteSeries removes the series of the tenant matching any of the matcher sets from the registry of this
+// generator and returns the number of removed series.
+func (g *Generator) DeleteSeries(ctx context.Context, matcherSets [][]*labels.Matcher) (int, error) {
+	instanceID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	instance, ok := g.getInstanceByID(instanceID)
+	if !ok || instance == nil {
+		return 0, nil
+	}
+
+	removed := 0
+	for _, matchers := range matcherSets {
+		removed += instance.deleteSeries(matchers)
+	}
+	return removed, nil
+}
+
 func (g *Generator) QueryRange(ctx context.Context, req *tempopb.QueryRangeRequest) (*tempopb.QueryRangeResponse, error) {
 	instanceID, err := user.ExtractOrgID(ctx)
 	if err != nil {
@@ -358,3 +536,78 @@ func (g *Generator) QueryRange(ctx context.Context, req *tempopb.QueryRangeReque
 
 	return instance.QueryRange(ctx, req)
 }
+
+func (g *Generator) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDRequest) (*tempopb.TraceByIDResponse, error) {
+	instanceID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// return empty if we don't have an instance
+	instance, ok := g.getInstanceByID(instanceID)
+	if !ok || instance == nil {
+		return &tempopb.TraceByIDResponse{}, nil
+	}
+
+	return instance.FindTraceByID(ctx, req)
+}
+
+func (g *Generator) SearchTags(ctx context.Context, req *tempopb.SearchTagsRequest) (*tempopb.SearchTagsResponse, error) {
+	instanceID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// return empty if we don't have an instance
+	instance, ok := g.getInstanceByID(instanceID)
+	if !ok || instance == nil {
+		return &tempopb.SearchTagsResponse{}, nil
+	}
+
+	return instance.SearchTags(ctx, req)
+}
+
+func (g *Generator) SearchTagsV2(ctx context.Context, req *tempopb.SearchTagsRequest) (*tempopb.SearchTagsV2Response, error) {
+	instanceID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// return empty if we don't have an instance
+	instance, ok := g.getInstanceByID(instanceID)
+	if !ok || instance == nil {
+		return &tempopb.SearchTagsV2Response{}, nil
+	}
+
+	return instance.SearchTagsV2(ctx, req)
+}
+
+func (g *Generator) SearchTagValues(ctx context.Context, req *tempopb.SearchTagValuesRequest) (*tempopb.SearchTagValuesResponse, error) {
+	instanceID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// return empty if we don't have an instance
+	instance, ok := g.getInstanceByID(instanceID)
+	if !ok || instance == nil {
+		return &tempopb.SearchTagValuesResponse{}, nil
+	}
+
+	return instance.SearchTagValues(ctx, req)
+}
+
+func (g *Generator) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTagValuesRequest) (*tempopb.SearchTagValuesV2Response, error) {
+	instanceID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// return empty if we don't have an instance
+	instance, ok := g.getInstanceByID(instanceID)
+	if !ok || instance == nil {
+		return &tempopb.SearchTagValuesV2Response{}, nil
+	}
+
+	return instance.SearchTagValuesV2(ctx, req)
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/grafana/dskit/services"
 	"github.com/grafana/tempo/modules/generator/processor/spanmetrics"
 	"github.com/grafana/tempo/modules/overrides"
+	objStorage "github.com/grafana/tempo/modules/storage"
+	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
@@ -98,6 +100,69 @@ overrides:
 	verifySubprocessors(t, instance2, allSubprocessors)
 }
 
+type mockDeletionStore struct {
+	objStorage.Store
+
+	marks map[string]*backend.TenantDeletionMark
+}
+
+func (m *mockDeletionStore) TenantDeletionMark(_ context.Context, tenantID string) (*backend.TenantDeletionMark, error) {
+	return m.marks[tenantID], nil
+}
+
+func TestGeneratorPurgeDeletedTenants(t *testing.T) {
+	overridesConfig := overrides.Config{
+		Defaults: overrides.Overrides{
+			MetricsGenerator: overrides.MetricsGeneratorOverrides{
+				CollectionInterval: 15 * time.Second,
+			},
+		},
+	}
+	o, err := overrides.NewOverrides(overridesConfig, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	store := &mockDeletionStore{marks: map[string]*backend.TenantDeletionMark{}}
+
+	generatorConfig := &Config{}
+	generatorConfig.Storage.Path = t.TempDir()
+	generatorConfig.Ring.KVStore.Store = "inmemory"
+	generatorConfig.Processor.SpanMetrics.RegisterFlagsAndApplyDefaults("", nil)
+	reg := prometheus.NewRegistry()
+	g, err := New(generatorConfig, o, reg, store, newTestLogger(t))
+	require.NoError(t, err)
+
+	_, err = g.getOrCreateInstance(user1)
+	require.NoError(t, err)
+	_, err = g.getOrCreateInstance(user2)
+	require.NoError(t, err)
+	require.DirExists(t, filepath.Join(generatorConfig.Storage.Path, user1))
+
+	store.marks[user1] = backend.NewTenantDeletionMark()
+	g.purgeDeletedTenants(context.Background())
+
+	_, ok := g.getInstanceByID(user1)
+	require.False(t, ok)
+	_, ok = g.getInstanceByID(user2)
+	require.True(t, ok)
+	require.NoDirExists(t, filepath.Join(generatorConfig.Storage.Path, user1))
+
+	_, err = g.getOrCreateInstance(user1)
+	require.ErrorIs(t, err, errTenantDeleted)
+
+	// tenants are checked in the backend before an instance is created
+	store.marks["user3"] = backend.NewTenantDeletionMark()
+	_, err = g.getOrCreateInstance("user3")
+	require.ErrorIs(t, err, errTenantDeleted)
+
+	// canceled deletions are picked up by the periodic check
+	delete(store.marks, user1)
+	g.purgeDeletedTenants(context.Background())
+	_, err = g.getOrCreateInstance(user1)
+	require.NoError(t, err)
+
+	require.NoError(t, g.stopping(nil))
+}
+
 func verifySubprocessors(t *testing.T, instance *instance, expected map[spanmetrics.Subprocessor]bool) {
 	instance.processorsMtx.RLock()
 	defer instance.processorsMtx.RUnlock()
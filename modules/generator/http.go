@@ -2,11 +2,16 @@ package generator
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
 
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/tempopb"
@@ -73,3 +78,54 @@ func (g *Generator) QueryRangeHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
 }
+
+// deleteSeriesResponse is returned by DeleteSeriesHandler.
+type deleteSeriesResponse struct {
+	RemovedSeries int `json:"removedSeries"`
+}
+
+// DeleteSeriesHandler removes the series of the tenant matching any of the series selectors passed in the match[]
+// parameter, e.g. match[]={__name__=~"traces_spanmetrics_.*", http_url!=""}. The series are removed from this
+// generator only.
+func (g *Generator) DeleteSeriesHandler(w http.ResponseWriter, r *http.Request) {
+	span, ctx := opentracing.StartSpanFromContext(r.Context(), "Generator.DeleteSeriesHandler")
+	defer span.Finish()
+
+	span.SetTag("requestURI", r.RequestURI)
+
+	matcherSets, err := parseSeriesSelectors(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	removed, err := g.DeleteSeries(ctx, matcherSets)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+	_ = json.NewEncoder(w).Encode(deleteSeriesResponse{RemovedSeries: removed})
+}
+
+func parseSeriesSelectors(r *http.Request) ([][]*labels.Matcher, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	selectors := r.Form["match[]"]
+	if len(selectors) == 0 {
+		return nil, errors.New("no match[] parameter provided")
+	}
+
+	matcherSets := make([][]*labels.Matcher, 0, len(selectors))
+	for _, s := range selectors {
+		matchers, err := parser.ParseMetricSelector(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid series selector %q: %w", s, err)
+		}
+		matcherSets = append(matcherSets, matchers)
+	}
+	return matcherSets, nil
+}
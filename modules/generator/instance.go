@@ -13,16 +13,20 @@ import (
 	"github.com/grafana/tempo/tempodb"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
 	"golang.org/x/exp/maps"
 
 	"github.com/grafana/tempo/modules/generator/processor"
+	"github.com/grafana/tempo/modules/generator/processor/linkmetrics"
 	"github.com/grafana/tempo/modules/generator/processor/localblocks"
 	"github.com/grafana/tempo/modules/generator/processor/servicegraphs"
+	"github.com/grafana/tempo/modules/generator/processor/spanevents"
 	"github.com/grafana/tempo/modules/generator/processor/spanmetrics"
 	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/modules/generator/storage"
+	"github.com/grafana/tempo/pkg/spanfilter"
+	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
 	"github.com/grafana/tempo/pkg/tempopb"
-	commonv1proto "github.com/grafana/tempo/pkg/tempopb/common/v1"
 	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/tempodb/wal"
@@ -31,7 +35,7 @@ import (
 )
 
 var (
-	SupportedProcessors = []string{servicegraphs.Name, spanmetrics.Name, localblocks.Name}
+	SupportedProcessors = []string{servicegraphs.Name, spanmetrics.Name, spanevents.Name, linkmetrics.Name, localblocks.Name}
 
 	metricActiveProcessors = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "tempo",
@@ -63,6 +67,7 @@ var (
 const (
 	reasonOutsideTimeRangeSlack = "outside_metrics_ingestion_slack"
 	reasonSpanMetricsFiltered   = "span_metrics_filtered"
+	reasonFiltered              = "filtered"
 )
 
 type instance struct {
@@ -72,6 +77,11 @@ type instance struct {
 	overrides              metricsGeneratorOverrides
 	ingestionSlackOverride atomic.Int64
 
+	// filter is applied to all spans before they are passed to the processors, it's nil if no
+	// filter policies are configured. filterPolicies is only accessed by updateProcessors.
+	filter         atomic.Pointer[spanfilter.SpanFilter]
+	filterPolicies []filterconfig.FilterPolicy
+
 	registry *registry.ManagedRegistry
 	wal      storage.Storage
 
@@ -83,6 +93,9 @@ type instance struct {
 	// processors is a map of processor name -> processor, only one instance of a processor can be
 	// active at any time
 	processors map[string]processor.Processor
+	// processorRegistries is a map of processor name -> registry used by that processor, it's
+	// protected by processorsMtx as well
+	processorRegistries map[string]*processorRegistry
 
 	shutdownCh chan struct{}
 
@@ -103,7 +116,8 @@ func newInstance(cfg *Config, instanceID string, overrides metricsGeneratorOverr
 		traceWAL: traceWAL,
 		writer:   writer,
 
-		processors: make(map[string]processor.Processor),
+		processors:          make(map[string]processor.Processor),
+		processorRegistries: make(map[string]*processorRegistry),
 
 		shutdownCh: make(chan struct{}, 1),
 
@@ -204,6 +218,8 @@ func (i *instance) updateProcessors() error {
 
 	i.ingestionSlackOverride.Store(ingestionSlackInt)
 
+	i.updateFilter()
+
 	desiredProcessors, desiredCfg = i.updateSubprocessors(desiredProcessors, desiredCfg)
 
 	i.processorsMtx.RLock()
@@ -266,6 +282,14 @@ func (i *instance) diffProcessors(desiredProcessors map[string]struct{}, desired
 			if !reflect.DeepEqual(p.Cfg, desiredCfg.ServiceGraphs) {
 				toReplace = append(toReplace, processorName)
 			}
+		case *spanevents.Processor:
+			if !reflect.DeepEqual(p.Cfg, desiredCfg.SpanEvents) {
+				toReplace = append(toReplace, processorName)
+			}
+		case *linkmetrics.Processor:
+			if !reflect.DeepEqual(p.Cfg, desiredCfg.LinkMetrics) {
+				toReplace = append(toReplace, processorName)
+			}
 		case *localblocks.Processor:
 			if !reflect.DeepEqual(p.Cfg, desiredCfg.LocalBlocks) {
 				toReplace = append(toReplace, processorName)
@@ -287,17 +311,28 @@ func (i *instance) diffProcessors(desiredProcessors map[string]struct{}, desired
 func (i *instance) addProcessor(processorName string, cfg ProcessorConfig) error {
 	level.Debug(i.logger).Log("msg", "adding processor", "processorName", processorName)
 
+	// check the processor wasn't added in the meantime
+	if _, ok := i.processors[processorName]; ok {
+		return nil
+	}
+
 	var newProcessor processor.Processor
 	var err error
+	reg := newProcessorRegistry(i.registry, i.processorStaleDuration(processorName))
 	switch processorName {
 	case spanmetrics.Name:
 		filteredSpansCounter := metricSpansDiscarded.WithLabelValues(i.instanceID, reasonSpanMetricsFiltered)
-		newProcessor, err = spanmetrics.New(cfg.SpanMetrics, i.registry, filteredSpansCounter)
+		newProcessor, err = spanmetrics.New(cfg.SpanMetrics, reg, filteredSpansCounter)
 		if err != nil {
+			reg.unregisterAll()
 			return err
 		}
 	case servicegraphs.Name:
-		newProcessor = servicegraphs.New(cfg.ServiceGraphs, i.instanceID, i.registry, i.logger)
+		newProcessor = servicegraphs.New(cfg.ServiceGraphs, i.instanceID, reg, i.logger)
+	case spanevents.Name:
+		newProcessor = spanevents.New(cfg.SpanEvents, reg)
+	case linkmetrics.Name:
+		newProcessor = linkmetrics.New(cfg.LinkMetrics, reg)
 	case localblocks.Name:
 		p, err := localblocks.New(cfg.LocalBlocks, i.instanceID, i.traceWAL, i.writer, i.overrides)
 		if err != nil {
@@ -312,18 +347,36 @@ func (i *instance) addProcessor(processorName string, cfg ProcessorConfig) error
 		return fmt.Errorf("unknown processor %s", processorName)
 	}
 
-	// check the processor wasn't added in the meantime
-	if _, ok := i.processors[processorName]; ok {
-		return nil
-	}
-
 	i.processors[processorName] = newProcessor
+	i.processorRegistries[processorName] = reg
 
 	return nil
 }
 
-// removeProcessor removes the processor from the processors map and shuts it down. Must be called
-// under a write lock.
+// processorStaleDuration returns the stale duration override of the series of the processor, or nil if the
+// processor uses the stale duration of the registry.
+func (i *instance) processorStaleDuration(processorName string) func() time.Duration {
+	switch processorName {
+	case spanmetrics.Name:
+		return func() time.Duration {
+			return i.overrides.MetricsGeneratorProcessorSpanMetricsStaleDuration(i.instanceID)
+		}
+	case servicegraphs.Name:
+		return func() time.Duration {
+			return i.overrides.MetricsGeneratorProcessorServiceGraphsStaleDuration(i.instanceID)
+		}
+	}
+	return nil
+}
+
+// deleteSeries removes all series matching the matchers from the registry and returns the number of removed
+// series.
+func (i *instance) deleteSeries(matchers []*labels.Matcher) int {
+	return i.registry.RemoveSeries(matchers)
+}
+
+// removeProcessor removes the processor from the processors map, shuts it down and removes all the
+// metrics it created from the registry. Must be called under a write lock.
 func (i *instance) removeProcessor(processorName string) {
 	level.Debug(i.logger).Log("msg", "removing processor", "processorName", processorName)
 
@@ -335,6 +388,11 @@ func (i *instance) removeProcessor(processorName string) {
 	delete(i.processors, processorName)
 
 	deletedProcessor.Shutdown(context.Background())
+
+	if reg, ok := i.processorRegistries[processorName]; ok {
+		delete(i.processorRegistries, processorName)
+		reg.unregisterAll()
+	}
 }
 
 // updateProcessorMetrics updates the active processor metrics. Must be called under a read lock.
@@ -348,6 +406,30 @@ func (i *instance) updateProcessorMetrics() {
 	}
 }
 
+// updateFilter rebuilds the span filter shared by all processors if the filter policies changed. Invalid
+// policies are logged and the previous filter is kept, so they don't hold back changes to the processors.
+func (i *instance) updateFilter() {
+	policies := i.overrides.MetricsGeneratorFilterPolicies(i.instanceID)
+	if reflect.DeepEqual(policies, i.filterPolicies) {
+		return
+	}
+
+	if len(policies) == 0 {
+		i.filter.Store(nil)
+		i.filterPolicies = nil
+		return
+	}
+
+	filter, err := spanfilter.NewSpanFilter(policies)
+	if err != nil {
+		level.Error(i.logger).Log("msg", "invalid filter policies, keeping the previous filter", "tenant", i.instanceID, "err", err)
+		return
+	}
+
+	i.filter.Store(filter)
+	i.filterPolicies = policies
+}
+
 func (i *instance) pushSpans(ctx context.Context, req *tempopb.PushSpansRequest) {
 	i.preprocessSpans(req)
 	i.processorsMtx.RLock()
@@ -362,7 +444,9 @@ func (i *instance) preprocessSpans(req *tempopb.PushSpansRequest) {
 	size := 0
 	spanCount := 0
 	expiredSpanCount := 0
+	filteredSpanCount := 0
 	ingestionSlackNano := i.ingestionSlackOverride.Load()
+	filter := i.filter.Load()
 
 	for _, b := range req.Batches {
 		size += b.Size()
@@ -376,17 +460,20 @@ func (i *instance) preprocessSpans(req *tempopb.PushSpansRequest) {
 
 			index := 0
 			for _, span := range ss.Spans {
-				if span.EndTimeUnixNano >= maxTimePast && span.EndTimeUnixNano <= maxTimeFuture {
+				switch {
+				case span.EndTimeUnixNano < maxTimePast || span.EndTimeUnixNano > maxTimeFuture:
+					expiredSpanCount++
+				case filter != nil && !filter.ApplyFilterPolicy(b.Resource, span):
+					filteredSpanCount++
+				default:
 					newSpansArr[index] = span
 					index++
-				} else {
-					expiredSpanCount++
 				}
 			}
 			ss.Spans = newSpansArr[0:index]
 		}
 	}
-	i.updatePushMetrics(size, spanCount, expiredSpanCount)
+	i.updatePushMetrics(size, spanCount, expiredSpanCount, filteredSpanCount)
 }
 
 func (i *instance) GetMetrics(ctx context.Context, req *tempopb.SpanMetricsRequest) (resp *tempopb.SpanMetricsResponse, err error) {
@@ -421,48 +508,76 @@ func (i *instance) QueryRange(ctx context.Context, req *tempopb.QueryRangeReques
 	return resp, fmt.Errorf("localblocks processor not found")
 }
 
-func (i *instance) queryRangeTraceQLToProto(set traceql.SeriesSet, req *tempopb.QueryRangeRequest) []*tempopb.TimeSeries {
-	resp := make([]*tempopb.TimeSeries, 0, len(set))
-
-	for promLabels, s := range set {
-		labels := make([]commonv1proto.KeyValue, 0, len(s.Labels))
-		for _, label := range s.Labels {
-			labels = append(labels,
-				commonv1proto.KeyValue{
-					Key:   label.Name,
-					Value: label.Value.AsAnyValue(),
-				},
-			)
+// FindTraceByID returns the spans of the trace in the localblocks processor. Tenants without the processor have
+// nothing to return.
+func (i *instance) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDRequest) (*tempopb.TraceByIDResponse, error) {
+	for _, processor := range i.processors {
+		switch p := processor.(type) {
+		case *localblocks.Processor:
+			tr, err := p.FindTraceByID(ctx, req.TraceID)
+			if err != nil {
+				return nil, err
+			}
+			return &tempopb.TraceByIDResponse{Trace: tr}, nil
+		default:
 		}
+	}
 
-		intervals := traceql.IntervalCount(req.Start, req.End, req.Step)
-		samples := make([]tempopb.Sample, 0, intervals)
-		for i, value := range s.Values {
+	return &tempopb.TraceByIDResponse{}, nil
+}
 
-			ts := traceql.TimestampOf(uint64(i), req.Start, req.Step)
+// SearchTags returns the tags in the localblocks processor. Tenants without the processor have nothing to return.
+func (i *instance) SearchTags(ctx context.Context, req *tempopb.SearchTagsRequest) (*tempopb.SearchTagsResponse, error) {
+	if p := i.localBlocksProcessor(); p != nil {
+		return p.SearchTags(ctx, req.Scope)
+	}
+	return &tempopb.SearchTagsResponse{}, nil
+}
 
-			samples = append(samples, tempopb.Sample{
-				TimestampMs: time.Unix(0, int64(ts)).UnixMilli(),
-				Value:       value,
-			})
-		}
+// SearchTagsV2 returns the tags by scope in the localblocks processor.
+func (i *instance) SearchTagsV2(ctx context.Context, req *tempopb.SearchTagsRequest) (*tempopb.SearchTagsV2Response, error) {
+	if p := i.localBlocksProcessor(); p != nil {
+		return p.SearchTagsV2(ctx, req.Scope)
+	}
+	return &tempopb.SearchTagsV2Response{}, nil
+}
 
-		ss := &tempopb.TimeSeries{
-			PromLabels: promLabels,
-			Labels:     labels,
-			Samples:    samples,
-		}
+// SearchTagValues returns the values of the tag in the localblocks processor.
+func (i *instance) SearchTagValues(ctx context.Context, req *tempopb.SearchTagValuesRequest) (*tempopb.SearchTagValuesResponse, error) {
+	if p := i.localBlocksProcessor(); p != nil {
+		return p.SearchTagValues(ctx, req.TagName)
+	}
+	return &tempopb.SearchTagValuesResponse{}, nil
+}
 
-		resp = append(resp, ss)
+// SearchTagValuesV2 returns the typed values of the tag in the localblocks processor.
+func (i *instance) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTagValuesRequest) (*tempopb.SearchTagValuesV2Response, error) {
+	if p := i.localBlocksProcessor(); p != nil {
+		return p.SearchTagValuesV2(ctx, req)
 	}
+	return &tempopb.SearchTagValuesV2Response{}, nil
+}
 
-	return resp
+func (i *instance) localBlocksProcessor() *localblocks.Processor {
+	for _, processor := range i.processors {
+		if p, ok := processor.(*localblocks.Processor); ok {
+			return p
+		}
+	}
+	return nil
 }
 
-func (i *instance) updatePushMetrics(bytesIngested int, spanCount int, expiredSpanCount int) {
+func (i *instance) queryRangeTraceQLToProto(set traceql.SeriesSet, req *tempopb.QueryRangeRequest) []*tempopb.TimeSeries {
+	return set.ToProto(req)
+}
+
+func (i *instance) updatePushMetrics(bytesIngested int, spanCount int, expiredSpanCount int, filteredSpanCount int) {
 	metricBytesIngested.WithLabelValues(i.instanceID).Add(float64(bytesIngested))
 	metricSpansIngested.WithLabelValues(i.instanceID).Add(float64(spanCount))
 	metricSpansDiscarded.WithLabelValues(i.instanceID, reasonOutsideTimeRangeSlack).Add(float64(expiredSpanCount))
+	if filteredSpanCount > 0 {
+		metricSpansDiscarded.WithLabelValues(i.instanceID, reasonFiltered).Add(float64(filteredSpanCount))
+	}
 }
 
 // shutdown stops the instance and flushes any remaining data. After shutdown
@@ -476,6 +591,7 @@ func (i *instance) shutdown() {
 	for processorName := range i.processors {
 		i.removeProcessor(processorName)
 	}
+	metricActiveProcessors.DeletePartialMatch(prometheus.Labels{"tenant": i.instanceID})
 
 	i.registry.Close()
 
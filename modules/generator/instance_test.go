@@ -20,6 +20,7 @@ import (
 	"github.com/grafana/tempo/modules/generator/processor/servicegraphs"
 	"github.com/grafana/tempo/modules/generator/processor/spanmetrics"
 	"github.com/grafana/tempo/modules/generator/storage"
+	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
 	"github.com/grafana/tempo/pkg/tempopb"
 	commonv1proto "github.com/grafana/tempo/pkg/tempopb/common/v1"
 	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
@@ -107,6 +108,9 @@ func Test_instance_updateProcessors(t *testing.T) {
 
 		assert.Len(t, instance.processors, 1)
 		assert.Equal(t, instance.processors[servicegraphs.Name].Name(), servicegraphs.Name)
+
+		assert.Len(t, instance.processorRegistries, 1)
+		assert.NotEmpty(t, instance.processorRegistries[servicegraphs.Name].metricNames)
 	})
 
 	t.Run("add unknown processor", func(t *testing.T) {
@@ -159,6 +163,7 @@ func Test_instance_updateProcessors(t *testing.T) {
 		assert.NoError(t, err)
 
 		assert.Len(t, instance.processors, 0)
+		assert.Len(t, instance.processorRegistries, 0)
 	})
 
 	t.Run("add span-latency subprocessor", func(t *testing.T) {
@@ -287,6 +292,75 @@ func Test_instance_updateProcessors(t *testing.T) {
 	})
 }
 
+func Test_instance_filterPolicies(t *testing.T) {
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+	overrides := mockOverrides{}
+
+	instance, err := newInstance(&cfg, "test", &overrides, &noopStorage{}, prometheus.DefaultRegisterer, log.NewNopLogger(), nil, nil)
+	assert.NoError(t, err)
+
+	// stop the update goroutine
+	close(instance.shutdownCh)
+
+	makeReq := func() *tempopb.PushSpansRequest {
+		now := uint64(time.Now().UnixNano())
+		return &tempopb.PushSpansRequest{
+			Batches: []*v1.ResourceSpans{{
+				ScopeSpans: []*v1.ScopeSpans{{
+					Spans: []*v1.Span{
+						{Name: "keep", StartTimeUnixNano: now, EndTimeUnixNano: now},
+						{Name: "drop", StartTimeUnixNano: now, EndTimeUnixNano: now},
+						{Name: "keep", StartTimeUnixNano: now, EndTimeUnixNano: now},
+					},
+				}},
+			}},
+		}
+	}
+	spanNames := func(req *tempopb.PushSpansRequest) []string {
+		var names []string
+		for _, s := range req.Batches[0].ScopeSpans[0].Spans {
+			names = append(names, s.Name)
+		}
+		return names
+	}
+
+	// no policies, all spans are passed on
+	req := makeReq()
+	instance.preprocessSpans(req)
+	assert.Equal(t, []string{"keep", "drop", "keep"}, spanNames(req))
+
+	overrides.filterPolicies = []filterconfig.FilterPolicy{{
+		Exclude: &filterconfig.PolicyMatch{
+			MatchType:  filterconfig.Strict,
+			Attributes: []filterconfig.MatchPolicyAttribute{{Key: "name", Value: "drop"}},
+		},
+	}}
+	assert.NoError(t, instance.updateProcessors())
+
+	req = makeReq()
+	instance.preprocessSpans(req)
+	assert.Equal(t, []string{"keep", "keep"}, spanNames(req))
+
+	// invalid policies are rejected and the previous filter is kept, processors are still updated
+	overrides.filterPolicies = []filterconfig.FilterPolicy{{}}
+	overrides.processors = map[string]struct{}{spanmetrics.Name: {}}
+	assert.NoError(t, instance.updateProcessors())
+	assert.Contains(t, instance.processors, spanmetrics.Name)
+
+	req = makeReq()
+	instance.preprocessSpans(req)
+	assert.Equal(t, []string{"keep", "keep"}, spanNames(req))
+
+	// removing the policies disables the filter
+	overrides.filterPolicies = nil
+	assert.NoError(t, instance.updateProcessors())
+
+	req = makeReq()
+	instance.preprocessSpans(req)
+	assert.Equal(t, []string{"keep", "drop", "keep"}, spanNames(req))
+}
+
 func Test_instanceQueryRangeTraceQLToProto(t *testing.T) {
 	cfg := Config{}
 	cfg.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
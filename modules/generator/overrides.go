@@ -16,14 +16,22 @@ type metricsGeneratorOverrides interface {
 	storage.Overrides
 
 	MetricsGeneratorIngestionSlack(userID string) time.Duration
+	MetricsGeneratorFilterPolicies(userID string) []filterconfig.FilterPolicy
 	MetricsGeneratorProcessors(userID string) map[string]struct{}
 	MetricsGeneratorProcessorServiceGraphsHistogramBuckets(userID string) []float64
+	MetricsGeneratorProcessorServiceGraphsDatabaseHistogramBuckets(userID string) []float64
+	MetricsGeneratorProcessorServiceGraphsMessagingSystemHistogramBuckets(userID string) []float64
 	MetricsGeneratorProcessorServiceGraphsDimensions(userID string) []string
 	MetricsGeneratorProcessorServiceGraphsPeerAttributes(userID string) []string
+	MetricsGeneratorProcessorServiceGraphsDatabaseNameAttributes(userID string) []string
+	MetricsGeneratorProcessorServiceGraphsVirtualNodeNameTemplate(userID string) string
+	MetricsGeneratorProcessorServiceGraphsStaleDuration(userID string) time.Duration
 	MetricsGeneratorProcessorSpanMetricsHistogramBuckets(userID string) []float64
 	MetricsGeneratorProcessorSpanMetricsDimensions(userID string) []string
 	MetricsGeneratorProcessorSpanMetricsIntrinsicDimensions(userID string) map[string]bool
+	MetricsGeneratorProcessorSpanMetricsSubprocessorIntrinsicDimensions(userID string) map[string]map[string]bool
 	MetricsGeneratorProcessorSpanMetricsFilterPolicies(userID string) []filterconfig.FilterPolicy
+	MetricsGeneratorProcessorSpanMetricsStaleDuration(userID string) time.Duration
 	MetricsGeneratorProcessorLocalBlocksMaxLiveTraces(userID string) uint64
 	MetricsGeneratorProcessorLocalBlocksMaxBlockDuration(userID string) time.Duration
 	MetricsGeneratorProcessorLocalBlocksMaxBlockBytes(userID string) uint64
@@ -36,8 +44,15 @@ type metricsGeneratorOverrides interface {
 	MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram(userID string) bool
 	MetricsGeneratorProcessorServiceGraphsEnableVirtualNodeLabel(userID string) bool
 	MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions(userID string) []string
+	MetricsGeneratorProcessorSpanMetricsTargetInfoMetricName(userID string) string
+	MetricsGeneratorProcessorSpanEventsEventNames(userID string) []string
+	MetricsGeneratorProcessorSpanEventsDimensions(userID string) []string
+	MetricsGeneratorProcessorSpanEventsDimensionMappings(userID string) []sharedconfig.DimensionMappings
+	MetricsGeneratorProcessorLinkMetricsDimensions(userID string) []string
 	DedicatedColumns(userID string) backend.DedicatedColumns
 	MaxBytesPerTrace(userID string) int
+	MaxBytesPerTagValuesQuery(userID string) int
+	MaxBlocksPerTagValuesQuery(userID string) int
 	UnsafeQueryHints(userID string) bool
 }
 
@@ -3,26 +3,40 @@ package generator
 import (
 	"time"
 
+	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/sharedconfig"
 	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
 	"github.com/grafana/tempo/tempodb/backend"
 )
 
 type mockOverrides struct {
+	filterPolicies                                     []filterconfig.FilterPolicy
 	processors                                         map[string]struct{}
 	serviceGraphsHistogramBuckets                      []float64
+	serviceGraphsDatabaseHistogramBuckets              []float64
+	serviceGraphsMessagingSystemHistogramBuckets       []float64
 	serviceGraphsDimensions                            []string
 	serviceGraphsPeerAttributes                        []string
+	serviceGraphsDatabaseNameAttributes                []string
+	serviceGraphsVirtualNodeNameTemplate               string
 	serviceGraphsEnableClientServerPrefix              bool
 	serviceGraphsEnableMessagingSystemLatencyHistogram bool
 	serviceGraphsEnableVirtualNodeLabel                bool
+	serviceGraphsStaleDuration                         time.Duration
 	spanMetricsHistogramBuckets                        []float64
 	spanMetricsDimensions                              []string
 	spanMetricsIntrinsicDimensions                     map[string]bool
+	spanMetricsSubprocessorIntrinsicDimensions         map[string]map[string]bool
 	spanMetricsFilterPolicies                          []filterconfig.FilterPolicy
 	spanMetricsDimensionMappings                       []sharedconfig.DimensionMappings
 	spanMetricsEnableTargetInfo                        bool
 	spanMetricsTargetInfoExcludedDimensions            []string
+	spanMetricsTargetInfoMetricName                    string
+	spanMetricsStaleDuration                           time.Duration
+	spanEventsEventNames                               []string
+	spanEventsDimensions                               []string
+	spanEventsDimensionMappings                        []sharedconfig.DimensionMappings
+	linkMetricsDimensions                              []string
 	localBlocksMaxLiveTraces                           uint64
 	localBlocksMaxBlockDuration                        time.Duration
 	localBlocksMaxBlockBytes                           uint64
@@ -60,14 +74,38 @@ func (m *mockOverrides) MetricsGenerationTraceIDLabelName(userID string) string
 	return ""
 }
 
+func (m *mockOverrides) MetricsGeneratorGenerateNativeHistograms(string) overrides.HistogramMethod {
+	return overrides.HistogramMethodClassic
+}
+
+func (m *mockOverrides) MetricsGeneratorNativeHistogramBucketFactor(string) float64 {
+	return 0
+}
+
+func (m *mockOverrides) MetricsGeneratorNativeHistogramMaxBucketNumber(string) uint32 {
+	return 0
+}
+
 func (m *mockOverrides) MetricsGeneratorRemoteWriteHeaders(string) map[string]string {
 	return nil
 }
 
+func (m *mockOverrides) MetricsGeneratorRemoteWriteRoutes(string) []overrides.RemoteWriteRoute {
+	return nil
+}
+
 func (m *mockOverrides) MetricsGeneratorProcessorServiceGraphsHistogramBuckets(string) []float64 {
 	return m.serviceGraphsHistogramBuckets
 }
 
+func (m *mockOverrides) MetricsGeneratorProcessorServiceGraphsDatabaseHistogramBuckets(string) []float64 {
+	return m.serviceGraphsDatabaseHistogramBuckets
+}
+
+func (m *mockOverrides) MetricsGeneratorProcessorServiceGraphsMessagingSystemHistogramBuckets(string) []float64 {
+	return m.serviceGraphsMessagingSystemHistogramBuckets
+}
+
 func (m *mockOverrides) MetricsGeneratorProcessorServiceGraphsDimensions(string) []string {
 	return m.serviceGraphsDimensions
 }
@@ -76,6 +114,14 @@ func (m *mockOverrides) MetricsGeneratorProcessorServiceGraphsPeerAttributes(str
 	return m.serviceGraphsPeerAttributes
 }
 
+func (m *mockOverrides) MetricsGeneratorProcessorServiceGraphsDatabaseNameAttributes(string) []string {
+	return m.serviceGraphsDatabaseNameAttributes
+}
+
+func (m *mockOverrides) MetricsGeneratorProcessorServiceGraphsVirtualNodeNameTemplate(string) string {
+	return m.serviceGraphsVirtualNodeNameTemplate
+}
+
 func (m *mockOverrides) MetricsGeneratorProcessorSpanMetricsHistogramBuckets(string) []float64 {
 	return m.spanMetricsHistogramBuckets
 }
@@ -88,6 +134,14 @@ func (m *mockOverrides) MetricsGeneratorProcessorSpanMetricsIntrinsicDimensions(
 	return m.spanMetricsIntrinsicDimensions
 }
 
+func (m *mockOverrides) MetricsGeneratorProcessorSpanMetricsSubprocessorIntrinsicDimensions(string) map[string]map[string]bool {
+	return m.spanMetricsSubprocessorIntrinsicDimensions
+}
+
+func (m *mockOverrides) MetricsGeneratorFilterPolicies(string) []filterconfig.FilterPolicy {
+	return m.filterPolicies
+}
+
 func (m *mockOverrides) MetricsGeneratorProcessorSpanMetricsFilterPolicies(string) []filterconfig.FilterPolicy {
 	return m.spanMetricsFilterPolicies
 }
@@ -142,6 +196,34 @@ func (m *mockOverrides) MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDi
 	return m.spanMetricsTargetInfoExcludedDimensions
 }
 
+func (m *mockOverrides) MetricsGeneratorProcessorSpanMetricsTargetInfoMetricName(string) string {
+	return m.spanMetricsTargetInfoMetricName
+}
+
+func (m *mockOverrides) MetricsGeneratorProcessorSpanMetricsStaleDuration(string) time.Duration {
+	return m.spanMetricsStaleDuration
+}
+
+func (m *mockOverrides) MetricsGeneratorProcessorServiceGraphsStaleDuration(string) time.Duration {
+	return m.serviceGraphsStaleDuration
+}
+
+func (m *mockOverrides) MetricsGeneratorProcessorSpanEventsEventNames(string) []string {
+	return m.spanEventsEventNames
+}
+
+func (m *mockOverrides) MetricsGeneratorProcessorSpanEventsDimensions(string) []string {
+	return m.spanEventsDimensions
+}
+
+func (m *mockOverrides) MetricsGeneratorProcessorSpanEventsDimensionMappings(string) []sharedconfig.DimensionMappings {
+	return m.spanEventsDimensionMappings
+}
+
+func (m *mockOverrides) MetricsGeneratorProcessorLinkMetricsDimensions(string) []string {
+	return m.linkMetricsDimensions
+}
+
 func (m *mockOverrides) DedicatedColumns(string) backend.DedicatedColumns {
 	return m.dedicatedColumns
 }
@@ -150,6 +232,14 @@ func (m *mockOverrides) MaxBytesPerTrace(string) int {
 	return m.maxBytesPerTrace
 }
 
+func (m *mockOverrides) MaxBytesPerTagValuesQuery(string) int {
+	return 0
+}
+
+func (m *mockOverrides) MaxBlocksPerTagValuesQuery(string) int {
+	return 0
+}
+
 func (m *mockOverrides) UnsafeQueryHints(string) bool {
 	return m.unsafeQueryHints
 }
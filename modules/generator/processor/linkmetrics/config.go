@@ -0,0 +1,41 @@
+package linkmetrics
+
+import (
+	"flag"
+)
+
+const (
+	Name = "link-metrics"
+
+	dimService          = "service"
+	dimSpanName         = "span_name"
+	dimSpanKind         = "span_kind"
+	dimStatusCode       = "status_code"
+	dimHasLinks         = "has_links"
+	dimLinkRelationship = "link_relationship"
+)
+
+type Config struct {
+	// Intrinsic dimensions (labels) added to the metrics, that are generated from fixed span data. The
+	// dimensions service and span_name are enabled by default. The spans metric always has the has_links label,
+	// the links metric always has the link_relationship label.
+	IntrinsicDimensions IntrinsicDimensions `yaml:"intrinsic_dimensions"`
+	// Additional dimensions (labels) to be added to the links metric. The dimensions are generated from the
+	// link attributes, or the span and resource attributes if the link doesn't have the attribute.
+	Dimensions []string `yaml:"dimensions"`
+
+	// If enabled attribute value will be used for metric calculation
+	SpanMultiplierKey string `yaml:"span_multiplier_key"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
+	cfg.IntrinsicDimensions.Service = true
+	cfg.IntrinsicDimensions.SpanName = true
+}
+
+type IntrinsicDimensions struct {
+	Service    bool `yaml:"service"`
+	SpanName   bool `yaml:"span_name"`
+	SpanKind   bool `yaml:"span_kind"`
+	StatusCode bool `yaml:"status_code"`
+}
@@ -0,0 +1,159 @@
+package linkmetrics
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/prometheus/util/strutil"
+
+	gen "github.com/grafana/tempo/modules/generator/processor"
+	processor_util "github.com/grafana/tempo/modules/generator/processor/util"
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+const (
+	metricSpansTotal = "traces_linkmetrics_spans_total"
+	metricLinksTotal = "traces_linkmetrics_links_total"
+
+	// relationships of the linked span to the span with the link
+	relationshipSameTrace  = "same_trace"
+	relationshipOtherTrace = "other_trace"
+)
+
+// Processor counts spans by whether they have links and the links of spans by the relationship of the linked
+// trace, for example to follow the batches of messages that link the traces of their producers.
+type Processor struct {
+	Cfg Config
+
+	registry registry.Registry
+
+	spansTotal registry.Counter
+	linksTotal registry.Counter
+	spanLabels []string
+	linkLabels []string
+}
+
+func New(cfg Config, registry registry.Registry) gen.Processor {
+	intrinsicLabels := make([]string, 0, 4)
+	if cfg.IntrinsicDimensions.Service {
+		intrinsicLabels = append(intrinsicLabels, dimService)
+	}
+	if cfg.IntrinsicDimensions.SpanName {
+		intrinsicLabels = append(intrinsicLabels, dimSpanName)
+	}
+	if cfg.IntrinsicDimensions.SpanKind {
+		intrinsicLabels = append(intrinsicLabels, dimSpanKind)
+	}
+	if cfg.IntrinsicDimensions.StatusCode {
+		intrinsicLabels = append(intrinsicLabels, dimStatusCode)
+	}
+
+	spanLabels := append(append(make([]string, 0, len(intrinsicLabels)+1), intrinsicLabels...), dimHasLinks)
+
+	linkLabels := make([]string, 0, len(intrinsicLabels)+1+len(cfg.Dimensions))
+	linkLabels = append(linkLabels, intrinsicLabels...)
+	linkLabels = append(linkLabels, dimLinkRelationship)
+	for _, d := range cfg.Dimensions {
+		linkLabels = append(linkLabels, sanitizeLabelNameWithCollisions(d))
+	}
+
+	return &Processor{
+		Cfg:        cfg,
+		registry:   registry,
+		spansTotal: registry.NewCounter(metricSpansTotal),
+		linksTotal: registry.NewCounter(metricLinksTotal),
+		spanLabels: spanLabels,
+		linkLabels: linkLabels,
+	}
+}
+
+func (p *Processor) Name() string {
+	return Name
+}
+
+func (p *Processor) PushSpans(ctx context.Context, req *tempopb.PushSpansRequest) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "linkmetrics.PushSpans")
+	defer span.Finish()
+
+	for _, rs := range req.Batches {
+		svcName, _ := processor_util.FindServiceName(rs.Resource.Attributes)
+
+		for _, ils := range rs.ScopeSpans {
+			for _, span := range ils.Spans {
+				p.aggregateMetricsForSpan(svcName, rs.Resource.Attributes, span)
+			}
+		}
+	}
+}
+
+func (p *Processor) Shutdown(context.Context) {
+}
+
+func (p *Processor) aggregateMetricsForSpan(svcName string, resourceAttributes []*v1_common.KeyValue, span *v1_trace.Span) {
+	intrinsicValues := p.intrinsicLabelValues(svcName, span)
+	spanMultiplier := processor_util.GetSpanMultiplier(p.Cfg.SpanMultiplierKey, span)
+
+	// important: the order of the label values must correspond to the order of the labels
+	spanLabelValues := append(intrinsicValues, strconv.FormatBool(len(span.Links) > 0))
+	p.spansTotal.Inc(p.registry.NewLabelValueCombo(p.spanLabels, spanLabelValues), 1*spanMultiplier)
+
+	for _, link := range span.Links {
+		linkLabelValues := make([]string, 0, len(p.linkLabels))
+		linkLabelValues = append(linkLabelValues, intrinsicValues...)
+		linkLabelValues = append(linkLabelValues, linkRelationship(span, link))
+
+		// link attributes take precedence over span and resource attributes
+		for _, d := range p.Cfg.Dimensions {
+			value, _ := processor_util.FindAttributeValue(d, link.Attributes, span.Attributes, resourceAttributes)
+			linkLabelValues = append(linkLabelValues, value)
+		}
+
+		p.linksTotal.Inc(p.registry.NewLabelValueCombo(p.linkLabels, linkLabelValues), 1*spanMultiplier)
+	}
+}
+
+func (p *Processor) intrinsicLabelValues(svcName string, span *v1_trace.Span) []string {
+	values := make([]string, 0, len(p.spanLabels))
+
+	if p.Cfg.IntrinsicDimensions.Service {
+		values = append(values, svcName)
+	}
+	if p.Cfg.IntrinsicDimensions.SpanName {
+		values = append(values, span.GetName())
+	}
+	if p.Cfg.IntrinsicDimensions.SpanKind {
+		values = append(values, span.GetKind().String())
+	}
+	if p.Cfg.IntrinsicDimensions.StatusCode {
+		values = append(values, span.GetStatus().GetCode().String())
+	}
+
+	return values
+}
+
+// linkRelationship returns whether the link points to a span of the same trace or of another trace
+func linkRelationship(span *v1_trace.Span, link *v1_trace.Span_Link) string {
+	if bytes.Equal(span.TraceId, link.TraceId) {
+		return relationshipSameTrace
+	}
+	return relationshipOtherTrace
+}
+
+func sanitizeLabelNameWithCollisions(name string) string {
+	sanitized := strutil.SanitizeLabelName(name)
+
+	if isIntrinsicDimension(sanitized) {
+		return "__" + sanitized
+	}
+
+	return sanitized
+}
+
+func isIntrinsicDimension(name string) bool {
+	return processor_util.Contains(name, []string{dimService, dimSpanName, dimSpanKind, dimStatusCode, dimHasLinks, dimLinkRelationship})
+}
@@ -0,0 +1,162 @@
+package linkmetrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/tempopb"
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	resource_v1 "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	trace_v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+var (
+	traceID      = []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10}
+	otherTraceID = []byte{0x10, 0x0f, 0x0e, 0x0d, 0x0c, 0x0b, 0x0a, 0x09, 0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01}
+)
+
+func TestLinkMetrics(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	p := New(cfg, testRegistry)
+	defer p.Shutdown(context.Background())
+
+	require.Equal(t, p.Name(), "link-metrics")
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{makeBatch()}})
+
+	assert.Equal(t, 1.0, testRegistry.Query("traces_linkmetrics_spans_total", labels.FromMap(map[string]string{
+		"service":   "test-service",
+		"span_name": "process-batch",
+		"has_links": "true",
+	})))
+	assert.Equal(t, 1.0, testRegistry.Query("traces_linkmetrics_spans_total", labels.FromMap(map[string]string{
+		"service":   "test-service",
+		"span_name": "poll",
+		"has_links": "false",
+	})))
+
+	assert.Equal(t, 2.0, testRegistry.Query("traces_linkmetrics_links_total", labels.FromMap(map[string]string{
+		"service":           "test-service",
+		"span_name":         "process-batch",
+		"link_relationship": "other_trace",
+	})))
+	assert.Equal(t, 1.0, testRegistry.Query("traces_linkmetrics_links_total", labels.FromMap(map[string]string{
+		"service":           "test-service",
+		"span_name":         "process-batch",
+		"link_relationship": "same_trace",
+	})))
+}
+
+func TestLinkMetricsDimensions(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.IntrinsicDimensions.SpanName = false
+	cfg.IntrinsicDimensions.SpanKind = true
+	cfg.Dimensions = []string{"messaging.operation", "link.relationship"}
+	p := New(cfg, testRegistry)
+	defer p.Shutdown(context.Background())
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{makeBatch()}})
+
+	assert.Equal(t, 1.0, testRegistry.Query("traces_linkmetrics_spans_total", labels.FromMap(map[string]string{
+		"service":   "test-service",
+		"span_kind": "SPAN_KIND_CONSUMER",
+		"has_links": "true",
+	})))
+
+	// the link attribute takes precedence over the span attribute and collides with the intrinsic dimension
+	assert.Equal(t, 1.0, testRegistry.Query("traces_linkmetrics_links_total", labels.FromMap(map[string]string{
+		"service":             "test-service",
+		"span_kind":           "SPAN_KIND_CONSUMER",
+		"link_relationship":   "other_trace",
+		"messaging_operation": "publish",
+		"__link_relationship": "producer",
+	})))
+	assert.Equal(t, 1.0, testRegistry.Query("traces_linkmetrics_links_total", labels.FromMap(map[string]string{
+		"service":             "test-service",
+		"span_kind":           "SPAN_KIND_CONSUMER",
+		"link_relationship":   "other_trace",
+		"messaging_operation": "process",
+		"__link_relationship": "",
+	})))
+}
+
+func TestLinkMetricsSpanMultiplier(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.SpanMultiplierKey = "X-SampleRatio"
+	p := New(cfg, testRegistry)
+	defer p.Shutdown(context.Background())
+
+	batch := makeBatch()
+	batch.ScopeSpans[0].Spans[0].Attributes = append(batch.ScopeSpans[0].Spans[0].Attributes, &common_v1.KeyValue{
+		Key:   "X-SampleRatio",
+		Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_DoubleValue{DoubleValue: 0.5}},
+	})
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{batch}})
+
+	assert.Equal(t, 2.0, testRegistry.Query("traces_linkmetrics_spans_total", labels.FromMap(map[string]string{
+		"service":   "test-service",
+		"span_name": "process-batch",
+		"has_links": "true",
+	})))
+	assert.Equal(t, 4.0, testRegistry.Query("traces_linkmetrics_links_total", labels.FromMap(map[string]string{
+		"service":           "test-service",
+		"span_name":         "process-batch",
+		"link_relationship": "other_trace",
+	})))
+}
+
+func makeBatch() *trace_v1.ResourceSpans {
+	return &trace_v1.ResourceSpans{
+		Resource: &resource_v1.Resource{
+			Attributes: []*common_v1.KeyValue{stringKV("service.name", "test-service")},
+		},
+		ScopeSpans: []*trace_v1.ScopeSpans{{
+			Spans: []*trace_v1.Span{
+				{
+					TraceId:    traceID,
+					Name:       "process-batch",
+					Kind:       trace_v1.Span_SPAN_KIND_CONSUMER,
+					Attributes: []*common_v1.KeyValue{stringKV("messaging.operation", "process")},
+					Links: []*trace_v1.Span_Link{
+						{
+							TraceId: otherTraceID,
+							Attributes: []*common_v1.KeyValue{
+								stringKV("messaging.operation", "publish"),
+								stringKV("link.relationship", "producer"),
+							},
+						},
+						{TraceId: otherTraceID},
+						{TraceId: traceID},
+					},
+				},
+				{
+					TraceId: traceID,
+					Name:    "poll",
+					Kind:    trace_v1.Span_SPAN_KIND_CONSUMER,
+				},
+			},
+		}},
+	}
+}
+
+func stringKV(key, value string) *common_v1.KeyValue {
+	return &common_v1.KeyValue{
+		Key:   key,
+		Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: value}},
+	}
+}
@@ -0,0 +1,66 @@
+package localblocks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// FindTraceByID returns the spans of the trace held by the processor. Live traces are included so spans that
+// haven't been cut to the head block yet are found.
+func (p *Processor) FindTraceByID(ctx context.Context, id []byte) (*tempopb.Trace, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Processor.FindTraceByID")
+	defer span.Finish()
+
+	maxBytes := p.overrides.MaxBytesPerTrace(p.tenant)
+	searchOpts := common.DefaultSearchOptionsWithMaxBytes(maxBytes)
+	combiner := trace.NewCombiner(maxBytes)
+
+	// live traces, the batches are cloned because the combiner is destructive
+	p.liveTracesMtx.Lock()
+	if t, ok := p.liveTraces.traces[p.liveTraces.token(id)]; ok {
+		_, err := combiner.Consume(proto.Clone(&tempopb.Trace{Batches: t.Batches}).(*tempopb.Trace))
+		if err != nil {
+			p.liveTracesMtx.Unlock()
+			return nil, err
+		}
+	}
+	p.liveTracesMtx.Unlock()
+
+	p.blocksMtx.RLock()
+	defer p.blocksMtx.RUnlock()
+
+	finders := make([]common.Finder, 0, 1+len(p.walBlocks)+len(p.completeBlocks))
+	if p.headBlock != nil {
+		finders = append(finders, p.headBlock)
+	}
+	for _, b := range p.walBlocks {
+		finders = append(finders, b)
+	}
+	for _, b := range p.completeBlocks {
+		finders = append(finders, b)
+	}
+
+	for _, f := range finders {
+		tr, err := f.FindTraceByID(ctx, id, searchOpts)
+		if err != nil {
+			return nil, fmt.Errorf("FindTraceByID failed: %w", err)
+		}
+		if tr == nil {
+			continue
+		}
+		_, err = combiner.Consume(tr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result, _ := combiner.Result()
+	return result, nil
+}
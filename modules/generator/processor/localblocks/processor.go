@@ -37,6 +37,8 @@ const timeBuffer = 5 * time.Minute
 type ProcessorOverrides interface {
 	DedicatedColumns(string) backend.DedicatedColumns
 	MaxBytesPerTrace(string) int
+	MaxBytesPerTagValuesQuery(string) int
+	MaxBlocksPerTagValuesQuery(string) int
 	UnsafeQueryHints(string) bool
 }
 
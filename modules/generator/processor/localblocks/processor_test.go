@@ -34,6 +34,14 @@ func (m *mockOverrides) MaxBytesPerTrace(string) int {
 	return 0
 }
 
+func (m *mockOverrides) MaxBytesPerTagValuesQuery(string) int {
+	return 0
+}
+
+func (m *mockOverrides) MaxBlocksPerTagValuesQuery(string) int {
+	return 0
+}
+
 func (m *mockOverrides) UnsafeQueryHints(string) bool {
 	return false
 }
@@ -66,6 +74,26 @@ func (m *mockWriter) CompleteBlockWithBackend(context.Context, common.WALBlock,
 	return nil, nil
 }
 
+func (m *mockWriter) MarkTenantDeleted(context.Context, string) (*backend.TenantDeletionMark, error) {
+	return nil, nil
+}
+
+func (m *mockWriter) UnmarkTenantDeleted(context.Context, string) error {
+	return nil
+}
+
+func (m *mockWriter) SnapshotWAL(context.Context, string, string, *wal.TenantFilesCopy) (*backend.IngesterSnapshot, error) {
+	return nil, nil
+}
+
+func (m *mockWriter) PruneWALSnapshot(context.Context, string, string, uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+func (m *mockWriter) RestoreWAL(context.Context, string) (map[string]*backend.IngesterSnapshot, error) {
+	return nil, nil
+}
+
 func (m *mockWriter) WAL() *wal.WAL { return nil }
 
 func TestProcessorDoesNotRace(t *testing.T) {
@@ -357,3 +385,146 @@ func (m *mockBlock) FetchTagValues(context.Context, traceql.FetchTagValuesReques
 }
 
 func (m *mockBlock) BlockMeta() *backend.BlockMeta { return m.meta }
+
+func TestFindTraceByID(t *testing.T) {
+	wal, err := wal.New(&wal.Config{
+		Filepath: t.TempDir(),
+		Version:  encoding.DefaultEncoding().Version(),
+	})
+	require.NoError(t, err)
+
+	cfg := Config{
+		FlushCheckPeriod:     time.Minute,
+		TraceIdlePeriod:      time.Minute,
+		CompleteBlockTimeout: time.Minute,
+		Block: &common.BlockConfig{
+			BloomShardSizeBytes: 100_000,
+			BloomFP:             0.05,
+			Version:             encoding.DefaultEncoding().Version(),
+		},
+		Metrics: MetricsConfig{
+			ConcurrentBlocks:  10,
+			TimeOverlapCutoff: 0.2,
+		},
+	}
+
+	p, err := New(cfg, "fake", wal, nil, &mockOverrides{})
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	id := test.ValidTraceID(nil)
+	tr := test.MakeTrace(2, id)
+	spans := countSpans(tr)
+
+	find := func() int {
+		found, err := p.FindTraceByID(context.Background(), id)
+		require.NoError(t, err)
+		if found == nil {
+			return 0
+		}
+		return countSpans(found)
+	}
+
+	require.Equal(t, 0, find())
+
+	// live traces
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: tr.Batches[:1]})
+	require.Equal(t, countSpans(&tempopb.Trace{Batches: tr.Batches[:1]}), find())
+
+	// head block, the rest of the trace is still live
+	require.NoError(t, p.cutIdleTraces(true))
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: tr.Batches[1:]})
+	require.Equal(t, spans, find())
+
+	// wal and complete blocks
+	require.NoError(t, p.cutIdleTraces(true))
+	require.NoError(t, p.cutBlocks(true))
+	require.Equal(t, spans, find())
+
+	require.NoError(t, p.completeBlock())
+	require.Equal(t, spans, find())
+
+	// spans pushed again are deduped
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: tr.Batches})
+	require.Equal(t, spans, find())
+}
+
+func TestSearchTags(t *testing.T) {
+	wal, err := wal.New(&wal.Config{
+		Filepath: t.TempDir(),
+		Version:  encoding.DefaultEncoding().Version(),
+	})
+	require.NoError(t, err)
+
+	cfg := Config{
+		FlushCheckPeriod:     time.Minute,
+		TraceIdlePeriod:      time.Minute,
+		CompleteBlockTimeout: time.Minute,
+		Block: &common.BlockConfig{
+			BloomShardSizeBytes: 100_000,
+			BloomFP:             0.05,
+			Version:             encoding.DefaultEncoding().Version(),
+		},
+		Metrics: MetricsConfig{
+			ConcurrentBlocks:  10,
+			TimeOverlapCutoff: 0.2,
+		},
+	}
+
+	p, err := New(cfg, "fake", wal, nil, &mockOverrides{})
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	ctx := context.Background()
+	search := func() {
+		tags, err := p.SearchTags(ctx, "")
+		require.NoError(t, err)
+		require.Contains(t, tags.TagNames, "service.name")
+
+		tagsV2, err := p.SearchTagsV2(ctx, "resource")
+		require.NoError(t, err)
+		require.Len(t, tagsV2.Scopes, 1)
+		require.Equal(t, "resource", tagsV2.Scopes[0].Name)
+		require.Contains(t, tagsV2.Scopes[0].Tags, "service.name")
+
+		values, err := p.SearchTagValues(ctx, "service.name")
+		require.NoError(t, err)
+		require.Equal(t, []string{"test-service"}, values.TagValues)
+
+		valuesV2, err := p.SearchTagValuesV2(ctx, &tempopb.SearchTagValuesRequest{TagName: "resource.service.name"})
+		require.NoError(t, err)
+		require.Equal(t, []*tempopb.TagValue{{Type: "string", Value: "test-service"}}, valuesV2.TagValues)
+
+		valuesV2, err = p.SearchTagValuesV2(ctx, &tempopb.SearchTagValuesRequest{TagName: "resource.service.name", Query: `{ resource.service.name = "other" }`})
+		require.NoError(t, err)
+		require.Empty(t, valuesV2.TagValues)
+	}
+
+	// nothing yet
+	tags, err := p.SearchTagsV2(ctx, "resource")
+	require.NoError(t, err)
+	require.Empty(t, tags.Scopes)
+
+	p.PushSpans(ctx, &tempopb.PushSpansRequest{Batches: test.MakeTrace(2, nil).Batches})
+
+	// head block
+	require.NoError(t, p.cutIdleTraces(true))
+	search()
+
+	// wal and complete blocks
+	require.NoError(t, p.cutBlocks(true))
+	search()
+
+	require.NoError(t, p.completeBlock())
+	search()
+}
+
+func countSpans(tr *tempopb.Trace) int {
+	n := 0
+	for _, b := range tr.Batches {
+		for _, ss := range b.ScopeSpans {
+			n += len(ss.Spans)
+		}
+	}
+	return n
+}
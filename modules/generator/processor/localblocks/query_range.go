@@ -28,8 +28,10 @@ func (p *Processor) QueryRange(ctx context.Context, req *tempopb.QueryRangeReque
 	p.blocksMtx.RLock()
 	defer p.blocksMtx.RUnlock()
 
+	spanStart, spanEnd := traceql.SpanTimeRange(req)
+
 	cutoff := time.Now().Add(-p.Cfg.CompleteBlockTimeout).Add(-timeBuffer)
-	if req.Start < uint64(cutoff.UnixNano()) {
+	if spanStart < uint64(cutoff.UnixNano()) {
 		return nil, fmt.Errorf("time range must be within last %v", p.Cfg.CompleteBlockTimeout)
 	}
 
@@ -72,7 +74,7 @@ func (p *Processor) QueryRange(ctx context.Context, req *tempopb.QueryRangeReque
 	withinRange := func(m *backend.BlockMeta) bool {
 		start := uint64(m.StartTime.UnixNano())
 		end := uint64(m.EndTime.UnixNano())
-		return req.Start < end && req.End > start
+		return spanStart < end && spanEnd > start
 	}
 
 	var (
@@ -173,7 +175,7 @@ func (p *Processor) queryRangeCompleteBlock(ctx context.Context, b *ingester.Loc
 	// Trim and align the request for this block. I.e. if the request is "Last Hour" we don't want to
 	// cache the response for that, we want only the few minutes time range for this block. This has
 	// size savings but the main thing is that the response is reuseable for any overlapping query.
-	req.Start, req.End = traceql.TrimToOverlap(req.Start, req.End, req.Step, uint64(m.StartTime.UnixNano()), uint64(m.EndTime.UnixNano()))
+	req.Start, req.End = traceql.TrimToBlockOverlap(&req, uint64(m.StartTime.UnixNano()), uint64(m.EndTime.UnixNano()))
 
 	if req.Start >= req.End {
 		// After alignment there is no overlap or something else isn't right
@@ -255,6 +257,10 @@ func queryRangeHashForBlock(req tempopb.QueryRangeRequest) uint64 {
 	h = fnv1a.AddUint64(h, req.Start)
 	h = fnv1a.AddUint64(h, req.End)
 	h = fnv1a.AddUint64(h, req.Step)
+	if req.Offset > 0 || req.Lookback > 0 {
+		h = fnv1a.AddUint64(h, req.Offset)
+		h = fnv1a.AddUint64(h, req.Lookback)
+	}
 
 	// TODO - caching for WAL blocks
 	// Including trace count means we can safely cache results
@@ -0,0 +1,220 @@
+package localblocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-kit/log/level"
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/collector"
+	"github.com/grafana/tempo/pkg/search"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// SearchTags returns the tags in the blocks of the processor.
+func (p *Processor) SearchTags(ctx context.Context, scope string) (*tempopb.SearchTagsResponse, error) {
+	v2Response, err := p.SearchTagsV2(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	distinctValues := collector.NewDistinctString(0) // search tags v2 enforces the limit
+
+	// flatten v2 response
+	for _, s := range v2Response.Scopes {
+		// SearchTags does not include intrinsics on an empty scope, but v2 does.
+		if scope == "" && s.Name == api.ParamScopeIntrinsic {
+			continue
+		}
+
+		for _, t := range s.Tags {
+			distinctValues.Collect(t)
+		}
+	}
+
+	return &tempopb.SearchTagsResponse{
+		TagNames: distinctValues.Strings(),
+	}, nil
+}
+
+// SearchTagsV2 returns the tags in the blocks of the processor by scope. Like the ingesters the intrinsics are
+// included for the intrinsic and empty scopes.
+func (p *Processor) SearchTagsV2(ctx context.Context, scope string) (*tempopb.SearchTagsV2Response, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Processor.SearchTagsV2")
+	defer span.Finish()
+
+	// check if it's the special intrinsic scope
+	if scope == api.ParamScopeIntrinsic {
+		return &tempopb.SearchTagsV2Response{
+			Scopes: []*tempopb.SearchTagsV2Scope{
+				{
+					Name: api.ParamScopeIntrinsic,
+					Tags: search.GetVirtualIntrinsicValues(),
+				},
+			},
+		}, nil
+	}
+
+	// parse for normal scopes
+	attributeScope := traceql.AttributeScopeFromString(scope)
+	if attributeScope == traceql.AttributeScopeUnknown {
+		return nil, fmt.Errorf("unknown scope: %s", scope)
+	}
+
+	limit := p.overrides.MaxBytesPerTagValuesQuery(p.tenant)
+	distinctValues := collector.NewScopedDistinctString(limit)
+
+	p.blocksMtx.RLock()
+	defer p.blocksMtx.RUnlock()
+
+	for _, b := range p.searchBlocks() {
+		if distinctValues.Exceeded() {
+			break
+		}
+
+		err := b.SearchTags(ctx, attributeScope, func(t string, scope traceql.AttributeScope) {
+			distinctValues.Collect(scope.String(), t)
+		}, common.DefaultSearchOptions())
+		if err != nil && !errors.Is(err, common.ErrUnsupported) {
+			return nil, fmt.Errorf("unexpected error searching tags of block (%s): %w", b.BlockMeta().BlockID, err)
+		}
+	}
+
+	if distinctValues.Exceeded() {
+		level.Warn(p.logger).Log("msg", "size of tags in local blocks exceeded limit, reduce cardinality or size of tags", "limit", limit)
+	}
+
+	collected := distinctValues.Strings()
+	resp := &tempopb.SearchTagsV2Response{
+		Scopes: make([]*tempopb.SearchTagsV2Scope, 0, len(collected)+1), // +1 for intrinsic below
+	}
+	for scope, vals := range collected {
+		resp.Scopes = append(resp.Scopes, &tempopb.SearchTagsV2Scope{
+			Name: scope,
+			Tags: vals,
+		})
+	}
+
+	// add intrinsic tags if scope is none
+	if attributeScope == traceql.AttributeScopeNone {
+		resp.Scopes = append(resp.Scopes, &tempopb.SearchTagsV2Scope{
+			Name: api.ParamScopeIntrinsic,
+			Tags: search.GetVirtualIntrinsicValues(),
+		})
+	}
+
+	return resp, nil
+}
+
+// SearchTagValues returns the values of the tag in the blocks of the processor.
+func (p *Processor) SearchTagValues(ctx context.Context, tagName string) (*tempopb.SearchTagValuesResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Processor.SearchTagValues")
+	defer span.Finish()
+
+	limit := p.overrides.MaxBytesPerTagValuesQuery(p.tenant)
+	distinctValues := collector.NewDistinctString(limit)
+
+	p.blocksMtx.RLock()
+	defer p.blocksMtx.RUnlock()
+
+	for _, b := range p.tagValuesBlocks() {
+		if distinctValues.Exceeded() {
+			break
+		}
+
+		err := b.SearchTagValues(ctx, tagName, distinctValues.Collect, common.DefaultSearchOptions())
+		if err != nil && !errors.Is(err, common.ErrUnsupported) {
+			return nil, fmt.Errorf("unexpected error searching tag values (%s) of block (%s): %w", tagName, b.BlockMeta().BlockID, err)
+		}
+	}
+
+	if distinctValues.Exceeded() {
+		level.Warn(p.logger).Log("msg", "size of tag values in local blocks exceeded limit, reduce cardinality or size of tags", "tag", tagName, "limit", limit, "total", distinctValues.TotalDataSize())
+	}
+
+	return &tempopb.SearchTagValuesResponse{
+		TagValues: distinctValues.Strings(),
+	}, nil
+}
+
+// SearchTagValuesV2 returns the typed values of the tag in the blocks of the processor. Values are filtered by
+// the query of the request if it has one.
+func (p *Processor) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTagValuesRequest) (*tempopb.SearchTagValuesV2Response, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Processor.SearchTagValuesV2")
+	defer span.Finish()
+
+	tag, err := traceql.ParseIdentifier(req.TagName)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := p.overrides.MaxBytesPerTagValuesQuery(p.tenant)
+	valueCollector := collector.NewDistinctValue[tempopb.TagValue](limit, func(v tempopb.TagValue) int { return len(v.Type) + len(v.Value) })
+
+	engine := traceql.NewEngine()
+	query := traceql.ExtractMatchers(req.Query)
+
+	p.blocksMtx.RLock()
+	defer p.blocksMtx.RUnlock()
+
+	for _, b := range p.tagValuesBlocks() {
+		if valueCollector.Exceeded() {
+			break
+		}
+
+		// if the query is empty use the old search
+		if traceql.IsEmptyQuery(query) {
+			err = b.SearchTagValuesV2(ctx, tag, traceql.MakeCollectTagValueFunc(valueCollector.Collect), common.DefaultSearchOptions())
+		} else {
+			fetcher := traceql.NewTagValuesFetcherWrapper(func(ctx context.Context, req traceql.FetchTagValuesRequest, cb traceql.FetchTagValuesCallback) error {
+				return b.FetchTagValues(ctx, req, cb, common.DefaultSearchOptions())
+			})
+			err = engine.ExecuteTagValues(ctx, tag, query, traceql.MakeCollectTagValueFunc(valueCollector.Collect), fetcher)
+		}
+		if err != nil && !errors.Is(err, common.ErrUnsupported) {
+			return nil, fmt.Errorf("unexpected error searching tag values (%s) of block (%s): %w", req.TagName, b.BlockMeta().BlockID, err)
+		}
+	}
+
+	if valueCollector.Exceeded() {
+		level.Warn(p.logger).Log("msg", "size of tag values in local blocks exceeded limit, reduce cardinality or size of tags", "tag", req.TagName, "limit", limit, "total", valueCollector.TotalDataSize())
+	}
+
+	resp := &tempopb.SearchTagValuesV2Response{}
+	for _, v := range valueCollector.Values() {
+		v2 := v
+		resp.TagValues = append(resp.TagValues, &v2)
+	}
+
+	return resp, nil
+}
+
+// searchBlocks returns the head, wal and complete blocks. blocksMtx must be held.
+func (p *Processor) searchBlocks() []common.BackendBlock {
+	blocks := make([]common.BackendBlock, 0, 1+len(p.walBlocks)+len(p.completeBlocks))
+	if p.headBlock != nil {
+		blocks = append(blocks, p.headBlock)
+	}
+	for _, b := range p.walBlocks {
+		blocks = append(blocks, b)
+	}
+	for _, b := range p.completeBlocks {
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+// tagValuesBlocks returns the blocks searched for tag values, limited to the max blocks per tag values query
+// of the tenant. blocksMtx must be held.
+func (p *Processor) tagValuesBlocks() []common.BackendBlock {
+	blocks := p.searchBlocks()
+	if maxBlocks := p.overrides.MaxBlocksPerTagValuesQuery(p.tenant); maxBlocks > 0 && len(blocks) > maxBlocks {
+		blocks = blocks[:maxBlocks]
+	}
+	return blocks
+}
@@ -23,6 +23,15 @@ type Config struct {
 	// Buckets for latency histogram in seconds.
 	HistogramBuckets []float64 `yaml:"histogram_buckets"`
 
+	// Buckets for the latency histograms of database edges in seconds. If set, database edges are
+	// recorded in separate histograms instead of the default ones.
+	DatabaseHistogramBuckets []float64 `yaml:"database_histogram_buckets"`
+
+	// Buckets for the latency histograms of messaging system edges in seconds. If set, messaging system
+	// edges are recorded in separate histograms instead of the default ones. These buckets are also used
+	// by the messaging system latency histogram.
+	MessagingSystemHistogramBuckets []float64 `yaml:"messaging_system_histogram_buckets"`
+
 	// Additional dimensions (labels) to be added to the metric along with the default ones.
 	// If client and server spans have the same attribute and EnableClientServerPrefix is not enabled,
 	// behaviour is undetermined (either value could get used)
@@ -40,6 +49,15 @@ type Config struct {
 	// Attributes are searched in the order they are provided
 	PeerAttributes []string `yaml:"peer_attributes"`
 
+	// DatabaseNameAttributes are attributes that will be used to name database nodes
+	// Attributes are searched in the order they are provided. If empty peer.service, server.address,
+	// network.peer.address (with network.peer.port) and db.name are used.
+	DatabaseNameAttributes []string `yaml:"database_name_attributes"`
+
+	// VirtualNodeNameTemplate names peer and database nodes from span attributes, i.e. "{db.system}/{db.name}".
+	// If any of the attributes is missing the node is named by PeerAttributes or DatabaseNameAttributes instead.
+	VirtualNodeNameTemplate string `yaml:"virtual_node_name_template"`
+
 	// If enabled attribute value will be used for metric calculation
 	SpanMultiplierKey string `yaml:"span_multiplier_key"`
 
@@ -0,0 +1,68 @@
+package servicegraphs
+
+import (
+	"strings"
+
+	processor_util "github.com/grafana/tempo/modules/generator/processor/util"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+)
+
+// nodeNameTemplate names virtual nodes from span attributes. Attribute names are written in braces, i.e.
+// "{db.system}/{db.name}", everything else is copied as is.
+type nodeNameTemplate struct {
+	parts []nodeNamePart
+}
+
+type nodeNamePart struct {
+	literal   string
+	attribute string
+}
+
+// parseNodeNameTemplate returns nil for an empty template. An unterminated brace is kept as a literal.
+func parseNodeNameTemplate(s string) *nodeNameTemplate {
+	if s == "" {
+		return nil
+	}
+
+	t := &nodeNameTemplate{}
+	for len(s) > 0 {
+		start := strings.IndexByte(s, '{')
+		if start < 0 {
+			t.parts = append(t.parts, nodeNamePart{literal: s})
+			break
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			t.parts = append(t.parts, nodeNamePart{literal: s})
+			break
+		}
+		end += start
+
+		if start > 0 {
+			t.parts = append(t.parts, nodeNamePart{literal: s[:start]})
+		}
+		t.parts = append(t.parts, nodeNamePart{attribute: s[start+1 : end]})
+		s = s[end+1:]
+	}
+
+	return t
+}
+
+// render returns the node name. It returns false if any of the attributes is missing.
+func (t *nodeNameTemplate) render(attributes ...[]*v1_common.KeyValue) (string, bool) {
+	sb := strings.Builder{}
+	for _, part := range t.parts {
+		if part.attribute == "" {
+			sb.WriteString(part.literal)
+			continue
+		}
+
+		v, ok := processor_util.FindAttributeValue(part.attribute, attributes...)
+		if !ok {
+			return "", false
+		}
+		sb.WriteString(v)
+	}
+
+	return sb.String(), true
+}
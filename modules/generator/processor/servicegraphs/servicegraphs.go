@@ -51,6 +51,11 @@ const (
 	metricRequestServerSeconds          = "traces_service_graph_request_server_seconds"
 	metricRequestClientSeconds          = "traces_service_graph_request_client_seconds"
 	metricRequestMessagingSystemSeconds = "traces_service_graph_request_messaging_system_seconds"
+
+	metricDatabaseRequestServerSeconds        = "traces_service_graph_database_request_server_seconds"
+	metricDatabaseRequestClientSeconds        = "traces_service_graph_database_request_client_seconds"
+	metricMessagingSystemRequestServerSeconds = "traces_service_graph_messaging_system_request_server_seconds"
+	metricMessagingSystemRequestClientSeconds = "traces_service_graph_messaging_system_request_client_seconds"
 )
 
 const virtualNodeLabel = "virtual_node"
@@ -67,12 +72,16 @@ func (t *tooManySpansError) Error() string {
 	return fmt.Sprintf("dropped %d spans", t.droppedSpans)
 }
 
+// connectionTypeHistograms holds the latency histograms of connection types with their own buckets.
+type connectionTypeHistograms map[store.ConnectionType]registry.Histogram
+
 type Processor struct {
 	Cfg Config
 
 	registry registry.Registry
 	labels   []string
 	store    store.Store
+	nodeName *nodeNameTemplate
 
 	closeCh chan struct{}
 
@@ -82,6 +91,9 @@ type Processor struct {
 	serviceGraphRequestClientSecondsHistogram          registry.Histogram
 	serviceGraphRequestMessagingSystemSecondsHistogram registry.Histogram
 
+	serviceGraphRequestServerSecondsByConnectionType connectionTypeHistograms
+	serviceGraphRequestClientSecondsByConnectionType connectionTypeHistograms
+
 	metricDroppedSpans prometheus.Counter
 	metricTotalEdges   prometheus.Counter
 	metricExpiredEdges prometheus.Counter
@@ -110,17 +122,26 @@ func New(cfg Config, tenant string, registry registry.Registry, logger log.Logge
 		}
 	}
 
+	messagingSystemBuckets := cfg.HistogramBuckets
+	if len(cfg.MessagingSystemHistogramBuckets) > 0 {
+		messagingSystemBuckets = cfg.MessagingSystemHistogramBuckets
+	}
+
 	p := &Processor{
 		Cfg:      cfg,
 		registry: registry,
 		labels:   labels,
+		nodeName: parseNodeNameTemplate(cfg.VirtualNodeNameTemplate),
 		closeCh:  make(chan struct{}, 1),
 
 		serviceGraphRequestTotal:                           registry.NewCounter(metricRequestTotal),
 		serviceGraphRequestFailedTotal:                     registry.NewCounter(metricRequestFailedTotal),
 		serviceGraphRequestServerSecondsHistogram:          registry.NewHistogram(metricRequestServerSeconds, cfg.HistogramBuckets),
 		serviceGraphRequestClientSecondsHistogram:          registry.NewHistogram(metricRequestClientSeconds, cfg.HistogramBuckets),
-		serviceGraphRequestMessagingSystemSecondsHistogram: registry.NewHistogram(metricRequestMessagingSystemSeconds, cfg.HistogramBuckets),
+		serviceGraphRequestMessagingSystemSecondsHistogram: registry.NewHistogram(metricRequestMessagingSystemSeconds, messagingSystemBuckets),
+
+		serviceGraphRequestServerSecondsByConnectionType: connectionTypeHistograms{},
+		serviceGraphRequestClientSecondsByConnectionType: connectionTypeHistograms{},
 
 		metricDroppedSpans: metricDroppedSpans.WithLabelValues(tenant),
 		metricTotalEdges:   metricTotalEdges.WithLabelValues(tenant),
@@ -128,6 +149,15 @@ func New(cfg Config, tenant string, registry registry.Registry, logger log.Logge
 		logger:             log.With(logger, "component", "service-graphs"),
 	}
 
+	if len(cfg.DatabaseHistogramBuckets) > 0 {
+		p.serviceGraphRequestServerSecondsByConnectionType[store.Database] = registry.NewHistogram(metricDatabaseRequestServerSeconds, cfg.DatabaseHistogramBuckets)
+		p.serviceGraphRequestClientSecondsByConnectionType[store.Database] = registry.NewHistogram(metricDatabaseRequestClientSeconds, cfg.DatabaseHistogramBuckets)
+	}
+	if len(cfg.MessagingSystemHistogramBuckets) > 0 {
+		p.serviceGraphRequestServerSecondsByConnectionType[store.MessagingSystem] = registry.NewHistogram(metricMessagingSystemRequestServerSeconds, cfg.MessagingSystemHistogramBuckets)
+		p.serviceGraphRequestClientSecondsByConnectionType[store.MessagingSystem] = registry.NewHistogram(metricMessagingSystemRequestClientSeconds, cfg.MessagingSystemHistogramBuckets)
+	}
+
 	p.store = store.NewStore(cfg.Wait, cfg.MaxItems, p.onComplete, p.onExpire)
 
 	expirationTicker := time.NewTicker(2 * time.Second)
@@ -264,10 +294,17 @@ func (p *Processor) upsertDimensions(prefix string, m map[string]string, resourc
 	}
 }
 
+// upsertPeerNode names the peer of the span if it has any of the peer attributes. The name is rendered from the
+// virtual node name template if configured, otherwise the first peer attribute found is used.
 func (p *Processor) upsertPeerNode(e *store.Edge, spanAttr []*v1_common.KeyValue) {
 	for _, peerKey := range p.Cfg.PeerAttributes {
 		if v, ok := processor_util.FindAttributeValue(peerKey, spanAttr); ok {
 			e.PeerNode = v
+			if p.nodeName != nil {
+				if name, ok := p.nodeName.render(spanAttr); ok {
+					e.PeerNode = name
+				}
+			}
 			return
 		}
 	}
@@ -275,8 +312,10 @@ func (p *Processor) upsertPeerNode(e *store.Edge, spanAttr []*v1_common.KeyValue
 
 // upsertDatabaseRequest handles the logic of adding a database edge on the
 // graph.  If we have a db.name or db.system attribute, we assume this is a
-// database request.  The name of the edge is determined by the following
-// order:
+// database request.  If a virtual node name template is configured and all of its
+// attributes are present, it names the database.  Otherwise, if database name
+// attributes are configured, the first one present is used.  Otherwise the name of
+// the edge is determined by the following order:
 //
 //	if we have a peer.service, use it as the database ServerService
 //	if we have a server.address, use it as the database ServerService
@@ -310,6 +349,24 @@ func (p *Processor) upsertDatabaseRequest(e *store.Edge, resourceAttr []*v1_comm
 	e.ConnectionType = store.Database
 	e.ServerLatencySec = spanDurationSec(span)
 
+	if p.nodeName != nil {
+		if name, ok := p.nodeName.render(resourceAttr, span.Attributes); ok {
+			e.ServerService = name
+			return
+		}
+	}
+
+	if len(p.Cfg.DatabaseNameAttributes) > 0 {
+		for _, key := range p.Cfg.DatabaseNameAttributes {
+			if name, ok := processor_util.FindAttributeValue(key, resourceAttr, span.Attributes); ok {
+				e.ServerService = name
+				return
+			}
+		}
+		e.ServerService = dbName
+		return
+	}
+
 	// Set the service name by order of precedence
 
 	// Check for peer.service
@@ -372,8 +429,9 @@ func (p *Processor) onComplete(e *store.Edge) {
 		p.serviceGraphRequestFailedTotal.Inc(registryLabelValues, 1*e.SpanMultiplier)
 	}
 
-	p.serviceGraphRequestServerSecondsHistogram.ObserveWithExemplar(registryLabelValues, e.ServerLatencySec, e.TraceID, e.SpanMultiplier)
-	p.serviceGraphRequestClientSecondsHistogram.ObserveWithExemplar(registryLabelValues, e.ClientLatencySec, e.TraceID, e.SpanMultiplier)
+	serverHistogram, clientHistogram := p.latencyHistograms(e.ConnectionType)
+	serverHistogram.ObserveWithExemplar(registryLabelValues, e.ServerLatencySec, e.TraceID, e.SpanMultiplier)
+	clientHistogram.ObserveWithExemplar(registryLabelValues, e.ClientLatencySec, e.TraceID, e.SpanMultiplier)
 
 	if p.Cfg.EnableMessagingSystemLatencyHistogram && e.ConnectionType == store.MessagingSystem {
 		messagingSystemLatencySec := unixNanosDiffSec(e.ClientEndTimeUnixNano, e.ServerStartTimeUnixNano)
@@ -385,6 +443,16 @@ func (p *Processor) onComplete(e *store.Edge) {
 	}
 }
 
+// latencyHistograms returns the server and client latency histograms for edges of the given connection type.
+// Connection types without their own buckets share the default histograms.
+func (p *Processor) latencyHistograms(connectionType store.ConnectionType) (server, client registry.Histogram) {
+	server, ok := p.serviceGraphRequestServerSecondsByConnectionType[connectionType]
+	if !ok {
+		return p.serviceGraphRequestServerSecondsHistogram, p.serviceGraphRequestClientSecondsHistogram
+	}
+	return server, p.serviceGraphRequestClientSecondsByConnectionType[connectionType]
+}
+
 func (p *Processor) onExpire(e *store.Edge) {
 	p.metricExpiredEdges.Inc()
 
@@ -18,6 +18,7 @@ import (
 
 	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
 )
 
 // NOTE: This is a way to know if the contents of the semconv package have changed.
@@ -182,6 +183,69 @@ func TestServiceGraphs_MessagingSystemLatencyHistogram(t *testing.T) {
 	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_messaging_system_seconds_count`, requesterToRecorderLabels))
 }
 
+func TestServiceGraphs_connectionTypeHistogramBuckets(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+
+	cfg.HistogramBuckets = []float64{0.04}
+	cfg.DatabaseHistogramBuckets = []float64{0.01, 0.05}
+	cfg.MessagingSystemHistogramBuckets = []float64{0.0001, 0.001}
+	cfg.Dimensions = []string{"beast", "god"}
+	cfg.EnableMessagingSystemLatencyHistogram = true
+
+	p := New(cfg, "test", testRegistry, log.NewNopLogger())
+	defer p.Shutdown(context.Background())
+
+	request, err := loadTestData("testdata/trace-with-queue-database.json")
+	require.NoError(t, err)
+
+	p.PushSpans(context.Background(), request)
+
+	requesterToServerLabels := labels.FromMap(map[string]string{
+		"client":          "mythical-requester",
+		"server":          "mythical-server",
+		"connection_type": "",
+		"beast":           "manticore",
+		"god":             "zeus",
+	})
+	serverToDatabaseLabels := labels.FromMap(map[string]string{
+		"client":          "mythical-server",
+		"server":          "postgres",
+		"connection_type": "database",
+		"beast":           "",
+		"god":             "",
+	})
+	requesterToRecorderLabels := labels.FromMap(map[string]string{
+		"client":          "mythical-requester",
+		"server":          "mythical-recorder",
+		"connection_type": "messaging_system",
+		"beast":           "",
+		"god":             "",
+	})
+
+	// edges without their own buckets use the default histograms
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_server_seconds_bucket`, withLe(requesterToServerLabels, 0.04)))
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_server_seconds_count`, requesterToServerLabels))
+
+	// database edges
+	assert.Equal(t, 0.0, testRegistry.Query(`traces_service_graph_request_server_seconds_count`, serverToDatabaseLabels))
+	assert.Equal(t, 0.0, testRegistry.Query(`traces_service_graph_database_request_server_seconds_bucket`, withLe(serverToDatabaseLabels, 0.01)))
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_database_request_server_seconds_bucket`, withLe(serverToDatabaseLabels, 0.05)))
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_database_request_server_seconds_count`, serverToDatabaseLabels))
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_database_request_client_seconds_count`, serverToDatabaseLabels))
+
+	// messaging system edges
+	assert.Equal(t, 0.0, testRegistry.Query(`traces_service_graph_request_client_seconds_count`, requesterToRecorderLabels))
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_messaging_system_request_client_seconds_bucket`, withLe(requesterToRecorderLabels, 0.0001)))
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_messaging_system_request_client_seconds_count`, requesterToRecorderLabels))
+	assert.Equal(t, 0.0, testRegistry.Query(`traces_service_graph_messaging_system_request_server_seconds_bucket`, withLe(requesterToRecorderLabels, 0.0001)))
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_messaging_system_request_server_seconds_bucket`, withLe(requesterToRecorderLabels, 0.001)))
+	assert.Equal(t, 0.0, testRegistry.Query(`traces_service_graph_request_messaging_system_seconds_bucket`, withLe(requesterToRecorderLabels, 0.001)))
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_messaging_system_seconds_count`, requesterToRecorderLabels))
+}
+
 func TestServiceGraphs_failedRequests(t *testing.T) {
 	testRegistry := registry.NewTestRegistry()
 
@@ -484,6 +548,120 @@ func TestServiceGraphs_prefixDimensionsAndEnableExtraLabels(t *testing.T) {
 	assert.Equal(t, 0.0, testRegistry.Query(`traces_service_graph_request_failed_total`, dbSystemSystemLabels))
 }
 
+func TestServiceGraphs_virtualNodeNaming(t *testing.T) {
+	cases := []struct {
+		name                   string
+		fixturePath            string
+		databaseNameAttributes []string
+		template               string
+		expectedLabels         labels.Labels
+	}{
+		{
+			name:                   "database name attributes",
+			fixturePath:            "testdata/trace-with-queue-database4.json",
+			databaseNameAttributes: []string{"db.missing", "db.system"},
+			expectedLabels: labels.FromMap(map[string]string{
+				"client":          "mythical-server",
+				"server":          "postgresql",
+				"connection_type": "database",
+			}),
+		},
+		{
+			name:                   "database name attributes fall back to db.name",
+			fixturePath:            "testdata/trace-with-queue-database4.json",
+			databaseNameAttributes: []string{"db.missing"},
+			expectedLabels: labels.FromMap(map[string]string{
+				"client":          "mythical-server",
+				"server":          "postgres",
+				"connection_type": "database",
+			}),
+		},
+		{
+			name:        "database template",
+			fixturePath: "testdata/trace-with-queue-database4.json",
+			template:    "{db.system}/{db.name}",
+			expectedLabels: labels.FromMap(map[string]string{
+				"client":          "mythical-server",
+				"server":          "postgresql/postgres",
+				"connection_type": "database",
+			}),
+		},
+		{
+			name:        "database template with missing attribute",
+			fixturePath: "testdata/trace-with-queue-database4.json",
+			template:    "{db.system}/{db.missing}",
+			expectedLabels: labels.FromMap(map[string]string{
+				"client":          "mythical-server",
+				"server":          "mythical-database:5432",
+				"connection_type": "database",
+			}),
+		},
+		{
+			name:        "peer template",
+			fixturePath: "testdata/trace-with-virtual-nodes.json",
+			template:    "external/{peer.service}",
+			expectedLabels: labels.FromMap(map[string]string{
+				"client":          "mythical-requester",
+				"server":          "external/external-payments-platform",
+				"connection_type": "virtual_node",
+			}),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			testRegistry := registry.NewTestRegistry()
+
+			cfg := Config{}
+			cfg.RegisterFlagsAndApplyDefaults("", nil)
+
+			cfg.Wait = time.Nanosecond
+			cfg.DatabaseNameAttributes = tc.databaseNameAttributes
+			cfg.VirtualNodeNameTemplate = tc.template
+
+			p := New(cfg, "test", testRegistry, log.NewNopLogger())
+			defer p.Shutdown(context.Background())
+
+			request, err := loadTestData(tc.fixturePath)
+			require.NoError(t, err)
+
+			p.PushSpans(context.Background(), request)
+			p.(*Processor).store.Expire()
+
+			assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_total`, tc.expectedLabels))
+		})
+	}
+}
+
+func TestParseNodeNameTemplate(t *testing.T) {
+	attrs := []*v1_common.KeyValue{
+		{Key: "db.system", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "postgresql"}}},
+		{Key: "db.name", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "users"}}},
+	}
+
+	cases := []struct {
+		template string
+		expected string
+		ok       bool
+	}{
+		{template: "{db.system}", expected: "postgresql", ok: true},
+		{template: "{db.system}://{db.name}", expected: "postgresql://users", ok: true},
+		{template: "db-{db.name}-primary", expected: "db-users-primary", ok: true},
+		{template: "{db.system}/{db.name", expected: "postgresql/{db.name", ok: true},
+		{template: "{db.system}/{server.address}", ok: false},
+	}
+
+	require.Nil(t, parseNodeNameTemplate(""))
+
+	for _, tc := range cases {
+		t.Run(tc.template, func(t *testing.T) {
+			name, ok := parseNodeNameTemplate(tc.template).render(attrs)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.expected, name)
+		})
+	}
+}
+
 func BenchmarkServiceGraphs(b *testing.B) {
 	testRegistry := registry.NewTestRegistry()
 
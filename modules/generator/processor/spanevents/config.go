@@ -0,0 +1,45 @@
+package spanevents
+
+import (
+	"flag"
+
+	"github.com/grafana/tempo/pkg/sharedconfig"
+)
+
+const (
+	Name = "span-events"
+
+	dimService    = "service"
+	dimSpanName   = "span_name"
+	dimSpanKind   = "span_kind"
+	dimStatusCode = "status_code"
+	dimEventName  = "event_name"
+)
+
+type Config struct {
+	// EventNames are the names of the span events that are counted. All events are counted if empty.
+	EventNames []string `yaml:"event_names"`
+	// Intrinsic dimensions (labels) added to the metric, that are generated from fixed span data. The
+	// dimensions service and span_name are enabled by default. The event name is always added.
+	IntrinsicDimensions IntrinsicDimensions `yaml:"intrinsic_dimensions"`
+	// Additional dimensions (labels) to be added to the metric. The dimensions are generated from the
+	// event attributes, or the span and resource attributes if the event doesn't have the attribute.
+	Dimensions []string `yaml:"dimensions"`
+	// Dimension label mapping to allow the user to rename attributes in their metrics
+	DimensionMappings []sharedconfig.DimensionMappings `yaml:"dimension_mappings"`
+
+	// If enabled attribute value will be used for metric calculation
+	SpanMultiplierKey string `yaml:"span_multiplier_key"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
+	cfg.IntrinsicDimensions.Service = true
+	cfg.IntrinsicDimensions.SpanName = true
+}
+
+type IntrinsicDimensions struct {
+	Service    bool `yaml:"service"`
+	SpanName   bool `yaml:"span_name"`
+	SpanKind   bool `yaml:"span_kind"`
+	StatusCode bool `yaml:"status_code"`
+}
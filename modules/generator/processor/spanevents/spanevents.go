@@ -0,0 +1,147 @@
+package spanevents
+
+import (
+	"context"
+	"slices"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/prometheus/util/strutil"
+
+	gen "github.com/grafana/tempo/modules/generator/processor"
+	processor_util "github.com/grafana/tempo/modules/generator/processor/util"
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+const (
+	metricEventsTotal = "traces_spanevents_total"
+)
+
+// Processor counts the events of spans, for example the exceptions recorded by the OpenTelemetry SDKs.
+type Processor struct {
+	Cfg Config
+
+	registry registry.Registry
+
+	spanEventsTotal registry.Counter
+	labels          []string
+}
+
+func New(cfg Config, registry registry.Registry) gen.Processor {
+	labels := make([]string, 0, 5+len(cfg.Dimensions)+len(cfg.DimensionMappings))
+
+	if cfg.IntrinsicDimensions.Service {
+		labels = append(labels, dimService)
+	}
+	if cfg.IntrinsicDimensions.SpanName {
+		labels = append(labels, dimSpanName)
+	}
+	if cfg.IntrinsicDimensions.SpanKind {
+		labels = append(labels, dimSpanKind)
+	}
+	if cfg.IntrinsicDimensions.StatusCode {
+		labels = append(labels, dimStatusCode)
+	}
+	labels = append(labels, dimEventName)
+
+	for _, d := range cfg.Dimensions {
+		labels = append(labels, sanitizeLabelNameWithCollisions(d))
+	}
+
+	for _, m := range cfg.DimensionMappings {
+		labels = append(labels, sanitizeLabelNameWithCollisions(m.Name))
+	}
+
+	return &Processor{
+		Cfg:             cfg,
+		registry:        registry,
+		spanEventsTotal: registry.NewCounter(metricEventsTotal),
+		labels:          labels,
+	}
+}
+
+func (p *Processor) Name() string {
+	return Name
+}
+
+func (p *Processor) PushSpans(ctx context.Context, req *tempopb.PushSpansRequest) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "spanevents.PushSpans")
+	defer span.Finish()
+
+	for _, rs := range req.Batches {
+		svcName, _ := processor_util.FindServiceName(rs.Resource.Attributes)
+
+		for _, ils := range rs.ScopeSpans {
+			for _, span := range ils.Spans {
+				for _, event := range span.Events {
+					if len(p.Cfg.EventNames) > 0 && !slices.Contains(p.Cfg.EventNames, event.Name) {
+						continue
+					}
+					p.aggregateMetricsForEvent(svcName, rs.Resource.Attributes, span, event)
+				}
+			}
+		}
+	}
+}
+
+func (p *Processor) Shutdown(context.Context) {
+}
+
+func (p *Processor) aggregateMetricsForEvent(svcName string, resourceAttributes []*v1_common.KeyValue, span *v1_trace.Span, event *v1_trace.Span_Event) {
+	labelValues := make([]string, 0, len(p.labels))
+
+	// important: the order of labelValues must correspond to the order of labels / intrinsic dimensions
+	if p.Cfg.IntrinsicDimensions.Service {
+		labelValues = append(labelValues, svcName)
+	}
+	if p.Cfg.IntrinsicDimensions.SpanName {
+		labelValues = append(labelValues, span.GetName())
+	}
+	if p.Cfg.IntrinsicDimensions.SpanKind {
+		labelValues = append(labelValues, span.GetKind().String())
+	}
+	if p.Cfg.IntrinsicDimensions.StatusCode {
+		labelValues = append(labelValues, span.GetStatus().GetCode().String())
+	}
+	labelValues = append(labelValues, event.GetName())
+
+	// event attributes take precedence over span and resource attributes
+	for _, d := range p.Cfg.Dimensions {
+		value, _ := processor_util.FindAttributeValue(d, event.Attributes, span.Attributes, resourceAttributes)
+		labelValues = append(labelValues, value)
+	}
+
+	for _, m := range p.Cfg.DimensionMappings {
+		values := ""
+		for _, s := range m.SourceLabel {
+			if value, _ := processor_util.FindAttributeValue(s, event.Attributes, span.Attributes, resourceAttributes); value != "" {
+				if values == "" {
+					values += value
+				} else {
+					values = values + m.Join + value
+				}
+			}
+		}
+		labelValues = append(labelValues, values)
+	}
+
+	spanMultiplier := processor_util.GetSpanMultiplier(p.Cfg.SpanMultiplierKey, span)
+
+	p.spanEventsTotal.Inc(p.registry.NewLabelValueCombo(p.labels, labelValues), 1*spanMultiplier)
+}
+
+func sanitizeLabelNameWithCollisions(name string) string {
+	sanitized := strutil.SanitizeLabelName(name)
+
+	if isIntrinsicDimension(sanitized) {
+		return "__" + sanitized
+	}
+
+	return sanitized
+}
+
+func isIntrinsicDimension(name string) bool {
+	return processor_util.Contains(name, []string{dimService, dimSpanName, dimSpanKind, dimStatusCode, dimEventName})
+}
@@ -0,0 +1,156 @@
+package spanevents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/sharedconfig"
+	"github.com/grafana/tempo/pkg/tempopb"
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	resource_v1 "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	trace_v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestSpanEvents(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	p := New(cfg, testRegistry)
+	defer p.Shutdown(context.Background())
+
+	require.Equal(t, p.Name(), "span-events")
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{makeBatch()}})
+
+	assert.Equal(t, 2.0, testRegistry.Query("traces_spanevents_total", labels.FromMap(map[string]string{
+		"service":    "test-service",
+		"span_name":  "test",
+		"event_name": "exception",
+	})))
+	assert.Equal(t, 1.0, testRegistry.Query("traces_spanevents_total", labels.FromMap(map[string]string{
+		"service":    "test-service",
+		"span_name":  "test",
+		"event_name": "retry",
+	})))
+}
+
+func TestSpanEventsFilterAndDimensions(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.IntrinsicDimensions.SpanName = false
+	cfg.IntrinsicDimensions.StatusCode = true
+	cfg.EventNames = []string{"exception"}
+	cfg.Dimensions = []string{"exception.type", "service.name"}
+	cfg.DimensionMappings = []sharedconfig.DimensionMappings{
+		{
+			Name:        "error",
+			SourceLabel: []string{"exception.type", "exception.message"},
+			Join:        ": ",
+		},
+	}
+	p := New(cfg, testRegistry)
+	defer p.Shutdown(context.Background())
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{makeBatch()}})
+
+	assert.Equal(t, 1.0, testRegistry.Query("traces_spanevents_total", labels.FromMap(map[string]string{
+		"service":        "test-service",
+		"status_code":    "STATUS_CODE_ERROR",
+		"event_name":     "exception",
+		"exception_type": "TimeoutError",
+		"service_name":   "test-service",
+		"error":          "TimeoutError: deadline exceeded",
+	})))
+	assert.Equal(t, 1.0, testRegistry.Query("traces_spanevents_total", labels.FromMap(map[string]string{
+		"service":        "test-service",
+		"status_code":    "STATUS_CODE_ERROR",
+		"event_name":     "exception",
+		"exception_type": "IOError",
+		"service_name":   "test-service",
+		"error":          "IOError",
+	})))
+	assert.Equal(t, 0.0, testRegistry.Query("traces_spanevents_total", labels.FromMap(map[string]string{
+		"service":        "test-service",
+		"status_code":    "STATUS_CODE_ERROR",
+		"event_name":     "retry",
+		"exception_type": "",
+		"service_name":   "test-service",
+		"error":          "",
+	})))
+}
+
+func TestSpanEventsSpanMultiplier(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.SpanMultiplierKey = "X-SampleRatio"
+	p := New(cfg, testRegistry)
+	defer p.Shutdown(context.Background())
+
+	batch := makeBatch()
+	batch.ScopeSpans[0].Spans[0].Attributes = append(batch.ScopeSpans[0].Spans[0].Attributes, &common_v1.KeyValue{
+		Key:   "X-SampleRatio",
+		Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_DoubleValue{DoubleValue: 0.2}},
+	})
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{batch}})
+
+	assert.Equal(t, 10.0, testRegistry.Query("traces_spanevents_total", labels.FromMap(map[string]string{
+		"service":    "test-service",
+		"span_name":  "test",
+		"event_name": "exception",
+	})))
+	assert.Equal(t, 5.0, testRegistry.Query("traces_spanevents_total", labels.FromMap(map[string]string{
+		"service":    "test-service",
+		"span_name":  "test",
+		"event_name": "retry",
+	})))
+}
+
+func makeBatch() *trace_v1.ResourceSpans {
+	return &trace_v1.ResourceSpans{
+		Resource: &resource_v1.Resource{
+			Attributes: []*common_v1.KeyValue{stringKV("service.name", "test-service")},
+		},
+		ScopeSpans: []*trace_v1.ScopeSpans{{
+			Spans: []*trace_v1.Span{{
+				Name:   "test",
+				Kind:   trace_v1.Span_SPAN_KIND_SERVER,
+				Status: &trace_v1.Status{Code: trace_v1.Status_STATUS_CODE_ERROR},
+				Events: []*trace_v1.Span_Event{
+					{
+						Name: "exception",
+						Attributes: []*common_v1.KeyValue{
+							stringKV("exception.type", "TimeoutError"),
+							stringKV("exception.message", "deadline exceeded"),
+						},
+					},
+					{
+						Name:       "retry",
+						Attributes: []*common_v1.KeyValue{stringKV("attempt", "1")},
+					},
+					{
+						Name:       "exception",
+						Attributes: []*common_v1.KeyValue{stringKV("exception.type", "IOError")},
+					},
+				},
+			}},
+		}},
+	}
+}
+
+func stringKV(key, value string) *common_v1.KeyValue {
+	return &common_v1.KeyValue{
+		Key:   key,
+		Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: value}},
+	}
+}
@@ -12,6 +12,10 @@ import (
 const (
 	Name = "span-metrics"
 
+	// DefaultTargetInfoMetricName is prefixed to not collide with the target_info metric of the
+	// OpenTelemetry SDKs and collectors.
+	DefaultTargetInfoMetricName = "traces_target_info"
+
 	dimService       = "service"
 	dimSpanName      = "span_name"
 	dimSpanKind      = "span_kind"
@@ -19,6 +23,9 @@ const (
 	dimStatusMessage = "status_message"
 	dimJob           = "job"
 	dimInstance      = "instance"
+
+	dimTraceRootName    = "trace_root_name"
+	dimTraceRootService = "trace_root_service"
 )
 
 type Config struct {
@@ -26,8 +33,12 @@ type Config struct {
 	HistogramBuckets []float64 `yaml:"histogram_buckets"`
 	// Intrinsic dimensions (labels) added to the metric, that are generated from fixed span
 	// data. The dimensions service, span_name, span_kind, status_code, job and instance are enabled by
-	// default, whereas the dimension status_message must be enabled explicitly.
+	// default, whereas the dimensions status_message, trace_root_name and trace_root_service must be enabled
+	// explicitly.
 	IntrinsicDimensions IntrinsicDimensions `yaml:"intrinsic_dimensions"`
+	// Intrinsic dimensions enabled or disabled for a single subprocessor (span-metrics-latency, span-metrics-count
+	// or span-metrics-size) on top of IntrinsicDimensions.
+	SubprocessorIntrinsicDimensions map[string]map[string]bool `yaml:"subprocessor_intrinsic_dimensions"`
 	// Additional dimensions (labels) to be added to the metric. The dimensions are generated
 	// from span attributes and are created along with the intrinsic dimensions.
 	Dimensions []string `yaml:"dimensions"`
@@ -35,6 +46,9 @@ type Config struct {
 	DimensionMappings []sharedconfig.DimensionMappings `yaml:"dimension_mappings"`
 	// Enable target_info as a metrics
 	EnableTargetInfo bool `yaml:"enable_target_info"`
+	// Name of the target info metric. Set it to target_info to join the span metrics with the metrics
+	// of the same job and instance that are written by OpenTelemetry SDKs and collectors.
+	TargetInfoMetricName string `yaml:"target_info_metric_name"`
 
 	// If enabled attribute value will be used for metric calculation
 	SpanMultiplierKey string `yaml:"span_multiplier_key"`
@@ -52,6 +66,7 @@ type Config struct {
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 	cfg.HistogramBuckets = prometheus.ExponentialBuckets(0.002, 2, 14)
+	cfg.TargetInfoMetricName = DefaultTargetInfoMetricName
 	cfg.IntrinsicDimensions.Service = true
 	cfg.IntrinsicDimensions.SpanName = true
 	cfg.IntrinsicDimensions.SpanKind = true
@@ -62,12 +77,44 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 	cfg.Subprocessors[Size] = true
 }
 
+// IntrinsicDimensionsFor returns the intrinsic dimensions of the given subprocessor.
+func (cfg *Config) IntrinsicDimensionsFor(sp Subprocessor) (IntrinsicDimensions, error) {
+	dims := cfg.IntrinsicDimensions
+	if err := dims.ApplyFromMap(cfg.SubprocessorIntrinsicDimensions[sp.String()]); err != nil {
+		return IntrinsicDimensions{}, fmt.Errorf("invalid intrinsic dimensions for %s: %w", sp, err)
+	}
+	return dims, nil
+}
+
+// ApplySubprocessorIntrinsicDimensions sets the intrinsic dimensions toggled per subprocessor after validating them.
+func (cfg *Config) ApplySubprocessorIntrinsicDimensions(dimensions map[string]map[string]bool) error {
+	for name, toggles := range dimensions {
+		if !isSubprocessorName(name) {
+			return fmt.Errorf("%s is not a valid span metrics subprocessor", name)
+		}
+		var dims IntrinsicDimensions
+		if err := dims.ApplyFromMap(toggles); err != nil {
+			return fmt.Errorf("invalid intrinsic dimensions for %s: %w", name, err)
+		}
+	}
+
+	cfg.SubprocessorIntrinsicDimensions = dimensions
+	return nil
+}
+
 type IntrinsicDimensions struct {
-	Service       bool `yaml:"service"`
-	SpanName      bool `yaml:"span_name"`
-	SpanKind      bool `yaml:"span_kind"`
-	StatusCode    bool `yaml:"status_code"`
-	StatusMessage bool `yaml:"status_message,omitempty"`
+	Service          bool `yaml:"service"`
+	SpanName         bool `yaml:"span_name"`
+	SpanKind         bool `yaml:"span_kind"`
+	StatusCode       bool `yaml:"status_code"`
+	StatusMessage    bool `yaml:"status_message,omitempty"`
+	TraceRootName    bool `yaml:"trace_root_name,omitempty"`
+	TraceRootService bool `yaml:"trace_root_service,omitempty"`
+}
+
+// traceRoot returns whether the dimensions need the root span of the trace.
+func (ic IntrinsicDimensions) traceRoot() bool {
+	return ic.TraceRootName || ic.TraceRootService
 }
 
 func (ic *IntrinsicDimensions) ApplyFromMap(dimensions map[string]bool) error {
@@ -83,6 +130,10 @@ func (ic *IntrinsicDimensions) ApplyFromMap(dimensions map[string]bool) error {
 			ic.StatusCode = active
 		case dimStatusMessage:
 			ic.StatusMessage = active
+		case dimTraceRootName:
+			ic.TraceRootName = active
+		case dimTraceRootService:
+			ic.TraceRootService = active
 		default:
 			return fmt.Errorf("%s is not a valid intrinsic dimension", label)
 		}
@@ -22,7 +22,6 @@ const (
 	metricCallsTotal      = "traces_spanmetrics_calls_total"
 	metricDurationSeconds = "traces_spanmetrics_latency"
 	metricSizeTotal       = "traces_spanmetrics_size_total"
-	targetInfo            = "traces_target_info"
 )
 
 type Processor struct {
@@ -34,7 +33,11 @@ type Processor struct {
 	spanMetricsDurationSeconds registry.Histogram
 	spanMetricsSizeTotal       registry.Counter
 	spanMetricsTargetInfo      registry.Gauge
-	labels                     []string
+
+	// subprocessors with the same intrinsic dimensions share a dimension group and its label values
+	dimensionGroups    []dimensionGroup
+	subprocessorGroups map[Subprocessor]int
+	traceRootDims      bool
 
 	filter               *spanfilter.SpanFilter
 	filteredSpansCounter prometheus.Counter
@@ -43,42 +46,44 @@ type Processor struct {
 	now func() time.Time
 }
 
-func New(cfg Config, registry registry.Registry, spanDiscardCounter prometheus.Counter) (gen.Processor, error) {
-	labels := make([]string, 0, 4+len(cfg.Dimensions))
-
-	if cfg.IntrinsicDimensions.Service {
-		labels = append(labels, dimService)
-	}
-	if cfg.IntrinsicDimensions.SpanName {
-		labels = append(labels, dimSpanName)
-	}
-	if cfg.IntrinsicDimensions.SpanKind {
-		labels = append(labels, dimSpanKind)
-	}
-	if cfg.IntrinsicDimensions.StatusCode {
-		labels = append(labels, dimStatusCode)
-	}
-	if cfg.IntrinsicDimensions.StatusMessage {
-		labels = append(labels, dimStatusMessage)
-	}
+type dimensionGroup struct {
+	intrinsicDimensions IntrinsicDimensions
+	labels              []string
+}
 
-	for _, d := range cfg.Dimensions {
-		labels = append(labels, sanitizeLabelNameWithCollisions(d))
-	}
+// traceRoot is the root span of a trace.
+type traceRoot struct {
+	name    string
+	service string
+}
 
-	for _, m := range cfg.DimensionMappings {
-		labels = append(labels, sanitizeLabelNameWithCollisions(m.Name))
+func New(cfg Config, registry registry.Registry, spanDiscardCounter prometheus.Counter) (gen.Processor, error) {
+	targetInfoName := cfg.TargetInfoMetricName
+	if targetInfoName == "" {
+		targetInfoName = DefaultTargetInfoMetricName
 	}
 
 	p := &Processor{
 		Cfg:                   cfg,
 		registry:              registry,
-		spanMetricsTargetInfo: registry.NewGauge(targetInfo),
+		spanMetricsTargetInfo: registry.NewGauge(targetInfoName),
 		now:                   time.Now,
-		labels:                labels,
+		subprocessorGroups:    make(map[Subprocessor]int),
 		filteredSpansCounter:  spanDiscardCounter,
 	}
 
+	for _, sp := range SupportedSubprocessors {
+		if !cfg.Subprocessors[sp] {
+			continue
+		}
+		dims, err := cfg.IntrinsicDimensionsFor(sp)
+		if err != nil {
+			return nil, err
+		}
+		p.subprocessorGroups[sp] = p.dimensionGroup(dims)
+		p.traceRootDims = p.traceRootDims || dims.traceRoot()
+	}
+
 	if cfg.Subprocessors[Latency] {
 		p.spanMetricsDurationSeconds = registry.NewHistogram(metricDurationSeconds, cfg.HistogramBuckets)
 	}
@@ -99,6 +104,50 @@ func New(cfg Config, registry registry.Registry, spanDiscardCounter prometheus.C
 	return p, nil
 }
 
+// dimensionGroup returns the index of the dimension group with the given intrinsic dimensions, adding it if needed.
+func (p *Processor) dimensionGroup(dims IntrinsicDimensions) int {
+	for i, g := range p.dimensionGroups {
+		if g.intrinsicDimensions == dims {
+			return i
+		}
+	}
+
+	labels := make([]string, 0, 7+len(p.Cfg.Dimensions)+len(p.Cfg.DimensionMappings))
+
+	if dims.Service {
+		labels = append(labels, dimService)
+	}
+	if dims.SpanName {
+		labels = append(labels, dimSpanName)
+	}
+	if dims.SpanKind {
+		labels = append(labels, dimSpanKind)
+	}
+	if dims.StatusCode {
+		labels = append(labels, dimStatusCode)
+	}
+	if dims.StatusMessage {
+		labels = append(labels, dimStatusMessage)
+	}
+	if dims.TraceRootName {
+		labels = append(labels, dimTraceRootName)
+	}
+	if dims.TraceRootService {
+		labels = append(labels, dimTraceRootService)
+	}
+
+	for _, d := range p.Cfg.Dimensions {
+		labels = append(labels, sanitizeLabelNameWithCollisions(d))
+	}
+
+	for _, m := range p.Cfg.DimensionMappings {
+		labels = append(labels, sanitizeLabelNameWithCollisions(m.Name))
+	}
+
+	p.dimensionGroups = append(p.dimensionGroups, dimensionGroup{intrinsicDimensions: dims, labels: labels})
+	return len(p.dimensionGroups) - 1
+}
+
 func (p *Processor) Name() string {
 	return Name
 }
@@ -114,6 +163,11 @@ func (p *Processor) Shutdown(_ context.Context) {
 }
 
 func (p *Processor) aggregateMetrics(resourceSpans []*v1_trace.ResourceSpans) {
+	var roots map[string]traceRoot
+	if p.traceRootDims {
+		roots = findTraceRoots(resourceSpans)
+	}
+
 	for _, rs := range resourceSpans {
 		// already extract job name & instance id, so we only have to do it once per batch of spans
 		svcName, _ := processor_util.FindServiceName(rs.Resource.Attributes)
@@ -128,7 +182,7 @@ func (p *Processor) aggregateMetrics(resourceSpans []*v1_trace.ResourceSpans) {
 		for _, ils := range rs.ScopeSpans {
 			for _, span := range ils.Spans {
 				if p.filter.ApplyFilterPolicy(rs.Resource, span) {
-					p.aggregateMetricsForSpan(svcName, jobName, instanceID, rs.Resource, span, resourceLabels, resourceValues)
+					p.aggregateMetricsForSpan(svcName, jobName, instanceID, rs.Resource, span, roots[string(span.TraceId)], resourceLabels, resourceValues)
 					continue
 				}
 				p.filteredSpansCounter.Inc()
@@ -137,41 +191,39 @@ func (p *Processor) aggregateMetrics(resourceSpans []*v1_trace.ResourceSpans) {
 	}
 }
 
-func (p *Processor) aggregateMetricsForSpan(svcName string, jobName string, instanceID string, rs *v1.Resource, span *v1_trace.Span, resourceLabels []string, resourceValues []string) {
+// findTraceRoots returns the root spans of the traces in the batches by trace ID. The spans of a trace whose root span
+// is not part of the same batches have empty trace root dimensions.
+func findTraceRoots(resourceSpans []*v1_trace.ResourceSpans) map[string]traceRoot {
+	roots := make(map[string]traceRoot)
+	for _, rs := range resourceSpans {
+		svcName, _ := processor_util.FindServiceName(rs.Resource.Attributes)
+		for _, ils := range rs.ScopeSpans {
+			for _, span := range ils.Spans {
+				if len(span.ParentSpanId) == 0 {
+					roots[string(span.TraceId)] = traceRoot{name: span.GetName(), service: svcName}
+				}
+			}
+		}
+	}
+	return roots
+}
+
+func (p *Processor) aggregateMetricsForSpan(svcName string, jobName string, instanceID string, rs *v1.Resource, span *v1_trace.Span, root traceRoot, resourceLabels []string, resourceValues []string) {
 	// Spans with negative latency are treated as zero.
 	latencySeconds := 0.0
 	if start, end := span.GetStartTimeUnixNano(), span.GetEndTimeUnixNano(); start < end {
 		latencySeconds = float64(end-start) / float64(time.Second.Nanoseconds())
 	}
 
-	labelValues := make([]string, 0, 4+len(p.Cfg.Dimensions))
+	attributeValues := make([]string, 0, len(p.Cfg.Dimensions)+len(p.Cfg.DimensionMappings))
 	targetInfoLabelValues := make([]string, len(resourceLabels))
-	labels := make([]string, len(p.labels))
 	targetInfoLabels := make([]string, len(resourceLabels))
-	copy(labels, p.labels)
 	copy(targetInfoLabels, resourceLabels)
 	copy(targetInfoLabelValues, resourceValues)
 
-	// important: the order of labelValues must correspond to the order of labels / intrinsic dimensions
-	if p.Cfg.IntrinsicDimensions.Service {
-		labelValues = append(labelValues, svcName)
-	}
-	if p.Cfg.IntrinsicDimensions.SpanName {
-		labelValues = append(labelValues, span.GetName())
-	}
-	if p.Cfg.IntrinsicDimensions.SpanKind {
-		labelValues = append(labelValues, span.GetKind().String())
-	}
-	if p.Cfg.IntrinsicDimensions.StatusCode {
-		labelValues = append(labelValues, span.GetStatus().GetCode().String())
-	}
-	if p.Cfg.IntrinsicDimensions.StatusMessage {
-		labelValues = append(labelValues, span.GetStatus().GetMessage())
-	}
-
 	for _, d := range p.Cfg.Dimensions {
 		value, _ := processor_util.FindAttributeValue(d, rs.Attributes, span.Attributes)
-		labelValues = append(labelValues, value)
+		attributeValues = append(attributeValues, value)
 	}
 
 	for _, m := range p.Cfg.DimensionMappings {
@@ -185,34 +237,65 @@ func (p *Processor) aggregateMetricsForSpan(svcName string, jobName string, inst
 				}
 			}
 		}
-		labelValues = append(labelValues, values)
+		attributeValues = append(attributeValues, values)
 	}
 
-	// add job label only if job is not blank
-	if jobName != "" && p.Cfg.EnableTargetInfo {
-		labels = append(labels, dimJob)
-		labelValues = append(labelValues, jobName)
-	}
-	//  add instance label only if job is not blank
-	if instanceID != "" && p.Cfg.EnableTargetInfo {
-		labels = append(labels, dimInstance)
-		labelValues = append(labelValues, instanceID)
+	registryLabelValues := make([]*registry.LabelValueCombo, len(p.dimensionGroups))
+	for i, g := range p.dimensionGroups {
+		labels := make([]string, len(g.labels), len(g.labels)+2)
+		copy(labels, g.labels)
+		labelValues := make([]string, 0, len(g.labels)+2)
+
+		// important: the order of labelValues must correspond to the order of labels / intrinsic dimensions
+		if g.intrinsicDimensions.Service {
+			labelValues = append(labelValues, svcName)
+		}
+		if g.intrinsicDimensions.SpanName {
+			labelValues = append(labelValues, span.GetName())
+		}
+		if g.intrinsicDimensions.SpanKind {
+			labelValues = append(labelValues, span.GetKind().String())
+		}
+		if g.intrinsicDimensions.StatusCode {
+			labelValues = append(labelValues, span.GetStatus().GetCode().String())
+		}
+		if g.intrinsicDimensions.StatusMessage {
+			labelValues = append(labelValues, span.GetStatus().GetMessage())
+		}
+		if g.intrinsicDimensions.TraceRootName {
+			labelValues = append(labelValues, root.name)
+		}
+		if g.intrinsicDimensions.TraceRootService {
+			labelValues = append(labelValues, root.service)
+		}
+		labelValues = append(labelValues, attributeValues...)
+
+		// add job label only if job is not blank
+		if jobName != "" && p.Cfg.EnableTargetInfo {
+			labels = append(labels, dimJob)
+			labelValues = append(labelValues, jobName)
+		}
+		//  add instance label only if job is not blank
+		if instanceID != "" && p.Cfg.EnableTargetInfo {
+			labels = append(labels, dimInstance)
+			labelValues = append(labelValues, instanceID)
+		}
+
+		registryLabelValues[i] = p.registry.NewLabelValueCombo(labels, labelValues)
 	}
 
 	spanMultiplier := processor_util.GetSpanMultiplier(p.Cfg.SpanMultiplierKey, span)
 
-	registryLabelValues := p.registry.NewLabelValueCombo(labels, labelValues)
-
 	if p.Cfg.Subprocessors[Count] {
-		p.spanMetricsCallsTotal.Inc(registryLabelValues, 1*spanMultiplier)
+		p.spanMetricsCallsTotal.Inc(registryLabelValues[p.subprocessorGroups[Count]], 1*spanMultiplier)
 	}
 
 	if p.Cfg.Subprocessors[Latency] {
-		p.spanMetricsDurationSeconds.ObserveWithExemplar(registryLabelValues, latencySeconds, tempo_util.TraceIDToHexString(span.TraceId), spanMultiplier)
+		p.spanMetricsDurationSeconds.ObserveWithExemplar(registryLabelValues[p.subprocessorGroups[Latency]], latencySeconds, tempo_util.TraceIDToHexString(span.TraceId), spanMultiplier)
 	}
 
 	if p.Cfg.Subprocessors[Size] {
-		p.spanMetricsSizeTotal.Inc(registryLabelValues, float64(span.Size()))
+		p.spanMetricsSizeTotal.Inc(registryLabelValues[p.subprocessorGroups[Size]], float64(span.Size()))
 	}
 
 	// update target_info label values
@@ -254,5 +337,5 @@ func sanitizeLabelNameWithCollisions(name string) string {
 }
 
 func isIntrinsicDimension(name string) bool {
-	return processor_util.Contains(name, []string{dimJob, dimSpanName, dimSpanKind, dimStatusCode, dimStatusMessage, dimInstance})
+	return processor_util.Contains(name, []string{dimJob, dimSpanName, dimSpanKind, dimStatusCode, dimStatusMessage, dimInstance, dimTraceRootName, dimTraceRootService})
 }
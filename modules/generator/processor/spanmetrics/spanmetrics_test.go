@@ -581,6 +581,42 @@ func TestTargetInfoEnabled(t *testing.T) {
 	assert.Equal(t, 1.0, testRegistry.Query("traces_target_info", lbls))
 }
 
+func TestTargetInfoMetricName(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+	filteredSpansCounter := metricSpansDiscarded.WithLabelValues("test-tenant", "filtered")
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.EnableTargetInfo = true
+	cfg.TargetInfoMetricName = "target_info"
+	cfg.HistogramBuckets = []float64{0.5, 1}
+
+	p, err := New(cfg, testRegistry, filteredSpansCounter)
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	batch := test.MakeBatch(10, nil)
+	batch.Resource.Attributes = append(batch.Resource.Attributes, &common_v1.KeyValue{
+		Key:   "service.instance.id",
+		Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: "abc-instance-id-test-def"}},
+	})
+	batch.Resource.Attributes = append(batch.Resource.Attributes, &common_v1.KeyValue{
+		Key:   "cluster",
+		Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: "eu-west-0"}},
+	})
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{batch}})
+
+	lbls := labels.FromMap(map[string]string{
+		"job":      "test-service",
+		"instance": "abc-instance-id-test-def",
+		"cluster":  "eu-west-0",
+	})
+
+	assert.Equal(t, 1.0, testRegistry.Query("target_info", lbls))
+	assert.False(t, strings.Contains(fmt.Sprint(testRegistry), "traces_target_info"))
+}
+
 func TestTargetInfoDisabled(t *testing.T) {
 	testRegistry := registry.NewTestRegistry()
 	filteredSpansCounter := metricSpansDiscarded.WithLabelValues("test-tenant", "filtered")
@@ -1065,6 +1101,127 @@ func TestSpanMetricsNegativeLatency(t *testing.T) {
 	require.Equal(t, 0.0, testRegistry.Query("traces_spanmetrics_latency_sum", lbls), "sum")
 }
 
+func TestSpanMetrics_traceRootDimensions(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+	filteredSpansCounter := metricSpansDiscarded.WithLabelValues("test-tenant", "filtered")
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.HistogramBuckets = []float64{0.5, 1}
+	cfg.IntrinsicDimensions.SpanKind = false
+	cfg.IntrinsicDimensions.TraceRootName = true
+	cfg.IntrinsicDimensions.TraceRootService = true
+
+	p, err := New(cfg, testRegistry, filteredSpansCounter)
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	// the root span is pushed in another batch than the other spans of its trace
+	root := test.MakeBatch(1, []byte{0x01})
+	root.Resource.Attributes[0].Value = &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: "root-service"}}
+	root.ScopeSpans[0].Spans[0].Name = "root"
+	root.ScopeSpans[0].Spans[0].ParentSpanId = nil
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{
+		test.MakeBatch(5, []byte{0x01}),
+		root,
+		test.MakeBatch(2, []byte{0x02}),
+	}})
+
+	rootLbls := labels.FromMap(map[string]string{
+		"service":            "root-service",
+		"span_name":          "root",
+		"status_code":        "STATUS_CODE_OK",
+		"trace_root_name":    "root",
+		"trace_root_service": "root-service",
+	})
+	childLbls := labels.FromMap(map[string]string{
+		"service":            "test-service",
+		"span_name":          "test",
+		"status_code":        "STATUS_CODE_OK",
+		"trace_root_name":    "root",
+		"trace_root_service": "root-service",
+	})
+	noRootLbls := labels.FromMap(map[string]string{
+		"service":            "test-service",
+		"span_name":          "test",
+		"status_code":        "STATUS_CODE_OK",
+		"trace_root_name":    "",
+		"trace_root_service": "",
+	})
+
+	assert.Equal(t, 1.0, testRegistry.Query("traces_spanmetrics_calls_total", rootLbls))
+	assert.Equal(t, 5.0, testRegistry.Query("traces_spanmetrics_calls_total", childLbls))
+	assert.Equal(t, 2.0, testRegistry.Query("traces_spanmetrics_calls_total", noRootLbls))
+	assert.Equal(t, 5.0, testRegistry.Query("traces_spanmetrics_latency_count", childLbls))
+}
+
+func TestSpanMetrics_subprocessorIntrinsicDimensions(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+	filteredSpansCounter := metricSpansDiscarded.WithLabelValues("test-tenant", "filtered")
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.HistogramBuckets = []float64{0.5, 1}
+	require.NoError(t, cfg.ApplySubprocessorIntrinsicDimensions(map[string]map[string]bool{
+		Latency.String(): {"span_kind": false, "status_code": false},
+		Size.String():    {"status_message": true},
+	}))
+
+	p, err := New(cfg, testRegistry, filteredSpansCounter)
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	batch := test.MakeBatch(10, nil)
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{batch}})
+
+	lbls := labels.FromMap(map[string]string{
+		"service":     "test-service",
+		"span_name":   "test",
+		"span_kind":   "SPAN_KIND_CLIENT",
+		"status_code": "STATUS_CODE_OK",
+	})
+	latencyLbls := labels.FromMap(map[string]string{
+		"service":   "test-service",
+		"span_name": "test",
+	})
+	sizeLbls := labels.FromMap(map[string]string{
+		"service":        "test-service",
+		"span_name":      "test",
+		"span_kind":      "SPAN_KIND_CLIENT",
+		"status_code":    "STATUS_CODE_OK",
+		"status_message": "OK",
+	})
+
+	assert.Equal(t, 10.0, testRegistry.Query("traces_spanmetrics_calls_total", lbls))
+	assert.Equal(t, 10.0, testRegistry.Query("traces_spanmetrics_latency_count", latencyLbls))
+	assert.Equal(t, 0.0, testRegistry.Query("traces_spanmetrics_latency_count", lbls))
+	assert.Less(t, 0.0, testRegistry.Query("traces_spanmetrics_size_total", sizeLbls))
+	assert.Equal(t, 0.0, testRegistry.Query("traces_spanmetrics_size_total", lbls))
+}
+
+func TestConfig_ApplySubprocessorIntrinsicDimensions(t *testing.T) {
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+
+	require.EqualError(t, cfg.ApplySubprocessorIntrinsicDimensions(map[string]map[string]bool{"span-metrics-foo": {"span_kind": false}}),
+		"span-metrics-foo is not a valid span metrics subprocessor")
+	require.EqualError(t, cfg.ApplySubprocessorIntrinsicDimensions(map[string]map[string]bool{Count.String(): {"foo": false}}),
+		"invalid intrinsic dimensions for span-metrics-count: foo is not a valid intrinsic dimension")
+	require.Nil(t, cfg.SubprocessorIntrinsicDimensions)
+
+	cfg.IntrinsicDimensions.StatusMessage = true
+	require.NoError(t, cfg.ApplySubprocessorIntrinsicDimensions(map[string]map[string]bool{Count.String(): {"span_kind": false}}))
+
+	dims, err := cfg.IntrinsicDimensionsFor(Count)
+	require.NoError(t, err)
+	require.Equal(t, IntrinsicDimensions{Service: true, SpanName: true, StatusCode: true, StatusMessage: true}, dims)
+
+	dims, err = cfg.IntrinsicDimensionsFor(Latency)
+	require.NoError(t, err)
+	require.Equal(t, cfg.IntrinsicDimensions, dims)
+}
+
 func withLe(lbls labels.Labels, le float64) labels.Labels {
 	lb := labels.NewBuilder(lbls)
 	lb = lb.Set(labels.BucketLabel, strconv.FormatFloat(le, 'f', -1, 64))
@@ -39,3 +39,12 @@ func ParseSubprocessor(s string) bool {
 	}
 	return false
 }
+
+func isSubprocessorName(s string) bool {
+	for _, p := range SupportedSubprocessors {
+		if p.String() == s {
+			return true
+		}
+	}
+	return false
+}
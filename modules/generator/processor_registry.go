@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"sync"
+	"time"
+
+	"github.com/grafana/tempo/modules/generator/registry"
+)
+
+// processorRegistry wraps the registry of an instance and keeps track of the metrics created by a
+// single processor. When the processor is removed all its metrics are removed from the registry
+// immediately instead of lingering until they become stale. If staleDuration is set the series of
+// the processor become stale after that duration instead of the stale duration of the registry.
+type processorRegistry struct {
+	registry      *registry.ManagedRegistry
+	staleDuration func() time.Duration
+
+	mtx         sync.Mutex
+	metricNames []string
+}
+
+var _ registry.Registry = (*processorRegistry)(nil)
+
+func newProcessorRegistry(r *registry.ManagedRegistry, staleDuration func() time.Duration) *processorRegistry {
+	return &processorRegistry{
+		registry:      r,
+		staleDuration: staleDuration,
+	}
+}
+
+func (p *processorRegistry) NewLabelValueCombo(labels []string, values []string) *registry.LabelValueCombo {
+	return p.registry.NewLabelValueCombo(labels, values)
+}
+
+func (p *processorRegistry) NewCounter(name string) registry.Counter {
+	p.track(name)
+	c := p.registry.NewCounter(name)
+	p.setStaleDuration(name)
+	return c
+}
+
+func (p *processorRegistry) NewHistogram(name string, buckets []float64) registry.Histogram {
+	p.track(name)
+	h := p.registry.NewHistogram(name, buckets)
+	p.setStaleDuration(name)
+	return h
+}
+
+func (p *processorRegistry) NewGauge(name string) registry.Gauge {
+	p.track(name)
+	g := p.registry.NewGauge(name)
+	p.setStaleDuration(name)
+	return g
+}
+
+func (p *processorRegistry) track(name string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.metricNames = append(p.metricNames, name)
+}
+
+// setStaleDuration sets the stale duration of the metric, it must be called after the metric is created.
+func (p *processorRegistry) setStaleDuration(name string) {
+	if p.staleDuration != nil {
+		p.registry.SetStaleDuration(name, p.staleDuration)
+	}
+}
+
+// unregisterAll removes all metrics created through this registry.
+func (p *processorRegistry) unregisterAll() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for _, name := range p.metricNames {
+		p.registry.UnregisterMetric(name)
+	}
+	p.metricNames = nil
+}
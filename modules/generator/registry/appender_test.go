@@ -43,6 +43,7 @@ func (n noopAppender) UpdateMetadata(storage.SeriesRef, labels.Labels, metadata.
 
 type capturingAppender struct {
 	samples      []sample
+	histograms   []histogramSample
 	exemplars    []exemplarSample
 	isCommitted  bool
 	isRolledback bool
@@ -54,6 +55,12 @@ type sample struct {
 	v float64
 }
 
+type histogramSample struct {
+	l labels.Labels
+	t int64
+	h *prom_histogram.Histogram
+}
+
 type exemplarSample struct {
 	l labels.Labels
 	e exemplar.Exemplar
@@ -101,7 +108,8 @@ func (c *capturingAppender) AppendExemplar(ref storage.SeriesRef, l labels.Label
 	return ref, nil
 }
 
-func (c *capturingAppender) AppendHistogram(ref storage.SeriesRef, _ labels.Labels, _ int64, _ *prom_histogram.Histogram, _ *prom_histogram.FloatHistogram) (storage.SeriesRef, error) {
+func (c *capturingAppender) AppendHistogram(ref storage.SeriesRef, l labels.Labels, t int64, h *prom_histogram.Histogram, _ *prom_histogram.FloatHistogram) (storage.SeriesRef, error) {
+	c.histograms = append(c.histograms, histogramSample{l, t, h})
 	return ref, nil
 }
 
@@ -174,6 +174,21 @@ func (c *counter) collectMetrics(appender storage.Appender, timeMs int64, extern
 	return
 }
 
+func (c *counter) removeSeries(match func(LabelPair) bool) int {
+	c.seriesMtx.Lock()
+	defer c.seriesMtx.Unlock()
+
+	removed := 0
+	for hash, s := range c.series {
+		if match(s.labels) {
+			delete(c.series, hash)
+			c.onRemoveSeries(1)
+			removed++
+		}
+	}
+	return removed
+}
+
 func (c *counter) removeStaleSeries(staleTimeMs int64) {
 	c.seriesMtx.Lock()
 	defer c.seriesMtx.Unlock()
@@ -168,6 +168,21 @@ func (g *gauge) collectMetrics(appender storage.Appender, timeMs int64, external
 	return
 }
 
+func (g *gauge) removeSeries(match func(LabelPair) bool) int {
+	g.seriesMtx.Lock()
+	defer g.seriesMtx.Unlock()
+
+	removed := 0
+	for hash, s := range g.series {
+		if match(s.labels) {
+			delete(g.series, hash)
+			g.onRemoveSeries(1)
+			removed++
+		}
+	}
+	return removed
+}
+
 func (g *gauge) removeStaleSeries(staleTimeMs int64) {
 	g.seriesMtx.Lock()
 	defer g.seriesMtx.Unlock()
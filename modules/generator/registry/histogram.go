@@ -217,6 +217,21 @@ func (h *histogram) collectMetrics(appender storage.Appender, timeMs int64, exte
 	return
 }
 
+func (h *histogram) removeSeries(match func(LabelPair) bool) int {
+	h.seriesMtx.Lock()
+	defer h.seriesMtx.Unlock()
+
+	removed := 0
+	for hash, s := range h.series {
+		if match(s.labels) {
+			delete(h.series, hash)
+			h.onRemoveSerie(h.activeSeriesPerHistogramSerie())
+			removed++
+		}
+	}
+	return removed
+}
+
 func (h *histogram) removeStaleSeries(staleTimeMs int64) {
 	h.seriesMtx.Lock()
 	defer h.seriesMtx.Unlock()
@@ -44,6 +44,16 @@ type LabelPair struct {
 	values []string
 }
 
+// value returns the value of the label with the given name, or an empty string if there is no such label.
+func (l LabelPair) value(name string) string {
+	for i, n := range l.names {
+		if n == name {
+			return l.values[i]
+		}
+	}
+	return ""
+}
+
 func newLabelPair(labels []string, values []string) LabelPair {
 	return LabelPair{
 		names:  labels,
@@ -0,0 +1,363 @@
+package registry
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/exemplar"
+	prom_histogram "github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+)
+
+const (
+	defaultNativeHistogramBucketFactor    = 1.1
+	defaultNativeHistogramMaxBucketNumber = 160
+)
+
+// nativeHistogram is a histogram with exponential buckets. The buckets follow the schema of the
+// Prometheus client, but observations are weighted so a span multiplier is a single update. If classic
+// is set, classic histograms are generated next to the native histograms.
+type nativeHistogram struct {
+	metricName string
+	classic    *histogram
+
+	seriesMtx sync.Mutex
+	series    map[uint64]*nativeHistogramSeries
+
+	onAddSerie    func(count uint32) bool
+	onRemoveSerie func(count uint32)
+
+	// settings returns the bucket factor and max bucket number, it's called for every new series so
+	// changes to the overrides are picked up without recreating the histogram.
+	settings func() (bucketFactor float64, maxBucketNumber uint32)
+
+	// metricSchema is set to the lowest schema of all series after every collection
+	metricSchema prometheus.Gauge
+
+	traceIDLabelName string
+}
+
+type nativeHistogramSeries struct {
+	// labels should not be modified after creation
+	labels LabelPair
+
+	schema          int32
+	maxBucketNumber uint32
+	count           uint64
+	sum             float64
+	zeroCount       uint64
+	positive        map[int32]uint64
+	negative        map[int32]uint64
+
+	// exemplar is the last observed traceID, it is cleared after every collection
+	exemplar      string
+	exemplarValue float64
+	lastUpdated   int64
+}
+
+var (
+	_ Histogram = (*nativeHistogram)(nil)
+	_ metric    = (*nativeHistogram)(nil)
+)
+
+func newNativeHistogram(name string, classic *histogram, onAddSeries func(uint32) bool, onRemoveSeries func(count uint32), settings func() (float64, uint32), metricSchema prometheus.Gauge, traceIDLabelName string) *nativeHistogram {
+	if onAddSeries == nil {
+		onAddSeries = func(uint32) bool {
+			return true
+		}
+	}
+	if onRemoveSeries == nil {
+		onRemoveSeries = func(uint32) {}
+	}
+
+	if traceIDLabelName == "" {
+		traceIDLabelName = "traceID"
+	}
+
+	return &nativeHistogram{
+		metricName:       name,
+		classic:          classic,
+		series:           make(map[uint64]*nativeHistogramSeries),
+		onAddSerie:       onAddSeries,
+		onRemoveSerie:    onRemoveSeries,
+		settings:         settings,
+		metricSchema:     metricSchema,
+		traceIDLabelName: traceIDLabelName,
+	}
+}
+
+// ObserveWithExemplar observes value and stores traceID as exemplar. Native histograms only hold
+// integer counts, so multiplier is rounded to the nearest integer (with a minimum of 1) and used as the
+// weight of the observation.
+func (h *nativeHistogram) ObserveWithExemplar(labelValueCombo *LabelValueCombo, value float64, traceID string, multiplier float64) {
+	if h.classic != nil {
+		h.classic.ObserveWithExemplar(labelValueCombo, value, traceID, multiplier)
+	}
+
+	hash := labelValueCombo.getHash()
+
+	h.seriesMtx.Lock()
+	defer h.seriesMtx.Unlock()
+
+	s, ok := h.series[hash]
+	if ok {
+		h.updateSeries(s, value, traceID, multiplier)
+		return
+	}
+
+	if !h.onAddSerie(1) {
+		return
+	}
+
+	s = h.newSeries(labelValueCombo)
+	h.updateSeries(s, value, traceID, multiplier)
+	h.series[hash] = s
+}
+
+func (h *nativeHistogram) newSeries(labelValueCombo *LabelValueCombo) *nativeHistogramSeries {
+	bucketFactor, maxBucketNumber := h.settings()
+	if bucketFactor <= 1 {
+		bucketFactor = defaultNativeHistogramBucketFactor
+	}
+	if maxBucketNumber == 0 {
+		maxBucketNumber = defaultNativeHistogramMaxBucketNumber
+	}
+
+	return &nativeHistogramSeries{
+		labels:          labelValueCombo.getLabelPair(),
+		schema:          pickNativeHistogramSchema(bucketFactor),
+		maxBucketNumber: maxBucketNumber,
+		positive:        make(map[int32]uint64),
+		negative:        make(map[int32]uint64),
+	}
+}
+
+func (h *nativeHistogram) updateSeries(s *nativeHistogramSeries, value float64, traceID string, multiplier float64) {
+	weight := uint64(math.Round(multiplier))
+	if weight < 1 {
+		weight = 1
+	}
+	s.observe(value, weight)
+
+	s.exemplar = traceID
+	s.exemplarValue = value
+	s.lastUpdated = time.Now().UnixMilli()
+}
+
+func (h *nativeHistogram) name() string {
+	return h.metricName
+}
+
+func (h *nativeHistogram) collectMetrics(appender storage.Appender, timeMs int64, externalLabels map[string]string) (activeSeries int, err error) {
+	if h.classic != nil {
+		activeSeries, err = h.classic.collectMetrics(appender, timeMs, externalLabels)
+		if err != nil {
+			return
+		}
+	}
+
+	h.seriesMtx.Lock()
+	defer h.seriesMtx.Unlock()
+
+	activeSeries += len(h.series)
+
+	lb := labels.NewBuilder(labels.EmptyLabels())
+	for name, value := range externalLabels {
+		lb.Set(name, value)
+	}
+	lb.Set(labels.MetricName, h.metricName)
+	baseLabels := lb.Labels()
+
+	minSchema := int32(math.MaxInt32)
+
+	for _, s := range h.series {
+		lb.Reset(baseLabels)
+		for i, name := range s.labels.names {
+			lb.Set(name, s.labels.values[i])
+		}
+		lbls := lb.Labels()
+
+		hist := s.toPromHistogram()
+		minSchema = min(minSchema, hist.Schema)
+
+		var ref storage.SeriesRef
+		ref, err = appender.AppendHistogram(0, lbls, timeMs, hist, nil)
+		if err != nil {
+			return
+		}
+
+		if s.exemplar != "" {
+			_, err = appender.AppendExemplar(ref, lbls, exemplar.Exemplar{
+				Labels: []labels.Label{{
+					Name:  h.traceIDLabelName,
+					Value: s.exemplar,
+				}},
+				Value: s.exemplarValue,
+				Ts:    timeMs,
+			})
+			if err != nil {
+				return
+			}
+			// clear the exemplar so we don't emit it again
+			s.exemplar = ""
+		}
+	}
+
+	if len(h.series) > 0 && h.metricSchema != nil {
+		h.metricSchema.Set(float64(minSchema))
+	}
+
+	return
+}
+
+func (h *nativeHistogram) removeSeries(match func(LabelPair) bool) int {
+	// the classic histogram has the same series, only count them once
+	if h.classic != nil {
+		h.classic.removeSeries(match)
+	}
+
+	h.seriesMtx.Lock()
+	defer h.seriesMtx.Unlock()
+
+	removed := 0
+	for hash, s := range h.series {
+		if match(s.labels) {
+			delete(h.series, hash)
+			h.onRemoveSerie(1)
+			removed++
+		}
+	}
+	return removed
+}
+
+func (h *nativeHistogram) removeStaleSeries(staleTimeMs int64) {
+	if h.classic != nil {
+		h.classic.removeStaleSeries(staleTimeMs)
+	}
+
+	h.seriesMtx.Lock()
+	defer h.seriesMtx.Unlock()
+
+	for hash, s := range h.series {
+		if s.lastUpdated < staleTimeMs {
+			delete(h.series, hash)
+			h.onRemoveSerie(1)
+		}
+	}
+}
+
+// observe adds value to its bucket with the given weight. If the series holds more buckets than
+// allowed afterwards, the resolution is reduced like the Prometheus client does.
+func (s *nativeHistogramSeries) observe(value float64, weight uint64) {
+	s.count += weight
+	s.sum += value * float64(weight)
+
+	switch {
+	case math.Abs(value) <= prometheus.DefNativeHistogramZeroThreshold:
+		s.zeroCount += weight
+	case value > 0:
+		s.positive[nativeHistogramBucketKey(value, s.schema)] += weight
+	default:
+		s.negative[nativeHistogramBucketKey(-value, s.schema)] += weight
+	}
+
+	for uint32(len(s.positive)+len(s.negative)) > s.maxBucketNumber && s.schema > -4 {
+		s.schema--
+		s.positive = halveResolution(s.positive)
+		s.negative = halveResolution(s.negative)
+	}
+}
+
+func (s *nativeHistogramSeries) toPromHistogram() *prom_histogram.Histogram {
+	h := &prom_histogram.Histogram{
+		Schema:        s.schema,
+		ZeroThreshold: prometheus.DefNativeHistogramZeroThreshold,
+		ZeroCount:     s.zeroCount,
+		Count:         s.count,
+		Sum:           s.sum,
+	}
+	h.PositiveSpans, h.PositiveBuckets = toPromBuckets(s.positive)
+	h.NegativeSpans, h.NegativeBuckets = toPromBuckets(s.negative)
+	return h
+}
+
+// pickNativeHistogramSchema returns the highest schema with a growth factor between two buckets of at
+// most bucketFactor, see prometheus.HistogramOpts.NativeHistogramBucketFactor.
+func pickNativeHistogramSchema(bucketFactor float64) int32 {
+	floor := math.Floor(math.Log2(math.Log2(bucketFactor)))
+	switch {
+	case floor <= -8:
+		return 8
+	case floor >= 4:
+		return -4
+	default:
+		return -int32(floor)
+	}
+}
+
+// nativeHistogramBucketKey returns the index of the bucket of the positive value v. Bucket i of schema s
+// holds the values in (2^((i-1)/2^s), 2^(i/2^s)].
+func nativeHistogramBucketKey(v float64, schema int32) int32 {
+	frac, exp := math.Frexp(v)
+	if schema > 0 {
+		if frac == 0.5 {
+			// powers of two are the upper bound of their bucket
+			return int32(exp-1) << schema
+		}
+		return int32(exp)<<schema + int32(math.Ceil(math.Log2(frac)*float64(int32(1)<<schema)))
+	}
+
+	key := int32(exp)
+	if frac == 0.5 {
+		key--
+	}
+	offset := int32(1)<<-schema - 1
+	return (key + offset) >> -schema
+}
+
+// halveResolution merges every two neighbouring buckets into the bucket of the next lower schema.
+func halveResolution(buckets map[int32]uint64) map[int32]uint64 {
+	merged := make(map[int32]uint64, len(buckets))
+	for key, count := range buckets {
+		merged[(key+1)>>1] += count
+	}
+	return merged
+}
+
+// toPromBuckets converts the bucket counts into spans of consecutive buckets and delta encoded counts,
+// the format expected by the appender.
+func toPromBuckets(buckets map[int32]uint64) ([]prom_histogram.Span, []int64) {
+	if len(buckets) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]int32, 0, len(buckets))
+	for key := range buckets {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var spans []prom_histogram.Span
+	deltas := make([]int64, 0, len(keys))
+	var prevKey int32
+	var prevCount int64
+	for i, key := range keys {
+		switch {
+		case i == 0:
+			spans = append(spans, prom_histogram.Span{Offset: key, Length: 1})
+		case key == prevKey+1:
+			spans[len(spans)-1].Length++
+		default:
+			spans = append(spans, prom_histogram.Span{Offset: key - prevKey - 1, Length: 1})
+		}
+
+		count := int64(buckets[key])
+		deltas = append(deltas, count-prevCount)
+		prevKey, prevCount = key, count
+	}
+	return spans, deltas
+}
@@ -0,0 +1,260 @@
+package registry
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/prometheus/model/exemplar"
+	prom_histogram "github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nativeHistogramSettings(bucketFactor float64, maxBucketNumber uint32) func() (float64, uint32) {
+	return func() (float64, uint32) {
+		return bucketFactor, maxBucketNumber
+	}
+}
+
+func Test_nativeHistogram(t *testing.T) {
+	var seriesAdded int
+	onAdd := func(count uint32) bool {
+		seriesAdded += int(count)
+		return true
+	}
+	schema := prometheus.NewGauge(prometheus.GaugeOpts{Name: "schema"})
+
+	h := newNativeHistogram("my_histogram", nil, onAdd, nil, nativeHistogramSettings(0, 0), schema, "trace_id")
+
+	h.ObserveWithExemplar(newLabelValueCombo([]string{"label"}, []string{"value-1"}), 1.0, "trace-1", 1.0)
+	h.ObserveWithExemplar(newLabelValueCombo([]string{"label"}, []string{"value-2"}), 1.5, "trace-2", 2.0)
+	h.ObserveWithExemplar(newLabelValueCombo([]string{"label"}, []string{"value-2"}), 0.5, "trace-3", 0.1)
+
+	assert.Equal(t, 2, seriesAdded)
+
+	appender := &capturingAppender{}
+	collectionTimeMs := time.Now().UnixMilli()
+
+	activeSeries, err := h.collectMetrics(appender, collectionTimeMs, map[string]string{"cluster": "test"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, activeSeries)
+	assert.Empty(t, appender.samples)
+	require.Len(t, appender.histograms, 2)
+
+	counts := map[string]uint64{}
+	sums := map[string]float64{}
+	for _, s := range appender.histograms {
+		assert.Equal(t, "my_histogram", s.l.Get(labels.MetricName))
+		assert.Equal(t, "test", s.l.Get("cluster"))
+		assert.Equal(t, collectionTimeMs, s.t)
+		// the default bucket factor of 1.1 results in schema 3
+		assert.Equal(t, int32(3), s.h.Schema)
+
+		counts[s.l.Get("label")] = s.h.Count
+		sums[s.l.Get("label")] = s.h.Sum
+	}
+	// the multiplier is rounded but at least 1
+	assert.Equal(t, map[string]uint64{"value-1": 1, "value-2": 3}, counts)
+	assert.Equal(t, map[string]float64{"value-1": 1.0, "value-2": 3.5}, sums)
+	assert.Equal(t, 3.0, testutil.ToFloat64(schema))
+
+	assert.ElementsMatch(t, []exemplarSample{
+		newExemplar(map[string]string{"__name__": "my_histogram", "cluster": "test", "label": "value-1"}, exemplar.Exemplar{
+			Labels: labels.FromMap(map[string]string{"trace_id": "trace-1"}),
+			Value:  1.0,
+			Ts:     collectionTimeMs,
+		}),
+		newExemplar(map[string]string{"__name__": "my_histogram", "cluster": "test", "label": "value-2"}, exemplar.Exemplar{
+			Labels: labels.FromMap(map[string]string{"trace_id": "trace-3"}),
+			Value:  0.5,
+			Ts:     collectionTimeMs,
+		}),
+	}, appender.exemplars)
+
+	// exemplars are only emitted once
+	appender = &capturingAppender{}
+	_, err = h.collectMetrics(appender, collectionTimeMs, nil)
+	require.NoError(t, err)
+	assert.Len(t, appender.histograms, 2)
+	assert.Empty(t, appender.exemplars)
+}
+
+func Test_nativeHistogram_bucketSettings(t *testing.T) {
+	schema := prometheus.NewGauge(prometheus.GaugeOpts{Name: "schema"})
+	settings := nativeHistogramSettings(1.02, 0)
+
+	h := newNativeHistogram("my_histogram", nil, nil, nil, func() (float64, uint32) { return settings() }, schema, "")
+
+	// a bucket factor of 1.02 results in schema 6
+	h.ObserveWithExemplar(newLabelValueCombo([]string{"label"}, []string{"value-1"}), 1.0, "", 1.0)
+
+	// new series pick up changes to the settings, a low max bucket number forces the schema down
+	settings = nativeHistogramSettings(1.02, 2)
+	lvc := newLabelValueCombo([]string{"label"}, []string{"value-2"})
+	for v := 1.05; v < 2; v += 0.1 {
+		h.ObserveWithExemplar(lvc, v, "", 1.0)
+	}
+
+	appender := &capturingAppender{}
+	_, err := h.collectMetrics(appender, time.Now().UnixMilli(), nil)
+	require.NoError(t, err)
+	require.Len(t, appender.histograms, 2)
+
+	for _, s := range appender.histograms {
+		switch s.l.Get("label") {
+		case "value-1":
+			assert.Equal(t, int32(6), s.h.Schema)
+		case "value-2":
+			assert.Less(t, s.h.Schema, int32(6))
+			buckets := 0
+			for _, span := range s.h.PositiveSpans {
+				buckets += int(span.Length)
+			}
+			assert.LessOrEqual(t, buckets, 2)
+		}
+	}
+
+	// the lowest schema of all series is exposed
+	assert.Less(t, testutil.ToFloat64(schema), 6.0)
+}
+
+func Test_nativeHistogram_weightedObservation(t *testing.T) {
+	h := newNativeHistogram("my_histogram", nil, nil, nil, nativeHistogramSettings(0, 0), nil, "")
+
+	// a large multiplier is a single weighted observation
+	h.ObserveWithExemplar(newLabelValueCombo([]string{"label"}, []string{"value-1"}), 1.5, "", 1e6)
+
+	appender := &capturingAppender{}
+	_, err := h.collectMetrics(appender, time.Now().UnixMilli(), nil)
+	require.NoError(t, err)
+	require.Len(t, appender.histograms, 1)
+
+	hist := appender.histograms[0].h
+	assert.Equal(t, uint64(1e6), hist.Count)
+	assert.Equal(t, 1.5e6, hist.Sum)
+	assert.Equal(t, []int64{1e6}, hist.PositiveBuckets)
+}
+
+func Test_nativeHistogram_bucketsMatchPrometheusClient(t *testing.T) {
+	values := []float64{-3, -0.25, 0, 1e-9, 0.1, 0.5, 1, 1.05, 1.5, 2, 3, 4, 1000, 1e9}
+
+	for _, schema := range []int32{-4, -1, 0, 1, 3, 6, 8} {
+		bucketFactor := math.Pow(2, math.Pow(2, -float64(schema)))
+		h := newNativeHistogram("my_histogram", nil, nil, nil, nativeHistogramSettings(bucketFactor, 1000), nil, "")
+		client := prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:                           "my_histogram",
+			NativeHistogramBucketFactor:    bucketFactor,
+			NativeHistogramMaxBucketNumber: 1000,
+		})
+
+		for _, v := range values {
+			h.ObserveWithExemplar(newLabelValueCombo([]string{"label"}, []string{"value-1"}), v, "", 1.0)
+			client.Observe(v)
+		}
+
+		appender := &capturingAppender{}
+		_, err := h.collectMetrics(appender, time.Now().UnixMilli(), nil)
+		require.NoError(t, err)
+		require.Len(t, appender.histograms, 1)
+
+		encoded := &dto.Metric{}
+		require.NoError(t, client.Write(encoded))
+
+		actual := appender.histograms[0].h
+		expected := encoded.GetHistogram()
+		assert.Equal(t, expected.GetSchema(), actual.Schema)
+		assert.Equal(t, expected.GetZeroCount(), actual.ZeroCount)
+		assert.Equal(t, bucketCounts(t, expected.GetPositiveSpan(), expected.GetPositiveDelta()), bucketCounts(t, toDtoSpans(actual.PositiveSpans), actual.PositiveBuckets), "schema %d", schema)
+		assert.Equal(t, bucketCounts(t, expected.GetNegativeSpan(), expected.GetNegativeDelta()), bucketCounts(t, toDtoSpans(actual.NegativeSpans), actual.NegativeBuckets), "schema %d", schema)
+	}
+}
+
+// bucketCounts returns the non-empty buckets by index of delta encoded buckets.
+func bucketCounts(t *testing.T, spans []*dto.BucketSpan, deltas []int64) map[int32]int64 {
+	counts := map[int32]int64{}
+	var key int32
+	var count int64
+	i := 0
+	for _, span := range spans {
+		key += span.GetOffset()
+		for j := uint32(0); j < span.GetLength(); j++ {
+			require.Less(t, i, len(deltas))
+			count += deltas[i]
+			if count != 0 {
+				counts[key] = count
+			}
+			key++
+			i++
+		}
+	}
+	return counts
+}
+
+func toDtoSpans(spans []prom_histogram.Span) []*dto.BucketSpan {
+	dtoSpans := make([]*dto.BucketSpan, 0, len(spans))
+	for _, s := range spans {
+		offset, length := s.Offset, s.Length
+		dtoSpans = append(dtoSpans, &dto.BucketSpan{Offset: &offset, Length: &length})
+	}
+	return dtoSpans
+}
+
+func Test_nativeHistogram_withClassic(t *testing.T) {
+	var seriesAdded int
+	onAdd := func(count uint32) bool {
+		seriesAdded += int(count)
+		return true
+	}
+
+	classic := newHistogram("my_histogram", []float64{1.0, 2.0}, onAdd, nil, "trace_id")
+	h := newNativeHistogram("my_histogram", classic, onAdd, nil, nativeHistogramSettings(0, 0), nil, "trace_id")
+
+	h.ObserveWithExemplar(newLabelValueCombo([]string{"label"}, []string{"value-1"}), 1.5, "trace-1", 1.0)
+
+	// sum + count + 3 buckets for the classic histogram, 1 for the native histogram
+	assert.Equal(t, 6, seriesAdded)
+
+	collectionTimeMs := time.Now().UnixMilli()
+	appender := &capturingAppender{}
+
+	activeSeries, err := h.collectMetrics(appender, collectionTimeMs, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 6, activeSeries)
+
+	assert.ElementsMatch(t, []sample{
+		newSample(map[string]string{"__name__": "my_histogram_count", "label": "value-1"}, collectionTimeMs, 1),
+		newSample(map[string]string{"__name__": "my_histogram_sum", "label": "value-1"}, collectionTimeMs, 1.5),
+		newSample(map[string]string{"__name__": "my_histogram_bucket", "label": "value-1", "le": "1"}, collectionTimeMs, 0),
+		newSample(map[string]string{"__name__": "my_histogram_bucket", "label": "value-1", "le": "2"}, collectionTimeMs, 1),
+		newSample(map[string]string{"__name__": "my_histogram_bucket", "label": "value-1", "le": "+Inf"}, collectionTimeMs, 1),
+	}, appender.samples)
+	require.Len(t, appender.histograms, 1)
+	assert.Equal(t, uint64(1), appender.histograms[0].h.Count)
+	assert.Len(t, appender.exemplars, 2)
+}
+
+func Test_nativeHistogram_removeStaleSeries(t *testing.T) {
+	var removedSeries int
+	onRemove := func(count uint32) {
+		removedSeries += int(count)
+	}
+
+	h := newNativeHistogram("my_histogram", nil, nil, onRemove, nativeHistogramSettings(0, 0), nil, "")
+
+	h.ObserveWithExemplar(newLabelValueCombo([]string{"label"}, []string{"value-1"}), 1.0, "", 1.0)
+	h.ObserveWithExemplar(newLabelValueCombo([]string{"label"}, []string{"value-2"}), 1.0, "", 1.0)
+
+	h.removeStaleSeries(math.MaxInt64)
+	assert.Equal(t, 2, removedSeries)
+
+	appender := &capturingAppender{}
+	activeSeries, err := h.collectMetrics(appender, time.Now().UnixMilli(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, activeSeries)
+	assert.Empty(t, appender.histograms)
+}
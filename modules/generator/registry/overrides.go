@@ -11,6 +11,9 @@ type Overrides interface {
 	MetricsGeneratorCollectionInterval(userID string) time.Duration
 	MetricsGeneratorDisableCollection(userID string) bool
 	MetricsGenerationTraceIDLabelName(userID string) string
+	MetricsGeneratorGenerateNativeHistograms(userID string) overrides.HistogramMethod
+	MetricsGeneratorNativeHistogramBucketFactor(userID string) float64
+	MetricsGeneratorNativeHistogramMaxBucketNumber(userID string) uint32
 }
 
 var _ Overrides = (overrides.Interface)(nil)
@@ -3,6 +3,7 @@ package registry
 import (
 	"context"
 	"fmt"
+	"math"
 	"os"
 	"sync"
 	"time"
@@ -11,9 +12,11 @@ import (
 	"github.com/go-kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/storage"
 	"go.uber.org/atomic"
 
+	"github.com/grafana/tempo/modules/overrides"
 	tempo_log "github.com/grafana/tempo/pkg/util/log"
 )
 
@@ -53,6 +56,11 @@ var (
 		Name:      "metrics_generator_registry_collections_failed_total",
 		Help:      "The total amount of failed metrics collections per tenant",
 	}, []string{"tenant"})
+	metricNativeHistogramSchema = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "metrics_generator_registry_native_histogram_schema",
+		Help:      "The lowest schema of all series of a native histogram per tenant. The schema is reduced when a series exceeds the max bucket number",
+	}, []string{"tenant", "metric"})
 )
 
 type ManagedRegistry struct {
@@ -63,9 +71,11 @@ type ManagedRegistry struct {
 	tenant         string
 	externalLabels map[string]string
 
-	metricsMtx   sync.RWMutex
-	metrics      map[string]metric
-	activeSeries atomic.Uint32
+	metricsMtx sync.RWMutex
+	metrics    map[string]metric
+	// staleDurations holds the stale duration of metrics that don't use the configured stale duration
+	staleDurations map[string]func() time.Duration
+	activeSeries   atomic.Uint32
 
 	appendable storage.Appendable
 
@@ -85,6 +95,8 @@ type metric interface {
 	name() string
 	collectMetrics(appender storage.Appender, timeMs int64, externalLabels map[string]string) (activeSeries int, err error)
 	removeStaleSeries(staleTimeMs int64)
+	// removeSeries removes the series for which match returns true and returns the number of removed series.
+	removeSeries(match func(LabelPair) bool) int
 }
 
 var _ Registry = (*ManagedRegistry)(nil)
@@ -113,7 +125,8 @@ func New(cfg *Config, overrides Overrides, tenant string, appendable storage.App
 		tenant:         tenant,
 		externalLabels: externalLabels,
 
-		metrics: map[string]metric{},
+		metrics:        map[string]metric{},
+		staleDurations: map[string]func() time.Duration{},
 
 		appendable: appendable,
 
@@ -148,11 +161,30 @@ func (r *ManagedRegistry) NewCounter(name string) Counter {
 }
 
 func (r *ManagedRegistry) NewHistogram(name string, buckets []float64) Histogram {
-	h := newHistogram(name, buckets, r.onAddMetricSeries, r.onRemoveMetricSeries, r.overrides.MetricsGenerationTraceIDLabelName(r.tenant))
+	traceIDLabelName := r.overrides.MetricsGenerationTraceIDLabelName(r.tenant)
+
+	var h interface {
+		Histogram
+		metric
+	}
+	switch r.overrides.MetricsGeneratorGenerateNativeHistograms(r.tenant) {
+	case overrides.HistogramMethodNative:
+		h = newNativeHistogram(name, nil, r.onAddMetricSeries, r.onRemoveMetricSeries, r.nativeHistogramSettings, metricNativeHistogramSchema.WithLabelValues(r.tenant, name), traceIDLabelName)
+	case overrides.HistogramMethodBoth:
+		classic := newHistogram(name, buckets, r.onAddMetricSeries, r.onRemoveMetricSeries, traceIDLabelName)
+		h = newNativeHistogram(name, classic, r.onAddMetricSeries, r.onRemoveMetricSeries, r.nativeHistogramSettings, metricNativeHistogramSchema.WithLabelValues(r.tenant, name), traceIDLabelName)
+	default:
+		h = newHistogram(name, buckets, r.onAddMetricSeries, r.onRemoveMetricSeries, traceIDLabelName)
+	}
+
 	r.registerMetric(h)
 	return h
 }
 
+func (r *ManagedRegistry) nativeHistogramSettings() (float64, uint32) {
+	return r.overrides.MetricsGeneratorNativeHistogramBucketFactor(r.tenant), r.overrides.MetricsGeneratorNativeHistogramMaxBucketNumber(r.tenant)
+}
+
 func (r *ManagedRegistry) NewGauge(name string) Gauge {
 	g := newGauge(name, r.onAddMetricSeries, r.onRemoveMetricSeries)
 	r.registerMetric(g)
@@ -169,6 +201,72 @@ func (r *ManagedRegistry) registerMetric(m metric) {
 	r.metrics[m.name()] = m
 }
 
+// UnregisterMetric removes the metric with the given name together with all its series. The metric
+// won't be collected anymore, creating a new metric with the same name starts from scratch.
+func (r *ManagedRegistry) UnregisterMetric(name string) {
+	r.metricsMtx.Lock()
+	m, ok := r.metrics[name]
+	delete(r.metrics, name)
+	delete(r.staleDurations, name)
+	r.metricsMtx.Unlock()
+
+	if !ok {
+		return
+	}
+
+	// every series is older than now, this removes all of them and updates the active series
+	m.removeStaleSeries(math.MaxInt64)
+	metricNativeHistogramSchema.DeleteLabelValues(r.tenant, name)
+}
+
+// SetStaleDuration sets the stale duration of the metric with the given name. staleDuration is evaluated every
+// time stale series are removed, if it returns 0 the configured stale duration is used.
+func (r *ManagedRegistry) SetStaleDuration(name string, staleDuration func() time.Duration) {
+	r.metricsMtx.Lock()
+	defer r.metricsMtx.Unlock()
+
+	r.staleDurations[name] = staleDuration
+}
+
+// RemoveSeries removes all series matching the given matchers immediately and returns the number of removed
+// series. The metric name can be matched with the __name__ label, series that don't have a label are matched
+// as if the label were empty.
+func (r *ManagedRegistry) RemoveSeries(matchers []*labels.Matcher) int {
+	r.metricsMtx.RLock()
+	defer r.metricsMtx.RUnlock()
+
+	var nameMatchers, labelMatchers []*labels.Matcher
+	for _, m := range matchers {
+		if m.Name == labels.MetricName {
+			nameMatchers = append(nameMatchers, m)
+		} else {
+			labelMatchers = append(labelMatchers, m)
+		}
+	}
+
+	removed := 0
+	for name, m := range r.metrics {
+		if !matchesAll(nameMatchers, func(string) string { return name }) {
+			continue
+		}
+		removed += m.removeSeries(func(lp LabelPair) bool {
+			return matchesAll(labelMatchers, lp.value)
+		})
+	}
+
+	level.Info(r.logger).Log("msg", "deleted series", "matchers", fmt.Sprint(matchers), "removed_series", removed, "active_series", r.activeSeries.Load())
+	return removed
+}
+
+func matchesAll(matchers []*labels.Matcher, value func(name string) string) bool {
+	for _, m := range matchers {
+		if !m.Matches(value(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
 func (r *ManagedRegistry) onAddMetricSeries(count uint32) bool {
 	maxActiveSeries := r.overrides.MetricsGeneratorMaxActiveSeries(r.tenant)
 	if maxActiveSeries != 0 && r.activeSeries.Load()+count > maxActiveSeries {
@@ -248,10 +346,17 @@ func (r *ManagedRegistry) removeStaleSeries(_ context.Context) {
 	r.metricsMtx.RLock()
 	defer r.metricsMtx.RUnlock()
 
-	timeMs := time.Now().Add(-1 * r.cfg.StaleDuration).UnixMilli()
+	now := time.Now()
+	timeMs := now.Add(-1 * r.cfg.StaleDuration).UnixMilli()
 
-	for _, m := range r.metrics {
-		m.removeStaleSeries(timeMs)
+	for name, m := range r.metrics {
+		staleTimeMs := timeMs
+		if staleDuration, ok := r.staleDurations[name]; ok {
+			if d := staleDuration(); d > 0 {
+				staleTimeMs = now.Add(-1 * d).UnixMilli()
+			}
+		}
+		m.removeStaleSeries(staleTimeMs)
 	}
 
 	level.Info(r.logger).Log("msg", "deleted stale series", "active_series", r.activeSeries.Load())
@@ -10,8 +10,11 @@ import (
 	"time"
 
 	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides"
 )
 
 func TestManagedRegistry_concurrency(*testing.T) {
@@ -93,6 +96,41 @@ func TestManagedRegistry_histogram(t *testing.T) {
 	collectRegistryMetricsAndAssert(t, registry, appender, expectedSamples)
 }
 
+func TestManagedRegistry_nativeHistogram(t *testing.T) {
+	for _, tc := range []struct {
+		method             overrides.HistogramMethod
+		expectedSamples    int
+		expectedHistograms int
+	}{
+		{method: "", expectedSamples: 5},
+		{method: overrides.HistogramMethodClassic, expectedSamples: 5},
+		{method: overrides.HistogramMethodNative, expectedHistograms: 1},
+		{method: overrides.HistogramMethodBoth, expectedSamples: 5, expectedHistograms: 1},
+	} {
+		t.Run(string(tc.method), func(t *testing.T) {
+			appender := &capturingAppender{}
+
+			registry := New(&Config{}, &mockOverrides{generateNativeHistograms: tc.method, nativeHistogramBucketFactor: 1.5}, "test", appender, log.NewNopLogger())
+			defer registry.Close()
+
+			histogram := registry.NewHistogram("histogram", []float64{1.0, 2.0})
+			histogram.ObserveWithExemplar(newLabelValueCombo([]string{"label"}, []string{"value-1"}), 1.0, "", 1.0)
+
+			registry.collectMetrics(context.Background())
+
+			assert.Len(t, appender.samples, tc.expectedSamples)
+			require.Len(t, appender.histograms, tc.expectedHistograms)
+			for _, h := range appender.histograms {
+				assert.Equal(t, "histogram", h.l.Get("__name__"))
+				assert.Equal(t, mustGetHostname(), h.l.Get("__metrics_gen_instance"))
+				// a bucket factor of 1.5 results in schema 1
+				assert.Equal(t, int32(1), h.h.Schema)
+			}
+			assert.Equal(t, uint32(tc.expectedSamples+tc.expectedHistograms), registry.activeSeries.Load())
+		})
+	}
+}
+
 func TestManagedRegistry_removeStaleSeries(t *testing.T) {
 	appender := &capturingAppender{}
 
@@ -132,6 +170,115 @@ func TestManagedRegistry_removeStaleSeries(t *testing.T) {
 	collectRegistryMetricsAndAssert(t, registry, appender, expectedSamples)
 }
 
+func TestManagedRegistry_staleDuration(t *testing.T) {
+	appender := &capturingAppender{}
+
+	cfg := &Config{
+		StaleDuration: time.Hour,
+	}
+	registry := New(cfg, &mockOverrides{}, "test", appender, log.NewNopLogger())
+	defer registry.Close()
+
+	counter1 := registry.NewCounter("metric_1")
+	counter2 := registry.NewCounter("metric_2")
+	counter3 := registry.NewCounter("metric_3")
+	registry.SetStaleDuration("metric_2", func() time.Duration { return 25 * time.Millisecond })
+	// 0 falls back to the configured stale duration
+	registry.SetStaleDuration("metric_3", func() time.Duration { return 0 })
+
+	counter1.Inc(nil, 1)
+	counter2.Inc(nil, 2)
+	counter3.Inc(nil, 3)
+
+	time.Sleep(50 * time.Millisecond)
+	registry.removeStaleSeries(context.Background())
+
+	expectedSamples := []sample{
+		newSample(map[string]string{"__name__": "metric_1", "__metrics_gen_instance": mustGetHostname()}, 0, 0),
+		newSample(map[string]string{"__name__": "metric_1", "__metrics_gen_instance": mustGetHostname()}, 0, 1),
+		newSample(map[string]string{"__name__": "metric_3", "__metrics_gen_instance": mustGetHostname()}, 0, 0),
+		newSample(map[string]string{"__name__": "metric_3", "__metrics_gen_instance": mustGetHostname()}, 0, 3),
+	}
+	collectRegistryMetricsAndAssert(t, registry, appender, expectedSamples)
+}
+
+func TestManagedRegistry_removeSeries(t *testing.T) {
+	appender := &capturingAppender{}
+
+	registry := New(&Config{}, &mockOverrides{}, "test", appender, log.NewNopLogger())
+	defer registry.Close()
+
+	counter := registry.NewCounter("metric_1")
+	gauge := registry.NewGauge("metric_2")
+	histogram := registry.NewHistogram("metric_3", []float64{1.0})
+
+	counter.Inc(newLabelValueCombo([]string{"label"}, []string{"value-1"}), 1)
+	counter.Inc(newLabelValueCombo([]string{"label"}, []string{"value-2"}), 1)
+	counter.Inc(nil, 1)
+	gauge.Set(newLabelValueCombo([]string{"label"}, []string{"value-1"}), 1)
+	histogram.ObserveWithExemplar(newLabelValueCombo([]string{"label"}, []string{"value-1"}), 1.0, "", 1.0)
+
+	// 3 counter series + 1 gauge series + 4 histogram series (count, sum, 2 buckets)
+	assert.Equal(t, uint32(8), registry.activeSeries.Load())
+
+	removed := registry.RemoveSeries([]*labels.Matcher{
+		labels.MustNewMatcher(labels.MatchRegexp, labels.MetricName, "metric_(1|3)"),
+		labels.MustNewMatcher(labels.MatchEqual, "label", "value-1"),
+	})
+	assert.Equal(t, 2, removed)
+	assert.Equal(t, uint32(3), registry.activeSeries.Load())
+
+	// series without the label match an empty value
+	removed = registry.RemoveSeries([]*labels.Matcher{
+		labels.MustNewMatcher(labels.MatchEqual, "label", ""),
+	})
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, uint32(2), registry.activeSeries.Load())
+
+	expectedSamples := []sample{
+		newSample(map[string]string{"__name__": "metric_1", "label": "value-2", "__metrics_gen_instance": mustGetHostname()}, 0, 0),
+		newSample(map[string]string{"__name__": "metric_1", "label": "value-2", "__metrics_gen_instance": mustGetHostname()}, 0, 1),
+		newSample(map[string]string{"__name__": "metric_2", "label": "value-1", "__metrics_gen_instance": mustGetHostname()}, 0, 1),
+	}
+	collectRegistryMetricsAndAssert(t, registry, appender, expectedSamples)
+}
+
+func TestManagedRegistry_unregisterMetric(t *testing.T) {
+	appender := &capturingAppender{}
+
+	registry := New(&Config{}, &mockOverrides{}, "test", appender, log.NewNopLogger())
+	defer registry.Close()
+
+	counter1 := registry.NewCounter("metric_1")
+	histogram := registry.NewHistogram("metric_2", []float64{1.0})
+
+	counter1.Inc(newLabelValueCombo([]string{"label"}, []string{"value-1"}), 1)
+	counter1.Inc(newLabelValueCombo([]string{"label"}, []string{"value-2"}), 1)
+	histogram.ObserveWithExemplar(nil, 1.0, "", 1.0)
+
+	// 2 counter series + 4 histogram series (count, sum, 2 buckets)
+	assert.Equal(t, uint32(6), registry.activeSeries.Load())
+
+	registry.UnregisterMetric("metric_2")
+	assert.Equal(t, uint32(2), registry.activeSeries.Load())
+
+	// unknown metrics are ignored
+	registry.UnregisterMetric("metric_3")
+
+	expectedSamples := []sample{
+		newSample(map[string]string{"__name__": "metric_1", "label": "value-1", "__metrics_gen_instance": mustGetHostname()}, 0, 0),
+		newSample(map[string]string{"__name__": "metric_1", "label": "value-1", "__metrics_gen_instance": mustGetHostname()}, 0, 1),
+		newSample(map[string]string{"__name__": "metric_1", "label": "value-2", "__metrics_gen_instance": mustGetHostname()}, 0, 0),
+		newSample(map[string]string{"__name__": "metric_1", "label": "value-2", "__metrics_gen_instance": mustGetHostname()}, 0, 1),
+	}
+	collectRegistryMetricsAndAssert(t, registry, appender, expectedSamples)
+
+	appender.samples = nil
+	registry.UnregisterMetric("metric_1")
+	assert.Equal(t, uint32(0), registry.activeSeries.Load())
+	collectRegistryMetricsAndAssert(t, registry, appender, nil)
+}
+
 func TestManagedRegistry_externalLabels(t *testing.T) {
 	appender := &capturingAppender{}
 
@@ -298,8 +445,11 @@ func collectRegistryMetricsAndAssert(t *testing.T, r *ManagedRegistry, appender
 }
 
 type mockOverrides struct {
-	maxActiveSeries   uint32
-	disableCollection bool
+	maxActiveSeries                uint32
+	disableCollection              bool
+	generateNativeHistograms       overrides.HistogramMethod
+	nativeHistogramBucketFactor    float64
+	nativeHistogramMaxBucketNumber uint32
 }
 
 var _ Overrides = (*mockOverrides)(nil)
@@ -320,6 +470,18 @@ func (m *mockOverrides) MetricsGenerationTraceIDLabelName(string) string {
 	return ""
 }
 
+func (m *mockOverrides) MetricsGeneratorGenerateNativeHistograms(string) overrides.HistogramMethod {
+	return m.generateNativeHistograms
+}
+
+func (m *mockOverrides) MetricsGeneratorNativeHistogramBucketFactor(string) float64 {
+	return m.nativeHistogramBucketFactor
+}
+
+func (m *mockOverrides) MetricsGeneratorNativeHistogramMaxBucketNumber(string) uint32 {
+	return m.nativeHistogramMaxBucketNumber
+}
+
 func mustGetHostname() string {
 	hostname, _ := os.Hostname()
 	return hostname
@@ -24,6 +24,9 @@ type Config struct {
 	// Prometheus remote write config
 	// https://prometheus.io/docs/prometheus/latest/configuration/configuration/#remote_write
 	RemoteWrite []prometheus_config.RemoteWriteConfig `yaml:"remote_write,omitempty"`
+
+	// OTLP exports the metrics to an OTLP endpoint in addition to remote write
+	OTLP OTLPConfig `yaml:"otlp,omitempty"`
 }
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
@@ -32,6 +35,8 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 	cfg.RemoteWriteFlushDeadline = time.Minute
 
 	cfg.RemoteWriteAddOrgIDHeader = true
+
+	cfg.OTLP.RegisterFlagsAndApplyDefaults()
 }
 
 // agentOptions is a copy of agent.Options but with yaml struct tags. Refer to agent.Options for
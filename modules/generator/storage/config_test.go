@@ -22,6 +22,8 @@ remote_write:
   - url: http://prometheus/api/prom/push
     headers:
       foo: bar
+otlp:
+  endpoint: http://otel-collector:4318/v1/metrics
 `
 
 	var cfg Config
@@ -49,6 +51,11 @@ remote_write:
 		RemoteWrite: []prometheus_config.RemoteWriteConfig{
 			remoteWriteConfig,
 		},
+		OTLP: OTLPConfig{
+			Endpoint:    "http://otel-collector:4318/v1/metrics",
+			Temporality: TemporalityDelta,
+			Timeout:     10 * time.Second,
+		},
 	}
 	assert.Equal(t, expectedCfg, cfg)
 }
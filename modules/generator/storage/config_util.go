@@ -1,13 +1,20 @@
 package storage
 
 import (
+	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/user"
+	"github.com/prometheus/common/model"
 	prometheus_config "github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/prometheus/prometheus/promql/parser"
 
+	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/util"
 )
 
@@ -48,6 +55,91 @@ func generateTenantRemoteWriteConfigs(originalCfgs []prometheus_config.RemoteWri
 	return cloneCfgs
 }
 
+// applyRemoteWriteRoutes replaces the remote write configurations with a copy of the endpoint of every route that
+// only sends the series matching the selector of the route. Series that don't match any route are not sent. If no
+// routes are given, the configurations are returned unchanged.
+func applyRemoteWriteRoutes(cfgs []*prometheus_config.RemoteWriteConfig, routes []overrides.RemoteWriteRoute, logger log.Logger) []*prometheus_config.RemoteWriteConfig {
+	if len(routes) == 0 {
+		return cfgs
+	}
+
+	endpoints := make(map[string]*prometheus_config.RemoteWriteConfig, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.Name != "" {
+			endpoints[cfg.Name] = cfg
+		}
+	}
+
+	routedCfgs := make([]*prometheus_config.RemoteWriteConfig, 0, len(routes))
+	for i, route := range routes {
+		endpoint, ok := endpoints[route.Endpoint]
+		if !ok {
+			level.Warn(logger).Log("msg", "discarding remote write route, unknown endpoint", "endpoint", route.Endpoint)
+			continue
+		}
+
+		relabelCfgs, err := matchRelabelConfigs(route.Match)
+		if err != nil {
+			level.Warn(logger).Log("msg", "discarding remote write route, invalid selector", "endpoint", route.Endpoint, "match", route.Match, "err", err)
+			continue
+		}
+
+		routedCfg := &prometheus_config.RemoteWriteConfig{}
+		*routedCfg = *endpoint
+		routedCfg.Name = fmt.Sprintf("%s-route-%d", route.Endpoint, i)
+
+		// The route selector is applied after the relabeling of the endpoint
+		routedCfg.WriteRelabelConfigs = append(append([]*relabel.Config{}, endpoint.WriteRelabelConfigs...), relabelCfgs...)
+
+		routedCfg.Headers = copyMap(endpoint.Headers)
+		for k, v := range route.Headers {
+			routedCfg.Headers[k] = string(v)
+		}
+
+		routedCfgs = append(routedCfgs, routedCfg)
+	}
+
+	return routedCfgs
+}
+
+// matchRelabelConfigs translates a series selector into relabel configs that drop the series not matching it.
+func matchRelabelConfigs(selector string) ([]*relabel.Config, error) {
+	matchers, err := parser.ParseMetricSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	relabelCfgs := make([]*relabel.Config, 0, len(matchers))
+	for _, m := range matchers {
+		cfg := &relabel.Config{
+			SourceLabels: model.LabelNames{model.LabelName(m.Name)},
+			Separator:    relabel.DefaultRelabelConfig.Separator,
+			Replacement:  relabel.DefaultRelabelConfig.Replacement,
+		}
+
+		var regex string
+		switch m.Type {
+		case labels.MatchEqual:
+			cfg.Action, regex = relabel.Keep, regexp.QuoteMeta(m.Value)
+		case labels.MatchNotEqual:
+			cfg.Action, regex = relabel.Drop, regexp.QuoteMeta(m.Value)
+		case labels.MatchRegexp:
+			cfg.Action, regex = relabel.Keep, m.Value
+		case labels.MatchNotRegexp:
+			cfg.Action, regex = relabel.Drop, m.Value
+		}
+
+		cfg.Regex, err = relabel.NewRegexp(regex)
+		if err != nil {
+			return nil, err
+		}
+
+		relabelCfgs = append(relabelCfgs, cfg)
+	}
+
+	return relabelCfgs, nil
+}
+
 // copyMap creates a new map containing all values from the given map.
 func copyMap(m map[string]string) map[string]string {
 	newMap := make(map[string]string, len(m))
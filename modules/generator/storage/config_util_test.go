@@ -8,8 +8,12 @@ import (
 	"github.com/go-kit/log"
 	prometheus_common_config "github.com/prometheus/common/config"
 	prometheus_config "github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/util"
 )
 
@@ -104,6 +108,55 @@ func Test_generateTenantRemoteWriteConfigs_addOrgIDHeader(t *testing.T) {
 	assert.Equal(t, map[string]string{"foo": "bar", "x-scope-orgid": "fake-tenant"}, result[1].Headers, "Original headers not modified")
 }
 
+func Test_applyRemoteWriteRoutes(t *testing.T) {
+	logger := log.NewNopLogger()
+
+	cfgs := []*prometheus_config.RemoteWriteConfig{
+		{
+			Name:    "mimir",
+			URL:     &prometheus_common_config.URL{URL: urlMustParse("http://mimir/api/v1/push")},
+			Headers: map[string]string{"X-Scope-OrgID": "my-tenant"},
+		},
+		{
+			Name: "other",
+			URL:  &prometheus_common_config.URL{URL: urlMustParse("http://other/api/v1/push")},
+		},
+	}
+
+	// without routes the configs are unchanged
+	assert.Equal(t, cfgs, applyRemoteWriteRoutes(cfgs, nil, logger))
+
+	routes := []overrides.RemoteWriteRoute{
+		{Endpoint: "mimir", Match: `{__name__=~"traces_service_graph_.*"}`, Headers: overrides.RemoteWriteHeaders{"X-Scope-OrgID": "service-graphs"}},
+		{Endpoint: "mimir", Match: `{__name__=~"traces_spanmetrics_.*", service!="internal"}`, Headers: overrides.RemoteWriteHeaders{"X-Scope-OrgID": "span-metrics"}},
+		{Endpoint: "unknown", Match: `{__name__="foo"}`},
+		{Endpoint: "other", Match: `{__name__=}`},
+	}
+
+	result := applyRemoteWriteRoutes(cfgs, routes, logger)
+	require.Len(t, result, 2)
+
+	assert.Equal(t, "mimir-route-0", result[0].Name)
+	assert.Equal(t, cfgs[0].URL, result[0].URL)
+	assert.Equal(t, map[string]string{"X-Scope-OrgID": "service-graphs"}, result[0].Headers)
+	assert.Equal(t, "mimir-route-1", result[1].Name)
+	assert.Equal(t, map[string]string{"X-Scope-OrgID": "span-metrics"}, result[1].Headers)
+	assert.Equal(t, map[string]string{"X-Scope-OrgID": "my-tenant"}, cfgs[0].Headers, "Original headers have been modified")
+	assert.Empty(t, cfgs[0].WriteRelabelConfigs, "Original relabel configs have been modified")
+
+	keep := func(cfg *prometheus_config.RemoteWriteConfig, lbls labels.Labels) bool {
+		_, keep := relabel.Process(lbls, cfg.WriteRelabelConfigs...)
+		return keep
+	}
+
+	assert.True(t, keep(result[0], labels.FromStrings("__name__", "traces_service_graph_request_total")))
+	assert.False(t, keep(result[0], labels.FromStrings("__name__", "traces_spanmetrics_calls_total")))
+	assert.True(t, keep(result[1], labels.FromStrings("__name__", "traces_spanmetrics_calls_total", "service", "api")))
+	assert.True(t, keep(result[1], labels.FromStrings("__name__", "traces_spanmetrics_calls_total")))
+	assert.False(t, keep(result[1], labels.FromStrings("__name__", "traces_spanmetrics_calls_total", "service", "internal")))
+	assert.False(t, keep(result[1], labels.FromStrings("__name__", "traces_service_graph_request_total")))
+}
+
 func Test_copyMap(t *testing.T) {
 	original := map[string]string{
 		"k1": "v1",
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"time"
 
 	"github.com/go-kit/log"
@@ -19,6 +20,8 @@ import (
 	"github.com/prometheus/prometheus/storage/remote"
 	"github.com/prometheus/prometheus/tsdb/agent"
 	tsdb_errors "github.com/prometheus/prometheus/tsdb/errors"
+
+	"github.com/grafana/tempo/modules/overrides"
 )
 
 var metricStorageHeadersUpdateFailed = promauto.NewCounterVec(prometheus.CounterOpts{
@@ -42,6 +45,7 @@ type storageImpl struct {
 
 	tenantID       string
 	currentHeaders map[string]string
+	currentRoutes  []overrides.RemoteWriteRoute
 	overrides      Overrides
 	closeCh        chan struct{}
 
@@ -74,6 +78,14 @@ func New(cfg *Config, o Overrides, tenant string, reg prometheus.Registerer, log
 		return nil, fmt.Errorf("could not create directory for metrics WAL: %w", err)
 	}
 
+	var otlp *otlpExporter
+	if cfg.OTLP.Endpoint != "" {
+		otlp, err = newOTLPExporter(&cfg.OTLP, tenant, cfg.RemoteWriteAddOrgIDHeader, o, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Set up remote storage writer
 	startTimeCallback := func() (int64, error) {
 		return int64(model.Latest), nil
@@ -81,8 +93,9 @@ func New(cfg *Config, o Overrides, tenant string, reg prometheus.Registerer, log
 	remoteStorage := remote.NewStorage(log.With(logger, "component", "remote"), reg, startTimeCallback, walDir, cfg.RemoteWriteFlushDeadline, &noopScrapeManager{})
 
 	headers := o.MetricsGeneratorRemoteWriteHeaders(tenant)
+	routes := o.MetricsGeneratorRemoteWriteRoutes(tenant)
 	remoteStorageConfig := &prometheus_config.Config{
-		RemoteWriteConfigs: generateTenantRemoteWriteConfigs(cfg.RemoteWrite, tenant, headers, cfg.RemoteWriteAddOrgIDHeader, logger),
+		RemoteWriteConfigs: tenantRemoteWriteConfigs(cfg, tenant, headers, routes, logger),
 	}
 
 	err = remoteStorage.ApplyConfig(remoteStorageConfig)
@@ -96,14 +109,20 @@ func New(cfg *Config, o Overrides, tenant string, reg prometheus.Registerer, log
 		return nil, err
 	}
 
+	secondaries := []storage.Storage{remoteStorage}
+	if otlp != nil {
+		secondaries = append(secondaries, otlp)
+	}
+
 	s := &storageImpl{
 		cfg:     cfg,
 		walDir:  walDir,
 		remote:  remoteStorage,
-		storage: storage.NewFanout(logger, wal, remoteStorage),
+		storage: storage.NewFanout(logger, wal, secondaries...),
 
 		tenantID:       tenant,
 		currentHeaders: headers,
+		currentRoutes:  routes,
 		overrides:      o,
 		closeCh:        make(chan struct{}),
 
@@ -141,15 +160,17 @@ func (s *storageImpl) watchOverrides() {
 		select {
 		case <-t.C:
 			newHeaders := s.overrides.MetricsGeneratorRemoteWriteHeaders(s.tenantID)
-			if !headersEqual(s.currentHeaders, newHeaders) {
-				level.Info(s.logger).Log("msg", "updating remote write headers")
+			newRoutes := s.overrides.MetricsGeneratorRemoteWriteRoutes(s.tenantID)
+			if !headersEqual(s.currentHeaders, newHeaders) || !reflect.DeepEqual(s.currentRoutes, newRoutes) {
+				level.Info(s.logger).Log("msg", "updating remote write headers and routes")
 				s.currentHeaders = newHeaders
+				s.currentRoutes = newRoutes
 				err := s.remote.ApplyConfig(&prometheus_config.Config{
-					RemoteWriteConfigs: generateTenantRemoteWriteConfigs(s.cfg.RemoteWrite, s.tenantID, newHeaders, s.cfg.RemoteWriteAddOrgIDHeader, s.logger),
+					RemoteWriteConfigs: tenantRemoteWriteConfigs(s.cfg, s.tenantID, newHeaders, newRoutes, s.logger),
 				})
 				if err != nil {
 					metricStorageHeadersUpdateFailed.WithLabelValues(s.tenantID).Inc()
-					level.Error(s.logger).Log("msg", "Failed to update remote write headers. Remote write will continue with old headers", "err", err)
+					level.Error(s.logger).Log("msg", "Failed to update remote write headers and routes. Remote write will continue with the old config", "err", err)
 				}
 			}
 		case <-s.closeCh:
@@ -158,6 +179,11 @@ func (s *storageImpl) watchOverrides() {
 	}
 }
 
+func tenantRemoteWriteConfigs(cfg *Config, tenant string, headers map[string]string, routes []overrides.RemoteWriteRoute, logger log.Logger) []*prometheus_config.RemoteWriteConfig {
+	cfgs := generateTenantRemoteWriteConfigs(cfg.RemoteWrite, tenant, headers, cfg.RemoteWriteAddOrgIDHeader, logger)
+	return applyRemoteWriteRoutes(cfgs, routes, logger)
+}
+
 func headersEqual(a, b map[string]string) bool {
 	if len(a) != len(b) {
 		return false
@@ -24,6 +24,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
+
+	"github.com/grafana/tempo/modules/overrides"
 )
 
 // Verify basic functionality like sending metrics and exemplars, buffering and retrying failed
@@ -368,6 +370,10 @@ func (m *mockOverrides) MetricsGeneratorRemoteWriteHeaders(string) map[string]st
 	return m.headers
 }
 
+func (m *mockOverrides) MetricsGeneratorRemoteWriteRoutes(string) []overrides.RemoteWriteRoute {
+	return nil
+}
+
 var _ prometheus.Registerer = (*noopRegisterer)(nil)
 
 type noopRegisterer struct{}
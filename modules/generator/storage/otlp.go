@@ -0,0 +1,459 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
+	"github.com/prometheus/prometheus/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+)
+
+const (
+	TemporalityDelta      = "delta"
+	TemporalityCumulative = "cumulative"
+
+	otlpScopeName = "tempo-metrics-generator"
+)
+
+type OTLPConfig struct {
+	// Endpoint of an OTLP/HTTP metrics receiver, e.g. http://otel-collector:4318/v1/metrics. The OTLP export
+	// is disabled if empty.
+	Endpoint string `yaml:"endpoint"`
+
+	// Headers added to every request.
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// Temporality of the counters and histograms, either delta or cumulative.
+	Temporality string `yaml:"temporality"`
+
+	// Timeout of a request.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+func (cfg *OTLPConfig) RegisterFlagsAndApplyDefaults() {
+	cfg.Temporality = TemporalityDelta
+	cfg.Timeout = 10 * time.Second
+}
+
+func (cfg *OTLPConfig) validate() error {
+	switch cfg.Temporality {
+	case TemporalityDelta, TemporalityCumulative:
+	default:
+		return fmt.Errorf("unsupported otlp temporality %q, must be %s or %s", cfg.Temporality, TemporalityDelta, TemporalityCumulative)
+	}
+	return nil
+}
+
+// otlpExporter is a storage that sends the samples of every commit to an OTLP metrics endpoint. The samples are
+// converted based on their name: series ending in _total are counters, series ending in _bucket, _sum and
+// _count are classic histograms and all other series are gauges. Native histograms are not exported.
+type otlpExporter struct {
+	cfg            *OTLPConfig
+	tenantID       string
+	addOrgIDHeader bool
+	overrides      Overrides
+	client         *http.Client
+	startTime      time.Time
+
+	// state of the series sent in the previous commit, used to calculate deltas
+	stateMtx sync.Mutex
+	state    map[uint64]*otlpSeriesState
+
+	logger log.Logger
+}
+
+var _ storage.Storage = (*otlpExporter)(nil)
+
+type otlpSeriesState struct {
+	start   time.Time
+	last    time.Time
+	value   float64
+	buckets []float64
+}
+
+func newOTLPExporter(cfg *OTLPConfig, tenantID string, addOrgIDHeader bool, o Overrides, logger log.Logger) (*otlpExporter, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return &otlpExporter{
+		cfg:            cfg,
+		tenantID:       tenantID,
+		addOrgIDHeader: addOrgIDHeader,
+		overrides:      o,
+		client:         &http.Client{Timeout: cfg.Timeout},
+		startTime:      time.Now(),
+		state:          map[uint64]*otlpSeriesState{},
+		logger:         log.With(logger, "component", "otlp"),
+	}, nil
+}
+
+func (e *otlpExporter) Appender(ctx context.Context) storage.Appender {
+	return &otlpAppender{
+		ctx:      ctx,
+		exporter: e,
+		samples:  map[uint64]otlpSample{},
+	}
+}
+
+func (e *otlpExporter) Querier(int64, int64) (storage.Querier, error) {
+	return storage.NoopQuerier(), nil
+}
+
+func (e *otlpExporter) ChunkQuerier(int64, int64) (storage.ChunkQuerier, error) {
+	return storage.NoopChunkedQuerier(), nil
+}
+
+func (e *otlpExporter) StartTime() (int64, error) {
+	return int64(model.Latest), nil
+}
+
+func (e *otlpExporter) Close() error {
+	return nil
+}
+
+type otlpSample struct {
+	labels labels.Labels
+	t      int64
+	v      float64
+}
+
+// otlpHistogram groups the series of a classic histogram.
+type otlpHistogram struct {
+	name   string
+	labels labels.Labels
+	t      int64
+	sum    float64
+	count  float64
+	bounds []float64
+}
+
+func (e *otlpExporter) export(ctx context.Context, samples map[uint64]otlpSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	e.stateMtx.Lock()
+	defer e.stateMtx.Unlock()
+
+	md, state := e.convert(samples)
+
+	err := e.send(ctx, md)
+	if err != nil {
+		// keep the previous state so the next deltas cover the samples that weren't sent
+		return fmt.Errorf("failed to export metrics over otlp: %w", err)
+	}
+
+	e.state = state
+	return nil
+}
+
+// convert builds the OTLP metrics of the samples and returns them together with the new state of the series.
+func (e *otlpExporter) convert(samples map[uint64]otlpSample) (pmetric.Metrics, map[uint64]*otlpSeriesState) {
+	histograms := map[uint64]*otlpHistogram{}
+	type bucket struct {
+		le    float64
+		count float64
+	}
+	histogramBuckets := map[uint64][]bucket{}
+
+	// first collect the buckets, so _sum and _count series are only treated as histograms if the buckets exist
+	for _, s := range samples {
+		name := s.labels.Get(labels.MetricName)
+		le := s.labels.Get(labels.BucketLabel)
+		if !strings.HasSuffix(name, "_bucket") || le == "" {
+			continue
+		}
+		bound, err := strconv.ParseFloat(le, 64)
+		if err != nil {
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "_bucket")
+		key, lbls := histogramKey(base, s.labels)
+		h, ok := histograms[key]
+		if !ok {
+			h = &otlpHistogram{name: base, labels: lbls}
+			histograms[key] = h
+		}
+		h.t = max(h.t, s.t)
+		histogramBuckets[key] = append(histogramBuckets[key], bucket{le: bound, count: s.v})
+	}
+
+	md := pmetric.NewMetrics()
+	sm := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName(otlpScopeName)
+
+	metrics := map[string]pmetric.Metric{}
+	metric := func(name string, init func(pmetric.Metric)) pmetric.Metric {
+		m, ok := metrics[name]
+		if !ok {
+			m = sm.Metrics().AppendEmpty()
+			m.SetName(name)
+			init(m)
+			metrics[name] = m
+		}
+		return m
+	}
+
+	temporality := pmetric.AggregationTemporalityCumulative
+	if e.cfg.Temporality == TemporalityDelta {
+		temporality = pmetric.AggregationTemporalityDelta
+	}
+
+	state := make(map[uint64]*otlpSeriesState, len(samples))
+
+	for hash, s := range samples {
+		name := s.labels.Get(labels.MetricName)
+
+		if base, ok := strings.CutSuffix(name, "_sum"); ok {
+			if key, _ := histogramKey(base, s.labels); histograms[key] != nil {
+				histograms[key].sum = s.v
+				continue
+			}
+		}
+		if base, ok := strings.CutSuffix(name, "_count"); ok {
+			if key, _ := histogramKey(base, s.labels); histograms[key] != nil {
+				histograms[key].count = s.v
+				continue
+			}
+		}
+		if strings.HasSuffix(name, "_bucket") {
+			if key, _ := histogramKey(strings.TrimSuffix(name, "_bucket"), s.labels); histograms[key] != nil {
+				continue
+			}
+		}
+
+		t := time.UnixMilli(s.t)
+
+		if !strings.HasSuffix(name, "_total") {
+			m := metric(name, func(m pmetric.Metric) { m.SetEmptyGauge() })
+			dp := m.Gauge().DataPoints().AppendEmpty()
+			dp.SetTimestamp(pcommon.NewTimestampFromTime(t))
+			dp.SetDoubleValue(s.v)
+			setAttributes(dp.Attributes(), s.labels)
+			continue
+		}
+
+		m := metric(name, func(m pmetric.Metric) {
+			m.SetEmptySum().SetIsMonotonic(true)
+			m.Sum().SetAggregationTemporality(temporality)
+		})
+
+		prev := e.state[hash]
+		next, reset := e.next(prev, t, s.v, nil)
+		state[hash] = next
+
+		value := s.v
+		if temporality == pmetric.AggregationTemporalityDelta && !reset {
+			value -= prev.value
+		}
+
+		dp := m.Sum().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(pcommon.NewTimestampFromTime(next.start))
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(t))
+		dp.SetDoubleValue(value)
+		setAttributes(dp.Attributes(), s.labels)
+	}
+
+	for key, h := range histograms {
+		buckets := histogramBuckets[key]
+		sort.Slice(buckets, func(i, j int) bool { return buckets[i].le < buckets[j].le })
+
+		// cumulative bucket counts, the last bucket is +Inf and equal to the count
+		cumulative := make([]float64, 0, len(buckets)+1)
+		for _, b := range buckets {
+			if !math.IsInf(b.le, 1) {
+				h.bounds = append(h.bounds, b.le)
+			}
+			cumulative = append(cumulative, b.count)
+		}
+		if !math.IsInf(buckets[len(buckets)-1].le, 1) {
+			cumulative = append(cumulative, h.count)
+		}
+
+		t := time.UnixMilli(h.t)
+		prev := e.state[key]
+		next, reset := e.next(prev, t, h.sum, cumulative)
+		state[key] = next
+
+		sum := h.sum
+		counts := cumulative
+		if temporality == pmetric.AggregationTemporalityDelta && !reset {
+			sum -= prev.value
+			counts = make([]float64, len(cumulative))
+			for i := range cumulative {
+				counts[i] = cumulative[i] - prev.buckets[i]
+			}
+		}
+
+		m := metric(h.name, func(m pmetric.Metric) {
+			m.SetEmptyHistogram().SetAggregationTemporality(temporality)
+		})
+		dp := m.Histogram().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(pcommon.NewTimestampFromTime(next.start))
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(t))
+		dp.SetSum(sum)
+		dp.SetCount(uint64(counts[len(counts)-1]))
+		dp.ExplicitBounds().FromRaw(h.bounds)
+		dp.BucketCounts().FromRaw(bucketCounts(counts))
+		setAttributes(dp.Attributes(), h.labels)
+	}
+
+	return md, state
+}
+
+// next returns the new state of a series. A series is reset if it's new or if any of its values decreased.
+func (e *otlpExporter) next(prev *otlpSeriesState, t time.Time, value float64, buckets []float64) (*otlpSeriesState, bool) {
+	next := &otlpSeriesState{
+		start:   e.startTime,
+		last:    t,
+		value:   value,
+		buckets: buckets,
+	}
+
+	reset := prev == nil || value < prev.value || len(buckets) != len(prev.buckets)
+	for i := 0; !reset && i < len(buckets); i++ {
+		reset = buckets[i] < prev.buckets[i]
+	}
+
+	switch {
+	case prev == nil:
+	case e.cfg.Temporality == TemporalityDelta || reset:
+		// deltas start at the previous sample and cumulative series start again after a reset
+		next.start = prev.last
+	default:
+		next.start = prev.start
+	}
+
+	return next, reset
+}
+
+// bucketCounts converts cumulative bucket counts into the counts per bucket.
+func bucketCounts(cumulative []float64) []uint64 {
+	counts := make([]uint64, len(cumulative))
+	var prev float64
+	for i, c := range cumulative {
+		if c > prev {
+			counts[i] = uint64(c - prev)
+		}
+		prev = max(prev, c)
+	}
+	return counts
+}
+
+func histogramKey(base string, lbls labels.Labels) (uint64, labels.Labels) {
+	lb := labels.NewBuilder(lbls)
+	lb.Set(labels.MetricName, base)
+	lb.Del(labels.BucketLabel)
+	l := lb.Labels()
+	return l.Hash(), l
+}
+
+func setAttributes(attrs pcommon.Map, lbls labels.Labels) {
+	attrs.EnsureCapacity(lbls.Len())
+	lbls.Range(func(l labels.Label) {
+		if l.Name == labels.MetricName {
+			return
+		}
+		attrs.PutStr(l.Name, l.Value)
+	})
+}
+
+func (e *otlpExporter) send(ctx context.Context, md pmetric.Metrics) error {
+	body, err := pmetricotlp.NewExportRequestFromMetrics(md).MarshalProto()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	for k, v := range e.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	for k, v := range e.overrides.MetricsGeneratorRemoteWriteHeaders(e.tenantID) {
+		req.Header.Set(k, v)
+	}
+	if e.addOrgIDHeader {
+		req.Header.Set(user.OrgIDHeaderName, e.tenantID)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("server returned HTTP status %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	level.Debug(e.logger).Log("msg", "exported metrics over otlp", "data_points", md.DataPointCount())
+	return nil
+}
+
+// otlpAppender buffers the samples of a commit. If a series is appended more than once only the last sample
+// is kept.
+type otlpAppender struct {
+	ctx      context.Context
+	exporter *otlpExporter
+	samples  map[uint64]otlpSample
+}
+
+var _ storage.Appender = (*otlpAppender)(nil)
+
+func (a *otlpAppender) Append(_ storage.SeriesRef, l labels.Labels, t int64, v float64) (storage.SeriesRef, error) {
+	hash := l.Hash()
+	if prev, ok := a.samples[hash]; ok && prev.t > t {
+		return 0, nil
+	}
+	a.samples[hash] = otlpSample{labels: l, t: t, v: v}
+	return 0, nil
+}
+
+func (a *otlpAppender) AppendExemplar(storage.SeriesRef, labels.Labels, exemplar.Exemplar) (storage.SeriesRef, error) {
+	return 0, nil
+}
+
+func (a *otlpAppender) AppendHistogram(storage.SeriesRef, labels.Labels, int64, *histogram.Histogram, *histogram.FloatHistogram) (storage.SeriesRef, error) {
+	return 0, nil
+}
+
+func (a *otlpAppender) UpdateMetadata(storage.SeriesRef, labels.Labels, metadata.Metadata) (storage.SeriesRef, error) {
+	return 0, nil
+}
+
+func (a *otlpAppender) Commit() error {
+	samples := a.samples
+	a.samples = map[uint64]otlpSample{}
+	return a.exporter.export(a.ctx, samples)
+}
+
+func (a *otlpAppender) Rollback() error {
+	a.samples = map[uint64]otlpSample{}
+	return nil
+}
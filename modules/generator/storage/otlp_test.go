@@ -0,0 +1,260 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.uber.org/atomic"
+)
+
+func TestOTLPExporter_delta(t *testing.T) {
+	srv := newMockOTLPServer(t)
+	e := newTestOTLPExporter(t, srv.URL(), TemporalityDelta)
+
+	t0 := time.Now()
+	appendTestSamples(t, e, t0, 5, []float64{2, 3}, 4.5)
+
+	md := srv.lastRequest(t)
+	requireSum(t, md, "calls_total", pmetric.AggregationTemporalityDelta, e.startTime, t0, 5)
+	requireHistogram(t, md, "latency", pmetric.AggregationTemporalityDelta, e.startTime, t0, []uint64{2, 1}, 3, 4.5)
+	requireGauge(t, md, "target_info", 1)
+
+	t1 := t0.Add(15 * time.Second)
+	appendTestSamples(t, e, t1, 8, []float64{4, 6}, 9)
+
+	md = srv.lastRequest(t)
+	requireSum(t, md, "calls_total", pmetric.AggregationTemporalityDelta, t0, t1, 3)
+	requireHistogram(t, md, "latency", pmetric.AggregationTemporalityDelta, t0, t1, []uint64{2, 1}, 3, 4.5)
+
+	// failed exports are included in the next delta
+	srv.fail.Store(true)
+	t2 := t1.Add(15 * time.Second)
+	err := appendTestSamplesErr(e, t2, 10, []float64{5, 7}, 10)
+	require.Error(t, err)
+
+	srv.fail.Store(false)
+	t3 := t2.Add(15 * time.Second)
+	appendTestSamples(t, e, t3, 11, []float64{5, 8}, 12)
+
+	md = srv.lastRequest(t)
+	requireSum(t, md, "calls_total", pmetric.AggregationTemporalityDelta, t1, t3, 3)
+	requireHistogram(t, md, "latency", pmetric.AggregationTemporalityDelta, t1, t3, []uint64{1, 1}, 2, 3)
+
+	// a decreasing counter is a reset
+	t4 := t3.Add(15 * time.Second)
+	appendTestSamples(t, e, t4, 2, []float64{5, 8}, 12)
+
+	md = srv.lastRequest(t)
+	requireSum(t, md, "calls_total", pmetric.AggregationTemporalityDelta, t3, t4, 2)
+	requireHistogram(t, md, "latency", pmetric.AggregationTemporalityDelta, t3, t4, []uint64{0, 0}, 0, 0)
+
+	require.Equal(t, "test-tenant", srv.lastHeader.Get(user.OrgIDHeaderName))
+	require.Equal(t, "value", srv.lastHeader.Get("X-Custom"))
+	require.Equal(t, "override", srv.lastHeader.Get("X-Override"))
+}
+
+func TestOTLPExporter_cumulative(t *testing.T) {
+	srv := newMockOTLPServer(t)
+	e := newTestOTLPExporter(t, srv.URL(), TemporalityCumulative)
+
+	t0 := time.Now()
+	appendTestSamples(t, e, t0, 5, []float64{2, 3}, 4.5)
+
+	md := srv.lastRequest(t)
+	requireSum(t, md, "calls_total", pmetric.AggregationTemporalityCumulative, e.startTime, t0, 5)
+	requireHistogram(t, md, "latency", pmetric.AggregationTemporalityCumulative, e.startTime, t0, []uint64{2, 1}, 3, 4.5)
+
+	t1 := t0.Add(15 * time.Second)
+	appendTestSamples(t, e, t1, 8, []float64{4, 6}, 9)
+
+	md = srv.lastRequest(t)
+	requireSum(t, md, "calls_total", pmetric.AggregationTemporalityCumulative, e.startTime, t1, 8)
+	requireHistogram(t, md, "latency", pmetric.AggregationTemporalityCumulative, e.startTime, t1, []uint64{4, 2}, 6, 9)
+
+	// a reset starts the series again
+	t2 := t1.Add(15 * time.Second)
+	appendTestSamples(t, e, t2, 1, []float64{4, 6}, 9)
+
+	md = srv.lastRequest(t)
+	requireSum(t, md, "calls_total", pmetric.AggregationTemporalityCumulative, t1, t2, 1)
+}
+
+func TestOTLPConfig_validate(t *testing.T) {
+	cfg := OTLPConfig{}
+	cfg.RegisterFlagsAndApplyDefaults()
+	require.NoError(t, cfg.validate())
+
+	cfg.Temporality = "gauge"
+	require.Error(t, cfg.validate())
+}
+
+func newTestOTLPExporter(t *testing.T, endpoint, temporality string) *otlpExporter {
+	cfg := &OTLPConfig{}
+	cfg.RegisterFlagsAndApplyDefaults()
+	cfg.Endpoint = endpoint
+	cfg.Temporality = temporality
+	cfg.Headers = map[string]string{"X-Custom": "value"}
+
+	o := &mockOverrides{headers: map[string]string{"X-Override": "override"}}
+	e, err := newOTLPExporter(cfg, "test-tenant", true, o, log.NewNopLogger())
+	require.NoError(t, err)
+	return e
+}
+
+func appendTestSamples(t *testing.T, e *otlpExporter, ts time.Time, calls float64, buckets []float64, sum float64) {
+	require.NoError(t, appendTestSamplesErr(e, ts, calls, buckets, sum))
+}
+
+func appendTestSamplesErr(e *otlpExporter, ts time.Time, calls float64, buckets []float64, sum float64) error {
+	appender := e.Appender(context.Background())
+	timeMs := ts.UnixMilli()
+
+	series := []struct {
+		lbls map[string]string
+		v    float64
+	}{
+		{map[string]string{"__name__": "calls_total", "service": "svc"}, calls},
+		{map[string]string{"__name__": "latency_bucket", "service": "svc", "le": "1"}, buckets[0]},
+		{map[string]string{"__name__": "latency_bucket", "service": "svc", "le": "+Inf"}, buckets[1]},
+		{map[string]string{"__name__": "latency_count", "service": "svc"}, buckets[1]},
+		{map[string]string{"__name__": "latency_sum", "service": "svc"}, sum},
+		{map[string]string{"__name__": "target_info", "service": "svc"}, 1},
+	}
+	for _, s := range series {
+		// the registry inserts a 0 sample before the first sample of a series, only the last one is exported
+		if _, err := appender.Append(0, labels.FromMap(s.lbls), timeMs-1, 0); err != nil {
+			return err
+		}
+		if _, err := appender.Append(0, labels.FromMap(s.lbls), timeMs, s.v); err != nil {
+			return err
+		}
+	}
+
+	return appender.Commit()
+}
+
+func requireSum(t *testing.T, md pmetric.Metrics, name string, temporality pmetric.AggregationTemporality, start, ts time.Time, value float64) {
+	m := findMetric(t, md, name)
+	require.Equal(t, pmetric.MetricTypeSum, m.Type())
+	require.True(t, m.Sum().IsMonotonic())
+	require.Equal(t, temporality, m.Sum().AggregationTemporality())
+	require.Equal(t, 1, m.Sum().DataPoints().Len())
+
+	dp := m.Sum().DataPoints().At(0)
+	require.Equal(t, start.UnixMilli(), dp.StartTimestamp().AsTime().UnixMilli())
+	require.Equal(t, ts.UnixMilli(), dp.Timestamp().AsTime().UnixMilli())
+	require.Equal(t, value, dp.DoubleValue())
+	require.Equal(t, map[string]any{"service": "svc"}, dp.Attributes().AsRaw())
+}
+
+func requireHistogram(t *testing.T, md pmetric.Metrics, name string, temporality pmetric.AggregationTemporality, start, ts time.Time, buckets []uint64, count uint64, sum float64) {
+	m := findMetric(t, md, name)
+	require.Equal(t, pmetric.MetricTypeHistogram, m.Type())
+	require.Equal(t, temporality, m.Histogram().AggregationTemporality())
+	require.Equal(t, 1, m.Histogram().DataPoints().Len())
+
+	dp := m.Histogram().DataPoints().At(0)
+	require.Equal(t, start.UnixMilli(), dp.StartTimestamp().AsTime().UnixMilli())
+	require.Equal(t, ts.UnixMilli(), dp.Timestamp().AsTime().UnixMilli())
+	require.Equal(t, []float64{1}, dp.ExplicitBounds().AsRaw())
+	require.Equal(t, buckets, dp.BucketCounts().AsRaw())
+	require.Equal(t, count, dp.Count())
+	require.Equal(t, sum, dp.Sum())
+	require.Equal(t, map[string]any{"service": "svc"}, dp.Attributes().AsRaw())
+}
+
+func requireGauge(t *testing.T, md pmetric.Metrics, name string, value float64) {
+	m := findMetric(t, md, name)
+	require.Equal(t, pmetric.MetricTypeGauge, m.Type())
+	require.Equal(t, 1, m.Gauge().DataPoints().Len())
+	require.Equal(t, value, m.Gauge().DataPoints().At(0).DoubleValue())
+}
+
+func findMetric(t *testing.T, md pmetric.Metrics, name string) pmetric.Metric {
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		if metrics.At(i).Name() == name {
+			return metrics.At(i)
+		}
+	}
+	require.Failf(t, "metric not found", "metric %s", name)
+	return pmetric.Metric{}
+}
+
+type mockOTLPServer struct {
+	server *httptest.Server
+	fail   atomic.Bool
+
+	mtx        sync.Mutex
+	requests   []pmetric.Metrics
+	lastHeader http.Header
+}
+
+func newMockOTLPServer(t *testing.T) *mockOTLPServer {
+	m := &mockOTLPServer{}
+	m.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		req := pmetricotlp.NewExportRequest()
+		require.NoError(t, req.UnmarshalProto(body))
+
+		m.mtx.Lock()
+		defer m.mtx.Unlock()
+		m.requests = append(m.requests, req.Metrics())
+		m.lastHeader = r.Header
+	}))
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func (m *mockOTLPServer) URL() string {
+	return m.server.URL + "/v1/metrics"
+}
+
+func (m *mockOTLPServer) lastRequest(t *testing.T) pmetric.Metrics {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	require.NotEmpty(t, m.requests)
+	return m.requests[len(m.requests)-1]
+}
+
+func TestInstance_otlp(t *testing.T) {
+	srv := newMockOTLPServer(t)
+
+	var cfg Config
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.Path = t.TempDir()
+	cfg.OTLP.Endpoint = srv.URL()
+
+	instance, err := New(&cfg, &mockOverrides{}, "test-tenant", &noopRegisterer{}, log.NewNopLogger())
+	require.NoError(t, err)
+	defer instance.Close()
+
+	appender := instance.Appender(context.Background())
+	_, err = appender.Append(0, labels.FromMap(map[string]string{"__name__": "calls_total", "service": "svc"}), time.Now().UnixMilli(), 1)
+	require.NoError(t, err)
+	require.NoError(t, appender.Commit())
+
+	require.Equal(t, 1.0, findMetric(t, srv.lastRequest(t), "calls_total").Sum().DataPoints().At(0).DoubleValue())
+
+	cfg.OTLP.Temporality = "gauge"
+	_, err = New(&cfg, &mockOverrides{}, "test-tenant", &noopRegisterer{}, log.NewNopLogger())
+	require.Error(t, err)
+}
@@ -1,5 +1,8 @@
 package storage
 
+import "github.com/grafana/tempo/modules/overrides"
+
 type Overrides interface {
 	MetricsGeneratorRemoteWriteHeaders(userID string) map[string]string
+	MetricsGeneratorRemoteWriteRoutes(userID string) []overrides.RemoteWriteRoute
 }
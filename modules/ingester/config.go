@@ -19,6 +19,7 @@ type Config struct {
 	LifecyclerConfig ring.LifecyclerConfig `yaml:"lifecycler,omitempty"`
 
 	ConcurrentFlushes    int           `yaml:"concurrent_flushes"`
+	ConcurrentCompletes  int           `yaml:"concurrent_completes"`
 	FlushCheckPeriod     time.Duration `yaml:"flush_check_period"`
 	FlushOpTimeout       time.Duration `yaml:"flush_op_timeout"`
 	MaxTraceIdle         time.Duration `yaml:"trace_idle_period"`
@@ -28,9 +29,56 @@ type Config struct {
 	OverrideRingKey      string        `yaml:"override_ring_key"`
 	FlushAllOnShutdown   bool          `yaml:"flush_all_on_shutdown"`
 
+	TenantDeletionCheckPeriod time.Duration `yaml:"tenant_deletion_check_period"`
+	RestoreWALSnapshot        bool          `yaml:"restore_wal_snapshot"`
+
+	// CompleteRetry and FlushRetry control the retries of completing WAL blocks and of uploading complete
+	// blocks to the backend.
+	CompleteRetry FlushRetryConfig `yaml:"complete_retry"`
+	FlushRetry    FlushRetryConfig `yaml:"flush_retry"`
+
+	LiveTracesSpill LiveTracesSpillConfig `yaml:"live_traces_spill"`
+	Search          SearchConfig          `yaml:"search"`
+	TraceByID       TraceByIDConfig       `yaml:"trace_by_id"`
+
 	DedicatedColumns backend.DedicatedColumns `yaml:"-"`
 }
 
+// FlushRetryConfig controls how a failed operation of a flush queue is retried.
+type FlushRetryConfig struct {
+	// MaxAttempts is the number of attempts before the operation is abandoned. 0 means no limit.
+	MaxAttempts int           `yaml:"max_attempts"`
+	MinBackoff  time.Duration `yaml:"min_backoff"`
+	MaxBackoff  time.Duration `yaml:"max_backoff"`
+}
+
+// LiveTracesSpillConfig controls writing live traces to the WAL before they are complete when a
+// tenant reaches its max_live_traces_bytes limit.
+type LiveTracesSpillConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	MinIdle time.Duration `yaml:"min_idle"`
+}
+
+// SearchConfig limits the resources used by searches of recent data so they can't starve appends to the
+// head block.
+type SearchConfig struct {
+	// MaxConcurrentQueries is the number of searches executed at the same time. Other searches wait.
+	MaxConcurrentQueries int `yaml:"max_concurrent_queries"`
+	// MaxConcurrentBlocks is the number of blocks a single search reads at the same time.
+	MaxConcurrentBlocks int `yaml:"max_concurrent_blocks"`
+	// YieldInterval is how long a search iterates over spansets before it yields the processor.
+	YieldInterval time.Duration `yaml:"yield_interval"`
+	// YieldPause is how long a search sleeps when it yields. Searches of the head block never sleep
+	// because they hold its lock.
+	YieldPause time.Duration `yaml:"yield_pause"`
+}
+
+// TraceByIDConfig limits the resources used by trace by ID lookups of recent data.
+type TraceByIDConfig struct {
+	// MaxConcurrentQueries is the number of lookups executed at the same time. Other lookups wait.
+	MaxConcurrentQueries int `yaml:"max_concurrent_queries"`
+}
+
 // RegisterFlagsAndApplyDefaults registers the flags.
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	// apply generic defaults and then overlay tempo default
@@ -40,13 +88,29 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	cfg.LifecyclerConfig.RingConfig.HeartbeatTimeout = 5 * time.Minute
 
 	cfg.ConcurrentFlushes = 4
+	cfg.ConcurrentCompletes = 2
 	cfg.FlushCheckPeriod = 10 * time.Second
 	cfg.FlushOpTimeout = 5 * time.Minute
 	cfg.FlushAllOnShutdown = false
 
+	f.IntVar(&cfg.CompleteRetry.MaxAttempts, prefix+".complete-retry.max-attempts", 3, "Number of attempts to complete a WAL block before it is deleted. 0 means no limit.")
+	f.DurationVar(&cfg.CompleteRetry.MinBackoff, prefix+".complete-retry.min-backoff", 30*time.Second, "Minimum delay before retrying to complete a WAL block.")
+	f.DurationVar(&cfg.CompleteRetry.MaxBackoff, prefix+".complete-retry.max-backoff", 2*time.Minute, "Maximum delay before retrying to complete a WAL block.")
+	f.IntVar(&cfg.FlushRetry.MaxAttempts, prefix+".flush-retry.max-attempts", 0, "Number of attempts to upload a complete block before it is abandoned until the next restart. 0 means no limit.")
+	f.DurationVar(&cfg.FlushRetry.MinBackoff, prefix+".flush-retry.min-backoff", 30*time.Second, "Minimum delay before retrying to upload a complete block.")
+	f.DurationVar(&cfg.FlushRetry.MaxBackoff, prefix+".flush-retry.max-backoff", 2*time.Minute, "Maximum delay before retrying to upload a complete block.")
 	f.DurationVar(&cfg.MaxTraceIdle, prefix+".trace-idle-period", 10*time.Second, "Duration after which to consider a trace complete if no spans have been received")
 	f.DurationVar(&cfg.MaxBlockDuration, prefix+".max-block-duration", 30*time.Minute, "Maximum duration which the head block can be appended to before cutting it.")
 	f.Uint64Var(&cfg.MaxBlockBytes, prefix+".max-block-bytes", 500*1024*1024, "Maximum size of the head block before cutting it.")
+	f.BoolVar(&cfg.LiveTracesSpill.Enabled, prefix+".live-traces-spill.enabled", false, "Write the largest idle live traces to the WAL instead of refusing pushes when a tenant reaches its live traces bytes limit.")
+	f.DurationVar(&cfg.LiveTracesSpill.MinIdle, prefix+".live-traces-spill.min-idle", 2*time.Second, "Minimum duration since the last push before a live trace can be written to the WAL early.")
+	f.DurationVar(&cfg.TenantDeletionCheckPeriod, prefix+".tenant-deletion-check-period", 5*time.Minute, "How often to check the backend for deleted tenants and purge their data. 0 disables the check.")
+	f.BoolVar(&cfg.RestoreWALSnapshot, prefix+".restore-wal-snapshot", false, "Restore the WAL snapshot taken by this ingester through the /snapshot endpoint on start. Tenants with data in the WAL are not restored.")
+	f.IntVar(&cfg.Search.MaxConcurrentQueries, prefix+".search.max-concurrent-queries", 0, "Maximum number of recent data searches executed at the same time. 0 means no limit.")
+	f.IntVar(&cfg.Search.MaxConcurrentBlocks, prefix+".search.max-concurrent-blocks", 0, "Maximum number of blocks a recent data search reads at the same time. 0 means no limit.")
+	f.DurationVar(&cfg.Search.YieldInterval, prefix+".search.yield-interval", 0, "Duration a recent data search iterates before yielding the processor. 0 disables yielding.")
+	f.DurationVar(&cfg.Search.YieldPause, prefix+".search.yield-pause", 0, "Duration a recent data search sleeps each time it yields. Not applied to the head block.")
+	f.IntVar(&cfg.TraceByID.MaxConcurrentQueries, prefix+".trace-by-id.max-concurrent-queries", 0, "Maximum number of trace by ID lookups executed at the same time. 0 means no limit.")
 	f.DurationVar(&cfg.CompleteBlockTimeout, prefix+".complete-block-timeout", 3*tempodb.DefaultBlocklistPoll, "Duration to keep blocks in the ingester after they have been flushed.")
 
 	hostname, err := os.Hostname()
@@ -2,6 +2,7 @@ package ingester
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net/http"
@@ -20,6 +21,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/uber/jaeger-client-go"
 
+	"github.com/grafana/tempo/pkg/flushqueues"
 	"github.com/grafana/tempo/pkg/util/log"
 )
 
@@ -56,13 +58,31 @@ var (
 		Help:      "Size in bytes of blocks flushed.",
 		Buckets:   prometheus.ExponentialBuckets(1024*1024, 2, 10), // from 1MB up to 1GB
 	})
+	metricBlocksCompleted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_blocks_completed_total",
+		Help:      "The total number of WAL blocks completed",
+	})
+	metricFailedCompletes = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_failed_completes_total",
+		Help:      "The total number of failed WAL block completions",
+	})
+	metricCompleteRetries = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_complete_retries_total",
+		Help:      "The total number of retries after a failed WAL block completion",
+	})
+	metricCompleteDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "tempo",
+		Name:      "ingester_complete_duration_seconds",
+		Help:      "Records the amount of time to complete a WAL block.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 10),
+	})
 )
 
 const (
-	initialBackoff      = 30 * time.Second
-	flushJitter         = 10 * time.Second
-	maxBackoff          = 120 * time.Second
-	maxCompleteAttempts = 3
+	flushJitter = 10 * time.Second
 )
 
 const (
@@ -191,14 +211,30 @@ func (i *Ingester) sweepInstance(instance *instance, immediate bool) {
 	}
 }
 
-func (i *Ingester) flushLoop(j int) {
+// queue returns the queues that execute ops of the kind
+func (i *Ingester) queue(kind int) *flushqueues.ExclusiveQueues {
+	if kind == opKindComplete {
+		return i.completeQueues
+	}
+	return i.flushQueues
+}
+
+// retryConfig returns the retry settings of ops of the kind
+func (i *Ingester) retryConfig(kind int) FlushRetryConfig {
+	if kind == opKindComplete {
+		return i.cfg.CompleteRetry
+	}
+	return i.cfg.FlushRetry
+}
+
+func (i *Ingester) flushLoop(queues *flushqueues.ExclusiveQueues, j int) {
 	defer func() {
 		level.Debug(log.Logger).Log("msg", "Ingester.flushLoop() exited")
 		i.flushQueuesDone.Done()
 	}()
 
 	for {
-		o := i.flushQueues.Dequeue(j)
+		o := queues.Dequeue(j)
 		if o == nil {
 			return
 		}
@@ -218,10 +254,17 @@ func (i *Ingester) flushLoop(j int) {
 			handleFailedOp(op, err)
 		}
 
+		if retry && op.kind == opKindFlush && i.cfg.FlushRetry.MaxAttempts > 0 && op.attempts >= uint(i.cfg.FlushRetry.MaxAttempts) {
+			// the block stays in the ingester and is flushed again when it's rediscovered on restart
+			level.Error(log.WithUserID(op.userID, log.Logger)).Log("msg", "Block exceeded max flush attempts. Abandoning it until restart",
+				"userID", op.userID, "attempts", op.attempts, "block", op.blockID.String())
+			retry = false
+		}
+
 		if retry {
 			i.requeue(op)
 		} else {
-			i.flushQueues.Clear(op)
+			queues.Clear(op)
 		}
 	}
 }
@@ -229,8 +272,13 @@ func (i *Ingester) flushLoop(j int) {
 func handleFailedOp(op *flushOp, err error) {
 	level.Error(log.WithUserID(op.userID, log.Logger)).Log("msg", "error performing op in flushQueue",
 		"op", op.kind, "block", op.blockID.String(), "attempts", op.attempts, "err", err)
-	metricFailedFlushes.Inc()
 
+	if op.kind == opKindComplete {
+		metricFailedCompletes.Inc()
+		return
+	}
+
+	metricFailedFlushes.Inc()
 	if op.attempts > 1 {
 		metricFlushFailedRetries.Inc()
 	}
@@ -244,7 +292,7 @@ func handleAbandonedOp(op *flushOp) {
 func (i *Ingester) handleComplete(op *flushOp) (retry bool, err error) {
 	// No point in proceeding if shutdown has been initiated since
 	// we won't be able to queue up the next flush op
-	if i.flushQueues.IsStopped() {
+	if i.completeQueues.IsStopped() {
 		handleAbandonedOp(op)
 		return false, nil
 	}
@@ -252,20 +300,25 @@ func (i *Ingester) handleComplete(op *flushOp) (retry bool, err error) {
 	start := time.Now()
 	level.Info(log.Logger).Log("msg", "completing block", "userid", op.userID, "blockID", op.blockID)
 	instance, err := i.getOrCreateInstance(op.userID)
+	if errors.Is(err, errTenantDeleted) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
 
 	err = instance.CompleteBlock(op.blockID)
+	metricCompleteDuration.Observe(time.Since(start).Seconds())
 	level.Info(log.Logger).Log("msg", "block completed", "userid", op.userID, "blockID", op.blockID, "duration", time.Since(start))
 	if err != nil {
 		handleFailedOp(op, err)
 
-		if op.attempts >= maxCompleteAttempts {
+		if maxAttempts := i.cfg.CompleteRetry.MaxAttempts; maxAttempts > 0 && op.attempts >= uint(maxAttempts) {
 			level.Error(log.WithUserID(op.userID, log.Logger)).Log("msg", "Block exceeded max completion errors. Deleting. POSSIBLE DATA LOSS",
 				"userID", op.userID, "attempts", op.attempts, "block", op.blockID.String())
 
 			// Delete WAL and move on
+			instance.forgetBlockIngestionLatency(op.blockID)
 			err = instance.ClearCompletingBlock(op.blockID)
 			return false, err
 		}
@@ -277,6 +330,7 @@ func (i *Ingester) handleComplete(op *flushOp) (retry bool, err error) {
 	if err != nil {
 		return false, fmt.Errorf("error clearing completing block: %w", err)
 	}
+	metricBlocksCompleted.Inc()
 
 	// add a flushOp for the block we just completed
 	// No delay
@@ -309,6 +363,9 @@ func (i *Ingester) handleFlush(ctx context.Context, userID string, blockID uuid.
 	withSpan(level.Info(log.Logger), sp).Log("msg", "flushing block", "userid", userID, "block", blockID.String())
 
 	instance, err := i.getOrCreateInstance(userID)
+	if errors.Is(err, errTenantDeleted) {
+		return false, nil
+	}
 	if err != nil {
 		return true, err
 	}
@@ -333,6 +390,8 @@ func (i *Ingester) handleFlush(ctx context.Context, userID string, blockID uuid.
 		}
 
 		metricBlocksFlushed.Inc()
+		instance.observeBlockIngestionLatency(blockID, stageBackend)
+		instance.pruneWALSnapshot(ctx, i.cfg.LifecyclerConfig.ID, blockID)
 	} else {
 		return false, fmt.Errorf("error getting block to flush")
 	}
@@ -341,13 +400,15 @@ func (i *Ingester) handleFlush(ctx context.Context, userID string, blockID uuid.
 }
 
 func (i *Ingester) enqueueExec(op *flushOp) {
+	queues := i.queue(op.kind)
+
 	// Check if shutdown initiated
-	if i.flushQueues.IsStopped() {
+	if queues.IsStopped() {
 		handleAbandonedOp(op)
 		return
 	}
 
-	err := i.flushQueues.Enqueue(op)
+	err := queues.Enqueue(op)
 	if err != nil {
 		handleFailedOp(op, err)
 	}
@@ -375,12 +436,15 @@ func (i *Ingester) enqueue(op *flushOp, jitter bool) {
 }
 
 func (i *Ingester) requeue(op *flushOp) {
+	cfg := i.retryConfig(op.kind)
+	queues := i.queue(op.kind)
+
 	op.backoff *= 2
-	if op.backoff < initialBackoff {
-		op.backoff = initialBackoff
+	if op.backoff < cfg.MinBackoff {
+		op.backoff = cfg.MinBackoff
 	}
-	if op.backoff > maxBackoff {
-		op.backoff = maxBackoff
+	if op.backoff > cfg.MaxBackoff {
+		op.backoff = cfg.MaxBackoff
 	}
 
 	op.at = time.Now().Add(op.backoff)
@@ -388,18 +452,23 @@ func (i *Ingester) requeue(op *flushOp) {
 	level.Info(log.WithUserID(op.userID, log.Logger)).Log("msg", "retrying op in flushQueue",
 		"op", op.kind, "block", op.blockID.String(), "backoff", op.backoff)
 
+	backoff := op.backoff
 	go func() {
-		time.Sleep(op.backoff)
+		time.Sleep(backoff)
 
 		// Check if shutdown initiated
-		if i.flushQueues.IsStopped() {
+		if queues.IsStopped() {
 			handleAbandonedOp(op)
 			return
 		}
 
-		metricFlushRetries.Inc()
+		if op.kind == opKindComplete {
+			metricCompleteRetries.Inc()
+		} else {
+			metricFlushRetries.Inc()
+		}
 
-		err := i.flushQueues.Requeue(op)
+		err := queues.Requeue(op)
 		if err != nil {
 			handleFailedOp(op, err)
 		}
@@ -0,0 +1,45 @@
+package ingester
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/flushqueues"
+)
+
+func TestFlushQueuesByKind(t *testing.T) {
+	cfg := defaultIngesterTestConfig()
+	cfg.CompleteRetry = FlushRetryConfig{MinBackoff: time.Second, MaxBackoff: 3 * time.Second}
+	cfg.FlushRetry = FlushRetryConfig{MinBackoff: time.Minute, MaxBackoff: time.Hour}
+
+	i := &Ingester{
+		cfg:            cfg,
+		completeQueues: flushqueues.New(1, prometheus.NewGauge(prometheus.GaugeOpts{Name: "complete"})),
+		flushQueues:    flushqueues.New(1, prometheus.NewGauge(prometheus.GaugeOpts{Name: "flush"})),
+	}
+	defer func() {
+		i.completeQueues.Stop()
+		i.flushQueues.Stop()
+	}()
+
+	complete := &flushOp{kind: opKindComplete, userID: "test", blockID: uuid.New()}
+	i.enqueue(complete, false)
+	require.False(t, i.completeQueues.IsEmpty())
+	require.True(t, i.flushQueues.IsEmpty())
+
+	flush := &flushOp{kind: opKindFlush, userID: "test", blockID: uuid.New()}
+	i.enqueue(flush, false)
+	require.False(t, i.flushQueues.IsEmpty())
+
+	// the ops are retried with the backoff of their queue
+	for _, expected := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		i.requeue(complete)
+		require.Equal(t, expected, complete.backoff)
+	}
+	i.requeue(flush)
+	require.Equal(t, time.Minute, flush.backoff)
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.uber.org/atomic"
+	"golang.org/x/sync/semaphore"
 	"google.golang.org/grpc/codes"
 
 	"github.com/grafana/tempo/modules/overrides"
@@ -27,6 +28,7 @@ import (
 	v1 "github.com/grafana/tempo/pkg/model/v1"
 	v2 "github.com/grafana/tempo/pkg/model/v2"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/pkg/validation"
@@ -37,13 +39,22 @@ import (
 var (
 	ErrShuttingDown = errors.New("Ingester is shutting down")
 	ErrStarting     = errors.New("Ingester is starting")
+
+	errTenantDeleted = status.Error(codes.FailedPrecondition, "tenant has been deleted")
 )
 
-var metricFlushQueueLength = promauto.NewGauge(prometheus.GaugeOpts{
-	Namespace: "tempo",
-	Name:      "ingester_flush_queue_length",
-	Help:      "The total number of series pending in the flush queue.",
-})
+var (
+	metricFlushQueueLength = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "ingester_flush_queue_length",
+		Help:      "The total number of series pending in the flush queue.",
+	})
+	metricCompleteQueueLength = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "ingester_complete_queue_length",
+		Help:      "The total number of WAL blocks pending in the complete queue.",
+	})
+)
 
 const (
 	ingesterRingKey = "ring"
@@ -57,18 +68,28 @@ type Ingester struct {
 
 	instancesMtx sync.RWMutex
 	instances    map[string]*instance
-	pushErr      atomic.Error
+	// deletedTenants caches the tenants found marked for deletion in the backend. Tenants are added the first
+	// time the mark is found and removed by the periodic check once the mark is gone.
+	deletedTenants map[string]struct{}
+	pushErr        atomic.Error
 
 	lifecycler   *ring.Lifecycler
 	store        storage.Store
 	local        *local.Backend
 	replayJitter bool // this var exists so tests can remove jitter
 
+	// completeQueues complete WAL blocks to local blocks and flushQueues upload local blocks to the backend. They
+	// are separate so a slow backend doesn't delay completing WAL blocks.
+	completeQueues  *flushqueues.ExclusiveQueues
 	flushQueues     *flushqueues.ExclusiveQueues
 	flushQueuesDone sync.WaitGroup
 
 	limiter *Limiter
 
+	// searchQueries and traceByIDQueries limit the number of concurrent queries. nil if unlimited.
+	searchQueries    *semaphore.Weighted
+	traceByIDQueries *semaphore.Weighted
+
 	overrides ingesterOverrides
 
 	subservicesWatcher *services.FailureWatcher
@@ -77,16 +98,25 @@ type Ingester struct {
 // New makes a new Ingester.
 func New(cfg Config, store storage.Store, overrides overrides.Interface, reg prometheus.Registerer) (*Ingester, error) {
 	i := &Ingester{
-		cfg:          cfg,
-		instances:    map[string]*instance{},
-		store:        store,
-		flushQueues:  flushqueues.New(cfg.ConcurrentFlushes, metricFlushQueueLength),
-		replayJitter: true,
-		overrides:    overrides,
+		cfg:            cfg,
+		instances:      map[string]*instance{},
+		deletedTenants: map[string]struct{}{},
+		store:          store,
+		completeQueues: flushqueues.New(cfg.ConcurrentCompletes, metricCompleteQueueLength),
+		flushQueues:    flushqueues.New(cfg.ConcurrentFlushes, metricFlushQueueLength),
+		replayJitter:   true,
+		overrides:      overrides,
 	}
 
 	i.pushErr.Store(ErrStarting)
 
+	if cfg.Search.MaxConcurrentQueries > 0 {
+		i.searchQueries = semaphore.NewWeighted(int64(cfg.Search.MaxConcurrentQueries))
+	}
+	if cfg.TraceByID.MaxConcurrentQueries > 0 {
+		i.traceByIDQueries = semaphore.NewWeighted(int64(cfg.TraceByID.MaxConcurrentQueries))
+	}
+
 	i.local = store.WAL().LocalBackend()
 
 	lc, err := ring.NewLifecycler(cfg.LifecyclerConfig, i, "ingester", cfg.OverrideRingKey, true, log.Logger, prometheus.WrapRegistererWithPrefix("tempo_", reg))
@@ -107,6 +137,10 @@ func New(cfg Config, store storage.Store, overrides overrides.Interface, reg pro
 }
 
 func (i *Ingester) starting(ctx context.Context) error {
+	if i.cfg.RestoreWALSnapshot {
+		i.restoreWALSnapshots(ctx)
+	}
+
 	err := i.replayWal()
 	if err != nil {
 		return fmt.Errorf("failed to replay wal: %w", err)
@@ -117,9 +151,12 @@ func (i *Ingester) starting(ctx context.Context) error {
 		return fmt.Errorf("failed to rediscover local blocks: %w", err)
 	}
 
-	i.flushQueuesDone.Add(i.cfg.ConcurrentFlushes)
+	i.flushQueuesDone.Add(i.cfg.ConcurrentCompletes + i.cfg.ConcurrentFlushes)
+	for j := 0; j < i.cfg.ConcurrentCompletes; j++ {
+		go i.flushLoop(i.completeQueues, j)
+	}
 	for j := 0; j < i.cfg.ConcurrentFlushes; j++ {
-		go i.flushLoop(j)
+		go i.flushLoop(i.flushQueues, j)
 	}
 
 	// Now that user states have been created, we can start the lifecycler.
@@ -140,11 +177,21 @@ func (i *Ingester) loop(ctx context.Context) error {
 	flushTicker := time.NewTicker(i.cfg.FlushCheckPeriod)
 	defer flushTicker.Stop()
 
+	var deletionCheck <-chan time.Time
+	if i.cfg.TenantDeletionCheckPeriod > 0 {
+		deletionTicker := time.NewTicker(i.cfg.TenantDeletionCheckPeriod)
+		defer deletionTicker.Stop()
+		deletionCheck = deletionTicker.C
+	}
+
 	for {
 		select {
 		case <-flushTicker.C:
 			i.sweepAllInstances(false)
 
+		case <-deletionCheck:
+			i.purgeDeletedTenants(ctx)
+
 		case <-ctx.Done():
 			return nil
 
@@ -160,13 +207,18 @@ func (i *Ingester) loop(ctx context.Context) error {
 // sweepAllInstances prepares remaining traces to be flushed by flushLoop routine, also updating ExclusiveQueues.activekeys with keys for new flush operations
 // ExclusiveQueues.activeKeys is cleared of a flush operation when a processing of flush operation is either successful or doesn't return retry signal
 // This ensures that i.flushQueues is empty only when all traces are flushed
+// A complete op enqueues its flush op before it's cleared, so both queues are empty only when all blocks are flushed
 func (i *Ingester) flushRemaining() {
 	i.sweepAllInstances(true)
-	for !i.flushQueues.IsEmpty() {
+	for !i.flushQueuesEmpty() {
 		time.Sleep(100 * time.Millisecond)
 	}
 }
 
+func (i *Ingester) flushQueuesEmpty() bool {
+	return i.completeQueues.IsEmpty() && i.flushQueues.IsEmpty()
+}
+
 // stopping is run when ingester is asked to stop
 func (i *Ingester) stopping(_ error) error {
 	i.markUnavailable()
@@ -177,6 +229,7 @@ func (i *Ingester) stopping(_ error) error {
 	}
 
 	if i.flushQueues != nil {
+		i.completeQueues.Stop()
 		i.flushQueues.Stop()
 		i.flushQueuesDone.Wait()
 	}
@@ -286,6 +339,12 @@ func (i *Ingester) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDRequ
 		return &tempopb.TraceByIDResponse{}, nil
 	}
 
+	release, err := acquireQuery(ctx, i.traceByIDQueries)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	trace, err := inst.FindTraceByID(ctx, req.TraceID)
 	if err != nil {
 		return nil, err
@@ -293,6 +352,20 @@ func (i *Ingester) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDRequ
 
 	span.LogFields(ot_log.Bool("trace found", trace != nil))
 
+	if req.SpanFilter != "" && trace != nil {
+		f, err := traceql.NewTraceFilter(req.SpanFilter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid span filter: %w", err)
+		}
+		trace, err = f.Filter(trace)
+		if err != nil {
+			return nil, err
+		}
+		if len(trace.Batches) == 0 {
+			trace = nil
+		}
+	}
+
 	res = &tempopb.TraceByIDResponse{
 		Trace: trace,
 	}
@@ -314,12 +387,17 @@ func (i *Ingester) getOrCreateInstance(instanceID string) (*instance, error) {
 		return inst, nil
 	}
 
+	// the backend is checked before creating an instance so deleted tenants are refused after a restart too
+	if i.isTenantDeleted(context.Background(), instanceID) {
+		return nil, errTenantDeleted
+	}
+
 	i.instancesMtx.Lock()
 	defer i.instancesMtx.Unlock()
 	inst, ok = i.instances[instanceID]
 	if !ok {
 		var err error
-		inst, err = newInstance(instanceID, i.limiter, i.overrides, i.store, i.local, i.cfg.DedicatedColumns)
+		inst, err = newInstance(instanceID, i.limiter, i.overrides, i.store, i.local, i.cfg.DedicatedColumns, i.cfg.LiveTracesSpill, i.cfg.Search)
 		if err != nil {
 			return nil, err
 		}
@@ -347,6 +425,93 @@ func (i *Ingester) getInstances() []*instance {
 	return instances
 }
 
+// LiveTracesPerTenant returns the number of live traces of each tenant.
+func (i *Ingester) LiveTracesPerTenant() map[string]float64 {
+	instances := i.getInstances()
+
+	traces := make(map[string]float64, len(instances))
+	for _, inst := range instances {
+		traces[inst.instanceID] = float64(inst.traceCount.Load())
+	}
+	return traces
+}
+
+// isTenantDeleted returns true if the tenant is marked for deletion in the backend. If the mark can't be read
+// the tenant is considered not deleted, its data is purged by the next periodic check.
+func (i *Ingester) isTenantDeleted(ctx context.Context, instanceID string) bool {
+	i.instancesMtx.RLock()
+	_, deleted := i.deletedTenants[instanceID]
+	i.instancesMtx.RUnlock()
+	if deleted {
+		return true
+	}
+
+	mark, err := i.store.TenantDeletionMark(ctx, instanceID)
+	if err != nil {
+		level.Error(log.WithUserID(instanceID, log.Logger)).Log("msg", "failed to check tenant deletion mark", "err", err)
+		return false
+	}
+	if mark == nil {
+		return false
+	}
+
+	i.instancesMtx.Lock()
+	i.deletedTenants[instanceID] = struct{}{}
+	i.instancesMtx.Unlock()
+	return true
+}
+
+// purgeDeletedTenants drops the instances of all tenants that have been marked for deletion in the backend.
+// Their live traces, WAL blocks and local blocks are discarded instead of being flushed and further pushes
+// are refused. Pushes of tenants whose deletion was canceled are accepted again.
+func (i *Ingester) purgeDeletedTenants(ctx context.Context) {
+	i.instancesMtx.RLock()
+	deleted := make([]string, 0, len(i.deletedTenants))
+	for id := range i.deletedTenants {
+		deleted = append(deleted, id)
+	}
+	i.instancesMtx.RUnlock()
+
+	for _, id := range deleted {
+		mark, err := i.store.TenantDeletionMark(ctx, id)
+		if err != nil {
+			level.Error(log.WithUserID(id, log.Logger)).Log("msg", "failed to check tenant deletion mark", "err", err)
+			continue
+		}
+		if mark == nil {
+			level.Info(log.Logger).Log("msg", "tenant deletion canceled", "tenant", id)
+			i.instancesMtx.Lock()
+			delete(i.deletedTenants, id)
+			i.instancesMtx.Unlock()
+		}
+	}
+
+	for _, inst := range i.getInstances() {
+		mark, err := i.store.TenantDeletionMark(ctx, inst.instanceID)
+		if err != nil {
+			level.Error(log.WithUserID(inst.instanceID, log.Logger)).Log("msg", "failed to check tenant deletion mark", "err", err)
+			continue
+		}
+		if mark == nil {
+			continue
+		}
+
+		i.purgeInstance(inst)
+	}
+}
+
+func (i *Ingester) purgeInstance(inst *instance) {
+	i.instancesMtx.Lock()
+	delete(i.instances, inst.instanceID)
+	i.deletedTenants[inst.instanceID] = struct{}{}
+	i.instancesMtx.Unlock()
+
+	level.Info(log.Logger).Log("msg", "purging deleted tenant", "tenant", inst.instanceID)
+	if err := inst.clear(); err != nil {
+		level.Error(log.WithUserID(inst.instanceID, log.Logger)).Log("msg", "failed to purge deleted tenant", "err", err)
+	}
+}
+
 // stopIncomingRequests implements ring.Lifecycler.
 func (i *Ingester) stopIncomingRequests() {
 	i.instancesMtx.Lock()
@@ -376,6 +541,13 @@ func (i *Ingester) replayWal() error {
 		tenantID := b.BlockMeta().TenantID
 
 		instance, err := i.getOrCreateInstance(tenantID)
+		if errors.Is(err, errTenantDeleted) {
+			level.Info(log.Logger).Log("msg", "discarding wal block of deleted tenant", "tenant", tenantID, "block", b.BlockMeta().BlockID)
+			if err := b.Clear(); err != nil {
+				return err
+			}
+			continue
+		}
 		if err != nil {
 			return err
 		}
@@ -427,6 +599,15 @@ func (i *Ingester) rediscoverLocalBlocks() error {
 		}
 
 		inst, err := i.getOrCreateInstance(t)
+		if errors.Is(err, errTenantDeleted) {
+			level.Info(log.Logger).Log("msg", "discarding local blocks of deleted tenant", "tenant", t, "blocks", len(blocks))
+			for _, b := range blocks {
+				if err := i.local.ClearBlock(b, t); err != nil {
+					return err
+				}
+			}
+			continue
+		}
 		if err != nil {
 			return err
 		}
@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/dskit/user"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/util/log"
+	"golang.org/x/sync/semaphore"
 )
 
 func (i *Ingester) SearchRecent(ctx context.Context, req *tempopb.SearchRequest) (res *tempopb.SearchResponse, err error) {
@@ -28,6 +29,12 @@ func (i *Ingester) SearchRecent(ctx context.Context, req *tempopb.SearchRequest)
 		return &tempopb.SearchResponse{}, nil
 	}
 
+	release, err := acquireQuery(ctx, i.searchQueries)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	res, err = inst.Search(ctx, req)
 	if err != nil {
 		return nil, err
@@ -36,6 +43,19 @@ func (i *Ingester) SearchRecent(ctx context.Context, req *tempopb.SearchRequest)
 	return res, nil
 }
 
+// acquireQuery waits until sem has room for another query and returns a func to release it. A nil sem
+// doesn't limit queries.
+func acquireQuery(ctx context.Context, sem *semaphore.Weighted) (func(), error) {
+	if sem == nil {
+		return func() {}, nil
+	}
+
+	if err := sem.Acquire(ctx, 1); err != nil {
+		return nil, err
+	}
+	return func() { sem.Release(1) }, nil
+}
+
 func (i *Ingester) SearchTags(ctx context.Context, req *tempopb.SearchTagsRequest) (res *tempopb.SearchTagsResponse, err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -3,16 +3,22 @@ package ingester
 import (
 	"context"
 	"crypto/rand"
+	"encoding/json"
 	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/gogo/protobuf/proto"
+	"github.com/google/uuid"
 	"github.com/grafana/dskit/flagext"
 	"github.com/grafana/dskit/kv/consul"
 	"github.com/grafana/dskit/ring"
+
 	"github.com/grafana/dskit/user"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
@@ -26,6 +32,7 @@ import (
 	model_v2 "github.com/grafana/tempo/pkg/model/v2"
 	"github.com/grafana/tempo/pkg/tempopb"
 	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/grafana/tempo/tempodb"
 	"github.com/grafana/tempo/tempodb/backend"
@@ -82,6 +89,49 @@ func TestPushQueryAllEncodings(t *testing.T) {
 	}
 }
 
+func TestFindTraceByIDSpanFilter(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "test")
+	ingester, traces, traceIDs := defaultIngester(t, t.TempDir())
+
+	expected := traces[0].Batches[0].ScopeSpans[0].Spans[0]
+
+	foundTrace, err := ingester.FindTraceByID(ctx, &tempopb.TraceByIDRequest{
+		TraceID:    traceIDs[0],
+		SpanFilter: fmt.Sprintf(`{ span:id = "%s" }`, util.SpanIDToHexString(expected.SpanId)),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, foundTrace.Trace)
+
+	spanIDs := func(tr *tempopb.Trace) [][]byte {
+		var ids [][]byte
+		for _, b := range tr.Batches {
+			for _, ss := range b.ScopeSpans {
+				for _, s := range ss.Spans {
+					ids = append(ids, s.SpanId)
+				}
+			}
+		}
+		return ids
+	}
+	require.Contains(t, spanIDs(foundTrace.Trace), expected.SpanId)
+	require.Less(t, len(spanIDs(foundTrace.Trace)), len(spanIDs(traces[0])))
+
+	// no matching spans
+	foundTrace, err = ingester.FindTraceByID(ctx, &tempopb.TraceByIDRequest{
+		TraceID:    traceIDs[0],
+		SpanFilter: `{ name = "does-not-exist" }`,
+	})
+	require.NoError(t, err)
+	require.Nil(t, foundTrace.Trace)
+
+	// invalid filter
+	_, err = ingester.FindTraceByID(ctx, &tempopb.TraceByIDRequest{
+		TraceID:    traceIDs[0],
+		SpanFilter: `{ name = `,
+	})
+	require.Error(t, err)
+}
+
 func TestFullTraceReturned(t *testing.T) {
 	ctx := user.InjectOrgID(context.Background(), "test")
 	ingester, _, _ := defaultIngester(t, t.TempDir())
@@ -162,7 +212,7 @@ func TestWal(t *testing.T) {
 
 	// a block that has been replayed should have a flush queue entry to complete it
 	// wait for the flush queues to be empty and then confirm there is a complete block
-	for !ingester.flushQueues.IsEmpty() {
+	for !ingester.flushQueuesEmpty() {
 		time.Sleep(100 * time.Millisecond)
 	}
 
@@ -359,6 +409,231 @@ func TestFlush(t *testing.T) {
 	}
 }
 
+func TestLiveTracesPerTenant(t *testing.T) {
+	ingester, traces, _ := defaultIngester(t, t.TempDir())
+
+	require.Equal(t, map[string]float64{"test": float64(len(traces))}, ingester.LiveTracesPerTenant())
+}
+
+func TestPurgeDeletedTenants(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	ingester, _, traceIDs := defaultIngester(t, tmpDir)
+
+	// cut a block so the tenant has live traces, a head block and a completing block
+	inst, ok := ingester.getInstanceByID("test")
+	require.True(t, ok)
+	require.NoError(t, inst.CutCompleteTraces(0, true))
+	_, err := inst.CutBlockIfReady(0, 0, true)
+	require.NoError(t, err)
+
+	// not marked, nothing happens
+	ingester.purgeDeletedTenants(ctx)
+	_, ok = ingester.getInstanceByID("test")
+	require.True(t, ok)
+
+	_, err = ingester.store.MarkTenantDeleted(ctx, "test")
+	require.NoError(t, err)
+	ingester.purgeDeletedTenants(ctx)
+
+	_, ok = ingester.getInstanceByID("test")
+	require.False(t, ok)
+
+	// the live trace series of the tenant are removed and not set again by requests to the purged instance
+	require.ErrorIs(t, inst.PushBytes(ctx, test.ValidTraceID(nil), []byte{}), errTenantDeleted)
+	require.NoError(t, inst.CutCompleteTraces(0, true))
+	require.False(t, metricLiveTraces.DeleteLabelValues("test"))
+	require.False(t, metricLiveTraceBytes.DeleteLabelValues("test"))
+
+	for _, traceID := range traceIDs {
+		foundTrace, err := ingester.FindTraceByID(ctx, &tempopb.TraceByIDRequest{
+			TraceID: traceID,
+		})
+		require.NoError(t, err)
+		require.Nil(t, foundTrace.Trace)
+	}
+
+	// wal is empty
+	blocks, err := ingester.store.WAL().RescanBlocks(0, log.NewNopLogger())
+	require.NoError(t, err)
+	require.Empty(t, blocks)
+
+	// pushes are refused
+	_, err = ingester.PushBytesV2(ctx, &tempopb.PushBytesRequest{})
+	require.ErrorIs(t, err, errTenantDeleted)
+
+	// and still are after a restart
+	ingester = defaultIngesterModule(t, tmpDir)
+	_, err = ingester.PushBytesV2(ctx, &tempopb.PushBytesRequest{})
+	require.ErrorIs(t, err, errTenantDeleted)
+
+	// pushes are accepted once the deletion is canceled
+	require.NoError(t, ingester.store.UnmarkTenantDeleted(ctx, "test"))
+	ingester.purgeDeletedTenants(ctx)
+	_, err = ingester.PushBytesV2(ctx, &tempopb.PushBytesRequest{})
+	require.NoError(t, err)
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	backendDir := t.TempDir()
+	ctx := user.InjectOrgID(context.Background(), "test")
+
+	newIngester := func(walDir string, restore bool) *Ingester {
+		limits, err := overrides.NewOverrides(defaultOverridesConfig(), nil, prometheus.DefaultRegisterer)
+		require.NoError(t, err)
+
+		cfg := defaultIngesterTestConfig()
+		cfg.RestoreWALSnapshot = restore
+
+		ingester, err := New(cfg, ingesterStore(t, backendDir, walDir), limits, prometheus.NewPedanticRegistry())
+		require.NoError(t, err)
+		ingester.replayJitter = false
+
+		err = ingester.starting(context.Background())
+		require.NoError(t, err)
+		return ingester
+	}
+
+	ingester := newIngester(t.TempDir(), false)
+
+	traces := make([]*tempopb.Trace, 0)
+	traceIDs := make([][]byte, 0)
+	for i := 0; i < 10; i++ {
+		id := test.ValidTraceID(nil)
+		testTrace := test.MakeTrace(10, id)
+		trace.SortTrace(testTrace)
+
+		for _, batch := range testTrace.Batches {
+			pushBatchV2(t, ingester, batch, id)
+		}
+
+		traces = append(traces, testTrace)
+		traceIDs = append(traceIDs, id)
+
+		// cut a block half way so the snapshot contains the head block and a completing block
+		if i == 4 {
+			inst, _ := ingester.getInstanceByID("test")
+			require.NoError(t, inst.CutCompleteTraces(0, true))
+			_, err := inst.CutBlockIfReady(0, 0, true)
+			require.NoError(t, err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	ingester.SnapshotHandler(rec, httptest.NewRequest(http.MethodPost, "/snapshot", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var results []snapshotResult
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &results))
+	require.Len(t, results, 1)
+	require.Equal(t, "test", results[0].Tenant)
+	require.Greater(t, results[0].Files, 0)
+
+	// the snapshot is not restored into a wal that has data of the tenant
+	walDir := t.TempDir()
+	ingester = newIngester(walDir, false)
+	id := test.ValidTraceID(nil)
+	for _, batch := range test.MakeTrace(10, id).Batches {
+		pushBatchV2(t, ingester, batch, id)
+	}
+	inst, _ := ingester.getInstanceByID("test")
+	require.NoError(t, inst.CutCompleteTraces(0, true))
+
+	// replayed blocks are completed in the background
+	ingester = newIngester(walDir, true)
+	inst, _ = ingester.getInstanceByID("test")
+	require.Equal(t, 1, walBlockCount(inst))
+
+	foundTrace, err := ingester.FindTraceByID(ctx, &tempopb.TraceByIDRequest{TraceID: traceIDs[0]})
+	require.NoError(t, err)
+	require.Nil(t, foundTrace.Trace)
+
+	// an ingester with an empty wal restores the snapshot and replays it
+	ingester = newIngester(t.TempDir(), true)
+
+	inst, ok := ingester.getInstanceByID("test")
+	require.True(t, ok)
+	require.Equal(t, 2, walBlockCount(inst))
+
+	for i, traceID := range traceIDs {
+		foundTrace, err := ingester.FindTraceByID(ctx, &tempopb.TraceByIDRequest{
+			TraceID: traceID,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, foundTrace.Trace)
+
+		trace.SortTrace(foundTrace.Trace)
+		test.TracesEqual(t, traces[i], foundTrace.Trace)
+	}
+
+	// the restored snapshot is deleted, it isn't restored a second time
+	rr, _, _, err := local.New(&local.Config{Path: backendDir})
+	require.NoError(t, err)
+	r := backend.NewReader(rr)
+	ingesterID := ingester.cfg.LifecyclerConfig.ID
+
+	_, err = r.IngesterSnapshot(ctx, "test", ingesterID)
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+
+	// flushed blocks are removed from the snapshot, the snapshot is deleted with the last one
+	ingester = newIngester(t.TempDir(), false)
+	var blockIDs []uuid.UUID
+	for j := 0; j < 2; j++ {
+		id := test.ValidTraceID(nil)
+		for _, batch := range test.MakeTrace(10, id).Batches {
+			pushBatchV2(t, ingester, batch, id)
+		}
+		inst, _ = ingester.getInstanceByID("test")
+		require.NoError(t, inst.CutCompleteTraces(0, true))
+		blockID, err := inst.CutBlockIfReady(0, 0, true)
+		require.NoError(t, err)
+		blockIDs = append(blockIDs, blockID)
+	}
+
+	rec = httptest.NewRecorder()
+	ingester.SnapshotHandler(rec, httptest.NewRequest(http.MethodPost, "/snapshot", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	for n, blockID := range blockIDs {
+		require.NoError(t, inst.CompleteBlock(blockID))
+		require.NoError(t, inst.ClearCompletingBlock(blockID))
+		retry, err := ingester.handleFlush(ctx, "test", blockID)
+		require.NoError(t, err)
+		require.False(t, retry)
+
+		snapshot, err := r.IngesterSnapshot(ctx, "test", ingesterID)
+		if n == len(blockIDs)-1 {
+			require.ErrorIs(t, err, backend.ErrDoesNotExist)
+			break
+		}
+		require.NoError(t, err)
+		require.NotEmpty(t, snapshot.Files)
+		for _, f := range snapshot.Files {
+			require.NotContains(t, f.Path, blockID.String())
+		}
+
+		// flushed blocks are not part of new snapshots
+		rec = httptest.NewRecorder()
+		ingester.SnapshotHandler(rec, httptest.NewRequest(http.MethodPost, "/snapshot", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		snapshot, err = r.IngesterSnapshot(ctx, "test", ingesterID)
+		require.NoError(t, err)
+		for _, f := range snapshot.Files {
+			require.NotContains(t, f.Path, blockID.String())
+		}
+	}
+}
+
+// walBlockCount returns the number of completing and complete blocks of the instance.
+func walBlockCount(inst *instance) int {
+	inst.blocksMtx.RLock()
+	defer inst.blocksMtx.RUnlock()
+
+	return len(inst.completingBlocks) + len(inst.completeBlocks)
+}
+
 func TestDedicatedColumns(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("/tmp", "")
 	require.NoError(t, err, "unexpected error getting tempdir")
@@ -424,11 +699,15 @@ func defaultIngesterModule(t testing.TB, tmpDir string) *Ingester {
 }
 
 func defaultIngesterStore(t testing.TB, tmpDir string) storage.Store {
+	return ingesterStore(t, tmpDir, tmpDir)
+}
+
+func ingesterStore(t testing.TB, backendDir, walDir string) storage.Store {
 	s, err := storage.NewStore(storage.Config{
 		Trace: tempodb.Config{
 			Backend: backend.Local,
 			Local: &local.Config{
-				Path: tmpDir,
+				Path: backendDir,
 			},
 			Block: &common.BlockConfig{
 				IndexDownsampleBytes: 2,
@@ -439,7 +718,7 @@ func defaultIngesterStore(t testing.TB, tmpDir string) storage.Store {
 				IndexPageSizeBytes:   1000,
 			},
 			WAL: &wal.Config{
-				Filepath: tmpDir,
+				Filepath: walDir,
 			},
 		},
 	}, nil, log.NewNopLogger())
@@ -511,6 +790,9 @@ func defaultIngesterTestConfig() Config {
 	cfg.FlushCheckPeriod = 99999 * time.Hour
 	cfg.MaxTraceIdle = 99999 * time.Hour
 	cfg.ConcurrentFlushes = 1
+	cfg.ConcurrentCompletes = 1
+	cfg.CompleteRetry = FlushRetryConfig{MaxAttempts: 3, MinBackoff: 30 * time.Second, MaxBackoff: 2 * time.Minute}
+	cfg.FlushRetry = FlushRetryConfig{MinBackoff: 30 * time.Second, MaxBackoff: 2 * time.Minute}
 	cfg.LifecyclerConfig.RingConfig.KVStore.Mock = mockStore
 	cfg.LifecyclerConfig.NumTokens = 1
 	cfg.LifecyclerConfig.ListenPort = 0
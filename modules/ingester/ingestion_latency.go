@@ -0,0 +1,74 @@
+package ingester
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Stages of the ingest path at which the spans of a trace become queryable.
+const (
+	stageLiveTraces    = "live_traces"
+	stageWALBlock      = "wal_block"
+	stageCompleteBlock = "complete_block"
+	stageBackend       = "backend"
+)
+
+var metricIngestionLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "tempo",
+	Name:      "ingester_ingestion_latency_seconds",
+	Help:      "The time from the end of the last span of a trace until it is queryable, per tenant and stage of the ingest path.",
+	Buckets:   prometheus.ExponentialBuckets(1, 2, 14), // from 1s up to ~2h
+}, []string{"tenant", "stage"})
+
+// traceEnds counts the traces written to a block by the unix second their last span ended. Traces of a block end
+// within a few seconds of each other, so the ingestion latency of every trace of a block can be observed once it
+// becomes queryable in the next stage without keeping a timestamp per trace.
+type traceEnds map[uint32]int
+
+func (e traceEnds) add(end uint32) {
+	e[end]++
+}
+
+func (e traceEnds) observe(instanceID, stage string, now time.Time) {
+	h := metricIngestionLatency.WithLabelValues(instanceID, stage)
+	for end, count := range e {
+		latency := ingestionLatency(end, now)
+		for j := 0; j < count; j++ {
+			h.Observe(latency)
+		}
+	}
+}
+
+// ingestionLatency returns the seconds since end. Spans ending in the future, which happens with clock skew between
+// the instrumented application and tempo, have no latency.
+func ingestionLatency(end uint32, now time.Time) float64 {
+	return max(now.Sub(time.Unix(int64(end), 0)).Seconds(), 0)
+}
+
+func observeIngestionLatency(instanceID, stage string, end uint32, now time.Time) {
+	metricIngestionLatency.WithLabelValues(instanceID, stage).Observe(ingestionLatency(end, now))
+}
+
+// observeBlockIngestionLatency observes the ingestion latency of the traces of a block that became queryable in the
+// given stage. Blocks replayed from the WAL after a restart are not tracked.
+func (i *instance) observeBlockIngestionLatency(blockID uuid.UUID, stage string) {
+	i.blockEndsMtx.Lock()
+	ends := i.blockEnds[blockID]
+	if stage == stageBackend {
+		// the last stage of the ingester
+		delete(i.blockEnds, blockID)
+	}
+	i.blockEndsMtx.Unlock()
+
+	ends.observe(i.instanceID, stage, time.Now())
+}
+
+func (i *instance) forgetBlockIngestionLatency(blockID uuid.UUID) {
+	i.blockEndsMtx.Lock()
+	defer i.blockEndsMtx.Unlock()
+
+	delete(i.blockEnds, blockID)
+}
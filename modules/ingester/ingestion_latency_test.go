@@ -0,0 +1,63 @@
+package ingester
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngestionLatency(t *testing.T) {
+	now := time.Unix(1000, 0)
+	require.Equal(t, 10.0, ingestionLatency(990, now))
+	require.Equal(t, 0.0, ingestionLatency(1010, now))
+}
+
+func TestInstanceIngestionLatency(t *testing.T) {
+	i, ingester := defaultInstance(t)
+
+	counts := func() map[string]uint64 {
+		res := map[string]uint64{}
+		for _, stage := range []string{stageLiveTraces, stageWALBlock, stageCompleteBlock, stageBackend} {
+			m := &dto.Metric{}
+			require.NoError(t, metricIngestionLatency.WithLabelValues(testTenantID, stage).(prometheus.Histogram).Write(m))
+			res[stage] = m.Histogram.GetSampleCount()
+		}
+		return res
+	}
+	start := counts()
+	requireObserved := func(stage string, expected uint64) {
+		require.Equal(t, expected, counts()[stage]-start[stage], stage)
+	}
+
+	traces := 3
+	for j := 0; j < traces; j++ {
+		response := i.PushBytesRequest(context.Background(), makeRequest([]byte{}))
+		errored, _, _ := CheckPushBytesError(response)
+		require.False(t, errored)
+	}
+	requireObserved(stageLiveTraces, uint64(traces))
+	requireObserved(stageWALBlock, 0)
+
+	require.NoError(t, i.CutCompleteTraces(0, true))
+	requireObserved(stageWALBlock, uint64(traces))
+
+	blockID, err := i.CutBlockIfReady(0, 0, true)
+	require.NoError(t, err)
+	require.NotEqual(t, uuid.Nil, blockID)
+	require.Len(t, i.blockEnds, 1)
+
+	require.NoError(t, i.CompleteBlock(blockID))
+	requireObserved(stageCompleteBlock, uint64(traces))
+	requireObserved(stageBackend, 0)
+
+	retry, err := ingester.handleFlush(context.Background(), testTenantID, blockID)
+	require.NoError(t, err)
+	require.False(t, retry)
+	requireObserved(stageBackend, uint64(traces))
+	require.Empty(t, i.blockEnds)
+}
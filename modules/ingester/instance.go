@@ -19,6 +19,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.uber.org/atomic"
+	"go.uber.org/multierr"
 	"google.golang.org/grpc/codes"
 
 	"github.com/grafana/tempo/modules/overrides"
@@ -45,6 +46,12 @@ func newTraceTooLargeError(traceID common.ID, instanceID string, maxBytes, reqSi
 	return errTraceTooLarge
 }
 
+func newMaxLiveTracesBytesError(instanceID string, maxBytes, liveBytes, reqSize int) error {
+	level.Warn(log.Logger).Log("msg", fmt.Sprintf("%s: max live traces bytes (%d) exceeded while adding %d bytes to %d live bytes for tenant %s",
+		overrides.ErrorPrefixLiveTracesExceeded, maxBytes, reqSize, liveBytes, instanceID))
+	return errMaxLiveTraces
+}
+
 func newMaxLiveTracesError(instanceID string, limit string) error {
 	level.Warn(log.Logger).Log("msg", fmt.Sprintf("%s: max live traces exceeded for tenant %s: %v", overrides.ErrorPrefixLiveTracesExceeded, instanceID, limit))
 	return errMaxLiveTraces
@@ -65,6 +72,16 @@ var (
 		Name:      "ingester_live_traces",
 		Help:      "The current number of lives traces per tenant.",
 	}, []string{"tenant"})
+	metricLiveTraceBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "ingester_live_trace_bytes",
+		Help:      "The current size in bytes of all live traces per tenant.",
+	}, []string{"tenant"})
+	metricTracesSpilledTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_traces_spilled_total",
+		Help:      "The total number of live traces written to the WAL early to stay within the live traces bytes limit.",
+	}, []string{"tenant"})
 	metricBlocksClearedTotal = promauto.NewCounter(prometheus.CounterOpts{
 		Namespace: "tempo",
 		Name:      "ingester_blocks_cleared_total",
@@ -85,11 +102,19 @@ var (
 type instance struct {
 	tracesMtx  sync.Mutex
 	traces     map[uint32]*liveTrace
-	traceSizes map[uint32]uint32
+	traceUsage map[uint32]traceUsage
 	traceCount atomic.Int32
+	traceBytes atomic.Int64 // size of all live traces
+	// cleared is set when the instance is purged, so the live trace metrics of the tenant aren't set again
+	cleared bool
+
+	headBlockMtx  sync.RWMutex
+	headBlock     common.WALBlock
+	headBlockEnds traceEnds
 
-	headBlockMtx sync.RWMutex
-	headBlock    common.WALBlock
+	// ends of the traces of the blocks cut from the head block, used to track the ingestion latency
+	blockEndsMtx sync.Mutex
+	blockEnds    map[uuid.UUID]traceEnds
 
 	blocksMtx        sync.RWMutex
 	completingBlocks []common.WALBlock
@@ -97,6 +122,9 @@ type instance struct {
 
 	lastBlockCut time.Time
 
+	// walSnapshot is false once it's known that there is no WAL snapshot of the instance to prune
+	walSnapshot atomic.Bool
+
 	instanceID         string
 	tracesCreatedTotal prometheus.Counter
 	bytesReceivedTotal *prometheus.CounterVec
@@ -105,6 +133,8 @@ type instance struct {
 
 	dedicatedColumns backend.DedicatedColumns
 	overrides        ingesterOverrides
+	spillCfg         LiveTracesSpillConfig
+	searchCfg        SearchConfig
 
 	local       *local.Backend
 	localReader backend.Reader
@@ -113,10 +143,11 @@ type instance struct {
 	hash hash.Hash32
 }
 
-func newInstance(instanceID string, limiter *Limiter, overrides ingesterOverrides, writer tempodb.Writer, l *local.Backend, dedicatedColumns backend.DedicatedColumns) (*instance, error) {
+func newInstance(instanceID string, limiter *Limiter, overrides ingesterOverrides, writer tempodb.Writer, l *local.Backend, dedicatedColumns backend.DedicatedColumns, spillCfg LiveTracesSpillConfig, searchCfg SearchConfig) (*instance, error) {
 	i := &instance{
 		traces:     map[uint32]*liveTrace{},
-		traceSizes: map[uint32]uint32{},
+		traceUsage: map[uint32]traceUsage{},
+		blockEnds:  map[uuid.UUID]traceEnds{},
 
 		instanceID:         instanceID,
 		tracesCreatedTotal: metricTracesCreatedTotal.WithLabelValues(instanceID),
@@ -126,6 +157,8 @@ func newInstance(instanceID string, limiter *Limiter, overrides ingesterOverride
 
 		dedicatedColumns: dedicatedColumns,
 		overrides:        overrides,
+		spillCfg:         spillCfg,
+		searchCfg:        searchCfg,
 
 		local:       l,
 		localReader: backend.NewReader(l),
@@ -133,6 +166,9 @@ func newInstance(instanceID string, limiter *Limiter, overrides ingesterOverride
 
 		hash: fnv.New32(),
 	}
+	// a snapshot may have been taken before a restart
+	i.walSnapshot.Store(true)
+
 	err := i.resetHeadBlock()
 	if err != nil {
 		return nil, err
@@ -197,24 +233,74 @@ func (i *instance) PushBytes(ctx context.Context, id, traceBytes []byte) error {
 		return newMaxLiveTracesError(i.instanceID, err.Error())
 	}
 
+	err = i.assertMaxLiveTracesBytes(len(traceBytes))
+	if err != nil {
+		return err
+	}
+
 	return i.push(ctx, id, traceBytes)
 }
 
+// assertMaxLiveTracesBytes checks that adding reqSize bytes keeps the live traces of the tenant within
+// the configured limit. If spilling is enabled, idle traces are written to the head block to make room
+// before the push is refused.
+func (i *instance) assertMaxLiveTracesBytes(reqSize int) error {
+	maxBytes := i.limiter.limits.MaxLiveTracesBytes(i.instanceID)
+	if maxBytes <= 0 {
+		return nil
+	}
+
+	liveBytes := int(i.traceBytes.Load())
+	if liveBytes+reqSize <= maxBytes {
+		return nil
+	}
+
+	if i.spillCfg.Enabled {
+		err := i.SpillTraces(liveBytes + reqSize - maxBytes)
+		if err != nil {
+			level.Error(log.WithUserID(i.instanceID, log.Logger)).Log("msg", "failed to spill live traces", "err", err)
+		}
+
+		liveBytes = int(i.traceBytes.Load())
+		if liveBytes+reqSize <= maxBytes {
+			return nil
+		}
+	}
+
+	return newMaxLiveTracesBytesError(i.instanceID, maxBytes, liveBytes, reqSize)
+}
+
 func (i *instance) push(ctx context.Context, id, traceBytes []byte) error {
 	i.tracesMtx.Lock()
 	defer i.tracesMtx.Unlock()
 
+	// pushes that got the instance before it was purged
+	if i.cleared {
+		return errTenantDeleted
+	}
+
 	tkn := i.tokenForTraceID(id)
-	maxBytes := i.limiter.limits.MaxBytesPerTrace(i.instanceID)
+	limits := i.traceLimits()
 
-	if maxBytes > 0 {
-		prevSize := int(i.traceSizes[tkn])
-		reqSize := len(traceBytes)
-		if prevSize+reqSize > maxBytes {
-			return newTraceTooLargeError(id, i.instanceID, maxBytes, reqSize)
+	var usage traceUsage
+	if limits.enabled() {
+		var err error
+		traceBytes, usage, err = i.applyTraceLimits(id, traceBytes, i.traceUsage[tkn], limits)
+		if err != nil {
+			return err
+		}
+		if traceBytes == nil {
+			// all spans were dropped from the truncated trace
+			i.traceUsage[tkn] = usage
+			return nil
 		}
 	}
 
+	maxBytes := limits.maxBytes
+	if limits.truncate {
+		// the segment is already truncated to the limit
+		maxBytes = 0
+	}
 	trace := i.getOrCreateTrace(id, tkn, maxBytes)
 
 	err := trace.Push(ctx, i.instanceID, traceBytes)
@@ -222,9 +308,11 @@ func (i *instance) push(ctx context.Context, id, traceBytes []byte) error {
 		return err
 	}
 
-	if maxBytes > 0 {
-		i.traceSizes[tkn] += uint32(len(traceBytes))
+	if limits.enabled() {
+		i.traceUsage[tkn] = usage
 	}
+	i.traceBytes.Add(int64(len(traceBytes)))
+	observeIngestionLatency(i.instanceID, stageLiveTraces, trace.segmentEnd, time.Now())
 
 	return nil
 }
@@ -239,6 +327,23 @@ func (i *instance) measureReceivedBytes(traceBytes []byte) {
 // CutCompleteTraces moves any complete traces out of the map to complete traces.
 func (i *instance) CutCompleteTraces(cutoff time.Duration, immediate bool) error {
 	tracesToCut := i.tracesToCut(cutoff, immediate)
+	return i.writeTracesToHeadBlock(tracesToCut)
+}
+
+// SpillTraces writes the largest live traces that have been idle for at least the configured minimum
+// to the head block until at least the requested number of bytes has been freed. A trace that receives
+// more spans after being spilled is written to the head block again once it is complete.
+func (i *instance) SpillTraces(bytesToFree int) error {
+	tracesToSpill := i.tracesToSpill(bytesToFree)
+	if len(tracesToSpill) == 0 {
+		return nil
+	}
+
+	metricTracesSpilledTotal.WithLabelValues(i.instanceID).Add(float64(len(tracesToSpill)))
+	return i.writeTracesToHeadBlock(tracesToSpill)
+}
+
+func (i *instance) writeTracesToHeadBlock(tracesToCut []*liveTrace) error {
 	segmentDecoder := model.MustNewSegmentDecoder(model.CurrentEncoding)
 
 	// Sort by ID
@@ -267,7 +372,16 @@ func (i *instance) CutCompleteTraces(cutoff time.Duration, immediate bool) error
 
 	i.headBlockMtx.Lock()
 	defer i.headBlockMtx.Unlock()
-	return i.headBlock.Flush()
+	if err := i.headBlock.Flush(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, t := range tracesToCut {
+		i.headBlockEnds.add(t.end)
+		observeIngestionLatency(i.instanceID, stageWALBlock, t.end, now)
+	}
+	return nil
 }
 
 // CutBlockIfReady cuts a completingBlock from the HeadBlock if ready.
@@ -302,6 +416,10 @@ func (i *instance) CutBlockIfReady(maxBlockLifetime time.Duration, maxBlockBytes
 
 		i.completingBlocks = append(i.completingBlocks, completingBlock)
 
+		i.blockEndsMtx.Lock()
+		i.blockEnds[completingBlock.BlockMeta().BlockID] = i.headBlockEnds
+		i.blockEndsMtx.Unlock()
+
 		err = i.resetHeadBlock()
 		if err != nil {
 			return uuid.Nil, fmt.Errorf("failed to resetHeadBlock: %w", err)
@@ -342,6 +460,7 @@ func (i *instance) CompleteBlock(blockID uuid.UUID) error {
 	i.completeBlocks = append(i.completeBlocks, ingesterBlock)
 	i.blocksMtx.Unlock()
 
+	i.observeBlockIngestionLatency(blockID, stageCompleteBlock)
 	return nil
 }
 
@@ -365,6 +484,25 @@ func (i *instance) ClearCompletingBlock(blockID uuid.UUID) error {
 	return errors.New("Error finding wal completingBlock to clear")
 }
 
+// hasBlock returns true if the block is a completing or complete block of the instance. Blocks are compared by
+// identity, a completed block has the id of the completing block it replaces.
+func (i *instance) hasBlock(block common.Searcher) bool {
+	i.blocksMtx.RLock()
+	defer i.blocksMtx.RUnlock()
+
+	for _, b := range i.completingBlocks {
+		if common.Searcher(b) == block {
+			return true
+		}
+	}
+	for _, b := range i.completeBlocks {
+		if common.Searcher(b) == block {
+			return true
+		}
+	}
+	return false
+}
+
 // GetBlockToBeFlushed gets a list of blocks that can be flushed to the backend.
 func (i *instance) GetBlockToBeFlushed(blockID uuid.UUID) *LocalBlock {
 	i.blocksMtx.RLock()
@@ -393,6 +531,7 @@ func (i *instance) ClearFlushedBlocks(completeBlockTimeout time.Duration) error
 
 		if flushedTime.Add(completeBlockTimeout).Before(time.Now()) {
 			i.completeBlocks = append(i.completeBlocks[:idx], i.completeBlocks[idx+1:]...)
+			i.forgetBlockIngestionLatency(b.BlockMeta().BlockID)
 
 			err = i.local.ClearBlock(b.BlockMeta().BlockID, i.instanceID)
 			if err == nil {
@@ -405,6 +544,52 @@ func (i *instance) ClearFlushedBlocks(completeBlockTimeout time.Duration) error
 	return err
 }
 
+// clear discards everything held by the instance: live traces, the head block, completing blocks and
+// complete blocks. It's used to purge the data of a deleted tenant.
+func (i *instance) clear() error {
+	var errs []error
+
+	i.tracesMtx.Lock()
+	i.traces = map[uint32]*liveTrace{}
+	i.traceUsage = map[uint32]traceUsage{}
+	i.traceCount.Store(0)
+	i.traceBytes.Store(0)
+	i.cleared = true
+	metricLiveTraces.DeleteLabelValues(i.instanceID)
+	metricLiveTraceBytes.DeleteLabelValues(i.instanceID)
+	i.tracesMtx.Unlock()
+
+	i.headBlockMtx.Lock()
+	if i.headBlock != nil {
+		errs = append(errs, i.headBlock.Clear())
+	}
+	i.headBlockEnds = traceEnds{}
+	i.headBlockMtx.Unlock()
+
+	i.blocksMtx.Lock()
+	for _, b := range i.completingBlocks {
+		errs = append(errs, b.Clear())
+	}
+	for _, b := range i.completeBlocks {
+		err := i.local.ClearBlock(b.BlockMeta().BlockID, i.instanceID)
+		if err == nil {
+			metricBlocksClearedTotal.Inc()
+		}
+		errs = append(errs, err)
+	}
+	i.completingBlocks = nil
+	i.completeBlocks = nil
+	i.blocksMtx.Unlock()
+
+	i.blockEndsMtx.Lock()
+	i.blockEnds = map[uuid.UUID]traceEnds{}
+	i.blockEndsMtx.Unlock()
+
+	metricIngestionLatency.DeletePartialMatch(prometheus.Labels{"tenant": i.instanceID})
+
+	return multierr.Combine(errs...)
+}
+
 func (i *instance) FindTraceByID(ctx context.Context, id []byte) (*tempopb.Trace, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "instance.FindTraceByID")
 	defer span.Finish()
@@ -510,9 +695,9 @@ func (i *instance) tokenForTraceID(id []byte) uint32 {
 
 // resetHeadBlock() should be called under lock
 func (i *instance) resetHeadBlock() error {
-	// Reset trace sizes when cutting block
+	// Reset trace usage when cutting block
 	i.tracesMtx.Lock()
-	i.traceSizes = make(map[uint32]uint32, len(i.traceSizes))
+	i.traceUsage = make(map[uint32]traceUsage, len(i.traceUsage))
 	i.tracesMtx.Unlock()
 
 	dedicatedColumns := i.getDedicatedColumns()
@@ -528,6 +713,7 @@ func (i *instance) resetHeadBlock() error {
 	}
 
 	i.headBlock = newHeadBlock
+	i.headBlockEnds = traceEnds{}
 	i.lastBlockCut = time.Now()
 
 	return nil
@@ -550,6 +736,10 @@ func (i *instance) tracesToCut(cutoff time.Duration, immediate bool) []*liveTrac
 	i.tracesMtx.Lock()
 	defer i.tracesMtx.Unlock()
 
+	if i.cleared {
+		return nil
+	}
+
 	// Set this before cutting to give a more accurate number.
 	metricLiveTraces.WithLabelValues(i.instanceID).Set(float64(len(i.traces)))
 
@@ -560,13 +750,59 @@ func (i *instance) tracesToCut(cutoff time.Duration, immediate bool) []*liveTrac
 		if cutoffTime.After(trace.lastAppend) || immediate {
 			tracesToCut = append(tracesToCut, trace)
 			delete(i.traces, key)
+			i.traceBytes.Sub(int64(trace.currentBytes))
 		}
 	}
 	i.traceCount.Store(int32(len(i.traces)))
+	metricLiveTraceBytes.WithLabelValues(i.instanceID).Set(float64(i.traceBytes.Load()))
 
 	return tracesToCut
 }
 
+func (i *instance) tracesToSpill(bytesToFree int) []*liveTrace {
+	i.tracesMtx.Lock()
+	defer i.tracesMtx.Unlock()
+
+	if i.cleared {
+		return nil
+	}
+
+	type candidate struct {
+		key   uint32
+		trace *liveTrace
+	}
+
+	idleTime := time.Now().Add(-i.spillCfg.MinIdle)
+	candidates := make([]candidate, 0, len(i.traces))
+	for key, trace := range i.traces {
+		if idleTime.After(trace.lastAppend) {
+			candidates = append(candidates, candidate{key: key, trace: trace})
+		}
+	}
+
+	// largest first to free the requested bytes with as few traces as possible
+	sort.Slice(candidates, func(a, b int) bool {
+		return candidates[a].trace.currentBytes > candidates[b].trace.currentBytes
+	})
+
+	var tracesToSpill []*liveTrace
+	freed := 0
+	for _, c := range candidates {
+		if freed >= bytesToFree {
+			break
+		}
+
+		tracesToSpill = append(tracesToSpill, c.trace)
+		delete(i.traces, c.key)
+		i.traceBytes.Sub(int64(c.trace.currentBytes))
+		freed += c.trace.currentBytes
+	}
+	i.traceCount.Store(int32(len(i.traces)))
+	metricLiveTraceBytes.WithLabelValues(i.instanceID).Set(float64(i.traceBytes.Load()))
+
+	return tracesToSpill
+}
+
 func (i *instance) writeTraceToHeadBlock(id common.ID, b []byte, start, end uint32) error {
 	i.headBlockMtx.Lock()
 	defer i.headBlockMtx.Unlock()
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	ot_log "github.com/opentracing/opentracing-go/log"
@@ -47,7 +50,7 @@ func (i *instance) Search(ctx context.Context, req *tempopb.SearchRequest) (*tem
 		anyErr     atomic.Error
 	)
 
-	search := func(blockID uuid.UUID, block common.Searcher, spanName string) {
+	search := func(blockID uuid.UUID, block common.Searcher, spanName string, yieldPause time.Duration) {
 		span, ctx := opentracing.StartSpanFromContext(ctx, "instance.searchBlock."+spanName)
 		defer span.Finish()
 
@@ -60,8 +63,14 @@ func (i *instance) Search(ctx context.Context, req *tempopb.SearchRequest) (*tem
 		if api.IsTraceQLQuery(req) {
 			// note: we are creating new engine for each wal block,
 			// and engine.ExecuteSearch is parsing the query for each block
+			throttle := &searchThrottle{interval: i.searchCfg.YieldInterval, pause: yieldPause, start: time.Now()}
 			resp, err = traceql.NewEngine().ExecuteSearch(ctx, req, traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
-				return block.Fetch(ctx, req, opts)
+				resp, err := block.Fetch(ctx, req, opts)
+				if err != nil {
+					return resp, err
+				}
+				resp.Results = throttle.wrap(resp.Results)
+				return resp, nil
 			}))
 		} else {
 			resp, err = block.Search(ctx, req, opts)
@@ -75,6 +84,11 @@ func (i *instance) Search(ctx context.Context, req *tempopb.SearchRequest) (*tem
 			// Ignore
 			return
 		}
+		if err != nil && spanName != "headBlock" && !i.hasBlock(block) {
+			// the block was completed or cleared while it was searched, its files may be gone
+			level.Debug(log.Logger).Log("msg", "ignoring error searching removed block", "blockID", blockID, "err", err)
+			return
+		}
 		if err != nil {
 			level.Error(log.Logger).Log("msg", "error searching block", "blockID", blockID, "err", err)
 			anyErr.Store(err)
@@ -117,7 +131,8 @@ func (i *instance) Search(ctx context.Context, req *tempopb.SearchRequest) (*tem
 	i.headBlockMtx.RLock()
 	span.LogFields(ot_log.String("msg", "acquired headblock mtx"))
 	if includeBlock(i.headBlock.BlockMeta(), req) {
-		search(i.headBlock.BlockMeta().BlockID, i.headBlock, "headBlock")
+		// never pause while holding the head block lock, appends are waiting for it
+		search(i.headBlock.BlockMeta().BlockID, i.headBlock, "headBlock", 0)
 	}
 	i.headBlockMtx.RUnlock()
 	if err := anyErr.Load(); err != nil {
@@ -131,16 +146,22 @@ func (i *instance) Search(ctx context.Context, req *tempopb.SearchRequest) (*tem
 	}
 
 	// Search all other blocks (concurrently)
-	// Lock blocks mutex until all search tasks are finished and this function exits. This avoids
-	// deadlocking with other activity (ingest, flushing), caused by releasing
-	// and then attempting to retake the lock.
+	// The blocks are copied and the blocks mutex released before searching. Searches can pause to
+	// yield, holding the mutex would delay completing and clearing blocks and, as writers have
+	// priority, every other reader.
 	i.blocksMtx.RLock()
-	defer i.blocksMtx.RUnlock()
 	span.LogFields(ot_log.String("msg", "acquired blocks mtx"))
+	completingBlocks := slices.Clone(i.completingBlocks)
+	completeBlocks := slices.Clone(i.completeBlocks)
+	i.blocksMtx.RUnlock()
 
-	wg := sync.WaitGroup{}
+	maxConcurrentBlocks := i.searchCfg.MaxConcurrentBlocks
+	if maxConcurrentBlocks <= 0 {
+		maxConcurrentBlocks = len(completingBlocks) + len(completeBlocks) + 1
+	}
+	wg := boundedwaitgroup.New(uint(maxConcurrentBlocks))
 
-	for _, b := range i.completingBlocks {
+	for _, b := range completingBlocks {
 		if !includeBlock(b.BlockMeta(), req) {
 			continue
 		}
@@ -148,18 +169,18 @@ func (i *instance) Search(ctx context.Context, req *tempopb.SearchRequest) (*tem
 		wg.Add(1)
 		go func(b common.WALBlock) {
 			defer wg.Done()
-			search(b.BlockMeta().BlockID, b, "completingBlock")
+			search(b.BlockMeta().BlockID, b, "completingBlock", i.searchCfg.YieldPause)
 		}(b)
 	}
 
-	for _, b := range i.completeBlocks {
+	for _, b := range completeBlocks {
 		if !includeBlock(b.BlockMeta(), req) {
 			continue
 		}
 		wg.Add(1)
 		go func(b *LocalBlock) {
 			defer wg.Done()
-			search(b.BlockMeta().BlockID, b, "completeBlock")
+			search(b.BlockMeta().BlockID, b, "completeBlock", i.searchCfg.YieldPause)
 		}(b)
 	}
 
@@ -487,6 +508,59 @@ func (i *instance) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTag
 }
 
 // includeBlock uses the provided time range to determine if the block should be included in the search.
+// searchThrottle cooperatively limits the CPU used by a search. After iterating for interval it yields the
+// processor so appends and other searches get scheduled, and sleeps for pause if set.
+type searchThrottle struct {
+	interval time.Duration
+	pause    time.Duration
+	start    time.Time
+}
+
+func (t *searchThrottle) wrap(iter traceql.SpansetIterator) traceql.SpansetIterator {
+	if t.interval <= 0 || iter == nil {
+		return iter
+	}
+	return &throttledSpansetIterator{iter: iter, throttle: t}
+}
+
+func (t *searchThrottle) yield(ctx context.Context) error {
+	if time.Since(t.start) < t.interval {
+		return nil
+	}
+
+	if t.pause > 0 {
+		timer := time.NewTimer(t.pause)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	} else {
+		runtime.Gosched()
+	}
+
+	t.start = time.Now()
+	return nil
+}
+
+type throttledSpansetIterator struct {
+	iter     traceql.SpansetIterator
+	throttle *searchThrottle
+}
+
+func (i *throttledSpansetIterator) Next(ctx context.Context) (*traceql.Spanset, error) {
+	if err := i.throttle.yield(ctx); err != nil {
+		return nil, err
+	}
+	return i.iter.Next(ctx)
+}
+
+func (i *throttledSpansetIterator) Close() {
+	i.iter.Close()
+}
+
 func includeBlock(b *backend.BlockMeta, req *tempopb.SearchRequest) bool {
 	start := int64(req.Start)
 	end := int64(req.End)
@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/uber-go/atomic"
+	"golang.org/x/sync/semaphore"
 
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/model"
@@ -940,3 +941,114 @@ func TestIncludeBlock(t *testing.T) {
 		})
 	}
 }
+
+func TestInstanceSearchThrottled(t *testing.T) {
+	i, _ := defaultInstance(t)
+	i.searchCfg = SearchConfig{
+		MaxConcurrentBlocks: 1,
+		YieldInterval:       time.Nanosecond,
+		YieldPause:          time.Microsecond,
+	}
+
+	req := &tempopb.SearchRequest{Query: `{ .service.name = "test-service" }`, Limit: 100, SpansPerSpanSet: 10}
+
+	// spread traces over a complete block, a completing block and the head block
+	var ids [][]byte
+	for j := 0; j < 3; j++ {
+		_, batchIDs := pushTracesToInstance(t, i, 5)
+		ids = append(ids, batchIDs...)
+		require.NoError(t, i.CutCompleteTraces(0, true))
+
+		if j == 2 {
+			break
+		}
+
+		blockID, err := i.CutBlockIfReady(0, 0, true)
+		require.NoError(t, err)
+		if j == 0 {
+			require.NoError(t, i.CompleteBlock(blockID))
+			require.NoError(t, i.ClearCompletingBlock(blockID))
+		}
+	}
+	require.Len(t, i.completeBlocks, 1)
+	require.Len(t, i.completingBlocks, 1)
+
+	sr, err := i.Search(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, sr.Traces, len(ids))
+	checkEqual(t, ids, sr)
+}
+
+func TestInstanceSearchThrottledReleasesBlocksLock(t *testing.T) {
+	i, _ := defaultInstance(t)
+	i.searchCfg = SearchConfig{
+		YieldInterval: time.Nanosecond,
+		YieldPause:    time.Second,
+	}
+
+	pushTracesToInstance(t, i, 5)
+	require.NoError(t, i.CutCompleteTraces(0, true))
+	blockID, err := i.CutBlockIfReady(0, 0, true)
+	require.NoError(t, err)
+	require.NoError(t, i.CompleteBlock(blockID))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		_, err := i.Search(ctx, &tempopb.SearchRequest{Query: `{ .service.name = "test-service" }`, Limit: 100})
+		done <- err
+	}()
+
+	// the completing and complete blocks can be cleared while the search pauses
+	time.Sleep(100 * time.Millisecond)
+	start := time.Now()
+	require.NoError(t, i.ClearCompletingBlock(blockID))
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestSearchThrottle(t *testing.T) {
+	throttle := &searchThrottle{interval: time.Hour, pause: time.Hour, start: time.Now()}
+
+	// interval not reached
+	require.NoError(t, throttle.yield(context.Background()))
+
+	// pauses once the interval is reached
+	throttle = &searchThrottle{interval: time.Millisecond, pause: 10 * time.Millisecond, start: time.Now().Add(-time.Second)}
+	start := time.Now()
+	require.NoError(t, throttle.yield(context.Background()))
+	require.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	require.True(t, throttle.start.After(start))
+
+	// pausing stops when the search is cancelled
+	throttle = &searchThrottle{interval: time.Millisecond, pause: time.Hour, start: time.Now().Add(-time.Second)}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, throttle.yield(ctx), context.Canceled)
+}
+
+func TestAcquireQuery(t *testing.T) {
+	// no limit
+	release, err := acquireQuery(context.Background(), nil)
+	require.NoError(t, err)
+	release()
+
+	sem := semaphore.NewWeighted(1)
+	release, err = acquireQuery(context.Background(), sem)
+	require.NoError(t, err)
+
+	// waits until the first query is released
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = acquireQuery(ctx, sem)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	release()
+	release, err = acquireQuery(context.Background(), sem)
+	require.NoError(t, err)
+	release()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	crand "crypto/rand"
 	"encoding/binary"
+	"fmt"
 	"math/rand"
 	"sync"
 	"testing"
@@ -578,6 +579,162 @@ func TestInstanceFailsLargeTracesEvenAfterFlushing(t *testing.T) {
 	require.False(t, errored, "push failed: %w", response.ErrorsByTrace)
 }
 
+func TestInstanceMaxSpansPerTrace(t *testing.T) {
+	ctx := context.Background()
+
+	for _, truncate := range []bool{false, true} {
+		t.Run(fmt.Sprintf("truncate=%t", truncate), func(t *testing.T) {
+			limits, err := overrides.NewOverrides(overrides.Config{
+				Defaults: overrides.Overrides{
+					Ingestion: overrides.IngestionOverrides{
+						MaxSpansPerTrace:    15,
+						TruncateLargeTraces: truncate,
+					},
+				},
+			}, nil, prometheus.NewRegistry())
+			require.NoError(t, err)
+
+			ingester, _, _ := defaultIngester(t, t.TempDir())
+			ingester.limiter = NewLimiter(limits, &ringCountMock{count: 1}, 1)
+			delete(ingester.instances, testTenantID)
+			i, err := ingester.getOrCreateInstance(testTenantID)
+			require.NoError(t, err)
+
+			id := test.ValidTraceID(nil)
+
+			// 10 spans fit
+			response := i.PushBytesRequest(ctx, makeRequest(id))
+			errored, _, _ := CheckPushBytesError(response)
+			require.False(t, errored, "push failed: %w", response.ErrorsByTrace)
+
+			// 20 spans don't
+			response = i.PushBytesRequest(ctx, makeRequest(id))
+			errored, _, traceTooLargeCount := CheckPushBytesError(response)
+			if !truncate {
+				require.Equal(t, 1, traceTooLargeCount)
+
+				tr, err := i.FindTraceByID(ctx, id)
+				require.NoError(t, err)
+				require.Equal(t, 10, countSpans(tr))
+				return
+			}
+			require.False(t, errored, "push failed: %w", response.ErrorsByTrace)
+
+			// Spans pushed after truncating are dropped
+			response = i.PushBytesRequest(ctx, makeRequest(id))
+			errored, _, _ = CheckPushBytesError(response)
+			require.False(t, errored, "push failed: %w", response.ErrorsByTrace)
+
+			tr, err := i.FindTraceByID(ctx, id)
+			require.NoError(t, err)
+			require.Equal(t, 15, countSpans(tr))
+			require.Equal(t, 5, countTruncatedSpans(tr))
+		})
+	}
+}
+
+func TestInstanceTruncatesLargeTraces(t *testing.T) {
+	ctx := context.Background()
+	maxTraceBytes := 1500
+
+	limits, err := overrides.NewOverrides(overrides.Config{
+		Defaults: overrides.Overrides{
+			Ingestion: overrides.IngestionOverrides{
+				TruncateLargeTraces: true,
+			},
+			Global: overrides.GlobalOverrides{
+				MaxBytesPerTrace: maxTraceBytes,
+			},
+		},
+	}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	ingester, _, _ := defaultIngester(t, t.TempDir())
+	ingester.limiter = NewLimiter(limits, &ringCountMock{count: 1}, 1)
+	delete(ingester.instances, testTenantID)
+	i, err := ingester.getOrCreateInstance(testTenantID)
+	require.NoError(t, err)
+
+	id := test.ValidTraceID(nil)
+	push := func() traceUsage {
+		response := i.PushBytesRequest(ctx, makeRequestWithSpans(id, 20))
+		errored, _, _ := CheckPushBytesError(response)
+		require.False(t, errored, "push failed: %w", response.ErrorsByTrace)
+		return i.traceUsage[i.tokenForTraceID(id)]
+	}
+
+	require.False(t, push().truncated)
+
+	// The second push is truncated and the third one is dropped
+	usage := push()
+	require.True(t, usage.truncated)
+	require.Equal(t, usage, push())
+
+	tr, err := i.FindTraceByID(ctx, id)
+	require.NoError(t, err)
+	require.Greater(t, countTruncatedSpans(tr), 0)
+	require.Less(t, countTruncatedSpans(tr), countSpans(tr))
+}
+
+func TestInstanceLiveTracesBytesLimit(t *testing.T) {
+	ctx := context.Background()
+
+	limits, err := overrides.NewOverrides(overrides.Config{
+		Defaults: overrides.Overrides{
+			Ingestion: overrides.IngestionOverrides{
+				MaxLiveTracesBytes: 1500,
+			},
+		},
+	}, nil, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+	limiter := NewLimiter(limits, &ringCountMock{count: 1}, 1)
+
+	for _, spill := range []bool{false, true} {
+		t.Run(fmt.Sprintf("spill=%t", spill), func(t *testing.T) {
+			ingester, _, _ := defaultIngester(t, t.TempDir())
+			ingester.limiter = limiter
+			ingester.cfg.LiveTracesSpill = LiveTracesSpillConfig{
+				Enabled: spill,
+				MinIdle: time.Millisecond,
+			}
+			delete(ingester.instances, testTenantID)
+			i, err := ingester.getOrCreateInstance(testTenantID)
+			require.NoError(t, err)
+
+			ids := [][]byte{test.ValidTraceID(nil), test.ValidTraceID(nil), test.ValidTraceID(nil)}
+
+			for _, id := range ids[:2] {
+				response := i.PushBytesRequest(ctx, makeRequestWithByteLimit(600, id))
+				errored, _, _ := CheckPushBytesError(response)
+				require.False(t, errored, "push failed: %w", response.ErrorsByTrace)
+			}
+			require.Equal(t, 2, len(i.traces))
+
+			time.Sleep(5 * time.Millisecond)
+
+			response := i.PushBytesRequest(ctx, makeRequestWithByteLimit(600, ids[2]))
+			errored, maxLiveCount, _ := CheckPushBytesError(response)
+			if !spill {
+				require.True(t, errored)
+				require.Equal(t, 1, maxLiveCount)
+				require.Equal(t, 2, len(i.traces))
+				return
+			}
+
+			require.False(t, errored, "push failed: %w", response.ErrorsByTrace)
+			require.Equal(t, 2, len(i.traces))
+			require.LessOrEqual(t, i.traceBytes.Load(), int64(1500))
+
+			// spilled trace can still be found in the head block
+			for _, id := range ids {
+				trace, err := i.FindTraceByID(ctx, id)
+				require.NoError(t, err)
+				require.NotNil(t, trace)
+			}
+		})
+	}
+}
+
 func TestInstancePartialSuccess(t *testing.T) {
 	ctx := context.Background()
 	maxTraceBytes := 1000
@@ -937,6 +1094,25 @@ func BenchmarkInstanceContention(t *testing.B) {
 	report(searchTags, "searchTags")
 }
 
+// makeRequestWithSpans generates a request with n spans of the same size and random ids
+func makeRequestWithSpans(traceID []byte, n int) *tempopb.PushBytesRequest {
+	traceID = test.ValidTraceID(traceID)
+	batch := test.MakeBatch(0, traceID)
+	ss := &v1_trace.ScopeSpans{}
+	for j := 0; j < n; j++ {
+		ss.Spans = append(ss.Spans, &v1_trace.Span{
+			TraceId:           traceID,
+			SpanId:            test.ValidTraceID(nil)[:8],
+			Name:              "span",
+			StartTimeUnixNano: uint64(time.Now().UnixNano()),
+			EndTimeUnixNano:   uint64(time.Now().UnixNano()),
+		})
+	}
+	batch.ScopeSpans = append(batch.ScopeSpans, ss)
+
+	return makePushBytesRequest(traceID, batch)
+}
+
 func makeBatchWithMaxBytes(maxBytes int, traceID []byte) *v1_trace.ResourceSpans {
 	traceID = test.ValidTraceID(traceID)
 	batch := test.MakeBatch(1, traceID)
@@ -988,6 +1164,22 @@ func makePushBytesRequestMultiTraces(traceIDs [][]byte, maxBytes []int) *tempopb
 	}
 }
 
+func countTruncatedSpans(tr *tempopb.Trace) int {
+	count := 0
+	for _, b := range tr.Batches {
+		for _, ss := range b.ScopeSpans {
+			for _, s := range ss.Spans {
+				for _, a := range s.Attributes {
+					if a.Key == TruncatedAttribute && a.Value.GetBoolValue() {
+						count++
+					}
+				}
+			}
+		}
+	}
+	return count
+}
+
 func CheckPushBytesError(response *tempopb.PushResponse) (errored bool, maxLiveTracesCount int, traceTooLargeCount int) {
 	for _, result := range response.ErrorsByTrace {
 		switch result {
@@ -0,0 +1,147 @@
+package ingester
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/pkg/util/log"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/wal"
+)
+
+type snapshotResult struct {
+	Tenant string `json:"tenant"`
+	Files  int    `json:"files"`
+	Bytes  int64  `json:"bytes"`
+}
+
+// SnapshotHandler uploads the WAL and head blocks of all instances or, if an instance is specified, just that
+// one to the backend. The snapshots are restored on start when restore_wal_snapshot is enabled, so an ingester
+// that lost its disk doesn't lose the data that wasn't flushed yet.
+func (i *Ingester) SnapshotHandler(w http.ResponseWriter, r *http.Request) {
+	queryParamInstance := "tenant"
+
+	instances := i.getInstances()
+	if r.URL.Query().Has(queryParamInstance) {
+		inst, ok := i.getInstanceByID(r.URL.Query().Get(queryParamInstance))
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		instances = []*instance{inst}
+	}
+
+	results := make([]snapshotResult, 0, len(instances))
+	for _, inst := range instances {
+		level.Info(log.Logger).Log("msg", "snapshotting instance", "instance", inst.instanceID)
+		snapshot, err := inst.snapshot(r.Context(), i.cfg.LifecyclerConfig.ID)
+		if err != nil {
+			level.Error(log.WithUserID(inst.instanceID, log.Logger)).Log("msg", "failed to snapshot instance", "err", err)
+			http.Error(w, fmt.Sprintf("failed to snapshot tenant %s: %s", inst.instanceID, err), http.StatusInternalServerError)
+			return
+		}
+
+		results = append(results, snapshotResult{
+			Tenant: inst.instanceID,
+			Files:  len(snapshot.Files),
+			Bytes:  snapshot.Size(),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(results)
+}
+
+// restoreWALSnapshots downloads the snapshots taken by this ingester into the WAL so they are replayed. The
+// snapshots are deleted once restored.
+func (i *Ingester) restoreWALSnapshots(ctx context.Context) {
+	level.Info(log.Logger).Log("msg", "restoring wal snapshots")
+
+	restored, err := i.store.RestoreWAL(ctx, i.cfg.LifecyclerConfig.ID)
+	if err != nil {
+		level.Error(log.Logger).Log("msg", "failed to restore wal snapshots", "err", err)
+		return
+	}
+
+	level.Info(log.Logger).Log("msg", "wal snapshots restored", "tenants", len(restored))
+}
+
+// snapshot writes all live traces to the head block and uploads the WAL files of the instance to the backend.
+// The head block and the blocks are locked while the files are copied so that they aren't modified, the copy is
+// uploaded after the locks are released. Pushes are still accepted into the live traces.
+func (i *instance) snapshot(ctx context.Context, ingesterID string) (*backend.IngesterSnapshot, error) {
+	err := i.CutCompleteTraces(0, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cut traces: %w", err)
+	}
+
+	c, err := i.copyWAL()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := c.Remove(); err != nil {
+			level.Warn(log.WithUserID(i.instanceID, log.Logger)).Log("msg", "failed to remove wal copy", "dir", c.Dir, "err", err)
+		}
+	}()
+
+	i.walSnapshot.Store(true)
+	return i.writer.SnapshotWAL(ctx, i.instanceID, ingesterID, c)
+}
+
+func (i *instance) copyWAL() (*wal.TenantFilesCopy, error) {
+	// same lock order as CutBlockIfReady
+	i.headBlockMtx.Lock()
+	defer i.headBlockMtx.Unlock()
+
+	if i.headBlock != nil {
+		err := i.headBlock.Flush()
+		if err != nil {
+			return nil, fmt.Errorf("failed to flush head block: %w", err)
+		}
+	}
+
+	i.blocksMtx.RLock()
+	defer i.blocksMtx.RUnlock()
+
+	// flushed blocks are in the backend already. an empty head block is never flushed, it would keep the
+	// snapshot from being deleted once all blocks are flushed.
+	skip := map[uuid.UUID]struct{}{}
+	for _, b := range i.completeBlocks {
+		if !b.FlushedTime().IsZero() {
+			skip[b.BlockMeta().BlockID] = struct{}{}
+		}
+	}
+	if i.headBlock != nil && i.headBlock.DataLength() == 0 {
+		skip[i.headBlock.BlockMeta().BlockID] = struct{}{}
+	}
+
+	c, err := i.writer.WAL().CopyTenantFiles(i.instanceID, func(blockID uuid.UUID) bool {
+		_, ok := skip[blockID]
+		return ok
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy wal files: %w", err)
+	}
+	return c, nil
+}
+
+// pruneWALSnapshot removes a flushed block from the WAL snapshot of the instance, so the block isn't restored
+// again. Failures are only logged, the block is in the backend.
+func (i *instance) pruneWALSnapshot(ctx context.Context, ingesterID string, blockID uuid.UUID) {
+	if !i.walSnapshot.Load() {
+		return
+	}
+
+	exists, err := i.writer.PruneWALSnapshot(ctx, i.instanceID, ingesterID, blockID)
+	if err != nil {
+		level.Error(log.WithUserID(i.instanceID, log.Logger)).Log("msg", "failed to prune flushed block from wal snapshot", "block", blockID, "err", err)
+		return
+	}
+	i.walSnapshot.Store(exists)
+}
@@ -16,6 +16,9 @@ type liveTrace struct {
 	end        uint32
 	decoder    model.SegmentDecoder
 
+	// end of the last pushed segment
+	segmentEnd uint32
+
 	// byte limits
 	maxBytes     int
 	currentBytes int
@@ -33,13 +36,9 @@ func newTrace(traceID []byte, maxBytes int) *liveTrace {
 
 func (t *liveTrace) Push(_ context.Context, instanceID string, trace []byte) error {
 	t.lastAppend = time.Now()
-	if t.maxBytes != 0 {
-		reqSize := len(trace)
-		if t.currentBytes+reqSize > t.maxBytes {
-			return newTraceTooLargeError(t.traceID, instanceID, t.maxBytes, reqSize)
-		}
-
-		t.currentBytes += reqSize
+	reqSize := len(trace)
+	if t.maxBytes != 0 && t.currentBytes+reqSize > t.maxBytes {
+		return newTraceTooLargeError(t.traceID, instanceID, t.maxBytes, reqSize)
 	}
 
 	start, end, err := t.decoder.FastRange(trace)
@@ -47,6 +46,8 @@ func (t *liveTrace) Push(_ context.Context, instanceID string, trace []byte) err
 		return fmt.Errorf("failed to get range while adding segment: %w", err)
 	}
 	t.batches = append(t.batches, trace)
+	t.currentBytes += reqSize
+	t.segmentEnd = end
 	if t.start == 0 || start < t.start {
 		t.start = start
 	}
@@ -0,0 +1,218 @@
+package ingester
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	"github.com/grafana/tempo/pkg/util/log"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// TruncatedAttribute marks the spans kept from the push that exceeded the per trace limits
+// of a truncated trace.
+const TruncatedAttribute = "tempo.truncated"
+
+var metricSpansTruncatedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "ingester_spans_truncated_total",
+	Help:      "The total number of spans dropped from traces that exceed the per trace limits per tenant.",
+}, []string{"tenant"})
+
+func newTraceTooManySpansError(traceID common.ID, instanceID string, maxSpans, reqSpans int) error {
+	level.Warn(log.Logger).Log("msg", fmt.Sprintf("%s: max spans of trace (%d) exceeded while adding %d spans to trace %s for tenant %s",
+		overrides.ErrorPrefixTraceTooLarge, maxSpans, reqSpans, hex.EncodeToString(traceID), instanceID))
+	return errTraceTooLarge
+}
+
+// traceUsage is what a trace has used of the per trace limits since the head block was cut.
+type traceUsage struct {
+	bytes     int
+	spans     int
+	truncated bool
+}
+
+type traceLimits struct {
+	maxBytes int
+	maxSpans int
+	truncate bool
+}
+
+func (l traceLimits) enabled() bool {
+	return l.maxBytes > 0 || l.maxSpans > 0
+}
+
+func (i *instance) traceLimits() traceLimits {
+	return traceLimits{
+		maxBytes: i.limiter.limits.MaxBytesPerTrace(i.instanceID),
+		maxSpans: i.limiter.limits.MaxSpansPerTrace(i.instanceID),
+		truncate: i.limiter.limits.TruncateLargeTraces(i.instanceID),
+	}
+}
+
+// applyTraceLimits checks a segment against the per trace limits and returns the segment to push with the
+// updated usage of the trace. If the limits are exceeded the push is refused, or if truncation is enabled
+// the segment is truncated to the spans that fit. A nil segment means all spans were dropped.
+//
+// Segments are only decoded if a span limit is set or the trace is truncated.
+func (i *instance) applyTraceLimits(id, segment []byte, usage traceUsage, limits traceLimits) ([]byte, traceUsage, error) {
+	var (
+		decoder = model.MustNewSegmentDecoder(model.CurrentEncoding)
+		tr      *tempopb.Trace
+		spans   int
+		err     error
+	)
+
+	if limits.maxSpans > 0 || usage.truncated {
+		tr, err = decoder.PrepareForRead([][]byte{segment})
+		if err != nil {
+			return nil, usage, fmt.Errorf("failed to decode segment: %w", err)
+		}
+		spans = countSpans(tr)
+	}
+
+	bytesExceeded := limits.maxBytes > 0 && usage.bytes+len(segment) > limits.maxBytes
+	spansExceeded := limits.maxSpans > 0 && usage.spans+spans > limits.maxSpans
+
+	if !usage.truncated && !bytesExceeded && !spansExceeded {
+		usage.bytes += len(segment)
+		usage.spans += spans
+		return segment, usage, nil
+	}
+
+	if !limits.truncate {
+		if bytesExceeded || limits.maxSpans <= 0 {
+			return nil, usage, newTraceTooLargeError(id, i.instanceID, limits.maxBytes, len(segment))
+		}
+		return nil, usage, newTraceTooManySpansError(id, i.instanceID, limits.maxSpans, spans)
+	}
+
+	if usage.truncated {
+		metricSpansTruncatedTotal.WithLabelValues(i.instanceID).Add(float64(spans))
+		return nil, usage, nil
+	}
+
+	start, end, err := decoder.FastRange(segment)
+	if err != nil {
+		return nil, usage, fmt.Errorf("failed to get range of segment: %w", err)
+	}
+
+	// The span sizes don't account for the resources and scopes of the segment, shrink the byte budget
+	// by the overshoot until the truncated segment fits.
+	var (
+		original = segment
+		kept     int
+		budget   = remaining(limits.maxBytes, usage.bytes)
+	)
+	for {
+		tr, err = decoder.PrepareForRead([][]byte{original})
+		if err != nil {
+			return nil, usage, fmt.Errorf("failed to decode segment: %w", err)
+		}
+		spans = countSpans(tr)
+
+		kept = truncateTrace(tr, budget, remaining(limits.maxSpans, usage.spans))
+		if kept == 0 {
+			segment = nil
+			break
+		}
+
+		segment, err = decoder.PrepareForWrite(tr, start, end)
+		if err != nil {
+			return nil, usage, fmt.Errorf("failed to encode truncated segment: %w", err)
+		}
+
+		overshoot := usage.bytes + len(segment) - limits.maxBytes
+		if limits.maxBytes <= 0 || overshoot <= 0 {
+			break
+		}
+		budget = max(budget-overshoot, 0)
+	}
+
+	level.Warn(log.Logger).Log("msg", "truncating trace that exceeds the per trace limits", "traceID", hex.EncodeToString(id),
+		"tenant", i.instanceID, "keptSpans", kept, "droppedSpans", spans-kept)
+	metricSpansTruncatedTotal.WithLabelValues(i.instanceID).Add(float64(spans - kept))
+
+	usage.bytes += len(segment)
+	usage.spans += kept
+	usage.truncated = true
+	return segment, usage, nil
+}
+
+// remaining returns how much of the limit is left, or -1 if there is no limit.
+func remaining(limit, used int) int {
+	if limit <= 0 {
+		return -1
+	}
+	return max(limit-used, 0)
+}
+
+// truncateTrace keeps the first spans of the trace that fit in maxBytes and maxSpans and marks them with
+// TruncatedAttribute. A negative limit is unlimited. It returns the number of spans kept.
+func truncateTrace(tr *tempopb.Trace, maxBytes, maxSpans int) int {
+	marker := v1_common.KeyValue{
+		Key:   TruncatedAttribute,
+		Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_BoolValue{BoolValue: true}},
+	}
+
+	var (
+		kept     int
+		keptSize int
+		full     bool
+	)
+
+	batches := tr.Batches[:0]
+	for _, b := range tr.Batches {
+		scopes := b.ScopeSpans[:0]
+		for _, ss := range b.ScopeSpans {
+			spans := ss.Spans[:0]
+			for _, s := range ss.Spans {
+				if full {
+					break
+				}
+
+				s.Attributes = append(s.Attributes, &marker)
+				size := s.Size()
+
+				full = (maxSpans >= 0 && kept >= maxSpans) || (maxBytes >= 0 && keptSize+size > maxBytes)
+				if full {
+					break
+				}
+
+				spans = append(spans, s)
+				kept++
+				keptSize += size
+			}
+
+			if len(spans) > 0 {
+				ss.Spans = spans
+				scopes = append(scopes, ss)
+			}
+		}
+
+		if len(scopes) > 0 {
+			b.ScopeSpans = scopes
+			batches = append(batches, b)
+		}
+	}
+	tr.Batches = batches
+
+	return kept
+}
+
+func countSpans(tr *tempopb.Trace) int {
+	count := 0
+	for _, b := range tr.Batches {
+		for _, ss := range b.ScopeSpans {
+			count += len(ss.Spans)
+		}
+	}
+	return count
+}
@@ -19,6 +19,15 @@ import (
 
 type ConfigType string
 
+// HistogramMethod controls which kind of histograms the metrics-generator produces.
+type HistogramMethod string
+
+const (
+	HistogramMethodClassic HistogramMethod = "classic"
+	HistogramMethodNative  HistogramMethod = "native"
+	HistogramMethodBoth    HistogramMethod = "both"
+)
+
 const (
 	ConfigTypeLegacy ConfigType = "legacy"
 	ConfigTypeNew    ConfigType = "new"
@@ -36,15 +45,21 @@ const (
 	ErrorPrefixTraceTooLarge = "TRACE_TOO_LARGE"
 	// ErrorPrefixRateLimited is used to flag batches that have exceeded the spans/second of the tenant
 	ErrorPrefixRateLimited = "RATE_LIMITED"
+	// ErrorPrefixLoadShed is used to flag batches that were rejected b/c the distributor is overloaded
+	ErrorPrefixLoadShed = "LOAD_SHED"
 
 	// metrics
 	MetricMaxLocalTracesPerUser           = "max_local_traces_per_user"
 	MetricMaxGlobalTracesPerUser          = "max_global_traces_per_user"
+	MetricMaxLiveTracesBytes              = "max_live_traces_bytes"
 	MetricMaxBytesPerTrace                = "max_bytes_per_trace"
+	MetricMaxSpansPerTrace                = "max_spans_per_trace"
 	MetricMaxBytesPerTagValuesQuery       = "max_bytes_per_tag_values_query"
 	MetricMaxBlocksPerTagValuesQuery      = "max_blocks_per_tag_values_query"
 	MetricIngestionRateLimitBytes         = "ingestion_rate_limit_bytes"
 	MetricIngestionBurstSizeBytes         = "ingestion_burst_size_bytes"
+	MetricIngestionRateLimitSpans         = "ingestion_rate_limit_spans"
+	MetricIngestionBurstSizeSpans         = "ingestion_burst_size_spans"
 	MetricBlockRetention                  = "block_retention"
 	MetricCompactionWindow                = "compaction_window"
 	MetricMetricsGeneratorMaxActiveSeries = "metrics_generator_max_active_series"
@@ -63,12 +78,48 @@ type IngestionOverrides struct {
 	RateStrategy   string `yaml:"rate_strategy,omitempty" json:"rate_strategy,omitempty"`
 	RateLimitBytes int    `yaml:"rate_limit_bytes,omitempty" json:"rate_limit_bytes,omitempty"`
 	BurstSizeBytes int    `yaml:"burst_size_bytes,omitempty" json:"burst_size_bytes,omitempty"`
+	RateLimitSpans int    `yaml:"rate_limit_spans,omitempty" json:"rate_limit_spans,omitempty"`
+	BurstSizeSpans int    `yaml:"burst_size_spans,omitempty" json:"burst_size_spans,omitempty"`
+	// MaxAttributesPerSpan drops the span attributes beyond the limit instead of refusing the push.
+	MaxAttributesPerSpan int `yaml:"max_attributes_per_span,omitempty" json:"max_attributes_per_span,omitempty"`
+	// MaxAttributeBytes truncates resource and span attribute values longer than the limit.
+	MaxAttributeBytes int `yaml:"max_attribute_bytes,omitempty" json:"max_attribute_bytes,omitempty"`
 
 	// Ingester enforced limits.
 	MaxLocalTracesPerUser  int `yaml:"max_traces_per_user,omitempty" json:"max_traces_per_user,omitempty"`
 	MaxGlobalTracesPerUser int `yaml:"max_global_traces_per_user,omitempty" json:"max_global_traces_per_user,omitempty"`
+	MaxLiveTracesBytes     int `yaml:"max_live_traces_bytes,omitempty" json:"max_live_traces_bytes,omitempty"`
+	MaxSpansPerTrace       int `yaml:"max_spans_per_trace,omitempty" json:"max_spans_per_trace,omitempty"`
+	// TruncateLargeTraces keeps the spans of a trace that fit in max_spans_per_trace and max_bytes_per_trace
+	// and marks them with tempo.truncated=true instead of refusing the push that exceeds the limit.
+	TruncateLargeTraces bool `yaml:"truncate_large_traces,omitempty" json:"truncate_large_traces,omitempty"`
 
 	TenantShardSize int `yaml:"tenant_shard_size,omitempty" json:"tenant_shard_size,omitempty"`
+
+	// DryRun validates and counts the spans of the tenant in the distributor but doesn't send them to the
+	// ingesters, metrics-generators or forwarders.
+	DryRun bool `yaml:"dry_run,omitempty" json:"dry_run,omitempty"`
+}
+
+type CostAttributionOverrides struct {
+	// Dimensions maps span or resource attributes to the label names used by the usage tracker.
+	Dimensions map[string]string `yaml:"dimensions,omitempty" json:"dimensions,omitempty"`
+}
+
+type EnrichmentOverrides struct {
+	// Lookups join the spans of the tenant against the lookup tables configured in the distributor.
+	Lookups []EnrichmentLookup `yaml:"lookups,omitempty" json:"lookups,omitempty"`
+}
+
+type EnrichmentLookup struct {
+	// Table is the name of a lookup table configured in the distributor.
+	Table string `yaml:"table" json:"table"`
+	// KeyAttribute is the attribute whose value is looked up in the table.
+	KeyAttribute string `yaml:"key_attribute" json:"key_attribute"`
+	// Scope of the key attribute and of the injected attributes, resource (default) or span.
+	Scope string `yaml:"scope,omitempty" json:"scope,omitempty"`
+	// Overwrite replaces attributes that are already set instead of keeping them.
+	Overwrite bool `yaml:"overwrite,omitempty" json:"overwrite,omitempty"`
 }
 
 type ForwarderOverrides struct {
@@ -77,22 +128,40 @@ type ForwarderOverrides struct {
 }
 
 type ServiceGraphsOverrides struct {
-	HistogramBuckets                      []float64 `yaml:"histogram_buckets,omitempty" json:"histogram_buckets,omitempty"`
-	Dimensions                            []string  `yaml:"dimensions,omitempty" json:"dimensions,omitempty"`
-	PeerAttributes                        []string  `yaml:"peer_attributes,omitempty" json:"peer_attributes,omitempty"`
-	EnableClientServerPrefix              bool      `yaml:"enable_client_server_prefix,omitempty" json:"enable_client_server_prefix,omitempty"`
-	EnableMessagingSystemLatencyHistogram bool      `yaml:"enable_messaging_system_latency_histogram,omitempty" json:"enable_messaging_system_latency_histogram,omitempty"`
-	EnableVirtualNodeLabel                bool      `yaml:"enable_virtual_node_label,omitempty" json:"enable_virtual_node_label,omitempty"`
+	HistogramBuckets                      []float64     `yaml:"histogram_buckets,omitempty" json:"histogram_buckets,omitempty"`
+	DatabaseHistogramBuckets              []float64     `yaml:"database_histogram_buckets,omitempty" json:"database_histogram_buckets,omitempty"`
+	MessagingSystemHistogramBuckets       []float64     `yaml:"messaging_system_histogram_buckets,omitempty" json:"messaging_system_histogram_buckets,omitempty"`
+	Dimensions                            []string      `yaml:"dimensions,omitempty" json:"dimensions,omitempty"`
+	PeerAttributes                        []string      `yaml:"peer_attributes,omitempty" json:"peer_attributes,omitempty"`
+	DatabaseNameAttributes                []string      `yaml:"database_name_attributes,omitempty" json:"database_name_attributes,omitempty"`
+	VirtualNodeNameTemplate               string        `yaml:"virtual_node_name_template,omitempty" json:"virtual_node_name_template,omitempty"`
+	EnableClientServerPrefix              bool          `yaml:"enable_client_server_prefix,omitempty" json:"enable_client_server_prefix,omitempty"`
+	EnableMessagingSystemLatencyHistogram bool          `yaml:"enable_messaging_system_latency_histogram,omitempty" json:"enable_messaging_system_latency_histogram,omitempty"`
+	EnableVirtualNodeLabel                bool          `yaml:"enable_virtual_node_label,omitempty" json:"enable_virtual_node_label,omitempty"`
+	StaleDuration                         time.Duration `yaml:"stale_duration,omitempty" json:"stale_duration,omitempty"`
 }
 
 type SpanMetricsOverrides struct {
-	HistogramBuckets             []float64                        `yaml:"histogram_buckets,omitempty" json:"histogram_buckets,omitempty"`
-	Dimensions                   []string                         `yaml:"dimensions,omitempty" json:"dimensions,omitempty"`
-	IntrinsicDimensions          map[string]bool                  `yaml:"intrinsic_dimensions,omitempty" json:"intrinsic_dimensions,omitempty"`
-	FilterPolicies               []filterconfig.FilterPolicy      `yaml:"filter_policies,omitempty" json:"filter_policies,omitempty"`
-	DimensionMappings            []sharedconfig.DimensionMappings `yaml:"dimension_mappings,omitempty" json:"dimension_mapings,omitempty"`
-	EnableTargetInfo             bool                             `yaml:"enable_target_info,omitempty" json:"enable_target_info,omitempty"`
-	TargetInfoExcludedDimensions []string                         `yaml:"target_info_excluded_dimensions,omitempty" json:"target_info_excluded_dimensions,omitempty"`
+	HistogramBuckets                []float64                        `yaml:"histogram_buckets,omitempty" json:"histogram_buckets,omitempty"`
+	Dimensions                      []string                         `yaml:"dimensions,omitempty" json:"dimensions,omitempty"`
+	IntrinsicDimensions             map[string]bool                  `yaml:"intrinsic_dimensions,omitempty" json:"intrinsic_dimensions,omitempty"`
+	SubprocessorIntrinsicDimensions map[string]map[string]bool       `yaml:"subprocessor_intrinsic_dimensions,omitempty" json:"subprocessor_intrinsic_dimensions,omitempty"`
+	FilterPolicies                  []filterconfig.FilterPolicy      `yaml:"filter_policies,omitempty" json:"filter_policies,omitempty"`
+	DimensionMappings               []sharedconfig.DimensionMappings `yaml:"dimension_mappings,omitempty" json:"dimension_mapings,omitempty"`
+	EnableTargetInfo                bool                             `yaml:"enable_target_info,omitempty" json:"enable_target_info,omitempty"`
+	TargetInfoExcludedDimensions    []string                         `yaml:"target_info_excluded_dimensions,omitempty" json:"target_info_excluded_dimensions,omitempty"`
+	TargetInfoMetricName            string                           `yaml:"target_info_metric_name,omitempty" json:"target_info_metric_name,omitempty"`
+	StaleDuration                   time.Duration                    `yaml:"stale_duration,omitempty" json:"stale_duration,omitempty"`
+}
+
+type SpanEventsOverrides struct {
+	EventNames        []string                         `yaml:"event_names,omitempty" json:"event_names,omitempty"`
+	Dimensions        []string                         `yaml:"dimensions,omitempty" json:"dimensions,omitempty"`
+	DimensionMappings []sharedconfig.DimensionMappings `yaml:"dimension_mappings,omitempty" json:"dimension_mappings,omitempty"`
+}
+
+type LinkMetricsOverrides struct {
+	Dimensions []string `yaml:"dimensions,omitempty" json:"dimensions,omitempty"`
 }
 
 type LocalBlocksOverrides struct {
@@ -109,6 +178,10 @@ type ProcessorOverrides struct {
 
 	SpanMetrics SpanMetricsOverrides `yaml:"span_metrics,omitempty" json:"span_metrics,omitempty"`
 
+	SpanEvents SpanEventsOverrides `yaml:"span_events,omitempty" json:"span_events,omitempty"`
+
+	LinkMetrics LinkMetricsOverrides `yaml:"link_metrics,omitempty" json:"link_metrics,omitempty"`
+
 	LocalBlocks LocalBlocksOverrides `yaml:"local_blocks,omitempty" json:"local_blocks,omitempty"`
 }
 
@@ -126,6 +199,17 @@ func (h *RemoteWriteHeaders) toStringStringMap() map[string]string {
 	return headers
 }
 
+// RemoteWriteRoute sends the series matching a selector to one of the remote write endpoints of the
+// metrics-generator storage.
+type RemoteWriteRoute struct {
+	// Endpoint is the name of a remote write endpoint configured in metrics_generator.storage.remote_write.
+	Endpoint string `yaml:"endpoint" json:"endpoint"`
+	// Match is a Prometheus series selector, e.g. {__name__=~"traces_service_graph_.*"}.
+	Match string `yaml:"match" json:"match"`
+	// Headers are added to the remote write requests of this route.
+	Headers RemoteWriteHeaders `yaml:"headers,omitempty" json:"headers,omitempty"`
+}
+
 type MetricsGeneratorOverrides struct {
 	RingSize           int                 `yaml:"ring_size,omitempty" json:"ring_size,omitempty"`
 	Processors         listtomap.ListToMap `yaml:"processors,omitempty" json:"processors,omitempty"`
@@ -134,11 +218,19 @@ type MetricsGeneratorOverrides struct {
 	DisableCollection  bool                `yaml:"disable_collection,omitempty" json:"disable_collection,omitempty"`
 	TraceIDLabelName   string              `yaml:"trace_id_label_name,omitempty" json:"trace_id_label_name,omitempty"`
 	RemoteWriteHeaders RemoteWriteHeaders  `yaml:"remote_write_headers,omitempty" json:"remote_write_headers,omitempty"`
+	RemoteWriteRoutes  []RemoteWriteRoute  `yaml:"remote_write_routes,omitempty" json:"remote_write_routes,omitempty"`
+
+	GenerateNativeHistograms       HistogramMethod `yaml:"generate_native_histograms,omitempty" json:"generate_native_histograms,omitempty"`
+	NativeHistogramBucketFactor    float64         `yaml:"native_histogram_bucket_factor,omitempty" json:"native_histogram_bucket_factor,omitempty"`
+	NativeHistogramMaxBucketNumber uint32          `yaml:"native_histogram_max_bucket_number,omitempty" json:"native_histogram_max_bucket_number,omitempty"`
 
 	Forwarder ForwarderOverrides `yaml:"forwarder,omitempty" json:"forwarder,omitempty"`
 
 	Processor      ProcessorOverrides `yaml:"processor,omitempty" json:"processor,omitempty"`
 	IngestionSlack time.Duration      `yaml:"ingestion_time_range_slack" json:"ingestion_time_range_slack"`
+
+	// FilterPolicies are applied to all spans before they are passed to any of the processors.
+	FilterPolicies []filterconfig.FilterPolicy `yaml:"filter_policies,omitempty" json:"filter_policies,omitempty"`
 }
 
 type ReadOverrides struct {
@@ -149,14 +241,36 @@ type ReadOverrides struct {
 	// QueryFrontend enforced overrides
 	MaxSearchDuration  model.Duration `yaml:"max_search_duration,omitempty" json:"max_search_duration,omitempty"`
 	MaxMetricsDuration model.Duration `yaml:"max_metrics_duration,omitempty" json:"max_metrics_duration,omitempty"`
+	MaxTagsDuration    model.Duration `yaml:"max_tags_duration,omitempty" json:"max_tags_duration,omitempty"`
+	MaxExemplars       int            `yaml:"max_exemplars,omitempty" json:"max_exemplars,omitempty"`
+	ExemplarPolicy     string         `yaml:"exemplar_policy,omitempty" json:"exemplar_policy,omitempty"`
+
+	// QueryFrontend enforced limit of the backend blocks searched for a tag-values query
+	MaxBackendBlocksPerTagValuesQuery int `yaml:"max_backend_blocks_per_tag_values_query,omitempty" json:"max_backend_blocks_per_tag_values_query,omitempty"`
+
+	// Querier enforced metrics overrides
+	MaxMetricsSeries        int `yaml:"max_metrics_series,omitempty" json:"max_metrics_series,omitempty"`
+	MaxMetricsResponseBytes int `yaml:"max_metrics_response_bytes,omitempty" json:"max_metrics_response_bytes,omitempty"`
+
+	// QueryFrontend queue overrides
+	MaxInflightRequests int `yaml:"max_inflight_requests,omitempty" json:"max_inflight_requests,omitempty"`
+	QueueWeight         int `yaml:"queue_weight,omitempty" json:"queue_weight,omitempty"`
 
 	UnsafeQueryHints bool `yaml:"unsafe_query_hints,omitempty" json:"unsafe_query_hints,omitempty"`
+
+	// InferAttributeScopes rewrites unscoped attribute conditions of TraceQL queries to the scope the attribute
+	// is observed in, as recorded in the block metas.
+	InferAttributeScopes bool `yaml:"infer_attribute_scopes,omitempty" json:"infer_attribute_scopes,omitempty"`
 }
 
 type CompactionOverrides struct {
 	// Compactor enforced overrides.
 	BlockRetention   model.Duration `yaml:"block_retention,omitempty" json:"block_retention,omitempty"`
 	CompactionWindow model.Duration `yaml:"compaction_window,omitempty" json:"compaction_window,omitempty"`
+
+	// Budgets of a compaction cycle of the tenant. 0 uses the budgets of the compactor configuration.
+	MaxBytesPerCycle uint64 `yaml:"max_bytes_per_cycle,omitempty" json:"max_bytes_per_cycle,omitempty"`
+	MaxJobsPerCycle  int    `yaml:"max_jobs_per_cycle,omitempty" json:"max_jobs_per_cycle,omitempty"`
 }
 
 type GlobalOverrides struct {
@@ -185,6 +299,10 @@ type Overrides struct {
 	Global GlobalOverrides `yaml:"global,omitempty" json:"global,omitempty"`
 	// Storage enforced overrides.
 	Storage StorageOverrides `yaml:"storage,omitempty" json:"storage,omitempty"`
+	// CostAttribution configures the dimensions of the usage tracker.
+	CostAttribution CostAttributionOverrides `yaml:"cost_attribution,omitempty" json:"cost_attribution,omitempty"`
+	// Enrichment configures the attributes the distributor injects from lookup tables.
+	Enrichment EnrichmentOverrides `yaml:"enrichment,omitempty" json:"enrichment,omitempty"`
 }
 
 type Config struct {
@@ -196,6 +314,9 @@ type Config struct {
 
 	UserConfigurableOverridesConfig UserConfigurableOverridesConfig `yaml:"user_configurable_overrides" json:"user_configurable_overrides"`
 
+	// EffectiveLimitsMetrics exports the effective limits of the tenants with overrides as tempo_limits_effective.
+	EffectiveLimitsMetrics bool `yaml:"effective_limits_metrics" json:"effective_limits_metrics"`
+
 	ConfigType ConfigType `yaml:"-" json:"-"`
 	ExpandEnv  bool       `yaml:"-" json:"-"`
 }
@@ -220,12 +341,15 @@ func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		PerTenantOverridePeriod model.Duration `yaml:"per_tenant_override_period"`
 
 		UserConfigurableOverridesConfig UserConfigurableOverridesConfig `yaml:"user_configurable_overrides"`
+
+		EffectiveLimitsMetrics bool `yaml:"effective_limits_metrics"`
 	}
 	var legacyCfg legacyConfig
 	legacyCfg.DefaultOverrides = c.Defaults.toLegacy()
 	legacyCfg.PerTenantOverrideConfig = c.PerTenantOverrideConfig
 	legacyCfg.PerTenantOverridePeriod = c.PerTenantOverridePeriod
 	legacyCfg.UserConfigurableOverridesConfig = c.UserConfigurableOverridesConfig
+	legacyCfg.EffectiveLimitsMetrics = c.EffectiveLimitsMetrics
 
 	if err := unmarshal(&legacyCfg); err != nil {
 		return err
@@ -235,6 +359,7 @@ func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	c.PerTenantOverrideConfig = legacyCfg.PerTenantOverrideConfig
 	c.PerTenantOverridePeriod = legacyCfg.PerTenantOverridePeriod
 	c.UserConfigurableOverridesConfig = legacyCfg.UserConfigurableOverridesConfig
+	c.EffectiveLimitsMetrics = legacyCfg.EffectiveLimitsMetrics
 	c.ConfigType = ConfigTypeLegacy
 	return nil
 }
@@ -245,10 +370,15 @@ func (c *Config) RegisterFlagsAndApplyDefaults(f *flag.FlagSet) {
 	f.StringVar(&c.Defaults.Ingestion.RateStrategy, "distributor.rate-limit-strategy", "local", "Whether the various ingestion rate limits should be applied individually to each distributor instance (local), or evenly shared across the cluster (global).")
 	f.IntVar(&c.Defaults.Ingestion.RateLimitBytes, "distributor.ingestion-rate-limit-bytes", 15e6, "Per-user ingestion rate limit in bytes per second.")
 	f.IntVar(&c.Defaults.Ingestion.BurstSizeBytes, "distributor.ingestion-burst-size-bytes", 20e6, "Per-user ingestion burst size in bytes. Should be set to the expected size (in bytes) of a single push request.")
+	f.IntVar(&c.Defaults.Ingestion.RateLimitSpans, "distributor.ingestion-rate-limit-spans", 0, "Per-user ingestion rate limit in spans per second. 0 to disable.")
+	f.IntVar(&c.Defaults.Ingestion.BurstSizeSpans, "distributor.ingestion-burst-size-spans", 0, "Per-user ingestion burst size in spans. Should be set to the expected number of spans in a single push request.")
 
 	// Ingester limits
 	f.IntVar(&c.Defaults.Ingestion.MaxLocalTracesPerUser, "ingester.max-traces-per-user", 10e3, "Maximum number of active traces per user, per ingester. 0 to disable.")
 	f.IntVar(&c.Defaults.Ingestion.MaxGlobalTracesPerUser, "ingester.max-global-traces-per-user", 0, "Maximum number of active traces per user, across the cluster. 0 to disable.")
+	f.IntVar(&c.Defaults.Ingestion.MaxLiveTracesBytes, "ingester.max-live-traces-bytes", 0, "Maximum size in bytes of all live traces per user, per ingester. 0 to disable.")
+	f.IntVar(&c.Defaults.Ingestion.MaxSpansPerTrace, "ingester.max-spans-per-trace", 0, "Maximum number of spans in a trace, per ingester. 0 to disable.")
+	f.BoolVar(&c.Defaults.Ingestion.TruncateLargeTraces, "ingester.truncate-large-traces", false, "Truncate traces that exceed the per trace limits instead of refusing the spans.")
 	f.IntVar(&c.Defaults.Global.MaxBytesPerTrace, "ingester.max-bytes-per-trace", 50e5, "Maximum size of a trace in bytes.  0 to disable.")
 
 	// Querier limits
@@ -269,11 +399,15 @@ func (c *Config) Describe(ch chan<- *prometheus.Desc) {
 func (c *Config) Collect(ch chan<- prometheus.Metric) {
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Ingestion.MaxLocalTracesPerUser), MetricMaxLocalTracesPerUser)
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Ingestion.MaxGlobalTracesPerUser), MetricMaxGlobalTracesPerUser)
+	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Ingestion.MaxLiveTracesBytes), MetricMaxLiveTracesBytes)
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Ingestion.RateLimitBytes), MetricIngestionRateLimitBytes)
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Ingestion.BurstSizeBytes), MetricIngestionBurstSizeBytes)
+	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Ingestion.RateLimitSpans), MetricIngestionRateLimitSpans)
+	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Ingestion.BurstSizeSpans), MetricIngestionBurstSizeSpans)
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Read.MaxBytesPerTagValuesQuery), MetricMaxBytesPerTagValuesQuery)
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Read.MaxBlocksPerTagValuesQuery), MetricMaxBlocksPerTagValuesQuery)
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Global.MaxBytesPerTrace), MetricMaxBytesPerTrace)
+	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Ingestion.MaxSpansPerTrace), MetricMaxSpansPerTrace)
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Compaction.BlockRetention), MetricBlockRetention)
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.MetricsGenerator.MaxActiveSeries), MetricMetricsGeneratorMaxActiveSeries)
 }
@@ -14,12 +14,20 @@ import (
 
 func (c *Overrides) toLegacy() LegacyOverrides {
 	return LegacyOverrides{
-		IngestionRateStrategy:    c.Ingestion.RateStrategy,
-		IngestionRateLimitBytes:  c.Ingestion.RateLimitBytes,
-		IngestionBurstSizeBytes:  c.Ingestion.BurstSizeBytes,
-		IngestionTenantShardSize: c.Ingestion.TenantShardSize,
-		MaxLocalTracesPerUser:    c.Ingestion.MaxLocalTracesPerUser,
-		MaxGlobalTracesPerUser:   c.Ingestion.MaxGlobalTracesPerUser,
+		IngestionRateStrategy:         c.Ingestion.RateStrategy,
+		IngestionRateLimitBytes:       c.Ingestion.RateLimitBytes,
+		IngestionBurstSizeBytes:       c.Ingestion.BurstSizeBytes,
+		IngestionRateLimitSpans:       c.Ingestion.RateLimitSpans,
+		IngestionBurstSizeSpans:       c.Ingestion.BurstSizeSpans,
+		IngestionTenantShardSize:      c.Ingestion.TenantShardSize,
+		MaxLocalTracesPerUser:         c.Ingestion.MaxLocalTracesPerUser,
+		MaxGlobalTracesPerUser:        c.Ingestion.MaxGlobalTracesPerUser,
+		MaxLiveTracesBytes:            c.Ingestion.MaxLiveTracesBytes,
+		MaxSpansPerTrace:              c.Ingestion.MaxSpansPerTrace,
+		TruncateLargeTraces:           c.Ingestion.TruncateLargeTraces,
+		IngestionDryRun:               c.Ingestion.DryRun,
+		IngestionMaxAttributesPerSpan: c.Ingestion.MaxAttributesPerSpan,
+		IngestionMaxAttributeBytes:    c.Ingestion.MaxAttributeBytes,
 
 		Forwarders: c.Forwarders,
 
@@ -30,21 +38,37 @@ func (c *Overrides) toLegacy() LegacyOverrides {
 		MetricsGeneratorDisableCollection:                                           c.MetricsGenerator.DisableCollection,
 		MetricsGeneratorTraceIDLabelName:                                            c.MetricsGenerator.TraceIDLabelName,
 		MetricsGeneratorRemoteWriteHeaders:                                          c.MetricsGenerator.RemoteWriteHeaders,
+		MetricsGeneratorRemoteWriteRoutes:                                           c.MetricsGenerator.RemoteWriteRoutes,
+		MetricsGeneratorGenerateNativeHistograms:                                    c.MetricsGenerator.GenerateNativeHistograms,
+		MetricsGeneratorNativeHistogramBucketFactor:                                 c.MetricsGenerator.NativeHistogramBucketFactor,
+		MetricsGeneratorNativeHistogramMaxBucketNumber:                              c.MetricsGenerator.NativeHistogramMaxBucketNumber,
 		MetricsGeneratorForwarderQueueSize:                                          c.MetricsGenerator.Forwarder.QueueSize,
 		MetricsGeneratorForwarderWorkers:                                            c.MetricsGenerator.Forwarder.Workers,
 		MetricsGeneratorProcessorServiceGraphsHistogramBuckets:                      c.MetricsGenerator.Processor.ServiceGraphs.HistogramBuckets,
+		MetricsGeneratorProcessorServiceGraphsDatabaseHistogramBuckets:              c.MetricsGenerator.Processor.ServiceGraphs.DatabaseHistogramBuckets,
+		MetricsGeneratorProcessorServiceGraphsMessagingSystemHistogramBuckets:       c.MetricsGenerator.Processor.ServiceGraphs.MessagingSystemHistogramBuckets,
 		MetricsGeneratorProcessorServiceGraphsDimensions:                            c.MetricsGenerator.Processor.ServiceGraphs.Dimensions,
 		MetricsGeneratorProcessorServiceGraphsPeerAttributes:                        c.MetricsGenerator.Processor.ServiceGraphs.PeerAttributes,
+		MetricsGeneratorProcessorServiceGraphsDatabaseNameAttributes:                c.MetricsGenerator.Processor.ServiceGraphs.DatabaseNameAttributes,
+		MetricsGeneratorProcessorServiceGraphsVirtualNodeNameTemplate:               c.MetricsGenerator.Processor.ServiceGraphs.VirtualNodeNameTemplate,
 		MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix:              c.MetricsGenerator.Processor.ServiceGraphs.EnableClientServerPrefix,
 		MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram: c.MetricsGenerator.Processor.ServiceGraphs.EnableMessagingSystemLatencyHistogram,
 		MetricsGeneratorProcessorServiceGraphsEnableVirtualNodeLabel:                c.MetricsGenerator.Processor.ServiceGraphs.EnableVirtualNodeLabel,
+		MetricsGeneratorProcessorServiceGraphsStaleDuration:                         c.MetricsGenerator.Processor.ServiceGraphs.StaleDuration,
 		MetricsGeneratorProcessorSpanMetricsHistogramBuckets:                        c.MetricsGenerator.Processor.SpanMetrics.HistogramBuckets,
 		MetricsGeneratorProcessorSpanMetricsDimensions:                              c.MetricsGenerator.Processor.SpanMetrics.Dimensions,
 		MetricsGeneratorProcessorSpanMetricsIntrinsicDimensions:                     c.MetricsGenerator.Processor.SpanMetrics.IntrinsicDimensions,
+		MetricsGeneratorProcessorSpanMetricsSubprocessorIntrinsicDimensions:         c.MetricsGenerator.Processor.SpanMetrics.SubprocessorIntrinsicDimensions,
 		MetricsGeneratorProcessorSpanMetricsFilterPolicies:                          c.MetricsGenerator.Processor.SpanMetrics.FilterPolicies,
 		MetricsGeneratorProcessorSpanMetricsDimensionMappings:                       c.MetricsGenerator.Processor.SpanMetrics.DimensionMappings,
 		MetricsGeneratorProcessorSpanMetricsEnableTargetInfo:                        c.MetricsGenerator.Processor.SpanMetrics.EnableTargetInfo,
 		MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions:            c.MetricsGenerator.Processor.SpanMetrics.TargetInfoExcludedDimensions,
+		MetricsGeneratorProcessorSpanMetricsTargetInfoMetricName:                    c.MetricsGenerator.Processor.SpanMetrics.TargetInfoMetricName,
+		MetricsGeneratorProcessorSpanMetricsStaleDuration:                           c.MetricsGenerator.Processor.SpanMetrics.StaleDuration,
+		MetricsGeneratorProcessorSpanEventsEventNames:                               c.MetricsGenerator.Processor.SpanEvents.EventNames,
+		MetricsGeneratorProcessorSpanEventsDimensions:                               c.MetricsGenerator.Processor.SpanEvents.Dimensions,
+		MetricsGeneratorProcessorSpanEventsDimensionMappings:                        c.MetricsGenerator.Processor.SpanEvents.DimensionMappings,
+		MetricsGeneratorProcessorLinkMetricsDimensions:                              c.MetricsGenerator.Processor.LinkMetrics.Dimensions,
 		MetricsGeneratorProcessorLocalBlocksMaxLiveTraces:                           c.MetricsGenerator.Processor.LocalBlocks.MaxLiveTraces,
 		MetricsGeneratorProcessorLocalBlocksMaxBlockDuration:                        c.MetricsGenerator.Processor.LocalBlocks.MaxBlockDuration,
 		MetricsGeneratorProcessorLocalBlocksMaxBlockBytes:                           c.MetricsGenerator.Processor.LocalBlocks.MaxBlockBytes,
@@ -52,14 +76,33 @@ func (c *Overrides) toLegacy() LegacyOverrides {
 		MetricsGeneratorProcessorLocalBlocksTraceIdlePeriod:                         c.MetricsGenerator.Processor.LocalBlocks.TraceIdlePeriod,
 		MetricsGeneratorProcessorLocalBlocksCompleteBlockTimeout:                    c.MetricsGenerator.Processor.LocalBlocks.CompleteBlockTimeout,
 		MetricsGeneratorIngestionSlack:                                              c.MetricsGenerator.IngestionSlack,
+		MetricsGeneratorFilterPolicies:                                              c.MetricsGenerator.FilterPolicies,
 
 		BlockRetention:   c.Compaction.BlockRetention,
 		CompactionWindow: c.Compaction.CompactionWindow,
 
+		CompactionMaxBytesPerCycle: c.Compaction.MaxBytesPerCycle,
+		CompactionMaxJobsPerCycle:  c.Compaction.MaxJobsPerCycle,
+
+		CostAttributionDimensions: c.CostAttribution.Dimensions,
+
+		EnrichmentLookups: c.Enrichment.Lookups,
+
 		MaxBytesPerTagValuesQuery:  c.Read.MaxBytesPerTagValuesQuery,
 		MaxBlocksPerTagValuesQuery: c.Read.MaxBlocksPerTagValuesQuery,
 		MaxSearchDuration:          c.Read.MaxSearchDuration,
+		MaxMetricsDuration:         c.Read.MaxMetricsDuration,
+		MaxTagsDuration:            c.Read.MaxTagsDuration,
+		MaxExemplars:               c.Read.MaxExemplars,
+		ExemplarPolicy:             c.Read.ExemplarPolicy,
+		MaxMetricsSeries:           c.Read.MaxMetricsSeries,
+		MaxMetricsResponseBytes:    c.Read.MaxMetricsResponseBytes,
 		UnsafeQueryHints:           c.Read.UnsafeQueryHints,
+		InferAttributeScopes:       c.Read.InferAttributeScopes,
+		MaxInflightRequests:        c.Read.MaxInflightRequests,
+		QueryQueueWeight:           c.Read.QueueWeight,
+
+		MaxBackendBlocksPerTagValuesQuery: c.Read.MaxBackendBlocksPerTagValuesQuery,
 
 		MaxBytesPerTrace: c.Global.MaxBytesPerTrace,
 
@@ -71,14 +114,23 @@ func (c *Overrides) toLegacy() LegacyOverrides {
 // limits via flags, or per-user limits via yaml config.
 type LegacyOverrides struct {
 	// Distributor enforced limits.
-	IngestionRateStrategy    string `yaml:"ingestion_rate_strategy" json:"ingestion_rate_strategy"`
-	IngestionRateLimitBytes  int    `yaml:"ingestion_rate_limit_bytes" json:"ingestion_rate_limit_bytes"`
-	IngestionBurstSizeBytes  int    `yaml:"ingestion_burst_size_bytes" json:"ingestion_burst_size_bytes"`
-	IngestionTenantShardSize int    `yaml:"ingestion_tenant_shard_size" json:"ingestion_tenant_shard_size"`
+	IngestionRateStrategy         string `yaml:"ingestion_rate_strategy" json:"ingestion_rate_strategy"`
+	IngestionRateLimitBytes       int    `yaml:"ingestion_rate_limit_bytes" json:"ingestion_rate_limit_bytes"`
+	IngestionBurstSizeBytes       int    `yaml:"ingestion_burst_size_bytes" json:"ingestion_burst_size_bytes"`
+	IngestionRateLimitSpans       int    `yaml:"ingestion_rate_limit_spans" json:"ingestion_rate_limit_spans"`
+	IngestionBurstSizeSpans       int    `yaml:"ingestion_burst_size_spans" json:"ingestion_burst_size_spans"`
+	IngestionTenantShardSize      int    `yaml:"ingestion_tenant_shard_size" json:"ingestion_tenant_shard_size"`
+	IngestionDryRun               bool   `yaml:"ingestion_dry_run,omitempty" json:"ingestion_dry_run,omitempty"`
+	IngestionMaxAttributesPerSpan int    `yaml:"ingestion_max_attributes_per_span,omitempty" json:"ingestion_max_attributes_per_span,omitempty"`
+	IngestionMaxAttributeBytes    int    `yaml:"ingestion_max_attribute_bytes,omitempty" json:"ingestion_max_attribute_bytes,omitempty"`
 
 	// Ingester enforced limits.
 	MaxLocalTracesPerUser  int `yaml:"max_traces_per_user" json:"max_traces_per_user"`
 	MaxGlobalTracesPerUser int `yaml:"max_global_traces_per_user" json:"max_global_traces_per_user"`
+	MaxLiveTracesBytes     int `yaml:"max_live_traces_bytes" json:"max_live_traces_bytes"`
+	MaxSpansPerTrace       int `yaml:"max_spans_per_trace" json:"max_spans_per_trace"`
+
+	TruncateLargeTraces bool `yaml:"truncate_large_traces" json:"truncate_large_traces"`
 
 	// Forwarders
 	Forwarders []string `yaml:"forwarders" json:"forwarders"`
@@ -93,19 +145,35 @@ type LegacyOverrides struct {
 	MetricsGeneratorForwarderQueueSize                                          int                              `yaml:"metrics_generator_forwarder_queue_size" json:"metrics_generator_forwarder_queue_size"`
 	MetricsGeneratorForwarderWorkers                                            int                              `yaml:"metrics_generator_forwarder_workers" json:"metrics_generator_forwarder_workers"`
 	MetricsGeneratorRemoteWriteHeaders                                          RemoteWriteHeaders               `yaml:"metrics_generator_remote_write_headers,omitempty" json:"metrics_generator_remote_write_headers,omitempty"`
+	MetricsGeneratorRemoteWriteRoutes                                           []RemoteWriteRoute               `yaml:"metrics_generator_remote_write_routes,omitempty" json:"metrics_generator_remote_write_routes,omitempty"`
+	MetricsGeneratorGenerateNativeHistograms                                    HistogramMethod                  `yaml:"metrics_generator_generate_native_histograms" json:"metrics_generator_generate_native_histograms"`
+	MetricsGeneratorNativeHistogramBucketFactor                                 float64                          `yaml:"metrics_generator_native_histogram_bucket_factor" json:"metrics_generator_native_histogram_bucket_factor"`
+	MetricsGeneratorNativeHistogramMaxBucketNumber                              uint32                           `yaml:"metrics_generator_native_histogram_max_bucket_number" json:"metrics_generator_native_histogram_max_bucket_number"`
 	MetricsGeneratorProcessorServiceGraphsHistogramBuckets                      []float64                        `yaml:"metrics_generator_processor_service_graphs_histogram_buckets" json:"metrics_generator_processor_service_graphs_histogram_buckets"`
+	MetricsGeneratorProcessorServiceGraphsDatabaseHistogramBuckets              []float64                        `yaml:"metrics_generator_processor_service_graphs_database_histogram_buckets" json:"metrics_generator_processor_service_graphs_database_histogram_buckets"`
+	MetricsGeneratorProcessorServiceGraphsMessagingSystemHistogramBuckets       []float64                        `yaml:"metrics_generator_processor_service_graphs_messaging_system_histogram_buckets" json:"metrics_generator_processor_service_graphs_messaging_system_histogram_buckets"`
 	MetricsGeneratorProcessorServiceGraphsDimensions                            []string                         `yaml:"metrics_generator_processor_service_graphs_dimensions" json:"metrics_generator_processor_service_graphs_dimensions"`
 	MetricsGeneratorProcessorServiceGraphsPeerAttributes                        []string                         `yaml:"metrics_generator_processor_service_graphs_peer_attributes" json:"metrics_generator_processor_service_graphs_peer_attributes"`
+	MetricsGeneratorProcessorServiceGraphsDatabaseNameAttributes                []string                         `yaml:"metrics_generator_processor_service_graphs_database_name_attributes" json:"metrics_generator_processor_service_graphs_database_name_attributes"`
+	MetricsGeneratorProcessorServiceGraphsVirtualNodeNameTemplate               string                           `yaml:"metrics_generator_processor_service_graphs_virtual_node_name_template" json:"metrics_generator_processor_service_graphs_virtual_node_name_template"`
 	MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix              bool                             `yaml:"metrics_generator_processor_service_graphs_enable_client_server_prefix" json:"metrics_generator_processor_service_graphs_enable_client_server_prefix"`
 	MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram bool                             `yaml:"metrics_generator_processor_service_graphs_enable_messaging_system_latency_histogram" json:"metrics_generator_processor_service_graphs_enable_messaging_system_latency_histogram"`
 	MetricsGeneratorProcessorServiceGraphsEnableVirtualNodeLabel                bool                             `yaml:"metrics_generator_processor_service_graphs_enable_virtual_node_label" json:"metrics_generator_processor_service_graphs_enable_virtual_node_label"`
+	MetricsGeneratorProcessorServiceGraphsStaleDuration                         time.Duration                    `yaml:"metrics_generator_processor_service_graphs_stale_duration,omitempty" json:"metrics_generator_processor_service_graphs_stale_duration,omitempty"`
 	MetricsGeneratorProcessorSpanMetricsHistogramBuckets                        []float64                        `yaml:"metrics_generator_processor_span_metrics_histogram_buckets" json:"metrics_generator_processor_span_metrics_histogram_buckets"`
 	MetricsGeneratorProcessorSpanMetricsDimensions                              []string                         `yaml:"metrics_generator_processor_span_metrics_dimensions" json:"metrics_generator_processor_span_metrics_dimensions"`
 	MetricsGeneratorProcessorSpanMetricsIntrinsicDimensions                     map[string]bool                  `yaml:"metrics_generator_processor_span_metrics_intrinsic_dimensions" json:"metrics_generator_processor_span_metrics_intrinsic_dimensions"`
+	MetricsGeneratorProcessorSpanMetricsSubprocessorIntrinsicDimensions         map[string]map[string]bool       `yaml:"metrics_generator_processor_span_metrics_subprocessor_intrinsic_dimensions" json:"metrics_generator_processor_span_metrics_subprocessor_intrinsic_dimensions"`
 	MetricsGeneratorProcessorSpanMetricsFilterPolicies                          []filterconfig.FilterPolicy      `yaml:"metrics_generator_processor_span_metrics_filter_policies" json:"metrics_generator_processor_span_metrics_filter_policies"`
 	MetricsGeneratorProcessorSpanMetricsDimensionMappings                       []sharedconfig.DimensionMappings `yaml:"metrics_generator_processor_span_metrics_dimension_mappings" json:"metrics_generator_processor_span_metrics_dimension_mapings"`
 	MetricsGeneratorProcessorSpanMetricsEnableTargetInfo                        bool                             `yaml:"metrics_generator_processor_span_metrics_enable_target_info" json:"metrics_generator_processor_span_metrics_enable_target_info"`
 	MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions            []string                         `yaml:"metrics_generator_processor_span_metrics_target_info_excluded_dimensions" json:"metrics_generator_processor_span_metrics_target_info_excluded_dimensions"`
+	MetricsGeneratorProcessorSpanMetricsTargetInfoMetricName                    string                           `yaml:"metrics_generator_processor_span_metrics_target_info_metric_name" json:"metrics_generator_processor_span_metrics_target_info_metric_name"`
+	MetricsGeneratorProcessorSpanMetricsStaleDuration                           time.Duration                    `yaml:"metrics_generator_processor_span_metrics_stale_duration,omitempty" json:"metrics_generator_processor_span_metrics_stale_duration,omitempty"`
+	MetricsGeneratorProcessorSpanEventsEventNames                               []string                         `yaml:"metrics_generator_processor_span_events_event_names" json:"metrics_generator_processor_span_events_event_names"`
+	MetricsGeneratorProcessorSpanEventsDimensions                               []string                         `yaml:"metrics_generator_processor_span_events_dimensions" json:"metrics_generator_processor_span_events_dimensions"`
+	MetricsGeneratorProcessorSpanEventsDimensionMappings                        []sharedconfig.DimensionMappings `yaml:"metrics_generator_processor_span_events_dimension_mappings" json:"metrics_generator_processor_span_events_dimension_mappings"`
+	MetricsGeneratorProcessorLinkMetricsDimensions                              []string                         `yaml:"metrics_generator_processor_link_metrics_dimensions" json:"metrics_generator_processor_link_metrics_dimensions"`
 	MetricsGeneratorProcessorLocalBlocksMaxLiveTraces                           uint64                           `yaml:"metrics_generator_processor_local_blocks_max_live_traces" json:"metrics_generator_processor_local_blocks_max_live_traces"`
 	MetricsGeneratorProcessorLocalBlocksMaxBlockDuration                        time.Duration                    `yaml:"metrics_generator_processor_local_blocks_max_block_duration" json:"metrics_generator_processor_local_blocks_max_block_duration"`
 	MetricsGeneratorProcessorLocalBlocksMaxBlockBytes                           uint64                           `yaml:"metrics_generator_processor_local_blocks_max_block_bytes" json:"metrics_generator_processor_local_blocks_max_block_bytes"`
@@ -113,19 +181,44 @@ type LegacyOverrides struct {
 	MetricsGeneratorProcessorLocalBlocksTraceIdlePeriod                         time.Duration                    `yaml:"metrics_generator_processor_local_blocks_trace_idle_period" json:"metrics_generator_processor_local_blocks_trace_idle_period"`
 	MetricsGeneratorProcessorLocalBlocksCompleteBlockTimeout                    time.Duration                    `yaml:"metrics_generator_processor_local_blocks_complete_block_timeout" json:"metrics_generator_processor_local_blocks_complete_block_timeout"`
 	MetricsGeneratorIngestionSlack                                              time.Duration                    `yaml:"metrics_generator_ingestion_time_range_slack" json:"metrics_generator_ingestion_time_range_slack"`
+	MetricsGeneratorFilterPolicies                                              []filterconfig.FilterPolicy      `yaml:"metrics_generator_filter_policies" json:"metrics_generator_filter_policies"`
 
 	// Compactor enforced limits.
 	BlockRetention   model.Duration `yaml:"block_retention" json:"block_retention"`
 	CompactionWindow model.Duration `yaml:"compaction_window" json:"compaction_window"`
 
+	CompactionMaxBytesPerCycle uint64 `yaml:"compaction_max_bytes_per_cycle" json:"compaction_max_bytes_per_cycle"`
+	CompactionMaxJobsPerCycle  int    `yaml:"compaction_max_jobs_per_cycle" json:"compaction_max_jobs_per_cycle"`
+
+	// Distributor usage tracker
+	CostAttributionDimensions map[string]string `yaml:"cost_attribution_dimensions" json:"cost_attribution_dimensions"`
+
+	// Distributor span enrichment
+	EnrichmentLookups []EnrichmentLookup `yaml:"enrichment_lookups" json:"enrichment_lookups"`
+
 	// Querier and Ingester enforced limits.
 	MaxBytesPerTagValuesQuery  int `yaml:"max_bytes_per_tag_values_query" json:"max_bytes_per_tag_values_query"`
 	MaxBlocksPerTagValuesQuery int `yaml:"max_blocks_per_tag_values_query" json:"max_blocks_per_tag_values_query"`
 
 	// QueryFrontend enforced limits
-	MaxSearchDuration  model.Duration `yaml:"max_search_duration" json:"max_search_duration"`
-	MaxMetricsDuration model.Duration `yaml:"max_metrics_duration" json:"max_metrics_duration"`
-	UnsafeQueryHints   bool           `yaml:"unsafe_query_hints" json:"unsafe_query_hints"`
+	MaxSearchDuration    model.Duration `yaml:"max_search_duration" json:"max_search_duration"`
+	MaxMetricsDuration   model.Duration `yaml:"max_metrics_duration" json:"max_metrics_duration"`
+	MaxTagsDuration      model.Duration `yaml:"max_tags_duration" json:"max_tags_duration"`
+	MaxExemplars         int            `yaml:"max_exemplars" json:"max_exemplars"`
+	ExemplarPolicy       string         `yaml:"exemplar_policy" json:"exemplar_policy"`
+	UnsafeQueryHints     bool           `yaml:"unsafe_query_hints" json:"unsafe_query_hints"`
+	InferAttributeScopes bool           `yaml:"infer_attribute_scopes" json:"infer_attribute_scopes"`
+
+	// QueryFrontend enforced limit of the backend blocks searched for a tag-values query
+	MaxBackendBlocksPerTagValuesQuery int `yaml:"max_backend_blocks_per_tag_values_query" json:"max_backend_blocks_per_tag_values_query"`
+
+	// Querier enforced metrics limits
+	MaxMetricsSeries        int `yaml:"max_metrics_series" json:"max_metrics_series"`
+	MaxMetricsResponseBytes int `yaml:"max_metrics_response_bytes" json:"max_metrics_response_bytes"`
+
+	// QueryFrontend queue limits
+	MaxInflightRequests int `yaml:"max_inflight_requests" json:"max_inflight_requests"`
+	QueryQueueWeight    int `yaml:"query_queue_weight" json:"query_queue_weight"`
 
 	// MaxBytesPerTrace is enforced in the Ingester, Compactor, Querier (Search) and Serverless (Search). It
 	//  is not used when doing a trace by id lookup.
@@ -141,20 +234,41 @@ func (l *LegacyOverrides) toNewLimits() Overrides {
 			RateStrategy:           l.IngestionRateStrategy,
 			RateLimitBytes:         l.IngestionRateLimitBytes,
 			BurstSizeBytes:         l.IngestionBurstSizeBytes,
+			RateLimitSpans:         l.IngestionRateLimitSpans,
+			BurstSizeSpans:         l.IngestionBurstSizeSpans,
 			MaxLocalTracesPerUser:  l.MaxLocalTracesPerUser,
 			MaxGlobalTracesPerUser: l.MaxGlobalTracesPerUser,
+			MaxLiveTracesBytes:     l.MaxLiveTracesBytes,
+			MaxSpansPerTrace:       l.MaxSpansPerTrace,
+			TruncateLargeTraces:    l.TruncateLargeTraces,
 			TenantShardSize:        l.IngestionTenantShardSize,
+			DryRun:                 l.IngestionDryRun,
+			MaxAttributesPerSpan:   l.IngestionMaxAttributesPerSpan,
+			MaxAttributeBytes:      l.IngestionMaxAttributeBytes,
 		},
 		Read: ReadOverrides{
 			MaxBytesPerTagValuesQuery:  l.MaxBytesPerTagValuesQuery,
 			MaxBlocksPerTagValuesQuery: l.MaxBlocksPerTagValuesQuery,
 			MaxSearchDuration:          l.MaxSearchDuration,
 			MaxMetricsDuration:         l.MaxMetricsDuration,
+			MaxTagsDuration:            l.MaxTagsDuration,
+			MaxExemplars:               l.MaxExemplars,
+			ExemplarPolicy:             l.ExemplarPolicy,
+			MaxMetricsSeries:           l.MaxMetricsSeries,
+			MaxMetricsResponseBytes:    l.MaxMetricsResponseBytes,
 			UnsafeQueryHints:           l.UnsafeQueryHints,
+			InferAttributeScopes:       l.InferAttributeScopes,
+			MaxInflightRequests:        l.MaxInflightRequests,
+			QueueWeight:                l.QueryQueueWeight,
+
+			MaxBackendBlocksPerTagValuesQuery: l.MaxBackendBlocksPerTagValuesQuery,
 		},
 		Compaction: CompactionOverrides{
 			BlockRetention:   l.BlockRetention,
 			CompactionWindow: l.CompactionWindow,
+
+			MaxBytesPerCycle: l.CompactionMaxBytesPerCycle,
+			MaxJobsPerCycle:  l.CompactionMaxJobsPerCycle,
 		},
 		MetricsGenerator: MetricsGeneratorOverrides{
 			RingSize:           l.MetricsGeneratorRingSize,
@@ -164,7 +278,13 @@ func (l *LegacyOverrides) toNewLimits() Overrides {
 			DisableCollection:  l.MetricsGeneratorDisableCollection,
 			TraceIDLabelName:   l.MetricsGeneratorTraceIDLabelName,
 			IngestionSlack:     l.MetricsGeneratorIngestionSlack,
+			FilterPolicies:     l.MetricsGeneratorFilterPolicies,
 			RemoteWriteHeaders: l.MetricsGeneratorRemoteWriteHeaders,
+			RemoteWriteRoutes:  l.MetricsGeneratorRemoteWriteRoutes,
+
+			GenerateNativeHistograms:       l.MetricsGeneratorGenerateNativeHistograms,
+			NativeHistogramBucketFactor:    l.MetricsGeneratorNativeHistogramBucketFactor,
+			NativeHistogramMaxBucketNumber: l.MetricsGeneratorNativeHistogramMaxBucketNumber,
 			Forwarder: ForwarderOverrides{
 				QueueSize: l.MetricsGeneratorForwarderQueueSize,
 				Workers:   l.MetricsGeneratorForwarderWorkers,
@@ -172,20 +292,36 @@ func (l *LegacyOverrides) toNewLimits() Overrides {
 			Processor: ProcessorOverrides{
 				ServiceGraphs: ServiceGraphsOverrides{
 					HistogramBuckets:                      l.MetricsGeneratorProcessorServiceGraphsHistogramBuckets,
+					DatabaseHistogramBuckets:              l.MetricsGeneratorProcessorServiceGraphsDatabaseHistogramBuckets,
+					MessagingSystemHistogramBuckets:       l.MetricsGeneratorProcessorServiceGraphsMessagingSystemHistogramBuckets,
 					Dimensions:                            l.MetricsGeneratorProcessorServiceGraphsDimensions,
 					PeerAttributes:                        l.MetricsGeneratorProcessorServiceGraphsPeerAttributes,
+					DatabaseNameAttributes:                l.MetricsGeneratorProcessorServiceGraphsDatabaseNameAttributes,
+					VirtualNodeNameTemplate:               l.MetricsGeneratorProcessorServiceGraphsVirtualNodeNameTemplate,
 					EnableClientServerPrefix:              l.MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix,
 					EnableMessagingSystemLatencyHistogram: l.MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram,
 					EnableVirtualNodeLabel:                l.MetricsGeneratorProcessorServiceGraphsEnableVirtualNodeLabel,
+					StaleDuration:                         l.MetricsGeneratorProcessorServiceGraphsStaleDuration,
 				},
 				SpanMetrics: SpanMetricsOverrides{
-					HistogramBuckets:             l.MetricsGeneratorProcessorSpanMetricsHistogramBuckets,
-					Dimensions:                   l.MetricsGeneratorProcessorSpanMetricsDimensions,
-					IntrinsicDimensions:          l.MetricsGeneratorProcessorSpanMetricsIntrinsicDimensions,
-					FilterPolicies:               l.MetricsGeneratorProcessorSpanMetricsFilterPolicies,
-					DimensionMappings:            l.MetricsGeneratorProcessorSpanMetricsDimensionMappings,
-					EnableTargetInfo:             l.MetricsGeneratorProcessorSpanMetricsEnableTargetInfo,
-					TargetInfoExcludedDimensions: l.MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions,
+					HistogramBuckets:                l.MetricsGeneratorProcessorSpanMetricsHistogramBuckets,
+					Dimensions:                      l.MetricsGeneratorProcessorSpanMetricsDimensions,
+					IntrinsicDimensions:             l.MetricsGeneratorProcessorSpanMetricsIntrinsicDimensions,
+					SubprocessorIntrinsicDimensions: l.MetricsGeneratorProcessorSpanMetricsSubprocessorIntrinsicDimensions,
+					FilterPolicies:                  l.MetricsGeneratorProcessorSpanMetricsFilterPolicies,
+					DimensionMappings:               l.MetricsGeneratorProcessorSpanMetricsDimensionMappings,
+					EnableTargetInfo:                l.MetricsGeneratorProcessorSpanMetricsEnableTargetInfo,
+					TargetInfoExcludedDimensions:    l.MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions,
+					TargetInfoMetricName:            l.MetricsGeneratorProcessorSpanMetricsTargetInfoMetricName,
+					StaleDuration:                   l.MetricsGeneratorProcessorSpanMetricsStaleDuration,
+				},
+				SpanEvents: SpanEventsOverrides{
+					EventNames:        l.MetricsGeneratorProcessorSpanEventsEventNames,
+					Dimensions:        l.MetricsGeneratorProcessorSpanEventsDimensions,
+					DimensionMappings: l.MetricsGeneratorProcessorSpanEventsDimensionMappings,
+				},
+				LinkMetrics: LinkMetricsOverrides{
+					Dimensions: l.MetricsGeneratorProcessorLinkMetricsDimensions,
 				},
 				LocalBlocks: LocalBlocksOverrides{
 					MaxLiveTraces:        l.MetricsGeneratorProcessorLocalBlocksMaxLiveTraces,
@@ -204,6 +340,12 @@ func (l *LegacyOverrides) toNewLimits() Overrides {
 		Storage: StorageOverrides{
 			DedicatedColumns: l.DedicatedColumns,
 		},
+		CostAttribution: CostAttributionOverrides{
+			Dimensions: l.CostAttributionDimensions,
+		},
+		Enrichment: EnrichmentOverrides{
+			Lookups: l.EnrichmentLookups,
+		},
 	}
 }
 
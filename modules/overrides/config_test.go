@@ -121,6 +121,9 @@ metrics_generator_processor_span_metrics_histogram_buckets: [3,4]
 metrics_generator_processor_span_metrics_dimensions: ['foo']
 metrics_generator_processor_span_metrics_intrinsic_dimensions:
   foo: true
+metrics_generator_processor_span_metrics_subprocessor_intrinsic_dimensions:
+  span-metrics-latency:
+    foo: false
 metrics_generator_processor_span_metrics_filter_policies:
   - include:
       match_type: strict
@@ -202,6 +205,9 @@ defaults:
         - foo
         intrinsic_dimensions:
           foo: true
+        subprocessor_intrinsic_dimensions:
+          span-metrics-latency:
+            foo: false
         filter_policies:
         - include:
             match_type: strict
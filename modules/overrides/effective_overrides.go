@@ -0,0 +1,150 @@
+package overrides
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	"github.com/grafana/tempo/pkg/util"
+)
+
+var metricEffectiveLimitsDesc = prometheus.NewDesc(
+	"tempo_limits_effective",
+	"Resource limits applied to tenants after resolving the defaults, runtime and user-configurable overrides",
+	[]string{"limit_name", "user"},
+	nil,
+)
+
+// EffectiveOverridesHandler responds with the value of every limit applied to the tenant. The response is json
+// if requested in the Accept header, yaml otherwise.
+func EffectiveOverridesHandler(o Interface) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		tenant := mux.Vars(req)["tenant"]
+		if tenant == "" {
+			http.Error(w, "Tenant ID can't be empty", http.StatusBadRequest)
+			return
+		}
+
+		limits := effectiveLimits(o.GetEffectiveOverridesFor(tenant))
+
+		if strings.Contains(req.Header.Get("Accept"), "application/json") {
+			util.WriteJSONResponse(w, limits)
+			return
+		}
+		util.WriteYAMLResponse(w, limits)
+	}
+}
+
+// effectiveLimitsCollector exports the numeric effective limits of the tenants with runtime or
+// user-configurable overrides.
+type effectiveLimitsCollector struct {
+	o Interface
+}
+
+// NewEffectiveLimitsCollector returns a collector of the effective limits of the tenants with overrides.
+func NewEffectiveLimitsCollector(o Interface) prometheus.Collector {
+	return &effectiveLimitsCollector{o: o}
+}
+
+func (c *effectiveLimitsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- metricEffectiveLimitsDesc
+}
+
+func (c *effectiveLimitsCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, tenant := range tenantsWithOverrides(c.o) {
+		for name, v := range effectiveLimits(c.o.GetEffectiveOverridesFor(tenant)) {
+			if f, ok := limitValue(v); ok {
+				ch <- prometheus.MustNewConstMetric(metricEffectiveLimitsDesc, prometheus.GaugeValue, f, name, tenant)
+			}
+		}
+	}
+}
+
+// tenantsWithOverrides returns the tenants with runtime or user-configurable overrides, the wildcard
+// tenant is not included.
+func tenantsWithOverrides(o Interface) []string {
+	tenants := map[string]struct{}{}
+	add := func(ids []string) {
+		for _, id := range ids {
+			if id != wildcardTenant {
+				tenants[id] = struct{}{}
+			}
+		}
+	}
+
+	switch o := o.(type) {
+	case *userConfigurableOverridesManager:
+		add(o.Interface.GetTenantIDs())
+		add(o.GetTenantIDs())
+	default:
+		add(o.GetTenantIDs())
+	}
+
+	ids := maps.Keys(tenants)
+	slices.Sort(ids)
+	return ids
+}
+
+// effectiveLimits flattens the overrides into the value of every limit keyed by its yaml path, e.g.
+// ingestion.rate_limit_bytes. Unset limits are included with their zero value.
+func effectiveLimits(o *Overrides) map[string]interface{} {
+	limits := map[string]interface{}{}
+	flattenLimits("", reflect.ValueOf(o).Elem(), limits)
+	return limits
+}
+
+func flattenLimits(prefix string, v reflect.Value, limits map[string]interface{}) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		name = prefix + name
+
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Struct {
+			flattenLimits(name+".", fv, limits)
+			continue
+		}
+		limits[name] = fv.Interface()
+	}
+}
+
+// limitValue returns the value of a limit as a gauge value, durations are in seconds. Limits that aren't
+// numbers or booleans have no gauge value.
+func limitValue(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	case time.Duration:
+		return v.Seconds(), true
+	case model.Duration:
+		return time.Duration(v).Seconds(), true
+	default:
+		return 0, false
+	}
+}
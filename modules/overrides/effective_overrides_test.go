@@ -0,0 +1,109 @@
+package overrides
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides/userconfigurable/client"
+)
+
+func TestEffectiveOverrides(t *testing.T) {
+	defaults := Overrides{
+		Ingestion: IngestionOverrides{
+			RateLimitBytes: 100,
+		},
+		MetricsGenerator: MetricsGeneratorOverrides{
+			CollectionInterval: 60 * time.Second,
+		},
+	}
+	tenantOverrides := &perTenantOverrides{
+		TenantLimits: map[string]*Overrides{
+			"foo": {
+				Ingestion: IngestionOverrides{
+					RateLimitBytes: 200,
+				},
+				MetricsGenerator: MetricsGeneratorOverrides{
+					CollectionInterval: 15 * time.Second,
+				},
+			},
+		},
+	}
+
+	_, o, cleanup := localUserConfigOverrides(t, defaults, toYamlBytes(t, tenantOverrides))
+	defer cleanup()
+
+	o.setTenantLimit("foo", &client.Limits{
+		MetricsGenerator: client.LimitsMetricsGenerator{
+			CollectionInterval: &client.Duration{Duration: 5 * time.Minute},
+		},
+	})
+	o.setTenantLimit("bar", &client.Limits{
+		Forwarders: &[]string{"fwd"},
+	})
+
+	// runtime overrides with the user-configurable overrides on top
+	effective := o.GetEffectiveOverridesFor("foo")
+	assert.Equal(t, 200, effective.Ingestion.RateLimitBytes)
+	assert.Equal(t, 5*time.Minute, effective.MetricsGenerator.CollectionInterval)
+	assert.Equal(t, 15*time.Second, o.GetRuntimeOverridesFor("foo").MetricsGenerator.CollectionInterval)
+
+	effective = o.GetEffectiveOverridesFor("bar")
+	assert.Equal(t, 100, effective.Ingestion.RateLimitBytes)
+	assert.Equal(t, []string{"fwd"}, effective.Forwarders)
+	assert.Empty(t, o.GetRuntimeOverridesFor("bar").Forwarders)
+
+	// every limit is listed, including unset ones
+	limits := effectiveLimits(effective)
+	assert.Equal(t, 100, limits["ingestion.rate_limit_bytes"])
+	assert.Equal(t, 0, limits["ingestion.max_traces_per_user"])
+	assert.Equal(t, 60*time.Second, limits["metrics_generator.collection_interval"])
+	assert.Contains(t, limits, "metrics_generator.processor.local_blocks.max_live_traces")
+
+	// api
+	router := mux.NewRouter()
+	router.Path("/status/overrides/{tenant}/effective").HandlerFunc(EffectiveOverridesHandler(o))
+
+	req := httptest.NewRequest(http.MethodGet, "/status/overrides/foo/effective", nil)
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 200.0, resp["ingestion.rate_limit_bytes"])
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status/overrides/foo/effective", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "ingestion.rate_limit_bytes: 200")
+
+	// metrics of the tenants with runtime or user-configurable overrides
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(NewEffectiveLimitsCollector(o))
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+
+	values := map[string]float64{}
+	for _, m := range families[0].Metric {
+		labels := map[string]string{}
+		for _, l := range m.Label {
+			labels[l.GetName()] = l.GetValue()
+		}
+		values[labels["user"]+"/"+labels["limit_name"]] = m.Gauge.GetValue()
+	}
+	assert.Equal(t, 200.0, values["foo/ingestion.rate_limit_bytes"])
+	assert.Equal(t, 300.0, values["foo/metrics_generator.collection_interval"])
+	assert.Equal(t, 100.0, values["bar/ingestion.rate_limit_bytes"])
+	assert.Equal(t, 60.0, values["bar/metrics_generator.collection_interval"])
+	assert.NotContains(t, values, "foo/forwarders")
+}
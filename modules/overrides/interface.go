@@ -28,35 +28,64 @@ type Interface interface {
 	// overrides from the user-configurable overrides, if enabled.
 	GetRuntimeOverridesFor(userID string) *Overrides
 
+	// GetEffectiveOverridesFor returns the overrides applied to the given user, the defaults, runtime
+	// and user-configurable overrides resolved in that order.
+	GetEffectiveOverridesFor(userID string) *Overrides
+
 	// Config
 	IngestionRateStrategy() string
 	MaxLocalTracesPerUser(userID string) int
 	MaxGlobalTracesPerUser(userID string) int
+	MaxLiveTracesBytes(userID string) int
+	MaxSpansPerTrace(userID string) int
+	TruncateLargeTraces(userID string) bool
+	CostAttributionDimensions(userID string) map[string]string
+	EnrichmentLookups(userID string) []EnrichmentLookup
 	MaxBytesPerTrace(userID string) int
 	MaxCompactionRange(userID string) time.Duration
+	MaxCompactionBytesPerCycle(userID string) uint64
+	MaxCompactionJobsPerCycle(userID string) int
 	Forwarders(userID string) []string
 	MaxBytesPerTagValuesQuery(userID string) int
 	MaxBlocksPerTagValuesQuery(userID string) int
+	MaxBackendBlocksPerTagValuesQuery(userID string) int
 	IngestionRateLimitBytes(userID string) float64
 	IngestionBurstSizeBytes(userID string) int
+	IngestionRateLimitSpans(userID string) float64
+	IngestionBurstSizeSpans(userID string) int
 	IngestionTenantShardSize(userID string) int
+	IngestionDryRun(userID string) bool
+	IngestionMaxAttributesPerSpan(userID string) int
+	IngestionMaxAttributeBytes(userID string) int
 	MetricsGeneratorIngestionSlack(userID string) time.Duration
+	MetricsGeneratorFilterPolicies(userID string) []config.FilterPolicy
 	MetricsGeneratorRingSize(userID string) int
 	MetricsGeneratorProcessors(userID string) map[string]struct{}
 	MetricsGeneratorMaxActiveSeries(userID string) uint32
 	MetricsGeneratorCollectionInterval(userID string) time.Duration
 	MetricsGeneratorDisableCollection(userID string) bool
 	MetricsGenerationTraceIDLabelName(userID string) string
+	MetricsGeneratorGenerateNativeHistograms(userID string) HistogramMethod
+	MetricsGeneratorNativeHistogramBucketFactor(userID string) float64
+	MetricsGeneratorNativeHistogramMaxBucketNumber(userID string) uint32
 	MetricsGeneratorRemoteWriteHeaders(userID string) map[string]string
+	MetricsGeneratorRemoteWriteRoutes(userID string) []RemoteWriteRoute
 	MetricsGeneratorForwarderQueueSize(userID string) int
 	MetricsGeneratorForwarderWorkers(userID string) int
 	MetricsGeneratorProcessorServiceGraphsHistogramBuckets(userID string) []float64
+	MetricsGeneratorProcessorServiceGraphsDatabaseHistogramBuckets(userID string) []float64
+	MetricsGeneratorProcessorServiceGraphsMessagingSystemHistogramBuckets(userID string) []float64
 	MetricsGeneratorProcessorServiceGraphsDimensions(userID string) []string
 	MetricsGeneratorProcessorServiceGraphsPeerAttributes(userID string) []string
+	MetricsGeneratorProcessorServiceGraphsDatabaseNameAttributes(userID string) []string
+	MetricsGeneratorProcessorServiceGraphsVirtualNodeNameTemplate(userID string) string
+	MetricsGeneratorProcessorServiceGraphsStaleDuration(userID string) time.Duration
 	MetricsGeneratorProcessorSpanMetricsHistogramBuckets(userID string) []float64
 	MetricsGeneratorProcessorSpanMetricsDimensions(userID string) []string
 	MetricsGeneratorProcessorSpanMetricsIntrinsicDimensions(userID string) map[string]bool
+	MetricsGeneratorProcessorSpanMetricsSubprocessorIntrinsicDimensions(userID string) map[string]map[string]bool
 	MetricsGeneratorProcessorSpanMetricsFilterPolicies(userID string) []config.FilterPolicy
+	MetricsGeneratorProcessorSpanMetricsStaleDuration(userID string) time.Duration
 	MetricsGeneratorProcessorLocalBlocksMaxLiveTraces(userID string) uint64
 	MetricsGeneratorProcessorLocalBlocksMaxBlockDuration(userID string) time.Duration
 	MetricsGeneratorProcessorLocalBlocksMaxBlockBytes(userID string) uint64
@@ -69,11 +98,24 @@ type Interface interface {
 	MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram(userID string) bool
 	MetricsGeneratorProcessorServiceGraphsEnableVirtualNodeLabel(userID string) bool
 	MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions(userID string) []string
+	MetricsGeneratorProcessorSpanMetricsTargetInfoMetricName(userID string) string
+	MetricsGeneratorProcessorSpanEventsEventNames(userID string) []string
+	MetricsGeneratorProcessorSpanEventsDimensions(userID string) []string
+	MetricsGeneratorProcessorSpanEventsDimensionMappings(userID string) []sharedconfig.DimensionMappings
+	MetricsGeneratorProcessorLinkMetricsDimensions(userID string) []string
 	BlockRetention(userID string) time.Duration
 	MaxSearchDuration(userID string) time.Duration
 	MaxMetricsDuration(userID string) time.Duration
+	MaxTagsDuration(userID string) time.Duration
+	MaxExemplars(userID string) int
+	ExemplarPolicy(userID string) string
+	MaxMetricsSeries(userID string) int
+	MaxMetricsResponseBytes(userID string) int
 	DedicatedColumns(userID string) backend.DedicatedColumns
 	UnsafeQueryHints(userID string) bool
+	InferAttributeScopes(userID string) bool
+	MaxInflightRequests(userID string) int
+	QueueWeight(userID string) int
 
 	// Management API
 	WriteStatusRuntimeConfig(w io.Writer, r *http.Request) error
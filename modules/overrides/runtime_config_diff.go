@@ -0,0 +1,147 @@
+package overrides
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/pkg/util/log"
+)
+
+var (
+	metricRuntimeConfigReloads = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "runtime_config_reloads_total",
+		Help:      "The total number of runtime config reloads that changed the overrides of at least one tenant.",
+	})
+	metricRuntimeConfigChangedLimits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "runtime_config_changed_limits_total",
+		Help:      "The total number of limits changed by runtime config reloads per tenant.",
+	}, []string{"tenant"})
+)
+
+// limitDiff is a limit of a tenant that differs from the default limits.
+type limitDiff struct {
+	Default interface{} `yaml:"default" json:"default"`
+	Value   interface{} `yaml:"value" json:"value"`
+}
+
+// RuntimeConfigDiffHandler responds with the limits of every tenant with runtime or user-configurable overrides
+// that differ from the default limits. The tenant query parameter restricts the response to a single tenant.
+// The response is json if requested in the Accept header, yaml otherwise.
+func RuntimeConfigDiffHandler(o Interface, defaults *Overrides) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		tenants := tenantsWithOverrides(o)
+		if tenant := req.URL.Query().Get("tenant"); tenant != "" {
+			tenants = []string{tenant}
+		}
+
+		defaultLimits := effectiveLimits(defaults)
+
+		diff := map[string]map[string]limitDiff{}
+		for _, tenant := range tenants {
+			limits := effectiveLimits(o.GetEffectiveOverridesFor(tenant))
+
+			tenantDiff := map[string]limitDiff{}
+			for _, name := range changedLimits(defaultLimits, limits) {
+				tenantDiff[name] = limitDiff{Default: defaultLimits[name], Value: limits[name]}
+			}
+			if len(tenantDiff) > 0 {
+				diff[tenant] = tenantDiff
+			}
+		}
+
+		if strings.Contains(req.Header.Get("Accept"), "application/json") {
+			util.WriteJSONResponse(w, diff)
+			return
+		}
+		util.WriteYAMLResponse(w, diff)
+	}
+}
+
+// changedLimits returns the sorted names of the limits with different values in a and b.
+func changedLimits(a, b map[string]interface{}) []string {
+	var changed []string
+	for name, v := range b {
+		if !limitEqual(a[name], v) {
+			changed = append(changed, name)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+// limitEqual compares the values of a limit, nil and empty maps or slices are equal.
+func limitEqual(a, b interface{}) bool {
+	isEmpty := func(v interface{}) bool {
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Map, reflect.Slice:
+			return rv.Len() == 0
+		default:
+			return false
+		}
+	}
+	if isEmpty(a) && isEmpty(b) {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// runtimeConfigChanges returns the names of the changed limits of every tenant whose overrides differ between
+// the previous and the new runtime config. Tenants without overrides use the default limits.
+func runtimeConfigChanges(prev, cfg *perTenantOverrides, defaults *Overrides) map[string][]string {
+	limitsFor := func(o *perTenantOverrides, tenant string) *Overrides {
+		if o != nil {
+			if l := o.forUser(tenant); l != nil {
+				return l
+			}
+		}
+		return defaults
+	}
+
+	tenants := map[string]struct{}{}
+	for _, o := range []*perTenantOverrides{prev, cfg} {
+		if o == nil {
+			continue
+		}
+		for tenant := range o.TenantLimits {
+			tenants[tenant] = struct{}{}
+		}
+	}
+
+	changes := map[string][]string{}
+	for tenant := range tenants {
+		changed := changedLimits(effectiveLimits(limitsFor(prev, tenant)), effectiveLimits(limitsFor(cfg, tenant)))
+		if len(changed) > 0 {
+			changes[tenant] = changed
+		}
+	}
+	return changes
+}
+
+// runtimeConfigReloaded logs and counts the limits changed by a runtime config reload.
+func (o *runtimeConfigOverridesManager) runtimeConfigReloaded(prev, cfg *perTenantOverrides) {
+	changes := runtimeConfigChanges(prev, cfg, o.defaultLimits)
+	if len(changes) == 0 {
+		return
+	}
+
+	tenants := maps.Keys(changes)
+	slices.Sort(tenants)
+
+	metricRuntimeConfigReloads.Inc()
+	for _, tenant := range tenants {
+		metricRuntimeConfigChangedLimits.WithLabelValues(tenant).Add(float64(len(changes[tenant])))
+		level.Info(log.Logger).Log("msg", "runtime config changed tenant overrides", "tenant", tenant, "limits", strings.Join(changes[tenant], ","))
+	}
+	level.Info(log.Logger).Log("msg", "runtime config reloaded", "changed_tenants", strings.Join(tenants, ","))
+}
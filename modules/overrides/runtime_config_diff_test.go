@@ -0,0 +1,144 @@
+package overrides
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides/userconfigurable/client"
+)
+
+func TestRuntimeConfigDiffHandler(t *testing.T) {
+	defaults := Overrides{
+		Ingestion: IngestionOverrides{
+			RateLimitBytes: 100,
+			BurstSizeBytes: 200,
+		},
+	}
+	tenantOverrides := &perTenantOverrides{
+		TenantLimits: map[string]*Overrides{
+			"foo": {
+				Ingestion: IngestionOverrides{
+					RateLimitBytes: 300,
+					BurstSizeBytes: 200,
+				},
+			},
+		},
+	}
+
+	_, o, cleanup := localUserConfigOverrides(t, defaults, toYamlBytes(t, tenantOverrides))
+	defer cleanup()
+
+	o.setTenantLimit("bar", &client.Limits{
+		Forwarders: &[]string{"fwd"},
+	})
+
+	handler := RuntimeConfigDiffHandler(o, &defaults)
+
+	req := httptest.NewRequest(http.MethodGet, "/status/runtime_config/diff", nil)
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp map[string]map[string]limitDiff
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp, 2)
+
+	// only limits that differ from the defaults are listed
+	assert.Equal(t, map[string]limitDiff{
+		"ingestion.rate_limit_bytes": {Default: 100.0, Value: 300.0},
+	}, resp["foo"])
+	assert.Equal(t, map[string]limitDiff{
+		"forwarders": {Default: nil, Value: []interface{}{"fwd"}},
+	}, resp["bar"])
+
+	// a single tenant
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/status/runtime_config/diff?tenant=foo", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "ingestion.rate_limit_bytes")
+	assert.NotContains(t, rec.Body.String(), "forwarders")
+}
+
+func TestRuntimeConfigChanges(t *testing.T) {
+	defaults := &Overrides{
+		Ingestion: IngestionOverrides{
+			RateLimitBytes: 100,
+		},
+	}
+	prev := &perTenantOverrides{
+		TenantLimits: map[string]*Overrides{
+			"removed":   {Ingestion: IngestionOverrides{RateLimitBytes: 200}},
+			"unchanged": {Ingestion: IngestionOverrides{RateLimitBytes: 200}},
+			"changed":   {Ingestion: IngestionOverrides{RateLimitBytes: 200}},
+		},
+	}
+	cfg := &perTenantOverrides{
+		TenantLimits: map[string]*Overrides{
+			"unchanged": {Ingestion: IngestionOverrides{RateLimitBytes: 200}},
+			"changed":   {Ingestion: IngestionOverrides{RateLimitBytes: 200, BurstSizeBytes: 10}, Forwarders: []string{"fwd"}},
+			"added":     {Ingestion: IngestionOverrides{RateLimitBytes: 100, MaxSpansPerTrace: 5}},
+		},
+	}
+
+	assert.Equal(t, map[string][]string{
+		"removed": {"ingestion.rate_limit_bytes"},
+		"changed": {"forwarders", "ingestion.burst_size_bytes"},
+		"added":   {"ingestion.max_spans_per_trace"},
+	}, runtimeConfigChanges(prev, cfg, defaults))
+
+	assert.Empty(t, runtimeConfigChanges(cfg, cfg, defaults))
+	assert.Equal(t, map[string][]string{
+		"unchanged": {"ingestion.rate_limit_bytes"},
+		"changed":   {"forwarders", "ingestion.burst_size_bytes", "ingestion.rate_limit_bytes"},
+		"added":     {"ingestion.max_spans_per_trace"},
+	}, runtimeConfigChanges(nil, cfg, defaults))
+}
+
+func TestRuntimeConfigReloadEvents(t *testing.T) {
+	overridesFile := filepath.Join(t.TempDir(), "Overrides.yaml")
+	require.NoError(t, os.WriteFile(overridesFile, []byte(`
+overrides:
+  reload-tenant:
+    ingestion:
+      rate_limit_bytes: 200
+`), os.ModePerm))
+
+	cfg := Config{
+		PerTenantOverrideConfig: overridesFile,
+		PerTenantOverridePeriod: model.Duration(50 * time.Millisecond),
+	}
+
+	overrides, err := newRuntimeConfigOverrides(cfg, &mockValidator{}, prometheus.NewRegistry())
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), overrides))
+	defer func() {
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), overrides))
+	}()
+
+	require.NoError(t, os.WriteFile(overridesFile, []byte(`
+overrides:
+  reload-tenant:
+    ingestion:
+      rate_limit_bytes: 300
+      burst_size_bytes: 400
+`), os.ModePerm))
+
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(metricRuntimeConfigChangedLimits.WithLabelValues("reload-tenant")) == 2
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.Equal(t, 300.0, overrides.IngestionRateLimitBytes("reload-tenant"))
+}
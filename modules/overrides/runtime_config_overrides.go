@@ -32,6 +32,11 @@ type Validator interface {
 type perTenantOverrides struct {
 	TenantLimits map[string]*Overrides `yaml:"overrides"`
 
+	// Groups are named override profiles shared by several tenants. A tenant listed in TenantGroups inherits
+	// the overrides of its group, values set in the tenant's own overrides take precedence.
+	Groups       map[string]*Overrides `yaml:"groups,omitempty"`
+	TenantGroups map[string]string     `yaml:"tenant_groups,omitempty"`
+
 	ConfigType ConfigType `yaml:"-"` // ConfigType is the type of overrides config we are using: legacy or new
 }
 
@@ -43,7 +48,7 @@ func (o *perTenantOverrides) UnmarshalYAML(unmarshal func(interface{}) error) er
 	type rawConfig perTenantOverrides
 	if err := unmarshal((*rawConfig)(o)); err == nil {
 		o.ConfigType = ConfigTypeNew
-		return nil
+		return o.applyGroups(unmarshal)
 	}
 
 	var legacyConfig perTenantLegacyOverrides
@@ -57,6 +62,52 @@ func (o *perTenantOverrides) UnmarshalYAML(unmarshal func(interface{}) error) er
 	return nil
 }
 
+// applyGroups replaces the limits of every tenant assigned to a group with the group's overrides with the
+// tenant's overrides applied on top. Both are merged as YAML so that only the fields set for the tenant
+// replace the values of the group.
+func (o *perTenantOverrides) applyGroups(unmarshal func(interface{}) error) error {
+	if len(o.TenantGroups) == 0 {
+		return nil
+	}
+
+	var raw struct {
+		TenantLimits map[string]yaml.MapSlice `yaml:"overrides"`
+		Groups       map[string]yaml.MapSlice `yaml:"groups"`
+		TenantGroups map[string]string        `yaml:"tenant_groups"`
+	}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	if o.TenantLimits == nil {
+		o.TenantLimits = make(map[string]*Overrides, len(o.TenantGroups))
+	}
+
+	for tenant, group := range o.TenantGroups {
+		if _, ok := o.Groups[group]; !ok {
+			return fmt.Errorf("tenant %s is assigned to unknown overrides group %s", tenant, group)
+		}
+
+		limits := &Overrides{}
+		for _, m := range []yaml.MapSlice{raw.Groups[group], raw.TenantLimits[tenant]} {
+			if len(m) == 0 {
+				continue
+			}
+
+			b, err := yaml.Marshal(m)
+			if err != nil {
+				return err
+			}
+			if err := yaml.UnmarshalStrict(b, limits); err != nil {
+				return fmt.Errorf("applying overrides group %s to tenant %s failed: %w", group, tenant, err)
+			}
+		}
+		o.TenantLimits[tenant] = limits
+	}
+
+	return nil
+}
+
 // forUser returns limits for a given tenant, or nil if there are no tenant-specific limits.
 func (o *perTenantOverrides) forUser(userID string) *Overrides {
 	l, ok := o.TenantLimits[userID]
@@ -121,6 +172,7 @@ type runtimeConfigOverridesManager struct {
 
 	defaultLimits    *Overrides
 	runtimeConfigMgr *runtimeconfig.Manager
+	runtimeConfigCh  <-chan interface{}
 
 	// Manager for subservices
 	subservices        *services.Manager
@@ -175,16 +227,29 @@ func (o *runtimeConfigOverridesManager) starting(ctx context.Context) error {
 		}
 	}
 
+	if o.runtimeConfigMgr != nil {
+		o.runtimeConfigCh = o.runtimeConfigMgr.CreateListenerChannel(1)
+	}
+
 	return nil
 }
 
 func (o *runtimeConfigOverridesManager) running(ctx context.Context) error {
 	if o.subservices != nil {
-		select {
-		case <-ctx.Done():
-			return nil
-		case err := <-o.subservicesWatcher.Chan():
-			return fmt.Errorf("overrides subservices failed: %w", err)
+		prev := o.tenantOverrides()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case err := <-o.subservicesWatcher.Chan():
+				return fmt.Errorf("overrides subservices failed: %w", err)
+			case cfg := <-o.runtimeConfigCh:
+				// the listener channel is never closed while the runtime config manager is running
+				if cfg, ok := cfg.(*perTenantOverrides); ok {
+					o.runtimeConfigReloaded(prev, cfg)
+					prev = cfg
+				}
+			}
 		}
 	}
 	<-ctx.Done()
@@ -284,6 +349,10 @@ func (o *runtimeConfigOverridesManager) GetRuntimeOverridesFor(userID string) *O
 	return o.getOverridesForUser(userID)
 }
 
+func (o *runtimeConfigOverridesManager) GetEffectiveOverridesFor(userID string) *Overrides {
+	return o.getOverridesForUser(userID)
+}
+
 // IngestionRateStrategy returns whether the ingestion rate limit should be individually applied
 // to each distributor instance (local) or evenly shared across the cluster (global).
 func (o *runtimeConfigOverridesManager) IngestionRateStrategy() string {
@@ -304,11 +373,50 @@ func (o *runtimeConfigOverridesManager) MaxGlobalTracesPerUser(userID string) in
 	return o.getOverridesForUser(userID).Ingestion.MaxGlobalTracesPerUser
 }
 
+// MaxLiveTracesBytes returns the maximum size in bytes of all live traces a user is allowed
+// to store in a single ingester.
+func (o *runtimeConfigOverridesManager) MaxLiveTracesBytes(userID string) int {
+	return o.getOverridesForUser(userID).Ingestion.MaxLiveTracesBytes
+}
+
+// MaxSpansPerTrace returns the maximum number of spans of a single trace allowed for a user
+// in a single ingester.
+func (o *runtimeConfigOverridesManager) MaxSpansPerTrace(userID string) int {
+	return o.getOverridesForUser(userID).Ingestion.MaxSpansPerTrace
+}
+
+// TruncateLargeTraces returns true if traces exceeding the per trace limits are truncated
+// instead of refused.
+func (o *runtimeConfigOverridesManager) TruncateLargeTraces(userID string) bool {
+	return o.getOverridesForUser(userID).Ingestion.TruncateLargeTraces
+}
+
+// CostAttributionDimensions returns the attributes used as dimensions of the usage tracker
+// mapped to their label names.
+func (o *runtimeConfigOverridesManager) CostAttributionDimensions(userID string) map[string]string {
+	return o.getOverridesForUser(userID).CostAttribution.Dimensions
+}
+
+// EnrichmentLookups returns the lookup tables the spans of the tenant are joined against in the distributor
+func (o *runtimeConfigOverridesManager) EnrichmentLookups(userID string) []EnrichmentLookup {
+	return o.getOverridesForUser(userID).Enrichment.Lookups
+}
+
 // MaxCompactionRange returns the maximum compaction window for this tenant.
 func (o *runtimeConfigOverridesManager) MaxCompactionRange(userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).Compaction.CompactionWindow)
 }
 
+// MaxCompactionBytesPerCycle returns the maximum input bytes compacted for this tenant in a compaction cycle.
+func (o *runtimeConfigOverridesManager) MaxCompactionBytesPerCycle(userID string) uint64 {
+	return o.getOverridesForUser(userID).Compaction.MaxBytesPerCycle
+}
+
+// MaxCompactionJobsPerCycle returns the maximum compaction jobs run for this tenant in a compaction cycle.
+func (o *runtimeConfigOverridesManager) MaxCompactionJobsPerCycle(userID string) int {
+	return o.getOverridesForUser(userID).Compaction.MaxJobsPerCycle
+}
+
 // IngestionRateLimitBytes is the number of spans per second allowed for this tenant.
 func (o *runtimeConfigOverridesManager) IngestionRateLimitBytes(userID string) float64 {
 	return float64(o.getOverridesForUser(userID).Ingestion.RateLimitBytes)
@@ -319,11 +427,36 @@ func (o *runtimeConfigOverridesManager) IngestionBurstSizeBytes(userID string) i
 	return o.getOverridesForUser(userID).Ingestion.BurstSizeBytes
 }
 
+// IngestionRateLimitSpans is the number of spans per second allowed for this tenant. 0 disables the limit.
+func (o *runtimeConfigOverridesManager) IngestionRateLimitSpans(userID string) float64 {
+	return float64(o.getOverridesForUser(userID).Ingestion.RateLimitSpans)
+}
+
+// IngestionBurstSizeSpans is the burst size in spans allowed for this tenant.
+func (o *runtimeConfigOverridesManager) IngestionBurstSizeSpans(userID string) int {
+	return o.getOverridesForUser(userID).Ingestion.BurstSizeSpans
+}
+
 // IngestionTenantShardSize is the shard size.
 func (o *runtimeConfigOverridesManager) IngestionTenantShardSize(userID string) int {
 	return o.getOverridesForUser(userID).Ingestion.TenantShardSize
 }
 
+// IngestionDryRun returns true if the spans of the tenant are only validated and counted by the distributor.
+func (o *runtimeConfigOverridesManager) IngestionDryRun(userID string) bool {
+	return o.getOverridesForUser(userID).Ingestion.DryRun
+}
+
+// IngestionMaxAttributesPerSpan is the number of attributes per span kept by the distributor.
+func (o *runtimeConfigOverridesManager) IngestionMaxAttributesPerSpan(userID string) int {
+	return o.getOverridesForUser(userID).Ingestion.MaxAttributesPerSpan
+}
+
+// IngestionMaxAttributeBytes is the size attribute values are truncated to by the distributor.
+func (o *runtimeConfigOverridesManager) IngestionMaxAttributeBytes(userID string) int {
+	return o.getOverridesForUser(userID).Ingestion.MaxAttributeBytes
+}
+
 // MaxBytesPerTrace returns the maximum size of a single trace in bytes allowed for a user.
 func (o *runtimeConfigOverridesManager) MaxBytesPerTrace(userID string) int {
 	return o.getOverridesForUser(userID).Global.MaxBytesPerTrace
@@ -344,10 +477,22 @@ func (o *runtimeConfigOverridesManager) MaxBlocksPerTagValuesQuery(userID string
 	return o.getOverridesForUser(userID).Read.MaxBlocksPerTagValuesQuery
 }
 
+// MaxBackendBlocksPerTagValuesQuery returns the maximum number of backend blocks the query-frontend searches for a
+// tag-values query of a user.
+func (o *runtimeConfigOverridesManager) MaxBackendBlocksPerTagValuesQuery(userID string) int {
+	return o.getOverridesForUser(userID).Read.MaxBackendBlocksPerTagValuesQuery
+}
+
 func (o *runtimeConfigOverridesManager) UnsafeQueryHints(userID string) bool {
 	return o.getOverridesForUser(userID).Read.UnsafeQueryHints
 }
 
+// InferAttributeScopes returns true if unscoped attribute conditions are rewritten to the scope the attribute is
+// observed in.
+func (o *runtimeConfigOverridesManager) InferAttributeScopes(userID string) bool {
+	return o.getOverridesForUser(userID).Read.InferAttributeScopes
+}
+
 // MaxSearchDuration is the duration of the max search duration for this tenant.
 func (o *runtimeConfigOverridesManager) MaxSearchDuration(userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).Read.MaxSearchDuration)
@@ -357,17 +502,63 @@ func (o *runtimeConfigOverridesManager) MaxMetricsDuration(userID string) time.D
 	return time.Duration(o.getOverridesForUser(userID).Read.MaxMetricsDuration)
 }
 
+// MaxTagsDuration is the duration of the max tags and tag values lookup duration for this tenant.
+func (o *runtimeConfigOverridesManager) MaxTagsDuration(userID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(userID).Read.MaxTagsDuration)
+}
+
+// MaxExemplars is the maximum number of exemplars per series returned by metrics queries for this tenant.
+func (o *runtimeConfigOverridesManager) MaxExemplars(userID string) int {
+	return o.getOverridesForUser(userID).Read.MaxExemplars
+}
+
+// ExemplarPolicy is how exemplars are selected by metrics queries that don't choose a policy for this tenant.
+func (o *runtimeConfigOverridesManager) ExemplarPolicy(userID string) string {
+	return o.getOverridesForUser(userID).Read.ExemplarPolicy
+}
+
+// MaxMetricsSeries is the maximum number of series a querier evaluates for a metrics query of this tenant.
+func (o *runtimeConfigOverridesManager) MaxMetricsSeries(userID string) int {
+	return o.getOverridesForUser(userID).Read.MaxMetricsSeries
+}
+
+// MaxMetricsResponseBytes is the maximum size of a metrics query response of a querier for this tenant.
+func (o *runtimeConfigOverridesManager) MaxMetricsResponseBytes(userID string) int {
+	return o.getOverridesForUser(userID).Read.MaxMetricsResponseBytes
+}
+
+// MaxInflightRequests is the maximum number of query requests of this tenant processed by queriers at once.
+func (o *runtimeConfigOverridesManager) MaxInflightRequests(userID string) int {
+	return o.getOverridesForUser(userID).Read.MaxInflightRequests
+}
+
+// QueueWeight is the number of consecutive batches a querier pulls from the queue of this tenant before moving on.
+func (o *runtimeConfigOverridesManager) QueueWeight(userID string) int {
+	return o.getOverridesForUser(userID).Read.QueueWeight
+}
+
 // MetricsGeneratorIngestionSlack is the max amount of time passed since a span's end time
 // for the span to be considered in metrics generation
 func (o *runtimeConfigOverridesManager) MetricsGeneratorIngestionSlack(userID string) time.Duration {
 	return o.getOverridesForUser(userID).MetricsGenerator.IngestionSlack
 }
 
+// MetricsGeneratorFilterPolicies returns the filter policies applied to all spans before they are passed
+// to the metrics-generator processors.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorFilterPolicies(userID string) []filterconfig.FilterPolicy {
+	return o.getOverridesForUser(userID).MetricsGenerator.FilterPolicies
+}
+
 // MetricsGeneratorRemoteWriteHeaders returns the custom remote write headers for this tenant.
 func (o *runtimeConfigOverridesManager) MetricsGeneratorRemoteWriteHeaders(userID string) map[string]string {
 	return o.getOverridesForUser(userID).MetricsGenerator.RemoteWriteHeaders.toStringStringMap()
 }
 
+// MetricsGeneratorRemoteWriteRoutes returns the routes of the series of this tenant to the remote write endpoints.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorRemoteWriteRoutes(userID string) []RemoteWriteRoute {
+	return o.getOverridesForUser(userID).MetricsGenerator.RemoteWriteRoutes
+}
+
 // MetricsGeneratorRingSize is the desired size of the metrics-generator ring for this tenant.
 // Using shuffle sharding, a tenant can use a smaller ring than the entire ring.
 func (o *runtimeConfigOverridesManager) MetricsGeneratorRingSize(userID string) int {
@@ -402,6 +593,25 @@ func (o *runtimeConfigOverridesManager) MetricsGenerationTraceIDLabelName(userID
 	return o.getOverridesForUser(userID).MetricsGenerator.TraceIDLabelName
 }
 
+// MetricsGeneratorGenerateNativeHistograms controls whether the metrics-generator produces classic
+// histograms, native histograms or both for this tenant. Classic histograms are used if no value
+// is provided.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorGenerateNativeHistograms(userID string) HistogramMethod {
+	return o.getOverridesForUser(userID).MetricsGenerator.GenerateNativeHistograms
+}
+
+// MetricsGeneratorNativeHistogramBucketFactor is the growth factor between consecutive buckets of
+// native histograms for this tenant.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorNativeHistogramBucketFactor(userID string) float64 {
+	return o.getOverridesForUser(userID).MetricsGenerator.NativeHistogramBucketFactor
+}
+
+// MetricsGeneratorNativeHistogramMaxBucketNumber is the maximum number of buckets of a single native
+// histogram series for this tenant.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorNativeHistogramMaxBucketNumber(userID string) uint32 {
+	return o.getOverridesForUser(userID).MetricsGenerator.NativeHistogramMaxBucketNumber
+}
+
 // MetricsGeneratorForwarderQueueSize is the size of the buffer of requests to send to the metrics-generator
 // from the distributor for this tenant.
 func (o *runtimeConfigOverridesManager) MetricsGeneratorForwarderQueueSize(userID string) int {
@@ -419,6 +629,18 @@ func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorServiceGraphsHi
 	return o.getOverridesForUser(userID).MetricsGenerator.Processor.ServiceGraphs.HistogramBuckets
 }
 
+// MetricsGeneratorProcessorServiceGraphsDatabaseHistogramBuckets controls the histogram buckets to be used
+// for the latency of database edges.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorServiceGraphsDatabaseHistogramBuckets(userID string) []float64 {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.ServiceGraphs.DatabaseHistogramBuckets
+}
+
+// MetricsGeneratorProcessorServiceGraphsMessagingSystemHistogramBuckets controls the histogram buckets to be used
+// for the latency of messaging system edges.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorServiceGraphsMessagingSystemHistogramBuckets(userID string) []float64 {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.ServiceGraphs.MessagingSystemHistogramBuckets
+}
+
 // MetricsGeneratorProcessorServiceGraphsDimensions controls the dimensions that are added to the
 // service graphs processor.
 func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorServiceGraphsDimensions(userID string) []string {
@@ -430,6 +652,16 @@ func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorServiceGraphsPe
 	return o.getOverridesForUser(userID).MetricsGenerator.Processor.ServiceGraphs.PeerAttributes
 }
 
+// MetricsGeneratorProcessorServiceGraphsDatabaseNameAttributes controls the attributes that are used to name database nodes
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorServiceGraphsDatabaseNameAttributes(userID string) []string {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.ServiceGraphs.DatabaseNameAttributes
+}
+
+// MetricsGeneratorProcessorServiceGraphsVirtualNodeNameTemplate controls the template that is used to name virtual nodes
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorServiceGraphsVirtualNodeNameTemplate(userID string) string {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.ServiceGraphs.VirtualNodeNameTemplate
+}
+
 // MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix enables "client" and "server" prefix
 func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix(userID string) bool {
 	return o.getOverridesForUser(userID).MetricsGenerator.Processor.ServiceGraphs.EnableClientServerPrefix
@@ -445,6 +677,12 @@ func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorServiceGraphsEn
 	return o.getOverridesForUser(userID).MetricsGenerator.Processor.ServiceGraphs.EnableVirtualNodeLabel
 }
 
+// MetricsGeneratorProcessorServiceGraphsStaleDuration controls how long series of the service graphs processor
+// are kept without being updated. If 0 the stale duration of the registry is used.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorServiceGraphsStaleDuration(userID string) time.Duration {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.ServiceGraphs.StaleDuration
+}
+
 // MetricsGeneratorProcessorSpanMetricsHistogramBuckets controls the histogram buckets to be used
 // by the span metrics processor.
 func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorSpanMetricsHistogramBuckets(userID string) []float64 {
@@ -463,11 +701,23 @@ func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorSpanMetricsIntr
 	return o.getOverridesForUser(userID).MetricsGenerator.Processor.SpanMetrics.IntrinsicDimensions
 }
 
+// MetricsGeneratorProcessorSpanMetricsSubprocessorIntrinsicDimensions controls the intrinsic dimensions that are
+// activated or deactivated for a single subprocessor of the span metrics processor, keyed by subprocessor.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorSpanMetricsSubprocessorIntrinsicDimensions(userID string) map[string]map[string]bool {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.SpanMetrics.SubprocessorIntrinsicDimensions
+}
+
 // MetricsGeneratorProcessorSpanMetricsFilterPolicies controls the filter policies that are added to the spanmetrics processor.
 func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorSpanMetricsFilterPolicies(userID string) []filterconfig.FilterPolicy {
 	return o.getOverridesForUser(userID).MetricsGenerator.Processor.SpanMetrics.FilterPolicies
 }
 
+// MetricsGeneratorProcessorSpanMetricsStaleDuration controls how long series of the span metrics processor are
+// kept without being updated. If 0 the stale duration of the registry is used.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorSpanMetricsStaleDuration(userID string) time.Duration {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.SpanMetrics.StaleDuration
+}
+
 // MetricsGeneratorProcessorSpanMetricsDimensionMappings controls custom dimension mapping
 func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorSpanMetricsDimensionMappings(userID string) []sharedconfig.DimensionMappings {
 	return o.getOverridesForUser(userID).MetricsGenerator.Processor.SpanMetrics.DimensionMappings
@@ -506,6 +756,32 @@ func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorSpanMetricsTarg
 	return o.getOverridesForUser(userID).MetricsGenerator.Processor.SpanMetrics.TargetInfoExcludedDimensions
 }
 
+// MetricsGeneratorProcessorSpanMetricsTargetInfoMetricName is the name of the target info metric. The default
+// name is used if it is empty.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorSpanMetricsTargetInfoMetricName(userID string) string {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.SpanMetrics.TargetInfoMetricName
+}
+
+// MetricsGeneratorProcessorSpanEventsEventNames controls the names of the span events that are counted.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorSpanEventsEventNames(userID string) []string {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.SpanEvents.EventNames
+}
+
+// MetricsGeneratorProcessorSpanEventsDimensions controls the dimensions that are added to the span events metric.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorSpanEventsDimensions(userID string) []string {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.SpanEvents.Dimensions
+}
+
+// MetricsGeneratorProcessorSpanEventsDimensionMappings controls custom dimension mapping of the span events metric.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorSpanEventsDimensionMappings(userID string) []sharedconfig.DimensionMappings {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.SpanEvents.DimensionMappings
+}
+
+// MetricsGeneratorProcessorLinkMetricsDimensions controls the dimensions that are added to the span links metric.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorLinkMetricsDimensions(userID string) []string {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.LinkMetrics.Dimensions
+}
+
 // BlockRetention is the duration of the block retention for this tenant.
 func (o *runtimeConfigOverridesManager) BlockRetention(userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).Compaction.BlockRetention)
@@ -544,8 +820,12 @@ func (o *runtimeConfigOverridesManager) Collect(ch chan<- prometheus.Metric) {
 	for tenant, limits := range overrides.TenantLimits {
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Ingestion.MaxLocalTracesPerUser), MetricMaxLocalTracesPerUser, tenant)
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Ingestion.MaxGlobalTracesPerUser), MetricMaxGlobalTracesPerUser, tenant)
+		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Ingestion.MaxLiveTracesBytes), MetricMaxLiveTracesBytes, tenant)
+		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Ingestion.MaxSpansPerTrace), MetricMaxSpansPerTrace, tenant)
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Ingestion.RateLimitBytes), MetricIngestionRateLimitBytes, tenant)
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Ingestion.BurstSizeBytes), MetricIngestionBurstSizeBytes, tenant)
+		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Ingestion.RateLimitSpans), MetricIngestionRateLimitSpans, tenant)
+		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Ingestion.BurstSizeSpans), MetricIngestionBurstSizeSpans, tenant)
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Global.MaxBytesPerTrace), MetricMaxBytesPerTrace, tenant)
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Compaction.BlockRetention), MetricBlockRetention, tenant)
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.MetricsGenerator.MaxActiveSeries), MetricMetricsGeneratorMaxActiveSeries, tenant)
@@ -642,3 +642,75 @@ func (m mockValidator) Validate(config *Overrides) error {
 	}
 	return nil
 }
+
+func TestRuntimeConfigOverridesGroups(t *testing.T) {
+	perTenantOverrides := `
+groups:
+  team-a:
+    ingestion:
+      rate_limit_bytes: 1000
+      burst_size_bytes: 2000
+    global:
+      max_bytes_per_trace: 3000
+tenant_groups:
+  user1: team-a
+  user2: team-a
+overrides:
+  user1:
+    ingestion:
+      burst_size_bytes: 4000
+  user3:
+    ingestion:
+      rate_limit_bytes: 5000
+`
+	defaultLimits := Overrides{
+		Ingestion: IngestionOverrides{
+			RateLimitBytes: 10,
+			BurstSizeBytes: 20,
+		},
+		Global: GlobalOverrides{
+			MaxBytesPerTrace: 30,
+		},
+	}
+
+	overrides, cleanup := createAndInitializeRuntimeOverridesManager(t, defaultLimits, []byte(perTenantOverrides))
+	defer cleanup()
+
+	tcs := []struct {
+		tenant                   string
+		expectedRateLimit        float64
+		expectedBurstSize        int
+		expectedMaxBytesPerTrace int
+	}{
+		// the tenant's own overrides replace the values of the group
+		{tenant: "user1", expectedRateLimit: 1000, expectedBurstSize: 4000, expectedMaxBytesPerTrace: 3000},
+		// a tenant only assigned to a group gets the overrides of the group
+		{tenant: "user2", expectedRateLimit: 1000, expectedBurstSize: 2000, expectedMaxBytesPerTrace: 3000},
+		// tenants without a group are not affected
+		{tenant: "user3", expectedRateLimit: 5000, expectedBurstSize: 0, expectedMaxBytesPerTrace: 0},
+		{tenant: "user4", expectedRateLimit: 10, expectedBurstSize: 20, expectedMaxBytesPerTrace: 30},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.tenant, func(t *testing.T) {
+			assert.Equal(t, tc.expectedRateLimit, overrides.IngestionRateLimitBytes(tc.tenant))
+			assert.Equal(t, tc.expectedBurstSize, overrides.IngestionBurstSizeBytes(tc.tenant))
+			assert.Equal(t, tc.expectedMaxBytesPerTrace, overrides.MaxBytesPerTrace(tc.tenant))
+		})
+	}
+
+	assert.ElementsMatch(t, []string{"user1", "user2", "user3"}, overrides.GetTenantIDs())
+}
+
+func TestRuntimeConfigOverridesGroups_unknownGroup(t *testing.T) {
+	loader := loadPerTenantOverrides(&mockValidator{}, ConfigTypeNew, false)
+
+	_, err := loader(bytes.NewReader([]byte(`
+groups:
+  team-a:
+    ingestion:
+      rate_limit_bytes: 1000
+tenant_groups:
+  user1: team-b
+`)))
+	assert.ErrorContains(t, err, "tenant user1 is assigned to unknown overrides group team-b")
+}
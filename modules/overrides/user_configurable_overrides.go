@@ -216,6 +216,36 @@ func (o *userConfigurableOverridesManager) GetTenantIDs() []string {
 	return maps.Keys(o.getAllTenantLimits())
 }
 
+// GetEffectiveOverridesFor returns the runtime overrides of the user with the user-configurable overrides
+// applied on top.
+func (o *userConfigurableOverridesManager) GetEffectiveOverridesFor(userID string) *Overrides {
+	effective := *o.Interface.GetEffectiveOverridesFor(userID)
+
+	effective.Forwarders = o.Forwarders(userID)
+	effective.MetricsGenerator.Processors = o.MetricsGeneratorProcessors(userID)
+	effective.MetricsGenerator.DisableCollection = o.MetricsGeneratorDisableCollection(userID)
+	effective.MetricsGenerator.CollectionInterval = o.MetricsGeneratorCollectionInterval(userID)
+
+	serviceGraphs := &effective.MetricsGenerator.Processor.ServiceGraphs
+	serviceGraphs.Dimensions = o.MetricsGeneratorProcessorServiceGraphsDimensions(userID)
+	serviceGraphs.EnableClientServerPrefix = o.MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix(userID)
+	serviceGraphs.EnableVirtualNodeLabel = o.MetricsGeneratorProcessorServiceGraphsEnableVirtualNodeLabel(userID)
+	serviceGraphs.PeerAttributes = o.MetricsGeneratorProcessorServiceGraphsPeerAttributes(userID)
+	serviceGraphs.HistogramBuckets = o.MetricsGeneratorProcessorServiceGraphsHistogramBuckets(userID)
+
+	spanMetrics := &effective.MetricsGenerator.Processor.SpanMetrics
+	spanMetrics.Dimensions = o.MetricsGeneratorProcessorSpanMetricsDimensions(userID)
+	spanMetrics.EnableTargetInfo = o.MetricsGeneratorProcessorSpanMetricsEnableTargetInfo(userID)
+	spanMetrics.FilterPolicies = o.MetricsGeneratorProcessorSpanMetricsFilterPolicies(userID)
+	spanMetrics.HistogramBuckets = o.MetricsGeneratorProcessorSpanMetricsHistogramBuckets(userID)
+	spanMetrics.TargetInfoExcludedDimensions = o.MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions(userID)
+	spanMetrics.TargetInfoMetricName = o.MetricsGeneratorProcessorSpanMetricsTargetInfoMetricName(userID)
+
+	effective.Storage.DedicatedColumns = o.DedicatedColumns(userID)
+
+	return &effective
+}
+
 func (o *userConfigurableOverridesManager) Forwarders(userID string) []string {
 	if forwarders, ok := o.getTenantLimits(userID).GetForwarders(); ok {
 		return forwarders
@@ -315,6 +345,20 @@ func (o *userConfigurableOverridesManager) MetricsGeneratorProcessorSpanMetricsT
 	return o.Interface.MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions(userID)
 }
 
+func (o *userConfigurableOverridesManager) MetricsGeneratorProcessorSpanMetricsTargetInfoMetricName(userID string) string {
+	if targetInfoMetricName, ok := o.getTenantLimits(userID).GetMetricsGenerator().GetProcessor().GetSpanMetrics().GetTargetInfoMetricName(); ok {
+		return targetInfoMetricName
+	}
+	return o.Interface.MetricsGeneratorProcessorSpanMetricsTargetInfoMetricName(userID)
+}
+
+func (o *userConfigurableOverridesManager) DedicatedColumns(userID string) backend.DedicatedColumns {
+	if dedicatedColumns, ok := o.getTenantLimits(userID).GetStorage().GetDedicatedColumns(); ok {
+		return dedicatedColumns
+	}
+	return o.Interface.DedicatedColumns(userID)
+}
+
 // statusUserConfigurableOverrides used to marshal userconfigurableoverrides.Limits for tenants
 type statusUserConfigurableOverrides struct {
 	TenantLimits tenantLimits `yaml:"user_configurable_overrides" json:"user_configurable_overrides"`
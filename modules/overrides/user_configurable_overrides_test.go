@@ -214,6 +214,28 @@ func TestUserConfigOverridesManager_deletedFromBackend(t *testing.T) {
 	assert.Equal(t, mgr.Forwarders("foo"), []string{"my-forwarder"})
 }
 
+func TestUserConfigOverridesManager_dedicatedColumns(t *testing.T) {
+	runtimeColumns := backend.DedicatedColumns{{Scope: "resource", Name: "host.name", Type: "string"}}
+	defaultLimits := Overrides{
+		Storage: StorageOverrides{DedicatedColumns: runtimeColumns},
+	}
+	_, mgr, cleanup := localUserConfigOverrides(t, defaultLimits, nil)
+	defer cleanup()
+
+	userColumns := backend.DedicatedColumns{{Scope: "span", Name: "http.user_agent", Type: "string"}}
+	limits := &userconfigurableoverrides.Limits{
+		Storage: &userconfigurableoverrides.LimitsStorage{DedicatedColumns: &userColumns},
+	}
+	_, err := mgr.client.Set(context.Background(), tenant1, limits, backend.VersionNew)
+	assert.NoError(t, err)
+
+	assert.NoError(t, mgr.reloadAllTenantLimits(context.Background()))
+
+	assert.Equal(t, userColumns, mgr.DedicatedColumns(tenant1))
+	assert.Equal(t, userColumns, mgr.GetEffectiveOverridesFor(tenant1).Storage.DedicatedColumns)
+	assert.Equal(t, runtimeColumns, mgr.DedicatedColumns(tenant2))
+}
+
 func TestUserConfigOverridesManager_backendUnavailable(t *testing.T) {
 	defaultLimits := Overrides{
 		Forwarders: []string{"my-forwarder"},
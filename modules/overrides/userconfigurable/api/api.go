@@ -207,6 +207,8 @@ func (a *UserConfigOverridesAPI) assertNoConflictingRuntimeOverrides(ctx context
 
 	// clear out processors since we merge this field
 	runtimeLimits.MetricsGenerator.Processors = nil
+	// clear out storage, dedicated columns are applied on top of the runtime overrides by the compactor
+	runtimeLimits.Storage = nil
 
 	emptyLimits := client.Limits{}
 	if reflect.DeepEqual(runtimeLimits, emptyLimits) {
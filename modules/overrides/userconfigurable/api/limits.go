@@ -29,6 +29,7 @@ func limitsFromOverrides(overrides overrides.Interface, userID string) *client.L
 					FilterPolicies:               filterPoliciesPtr(overrides.MetricsGeneratorProcessorSpanMetricsFilterPolicies(userID)),
 					HistogramBuckets:             floatArrPtr(overrides.MetricsGeneratorProcessorSpanMetricsHistogramBuckets(userID)),
 					TargetInfoExcludedDimensions: strArrPtr(overrides.MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions(userID)),
+					TargetInfoMetricName:         strPtr(overrides.MetricsGeneratorProcessorSpanMetricsTargetInfoMetricName(userID)),
 				},
 			},
 		},
@@ -43,6 +44,10 @@ func timePtr(t time.Duration) *client.Duration {
 	return &client.Duration{Duration: t}
 }
 
+func strPtr(s string) *string {
+	return &s
+}
+
 func strArrPtr(s []string) *[]string {
 	return &s
 }
@@ -47,6 +47,7 @@ func Test_limitsFromOverrides(t *testing.T) {
 						},
 						HistogramBuckets:             []float64{1, 2, 5},
 						TargetInfoExcludedDimensions: []string{"no"},
+						TargetInfoMetricName:         "target_info",
 					},
 				},
 			},
@@ -111,7 +112,8 @@ func Test_limitsFromOverrides(t *testing.T) {
         ],
         "target_info_excluded_dimensions": [
           "no"
-        ]
+        ],
+        "target_info_metric_name": "target_info"
       }
     }
   }
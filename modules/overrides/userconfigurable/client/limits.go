@@ -5,12 +5,15 @@ import (
 
 	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
 	"github.com/grafana/tempo/pkg/util/listtomap"
+	"github.com/grafana/tempo/tempodb/backend"
 )
 
 type Limits struct {
 	Forwarders *[]string `yaml:"forwarders,omitempty" json:"forwarders,omitempty"`
 
 	MetricsGenerator LimitsMetricsGenerator `yaml:"metrics_generator,omitempty" json:"metrics_generator,omitempty"`
+
+	Storage *LimitsStorage `yaml:"storage,omitempty" json:"storage,omitempty"`
 }
 
 func (l *Limits) GetForwarders() ([]string, bool) {
@@ -27,6 +30,24 @@ func (l *Limits) GetMetricsGenerator() *LimitsMetricsGenerator {
 	return nil
 }
 
+func (l *Limits) GetStorage() *LimitsStorage {
+	if l != nil {
+		return l.Storage
+	}
+	return nil
+}
+
+type LimitsStorage struct {
+	DedicatedColumns *backend.DedicatedColumns `yaml:"parquet_dedicated_columns,omitempty" json:"parquet_dedicated_columns,omitempty"`
+}
+
+func (l *LimitsStorage) GetDedicatedColumns() (backend.DedicatedColumns, bool) {
+	if l != nil && l.DedicatedColumns != nil {
+		return *l.DedicatedColumns, true
+	}
+	return nil, false
+}
+
 type LimitsMetricsGenerator struct {
 	Processors         listtomap.ListToMap `yaml:"processors,omitempty" json:"processors,omitempty"`
 	DisableCollection  *bool               `yaml:"disable_collection,omitempty" json:"disable_collection,omitempty"`
@@ -132,6 +153,7 @@ type LimitsMetricsGeneratorProcessorSpanMetrics struct {
 	FilterPolicies               *[]filterconfig.FilterPolicy `yaml:"filter_policies,omitempty" json:"filter_policies,omitempty"`
 	HistogramBuckets             *[]float64                   `yaml:"histogram_buckets,omitempty" json:"histogram_buckets,omitempty"`
 	TargetInfoExcludedDimensions *[]string                    `yaml:"target_info_excluded_dimensions,omitempty" json:"target_info_excluded_dimensions,omitempty"`
+	TargetInfoMetricName         *string                      `yaml:"target_info_metric_name,omitempty" json:"target_info_metric_name,omitempty"`
 }
 
 func (l *LimitsMetricsGeneratorProcessorSpanMetrics) GetDimensions() ([]string, bool) {
@@ -168,3 +190,10 @@ func (l *LimitsMetricsGeneratorProcessorSpanMetrics) GetTargetInfoExcludedDimens
 	}
 	return nil, false
 }
+
+func (l *LimitsMetricsGeneratorProcessorSpanMetrics) GetTargetInfoMetricName() (string, bool) {
+	if l != nil && l.TargetInfoMetricName != nil {
+		return *l.TargetInfoMetricName, true
+	}
+	return "", false
+}
@@ -0,0 +1,139 @@
+package querier
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+var metricInferredAttributeScopes = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "querier_inferred_attribute_scopes_total",
+	Help:      "The total number of unscoped attribute conditions of block fetches that were rewritten to a single scope.",
+}, []string{"scope"})
+
+type blocklistReader interface {
+	BlockMetas(tenantID string) []*backend.BlockMeta
+	LastBlocklistPoll() time.Time
+}
+
+// attributeScopeCache records per tenant the scopes each attribute name is observed in by the blocks of the
+// blocklist. It's rebuilt from the block metas after every poll of the blocklist.
+type attributeScopeCache struct {
+	blocklist blocklistReader
+
+	mtx     sync.Mutex
+	tenants map[string]*tenantAttributeScopes
+}
+
+type tenantAttributeScopes struct {
+	polled time.Time
+	// blocks with recorded attribute names
+	blocks map[uuid.UUID]struct{}
+	scopes map[string]observedScopes
+}
+
+type observedScopes uint8
+
+const (
+	observedInResource observedScopes = 1 << iota
+	observedInSpan
+)
+
+func newAttributeScopeCache(blocklist blocklistReader) *attributeScopeCache {
+	return &attributeScopeCache{
+		blocklist: blocklist,
+		tenants:   map[string]*tenantAttributeScopes{},
+	}
+}
+
+// rewrite sets the scope of the unscoped attribute conditions of the fetch of the block to resource or span if
+// the attribute is only observed in that scope. The storage then reads a single column instead of both. Blocks
+// without recorded attribute names are fetched unchanged.
+func (c *attributeScopeCache) rewrite(tenantID string, blockID uuid.UUID, req traceql.FetchSpansRequest) traceql.FetchSpansRequest {
+	t := c.tenant(tenantID)
+	if t == nil {
+		return req
+	}
+	if _, ok := t.blocks[blockID]; !ok {
+		return req
+	}
+
+	req.Conditions = t.rewriteConditions(req.Conditions)
+	req.SecondPassConditions = t.rewriteConditions(req.SecondPassConditions)
+	return req
+}
+
+func (c *attributeScopeCache) tenant(tenantID string) *tenantAttributeScopes {
+	polled := c.blocklist.LastBlocklistPoll()
+	if polled.IsZero() {
+		return nil
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if t, ok := c.tenants[tenantID]; ok && t.polled.Equal(polled) {
+		return t
+	}
+
+	t := &tenantAttributeScopes{
+		polled: polled,
+		blocks: map[uuid.UUID]struct{}{},
+		scopes: map[string]observedScopes{},
+	}
+	for _, m := range c.blocklist.BlockMetas(tenantID) {
+		if m.AttributeScopes == nil {
+			continue
+		}
+		t.blocks[m.BlockID] = struct{}{}
+		for _, n := range m.AttributeScopes.Resource {
+			t.scopes[n] |= observedInResource
+		}
+		for _, n := range m.AttributeScopes.Span {
+			t.scopes[n] |= observedInSpan
+		}
+	}
+	c.tenants[tenantID] = t
+
+	return t
+}
+
+// rewriteConditions returns the conditions with inferred scopes. The given slice is copied before any change.
+func (t *tenantAttributeScopes) rewriteConditions(conds []traceql.Condition) []traceql.Condition {
+	rewritten := conds
+	copied := false
+
+	for i, cond := range conds {
+		a := cond.Attribute
+		if a.Scope != traceql.AttributeScopeNone || a.Intrinsic != traceql.IntrinsicNone || a.Parent {
+			continue
+		}
+
+		var scope traceql.AttributeScope
+		switch t.scopes[a.Name] {
+		case observedInResource:
+			scope = traceql.AttributeScopeResource
+		case observedInSpan:
+			scope = traceql.AttributeScopeSpan
+		default:
+			// observed in both or in none of the scopes
+			continue
+		}
+
+		if !copied {
+			rewritten = append([]traceql.Condition(nil), conds...)
+			copied = true
+		}
+		rewritten[i].Attribute = traceql.NewScopedAttribute(scope, false, a.Name)
+		metricInferredAttributeScopes.WithLabelValues(scope.String()).Inc()
+	}
+
+	return rewritten
+}
@@ -0,0 +1,76 @@
+package querier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+type mockBlocklist struct {
+	metas  []*backend.BlockMeta
+	polled time.Time
+}
+
+func (m *mockBlocklist) BlockMetas(string) []*backend.BlockMeta {
+	return m.metas
+}
+
+func (m *mockBlocklist) LastBlocklistPoll() time.Time {
+	return m.polled
+}
+
+func TestAttributeScopeCacheRewrite(t *testing.T) {
+	recorded := &backend.BlockMeta{BlockID: uuid.New(), AttributeScopes: &backend.AttributeScopes{
+		Resource: []string{"host.name", "service.name"},
+		Span:     []string{"host.name", "user.id"},
+	}}
+	unrecorded := &backend.BlockMeta{BlockID: uuid.New()}
+
+	blocklist := &mockBlocklist{metas: []*backend.BlockMeta{recorded, unrecorded}}
+	c := newAttributeScopeCache(blocklist)
+
+	query := `{ .service.name = "svc" && .user.id = "u1" && .host.name = "h1" && .unknown = 1 && span.user.id != "u2" && name = "root" }`
+	req := traceql.MustExtractFetchSpansRequestWithMetadata(query)
+
+	// the blocklist wasn't polled yet
+	require.Equal(t, req.Conditions, c.rewrite("tenant", recorded.BlockID, req).Conditions)
+
+	blocklist.polled = time.Now()
+
+	// the names of the block weren't recorded
+	require.Equal(t, req.Conditions, c.rewrite("tenant", unrecorded.BlockID, req).Conditions)
+
+	rewritten := c.rewrite("tenant", recorded.BlockID, req)
+	scopes := map[string]traceql.AttributeScope{}
+	for i, cond := range rewritten.Conditions {
+		if cond.Attribute.Intrinsic == traceql.IntrinsicNone && req.Conditions[i].Attribute.Scope == traceql.AttributeScopeNone {
+			scopes[cond.Attribute.Name] = cond.Attribute.Scope
+		}
+	}
+	require.Equal(t, map[string]traceql.AttributeScope{
+		"service.name": traceql.AttributeScopeResource,
+		"user.id":      traceql.AttributeScopeSpan,
+		"host.name":    traceql.AttributeScopeNone, // observed in both scopes
+		"unknown":      traceql.AttributeScopeNone,
+	}, scopes)
+
+	// the conditions of the request are left unchanged
+	for _, cond := range req.Conditions {
+		require.NotEqual(t, traceql.AttributeScopeResource, cond.Attribute.Scope)
+	}
+
+	// the cache is rebuilt after the next poll
+	recorded.AttributeScopes = &backend.AttributeScopes{Resource: []string{"user.id"}}
+	blocklist.polled = blocklist.polled.Add(time.Minute)
+	rewritten = c.rewrite("tenant", recorded.BlockID, req)
+	for i, cond := range rewritten.Conditions {
+		if cond.Attribute.Name == "user.id" && req.Conditions[i].Attribute.Scope == traceql.AttributeScopeNone {
+			require.Equal(t, traceql.AttributeScopeResource, cond.Attribute.Scope)
+		}
+	}
+}
@@ -24,6 +24,11 @@ type Config struct {
 	ShuffleShardingIngestersLookbackPeriod time.Duration `yaml:"shuffle_sharding_ingesters_lookback_period"`
 	QueryRelevantIngesters                 bool          `yaml:"query_relevant_ingesters"`
 	SecondaryIngesterRing                  string        `yaml:"secondary_ingester_ring,omitempty"`
+
+	// WaitForBlocklistPoll keeps the querier from starting and reporting ready until the blocklist was polled
+	// successfully. Without it queriers started by a deploy return empty results for the blocks in the backend
+	// until their first poll succeeded.
+	WaitForBlocklistPoll bool `yaml:"wait_for_blocklist_poll"`
 }
 
 type SearchConfig struct {
@@ -32,6 +37,24 @@ type SearchConfig struct {
 	HedgeRequestsAt   time.Duration `yaml:"external_hedge_requests_at"`
 	HedgeRequestsUpTo int           `yaml:"external_hedge_requests_up_to"`
 
+	// MaxConcurrentRequestsPerEndpoint limits the sub-queries in flight to each external endpoint. 0 is unlimited.
+	MaxConcurrentRequestsPerEndpoint int `yaml:"external_max_concurrent_requests_per_endpoint"`
+
+	// PartialResultsOnTimeout returns the results found so far instead of an error when a block search
+	// exceeds the query timeout. The response is marked as partial and lists the shards that failed.
+	PartialResultsOnTimeout bool `yaml:"partial_results_on_timeout"`
+
+	// TagStatsMaxValuesPerTag limits the distinct values of a tag that are tracked by the tag stats endpoint. Tags
+	// with more values are reported as capped. 0 is unlimited.
+	TagStatsMaxValuesPerTag int `yaml:"tag_stats_max_values_per_tag"`
+	// TagStatsConcurrentBlocks is the number of blocks read concurrently by the tag stats endpoint.
+	TagStatsConcurrentBlocks int `yaml:"tag_stats_concurrent_blocks"`
+
+	// QueryGenerators includes the tags and tag values held by the local-blocks processor of the
+	// metrics-generators in searches of recent tags and tag values. The generators are a best effort addition to
+	// the ingesters and the only source of recent data if no ingesters are configured.
+	QueryGenerators bool `yaml:"query_generators"`
+
 	// backends
 	ExternalBackend string                   `yaml:"external_backend"`
 	CloudRun        *external.CloudRunConfig `yaml:"google_cloud_run"`
@@ -42,7 +65,12 @@ type SearchConfig struct {
 }
 
 type TraceByIDConfig struct {
-	QueryTimeout time.Duration `yaml:"query_timeout"`
+	QueryTimeout time.Duration    `yaml:"query_timeout"`
+	ReadRepair   ReadRepairConfig `yaml:"read_repair"`
+
+	// QueryGenerators includes the spans of the trace held by the local-blocks processor of the metrics-generators
+	// when the ingesters are queried. The generators see spans before they are cut in the ingesters.
+	QueryGenerators bool `yaml:"query_generators"`
 }
 
 type MetricsConfig struct {
@@ -55,11 +83,16 @@ type MetricsConfig struct {
 	// between 0.0 and 1.0.  If a block overlaps the time window by less than this value,
 	// then we skip the columns. A value of 1.0 will always load the columns, and 0.0 never.
 	TimeOverlapCutoff float64 `yaml:"time_overlap_cutoff,omitempty"`
+
+	// FetchCacheMaxItemSize is the maximum size in bytes of the fetch results of a block that are stored in the
+	// traceql-fetch cache. Larger results aren't cached. 0 is unlimited.
+	FetchCacheMaxItemSize int `yaml:"fetch_cache_max_item_size,omitempty"`
 }
 
 // RegisterFlagsAndApplyDefaults register flags.
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	cfg.TraceByID.QueryTimeout = 10 * time.Second
+	cfg.TraceByID.ReadRepair.Timeout = 5 * time.Second
 	cfg.QueryRelevantIngesters = false
 	cfg.ExtraQueryDelay = 0
 	cfg.MaxConcurrentQueries = 20
@@ -67,8 +100,11 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	cfg.Search.HedgeRequestsAt = 8 * time.Second
 	cfg.Search.HedgeRequestsUpTo = 2
 	cfg.Search.QueryTimeout = 30 * time.Second
+	cfg.Search.TagStatsMaxValuesPerTag = 10000
+	cfg.Search.TagStatsConcurrentBlocks = 4
 	cfg.Metrics.ConcurrentBlocks = 2
 	cfg.Metrics.TimeOverlapCutoff = 0.2
+	cfg.Metrics.FetchCacheMaxItemSize = 1 << 20
 	cfg.Worker = worker.Config{
 		MatchMaxConcurrency:   true,
 		MaxConcurrentRequests: cfg.MaxConcurrentQueries,
@@ -84,6 +120,12 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 			},
 		},
 		DNSLookupPeriod: 10 * time.Second,
+		Autoscaling: worker.AutoscalingConfig{
+			MinParallelism:       1,
+			MaxParallelism:       10,
+			Interval:             10 * time.Second,
+			TargetCPUUtilization: 0.8,
+		},
 	}
 	cfg.ShuffleShardingIngestersLookbackPeriod = 1 * time.Hour
 
@@ -43,17 +43,31 @@ type Config struct {
 
 	HedgeRequestsAt   time.Duration
 	HedgeRequestsUpTo int
+
+	// MaxConcurrentRequestsPerEndpoint limits the requests in flight to each endpoint. 0 is unlimited.
+	MaxConcurrentRequestsPerEndpoint int
 }
 
 type Client struct {
 	httpClient    *http.Client
 	endpoints     []string
 	tokenProvider tokenProvider
+
+	// limits holds a semaphore per endpoint if the concurrent requests are limited
+	limits map[string]chan struct{}
 }
 
 type CloudRunConfig struct {
 	Endpoints []string `yaml:"external_endpoints"`
-	NoAuth    bool     // For testing
+	// Audience of the ID tokens. Defaults to each endpoint, which is the audience Cloud Functions
+	// require. Set it if the services use a custom audience.
+	Audience string `yaml:"audience"`
+	// AudienceFromHost uses the scheme and host of each endpoint as default audience. Cloud Run services
+	// accept it, and endpoints on the same service share a cached token.
+	AudienceFromHost bool `yaml:"audience_from_host"`
+	// TokenRefreshBefore is how long before they expire ID tokens are refreshed in the background.
+	TokenRefreshBefore time.Duration `yaml:"token_refresh_before"`
+	NoAuth             bool          // For testing
 }
 
 type HTTPConfig struct {
@@ -77,19 +91,21 @@ func NewClient(cfg *Config) (*Client, error) {
 	case "":
 		// For backwards compatibility, use unauthenticated http as the default.
 		return newClientWithOpts(&commonConfig{
-			endpoints:         cfg.HTTPConfig.Endpoints,
-			hedgeRequestsAt:   cfg.HedgeRequestsAt,
-			hedgeRequestsUpTo: cfg.HedgeRequestsUpTo,
+			endpoints:                        cfg.HTTPConfig.Endpoints,
+			hedgeRequestsAt:                  cfg.HedgeRequestsAt,
+			hedgeRequestsUpTo:                cfg.HedgeRequestsUpTo,
+			maxConcurrentRequestsPerEndpoint: cfg.MaxConcurrentRequestsPerEndpoint,
 		})
 	case "google_cloud_run":
-		provider, err := newGoogleProvider(ctx, cfg.CloudRunConfig.Endpoints, cfg.CloudRunConfig.NoAuth)
+		provider, err := newGoogleProvider(ctx, cfg.CloudRunConfig)
 		if err != nil {
 			return nil, err
 		}
 		return newClientWithOpts(&commonConfig{
-			endpoints:         cfg.CloudRunConfig.Endpoints,
-			hedgeRequestsAt:   cfg.HedgeRequestsAt,
-			hedgeRequestsUpTo: cfg.HedgeRequestsUpTo,
+			endpoints:                        cfg.CloudRunConfig.Endpoints,
+			hedgeRequestsAt:                  cfg.HedgeRequestsAt,
+			hedgeRequestsUpTo:                cfg.HedgeRequestsUpTo,
+			maxConcurrentRequestsPerEndpoint: cfg.MaxConcurrentRequestsPerEndpoint,
 		}, withTokenProvider(provider))
 
 	case "aws_lambda":
@@ -103,9 +119,10 @@ func NewClient(cfg *Config) (*Client, error) {
 }
 
 type commonConfig struct {
-	hedgeRequestsAt   time.Duration
-	hedgeRequestsUpTo int
-	endpoints         []string
+	hedgeRequestsAt                  time.Duration
+	hedgeRequestsUpTo                int
+	endpoints                        []string
+	maxConcurrentRequestsPerEndpoint int
 }
 
 func newClientWithOpts(cfg *commonConfig, opts ...option) (*Client, error) {
@@ -130,6 +147,13 @@ func newClientWithOpts(cfg *commonConfig, opts ...option) (*Client, error) {
 		tokenProvider: &nilTokenProvider{},
 	}
 
+	if cfg.maxConcurrentRequestsPerEndpoint > 0 {
+		c.limits = make(map[string]chan struct{}, len(cfg.endpoints))
+		for _, endpoint := range cfg.endpoints {
+			c.limits[endpoint] = make(chan struct{}, cfg.maxConcurrentRequestsPerEndpoint)
+		}
+	}
+
 	for _, opt := range opts {
 		err := opt(c)
 		if err != nil {
@@ -141,8 +165,13 @@ func newClientWithOpts(cfg *commonConfig, opts ...option) (*Client, error) {
 }
 
 func (s *Client) Search(ctx context.Context, maxBytes int, searchReq *tempopb.SearchBlockRequest) (*tempopb.SearchResponse, error) {
-	endpoint := s.endpoints[rand.Intn(len(s.endpoints))]
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	endpoint, release, err := s.acquireEndpoint(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("external endpoint failed to acquire endpoint: %w", err)
+	}
+	defer release()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return nil, fmt.Errorf("external endpoint failed to make new request: %w", err)
 	}
@@ -185,3 +214,32 @@ func (s *Client) Search(ctx context.Context, maxBytes int, searchReq *tempopb.Se
 
 	return &searchResp, nil
 }
+
+// acquireEndpoint picks a random endpoint. If the concurrent requests are limited it prefers endpoints
+// with free capacity and waits for the random endpoint if all of them are busy. release must be called
+// once the request is done.
+func (s *Client) acquireEndpoint(ctx context.Context) (endpoint string, release func(), err error) {
+	start := rand.Intn(len(s.endpoints))
+	if s.limits == nil {
+		return s.endpoints[start], func() {}, nil
+	}
+
+	for i := range s.endpoints {
+		endpoint := s.endpoints[(start+i)%len(s.endpoints)]
+		sem := s.limits[endpoint]
+		select {
+		case sem <- struct{}{}:
+			return endpoint, func() { <-sem }, nil
+		default:
+		}
+	}
+
+	endpoint = s.endpoints[start]
+	sem := s.limits[endpoint]
+	select {
+	case sem <- struct{}{}:
+		return endpoint, func() { <-sem }, nil
+	case <-ctx.Done():
+		return "", nil, ctx.Err()
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -98,3 +99,61 @@ func (t *stubbedProvider) getToken(_ context.Context, _ string) (*oauth2.Token,
 func getStubbedTokenProvider(dummyToken string) tokenProvider {
 	return &stubbedProvider{dummyToken: dummyToken}
 }
+
+func TestMaxConcurrentRequestsPerEndpoint(t *testing.T) {
+	inFlight := atomic.NewInt32(0)
+	maxInFlight := atomic.NewInt32(0)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := inFlight.Inc()
+		defer inFlight.Dec()
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	c, err := newClientWithOpts(&commonConfig{
+		endpoints:                        []string{srv.URL},
+		maxConcurrentRequestsPerEndpoint: 2,
+	})
+	require.NoError(t, err)
+
+	ctx := user.InjectOrgID(context.Background(), "blerg")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.Search(ctx, 0, &tempopb.SearchBlockRequest{})
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(2), maxInFlight.Load())
+
+	// waiting for a free slot respects the context
+	release := make([]func(), 0, 2)
+	for i := 0; i < 2; i++ {
+		_, r, err := c.acquireEndpoint(ctx)
+		require.NoError(t, err)
+		release = append(release, r)
+	}
+
+	cancelCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = c.Search(cancelCtx, 0, &tempopb.SearchBlockRequest{})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	for _, r := range release {
+		r()
+	}
+}
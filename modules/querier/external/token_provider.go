@@ -3,13 +3,36 @@ package external
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"sync"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.uber.org/multierr"
 	"golang.org/x/oauth2"
 	"google.golang.org/api/idtoken"
 )
 
+const (
+	// DefaultTokenRefreshBefore is how long before they expire ID tokens are refreshed in the background.
+	// Google ID tokens are valid for an hour.
+	DefaultTokenRefreshBefore = 5 * time.Minute
+
+	// tokenExpiryDelta treats tokens as expired a little early, like oauth2.ReuseTokenSource.
+	tokenExpiryDelta  = 10 * time.Second
+	tokenFetchTimeout = 10 * time.Second
+
+	tokenFetchBackground = "background"
+	tokenFetchBlocking   = "blocking"
+)
+
+var metricTokenFetches = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "querier_external_endpoint_token_fetches_total",
+	Help:      "Total number of auth token fetches for the external endpoints by mode and result. Blocking fetches delay the sub-query.",
+}, []string{"mode", "result"})
+
 type tokenProvider interface {
 	// Returns an oauth2 token, leveraging a cache unless the token is expired.
 	// If expired, the token is renewed and added to the cache.
@@ -18,28 +41,64 @@ type tokenProvider interface {
 	getToken(ctx context.Context, endpoint string) (*oauth2.Token, error)
 }
 
-// Caches an oauth2.TokenSource to enable efficient auth on each of our external
-// endpoints.
+// tokenSourceFunc creates a token source for the audience. A new source is created for every fetch because
+// sources like idtoken cache their token until it expires.
+type tokenSourceFunc func(ctx context.Context, audience string) (oauth2.TokenSource, error)
+
+// Caches a token per audience to enable efficient auth on each of our external
+// endpoints. Endpoints with the same audience share the token.
 type cachedTokenProvider struct {
-	tokenSources map[string]oauth2.TokenSource
+	audiences map[string]string // endpoint -> audience
+	tokens    map[string]*refreshingToken
 }
 
+// newTokenProvider fetches the tokens of all audiences. If audience is empty the audience of each
+// endpoint is the endpoint, or its scheme and host if audienceFromHost is set.
 func newTokenProvider(
 	ctx context.Context,
 	endpoints []string,
-	getTokenSource func(ctx context.Context, endpoint string) (oauth2.TokenSource, error),
+	audience string,
+	audienceFromHost bool,
+	refreshBefore time.Duration,
+	getTokenSource tokenSourceFunc,
 ) (*cachedTokenProvider, error) {
-	sources := make(map[string]oauth2.TokenSource, len(endpoints))
+	t := &cachedTokenProvider{
+		audiences: make(map[string]string, len(endpoints)),
+		tokens:    map[string]*refreshingToken{},
+	}
+
+	for _, endpoint := range endpoints {
+		aud := audience
+		if aud == "" && audienceFromHost {
+			var err error
+			aud, err = hostAudience(endpoint)
+			if err != nil {
+				return nil, err
+			}
+		} else if aud == "" {
+			aud = endpoint
+		}
+
+		t.audiences[endpoint] = aud
+		if _, ok := t.tokens[aud]; !ok {
+			t.tokens[aud] = &refreshingToken{
+				audience:      aud,
+				newSource:     getTokenSource,
+				refreshBefore: refreshBefore,
+				now:           time.Now,
+			}
+		}
+	}
 
 	var mtx sync.Mutex
 	var wg sync.WaitGroup
 
 	var tsErr error
-	for _, endpoint := range endpoints {
+	for _, tok := range t.tokens {
 		wg.Add(1)
-		go func(ep string) {
+		go func(tok *refreshingToken) {
 			defer wg.Done()
-			ts, err := getTokenSource(ctx, ep)
+			_, err := tok.get(ctx)
 
 			mtx.Lock()
 			defer mtx.Unlock()
@@ -47,33 +106,128 @@ func newTokenProvider(
 			if err != nil {
 				tsErr = multierr.Combine(tsErr, err)
 			}
-			sources[ep] = oauth2.ReuseTokenSource(nil, ts)
-		}(endpoint)
+		}(tok)
 	}
 	wg.Wait()
 	if tsErr != nil {
-		return nil, fmt.Errorf("failed to create one or more token sources: %w", tsErr)
+		return nil, fmt.Errorf("failed to fetch one or more tokens: %w", tsErr)
 	}
 
-	return &cachedTokenProvider{
-		tokenSources: sources,
-	}, nil
+	return t, nil
 }
 
-func (t *cachedTokenProvider) getToken(_ context.Context, endpoint string) (*oauth2.Token, error) {
-	if src, containsKey := t.tokenSources[endpoint]; containsKey {
-		return src.Token()
+func (t *cachedTokenProvider) getToken(ctx context.Context, endpoint string) (*oauth2.Token, error) {
+	if aud, containsKey := t.audiences[endpoint]; containsKey {
+		return t.tokens[aud].get(ctx)
 	}
 	return nil, fmt.Errorf("endpoint is not configured: %s", endpoint)
 }
 
-func newGoogleProvider(ctx context.Context, endpoints []string, noAuth bool) (tokenProvider, error) {
-	if noAuth {
+// hostAudience returns the scheme and host of the endpoint. Cloud Run services accept ID tokens with
+// their URL as audience, paths are not part of it.
+func hostAudience(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse external endpoint %s: %w", endpoint, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("external endpoint %s must be an absolute URL", endpoint)
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
+
+// refreshingToken caches the token of an audience. Once the token is about to expire a new one is fetched
+// in the background so that sub-queries don't wait for it. Sub-queries only wait if there is no valid token.
+type refreshingToken struct {
+	audience      string
+	newSource     tokenSourceFunc
+	refreshBefore time.Duration
+	now           func() time.Time
+
+	mtx        sync.Mutex
+	token      *oauth2.Token
+	refreshing bool
+}
+
+func (r *refreshingToken) get(ctx context.Context) (*oauth2.Token, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	now := r.now()
+	if r.token != nil && !tokenExpired(r.token, now) {
+		if !r.refreshing && r.refreshBefore > 0 && !r.token.Expiry.IsZero() && now.After(r.token.Expiry.Add(-r.refreshBefore)) {
+			r.refreshing = true
+			go r.refresh()
+		}
+		return r.token, nil
+	}
+
+	// Fetching while holding the lock makes concurrent sub-queries share the fetch
+	tok, err := r.fetch(ctx, tokenFetchBlocking)
+	if err != nil {
+		return nil, err
+	}
+	r.token = tok
+	return tok, nil
+}
+
+func (r *refreshingToken) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), tokenFetchTimeout)
+	defer cancel()
+
+	tok, err := r.fetch(ctx, tokenFetchBackground)
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.refreshing = false
+	// On failure the current token is used until it expires, then it is fetched by the next sub-query
+	if err == nil {
+		r.token = tok
+	}
+}
+
+func (r *refreshingToken) fetch(ctx context.Context, mode string) (*oauth2.Token, error) {
+	tok, err := r.fetchToken(ctx)
+	if err != nil {
+		metricTokenFetches.WithLabelValues(mode, "error").Inc()
+		return nil, fmt.Errorf("failed to fetch token for audience %s: %w", r.audience, err)
+	}
+	metricTokenFetches.WithLabelValues(mode, "success").Inc()
+	return tok, nil
+}
+
+func (r *refreshingToken) fetchToken(ctx context.Context) (*oauth2.Token, error) {
+	ctx, cancel := context.WithTimeout(ctx, tokenFetchTimeout)
+	defer cancel()
+
+	ts, err := r.newSource(ctx, r.audience)
+	if err != nil {
+		return nil, err
+	}
+	return ts.Token()
+}
+
+// tokenExpired returns true if the token expires within tokenExpiryDelta. Tokens without expiry never expire.
+func tokenExpired(tok *oauth2.Token, now time.Time) bool {
+	if tok.Expiry.IsZero() {
+		return false
+	}
+	return !now.Before(tok.Expiry.Add(-tokenExpiryDelta))
+}
+
+func newGoogleProvider(ctx context.Context, cfg *CloudRunConfig) (tokenProvider, error) {
+	if cfg.NoAuth {
 		return &nilTokenProvider{}, nil
 	}
 
-	return newTokenProvider(ctx, endpoints, func(ctx context.Context, endpoint string) (oauth2.TokenSource, error) {
-		return idtoken.NewTokenSource(ctx, endpoint)
+	refreshBefore := cfg.TokenRefreshBefore
+	if refreshBefore == 0 {
+		refreshBefore = DefaultTokenRefreshBefore
+	}
+
+	return newTokenProvider(ctx, cfg.Endpoints, cfg.Audience, cfg.AudienceFromHost, refreshBefore, func(ctx context.Context, audience string) (oauth2.TokenSource, error) {
+		return idtoken.NewTokenSource(ctx, audience)
 	})
 }
 
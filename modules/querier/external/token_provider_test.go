@@ -4,16 +4,20 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
 	"golang.org/x/oauth2"
 )
 
 func TestCachedTokenProvider(t *testing.T) {
 	tests := []struct {
-		endpoints     []string
-		expectedToken *oauth2.Token
-		testEndpoint  string
+		endpoints        []string
+		audience         string
+		audienceFromHost bool
+		expectedToken    *oauth2.Token
+		testEndpoint     string
 	}{
 		{
 			endpoints:     []string{"foo"},
@@ -30,6 +34,19 @@ func TestCachedTokenProvider(t *testing.T) {
 			testEndpoint:  "bar",
 			expectedToken: &oauth2.Token{AccessToken: "bar-token"},
 		},
+		{
+			// the audience doesn't include the path
+			endpoints:        []string{"https://foo/api/search"},
+			audienceFromHost: true,
+			testEndpoint:     "https://foo/api/search",
+			expectedToken:    &oauth2.Token{AccessToken: "https://foo-token"},
+		},
+		{
+			endpoints:     []string{"https://foo", "https://bar"},
+			audience:      "custom",
+			testEndpoint:  "https://bar",
+			expectedToken: &oauth2.Token{AccessToken: "custom-token"},
+		},
 	}
 
 	for _, tc := range tests {
@@ -37,13 +54,67 @@ func TestCachedTokenProvider(t *testing.T) {
 			return getStubbedTokenSource(fmt.Sprintf("%s-token", endpoint)), nil
 		}
 
-		tp, err := newTokenProvider(context.Background(), tc.endpoints, getTokenSource)
+		tp, err := newTokenProvider(context.Background(), tc.endpoints, tc.audience, tc.audienceFromHost, 0, getTokenSource)
 		require.NoError(t, err)
 
 		actual, err := tp.getToken(context.Background(), tc.testEndpoint)
 		require.NoError(t, err)
 		require.Equal(t, tc.expectedToken, actual)
 	}
+
+	_, err := newTokenProvider(context.Background(), []string{"foo"}, "", true, 0, nil)
+	require.Error(t, err)
+}
+
+func TestCachedTokenProviderRefresh(t *testing.T) {
+	now := time.Now()
+	fetches := atomic.NewInt32(0)
+	getTokenSource := func(_ context.Context, _ string) (oauth2.TokenSource, error) {
+		// every token is valid an hour longer than the previous one
+		n := fetches.Inc()
+		return oauth2.StaticTokenSource(&oauth2.Token{
+			AccessToken: fmt.Sprintf("token-%d", n),
+			Expiry:      now.Add(time.Duration(n) * time.Hour),
+		}), nil
+	}
+
+	tp, err := newTokenProvider(context.Background(), []string{"https://foo", "https://foo/bar"}, "", true, 5*time.Minute, getTokenSource)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), fetches.Load()) // endpoints share the audience
+
+	tok := tp.tokens["https://foo"]
+	clock := now
+	tok.mtx.Lock()
+	tok.now = func() time.Time { return clock }
+	tok.mtx.Unlock()
+
+	// the token is cached
+	actual, err := tp.getToken(context.Background(), "https://foo/bar")
+	require.NoError(t, err)
+	require.Equal(t, "token-1", actual.AccessToken)
+	require.Equal(t, int32(1), fetches.Load())
+
+	// close to expiry the cached token is returned and a new one fetched in the background
+	tok.mtx.Lock()
+	clock = now.Add(56 * time.Minute)
+	tok.mtx.Unlock()
+
+	actual, err = tp.getToken(context.Background(), "https://foo")
+	require.NoError(t, err)
+	require.Equal(t, "token-1", actual.AccessToken)
+	require.Eventually(t, func() bool {
+		actual, err := tp.getToken(context.Background(), "https://foo")
+		return err == nil && actual.AccessToken == "token-2"
+	}, time.Second, 10*time.Millisecond)
+
+	// an expired token is fetched before it's returned
+	tok.mtx.Lock()
+	clock = now.Add(2 * time.Hour)
+	tok.mtx.Unlock()
+
+	actual, err = tp.getToken(context.Background(), "https://foo")
+	require.NoError(t, err)
+	require.Equal(t, "token-3", actual.AccessToken)
 }
 
 type stubbedTokenSource struct {
@@ -0,0 +1,295 @@
+package querier
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/go-kit/log/level"
+
+	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/util/log"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+const fetchCacheVersion byte = 1
+
+// fetchCache caches the spansets returned by the fetch of a metrics query per block. Blocks in the backend
+// never change so repeated queries, like the refreshes of a dashboard, replay the spansets instead of
+// reading and decoding the parquet files again.
+type fetchCache struct {
+	c           cache.Cache
+	maxItemSize int
+}
+
+func newFetchCache(cacheProvider cache.Provider, maxItemSize int) *fetchCache {
+	var c cache.Cache
+	if cacheProvider != nil {
+		c = cacheProvider.CacheFor(cache.RoleTraceQLFetch)
+	}
+
+	level.Info(log.Logger).Log("msg", "init traceql fetch cache", "enabled", c != nil)
+
+	if c == nil {
+		return nil
+	}
+
+	return &fetchCache{
+		c:           c,
+		maxItemSize: maxItemSize,
+	}
+}
+
+// fetcher wraps the fetcher of the pages of a block. The results are only cached if they only contain the
+// attributes requested in the conditions, so requests that select all attributes are passed through.
+func (c *fetchCache) fetcher(meta *backend.BlockMeta, startPage, totalPages int, query string, next traceql.SpansetFetcher) traceql.SpansetFetcher {
+	if c == nil {
+		return next
+	}
+
+	return traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		if req.SecondPassSelectAll {
+			return next.Fetch(ctx, req)
+		}
+
+		key := fetchCacheKey(meta, startPage, totalPages, query, req)
+		attrs := fetchAttributes(req)
+
+		found, bufs, _ := c.c.Fetch(ctx, []string{key})
+		if len(found) == 1 && len(bufs[0]) > 0 && bufs[0][0] == fetchCacheVersion {
+			return traceql.FetchSpansResponse{
+				Results: &cachedSpansetIterator{
+					dec:   gob.NewDecoder(bytes.NewReader(bufs[0][1:])),
+					attrs: attrs,
+				},
+				Bytes: func() uint64 { return 0 },
+			}, nil
+		}
+
+		resp, err := next.Fetch(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+
+		it := &recordingSpansetIterator{
+			next:  resp.Results,
+			cache: c,
+			key:   key,
+			attrs: attrs,
+		}
+		it.buf.WriteByte(fetchCacheVersion)
+		it.enc = gob.NewEncoder(&it.buf)
+		resp.Results = it
+
+		return resp, nil
+	})
+}
+
+// fetchCacheKey identifies the results of a fetch. The query is part of the key because the second pass
+// evaluates it and different queries can result in the same conditions.
+func fetchCacheKey(meta *backend.BlockMeta, startPage, totalPages int, query string, req traceql.FetchSpansRequest) string {
+	req.SecondPass = nil
+
+	h := xxhash.New()
+	_, _ = fmt.Fprintf(h, "%s\x00%d\x00%d\x00%s\x00%+v", meta.TenantID, startPage, totalPages, query, req)
+
+	return "traceql-fetch:" + meta.BlockID.String() + ":" + strconv.FormatUint(h.Sum64(), 16)
+}
+
+// fetchAttributes returns the attributes that are recorded for each span. These are all the attributes the
+// engine can read from the spans returned by the fetch.
+func fetchAttributes(req traceql.FetchSpansRequest) []traceql.Attribute {
+	var attrs []traceql.Attribute
+	seen := map[traceql.Attribute]struct{}{}
+
+	for _, conds := range [][]traceql.Condition{req.Conditions, req.SecondPassConditions} {
+		for _, c := range conds {
+			if _, ok := seen[c.Attribute]; ok {
+				continue
+			}
+			seen[c.Attribute] = struct{}{}
+			attrs = append(attrs, c.Attribute)
+		}
+	}
+
+	return attrs
+}
+
+type cachedSpanset struct {
+	TraceID []byte
+	Spans   []cachedSpanData
+}
+
+type cachedSpanData struct {
+	ID                 []byte
+	StartTimeUnixNanos uint64
+	DurationNanos      uint64
+	// Attributes holds the value of each attribute returned by fetchAttributes, TypeNil if the span doesn't
+	// have it.
+	Attributes []traceql.Static
+}
+
+// recordingSpansetIterator encodes the spansets as they are returned and stores them in the cache once all
+// of them were read. Spansets are encoded immediately because the storage reuses their buffers after they
+// are released.
+type recordingSpansetIterator struct {
+	next  traceql.SpansetIterator
+	cache *fetchCache
+	key   string
+	attrs []traceql.Attribute
+
+	buf      bytes.Buffer
+	enc      *gob.Encoder
+	tooLarge bool
+}
+
+var _ traceql.SpansetIterator = (*recordingSpansetIterator)(nil)
+
+func (i *recordingSpansetIterator) Next(ctx context.Context) (*traceql.Spanset, error) {
+	ss, err := i.next.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if ss == nil {
+		if !i.tooLarge {
+			i.cache.c.Store(ctx, []string{i.key}, [][]byte{i.buf.Bytes()})
+		}
+		return nil, nil
+	}
+
+	if !i.tooLarge {
+		i.record(ss)
+	}
+
+	return ss, nil
+}
+
+func (i *recordingSpansetIterator) record(ss *traceql.Spanset) {
+	cached := cachedSpanset{
+		TraceID: ss.TraceID,
+		Spans:   make([]cachedSpanData, 0, len(ss.Spans)),
+	}
+
+	for _, s := range ss.Spans {
+		data := cachedSpanData{
+			ID:                 s.ID(),
+			StartTimeUnixNanos: s.StartTimeUnixNanos(),
+			DurationNanos:      s.DurationNanos(),
+			Attributes:         make([]traceql.Static, len(i.attrs)),
+		}
+		for j, a := range i.attrs {
+			data.Attributes[j], _ = s.AttributeFor(a)
+		}
+		cached.Spans = append(cached.Spans, data)
+	}
+
+	err := i.enc.Encode(cached)
+	if err != nil || (i.cache.maxItemSize > 0 && i.buf.Len() > i.cache.maxItemSize) {
+		i.tooLarge = true
+		i.buf = bytes.Buffer{}
+	}
+}
+
+func (i *recordingSpansetIterator) Close() {
+	i.next.Close()
+}
+
+// cachedSpansetIterator replays the spansets stored by a recordingSpansetIterator.
+type cachedSpansetIterator struct {
+	dec   *gob.Decoder
+	attrs []traceql.Attribute
+}
+
+var _ traceql.SpansetIterator = (*cachedSpansetIterator)(nil)
+
+func (i *cachedSpansetIterator) Next(context.Context) (*traceql.Spanset, error) {
+	var cached cachedSpanset
+	err := i.dec.Decode(&cached)
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error decoding cached spanset: %w", err)
+	}
+
+	ss := &traceql.Spanset{
+		TraceID: cached.TraceID,
+		Spans:   make([]traceql.Span, 0, len(cached.Spans)),
+	}
+	for j := range cached.Spans {
+		ss.Spans = append(ss.Spans, &cachedSpan{
+			data:  &cached.Spans[j],
+			attrs: i.attrs,
+		})
+	}
+
+	return ss, nil
+}
+
+func (i *cachedSpansetIterator) Close() {}
+
+// cachedSpan is a span replayed from the cache. Its spansets are final results, so the structural
+// operations are never evaluated on it.
+type cachedSpan struct {
+	data  *cachedSpanData
+	attrs []traceql.Attribute
+}
+
+var _ traceql.Span = (*cachedSpan)(nil)
+
+func (s *cachedSpan) AttributeFor(a traceql.Attribute) (traceql.Static, bool) {
+	for i, attr := range s.attrs {
+		if attr == a && i < len(s.data.Attributes) {
+			v := s.data.Attributes[i]
+			return v, v.Type != traceql.TypeNil
+		}
+	}
+	return traceql.Static{}, false
+}
+
+func (s *cachedSpan) AllAttributes() map[traceql.Attribute]traceql.Static {
+	atts := make(map[traceql.Attribute]traceql.Static, len(s.attrs))
+	s.AllAttributesFunc(func(a traceql.Attribute, v traceql.Static) {
+		atts[a] = v
+	})
+	return atts
+}
+
+func (s *cachedSpan) AllAttributesFunc(cb func(traceql.Attribute, traceql.Static)) {
+	for i, a := range s.attrs {
+		if i < len(s.data.Attributes) && s.data.Attributes[i].Type != traceql.TypeNil {
+			cb(a, s.data.Attributes[i])
+		}
+	}
+}
+
+func (s *cachedSpan) ID() []byte {
+	return s.data.ID
+}
+
+func (s *cachedSpan) StartTimeUnixNanos() uint64 {
+	return s.data.StartTimeUnixNanos
+}
+
+func (s *cachedSpan) DurationNanos() uint64 {
+	return s.data.DurationNanos
+}
+
+func (s *cachedSpan) SiblingOf([]traceql.Span, []traceql.Span, bool, bool, []traceql.Span) []traceql.Span {
+	return nil
+}
+
+func (s *cachedSpan) DescendantOf([]traceql.Span, []traceql.Span, bool, bool, bool, []traceql.Span) []traceql.Span {
+	return nil
+}
+
+func (s *cachedSpan) ChildOf([]traceql.Span, []traceql.Span, bool, bool, bool, []traceql.Span) []traceql.Span {
+	return nil
+}
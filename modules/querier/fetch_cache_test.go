@@ -0,0 +1,146 @@
+package querier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestFetchCache(t *testing.T) {
+	now := time.Now().Truncate(time.Minute)
+	req := &tempopb.QueryRangeRequest{
+		Query: `{ span.foo = "bar" } | rate() by (resource.service.name)`,
+		Start: uint64(now.Add(-time.Hour).UnixNano()),
+		End:   uint64(now.UnixNano()),
+		Step:  uint64(time.Minute),
+	}
+	meta := &backend.BlockMeta{TenantID: "test", BlockID: uuid.New()}
+
+	spansets := []*traceql.Spanset{
+		{
+			TraceID: []byte{1},
+			Spans: []traceql.Span{
+				newTestSpan(now.Add(-30*time.Minute), "a"),
+				newTestSpan(now.Add(-20*time.Minute), "b"),
+			},
+		},
+		{
+			TraceID: []byte{2},
+			Spans: []traceql.Span{
+				newTestSpan(now.Add(-10*time.Minute), "a"),
+				newTestSpan(now.Add(-2*time.Hour), "a"), // outside of the range
+			},
+		},
+	}
+
+	tcs := []struct {
+		name          string
+		maxItemSize   int
+		expectedCalls int
+	}{
+		{name: "cached", expectedCalls: 1},
+		{name: "too large", maxItemSize: 10, expectedCalls: 2},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newFetchCache(test.NewMockProvider(), tc.maxItemSize)
+			calls := 0
+			next := traceql.NewSpansetFetcherWrapper(func(context.Context, traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+				calls++
+				return traceql.FetchSpansResponse{
+					Results: &sliceSpansetIterator{spansets: spansets},
+					Bytes:   func() uint64 { return 100 },
+				}, nil
+			})
+
+			var results []traceql.SeriesSet
+			var bytes []uint64
+			for i := 0; i < 2; i++ {
+				eval, err := traceql.NewEngine().CompileMetricsQueryRange(req, false, 0, false)
+				require.NoError(t, err)
+
+				err = eval.Do(context.Background(), c.fetcher(meta, 0, 0, req.Query, next), 0, 0)
+				require.NoError(t, err)
+
+				b, spans, _ := eval.Metrics()
+				require.Equal(t, uint64(3), spans)
+				results = append(results, eval.Results())
+				bytes = append(bytes, b)
+			}
+
+			require.Equal(t, tc.expectedCalls, calls)
+			require.Equal(t, results[0], results[1])
+			require.Len(t, results[0], 2)
+			require.Equal(t, uint64(100), bytes[0])
+			if tc.expectedCalls == 1 {
+				require.Equal(t, uint64(0), bytes[1])
+			}
+		})
+	}
+}
+
+func TestFetchCacheKey(t *testing.T) {
+	meta := &backend.BlockMeta{TenantID: "test", BlockID: uuid.New()}
+	req := traceql.FetchSpansRequest{
+		Conditions: []traceql.Condition{{Attribute: traceql.NewScopedAttribute(traceql.AttributeScopeSpan, false, "foo")}},
+	}
+	key := fetchCacheKey(meta, 0, 0, "{ span.foo }", req)
+
+	req.SecondPass = func(s *traceql.Spanset) ([]*traceql.Spanset, error) { return []*traceql.Spanset{s}, nil }
+	require.Equal(t, key, fetchCacheKey(meta, 0, 0, "{ span.foo }", req))
+
+	require.NotEqual(t, key, fetchCacheKey(meta, 1, 1, "{ span.foo }", req))
+	require.NotEqual(t, key, fetchCacheKey(meta, 0, 0, "{ span.foo } >> { span.foo }", req))
+	require.NotEqual(t, key, fetchCacheKey(&backend.BlockMeta{TenantID: "other", BlockID: meta.BlockID}, 0, 0, "{ span.foo }", req))
+
+	req.StartTimeUnixNanos = 1
+	require.NotEqual(t, key, fetchCacheKey(meta, 0, 0, "{ span.foo }", req))
+}
+
+func TestFetchCacheDisabled(t *testing.T) {
+	require.Nil(t, newFetchCache(nil, 0))
+
+	var c *fetchCache
+	next := traceql.NewSpansetFetcherWrapper(nil)
+	require.Equal(t, next, c.fetcher(&backend.BlockMeta{}, 0, 0, "", next))
+}
+
+func newTestSpan(start time.Time, service string) traceql.Span {
+	attrs := []traceql.Attribute{
+		traceql.NewScopedAttribute(traceql.AttributeScopeSpan, false, "foo"),
+		traceql.NewScopedAttribute(traceql.AttributeScopeResource, false, "service.name"),
+	}
+	return &cachedSpan{
+		data: &cachedSpanData{
+			ID:                 []byte{1, 2, 3},
+			StartTimeUnixNanos: uint64(start.UnixNano()),
+			DurationNanos:      uint64(time.Second),
+			Attributes:         []traceql.Static{traceql.NewStaticString("bar"), traceql.NewStaticString(service)},
+		},
+		attrs: attrs,
+	}
+}
+
+type sliceSpansetIterator struct {
+	spansets []*traceql.Spanset
+}
+
+func (i *sliceSpansetIterator) Next(context.Context) (*traceql.Spanset, error) {
+	if len(i.spansets) == 0 {
+		return nil, nil
+	}
+	ss := i.spansets[0]
+	i.spansets = i.spansets[1:]
+	return ss, nil
+}
+
+func (i *sliceSpansetIterator) Close() {}
@@ -2,8 +2,10 @@ package querier
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -11,10 +13,13 @@ import (
 	"github.com/golang/protobuf/proto"  //nolint:all //ProtoReflect
 	"github.com/opentracing/opentracing-go"
 	ot_log "github.com/opentracing/opentracing-go/log"
+	"github.com/parquet-go/parquet-go"
 
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
 const (
@@ -57,14 +62,21 @@ func (q *Querier) TraceByIDHandler(w http.ResponseWriter, r *http.Request) {
 		ot_log.String("timeStart", fmt.Sprint(timeStart)),
 		ot_log.String("timeEnd", fmt.Sprint(timeEnd)))
 
+	spanFilter, err := api.ParseSpanFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	resp, err := q.FindTraceByID(ctx, &tempopb.TraceByIDRequest{
 		TraceID:    byteID,
 		BlockStart: blockStart,
 		BlockEnd:   blockEnd,
 		QueryMode:  queryMode,
+		SpanFilter: spanFilter,
 	}, timeStart, timeEnd)
 	if err != nil {
-		handleError(w, err)
+		handleError(w, r, err)
 		return
 	}
 
@@ -125,7 +137,7 @@ func (q *Querier) SearchHandler(w http.ResponseWriter, r *http.Request) {
 
 		resp, err = q.SearchRecent(ctx, req)
 		if err != nil {
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 	} else {
@@ -139,7 +151,7 @@ func (q *Querier) SearchHandler(w http.ResponseWriter, r *http.Request) {
 
 		resp, err = q.SearchBlock(ctx, req)
 		if err != nil {
-			handleError(w, err)
+			handleBlockError(w, r, err, req.BlockID)
 			return
 		}
 	}
@@ -171,7 +183,7 @@ func (q *Querier) SearchTagsHandler(w http.ResponseWriter, r *http.Request) {
 		}
 		resp, err := q.SearchTags(ctx, req)
 		if err != nil {
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
@@ -189,7 +201,7 @@ func (q *Querier) SearchTagsHandler(w http.ResponseWriter, r *http.Request) {
 		}
 		resp, err := q.SearchTagsBlocks(ctx, req)
 		if err != nil {
-			handleError(w, err)
+			handleBlockError(w, r, err, req.BlockID)
 			return
 		}
 		marshaller := &jsonpb.Marshaler{}
@@ -221,7 +233,7 @@ func (q *Querier) SearchTagsV2Handler(w http.ResponseWriter, r *http.Request) {
 
 		resp, err := q.SearchTagsV2(ctx, req)
 		if err != nil {
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
@@ -239,7 +251,7 @@ func (q *Querier) SearchTagsV2Handler(w http.ResponseWriter, r *http.Request) {
 		}
 		resp, err := q.SearchTagsBlocksV2(ctx, req)
 		if err != nil {
-			handleError(w, err)
+			handleBlockError(w, r, err, req.BlockID)
 			return
 		}
 		marshaller := &jsonpb.Marshaler{}
@@ -271,7 +283,7 @@ func (q *Querier) SearchTagValuesHandler(w http.ResponseWriter, r *http.Request)
 
 		resp, err := q.SearchTagValues(ctx, req)
 		if err != nil {
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 		marshaller := &jsonpb.Marshaler{}
@@ -288,7 +300,7 @@ func (q *Querier) SearchTagValuesHandler(w http.ResponseWriter, r *http.Request)
 		}
 		resp, err := q.SearchTagValuesBlocks(ctx, req)
 		if err != nil {
-			handleError(w, err)
+			handleBlockError(w, r, err, req.BlockID)
 			return
 		}
 		marshaller := &jsonpb.Marshaler{}
@@ -331,16 +343,56 @@ func (q *Querier) SearchTagValuesV2Handler(w http.ResponseWriter, r *http.Reques
 			return
 		}
 		resp, err = q.SearchTagValuesBlocksV2(ctx, req)
+		if err != nil {
+			handleBlockError(w, r, err, req.BlockID)
+			return
+		}
 	}
 
 	if err != nil {
-		handleError(w, err)
+		handleError(w, r, err)
 		return
 	}
 
 	writeFormattedContentForRequest(w, r, resp)
 }
 
+func (q *Querier) TagStatsHandler(w http.ResponseWriter, r *http.Request) {
+	// Enforce the query timeout while querying backends
+	ctx, cancel := context.WithDeadline(r.Context(), time.Now().Add(q.cfg.Search.QueryTimeout))
+	defer cancel()
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.TagStatsHandler")
+	defer span.Finish()
+
+	req, err := api.ParseSearchTagsRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Scope == api.ParamScopeIntrinsic {
+		http.Error(w, "tag stats are not supported for intrinsics", http.StatusBadRequest)
+		return
+	}
+	if req.Start == 0 || req.End == 0 || req.End < req.Start {
+		http.Error(w, "a valid start and end are required", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := q.TagStats(ctx, req)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
 func (q *Querier) SpanMetricsSummaryHandler(w http.ResponseWriter, r *http.Request) {
 	// Enforce the query timeout while querying backends
 	ctx, cancel := context.WithDeadline(r.Context(), time.Now().Add(q.cfg.Search.QueryTimeout))
@@ -357,7 +409,7 @@ func (q *Querier) SpanMetricsSummaryHandler(w http.ResponseWriter, r *http.Reque
 
 	resp, err := q.SpanMetricsSummary(ctx, req)
 	if err != nil {
-		handleError(w, err)
+		handleError(w, r, err)
 		return
 	}
 
@@ -373,6 +425,7 @@ func (q *Querier) SpanMetricsSummaryHandler(w http.ResponseWriter, r *http.Reque
 func (q *Querier) QueryRangeHandler(w http.ResponseWriter, r *http.Request) {
 	var (
 		err  error
+		req  *tempopb.QueryRangeRequest
 		resp *tempopb.QueryRangeResponse
 	)
 
@@ -405,14 +458,18 @@ func (q *Querier) QueryRangeHandler(w http.ResponseWriter, r *http.Request) {
 		errHandler(ctx, span, err)
 
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			queryErr := queryError(err)
+			if req != nil && req.BlockID != "" {
+				queryErr.WithBlock(req.BlockID)
+			}
+			writeQueryError(w, r, queryErr)
 			return
 		}
 
 		writeFormattedContentForRequest(w, r, resp)
 	}()
 
-	req, err := api.ParseQueryRangeRequest(r)
+	req, err = api.ParseQueryRangeRequest(r)
 	if err != nil {
 		errHandler(ctx, span, err)
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -437,7 +494,7 @@ func (q *Querier) QueryRangeHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func handleError(w http.ResponseWriter, err error) {
+func handleError(w http.ResponseWriter, r *http.Request, err error) {
 	if err == nil {
 		return
 	}
@@ -448,13 +505,59 @@ func handleError(w http.ResponseWriter, err error) {
 		return
 	}
 
-	// todo: better understand all errors returned from queriers and categorize more as 4XX
-	if errors.Is(err, trace.ErrTraceTooLarge) {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	writeQueryError(w, r, queryError(err))
+}
+
+// handleBlockError is handleError for requests searching a single block. The block is recorded in the error.
+func handleBlockError(w http.ResponseWriter, r *http.Request, err error, blockID string) {
+	if err == nil {
 		return
 	}
+	handleError(w, r, queryError(err).WithBlock(blockID))
+}
+
+// writeQueryError writes the error with a 500 so it is retried by the frontend, unless it is a limit error. It is
+// written as json if the request accepts json errors.
+func writeQueryError(w http.ResponseWriter, r *http.Request, queryErr *api.QueryError) {
+	status := http.StatusInternalServerError
+	if queryErr.Code == api.ErrorCodeLimitExceeded {
+		status = http.StatusBadRequest
+	}
+	api.WriteQueryErrorForRequest(w, r, status, queryErr)
+}
+
+// queryError classifies the errors of the querier. Errors that aren't classified are internal errors.
+func queryError(err error) *api.QueryError {
+	var (
+		queryErr *api.QueryError
+		limitErr *traceql.MetricsLimitError
+	)
+	switch {
+	case errors.As(err, &queryErr):
+		return queryErr
+	case errors.Is(err, trace.ErrTraceTooLarge):
+		return api.NewQueryError(api.ErrorCodeLimitExceeded, err).WithLimit("max_bytes_per_trace")
+	case errors.As(err, &limitErr):
+		limit := "max_metrics_series"
+		if limitErr.Limit == traceql.MetricsLimitResponseBytes {
+			limit = "max_metrics_response_bytes"
+		}
+		return api.NewQueryError(api.ErrorCodeLimitExceeded, err).WithLimit(limit)
+	case isCorrupted(err):
+		return api.NewQueryError(api.ErrorCodeDataCorrupted, err)
+	}
+	return api.AsQueryError(err)
+}
 
-	http.Error(w, err.Error(), http.StatusInternalServerError)
+// isCorrupted returns true if err is caused by the contents of a block.
+func isCorrupted(err error) bool {
+	return errors.Is(err, common.ErrCorrupted) ||
+		errors.Is(err, parquet.ErrCorrupted) ||
+		errors.Is(err, parquet.ErrMissingPageHeader) ||
+		errors.Is(err, parquet.ErrMissingRootColumn) ||
+		errors.Is(err, parquet.ErrUnexpectedDefinitionLevels) ||
+		errors.Is(err, parquet.ErrUnexpectedRepetitionLevels) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
 }
 
 func writeFormattedContentForRequest(w http.ResponseWriter, req *http.Request, m proto.Message) {
@@ -30,6 +30,7 @@ import (
 	"github.com/grafana/tempo/modules/querier/worker"
 	"github.com/grafana/tempo/modules/storage"
 	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/cache"
 	"github.com/grafana/tempo/pkg/collector"
 	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/search"
@@ -43,6 +44,9 @@ import (
 	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
+// blocklistPollCheckInterval is how often a querier waiting for the first blocklist poll checks for it
+const blocklistPollCheckInterval = time.Second
+
 var (
 	metricIngesterClients = promauto.NewGauge(prometheus.GaugeOpts{
 		Namespace: "tempo",
@@ -54,6 +58,11 @@ var (
 		Name:      "querier_metrics_generator_clients",
 		Help:      "The current number of generator clients.",
 	})
+	metricPartialSearchBlocks = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "querier_search_block_partial_results_total",
+		Help:      "The total number of block searches that timed out and returned partial results.",
+	})
 )
 
 // Querier handlers queries.
@@ -72,7 +81,9 @@ type Querier struct {
 	store  storage.Store
 	limits overrides.Interface
 
-	externalClient *external.Client
+	externalClient  *external.Client
+	fetchCache      *fetchCache
+	attributeScopes *attributeScopeCache
 
 	searchPreferSelf *semaphore.Weighted
 
@@ -83,6 +94,8 @@ type Querier struct {
 type responseFromIngesters struct {
 	addr     string
 	response interface{}
+	// index of the ingester ring
+	ring int
 }
 
 type responseFromGenerators struct {
@@ -99,6 +112,7 @@ func New(
 	generatorRing ring.ReadRing,
 	store storage.Store,
 	limits overrides.Interface,
+	cacheProvider cache.Provider,
 ) (*Querier, error) {
 	var ingesterClientFactory ring_client.PoolAddrFunc = func(addr string) (ring_client.PoolClient, error) {
 		return ingester_client.New(addr, ingesterClientConfig)
@@ -115,6 +129,8 @@ func New(
 		HedgeRequestsAt:   cfg.Search.HedgeRequestsAt,
 		HedgeRequestsUpTo: cfg.Search.HedgeRequestsUpTo,
 
+		MaxConcurrentRequestsPerEndpoint: cfg.Search.MaxConcurrentRequestsPerEndpoint,
+
 		HTTPConfig: &external.HTTPConfig{
 			Endpoints: cfg.Search.ExternalEndpoints,
 		},
@@ -150,6 +166,10 @@ func New(
 		limits:           limits,
 		searchPreferSelf: semaphore.NewWeighted(int64(cfg.Search.PreferSelf)),
 		externalClient:   externalClient,
+		fetchCache:       newFetchCache(cacheProvider, cfg.Metrics.FetchCacheMaxItemSize),
+	}
+	if store != nil {
+		q.attributeScopes = newAttributeScopeCache(store)
 	}
 
 	q.Service = services.NewBasicService(q.starting, q.running, q.stopping)
@@ -189,6 +209,12 @@ func (q *Querier) RegisterSubservices(s ...services.Service) error {
 }
 
 func (q *Querier) starting(ctx context.Context) error {
+	if q.cfg.WaitForBlocklistPoll {
+		if err := q.waitForBlocklistPoll(ctx); err != nil {
+			return err
+		}
+	}
+
 	if q.subservices != nil {
 		err := services.StartManagerAndAwaitHealthy(ctx, q.subservices)
 		if err != nil {
@@ -199,6 +225,31 @@ func (q *Querier) starting(ctx context.Context) error {
 	return nil
 }
 
+// waitForBlocklistPoll blocks until the blocklist was polled successfully. The frontend worker isn't started before
+// that, so no jobs are pulled that would miss the blocks in the backend.
+func (q *Querier) waitForBlocklistPoll(ctx context.Context) error {
+	if !q.store.LastBlocklistPoll().IsZero() {
+		return nil
+	}
+
+	level.Info(log.Logger).Log("msg", "waiting for the first successful blocklist poll")
+
+	ticker := time.NewTicker(blocklistPollCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if !q.store.LastBlocklistPoll().IsZero() {
+				level.Info(log.Logger).Log("msg", "blocklist polled, starting querier")
+				return nil
+			}
+		}
+	}
+}
+
 func (q *Querier) running(ctx context.Context) error {
 	if q.subservices != nil {
 		select {
@@ -236,6 +287,15 @@ func (q *Querier) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDReque
 
 	span.SetTag("queryMode", req.QueryMode)
 
+	var spanFilter *traceql.TraceFilter
+	if req.SpanFilter != "" {
+		span.SetTag("spanFilter", req.SpanFilter)
+		spanFilter, err = traceql.NewTraceFilter(req.SpanFilter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid span filter in Querier.FindTraceByID: %w", err)
+		}
+	}
+
 	maxBytes := q.limits.MaxBytesPerTrace(userID)
 	combiner := trace.NewCombiner(maxBytes)
 
@@ -258,6 +318,17 @@ func (q *Querier) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDReque
 			return nil, fmt.Errorf("error querying ingesters in Querier.FindTraceByID: %w", err)
 		}
 
+		// only the replicas of the trace are queried with query relevant ingesters
+		if q.cfg.TraceByID.ReadRepair.Enabled && q.cfg.QueryRelevantIngesters {
+			repairs, err := q.readRepairs(req.TraceID, responses)
+			if err != nil {
+				level.Warn(log.Logger).Log("msg", "failed to prepare read repair", "tenant", userID, "err", err)
+			} else if len(repairs) > 0 {
+				span.LogFields(ot_log.Int("readRepairs", len(repairs)))
+				go q.pushReadRepairs(userID, repairs)
+			}
+		}
+
 		found := false
 		for _, r := range responses {
 			t := r.response.(*tempopb.TraceByIDResponse).Trace
@@ -278,6 +349,24 @@ func (q *Querier) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDReque
 			ot_log.Int("combinedTraces", traceCountTotal))
 	}
 
+	if q.cfg.TraceByID.QueryGenerators && (req.QueryMode == QueryModeIngesters || req.QueryMode == QueryModeAll) {
+		span.LogFields(ot_log.String("msg", "searching generators"))
+
+		// the generators are a best effort addition to the ingesters, failures don't fail the request
+		traces, err := q.findTraceInGenerators(ctx, userID, req)
+		if err != nil {
+			level.Warn(log.Logger).Log("msg", "error querying generators in Querier.FindTraceByID", "tenant", userID, "err", err)
+		}
+
+		for _, t := range traces {
+			_, err = combiner.Consume(t)
+			if err != nil {
+				return nil, err
+			}
+		}
+		span.LogFields(ot_log.String("msg", "done searching generators"), ot_log.Int("foundPartialTraces", len(traces)))
+	}
+
 	if req.QueryMode == QueryModeBlocks || req.QueryMode == QueryModeAll {
 		span.LogFields(ot_log.String("msg", "searching store"))
 		span.LogFields(ot_log.String("timeStart", fmt.Sprint(timeStart)))
@@ -310,15 +399,97 @@ func (q *Querier) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDReque
 
 	completeTrace, _ := combiner.Result()
 
+	// ingesters trim their results before responding. filter the combined trace so spans found in
+	// blocks are trimmed as well and ancestors are resolved across all partial traces.
+	if spanFilter != nil {
+		completeTrace, err = spanFilter.Filter(completeTrace)
+		if err != nil {
+			return nil, fmt.Errorf("error filtering trace in Querier.FindTraceByID: %w", err)
+		}
+	}
+
 	return &tempopb.TraceByIDResponse{
 		Trace:   completeTrace,
 		Metrics: &tempopb.TraceByIDMetrics{},
 	}, nil
 }
 
+// findTraceInGenerators returns the partial traces held by the generators the spans of the trace are sent to.
+func (q *Querier) findTraceInGenerators(ctx context.Context, userID string, req *tempopb.TraceByIDRequest) ([]*tempopb.Trace, error) {
+	if q.generatorRing == nil {
+		return nil, nil
+	}
+
+	// the distributor shards the spans of a tenant across the generators by trace
+	readRing := q.generatorRing.ShuffleShard(userID, q.limits.MetricsGeneratorRingSize(userID))
+	replicationSet, err := readRing.Get(util.TokenFor(userID, req.TraceID), ring.Read, nil, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error finding generators: %w", err)
+	}
+
+	responses, err := q.forGivenGenerators(ctx, replicationSet, func(ctx context.Context, client tempopb.MetricsGeneratorClient) (interface{}, error) {
+		return client.FindTraceByID(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	traces := make([]*tempopb.Trace, 0, len(responses))
+	for _, r := range responses {
+		if t := r.response.(*tempopb.TraceByIDResponse).Trace; t != nil {
+			traces = append(traces, t)
+		}
+	}
+	return traces, nil
+}
+
+// forRecentTagSources runs ingesterFn for the ingesters and, if enabled, generatorFn for the generators of the
+// tenant and returns all responses. Failures of the generators are logged unless they are the only source.
+func (q *Querier) forRecentTagSources(ctx context.Context, userID string, ingesterFn forEachFn, generatorFn forEachGeneratorFn) ([]interface{}, error) {
+	queryGenerators := q.cfg.Search.QueryGenerators && q.generatorRing != nil
+	var responses []interface{}
+
+	if len(q.ingesterRings) > 0 || !queryGenerators {
+		lookupResults, err := q.forIngesterRings(ctx, userID, nil, ingesterFn)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range lookupResults {
+			responses = append(responses, r.response)
+		}
+	}
+
+	if queryGenerators {
+		generatorResponses, err := q.forTenantGenerators(ctx, userID, generatorFn)
+		if err != nil {
+			if len(q.ingesterRings) == 0 {
+				return nil, fmt.Errorf("error querying generators: %w", err)
+			}
+			level.Warn(log.Logger).Log("msg", "error querying generators for recent tags", "tenant", userID, "err", err)
+		}
+		for _, r := range generatorResponses {
+			responses = append(responses, r.response)
+		}
+	}
+
+	return responses, nil
+}
+
+// forTenantGenerators runs f for all generators in the shuffle shard of the tenant.
+func (q *Querier) forTenantGenerators(ctx context.Context, userID string, f forEachGeneratorFn) ([]responseFromGenerators, error) {
+	readRing := q.generatorRing.ShuffleShard(userID, q.limits.MetricsGeneratorRingSize(userID))
+	replicationSet, err := readRing.GetReplicationSetForOperation(ring.Read)
+	if err != nil {
+		return nil, fmt.Errorf("error finding generators: %w", err)
+	}
+
+	return q.forGivenGenerators(ctx, replicationSet, f)
+}
+
 type (
-	forEachFn        func(ctx context.Context, client tempopb.QuerierClient) (interface{}, error)
-	replicationSetFn func(r ring.ReadRing) (ring.ReplicationSet, error)
+	forEachFn          func(ctx context.Context, client tempopb.QuerierClient) (interface{}, error)
+	forEachGeneratorFn func(ctx context.Context, client tempopb.MetricsGeneratorClient) (interface{}, error)
+	replicationSetFn   func(r ring.ReadRing) (ring.ReplicationSet, error)
 )
 
 // forIngesterRings runs f, in parallel, for given ingesters
@@ -376,7 +547,9 @@ func (q *Querier) forIngesterRings(ctx context.Context, userID string, getReplic
 			}
 
 			for _, r := range res {
-				responses = append(responses, r.(responseFromIngesters))
+				resp := r.(responseFromIngesters)
+				resp.ring = i
+				responses = append(responses, resp)
 			}
 		}()
 	}
@@ -410,7 +583,7 @@ func forOneIngesterRing(ctx context.Context, replicationSet ring.ReplicationSet,
 			return nil, fmt.Errorf("failed to execute f() for %s: %w", ingester.Addr, err)
 		}
 
-		return responseFromIngesters{ingester.Addr, resp}, nil
+		return responseFromIngesters{addr: ingester.Addr, response: resp}, nil
 	}
 
 	return replicationSet.Do(ctx, extraQueryDelay, doFunc)
@@ -524,14 +697,16 @@ func (q *Querier) SearchTags(ctx context.Context, req *tempopb.SearchTagsRequest
 	limit := q.limits.MaxBytesPerTagValuesQuery(userID)
 	distinctValues := collector.NewDistinctString(limit)
 
-	lookupResults, err := q.forIngesterRings(ctx, userID, nil, func(ctx context.Context, client tempopb.QuerierClient) (interface{}, error) {
+	lookupResults, err := q.forRecentTagSources(ctx, userID, func(ctx context.Context, client tempopb.QuerierClient) (interface{}, error) {
+		return client.SearchTags(ctx, req)
+	}, func(ctx context.Context, client tempopb.MetricsGeneratorClient) (interface{}, error) {
 		return client.SearchTags(ctx, req)
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error querying ingesters in Querier.SearchTags: %w", err)
 	}
 	for _, resp := range lookupResults {
-		for _, res := range resp.response.(*tempopb.SearchTagsResponse).TagNames {
+		for _, res := range resp.(*tempopb.SearchTagsResponse).TagNames {
 			distinctValues.Collect(res)
 		}
 	}
@@ -554,7 +729,9 @@ func (q *Querier) SearchTagsV2(ctx context.Context, req *tempopb.SearchTagsReque
 	}
 
 	// Get results from all ingesters
-	lookupResults, err := q.forIngesterRings(ctx, userID, nil, func(ctx context.Context, client tempopb.QuerierClient) (interface{}, error) {
+	lookupResults, err := q.forRecentTagSources(ctx, userID, func(ctx context.Context, client tempopb.QuerierClient) (interface{}, error) {
+		return client.SearchTagsV2(ctx, req)
+	}, func(ctx context.Context, client tempopb.MetricsGeneratorClient) (interface{}, error) {
 		return client.SearchTagsV2(ctx, req)
 	})
 	if err != nil {
@@ -565,7 +742,7 @@ func (q *Querier) SearchTagsV2(ctx context.Context, req *tempopb.SearchTagsReque
 	distinctValues := collector.NewScopedDistinctString(limit)
 
 	for _, resp := range lookupResults {
-		for _, res := range resp.response.(*tempopb.SearchTagsV2Response).Scopes {
+		for _, res := range resp.(*tempopb.SearchTagsV2Response).Scopes {
 			for _, tag := range res.Tags {
 				distinctValues.Collect(res.Name, tag)
 			}
@@ -604,14 +781,16 @@ func (q *Querier) SearchTagValues(ctx context.Context, req *tempopb.SearchTagVal
 		distinctValues.Collect(v)
 	}
 
-	lookupResults, err := q.forIngesterRings(ctx, userID, nil, func(ctx context.Context, client tempopb.QuerierClient) (interface{}, error) {
+	lookupResults, err := q.forRecentTagSources(ctx, userID, func(ctx context.Context, client tempopb.QuerierClient) (interface{}, error) {
+		return client.SearchTagValues(ctx, req)
+	}, func(ctx context.Context, client tempopb.MetricsGeneratorClient) (interface{}, error) {
 		return client.SearchTagValues(ctx, req)
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error querying ingesters in Querier.SearchTagValues: %w", err)
 	}
 	for _, resp := range lookupResults {
-		for _, res := range resp.response.(*tempopb.SearchTagValuesResponse).TagValues {
+		for _, res := range resp.(*tempopb.SearchTagValuesResponse).TagValues {
 			distinctValues.Collect(res)
 		}
 	}
@@ -649,14 +828,16 @@ func (q *Querier) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTagV
 	}
 
 	// Get results from all ingesters
-	lookupResults, err := q.forIngesterRings(ctx, userID, nil, func(ctx context.Context, client tempopb.QuerierClient) (interface{}, error) {
+	lookupResults, err := q.forRecentTagSources(ctx, userID, func(ctx context.Context, client tempopb.QuerierClient) (interface{}, error) {
+		return client.SearchTagValuesV2(ctx, req)
+	}, func(ctx context.Context, client tempopb.MetricsGeneratorClient) (interface{}, error) {
 		return client.SearchTagValuesV2(ctx, req)
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error querying ingesters in Querier.SearchTagValues: %w", err)
 	}
 	for _, resp := range lookupResults {
-		for _, res := range resp.response.(*tempopb.SearchTagValuesV2Response).TagValues {
+		for _, res := range resp.(*tempopb.SearchTagValuesV2Response).TagValues {
 			distinctValues.Collect(*res)
 		}
 	}
@@ -770,6 +951,16 @@ func valuesToV2Response(distinctValues *collector.DistinctValue[tempopb.TagValue
 
 // SearchBlock searches the specified subset of the block for the passed tags.
 func (q *Querier) SearchBlock(ctx context.Context, req *tempopb.SearchBlockRequest) (*tempopb.SearchResponse, error) {
+	resp, err := q.searchBlock(ctx, req)
+	if err != nil && q.cfg.Search.PartialResultsOnTimeout && errors.Is(err, context.DeadlineExceeded) {
+		metricPartialSearchBlocks.Inc()
+		return partialSearchResponse(resp, req, err), nil
+	}
+
+	return resp, err
+}
+
+func (q *Querier) searchBlock(ctx context.Context, req *tempopb.SearchBlockRequest) (*tempopb.SearchResponse, error) {
 	// if we have no external configuration always search in the querier
 	if q.cfg.Search.ExternalBackend == "" && len(q.cfg.Search.ExternalEndpoints) == 0 {
 		return q.internalSearchBlock(ctx, req)
@@ -791,6 +982,27 @@ func (q *Querier) SearchBlock(ctx context.Context, req *tempopb.SearchBlockReque
 	return q.externalClient.Search(ctx, maxBytes, req)
 }
 
+// partialSearchResponse marks the response of a block search that ran out of time as partial and records
+// the shard that failed. Any results found before the deadline are kept.
+func partialSearchResponse(resp *tempopb.SearchResponse, req *tempopb.SearchBlockRequest, err error) *tempopb.SearchResponse {
+	if resp == nil {
+		resp = &tempopb.SearchResponse{}
+	}
+	if resp.Metrics == nil {
+		resp.Metrics = &tempopb.SearchMetrics{}
+	}
+
+	resp.Partial = true
+	resp.ShardErrors = append(resp.ShardErrors, &tempopb.SearchShardError{
+		BlockID:       req.BlockID,
+		StartPage:     req.StartPage,
+		PagesToSearch: req.PagesToSearch,
+		Error:         err.Error(),
+	})
+
+	return resp
+}
+
 func (q *Querier) internalSearchBlock(ctx context.Context, req *tempopb.SearchBlockRequest) (*tempopb.SearchResponse, error) {
 	tenantID, err := user.ExtractOrgID(ctx)
 	if err != nil {
@@ -832,7 +1044,7 @@ func (q *Querier) internalSearchBlock(ctx context.Context, req *tempopb.SearchBl
 
 	if api.IsTraceQLQuery(req.SearchReq) {
 		fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
-			return q.store.Fetch(ctx, meta, req, opts)
+			return q.fetch(ctx, meta, req, opts)
 		})
 
 		return q.engine.ExecuteSearch(ctx, req.SearchReq, fetcher)
@@ -841,6 +1053,15 @@ func (q *Querier) internalSearchBlock(ctx context.Context, req *tempopb.SearchBl
 	return q.store.Search(ctx, meta, req.SearchReq, opts)
 }
 
+// fetch fetches the spans of the block. If the tenant enabled it, unscoped attribute conditions are rewritten to
+// the scope the attribute is observed in.
+func (q *Querier) fetch(ctx context.Context, meta *backend.BlockMeta, req traceql.FetchSpansRequest, opts common.SearchOptions) (traceql.FetchSpansResponse, error) {
+	if q.attributeScopes != nil && q.limits.InferAttributeScopes(meta.TenantID) {
+		req = q.attributeScopes.rewrite(meta.TenantID, meta.BlockID, req)
+	}
+	return q.store.Fetch(ctx, meta, req, opts)
+}
+
 func (q *Querier) internalTagsSearchBlockV2(ctx context.Context, req *tempopb.SearchTagsBlockRequest) (*tempopb.SearchTagsV2Response, error) {
 	// check if it's the special intrinsic scope
 	// note that every block search passes the same values up. this could be handled in the frontend and be far more efficient
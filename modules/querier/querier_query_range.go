@@ -11,7 +11,6 @@ import (
 	"github.com/grafana/dskit/user"
 	"github.com/grafana/tempo/pkg/boundedwaitgroup"
 	"github.com/grafana/tempo/pkg/tempopb"
-	v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
 	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/tempodb/backend"
@@ -34,6 +33,11 @@ func (q *Querier) QueryRange(ctx context.Context, req *tempopb.QueryRangeRequest
 }
 
 func (q *Querier) queryRangeRecent(ctx context.Context, req *tempopb.QueryRangeRequest) (*tempopb.QueryRangeResponse, error) {
+	tenantID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// // Get results from all generators
 	replicationSet, err := q.generatorRing.GetReplicationSetForOperation(ring.Read)
 	if err != nil {
@@ -61,7 +65,12 @@ func (q *Querier) queryRangeRecent(ctx context.Context, req *tempopb.QueryRangeR
 		c.Combine(result.response.(*tempopb.QueryRangeResponse))
 	}
 
-	return c.Response(), nil
+	resp := c.Response()
+	if err := q.checkMetricsLimits(tenantID, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
 }
 
 func (q *Querier) queryBlock(ctx context.Context, req *tempopb.QueryRangeRequest) (*tempopb.QueryRangeResponse, error) {
@@ -120,10 +129,11 @@ func (q *Querier) queryBlock(ctx context.Context, req *tempopb.QueryRangeRequest
 	if err != nil {
 		return nil, err
 	}
+	eval.SetLimits(q.limits.MaxMetricsSeries(tenantID), q.limits.MaxMetricsResponseBytes(tenantID))
 
-	f := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
-		return q.store.Fetch(ctx, meta, req, opts)
-	})
+	f := q.fetchCache.fetcher(meta, opts.StartPage, opts.TotalPages, req.Query, traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return q.fetch(ctx, meta, req, opts)
+	}))
 	err = eval.Do(ctx, f, uint64(meta.StartTime.UnixNano()), uint64(meta.EndTime.UnixNano()))
 	if err != nil {
 		return nil, err
@@ -133,13 +143,18 @@ func (q *Querier) queryBlock(ctx context.Context, req *tempopb.QueryRangeRequest
 
 	inspectedBytes, spansTotal, _ := eval.Metrics()
 
-	return &tempopb.QueryRangeResponse{
-		Series: queryRangeTraceQLToProto(res, req),
+	resp := &tempopb.QueryRangeResponse{
+		Series: res.ToProto(req),
 		Metrics: &tempopb.SearchMetrics{
 			InspectedBytes: inspectedBytes,
 			InspectedSpans: spansTotal,
 		},
-	}, nil
+	}
+	if err := q.checkMetricsLimits(tenantID, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
 }
 
 func (q *Querier) queryBackend(ctx context.Context, req *tempopb.QueryRangeRequest) (*tempopb.QueryRangeResponse, error) {
@@ -153,9 +168,10 @@ func (q *Querier) queryBackend(ctx context.Context, req *tempopb.QueryRangeReque
 
 	// Get blocks that overlap this time range
 	metas := q.store.BlockMetas(tenantID)
+	spanStart, spanEnd := traceql.SpanTimeRange(req)
 	withinTimeRange := metas[:0]
 	for _, m := range metas {
-		if m.StartTime.UnixNano() <= int64(req.End) && m.EndTime.UnixNano() > int64(req.Start) {
+		if m.StartTime.UnixNano() <= int64(spanEnd) && m.EndTime.UnixNano() > int64(spanStart) {
 			withinTimeRange = append(withinTimeRange, m)
 		}
 	}
@@ -190,6 +206,7 @@ func (q *Querier) queryBackend(ctx context.Context, req *tempopb.QueryRangeReque
 	if err != nil {
 		return nil, err
 	}
+	eval.SetLimits(q.limits.MaxMetricsSeries(tenantID), q.limits.MaxMetricsResponseBytes(tenantID))
 
 	wg := boundedwaitgroup.New(uint(concurrency))
 	jobErr := atomic.Error{}
@@ -210,9 +227,10 @@ func (q *Querier) queryBackend(ctx context.Context, req *tempopb.QueryRangeReque
 			})
 			defer span.Finish()
 
-			f := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
-				return q.store.Fetch(ctx, m, req, common.DefaultSearchOptions())
-			})
+			opts := common.DefaultSearchOptions()
+			f := q.fetchCache.fetcher(m, opts.StartPage, opts.TotalPages, req.Query, traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+				return q.fetch(ctx, m, req, opts)
+			}))
 
 			// TODO handle error
 			err := eval.Do(ctx, f, uint64(m.StartTime.UnixNano()), uint64(m.EndTime.UnixNano()))
@@ -231,49 +249,32 @@ func (q *Querier) queryBackend(ctx context.Context, req *tempopb.QueryRangeReque
 
 	inspectedBytes, spansTotal, _ := eval.Metrics()
 
-	return &tempopb.QueryRangeResponse{
-		Series: queryRangeTraceQLToProto(res, req),
+	resp := &tempopb.QueryRangeResponse{
+		Series: res.ToProto(req),
 		Metrics: &tempopb.SearchMetrics{
 			InspectedBytes: inspectedBytes,
 			InspectedSpans: spansTotal,
 		},
-	}, nil
-}
-
-func queryRangeTraceQLToProto(set traceql.SeriesSet, req *tempopb.QueryRangeRequest) []*tempopb.TimeSeries {
-	resp := make([]*tempopb.TimeSeries, 0, len(set))
-
-	for promLabels, s := range set {
-		labels := make([]v1.KeyValue, 0, len(s.Labels))
-		for _, label := range s.Labels {
-			labels = append(labels,
-				v1.KeyValue{
-					Key:   label.Name,
-					Value: label.Value.AsAnyValue(),
-				},
-			)
-		}
-
-		intervals := traceql.IntervalCount(req.Start, req.End, req.Step)
-		samples := make([]tempopb.Sample, 0, intervals)
-		for i, value := range s.Values {
+	}
+	if err := q.checkMetricsLimits(tenantID, resp); err != nil {
+		return nil, err
+	}
 
-			ts := traceql.TimestampOf(uint64(i), req.Start, req.Step)
+	return resp, nil
+}
 
-			samples = append(samples, tempopb.Sample{
-				TimestampMs: time.Unix(0, int64(ts)).UnixMilli(),
-				Value:       value,
-			})
-		}
+// checkMetricsLimits returns a *traceql.MetricsLimitError if the response exceeds the metrics limits of the tenant.
+// The evaluator already stops early based on an estimate of the response size, this checks the actual size.
+func (q *Querier) checkMetricsLimits(tenantID string, resp *tempopb.QueryRangeResponse) error {
+	if maxSeries := q.limits.MaxMetricsSeries(tenantID); maxSeries > 0 && len(resp.Series) > maxSeries {
+		return &traceql.MetricsLimitError{Limit: traceql.MetricsLimitSeries, Max: maxSeries, Actual: len(resp.Series)}
+	}
 
-		ss := &tempopb.TimeSeries{
-			PromLabels: promLabels,
-			Labels:     labels,
-			Samples:    samples,
+	if maxBytes := q.limits.MaxMetricsResponseBytes(tenantID); maxBytes > 0 {
+		if size := resp.Size(); size > maxBytes {
+			return &traceql.MetricsLimitError{Limit: traceql.MetricsLimitResponseBytes, Max: maxBytes, Actual: size}
 		}
-
-		resp = append(resp, ss)
 	}
 
-	return resp
+	return nil
 }
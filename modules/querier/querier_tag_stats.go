@@ -0,0 +1,91 @@
+package querier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/dskit/user"
+	"github.com/opentracing/opentracing-go"
+	"github.com/uber-go/atomic"
+
+	"github.com/grafana/tempo/pkg/boundedwaitgroup"
+	"github.com/grafana/tempo/pkg/collector"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// TagStatsResponse is the estimated cardinality of the tags of a tenant in a time range
+type TagStatsResponse struct {
+	Tags []collector.TagStat `json:"tags"`
+	// Blocks is the number of blocks that were read
+	Blocks int `json:"blocks"`
+	// SkippedBlocks is the number of blocks in the time range whose version doesn't support tag stats
+	SkippedBlocks int `json:"skippedBlocks"`
+}
+
+// TagStats reads the values of the tags of all backend blocks that overlap the time range of the request. Values
+// are read from the dictionaries of the blocks, so the stats are cheap compared to a search, but they only include
+// traces that were flushed to the backend.
+func (q *Querier) TagStats(ctx context.Context, req *tempopb.SearchTagsRequest) (*TagStatsResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tenantID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting org id in Querier.TagStats: %w", err)
+	}
+
+	var withinTimeRange []*backend.BlockMeta
+	for _, m := range q.store.BlockMetas(tenantID) {
+		if m.StartTime.Unix() <= int64(req.End) && m.EndTime.Unix() >= int64(req.Start) {
+			withinTimeRange = append(withinTimeRange, m)
+		}
+	}
+
+	var (
+		stats   = collector.NewTagStats(q.cfg.Search.TagStatsMaxValuesPerTag)
+		wg      = boundedwaitgroup.New(uint(max(q.cfg.Search.TagStatsConcurrentBlocks, 1)))
+		jobErr  = atomic.Error{}
+		skipped = atomic.NewInt32(0)
+	)
+
+	for _, m := range withinTimeRange {
+		// If a job errored then quit immediately.
+		if err := jobErr.Load(); err != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(m *backend.BlockMeta) {
+			defer wg.Done()
+
+			span, ctx := opentracing.StartSpanFromContext(ctx, "querier.TagStats.Block", opentracing.Tags{
+				"block":     m.BlockID.String(),
+				"blockSize": m.Size,
+			})
+			defer span.Finish()
+
+			err := q.store.TagStats(ctx, m, req.Scope, stats, common.DefaultSearchOptions())
+			switch {
+			case errors.Is(err, common.ErrUnsupported):
+				skipped.Inc()
+			case err != nil:
+				jobErr.Store(fmt.Errorf("tag stats of block %s: %w", m.BlockID, err))
+				cancel()
+			}
+		}(m)
+	}
+
+	wg.Wait()
+	if err := jobErr.Load(); err != nil {
+		return nil, err
+	}
+
+	return &TagStatsResponse{
+		Tags:          stats.Stats(),
+		Blocks:        len(withinTimeRange) - int(skipped.Load()),
+		SkippedBlocks: int(skipped.Load()),
+	}, nil
+}
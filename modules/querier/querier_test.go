@@ -2,13 +2,17 @@ package querier
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"sort"
 	"testing"
 	"time"
 
+	"github.com/grafana/dskit/services"
 	"github.com/grafana/dskit/user"
+	"github.com/parquet-go/parquet-go"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 	"github.com/uber-go/atomic"
@@ -17,7 +21,11 @@ import (
 	ingester_client "github.com/grafana/tempo/modules/ingester/client"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/modules/querier/external"
+	"github.com/grafana/tempo/modules/storage"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
 )
 
 func TestQuerierUsesSearchExternalEndpoint(t *testing.T) {
@@ -84,7 +92,7 @@ func TestQuerierUsesSearchExternalEndpoint(t *testing.T) {
 		o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.DefaultRegisterer)
 		require.NoError(t, err)
 
-		q, err := New(tc.cfg, ingester_client.Config{}, nil, generator_client.Config{}, nil, nil, o)
+		q, err := New(tc.cfg, ingester_client.Config{}, nil, generator_client.Config{}, nil, nil, o, nil)
 		require.NoError(t, err)
 
 		for i := 0; i < tc.queriesToExecute; i++ {
@@ -101,7 +109,7 @@ func TestVirtualTagsDoesntHitBackend(t *testing.T) {
 	o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.DefaultRegisterer)
 	require.NoError(t, err)
 
-	q, err := New(Config{}, ingester_client.Config{}, nil, generator_client.Config{}, nil, nil, o)
+	q, err := New(Config{}, ingester_client.Config{}, nil, generator_client.Config{}, nil, nil, o, nil)
 	require.NoError(t, err)
 
 	ctx := user.InjectOrgID(context.Background(), "blerg")
@@ -184,3 +192,206 @@ func TestVirtualTagsDoesntHitBackend(t *testing.T) {
 	})
 	require.Error(t, err)
 }
+
+func TestSearchBlockPartialResultsOnTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	for _, partial := range []bool{true, false} {
+		o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.DefaultRegisterer)
+		require.NoError(t, err)
+
+		cfg := Config{
+			Search: SearchConfig{
+				ExternalEndpoints:       []string{srv.URL},
+				PartialResultsOnTimeout: partial,
+			},
+		}
+		q, err := New(cfg, ingester_client.Config{}, nil, generator_client.Config{}, nil, nil, o, nil)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(user.InjectOrgID(context.Background(), "blerg"), 50*time.Millisecond)
+		resp, err := q.SearchBlock(ctx, &tempopb.SearchBlockRequest{
+			BlockID:       "block",
+			StartPage:     1,
+			PagesToSearch: 2,
+		})
+		cancel()
+
+		if !partial {
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+			continue
+		}
+
+		require.NoError(t, err)
+		require.True(t, resp.Partial)
+		require.Len(t, resp.ShardErrors, 1)
+		require.Equal(t, "block", resp.ShardErrors[0].BlockID)
+		require.Equal(t, uint32(1), resp.ShardErrors[0].StartPage)
+		require.Equal(t, uint32(2), resp.ShardErrors[0].PagesToSearch)
+		require.NotEmpty(t, resp.ShardErrors[0].Error)
+	}
+}
+
+func TestTagStatsHandlerValidatesRequest(t *testing.T) {
+	o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+
+	q, err := New(Config{}, ingester_client.Config{}, nil, generator_client.Config{}, nil, nil, o, nil)
+	require.NoError(t, err)
+
+	for _, query := range []string{
+		"",
+		"start=10",
+		"start=20&end=10",
+		"start=10&end=20&scope=intrinsic",
+		"start=10&end=20&scope=foo",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v2/search/tags/stats?"+query, nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+		w := httptest.NewRecorder()
+
+		q.TagStatsHandler(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestCheckMetricsLimits(t *testing.T) {
+	resp := &tempopb.QueryRangeResponse{
+		Series: []*tempopb.TimeSeries{
+			{PromLabels: `{span.foo="bar"}`, Samples: []tempopb.Sample{{TimestampMs: 1, Value: 1}}},
+			{PromLabels: `{span.foo="baz"}`, Samples: []tempopb.Sample{{TimestampMs: 1, Value: 2}}},
+		},
+	}
+
+	tcs := []struct {
+		name        string
+		read        overrides.ReadOverrides
+		expectedErr *traceql.MetricsLimitError
+	}{
+		{
+			name: "no limits",
+		},
+		{
+			name: "within limits",
+			read: overrides.ReadOverrides{MaxMetricsSeries: 2, MaxMetricsResponseBytes: resp.Size()},
+		},
+		{
+			name:        "max series",
+			read:        overrides.ReadOverrides{MaxMetricsSeries: 1},
+			expectedErr: &traceql.MetricsLimitError{Limit: traceql.MetricsLimitSeries, Max: 1, Actual: 2},
+		},
+		{
+			name:        "max response bytes",
+			read:        overrides.ReadOverrides{MaxMetricsResponseBytes: 10},
+			expectedErr: &traceql.MetricsLimitError{Limit: traceql.MetricsLimitResponseBytes, Max: 10, Actual: resp.Size()},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			o, err := overrides.NewOverrides(overrides.Config{Defaults: overrides.Overrides{Read: tc.read}}, nil, prometheus.NewRegistry())
+			require.NoError(t, err)
+
+			q, err := New(Config{}, ingester_client.Config{}, nil, generator_client.Config{}, nil, nil, o, nil)
+			require.NoError(t, err)
+
+			err = q.checkMetricsLimits("blerg", resp)
+			if tc.expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var limitErr *traceql.MetricsLimitError
+			require.ErrorAs(t, err, &limitErr)
+			require.Equal(t, tc.expectedErr, limitErr)
+		})
+	}
+}
+
+func TestHandleBlockError(t *testing.T) {
+	tcs := []struct {
+		err            error
+		expectedStatus int
+		expectedErr    *api.QueryError
+	}{
+		{
+			err:            fmt.Errorf("error reading page: %w", parquet.ErrCorrupted),
+			expectedStatus: http.StatusInternalServerError,
+			expectedErr:    &api.QueryError{Code: api.ErrorCodeDataCorrupted, Message: "error reading page: corrupted parquet page", Block: "block"},
+		},
+		{
+			err:            fmt.Errorf("error combining: %w", trace.ErrTraceTooLarge),
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    &api.QueryError{Code: api.ErrorCodeLimitExceeded, Message: "error combining: trace exceeds max size", Block: "block", Limit: "max_bytes_per_trace"},
+		},
+		{
+			err:            &traceql.MetricsLimitError{Limit: traceql.MetricsLimitResponseBytes, Max: 1, Actual: 2},
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    &api.QueryError{Code: api.ErrorCodeLimitExceeded, Message: (&traceql.MetricsLimitError{Limit: traceql.MetricsLimitResponseBytes, Max: 1, Actual: 2}).Error(), Block: "block", Limit: "max_metrics_response_bytes"},
+		},
+		{
+			err:            context.DeadlineExceeded,
+			expectedStatus: http.StatusInternalServerError,
+			expectedErr:    &api.QueryError{Code: api.ErrorCodeTimeout, Message: "context deadline exceeded", Retryable: true, Block: "block"},
+		},
+		{
+			err:            errors.New("backend unavailable"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedErr:    &api.QueryError{Code: api.ErrorCodeInternal, Message: "backend unavailable", Retryable: true, Block: "block"},
+		},
+	}
+
+	for _, tc := range tcs {
+		// json errors
+		r := httptest.NewRequest(http.MethodGet, "/querier/api/search", nil)
+		r.Header.Set(api.HeaderJSONErrors, "true")
+		w := httptest.NewRecorder()
+		handleBlockError(w, r, tc.err, "block")
+
+		require.Equal(t, tc.expectedStatus, w.Code, tc.err)
+		require.Equal(t, tc.expectedErr.Body(), w.Body.String())
+
+		// plain text errors
+		r = httptest.NewRequest(http.MethodGet, "/querier/api/search", nil)
+		w = httptest.NewRecorder()
+		handleBlockError(w, r, tc.err, "block")
+
+		require.Equal(t, tc.expectedStatus, w.Code, tc.err)
+		require.Equal(t, tc.expectedErr.Message+"\n", w.Body.String())
+	}
+}
+
+type blocklistPollStore struct {
+	storage.Store
+	polled *atomic.Bool
+}
+
+func (s blocklistPollStore) LastBlocklistPoll() time.Time {
+	if s.polled.Load() {
+		return time.Now()
+	}
+	return time.Time{}
+}
+
+func TestQuerierWaitsForBlocklistPoll(t *testing.T) {
+	o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+
+	store := blocklistPollStore{polled: atomic.NewBool(false)}
+	q, err := New(Config{WaitForBlocklistPoll: true}, ingester_client.Config{}, nil, generator_client.Config{}, nil, store, o, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, q.StartAsync(context.Background()))
+	t.Cleanup(func() {
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), q))
+	})
+
+	time.Sleep(2 * blocklistPollCheckInterval)
+	require.Equal(t, services.Starting, q.State())
+
+	store.polled.Store(true)
+	require.NoError(t, q.AwaitRunning(context.Background()))
+}
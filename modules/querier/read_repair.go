@@ -0,0 +1,227 @@
+package querier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log/level"
+	ring_client "github.com/grafana/dskit/ring/client"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/log"
+)
+
+var (
+	metricReadRepairDivergentTraces = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "querier_read_repair_divergent_traces_total",
+		Help:      "The total number of traces found by id with divergent results across ingester replicas.",
+	})
+	metricReadRepairMissingSpans = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "querier_read_repair_missing_spans_total",
+		Help:      "The total number of spans missing from ingester replicas of traces found by id.",
+	})
+	metricReadRepairPushes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "querier_read_repair_pushes_total",
+		Help:      "The total number of read repair pushes to ingester replicas by result.",
+	}, []string{"result"})
+)
+
+// ReadRepairConfig configures the read repair of trace by id requests.
+type ReadRepairConfig struct {
+	// Enabled compares the results of the ingester replicas of a trace and pushes the missing spans to the lagging
+	// replicas in the background. Requires query_relevant_ingesters.
+	Enabled bool `yaml:"enabled"`
+	// Timeout of the read repair pushes of a trace.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// readRepair is a push of the spans missing from an ingester replica
+type readRepair struct {
+	pool  *ring_client.Pool
+	addr  string
+	spans int
+	req   *tempopb.PushBytesRequest
+}
+
+// readRepairs compares the results of the ingester replicas of a trace and returns the pushes that add the missing
+// spans to the lagging replicas. Replicas are compared per ingester ring. The pushes are prepared up front because
+// the traces of the responses are modified once they are combined.
+func (q *Querier) readRepairs(traceID []byte, responses []responseFromIngesters) ([]readRepair, error) {
+	byRing := map[int][]responseFromIngesters{}
+	for _, r := range responses {
+		byRing[r.ring] = append(byRing[r.ring], r)
+	}
+
+	decoder := model.MustNewSegmentDecoder(model.CurrentEncoding)
+
+	var repairs []readRepair
+	for ring, replicas := range byRing {
+		if len(replicas) < 2 {
+			continue
+		}
+
+		traces := make([]*tempopb.Trace, len(replicas))
+		spanIDs := make([]map[string]struct{}, len(replicas))
+		all := map[string]struct{}{}
+		for i, r := range replicas {
+			traces[i] = r.response.(*tempopb.TraceByIDResponse).Trace
+			spanIDs[i] = traceSpanIDs(traces[i])
+			for id := range spanIDs[i] {
+				all[id] = struct{}{}
+			}
+		}
+
+		diverged := false
+		for i, r := range replicas {
+			if len(spanIDs[i]) == len(all) {
+				continue
+			}
+			diverged = true
+
+			missing, count, start, end := missingSpans(traces, spanIDs[i])
+			metricReadRepairMissingSpans.Add(float64(count))
+
+			b, err := decoder.PrepareForWrite(missing, start, end)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal read repair: %w", err)
+			}
+
+			var pool *ring_client.Pool
+			if ring < len(q.ingesterPools) {
+				pool = q.ingesterPools[ring]
+			}
+			repairs = append(repairs, readRepair{
+				pool:  pool,
+				addr:  r.addr,
+				spans: count,
+				req: &tempopb.PushBytesRequest{
+					Traces: []tempopb.PreallocBytes{{Slice: b}},
+					Ids:    []tempopb.PreallocBytes{{Slice: traceID}},
+				},
+			})
+		}
+
+		if diverged {
+			metricReadRepairDivergentTraces.Inc()
+		}
+	}
+
+	return repairs, nil
+}
+
+// pushReadRepairs pushes the missing spans to the lagging replicas. Failures are logged and counted, the trace will
+// be repaired by the next request that finds it.
+func (q *Querier) pushReadRepairs(userID string, repairs []readRepair) {
+	ctx, cancel := context.WithTimeout(user.InjectOrgID(context.Background(), userID), q.cfg.TraceByID.ReadRepair.Timeout)
+	defer cancel()
+
+	for _, r := range repairs {
+		if err := pushReadRepair(ctx, r); err != nil {
+			level.Warn(log.Logger).Log("msg", "read repair push failed", "tenant", userID, "addr", r.addr, "spans", r.spans, "err", err)
+			metricReadRepairPushes.WithLabelValues("failed").Inc()
+			continue
+		}
+		metricReadRepairPushes.WithLabelValues("success").Inc()
+	}
+}
+
+func pushReadRepair(ctx context.Context, r readRepair) error {
+	if r.pool == nil {
+		return errors.New("no ingester pool")
+	}
+
+	c, err := r.pool.GetClientFor(r.addr)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.(tempopb.PusherClient).PushBytesV2(ctx, r.req)
+	if err != nil {
+		return err
+	}
+	for _, reason := range resp.ErrorsByTrace {
+		if reason != tempopb.PushErrorReason_NO_ERROR {
+			return fmt.Errorf("push rejected: %s", reason)
+		}
+	}
+
+	return nil
+}
+
+func traceSpanIDs(t *tempopb.Trace) map[string]struct{} {
+	ids := map[string]struct{}{}
+	if t == nil {
+		return ids
+	}
+
+	for _, rs := range t.Batches {
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				ids[string(s.SpanId)] = struct{}{}
+			}
+		}
+	}
+	return ids
+}
+
+// missingSpans returns a trace with the spans of the traces that aren't in have, along with the number of spans and
+// their time range in seconds. Resources and scopes are shared with the input traces.
+func missingSpans(traces []*tempopb.Trace, have map[string]struct{}) (*tempopb.Trace, int, uint32, uint32) {
+	added := map[string]struct{}{}
+	missing := &tempopb.Trace{}
+	count := 0
+	var start, end uint64
+
+	for _, t := range traces {
+		if t == nil {
+			continue
+		}
+
+		for _, rs := range t.Batches {
+			var newRS *v1.ResourceSpans
+			for _, ss := range rs.ScopeSpans {
+				var newSS *v1.ScopeSpans
+				for _, s := range ss.Spans {
+					id := string(s.SpanId)
+					if _, ok := have[id]; ok {
+						continue
+					}
+					if _, ok := added[id]; ok {
+						continue
+					}
+					added[id] = struct{}{}
+
+					if newRS == nil {
+						newRS = &v1.ResourceSpans{Resource: rs.Resource, SchemaUrl: rs.SchemaUrl}
+						missing.Batches = append(missing.Batches, newRS)
+					}
+					if newSS == nil {
+						newSS = &v1.ScopeSpans{Scope: ss.Scope, SchemaUrl: ss.SchemaUrl}
+						newRS.ScopeSpans = append(newRS.ScopeSpans, newSS)
+					}
+					newSS.Spans = append(newSS.Spans, s)
+
+					count++
+					if start == 0 || s.StartTimeUnixNano < start {
+						start = s.StartTimeUnixNano
+					}
+					if s.EndTimeUnixNano > end {
+						end = s.EndTimeUnixNano
+					}
+				}
+			}
+		}
+	}
+
+	return missing, count, uint32(start / uint64(time.Second)), uint32(end / uint64(time.Second))
+}
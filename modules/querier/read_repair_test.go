@@ -0,0 +1,60 @@
+package querier
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestReadRepairs(t *testing.T) {
+	traceID := test.ValidTraceID(nil)
+	full := test.MakeTrace(2, traceID)
+
+	// the partial replica only has the first batch of the trace
+	partial := &tempopb.Trace{Batches: []*v1.ResourceSpans{proto.Clone(full.Batches[0]).(*v1.ResourceSpans)}}
+	missingCount := len(traceSpanIDs(full)) - len(traceSpanIDs(partial))
+	require.Greater(t, missingCount, 0)
+
+	responses := []responseFromIngesters{
+		{addr: "full", response: &tempopb.TraceByIDResponse{Trace: full}},
+		{addr: "partial", response: &tempopb.TraceByIDResponse{Trace: partial}},
+		{addr: "empty", response: &tempopb.TraceByIDResponse{}},
+		// another ring is compared on its own
+		{addr: "other", response: &tempopb.TraceByIDResponse{}, ring: 1},
+	}
+
+	q := &Querier{}
+	repairs, err := q.readRepairs(traceID, responses)
+	require.NoError(t, err)
+	require.Len(t, repairs, 2)
+
+	sort.Slice(repairs, func(i, j int) bool { return repairs[i].addr < repairs[j].addr })
+	require.Equal(t, "empty", repairs[0].addr)
+	require.Equal(t, len(traceSpanIDs(full)), repairs[0].spans)
+	require.Equal(t, "partial", repairs[1].addr)
+	require.Equal(t, missingCount, repairs[1].spans)
+
+	// the repair of the partial replica only has the missing spans
+	decoder := model.MustNewSegmentDecoder(model.CurrentEncoding)
+	tr, err := decoder.PrepareForRead([][]byte{repairs[1].req.Traces[0].Slice})
+	require.NoError(t, err)
+	require.Equal(t, traceID, repairs[1].req.Ids[0].Slice)
+
+	ids := traceSpanIDs(tr)
+	require.Len(t, ids, missingCount)
+	for id := range traceSpanIDs(partial) {
+		require.NotContains(t, ids, id)
+	}
+
+	// replicas in sync don't need a repair
+	repairs, err = q.readRepairs(traceID, responses[:1])
+	require.NoError(t, err)
+	require.Empty(t, repairs)
+}
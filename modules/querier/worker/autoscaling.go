@@ -0,0 +1,120 @@
+package worker
+
+import (
+	"errors"
+	"flag"
+	"runtime/metrics"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var metricWorkerParallelism = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: "tempo",
+	Name:      "querier_worker_parallelism",
+	Help:      "The total number of requests the querier worker processes concurrently across all query-frontends.",
+})
+
+// AutoscalingConfig configures the worker to adjust its concurrency per query-frontend to the queue length reported by
+// the query-frontend and the cpu utilization of the querier. If enabled it overrides parallelism and match_max_concurrent.
+type AutoscalingConfig struct {
+	Enabled              bool          `yaml:"enabled"`
+	MinParallelism       int           `yaml:"min_parallelism"`
+	MaxParallelism       int           `yaml:"max_parallelism"`
+	Interval             time.Duration `yaml:"interval"`
+	TargetCPUUtilization float64       `yaml:"target_cpu_utilization"`
+}
+
+func (cfg *AutoscalingConfig) RegisterFlags(f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, "querier.worker-autoscaling.enabled", false, "Adjust the worker concurrency per query-frontend to the queue length of the query-frontend and the cpu utilization of the querier. Overrides querier.worker-parallelism and querier.worker-match-max-concurrent.")
+	f.IntVar(&cfg.MinParallelism, "querier.worker-autoscaling.min-parallelism", 1, "Minimum number of simultaneous queries to process per query-frontend.")
+	f.IntVar(&cfg.MaxParallelism, "querier.worker-autoscaling.max-parallelism", 10, "Maximum number of simultaneous queries to process per query-frontend.")
+	f.DurationVar(&cfg.Interval, "querier.worker-autoscaling.interval", 10*time.Second, "How often the worker concurrency is adjusted.")
+	f.Float64Var(&cfg.TargetCPUUtilization, "querier.worker-autoscaling.target-cpu-utilization", 0.8, "Cpu utilization of the querier, between 0 and 1, above which the worker concurrency is reduced.")
+}
+
+func (cfg *AutoscalingConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.MinParallelism < 1 {
+		return errors.New("worker autoscaling min_parallelism must be at least 1")
+	}
+	if cfg.MaxParallelism < cfg.MinParallelism {
+		return errors.New("worker autoscaling max_parallelism must not be less than min_parallelism")
+	}
+	if cfg.Interval <= 0 {
+		return errors.New("worker autoscaling interval must be greater than 0")
+	}
+	if cfg.TargetCPUUtilization <= 0 || cfg.TargetCPUUtilization > 1 {
+		return errors.New("worker autoscaling target_cpu_utilization must be greater than 0 and at most 1")
+	}
+	return nil
+}
+
+// nextParallelism returns the concurrency for a query-frontend. It is reduced while the querier is above its target
+// cpu utilization, grown towards the queue length while requests are waiting in the query-frontend, at most doubling
+// at once, and reduced again when the queue is empty.
+func (cfg *AutoscalingConfig) nextParallelism(current, queueLength int, cpuUtilization float64) int {
+	next := current
+	switch {
+	case cpuUtilization > cfg.TargetCPUUtilization:
+		next--
+	case queueLength > 0:
+		next += min(queueLength, max(current, 1))
+	default:
+		next--
+	}
+
+	return min(max(next, cfg.MinParallelism), cfg.MaxParallelism)
+}
+
+// cpuSampler measures the cpu utilization of the process between two calls using the cpu time estimates of the go
+// runtime.
+type cpuSampler struct {
+	mtx     sync.Mutex
+	samples []metrics.Sample
+
+	lastTotal float64
+	lastIdle  float64
+}
+
+func newCPUSampler() *cpuSampler {
+	s := &cpuSampler{
+		samples: []metrics.Sample{
+			{Name: "/cpu/classes/total:cpu-seconds"},
+			{Name: "/cpu/classes/idle:cpu-seconds"},
+		},
+	}
+	s.lastTotal, s.lastIdle = s.read()
+	return s
+}
+
+// utilization returns the share of the available cpu time used since the last call, between 0 and 1.
+func (s *cpuSampler) utilization() float64 {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	total, idle := s.read()
+	deltaTotal, deltaIdle := total-s.lastTotal, idle-s.lastIdle
+	s.lastTotal, s.lastIdle = total, idle
+
+	if deltaTotal <= 0 {
+		return 0
+	}
+	return min(max(1-deltaIdle/deltaTotal, 0), 1)
+}
+
+func (s *cpuSampler) read() (total, idle float64) {
+	metrics.Read(s.samples)
+
+	if s.samples[0].Value.Kind() == metrics.KindFloat64 {
+		total = s.samples[0].Value.Float64()
+	}
+	if s.samples[1].Value.Kind() == metrics.KindFloat64 {
+		idle = s.samples[1].Value.Float64()
+	}
+	return total, idle
+}
@@ -0,0 +1,137 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestAutoscalingConfigValidate(t *testing.T) {
+	valid := AutoscalingConfig{
+		Enabled:              true,
+		MinParallelism:       1,
+		MaxParallelism:       10,
+		Interval:             time.Second,
+		TargetCPUUtilization: 0.8,
+	}
+	require.NoError(t, valid.Validate())
+
+	disabled := AutoscalingConfig{}
+	require.NoError(t, disabled.Validate())
+
+	cfg := valid
+	cfg.MinParallelism = 0
+	require.Error(t, cfg.Validate())
+
+	cfg = valid
+	cfg.MaxParallelism = 0
+	require.Error(t, cfg.Validate())
+
+	cfg = valid
+	cfg.Interval = 0
+	require.Error(t, cfg.Validate())
+
+	cfg = valid
+	cfg.TargetCPUUtilization = 1.5
+	require.Error(t, cfg.Validate())
+}
+
+func TestNextParallelism(t *testing.T) {
+	cfg := AutoscalingConfig{
+		MinParallelism:       2,
+		MaxParallelism:       10,
+		TargetCPUUtilization: 0.8,
+	}
+
+	tcs := []struct {
+		name        string
+		current     int
+		queueLength int
+		cpu         float64
+		expected    int
+	}{
+		{name: "grows with queue", current: 4, queueLength: 1, cpu: 0.5, expected: 5},
+		{name: "at most doubles", current: 4, queueLength: 100, cpu: 0.5, expected: 8},
+		{name: "capped at max", current: 8, queueLength: 100, cpu: 0.5, expected: 10},
+		{name: "shrinks with empty queue", current: 4, queueLength: 0, cpu: 0.5, expected: 3},
+		{name: "shrinks above target cpu", current: 4, queueLength: 100, cpu: 0.9, expected: 3},
+		{name: "kept at min", current: 2, queueLength: 0, cpu: 0.9, expected: 2},
+		{name: "raised to min", current: 0, queueLength: 0, cpu: 0, expected: 2},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, cfg.nextParallelism(tc.current, tc.queueLength, tc.cpu))
+		})
+	}
+}
+
+func TestCPUSampler(t *testing.T) {
+	s := newCPUSampler()
+	u := s.utilization()
+	require.GreaterOrEqual(t, u, 0.0)
+	require.LessOrEqual(t, u, 1.0)
+}
+
+type mockProcessor struct {
+	queueLengths map[string]int
+}
+
+func (p *mockProcessor) processQueriesOnSingleStream(ctx context.Context, _ *grpc.ClientConn, _ string) {
+	<-ctx.Done()
+}
+
+func (p *mockProcessor) notifyShutdown(context.Context, *grpc.ClientConn, string) {}
+
+func (p *mockProcessor) queueLength(address string) int {
+	return p.queueLengths[address]
+}
+
+func TestWorkerScale(t *testing.T) {
+	cfg := Config{
+		Autoscaling: AutoscalingConfig{
+			Enabled:              true,
+			MinParallelism:       1,
+			MaxParallelism:       4,
+			Interval:             time.Hour,
+			TargetCPUUtilization: 1,
+		},
+	}
+	p := &mockProcessor{queueLengths: map[string]int{"busy": 10}}
+
+	w, err := newQuerierWorkerWithProcessor(cfg, log.NewNopLogger(), p, "", nil)
+	require.NoError(t, err)
+	w.cpu = newCPUSampler()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, address := range []string{"busy", "idle"} {
+		w.managers[address] = newProcessorManager(ctx, p, nil, address, cfg.Autoscaling.MinParallelism)
+	}
+	w.resetConcurrency()
+
+	require.NoError(t, w.scale(ctx))
+	require.NoError(t, w.scale(ctx))
+	require.NoError(t, w.scale(ctx))
+
+	require.Equal(t, 4, w.managers["busy"].parallelism)
+	require.Equal(t, 1, w.managers["idle"].parallelism)
+	require.Eventually(t, func() bool {
+		return w.managers["busy"].currentProcessors.Load() == 4 && w.managers["idle"].currentProcessors.Load() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// the queue drained
+	p.queueLengths["busy"] = 0
+	require.NoError(t, w.scale(ctx))
+	require.Equal(t, 3, w.managers["busy"].parallelism)
+
+	for _, m := range w.managers {
+		m.concurrency(0)
+		m.wg.Wait()
+	}
+}
@@ -41,6 +41,7 @@ func newFrontendProcessor(cfg Config, handler RequestHandler, log log.Logger) pr
 		maxMessageSize:      cfg.GRPCClientConfig.MaxSendMsgSize,
 		querierID:           cfg.QuerierID,
 		metricRequestsTotal: metricWorkerRequests,
+		queueLengths:        map[string]int{},
 	}
 }
 
@@ -50,6 +51,10 @@ type frontendProcessor struct {
 	maxMessageSize int
 	querierID      string
 
+	// Queue length last reported by each query-frontend.
+	queueLengthsMtx sync.Mutex
+	queueLengths    map[string]int
+
 	metricRequestsTotal prometheus.Counter
 	log                 log.Logger
 }
@@ -78,7 +83,7 @@ func (fp *frontendProcessor) processQueriesOnSingleStream(ctx context.Context, c
 			continue
 		}
 
-		if err := fp.process(c); err != nil {
+		if err := fp.process(c, address); err != nil {
 			// Avoid logging and connection backoff in the case of a canceled context on the gRPC stream.  This will allow queriers to reconnect and work more quickly.
 			if status.Code(err) != codes.Canceled {
 				level.Error(fp.log).Log("msg", "error processing requests", "address", address, "err", err)
@@ -92,7 +97,7 @@ func (fp *frontendProcessor) processQueriesOnSingleStream(ctx context.Context, c
 }
 
 // process loops processing requests on an established stream.
-func (fp *frontendProcessor) process(c frontendv1pb.Frontend_ProcessClient) error {
+func (fp *frontendProcessor) process(c frontendv1pb.Frontend_ProcessClient, address string) error {
 	// Build a child context so we can cancel a query when the stream is closed.
 	ctx, cancel := context.WithCancel(c.Context())
 	defer cancel()
@@ -103,6 +108,10 @@ func (fp *frontendProcessor) process(c frontendv1pb.Frontend_ProcessClient) erro
 			return err
 		}
 
+		if request.Type != frontendv1pb.Type_GET_ID {
+			fp.setQueueLength(address, int(request.QueueLength))
+		}
+
 		switch request.Type {
 		case frontendv1pb.Type_HTTP_REQUEST:
 			// Handle the request on a "background" goroutine, so we go back to
@@ -146,6 +155,21 @@ func (fp *frontendProcessor) process(c frontendv1pb.Frontend_ProcessClient) erro
 	}
 }
 
+// queueLength implements processor.
+func (fp *frontendProcessor) queueLength(address string) int {
+	fp.queueLengthsMtx.Lock()
+	defer fp.queueLengthsMtx.Unlock()
+
+	return fp.queueLengths[address]
+}
+
+func (fp *frontendProcessor) setQueueLength(address string, length int) {
+	fp.queueLengthsMtx.Lock()
+	defer fp.queueLengthsMtx.Unlock()
+
+	fp.queueLengths[address] = length
+}
+
 func (fp *frontendProcessor) runRequests(ctx context.Context, requests []*httpgrpc.HTTPRequest) []*httpgrpc.HTTPResponse {
 	wg := sync.WaitGroup{}
 
@@ -28,15 +28,19 @@ type processorManager struct {
 	cancels   []context.CancelFunc
 
 	currentProcessors *atomic.Int32
+
+	// Concurrency chosen by the autoscaling of the worker. Guarded by the lock of the worker.
+	parallelism int
 }
 
-func newProcessorManager(ctx context.Context, p processor, conn *grpc.ClientConn, address string) *processorManager {
+func newProcessorManager(ctx context.Context, p processor, conn *grpc.ClientConn, address string, parallelism int) *processorManager {
 	return &processorManager{
 		p:                 p,
 		ctx:               ctx,
 		conn:              conn,
 		address:           address,
 		currentProcessors: atomic.NewInt32(0),
+		parallelism:       parallelism,
 	}
 }
 
@@ -31,6 +31,8 @@ type Config struct {
 	QuerierID string `yaml:"id"`
 
 	GRPCClientConfig grpcclient.Config `yaml:"grpc_client_config"`
+
+	Autoscaling AutoscalingConfig `yaml:"autoscaling"`
 }
 
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
@@ -43,6 +45,7 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.StringVar(&cfg.QuerierID, "querier.id", "", "Querier ID, sent to frontend service to identify requests from the same querier. Defaults to hostname.")
 
 	cfg.GRPCClientConfig.RegisterFlagsWithPrefix("querier.frontend-client", f)
+	cfg.Autoscaling.RegisterFlags(f)
 }
 
 func (cfg *Config) Validate() error {
@@ -71,6 +74,10 @@ type processor interface {
 	// notifyShutdown notifies the remote query-frontend or query-scheduler that the querier is
 	// shutting down.
 	notifyShutdown(ctx context.Context, conn *grpc.ClientConn, address string)
+
+	// queueLength returns the number of requests waiting in the queue of the query-frontend, as last
+	// reported by it.
+	queueLength(address string) int
 }
 
 type querierWorker struct {
@@ -80,6 +87,7 @@ type querierWorker struct {
 	log log.Logger
 
 	processor processor
+	cpu       *cpuSampler
 
 	subservices *services.Manager
 
@@ -97,6 +105,10 @@ func NewQuerierWorker(cfg Config, handler RequestHandler, log log.Logger, _ prom
 		cfg.QuerierID = hostname
 	}
 
+	if err := cfg.Autoscaling.Validate(); err != nil {
+		return nil, err
+	}
+
 	var processor processor
 	var servs []services.Service
 	var address string
@@ -127,6 +139,11 @@ func newQuerierWorkerWithProcessor(cfg Config, log log.Logger, processor process
 		servs = append(servs, w)
 	}
 
+	if cfg.Autoscaling.Enabled {
+		f.cpu = newCPUSampler()
+		servs = append(servs, services.NewTimerService(cfg.Autoscaling.Interval, nil, f.scale, nil))
+	}
+
 	if len(servs) > 0 {
 		subservices, err := services.NewManager(servs...)
 		if err != nil {
@@ -184,7 +201,7 @@ func (w *querierWorker) AddressAdded(address string) {
 		return
 	}
 
-	w.managers[address] = newProcessorManager(ctx, w.processor, conn, address)
+	w.managers[address] = newProcessorManager(ctx, w.processor, conn, address, w.cfg.Autoscaling.MinParallelism)
 	// Called with lock.
 	w.resetConcurrency()
 }
@@ -212,7 +229,9 @@ func (w *querierWorker) resetConcurrency() {
 	for _, m := range w.managers {
 		concurrency := 0
 
-		if w.cfg.MatchMaxConcurrency {
+		if w.cfg.Autoscaling.Enabled {
+			concurrency = m.parallelism
+		} else if w.cfg.MatchMaxConcurrency {
 			concurrency = w.cfg.MaxConcurrentRequests / len(w.managers)
 
 			// If max concurrency does not evenly divide into our frontends a subset will be chosen
@@ -243,9 +262,34 @@ func (w *querierWorker) resetConcurrency() {
 		level.Warn(w.log).Log("msg", "total worker concurrency is greater than promql max concurrency. Queries may be queued in the querier which reduces QOS")
 	}
 
+	metricWorkerParallelism.Set(float64(totalConcurrency))
 	level.Info(w.log).Log("msg", "total worker concurrency updated", "totalConcurrency", totalConcurrency)
 }
 
+// scale adjusts the concurrency of each connection to the queue length reported by the query-frontend and
+// the cpu utilization of the querier.
+func (w *querierWorker) scale(_ context.Context) error {
+	utilization := w.cpu.utilization()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	totalConcurrency := 0
+	for address, m := range w.managers {
+		queueLength := w.processor.queueLength(address)
+		parallelism := w.cfg.Autoscaling.nextParallelism(m.parallelism, queueLength, utilization)
+		if parallelism != m.parallelism {
+			level.Debug(w.log).Log("msg", "scaling worker concurrency", "addr", address, "from", m.parallelism, "to", parallelism, "queueLength", queueLength, "cpuUtilization", utilization)
+			m.parallelism = parallelism
+			m.concurrency(parallelism)
+		}
+		totalConcurrency += parallelism
+	}
+
+	metricWorkerParallelism.Set(float64(totalConcurrency))
+	return nil
+}
+
 func (w *querierWorker) connect(ctx context.Context, address string) (*grpc.ClientConn, error) {
 	// Because we only use single long-running method, it doesn't make sense to inject user ID, send over tracing or add metrics.
 	opts, err := w.cfg.GRPCClientConfig.DialOption(nil, nil)
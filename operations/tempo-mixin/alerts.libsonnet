@@ -253,6 +253,20 @@
               runbook_url: 'https://github.com/grafana/tempo/tree/main/operations/tempo-mixin/runbook.md#TempoIngesterReplayErrors',
             },
           },
+          {
+            alert: 'TempoIngestionLatencyHigh',
+            expr: |||
+              histogram_quantile(0.99, sum by (%s, le) (rate(tempo_ingester_ingestion_latency_seconds_bucket{namespace=~"%s", stage="wal_block"}[5m]))) > %d
+            ||| % [$._config.group_by_tenant, $._config.namespace, $._config.alerts.p99_ingestion_latency_threshold_seconds],
+            'for': '15m',
+            labels: {
+              severity: 'warning',
+            },
+            annotations: {
+              message: 'Traces of tenant {{ $labels.tenant }} in {{ $labels.%s }}/{{ $labels.namespace }} take {{ printf "%%.0f" $value }}s to become queryable.' % $._config.per_cluster_label,
+              runbook_url: 'https://github.com/grafana/tempo/tree/main/operations/tempo-mixin/runbook.md#TempoIngestionLatencyHigh',
+            },
+          },
         ],
       },
     ],
@@ -24,6 +24,7 @@
       p99_request_exclude_regex: 'metrics|/frontend.Frontend/Process|debug_pprof',
       outstanding_blocks_warning: 100,
       outstanding_blocks_critical: 250,
+      p99_ingestion_latency_threshold_seconds: 600,
     },
 
     per_cluster_label: 'cluster',
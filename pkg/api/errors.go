@@ -0,0 +1,212 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+)
+
+// ErrorCode is a machine-readable classification of a failed query.
+type ErrorCode string
+
+const (
+	// ErrorCodeBadRequest indicates an invalid request, e.g. a query that doesn't parse.
+	ErrorCodeBadRequest ErrorCode = "bad_request"
+	// ErrorCodeLimitExceeded indicates the query exceeded a limit. The limit is named in QueryError.Limit.
+	ErrorCodeLimitExceeded ErrorCode = "limit_exceeded"
+	// ErrorCodeRateLimited indicates too many queries are in flight.
+	ErrorCodeRateLimited ErrorCode = "rate_limited"
+	// ErrorCodeTimeout indicates the query didn't complete in time.
+	ErrorCodeTimeout ErrorCode = "timeout"
+	// ErrorCodeCanceled indicates the query was canceled by the client.
+	ErrorCodeCanceled ErrorCode = "canceled"
+	// ErrorCodeNotFound indicates the requested resource doesn't exist.
+	ErrorCodeNotFound ErrorCode = "not_found"
+	// ErrorCodeDataCorrupted indicates stored data could not be read because it is corrupted. The block is
+	// named in QueryError.Block if known.
+	ErrorCodeDataCorrupted ErrorCode = "data_corrupted"
+	// ErrorCodeInternal indicates any other error.
+	ErrorCodeInternal ErrorCode = "internal"
+)
+
+// StatusClientClosedRequest is the status code used when a client canceled its request.
+const StatusClientClosedRequest = 499
+
+// HeaderJSONErrors asks for the errors of a request as json QueryError. The query frontend sets it on the
+// requests to the queriers if json errors are enabled. Errors are written as plain text without it.
+const HeaderJSONErrors = "X-Tempo-Json-Errors"
+
+// QueryError is an error with a machine-readable code. It is serialized as json into the body of failed
+// responses by the queriers and the query frontend if json errors are enabled, so clients can tell the causes
+// of failed queries apart.
+type QueryError struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+	// Retryable is true if the same request may succeed when retried.
+	Retryable bool `json:"retryable"`
+	// Block is the id of the block that caused the error, if any.
+	Block string `json:"block,omitempty"`
+	// Limit is the name of the exceeded limit, if any.
+	Limit string `json:"limit,omitempty"`
+
+	err error
+}
+
+// NewQueryError returns a QueryError with the given code wrapping err. Rate limited, timed out and internal
+// errors are retryable.
+func NewQueryError(code ErrorCode, err error) *QueryError {
+	return &QueryError{
+		Code:      code,
+		Message:   err.Error(),
+		Retryable: code == ErrorCodeRateLimited || code == ErrorCodeTimeout || code == ErrorCodeInternal,
+		err:       err,
+	}
+}
+
+// WithBlock sets the block that caused the error.
+func (e *QueryError) WithBlock(blockID string) *QueryError {
+	e.Block = blockID
+	return e
+}
+
+// WithLimit sets the name of the exceeded limit.
+func (e *QueryError) WithLimit(limit string) *QueryError {
+	e.Limit = limit
+	return e
+}
+
+func (e *QueryError) Error() string {
+	return e.Message
+}
+
+func (e *QueryError) Unwrap() error {
+	return e.err
+}
+
+// HTTPStatus returns the http status code the error is returned with. Exceeded limits are bad requests.
+func (e *QueryError) HTTPStatus() int {
+	switch e.Code {
+	case ErrorCodeBadRequest, ErrorCodeLimitExceeded:
+		return http.StatusBadRequest
+	case ErrorCodeRateLimited:
+		return http.StatusTooManyRequests
+	case ErrorCodeTimeout:
+		return http.StatusGatewayTimeout
+	case ErrorCodeCanceled:
+		return StatusClientClosedRequest
+	case ErrorCodeNotFound:
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// GRPCCode returns the grpc status code the error is returned with.
+func (e *QueryError) GRPCCode() codes.Code {
+	switch e.Code {
+	case ErrorCodeBadRequest:
+		return codes.InvalidArgument
+	case ErrorCodeLimitExceeded, ErrorCodeRateLimited:
+		return codes.ResourceExhausted
+	case ErrorCodeTimeout:
+		return codes.DeadlineExceeded
+	case ErrorCodeCanceled:
+		return codes.Canceled
+	case ErrorCodeNotFound:
+		return codes.NotFound
+	case ErrorCodeDataCorrupted:
+		return codes.DataLoss
+	}
+	return codes.Internal
+}
+
+// Body returns the json serialization of the error.
+func (e *QueryError) Body() string {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return e.Message
+	}
+	return string(b)
+}
+
+// ErrorCodeFromStatus returns the code of an error returned with the given http status code. It is the inverse of
+// HTTPStatus for the codes that can be told apart by status. Exceeded limits and corrupted data are only known
+// from the body of a QueryError, other 4xx are bad requests and other 5xx internal errors.
+func ErrorCodeFromStatus(status int) ErrorCode {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return ErrorCodeRateLimited
+	case status == http.StatusGatewayTimeout || status == http.StatusRequestTimeout:
+		return ErrorCodeTimeout
+	case status == StatusClientClosedRequest:
+		return ErrorCodeCanceled
+	case status == http.StatusNotFound:
+		return ErrorCodeNotFound
+	case status/100 == 4:
+		return ErrorCodeBadRequest
+	}
+	return ErrorCodeInternal
+}
+
+// AsQueryError returns err as QueryError. Errors that aren't a QueryError are classified as canceled, timed out
+// or internal errors.
+func AsQueryError(err error) *QueryError {
+	var queryErr *QueryError
+	switch {
+	case errors.As(err, &queryErr):
+		return queryErr
+	case errors.Is(err, context.Canceled):
+		return NewQueryError(ErrorCodeCanceled, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return NewQueryError(ErrorCodeTimeout, err)
+	}
+	return NewQueryError(ErrorCodeInternal, err)
+}
+
+// ParseQueryError parses the body of a failed response. Bodies that aren't a serialized QueryError are
+// classified by the status code of the response and used as message.
+func ParseQueryError(status int, body []byte) *QueryError {
+	queryErr := &QueryError{}
+	if err := json.Unmarshal(body, queryErr); err == nil && queryErr.Code != "" {
+		return queryErr
+	}
+
+	return NewQueryError(ErrorCodeFromStatus(status), errors.New(strings.TrimSpace(string(body))))
+}
+
+// IsQueryErrorBody returns true if the body is a serialized QueryError.
+func IsQueryErrorBody(body []byte) bool {
+	queryErr := &QueryError{}
+	return json.Unmarshal(body, queryErr) == nil && queryErr.Code != ""
+}
+
+// AcceptsJSONErrors returns true if the errors of the request are written as json QueryError.
+func AcceptsJSONErrors(r *http.Request) bool {
+	return r.Header.Get(HeaderJSONErrors) == "true"
+}
+
+// WriteQueryErrorForRequest writes the error with the given http status code. It is written as json if the request
+// accepts json errors, as plain text otherwise.
+func WriteQueryErrorForRequest(w http.ResponseWriter, r *http.Request, status int, err *QueryError) {
+	if !AcceptsJSONErrors(r) {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	WriteQueryErrorWithStatus(w, status, err)
+}
+
+// WriteQueryError writes the error as json with its http status code.
+func WriteQueryError(w http.ResponseWriter, err *QueryError) {
+	WriteQueryErrorWithStatus(w, err.HTTPStatus(), err)
+}
+
+// WriteQueryErrorWithStatus writes the error as json with the given http status code.
+func WriteQueryErrorWithStatus(w http.ResponseWriter, status int, err *QueryError) {
+	w.Header().Set(HeaderContentType, HeaderAcceptJSON)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(err.Body()))
+}
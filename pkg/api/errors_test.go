@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestQueryErrorRoundTrip(t *testing.T) {
+	queryErr := NewQueryError(ErrorCodeDataCorrupted, errors.New("corrupted parquet page")).WithBlock("b18beca6-4d7f-4464-9f72-f343e688a4a0")
+
+	w := httptest.NewRecorder()
+	WriteQueryError(w, queryErr)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, HeaderAcceptJSON, w.Header().Get(HeaderContentType))
+	assert.Equal(t, `{"code":"data_corrupted","message":"corrupted parquet page","retryable":false,"block":"b18beca6-4d7f-4464-9f72-f343e688a4a0"}`, w.Body.String())
+
+	parsed := ParseQueryError(w.Code, w.Body.Bytes())
+	assert.Equal(t, ErrorCodeDataCorrupted, parsed.Code)
+	assert.Equal(t, "corrupted parquet page", parsed.Message)
+	assert.False(t, parsed.Retryable)
+	assert.Equal(t, "b18beca6-4d7f-4464-9f72-f343e688a4a0", parsed.Block)
+	assert.Equal(t, codes.DataLoss, parsed.GRPCCode())
+}
+
+func TestParseQueryErrorPlainBody(t *testing.T) {
+	tcs := []struct {
+		status    int
+		code      ErrorCode
+		retryable bool
+	}{
+		{status: http.StatusBadRequest, code: ErrorCodeBadRequest},
+		{status: http.StatusNotFound, code: ErrorCodeNotFound},
+		{status: http.StatusRequestEntityTooLarge, code: ErrorCodeBadRequest},
+		{status: http.StatusTooManyRequests, code: ErrorCodeRateLimited, retryable: true},
+		{status: StatusClientClosedRequest, code: ErrorCodeCanceled},
+		{status: http.StatusInternalServerError, code: ErrorCodeInternal, retryable: true},
+		{status: http.StatusGatewayTimeout, code: ErrorCodeTimeout, retryable: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(fmt.Sprint(tc.status), func(t *testing.T) {
+			queryErr := ParseQueryError(tc.status, []byte("something failed\n"))
+			assert.Equal(t, tc.code, queryErr.Code)
+			assert.Equal(t, "something failed", queryErr.Message)
+			assert.Equal(t, tc.retryable, queryErr.Retryable)
+		})
+	}
+}
+
+func TestAsQueryError(t *testing.T) {
+	limitErr := NewQueryError(ErrorCodeLimitExceeded, errors.New("too big")).WithLimit("max_bytes_per_trace")
+	require.Same(t, limitErr, AsQueryError(fmt.Errorf("wrapped: %w", limitErr)))
+
+	assert.Equal(t, ErrorCodeCanceled, AsQueryError(fmt.Errorf("wrapped: %w", context.Canceled)).Code)
+	assert.Equal(t, ErrorCodeTimeout, AsQueryError(fmt.Errorf("wrapped: %w", context.DeadlineExceeded)).Code)
+	assert.Equal(t, ErrorCodeInternal, AsQueryError(errors.New("boom")).Code)
+
+	assert.ErrorIs(t, AsQueryError(context.DeadlineExceeded), context.DeadlineExceeded)
+}
+
+func TestErrorCodeFromStatusIsInverseOfHTTPStatus(t *testing.T) {
+	for _, code := range []ErrorCode{
+		ErrorCodeBadRequest,
+		ErrorCodeRateLimited,
+		ErrorCodeTimeout,
+		ErrorCodeCanceled,
+		ErrorCodeNotFound,
+		ErrorCodeInternal,
+	} {
+		assert.Equal(t, code, ErrorCodeFromStatus(NewQueryError(code, errors.New("failed")).HTTPStatus()), code)
+	}
+
+	// exceeded limits and corrupted data share the status of bad requests and internal errors
+	assert.Equal(t, http.StatusBadRequest, NewQueryError(ErrorCodeLimitExceeded, errors.New("failed")).HTTPStatus())
+	assert.Equal(t, http.StatusInternalServerError, NewQueryError(ErrorCodeDataCorrupted, errors.New("failed")).HTTPStatus())
+}
+
+func TestWriteQueryErrorForRequest(t *testing.T) {
+	queryErr := NewQueryError(ErrorCodeLimitExceeded, errors.New("trace too large")).WithLimit("max_bytes_per_trace")
+
+	r := httptest.NewRequest(http.MethodGet, "/api/traces/1234", nil)
+	w := httptest.NewRecorder()
+	WriteQueryErrorForRequest(w, r, http.StatusBadRequest, queryErr)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "trace too large\n", w.Body.String())
+
+	r.Header.Set(HeaderJSONErrors, "true")
+	w = httptest.NewRecorder()
+	WriteQueryErrorForRequest(w, r, http.StatusBadRequest, queryErr)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, HeaderAcceptJSON, w.Header().Get(HeaderContentType))
+	assert.Equal(t, queryErr.Body(), w.Body.String())
+}
@@ -39,6 +39,15 @@ const (
 	urlParamShard           = "shard"
 	urlParamShardCount      = "shardCount"
 	urlParamSince           = "since"
+	urlParamBaselineStart   = "baselineStart"
+	urlParamBaselineEnd     = "baselineEnd"
+
+	// metrics query range
+	urlParamExemplars      = "exemplars"
+	urlParamExemplarPolicy = "exemplarPolicy"
+	urlParamOffset         = "offset"
+	urlParamLookback       = "lookback"
+	urlParamAlign          = "align"
 
 	// backend search (querier/serverless)
 	urlParamStartPage        = "startPage"
@@ -78,22 +87,40 @@ const (
 	PathEcho               = "/api/echo"
 	PathBuildInfo          = "/api/status/buildinfo"
 	PathUsageStats         = "/status/usage-stats"
+	PathUsageMetrics       = "/usage_metrics"
 	PathSpanMetrics        = "/api/metrics"
 	PathSpanMetricsSummary = "/api/metrics/summary"
 	PathMetricsQueryRange  = "/api/metrics/query_range"
 
+	// PathGeneratorSeriesDelete removes series of the metrics-generator registry matching a series selector
+	PathGeneratorSeriesDelete = "/api/metrics/series/delete"
+
 	// PathOverrides user configurable overrides
 	PathOverrides = "/api/overrides"
 
 	PathSearchTagValuesV2 = "/api/v2/search/tag/{" + MuxVarTagName + "}/values"
 	PathSearchTagsV2      = "/api/v2/search/tags"
 
+	// PathSearchTagsStats returns the estimated cardinality of the values of the tags
+	PathSearchTagsStats = "/api/v2/search/tags/stats"
+
+	// PathSearchV2 and PathMetricsQueryRangeV2 return their results in the v2 response envelope
+	PathSearchV2            = "/api/v2/search"
+	PathMetricsQueryRangeV2 = "/api/v2/metrics/query_range"
+
+	// PathTraceQLLint checks a query for warnings and estimates its cost without executing it
+	PathTraceQLLint = "/api/v2/traceql/lint"
+
+	// PathMetricsQueryRangeDiff runs a metrics query over a baseline and a comparison time range
+	PathMetricsQueryRangeDiff = "/api/metrics/query_range/diff"
+
 	QueryModeKey       = "mode"
 	QueryModeIngesters = "ingesters"
 	QueryModeBlocks    = "blocks"
 	QueryModeAll       = "all"
 	BlockStartKey      = "blockStart"
 	BlockEndKey        = "blockEnd"
+	SpanFilterKey      = "spanFilter"
 
 	defaultLimit           = 20
 	defaultSpansPerSpanSet = 3
@@ -115,6 +142,21 @@ func ParseTraceID(r *http.Request) ([]byte, error) {
 	return byteID, nil
 }
 
+// ParseSpanFilter returns the optional TraceQL query used to trim a trace by id response down to the
+// matching spans and their ancestors. An empty string is returned if the parameter is not set.
+func ParseSpanFilter(r *http.Request) (string, error) {
+	q, ok := extractQueryParam(r, SpanFilterKey)
+	if !ok {
+		return "", nil
+	}
+
+	if _, err := traceql.NewTraceFilter(q); err != nil {
+		return "", fmt.Errorf("invalid spanFilter: %w", err)
+	}
+
+	return q, nil
+}
+
 // ParseSearchRequest takes an http.Request and decodes query params to create a tempopb.SearchRequest
 func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 	req := &tempopb.SearchRequest{
@@ -352,6 +394,37 @@ func ParseQueryRangeRequest(r *http.Request) (*tempopb.QueryRangeRequest, error)
 	}
 	req.Step = uint64(step.Nanoseconds())
 
+	if s, ok := extractQueryParam(r, urlParamExemplars); ok {
+		exemplars, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return nil, httpgrpc.Errorf(http.StatusBadRequest, "invalid exemplars: %s", err)
+		}
+		req.Exemplars = uint32(exemplars)
+	}
+
+	if s, ok := extractQueryParam(r, urlParamExemplarPolicy); ok {
+		if _, err := traceql.ParseExemplarPolicy(s); err != nil {
+			return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+		}
+		req.ExemplarPolicy = s
+	}
+
+	if s, ok := extractQueryParam(r, urlParamOffset); ok {
+		offset, err := parseSecondsOrDuration(s)
+		if err != nil || offset < 0 {
+			return nil, httpgrpc.Errorf(http.StatusBadRequest, "invalid offset: %s", s)
+		}
+		req.Offset = uint64(offset.Nanoseconds())
+	}
+
+	if s, ok := extractQueryParam(r, urlParamLookback); ok {
+		lookback, err := parseSecondsOrDuration(s)
+		if err != nil || lookback < 0 {
+			return nil, httpgrpc.Errorf(http.StatusBadRequest, "invalid lookback: %s", s)
+		}
+		req.Lookback = uint64(lookback.Nanoseconds())
+	}
+
 	shardCount, _ := extractQueryParam(r, urlParamShardCount)
 	if shardCount, err := strconv.Atoi(shardCount); err == nil {
 		req.ShardCount = uint32(shardCount)
@@ -404,6 +477,54 @@ func ParseQueryRangeRequest(r *http.Request) (*tempopb.QueryRangeRequest, error)
 	return req, nil
 }
 
+// ParseQueryRangeAlign returns whether the time range of a query range request is aligned to its step. Alignment
+// is enabled unless the request disables it with align=false.
+func ParseQueryRangeAlign(r *http.Request) (bool, error) {
+	s, ok := extractQueryParam(r, urlParamAlign)
+	if !ok {
+		return true, nil
+	}
+
+	align, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, httpgrpc.Errorf(http.StatusBadRequest, "invalid align: %s", s)
+	}
+	return align, nil
+}
+
+// ParseQueryRangeDiffRequest parses a query range diff request. The returned query range request is the
+// comparison range, the baseline range is returned in unix nanoseconds. The baseline range must be as long as
+// the comparison range, if baselineEnd is missing it's derived from baselineStart.
+func ParseQueryRangeDiffRequest(r *http.Request) (*tempopb.QueryRangeRequest, uint64, uint64, error) {
+	req, err := ParseQueryRangeRequest(r)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	s, ok := extractQueryParam(r, urlParamBaselineStart)
+	if !ok {
+		return nil, 0, 0, httpgrpc.Errorf(http.StatusBadRequest, "please provide the baseline range in the %s parameter", urlParamBaselineStart)
+	}
+	baselineStart, err := parseTimestamp(s, time.Time{})
+	if err != nil {
+		return nil, 0, 0, httpgrpc.Errorf(http.StatusBadRequest, "could not parse '%s' parameter: %s", urlParamBaselineStart, err)
+	}
+
+	duration := time.Duration(req.End - req.Start)
+	baselineEnd := baselineStart.Add(duration)
+	if s, ok := extractQueryParam(r, urlParamBaselineEnd); ok {
+		baselineEnd, err = parseTimestamp(s, time.Time{})
+		if err != nil {
+			return nil, 0, 0, httpgrpc.Errorf(http.StatusBadRequest, "could not parse '%s' parameter: %s", urlParamBaselineEnd, err)
+		}
+		if baselineEnd.Sub(baselineStart) != duration {
+			return nil, 0, 0, httpgrpc.Errorf(http.StatusBadRequest, "the baseline range must be as long as the query range, baseline=%s query=%s", baselineEnd.Sub(baselineStart), duration)
+		}
+	}
+
+	return req, uint64(baselineStart.UnixNano()), uint64(baselineEnd.UnixNano()), nil
+}
+
 func BuildQueryRangeRequest(req *http.Request, searchReq *tempopb.QueryRangeRequest) *http.Request {
 	if req == nil {
 		req = &http.Request{
@@ -422,6 +543,14 @@ func BuildQueryRangeRequest(req *http.Request, searchReq *tempopb.QueryRangeRequ
 	q.Set(urlParamShard, strconv.FormatUint(uint64(searchReq.ShardID), 10))
 	q.Set(urlParamShardCount, strconv.FormatUint(uint64(searchReq.ShardCount), 10))
 	q.Set(QueryModeKey, searchReq.QueryMode)
+	q.Set(urlParamExemplars, strconv.FormatUint(uint64(searchReq.Exemplars), 10))
+	q.Set(urlParamExemplarPolicy, searchReq.ExemplarPolicy)
+	if searchReq.Offset > 0 {
+		q.Set(urlParamOffset, time.Duration(searchReq.Offset).String())
+	}
+	if searchReq.Lookback > 0 {
+		q.Set(urlParamLookback, time.Duration(searchReq.Lookback).String())
+	}
 	// New RF1 params
 	q.Set(urlParamBlockID, searchReq.BlockID)
 	q.Set(urlParamStartPage, strconv.Itoa(int(searchReq.StartPage)))
@@ -656,6 +785,37 @@ func ExtractServerlessParams(req *http.Request) (int, error) {
 	return int(maxBytes), nil
 }
 
+// ParseTraceQLLintRequest returns the query and the optional start and end of a lint request. The query
+// is not parsed so that parse errors can be reported by the linter.
+func ParseTraceQLLintRequest(r *http.Request) (query string, start, end uint32, err error) {
+	query, ok := extractQueryParam(r, urlParamQuery)
+	if !ok {
+		return "", 0, 0, errors.New("please provide a query in the q parameter")
+	}
+
+	if s, ok := extractQueryParam(r, urlParamStart); ok {
+		v, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return "", 0, 0, fmt.Errorf("invalid start: %w", err)
+		}
+		start = uint32(v)
+	}
+
+	if s, ok := extractQueryParam(r, urlParamEnd); ok {
+		v, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return "", 0, 0, fmt.Errorf("invalid end: %w", err)
+		}
+		end = uint32(v)
+	}
+
+	if start > end {
+		return "", 0, 0, fmt.Errorf("http parameter start must be before end. received start=%d end=%d", start, end)
+	}
+
+	return query, start, end, nil
+}
+
 func extractQueryParam(r *http.Request, param string) (string, bool) {
 	value := r.URL.Query().Get(param)
 	return value, value != ""
@@ -556,6 +556,41 @@ func TestValidateAndSanitizeRequest(t *testing.T) {
 	}
 }
 
+func TestParseSpanFilter(t *testing.T) {
+	tests := []struct {
+		httpReq       *http.Request
+		expected      string
+		expectedError bool
+	}{
+		{
+			httpReq:  httptest.NewRequest("GET", "/api/traces/1234", nil),
+			expected: "",
+		},
+		{
+			httpReq:  httptest.NewRequest("GET", "/api/traces/1234?spanFilter="+url.QueryEscape(`{ span.foo = "bar" }`), nil),
+			expected: `{ span.foo = "bar" }`,
+		},
+		{
+			httpReq:       httptest.NewRequest("GET", "/api/traces/1234?spanFilter="+url.QueryEscape(`{ span.foo = `), nil),
+			expectedError: true,
+		},
+		{
+			httpReq:       httptest.NewRequest("GET", "/api/traces/1234?spanFilter="+url.QueryEscape(`{ } | rate()`), nil),
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		actual, err := ParseSpanFilter(tc.httpReq)
+		if tc.expectedError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, actual)
+	}
+}
+
 func TestBuildSearchRequest(t *testing.T) {
 	tests := []struct {
 		req     *tempopb.SearchRequest
@@ -719,6 +754,28 @@ func TestQueryRangeRoundtrip(t *testing.T) {
 				QueryMode:  "foo",
 			},
 		},
+		{
+			name: "exemplars",
+			req: &tempopb.QueryRangeRequest{
+				Query:          "{ foo = `bar` } | rate()",
+				Start:          uint64(24 * time.Hour),
+				End:            uint64(25 * time.Hour),
+				Step:           uint64(30 * time.Second),
+				Exemplars:      5,
+				ExemplarPolicy: "slowest",
+			},
+		},
+		{
+			name: "offset and lookback",
+			req: &tempopb.QueryRangeRequest{
+				Query:    "{ foo = `bar` } | rate()",
+				Start:    uint64(24 * time.Hour),
+				End:      uint64(25 * time.Hour),
+				Step:     uint64(30 * time.Second),
+				Offset:   uint64(time.Hour),
+				Lookback: uint64(5 * time.Minute),
+			},
+		},
 	}
 
 	for _, tc := range tcs {
@@ -731,6 +788,39 @@ func TestQueryRangeRoundtrip(t *testing.T) {
 	}
 }
 
+func TestQueryRangeInvalidExemplars(t *testing.T) {
+	for _, query := range []string{"exemplars=-1", "exemplars=foo", "exemplarPolicy=fastest"} {
+		t.Run(query, func(t *testing.T) {
+			httpReq := httptest.NewRequest("GET", "/api/metrics/query_range?q={}|rate()&"+query, nil)
+			_, err := ParseQueryRangeRequest(httpReq)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestQueryRangeInvalidOffsetAndLookback(t *testing.T) {
+	for _, query := range []string{"offset=-1m", "offset=foo", "lookback=-5m", "lookback=foo"} {
+		t.Run(query, func(t *testing.T) {
+			httpReq := httptest.NewRequest("GET", "/api/metrics/query_range?q={}|rate()&"+query, nil)
+			_, err := ParseQueryRangeRequest(httpReq)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseQueryRangeAlign(t *testing.T) {
+	for query, expected := range map[string]bool{"": true, "align=true": true, "align=false": false} {
+		t.Run(query, func(t *testing.T) {
+			align, err := ParseQueryRangeAlign(httptest.NewRequest("GET", "/api/metrics/query_range?"+query, nil))
+			require.NoError(t, err)
+			require.Equal(t, expected, align)
+		})
+	}
+
+	_, err := ParseQueryRangeAlign(httptest.NewRequest("GET", "/api/metrics/query_range?align=foo", nil))
+	require.Error(t, err)
+}
+
 func Test_determineBounds(t *testing.T) {
 	type args struct {
 		now         time.Time
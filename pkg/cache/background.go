@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"sync"
+	"time"
 
 	opentracing "github.com/opentracing/opentracing-go"
 	otlog "github.com/opentracing/opentracing-go/log"
@@ -38,6 +39,7 @@ type backgroundCache struct {
 type backgroundWrite struct {
 	keys []string
 	bufs [][]byte
+	ttl  time.Duration
 }
 
 // NewBackground returns a new Cache that does stores on background goroutines.
@@ -82,6 +84,11 @@ const keysPerBatch = 100
 
 // Store writes keys for the cache in the background.
 func (c *backgroundCache) Store(ctx context.Context, keys []string, bufs [][]byte) {
+	c.StoreWithTTL(ctx, keys, bufs, 0)
+}
+
+// StoreWithTTL writes keys with a TTL for the cache in the background. A TTL of 0 uses the configured expiration.
+func (c *backgroundCache) StoreWithTTL(ctx context.Context, keys []string, bufs [][]byte, ttl time.Duration) {
 	for len(keys) > 0 {
 		num := keysPerBatch
 		if num > len(keys) {
@@ -91,6 +98,7 @@ func (c *backgroundCache) Store(ctx context.Context, keys []string, bufs [][]byt
 		bgWrite := backgroundWrite{
 			keys: keys[:num],
 			bufs: bufs[:num],
+			ttl:  ttl,
 		}
 		select {
 		case c.bgWrites <- bgWrite:
@@ -118,7 +126,7 @@ func (c *backgroundCache) writeBackLoop() {
 				return
 			}
 			c.queueLength.Sub(float64(len(bgWrite.keys)))
-			c.Cache.Store(context.Background(), bgWrite.keys, bgWrite.bufs)
+			StoreWithTTL(context.Background(), c.Cache, bgWrite.keys, bgWrite.bufs, bgWrite.ttl)
 
 		case <-c.quit:
 			return
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/dskit/services"
 )
@@ -13,11 +14,13 @@ const (
 	RoleNone             Role = "none"
 	RoleBloom            Role = "bloom"
 	RoleTraceIDIdx       Role = "trace-id-index"
+	RoleAttributeIdx     Role = "attribute-index"
 	RoleParquetFooter    Role = "parquet-footer"
 	RoleParquetColumnIdx Role = "parquet-column-idx"
 	RoleParquetOffsetIdx Role = "parquet-offset-idx"
 	RoleFrontendSearch   Role = "frontend-search"
 	RoleParquetPage      Role = "parquet-page"
+	RoleTraceQLFetch     Role = "traceql-fetch"
 )
 
 // Provider is an object that can return a cache for a requested role
@@ -42,3 +45,18 @@ type Cache interface {
 	Fetch(ctx context.Context, keys []string) (found []string, bufs [][]byte, missing []string)
 	Stop()
 }
+
+// TTLCache is implemented by caches that can store keys with a TTL other than their configured expiration.
+type TTLCache interface {
+	StoreWithTTL(ctx context.Context, key []string, buf [][]byte, ttl time.Duration)
+}
+
+// StoreWithTTL stores the keys with the given TTL if the cache supports it. Otherwise, or if the TTL is 0, the
+// keys are stored with the configured expiration of the cache.
+func StoreWithTTL(ctx context.Context, c Cache, keys []string, bufs [][]byte, ttl time.Duration) {
+	if tc, ok := c.(TTLCache); ok && ttl > 0 {
+		tc.StoreWithTTL(ctx, keys, bufs, ttl)
+		return
+	}
+	c.Store(ctx, keys, bufs)
+}
@@ -220,14 +220,30 @@ loopResults:
 	return
 }
 
+// maxMemcachedTTL is the longest relative expiration, memcached treats longer expirations as unix timestamps.
+const maxMemcachedTTL = 30 * 24 * time.Hour
+
 // Store stores the key in the cache.
 func (c *Memcached) Store(ctx context.Context, keys []string, bufs [][]byte) {
+	c.store(ctx, keys, bufs, c.cfg.Expiration)
+}
+
+// StoreWithTTL stores the key in the cache with the given TTL instead of the configured expiration. TTLs longer
+// than 30 days are capped.
+func (c *Memcached) StoreWithTTL(ctx context.Context, keys []string, bufs [][]byte, ttl time.Duration) {
+	if ttl > maxMemcachedTTL {
+		ttl = maxMemcachedTTL
+	}
+	c.store(ctx, keys, bufs, ttl)
+}
+
+func (c *Memcached) store(ctx context.Context, keys []string, bufs [][]byte, ttl time.Duration) {
 	for i := range keys {
 		err := measureRequest(ctx, "Memcache.Put", c.requestDuration, memcacheStatusCode, func(_ context.Context) error {
 			item := memcache.Item{
 				Key:        keys[i],
 				Value:      bufs[i],
-				Expiration: int32(c.cfg.Expiration.Seconds()),
+				Expiration: int32(ttl.Seconds()),
 			}
 			return c.memcache.Set(&item)
 		})
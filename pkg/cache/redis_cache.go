@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
@@ -102,6 +103,14 @@ func (c *RedisCache) Store(ctx context.Context, keys []string, bufs [][]byte) {
 	}
 }
 
+// StoreWithTTL stores the keys in the cache with the given TTL instead of the configured expiration.
+func (c *RedisCache) StoreWithTTL(ctx context.Context, keys []string, bufs [][]byte, ttl time.Duration) {
+	err := c.redis.MSetWithTTL(ctx, keys, bufs, ttl)
+	if err != nil {
+		level.Error(c.logger).Log("msg", "failed to put to redis", "name", c.name, "err", err)
+	}
+}
+
 // Stop stops the redis client.
 func (c *RedisCache) Stop() {
 	_ = c.redis.Close()
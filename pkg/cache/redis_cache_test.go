@@ -64,3 +64,28 @@ func mockRedisCache() (*RedisCache, error) {
 	}
 	return NewRedisCache("mock", redisClient, nil, log.NewNopLogger()), nil
 }
+
+func TestRedisCacheStoreWithTTL(t *testing.T) {
+	redisServer, err := miniredis.Run()
+	require.NoError(t, err)
+	defer redisServer.Close()
+
+	c := NewRedisCache("mock", &RedisClient{
+		expiration: time.Minute,
+		timeout:    100 * time.Millisecond,
+		rdb: redis.NewUniversalClient(&redis.UniversalOptions{
+			Addrs: []string{redisServer.Addr()},
+		}),
+	}, nil, log.NewNopLogger())
+	defer c.Stop()
+
+	ctx := context.Background()
+
+	c.Store(ctx, []string{"default"}, [][]byte{[]byte("data")})
+	StoreWithTTL(ctx, c, []string{"ttl"}, [][]byte{[]byte("data")}, time.Hour)
+	StoreWithTTL(ctx, c, []string{"zero"}, [][]byte{[]byte("data")}, 0)
+
+	require.Equal(t, time.Minute, redisServer.TTL("default"))
+	require.Equal(t, time.Hour, redisServer.TTL("ttl"))
+	require.Equal(t, time.Minute, redisServer.TTL("zero"))
+}
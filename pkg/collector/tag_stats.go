@@ -0,0 +1,102 @@
+package collector
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// TagStat is the estimated cardinality of the values of a tag
+type TagStat struct {
+	Scope string `json:"scope"`
+	Name  string `json:"name"`
+	// DistinctValues is the number of distinct values of the tag. It's a lower bound if Capped is set.
+	DistinctValues int `json:"distinctValues"`
+	// Bytes is the total size of the distinct values.
+	Bytes int `json:"bytes"`
+	// Capped is set if the tag has more than the maximum number of values that are tracked per tag.
+	Capped bool `json:"capped"`
+}
+
+type tagStatsKey struct {
+	scope string
+	name  string
+}
+
+type tagValues struct {
+	values map[string]struct{}
+	bytes  int
+	capped bool
+}
+
+// TagStats collects the distinct values of tags to estimate their cardinality. It is safe for concurrent use.
+type TagStats struct {
+	mtx       sync.Mutex
+	tags      map[tagStatsKey]*tagValues
+	maxValues int
+}
+
+// NewTagStats tracks up to maxValues distinct values per tag. For ease of use, maxValues=0 is interpreted as
+// unlimited.
+func NewTagStats(maxValues int) *TagStats {
+	return &TagStats{
+		tags:      map[tagStatsKey]*tagValues{},
+		maxValues: maxValues,
+	}
+}
+
+// Collect records a value of the tag
+func (t *TagStats) Collect(scope, name, value string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	k := tagStatsKey{scope: scope, name: name}
+	tv, ok := t.tags[k]
+	if !ok {
+		tv = &tagValues{values: map[string]struct{}{}}
+		k.scope = strings.Clone(scope)
+		k.name = strings.Clone(name)
+		t.tags[k] = tv
+	}
+
+	if _, ok := tv.values[value]; ok {
+		return
+	}
+	if t.maxValues > 0 && len(tv.values) >= t.maxValues {
+		tv.capped = true
+		return
+	}
+
+	// Clone instead of referencing original
+	tv.values[strings.Clone(value)] = struct{}{}
+	tv.bytes += len(value)
+}
+
+// Stats returns the stats of all tags, ordered by the number of distinct values, highest first.
+func (t *TagStats) Stats() []TagStat {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	stats := make([]TagStat, 0, len(t.tags))
+	for k, tv := range t.tags {
+		stats = append(stats, TagStat{
+			Scope:          k.scope,
+			Name:           k.name,
+			DistinctValues: len(tv.values),
+			Bytes:          tv.bytes,
+			Capped:         tv.capped,
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].DistinctValues != stats[j].DistinctValues {
+			return stats[i].DistinctValues > stats[j].DistinctValues
+		}
+		if stats[i].Scope != stats[j].Scope {
+			return stats[i].Scope < stats[j].Scope
+		}
+		return stats[i].Name < stats[j].Name
+	})
+
+	return stats
+}
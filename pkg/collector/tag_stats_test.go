@@ -0,0 +1,38 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagStats(t *testing.T) {
+	s := NewTagStats(3)
+
+	s.Collect("span", "http.url", "/a")
+	s.Collect("span", "http.url", "/bb")
+	s.Collect("span", "http.url", "/a")
+	s.Collect("span", "http.url", "/ccc")
+	s.Collect("span", "http.url", "/dddd")
+	s.Collect("resource", "service.name", "foo")
+	s.Collect("span", "service.name", "foo")
+	s.Collect("span", "service.name", "bar")
+
+	require.Equal(t, []TagStat{
+		{Scope: "span", Name: "http.url", DistinctValues: 3, Bytes: 9, Capped: true},
+		{Scope: "span", Name: "service.name", DistinctValues: 2, Bytes: 6},
+		{Scope: "resource", Name: "service.name", DistinctValues: 1, Bytes: 3},
+	}, s.Stats())
+}
+
+func TestTagStatsUnlimited(t *testing.T) {
+	s := NewTagStats(0)
+
+	for _, v := range []string{"a", "b", "c", "d", "a"} {
+		s.Collect("span", "foo", v)
+	}
+
+	require.Equal(t, []TagStat{
+		{Scope: "span", Name: "foo", DistinctValues: 4, Bytes: 4},
+	}, s.Stats())
+}
@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/golang/protobuf/jsonpb" //nolint:all
 	"github.com/golang/protobuf/proto"  //nolint:all
@@ -24,7 +25,8 @@ import (
 )
 
 const (
-	orgIDHeader = "X-Scope-OrgID"
+	orgIDHeader         = "X-Scope-OrgID"
+	authorizationHeader = "Authorization"
 
 	QueryTraceEndpoint = "/api/traces"
 
@@ -40,13 +42,15 @@ type Client struct {
 	BaseURL string
 	OrgID   string
 	client  *http.Client
+	headers http.Header
 }
 
 func New(baseURL, orgID string) *Client {
 	return &Client{
 		BaseURL: baseURL,
 		OrgID:   orgID,
-		client:  http.DefaultClient,
+		client:  &http.Client{},
+		headers: http.Header{},
 	}
 }
 
@@ -60,6 +64,21 @@ func (c *Client) WithTransport(t http.RoundTripper) {
 	c.client.Transport = t
 }
 
+// WithHeader sets a header on all requests sent by the client.
+func (c *Client) WithHeader(key, value string) {
+	c.headers.Set(key, value)
+}
+
+// WithBasicAuth authenticates all requests sent by the client with basic auth.
+func (c *Client) WithBasicAuth(username, password string) {
+	c.WithHeader(authorizationHeader, basicAuth(username, password))
+}
+
+// WithBearerToken authenticates all requests sent by the client with a bearer token.
+func (c *Client) WithBearerToken(token string) {
+	c.WithHeader(authorizationHeader, bearerToken(token))
+}
+
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
 	return c.client.Do(req)
 }
@@ -102,11 +121,14 @@ func (c *Client) getFor(url string, m proto.Message) (*http.Response, error) {
 
 // doRequest sends the given request, it injects X-Scope-OrgID and handles bad status codes.
 func (c *Client) doRequest(req *http.Request) (*http.Response, []byte, error) {
+	for k, v := range c.headers {
+		req.Header[k] = v
+	}
 	if len(c.OrgID) > 0 {
 		req.Header.Set(orgIDHeader, c.OrgID)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error querying Tempo %v", err)
 	}
@@ -235,6 +257,38 @@ func (c *Client) QueryTrace(id string) (*tempopb.Trace, error) {
 	return m, nil
 }
 
+// SearchWithRequest calls the /api/search endpoint with all parameters of the search request.
+func (c *Client) SearchWithRequest(searchReq *tempopb.SearchRequest) (*tempopb.SearchResponse, error) {
+	u, err := c.buildSearchRequestURL(tempo_api.PathSearch, searchReq)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &tempopb.SearchResponse{}
+	_, err = c.getFor(u, m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// SearchV2 calls the /api/v2/search endpoint, which returns the search response in the v2 response envelope.
+func (c *Client) SearchV2(searchReq *tempopb.SearchRequest) (*tempopb.QueryResponseV2, error) {
+	u, err := c.buildSearchRequestURL(tempo_api.PathSearchV2, searchReq)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &tempopb.QueryResponseV2{}
+	_, err = c.getFor(u, m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
 func (c *Client) SearchTraceQL(query string) (*tempopb.SearchResponse, error) {
 	m := &tempopb.SearchResponse{}
 	_, err := c.getFor(c.buildSearchQueryURL("q", query, 0, 0), m)
@@ -275,6 +329,67 @@ func (c *Client) MetricsSummary(query string, groupBy string, start int64, end i
 	return m, nil
 }
 
+// MetricsQueryRange calls the /api/metrics/query_range endpoint. Start and end of the request are unix epoch
+// timestamps in nanoseconds and step is a duration in nanoseconds. If step is 0 Tempo picks a step based on the
+// range.
+func (c *Client) MetricsQueryRange(queryRangeReq *tempopb.QueryRangeRequest) (*tempopb.QueryRangeResponse, error) {
+	m := &tempopb.QueryRangeResponse{}
+	_, err := c.getFor(c.buildQueryRangeURL(tempo_api.PathMetricsQueryRange, queryRangeReq), m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// MetricsQueryRangeV2 calls the /api/v2/metrics/query_range endpoint, which returns the query range response in
+// the v2 response envelope.
+func (c *Client) MetricsQueryRangeV2(queryRangeReq *tempopb.QueryRangeRequest) (*tempopb.QueryResponseV2, error) {
+	m := &tempopb.QueryResponseV2{}
+	_, err := c.getFor(c.buildQueryRangeURL(tempo_api.PathMetricsQueryRangeV2, queryRangeReq), m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (c *Client) buildSearchRequestURL(path string, searchReq *tempopb.SearchRequest) (string, error) {
+	joinURL, err := url.Parse(c.BaseURL + path)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := tempo_api.BuildSearchRequest(&http.Request{URL: joinURL}, searchReq)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprint(req.URL), nil
+}
+
+func (c *Client) buildQueryRangeURL(path string, queryRangeReq *tempopb.QueryRangeRequest) string {
+	joinURL, _ := url.Parse(c.BaseURL + path + "?")
+	q := joinURL.Query()
+	q.Set("q", queryRangeReq.Query)
+	if queryRangeReq.Start != 0 && queryRangeReq.End != 0 {
+		q.Set("start", strconv.FormatUint(queryRangeReq.Start, 10))
+		q.Set("end", strconv.FormatUint(queryRangeReq.End, 10))
+	}
+	if queryRangeReq.Step != 0 {
+		q.Set("step", time.Duration(queryRangeReq.Step).String())
+	}
+	if queryRangeReq.Exemplars != 0 {
+		q.Set("exemplars", strconv.FormatUint(uint64(queryRangeReq.Exemplars), 10))
+	}
+	if queryRangeReq.ExemplarPolicy != "" {
+		q.Set("exemplarPolicy", queryRangeReq.ExemplarPolicy)
+	}
+	joinURL.RawQuery = q.Encode()
+
+	return fmt.Sprint(joinURL)
+}
+
 func (c *Client) buildSearchQueryURL(queryType string, query string, start int64, end int64) string {
 	joinURL, _ := url.Parse(c.BaseURL + "/api/search?")
 	q := joinURL.Query()
@@ -0,0 +1,182 @@
+package httpclient
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+func TestClientHeaders(t *testing.T) {
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		_, _ = w.Write([]byte(`{"tagNames":["foo"]}`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "tenant")
+	c.WithHeader("X-Custom", "value")
+	c.WithBasicAuth("user", "pass")
+
+	_, err := c.SearchTags()
+	require.NoError(t, err)
+	require.Equal(t, "tenant", header.Get(orgIDHeader))
+	require.Equal(t, "value", header.Get("X-Custom"))
+	username, password, ok := (&http.Request{Header: header}).BasicAuth()
+	require.True(t, ok)
+	require.Equal(t, "user", username)
+	require.Equal(t, "pass", password)
+
+	c.WithBearerToken("token")
+	_, err = c.SearchTags()
+	require.NoError(t, err)
+	require.Equal(t, "Bearer token", header.Get(authorizationHeader))
+}
+
+func TestClientSearchWithRequest(t *testing.T) {
+	expected := &tempopb.SearchResponse{
+		Traces: []*tempopb.TraceSearchMetadata{{TraceID: "1234", RootServiceName: "svc"}},
+	}
+
+	var searchReq *tempopb.SearchRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, api.PathSearch, r.URL.Path)
+
+		var err error
+		searchReq, err = api.ParseSearchRequest(r)
+		require.NoError(t, err)
+		require.NoError(t, (&jsonpb.Marshaler{}).Marshal(w, expected))
+	}))
+	defer srv.Close()
+
+	req := &tempopb.SearchRequest{
+		Query:           "{ .foo = `bar` }",
+		Start:           100,
+		End:             200,
+		Limit:           10,
+		SpansPerSpanSet: 5,
+	}
+	actual, err := New(srv.URL, "").SearchWithRequest(req)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	require.Equal(t, req.Query, searchReq.Query)
+	require.Equal(t, req.Start, searchReq.Start)
+	require.Equal(t, req.End, searchReq.End)
+	require.Equal(t, req.Limit, searchReq.Limit)
+	require.Equal(t, req.SpansPerSpanSet, searchReq.SpansPerSpanSet)
+}
+
+func TestClientMetricsQueryRange(t *testing.T) {
+	expected := &tempopb.QueryRangeResponse{
+		Series: []*tempopb.TimeSeries{{PromLabels: "{}", Samples: []tempopb.Sample{{TimestampMs: 1000, Value: 1}}}},
+	}
+
+	var queryRangeReq *tempopb.QueryRangeRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		queryRangeReq, err = api.ParseQueryRangeRequest(r)
+		require.NoError(t, err)
+
+		switch r.URL.Path {
+		case api.PathMetricsQueryRange:
+			require.NoError(t, (&jsonpb.Marshaler{}).Marshal(w, expected))
+		case api.PathMetricsQueryRangeV2:
+			require.NoError(t, (&jsonpb.Marshaler{}).Marshal(w, &tempopb.QueryResponseV2{
+				Status: "success",
+				Data:   &tempopb.QueryResponseV2_QueryRange{QueryRange: expected},
+			}))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	req := &tempopb.QueryRangeRequest{
+		Query:     "{} | rate()",
+		Start:     uint64(time.Unix(100, 0).UnixNano()),
+		End:       uint64(time.Unix(200, 0).UnixNano()),
+		Step:      uint64(10 * time.Second),
+		Exemplars: 5,
+	}
+	c := New(srv.URL, "")
+
+	actual, err := c.MetricsQueryRange(req)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	require.Equal(t, req.Query, queryRangeReq.Query)
+	require.Equal(t, req.Start, queryRangeReq.Start)
+	require.Equal(t, req.End, queryRangeReq.End)
+	require.Equal(t, req.Step, queryRangeReq.Step)
+	require.Equal(t, req.Exemplars, queryRangeReq.Exemplars)
+
+	actualV2, err := c.MetricsQueryRangeV2(req)
+	require.NoError(t, err)
+	require.Equal(t, "success", actualV2.Status)
+	require.Equal(t, expected, actualV2.GetQueryRange())
+}
+
+func TestStreamingClient(t *testing.T) {
+	listener := bufconn.Listen(1024 * 1024)
+	serv := grpc.NewServer()
+	defer serv.Stop()
+
+	mock := &mockStreamingQuerier{}
+	tempopb.RegisterStreamingQuerierServer(serv, mock)
+	go func() {
+		_ = serv.Serve(listener)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	c := NewStreaming(conn, "tenant")
+	c.WithBearerToken("token")
+
+	var inspected []uint32
+	err = c.Search(context.Background(), &tempopb.SearchRequest{Query: "{}"}, func(resp *tempopb.SearchResponse) error {
+		inspected = append(inspected, resp.Metrics.InspectedTraces)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []uint32{1, 2, 3}, inspected)
+	require.Equal(t, "tenant", mock.orgID)
+	require.Equal(t, []string{"Bearer token"}, mock.md.Get(authorizationHeader))
+}
+
+type mockStreamingQuerier struct {
+	tempopb.UnimplementedStreamingQuerierServer
+
+	orgID string
+	md    metadata.MD
+}
+
+func (m *mockStreamingQuerier) Search(_ *tempopb.SearchRequest, srv tempopb.StreamingQuerier_SearchServer) error {
+	m.md, _ = metadata.FromIncomingContext(srv.Context())
+	m.orgID = m.md.Get(user.OrgIDHeaderName)[0]
+
+	for i := 1; i <= 3; i++ {
+		if err := srv.Send(&tempopb.SearchResponse{Metrics: &tempopb.SearchMetrics{InspectedTraces: uint32(i)}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/grafana/dskit/user"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+// StreamingClient is a client to the streaming GRPC API of the Tempo query frontend. The responses of a query are
+// passed to a callback as they are received. Each response contains the results of the query so far.
+type StreamingClient struct {
+	OrgID    string
+	client   tempopb.StreamingQuerierClient
+	metadata metadata.MD
+}
+
+// NewStreaming returns a streaming client that sends its queries over the given connection.
+func NewStreaming(conn *grpc.ClientConn, orgID string) *StreamingClient {
+	return &StreamingClient{
+		OrgID:    orgID,
+		client:   tempopb.NewStreamingQuerierClient(conn),
+		metadata: metadata.MD{},
+	}
+}
+
+// WithHeader sets a header on all queries sent by the client.
+func (c *StreamingClient) WithHeader(key, value string) {
+	c.metadata.Set(key, value)
+}
+
+// WithBasicAuth authenticates all queries sent by the client with basic auth.
+func (c *StreamingClient) WithBasicAuth(username, password string) {
+	c.WithHeader(authorizationHeader, basicAuth(username, password))
+}
+
+// WithBearerToken authenticates all queries sent by the client with a bearer token.
+func (c *StreamingClient) WithBearerToken(token string) {
+	c.WithHeader(authorizationHeader, bearerToken(token))
+}
+
+func (c *StreamingClient) Search(ctx context.Context, req *tempopb.SearchRequest, fn func(*tempopb.SearchResponse) error) error {
+	ctx, err := c.outgoingContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	stream, err := c.client.Search(ctx, req)
+	if err != nil {
+		return err
+	}
+	return recvAll[tempopb.SearchResponse](stream, fn)
+}
+
+func (c *StreamingClient) SearchTagsV2(ctx context.Context, req *tempopb.SearchTagsRequest, fn func(*tempopb.SearchTagsV2Response) error) error {
+	ctx, err := c.outgoingContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	stream, err := c.client.SearchTagsV2(ctx, req)
+	if err != nil {
+		return err
+	}
+	return recvAll[tempopb.SearchTagsV2Response](stream, fn)
+}
+
+func (c *StreamingClient) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTagValuesRequest, fn func(*tempopb.SearchTagValuesV2Response) error) error {
+	ctx, err := c.outgoingContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	stream, err := c.client.SearchTagValuesV2(ctx, req)
+	if err != nil {
+		return err
+	}
+	return recvAll[tempopb.SearchTagValuesV2Response](stream, fn)
+}
+
+func (c *StreamingClient) MetricsQueryRange(ctx context.Context, req *tempopb.QueryRangeRequest, fn func(*tempopb.QueryRangeResponse) error) error {
+	ctx, err := c.outgoingContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	stream, err := c.client.MetricsQueryRange(ctx, req)
+	if err != nil {
+		return err
+	}
+	return recvAll[tempopb.QueryRangeResponse](stream, fn)
+}
+
+// outgoingContext injects the org ID and the configured headers into the GRPC metadata.
+func (c *StreamingClient) outgoingContext(ctx context.Context) (context.Context, error) {
+	if len(c.OrgID) > 0 {
+		var err error
+		ctx, err = user.InjectIntoGRPCRequest(user.InjectOrgID(ctx, c.OrgID))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for k, v := range c.metadata {
+		ctx = metadata.AppendToOutgoingContext(ctx, k, strings.Join(v, ","))
+	}
+	return ctx, nil
+}
+
+type receiver[T any] interface {
+	Recv() (*T, error)
+}
+
+// recvAll passes all responses of the stream to fn until the stream ends or fn returns an error.
+func recvAll[T any](stream receiver[T], fn func(*T) error) error {
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := fn(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func basicAuth(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+func bearerToken(token string) string {
+	return "Bearer " + token
+}
@@ -795,6 +795,7 @@ type SyncIterator struct {
 	filter     Predicate
 
 	// Status
+	ctx             context.Context
 	span            opentracing.Span
 	curr            RowNumber
 	currRowGroup    pq.RowGroup
@@ -848,6 +849,7 @@ func NewSyncIterator(ctx context.Context, rgs []pq.RowGroup, column int, columnN
 
 	// Create the iterator
 	i := &SyncIterator{
+		ctx:        ctx,
 		span:       span,
 		column:     column,
 		columnName: columnName,
@@ -996,6 +998,12 @@ func (c *SyncIterator) seekPages(seekTo RowNumber, definitionLevel int) (done bo
 			}*/
 
 		for c.currPage == nil {
+			// Check for cancellation or an expired deadline before reading each page
+			if err := c.ctx.Err(); err != nil {
+				c.closeCurrRowGroup()
+				return true, err
+			}
+
 			pg, err := c.currChunk.NextPage()
 			if pg == nil || err != nil {
 				// No more pages in this column chunk,
@@ -1124,6 +1132,11 @@ func (c *SyncIterator) next() (RowNumber, *pq.Value, error) {
 		}
 
 		if c.currPage == nil {
+			// Check for cancellation or an expired deadline before reading each page
+			if err := c.ctx.Err(); err != nil {
+				return EmptyRowNumber(), nil, err
+			}
+
 			pg, err := c.currChunk.NextPage()
 			if pg == nil || errors.Is(err, io.EOF) {
 				// This row group is exhausted
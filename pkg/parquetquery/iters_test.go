@@ -297,6 +297,33 @@ func TestColumnIteratorExitEarly(t *testing.T) {
 		require.ErrorContains(t, err, "context canceled")
 	})
 
+	t.Run("syncCancelledEarly", func(t *testing.T) {
+		// Cancel before iterating
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+		iter := NewSyncIterator(ctx, pf.RowGroups(), idx, "", readSize, nil, "A")
+		count, err := readIter(iter)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, 0, count)
+	})
+
+	t.Run("syncCancelledPartial", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		iter := NewSyncIterator(ctx, pf.RowGroups(), idx, "", readSize, nil, "A")
+
+		// Read some results
+		_, err := iter.Next()
+		require.NoError(t, err)
+
+		// Then cancel
+		cancel()
+
+		// The current page is drained and the next one is never read
+		res2, err := readIter(iter)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Less(t, 1+res2, count)
+	})
+
 	t.Run("closedEarly", func(t *testing.T) {
 		// Close before iterating
 		iter := NewColumnIterator(context.TODO(), pf.RowGroups(), idx, "", readSize, nil, "A")
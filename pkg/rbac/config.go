@@ -0,0 +1,83 @@
+package rbac
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"regexp"
+
+	"github.com/grafana/tempo/pkg/tlsauth"
+)
+
+const (
+	// RoleRead can call the query APIs.
+	RoleRead = "read"
+	// RoleAdmin can call the query APIs and the admin APIs, such as flush, shutdown, the overrides API and the
+	// delete APIs.
+	RoleAdmin = "admin"
+)
+
+// Config maps the identity of HTTP requests to a role that limits the APIs they can call. Endpoints that are
+// neither query nor admin APIs, such as /ready and /metrics, are not limited.
+type Config struct {
+	Enabled bool `yaml:"enabled"`
+	// IdentityHeader is the header with the identity of requests authenticated by a proxy in front of Tempo. The
+	// proxy must overwrite the header of incoming requests. Empty disables the header.
+	IdentityHeader string `yaml:"identity_header"`
+	// CertificateSource is the field of the verified TLS client certificate used as identity of requests. It takes
+	// precedence over IdentityHeader. Empty disables certificates.
+	CertificateSource string `yaml:"certificate_source"`
+	// Identities are checked in order and the first one that matches the identity of a request sets its role.
+	Identities []IdentityConfig `yaml:"identities"`
+	// DefaultRole is the role of requests that match no identity. Empty denies them access to the query and admin
+	// APIs.
+	DefaultRole string `yaml:"default_role"`
+}
+
+// IdentityConfig assigns Role to the identities that match Regex.
+type IdentityConfig struct {
+	Regex string `yaml:"regex"`
+	Role  string `yaml:"role"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+"rbac.enabled", false, "Limit the APIs HTTP requests can call by the role of their identity.")
+}
+
+func (cfg *Config) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.IdentityHeader == "" && cfg.CertificateSource == "" {
+		return errors.New("rbac requires an identity_header or a certificate_source")
+	}
+	switch cfg.CertificateSource {
+	case "", tlsauth.SourceDNSSAN, tlsauth.SourceURISAN, tlsauth.SourceEmailSAN, tlsauth.SourceCommonName:
+	default:
+		return fmt.Errorf("unsupported certificate_source %q", cfg.CertificateSource)
+	}
+	if cfg.DefaultRole != "" && !validRole(cfg.DefaultRole) {
+		return fmt.Errorf("unsupported default_role %q", cfg.DefaultRole)
+	}
+	for i, id := range cfg.Identities {
+		if !validRole(id.Role) {
+			return fmt.Errorf("identity %d: unsupported role %q", i, id.Role)
+		}
+		if id.Regex == "" {
+			return fmt.Errorf("identity %d: regex is required", i)
+		}
+		if _, err := id.compile(); err != nil {
+			return fmt.Errorf("identity %d: invalid regex: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// compile anchors the regex so it has to match the whole identity
+func (id IdentityConfig) compile() (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + id.Regex + ")$")
+}
+
+func validRole(role string) bool {
+	return role == RoleRead || role == RoleAdmin
+}
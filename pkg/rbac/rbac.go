@@ -0,0 +1,173 @@
+package rbac
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/dskit/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/tempo/pkg/tlsauth"
+)
+
+type identity struct {
+	regex *regexp.Regexp
+	role  string
+}
+
+// Authorizer resolves the role of HTTP requests from their identity.
+type Authorizer struct {
+	identityHeader    string
+	certificateSource string
+	identities        []identity
+	defaultRole       string
+}
+
+func New(cfg Config) (*Authorizer, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	a := &Authorizer{
+		identityHeader:    cfg.IdentityHeader,
+		certificateSource: cfg.CertificateSource,
+		defaultRole:       cfg.DefaultRole,
+	}
+	for _, id := range cfg.Identities {
+		regex, err := id.compile()
+		if err != nil {
+			return nil, err
+		}
+		a.identities = append(a.identities, identity{regex: regex, role: id.Role})
+	}
+	return a, nil
+}
+
+// Identities returns the identities of the request. The values of the verified client certificate come before the
+// identity header.
+func (a *Authorizer) Identities(r *http.Request) []string {
+	var header string
+	if a.identityHeader != "" {
+		header = r.Header.Get(a.identityHeader)
+	}
+	return a.identitiesOf(r.TLS, header)
+}
+
+// IdentitiesFromContext returns the identities of a gRPC request. The identity header is read from the metadata
+// of the request.
+func (a *Authorizer) IdentitiesFromContext(ctx context.Context) []string {
+	var state *tls.ConnectionState
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			state = &info.State
+		}
+	}
+
+	var header string
+	if a.identityHeader != "" {
+		if values := metadata.ValueFromIncomingContext(ctx, strings.ToLower(a.identityHeader)); len(values) > 0 {
+			header = values[0]
+		}
+	}
+	return a.identitiesOf(state, header)
+}
+
+func (a *Authorizer) identitiesOf(state *tls.ConnectionState, header string) []string {
+	var ids []string
+	if a.certificateSource != "" && state != nil && len(state.VerifiedChains) > 0 && len(state.VerifiedChains[0]) > 0 {
+		ids = append(ids, tlsauth.CertificateValues(state.VerifiedChains[0][0], a.certificateSource)...)
+	}
+	if header != "" {
+		ids = append(ids, header)
+	}
+	return ids
+}
+
+// Role returns the role of the first identity config that matches one of the identities, or the default role.
+func (a *Authorizer) Role(ids []string) string {
+	for _, cfg := range a.identities {
+		for _, id := range ids {
+			if cfg.regex.MatchString(id) {
+				return cfg.role
+			}
+		}
+	}
+	return a.defaultRole
+}
+
+// Middleware rejects requests whose role doesn't grant the given role. Internal requests are not checked.
+func (a *Authorizer) Middleware(required string) middleware.Interface {
+	return middleware.Func(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isInternal(r.Context()) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ids := a.Identities(r)
+			if role := a.Role(ids); !grants(role, required) {
+				http.Error(w, forbiddenError(ids, required).Error(), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+}
+
+// StreamServerInterceptor rejects the gRPC streams of the methods with the given prefix whose role doesn't grant
+// the given role. Other methods, e.g. the internal APIs between Tempo components, are not checked.
+func (a *Authorizer) StreamServerInterceptor(methodPrefix, required string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !strings.HasPrefix(info.FullMethod, methodPrefix) {
+			return handler(srv, ss)
+		}
+
+		ids := a.IdentitiesFromContext(ss.Context())
+		if role := a.Role(ids); !grants(role, required) {
+			return status.Error(codes.PermissionDenied, forbiddenError(ids, required).Error())
+		}
+		return handler(srv, ss)
+	}
+}
+
+type internalKey struct{}
+
+// InternalHandler marks the requests it passes to next as internal. Internal requests were already authorized by
+// the component that sent them, e.g. the queries the query frontend sends to the workers of the queriers. They
+// don't carry the identity of the original request, so the role can't be resolved again.
+func InternalHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), internalKey{}, true)))
+	})
+}
+
+func isInternal(ctx context.Context) bool {
+	internal, _ := ctx.Value(internalKey{}).(bool)
+	return internal
+}
+
+func grants(role, required string) bool {
+	switch role {
+	case RoleAdmin:
+		return true
+	case RoleRead:
+		return required == RoleRead
+	}
+	return false
+}
+
+func forbiddenError(ids []string, required string) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("the %s role is required to access this API, but the request has no identity", required)
+	}
+	return fmt.Errorf("the %s role is required to access this API, but is not granted to %q", required, ids)
+}
@@ -0,0 +1,243 @@
+package rbac
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/dskit/httpgrpc"
+	httpgrpc_server "github.com/grafana/dskit/httpgrpc/server"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/tempo/pkg/tlsauth"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    Config
+		expErr bool
+	}{
+		{
+			name: "disabled",
+			cfg:  Config{},
+		},
+		{
+			name:   "no identity source",
+			cfg:    Config{Enabled: true},
+			expErr: true,
+		},
+		{
+			name: "valid",
+			cfg: Config{Enabled: true, IdentityHeader: "X-Forwarded-User", CertificateSource: tlsauth.SourceCommonName, DefaultRole: RoleRead, Identities: []IdentityConfig{
+				{Regex: "ops-.*", Role: RoleAdmin},
+			}},
+		},
+		{
+			name:   "invalid certificate source",
+			cfg:    Config{Enabled: true, CertificateSource: "ip_san"},
+			expErr: true,
+		},
+		{
+			name:   "invalid default role",
+			cfg:    Config{Enabled: true, IdentityHeader: "X-Forwarded-User", DefaultRole: "write"},
+			expErr: true,
+		},
+		{
+			name:   "invalid role",
+			cfg:    Config{Enabled: true, IdentityHeader: "X-Forwarded-User", Identities: []IdentityConfig{{Regex: ".*"}}},
+			expErr: true,
+		},
+		{
+			name:   "invalid regex",
+			cfg:    Config{Enabled: true, IdentityHeader: "X-Forwarded-User", Identities: []IdentityConfig{{Regex: "(", Role: RoleRead}}},
+			expErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	a, err := New(Config{
+		Enabled:           true,
+		IdentityHeader:    "X-Forwarded-User",
+		CertificateSource: tlsauth.SourceCommonName,
+		Identities: []IdentityConfig{
+			{Regex: "ops-.*", Role: RoleAdmin},
+			{Regex: "grafana|dev-.*", Role: RoleRead},
+		},
+	})
+	require.NoError(t, err)
+
+	withCert := func(commonName string) *tls.ConnectionState {
+		return &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: commonName}}}}}
+	}
+
+	tests := []struct {
+		name     string
+		header   string
+		tls      *tls.ConnectionState
+		expRead  int
+		expAdmin int
+	}{
+		{
+			name:     "no identity",
+			expRead:  http.StatusForbidden,
+			expAdmin: http.StatusForbidden,
+		},
+		{
+			name:     "unknown identity",
+			header:   "alice",
+			expRead:  http.StatusForbidden,
+			expAdmin: http.StatusForbidden,
+		},
+		{
+			name:     "read",
+			header:   "grafana",
+			expRead:  http.StatusOK,
+			expAdmin: http.StatusForbidden,
+		},
+		{
+			name:     "admin",
+			header:   "ops-bob",
+			expRead:  http.StatusOK,
+			expAdmin: http.StatusOK,
+		},
+		{
+			name:     "regex must match completely",
+			header:   "grafana-ops-bob",
+			expRead:  http.StatusForbidden,
+			expAdmin: http.StatusForbidden,
+		},
+		{
+			name:     "certificate",
+			tls:      withCert("dev-carol"),
+			expRead:  http.StatusOK,
+			expAdmin: http.StatusForbidden,
+		},
+		{
+			name:     "first matching identity config wins",
+			header:   "grafana",
+			tls:      withCert("ops-dave"),
+			expRead:  http.StatusOK,
+			expAdmin: http.StatusOK,
+		},
+	}
+
+	handler := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for role, expected := range map[string]int{RoleRead: tc.expRead, RoleAdmin: tc.expAdmin} {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.TLS = tc.tls
+				if tc.header != "" {
+					req.Header.Set("X-Forwarded-User", tc.header)
+				}
+
+				rec := httptest.NewRecorder()
+				a.Middleware(role).Wrap(handler).ServeHTTP(rec, req)
+				require.Equal(t, expected, rec.Code, role)
+			}
+		})
+	}
+}
+
+func TestDefaultRole(t *testing.T) {
+	a, err := New(Config{Enabled: true, IdentityHeader: "X-Forwarded-User", DefaultRole: RoleRead})
+	require.NoError(t, err)
+
+	require.Equal(t, RoleRead, a.Role(nil))
+	require.True(t, grants(a.Role([]string{"anyone"}), RoleRead))
+	require.False(t, grants(a.Role([]string{"anyone"}), RoleAdmin))
+}
+
+func TestInternalHandler(t *testing.T) {
+	a, err := New(Config{Enabled: true, IdentityHeader: "X-Forwarded-User"})
+	require.NoError(t, err)
+
+	router := http.NewServeMux()
+	router.Handle("/querier/api/traces/", a.Middleware(RoleRead).Wrap(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})))
+
+	// queries from the query frontend reach the querier through its worker without an identity
+	req := &httpgrpc.HTTPRequest{Method: http.MethodGet, Url: "/querier/api/traces/1234"}
+
+	resp, err := httpgrpc_server.NewServer(InternalHandler(router)).Handle(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, int32(http.StatusOK), resp.Code)
+
+	// requests that aren't marked internal are checked
+	resp, err = httpgrpc_server.NewServer(router).Handle(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, int32(http.StatusForbidden), resp.Code)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/querier/api/traces/1234", nil))
+	require.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	a, err := New(Config{
+		Enabled:           true,
+		IdentityHeader:    "X-Forwarded-User",
+		CertificateSource: tlsauth.SourceCommonName,
+		Identities: []IdentityConfig{
+			{Regex: "ops-.*", Role: RoleAdmin},
+			{Regex: "grafana", Role: RoleRead},
+		},
+	})
+	require.NoError(t, err)
+
+	interceptor := a.StreamServerInterceptor("/tempopb.StreamingQuerier/", RoleRead)
+	handler := func(interface{}, grpc.ServerStream) error { return nil }
+
+	call := func(ctx context.Context, method string) error {
+		return interceptor(nil, streamWithContext{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: method}, handler)
+	}
+
+	// header
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-forwarded-user", "grafana"))
+	require.NoError(t, call(ctx, "/tempopb.StreamingQuerier/Search"))
+
+	// certificate
+	ctx = peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "ops-bob"}}}},
+	}}})
+	require.NoError(t, call(ctx, "/tempopb.StreamingQuerier/Search"))
+
+	// no identity
+	err = call(context.Background(), "/tempopb.StreamingQuerier/Search")
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// other methods are not checked
+	require.NoError(t, call(context.Background(), "/tempopb.Pusher/PushBytesV2"))
+}
+
+type streamWithContext struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s streamWithContext) Context() context.Context {
+	return s.ctx
+}
@@ -114,6 +114,8 @@ type TraceByIDRequest struct {
 	BlockStart string `protobuf:"bytes,2,opt,name=blockStart,proto3" json:"blockStart,omitempty"`
 	BlockEnd   string `protobuf:"bytes,3,opt,name=blockEnd,proto3" json:"blockEnd,omitempty"`
 	QueryMode  string `protobuf:"bytes,5,opt,name=queryMode,proto3" json:"queryMode,omitempty"`
+	// optional TraceQL query. if set only matching spans and their ancestors are returned
+	SpanFilter string `protobuf:"bytes,6,opt,name=spanFilter,proto3" json:"spanFilter,omitempty"`
 }
 
 func (m *TraceByIDRequest) Reset()         { *m = TraceByIDRequest{} }
@@ -177,6 +179,13 @@ func (m *TraceByIDRequest) GetQueryMode() string {
 	return ""
 }
 
+func (m *TraceByIDRequest) GetSpanFilter() string {
+	if m != nil {
+		return m.SpanFilter
+	}
+	return ""
+}
+
 type TraceByIDResponse struct {
 	Trace   *Trace            `protobuf:"bytes,1,opt,name=trace,proto3" json:"trace,omitempty"`
 	Metrics *TraceByIDMetrics `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
@@ -566,6 +575,11 @@ func (m *DedicatedColumn) GetType() DedicatedColumn_Type {
 type SearchResponse struct {
 	Traces  []*TraceSearchMetadata `protobuf:"bytes,1,rep,name=traces,proto3" json:"traces,omitempty"`
 	Metrics *SearchMetrics         `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	// partial is set when one or more shards failed to complete and the results are incomplete
+	Partial     bool                `protobuf:"varint,3,opt,name=partial,proto3" json:"partial,omitempty"`
+	ShardErrors []*SearchShardError `protobuf:"bytes,4,rep,name=shardErrors,proto3" json:"shardErrors,omitempty"`
+	// groups counts the returned traces and matched spans per group when the query groups spansets with by()
+	Groups []*SearchGroup `protobuf:"bytes,5,rep,name=groups,proto3" json:"groups,omitempty"`
 }
 
 func (m *SearchResponse) Reset()         { *m = SearchResponse{} }
@@ -615,6 +629,156 @@ func (m *SearchResponse) GetMetrics() *SearchMetrics {
 	return nil
 }
 
+func (m *SearchResponse) GetPartial() bool {
+	if m != nil {
+		return m.Partial
+	}
+	return false
+}
+
+func (m *SearchResponse) GetShardErrors() []*SearchShardError {
+	if m != nil {
+		return m.ShardErrors
+	}
+	return nil
+}
+
+func (m *SearchResponse) GetGroups() []*SearchGroup {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+type SearchGroup struct {
+	// attributes are the by() attributes and values identifying the group
+	Attributes []*v1.KeyValue `protobuf:"bytes,1,rep,name=attributes,proto3" json:"attributes,omitempty"`
+	Traces     uint32         `protobuf:"varint,2,opt,name=traces,proto3" json:"traces,omitempty"`
+	Matched    uint32         `protobuf:"varint,3,opt,name=matched,proto3" json:"matched,omitempty"`
+}
+
+func (m *SearchGroup) Reset()         { *m = SearchGroup{} }
+func (m *SearchGroup) String() string { return proto.CompactTextString(m) }
+func (*SearchGroup) ProtoMessage()    {}
+func (*SearchGroup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{7}
+}
+func (m *SearchGroup) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SearchGroup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SearchGroup.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SearchGroup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchGroup.Merge(m, src)
+}
+func (m *SearchGroup) XXX_Size() int {
+	return m.Size()
+}
+func (m *SearchGroup) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchGroup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchGroup proto.InternalMessageInfo
+
+func (m *SearchGroup) GetAttributes() []*v1.KeyValue {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+func (m *SearchGroup) GetTraces() uint32 {
+	if m != nil {
+		return m.Traces
+	}
+	return 0
+}
+
+func (m *SearchGroup) GetMatched() uint32 {
+	if m != nil {
+		return m.Matched
+	}
+	return 0
+}
+
+type SearchShardError struct {
+	BlockID       string `protobuf:"bytes,1,opt,name=blockID,proto3" json:"blockID,omitempty"`
+	StartPage     uint32 `protobuf:"varint,2,opt,name=startPage,proto3" json:"startPage,omitempty"`
+	PagesToSearch uint32 `protobuf:"varint,3,opt,name=pagesToSearch,proto3" json:"pagesToSearch,omitempty"`
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *SearchShardError) Reset()         { *m = SearchShardError{} }
+func (m *SearchShardError) String() string { return proto.CompactTextString(m) }
+func (*SearchShardError) ProtoMessage()    {}
+func (*SearchShardError) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{8}
+}
+func (m *SearchShardError) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SearchShardError) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SearchShardError.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SearchShardError) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchShardError.Merge(m, src)
+}
+func (m *SearchShardError) XXX_Size() int {
+	return m.Size()
+}
+func (m *SearchShardError) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchShardError.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchShardError proto.InternalMessageInfo
+
+func (m *SearchShardError) GetBlockID() string {
+	if m != nil {
+		return m.BlockID
+	}
+	return ""
+}
+
+func (m *SearchShardError) GetStartPage() uint32 {
+	if m != nil {
+		return m.StartPage
+	}
+	return 0
+}
+
+func (m *SearchShardError) GetPagesToSearch() uint32 {
+	if m != nil {
+		return m.PagesToSearch
+	}
+	return 0
+}
+
+func (m *SearchShardError) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 type TraceSearchMetadata struct {
 	TraceID           string                   `protobuf:"bytes,1,opt,name=traceID,proto3" json:"traceID,omitempty"`
 	RootServiceName   string                   `protobuf:"bytes,2,opt,name=rootServiceName,proto3" json:"rootServiceName,omitempty"`
@@ -630,7 +794,7 @@ func (m *TraceSearchMetadata) Reset()         { *m = TraceSearchMetadata{} }
 func (m *TraceSearchMetadata) String() string { return proto.CompactTextString(m) }
 func (*TraceSearchMetadata) ProtoMessage()    {}
 func (*TraceSearchMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{7}
+	return fileDescriptor_f22805646f4f62b6, []int{9}
 }
 func (m *TraceSearchMetadata) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -724,7 +888,7 @@ func (m *ServiceStats) Reset()         { *m = ServiceStats{} }
 func (m *ServiceStats) String() string { return proto.CompactTextString(m) }
 func (*ServiceStats) ProtoMessage()    {}
 func (*ServiceStats) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{8}
+	return fileDescriptor_f22805646f4f62b6, []int{10}
 }
 func (m *ServiceStats) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -777,7 +941,7 @@ func (m *SpanSet) Reset()         { *m = SpanSet{} }
 func (m *SpanSet) String() string { return proto.CompactTextString(m) }
 func (*SpanSet) ProtoMessage()    {}
 func (*SpanSet) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{9}
+	return fileDescriptor_f22805646f4f62b6, []int{11}
 }
 func (m *SpanSet) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -839,7 +1003,7 @@ func (m *Span) Reset()         { *m = Span{} }
 func (m *Span) String() string { return proto.CompactTextString(m) }
 func (*Span) ProtoMessage()    {}
 func (*Span) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{10}
+	return fileDescriptor_f22805646f4f62b6, []int{12}
 }
 func (m *Span) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -917,7 +1081,7 @@ func (m *SearchMetrics) Reset()         { *m = SearchMetrics{} }
 func (m *SearchMetrics) String() string { return proto.CompactTextString(m) }
 func (*SearchMetrics) ProtoMessage()    {}
 func (*SearchMetrics) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{11}
+	return fileDescriptor_f22805646f4f62b6, []int{13}
 }
 func (m *SearchMetrics) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1005,7 +1169,7 @@ func (m *SearchTagsRequest) Reset()         { *m = SearchTagsRequest{} }
 func (m *SearchTagsRequest) String() string { return proto.CompactTextString(m) }
 func (*SearchTagsRequest) ProtoMessage()    {}
 func (*SearchTagsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{12}
+	return fileDescriptor_f22805646f4f62b6, []int{14}
 }
 func (m *SearchTagsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1076,7 +1240,7 @@ func (m *SearchTagsBlockRequest) Reset()         { *m = SearchTagsBlockRequest{}
 func (m *SearchTagsBlockRequest) String() string { return proto.CompactTextString(m) }
 func (*SearchTagsBlockRequest) ProtoMessage()    {}
 func (*SearchTagsBlockRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{13}
+	return fileDescriptor_f22805646f4f62b6, []int{15}
 }
 func (m *SearchTagsBlockRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1208,7 +1372,7 @@ func (m *SearchTagValuesBlockRequest) Reset()         { *m = SearchTagValuesBloc
 func (m *SearchTagValuesBlockRequest) String() string { return proto.CompactTextString(m) }
 func (*SearchTagValuesBlockRequest) ProtoMessage()    {}
 func (*SearchTagValuesBlockRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{14}
+	return fileDescriptor_f22805646f4f62b6, []int{16}
 }
 func (m *SearchTagValuesBlockRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1329,7 +1493,7 @@ func (m *SearchTagsResponse) Reset()         { *m = SearchTagsResponse{} }
 func (m *SearchTagsResponse) String() string { return proto.CompactTextString(m) }
 func (*SearchTagsResponse) ProtoMessage()    {}
 func (*SearchTagsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{15}
+	return fileDescriptor_f22805646f4f62b6, []int{17}
 }
 func (m *SearchTagsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1373,7 +1537,7 @@ func (m *SearchTagsV2Response) Reset()         { *m = SearchTagsV2Response{} }
 func (m *SearchTagsV2Response) String() string { return proto.CompactTextString(m) }
 func (*SearchTagsV2Response) ProtoMessage()    {}
 func (*SearchTagsV2Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{16}
+	return fileDescriptor_f22805646f4f62b6, []int{18}
 }
 func (m *SearchTagsV2Response) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1418,7 +1582,7 @@ func (m *SearchTagsV2Scope) Reset()         { *m = SearchTagsV2Scope{} }
 func (m *SearchTagsV2Scope) String() string { return proto.CompactTextString(m) }
 func (*SearchTagsV2Scope) ProtoMessage()    {}
 func (*SearchTagsV2Scope) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{17}
+	return fileDescriptor_f22805646f4f62b6, []int{19}
 }
 func (m *SearchTagsV2Scope) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1472,7 +1636,7 @@ func (m *SearchTagValuesRequest) Reset()         { *m = SearchTagValuesRequest{}
 func (m *SearchTagValuesRequest) String() string { return proto.CompactTextString(m) }
 func (*SearchTagValuesRequest) ProtoMessage()    {}
 func (*SearchTagValuesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{18}
+	return fileDescriptor_f22805646f4f62b6, []int{20}
 }
 func (m *SearchTagValuesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1537,7 +1701,7 @@ func (m *SearchTagValuesResponse) Reset()         { *m = SearchTagValuesResponse
 func (m *SearchTagValuesResponse) String() string { return proto.CompactTextString(m) }
 func (*SearchTagValuesResponse) ProtoMessage()    {}
 func (*SearchTagValuesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{19}
+	return fileDescriptor_f22805646f4f62b6, []int{21}
 }
 func (m *SearchTagValuesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1582,7 +1746,7 @@ func (m *TagValue) Reset()         { *m = TagValue{} }
 func (m *TagValue) String() string { return proto.CompactTextString(m) }
 func (*TagValue) ProtoMessage()    {}
 func (*TagValue) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{20}
+	return fileDescriptor_f22805646f4f62b6, []int{22}
 }
 func (m *TagValue) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1633,7 +1797,7 @@ func (m *SearchTagValuesV2Response) Reset()         { *m = SearchTagValuesV2Resp
 func (m *SearchTagValuesV2Response) String() string { return proto.CompactTextString(m) }
 func (*SearchTagValuesV2Response) ProtoMessage()    {}
 func (*SearchTagValuesV2Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{21}
+	return fileDescriptor_f22805646f4f62b6, []int{23}
 }
 func (m *SearchTagValuesV2Response) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1677,7 +1841,7 @@ func (m *Trace) Reset()         { *m = Trace{} }
 func (m *Trace) String() string { return proto.CompactTextString(m) }
 func (*Trace) ProtoMessage()    {}
 func (*Trace) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{22}
+	return fileDescriptor_f22805646f4f62b6, []int{24}
 }
 func (m *Trace) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1722,7 +1886,7 @@ func (m *PushResponse) Reset()         { *m = PushResponse{} }
 func (m *PushResponse) String() string { return proto.CompactTextString(m) }
 func (*PushResponse) ProtoMessage()    {}
 func (*PushResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{23}
+	return fileDescriptor_f22805646f4f62b6, []int{25}
 }
 func (m *PushResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1760,6 +1924,7 @@ func (m *PushResponse) GetErrorsByTrace() []PushErrorReason {
 
 // PushBytesRequest pushes slices of traces, ids and searchdata. Traces are
 // encoded using the
+//
 //  current BatchDecoder in ./pkg/model
 type PushBytesRequest struct {
 	// pre-marshalled Traces. length must match ids
@@ -1774,7 +1939,7 @@ func (m *PushBytesRequest) Reset()         { *m = PushBytesRequest{} }
 func (m *PushBytesRequest) String() string { return proto.CompactTextString(m) }
 func (*PushBytesRequest) ProtoMessage()    {}
 func (*PushBytesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{24}
+	return fileDescriptor_f22805646f4f62b6, []int{26}
 }
 func (m *PushBytesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1812,7 +1977,7 @@ func (m *PushSpansRequest) Reset()         { *m = PushSpansRequest{} }
 func (m *PushSpansRequest) String() string { return proto.CompactTextString(m) }
 func (*PushSpansRequest) ProtoMessage()    {}
 func (*PushSpansRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{25}
+	return fileDescriptor_f22805646f4f62b6, []int{27}
 }
 func (m *PushSpansRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1857,7 +2022,7 @@ func (m *TraceBytes) Reset()         { *m = TraceBytes{} }
 func (m *TraceBytes) String() string { return proto.CompactTextString(m) }
 func (*TraceBytes) ProtoMessage()    {}
 func (*TraceBytes) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{26}
+	return fileDescriptor_f22805646f4f62b6, []int{28}
 }
 func (m *TraceBytes) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1905,7 +2070,7 @@ func (m *LinkSlice) Reset()         { *m = LinkSlice{} }
 func (m *LinkSlice) String() string { return proto.CompactTextString(m) }
 func (*LinkSlice) ProtoMessage()    {}
 func (*LinkSlice) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{27}
+	return fileDescriptor_f22805646f4f62b6, []int{29}
 }
 func (m *LinkSlice) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1953,7 +2118,7 @@ func (m *SpanMetricsRequest) Reset()         { *m = SpanMetricsRequest{} }
 func (m *SpanMetricsRequest) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsRequest) ProtoMessage()    {}
 func (*SpanMetricsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{28}
+	return fileDescriptor_f22805646f4f62b6, []int{30}
 }
 func (m *SpanMetricsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2029,7 +2194,7 @@ func (m *SpanMetricsSummaryRequest) Reset()         { *m = SpanMetricsSummaryReq
 func (m *SpanMetricsSummaryRequest) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsSummaryRequest) ProtoMessage()    {}
 func (*SpanMetricsSummaryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{29}
+	return fileDescriptor_f22805646f4f62b6, []int{31}
 }
 func (m *SpanMetricsSummaryRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2104,7 +2269,7 @@ func (m *SpanMetricsResponse) Reset()         { *m = SpanMetricsResponse{} }
 func (m *SpanMetricsResponse) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsResponse) ProtoMessage()    {}
 func (*SpanMetricsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{30}
+	return fileDescriptor_f22805646f4f62b6, []int{32}
 }
 func (m *SpanMetricsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2170,7 +2335,7 @@ func (m *RawHistogram) Reset()         { *m = RawHistogram{} }
 func (m *RawHistogram) String() string { return proto.CompactTextString(m) }
 func (*RawHistogram) ProtoMessage()    {}
 func (*RawHistogram) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{31}
+	return fileDescriptor_f22805646f4f62b6, []int{33}
 }
 func (m *RawHistogram) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2222,7 +2387,7 @@ func (m *KeyValue) Reset()         { *m = KeyValue{} }
 func (m *KeyValue) String() string { return proto.CompactTextString(m) }
 func (*KeyValue) ProtoMessage()    {}
 func (*KeyValue) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{32}
+	return fileDescriptor_f22805646f4f62b6, []int{34}
 }
 func (m *KeyValue) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2275,7 +2440,7 @@ func (m *SpanMetrics) Reset()         { *m = SpanMetrics{} }
 func (m *SpanMetrics) String() string { return proto.CompactTextString(m) }
 func (*SpanMetrics) ProtoMessage()    {}
 func (*SpanMetrics) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{33}
+	return fileDescriptor_f22805646f4f62b6, []int{35}
 }
 func (m *SpanMetrics) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2339,7 +2504,7 @@ func (m *SpanMetricsSummary) Reset()         { *m = SpanMetricsSummary{} }
 func (m *SpanMetricsSummary) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsSummary) ProtoMessage()    {}
 func (*SpanMetricsSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{34}
+	return fileDescriptor_f22805646f4f62b6, []int{36}
 }
 func (m *SpanMetricsSummary) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2425,7 +2590,7 @@ func (m *SpanMetricsSummaryResponse) Reset()         { *m = SpanMetricsSummaryRe
 func (m *SpanMetricsSummaryResponse) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsSummaryResponse) ProtoMessage()    {}
 func (*SpanMetricsSummaryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{35}
+	return fileDescriptor_f22805646f4f62b6, []int{37}
 }
 func (m *SpanMetricsSummaryResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2476,7 +2641,7 @@ func (m *TraceQLStatic) Reset()         { *m = TraceQLStatic{} }
 func (m *TraceQLStatic) String() string { return proto.CompactTextString(m) }
 func (*TraceQLStatic) ProtoMessage()    {}
 func (*TraceQLStatic) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{36}
+	return fileDescriptor_f22805646f4f62b6, []int{38}
 }
 func (m *TraceQLStatic) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2570,7 +2735,7 @@ func (m *SpanMetricsData) Reset()         { *m = SpanMetricsData{} }
 func (m *SpanMetricsData) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsData) ProtoMessage()    {}
 func (*SpanMetricsData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{37}
+	return fileDescriptor_f22805646f4f62b6, []int{39}
 }
 func (m *SpanMetricsData) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2623,7 +2788,7 @@ func (m *SpanMetricsResult) Reset()         { *m = SpanMetricsResult{} }
 func (m *SpanMetricsResult) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsResult) ProtoMessage()    {}
 func (*SpanMetricsResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{38}
+	return fileDescriptor_f22805646f4f62b6, []int{40}
 }
 func (m *SpanMetricsResult) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2684,7 +2849,7 @@ func (m *SpanMetricsResultPoint) Reset()         { *m = SpanMetricsResultPoint{}
 func (m *SpanMetricsResultPoint) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsResultPoint) ProtoMessage()    {}
 func (*SpanMetricsResultPoint) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{39}
+	return fileDescriptor_f22805646f4f62b6, []int{41}
 }
 func (m *SpanMetricsResultPoint) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2758,13 +2923,17 @@ type QueryRangeRequest struct {
 	Size_            uint64             `protobuf:"varint,13,opt,name=size,proto3" json:"size,omitempty"`
 	FooterSize       uint32             `protobuf:"varint,14,opt,name=footerSize,proto3" json:"footerSize,omitempty"`
 	DedicatedColumns []*DedicatedColumn `protobuf:"bytes,15,rep,name=dedicatedColumns,proto3" json:"dedicatedColumns,omitempty"`
+	Exemplars        uint32             `protobuf:"varint,16,opt,name=exemplars,proto3" json:"exemplars,omitempty"`
+	ExemplarPolicy   string             `protobuf:"bytes,17,opt,name=exemplarPolicy,proto3" json:"exemplarPolicy,omitempty"`
+	Offset           uint64             `protobuf:"varint,18,opt,name=offset,proto3" json:"offset,omitempty"`
+	Lookback         uint64             `protobuf:"varint,19,opt,name=lookback,proto3" json:"lookback,omitempty"`
 }
 
 func (m *QueryRangeRequest) Reset()         { *m = QueryRangeRequest{} }
 func (m *QueryRangeRequest) String() string { return proto.CompactTextString(m) }
 func (*QueryRangeRequest) ProtoMessage()    {}
 func (*QueryRangeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{40}
+	return fileDescriptor_f22805646f4f62b6, []int{42}
 }
 func (m *QueryRangeRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2898,6 +3067,34 @@ func (m *QueryRangeRequest) GetDedicatedColumns() []*DedicatedColumn {
 	return nil
 }
 
+func (m *QueryRangeRequest) GetExemplars() uint32 {
+	if m != nil {
+		return m.Exemplars
+	}
+	return 0
+}
+
+func (m *QueryRangeRequest) GetExemplarPolicy() string {
+	if m != nil {
+		return m.ExemplarPolicy
+	}
+	return ""
+}
+
+func (m *QueryRangeRequest) GetOffset() uint64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *QueryRangeRequest) GetLookback() uint64 {
+	if m != nil {
+		return m.Lookback
+	}
+	return 0
+}
+
 type QueryRangeResponse struct {
 	Series  []*TimeSeries  `protobuf:"bytes,1,rep,name=series,proto3" json:"series,omitempty"`
 	Metrics *SearchMetrics `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
@@ -2907,7 +3104,7 @@ func (m *QueryRangeResponse) Reset()         { *m = QueryRangeResponse{} }
 func (m *QueryRangeResponse) String() string { return proto.CompactTextString(m) }
 func (*QueryRangeResponse) ProtoMessage()    {}
 func (*QueryRangeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{41}
+	return fileDescriptor_f22805646f4f62b6, []int{43}
 }
 func (m *QueryRangeResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2960,7 +3157,7 @@ func (m *Sample) Reset()         { *m = Sample{} }
 func (m *Sample) String() string { return proto.CompactTextString(m) }
 func (*Sample) ProtoMessage()    {}
 func (*Sample) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{42}
+	return fileDescriptor_f22805646f4f62b6, []int{44}
 }
 func (m *Sample) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3011,13 +3208,15 @@ type TimeSeries struct {
 	// prom_labels are a flattened string-only version of the typed labels.
 	// They are used internally and may differ from official prometheus conventions.
 	PromLabels string `protobuf:"bytes,3,opt,name=prom_labels,json=promLabels,proto3" json:"prom_labels,omitempty"`
+	// Spans selected as examples of the series.
+	Exemplars []Exemplar `protobuf:"bytes,4,rep,name=exemplars,proto3" json:"exemplars"`
 }
 
 func (m *TimeSeries) Reset()         { *m = TimeSeries{} }
 func (m *TimeSeries) String() string { return proto.CompactTextString(m) }
 func (*TimeSeries) ProtoMessage()    {}
 func (*TimeSeries) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{43}
+	return fileDescriptor_f22805646f4f62b6, []int{45}
 }
 func (m *TimeSeries) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3067,232 +3266,555 @@ func (m *TimeSeries) GetPromLabels() string {
 	return ""
 }
 
-func init() {
-	proto.RegisterEnum("tempopb.PushErrorReason", PushErrorReason_name, PushErrorReason_value)
-	proto.RegisterEnum("tempopb.DedicatedColumn_Scope", DedicatedColumn_Scope_name, DedicatedColumn_Scope_value)
-	proto.RegisterEnum("tempopb.DedicatedColumn_Type", DedicatedColumn_Type_name, DedicatedColumn_Type_value)
-	proto.RegisterType((*TraceByIDRequest)(nil), "tempopb.TraceByIDRequest")
-	proto.RegisterType((*TraceByIDResponse)(nil), "tempopb.TraceByIDResponse")
-	proto.RegisterType((*TraceByIDMetrics)(nil), "tempopb.TraceByIDMetrics")
-	proto.RegisterType((*SearchRequest)(nil), "tempopb.SearchRequest")
-	proto.RegisterMapType((map[string]string)(nil), "tempopb.SearchRequest.TagsEntry")
-	proto.RegisterType((*SearchBlockRequest)(nil), "tempopb.SearchBlockRequest")
-	proto.RegisterType((*DedicatedColumn)(nil), "tempopb.DedicatedColumn")
-	proto.RegisterType((*SearchResponse)(nil), "tempopb.SearchResponse")
-	proto.RegisterType((*TraceSearchMetadata)(nil), "tempopb.TraceSearchMetadata")
-	proto.RegisterMapType((map[string]*ServiceStats)(nil), "tempopb.TraceSearchMetadata.ServiceStatsEntry")
-	proto.RegisterType((*ServiceStats)(nil), "tempopb.ServiceStats")
-	proto.RegisterType((*SpanSet)(nil), "tempopb.SpanSet")
-	proto.RegisterType((*Span)(nil), "tempopb.Span")
-	proto.RegisterType((*SearchMetrics)(nil), "tempopb.SearchMetrics")
-	proto.RegisterType((*SearchTagsRequest)(nil), "tempopb.SearchTagsRequest")
-	proto.RegisterType((*SearchTagsBlockRequest)(nil), "tempopb.SearchTagsBlockRequest")
-	proto.RegisterType((*SearchTagValuesBlockRequest)(nil), "tempopb.SearchTagValuesBlockRequest")
-	proto.RegisterType((*SearchTagsResponse)(nil), "tempopb.SearchTagsResponse")
-	proto.RegisterType((*SearchTagsV2Response)(nil), "tempopb.SearchTagsV2Response")
-	proto.RegisterType((*SearchTagsV2Scope)(nil), "tempopb.SearchTagsV2Scope")
-	proto.RegisterType((*SearchTagValuesRequest)(nil), "tempopb.SearchTagValuesRequest")
-	proto.RegisterType((*SearchTagValuesResponse)(nil), "tempopb.SearchTagValuesResponse")
-	proto.RegisterType((*TagValue)(nil), "tempopb.TagValue")
-	proto.RegisterType((*SearchTagValuesV2Response)(nil), "tempopb.SearchTagValuesV2Response")
-	proto.RegisterType((*Trace)(nil), "tempopb.Trace")
-	proto.RegisterType((*PushResponse)(nil), "tempopb.PushResponse")
-	proto.RegisterType((*PushBytesRequest)(nil), "tempopb.PushBytesRequest")
-	proto.RegisterType((*PushSpansRequest)(nil), "tempopb.PushSpansRequest")
-	proto.RegisterType((*TraceBytes)(nil), "tempopb.TraceBytes")
-	proto.RegisterType((*LinkSlice)(nil), "tempopb.LinkSlice")
-	proto.RegisterType((*SpanMetricsRequest)(nil), "tempopb.SpanMetricsRequest")
-	proto.RegisterType((*SpanMetricsSummaryRequest)(nil), "tempopb.SpanMetricsSummaryRequest")
-	proto.RegisterType((*SpanMetricsResponse)(nil), "tempopb.SpanMetricsResponse")
-	proto.RegisterType((*RawHistogram)(nil), "tempopb.RawHistogram")
-	proto.RegisterType((*KeyValue)(nil), "tempopb.KeyValue")
-	proto.RegisterType((*SpanMetrics)(nil), "tempopb.SpanMetrics")
-	proto.RegisterType((*SpanMetricsSummary)(nil), "tempopb.SpanMetricsSummary")
-	proto.RegisterType((*SpanMetricsSummaryResponse)(nil), "tempopb.SpanMetricsSummaryResponse")
-	proto.RegisterType((*TraceQLStatic)(nil), "tempopb.TraceQLStatic")
-	proto.RegisterType((*SpanMetricsData)(nil), "tempopb.SpanMetricsData")
-	proto.RegisterType((*SpanMetricsResult)(nil), "tempopb.SpanMetricsResult")
-	proto.RegisterType((*SpanMetricsResultPoint)(nil), "tempopb.SpanMetricsResultPoint")
-	proto.RegisterType((*QueryRangeRequest)(nil), "tempopb.QueryRangeRequest")
-	proto.RegisterType((*QueryRangeResponse)(nil), "tempopb.QueryRangeResponse")
-	proto.RegisterType((*Sample)(nil), "tempopb.Sample")
-	proto.RegisterType((*TimeSeries)(nil), "tempopb.TimeSeries")
+func (m *TimeSeries) GetExemplars() []Exemplar {
+	if m != nil {
+		return m.Exemplars
+	}
+	return nil
 }
 
-func init() { proto.RegisterFile("pkg/tempopb/tempo.proto", fileDescriptor_f22805646f4f62b6) }
-
-var fileDescriptor_f22805646f4f62b6 = []byte{
-	// 2646 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x5a, 0xcd, 0x6e, 0x23, 0xc7,
-	0xf1, 0xd7, 0x88, 0x1f, 0x22, 0x8b, 0x94, 0x44, 0xf5, 0xae, 0x65, 0x2e, 0xd7, 0xd6, 0xea, 0x3f,
-	0x5e, 0xf8, 0xaf, 0xf8, 0x43, 0xd2, 0xd2, 0xbb, 0xb0, 0xd7, 0x4e, 0x1c, 0xac, 0x56, 0xca, 0x5a,
-	0xb6, 0xbe, 0xdc, 0x94, 0x65, 0x23, 0x30, 0x20, 0x8c, 0xc8, 0x5e, 0xee, 0x40, 0xe4, 0x0c, 0x3d,
-	0x33, 0x54, 0x96, 0x39, 0x06, 0x48, 0x80, 0x00, 0x39, 0xe4, 0x90, 0x1c, 0x7c, 0xcc, 0x29, 0xc8,
-	0x39, 0x8f, 0x10, 0x20, 0x30, 0x10, 0xc4, 0x30, 0x90, 0x8b, 0x91, 0x83, 0x11, 0xd8, 0x87, 0x3c,
-	0x40, 0x5e, 0x20, 0xa8, 0xea, 0xee, 0x99, 0x1e, 0x72, 0xa4, 0xf5, 0x26, 0x6b, 0xc4, 0x07, 0x9f,
-	0xd4, 0x55, 0x5d, 0x5d, 0x5d, 0xdd, 0x55, 0xfd, 0xab, 0xaa, 0xa1, 0xe0, 0xe9, 0xc1, 0x69, 0x77,
-	0x2d, 0x12, 0xfd, 0x81, 0x3f, 0x38, 0x91, 0x7f, 0x57, 0x07, 0x81, 0x1f, 0xf9, 0x6c, 0x46, 0x31,
-	0x1b, 0x8b, 0x6d, 0xbf, 0xdf, 0xf7, 0xbd, 0xb5, 0xb3, 0x1b, 0x6b, 0x72, 0x24, 0x05, 0x1a, 0x2f,
-	0x77, 0xdd, 0xe8, 0xc1, 0xf0, 0x64, 0xb5, 0xed, 0xf7, 0xd7, 0xba, 0x7e, 0xd7, 0x5f, 0x23, 0xf6,
-	0xc9, 0xf0, 0x3e, 0x51, 0x44, 0xd0, 0x48, 0x89, 0x5f, 0x8e, 0x02, 0xa7, 0x2d, 0x50, 0x0b, 0x0d,
-	0x24, 0xd7, 0xfe, 0x85, 0x05, 0xb5, 0x43, 0xa4, 0x37, 0x46, 0xdb, 0x9b, 0x5c, 0x7c, 0x34, 0x14,
-	0x61, 0xc4, 0xea, 0x30, 0x43, 0x32, 0xdb, 0x9b, 0x75, 0x6b, 0xd9, 0x5a, 0xa9, 0x72, 0x4d, 0xb2,
-	0x25, 0x80, 0x93, 0x9e, 0xdf, 0x3e, 0x6d, 0x45, 0x4e, 0x10, 0xd5, 0xa7, 0x97, 0xad, 0x95, 0x32,
-	0x37, 0x38, 0xac, 0x01, 0x25, 0xa2, 0xb6, 0xbc, 0x4e, 0x3d, 0x47, 0xb3, 0x31, 0xcd, 0x9e, 0x81,
-	0xf2, 0x47, 0x43, 0x11, 0x8c, 0x76, 0xfd, 0x8e, 0xa8, 0x17, 0x68, 0x32, 0x61, 0xd8, 0x1e, 0x2c,
-	0x18, 0x76, 0x84, 0x03, 0xdf, 0x0b, 0x05, 0xbb, 0x0e, 0x05, 0xda, 0x99, 0xcc, 0xa8, 0x34, 0xe7,
-	0x56, 0xd5, 0x9d, 0xac, 0x92, 0x28, 0x97, 0x93, 0xec, 0x15, 0x98, 0xe9, 0x8b, 0x28, 0x70, 0xdb,
-	0x21, 0x59, 0x54, 0x69, 0x5e, 0x49, 0xcb, 0xa1, 0xca, 0x5d, 0x29, 0xc0, 0xb5, 0xa4, 0xcd, 0x8c,
-	0x73, 0xab, 0x49, 0xfb, 0xd3, 0x69, 0x98, 0x6d, 0x09, 0x27, 0x68, 0x3f, 0xd0, 0x37, 0xf1, 0x3a,
-	0xe4, 0x0f, 0x9d, 0x6e, 0x58, 0xb7, 0x96, 0x73, 0x2b, 0x95, 0xe6, 0x72, 0xac, 0x37, 0x25, 0xb5,
-	0x8a, 0x22, 0x5b, 0x5e, 0x14, 0x8c, 0x36, 0xf2, 0x9f, 0x7c, 0x71, 0x6d, 0x8a, 0xd3, 0x1a, 0x76,
-	0x1d, 0x66, 0x77, 0x5d, 0x6f, 0x73, 0x18, 0x38, 0x91, 0xeb, 0x7b, 0xbb, 0xd2, 0xb8, 0x59, 0x9e,
-	0x66, 0x92, 0x94, 0xf3, 0xd0, 0x90, 0xca, 0x29, 0x29, 0x93, 0xc9, 0x2e, 0x43, 0x61, 0xc7, 0xed,
-	0xbb, 0x51, 0x3d, 0x4f, 0xb3, 0x92, 0x40, 0x6e, 0x48, 0x8e, 0x28, 0x48, 0x2e, 0x11, 0xac, 0x06,
-	0x39, 0xe1, 0x75, 0xea, 0x45, 0xe2, 0xe1, 0x10, 0xe5, 0xde, 0xc5, 0x8b, 0xae, 0x97, 0xe8, 0xd6,
-	0x25, 0xc1, 0x56, 0x60, 0xbe, 0x35, 0x70, 0xbc, 0xf0, 0x40, 0x04, 0xf8, 0xb7, 0x25, 0xa2, 0x7a,
-	0x99, 0xd6, 0x8c, 0xb3, 0x1b, 0xaf, 0x42, 0x39, 0x3e, 0x22, 0xaa, 0x3f, 0x15, 0x23, 0xf2, 0x48,
-	0x99, 0xe3, 0x10, 0xd5, 0x9f, 0x39, 0xbd, 0xa1, 0x50, 0xf1, 0x20, 0x89, 0xd7, 0xa7, 0x5f, 0xb3,
-	0xec, 0x3f, 0xe7, 0x80, 0xc9, 0xab, 0xda, 0xc0, 0x28, 0xd0, 0xb7, 0x7a, 0x13, 0xca, 0xa1, 0xbe,
-	0x40, 0xe5, 0xda, 0xc5, 0xec, 0xab, 0xe5, 0x89, 0x20, 0x46, 0x25, 0xc5, 0xd2, 0xf6, 0xa6, 0xda,
-	0x48, 0x93, 0x18, 0x59, 0x74, 0xf4, 0x03, 0xa7, 0x2b, 0xd4, 0xfd, 0x25, 0x0c, 0xbc, 0xe1, 0x81,
-	0xd3, 0x15, 0xe1, 0xa1, 0x2f, 0x55, 0xab, 0x3b, 0x4c, 0x33, 0x31, 0x72, 0x85, 0xd7, 0xf6, 0x3b,
-	0xae, 0xd7, 0x55, 0xc1, 0x19, 0xd3, 0xa8, 0xc1, 0xf5, 0x3a, 0xe2, 0x21, 0xaa, 0x6b, 0xb9, 0x3f,
-	0x15, 0xea, 0x6e, 0xd3, 0x4c, 0x66, 0x43, 0x35, 0xf2, 0x23, 0xa7, 0xc7, 0x45, 0xdb, 0x0f, 0x3a,
-	0x61, 0x7d, 0x86, 0x84, 0x52, 0x3c, 0x94, 0xe9, 0x38, 0x91, 0xb3, 0xa5, 0x77, 0x92, 0x0e, 0x49,
-	0xf1, 0xf0, 0x9c, 0x67, 0x22, 0x08, 0x5d, 0xdf, 0x23, 0x7f, 0x94, 0xb9, 0x26, 0x19, 0x83, 0x7c,
-	0x88, 0xdb, 0xc3, 0xb2, 0xb5, 0x92, 0xe7, 0x34, 0xc6, 0x17, 0x79, 0xdf, 0xf7, 0x23, 0x11, 0x90,
-	0x61, 0x15, 0xda, 0xd3, 0xe0, 0xb0, 0x4d, 0xa8, 0x75, 0x44, 0xc7, 0x6d, 0x3b, 0x91, 0xe8, 0xdc,
-	0xf5, 0x7b, 0xc3, 0xbe, 0x17, 0xd6, 0xab, 0x14, 0xcd, 0xf5, 0xf8, 0xca, 0x37, 0xd3, 0x02, 0x7c,
-	0x62, 0x85, 0xfd, 0x27, 0x0b, 0xe6, 0xc7, 0xa4, 0xd8, 0x4d, 0x28, 0x84, 0x6d, 0x7f, 0x20, 0x6f,
-	0x7c, 0xae, 0xb9, 0x74, 0x9e, 0xba, 0xd5, 0x16, 0x4a, 0x71, 0x29, 0x8c, 0x67, 0xf0, 0x9c, 0xbe,
-	0x8e, 0x15, 0x1a, 0xb3, 0x1b, 0x90, 0x8f, 0x46, 0x03, 0xf9, 0xca, 0xe7, 0x9a, 0xcf, 0x9e, 0xab,
-	0xe8, 0x70, 0x34, 0x10, 0x9c, 0x44, 0xed, 0x6b, 0x50, 0x20, 0xb5, 0xac, 0x04, 0xf9, 0xd6, 0xc1,
-	0x9d, 0xbd, 0xda, 0x14, 0xab, 0x42, 0x89, 0x6f, 0xb5, 0xf6, 0xdf, 0xe3, 0x77, 0xb7, 0x6a, 0x96,
-	0xcd, 0x20, 0x8f, 0xe2, 0x0c, 0xa0, 0xd8, 0x3a, 0xe4, 0xdb, 0x7b, 0xf7, 0x6a, 0x53, 0xf6, 0x43,
-	0x98, 0xd3, 0xd1, 0xa5, 0x00, 0xe6, 0x26, 0x14, 0x09, 0x43, 0xf4, 0x0b, 0x7f, 0x26, 0x8d, 0x1c,
-	0x52, 0x7a, 0x57, 0x44, 0x0e, 0x7a, 0x88, 0x2b, 0x59, 0xb6, 0x3e, 0x0e, 0x38, 0xe3, 0xd1, 0x3b,
-	0x81, 0x36, 0x7f, 0xcb, 0xc1, 0xa5, 0x0c, 0x8d, 0xe3, 0x48, 0x5b, 0x4e, 0x90, 0x76, 0x05, 0xe6,
-	0x03, 0xdf, 0x8f, 0x5a, 0x22, 0x38, 0x73, 0xdb, 0x62, 0x2f, 0xb9, 0xb2, 0x71, 0x36, 0x46, 0x27,
-	0xb2, 0x48, 0x3d, 0xc9, 0x49, 0xe0, 0x4d, 0x33, 0xd9, 0x4b, 0xb0, 0x40, 0x4f, 0xe2, 0xd0, 0xed,
-	0x8b, 0xf7, 0x3c, 0xf7, 0xe1, 0x9e, 0xe3, 0xf9, 0xf4, 0x12, 0xf2, 0x7c, 0x72, 0x02, 0xa3, 0xaa,
-	0x93, 0x40, 0x92, 0x84, 0x17, 0x83, 0xc3, 0x5e, 0x80, 0x99, 0x50, 0x61, 0x46, 0x91, 0x6e, 0xa0,
-	0x96, 0xdc, 0x80, 0xe4, 0x73, 0x2d, 0xc0, 0x5e, 0x82, 0x92, 0x1a, 0xe2, 0x9b, 0xc8, 0x65, 0x0a,
-	0xc7, 0x12, 0x8c, 0x43, 0x35, 0x94, 0x87, 0x6b, 0x45, 0x4e, 0x14, 0xd6, 0x4b, 0xb4, 0x62, 0xf5,
-	0x22, 0xbf, 0xac, 0xb6, 0x8c, 0x05, 0x04, 0x52, 0x3c, 0xa5, 0xa3, 0x71, 0x04, 0x0b, 0x13, 0x22,
-	0x19, 0x38, 0xf6, 0xa2, 0x89, 0x63, 0x95, 0xe6, 0x53, 0x86, 0x53, 0x93, 0xc5, 0x26, 0xbc, 0xed,
-	0x40, 0xd5, 0x9c, 0x22, 0x1c, 0x1a, 0x38, 0xde, 0x5d, 0x7f, 0xe8, 0x45, 0xa4, 0x18, 0x71, 0x48,
-	0x33, 0xf0, 0x4e, 0x45, 0x10, 0xf8, 0x81, 0x9c, 0x96, 0xc9, 0xc0, 0xe0, 0xd8, 0x3f, 0xb7, 0x60,
-	0x46, 0xdd, 0x07, 0x7b, 0x0e, 0x0a, 0xb8, 0x50, 0x87, 0xe5, 0x6c, 0xea, 0xc2, 0xb8, 0x9c, 0xc3,
-	0xe0, 0xe9, 0x3b, 0x51, 0xfb, 0x81, 0xe8, 0x28, 0x6d, 0x9a, 0x64, 0x6f, 0x00, 0x38, 0x51, 0x14,
-	0xb8, 0x27, 0xc3, 0x48, 0x60, 0x46, 0x41, 0x1d, 0x57, 0x63, 0x1d, 0xaa, 0x8a, 0x38, 0xbb, 0xb1,
-	0xfa, 0x8e, 0x18, 0x1d, 0xe1, 0x69, 0xb8, 0x21, 0x8e, 0x6f, 0x3d, 0x8f, 0xdb, 0xb0, 0x45, 0x28,
-	0xe2, 0x46, 0x71, 0x6c, 0x2a, 0x2a, 0xf3, 0x09, 0x67, 0x86, 0x57, 0xee, 0xbc, 0xf0, 0xba, 0x0e,
-	0xb3, 0x3a, 0x98, 0x90, 0x0e, 0x55, 0x20, 0xa6, 0x99, 0x63, 0xa7, 0x28, 0x3c, 0xde, 0x29, 0x3e,
-	0x8e, 0x73, 0xb9, 0x7a, 0x8c, 0xf8, 0xa2, 0x5c, 0x2f, 0x1c, 0x88, 0x76, 0x24, 0x3a, 0x87, 0xfa,
-	0xd1, 0x53, 0xbe, 0x1b, 0x63, 0xb3, 0xe7, 0x61, 0x2e, 0x66, 0x6d, 0x8c, 0x70, 0xf3, 0x69, 0xb2,
-	0x6f, 0x8c, 0xcb, 0x96, 0xa1, 0x42, 0xe8, 0x4e, 0xc9, 0x4d, 0x67, 0x6e, 0x93, 0x85, 0x07, 0x6d,
-	0xfb, 0xfd, 0x41, 0x4f, 0x44, 0xa2, 0xf3, 0xb6, 0x7f, 0x12, 0xea, 0xdc, 0x93, 0x62, 0x62, 0xdc,
-	0xd0, 0x22, 0x92, 0x90, 0x8f, 0x2d, 0x61, 0xa0, 0xdd, 0x89, 0x4a, 0x69, 0x4e, 0x91, 0xcc, 0x19,
-	0x67, 0xa7, 0xec, 0xa6, 0x1c, 0x4e, 0x39, 0xc8, 0xb4, 0x9b, 0xb8, 0xf6, 0xbb, 0xf8, 0x1e, 0xf0,
-	0x6a, 0x30, 0xab, 0xeb, 0xa4, 0x7c, 0x59, 0xc3, 0xb9, 0x74, 0xb6, 0x82, 0xeb, 0xb8, 0xc4, 0xc8,
-	0x65, 0x94, 0x18, 0xf9, 0xb8, 0xc4, 0xb0, 0x3f, 0xcd, 0xc1, 0x62, 0xa2, 0x33, 0x95, 0xed, 0x5f,
-	0x9b, 0xcc, 0xf6, 0x8d, 0x31, 0xbc, 0x34, 0xec, 0xf8, 0x2e, 0xe3, 0x7f, 0x3b, 0x32, 0xfe, 0xe7,
-	0x39, 0xb8, 0x1a, 0x3b, 0x87, 0x9e, 0x57, 0xda, 0xab, 0x3f, 0x98, 0xf4, 0xea, 0xb5, 0x49, 0xaf,
-	0xca, 0x85, 0xdf, 0xb9, 0xf6, 0x5b, 0xe5, 0xda, 0x75, 0x5d, 0x94, 0xcb, 0x67, 0xa7, 0x4a, 0xa1,
-	0x06, 0x94, 0x22, 0xa7, 0x8b, 0xb5, 0x82, 0xcc, 0x3a, 0x65, 0x1e, 0xd3, 0xf6, 0xdb, 0x70, 0x39,
-	0x59, 0x71, 0xd4, 0x8c, 0xd7, 0x34, 0xa1, 0x48, 0x30, 0xa1, 0xf3, 0x54, 0xd6, 0xbb, 0x3e, 0x6a,
-	0xca, 0xfa, 0x4f, 0x49, 0xda, 0x6f, 0x98, 0xe0, 0xa3, 0x26, 0xe3, 0x94, 0x62, 0x19, 0x29, 0x85,
-	0x41, 0x3e, 0xc2, 0xde, 0x6b, 0x9a, 0x8c, 0xa1, 0xb1, 0x3d, 0x30, 0x50, 0x26, 0x15, 0x5b, 0x54,
-	0x49, 0x49, 0x73, 0xe3, 0x4a, 0x4a, 0x92, 0x08, 0x61, 0xd4, 0x66, 0xea, 0xf6, 0x84, 0x88, 0x04,
-	0xd8, 0xf2, 0x19, 0xc0, 0x56, 0x48, 0x80, 0xed, 0x55, 0x78, 0x7a, 0x62, 0x47, 0x75, 0x7a, 0x84,
-	0x6d, 0xcd, 0x54, 0x57, 0x96, 0x30, 0xec, 0x9b, 0x50, 0xd2, 0x4b, 0xe8, 0x28, 0xa3, 0x18, 0x5a,
-	0x69, 0x9c, 0xdd, 0x35, 0xd9, 0x3b, 0x70, 0x65, 0x6c, 0x3b, 0xe3, 0xba, 0xd7, 0xc6, 0x37, 0xac,
-	0x34, 0x17, 0x92, 0xc2, 0x48, 0xcd, 0x98, 0x36, 0x6c, 0x40, 0x81, 0x52, 0x1a, 0xbb, 0x0d, 0x33,
-	0x27, 0x54, 0x1b, 0xe8, 0x75, 0xc9, 0x5b, 0x95, 0x5f, 0x03, 0xce, 0x6e, 0xac, 0x72, 0x11, 0xfa,
-	0xc3, 0xa0, 0x2d, 0x28, 0x47, 0x70, 0x2d, 0x6f, 0xef, 0x41, 0xf5, 0x60, 0x18, 0x26, 0x25, 0xf3,
-	0x9b, 0x30, 0x4b, 0x45, 0x4b, 0xb8, 0x31, 0x3a, 0x54, 0xbd, 0x79, 0x6e, 0x65, 0xce, 0x08, 0x40,
-	0x94, 0xde, 0x42, 0x09, 0x2e, 0x9c, 0xd0, 0xf7, 0x78, 0x5a, 0xdc, 0xfe, 0x9d, 0x05, 0x35, 0x14,
-	0xa1, 0x94, 0xa5, 0xbd, 0xf7, 0x72, 0x5c, 0x87, 0xa3, 0xb7, 0xab, 0x1b, 0x4f, 0x61, 0x1f, 0xfd,
-	0xf7, 0x2f, 0xae, 0xcd, 0x1e, 0x04, 0xc2, 0xe9, 0xf5, 0xfc, 0xb6, 0x94, 0xd6, 0x05, 0xf8, 0xff,
-	0x43, 0xce, 0xed, 0xc8, 0xc2, 0xe6, 0x5c, 0x59, 0x94, 0x60, 0xb7, 0x00, 0x24, 0xe6, 0x6c, 0x3a,
-	0x91, 0x53, 0xcf, 0x5f, 0x24, 0x6f, 0x08, 0xda, 0xbb, 0xd2, 0x44, 0x79, 0x13, 0xca, 0xc4, 0xff,
-	0xe2, 0x0a, 0xaf, 0x03, 0xa8, 0x6f, 0x0d, 0x98, 0xa5, 0x17, 0x53, 0x3d, 0x47, 0x55, 0x1f, 0xca,
-	0x7e, 0x13, 0xca, 0x3b, 0xae, 0x77, 0xda, 0xea, 0xb9, 0x6d, 0x6c, 0x89, 0x0a, 0x3d, 0xd7, 0x3b,
-	0xd5, 0x7b, 0x5d, 0x9d, 0xdc, 0x0b, 0xf7, 0x58, 0xc5, 0x05, 0x5c, 0x4a, 0xda, 0x3f, 0xb3, 0x80,
-	0x21, 0x53, 0x37, 0x1f, 0x49, 0x5e, 0x97, 0xe1, 0x6f, 0x99, 0xe1, 0x5f, 0x87, 0x99, 0x6e, 0xe0,
-	0x0f, 0x07, 0x1b, 0xfa, 0x59, 0x68, 0x12, 0xe5, 0x7b, 0xf4, 0xa9, 0x41, 0x56, 0x6f, 0x92, 0xf8,
-	0xda, 0xcf, 0xe5, 0x97, 0x16, 0x5c, 0x31, 0x8c, 0x68, 0x0d, 0xfb, 0x7d, 0x27, 0x18, 0xfd, 0x6f,
-	0x6c, 0xf9, 0x83, 0x05, 0x97, 0x52, 0x17, 0x92, 0xbc, 0x5b, 0x11, 0x46, 0x6e, 0x1f, 0x31, 0x91,
-	0x2c, 0x29, 0xf1, 0x84, 0x91, 0x2e, 0xe2, 0x65, 0xdd, 0x67, 0x14, 0xf1, 0xcf, 0xc3, 0x1c, 0x85,
-	0x73, 0x2b, 0x16, 0x91, 0xa6, 0x8d, 0x71, 0xd9, 0x6a, 0xd2, 0x22, 0xe6, 0xc9, 0x83, 0x97, 0x53,
-	0x25, 0xfc, 0x44, 0x83, 0xf8, 0x7d, 0xa8, 0x72, 0xe7, 0x27, 0x6f, 0xb9, 0x61, 0xe4, 0x77, 0x03,
-	0xa7, 0x8f, 0x41, 0x72, 0x32, 0x6c, 0x9f, 0x0a, 0xd9, 0x47, 0xe4, 0xb9, 0xa2, 0xf0, 0xec, 0x6d,
-	0xc3, 0x32, 0x49, 0xd8, 0x6f, 0x43, 0x49, 0x17, 0xc1, 0x19, 0x7d, 0xcd, 0x4b, 0xe9, 0xbe, 0x66,
-	0x31, 0xdd, 0x4b, 0xbd, 0xbb, 0x83, 0xcd, 0x8b, 0xdb, 0xd6, 0x08, 0xf4, 0x1b, 0x0b, 0x2a, 0x86,
-	0x89, 0x6c, 0x03, 0x16, 0x7a, 0x4e, 0x24, 0xbc, 0xf6, 0xe8, 0xf8, 0x81, 0x36, 0x4f, 0x45, 0x65,
-	0xd2, 0x21, 0x99, 0xb6, 0xf3, 0x9a, 0x92, 0x4f, 0x4e, 0xf3, 0x3d, 0x28, 0x86, 0x22, 0x70, 0xd5,
-	0xf3, 0x36, 0x51, 0x2b, 0xae, 0xdd, 0x95, 0x00, 0x1e, 0x5c, 0xe2, 0x85, 0xba, 0x58, 0x45, 0xd9,
-	0x7f, 0x4d, 0x47, 0xb7, 0x0a, 0xac, 0xc9, 0x96, 0xeb, 0x11, 0xde, 0x9a, 0xce, 0xf4, 0x56, 0x62,
-	0x5f, 0xee, 0x51, 0xf6, 0xd5, 0x20, 0x37, 0xb8, 0x7d, 0x5b, 0x35, 0x2c, 0x38, 0x94, 0x9c, 0x5b,
-	0x14, 0x78, 0xc4, 0xb9, 0x25, 0x39, 0xeb, 0xaa, 0x4a, 0xc7, 0x21, 0x71, 0x6e, 0xad, 0xab, 0x72,
-	0x1c, 0x87, 0xf6, 0xfb, 0xd0, 0xc8, 0x7a, 0x27, 0x2a, 0x44, 0x6f, 0x43, 0x39, 0x24, 0x96, 0x2b,
-	0x26, 0x21, 0x20, 0x63, 0x5d, 0x22, 0x6d, 0xff, 0xd6, 0x82, 0xd9, 0x94, 0x63, 0x53, 0xd9, 0xa7,
-	0xa0, 0xb2, 0x4f, 0x15, 0x2c, 0x8f, 0x2e, 0x23, 0xc7, 0x2d, 0x0f, 0xa9, 0xfb, 0x74, 0xdf, 0x16,
-	0xb7, 0xee, 0x23, 0x25, 0x1b, 0x95, 0x32, 0xb7, 0x42, 0xa4, 0x4e, 0xe8, 0x70, 0x25, 0x6e, 0x9d,
-	0x20, 0xd5, 0x51, 0x07, 0xb3, 0x3a, 0xd4, 0x21, 0x46, 0x4e, 0x34, 0x94, 0xf5, 0x51, 0x81, 0x2b,
-	0x0a, 0x77, 0x3c, 0x75, 0xbd, 0x0e, 0x55, 0x44, 0x05, 0x4e, 0x63, 0x5b, 0xc8, 0xcf, 0x8d, 0xca,
-	0x70, 0x84, 0x59, 0x2c, 0x77, 0x02, 0x11, 0x0e, 0x7b, 0xd1, 0x61, 0x92, 0x1c, 0x0d, 0x0e, 0x96,
-	0x17, 0x92, 0x52, 0x61, 0xd3, 0xc8, 0x7c, 0x43, 0x24, 0xc1, 0x95, 0x24, 0xa2, 0xe0, 0xc2, 0xc4,
-	0x2c, 0x86, 0x49, 0xcf, 0x39, 0x11, 0x3d, 0xa3, 0x3e, 0x48, 0x18, 0x68, 0x07, 0x11, 0x47, 0x46,
-	0x3e, 0x36, 0x38, 0x6c, 0x0d, 0xa6, 0x23, 0x1d, 0x1a, 0xd7, 0xce, 0xb7, 0xe1, 0xc0, 0x77, 0xbd,
-	0x88, 0x4f, 0x47, 0x21, 0xbe, 0xa1, 0xc5, 0xec, 0x69, 0x72, 0x86, 0xab, 0x8c, 0x98, 0xe5, 0x34,
-	0xc6, 0xe8, 0x38, 0x73, 0x7a, 0xb4, 0xb1, 0xc5, 0x71, 0x88, 0x3d, 0x9f, 0x78, 0x28, 0xfa, 0x83,
-	0x9e, 0x13, 0x1c, 0xaa, 0xef, 0x43, 0x39, 0xfa, 0x12, 0x3f, 0xce, 0x66, 0x2f, 0x40, 0x4d, 0xb3,
-	0xf4, 0xf7, 0x62, 0x15, 0x9c, 0x13, 0x7c, 0xfb, 0x2f, 0x39, 0x58, 0xa0, 0x6f, 0xbf, 0xdc, 0xf1,
-	0xba, 0xe2, 0x62, 0x50, 0x8e, 0x41, 0x56, 0x01, 0x4d, 0x0a, 0x64, 0xe5, 0xd3, 0xa4, 0x6f, 0xcb,
-	0x58, 0xc6, 0x46, 0x62, 0xa0, 0xf6, 0xa4, 0x31, 0x02, 0x7a, 0xf8, 0xc0, 0x09, 0x3a, 0xdb, 0x9b,
-	0x0a, 0x8e, 0x35, 0x89, 0x37, 0x4d, 0x43, 0xf9, 0x18, 0x65, 0xe5, 0x6d, 0x70, 0xd2, 0xbf, 0x11,
-	0xcc, 0x8c, 0xfd, 0x46, 0x60, 0x36, 0x0d, 0xa5, 0x0b, 0x9a, 0x86, 0xf2, 0x23, 0x9b, 0x06, 0xc8,
-	0x6a, 0x1a, 0x8c, 0x52, 0xbd, 0x92, 0x2e, 0xd5, 0xcd, 0x76, 0xa2, 0x3a, 0xd6, 0x4e, 0xe8, 0x32,
-	0x7e, 0xf6, 0xdc, 0x32, 0x7e, 0xee, 0x6b, 0x95, 0xf1, 0xf3, 0x8f, 0x5d, 0xc6, 0x87, 0xc0, 0x4c,
-	0x67, 0x2a, 0xe4, 0x78, 0x31, 0x86, 0x32, 0x09, 0x1b, 0x97, 0x12, 0xb4, 0x77, 0xfb, 0xa2, 0x45,
-	0x53, 0x31, 0x98, 0x3d, 0xfe, 0x87, 0xcc, 0x3b, 0x50, 0x6c, 0x39, 0xfd, 0x41, 0x4f, 0xb0, 0xff,
-	0x83, 0x2a, 0x06, 0x6f, 0x18, 0x39, 0xfd, 0xc1, 0x71, 0x3f, 0x54, 0x60, 0x52, 0x89, 0x79, 0xf2,
-	0x57, 0x0b, 0x99, 0x78, 0x2c, 0x8a, 0x6c, 0x95, 0x60, 0x3e, 0xb6, 0x00, 0x12, 0x5b, 0xd8, 0x6d,
-	0x28, 0xd2, 0x53, 0x9b, 0xc4, 0xb9, 0xc9, 0x2f, 0x3c, 0xea, 0xf7, 0x15, 0xb5, 0x80, 0xad, 0xc1,
-	0x4c, 0x48, 0xc6, 0xe8, 0xbc, 0x32, 0x9f, 0x98, 0x4f, 0x7c, 0x25, 0xaf, 0xa5, 0xd8, 0x35, 0xa8,
-	0x0c, 0x02, 0xbf, 0x7f, 0xac, 0x36, 0x94, 0x1f, 0x4a, 0x01, 0x59, 0x3b, 0xc4, 0x79, 0xe1, 0x43,
-	0x98, 0x1f, 0x2b, 0x5f, 0x59, 0x15, 0x4a, 0x7b, 0xfb, 0xc7, 0x5b, 0x9c, 0xef, 0xf3, 0xda, 0x14,
-	0xbb, 0x04, 0xf3, 0xbb, 0x77, 0x3e, 0x38, 0xde, 0xd9, 0x3e, 0xda, 0x3a, 0x3e, 0xe4, 0x77, 0xee,
-	0x6e, 0xb5, 0x6a, 0x16, 0x32, 0x69, 0x7c, 0x7c, 0xb8, 0xbf, 0x7f, 0xbc, 0x73, 0x87, 0xdf, 0xdb,
-	0xaa, 0x4d, 0xb3, 0x05, 0x98, 0x7d, 0x6f, 0xef, 0x9d, 0xbd, 0xfd, 0xf7, 0xf7, 0xd4, 0xe2, 0x5c,
-	0xf3, 0x57, 0x16, 0x14, 0x51, 0xbd, 0x08, 0xd8, 0x0f, 0xa1, 0x1c, 0x17, 0xc1, 0xec, 0x4a, 0xaa,
-	0x76, 0x36, 0x0b, 0xe3, 0xc6, 0x53, 0xa9, 0x29, 0xed, 0x65, 0x7b, 0x8a, 0xdd, 0x81, 0x4a, 0x2c,
-	0x7c, 0xd4, 0xfc, 0x4f, 0x54, 0x34, 0xff, 0x69, 0x41, 0x4d, 0x39, 0xf8, 0x9e, 0xf0, 0x44, 0xe0,
-	0x44, 0x7e, 0x6c, 0x18, 0x55, 0xb0, 0x63, 0x5a, 0xcd, 0x72, 0xf8, 0x7c, 0xc3, 0xb6, 0x01, 0xee,
-	0x89, 0x48, 0x57, 0x0f, 0x57, 0xb3, 0xe1, 0x52, 0xea, 0x78, 0xe6, 0x1c, 0x2c, 0xd5, 0xaa, 0xee,
-	0x01, 0x24, 0x11, 0xce, 0x12, 0xf4, 0x9f, 0xc0, 0xb0, 0xc6, 0xd5, 0xcc, 0xb9, 0xf8, 0xa4, 0xbf,
-	0xcf, 0xc3, 0x0c, 0x4e, 0xb8, 0x22, 0x60, 0x6f, 0xc1, 0xec, 0x8f, 0x5c, 0xaf, 0x13, 0xff, 0xf8,
-	0xc7, 0x32, 0x7e, 0x2d, 0xd4, 0x6a, 0x1b, 0x59, 0x53, 0x86, 0x0b, 0xaa, 0xfa, 0xe7, 0x84, 0xb6,
-	0xf0, 0x22, 0x76, 0xce, 0x6f, 0x58, 0x8d, 0xa7, 0x27, 0xf8, 0xb1, 0x8a, 0x2d, 0xa8, 0x18, 0xbf,
-	0x8f, 0x99, 0xb7, 0x35, 0xf1, 0xab, 0xd9, 0x45, 0x6a, 0xee, 0x01, 0x24, 0x3d, 0x35, 0xbb, 0xe0,
-	0xeb, 0x5a, 0xe3, 0x6a, 0xe6, 0x5c, 0xac, 0xe8, 0x1d, 0x7d, 0x24, 0xd9, 0x9c, 0x5f, 0xa8, 0xea,
-	0xd9, 0xcc, 0x66, 0xdf, 0x50, 0x76, 0x04, 0xf3, 0x63, 0xbd, 0x2c, 0x7b, 0xd4, 0x27, 0xa2, 0xc6,
-	0xf2, 0xf9, 0x02, 0xb1, 0xde, 0x1f, 0x1b, 0x5f, 0x10, 0x74, 0x8f, 0xfc, 0x68, 0xcd, 0xf6, 0x79,
-	0x02, 0xa6, 0xcd, 0xcd, 0x7f, 0xe5, 0xa0, 0xd6, 0x8a, 0x02, 0xe1, 0xf4, 0x5d, 0xaf, 0xab, 0x43,
-	0xe6, 0x0d, 0x28, 0xaa, 0x1c, 0xf1, 0xb8, 0x2e, 0x5e, 0xb7, 0xf0, 0x3d, 0x3c, 0x11, 0xdf, 0xac,
-	0x5b, 0x6c, 0xf7, 0x09, 0x7a, 0x67, 0xdd, 0x62, 0x1f, 0x7c, 0x33, 0xfe, 0x59, 0xb7, 0xd8, 0x87,
-	0xdf, 0x9c, 0x87, 0xd6, 0x2d, 0x76, 0x00, 0x0b, 0x0a, 0x2b, 0x9e, 0x08, 0x3a, 0xac, 0x5b, 0xcd,
-	0x3f, 0x5a, 0x30, 0xa3, 0x11, 0xeb, 0x38, 0xb3, 0xcf, 0xb0, 0x2f, 0xaa, 0xbe, 0xd5, 0x36, 0xcf,
-	0x5d, 0x28, 0xf3, 0xc4, 0x51, 0x6d, 0xa3, 0xfe, 0xc9, 0x97, 0x4b, 0xd6, 0x67, 0x5f, 0x2e, 0x59,
-	0xff, 0xf8, 0x72, 0xc9, 0xfa, 0xf5, 0x57, 0x4b, 0x53, 0x9f, 0x7d, 0xb5, 0x34, 0xf5, 0xf9, 0x57,
-	0x4b, 0x53, 0x27, 0x45, 0xfa, 0xe7, 0x8e, 0x57, 0xfe, 0x1d, 0x00, 0x00, 0xff, 0xff, 0x35, 0x3c,
-	0x12, 0xda, 0x5d, 0x22, 0x00, 0x00,
+type Exemplar struct {
+	// Identify the span, i.e. trace:id and span:id.
+	Labels      []v1.KeyValue `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels"`
+	Value       float64       `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	TimestampMs int64         `protobuf:"varint,3,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
+func (m *Exemplar) Reset()         { *m = Exemplar{} }
+func (m *Exemplar) String() string { return proto.CompactTextString(m) }
+func (*Exemplar) ProtoMessage()    {}
+func (*Exemplar) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{46}
+}
+func (m *Exemplar) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Exemplar) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Exemplar.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Exemplar) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Exemplar.Merge(m, src)
+}
+func (m *Exemplar) XXX_Size() int {
+	return m.Size()
+}
+func (m *Exemplar) XXX_DiscardUnknown() {
+	xxx_messageInfo_Exemplar.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Exemplar proto.InternalMessageInfo
+
+func (m *Exemplar) GetLabels() []v1.KeyValue {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *Exemplar) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *Exemplar) GetTimestampMs() int64 {
+	if m != nil {
+		return m.TimestampMs
+	}
+	return 0
+}
+
+// QueryResponseV2 is the response envelope of the v2 search and metrics APIs. On success status is "success" and
+// the data of the endpoint is set, otherwise status is "error" and error is set.
+type QueryResponseV2 struct {
+	Status string        `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error  *QueryErrorV2 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	// partial is set when one or more shards failed to complete and the results are incomplete
+	Partial bool `protobuf:"varint,3,opt,name=partial,proto3" json:"partial,omitempty"`
+	// Types that are valid to be assigned to Data:
+	//	*QueryResponseV2_Search
+	//	*QueryResponseV2_QueryRange
+	Data isQueryResponseV2_Data `protobuf_oneof:"data"`
+}
+
+func (m *QueryResponseV2) Reset()         { *m = QueryResponseV2{} }
+func (m *QueryResponseV2) String() string { return proto.CompactTextString(m) }
+func (*QueryResponseV2) ProtoMessage()    {}
+func (*QueryResponseV2) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{47}
+}
+func (m *QueryResponseV2) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryResponseV2) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryResponseV2.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryResponseV2) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryResponseV2.Merge(m, src)
+}
+func (m *QueryResponseV2) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryResponseV2) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryResponseV2.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryResponseV2 proto.InternalMessageInfo
+
+type isQueryResponseV2_Data interface {
+	isQueryResponseV2_Data()
+	MarshalTo([]byte) (int, error)
+	Size() int
+}
+
+type QueryResponseV2_Search struct {
+	Search *SearchResponse `protobuf:"bytes,4,opt,name=search,proto3,oneof" json:"search,omitempty"`
+}
+type QueryResponseV2_QueryRange struct {
+	QueryRange *QueryRangeResponse `protobuf:"bytes,5,opt,name=queryRange,proto3,oneof" json:"queryRange,omitempty"`
+}
+
+func (*QueryResponseV2_Search) isQueryResponseV2_Data()     {}
+func (*QueryResponseV2_QueryRange) isQueryResponseV2_Data() {}
+
+func (m *QueryResponseV2) GetData() isQueryResponseV2_Data {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *QueryResponseV2) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *QueryResponseV2) GetError() *QueryErrorV2 {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *QueryResponseV2) GetPartial() bool {
+	if m != nil {
+		return m.Partial
+	}
+	return false
+}
+
+func (m *QueryResponseV2) GetSearch() *SearchResponse {
+	if x, ok := m.GetData().(*QueryResponseV2_Search); ok {
+		return x.Search
+	}
+	return nil
+}
+
+func (m *QueryResponseV2) GetQueryRange() *QueryRangeResponse {
+	if x, ok := m.GetData().(*QueryResponseV2_QueryRange); ok {
+		return x.QueryRange
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*QueryResponseV2) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*QueryResponseV2_Search)(nil),
+		(*QueryResponseV2_QueryRange)(nil),
+	}
+}
+
+type QueryErrorV2 struct {
+	// code is the HTTP status code of the response
+	Code int32 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	// type is one of bad_request, not_acceptable, too_many_requests, canceled, timeout or internal
+	Type    string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (m *QueryErrorV2) Reset()         { *m = QueryErrorV2{} }
+func (m *QueryErrorV2) String() string { return proto.CompactTextString(m) }
+func (*QueryErrorV2) ProtoMessage()    {}
+func (*QueryErrorV2) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{48}
+}
+func (m *QueryErrorV2) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryErrorV2) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryErrorV2.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryErrorV2) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryErrorV2.Merge(m, src)
+}
+func (m *QueryErrorV2) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryErrorV2) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryErrorV2.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryErrorV2 proto.InternalMessageInfo
+
+func (m *QueryErrorV2) GetCode() int32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *QueryErrorV2) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *QueryErrorV2) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+// PushStreamResponse is returned when the client closes a push stream.
+type PushStreamResponse struct {
+	Batches uint64 `protobuf:"varint,1,opt,name=batches,proto3" json:"batches,omitempty"`
+	Spans   uint64 `protobuf:"varint,2,opt,name=spans,proto3" json:"spans,omitempty"`
+}
+
+func (m *PushStreamResponse) Reset()         { *m = PushStreamResponse{} }
+func (m *PushStreamResponse) String() string { return proto.CompactTextString(m) }
+func (*PushStreamResponse) ProtoMessage()    {}
+func (*PushStreamResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{49}
+}
+func (m *PushStreamResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PushStreamResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PushStreamResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PushStreamResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushStreamResponse.Merge(m, src)
+}
+func (m *PushStreamResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *PushStreamResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushStreamResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushStreamResponse proto.InternalMessageInfo
+
+func (m *PushStreamResponse) GetBatches() uint64 {
+	if m != nil {
+		return m.Batches
+	}
+	return 0
+}
+
+func (m *PushStreamResponse) GetSpans() uint64 {
+	if m != nil {
+		return m.Spans
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterEnum("tempopb.PushErrorReason", PushErrorReason_name, PushErrorReason_value)
+	proto.RegisterEnum("tempopb.DedicatedColumn_Scope", DedicatedColumn_Scope_name, DedicatedColumn_Scope_value)
+	proto.RegisterEnum("tempopb.DedicatedColumn_Type", DedicatedColumn_Type_name, DedicatedColumn_Type_value)
+	proto.RegisterType((*TraceByIDRequest)(nil), "tempopb.TraceByIDRequest")
+	proto.RegisterType((*TraceByIDResponse)(nil), "tempopb.TraceByIDResponse")
+	proto.RegisterType((*TraceByIDMetrics)(nil), "tempopb.TraceByIDMetrics")
+	proto.RegisterType((*SearchRequest)(nil), "tempopb.SearchRequest")
+	proto.RegisterMapType((map[string]string)(nil), "tempopb.SearchRequest.TagsEntry")
+	proto.RegisterType((*SearchBlockRequest)(nil), "tempopb.SearchBlockRequest")
+	proto.RegisterType((*DedicatedColumn)(nil), "tempopb.DedicatedColumn")
+	proto.RegisterType((*SearchResponse)(nil), "tempopb.SearchResponse")
+	proto.RegisterType((*SearchGroup)(nil), "tempopb.SearchGroup")
+	proto.RegisterType((*SearchShardError)(nil), "tempopb.SearchShardError")
+	proto.RegisterType((*TraceSearchMetadata)(nil), "tempopb.TraceSearchMetadata")
+	proto.RegisterMapType((map[string]*ServiceStats)(nil), "tempopb.TraceSearchMetadata.ServiceStatsEntry")
+	proto.RegisterType((*ServiceStats)(nil), "tempopb.ServiceStats")
+	proto.RegisterType((*SpanSet)(nil), "tempopb.SpanSet")
+	proto.RegisterType((*Span)(nil), "tempopb.Span")
+	proto.RegisterType((*SearchMetrics)(nil), "tempopb.SearchMetrics")
+	proto.RegisterType((*SearchTagsRequest)(nil), "tempopb.SearchTagsRequest")
+	proto.RegisterType((*SearchTagsBlockRequest)(nil), "tempopb.SearchTagsBlockRequest")
+	proto.RegisterType((*SearchTagValuesBlockRequest)(nil), "tempopb.SearchTagValuesBlockRequest")
+	proto.RegisterType((*SearchTagsResponse)(nil), "tempopb.SearchTagsResponse")
+	proto.RegisterType((*SearchTagsV2Response)(nil), "tempopb.SearchTagsV2Response")
+	proto.RegisterType((*SearchTagsV2Scope)(nil), "tempopb.SearchTagsV2Scope")
+	proto.RegisterType((*SearchTagValuesRequest)(nil), "tempopb.SearchTagValuesRequest")
+	proto.RegisterType((*SearchTagValuesResponse)(nil), "tempopb.SearchTagValuesResponse")
+	proto.RegisterType((*TagValue)(nil), "tempopb.TagValue")
+	proto.RegisterType((*SearchTagValuesV2Response)(nil), "tempopb.SearchTagValuesV2Response")
+	proto.RegisterType((*Trace)(nil), "tempopb.Trace")
+	proto.RegisterType((*PushResponse)(nil), "tempopb.PushResponse")
+	proto.RegisterType((*PushBytesRequest)(nil), "tempopb.PushBytesRequest")
+	proto.RegisterType((*PushSpansRequest)(nil), "tempopb.PushSpansRequest")
+	proto.RegisterType((*TraceBytes)(nil), "tempopb.TraceBytes")
+	proto.RegisterType((*LinkSlice)(nil), "tempopb.LinkSlice")
+	proto.RegisterType((*SpanMetricsRequest)(nil), "tempopb.SpanMetricsRequest")
+	proto.RegisterType((*SpanMetricsSummaryRequest)(nil), "tempopb.SpanMetricsSummaryRequest")
+	proto.RegisterType((*SpanMetricsResponse)(nil), "tempopb.SpanMetricsResponse")
+	proto.RegisterType((*RawHistogram)(nil), "tempopb.RawHistogram")
+	proto.RegisterType((*KeyValue)(nil), "tempopb.KeyValue")
+	proto.RegisterType((*SpanMetrics)(nil), "tempopb.SpanMetrics")
+	proto.RegisterType((*SpanMetricsSummary)(nil), "tempopb.SpanMetricsSummary")
+	proto.RegisterType((*SpanMetricsSummaryResponse)(nil), "tempopb.SpanMetricsSummaryResponse")
+	proto.RegisterType((*TraceQLStatic)(nil), "tempopb.TraceQLStatic")
+	proto.RegisterType((*SpanMetricsData)(nil), "tempopb.SpanMetricsData")
+	proto.RegisterType((*SpanMetricsResult)(nil), "tempopb.SpanMetricsResult")
+	proto.RegisterType((*SpanMetricsResultPoint)(nil), "tempopb.SpanMetricsResultPoint")
+	proto.RegisterType((*QueryRangeRequest)(nil), "tempopb.QueryRangeRequest")
+	proto.RegisterType((*QueryRangeResponse)(nil), "tempopb.QueryRangeResponse")
+	proto.RegisterType((*Sample)(nil), "tempopb.Sample")
+	proto.RegisterType((*TimeSeries)(nil), "tempopb.TimeSeries")
+	proto.RegisterType((*Exemplar)(nil), "tempopb.Exemplar")
+	proto.RegisterType((*QueryResponseV2)(nil), "tempopb.QueryResponseV2")
+	proto.RegisterType((*QueryErrorV2)(nil), "tempopb.QueryErrorV2")
+	proto.RegisterType((*PushStreamResponse)(nil), "tempopb.PushStreamResponse")
+}
+
+func init() { proto.RegisterFile("pkg/tempopb/tempo.proto", fileDescriptor_f22805646f4f62b6) }
+
+var fileDescriptor_f22805646f4f62b6 = []byte{
+	// 3003 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x3a, 0xcd, 0x6f, 0x1b, 0xc7,
+	0xf5, 0x5a, 0xf1, 0x43, 0xe4, 0x23, 0x65, 0x51, 0x63, 0xc7, 0x59, 0xd3, 0x89, 0xac, 0xdf, 0xc6,
+	0xf8, 0x55, 0x4d, 0x1c, 0x49, 0x66, 0x6c, 0x24, 0x8e, 0x9b, 0x14, 0x96, 0xa5, 0xd8, 0x4a, 0x24,
+	0x59, 0x19, 0x2a, 0x4a, 0x50, 0x04, 0x10, 0x56, 0xe4, 0x58, 0x5e, 0x88, 0xdc, 0x65, 0x76, 0x97,
+	0xaa, 0x55, 0x14, 0x3d, 0x14, 0x68, 0x81, 0x02, 0x3d, 0xf4, 0xd0, 0x1e, 0x7a, 0xec, 0xa5, 0x45,
+	0xcf, 0xfd, 0x13, 0x8a, 0x16, 0xb9, 0x34, 0x08, 0xd0, 0x4b, 0xd0, 0x43, 0x50, 0x24, 0xb7, 0x5e,
+	0x7b, 0x2c, 0x0a, 0x14, 0xef, 0xcd, 0xcc, 0xee, 0xec, 0x92, 0x92, 0xe3, 0xc6, 0x41, 0x73, 0xc8,
+	0x49, 0xf3, 0xde, 0xbc, 0x79, 0xfb, 0xe6, 0xcd, 0xfb, 0xa6, 0xe0, 0xe9, 0xc1, 0xe1, 0xc1, 0x52,
+	0x2c, 0xfa, 0x83, 0x60, 0xb0, 0x2f, 0xff, 0x2e, 0x0e, 0xc2, 0x20, 0x0e, 0xd8, 0x94, 0x42, 0x36,
+	0xcf, 0x77, 0x82, 0x7e, 0x3f, 0xf0, 0x97, 0x8e, 0xae, 0x2e, 0xc9, 0x95, 0x24, 0x68, 0xbe, 0x78,
+	0xe0, 0xc5, 0x0f, 0x86, 0xfb, 0x8b, 0x9d, 0xa0, 0xbf, 0x74, 0x10, 0x1c, 0x04, 0x4b, 0x84, 0xde,
+	0x1f, 0xde, 0x27, 0x88, 0x00, 0x5a, 0x29, 0xf2, 0x73, 0x71, 0xe8, 0x76, 0x04, 0x72, 0xa1, 0x85,
+	0xc4, 0x3a, 0xbf, 0xb5, 0xa0, 0xb1, 0x83, 0xf0, 0xca, 0xf1, 0xfa, 0x2a, 0x17, 0x1f, 0x0c, 0x45,
+	0x14, 0x33, 0x1b, 0xa6, 0x88, 0x66, 0x7d, 0xd5, 0xb6, 0xe6, 0xad, 0x85, 0x3a, 0xd7, 0x20, 0x9b,
+	0x03, 0xd8, 0xef, 0x05, 0x9d, 0xc3, 0x76, 0xec, 0x86, 0xb1, 0x3d, 0x39, 0x6f, 0x2d, 0x54, 0xb9,
+	0x81, 0x61, 0x4d, 0xa8, 0x10, 0xb4, 0xe6, 0x77, 0xed, 0x02, 0xed, 0x26, 0x30, 0x7b, 0x06, 0xaa,
+	0x1f, 0x0c, 0x45, 0x78, 0xbc, 0x19, 0x74, 0x85, 0x5d, 0xa2, 0xcd, 0x14, 0x81, 0x9c, 0xa3, 0x81,
+	0xeb, 0xbf, 0xe1, 0xf5, 0x62, 0x11, 0xda, 0x65, 0xc9, 0x39, 0xc5, 0x38, 0x3e, 0xcc, 0x1a, 0x72,
+	0x46, 0x83, 0xc0, 0x8f, 0x04, 0xbb, 0x0c, 0x25, 0x92, 0x8c, 0xc4, 0xac, 0xb5, 0xce, 0x2c, 0x2a,
+	0x9d, 0x2d, 0x12, 0x29, 0x97, 0x9b, 0xec, 0x25, 0x98, 0xea, 0x8b, 0x38, 0xf4, 0x3a, 0x11, 0x49,
+	0x5c, 0x6b, 0x5d, 0xc8, 0xd2, 0x21, 0xcb, 0x4d, 0x49, 0xc0, 0x35, 0xa5, 0xc3, 0xa0, 0x91, 0xdf,
+	0x74, 0x3e, 0x9a, 0x84, 0xe9, 0xb6, 0x70, 0xc3, 0xce, 0x03, 0xad, 0xa9, 0x57, 0xa1, 0xb8, 0xe3,
+	0x1e, 0x44, 0xb6, 0x35, 0x5f, 0x58, 0xa8, 0xb5, 0xe6, 0x13, 0xbe, 0x19, 0xaa, 0x45, 0x24, 0x59,
+	0xf3, 0xe3, 0xf0, 0x78, 0xa5, 0xf8, 0xe1, 0xa7, 0x97, 0x26, 0x38, 0x9d, 0x61, 0x97, 0x61, 0x7a,
+	0xd3, 0xf3, 0x57, 0x87, 0xa1, 0x1b, 0x7b, 0x81, 0xbf, 0x29, 0x85, 0x9b, 0xe6, 0x59, 0x24, 0x51,
+	0xb9, 0x0f, 0x0d, 0xaa, 0x82, 0xa2, 0x32, 0x91, 0xec, 0x1c, 0x94, 0x36, 0xbc, 0xbe, 0x17, 0xdb,
+	0x45, 0xda, 0x95, 0x00, 0x62, 0x23, 0x7a, 0xa8, 0x92, 0xc4, 0x12, 0xc0, 0x1a, 0x50, 0x10, 0x7e,
+	0x97, 0x54, 0x3c, 0xcd, 0x71, 0x89, 0x74, 0x6f, 0xe3, 0x43, 0xd8, 0x15, 0x52, 0xbb, 0x04, 0xd8,
+	0x02, 0xcc, 0xb4, 0x07, 0xae, 0x1f, 0x6d, 0x8b, 0x10, 0xff, 0xb6, 0x45, 0x6c, 0x57, 0xe9, 0x4c,
+	0x1e, 0xdd, 0x7c, 0x19, 0xaa, 0xc9, 0x15, 0x91, 0xfd, 0xa1, 0x38, 0xa6, 0x17, 0xa9, 0x72, 0x5c,
+	0x22, 0xfb, 0x23, 0xb7, 0x37, 0x14, 0xca, 0x5e, 0x24, 0xf0, 0xea, 0xe4, 0x2b, 0x96, 0xf3, 0xe7,
+	0x02, 0x30, 0xa9, 0xaa, 0x15, 0xb4, 0x12, 0xad, 0xd5, 0x6b, 0x50, 0x8d, 0xb4, 0x02, 0xd5, 0xd3,
+	0x9e, 0x1f, 0xaf, 0x5a, 0x9e, 0x12, 0xa2, 0xd5, 0x92, 0xad, 0xad, 0xaf, 0xaa, 0x0f, 0x69, 0x10,
+	0x2d, 0x8f, 0xae, 0xbe, 0xed, 0x1e, 0x08, 0xa5, 0xbf, 0x14, 0x81, 0x1a, 0x1e, 0xb8, 0x07, 0x22,
+	0xda, 0x09, 0x24, 0x6b, 0xa5, 0xc3, 0x2c, 0x12, 0x2d, 0x5b, 0xf8, 0x9d, 0xa0, 0xeb, 0xf9, 0x07,
+	0xca, 0x78, 0x13, 0x18, 0x39, 0x78, 0x7e, 0x57, 0x3c, 0x44, 0x76, 0x6d, 0xef, 0x07, 0x42, 0xe9,
+	0x36, 0x8b, 0x64, 0x0e, 0xd4, 0xe3, 0x20, 0x76, 0x7b, 0x5c, 0x74, 0x82, 0xb0, 0x1b, 0xd9, 0x53,
+	0x44, 0x94, 0xc1, 0x21, 0x4d, 0xd7, 0x8d, 0xdd, 0x35, 0xfd, 0x25, 0xf9, 0x20, 0x19, 0x1c, 0xde,
+	0xf3, 0x48, 0x84, 0x91, 0x17, 0xf8, 0xf4, 0x1e, 0x55, 0xae, 0x41, 0xc6, 0xa0, 0x18, 0xe1, 0xe7,
+	0x61, 0xde, 0x5a, 0x28, 0x72, 0x5a, 0xa3, 0x5f, 0xdd, 0x0f, 0x82, 0x58, 0x84, 0x24, 0x58, 0x8d,
+	0xbe, 0x69, 0x60, 0xd8, 0x2a, 0x34, 0xba, 0xa2, 0xeb, 0x75, 0xdc, 0x58, 0x74, 0x6f, 0x07, 0xbd,
+	0x61, 0xdf, 0x8f, 0xec, 0x3a, 0x59, 0xb3, 0x9d, 0xa8, 0x7c, 0x35, 0x4b, 0xc0, 0x47, 0x4e, 0x38,
+	0x7f, 0xb4, 0x60, 0x26, 0x47, 0xc5, 0xae, 0x41, 0x29, 0xea, 0x04, 0x03, 0xa9, 0xf1, 0x33, 0xad,
+	0xb9, 0x93, 0xd8, 0x2d, 0xb6, 0x91, 0x8a, 0x4b, 0x62, 0xbc, 0x83, 0xef, 0xf6, 0xb5, 0xad, 0xd0,
+	0x9a, 0x5d, 0x85, 0x62, 0x7c, 0x3c, 0x90, 0x5e, 0x7e, 0xa6, 0xf5, 0xec, 0x89, 0x8c, 0x76, 0x8e,
+	0x07, 0x82, 0x13, 0xa9, 0x73, 0x09, 0x4a, 0xc4, 0x96, 0x55, 0xa0, 0xd8, 0xde, 0xbe, 0xb5, 0xd5,
+	0x98, 0x60, 0x75, 0xa8, 0xf0, 0xb5, 0xf6, 0xbd, 0x77, 0xf8, 0xed, 0xb5, 0x86, 0xe5, 0x30, 0x28,
+	0x22, 0x39, 0x03, 0x28, 0xb7, 0x77, 0xf8, 0xfa, 0xd6, 0x9d, 0xc6, 0x84, 0xf3, 0x6f, 0x0b, 0xce,
+	0x68, 0xf3, 0x52, 0x11, 0xe6, 0x1a, 0x94, 0x29, 0x88, 0x68, 0x17, 0x7f, 0x26, 0x1b, 0x3a, 0x24,
+	0xf5, 0xa6, 0x88, 0x5d, 0x7c, 0x22, 0xae, 0x68, 0xd9, 0x72, 0x3e, 0xe2, 0xe4, 0xcd, 0x37, 0x1f,
+	0x6e, 0xf0, 0x51, 0x07, 0x6e, 0x18, 0x7b, 0x6e, 0x8f, 0xd4, 0x55, 0xe1, 0x1a, 0x64, 0x37, 0xa1,
+	0x16, 0x3d, 0x70, 0xc3, 0xee, 0x5a, 0x18, 0x06, 0x61, 0x64, 0x17, 0xe7, 0x0b, 0x99, 0x08, 0x26,
+	0xf9, 0xb5, 0x13, 0x0a, 0x6e, 0x52, 0xb3, 0x2b, 0x50, 0x3e, 0x08, 0x83, 0xe1, 0x20, 0xb2, 0x4b,
+	0x74, 0xee, 0x5c, 0xee, 0xdc, 0x1d, 0xdc, 0xe4, 0x8a, 0xc6, 0xf9, 0x21, 0xd4, 0x0c, 0x34, 0xbb,
+	0x09, 0xe0, 0xc6, 0x71, 0xe8, 0xed, 0x0f, 0xe3, 0xe4, 0xfe, 0x17, 0x13, 0x06, 0x2a, 0x17, 0x1d,
+	0x5d, 0x5d, 0x7c, 0x4b, 0x1c, 0xef, 0xa2, 0x4b, 0x73, 0x83, 0x9c, 0x9d, 0x4f, 0x14, 0x27, 0xc3,
+	0x9a, 0x82, 0xf0, 0xa2, 0x7d, 0x37, 0xee, 0x3c, 0x10, 0x5d, 0xe5, 0x89, 0x1a, 0x74, 0x7e, 0x6a,
+	0x41, 0x23, 0x7f, 0x1b, 0xd3, 0xa9, 0xad, 0x53, 0x9c, 0x7a, 0xf2, 0x91, 0x4e, 0x5d, 0x18, 0xe7,
+	0xd4, 0xe7, 0xa0, 0x24, 0xf0, 0x33, 0xe4, 0xf2, 0x55, 0x2e, 0x01, 0xe7, 0xaf, 0x05, 0x38, 0x3b,
+	0xe6, 0x75, 0xf3, 0x69, 0xb1, 0x9a, 0xa6, 0xc5, 0x05, 0x98, 0x09, 0x83, 0x20, 0x6e, 0x8b, 0xf0,
+	0xc8, 0xeb, 0x88, 0xad, 0xd4, 0x7e, 0xf3, 0x68, 0x94, 0x0b, 0x51, 0xc4, 0x9e, 0xe8, 0x64, 0x96,
+	0xcc, 0x22, 0xd9, 0x15, 0x98, 0xa5, 0xab, 0xec, 0x78, 0x7d, 0xf1, 0x8e, 0xef, 0x3d, 0xdc, 0x72,
+	0xfd, 0x80, 0x64, 0x2c, 0xf2, 0xd1, 0x0d, 0x74, 0xf1, 0x6e, 0x9a, 0x1f, 0x64, 0xac, 0x37, 0x30,
+	0xec, 0x79, 0x98, 0x8a, 0x54, 0x00, 0x2f, 0x93, 0x35, 0x36, 0x52, 0x2b, 0x90, 0x78, 0xae, 0x09,
+	0xd8, 0x15, 0xa8, 0xa8, 0x25, 0x06, 0xa8, 0xc2, 0x58, 0xe2, 0x84, 0x82, 0x71, 0xa8, 0x47, 0xf2,
+	0x72, 0xed, 0xd8, 0x8d, 0x23, 0xbb, 0x42, 0x27, 0x16, 0x4f, 0xf3, 0x91, 0xc5, 0xb6, 0x71, 0x80,
+	0x32, 0x06, 0xcf, 0xf0, 0x68, 0xee, 0xc2, 0xec, 0x08, 0xc9, 0x98, 0xa4, 0xf2, 0x82, 0x99, 0x54,
+	0x6a, 0xad, 0xa7, 0x0c, 0xc3, 0x4e, 0x0f, 0x9b, 0xb9, 0x66, 0x03, 0xea, 0xe6, 0x16, 0xd9, 0xcf,
+	0xc0, 0xf5, 0x6f, 0x07, 0x43, 0x3f, 0xb6, 0x2d, 0x65, 0x3f, 0x1a, 0x81, 0x3a, 0x25, 0x63, 0x90,
+	0xdb, 0xd2, 0xbc, 0x0c, 0x8c, 0xf3, 0x13, 0x0b, 0xa6, 0x94, 0x3e, 0xd8, 0x73, 0x50, 0xc2, 0x83,
+	0xda, 0x45, 0xa6, 0x33, 0x0a, 0xe3, 0x72, 0xcf, 0xb4, 0xfb, 0xc9, 0x8c, 0xdd, 0xe7, 0xdc, 0xac,
+	0xf0, 0x58, 0x6e, 0x86, 0x81, 0xb7, 0x88, 0x9f, 0x41, 0x7f, 0xc3, 0x0f, 0x25, 0xb6, 0xa9, 0xa0,
+	0xb1, 0xf1, 0x74, 0xac, 0x79, 0x15, 0x4e, 0x32, 0xaf, 0xcb, 0x30, 0xad, 0x8d, 0x09, 0xe1, 0x48,
+	0x19, 0x62, 0x16, 0x99, 0xbb, 0x45, 0xe9, 0xf1, 0x6e, 0xf1, 0xeb, 0xa4, 0xb0, 0x52, 0x81, 0x11,
+	0x3d, 0xca, 0xf3, 0xa3, 0x81, 0xe8, 0xc4, 0xa2, 0xbb, 0xa3, 0x03, 0x30, 0x15, 0x1f, 0x39, 0x34,
+	0xfb, 0x7f, 0x38, 0x93, 0xa0, 0x56, 0x8e, 0x63, 0x15, 0x70, 0x8a, 0x3c, 0x87, 0x65, 0xf3, 0x50,
+	0xa3, 0x54, 0x4b, 0x95, 0x86, 0x2e, 0xa3, 0x4c, 0x14, 0x5e, 0xb4, 0x13, 0xf4, 0x07, 0x3d, 0x11,
+	0x8b, 0xee, 0x9b, 0xc1, 0x7e, 0xa4, 0x0b, 0x81, 0x0c, 0x12, 0xed, 0x86, 0x0e, 0x11, 0x85, 0x74,
+	0xb6, 0x14, 0x81, 0x72, 0xa7, 0x2c, 0xa5, 0x38, 0x65, 0x12, 0x27, 0x8f, 0xce, 0xc8, 0x4d, 0x05,
+	0x95, 0x3d, 0x95, 0x93, 0x9b, 0xb0, 0xce, 0xdb, 0x30, 0x2b, 0x55, 0x83, 0x25, 0x96, 0xae, 0x90,
+	0xce, 0xe9, 0xdc, 0x2a, 0x1f, 0x5b, 0x02, 0x69, 0xbd, 0x57, 0x18, 0x53, 0xef, 0x15, 0x93, 0x7a,
+	0xcf, 0xf9, 0xa8, 0x00, 0xe7, 0x53, 0x9e, 0x99, 0xd2, 0xeb, 0x95, 0xd1, 0xd2, 0xab, 0x99, 0xcb,
+	0x19, 0x86, 0x1c, 0xdf, 0x94, 0x5f, 0x5f, 0x8f, 0xf2, 0xeb, 0x93, 0x02, 0x5c, 0x4c, 0x1e, 0x87,
+	0xdc, 0x2b, 0xfb, 0xaa, 0xaf, 0x8d, 0xbe, 0xea, 0xa5, 0xd1, 0x57, 0x95, 0x07, 0xbf, 0x79, 0xda,
+	0xaf, 0xd5, 0xd3, 0x2e, 0x03, 0x33, 0xdd, 0x4e, 0x95, 0xa5, 0x4d, 0xa8, 0xc4, 0xee, 0x01, 0xd6,
+	0x0a, 0x32, 0xeb, 0x54, 0x79, 0x02, 0x3b, 0x6f, 0xc2, 0xb9, 0xf4, 0xc4, 0x6e, 0x2b, 0x39, 0xd3,
+	0x82, 0x32, 0x85, 0x09, 0x9d, 0xa7, 0xc6, 0xf9, 0xf5, 0x6e, 0x4b, 0x16, 0xe3, 0x8a, 0xd2, 0xb9,
+	0x09, 0xb3, 0x23, 0x9b, 0x49, 0x4a, 0xb1, 0x8c, 0x94, 0xc2, 0xa0, 0x18, 0x63, 0x23, 0x3c, 0x49,
+	0xc2, 0xd0, 0xda, 0x19, 0xc0, 0xf9, 0xf1, 0xb6, 0x45, 0x95, 0x94, 0x14, 0x37, 0xa9, 0xa4, 0x24,
+	0x88, 0x21, 0x8c, 0x66, 0x02, 0xba, 0x57, 0x24, 0x20, 0x0d, 0x6c, 0xc5, 0x31, 0x81, 0xad, 0x94,
+	0x06, 0xb6, 0x97, 0xe1, 0xe9, 0x91, 0x2f, 0xaa, 0xdb, 0x63, 0xd8, 0xd6, 0x48, 0xa5, 0xb2, 0x14,
+	0xe1, 0x5c, 0x83, 0x8a, 0x3e, 0xc2, 0x98, 0xd1, 0x6d, 0x54, 0x65, 0x3b, 0x31, 0xbe, 0x85, 0x75,
+	0x36, 0xe0, 0x42, 0xee, 0x73, 0x86, 0xba, 0x97, 0xf2, 0x1f, 0xac, 0xb5, 0x66, 0xd3, 0xc2, 0x48,
+	0xed, 0x98, 0x32, 0xac, 0x40, 0x89, 0x52, 0x1a, 0xbb, 0x01, 0x53, 0xfb, 0x54, 0x1b, 0xe8, 0x73,
+	0xa9, 0xaf, 0xca, 0xd1, 0xcd, 0xd1, 0xd5, 0x45, 0x2e, 0xa2, 0x60, 0x18, 0x76, 0x04, 0xe5, 0x08,
+	0xae, 0xe9, 0x9d, 0x2d, 0xa8, 0x6f, 0x0f, 0xa3, 0xb4, 0x7d, 0x79, 0x1d, 0xa6, 0xa9, 0x68, 0x89,
+	0x56, 0x8e, 0x77, 0xd4, 0xa0, 0xa4, 0xb0, 0x70, 0xc6, 0x30, 0x40, 0xa4, 0x96, 0x7d, 0x83, 0x70,
+	0xa3, 0xc0, 0xe7, 0x59, 0x72, 0xe7, 0x37, 0x16, 0x34, 0x90, 0x84, 0x52, 0x96, 0x7e, 0xbd, 0x17,
+	0x8d, 0xd2, 0xbe, 0xb0, 0x50, 0x5f, 0x79, 0x0a, 0x87, 0x1a, 0x7f, 0xfb, 0xf4, 0xd2, 0xf4, 0x76,
+	0x28, 0xdc, 0x5e, 0x2f, 0xe8, 0x48, 0x6a, 0x45, 0xc4, 0xbe, 0x05, 0x05, 0xaf, 0x2b, 0x0b, 0x9b,
+	0x13, 0x69, 0x91, 0x82, 0x5d, 0x07, 0x90, 0x31, 0x67, 0xd5, 0x8d, 0x5d, 0xbb, 0x78, 0x1a, 0xbd,
+	0x41, 0xe8, 0x6c, 0x4a, 0x11, 0xa5, 0x26, 0x94, 0x88, 0x5f, 0x42, 0x85, 0x97, 0x01, 0xd4, 0xe0,
+	0x27, 0xdb, 0xc6, 0x20, 0x9f, 0xba, 0xbe, 0x94, 0xf3, 0x3a, 0x54, 0x37, 0x3c, 0xff, 0xb0, 0xdd,
+	0xf3, 0x3a, 0xd8, 0x9f, 0x96, 0x7a, 0x9e, 0x7f, 0x38, 0xda, 0x23, 0x25, 0xdf, 0xc2, 0x6f, 0x2c,
+	0xe2, 0x01, 0x2e, 0x29, 0x9d, 0x1f, 0x5b, 0xc0, 0x10, 0xa9, 0x1b, 0xc1, 0x34, 0xaf, 0x4b, 0xf3,
+	0xb7, 0x4c, 0xf3, 0xb7, 0x61, 0x8a, 0x3a, 0xb4, 0x15, 0xed, 0x16, 0x1a, 0x44, 0xfa, 0x1e, 0xcd,
+	0x7d, 0x64, 0xf5, 0x26, 0x81, 0x2f, 0xec, 0x2e, 0x3f, 0xb3, 0xe0, 0x82, 0x21, 0x44, 0x7b, 0xd8,
+	0xef, 0xbb, 0xe1, 0xf1, 0xff, 0x46, 0x96, 0xdf, 0x5b, 0x70, 0x36, 0xa3, 0x90, 0xd4, 0x6f, 0x45,
+	0x14, 0x7b, 0x7d, 0x8c, 0x89, 0x24, 0x49, 0x85, 0xa7, 0x88, 0x6c, 0x11, 0x2f, 0xeb, 0xbe, 0x14,
+	0x81, 0x25, 0x16, 0x99, 0x73, 0x3b, 0x21, 0x91, 0xa2, 0xe5, 0xb0, 0x6c, 0x31, 0x6d, 0xd7, 0x8b,
+	0xf9, 0x36, 0xd9, 0x10, 0x49, 0x13, 0x39, 0xdf, 0x81, 0x3a, 0x77, 0xbf, 0x7f, 0xd7, 0x8b, 0xe2,
+	0xe0, 0x20, 0x74, 0xfb, 0x68, 0x24, 0xfb, 0xc3, 0xce, 0xa1, 0x90, 0x7d, 0x44, 0x91, 0x2b, 0x08,
+	0xef, 0xde, 0x31, 0x24, 0x93, 0x80, 0xf3, 0x26, 0x54, 0x74, 0x11, 0x3c, 0xa6, 0xaf, 0xb9, 0x92,
+	0xed, 0x6b, 0xce, 0x67, 0x7b, 0xa9, 0xb7, 0x37, 0xb0, 0x79, 0xf1, 0x3a, 0x3a, 0x02, 0xfd, 0xd2,
+	0x82, 0x9a, 0x21, 0x22, 0x5b, 0x81, 0xd9, 0x9e, 0x1b, 0x0b, 0xbf, 0x73, 0xbc, 0xf7, 0x40, 0x8b,
+	0xa7, 0xac, 0x32, 0xed, 0x90, 0x4c, 0xd9, 0x79, 0x43, 0xd1, 0xa7, 0xb7, 0xf9, 0x36, 0x94, 0x23,
+	0x11, 0x7a, 0xca, 0xbd, 0xcd, 0xa8, 0x95, 0xd4, 0xee, 0x8a, 0x00, 0x2f, 0x2e, 0xe3, 0x85, 0x52,
+	0xac, 0x82, 0x9c, 0xbf, 0x64, 0xad, 0x5b, 0x19, 0xd6, 0x68, 0xcb, 0xf5, 0x88, 0xd7, 0x9a, 0x1c,
+	0xfb, 0x5a, 0xa9, 0x7c, 0x85, 0x47, 0xc9, 0xd7, 0x80, 0xc2, 0xe0, 0xc6, 0x0d, 0xd5, 0xb0, 0xe0,
+	0x52, 0x62, 0xae, 0xdb, 0x25, 0x8d, 0xb9, 0x2e, 0x31, 0xcb, 0xaa, 0x4a, 0xc7, 0x25, 0x61, 0xae,
+	0x2f, 0xab, 0x72, 0x1c, 0x97, 0xce, 0xbb, 0xd0, 0x1c, 0xe7, 0x27, 0xca, 0x44, 0x6f, 0x40, 0x35,
+	0x22, 0x94, 0x37, 0x66, 0x4c, 0x32, 0xe6, 0x5c, 0x4a, 0xed, 0xfc, 0xca, 0x82, 0xe9, 0xcc, 0xc3,
+	0x66, 0xb2, 0x4f, 0x49, 0x65, 0x9f, 0x3a, 0x58, 0x3e, 0x29, 0xa3, 0xc0, 0x2d, 0x1f, 0xa1, 0xfb,
+	0xa4, 0x6f, 0x8b, 0x5b, 0xf7, 0x11, 0x8a, 0xd4, 0xf8, 0xc2, 0x8a, 0x10, 0xda, 0xa7, 0xcb, 0x55,
+	0xb8, 0xb5, 0x8f, 0x50, 0x57, 0x5d, 0xcc, 0xea, 0x52, 0x87, 0x18, 0xbb, 0xf1, 0x50, 0xd6, 0x47,
+	0x25, 0xae, 0x20, 0xfc, 0xe2, 0xa1, 0xe7, 0x77, 0xa9, 0x22, 0x2a, 0x71, 0x5a, 0x3b, 0x02, 0x66,
+	0x0c, 0xc1, 0x31, 0xcc, 0x62, 0xb9, 0x13, 0x8a, 0x68, 0xd8, 0x8b, 0x77, 0xd2, 0xe4, 0x68, 0x60,
+	0xb0, 0xbc, 0x90, 0x90, 0x3d, 0x99, 0x2f, 0x2f, 0x32, 0x6e, 0x3d, 0xec, 0xc5, 0x5c, 0x51, 0x62,
+	0x14, 0x9c, 0x1d, 0xd9, 0x45, 0x33, 0xe9, 0xb9, 0xfb, 0xa2, 0x67, 0xd4, 0x07, 0x29, 0x02, 0xe5,
+	0x20, 0x60, 0xd7, 0xc8, 0xc7, 0x06, 0x86, 0x2d, 0xc1, 0x64, 0xac, 0x4d, 0xe3, 0xd2, 0xc9, 0x32,
+	0x6c, 0x07, 0x9e, 0x1f, 0xf3, 0xc9, 0x38, 0x42, 0x1f, 0x3a, 0x3f, 0x7e, 0x9b, 0x1e, 0xc3, 0x53,
+	0x42, 0x4c, 0x73, 0x5a, 0xa3, 0x75, 0x1c, 0xb9, 0x3d, 0xfa, 0xb0, 0xc5, 0x71, 0x89, 0x3d, 0x9f,
+	0x78, 0x28, 0xfa, 0x83, 0x9e, 0x1b, 0xee, 0xa8, 0xf9, 0x50, 0x81, 0x7e, 0x36, 0xc9, 0xa3, 0xd9,
+	0xf3, 0xd0, 0xd0, 0x28, 0x3d, 0xbc, 0x57, 0xc6, 0x39, 0x82, 0x77, 0xfe, 0x54, 0x84, 0x59, 0x1a,
+	0xc4, 0x73, 0xd7, 0x3f, 0x10, 0xa7, 0x07, 0xe5, 0x24, 0xc8, 0xaa, 0x40, 0x93, 0x09, 0xb2, 0xd2,
+	0x35, 0x71, 0x89, 0xf7, 0x89, 0x62, 0x31, 0x50, 0xdf, 0xa4, 0x35, 0x06, 0x74, 0x9a, 0x18, 0xae,
+	0xaf, 0xaa, 0x70, 0xac, 0x41, 0xd4, 0x34, 0x2d, 0xa5, 0x33, 0xca, 0xca, 0xdb, 0xc0, 0x64, 0x7f,
+	0xd0, 0x99, 0xca, 0xff, 0xa0, 0x63, 0x34, 0x0d, 0x95, 0x53, 0x9a, 0x86, 0xea, 0x23, 0x9b, 0x06,
+	0x18, 0xd7, 0x34, 0x18, 0xa5, 0x7a, 0x2d, 0x5b, 0xaa, 0x9b, 0xed, 0x44, 0x3d, 0xd7, 0x4e, 0xe8,
+	0x32, 0x7e, 0xfa, 0xc4, 0x32, 0xfe, 0xcc, 0x17, 0x2a, 0xe3, 0x67, 0x1e, 0xb7, 0x8c, 0xa7, 0x34,
+	0xa6, 0x5e, 0x38, 0xb2, 0x1b, 0xf2, 0xce, 0x09, 0x82, 0x42, 0x9f, 0x02, 0xb6, 0x83, 0x9e, 0xd7,
+	0x39, 0xb6, 0x67, 0x49, 0xf2, 0x1c, 0x16, 0x5d, 0x38, 0xb8, 0x7f, 0x3f, 0x12, 0xb1, 0xcd, 0x64,
+	0xbc, 0x95, 0x10, 0xde, 0xb9, 0x17, 0x04, 0x87, 0xfb, 0x6e, 0xe7, 0xd0, 0x3e, 0x4b, 0x3b, 0x09,
+	0xec, 0x44, 0xc0, 0x4c, 0x33, 0x52, 0x31, 0xeb, 0x85, 0x24, 0x88, 0xca, 0x80, 0x75, 0x36, 0xcd,
+	0x33, 0x5e, 0x5f, 0xb4, 0x69, 0x2b, 0x09, 0xa3, 0x8f, 0x3d, 0xce, 0x76, 0x6e, 0x41, 0xb9, 0xed,
+	0xe2, 0xd4, 0x84, 0xfd, 0x1f, 0xd4, 0xd1, 0x6d, 0xa2, 0xd8, 0xed, 0x0f, 0xf6, 0xfa, 0x91, 0x0a,
+	0x63, 0xb5, 0x04, 0x27, 0x7f, 0xbc, 0x92, 0x29, 0xcf, 0x22, 0x9f, 0x92, 0x80, 0xf3, 0xb1, 0x05,
+	0x90, 0xca, 0xc2, 0x6e, 0x40, 0x99, 0x9c, 0xfc, 0x8b, 0x0c, 0xa2, 0xd5, 0xcf, 0x6c, 0xea, 0x00,
+	0x5b, 0x82, 0xa9, 0x88, 0x84, 0xd1, 0x19, 0x6d, 0x26, 0x15, 0x9f, 0xf0, 0x8a, 0x5e, 0x53, 0xb1,
+	0x4b, 0x50, 0x1b, 0x84, 0x41, 0x7f, 0x4f, 0x7d, 0x50, 0x8e, 0x68, 0x01, 0x51, 0x1b, 0x92, 0xe3,
+	0x75, 0xf3, 0x35, 0x8b, 0xb9, 0x2c, 0xb4, 0xa6, 0x76, 0x14, 0xd7, 0x94, 0xd2, 0xf9, 0x11, 0x54,
+	0xf4, 0xe6, 0x97, 0xb9, 0x4f, 0xa6, 0x19, 0xd1, 0xfa, 0x1a, 0x51, 0x74, 0x61, 0x44, 0xd1, 0xce,
+	0x3f, 0x2c, 0x98, 0x91, 0xb6, 0xa0, 0xcc, 0x60, 0xb7, 0x65, 0x64, 0x05, 0x3d, 0x37, 0x24, 0x08,
+	0xe7, 0xab, 0x72, 0x34, 0x9e, 0x9f, 0xaf, 0x12, 0x03, 0x6a, 0x19, 0x76, 0x5b, 0x6a, 0x62, 0x7e,
+	0xca, 0xaf, 0x17, 0x57, 0xd1, 0xce, 0x92, 0xde, 0xbf, 0xd6, 0x7a, 0x7a, 0xe4, 0x77, 0x3c, 0x29,
+	0xc9, 0xdd, 0x09, 0xae, 0x08, 0xd9, 0x6b, 0x00, 0x1f, 0x24, 0x06, 0x4b, 0x31, 0xc9, 0xd4, 0xce,
+	0xa8, 0x2d, 0xdf, 0x9d, 0xe0, 0xc6, 0x81, 0x95, 0x32, 0x14, 0xb1, 0xa9, 0x77, 0xb6, 0xa1, 0x6e,
+	0x8a, 0x8a, 0xbe, 0xdf, 0xc1, 0x40, 0xa5, 0x12, 0x2b, 0xae, 0x93, 0x64, 0x3b, 0x69, 0xb4, 0x7a,
+	0x38, 0xa8, 0x15, 0x51, 0xa4, 0x07, 0x1a, 0x55, 0xae, 0x41, 0x67, 0x15, 0x18, 0x35, 0x1a, 0x71,
+	0x28, 0xdc, 0x7e, 0xe2, 0x49, 0xb6, 0xd9, 0x6a, 0xa0, 0xeb, 0x69, 0x90, 0xa2, 0x32, 0x0d, 0xf6,
+	0x74, 0x54, 0x46, 0xe0, 0xf9, 0xf7, 0x61, 0x26, 0xd7, 0x74, 0xe1, 0x2f, 0x53, 0x5b, 0xf7, 0xf6,
+	0xd6, 0x38, 0xbf, 0xc7, 0x1b, 0x13, 0xec, 0x2c, 0xcc, 0x6c, 0xde, 0x7a, 0x6f, 0x6f, 0x63, 0x7d,
+	0x77, 0x6d, 0x6f, 0x87, 0xdf, 0xba, 0xbd, 0xd6, 0x6e, 0x58, 0x88, 0xa4, 0xf5, 0xde, 0xce, 0xbd,
+	0x7b, 0x7b, 0x1b, 0xb7, 0xf8, 0x9d, 0xb5, 0xc6, 0x24, 0x9b, 0x85, 0xe9, 0x77, 0xb6, 0xde, 0xda,
+	0xba, 0xf7, 0xee, 0x96, 0x3a, 0x5c, 0x68, 0xfd, 0xdc, 0x82, 0x32, 0xb2, 0x17, 0x21, 0xfb, 0x2e,
+	0x54, 0x93, 0xd6, 0x8d, 0x5d, 0xc8, 0x74, 0x7c, 0x66, 0x3b, 0xd7, 0x7c, 0x2a, 0xb3, 0xa5, 0xef,
+	0xe5, 0x4c, 0xb0, 0x5b, 0x50, 0x4b, 0x88, 0x77, 0x5b, 0xff, 0x0d, 0x8b, 0xd6, 0x36, 0xcc, 0x48,
+	0x75, 0x79, 0xfe, 0x81, 0x12, 0xeb, 0x35, 0x80, 0x54, 0x8b, 0x2c, 0xf7, 0x93, 0x7d, 0xf3, 0x62,
+	0x86, 0x53, 0x56, 0xd5, 0xce, 0xc4, 0x82, 0xd5, 0xfa, 0x57, 0x11, 0x1a, 0x2a, 0xdc, 0xdc, 0x11,
+	0xbe, 0x08, 0xdd, 0x38, 0x48, 0xae, 0x4a, 0x9d, 0x5c, 0x4e, 0x4e, 0xb3, 0x2d, 0x3c, 0xf9, 0xaa,
+	0xeb, 0x00, 0x77, 0x44, 0xac, 0xf8, 0xb2, 0x8b, 0xe3, 0xcb, 0x06, 0xc9, 0xe3, 0x99, 0xf1, 0x9b,
+	0x09, 0xab, 0x3b, 0x00, 0xa9, 0x8d, 0xb2, 0xe6, 0x58, 0xc3, 0x95, 0x9c, 0x4e, 0x33, 0x6a, 0x67,
+	0x82, 0xdd, 0x85, 0xe9, 0x37, 0x3c, 0xbf, 0x9b, 0xfc, 0x17, 0x02, 0x1b, 0xf3, 0x6f, 0x0b, 0x9a,
+	0x55, 0x73, 0xdc, 0x96, 0x29, 0x52, 0x3a, 0xc5, 0x61, 0xa7, 0xcc, 0x73, 0x9b, 0x17, 0xc7, 0xee,
+	0x25, 0x8c, 0xde, 0x82, 0x7a, 0x8a, 0xdf, 0x6d, 0x9d, 0xca, 0xea, 0xd9, 0xb1, 0xe3, 0x25, 0x83,
+	0xd9, 0x2e, 0xcc, 0xe4, 0xa6, 0x27, 0xec, 0x51, 0x43, 0xc9, 0xe6, 0xfc, 0xc9, 0x04, 0x09, 0xdf,
+	0xef, 0xc1, 0x6c, 0x6e, 0x73, 0xb7, 0xf5, 0x68, 0xce, 0xce, 0x49, 0x04, 0xa6, 0xcc, 0xad, 0xdf,
+	0x15, 0x61, 0x0a, 0x1f, 0xcb, 0x13, 0xe1, 0x13, 0x7c, 0x9f, 0x5b, 0x5a, 0xad, 0x5c, 0x74, 0x84,
+	0x1f, 0xb3, 0x13, 0xfe, 0xd9, 0xa1, 0x79, 0x52, 0xf0, 0x74, 0x26, 0xd8, 0x9a, 0xfe, 0xe9, 0x96,
+	0xe6, 0xbe, 0x2c, 0xff, 0x8e, 0xe6, 0x34, 0xf8, 0x34, 0x36, 0xdf, 0x58, 0xca, 0x93, 0xb2, 0x94,
+	0x7f, 0x16, 0xa0, 0x91, 0x84, 0x3e, 0x6d, 0x32, 0x37, 0xa1, 0x2c, 0xcf, 0x3c, 0xf6, 0x13, 0x2f,
+	0x5b, 0x18, 0xa3, 0x9e, 0xc8, 0xdb, 0x2c, 0x5b, 0x6c, 0xf3, 0x09, 0xbe, 0xce, 0xb2, 0xc5, 0xde,
+	0xfb, 0x6a, 0xde, 0x67, 0xd9, 0x62, 0xef, 0x7f, 0x75, 0x2f, 0xb4, 0x6c, 0xb1, 0x6d, 0x98, 0x55,
+	0xf1, 0xfb, 0x89, 0x44, 0xec, 0x65, 0xab, 0xf5, 0x07, 0x0b, 0xa6, 0x74, 0x16, 0xd9, 0x1b, 0x3b,
+	0x03, 0x71, 0x4e, 0x9b, 0x0c, 0xa8, 0xcf, 0x3c, 0x77, 0x2a, 0xcd, 0x13, 0xcf, 0x34, 0x2b, 0xf6,
+	0x87, 0x9f, 0xcd, 0x59, 0x1f, 0x7f, 0x36, 0x67, 0xfd, 0xfd, 0xb3, 0x39, 0xeb, 0x17, 0x9f, 0xcf,
+	0x4d, 0x7c, 0xfc, 0xf9, 0xdc, 0xc4, 0x27, 0x9f, 0xcf, 0x4d, 0xec, 0x97, 0xe9, 0xbf, 0x04, 0x5f,
+	0xfa, 0xcf, 0x00, 0x99, 0xd2, 0x1e, 0xe5, 0xa6, 0x28, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
 var _ grpc.ClientConn
 
 // This is a compile-time assertion to ensure that this generated file
@@ -3411,55 +3933,220 @@ var _Pusher_serviceDesc = grpc.ServiceDesc{
 	Metadata: "pkg/tempopb/tempo.proto",
 }
 
-// MetricsGeneratorClient is the client API for MetricsGenerator service.
+// StreamingPusherClient is the client API for StreamingPusher service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type MetricsGeneratorClient interface {
-	PushSpans(ctx context.Context, in *PushSpansRequest, opts ...grpc.CallOption) (*PushResponse, error)
-	GetMetrics(ctx context.Context, in *SpanMetricsRequest, opts ...grpc.CallOption) (*SpanMetricsResponse, error)
-	QueryRange(ctx context.Context, in *QueryRangeRequest, opts ...grpc.CallOption) (*QueryRangeResponse, error)
+type StreamingPusherClient interface {
+	// PushStream receives batches until the client closes the stream. Its messages are wire compatible with OTLP
+	// ExportTraceServiceRequest. A rejected batch ends the stream with the error of the batch.
+	PushStream(ctx context.Context, opts ...grpc.CallOption) (StreamingPusher_PushStreamClient, error)
 }
 
-type metricsGeneratorClient struct {
+type streamingPusherClient struct {
 	cc *grpc.ClientConn
 }
 
-func NewMetricsGeneratorClient(cc *grpc.ClientConn) MetricsGeneratorClient {
-	return &metricsGeneratorClient{cc}
+func NewStreamingPusherClient(cc *grpc.ClientConn) StreamingPusherClient {
+	return &streamingPusherClient{cc}
 }
 
-func (c *metricsGeneratorClient) PushSpans(ctx context.Context, in *PushSpansRequest, opts ...grpc.CallOption) (*PushResponse, error) {
-	out := new(PushResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/PushSpans", in, out, opts...)
+func (c *streamingPusherClient) PushStream(ctx context.Context, opts ...grpc.CallOption) (StreamingPusher_PushStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_StreamingPusher_serviceDesc.Streams[0], "/tempopb.StreamingPusher/PushStream", opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	x := &streamingPusherPushStreamClient{stream}
+	return x, nil
 }
 
-func (c *metricsGeneratorClient) GetMetrics(ctx context.Context, in *SpanMetricsRequest, opts ...grpc.CallOption) (*SpanMetricsResponse, error) {
-	out := new(SpanMetricsResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/GetMetrics", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type StreamingPusher_PushStreamClient interface {
+	Send(*Trace) error
+	CloseAndRecv() (*PushStreamResponse, error)
+	grpc.ClientStream
 }
 
-func (c *metricsGeneratorClient) QueryRange(ctx context.Context, in *QueryRangeRequest, opts ...grpc.CallOption) (*QueryRangeResponse, error) {
-	out := new(QueryRangeResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/QueryRange", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type streamingPusherPushStreamClient struct {
+	grpc.ClientStream
 }
 
-// MetricsGeneratorServer is the server API for MetricsGenerator service.
-type MetricsGeneratorServer interface {
+func (x *streamingPusherPushStreamClient) Send(m *Trace) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *streamingPusherPushStreamClient) CloseAndRecv() (*PushStreamResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(PushStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// StreamingPusherServer is the server API for StreamingPusher service.
+type StreamingPusherServer interface {
+	// PushStream receives batches until the client closes the stream. Its messages are wire compatible with OTLP
+	// ExportTraceServiceRequest. A rejected batch ends the stream with the error of the batch.
+	PushStream(StreamingPusher_PushStreamServer) error
+}
+
+// UnimplementedStreamingPusherServer can be embedded to have forward compatible implementations.
+type UnimplementedStreamingPusherServer struct {
+}
+
+func (*UnimplementedStreamingPusherServer) PushStream(srv StreamingPusher_PushStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method PushStream not implemented")
+}
+
+func RegisterStreamingPusherServer(s *grpc.Server, srv StreamingPusherServer) {
+	s.RegisterService(&_StreamingPusher_serviceDesc, srv)
+}
+
+func _StreamingPusher_PushStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StreamingPusherServer).PushStream(&streamingPusherPushStreamServer{stream})
+}
+
+type StreamingPusher_PushStreamServer interface {
+	SendAndClose(*PushStreamResponse) error
+	Recv() (*Trace, error)
+	grpc.ServerStream
+}
+
+type streamingPusherPushStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamingPusherPushStreamServer) SendAndClose(m *PushStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *streamingPusherPushStreamServer) Recv() (*Trace, error) {
+	m := new(Trace)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _StreamingPusher_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "tempopb.StreamingPusher",
+	HandlerType: (*StreamingPusherServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PushStream",
+			Handler:       _StreamingPusher_PushStream_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "pkg/tempopb/tempo.proto",
+}
+
+// MetricsGeneratorClient is the client API for MetricsGenerator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type MetricsGeneratorClient interface {
+	PushSpans(ctx context.Context, in *PushSpansRequest, opts ...grpc.CallOption) (*PushResponse, error)
+	GetMetrics(ctx context.Context, in *SpanMetricsRequest, opts ...grpc.CallOption) (*SpanMetricsResponse, error)
+	QueryRange(ctx context.Context, in *QueryRangeRequest, opts ...grpc.CallOption) (*QueryRangeResponse, error)
+	FindTraceByID(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (*TraceByIDResponse, error)
+	SearchTags(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsResponse, error)
+	SearchTagsV2(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsV2Response, error)
+	SearchTagValues(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesResponse, error)
+	SearchTagValuesV2(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesV2Response, error)
+}
+
+type metricsGeneratorClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewMetricsGeneratorClient(cc *grpc.ClientConn) MetricsGeneratorClient {
+	return &metricsGeneratorClient{cc}
+}
+
+func (c *metricsGeneratorClient) PushSpans(ctx context.Context, in *PushSpansRequest, opts ...grpc.CallOption) (*PushResponse, error) {
+	out := new(PushResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/PushSpans", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricsGeneratorClient) GetMetrics(ctx context.Context, in *SpanMetricsRequest, opts ...grpc.CallOption) (*SpanMetricsResponse, error) {
+	out := new(SpanMetricsResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/GetMetrics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricsGeneratorClient) QueryRange(ctx context.Context, in *QueryRangeRequest, opts ...grpc.CallOption) (*QueryRangeResponse, error) {
+	out := new(QueryRangeResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/QueryRange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricsGeneratorClient) FindTraceByID(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (*TraceByIDResponse, error) {
+	out := new(TraceByIDResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/FindTraceByID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricsGeneratorClient) SearchTags(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsResponse, error) {
+	out := new(SearchTagsResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/SearchTags", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricsGeneratorClient) SearchTagsV2(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsV2Response, error) {
+	out := new(SearchTagsV2Response)
+	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/SearchTagsV2", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricsGeneratorClient) SearchTagValues(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesResponse, error) {
+	out := new(SearchTagValuesResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/SearchTagValues", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricsGeneratorClient) SearchTagValuesV2(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesV2Response, error) {
+	out := new(SearchTagValuesV2Response)
+	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/SearchTagValuesV2", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MetricsGeneratorServer is the server API for MetricsGenerator service.
+type MetricsGeneratorServer interface {
 	PushSpans(context.Context, *PushSpansRequest) (*PushResponse, error)
 	GetMetrics(context.Context, *SpanMetricsRequest) (*SpanMetricsResponse, error)
 	QueryRange(context.Context, *QueryRangeRequest) (*QueryRangeResponse, error)
+	FindTraceByID(context.Context, *TraceByIDRequest) (*TraceByIDResponse, error)
+	SearchTags(context.Context, *SearchTagsRequest) (*SearchTagsResponse, error)
+	SearchTagsV2(context.Context, *SearchTagsRequest) (*SearchTagsV2Response, error)
+	SearchTagValues(context.Context, *SearchTagValuesRequest) (*SearchTagValuesResponse, error)
+	SearchTagValuesV2(context.Context, *SearchTagValuesRequest) (*SearchTagValuesV2Response, error)
 }
 
 // UnimplementedMetricsGeneratorServer can be embedded to have forward compatible implementations.
@@ -3475,6 +4162,21 @@ func (*UnimplementedMetricsGeneratorServer) GetMetrics(ctx context.Context, req
 func (*UnimplementedMetricsGeneratorServer) QueryRange(ctx context.Context, req *QueryRangeRequest) (*QueryRangeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method QueryRange not implemented")
 }
+func (*UnimplementedMetricsGeneratorServer) FindTraceByID(ctx context.Context, req *TraceByIDRequest) (*TraceByIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindTraceByID not implemented")
+}
+func (*UnimplementedMetricsGeneratorServer) SearchTags(ctx context.Context, req *SearchTagsRequest) (*SearchTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTags not implemented")
+}
+func (*UnimplementedMetricsGeneratorServer) SearchTagsV2(ctx context.Context, req *SearchTagsRequest) (*SearchTagsV2Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTagsV2 not implemented")
+}
+func (*UnimplementedMetricsGeneratorServer) SearchTagValues(ctx context.Context, req *SearchTagValuesRequest) (*SearchTagValuesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTagValues not implemented")
+}
+func (*UnimplementedMetricsGeneratorServer) SearchTagValuesV2(ctx context.Context, req *SearchTagValuesRequest) (*SearchTagValuesV2Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTagValuesV2 not implemented")
+}
 
 func RegisterMetricsGeneratorServer(s *grpc.Server, srv MetricsGeneratorServer) {
 	s.RegisterService(&_MetricsGenerator_serviceDesc, srv)
@@ -3534,6 +4236,96 @@ func _MetricsGenerator_QueryRange_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MetricsGenerator_FindTraceByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TraceByIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricsGeneratorServer).FindTraceByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.MetricsGenerator/FindTraceByID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsGeneratorServer).FindTraceByID(ctx, req.(*TraceByIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricsGenerator_SearchTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricsGeneratorServer).SearchTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.MetricsGenerator/SearchTags",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsGeneratorServer).SearchTags(ctx, req.(*SearchTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricsGenerator_SearchTagsV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricsGeneratorServer).SearchTagsV2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.MetricsGenerator/SearchTagsV2",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsGeneratorServer).SearchTagsV2(ctx, req.(*SearchTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricsGenerator_SearchTagValues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTagValuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricsGeneratorServer).SearchTagValues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.MetricsGenerator/SearchTagValues",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsGeneratorServer).SearchTagValues(ctx, req.(*SearchTagValuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricsGenerator_SearchTagValuesV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTagValuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricsGeneratorServer).SearchTagValuesV2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.MetricsGenerator/SearchTagValuesV2",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsGeneratorServer).SearchTagValuesV2(ctx, req.(*SearchTagValuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _MetricsGenerator_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "tempopb.MetricsGenerator",
 	HandlerType: (*MetricsGeneratorServer)(nil),
@@ -3550,6 +4342,26 @@ var _MetricsGenerator_serviceDesc = grpc.ServiceDesc{
 			MethodName: "QueryRange",
 			Handler:    _MetricsGenerator_QueryRange_Handler,
 		},
+		{
+			MethodName: "FindTraceByID",
+			Handler:    _MetricsGenerator_FindTraceByID_Handler,
+		},
+		{
+			MethodName: "SearchTags",
+			Handler:    _MetricsGenerator_SearchTags_Handler,
+		},
+		{
+			MethodName: "SearchTagsV2",
+			Handler:    _MetricsGenerator_SearchTagsV2_Handler,
+		},
+		{
+			MethodName: "SearchTagValues",
+			Handler:    _MetricsGenerator_SearchTagValues_Handler,
+		},
+		{
+			MethodName: "SearchTagValuesV2",
+			Handler:    _MetricsGenerator_SearchTagValuesV2_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/tempopb/tempo.proto",
@@ -4385,6 +5197,13 @@ func (m *TraceByIDRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.SpanFilter) > 0 {
+		i -= len(m.SpanFilter)
+		copy(dAtA[i:], m.SpanFilter)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.SpanFilter)))
+		i--
+		dAtA[i] = 0x32
+	}
 	if len(m.QueryMode) > 0 {
 		i -= len(m.QueryMode)
 		copy(dAtA[i:], m.QueryMode)
@@ -4732,22 +5551,24 @@ func (m *SearchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
-	if m.Metrics != nil {
-		{
-			size, err := m.Metrics.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
+	if len(m.Groups) > 0 {
+		for iNdEx := len(m.Groups) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Groups[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
 			}
-			i -= size
-			i = encodeVarintTempo(dAtA, i, uint64(size))
+			i--
+			dAtA[i] = 0x2a
 		}
-		i--
-		dAtA[i] = 0x12
 	}
-	if len(m.Traces) > 0 {
-		for iNdEx := len(m.Traces) - 1; iNdEx >= 0; iNdEx-- {
+	if len(m.ShardErrors) > 0 {
+		for iNdEx := len(m.ShardErrors) - 1; iNdEx >= 0; iNdEx-- {
 			{
-				size, err := m.Traces[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				size, err := m.ShardErrors[iNdEx].MarshalToSizedBuffer(dAtA[:i])
 				if err != nil {
 					return 0, err
 				}
@@ -4755,11 +5576,141 @@ func (m *SearchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 				i = encodeVarintTempo(dAtA, i, uint64(size))
 			}
 			i--
-			dAtA[i] = 0xa
+			dAtA[i] = 0x22
 		}
 	}
-	return len(dAtA) - i, nil
-}
+	if m.Partial {
+		i--
+		if m.Partial {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Metrics != nil {
+		{
+			size, err := m.Metrics.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Traces) > 0 {
+		for iNdEx := len(m.Traces) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Traces[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SearchGroup) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchGroup) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchGroup) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Matched != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Matched))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Traces != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Traces))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Attributes) > 0 {
+		for iNdEx := len(m.Attributes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Attributes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SearchShardError) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchShardError) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchShardError) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Error) > 0 {
+		i -= len(m.Error)
+		copy(dAtA[i:], m.Error)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Error)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.PagesToSearch != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.PagesToSearch))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.StartPage != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.StartPage))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.BlockID) > 0 {
+		i -= len(m.BlockID)
+		copy(dAtA[i:], m.BlockID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.BlockID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
 
 func (m *TraceSearchMetadata) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
@@ -6467,6 +7418,36 @@ func (m *QueryRangeRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Lookback != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Lookback))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x98
+	}
+	if m.Offset != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Offset))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x90
+	}
+	if len(m.ExemplarPolicy) > 0 {
+		i -= len(m.ExemplarPolicy)
+		copy(dAtA[i:], m.ExemplarPolicy)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.ExemplarPolicy)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x8a
+	}
+	if m.Exemplars != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Exemplars))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x80
+	}
 	if len(m.DedicatedColumns) > 0 {
 		for iNdEx := len(m.DedicatedColumns) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -6667,6 +7648,20 @@ func (m *TimeSeries) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Exemplars) > 0 {
+		for iNdEx := len(m.Exemplars) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Exemplars[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
 	if len(m.PromLabels) > 0 {
 		i -= len(m.PromLabels)
 		copy(dAtA[i:], m.PromLabels)
@@ -6705,89 +7700,319 @@ func (m *TimeSeries) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintTempo(dAtA []byte, offset int, v uint64) int {
-	offset -= sovTempo(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *Exemplar) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *TraceByIDRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *Exemplar) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Exemplar) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.TraceID)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	l = len(m.BlockStart)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if m.TimestampMs != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.TimestampMs))
+		i--
+		dAtA[i] = 0x18
 	}
-	l = len(m.BlockEnd)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if m.Value != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Value))))
+		i--
+		dAtA[i] = 0x11
 	}
-	l = len(m.QueryMode)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if len(m.Labels) > 0 {
+		for iNdEx := len(m.Labels) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Labels[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *TraceByIDResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Trace != nil {
-		l = m.Trace.Size()
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	if m.Metrics != nil {
-		l = m.Metrics.Size()
-		n += 1 + l + sovTempo(uint64(l))
+func (m *QueryResponseV2) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *TraceByIDMetrics) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	return n
+func (m *QueryResponseV2) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SearchRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *QueryResponseV2) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Tags) > 0 {
-		for k, v := range m.Tags {
-			_ = k
-			_ = v
-			mapEntrySize := 1 + len(k) + sovTempo(uint64(len(k))) + 1 + len(v) + sovTempo(uint64(len(v)))
-			n += mapEntrySize + 1 + sovTempo(uint64(mapEntrySize))
+	if m.Data != nil {
+		{
+			size := m.Data.Size()
+			i -= size
+			if _, err := m.Data.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
 		}
 	}
-	if m.MinDurationMs != 0 {
-		n += 1 + sovTempo(uint64(m.MinDurationMs))
-	}
-	if m.MaxDurationMs != 0 {
-		n += 1 + sovTempo(uint64(m.MaxDurationMs))
-	}
-	if m.Limit != 0 {
+	if m.Partial {
+		i--
+		if m.Partial {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Error != nil {
+		{
+			size, err := m.Error.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Status) > 0 {
+		i -= len(m.Status)
+		copy(dAtA[i:], m.Status)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Status)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryResponseV2_Search) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryResponseV2_Search) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Search != nil {
+		{
+			size, err := m.Search.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
+	return len(dAtA) - i, nil
+}
+func (m *QueryResponseV2_QueryRange) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryResponseV2_QueryRange) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.QueryRange != nil {
+		{
+			size, err := m.QueryRange.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *QueryErrorV2) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryErrorV2) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryErrorV2) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Message) > 0 {
+		i -= len(m.Message)
+		copy(dAtA[i:], m.Message)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Message)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Type) > 0 {
+		i -= len(m.Type)
+		copy(dAtA[i:], m.Type)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Type)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Code != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Code))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PushStreamResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PushStreamResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PushStreamResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Spans != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Spans))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Batches != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Batches))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintTempo(dAtA []byte, offset int, v uint64) int {
+	offset -= sovTempo(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *TraceByIDRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TraceID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.BlockStart)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.BlockEnd)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.QueryMode)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.SpanFilter)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *TraceByIDResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Trace != nil {
+		l = m.Trace.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Metrics != nil {
+		l = m.Metrics.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *TraceByIDMetrics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *SearchRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Tags) > 0 {
+		for k, v := range m.Tags {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovTempo(uint64(len(k))) + 1 + len(v) + sovTempo(uint64(len(v)))
+			n += mapEntrySize + 1 + sovTempo(uint64(mapEntrySize))
+		}
+	}
+	if m.MinDurationMs != 0 {
+		n += 1 + sovTempo(uint64(m.MinDurationMs))
+	}
+	if m.MaxDurationMs != 0 {
+		n += 1 + sovTempo(uint64(m.MaxDurationMs))
+	}
+	if m.Limit != 0 {
 		n += 1 + sovTempo(uint64(m.Limit))
 	}
 	if m.Start != 0 {
@@ -6894,6 +8119,65 @@ func (m *SearchResponse) Size() (n int) {
 		l = m.Metrics.Size()
 		n += 1 + l + sovTempo(uint64(l))
 	}
+	if m.Partial {
+		n += 2
+	}
+	if len(m.ShardErrors) > 0 {
+		for _, e := range m.ShardErrors {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if len(m.Groups) > 0 {
+		for _, e := range m.Groups {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SearchGroup) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Attributes) > 0 {
+		for _, e := range m.Attributes {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.Traces != 0 {
+		n += 1 + sovTempo(uint64(m.Traces))
+	}
+	if m.Matched != 0 {
+		n += 1 + sovTempo(uint64(m.Matched))
+	}
+	return n
+}
+
+func (m *SearchShardError) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.BlockID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.StartPage != 0 {
+		n += 1 + sovTempo(uint64(m.StartPage))
+	}
+	if m.PagesToSearch != 0 {
+		n += 1 + sovTempo(uint64(m.PagesToSearch))
+	}
+	l = len(m.Error)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
 	return n
 }
 
@@ -7729,10 +9013,23 @@ func (m *QueryRangeRequest) Size() (n int) {
 			n += 1 + l + sovTempo(uint64(l))
 		}
 	}
-	return n
-}
-
-func (m *QueryRangeResponse) Size() (n int) {
+	if m.Exemplars != 0 {
+		n += 2 + sovTempo(uint64(m.Exemplars))
+	}
+	l = len(m.ExemplarPolicy)
+	if l > 0 {
+		n += 2 + l + sovTempo(uint64(l))
+	}
+	if m.Offset != 0 {
+		n += 2 + sovTempo(uint64(m.Offset))
+	}
+	if m.Lookback != 0 {
+		n += 2 + sovTempo(uint64(m.Lookback))
+	}
+	return n
+}
+
+func (m *QueryRangeResponse) Size() (n int) {
 	if m == nil {
 		return 0
 	}
@@ -7788,6 +9085,115 @@ func (m *TimeSeries) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovTempo(uint64(l))
 	}
+	if len(m.Exemplars) > 0 {
+		for _, e := range m.Exemplars {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Exemplar) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for _, e := range m.Labels {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.Value != 0 {
+		n += 9
+	}
+	if m.TimestampMs != 0 {
+		n += 1 + sovTempo(uint64(m.TimestampMs))
+	}
+	return n
+}
+
+func (m *QueryResponseV2) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Status)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Error != nil {
+		l = m.Error.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Partial {
+		n += 2
+	}
+	if m.Data != nil {
+		n += m.Data.Size()
+	}
+	return n
+}
+
+func (m *QueryResponseV2_Search) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Search != nil {
+		l = m.Search.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+func (m *QueryResponseV2_QueryRange) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.QueryRange != nil {
+		l = m.QueryRange.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+func (m *QueryErrorV2) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Code != 0 {
+		n += 1 + sovTempo(uint64(m.Code))
+	}
+	l = len(m.Type)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *PushStreamResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Batches != 0 {
+		n += 1 + sovTempo(uint64(m.Batches))
+	}
+	if m.Spans != 0 {
+		n += 1 + sovTempo(uint64(m.Spans))
+	}
 	return n
 }
 
@@ -7956,6 +9362,38 @@ func (m *TraceByIDRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.QueryMode = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanFilter", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SpanFilter = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -9053,6 +10491,94 @@ func (m *SearchResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Partial", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Partial = bool(v != 0)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ShardErrors", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ShardErrors = append(m.ShardErrors, &SearchShardError{})
+			if err := m.ShardErrors[len(m.ShardErrors)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Groups", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Groups = append(m.Groups, &SearchGroup{})
+			if err := m.Groups[len(m.Groups)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -9074,7 +10600,7 @@ func (m *SearchResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
+func (m *SearchGroup) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -9097,17 +10623,17 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TraceSearchMetadata: wiretype end group for non-group")
+			return fmt.Errorf("proto: SearchGroup: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TraceSearchMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SearchGroup: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TraceID", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9117,29 +10643,31 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TraceID = string(dAtA[iNdEx:postIndex])
+			m.Attributes = append(m.Attributes, &v1.KeyValue{})
+			if err := m.Attributes[len(m.Attributes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RootServiceName", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
 			}
-			var stringLen uint64
+			m.Traces = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9149,29 +10677,16 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Traces |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.RootServiceName = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RootTraceName", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Matched", wireType)
 			}
-			var stringLen uint64
+			m.Matched = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9181,48 +10696,66 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Matched |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			if postIndex > l {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.RootTraceName = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeUnixNano", wireType)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchShardError) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
 			}
-			m.StartTimeUnixNano = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.StartTimeUnixNano |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
 			}
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DurationMs", wireType)
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
 			}
-			m.DurationMs = 0
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchShardError: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchShardError: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockID", wireType)
+			}
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9232,16 +10765,29 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.DurationMs |= uint32(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 6:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SpanSet", wireType)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
 			}
-			var msglen int
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BlockID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartPage", wireType)
+			}
+			m.StartPage = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9251,33 +10797,16 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.StartPage |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.SpanSet == nil {
-				m.SpanSet = &SpanSet{}
-			}
-			if err := m.SpanSet.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 7:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SpanSets", wireType)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PagesToSearch", wireType)
 			}
-			var msglen int
+			m.PagesToSearch = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9287,31 +10816,16 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.PagesToSearch |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.SpanSets = append(m.SpanSets, &SpanSet{})
-			if err := m.SpanSets[len(m.SpanSets)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 8:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ServiceStats", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9321,120 +10835,23 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.ServiceStats == nil {
-				m.ServiceStats = make(map[string]*ServiceStats)
-			}
-			var mapkey string
-			var mapvalue *ServiceStats
-			for iNdEx < postIndex {
-				entryPreIndex := iNdEx
-				var wire uint64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowTempo
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					wire |= uint64(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				fieldNum := int32(wire >> 3)
-				if fieldNum == 1 {
-					var stringLenmapkey uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowTempo
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapkey |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapkey := int(stringLenmapkey)
-					if intStringLenmapkey < 0 {
-						return ErrInvalidLengthTempo
-					}
-					postStringIndexmapkey := iNdEx + intStringLenmapkey
-					if postStringIndexmapkey < 0 {
-						return ErrInvalidLengthTempo
-					}
-					if postStringIndexmapkey > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
-					iNdEx = postStringIndexmapkey
-				} else if fieldNum == 2 {
-					var mapmsglen int
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowTempo
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						mapmsglen |= int(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					if mapmsglen < 0 {
-						return ErrInvalidLengthTempo
-					}
-					postmsgIndex := iNdEx + mapmsglen
-					if postmsgIndex < 0 {
-						return ErrInvalidLengthTempo
-					}
-					if postmsgIndex > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapvalue = &ServiceStats{}
-					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
-						return err
-					}
-					iNdEx = postmsgIndex
-				} else {
-					iNdEx = entryPreIndex
-					skippy, err := skipTempo(dAtA[iNdEx:])
-					if err != nil {
-						return err
-					}
-					if (skippy < 0) || (iNdEx+skippy) < 0 {
-						return ErrInvalidLengthTempo
-					}
-					if (iNdEx + skippy) > postIndex {
-						return io.ErrUnexpectedEOF
-					}
-					iNdEx += skippy
-				}
-			}
-			m.ServiceStats[mapkey] = mapvalue
+			m.Error = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -9457,7 +10874,7 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ServiceStats) Unmarshal(dAtA []byte) error {
+func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -9480,17 +10897,17 @@ func (m *ServiceStats) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ServiceStats: wiretype end group for non-group")
+			return fmt.Errorf("proto: TraceSearchMetadata: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ServiceStats: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TraceSearchMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SpanCount", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceID", wireType)
 			}
-			m.SpanCount = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9500,16 +10917,29 @@ func (m *ServiceStats) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.SpanCount |= uint32(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TraceID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ErrorCount", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RootServiceName", wireType)
 			}
-			m.ErrorCount = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9519,66 +10949,29 @@ func (m *ServiceStats) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ErrorCount |= uint32(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTempo(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *SpanSet) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTempo
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
 			}
-			if iNdEx >= l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: SpanSet: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SpanSet: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			m.RootServiceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Spans", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RootTraceName", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9588,31 +10981,29 @@ func (m *SpanSet) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Spans = append(m.Spans, &Span{})
-			if err := m.Spans[len(m.Spans)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.RootTraceName = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Matched", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeUnixNano", wireType)
 			}
-			m.Matched = 0
+			m.StartTimeUnixNano = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9622,16 +11013,16 @@ func (m *SpanSet) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Matched |= uint32(b&0x7F) << shift
+				m.StartTimeUnixNano |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationMs", wireType)
 			}
-			var msglen int
+			m.DurationMs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9641,81 +11032,16 @@ func (m *SpanSet) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.DurationMs |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Attributes = append(m.Attributes, &v1.KeyValue{})
-			if err := m.Attributes[len(m.Attributes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTempo(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Span) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTempo
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Span: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Span: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SpanID", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanSet", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9725,29 +11051,33 @@ func (m *Span) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.SpanID = string(dAtA[iNdEx:postIndex])
+			if m.SpanSet == nil {
+				m.SpanSet = &SpanSet{}
+			}
+			if err := m.SpanSet.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 2:
+		case 7:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanSets", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9757,29 +11087,31 @@ func (m *Span) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(dAtA[iNdEx:postIndex])
+			m.SpanSets = append(m.SpanSets, &SpanSet{})
+			if err := m.SpanSets[len(m.SpanSets)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeUnixNano", wireType)
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceStats", wireType)
 			}
-			m.StartTimeUnixNano = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9789,63 +11121,120 @@ func (m *Span) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.StartTimeUnixNano |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DurationNanos", wireType)
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
 			}
-			m.DurationNanos = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.DurationNanos |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
 			}
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
+			if m.ServiceStats == nil {
+				m.ServiceStats = make(map[string]*ServiceStats)
+			}
+			var mapkey string
+			var mapvalue *ServiceStats
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowTempo
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
 				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowTempo
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthTempo
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthTempo
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowTempo
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthTempo
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthTempo
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &ServiceStats{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipTempo(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthTempo
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Attributes = append(m.Attributes, &v1.KeyValue{})
-			if err := m.Attributes[len(m.Attributes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.ServiceStats[mapkey] = mapvalue
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -9868,7 +11257,7 @@ func (m *Span) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
+func (m *ServiceStats) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -9891,17 +11280,17 @@ func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchMetrics: wiretype end group for non-group")
+			return fmt.Errorf("proto: ServiceStats: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchMetrics: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ServiceStats: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field InspectedTraces", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanCount", wireType)
 			}
-			m.InspectedTraces = 0
+			m.SpanCount = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9911,111 +11300,16 @@ func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.InspectedTraces |= uint32(b&0x7F) << shift
+				m.SpanCount |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field InspectedBytes", wireType)
-			}
-			m.InspectedBytes = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.InspectedBytes |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TotalBlocks", wireType)
-			}
-			m.TotalBlocks = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.TotalBlocks |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CompletedJobs", wireType)
-			}
-			m.CompletedJobs = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.CompletedJobs |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TotalJobs", wireType)
-			}
-			m.TotalJobs = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.TotalJobs |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 6:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TotalBlockBytes", wireType)
-			}
-			m.TotalBlockBytes = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.TotalBlockBytes |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 7:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field InspectedSpans", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorCount", wireType)
 			}
-			m.InspectedSpans = 0
+			m.ErrorCount = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -10025,7 +11319,7 @@ func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.InspectedSpans |= uint64(b&0x7F) << shift
+				m.ErrorCount |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -10051,7 +11345,7 @@ func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchTagsRequest) Unmarshal(dAtA []byte) error {
+func (m *SpanSet) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -10074,17 +11368,17 @@ func (m *SearchTagsRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagsRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: SpanSet: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SpanSet: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Scope", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Spans", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -10094,29 +11388,31 @@ func (m *SearchTagsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Scope = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			m.Spans = append(m.Spans, &Span{})
+			if err := m.Spans[len(m.Spans)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-			m.Start = 0
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Matched", wireType)
+			}
+			m.Matched = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -10126,16 +11422,16 @@ func (m *SearchTagsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Start |= uint32(b&0x7F) << shift
+				m.Matched |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
 			}
-			m.End = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -10145,11 +11441,26 @@ func (m *SearchTagsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.End |= uint32(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Attributes = append(m.Attributes, &v1.KeyValue{})
+			if err := m.Attributes[len(m.Attributes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -10171,7 +11482,7 @@ func (m *SearchTagsRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchTagsBlockRequest) Unmarshal(dAtA []byte) error {
+func (m *Span) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -10194,17 +11505,17 @@ func (m *SearchTagsBlockRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagsBlockRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: Span: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagsBlockRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Span: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SearchReq", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanID", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -10214,31 +11525,27 @@ func (m *SearchTagsBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.SearchReq == nil {
-				m.SearchReq = &SearchTagsRequest{}
-			}
-			if err := m.SearchReq.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.SpanID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field BlockID", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -10266,13 +11573,13 @@ func (m *SearchTagsBlockRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.BlockID = string(dAtA[iNdEx:postIndex])
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StartPage", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeUnixNano", wireType)
 			}
-			m.StartPage = 0
+			m.StartTimeUnixNano = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -10282,16 +11589,16 @@ func (m *SearchTagsBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.StartPage |= uint32(b&0x7F) << shift
+				m.StartTimeUnixNano |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field PagesToSearch", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationNanos", wireType)
 			}
-			m.PagesToSearch = 0
+			m.DurationNanos = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -10301,16 +11608,16 @@ func (m *SearchTagsBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.PagesToSearch |= uint32(b&0x7F) << shift
+				m.DurationNanos |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Encoding", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -10320,29 +11627,81 @@ func (m *SearchTagsBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Encoding = string(dAtA[iNdEx:postIndex])
+			m.Attributes = append(m.Attributes, &v1.KeyValue{})
+			if err := m.Attributes[len(m.Attributes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 6:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchMetrics: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchMetrics: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field IndexPageSize", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field InspectedTraces", wireType)
 			}
-			m.IndexPageSize = 0
+			m.InspectedTraces = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -10352,16 +11711,16 @@ func (m *SearchTagsBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.IndexPageSize |= uint32(b&0x7F) << shift
+				m.InspectedTraces |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 7:
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TotalRecords", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field InspectedBytes", wireType)
 			}
-			m.TotalRecords = 0
+			m.InspectedBytes = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -10371,16 +11730,16 @@ func (m *SearchTagsBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.TotalRecords |= uint32(b&0x7F) << shift
+				m.InspectedBytes |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 8:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DataEncoding", wireType)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalBlocks", wireType)
 			}
-			var stringLen uint64
+			m.TotalBlocks = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -10390,29 +11749,16 @@ func (m *SearchTagsBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.TotalBlocks |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.DataEncoding = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 9:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CompletedJobs", wireType)
 			}
-			var stringLen uint64
+			m.CompletedJobs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -10422,29 +11768,16 @@ func (m *SearchTagsBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.CompletedJobs |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Version = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 10:
+		case 5:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Size_", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalJobs", wireType)
 			}
-			m.Size_ = 0
+			m.TotalJobs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -10454,16 +11787,16 @@ func (m *SearchTagsBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Size_ |= uint64(b&0x7F) << shift
+				m.TotalJobs |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 11:
+		case 6:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field FooterSize", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalBlockBytes", wireType)
 			}
-			m.FooterSize = 0
+			m.TotalBlockBytes = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -10473,16 +11806,16 @@ func (m *SearchTagsBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.FooterSize |= uint32(b&0x7F) << shift
+				m.TotalBlockBytes |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 12:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DedicatedColumns", wireType)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InspectedSpans", wireType)
 			}
-			var msglen int
+			m.InspectedSpans = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -10492,26 +11825,11 @@ func (m *SearchTagsBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.InspectedSpans |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.DedicatedColumns = append(m.DedicatedColumns, &DedicatedColumn{})
-			if err := m.DedicatedColumns[len(m.DedicatedColumns)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -10533,7 +11851,7 @@ func (m *SearchTagsBlockRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchTagValuesBlockRequest) Unmarshal(dAtA []byte) error {
+func (m *SearchTagsRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -10556,17 +11874,137 @@ func (m *SearchTagValuesBlockRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagValuesBlockRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: SearchTagsRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagValuesBlockRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SearchTagsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SearchReq", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Scope", wireType)
 			}
-			var msglen int
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Scope = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			}
+			m.Start = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Start |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+			}
+			m.End = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.End |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchTagsBlockRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchTagsBlockRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchTagsBlockRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SearchReq", wireType)
+			}
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -10592,7 +12030,7 @@ func (m *SearchTagValuesBlockRequest) Unmarshal(dAtA []byte) error {
 				return io.ErrUnexpectedEOF
 			}
 			if m.SearchReq == nil {
-				m.SearchReq = &SearchTagValuesRequest{}
+				m.SearchReq = &SearchTagsRequest{}
 			}
 			if err := m.SearchReq.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
@@ -10895,7 +12333,7 @@ func (m *SearchTagValuesBlockRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchTagsResponse) Unmarshal(dAtA []byte) error {
+func (m *SearchTagValuesBlockRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -10918,17 +12356,17 @@ func (m *SearchTagsResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagsResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: SearchTagValuesBlockRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SearchTagValuesBlockRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TagNames", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SearchReq", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -10938,79 +12376,33 @@ func (m *SearchTagsResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TagNames = append(m.TagNames, string(dAtA[iNdEx:postIndex]))
-			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTempo(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *SearchTagsV2Response) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTempo
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+			if m.SearchReq == nil {
+				m.SearchReq = &SearchTagValuesRequest{}
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			if err := m.SearchReq.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagsV2Response: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagsV2Response: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			iNdEx = postIndex
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Scopes", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockID", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -11020,81 +12412,48 @@ func (m *SearchTagsV2Response) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Scopes = append(m.Scopes, &SearchTagsV2Scope{})
-			if err := m.Scopes[len(m.Scopes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.BlockID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTempo(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartPage", wireType)
 			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthTempo
+			m.StartPage = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartPage |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PagesToSearch", wireType)
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *SearchTagsV2Scope) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTempo
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagsV2Scope: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagsV2Scope: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
-			}
-			var stringLen uint64
+			m.PagesToSearch = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -11104,27 +12463,14 @@ func (m *SearchTagsV2Scope) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.PagesToSearch |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Name = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Encoding", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -11152,61 +12498,49 @@ func (m *SearchTagsV2Scope) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Tags = append(m.Tags, string(dAtA[iNdEx:postIndex]))
+			m.Encoding = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTempo(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IndexPageSize", wireType)
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *SearchTagValuesRequest) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTempo
+			m.IndexPageSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.IndexPageSize |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalRecords", wireType)
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			m.TotalRecords = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalRecords |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagValuesRequest: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagValuesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		case 8:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TagName", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DataEncoding", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -11234,11 +12568,11 @@ func (m *SearchTagValuesRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TagName = string(dAtA[iNdEx:postIndex])
+			m.DataEncoding = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -11266,13 +12600,13 @@ func (m *SearchTagValuesRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Query = string(dAtA[iNdEx:postIndex])
+			m.Version = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 4:
+		case 10:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Size_", wireType)
 			}
-			m.Start = 0
+			m.Size_ = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -11282,16 +12616,16 @@ func (m *SearchTagValuesRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Start |= uint32(b&0x7F) << shift
+				m.Size_ |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 5:
+		case 11:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FooterSize", wireType)
 			}
-			m.End = 0
+			m.FooterSize = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -11301,11 +12635,45 @@ func (m *SearchTagValuesRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.End |= uint32(b&0x7F) << shift
+				m.FooterSize |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DedicatedColumns", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DedicatedColumns = append(m.DedicatedColumns, &DedicatedColumn{})
+			if err := m.DedicatedColumns[len(m.DedicatedColumns)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -11327,7 +12695,7 @@ func (m *SearchTagValuesRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchTagValuesResponse) Unmarshal(dAtA []byte) error {
+func (m *SearchTagsResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -11350,15 +12718,15 @@ func (m *SearchTagValuesResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagValuesResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: SearchTagsResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagValuesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SearchTagsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TagValues", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TagNames", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -11386,7 +12754,7 @@ func (m *SearchTagValuesResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TagValues = append(m.TagValues, string(dAtA[iNdEx:postIndex]))
+			m.TagNames = append(m.TagNames, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -11409,7 +12777,7 @@ func (m *SearchTagValuesResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TagValue) Unmarshal(dAtA []byte) error {
+func (m *SearchTagsV2Response) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -11432,17 +12800,17 @@ func (m *TagValue) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TagValue: wiretype end group for non-group")
+			return fmt.Errorf("proto: SearchTagsV2Response: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TagValue: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SearchTagsV2Response: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Scopes", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -11452,55 +12820,25 @@ func (m *TagValue) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Type = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+			m.Scopes = append(m.Scopes, &SearchTagsV2Scope{})
+			if err := m.Scopes[len(m.Scopes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-			m.Value = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -11523,7 +12861,7 @@ func (m *TagValue) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchTagValuesV2Response) Unmarshal(dAtA []byte) error {
+func (m *SearchTagsV2Scope) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -11546,17 +12884,17 @@ func (m *SearchTagValuesV2Response) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagValuesV2Response: wiretype end group for non-group")
+			return fmt.Errorf("proto: SearchTagsV2Scope: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagValuesV2Response: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SearchTagsV2Scope: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TagValues", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -11566,25 +12904,55 @@ func (m *SearchTagValuesV2Response) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TagValues = append(m.TagValues, &TagValue{})
-			if err := m.TagValues[len(m.TagValues)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
+			m.Tags = append(m.Tags, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -11607,7 +12975,7 @@ func (m *SearchTagValuesV2Response) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Trace) Unmarshal(dAtA []byte) error {
+func (m *SearchTagValuesRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -11630,17 +12998,17 @@ func (m *Trace) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Trace: wiretype end group for non-group")
+			return fmt.Errorf("proto: SearchTagValuesRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Trace: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SearchTagValuesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Batches", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TagName", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -11650,26 +13018,94 @@ func (m *Trace) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Batches = append(m.Batches, &v11.ResourceSpans{})
-			if err := m.Batches[len(m.Batches)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.TagName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
+			m.Query = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			}
+			m.Start = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Start |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+			}
+			m.End = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.End |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -11691,7 +13127,7 @@ func (m *Trace) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PushResponse) Unmarshal(dAtA []byte) error {
+func (m *SearchTagValuesResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -11714,81 +13150,44 @@ func (m *PushResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PushResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: SearchTagValuesResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PushResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SearchTagValuesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType == 0 {
-				var v PushErrorReason
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowTempo
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					v |= PushErrorReason(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				m.ErrorsByTrace = append(m.ErrorsByTrace, v)
-			} else if wireType == 2 {
-				var packedLen int
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowTempo
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					packedLen |= int(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				if packedLen < 0 {
-					return ErrInvalidLengthTempo
-				}
-				postIndex := iNdEx + packedLen
-				if postIndex < 0 {
-					return ErrInvalidLengthTempo
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TagValues", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
 				}
-				if postIndex > l {
+				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				var elementCount int
-				if elementCount != 0 && len(m.ErrorsByTrace) == 0 {
-					m.ErrorsByTrace = make([]PushErrorReason, 0, elementCount)
-				}
-				for iNdEx < postIndex {
-					var v PushErrorReason
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowTempo
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						v |= PushErrorReason(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					m.ErrorsByTrace = append(m.ErrorsByTrace, v)
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
 				}
-			} else {
-				return fmt.Errorf("proto: wrong wireType = %d for field ErrorsByTrace", wireType)
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TagValues = append(m.TagValues, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -11810,7 +13209,7 @@ func (m *PushResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
+func (m *TagValue) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -11833,17 +13232,17 @@ func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PushBytesRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: TagValue: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PushBytesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TagValue: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		case 2:
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -11853,67 +13252,29 @@ func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			var v PreallocBytes
-			m.Traces = append(m.Traces, v)
-			if err := m.Traces[len(m.Traces)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Ids", wireType)
-			}
-			var byteLen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				byteLen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if byteLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			var v PreallocBytes
-			m.Ids = append(m.Ids, v)
-			if err := m.Ids[len(m.Ids)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Type = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 4:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SearchData", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -11923,26 +13284,23 @@ func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			var v PreallocBytes
-			m.SearchData = append(m.SearchData, v)
-			if err := m.SearchData[len(m.SearchData)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Value = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -11965,7 +13323,7 @@ func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PushSpansRequest) Unmarshal(dAtA []byte) error {
+func (m *SearchTagValuesV2Response) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -11988,15 +13346,15 @@ func (m *PushSpansRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PushSpansRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: SearchTagValuesV2Response: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PushSpansRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SearchTagValuesV2Response: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Batches", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TagValues", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -12023,8 +13381,8 @@ func (m *PushSpansRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Batches = append(m.Batches, &v11.ResourceSpans{})
-			if err := m.Batches[len(m.Batches)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.TagValues = append(m.TagValues, &TagValue{})
+			if err := m.TagValues[len(m.TagValues)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -12049,7 +13407,7 @@ func (m *PushSpansRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TraceBytes) Unmarshal(dAtA []byte) error {
+func (m *Trace) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -12072,17 +13430,17 @@ func (m *TraceBytes) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TraceBytes: wiretype end group for non-group")
+			return fmt.Errorf("proto: Trace: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TraceBytes: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Trace: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Batches", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -12092,23 +13450,25 @@ func (m *TraceBytes) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Traces = append(m.Traces, make([]byte, postIndex-iNdEx))
-			copy(m.Traces[len(m.Traces)-1], dAtA[iNdEx:postIndex])
+			m.Batches = append(m.Batches, &v11.ResourceSpans{})
+			if err := m.Batches[len(m.Batches)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -12131,7 +13491,7 @@ func (m *TraceBytes) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LinkSlice) Unmarshal(dAtA []byte) error {
+func (m *PushResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -12154,46 +13514,81 @@ func (m *LinkSlice) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LinkSlice: wiretype end group for non-group")
+			return fmt.Errorf("proto: PushResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LinkSlice: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PushResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Links", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
+			if wireType == 0 {
+				var v PushErrorReason
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowTempo
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= PushErrorReason(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
 				}
-				if iNdEx >= l {
+				m.ErrorsByTrace = append(m.ErrorsByTrace, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowTempo
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthTempo
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthTempo
+				}
+				if postIndex > l {
 					return io.ErrUnexpectedEOF
 				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
+				var elementCount int
+				if elementCount != 0 && len(m.ErrorsByTrace) == 0 {
+					m.ErrorsByTrace = make([]PushErrorReason, 0, elementCount)
 				}
+				for iNdEx < postIndex {
+					var v PushErrorReason
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowTempo
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= PushErrorReason(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.ErrorsByTrace = append(m.ErrorsByTrace, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorsByTrace", wireType)
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Links = append(m.Links, &v11.Span_Link{})
-			if err := m.Links[len(m.Links)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -12215,7 +13610,7 @@ func (m *LinkSlice) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
+func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -12238,17 +13633,17 @@ func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SpanMetricsRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: PushBytesRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SpanMetricsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PushBytesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		case 1:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -12258,29 +13653,32 @@ func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Query = string(dAtA[iNdEx:postIndex])
+			var v PreallocBytes
+			m.Traces = append(m.Traces, v)
+			if err := m.Traces[len(m.Traces)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 2:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field GroupBy", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Ids", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -12290,29 +13688,32 @@ func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.GroupBy = string(dAtA[iNdEx:postIndex])
+			var v PreallocBytes
+			m.Ids = append(m.Ids, v)
+			if err := m.Ids[len(m.Ids)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SearchData", wireType)
 			}
-			m.Limit = 0
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -12322,35 +13723,166 @@ func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Limit |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			if byteLen < 0 {
+				return ErrInvalidLengthTempo
 			}
-			m.Start = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			var v PreallocBytes
+			m.SearchData = append(m.SearchData, v)
+			if err := m.SearchData[len(m.SearchData)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PushSpansRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PushSpansRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PushSpansRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Batches", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Start |= uint32(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
 			}
-			m.End = 0
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Batches = append(m.Batches, &v11.ResourceSpans{})
+			if err := m.Batches[len(m.Batches)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TraceBytes) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TraceBytes: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TraceBytes: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
+			}
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -12360,11 +13892,24 @@ func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.End |= uint32(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Traces = append(m.Traces, make([]byte, postIndex-iNdEx))
+			copy(m.Traces[len(m.Traces)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -12386,7 +13931,7 @@ func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SpanMetricsSummaryRequest) Unmarshal(dAtA []byte) error {
+func (m *LinkSlice) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -12409,10 +13954,94 @@ func (m *SpanMetricsSummaryRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SpanMetricsSummaryRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: LinkSlice: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SpanMetricsSummaryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: LinkSlice: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Links", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Links = append(m.Links, &v11.Span_Link{})
+			if err := m.Links[len(m.Links)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SpanMetricsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SpanMetricsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -12557,7 +14186,178 @@ func (m *SpanMetricsSummaryRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SpanMetricsResponse) Unmarshal(dAtA []byte) error {
+func (m *SpanMetricsSummaryRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SpanMetricsSummaryRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SpanMetricsSummaryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Query = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GroupBy", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GroupBy = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			}
+			m.Start = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Start |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+			}
+			m.End = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.End |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SpanMetricsResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -14189,7 +15989,780 @@ func (m *QueryRangeRequest) Unmarshal(dAtA []byte) error {
 			}
 		case 11:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Version = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Encoding", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Encoding = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Size_", wireType)
+			}
+			m.Size_ = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Size_ |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FooterSize", wireType)
+			}
+			m.FooterSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FooterSize |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DedicatedColumns", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DedicatedColumns = append(m.DedicatedColumns, &DedicatedColumn{})
+			if err := m.DedicatedColumns[len(m.DedicatedColumns)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 16:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exemplars", wireType)
+			}
+			m.Exemplars = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Exemplars |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExemplarPolicy", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExemplarPolicy = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 18:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 19:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Lookback", wireType)
+			}
+			m.Lookback = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Lookback |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryRangeResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryRangeResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryRangeResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Series", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Series = append(m.Series, &TimeSeries{})
+			if err := m.Series[len(m.Series)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Metrics == nil {
+				m.Metrics = &SearchMetrics{}
+			}
+			if err := m.Metrics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Sample) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Sample: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Sample: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Value = float64(math.Float64frombits(v))
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimestampMs", wireType)
+			}
+			m.TimestampMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimestampMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TimeSeries) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TimeSeries: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TimeSeries: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Labels = append(m.Labels, v1.KeyValue{})
+			if err := m.Labels[len(m.Labels)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Samples", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Samples = append(m.Samples, Sample{})
+			if err := m.Samples[len(m.Samples)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PromLabels", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PromLabels = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exemplars", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Exemplars = append(m.Exemplars, Exemplar{})
+			if err := m.Exemplars[len(m.Exemplars)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Exemplar) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Exemplar: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Exemplar: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Labels = append(m.Labels, v1.KeyValue{})
+			if err := m.Labels[len(m.Labels)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Value = float64(math.Float64frombits(v))
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimestampMs", wireType)
+			}
+			m.TimestampMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimestampMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryResponseV2) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryResponseV2: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryResponseV2: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -14217,13 +16790,13 @@ func (m *QueryRangeRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Version = string(dAtA[iNdEx:postIndex])
+			m.Status = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 12:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Encoding", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -14233,29 +16806,33 @@ func (m *QueryRangeRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Encoding = string(dAtA[iNdEx:postIndex])
+			if m.Error == nil {
+				m.Error = &QueryErrorV2{}
+			}
+			if err := m.Error.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 13:
+		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Size_", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Partial", wireType)
 			}
-			m.Size_ = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -14265,16 +16842,17 @@ func (m *QueryRangeRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Size_ |= uint64(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 14:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field FooterSize", wireType)
+			m.Partial = bool(v != 0)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Search", wireType)
 			}
-			m.FooterSize = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -14284,14 +16862,30 @@ func (m *QueryRangeRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.FooterSize |= uint32(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 15:
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &SearchResponse{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Data = &QueryResponseV2_Search{v}
+			iNdEx = postIndex
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DedicatedColumns", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field QueryRange", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -14318,10 +16912,11 @@ func (m *QueryRangeRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.DedicatedColumns = append(m.DedicatedColumns, &DedicatedColumn{})
-			if err := m.DedicatedColumns[len(m.DedicatedColumns)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			v := &QueryRangeResponse{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
+			m.Data = &QueryResponseV2_QueryRange{v}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -14344,7 +16939,7 @@ func (m *QueryRangeRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *QueryRangeResponse) Unmarshal(dAtA []byte) error {
+func (m *QueryErrorV2) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -14367,17 +16962,17 @@ func (m *QueryRangeResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: QueryRangeResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: QueryErrorV2: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: QueryRangeResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: QueryErrorV2: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Series", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Code", wireType)
 			}
-			var msglen int
+			m.Code = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -14387,31 +16982,16 @@ func (m *QueryRangeResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Code |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Series = append(m.Series, &TimeSeries{})
-			if err := m.Series[len(m.Series)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -14421,94 +17001,29 @@ func (m *QueryRangeResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Metrics == nil {
-				m.Metrics = &SearchMetrics{}
-			}
-			if err := m.Metrics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Type = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTempo(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Sample) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTempo
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Sample: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Sample: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
-			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
-			}
-			var v uint64
-			if (iNdEx + 8) > l {
-				return io.ErrUnexpectedEOF
-			}
-			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
-			iNdEx += 8
-			m.Value = float64(math.Float64frombits(v))
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TimestampMs", wireType)
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
 			}
-			m.TimestampMs = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -14518,11 +17033,24 @@ func (m *Sample) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.TimestampMs |= int64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Message = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -14544,7 +17072,7 @@ func (m *Sample) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TimeSeries) Unmarshal(dAtA []byte) error {
+func (m *PushStreamResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -14567,17 +17095,17 @@ func (m *TimeSeries) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TimeSeries: wiretype end group for non-group")
+			return fmt.Errorf("proto: PushStreamResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TimeSeries: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PushStreamResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Batches", wireType)
 			}
-			var msglen int
+			m.Batches = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -14587,65 +17115,16 @@ func (m *TimeSeries) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Batches |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Labels = append(m.Labels, v1.KeyValue{})
-			if err := m.Labels[len(m.Labels)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Samples", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Samples = append(m.Samples, Sample{})
-			if err := m.Samples[len(m.Samples)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field PromLabels", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Spans", wireType)
 			}
-			var stringLen uint64
+			m.Spans = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -14655,24 +17134,11 @@ func (m *TimeSeries) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Spans |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.PromLabels = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
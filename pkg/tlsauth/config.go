@@ -0,0 +1,82 @@
+package tlsauth
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"regexp"
+)
+
+const (
+	SourceDNSSAN     = "dns_san"
+	SourceURISAN     = "uri_san"
+	SourceEmailSAN   = "email_san"
+	SourceCommonName = "common_name"
+
+	defaultTenant = "$1"
+)
+
+// Config maps the verified client certificate of a request to a tenant. Requests without a certificate
+// that matches one of the mappings are authenticated with the X-Scope-OrgID header.
+type Config struct {
+	Enabled  bool            `yaml:"enabled"`
+	Mappings []MappingConfig `yaml:"mappings"`
+}
+
+// MappingConfig matches Regex against the values of Source. The first value that matches is expanded
+// into Tenant, which can reference the capture groups of Regex.
+type MappingConfig struct {
+	Source string `yaml:"source"`
+	Regex  string `yaml:"regex"`
+	Tenant string `yaml:"tenant"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+"tls-tenant-auth.enabled", false, "Map the TLS client certificate of requests to the tenant.")
+}
+
+func (cfg *Config) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if len(cfg.Mappings) == 0 {
+		return errors.New("tls tenant auth requires at least one mapping")
+	}
+
+	for i, m := range cfg.Mappings {
+		switch m.source() {
+		case SourceDNSSAN, SourceURISAN, SourceEmailSAN, SourceCommonName:
+		default:
+			return fmt.Errorf("mapping %d: unsupported source %q", i, m.Source)
+		}
+
+		if m.Regex == "" {
+			return fmt.Errorf("mapping %d: regex is required", i)
+		}
+		if _, err := m.compile(); err != nil {
+			return fmt.Errorf("mapping %d: invalid regex: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (m MappingConfig) source() string {
+	if m.Source == "" {
+		return SourceDNSSAN
+	}
+	return m.Source
+}
+
+func (m MappingConfig) tenant() string {
+	if m.Tenant == "" {
+		return defaultTenant
+	}
+	return m.Tenant
+}
+
+// compile anchors the regex so it has to match the whole value
+func (m MappingConfig) compile() (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + m.Regex + ")$")
+}
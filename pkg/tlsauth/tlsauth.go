@@ -0,0 +1,218 @@
+package tlsauth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/dskit/middleware"
+	"github.com/grafana/dskit/tenant"
+	"github.com/grafana/dskit/user"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type mapping struct {
+	source string
+	regex  *regexp.Regexp
+	tenant string
+}
+
+// Authenticator resolves the tenant of a request from its verified TLS client certificate.
+type Authenticator struct {
+	mappings []mapping
+}
+
+func New(cfg Config) (*Authenticator, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	a := &Authenticator{}
+	for _, m := range cfg.Mappings {
+		regex, err := m.compile()
+		if err != nil {
+			return nil, err
+		}
+		a.mappings = append(a.mappings, mapping{
+			source: m.source(),
+			regex:  regex,
+			tenant: m.tenant(),
+		})
+	}
+
+	return a, nil
+}
+
+// TenantFromCertificate returns the tenant of the first mapping that matches the certificate.
+func (a *Authenticator) TenantFromCertificate(cert *x509.Certificate) (string, bool) {
+	for _, m := range a.mappings {
+		for _, value := range CertificateValues(cert, m.source) {
+			match := m.regex.FindStringSubmatchIndex(value)
+			if match == nil {
+				continue
+			}
+
+			tenantID := string(m.regex.ExpandString(nil, m.tenant, value, match))
+			if tenant.ValidTenantID(tenantID) != nil {
+				continue
+			}
+			return tenantID, true
+		}
+	}
+
+	return "", false
+}
+
+// TenantFromConnectionState returns the tenant of the verified client certificate of the connection.
+// Certificates that were not verified by the server are ignored.
+func (a *Authenticator) TenantFromConnectionState(state *tls.ConnectionState) (string, bool) {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	return a.TenantFromCertificate(state.VerifiedChains[0][0])
+}
+
+// TenantFromContext returns the tenant of the client certificate of a gRPC request.
+func (a *Authenticator) TenantFromContext(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return "", false
+	}
+
+	return a.TenantFromConnectionState(&info.State)
+}
+
+// HTTPMiddleware sets the X-Scope-OrgID header to the tenant of the client certificate before calling next.
+// Requests that set a different tenant in the header are rejected.
+func (a *Authenticator) HTTPMiddleware(next middleware.Interface) middleware.Interface {
+	return middleware.Func(func(h http.Handler) http.Handler {
+		wrapped := next.Wrap(h)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenantID, ok := a.TenantFromConnectionState(r.TLS)
+			if ok {
+				if orgID := r.Header.Get(user.OrgIDHeaderName); orgID != "" && orgID != tenantID {
+					http.Error(w, conflictError(orgID, tenantID).Error(), http.StatusUnauthorized)
+					return
+				}
+				r.Header.Set(user.OrgIDHeaderName, tenantID)
+			}
+			wrapped.ServeHTTP(w, r)
+		})
+	})
+}
+
+// UnaryServerInterceptor sets the X-Scope-OrgID metadata to the tenant of the client certificate before
+// calling next. Requests that set a different tenant in the metadata are rejected.
+func (a *Authenticator) UnaryServerInterceptor(next grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.injectIntoIncomingContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return next(ctx, req, info, handler)
+	}
+}
+
+// StreamServerInterceptor is the streaming equivalent of UnaryServerInterceptor.
+func (a *Authenticator) StreamServerInterceptor(next grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.injectIntoIncomingContext(ss.Context())
+		if err != nil {
+			return err
+		}
+		return next(srv, serverStream{ctx: ctx, ServerStream: ss}, info, handler)
+	}
+}
+
+// ExtractFromContext returns a context with the tenant of the client certificate of a gRPC request. It returns
+// false if the request has no certificate that maps to a tenant.
+func (a *Authenticator) ExtractFromContext(ctx context.Context) (context.Context, bool, error) {
+	tenantID, ok := a.TenantFromContext(ctx)
+	if !ok {
+		return ctx, false, nil
+	}
+
+	if orgID := orgIDFromIncomingContext(ctx); orgID != "" && orgID != tenantID {
+		return ctx, false, conflictError(orgID, tenantID)
+	}
+
+	return user.InjectOrgID(ctx, tenantID), true, nil
+}
+
+func (a *Authenticator) injectIntoIncomingContext(ctx context.Context) (context.Context, error) {
+	tenantID, ok := a.TenantFromContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+
+	orgID := orgIDFromIncomingContext(ctx)
+	if orgID != "" && orgID != tenantID {
+		return ctx, status.Error(codes.Unauthenticated, conflictError(orgID, tenantID).Error())
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = md.Copy()
+	md.Set(user.OrgIDHeaderName, tenantID)
+	return metadata.NewIncomingContext(ctx, md), nil
+}
+
+func orgIDFromIncomingContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	orgIDs := md.Get(user.OrgIDHeaderName)
+	if len(orgIDs) == 0 {
+		return ""
+	}
+	return strings.Join(orgIDs, ",")
+}
+
+func conflictError(orgID, tenantID string) error {
+	return fmt.Errorf("tenant %q of the request doesn't match the tenant %q of the client certificate", orgID, tenantID)
+}
+
+// CertificateValues returns the values of the certificate field of the given source.
+func CertificateValues(cert *x509.Certificate, source string) []string {
+	switch source {
+	case SourceDNSSAN:
+		return cert.DNSNames
+	case SourceURISAN:
+		values := make([]string, 0, len(cert.URIs))
+		for _, u := range cert.URIs {
+			values = append(values, u.String())
+		}
+		return values
+	case SourceEmailSAN:
+		return cert.EmailAddresses
+	case SourceCommonName:
+		if cert.Subject.CommonName == "" {
+			return nil
+		}
+		return []string{cert.Subject.CommonName}
+	}
+	return nil
+}
+
+type serverStream struct {
+	ctx context.Context
+	grpc.ServerStream
+}
+
+func (ss serverStream) Context() context.Context {
+	return ss.ctx
+}
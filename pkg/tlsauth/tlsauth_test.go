@@ -0,0 +1,249 @@
+package tlsauth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/grafana/dskit/middleware"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    Config
+		expErr bool
+	}{
+		{
+			name: "disabled",
+			cfg:  Config{},
+		},
+		{
+			name:   "no mappings",
+			cfg:    Config{Enabled: true},
+			expErr: true,
+		},
+		{
+			name: "valid",
+			cfg:  Config{Enabled: true, Mappings: []MappingConfig{{Regex: "(.*)\\.tenants\\.example\\.com"}}},
+		},
+		{
+			name:   "invalid source",
+			cfg:    Config{Enabled: true, Mappings: []MappingConfig{{Source: "ip_san", Regex: ".*"}}},
+			expErr: true,
+		},
+		{
+			name:   "missing regex",
+			cfg:    Config{Enabled: true, Mappings: []MappingConfig{{Source: SourceCommonName}}},
+			expErr: true,
+		},
+		{
+			name:   "invalid regex",
+			cfg:    Config{Enabled: true, Mappings: []MappingConfig{{Regex: "("}}},
+			expErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestTenantFromCertificate(t *testing.T) {
+	a, err := New(Config{
+		Enabled: true,
+		Mappings: []MappingConfig{
+			{Source: SourceURISAN, Regex: "spiffe://example.com/tenant/([a-z-]+)"},
+			{Source: SourceDNSSAN, Regex: "([a-z-]+)\\.tenants\\.example\\.com", Tenant: "team-$1"},
+			{Source: SourceEmailSAN, Regex: "(.+)@example\\.com"},
+			{Source: SourceCommonName, Regex: "tempo-.*", Tenant: "static"},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		cert     *x509.Certificate
+		expected string
+	}{
+		{
+			name:     "uri",
+			cert:     &x509.Certificate{URIs: []*url.URL{{Scheme: "spiffe", Host: "example.com", Path: "/tenant/foo"}}},
+			expected: "foo",
+		},
+		{
+			name:     "dns",
+			cert:     &x509.Certificate{DNSNames: []string{"other.example.com", "bar.tenants.example.com"}},
+			expected: "team-bar",
+		},
+		{
+			name:     "dns must match completely",
+			cert:     &x509.Certificate{DNSNames: []string{"bar.tenants.example.com.evil.com"}},
+			expected: "",
+		},
+		{
+			name:     "email",
+			cert:     &x509.Certificate{EmailAddresses: []string{"baz@example.com"}},
+			expected: "baz",
+		},
+		{
+			name:     "common name",
+			cert:     &x509.Certificate{Subject: pkix.Name{CommonName: "tempo-client"}},
+			expected: "static",
+		},
+		{
+			name:     "first mapping wins",
+			cert:     &x509.Certificate{DNSNames: []string{"bar.tenants.example.com"}, Subject: pkix.Name{CommonName: "tempo-client"}},
+			expected: "team-bar",
+		},
+		{
+			name:     "invalid tenant",
+			cert:     &x509.Certificate{EmailAddresses: []string{"a/b@example.com"}},
+			expected: "",
+		},
+		{
+			name:     "no match",
+			cert:     &x509.Certificate{DNSNames: []string{"example.com"}},
+			expected: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tenantID, ok := a.TenantFromCertificate(tc.cert)
+			require.Equal(t, tc.expected != "", ok)
+			require.Equal(t, tc.expected, tenantID)
+		})
+	}
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	a := newTestAuthenticator(t)
+
+	var orgID string
+	h := a.HTTPMiddleware(middleware.AuthenticateUser).Wrap(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		orgID, _ = user.ExtractOrgID(r.Context())
+	}))
+
+	tests := []struct {
+		name      string
+		state     *tls.ConnectionState
+		header    string
+		expStatus int
+		expTenant string
+	}{
+		{
+			name:      "certificate",
+			state:     verifiedState("foo.tenants.example.com"),
+			expStatus: http.StatusOK,
+			expTenant: "foo",
+		},
+		{
+			name:      "certificate and same header",
+			state:     verifiedState("foo.tenants.example.com"),
+			header:    "foo",
+			expStatus: http.StatusOK,
+			expTenant: "foo",
+		},
+		{
+			name:      "certificate and different header",
+			state:     verifiedState("foo.tenants.example.com"),
+			header:    "bar",
+			expStatus: http.StatusUnauthorized,
+		},
+		{
+			name:      "unverified certificate falls back to header",
+			state:     &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{DNSNames: []string{"foo.tenants.example.com"}}}},
+			header:    "bar",
+			expStatus: http.StatusOK,
+			expTenant: "bar",
+		},
+		{
+			name:      "unmapped certificate falls back to header",
+			state:     verifiedState("ingester.example.com"),
+			header:    "bar",
+			expStatus: http.StatusOK,
+			expTenant: "bar",
+		},
+		{
+			name:      "no certificate and no header",
+			expStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			orgID = ""
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.TLS = tc.state
+			if tc.header != "" {
+				req.Header.Set(user.OrgIDHeaderName, tc.header)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			require.Equal(t, tc.expStatus, rec.Code)
+			require.Equal(t, tc.expTenant, orgID)
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	a := newTestAuthenticator(t)
+
+	interceptor := a.UnaryServerInterceptor(middleware.ServerUserHeaderInterceptor)
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		return user.ExtractOrgID(ctx)
+	}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: *verifiedState("foo.tenants.example.com")}})
+
+	orgID, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	require.Equal(t, "foo", orgID)
+
+	_, err = interceptor(metadata.NewIncomingContext(ctx, metadata.Pairs(user.OrgIDHeaderName, "bar")), nil, &grpc.UnaryServerInfo{}, handler)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	orgID, err = interceptor(metadata.NewIncomingContext(context.Background(), metadata.Pairs(user.OrgIDHeaderName, "bar")), nil, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	require.Equal(t, "bar", orgID)
+}
+
+func newTestAuthenticator(t *testing.T) *Authenticator {
+	a, err := New(Config{
+		Enabled:  true,
+		Mappings: []MappingConfig{{Regex: "([a-z-]+)\\.tenants\\.example\\.com"}},
+	})
+	require.NoError(t, err)
+	return a
+}
+
+func verifiedState(dnsNames ...string) *tls.ConnectionState {
+	cert := &x509.Certificate{DNSNames: dnsNames}
+	return &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+		VerifiedChains:   [][]*x509.Certificate{{cert}},
+	}
+}
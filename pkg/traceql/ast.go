@@ -21,6 +21,7 @@ type metricsFirstStageElement interface {
 	observe(Span)                        // TODO - batching?
 	observeSeries([]*tempopb.TimeSeries) // Re-entrant metrics on the query-frontend.  Using proto version for efficiency
 	result() SeriesSet
+	length() int // Number of series observed so far
 }
 
 type pipelineElement interface {
@@ -222,6 +223,7 @@ func (o ScalarOperation) extractConditions(request *FetchSpansRequest) {
 type Aggregate struct {
 	op AggregateOp
 	e  FieldExpression
+	q  float64 // quantile of aggregatePercentile, e.g. 0.9 for p90
 }
 
 func newAggregate(agg AggregateOp, e FieldExpression) Aggregate {
@@ -231,6 +233,14 @@ func newAggregate(agg AggregateOp, e FieldExpression) Aggregate {
 	}
 }
 
+func newAggregatePercentile(q float64, e FieldExpression) Aggregate {
+	return Aggregate{
+		op: aggregatePercentile,
+		e:  e,
+		q:  q,
+	}
+}
+
 // nolint: revive
 func (Aggregate) __scalarExpression() {}
 
@@ -717,6 +727,10 @@ func (a Attribute) impliedType() StaticType {
 		return TypeString
 	case IntrinsicLinkSpanID:
 		return TypeString
+	case IntrinsicEventCount:
+		return TypeInt
+	case IntrinsicLinkCount:
+		return TypeInt
 	case IntrinsicParent:
 		return TypeNil
 	case IntrinsicTraceDuration:
@@ -810,6 +824,14 @@ func newMetricsAggregateQuantileOverTime(attr Attribute, qs []float64, by []Attr
 	}
 }
 
+func newMetricsAggregateWithAttr(agg MetricsAggregateOp, attr Attribute, by []Attribute) *MetricsAggregate {
+	return &MetricsAggregate{
+		op:   agg,
+		attr: attr,
+		by:   by,
+	}
+}
+
 func newMetricsAggregateHistogramOverTime(attr Attribute, by []Attribute) *MetricsAggregate {
 	return &MetricsAggregate{
 		op:   metricsAggregateHistogramOverTime,
@@ -822,7 +844,8 @@ func (a *MetricsAggregate) extractConditions(request *FetchSpansRequest) {
 	switch a.op {
 	case metricsAggregateRate, metricsAggregateCountOverTime:
 		// No extra conditions, start time is already enough
-	case metricsAggregateQuantileOverTime, metricsAggregateHistogramOverTime:
+	case metricsAggregateQuantileOverTime, metricsAggregateHistogramOverTime,
+		metricsAggregateSumOverTime, metricsAggregateMinOverTime, metricsAggregateMaxOverTime:
 		if !request.HasAttribute(a.attr) {
 			request.SecondPassConditions = append(request.SecondPassConditions, Condition{
 				Attribute: a.attr,
@@ -861,7 +884,15 @@ func (a *MetricsAggregate) init(q *tempopb.QueryRangeRequest, mode AggregateMode
 		innerAgg = func() VectorAggregator { return NewCountOverTimeAggregator() }
 
 	case metricsAggregateRate:
-		innerAgg = func() VectorAggregator { return NewRateAggregator(1.0 / time.Duration(q.Step).Seconds()) }
+		// The rate is per second over the window of each sample
+		window := q.Step
+		if q.Lookback > 0 {
+			window = q.Lookback
+		}
+		innerAgg = func() VectorAggregator { return NewRateAggregator(1.0 / time.Duration(window).Seconds()) }
+
+	case metricsAggregateSumOverTime, metricsAggregateMinOverTime, metricsAggregateMaxOverTime:
+		innerAgg = func() VectorAggregator { return NewOverTimeAggregator(a.attr, a.simpleAggregationOp()) }
 
 	case metricsAggregateHistogramOverTime:
 		// Histograms are implemented as count_over_time() by(2^log2(attr)) for now
@@ -884,25 +915,8 @@ func (a *MetricsAggregate) init(q *tempopb.QueryRangeRequest, mode AggregateMode
 				return NewStaticFloat(Log2Bucketize(d) / float64(time.Second)), true
 			}
 		default:
-			// Basic implementation for all other attributes
-			byFunc = func(s Span) (Static, bool) {
-				v, ok := s.AttributeFor(a.attr)
-				if !ok {
-					return Static{}, false
-				}
-
-				// TODO(mdisibio) - Add support for floats, we need to map them into buckets.
-				// Because of the range of floats, we need a native histogram approach.
-				if v.Type != TypeInt {
-					return Static{}, false
-				}
-
-				if v.N < 2 {
-					return Static{}, false
-				}
-				// Bucket is the value rounded up to the nearest power of 2
-				return NewStaticFloat(Log2Bucketize(uint64(v.N))), true
-			}
+			// Basic implementation for all other numeric attributes
+			byFunc = bucketizeAttribute(a.attr)
 		}
 
 	case metricsAggregateQuantileOverTime:
@@ -921,36 +935,58 @@ func (a *MetricsAggregate) init(q *tempopb.QueryRangeRequest, mode AggregateMode
 				return NewStaticFloat(Log2Bucketize(d) / float64(time.Second)), true
 			}
 		default:
-			// Basic implementation for all other attributes
-			byFunc = func(s Span) (Static, bool) {
-				v, ok := s.AttributeFor(a.attr)
-				if !ok {
-					return Static{}, false
-				}
-
-				// TODO(mdisibio) - Add support for floats, we need to map them into buckets.
-				// Because of the range of floats, we need a native histogram approach.
-				if v.Type != TypeInt {
-					return Static{}, false
-				}
-
-				if v.N < 2 {
-					return Static{}, false
-				}
-				return NewStaticFloat(Log2Bucketize(uint64(v.N))), true
-			}
+			// Basic implementation for all other numeric attributes
+			byFunc = bucketizeAttribute(a.attr)
 		}
 	}
 
+	newExemplars := exemplarSamplerFor(q)
 	a.agg = NewGroupingAggregator(a.op.String(), func() RangeAggregator {
-		return NewStepAggregator(q.Start, q.End, q.Step, innerAgg)
+		agg := NewStepAggregator(q.Start, q.End, q.Step, innerAgg)
+		agg.offset = q.Offset
+		agg.lookback = q.Lookback
+		agg.exemplars = newExemplars()
+		return agg
 	}, a.by, byFunc, byFuncLabel)
 }
 
+// bucketizeAttribute returns a function that puts the value of a numeric attribute in its power of 2 bucket. Ints and
+// floats are bucketed by their value, durations by their value in seconds like the duration intrinsic. Spans without
+// the attribute, with a value of another type or with a value below the first bucket are skipped.
+func bucketizeAttribute(attr Attribute) func(Span) (Static, bool) {
+	return func(s Span) (Static, bool) {
+		v, ok := s.AttributeFor(attr)
+		if !ok {
+			return Static{}, false
+		}
+
+		switch v.Type {
+		case TypeInt:
+			if v.N < 2 {
+				return Static{}, false
+			}
+			// Bucket is the value rounded up to the nearest power of 2
+			return NewStaticFloat(Log2Bucketize(uint64(v.N))), true
+		case TypeFloat:
+			if v.F <= 0 {
+				return Static{}, false
+			}
+			return NewStaticFloat(Log2BucketizeFloat(v.F)), true
+		case TypeDuration:
+			if v.D < 2 {
+				return Static{}, false
+			}
+			return NewStaticFloat(Log2Bucketize(uint64(v.D)) / float64(time.Second)), true
+		default:
+			return Static{}, false
+		}
+	}
+}
+
 func (a *MetricsAggregate) initSum(q *tempopb.QueryRangeRequest) {
-	// Currently all metrics are summed by job to produce
-	// intermediate results. This will change when adding min/max/topk/etc
-	a.seriesAgg = NewSimpleAdditionCombiner(q)
+	// Intermediate results are summed by job, except for min/max
+	// which compute another level of min/maxing.
+	a.seriesAgg = NewSimpleCombiner(q, a.simpleAggregationOp())
 }
 
 func (a *MetricsAggregate) initFinal(q *tempopb.QueryRangeRequest) {
@@ -958,8 +994,20 @@ func (a *MetricsAggregate) initFinal(q *tempopb.QueryRangeRequest) {
 	case metricsAggregateQuantileOverTime:
 		a.seriesAgg = NewHistogramAggregator(q, a.floats)
 	default:
-		// These are simple additions by series
-		a.seriesAgg = NewSimpleAdditionCombiner(q)
+		// These are simple aggregations by series
+		a.seriesAgg = NewSimpleCombiner(q, a.simpleAggregationOp())
+	}
+}
+
+// simpleAggregationOp returns how values of the same series are combined.
+func (a *MetricsAggregate) simpleAggregationOp() SimpleAggregationOp {
+	switch a.op {
+	case metricsAggregateMinOverTime:
+		return minAggregation
+	case metricsAggregateMaxOverTime:
+		return maxAggregation
+	default:
+		return sumAggregation
 	}
 }
 
@@ -981,6 +1029,13 @@ func (a *MetricsAggregate) result() SeriesSet {
 	return a.seriesAgg.Results()
 }
 
+func (a *MetricsAggregate) length() int {
+	if a.agg != nil {
+		return a.agg.Length()
+	}
+	return 0
+}
+
 func (a *MetricsAggregate) validate() error {
 	switch a.op {
 	case metricsAggregateCountOverTime:
@@ -990,6 +1045,13 @@ func (a *MetricsAggregate) validate() error {
 			// We reserve a spot for the bucket so quantile has 1 less group by
 			return newUnsupportedError(fmt.Sprintf("metrics group by %v values", len(a.by)))
 		}
+		if err := a.validateNumericAttr(); err != nil {
+			return err
+		}
+	case metricsAggregateSumOverTime, metricsAggregateMinOverTime, metricsAggregateMaxOverTime:
+		if err := a.validateNumericAttr(); err != nil {
+			return err
+		}
 	case metricsAggregateQuantileOverTime:
 		if len(a.by) >= maxGroupBys {
 			// We reserve a spot for the bucket so quantile has 1 less group by
@@ -1000,6 +1062,9 @@ func (a *MetricsAggregate) validate() error {
 				return fmt.Errorf("quantile must be between 0 and 1: %v", q)
 			}
 		}
+		if err := a.validateNumericAttr(); err != nil {
+			return err
+		}
 	default:
 		return newUnsupportedError(fmt.Sprintf("metrics aggregate operation (%v)", a.op))
 	}
@@ -1011,4 +1076,17 @@ func (a *MetricsAggregate) validate() error {
 	return nil
 }
 
+// validateNumericAttr returns an error if the attribute of the aggregate can't be numeric.
+func (a *MetricsAggregate) validateNumericAttr() error {
+	if err := a.attr.validate(); err != nil {
+		return err
+	}
+	switch a.attr.impliedType() {
+	case TypeAttribute, TypeInt, TypeFloat, TypeDuration:
+		return nil
+	default:
+		return fmt.Errorf("%s requires a numeric attribute: %s", a.op, a.attr)
+	}
+}
+
 var _ metricsFirstStageElement = (*MetricsAggregate)(nil)
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"math"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 )
 
 var errSpansetOperationMultiple = errors.New("spanset operators are not supported for multiple spansets per trace. consider using coalesce()")
@@ -202,126 +204,208 @@ func (o SelectOperation) evaluate(input []*Spanset) (output []*Spanset, err erro
 }
 
 func (f ScalarFilter) evaluate(input []*Spanset) (output []*Spanset, err error) {
-	// TODO we solve this gap where pipeline elements and scalar binary
-	// operations meet in a generic way. For now we only support well-defined
-	// case: aggregate binop static
-	switch l := f.lhs.(type) {
-	case Aggregate:
-		switch r := f.rhs.(type) {
-		case Static:
-			input, err = l.evaluate(input)
-			if err != nil {
-				return nil, err
-			}
-
-			for _, ss := range input {
-				res, err := binOp(f.op, ss.Scalar, r)
-				if err != nil {
-					return nil, fmt.Errorf("scalar filter (%v) failed: %v", f, err)
-				}
-				if res {
-					output = append(output, ss)
-				}
-			}
+	for _, ss := range input {
+		cpy := ss.clone()
 
-		default:
-			return nil, fmt.Errorf("scalar filter lhs (%v) not supported", f.lhs)
+		lhs, err := evaluateScalar(f.lhs, ss, cpy)
+		if err != nil {
+			return nil, err
+		}
+		rhs, err := evaluateScalar(f.rhs, ss, cpy)
+		if err != nil {
+			return nil, err
 		}
 
-	default:
-		return nil, fmt.Errorf("scalar filter lhs (%v) not supported", f.lhs)
+		res, err := binOp(f.op, lhs, rhs)
+		if err != nil {
+			return nil, fmt.Errorf("scalar filter (%v) failed: %v", f, err)
+		}
+		if res {
+			cpy.Scalar = lhs
+			output = append(output, cpy)
+		}
 	}
 
 	return output, nil
 }
 
+// evaluateScalar evaluates an operand of a scalar filter for the spanset. The values of the aggregates are added
+// as attributes to out.
+func evaluateScalar(e ScalarExpression, ss *Spanset, out *Spanset) (Static, error) {
+	switch o := e.(type) {
+	case Static:
+		return o, nil
+
+	case Aggregate:
+		val, err := o.aggregate(ss)
+		if err != nil {
+			return NewStaticNil(), err
+		}
+		out.AddAttribute(o.String(), val)
+		return val, nil
+
+	case ScalarOperation:
+		lhs, err := evaluateScalar(o.LHS, ss, out)
+		if err != nil {
+			return NewStaticNil(), err
+		}
+		rhs, err := evaluateScalar(o.RHS, ss, out)
+		if err != nil {
+			return NewStaticNil(), err
+		}
+		return (&BinaryOperation{Op: o.Op, LHS: lhs, RHS: rhs}).execute(nil)
+	}
+
+	return NewStaticNil(), fmt.Errorf("scalar filter operand (%v) not supported", e)
+}
+
 func (a Aggregate) evaluate(input []*Spanset) (output []*Spanset, err error) {
 	for _, ss := range input {
-		switch a.op {
-		case aggregateCount:
-			cpy := ss.clone()
-			cpy.Scalar = NewStaticInt(len(ss.Spans))
-			cpy.AddAttribute(a.String(), cpy.Scalar)
-			output = append(output, cpy)
+		val, err := a.aggregate(ss)
+		if err != nil {
+			return nil, err
+		}
+
+		cpy := ss.clone()
+		cpy.Scalar = val
+		cpy.AddAttribute(a.String(), cpy.Scalar)
+		output = append(output, cpy)
+	}
 
-		case aggregateAvg:
-			var sum *Static
-			count := 0
-			for _, s := range ss.Spans {
-				val, err := a.e.execute(s)
-				if err != nil {
-					return nil, err
-				}
-
-				if sum == nil {
-					sum = &val
-				} else {
-					sum.sumInto(val)
-				}
-				count++
+	return output, nil
+}
+
+// aggregate returns the value of the aggregate over the spans of the spanset.
+func (a Aggregate) aggregate(ss *Spanset) (Static, error) {
+	switch a.op {
+	case aggregateCount:
+		return NewStaticInt(len(ss.Spans)), nil
+
+	case aggregateAvg:
+		var sum *Static
+		count := 0
+		for _, s := range ss.Spans {
+			val, err := a.e.execute(s)
+			if err != nil {
+				return NewStaticNil(), err
 			}
 
-			cpy := ss.clone()
-			cpy.Scalar = sum.divideBy(float64(count))
-			cpy.AddAttribute(a.String(), cpy.Scalar)
-			output = append(output, cpy)
+			if sum == nil {
+				sum = &val
+			} else {
+				sum.sumInto(val)
+			}
+			count++
+		}
 
-		case aggregateMax:
-			var max *Static
-			for _, s := range ss.Spans {
-				val, err := a.e.execute(s)
-				if err != nil {
-					return nil, err
-				}
-				if max == nil || val.compare(max) == 1 {
-					max = &val
-				}
+		return sum.divideBy(float64(count)), nil
+
+	case aggregateMax:
+		var max *Static
+		for _, s := range ss.Spans {
+			val, err := a.e.execute(s)
+			if err != nil {
+				return NewStaticNil(), err
 			}
-			cpy := ss.clone()
-			cpy.Scalar = *max
-			cpy.AddAttribute(a.String(), cpy.Scalar)
-			output = append(output, cpy)
+			if max == nil || val.compare(max) == 1 {
+				max = &val
+			}
+		}
+		return *max, nil
 
-		case aggregateMin:
-			var min *Static
-			for _, s := range ss.Spans {
-				val, err := a.e.execute(s)
-				if err != nil {
-					return nil, err
-				}
-				if min == nil || val.compare(min) == -1 {
-					min = &val
-				}
+	case aggregateMin:
+		var min *Static
+		for _, s := range ss.Spans {
+			val, err := a.e.execute(s)
+			if err != nil {
+				return NewStaticNil(), err
 			}
-			cpy := ss.clone()
-			cpy.Scalar = *min
-			cpy.AddAttribute(a.String(), cpy.Scalar)
-			output = append(output, cpy)
+			if min == nil || val.compare(min) == -1 {
+				min = &val
+			}
+		}
+		return *min, nil
 
-		case aggregateSum:
-			var sum *Static
-			for _, s := range ss.Spans {
-				val, err := a.e.execute(s)
-				if err != nil {
-					return nil, err
-				}
-				if sum == nil {
-					sum = &val
-				} else {
-					sum.sumInto(val)
-				}
+	case aggregateSum:
+		var sum *Static
+		for _, s := range ss.Spans {
+			val, err := a.e.execute(s)
+			if err != nil {
+				return NewStaticNil(), err
 			}
-			cpy := ss.clone()
-			cpy.Scalar = *sum
-			cpy.AddAttribute(a.String(), cpy.Scalar)
-			output = append(output, cpy)
+			if sum == nil {
+				sum = &val
+			} else {
+				sum.sumInto(val)
+			}
+		}
+		return *sum, nil
 
-		default:
-			return nil, fmt.Errorf("aggregate operation (%v) not supported", a.op)
+	case aggregateStddev:
+		vals, t, err := a.numericValues(ss)
+		if err != nil || len(vals) == 0 {
+			return NewStaticNil(), err
 		}
+
+		mean := 0.0
+		for _, v := range vals {
+			mean += v
+		}
+		mean /= float64(len(vals))
+
+		variance := 0.0
+		for _, v := range vals {
+			variance += (v - mean) * (v - mean)
+		}
+		variance /= float64(len(vals))
+
+		return newStaticNumeric(t, math.Sqrt(variance)), nil
+
+	case aggregatePercentile:
+		vals, t, err := a.numericValues(ss)
+		if err != nil || len(vals) == 0 {
+			return NewStaticNil(), err
+		}
+
+		// linear interpolation between the closest ranks
+		sort.Float64s(vals)
+		rank := a.q * float64(len(vals)-1)
+		lo, hi := int(math.Floor(rank)), int(math.Ceil(rank))
+		v := vals[lo] + (vals[hi]-vals[lo])*(rank-float64(lo))
+
+		return newStaticNumeric(t, v), nil
 	}
 
-	return output, nil
+	return NewStaticNil(), fmt.Errorf("aggregate operation (%v) not supported", a.op)
+}
+
+// numericValues returns the numeric values of the aggregated field expression. Values of other types are skipped.
+// The returned type is TypeDuration if all values are durations and TypeFloat otherwise.
+func (a Aggregate) numericValues(ss *Spanset) ([]float64, StaticType, error) {
+	vals := make([]float64, 0, len(ss.Spans))
+	t := TypeDuration
+	for _, s := range ss.Spans {
+		val, err := a.e.execute(s)
+		if err != nil {
+			return nil, TypeNil, err
+		}
+		if !val.Type.isNumeric() {
+			continue
+		}
+		if val.Type != TypeDuration {
+			t = TypeFloat
+		}
+		vals = append(vals, val.asFloat())
+	}
+
+	return vals, t, nil
+}
+
+func newStaticNumeric(t StaticType, v float64) Static {
+	if t == TypeDuration {
+		return NewStaticDuration(time.Duration(v))
+	}
+	return NewStaticFloat(v)
 }
 
 func (o *BinaryOperation) execute(span Span) (Static, error) {
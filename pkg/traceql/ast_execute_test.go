@@ -784,6 +784,75 @@ func TestScalarFilterEvaluate(t *testing.T) {
 	}
 }
 
+func TestScalarFilterEvaluateAggregates(t *testing.T) {
+	spans := func(ds ...time.Duration) []Span {
+		spans := make([]Span, 0, len(ds))
+		for _, d := range ds {
+			spans = append(spans, &mockSpan{attributes: map[Attribute]Static{NewIntrinsic(IntrinsicDuration): NewStaticDuration(d)}})
+		}
+		return spans
+	}
+
+	testCases := []evalTC{
+		{
+			"{ true } | p90(duration) > 10ms",
+			[]*Spanset{
+				// p90 = 9.1ms
+				{Spans: spans(1*time.Millisecond, 2*time.Millisecond, 3*time.Millisecond, 4*time.Millisecond, 5*time.Millisecond,
+					6*time.Millisecond, 7*time.Millisecond, 8*time.Millisecond, 9*time.Millisecond, 10*time.Millisecond)},
+				// p90 = 46ms
+				{Spans: spans(10*time.Millisecond, 20*time.Millisecond, 30*time.Millisecond, 40*time.Millisecond, 50*time.Millisecond)},
+			},
+			[]*Spanset{
+				{
+					Scalar:     NewStaticDuration(46 * time.Millisecond),
+					Spans:      spans(10*time.Millisecond, 20*time.Millisecond, 30*time.Millisecond, 40*time.Millisecond, 50*time.Millisecond),
+					Attributes: []*SpansetAttribute{{Name: "p90(duration)", Val: NewStaticDuration(46 * time.Millisecond)}},
+				},
+			},
+		},
+		{
+			"{ true } | stddev(duration) > 1ms",
+			[]*Spanset{
+				// stddev = 0
+				{Spans: spans(2*time.Millisecond, 2*time.Millisecond)},
+				// stddev = 2ms
+				{Spans: spans(2*time.Millisecond, 6*time.Millisecond)},
+			},
+			[]*Spanset{
+				{
+					Scalar:     NewStaticDuration(2 * time.Millisecond),
+					Spans:      spans(2*time.Millisecond, 6*time.Millisecond),
+					Attributes: []*SpansetAttribute{{Name: "stddev(duration)", Val: NewStaticDuration(2 * time.Millisecond)}},
+				},
+			},
+		},
+		{
+			"{ true } | max(duration) > 2 * avg(duration)",
+			[]*Spanset{
+				// max = 10ms, avg = 4ms
+				{Spans: spans(1*time.Millisecond, 1*time.Millisecond, 10*time.Millisecond)},
+				// max = 6ms, avg = 5ms
+				{Spans: spans(4*time.Millisecond, 5*time.Millisecond, 6*time.Millisecond)},
+			},
+			[]*Spanset{
+				{
+					Scalar: NewStaticDuration(10 * time.Millisecond),
+					Spans:  spans(1*time.Millisecond, 1*time.Millisecond, 10*time.Millisecond),
+					Attributes: []*SpansetAttribute{
+						{Name: "max(duration)", Val: NewStaticDuration(10 * time.Millisecond)},
+						{Name: "avg(duration)", Val: NewStaticDuration(4 * time.Millisecond)},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		testEvaluator(t, tc)
+	}
+}
+
 func TestBinaryOperationsWorkAcrossNumberTypes(t *testing.T) {
 	testCases := []evalTC{
 		{
@@ -49,11 +49,25 @@ func (o ScalarOperation) String() string {
 }
 
 func (a Aggregate) String() string {
+	op := a.op.String()
+	if a.op == aggregatePercentile {
+		op = percentileName(a.q)
+	}
+
 	if a.e == nil {
-		return a.op.String() + "()"
+		return op + "()"
 	}
 
-	return a.op.String() + "(" + a.e.String() + ")"
+	return op + "(" + a.e.String() + ")"
+}
+
+// percentileName is the inverse of parsePercentile, e.g. 0.9 is p90.
+func percentileName(q float64) string {
+	digits := strings.TrimPrefix(strconv.FormatFloat(q, 'f', -1, 64), "0.")
+	if len(digits) < 2 {
+		digits += "0"
+	}
+	return "p" + digits
 }
 
 func (o SpansetOperation) String() string {
@@ -141,6 +155,8 @@ func (a MetricsAggregate) String() string {
 	s.WriteString(a.op.String())
 	s.WriteString("(")
 	switch a.op {
+	case metricsAggregateSumOverTime, metricsAggregateMinOverTime, metricsAggregateMaxOverTime:
+		s.WriteString(a.attr.String())
 	case metricsAggregateQuantileOverTime:
 		s.WriteString(a.attr.String())
 		s.WriteString(",")
@@ -105,7 +105,7 @@ func (a Aggregate) validate() error {
 	}
 
 	switch a.op {
-	case aggregateCount, aggregateAvg, aggregateMin, aggregateMax, aggregateSum:
+	case aggregateCount, aggregateAvg, aggregateMin, aggregateMax, aggregateSum, aggregateStddev, aggregatePercentile:
 	default:
 		return newUnsupportedError(fmt.Sprintf("aggregate operation (%v)", a.op))
 	}
@@ -153,19 +153,42 @@ func (f ScalarFilter) validate() error {
 	}
 
 	// Only supported expression types
-	switch f.lhs.(type) {
-	case Aggregate:
-	default:
-		return newUnsupportedError("scalar filter lhs of type (%v)")
+	lhsAggregate, err := validateScalarFilterOperand(f.lhs)
+	if err != nil {
+		return err
 	}
+	rhsAggregate, err := validateScalarFilterOperand(f.rhs)
+	if err != nil {
+		return err
+	}
+	if !lhsAggregate && !rhsAggregate {
+		return newUnsupportedError("scalar filter without an aggregate")
+	}
+
+	return nil
+}
 
-	switch f.rhs.(type) {
+// validateScalarFilterOperand checks that the operand of a scalar filter only consists of aggregates, statics and
+// arithmetic between them. It returns true if the operand contains an aggregate.
+func validateScalarFilterOperand(e ScalarExpression) (bool, error) {
+	switch o := e.(type) {
+	case Aggregate:
+		return true, nil
 	case Static:
-	default:
-		return newUnsupportedError("scalar filter rhs of type (%v)")
+		return false, nil
+	case ScalarOperation:
+		lhs, err := validateScalarFilterOperand(o.LHS)
+		if err != nil {
+			return false, err
+		}
+		rhs, err := validateScalarFilterOperand(o.RHS)
+		if err != nil {
+			return false, err
+		}
+		return lhs || rhs, nil
 	}
 
-	return nil
+	return false, newUnsupportedError(fmt.Sprintf("scalar filter operand (%v)", e))
 }
 
 func (o *BinaryOperation) validate() error {
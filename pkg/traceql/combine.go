@@ -37,6 +37,22 @@ func (c *MetadataCombiner) Exists(id string) bool {
 	return ok
 }
 
+// Remove removes the trace with the given id.
+func (c *MetadataCombiner) Remove(id string) {
+	delete(c.trs, id)
+}
+
+// Oldest returns the trace with the earliest start time or nil if the combiner is empty.
+func (c *MetadataCombiner) Oldest() *tempopb.TraceSearchMetadata {
+	var oldest *tempopb.TraceSearchMetadata
+	for _, tr := range c.trs {
+		if oldest == nil || tr.StartTimeUnixNano < oldest.StartTimeUnixNano {
+			oldest = tr
+		}
+	}
+	return oldest
+}
+
 func (c *MetadataCombiner) Metadata() []*tempopb.TraceSearchMetadata {
 	m := make([]*tempopb.TraceSearchMetadata, 0, len(c.trs))
 	for _, tr := range c.trs {
@@ -141,6 +157,61 @@ func spansetID(ss *tempopb.SpanSet) string {
 	return id
 }
 
+// SearchGroups counts the traces and matched spans per group of the spansets grouped with by(). Spansets
+// that aren't grouped are skipped. Groups are sorted by number of traces, then matched spans.
+func SearchGroups(traces []*tempopb.TraceSearchMetadata) []*tempopb.SearchGroup {
+	groups := map[string]*tempopb.SearchGroup{}
+
+	for _, tr := range traces {
+		for _, ss := range tr.SpanSets {
+			// spansets of a trace are unique per group, see spansetID
+			id := spansetID(ss)
+			if id == "" {
+				continue
+			}
+
+			group, ok := groups[id]
+			if !ok {
+				group = &tempopb.SearchGroup{}
+				for _, a := range ss.Attributes {
+					if strings.HasPrefix(a.Key, "by") {
+						group.Attributes = append(group.Attributes, a)
+					}
+				}
+				groups[id] = group
+			}
+
+			group.Traces++
+			group.Matched += ss.Matched
+		}
+	}
+
+	if len(groups) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(groups))
+	for id := range groups {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		gi, gj := groups[ids[i]], groups[ids[j]]
+		if gi.Traces != gj.Traces {
+			return gi.Traces > gj.Traces
+		}
+		if gi.Matched != gj.Matched {
+			return gi.Matched > gj.Matched
+		}
+		return ids[i] < ids[j]
+	})
+
+	result := make([]*tempopb.SearchGroup, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, groups[id])
+	}
+	return result
+}
+
 type QueryRangeCombiner struct {
 	req     *tempopb.QueryRangeRequest
 	eval    *MetricsFrontendEvaluator
@@ -263,3 +263,31 @@ func TestCombineResults(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchGroups(t *testing.T) {
+	byService := func(service string, matched uint32) *tempopb.SpanSet {
+		return &tempopb.SpanSet{
+			Matched: matched,
+			Attributes: []*v1.KeyValue{
+				{Key: "by(resource.service.name)", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: service}}},
+			},
+		}
+	}
+
+	traces := []*tempopb.TraceSearchMetadata{
+		{TraceID: "1", SpanSets: []*tempopb.SpanSet{byService("a", 2), byService("b", 1)}},
+		{TraceID: "2", SpanSets: []*tempopb.SpanSet{byService("b", 5)}},
+		{TraceID: "3", SpanSets: []*tempopb.SpanSet{byService("c", 3), {Matched: 4}}},
+		{TraceID: "4", SpanSets: []*tempopb.SpanSet{byService("a", 1)}},
+	}
+
+	expected := []*tempopb.SearchGroup{
+		{Attributes: byService("b", 0).Attributes, Traces: 2, Matched: 6},
+		{Attributes: byService("a", 0).Attributes, Traces: 2, Matched: 3},
+		{Attributes: byService("c", 0).Attributes, Traces: 1, Matched: 3},
+	}
+	require.Equal(t, expected, SearchGroups(traces))
+
+	// no grouping
+	require.Nil(t, SearchGroups([]*tempopb.TraceSearchMetadata{{TraceID: "1", SpanSets: []*tempopb.SpanSet{{Matched: 1}}}}))
+}
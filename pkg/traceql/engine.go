@@ -45,6 +45,8 @@ func (e *Engine) Compile(query string) (*RootExpr, SpansetFilterFunc, metricsFir
 	return expr, expr.Pipeline.evaluate, expr.MetricsPipeline, req, nil
 }
 
+// ExecuteSearch runs the search request against the passed fetcher. If the context deadline is exceeded while
+// reading results the spansets found so far are returned along with the error.
 func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchRequest, spanSetFetcher SpansetFetcher) (*tempopb.SearchResponse, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "traceql.Engine.ExecuteSearch")
 	defer span.Finish()
@@ -110,8 +112,14 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 		Metrics: &tempopb.SearchMetrics{},
 	}
 	combiner := NewMetadataCombiner()
+	var deadlineErr error
 	for {
 		spanset, err := iterator.Next(ctx)
+		if errors.Is(err, context.DeadlineExceeded) {
+			span.LogKV("msg", "iterator.Next", "err", err)
+			deadlineErr = err
+			break
+		}
 		if err != nil && !errors.Is(err, io.EOF) {
 			span.LogKV("msg", "iterator.Next", "err", err)
 			return nil, err
@@ -137,7 +145,7 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 		span.SetTag("inspectedBytes", res.Metrics.InspectedBytes)
 	}
 
-	return res, nil
+	return res, deadlineErr
 }
 
 func (e *Engine) ExecuteTagValues(
@@ -80,6 +80,54 @@ func TrimToOverlap(start1, end1, step, start2, end2 uint64) (uint64, uint64) {
 	return start1, end1
 }
 
+// TrimToBlockOverlap returns the range of the samples of the request that the spans of a block between blockStart
+// and blockEnd contribute to. The range is aligned to the sample timestamps of the request, so unlike TrimToOverlap
+// it also works for requests that aren't aligned to the step.
+func TrimToBlockOverlap(req *tempopb.QueryRangeRequest, blockStart, blockEnd uint64) (uint64, uint64) {
+	// Samples evaluate the spans up to their timestamp minus the offset, and with a lookback
+	// the samples up to the lookback after the end of the block include its spans.
+	start := max(req.Start, blockStart+req.Offset)
+	end := min(req.End, blockEnd+req.Offset+req.Lookback)
+	if end < start {
+		return start, start
+	}
+
+	start = req.Start + (start-req.Start)/req.Step*req.Step
+	end = req.Start + (end-req.Start)/req.Step*req.Step + req.Step
+	return start, end
+}
+
+// SpanTimeRange returns the range [start, end) of the span start times that contribute to the samples of the
+// request. Without an offset and lookback this is the time range of the request.
+func SpanTimeRange(req *tempopb.QueryRangeRequest) (uint64, uint64) {
+	start, end := req.Start, req.End
+	if req.Lookback > 0 {
+		// The sample at t includes the spans that started in (t-lookback, t]
+		start = start - min(start, req.Lookback) + 1
+		end++
+	}
+
+	return start - min(start, req.Offset), end - min(end, req.Offset)
+}
+
+// lookbackIntervals returns the first and last interval whose lookback window (t-lookback, t] includes the
+// timestamp. first is greater than last if there is none.
+func lookbackIntervals(ts, start, step, lookback uint64, intervals int) (int, int) {
+	if ts+lookback <= start {
+		return 0, -1
+	}
+
+	first := 0
+	if ts > start {
+		first = int((ts - start + step - 1) / step)
+	}
+	last := int((ts + lookback - 1 - start) / step)
+	if last >= intervals {
+		last = intervals - 1
+	}
+	return first, last
+}
+
 type Label struct {
 	Name  string
 	Value Static
@@ -116,8 +164,9 @@ func (ls Labels) String() string {
 }
 
 type TimeSeries struct {
-	Labels Labels
-	Values []float64
+	Labels    Labels
+	Values    []float64
+	Exemplars []Exemplar
 }
 
 // SeriesSet is a set of unique timeseries. They are mapped by the "Prometheus"-style
@@ -141,6 +190,10 @@ func (set SeriesSet) ToProto(req *tempopb.QueryRangeRequest) []*tempopb.TimeSeri
 		intervals := IntervalCount(req.Start, req.End, req.Step)
 		samples := make([]tempopb.Sample, 0, intervals)
 		for i, value := range s.Values {
+			// NaN marks intervals without a value, e.g. min_over_time without any span
+			if math.IsNaN(value) {
+				continue
+			}
 			ts := TimestampOf(uint64(i), req.Start, req.Step)
 			samples = append(samples, tempopb.Sample{
 				TimestampMs: time.Unix(0, int64(ts)).UnixMilli(),
@@ -152,6 +205,7 @@ func (set SeriesSet) ToProto(req *tempopb.QueryRangeRequest) []*tempopb.TimeSeri
 			PromLabels: promLabels,
 			Labels:     labels,
 			Samples:    samples,
+			Exemplars:  exemplarsToProto(s.Exemplars),
 		}
 
 		resp = append(resp, ss)
@@ -177,6 +231,7 @@ type RangeAggregator interface {
 type SpanAggregator interface {
 	Observe(Span)
 	Series() SeriesSet
+	Length() int
 }
 
 // CountOverTimeAggregator counts the number of spans. It can also
@@ -209,12 +264,79 @@ func (c *CountOverTimeAggregator) Sample() float64 {
 	return c.count * c.rateMult
 }
 
+// OverTimeAggregator aggregates a numeric attribute of the spans using the given operation,
+// i.e. sum_over_time, min_over_time and max_over_time. Spans where the attribute is missing
+// or not numeric are ignored.
+type OverTimeAggregator struct {
+	getSpanAttValue func(s Span) (float64, bool)
+	agg             func(current, new float64) float64
+	val             float64
+}
+
+var _ VectorAggregator = (*OverTimeAggregator)(nil)
+
+func NewOverTimeAggregator(attr Attribute, op SimpleAggregationOp) *OverTimeAggregator {
+	var fn func(s Span) (float64, bool)
+
+	switch attr {
+	case IntrinsicDurationAttribute:
+		// Optimal implementation for duration attribute
+		fn = func(s Span) (float64, bool) {
+			return float64(s.DurationNanos()) / float64(time.Second), true
+		}
+	default:
+		fn = func(s Span) (float64, bool) {
+			v, ok := s.AttributeFor(attr)
+			if !ok {
+				return 0, false
+			}
+			return numericValue(v)
+		}
+	}
+
+	return &OverTimeAggregator{
+		getSpanAttValue: fn,
+		agg:             op.aggregate,
+		val:             math.NaN(),
+	}
+}
+
+func (o *OverTimeAggregator) Observe(span Span) {
+	v, ok := o.getSpanAttValue(span)
+	if !ok {
+		return
+	}
+	o.val = o.agg(o.val, v)
+}
+
+// Sample returns the aggregated value, or NaN if no span was observed.
+func (o *OverTimeAggregator) Sample() float64 {
+	return o.val
+}
+
+// numericValue returns the value of a static as float. Durations are returned in seconds.
+func numericValue(s Static) (float64, bool) {
+	switch s.Type {
+	case TypeInt:
+		return float64(s.N), true
+	case TypeFloat:
+		return s.F, true
+	case TypeDuration:
+		return s.D.Seconds(), true
+	default:
+		return 0, false
+	}
+}
+
 // StepAggregator sorts spans into time slots using a step interval like 30s or 1m
 type StepAggregator struct {
-	start   uint64
-	end     uint64
-	step    uint64
-	vectors []VectorAggregator
+	start     uint64
+	end       uint64
+	step      uint64
+	offset    uint64 // Optional, shifts the spans of each slot into the past
+	lookback  uint64 // Optional, the slot at t includes the spans in (t-lookback, t] instead of [t, t+step)
+	vectors   []VectorAggregator
+	exemplars *exemplarSampler // Optional
 }
 
 var _ RangeAggregator = (*StepAggregator)(nil)
@@ -235,11 +357,28 @@ func NewStepAggregator(start, end, step uint64, innerAgg func() VectorAggregator
 }
 
 func (s *StepAggregator) Observe(span Span) {
-	interval := IntervalOf(span.StartTimeUnixNanos(), s.start, s.end, s.step)
-	if interval == -1 {
-		return
+	ts := span.StartTimeUnixNanos() + s.offset
+
+	if s.lookback == 0 {
+		interval := IntervalOf(ts, s.start, s.end, s.step)
+		if interval == -1 {
+			return
+		}
+		s.vectors[interval].Observe(span)
+	} else {
+		// With a lookback longer than the step a span is included in multiple slots
+		first, last := lookbackIntervals(ts, s.start, s.step, s.lookback, len(s.vectors))
+		if first > last {
+			return
+		}
+		for i := first; i <= last; i++ {
+			s.vectors[i].Observe(span)
+		}
+	}
+
+	if s.exemplars != nil {
+		s.exemplars.observe(span)
 	}
-	s.vectors[interval].Observe(span)
 }
 
 func (s *StepAggregator) Samples() []float64 {
@@ -250,6 +389,19 @@ func (s *StepAggregator) Samples() []float64 {
 	return ss
 }
 
+// Exemplars returns the spans sampled as exemplars, if enabled.
+func (s *StepAggregator) Exemplars() []Exemplar {
+	return s.exemplars.results()
+}
+
+// exemplarsOf returns the exemplars of the range aggregator if it samples them.
+func exemplarsOf(agg RangeAggregator) []Exemplar {
+	if e, ok := agg.(interface{ Exemplars() []Exemplar }); ok {
+		return e.Exemplars()
+	}
+	return nil
+}
+
 const maxGroupBys = 5 // TODO - This isn't ideal but see comment below.
 
 // FastValues is an array of attribute values (static values) that can be used
@@ -424,6 +576,11 @@ func (g *GroupingAggregator[FV]) labelsFor(vals FV) (Labels, string) {
 	return labels, labels.String()
 }
 
+// Length returns the number of series.
+func (g *GroupingAggregator[FV]) Length() int {
+	return len(g.series)
+}
+
 func (g *GroupingAggregator[FV]) Series() SeriesSet {
 	ss := SeriesSet{}
 
@@ -431,8 +588,9 @@ func (g *GroupingAggregator[FV]) Series() SeriesSet {
 		labels, promLabels := g.labelsFor(vals)
 
 		ss[promLabels] = TimeSeries{
-			Labels: labels,
-			Values: agg.Samples(),
+			Labels:    labels,
+			Values:    agg.Samples(),
+			Exemplars: exemplarsOf(agg),
 		}
 	}
 
@@ -451,6 +609,11 @@ func (u *UngroupedAggregator) Observe(span Span) {
 	u.innerAgg.Observe(span)
 }
 
+// Length is always 1 because there is a single series.
+func (u *UngroupedAggregator) Length() int {
+	return 1
+}
+
 // Series output.
 // This is tweaked to match what prometheus does.  For ungrouped metrics we
 // fill in a placeholder metric name with the name of the aggregation.
@@ -459,8 +622,9 @@ func (u *UngroupedAggregator) Series() SeriesSet {
 	l := labels.FromStrings(labels.MetricName, u.name)
 	return SeriesSet{
 		l.String(): {
-			Labels: []Label{{labels.MetricName, NewStaticString(u.name)}},
-			Values: u.innerAgg.Samples(),
+			Labels:    []Label{{labels.MetricName, NewStaticString(u.name)}},
+			Values:    u.innerAgg.Samples(),
+			Exemplars: exemplarsOf(u.innerAgg),
 		},
 	}
 }
@@ -478,6 +642,9 @@ func (e *Engine) CompileMetricsQueryRangeNonRaw(req *tempopb.QueryRangeRequest,
 	if req.Step <= 0 {
 		return nil, fmt.Errorf("step required")
 	}
+	if _, err := ParseExemplarPolicy(req.ExemplarPolicy); err != nil {
+		return nil, err
+	}
 
 	_, _, metricsPipeline, _, err := e.Compile(req.Query)
 	if err != nil {
@@ -512,6 +679,9 @@ func (e *Engine) CompileMetricsQueryRange(req *tempopb.QueryRangeRequest, dedupe
 	if req.Step <= 0 {
 		return nil, fmt.Errorf("step required")
 	}
+	if _, err := ParseExemplarPolicy(req.ExemplarPolicy); err != nil {
+		return nil, err
+	}
 
 	expr, eval, metricsPipeline, storageReq, err := e.Compile(req.Query)
 	if err != nil {
@@ -522,6 +692,10 @@ func (e *Engine) CompileMetricsQueryRange(req *tempopb.QueryRangeRequest, dedupe
 		return nil, fmt.Errorf("not a metrics query")
 	}
 
+	if _, ok := metricsPipeline.(*MetricsCompare); ok && req.Lookback > 0 {
+		return nil, fmt.Errorf("lookback is not supported by compare()")
+	}
+
 	if v, ok := expr.Hints.GetBool(HintDedupe, allowUnsafeQueryHints); ok {
 		dedupeSpans = v
 	}
@@ -534,6 +708,7 @@ func (e *Engine) CompileMetricsQueryRange(req *tempopb.QueryRangeRequest, dedupe
 		metricsPipeline:   metricsPipeline,
 		dedupeSpans:       dedupeSpans,
 		timeOverlapCutoff: timeOverlapCutoff,
+		intervals:         IntervalCount(req.Start, req.End, req.Step),
 	}
 
 	// TraceID (optional)
@@ -555,6 +730,20 @@ func (e *Engine) CompileMetricsQueryRange(req *tempopb.QueryRangeRequest, dedupe
 		}
 	}
 
+	// Exemplars are identified by trace ID and span ID, and valued by duration. Only needed
+	// on matching spans so like dedupe this goes in the second pass.
+	if req.Exemplars > 0 {
+		exemplarAttrs := []Attribute{IntrinsicTraceIDAttribute, IntrinsicSpanIDAttribute, IntrinsicDurationAttribute}
+		if req.ExemplarPolicy == ExemplarPolicyErrors.String() {
+			exemplarAttrs = append(exemplarAttrs, IntrinsicStatusAttribute)
+		}
+		for _, attr := range exemplarAttrs {
+			if !storageReq.HasAttribute(attr) {
+				storageReq.SecondPassConditions = append(storageReq.SecondPassConditions, Condition{Attribute: attr})
+			}
+		}
+	}
+
 	// Span start time (always required)
 	if !storageReq.HasAttribute(IntrinsicSpanStartTimeAttribute) {
 		// Technically we only need the start time of matching spans, so we add it to the second pass.
@@ -576,8 +765,7 @@ func (e *Engine) CompileMetricsQueryRange(req *tempopb.QueryRangeRequest, dedupe
 	// Queries where we can't are like:  {A} >> {B} | rate() because only require
 	// that {B} occurs within our time range but {A} is allowed to occur any time.
 	me.checkTime = true
-	me.start = req.Start
-	me.end = req.End
+	me.start, me.end = SpanTimeRange(req)
 
 	// Setup second pass callback.  It might be optimized away
 	storageReq.SecondPass = func(s *Spanset) ([]*Spanset, error) {
@@ -670,12 +858,60 @@ type MetricsEvalulator struct {
 	timeOverlapCutoff float64
 	storageReq        *FetchSpansRequest
 	metricsPipeline   metricsFirstStageElement
+	intervals         int
+	maxSeries         int
+	maxResponseBytes  int
 	spansTotal        uint64
 	spansDeduped      uint64
 	bytes             uint64
 	mtx               sync.Mutex
 }
 
+// MetricsLimitError is returned by the evaluator when a metrics query exceeds one of its limits.
+type MetricsLimitError struct {
+	Limit  string
+	Max    int
+	Actual int
+}
+
+func (e *MetricsLimitError) Error() string {
+	return fmt.Sprintf("metrics query exceeded the max %s limit (%d > %d). reduce the cardinality of the query or the time range", e.Limit, e.Actual, e.Max)
+}
+
+const (
+	MetricsLimitSeries        = "series"
+	MetricsLimitResponseBytes = "response bytes"
+
+	// bytesPerSample is the approximate size of a sample in the response. It is
+	// used to estimate the response size before the results are built.
+	bytesPerSample = 16
+)
+
+// SetLimits configures the max number of series and the max estimated response size in bytes. Do stops
+// with a *MetricsLimitError as soon as a limit is exceeded. 0 disables a limit.
+func (e *MetricsEvalulator) SetLimits(maxSeries, maxResponseBytes int) {
+	e.maxSeries = maxSeries
+	e.maxResponseBytes = maxResponseBytes
+}
+
+// checkLimits must be called with the lock held.
+func (e *MetricsEvalulator) checkLimits() error {
+	if e.maxSeries <= 0 && e.maxResponseBytes <= 0 {
+		return nil
+	}
+
+	series := e.metricsPipeline.length()
+	if e.maxSeries > 0 && series > e.maxSeries {
+		return &MetricsLimitError{Limit: MetricsLimitSeries, Max: e.maxSeries, Actual: series}
+	}
+
+	if bytes := series * e.intervals * bytesPerSample; e.maxResponseBytes > 0 && bytes > e.maxResponseBytes {
+		return &MetricsLimitError{Limit: MetricsLimitResponseBytes, Max: e.maxResponseBytes, Actual: bytes}
+	}
+
+	return nil
+}
+
 func timeRangeOverlap(reqStart, reqEnd, dataStart, dataEnd uint64) float64 {
 	st := max(reqStart, dataStart)
 	end := min(reqEnd, dataEnd)
@@ -756,8 +992,12 @@ func (e *MetricsEvalulator) Do(ctx context.Context, f SpansetFetcher, fetcherSta
 			e.metricsPipeline.observe(s)
 
 		}
+		err = e.checkLimits()
 		e.mtx.Unlock()
 		ss.Release()
+		if err != nil {
+			return err
+		}
 	}
 
 	e.mtx.Lock()
@@ -847,23 +1087,58 @@ type SeriesAggregator interface {
 	Results() SeriesSet
 }
 
-type SimpleAdditionAggregator struct {
+// SimpleAggregationOp is the operation used to combine values of the same series and interval.
+type SimpleAggregationOp int
+
+const (
+	sumAggregation SimpleAggregationOp = iota
+	minAggregation
+	maxAggregation
+)
+
+// aggregate combines the current value with a new one. NaN means there is no value yet.
+func (op SimpleAggregationOp) aggregate(current, new float64) float64 {
+	if math.IsNaN(current) {
+		return new
+	}
+	if math.IsNaN(new) {
+		return current
+	}
+
+	switch op {
+	case minAggregation:
+		return math.Min(current, new)
+	case maxAggregation:
+		return math.Max(current, new)
+	default:
+		return current + new
+	}
+}
+
+// SimpleAggregator combines series by summing, min- or maxing their values.
+type SimpleAggregator struct {
 	ss               SeriesSet
 	len              int
 	start, end, step uint64
+	op               SimpleAggregationOp
+	exemplars        map[string]*exemplarSampler
+	newExemplars     func() *exemplarSampler
 }
 
-func NewSimpleAdditionCombiner(req *tempopb.QueryRangeRequest) *SimpleAdditionAggregator {
-	return &SimpleAdditionAggregator{
-		ss:    make(SeriesSet),
-		len:   IntervalCount(req.Start, req.End, req.Step),
-		start: req.Start,
-		end:   req.End,
-		step:  req.Step,
+func NewSimpleCombiner(req *tempopb.QueryRangeRequest, op SimpleAggregationOp) *SimpleAggregator {
+	return &SimpleAggregator{
+		ss:           make(SeriesSet),
+		len:          IntervalCount(req.Start, req.End, req.Step),
+		start:        req.Start,
+		end:          req.End,
+		step:         req.Step,
+		op:           op,
+		exemplars:    make(map[string]*exemplarSampler),
+		newExemplars: exemplarSamplerFor(req),
 	}
 }
 
-func (b *SimpleAdditionAggregator) Combine(in []*tempopb.TimeSeries) {
+func (b *SimpleAggregator) Combine(in []*tempopb.TimeSeries) {
 	for _, ts := range in {
 		existing, ok := b.ss[ts.PromLabels]
 		if !ok {
@@ -880,19 +1155,43 @@ func (b *SimpleAdditionAggregator) Combine(in []*tempopb.TimeSeries) {
 				Labels: labels,
 				Values: make([]float64, b.len),
 			}
+			if b.op != sumAggregation {
+				// min/max distinguish between no value and zero
+				for i := range existing.Values {
+					existing.Values[i] = math.NaN()
+				}
+			}
 			b.ss[ts.PromLabels] = existing
 		}
 
 		for _, sample := range ts.Samples {
 			j := IntervalOfMs(sample.TimestampMs, b.start, b.end, b.step)
 			if j >= 0 && j < len(existing.Values) {
-				existing.Values[j] += sample.Value
+				existing.Values[j] = b.op.aggregate(existing.Values[j], sample.Value)
+			}
+		}
+
+		if len(ts.Exemplars) > 0 {
+			e, ok := b.exemplars[ts.PromLabels]
+			if !ok {
+				e = b.newExemplars()
+				b.exemplars[ts.PromLabels] = e
+			}
+			if e != nil {
+				for _, ex := range exemplarsFromProto(ts.Exemplars) {
+					e.observeExemplar(ex)
+				}
 			}
 		}
 	}
 }
 
-func (b *SimpleAdditionAggregator) Results() SeriesSet {
+func (b *SimpleAggregator) Results() SeriesSet {
+	for promLabels, e := range b.exemplars {
+		ts := b.ss[promLabels]
+		ts.Exemplars = e.results()
+		b.ss[promLabels] = ts
+	}
 	return b.ss
 }
 
@@ -920,8 +1219,9 @@ func (h *Histogram) Record(bucket float64, count int) {
 }
 
 type histSeries struct {
-	labels Labels
-	hist   []Histogram
+	labels    Labels
+	hist      []Histogram
+	exemplars *exemplarSampler
 }
 
 type HistogramAggregator struct {
@@ -929,16 +1229,18 @@ type HistogramAggregator struct {
 	qs               []float64
 	len              int
 	start, end, step uint64
+	newExemplars     func() *exemplarSampler
 }
 
 func NewHistogramAggregator(req *tempopb.QueryRangeRequest, qs []float64) *HistogramAggregator {
 	return &HistogramAggregator{
-		qs:    qs,
-		ss:    make(map[string]histSeries),
-		len:   IntervalCount(req.Start, req.End, req.Step),
-		start: req.Start,
-		end:   req.End,
-		step:  req.Step,
+		qs:           qs,
+		ss:           make(map[string]histSeries),
+		len:          IntervalCount(req.Start, req.End, req.Step),
+		start:        req.Start,
+		end:          req.End,
+		step:         req.Step,
+		newExemplars: exemplarSamplerFor(req),
 	}
 }
 
@@ -972,8 +1274,9 @@ func (h *HistogramAggregator) Combine(in []*tempopb.TimeSeries) {
 		existing, ok := h.ss[withoutBucketStr]
 		if !ok {
 			existing = histSeries{
-				labels: withoutBucket,
-				hist:   make([]Histogram, h.len),
+				labels:    withoutBucket,
+				hist:      make([]Histogram, h.len),
+				exemplars: h.newExemplars(),
 			}
 			h.ss[withoutBucketStr] = existing
 		}
@@ -989,6 +1292,13 @@ func (h *HistogramAggregator) Combine(in []*tempopb.TimeSeries) {
 				existing.hist[j].Record(b, int(sample.Value))
 			}
 		}
+
+		// Exemplars of all buckets are shared by the quantiles of the series
+		if existing.exemplars != nil {
+			for _, ex := range exemplarsFromProto(ts.Exemplars) {
+				existing.exemplars.observeExemplar(ex)
+			}
+		}
 	}
 }
 
@@ -1004,8 +1314,9 @@ func (h *HistogramAggregator) Results() SeriesSet {
 			s := labels.String()
 
 			ts := TimeSeries{
-				Labels: labels,
-				Values: make([]float64, len(in.hist)),
+				Labels:    labels,
+				Values:    make([]float64, len(in.hist)),
+				Exemplars: in.exemplars.results(),
 			}
 			for i := range in.hist {
 
@@ -1031,6 +1342,15 @@ func Log2Bucketize(v uint64) float64 {
 	return math.Pow(2, math.Ceil(math.Log2(float64(v))))
 }
 
+// Log2BucketizeFloat rounds a positive float up to the nearest power of 2, which can be a fraction for values below 1.
+func Log2BucketizeFloat(v float64) float64 {
+	if v <= 0 {
+		return -1
+	}
+
+	return math.Pow(2, math.Ceil(math.Log2(v)))
+}
+
 // Log2Quantile returns the quantile given bucket labeled with float ranges and counts. Uses
 // exponential power-of-two interpolation between buckets as needed.
 func Log2Quantile(p float64, buckets []HistogramBucket) float64 {
@@ -1102,6 +1422,6 @@ func Log2Quantile(p float64, buckets []HistogramBucket) float64 {
 }
 
 var (
-	_ SeriesAggregator = (*SimpleAdditionAggregator)(nil)
+	_ SeriesAggregator = (*SimpleAggregator)(nil)
 	_ SeriesAggregator = (*HistogramAggregator)(nil)
 )
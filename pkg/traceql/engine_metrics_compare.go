@@ -30,6 +30,7 @@ var (
 type MetricsCompare struct {
 	f                   *SpansetFilter
 	qstart, qend, qstep uint64
+	qoffset             uint64
 	len                 int
 	start, end          int
 	topN                int
@@ -64,6 +65,7 @@ func (m *MetricsCompare) init(q *tempopb.QueryRangeRequest, mode AggregateMode)
 		m.qstart = q.Start
 		m.qend = q.End
 		m.qstep = q.Step
+		m.qoffset = q.Offset
 		m.len = IntervalCount(q.Start, q.End, q.Step)
 		m.baselines = make(map[Attribute]map[Static][]float64)
 		m.selections = make(map[Attribute]map[Static][]float64)
@@ -71,7 +73,7 @@ func (m *MetricsCompare) init(q *tempopb.QueryRangeRequest, mode AggregateMode)
 		m.selectionTotals = make(map[Attribute][]float64)
 
 	case AggregateModeSum:
-		m.seriesAgg = NewSimpleAdditionCombiner(q)
+		m.seriesAgg = NewSimpleCombiner(q, sumAggregation)
 		return
 
 	case AggregateModeFinal:
@@ -89,7 +91,7 @@ func (m *MetricsCompare) observe(span Span) {
 	//   then again instead of StepAggregator.
 	// TODO - It would be nice to use those abstractions, area for future improvement
 	st := span.StartTimeUnixNanos()
-	i := IntervalOf(st, m.qstart, m.qend, m.qstep)
+	i := IntervalOf(st+m.qoffset, m.qstart, m.qend, m.qstep)
 
 	// Determine if this span is inside the selection
 	isSelection := StaticFalse
@@ -156,6 +158,19 @@ func (m *MetricsCompare) observeSeries(ss []*tempopb.TimeSeries) {
 	m.seriesAgg.Combine(ss)
 }
 
+// length returns the number of attribute values in the baseline and selection, which is
+// roughly the number of series in the results before topN is applied.
+func (m *MetricsCompare) length() int {
+	n := 0
+	for _, vals := range m.baselines {
+		n += len(vals)
+	}
+	for _, vals := range m.selections {
+		n += len(vals)
+	}
+	return n
+}
+
 func (m *MetricsCompare) result() SeriesSet {
 	// In the other modes return these results
 	if m.seriesAgg != nil {
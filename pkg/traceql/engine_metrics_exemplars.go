@@ -0,0 +1,217 @@
+package traceql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	commonv1proto "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+const (
+	exemplarLabelTraceID = "trace:id"
+	exemplarLabelSpanID  = "span:id"
+	exemplarLabelStatus  = "status"
+)
+
+// ExemplarPolicy decides which spans are kept as exemplars when a series has more
+// candidates than the exemplar limit.
+type ExemplarPolicy int
+
+const (
+	// ExemplarPolicyAny keeps the first spans seen. It is the cheapest policy.
+	ExemplarPolicyAny ExemplarPolicy = iota
+	// ExemplarPolicyErrors prefers spans with status=error.
+	ExemplarPolicyErrors
+	// ExemplarPolicySlowest prefers the spans with the longest duration.
+	ExemplarPolicySlowest
+)
+
+func (p ExemplarPolicy) String() string {
+	switch p {
+	case ExemplarPolicyErrors:
+		return "errors"
+	case ExemplarPolicySlowest:
+		return "slowest"
+	default:
+		return "any"
+	}
+}
+
+// ParseExemplarPolicy parses the name of an exemplar policy. An empty string is ExemplarPolicyAny.
+func ParseExemplarPolicy(s string) (ExemplarPolicy, error) {
+	switch s {
+	case "", "any":
+		return ExemplarPolicyAny, nil
+	case "errors":
+		return ExemplarPolicyErrors, nil
+	case "slowest":
+		return ExemplarPolicySlowest, nil
+	default:
+		return 0, fmt.Errorf("unknown exemplar policy %q, must be one of any, errors or slowest", s)
+	}
+}
+
+// Exemplar is a span selected as an example of a series. The value is the span duration in seconds.
+type Exemplar struct {
+	Labels      Labels
+	Value       float64
+	TimestampMs uint64
+}
+
+// exemplarSampler keeps up to max exemplars, replacing the lowest scored exemplar
+// when a span with a higher score is observed.
+type exemplarSampler struct {
+	max       int
+	policy    ExemplarPolicy
+	exemplars []Exemplar
+	scores    []float64
+}
+
+func newExemplarSampler(max int, policy ExemplarPolicy) *exemplarSampler {
+	if max <= 0 {
+		return nil
+	}
+	return &exemplarSampler{
+		max:    max,
+		policy: policy,
+	}
+}
+
+func (e *exemplarSampler) observe(span Span) {
+	score := e.spanScore(span)
+	i := e.slot(score)
+	if i < 0 {
+		return
+	}
+
+	labels := make(Labels, 0, 3)
+	if tid, ok := span.AttributeFor(IntrinsicTraceIDAttribute); ok {
+		labels = append(labels, Label{exemplarLabelTraceID, tid})
+	}
+	labels = append(labels, Label{exemplarLabelSpanID, NewStaticString(util.SpanIDToHexString(span.ID()))})
+	if e.policy == ExemplarPolicyErrors && score > 0 {
+		labels = append(labels, Label{exemplarLabelStatus, NewStaticString(StatusError.String())})
+	}
+
+	e.set(i, score, Exemplar{
+		Labels:      labels,
+		Value:       float64(span.DurationNanos()) / float64(time.Second),
+		TimestampMs: span.StartTimeUnixNanos() / uint64(time.Millisecond),
+	})
+}
+
+// observeExemplar keeps an exemplar received from another sampler, i.e. in the frontend.
+func (e *exemplarSampler) observeExemplar(ex Exemplar) {
+	score := e.exemplarScore(ex)
+	if i := e.slot(score); i >= 0 {
+		e.set(i, score, ex)
+	}
+}
+
+// slot returns where an exemplar with the given score is stored or -1 if it is dropped.
+func (e *exemplarSampler) slot(score float64) int {
+	if len(e.exemplars) < e.max {
+		return len(e.exemplars)
+	}
+
+	lowest := 0
+	for i, s := range e.scores {
+		if s < e.scores[lowest] {
+			lowest = i
+		}
+	}
+	if score <= e.scores[lowest] {
+		return -1
+	}
+	return lowest
+}
+
+func (e *exemplarSampler) set(i int, score float64, ex Exemplar) {
+	if i == len(e.exemplars) {
+		e.exemplars = append(e.exemplars, ex)
+		e.scores = append(e.scores, score)
+		return
+	}
+	e.exemplars[i] = ex
+	e.scores[i] = score
+}
+
+func (e *exemplarSampler) spanScore(span Span) float64 {
+	switch e.policy {
+	case ExemplarPolicyErrors:
+		if v, ok := span.AttributeFor(IntrinsicStatusAttribute); ok && v == NewStaticStatus(StatusError) {
+			return 1
+		}
+	case ExemplarPolicySlowest:
+		return float64(span.DurationNanos()) / float64(time.Second)
+	}
+	return 0
+}
+
+func (e *exemplarSampler) exemplarScore(ex Exemplar) float64 {
+	switch e.policy {
+	case ExemplarPolicyErrors:
+		for _, l := range ex.Labels {
+			if l.Name == exemplarLabelStatus && l.Value == NewStaticString(StatusError.String()) {
+				return 1
+			}
+		}
+	case ExemplarPolicySlowest:
+		return ex.Value
+	}
+	return 0
+}
+
+func (e *exemplarSampler) results() []Exemplar {
+	if e == nil {
+		return nil
+	}
+	return e.exemplars
+}
+
+func exemplarsFromProto(in []tempopb.Exemplar) []Exemplar {
+	out := make([]Exemplar, 0, len(in))
+	for _, ex := range in {
+		out = append(out, Exemplar{
+			Labels:      LabelsFromProto(ex.Labels),
+			Value:       ex.Value,
+			TimestampMs: uint64(ex.TimestampMs),
+		})
+	}
+	return out
+}
+
+func exemplarsToProto(in []Exemplar) []tempopb.Exemplar {
+	if len(in) == 0 {
+		return nil
+	}
+
+	out := make([]tempopb.Exemplar, 0, len(in))
+	for _, ex := range in {
+		labels := make([]commonv1proto.KeyValue, 0, len(ex.Labels))
+		for _, l := range ex.Labels {
+			labels = append(labels, commonv1proto.KeyValue{
+				Key:   l.Name,
+				Value: l.Value.AsAnyValue(),
+			})
+		}
+		out = append(out, tempopb.Exemplar{
+			Labels:      labels,
+			Value:       ex.Value,
+			TimestampMs: int64(ex.TimestampMs),
+		})
+	}
+	return out
+}
+
+// exemplarSamplerFor returns a constructor of samplers for the exemplar settings of the request.
+// The samplers are nil when the request doesn't ask for exemplars.
+func exemplarSamplerFor(req *tempopb.QueryRangeRequest) func() *exemplarSampler {
+	policy, _ := ParseExemplarPolicy(req.ExemplarPolicy) // Checked when compiling the request
+	max := int(req.Exemplars)
+	return func() *exemplarSampler {
+		return newExemplarSampler(max, policy)
+	}
+}
@@ -1,7 +1,10 @@
 package traceql
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"sort"
 	"testing"
 	"time"
 
@@ -138,6 +141,142 @@ func TestTrimToOverlap(t *testing.T) {
 	}
 }
 
+func TestTrimToBlockOverlap(t *testing.T) {
+	tc := []struct {
+		name                       string
+		offset, lookback           time.Duration
+		blockStart, blockEnd       time.Duration
+		expectedStart, expectedEnd time.Duration
+	}{
+		{
+			// Samples at 10s and 30s include the spans in [10s, 30s) and [30s, 50s)
+			name:       "unaligned",
+			blockStart: 25 * time.Second, blockEnd: 35 * time.Second,
+			expectedStart: 10 * time.Second, expectedEnd: 50 * time.Second,
+		},
+		{
+			// The sample at 30s includes the spans in [25s, 45s)
+			name:       "offset",
+			offset:     5 * time.Second,
+			blockStart: 25 * time.Second, blockEnd: 35 * time.Second,
+			expectedStart: 30 * time.Second, expectedEnd: 50 * time.Second,
+		},
+		{
+			// The sample at 50s includes the spans in (30s, 50s]
+			name:       "lookback",
+			lookback:   20 * time.Second,
+			blockStart: 25 * time.Second, blockEnd: 35 * time.Second,
+			expectedStart: 10 * time.Second, expectedEnd: 70 * time.Second,
+		},
+		{
+			name:       "no overlap",
+			blockStart: 80 * time.Second, blockEnd: 90 * time.Second,
+			expectedStart: 80 * time.Second, expectedEnd: 80 * time.Second,
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			req := &tempopb.QueryRangeRequest{
+				Start:    uint64(10 * time.Second),
+				End:      uint64(70 * time.Second),
+				Step:     uint64(20 * time.Second),
+				Offset:   uint64(c.offset),
+				Lookback: uint64(c.lookback),
+			}
+
+			start, end := TrimToBlockOverlap(req, uint64(c.blockStart), uint64(c.blockEnd))
+			require.Equal(t, uint64(c.expectedStart), start)
+			require.Equal(t, uint64(c.expectedEnd), end)
+		})
+	}
+}
+
+func TestSpanTimeRange(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Start: uint64(100 * time.Second),
+		End:   uint64(200 * time.Second),
+		Step:  uint64(10 * time.Second),
+	}
+
+	start, end := SpanTimeRange(req)
+	require.Equal(t, uint64(100*time.Second), start)
+	require.Equal(t, uint64(200*time.Second), end)
+
+	// The samples include the spans in (t-lookback, t], shifted by the offset
+	req.Offset = uint64(10 * time.Second)
+	req.Lookback = uint64(30 * time.Second)
+	start, end = SpanTimeRange(req)
+	require.Equal(t, uint64(60*time.Second)+1, start)
+	require.Equal(t, uint64(190*time.Second)+1, end)
+}
+
+func TestOffsetAndLookback(t *testing.T) {
+	in := []Span{
+		newMockSpan(nil).WithStartTime(uint64(1 * time.Second)),
+		newMockSpan(nil).WithStartTime(uint64(2 * time.Second)),
+		newMockSpan(nil).WithStartTime(uint64(3 * time.Second)),
+	}
+
+	tcs := []struct {
+		name             string
+		query            string
+		offset, lookback time.Duration
+		expected         []float64
+	}{
+		{
+			name:     "offset",
+			query:    "{ } | count_over_time()",
+			offset:   time.Second,
+			expected: []float64{0, 1, 1},
+		},
+		{
+			// The rate is per second over the lookback, and each span is included in two samples
+			name:     "lookback",
+			query:    "{ } | rate()",
+			lookback: 2 * time.Second,
+			expected: []float64{0.5, 1, 1},
+		},
+		{
+			name:     "offset and lookback",
+			query:    "{ } | count_over_time()",
+			offset:   time.Second,
+			lookback: 2 * time.Second,
+			expected: []float64{0, 1, 2},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &tempopb.QueryRangeRequest{
+				Start:    uint64(1 * time.Second),
+				End:      uint64(3 * time.Second),
+				Step:     uint64(1 * time.Second),
+				Offset:   uint64(tc.offset),
+				Lookback: uint64(tc.lookback),
+				Query:    tc.query,
+			}
+
+			final := runMetricsLayers(t, req, in)
+			require.Len(t, final, 1)
+			for _, ts := range final {
+				require.Equal(t, tc.expected, ts.Values)
+			}
+		})
+	}
+}
+
+func TestCompileMetricsQueryRangeLookbackCompare(t *testing.T) {
+	_, err := NewEngine().CompileMetricsQueryRange(&tempopb.QueryRangeRequest{
+		Query:    "{} | compare({status=error})",
+		Start:    1,
+		End:      2,
+		Step:     1,
+		Lookback: 1,
+	}, false, 0, false)
+	require.EqualError(t, err, "lookback is not supported by compare()")
+}
+
 func TestTimeRangeOverlap(t *testing.T) {
 	tc := []struct {
 		reqStart, reqEnd, dataStart, dataEnd uint64
@@ -221,11 +360,13 @@ func TestCompileMetricsQueryRange(t *testing.T) {
 
 func TestCompileMetricsQueryRangeFetchSpansRequest(t *testing.T) {
 	tc := map[string]struct {
-		q           string
-		shardID     uint32
-		shardCount  uint32
-		dedupe      bool
-		expectedReq FetchSpansRequest
+		q              string
+		shardID        uint32
+		shardCount     uint32
+		dedupe         bool
+		exemplars      uint32
+		exemplarPolicy string
+		expectedReq    FetchSpansRequest
 	}{
 		"minimal": {
 			q: "{} | rate()",
@@ -254,6 +395,22 @@ func TestCompileMetricsQueryRangeFetchSpansRequest(t *testing.T) {
 				},
 			},
 		},
+		"exemplars": {
+			q:              "{} | rate()",
+			exemplars:      3,
+			exemplarPolicy: "errors",
+			expectedReq: FetchSpansRequest{
+				AllConditions: true,
+				Conditions: []Condition{
+					{Attribute: IntrinsicSpanStartTimeAttribute},
+					// Exemplar attributes are intrinsics and moved to the first pass
+					{Attribute: IntrinsicTraceIDAttribute},
+					{Attribute: IntrinsicSpanIDAttribute},
+					{Attribute: IntrinsicDurationAttribute},
+					{Attribute: IntrinsicStatusAttribute}, // Required by the errors policy
+				},
+			},
+		},
 		"secondPass": {
 			q:          "{duration > 10s} | rate() by (resource.cluster)",
 			shardID:    123,
@@ -320,12 +477,14 @@ func TestCompileMetricsQueryRangeFetchSpansRequest(t *testing.T) {
 	for n, tc := range tc {
 		t.Run(n, func(t *testing.T) {
 			eval, err := NewEngine().CompileMetricsQueryRange(&tempopb.QueryRangeRequest{
-				Query:      tc.q,
-				ShardID:    tc.shardID,
-				ShardCount: tc.shardCount,
-				Start:      1,
-				End:        2,
-				Step:       3,
+				Query:          tc.q,
+				ShardID:        tc.shardID,
+				ShardCount:     tc.shardCount,
+				Start:          1,
+				End:            2,
+				Step:           3,
+				Exemplars:      tc.exemplars,
+				ExemplarPolicy: tc.exemplarPolicy,
 			}, tc.dedupe, 0, false)
 			require.NoError(t, err)
 
@@ -336,6 +495,78 @@ func TestCompileMetricsQueryRangeFetchSpansRequest(t *testing.T) {
 	}
 }
 
+func TestMetricsEvaluatorLimits(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Start: uint64(1 * time.Second),
+		End:   uint64(3 * time.Second),
+		Step:  uint64(1 * time.Second),
+		Query: "{ } | rate() by (span.foo)",
+	}
+
+	// One spanset per series, 3 intervals of 16 bytes per series
+	spansets := func() []*Spanset {
+		var ss []*Spanset
+		for i := 0; i < 5; i++ {
+			ss = append(ss, &Spanset{Spans: []Span{
+				newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanString("foo", fmt.Sprintf("bar%d", i)),
+			}})
+		}
+		return ss
+	}
+
+	tcs := []struct {
+		name             string
+		maxSeries        int
+		maxResponseBytes int
+		expectedErr      *MetricsLimitError
+		expectedRemain   int
+	}{
+		{
+			name: "no limits",
+		},
+		{
+			name:             "within limits",
+			maxSeries:        5,
+			maxResponseBytes: 5 * 3 * 16,
+		},
+		{
+			name:           "max series",
+			maxSeries:      3,
+			expectedErr:    &MetricsLimitError{Limit: MetricsLimitSeries, Max: 3, Actual: 4},
+			expectedRemain: 1,
+		},
+		{
+			name:             "max response bytes",
+			maxResponseBytes: 100,
+			expectedErr:      &MetricsLimitError{Limit: MetricsLimitResponseBytes, Max: 100, Actual: 3 * 3 * 16},
+			expectedRemain:   2,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			eval, err := NewEngine().CompileMetricsQueryRange(req, false, 0, false)
+			require.NoError(t, err)
+			eval.SetLimits(tc.maxSeries, tc.maxResponseBytes)
+
+			iter := &MockSpanSetIterator{results: spansets()}
+			err = eval.Do(context.Background(), &MockSpanSetFetcher{iterator: iter}, 0, 0)
+
+			// the evaluator stops as soon as a limit is exceeded
+			require.Len(t, iter.results, tc.expectedRemain)
+			if tc.expectedErr == nil {
+				require.NoError(t, err)
+				require.Len(t, eval.Results(), 5)
+				return
+			}
+
+			var limitErr *MetricsLimitError
+			require.ErrorAs(t, err, &limitErr)
+			require.Equal(t, tc.expectedErr, limitErr)
+		})
+	}
+}
+
 func TestQuantileOverTime(t *testing.T) {
 	req := &tempopb.QueryRangeRequest{
 		Start: uint64(1 * time.Second),
@@ -562,6 +793,321 @@ func TestHistogramOverTime(t *testing.T) {
 	require.Equal(t, out, final)
 }
 
+func TestHistogramOverTimeNumericAttribute(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Start: uint64(1 * time.Second),
+		End:   uint64(2 * time.Second),
+		Step:  uint64(1 * time.Second),
+		Query: "{ } | histogram_over_time(span.payload_size)",
+	}
+
+	// Ints and floats are mixed on purpose, values that aren't numeric or positive are skipped
+	in := []Span{
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanFloat("payload_size", 0.3),
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanInt("payload_size", 1000),
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanFloat("payload_size", 1000.5),
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanFloat("payload_size", -1),
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanString("payload_size", "large"),
+		newMockSpan(nil).WithStartTime(uint64(2*time.Second)).WithSpanInt("payload_size", 4096),
+	}
+
+	e := NewEngine()
+	layer1, err := e.CompileMetricsQueryRange(req, false, 0, false)
+	require.NoError(t, err)
+
+	for _, s := range in {
+		layer1.metricsPipeline.observe(s)
+	}
+
+	bucket := func(v float64) string {
+		return `{` + internalLabelBucket + `="` + NewStaticFloat(v).EncodeToString(true) + `"}`
+	}
+
+	res := layer1.Results()
+	require.Len(t, res, 3)
+	require.Equal(t, []float64{1, 0}, res[bucket(0.5)].Values)
+	require.Equal(t, []float64{2, 0}, res[bucket(1024)].Values)
+	require.Equal(t, []float64{0, 1}, res[bucket(4096)].Values)
+
+	// The buckets are turned into quantiles like the duration buckets
+	req.Query = "{ } | quantile_over_time(span.payload_size, 0.5, 1)"
+	layer1, err = e.CompileMetricsQueryRange(req, false, 0, false)
+	require.NoError(t, err)
+	layer3, err := e.CompileMetricsQueryRangeNonRaw(req, AggregateModeFinal)
+	require.NoError(t, err)
+
+	for _, s := range in {
+		layer1.metricsPipeline.observe(s)
+	}
+	layer3.ObserveSeries(layer1.Results().ToProto(req))
+
+	final := layer3.Results()
+	require.Equal(t, []float64{percentileHelper(0.5, 0.5, 1024, 1024), percentileHelper(0.5, 4096)}, final[`{p="0.5"}`].Values)
+	require.Equal(t, []float64{1024, 4096}, final[`{p="1"}`].Values)
+}
+
+func TestOverTimeAggregations(t *testing.T) {
+	// Spans of two series, the int and float values are mixed on purpose
+	in := []Span{
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanString("foo", "bar").WithSpanInt("bytes", 10),
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanString("foo", "bar").WithSpanFloat("bytes", 2.5),
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanString("foo", "bar").WithSpanString("bytes", "not a number"),
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanString("foo", "bar"),
+
+		newMockSpan(nil).WithStartTime(uint64(2*time.Second)).WithSpanString("foo", "bar").WithSpanInt("bytes", 7),
+		newMockSpan(nil).WithStartTime(uint64(2*time.Second)).WithSpanString("foo", "baz").WithSpanInt("bytes", -3),
+
+		newMockSpan(nil).WithStartTime(uint64(3*time.Second)).WithSpanString("foo", "baz").WithSpanInt("bytes", 4),
+		newMockSpan(nil).WithStartTime(uint64(3*time.Second)).WithSpanString("foo", "baz").WithSpanInt("bytes", 1),
+	}
+
+	nan := math.NaN()
+
+	tcs := []struct {
+		query    string
+		expected map[string][]float64
+	}{
+		{
+			query: "{ } | sum_over_time(span.bytes) by (span.foo)",
+			expected: map[string][]float64{
+				`{span.foo="bar"}`: {12.5, 7, 0},
+				`{span.foo="baz"}`: {0, -3, 5},
+			},
+		},
+		{
+			query: "{ } | min_over_time(span.bytes) by (span.foo)",
+			expected: map[string][]float64{
+				`{span.foo="bar"}`: {2.5, 7, nan},
+				`{span.foo="baz"}`: {nan, -3, 1},
+			},
+		},
+		{
+			query: "{ } | max_over_time(span.bytes) by (span.foo)",
+			expected: map[string][]float64{
+				`{span.foo="bar"}`: {10, 7, nan},
+				`{span.foo="baz"}`: {nan, -3, 4},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			req := &tempopb.QueryRangeRequest{
+				Start: uint64(1 * time.Second),
+				End:   uint64(3 * time.Second),
+				Step:  uint64(1 * time.Second),
+				Query: tc.query,
+			}
+
+			final := runMetricsLayers(t, req, in)
+
+			require.Len(t, final, len(tc.expected))
+			for promLabels, expected := range tc.expected {
+				actual, ok := final[promLabels]
+				require.True(t, ok, promLabels)
+				require.Len(t, actual.Values, len(expected))
+				for i := range expected {
+					if math.IsNaN(expected[i]) {
+						require.True(t, math.IsNaN(actual.Values[i]), "%s[%d] = %v", promLabels, i, actual.Values[i])
+						continue
+					}
+					require.Equal(t, expected[i], actual.Values[i], "%s[%d]", promLabels, i)
+				}
+			}
+		})
+	}
+}
+
+func TestOverTimeAggregationsDuration(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Start: uint64(1 * time.Second),
+		End:   uint64(2 * time.Second),
+		Step:  uint64(1 * time.Second),
+		Query: "{ } | max_over_time(duration)",
+	}
+
+	in := []Span{
+		newMockSpan(nil).WithStartTime(uint64(1 * time.Second)).WithDuration(uint64(500 * time.Millisecond)),
+		newMockSpan(nil).WithStartTime(uint64(1 * time.Second)).WithDuration(uint64(2 * time.Second)),
+		newMockSpan(nil).WithStartTime(uint64(2 * time.Second)).WithDuration(uint64(time.Second)),
+	}
+
+	final := runMetricsLayers(t, req, in)
+	require.Equal(t, SeriesSet{
+		`{__name__="max_over_time"}`: TimeSeries{
+			Labels: []Label{{Name: "__name__", Value: NewStaticString("max_over_time")}},
+			Values: []float64{2, 1},
+		},
+	}, final)
+}
+
+func TestOverTimeAggregationsEmptyInterval(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Start: uint64(1 * time.Second),
+		End:   uint64(3 * time.Second),
+		Step:  uint64(1 * time.Second),
+		Query: "{ } | min_over_time(span.bytes)",
+	}
+
+	// No span in the second interval
+	in := []Span{
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanInt("bytes", 3),
+		newMockSpan(nil).WithStartTime(uint64(3*time.Second)).WithSpanInt("bytes", 5),
+	}
+
+	final := runMetricsLayers(t, req, in).ToProto(req)
+	require.Len(t, final, 1)
+	require.Equal(t, []tempopb.Sample{
+		{TimestampMs: 1000, Value: 3},
+		{TimestampMs: 3000, Value: 5},
+	}, final[0].Samples)
+}
+
+func TestExemplars(t *testing.T) {
+	errorStatus := func(s *mockSpan) *mockSpan {
+		s.attributes[IntrinsicStatusAttribute] = NewStaticStatus(StatusError)
+		return s
+	}
+
+	in := []Span{
+		newMockSpan([]byte{1}).WithStartTime(uint64(1*time.Second)).WithSpanString("foo", "bar").WithDuration(uint64(1 * time.Second)),
+		errorStatus(newMockSpan([]byte{2}).WithStartTime(uint64(1*time.Second)).WithSpanString("foo", "bar").WithDuration(uint64(2 * time.Second))),
+		newMockSpan([]byte{3}).WithStartTime(uint64(2*time.Second)).WithSpanString("foo", "bar").WithDuration(uint64(4 * time.Second)),
+		newMockSpan([]byte{4}).WithStartTime(uint64(2*time.Second)).WithSpanString("foo", "bar").WithDuration(uint64(3 * time.Second)),
+		newMockSpan([]byte{5}).WithStartTime(uint64(3*time.Second)).WithSpanString("foo", "baz").WithDuration(uint64(1 * time.Second)),
+	}
+
+	// exemplarIDs returns the span IDs of the exemplars by series
+	exemplarIDs := func(ss SeriesSet) map[string][]string {
+		ids := map[string][]string{}
+		for promLabels, s := range ss {
+			for _, e := range s.Exemplars {
+				for _, l := range e.Labels {
+					if l.Name == exemplarLabelSpanID {
+						ids[promLabels] = append(ids[promLabels], l.Value.S)
+					}
+				}
+			}
+			sort.Strings(ids[promLabels])
+		}
+		return ids
+	}
+
+	tcs := []struct {
+		name     string
+		query    string
+		policy   string
+		expected map[string][]string
+	}{
+		{
+			name:   "any",
+			query:  "{} | rate() by (span.foo)",
+			policy: "",
+			expected: map[string][]string{
+				`{span.foo="bar"}`: {"0000000000000001", "0000000000000002"},
+				`{span.foo="baz"}`: {"0000000000000005"},
+			},
+		},
+		{
+			name:   "errors",
+			query:  "{} | rate() by (span.foo)",
+			policy: "errors",
+			expected: map[string][]string{
+				`{span.foo="bar"}`: {"0000000000000001", "0000000000000002"},
+				`{span.foo="baz"}`: {"0000000000000005"},
+			},
+		},
+		{
+			name:   "slowest",
+			query:  "{} | rate() by (span.foo)",
+			policy: "slowest",
+			expected: map[string][]string{
+				`{span.foo="bar"}`: {"0000000000000003", "0000000000000004"},
+				`{span.foo="baz"}`: {"0000000000000005"},
+			},
+		},
+		{
+			name:   "quantiles share the exemplars of all buckets",
+			query:  "{} | quantile_over_time(duration, .5)",
+			policy: "slowest",
+			expected: map[string][]string{
+				`{p="0.5"}`: {"0000000000000003", "0000000000000004"},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &tempopb.QueryRangeRequest{
+				Start:          uint64(1 * time.Second),
+				End:            uint64(4 * time.Second),
+				Step:           uint64(1 * time.Second),
+				Query:          tc.query,
+				Exemplars:      2,
+				ExemplarPolicy: tc.policy,
+			}
+
+			final := runMetricsLayers(t, req, in)
+			require.Equal(t, tc.expected, exemplarIDs(final))
+
+			for _, s := range final {
+				for _, e := range s.Exemplars {
+					require.Greater(t, e.Value, 0.0)
+					require.NotZero(t, e.TimestampMs)
+				}
+			}
+		})
+	}
+
+	// errors are preferred over the first spans seen
+	req := &tempopb.QueryRangeRequest{
+		Start:          uint64(1 * time.Second),
+		End:            uint64(4 * time.Second),
+		Step:           uint64(1 * time.Second),
+		Query:          "{} | count_over_time()",
+		Exemplars:      1,
+		ExemplarPolicy: "errors",
+	}
+	final := runMetricsLayers(t, req, in)
+	require.Equal(t, map[string][]string{`{__name__="count_over_time"}`: {"0000000000000002"}}, exemplarIDs(final))
+	require.Contains(t, final[`{__name__="count_over_time"}`].Exemplars[0].Labels, Label{exemplarLabelStatus, NewStaticString("error")})
+
+	// no exemplars unless requested
+	req.Exemplars = 0
+	final = runMetricsLayers(t, req, in)
+	require.Empty(t, exemplarIDs(final))
+
+	req.ExemplarPolicy = "fastest"
+	_, err := NewEngine().CompileMetricsQueryRange(req, false, 0, false)
+	require.Error(t, err)
+}
+
+// runMetricsLayers runs the spans through the 3 layers of processing:  query-frontend -> queriers -> generators -> blocks
+func runMetricsLayers(t *testing.T, req *tempopb.QueryRangeRequest, in []Span) SeriesSet {
+	e := NewEngine()
+
+	layer1, err := e.CompileMetricsQueryRange(req, false, 0, false)
+	require.NoError(t, err)
+
+	layer2, err := e.CompileMetricsQueryRangeNonRaw(req, AggregateModeSum)
+	require.NoError(t, err)
+
+	layer3, err := e.CompileMetricsQueryRangeNonRaw(req, AggregateModeFinal)
+	require.NoError(t, err)
+
+	for _, s := range in {
+		layer1.metricsPipeline.observe(s)
+	}
+
+	res := layer1.Results()
+	layer2.metricsPipeline.observeSeries(res.ToProto(req))
+
+	res = layer2.Results()
+	layer3.ObserveSeries(res.ToProto(req))
+
+	return layer3.Results()
+}
+
 func TestSpanDeduper(t *testing.T) {
 	d := NewSpanDeduper2()
 
@@ -204,6 +204,45 @@ func TestEngine_Execute(t *testing.T) {
 	assert.Equal(t, uint64(100_00), response.Metrics.InspectedBytes)
 }
 
+func TestEngine_ExecuteSearchDeadlineExceeded(t *testing.T) {
+	e := NewEngine()
+
+	req := &tempopb.SearchRequest{
+		Query: `{ .foo = "value" }`,
+	}
+	spanSetFetcher := MockSpanSetFetcher{
+		iterator: &MockSpanSetIterator{
+			results: []*Spanset{
+				{
+					TraceID: []byte{1},
+					Spans: []Span{
+						&mockSpan{
+							id: []byte{1},
+							attributes: map[Attribute]Static{
+								NewAttribute("foo"): NewStaticString("value"),
+							},
+						},
+					},
+				},
+			},
+			err: context.DeadlineExceeded,
+		},
+	}
+
+	// results found before the deadline are returned along with the error
+	response, err := e.ExecuteSearch(context.Background(), req, &spanSetFetcher)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.NotNil(t, response)
+	require.Len(t, response.Traces, 1)
+	assert.Equal(t, uint64(100_00), response.Metrics.InspectedBytes)
+
+	// any other error fails the search
+	spanSetFetcher.iterator = &MockSpanSetIterator{err: errors.New("boom")}
+	response, err = e.ExecuteSearch(context.Background(), req, &spanSetFetcher)
+	require.EqualError(t, err, "boom")
+	require.Nil(t, response)
+}
+
 func TestEngine_asTraceSearchMetadata(t *testing.T) {
 	now := time.Now()
 
@@ -426,12 +465,13 @@ func (m *MockSpanSetFetcher) Fetch(_ context.Context, request FetchSpansRequest)
 type MockSpanSetIterator struct {
 	results []*Spanset
 	filter  SecondPassFn
+	err     error // returned once results are exhausted
 }
 
 func (m *MockSpanSetIterator) Next(context.Context) (*Spanset, error) {
 	for {
 		if len(m.results) == 0 {
-			return nil, nil
+			return nil, m.err
 		}
 		r := m.results[0]
 		m.results = m.results[1:]
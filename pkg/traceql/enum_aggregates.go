@@ -10,6 +10,8 @@ const (
 	aggregateMin
 	aggregateSum
 	aggregateAvg
+	aggregateStddev
+	aggregatePercentile
 )
 
 func (a AggregateOp) String() string {
@@ -24,6 +26,10 @@ func (a AggregateOp) String() string {
 		return "sum"
 	case aggregateAvg:
 		return "avg"
+	case aggregateStddev:
+		return "stddev"
+	case aggregatePercentile:
+		return "percentile"
 	}
 
 	return fmt.Sprintf("aggregate(%d)", a)
@@ -56,6 +62,9 @@ const (
 	metricsAggregateCountOverTime
 	metricsAggregateQuantileOverTime
 	metricsAggregateHistogramOverTime
+	metricsAggregateSumOverTime
+	metricsAggregateMinOverTime
+	metricsAggregateMaxOverTime
 )
 
 func (a MetricsAggregateOp) String() string {
@@ -68,6 +77,12 @@ func (a MetricsAggregateOp) String() string {
 		return "quantile_over_time"
 	case metricsAggregateHistogramOverTime:
 		return "histogram_over_time"
+	case metricsAggregateSumOverTime:
+		return "sum_over_time"
+	case metricsAggregateMinOverTime:
+		return "min_over_time"
+	case metricsAggregateMaxOverTime:
+		return "max_over_time"
 	}
 
 	return fmt.Sprintf("aggregate(%d)", a)
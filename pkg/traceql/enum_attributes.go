@@ -75,6 +75,8 @@ const (
 	IntrinsicEventName
 	IntrinsicLinkSpanID
 	IntrinsicLinkTraceID
+	IntrinsicEventCount
+	IntrinsicLinkCount
 
 	// not yet implemented in traceql but will be
 	IntrinsicParent
@@ -123,6 +125,8 @@ var (
 	IntrinsicNestedSetLeftAttribute    = NewIntrinsic(IntrinsicNestedSetLeft)
 	IntrinsicNestedSetRightAttribute   = NewIntrinsic(IntrinsicNestedSetRight)
 	IntrinsicNestedSetParentAttribute  = NewIntrinsic(IntrinsicNestedSetParent)
+	IntrinsicEventCountAttribute       = NewIntrinsic(IntrinsicEventCount)
+	IntrinsicLinkCountAttribute        = NewIntrinsic(IntrinsicLinkCount)
 )
 
 func (i Intrinsic) String() string {
@@ -147,6 +151,10 @@ func (i Intrinsic) String() string {
 		return "link:spanID"
 	case IntrinsicLinkTraceID:
 		return "link:traceID"
+	case IntrinsicEventCount:
+		return "span:eventCount"
+	case IntrinsicLinkCount:
+		return "span:linkCount"
 	case IntrinsicParent:
 		return "parent"
 	case IntrinsicTraceRootService:
@@ -212,6 +220,10 @@ func intrinsicFromString(s string) Intrinsic {
 		return IntrinsicLinkSpanID
 	case "link:traceID":
 		return IntrinsicLinkTraceID
+	case "span:eventCount":
+		return IntrinsicEventCount
+	case "span:linkCount":
+		return IntrinsicLinkCount
 	case "parent":
 		return IntrinsicParent
 	case "rootServiceName":
@@ -89,16 +89,18 @@ import (
 %token <staticInt>      INTEGER
 %token <staticFloat>    FLOAT
 %token <staticDuration> DURATION
+%token <staticFloat>    PERCENTILE
 %token <val>            DOT OPEN_BRACE CLOSE_BRACE OPEN_PARENS CLOSE_PARENS COMMA
                         NIL TRUE FALSE STATUS_ERROR STATUS_OK STATUS_UNSET
                         KIND_UNSPECIFIED KIND_INTERNAL KIND_SERVER KIND_CLIENT KIND_PRODUCER KIND_CONSUMER
                         IDURATION CHILDCOUNT NAME STATUS STATUS_MESSAGE PARENT KIND ROOTNAME ROOTSERVICENAME 
-                        ROOTSERVICE TRACEDURATION NESTEDSETLEFT NESTEDSETRIGHT NESTEDSETPARENT ID TRACE_ID SPAN_ID
+                        ROOTSERVICE TRACEDURATION NESTEDSETLEFT NESTEDSETRIGHT NESTEDSETPARENT ID TRACE_ID SPAN_ID EVENT_COUNT LINK_COUNT
                         PARENT_DOT RESOURCE_DOT SPAN_DOT TRACE_COLON SPAN_COLON EVENT_COLON EVENT_DOT LINK_COLON LINK_DOT
-                        COUNT AVG MAX MIN SUM
+                        COUNT AVG MAX MIN SUM STDDEV
                         BY COALESCE SELECT
                         END_ATTRIBUTE
                         RATE COUNT_OVER_TIME QUANTILE_OVER_TIME HISTOGRAM_OVER_TIME COMPARE
+                        SUM_OVER_TIME MIN_OVER_TIME MAX_OVER_TIME
                         WITH
 
 // Operators are listed with increasing precedence.
@@ -119,6 +121,10 @@ root:
   | spansetPipelineExpression                   { yylex.(*lexer).expr = newRootExpr($1) }
   | scalarPipelineExpressionFilter              { yylex.(*lexer).expr = newRootExpr($1) } 
   | spansetPipeline PIPE metricsAggregation     { yylex.(*lexer).expr = newRootExprWithMetrics($1, $3) }
+  | bindings spansetPipeline                             { yylex.(*lexer).expr = newRootExpr($2) }
+  | bindings spansetPipelineExpression                   { yylex.(*lexer).expr = newRootExpr($2) }
+  | bindings scalarPipelineExpressionFilter              { yylex.(*lexer).expr = newRootExpr($2) }
+  | bindings spansetPipeline PIPE metricsAggregation     { yylex.(*lexer).expr = newRootExprWithMetrics($2, $4) }
   | root hints                                  { yylex.(*lexer).expr.withHints($2) }
   ;
 
@@ -278,6 +284,7 @@ scalarExpression: // shares the same operators as scalarPipelineExpression. spli
   | SUB INTEGER                                { $$ = NewStaticInt(-$2)             }
   | SUB FLOAT                                  { $$ = NewStaticFloat(-$2)           }
   | SUB DURATION                               { $$ = NewStaticDuration(-$2)        }
+  | IDENTIFIER                                 { $$ = yylex.(*lexer).scalarBinding($1) }
   ;
 
 aggregate:
@@ -286,6 +293,8 @@ aggregate:
   | MIN OPEN_PARENS fieldExpression CLOSE_PARENS  { $$ = newAggregate(aggregateMin, $3) }
   | AVG OPEN_PARENS fieldExpression CLOSE_PARENS  { $$ = newAggregate(aggregateAvg, $3) }
   | SUM OPEN_PARENS fieldExpression CLOSE_PARENS  { $$ = newAggregate(aggregateSum, $3) }
+  | STDDEV OPEN_PARENS fieldExpression CLOSE_PARENS  { $$ = newAggregate(aggregateStddev, $3) }
+  | PERCENTILE OPEN_PARENS fieldExpression CLOSE_PARENS  { $$ = newAggregatePercentile($1, $3) }
   ;
 
 // **********************
@@ -300,6 +309,12 @@ metricsAggregation:
     | QUANTILE_OVER_TIME OPEN_PARENS attribute COMMA numericList CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS { $$ = newMetricsAggregateQuantileOverTime($3, $5, $9) }
     | HISTOGRAM_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS                                                            { $$ = newMetricsAggregateHistogramOverTime($3, nil) }
     | HISTOGRAM_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS                  { $$ = newMetricsAggregateHistogramOverTime($3, $7) }
+    | SUM_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS                                                                  { $$ = newMetricsAggregateWithAttr(metricsAggregateSumOverTime, $3, nil) }
+    | SUM_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS                        { $$ = newMetricsAggregateWithAttr(metricsAggregateSumOverTime, $3, $7) }
+    | MIN_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS                                                                  { $$ = newMetricsAggregateWithAttr(metricsAggregateMinOverTime, $3, nil) }
+    | MIN_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS                        { $$ = newMetricsAggregateWithAttr(metricsAggregateMinOverTime, $3, $7) }
+    | MAX_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS                                                                  { $$ = newMetricsAggregateWithAttr(metricsAggregateMaxOverTime, $3, nil) }
+    | MAX_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS                        { $$ = newMetricsAggregateWithAttr(metricsAggregateMaxOverTime, $3, $7) }
     | COMPARE OPEN_PARENS spansetFilter CLOSE_PARENS                                                                    { $$ = newMetricsCompare($3, 10, 0, 0)}
     | COMPARE OPEN_PARENS spansetFilter COMMA INTEGER CLOSE_PARENS                                                      { $$ = newMetricsCompare($3, $5, 0, 0)}
     | COMPARE OPEN_PARENS spansetFilter COMMA INTEGER COMMA INTEGER COMMA INTEGER CLOSE_PARENS                          { $$ = newMetricsCompare($3, $5, $7, $9)}
@@ -321,6 +336,22 @@ hintList:
   | hintList COMMA hint { $$ = append($1, $3) }
   ;
 
+// **********************
+// Bindings
+// **********************
+binding:
+    IDENTIFIER EQ fieldExpression { yylex.(*lexer).bind($1, $3) }
+  ;
+
+bindings:
+    WITH OPEN_PARENS bindingList CLOSE_PARENS
+  ;
+
+bindingList:
+    binding
+  | bindingList COMMA binding
+  ;
+
 
 // **********************
 // FieldExpressions
@@ -349,6 +380,7 @@ fieldExpression:
   | intrinsicField                           { $$ = $1 }
   | attributeField                           { $$ = $1 }
   | scopedIntrinsicField                     { $$ = $1 }
+  | IDENTIFIER                               { $$ = yylex.(*lexer).binding($1) }
   ;
 
 // **********************
@@ -404,6 +436,8 @@ scopedIntrinsicField:
   | SPAN_COLON STATUS            { $$ = NewIntrinsic(IntrinsicStatus)              }
   | SPAN_COLON STATUS_MESSAGE    { $$ = NewIntrinsic(IntrinsicStatusMessage)       }
   | SPAN_COLON ID                { $$ = NewIntrinsic(IntrinsicSpanID)              }
+  | SPAN_COLON EVENT_COUNT       { $$ = NewIntrinsic(IntrinsicEventCount)          }
+  | SPAN_COLON LINK_COUNT        { $$ = NewIntrinsic(IntrinsicLinkCount)           }
 // event:
   | EVENT_COLON NAME             { $$ = NewIntrinsic(IntrinsicEventName)           }
 // link:
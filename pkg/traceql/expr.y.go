@@ -60,95 +60,102 @@ const STRING = 57347
 const INTEGER = 57348
 const FLOAT = 57349
 const DURATION = 57350
-const DOT = 57351
-const OPEN_BRACE = 57352
-const CLOSE_BRACE = 57353
-const OPEN_PARENS = 57354
-const CLOSE_PARENS = 57355
-const COMMA = 57356
-const NIL = 57357
-const TRUE = 57358
-const FALSE = 57359
-const STATUS_ERROR = 57360
-const STATUS_OK = 57361
-const STATUS_UNSET = 57362
-const KIND_UNSPECIFIED = 57363
-const KIND_INTERNAL = 57364
-const KIND_SERVER = 57365
-const KIND_CLIENT = 57366
-const KIND_PRODUCER = 57367
-const KIND_CONSUMER = 57368
-const IDURATION = 57369
-const CHILDCOUNT = 57370
-const NAME = 57371
-const STATUS = 57372
-const STATUS_MESSAGE = 57373
-const PARENT = 57374
-const KIND = 57375
-const ROOTNAME = 57376
-const ROOTSERVICENAME = 57377
-const ROOTSERVICE = 57378
-const TRACEDURATION = 57379
-const NESTEDSETLEFT = 57380
-const NESTEDSETRIGHT = 57381
-const NESTEDSETPARENT = 57382
-const ID = 57383
-const TRACE_ID = 57384
-const SPAN_ID = 57385
-const PARENT_DOT = 57386
-const RESOURCE_DOT = 57387
-const SPAN_DOT = 57388
-const TRACE_COLON = 57389
-const SPAN_COLON = 57390
-const EVENT_COLON = 57391
-const EVENT_DOT = 57392
-const LINK_COLON = 57393
-const LINK_DOT = 57394
-const COUNT = 57395
-const AVG = 57396
-const MAX = 57397
-const MIN = 57398
-const SUM = 57399
-const BY = 57400
-const COALESCE = 57401
-const SELECT = 57402
-const END_ATTRIBUTE = 57403
-const RATE = 57404
-const COUNT_OVER_TIME = 57405
-const QUANTILE_OVER_TIME = 57406
-const HISTOGRAM_OVER_TIME = 57407
-const COMPARE = 57408
-const WITH = 57409
-const PIPE = 57410
-const AND = 57411
-const OR = 57412
-const EQ = 57413
-const NEQ = 57414
-const LT = 57415
-const LTE = 57416
-const GT = 57417
-const GTE = 57418
-const NRE = 57419
-const RE = 57420
-const DESC = 57421
-const ANCE = 57422
-const SIBL = 57423
-const NOT_CHILD = 57424
-const NOT_PARENT = 57425
-const NOT_DESC = 57426
-const NOT_ANCE = 57427
-const UNION_CHILD = 57428
-const UNION_PARENT = 57429
-const UNION_DESC = 57430
-const UNION_ANCE = 57431
-const UNION_SIBL = 57432
-const ADD = 57433
-const SUB = 57434
-const NOT = 57435
-const MUL = 57436
-const DIV = 57437
-const MOD = 57438
-const POW = 57439
+const PERCENTILE = 57351
+const DOT = 57352
+const OPEN_BRACE = 57353
+const CLOSE_BRACE = 57354
+const OPEN_PARENS = 57355
+const CLOSE_PARENS = 57356
+const COMMA = 57357
+const NIL = 57358
+const TRUE = 57359
+const FALSE = 57360
+const STATUS_ERROR = 57361
+const STATUS_OK = 57362
+const STATUS_UNSET = 57363
+const KIND_UNSPECIFIED = 57364
+const KIND_INTERNAL = 57365
+const KIND_SERVER = 57366
+const KIND_CLIENT = 57367
+const KIND_PRODUCER = 57368
+const KIND_CONSUMER = 57369
+const IDURATION = 57370
+const CHILDCOUNT = 57371
+const NAME = 57372
+const STATUS = 57373
+const STATUS_MESSAGE = 57374
+const PARENT = 57375
+const KIND = 57376
+const ROOTNAME = 57377
+const ROOTSERVICENAME = 57378
+const ROOTSERVICE = 57379
+const TRACEDURATION = 57380
+const NESTEDSETLEFT = 57381
+const NESTEDSETRIGHT = 57382
+const NESTEDSETPARENT = 57383
+const ID = 57384
+const TRACE_ID = 57385
+const SPAN_ID = 57386
+const EVENT_COUNT = 57387
+const LINK_COUNT = 57388
+const PARENT_DOT = 57389
+const RESOURCE_DOT = 57390
+const SPAN_DOT = 57391
+const TRACE_COLON = 57392
+const SPAN_COLON = 57393
+const EVENT_COLON = 57394
+const EVENT_DOT = 57395
+const LINK_COLON = 57396
+const LINK_DOT = 57397
+const COUNT = 57398
+const AVG = 57399
+const MAX = 57400
+const MIN = 57401
+const SUM = 57402
+const STDDEV = 57403
+const BY = 57404
+const COALESCE = 57405
+const SELECT = 57406
+const END_ATTRIBUTE = 57407
+const RATE = 57408
+const COUNT_OVER_TIME = 57409
+const QUANTILE_OVER_TIME = 57410
+const HISTOGRAM_OVER_TIME = 57411
+const COMPARE = 57412
+const SUM_OVER_TIME = 57413
+const MIN_OVER_TIME = 57414
+const MAX_OVER_TIME = 57415
+const WITH = 57416
+const PIPE = 57417
+const AND = 57418
+const OR = 57419
+const EQ = 57420
+const NEQ = 57421
+const LT = 57422
+const LTE = 57423
+const GT = 57424
+const GTE = 57425
+const NRE = 57426
+const RE = 57427
+const DESC = 57428
+const ANCE = 57429
+const SIBL = 57430
+const NOT_CHILD = 57431
+const NOT_PARENT = 57432
+const NOT_DESC = 57433
+const NOT_ANCE = 57434
+const UNION_CHILD = 57435
+const UNION_PARENT = 57436
+const UNION_DESC = 57437
+const UNION_ANCE = 57438
+const UNION_SIBL = 57439
+const ADD = 57440
+const SUB = 57441
+const NOT = 57442
+const MUL = 57443
+const DIV = 57444
+const MOD = 57445
+const POW = 57446
 
 var yyToknames = [...]string{
 	"$end",
@@ -159,6 +166,7 @@ var yyToknames = [...]string{
 	"INTEGER",
 	"FLOAT",
 	"DURATION",
+	"PERCENTILE",
 	"DOT",
 	"OPEN_BRACE",
 	"CLOSE_BRACE",
@@ -194,6 +202,8 @@ var yyToknames = [...]string{
 	"ID",
 	"TRACE_ID",
 	"SPAN_ID",
+	"EVENT_COUNT",
+	"LINK_COUNT",
 	"PARENT_DOT",
 	"RESOURCE_DOT",
 	"SPAN_DOT",
@@ -208,6 +218,7 @@ var yyToknames = [...]string{
 	"MAX",
 	"MIN",
 	"SUM",
+	"STDDEV",
 	"BY",
 	"COALESCE",
 	"SELECT",
@@ -217,6 +228,9 @@ var yyToknames = [...]string{
 	"QUANTILE_OVER_TIME",
 	"HISTOGRAM_OVER_TIME",
 	"COMPARE",
+	"SUM_OVER_TIME",
+	"MIN_OVER_TIME",
+	"MAX_OVER_TIME",
 	"WITH",
 	"PIPE",
 	"AND",
@@ -249,6 +263,7 @@ var yyToknames = [...]string{
 	"MOD",
 	"POW",
 }
+
 var yyStatenames = [...]string{}
 
 const yyEofCode = 1
@@ -260,295 +275,337 @@ var yyExca = [...]int{
 	-1, 1,
 	1, -1,
 	-2, 0,
-	-1, 288,
-	13, 86,
-	-2, 94,
+	-1, 315,
+	14, 90,
+	-2, 98,
 }
 
 const yyPrivate = 57344
 
-const yyLast = 959
+const yyLast = 1182
 
 var yyAct = [...]int{
-
-	101, 5, 100, 6, 99, 8, 224, 7, 276, 98,
-	12, 67, 18, 13, 286, 2, 243, 90, 225, 94,
-	77, 325, 201, 70, 66, 230, 231, 30, 232, 233,
-	234, 243, 151, 29, 152, 200, 155, 200, 153, 85,
-	86, 335, 87, 88, 89, 90, 232, 233, 234, 243,
-	181, 183, 184, 185, 186, 187, 188, 189, 190, 191,
-	192, 193, 194, 195, 196, 197, 198, 322, 72, 73,
-	334, 74, 75, 76, 77, 318, 317, 321, 314, 313,
-	312, 207, 87, 88, 89, 90, 74, 75, 76, 77,
-	332, 311, 201, 228, 362, 227, 349, 226, 215, 217,
-	218, 219, 220, 221, 222, 339, 338, 268, 269, 267,
-	367, 223, 252, 370, 293, 246, 247, 248, 340, 366,
-	293, 17, 371, 244, 245, 235, 236, 237, 238, 239,
-	240, 242, 241, 244, 245, 235, 236, 237, 238, 239,
-	240, 242, 241, 68, 11, 230, 231, 341, 232, 233,
-	234, 243, 331, 253, 254, 230, 231, 327, 232, 233,
-	234, 243, 326, 283, 270, 271, 272, 273, 274, 359,
-	293, 358, 293, 356, 357, 284, 235, 236, 237, 238,
-	239, 240, 242, 241, 283, 19, 20, 21, 17, 17,
-	182, 161, 277, 354, 353, 204, 230, 231, 365, 232,
-	233, 234, 243, 151, 355, 152, 345, 155, 344, 153,
-	342, 343, 323, 324, 288, 206, 209, 210, 211, 212,
-	213, 214, 285, 290, 282, 19, 20, 21, 281, 17,
-	284, 161, 23, 26, 24, 25, 27, 14, 162, 15,
-	205, 156, 157, 158, 159, 160, 369, 205, 292, 293,
-	294, 295, 296, 297, 298, 299, 300, 301, 302, 303,
-	304, 305, 306, 307, 308, 309, 280, 279, 278, 208,
-	164, 22, 23, 26, 24, 25, 27, 14, 162, 15,
-	149, 228, 228, 227, 227, 226, 226, 148, 257, 67,
-	147, 67, 146, 330, 228, 258, 227, 259, 226, 328,
-	329, 70, 260, 70, 290, 78, 79, 80, 81, 82,
-	83, 22, 333, 145, 144, 92, 91, 364, 85, 86,
-	84, 87, 88, 89, 90, 85, 86, 350, 87, 88,
-	89, 90, 71, 336, 151, 337, 152, 316, 155, 28,
-	153, 141, 142, 143, 315, 228, 228, 227, 227, 226,
-	226, 351, 352, 361, 360, 256, 228, 255, 227, 251,
-	226, 275, 363, 348, 347, 250, 228, 249, 227, 346,
-	226, 69, 368, 102, 103, 104, 108, 131, 16, 93,
-	95, 4, 150, 107, 105, 106, 110, 109, 111, 112,
-	113, 114, 115, 116, 117, 118, 119, 120, 121, 122,
-	124, 123, 125, 126, 10, 127, 128, 129, 130, 203,
-	154, 1, 134, 132, 133, 137, 138, 139, 135, 140,
-	136, 320, 0, 102, 103, 104, 108, 131, 0, 0,
-	95, 0, 0, 107, 105, 106, 110, 109, 111, 112,
-	113, 114, 115, 116, 117, 118, 119, 120, 121, 122,
-	124, 123, 125, 126, 0, 127, 128, 129, 130, 319,
-	96, 97, 134, 132, 133, 137, 138, 139, 135, 140,
-	136, 310, 0, 0, 0, 0, 0, 244, 245, 235,
-	236, 237, 238, 239, 240, 242, 241, 72, 73, 0,
-	74, 75, 76, 77, 0, 0, 0, 0, 0, 230,
-	231, 291, 232, 233, 234, 243, 0, 0, 0, 229,
-	96, 97, 0, 0, 0, 244, 245, 235, 236, 237,
-	238, 239, 240, 242, 241, 0, 0, 244, 245, 235,
-	236, 237, 238, 239, 240, 242, 241, 230, 231, 0,
-	232, 233, 234, 243, 0, 0, 0, 0, 0, 230,
-	231, 0, 232, 233, 234, 243, 0, 244, 245, 235,
-	236, 237, 238, 239, 240, 242, 241, 244, 245, 235,
-	236, 237, 238, 239, 240, 242, 241, 202, 0, 230,
-	231, 0, 232, 233, 234, 243, 0, 0, 0, 230,
-	231, 0, 232, 233, 234, 243, 78, 79, 80, 81,
-	82, 83, 199, 78, 79, 80, 81, 82, 83, 0,
-	0, 0, 0, 0, 0, 0, 85, 86, 0, 87,
-	88, 89, 90, 72, 73, 0, 74, 75, 76, 77,
-	0, 0, 0, 48, 53, 0, 0, 50, 0, 49,
-	0, 57, 0, 51, 52, 54, 55, 56, 59, 58,
-	60, 61, 64, 63, 62, 0, 0, 0, 31, 36,
-	0, 0, 33, 0, 32, 0, 42, 0, 34, 35,
-	37, 38, 39, 40, 41, 43, 44, 45, 46, 47,
-	48, 53, 0, 0, 50, 0, 49, 0, 57, 0,
-	51, 52, 54, 55, 56, 59, 58, 60, 61, 64,
-	63, 62, 31, 36, 0, 0, 33, 0, 32, 0,
-	42, 0, 34, 35, 37, 38, 39, 40, 41, 43,
-	44, 45, 46, 47, 19, 20, 21, 0, 17, 0,
-	289, 0, 19, 20, 21, 50, 17, 49, 287, 57,
-	0, 51, 52, 54, 55, 56, 59, 58, 60, 61,
-	64, 63, 62, 33, 0, 32, 0, 42, 0, 34,
-	35, 37, 38, 39, 40, 41, 43, 44, 45, 46,
-	47, 23, 26, 24, 25, 27, 14, 0, 15, 23,
-	26, 24, 25, 27, 14, 0, 15, 19, 20, 21,
-	0, 17, 0, 9, 0, 19, 20, 21, 0, 17,
-	0, 161, 19, 20, 21, 0, 0, 0, 216, 0,
-	22, 261, 0, 262, 264, 265, 0, 263, 22, 0,
-	0, 0, 65, 3, 0, 266, 0, 0, 0, 0,
-	0, 0, 0, 0, 23, 26, 24, 25, 27, 14,
-	0, 15, 23, 26, 24, 25, 27, 0, 0, 23,
-	26, 24, 25, 27, 163, 165, 166, 167, 168, 169,
-	170, 171, 172, 173, 174, 175, 176, 177, 178, 179,
-	180, 131, 0, 22, 0, 0, 0, 0, 0, 0,
-	0, 22, 0, 0, 0, 0, 0, 0, 22, 118,
-	119, 120, 121, 122, 124, 123, 125, 126, 0, 127,
-	128, 129, 130, 0, 0, 0, 134, 132, 133, 137,
-	138, 139, 135, 140, 136, 102, 103, 104, 108, 0,
-	0, 0, 208, 0, 0, 107, 105, 106, 110, 109,
-	111, 112, 113, 114, 115, 116, 117, 102, 103, 104,
-	108, 0, 0, 0, 0, 0, 0, 107, 105, 106,
-	110, 109, 111, 112, 113, 114, 115, 116, 117,
+	110, 7, 9, 8, 107, 109, 6, 299, 232, 103,
+	108, 20, 14, 162, 312, 2, 221, 75, 249, 250,
+	53, 251, 252, 253, 262, 74, 262, 99, 243, 94,
+	95, 244, 96, 97, 98, 99, 85, 164, 167, 165,
+	15, 357, 163, 80, 81, 320, 82, 83, 84, 85,
+	216, 78, 251, 252, 253, 262, 96, 97, 98, 99,
+	216, 217, 196, 197, 199, 200, 201, 202, 203, 204,
+	205, 206, 207, 208, 209, 210, 211, 212, 213, 214,
+	82, 83, 84, 85, 223, 35, 34, 372, 371, 25,
+	348, 21, 22, 23, 32, 347, 19, 344, 176, 343,
+	94, 95, 247, 96, 97, 98, 99, 246, 342, 341,
+	242, 367, 245, 408, 265, 266, 267, 392, 391, 390,
+	389, 217, 376, 25, 354, 21, 22, 23, 32, 375,
+	19, 288, 176, 419, 234, 236, 237, 238, 239, 240,
+	241, 26, 29, 27, 28, 30, 31, 16, 177, 17,
+	377, 168, 169, 170, 171, 175, 172, 173, 174, 289,
+	290, 423, 271, 422, 323, 292, 293, 294, 295, 296,
+	297, 418, 323, 417, 323, 26, 29, 27, 28, 30,
+	31, 416, 323, 309, 24, 381, 263, 264, 254, 255,
+	256, 257, 258, 259, 261, 260, 219, 380, 164, 167,
+	165, 415, 323, 163, 379, 309, 272, 273, 249, 250,
+	314, 251, 252, 253, 262, 405, 323, 310, 24, 164,
+	167, 165, 404, 323, 163, 402, 403, 397, 396, 315,
+	254, 255, 256, 257, 258, 259, 261, 260, 25, 317,
+	21, 22, 23, 32, 378, 19, 366, 176, 19, 359,
+	249, 250, 358, 251, 252, 253, 262, 382, 383, 324,
+	325, 326, 327, 328, 329, 330, 331, 332, 333, 334,
+	335, 336, 337, 338, 339, 291, 310, 355, 356, 220,
+	80, 81, 414, 82, 83, 84, 85, 322, 323, 401,
+	26, 29, 27, 28, 30, 31, 16, 177, 17, 318,
+	319, 19, 400, 198, 247, 247, 247, 247, 247, 246,
+	246, 246, 246, 246, 245, 245, 245, 245, 245, 399,
+	75, 365, 398, 75, 247, 385, 384, 421, 368, 246,
+	369, 317, 311, 24, 245, 360, 361, 362, 363, 364,
+	308, 307, 280, 231, 281, 283, 284, 306, 282, 305,
+	304, 303, 302, 301, 78, 370, 285, 78, 276, 286,
+	287, 224, 374, 179, 373, 277, 161, 278, 160, 164,
+	167, 165, 279, 159, 163, 158, 157, 156, 155, 154,
+	101, 100, 92, 413, 93, 247, 247, 151, 152, 153,
+	246, 246, 407, 406, 393, 245, 245, 79, 300, 247,
+	247, 247, 247, 233, 246, 246, 246, 246, 346, 245,
+	245, 245, 245, 394, 395, 247, 388, 387, 4, 345,
+	246, 275, 274, 270, 55, 245, 269, 409, 410, 411,
+	412, 268, 111, 112, 113, 114, 118, 5, 141, 33,
+	102, 104, 298, 420, 117, 115, 116, 120, 119, 121,
+	122, 123, 124, 125, 126, 127, 128, 129, 130, 131,
+	132, 134, 133, 135, 136, 386, 137, 138, 139, 140,
+	77, 18, 11, 166, 221, 144, 142, 143, 147, 148,
+	149, 145, 150, 146, 353, 1, 111, 112, 113, 114,
+	118, 0, 141, 0, 0, 104, 0, 0, 117, 115,
+	116, 120, 119, 121, 122, 123, 124, 125, 126, 127,
+	128, 129, 130, 131, 132, 134, 133, 135, 136, 0,
+	137, 138, 139, 140, 352, 0, 0, 105, 106, 144,
+	142, 143, 147, 148, 149, 145, 150, 146, 86, 87,
+	88, 89, 90, 91, 0, 0, 263, 264, 254, 255,
+	256, 257, 258, 259, 261, 260, 351, 0, 94, 95,
+	0, 96, 97, 98, 99, 0, 350, 0, 249, 250,
+	0, 251, 252, 253, 262, 0, 0, 0, 0, 0,
+	0, 105, 106, 0, 0, 0, 263, 264, 254, 255,
+	256, 257, 258, 259, 261, 260, 349, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 340, 0, 249, 250,
+	0, 251, 252, 253, 262, 0, 0, 0, 263, 264,
+	254, 255, 256, 257, 258, 259, 261, 260, 263, 264,
+	254, 255, 256, 257, 258, 259, 261, 260, 321, 0,
+	249, 250, 0, 251, 252, 253, 262, 248, 0, 0,
+	249, 250, 0, 251, 252, 253, 262, 0, 263, 264,
+	254, 255, 256, 257, 258, 259, 261, 260, 263, 264,
+	254, 255, 256, 257, 258, 259, 261, 260, 0, 0,
+	249, 250, 0, 251, 252, 253, 262, 0, 0, 0,
+	249, 250, 0, 251, 252, 253, 262, 0, 0, 0,
+	263, 264, 254, 255, 256, 257, 258, 259, 261, 260,
+	0, 263, 264, 254, 255, 256, 257, 258, 259, 261,
+	260, 0, 249, 250, 0, 251, 252, 253, 262, 0,
+	0, 0, 0, 249, 250, 0, 251, 252, 253, 262,
+	263, 264, 254, 255, 256, 257, 258, 259, 261, 260,
+	86, 87, 88, 89, 90, 91, 0, 0, 0, 0,
+	0, 0, 249, 250, 0, 251, 252, 253, 262, 0,
+	94, 95, 0, 96, 97, 98, 99, 86, 87, 88,
+	89, 90, 91, 25, 0, 21, 22, 23, 32, 0,
+	19, 0, 10, 0, 0, 0, 0, 80, 81, 0,
+	82, 83, 84, 85, 25, 0, 21, 22, 23, 32,
+	0, 19, 0, 316, 25, 0, 21, 22, 23, 32,
+	0, 19, 0, 313, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 26, 29, 27, 28, 30,
+	31, 16, 218, 17, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 13, 0, 0, 26, 29, 27, 28,
+	30, 31, 16, 0, 17, 215, 26, 29, 27, 28,
+	30, 31, 16, 0, 17, 56, 61, 0, 24, 58,
+	0, 57, 0, 65, 0, 59, 60, 62, 63, 64,
+	67, 66, 68, 69, 72, 71, 70, 0, 0, 24,
+	0, 0, 0, 0, 56, 61, 0, 0, 58, 24,
+	57, 0, 65, 0, 59, 60, 62, 63, 64, 67,
+	66, 68, 69, 72, 71, 70, 0, 36, 41, 0,
+	0, 38, 0, 37, 0, 47, 0, 39, 40, 42,
+	43, 44, 45, 46, 48, 49, 50, 51, 52, 25,
+	0, 21, 22, 23, 32, 0, 19, 0, 10, 36,
+	41, 0, 0, 38, 0, 37, 0, 47, 0, 39,
+	40, 42, 43, 44, 45, 46, 48, 49, 50, 51,
+	52, 25, 0, 21, 22, 23, 32, 76, 12, 0,
+	235, 0, 0, 12, 0, 0, 0, 0, 0, 0,
+	0, 26, 29, 27, 28, 30, 31, 16, 58, 17,
+	57, 0, 65, 0, 59, 60, 62, 63, 64, 67,
+	66, 68, 69, 72, 71, 70, 0, 0, 0, 0,
+	0, 0, 0, 26, 29, 27, 28, 30, 31, 38,
+	0, 37, 0, 47, 24, 39, 40, 42, 43, 44,
+	45, 46, 48, 49, 50, 51, 52, 0, 0, 141,
+	0, 0, 0, 0, 0, 0, 0, 222, 225, 226,
+	227, 228, 229, 230, 0, 0, 24, 128, 129, 130,
+	131, 132, 134, 133, 135, 136, 0, 137, 138, 139,
+	140, 0, 0, 0, 0, 0, 144, 142, 143, 147,
+	148, 149, 145, 150, 146, 73, 3, 112, 113, 114,
+	118, 54, 0, 0, 0, 224, 0, 0, 117, 115,
+	116, 120, 119, 121, 122, 123, 124, 125, 126, 127,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 178, 180, 181, 182, 183, 184, 185, 186,
+	187, 188, 189, 190, 191, 192, 193, 194, 195, 112,
+	113, 114, 118, 0, 0, 0, 0, 0, 0, 0,
+	117, 115, 116, 120, 119, 121, 122, 123, 124, 125,
+	126, 127,
 }
-var yyPact = [...]int{
 
-	781, -34, -41, 633, -1000, 611, -1000, -1000, -1000, 781,
-	-1000, 532, -1000, 525, 304, 303, -1000, 368, -1000, -1000,
-	-1000, -1000, 335, 302, 301, 280, 278, 275, -1000, 268,
-	179, 258, 258, 258, 258, 258, 258, 258, 258, 258,
-	258, 258, 258, 258, 258, 258, 258, 258, 178, 178,
-	178, 178, 178, 178, 178, 178, 178, 178, 178, 178,
-	178, 178, 178, 178, 178, 589, 24, 564, 396, 182,
-	234, 910, 257, 257, 257, 257, 257, 257, -1000, -1000,
-	-1000, -1000, -1000, -1000, 796, 796, 796, 796, 796, 796,
-	796, 418, 862, -1000, 498, 418, 418, 418, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+var yyPact = [...]int{
+	779, 12, 10, 883, -1000, 945, 799, -1000, -1000, -1000,
+	945, -1000, 699, 369, -1000, 672, 368, 367, -1000, 428,
+	-1000, -1000, -1000, -1000, 381, -1000, 366, 365, 364, 363,
+	362, 360, 355, -1000, 353, 85, 350, 350, 350, 350,
+	350, 350, 350, 350, 350, 350, 350, 350, 350, 350,
+	350, 350, 350, -13, 883, -1000, 290, 290, 290, 290,
+	290, 290, 290, 290, 290, 290, 290, 290, 290, 290,
+	290, 290, 290, 851, 46, 828, 182, 265, 460, 1102,
+	348, 348, 348, 348, 348, 348, -1000, -1000, -1000, -1000,
+	-1000, -1000, 399, 977, 977, 977, 977, 977, 977, 977,
+	482, 1049, -1000, 635, 482, 482, 482, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, 363, 361, 355, 108, 353, 351, 261, 784, 80,
-	65, -1000, -1000, -1000, 151, 418, 418, 418, 418, 188,
-	-1000, 611, -1000, -1000, -1000, -1000, 256, 255, 254, 216,
-	212, 789, 210, 680, 726, -1000, -1000, -1000, -1000, 680,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, 662, 178, -1000, -1000, -1000, -1000, 662, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, 219, -1000, -1000, -1000, -1000, -23, -1000, 718, -8,
-	-8, -77, -77, -77, -77, -52, 796, -12, -12, -80,
-	-80, -80, -80, 488, 235, -1000, -1000, -1000, -1000, -1000,
-	418, 418, 418, 418, 418, 418, 418, 418, 418, 418,
-	418, 418, 418, 418, 418, 418, 458, -48, -48, 30,
-	19, 18, 17, 340, 333, 15, 14, -1000, -1000, -1000,
+	-1000, 427, 422, 419, 158, 418, 417, 330, 314, 101,
+	116, -1000, -1000, -1000, 261, 482, 482, 482, 482, 482,
+	482, 394, -1000, 799, -1000, -1000, -1000, -1000, 340, 339,
+	338, 337, 336, 334, 328, 327, 119, 319, 959, 810,
+	-1000, -1000, -1000, -1000, 959, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, 85, 928, 290, -1000,
+	-1000, -1000, -1000, 928, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 234, -1000, -1000,
+	-1000, -1000, -55, -1000, 800, -21, -21, -68, -68, -68,
+	-68, 285, -1000, -33, -69, 977, -45, -45, -77, -77,
+	-77, -77, 624, 273, -1000, -1000, -1000, -1000, -1000, 482,
+	482, 482, 482, 482, 482, 482, 482, 482, 482, 482,
+	482, 482, 482, 482, 482, 592, -49, -49, 44, 43,
+	34, 32, 415, 404, 30, 25, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, 446, 408, 64, 54, 199, -1000, -50, 149, 144,
-	862, 862, 111, 564, 227, 139, 22, 726, -1000, 718,
-	-46, -1000, -1000, 862, -48, -48, -81, -81, -81, -66,
-	-66, -66, -66, -66, -66, -66, -66, -81, 105, 105,
-	-1000, -1000, -1000, -1000, -1000, 9, -20, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, 188, 932, 48, 47, 104, 134,
-	197, -1000, 219, -1000, -1000, -1000, -1000, -1000, 196, 194,
-	357, 38, -1000, 321, 862, 862, 180, -1000, -1000, 192,
-	160, 158, 156, 347, 36, 862, -1000, 311, -1000, -1000,
-	-1000, -1000, 186, 106, 96, 862, -1000, 240, 100, 109,
-	-1000, -1000,
+	-1000, -1000, 582, 552, 542, 510, 470, 110, 263, -1000,
+	-37, 238, 235, 1049, 1049, 1049, 1049, 1049, 237, 828,
+	2, 232, 36, 810, -1000, -1000, 800, -14, -1000, 399,
+	482, -1000, -1000, 1049, -49, -49, -78, -78, -78, -80,
+	-80, -80, -80, -80, -80, -80, -80, -78, 152, 152,
+	-1000, -1000, -1000, -1000, -1000, 23, 22, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, 394, 1154, 67, 60,
+	135, 230, 190, 183, 171, 243, -1000, 234, -1000, 664,
+	-1000, -1000, -1000, -1000, -1000, 313, 312, 410, 58, 57,
+	56, 55, -1000, 388, 1049, 1049, 213, -1000, -1000, 309,
+	306, 289, 276, 211, 208, 201, 386, 51, 1049, 1049,
+	1049, 1049, -1000, 377, -1000, -1000, -1000, -1000, 269, 187,
+	167, 159, 157, 118, 1049, -1000, -1000, -1000, -1000, 321,
+	149, 147, -1000, -1000,
 }
-var yyPgo = [...]int{
 
-	0, 411, 7, 410, 5, 6, 1, 822, 404, 14,
-	10, 3, 320, 382, 381, 143, 13, 378, 371, 12,
-	19, 9, 4, 2, 0, 18, 369, 8, 361, 339,
+var yyPgo = [...]int{
+	0, 485, 3, 473, 2, 28, 6, 1105, 472, 14,
+	12, 1, 384, 13, 418, 987, 40, 471, 470, 11,
+	9, 4, 10, 5, 0, 31, 465, 7, 442, 439,
+	437, 8, 343,
 }
-var yyR1 = [...]int{
 
-	0, 1, 1, 1, 1, 1, 7, 7, 7, 7,
+var yyR1 = [...]int{
+	0, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	7, 7, 7, 7, 7, 7, 7, 7, 7, 7,
-	7, 7, 7, 7, 7, 8, 9, 9, 9, 9,
-	9, 9, 9, 9, 9, 2, 3, 4, 25, 25,
-	25, 5, 5, 26, 26, 26, 26, 6, 6, 6,
+	7, 7, 7, 7, 7, 7, 7, 7, 7, 8,
+	9, 9, 9, 9, 9, 9, 9, 9, 9, 2,
+	3, 4, 25, 25, 25, 5, 5, 26, 26, 26,
+	26, 6, 6, 6, 6, 6, 6, 6, 6, 6,
 	6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
-	6, 6, 6, 6, 6, 6, 10, 10, 11, 12,
-	12, 12, 12, 12, 12, 14, 14, 15, 15, 15,
-	15, 15, 15, 15, 15, 17, 18, 16, 16, 16,
-	16, 16, 16, 16, 16, 16, 16, 16, 16, 16,
-	16, 19, 19, 19, 19, 19, 13, 13, 13, 13,
-	13, 13, 13, 13, 13, 13, 13, 27, 29, 28,
-	28, 20, 20, 20, 20, 20, 20, 20, 20, 20,
+	10, 10, 11, 12, 12, 12, 12, 12, 12, 14,
+	14, 15, 15, 15, 15, 15, 15, 15, 15, 17,
+	18, 16, 16, 16, 16, 16, 16, 16, 16, 16,
+	16, 16, 16, 16, 16, 16, 19, 19, 19, 19,
+	19, 19, 19, 13, 13, 13, 13, 13, 13, 13,
+	13, 13, 13, 13, 13, 13, 13, 13, 13, 13,
+	27, 29, 28, 28, 31, 30, 32, 32, 20, 20,
 	20, 20, 20, 20, 20, 20, 20, 20, 20, 20,
-	20, 20, 20, 20, 21, 21, 21, 21, 21, 21,
-	21, 21, 21, 21, 21, 21, 21, 21, 21, 21,
+	20, 20, 20, 20, 20, 20, 20, 20, 20, 20,
+	20, 20, 21, 21, 21, 21, 21, 21, 21, 21,
+	21, 21, 21, 21, 21, 21, 21, 21, 22, 22,
 	22, 22, 22, 22, 22, 22, 22, 22, 22, 22,
-	22, 22, 22, 24, 24, 24, 24, 24, 24, 24,
+	22, 24, 24, 24, 24, 24, 24, 24, 24, 24,
 	24, 24, 24, 24, 24, 24, 23, 23, 23, 23,
 	23, 23, 23, 23,
 }
-var yyR2 = [...]int{
 
-	0, 1, 1, 1, 3, 2, 3, 3, 3, 3,
+var yyR2 = [...]int{
+	0, 1, 1, 1, 3, 2, 2, 2, 4, 2,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 1, 3, 1, 1, 1, 1,
-	3, 3, 3, 3, 3, 4, 3, 4, 1, 1,
-	1, 1, 3, 1, 1, 3, 3, 3, 3, 3,
+	3, 3, 3, 3, 3, 3, 3, 3, 1, 3,
+	1, 1, 1, 1, 3, 3, 3, 3, 3, 4,
+	3, 4, 1, 1, 1, 1, 3, 1, 1, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 1, 2, 3, 3, 1,
-	1, 1, 1, 1, 1, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 1, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 1, 1, 1, 1, 2, 2,
-	2, 3, 4, 4, 4, 4, 3, 7, 3, 7,
-	6, 10, 4, 8, 4, 6, 10, 3, 4, 1,
+	3, 3, 3, 3, 3, 3, 3, 3, 3, 1,
+	2, 3, 3, 1, 1, 1, 1, 1, 1, 3,
+	3, 3, 3, 3, 3, 3, 3, 3, 1, 3,
+	3, 3, 3, 3, 3, 3, 3, 3, 1, 1,
+	1, 1, 2, 2, 2, 1, 3, 4, 4, 4,
+	4, 4, 4, 3, 7, 3, 7, 6, 10, 4,
+	8, 4, 8, 4, 8, 4, 8, 4, 6, 10,
+	3, 4, 1, 3, 3, 4, 1, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 3, 3, 3, 2, 2,
+	3, 3, 3, 3, 3, 2, 2, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 2, 2, 2, 2, 2, 2, 2,
+	1, 2, 2, 2, 2, 2, 2, 2, 2, 2,
 	2, 2, 2, 2, 2, 2, 3, 3, 3, 3,
 	4, 4, 3, 3,
 }
-var yyChk = [...]int{
 
-	-1000, -1, -9, -7, -14, -6, -11, -2, -4, 12,
-	-8, -15, -10, -16, 58, 60, -17, 10, -19, 6,
-	7, 8, 92, 53, 55, 56, 54, 57, -29, 67,
-	68, 69, 75, 73, 79, 80, 70, 81, 82, 83,
-	84, 85, 77, 86, 87, 88, 89, 90, 69, 75,
-	73, 79, 80, 70, 81, 82, 83, 77, 85, 84,
-	86, 87, 90, 89, 88, -7, -9, -6, -15, -18,
-	-16, -12, 91, 92, 94, 95, 96, 97, 71, 72,
-	73, 74, 75, 76, -12, 91, 92, 94, 95, 96,
-	97, 12, 12, 11, -20, 12, 92, 93, -21, -22,
-	-23, -24, 5, 6, 7, 16, 17, 15, 8, 19,
-	18, 20, 21, 22, 23, 24, 25, 26, 27, 28,
-	29, 30, 31, 33, 32, 34, 35, 37, 38, 39,
-	40, 9, 45, 46, 44, 50, 52, 47, 48, 49,
-	51, 6, 7, 8, 12, 12, 12, 12, 12, 12,
-	-13, -6, -11, -2, -3, -4, 62, 63, 64, 65,
-	66, 12, 59, -7, 12, -7, -7, -7, -7, -7,
+var yyChk = [...]int{
+	-1000, -1, -9, -7, -14, -30, -6, -11, -2, -4,
+	13, -8, -15, 74, -10, -16, 62, 64, -17, 11,
+	-19, 6, 7, 8, 99, 4, 56, 58, 59, 57,
+	60, 61, 9, -29, 74, 75, 76, 82, 80, 86,
+	87, 77, 88, 89, 90, 91, 92, 84, 93, 94,
+	95, 96, 97, -9, -7, -14, 76, 82, 80, 86,
+	87, 77, 88, 89, 90, 84, 92, 91, 93, 94,
+	97, 96, 95, -7, -9, -6, -15, -18, -16, -12,
+	98, 99, 101, 102, 103, 104, 78, 79, 80, 81,
+	82, 83, 13, -12, 98, 99, 101, 102, 103, 104,
+	13, 13, 12, -20, 13, 99, 100, -21, -22, -23,
+	-24, 4, 5, 6, 7, 17, 18, 16, 8, 20,
+	19, 21, 22, 23, 24, 25, 26, 27, 28, 29,
+	30, 31, 32, 34, 33, 35, 36, 38, 39, 40,
+	41, 10, 48, 49, 47, 53, 55, 50, 51, 52,
+	54, 6, 7, 8, 13, 13, 13, 13, 13, 13,
+	13, 13, -13, -6, -11, -2, -3, -4, 66, 67,
+	68, 69, 71, 72, 73, 70, 13, 63, -7, 13,
 	-7, -7, -7, -7, -7, -7, -7, -7, -7, -7,
-	-7, -6, 12, -6, -6, -6, -6, -6, -6, -6,
-	-6, -6, -6, -6, -6, -6, -6, -6, -6, 13,
-	13, 68, 13, 13, 13, 13, -15, -21, 12, -15,
-	-15, -15, -15, -15, -15, -16, 12, -16, -16, -16,
-	-16, -16, -16, -20, -5, -25, -22, -23, -24, 11,
-	91, 92, 94, 95, 96, 71, 72, 73, 74, 75,
-	76, 78, 77, 97, 69, 70, -20, -20, -20, 4,
-	4, 4, 4, 45, 46, 4, 4, 27, 34, 36,
-	41, 27, 29, 33, 30, 31, 41, 29, 42, 43,
-	13, -20, -20, -20, -20, -28, -27, 4, 12, 12,
-	12, 12, 12, -6, -16, 12, -9, 12, -19, 12,
-	-9, 13, 13, 14, -20, -20, -20, -20, -20, -20,
+	-7, -7, -7, -7, -7, -7, 75, -6, 13, -6,
+	-6, -6, -6, -6, -6, -6, -6, -6, -6, -6,
+	-6, -6, -6, -6, -6, 14, 14, 75, 14, 14,
+	14, 14, -15, -21, 13, -15, -15, -15, -15, -15,
+	-15, -32, -31, 4, -16, 13, -16, -16, -16, -16,
+	-16, -16, -20, -5, -25, -22, -23, -24, 12, 98,
+	99, 101, 102, 103, 78, 79, 80, 81, 82, 83,
+	85, 84, 104, 76, 77, -20, -20, -20, 4, 4,
+	4, 4, 48, 49, 4, 4, 28, 35, 37, 42,
+	28, 30, 34, 31, 32, 42, 45, 46, 30, 43,
+	44, 14, -20, -20, -20, -20, -20, -20, -28, -27,
+	4, 13, 13, 13, 13, 13, 13, 13, 13, -6,
+	-16, 13, -9, 13, -13, -19, 13, -9, 14, 15,
+	78, 14, 14, 15, -20, -20, -20, -20, -20, -20,
 	-20, -20, -20, -20, -20, -20, -20, -20, -20, -20,
-	13, 61, 61, 61, 61, 4, 4, 61, 61, 13,
-	13, 13, 13, 13, 14, 71, 13, 13, -25, -25,
-	-10, 13, 68, -25, 61, 61, -27, -21, 58, 58,
-	14, 13, 13, 14, 12, 12, -26, 7, 6, 58,
-	6, -5, -5, 14, 13, 12, 13, 14, 13, 13,
-	7, 6, 58, -5, 6, 12, 13, 14, -5, 6,
-	13, 13,
+	14, 65, 65, 65, 65, 4, 4, 65, 65, 14,
+	14, 14, 14, 14, 14, 14, 15, 78, 14, 14,
+	-25, -25, -25, -25, -25, -10, 14, 75, -31, -20,
+	-25, 65, 65, -27, -21, 62, 62, 15, 14, 14,
+	14, 14, 14, 15, 13, 13, -26, 7, 6, 62,
+	62, 62, 62, 6, -5, -5, 15, 14, 13, 13,
+	13, 13, 14, 15, 14, 14, 7, 6, 62, -5,
+	-5, -5, -5, 6, 13, 14, 14, 14, 14, 15,
+	-5, 6, 14, 14,
 }
-var yyDef = [...]int{
 
-	0, -2, 1, 2, 3, 26, 27, 28, 29, 0,
-	24, 0, 65, 0, 0, 0, 84, 0, 94, 95,
-	96, 97, 0, 0, 0, 0, 0, 0, 5, 0,
+var yyDef = [...]int{
+	0, -2, 1, 2, 3, 0, 30, 31, 32, 33,
+	0, 28, 0, 0, 69, 0, 0, 0, 88, 0,
+	98, 99, 100, 101, 0, 105, 0, 0, 0, 0,
+	0, 0, 0, 9, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 5, 6, 7, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 30, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 73, 74, 75, 76,
+	77, 78, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 70, 0, 0, 0, 0, 157, 158, 159,
+	160, 161, 162, 163, 164, 165, 166, 167, 168, 169,
+	170, 171, 172, 173, 174, 175, 176, 177, 178, 179,
+	180, 181, 182, 183, 184, 185, 186, 187, 188, 189,
+	190, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 102, 103, 104, 0, 0, 0, 0, 0, 0,
+	0, 0, 4, 34, 35, 36, 37, 38, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 11, 0,
+	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
+	22, 23, 24, 25, 26, 27, 0, 52, 0, 53,
+	54, 55, 56, 57, 58, 59, 60, 61, 62, 63,
+	64, 65, 66, 67, 68, 10, 29, 0, 51, 81,
+	89, 91, 79, 80, 0, 82, 83, 84, 85, 86,
+	87, 0, 136, 0, 72, 0, 92, 93, 94, 95,
+	96, 97, 0, 0, 45, 42, 43, 44, 71, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 26, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 69, 70,
-	71, 72, 73, 74, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 66, 0, 0, 0, 0, 140, 141,
-	142, 143, 144, 145, 146, 147, 148, 149, 150, 151,
-	152, 153, 154, 155, 156, 157, 158, 159, 160, 161,
-	162, 163, 164, 165, 166, 167, 168, 169, 170, 171,
-	172, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 98, 99, 100, 0, 0, 0, 0, 0, 0,
-	4, 30, 31, 32, 33, 34, 0, 0, 0, 0,
-	0, 0, 0, 7, 0, 8, 9, 10, 11, 12,
-	13, 14, 15, 16, 17, 18, 19, 20, 21, 22,
-	23, 48, 0, 49, 50, 51, 52, 53, 54, 55,
-	56, 57, 58, 59, 60, 61, 62, 63, 64, 6,
-	25, 0, 47, 77, 85, 87, 75, 76, 0, 78,
-	79, 80, 81, 82, 83, 68, 0, 88, 89, 90,
-	91, 92, 93, 0, 0, 41, 38, 39, 40, 67,
+	0, 0, 0, 0, 0, 0, 155, 156, 0, 0,
+	0, 0, 0, 0, 0, 0, 191, 192, 193, 194,
+	195, 196, 197, 198, 199, 200, 201, 202, 203, 204,
+	205, 106, 0, 0, 0, 0, 0, 0, 0, 132,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 138, 139, 0,
-	0, 0, 0, 0, 0, 0, 0, 173, 174, 175,
-	176, 177, 178, 179, 180, 181, 182, 183, 184, 185,
-	101, 0, 0, 0, 0, 0, 119, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, -2, 0,
-	0, 35, 37, 0, 122, 123, 124, 125, 126, 127,
-	128, 129, 130, 131, 132, 133, 134, 135, 136, 137,
-	121, 186, 187, 188, 189, 0, 0, 192, 193, 102,
-	103, 104, 105, 118, 0, 0, 106, 108, 0, 0,
-	0, 36, 0, 42, 190, 191, 120, 117, 0, 0,
-	0, 112, 114, 0, 0, 0, 0, 43, 44, 0,
-	0, 0, 0, 0, 110, 0, 115, 0, 107, 109,
-	45, 46, 0, 0, 0, 0, 113, 0, 0, 0,
-	111, 116,
+	0, 0, 0, 0, 8, -2, 0, 0, 135, 0,
+	0, 39, 41, 0, 139, 140, 141, 142, 143, 144,
+	145, 146, 147, 148, 149, 150, 151, 152, 153, 154,
+	138, 206, 207, 208, 209, 0, 0, 212, 213, 107,
+	108, 109, 110, 111, 112, 131, 0, 0, 113, 115,
+	0, 0, 0, 0, 0, 0, 40, 0, 137, 134,
+	46, 210, 211, 133, 130, 0, 0, 0, 119, 121,
+	123, 125, 127, 0, 0, 0, 0, 47, 48, 0,
+	0, 0, 0, 0, 0, 0, 0, 117, 0, 0,
+	0, 0, 128, 0, 114, 116, 49, 50, 0, 0,
+	0, 0, 0, 0, 0, 120, 122, 124, 126, 0,
+	0, 0, 118, 129,
 }
-var yyTok1 = [...]int{
 
+var yyTok1 = [...]int{
 	1,
 }
-var yyTok2 = [...]int{
 
+var yyTok2 = [...]int{
 	2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
 	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
 	22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
@@ -558,8 +615,10 @@ var yyTok2 = [...]int{
 	62, 63, 64, 65, 66, 67, 68, 69, 70, 71,
 	72, 73, 74, 75, 76, 77, 78, 79, 80, 81,
 	82, 83, 84, 85, 86, 87, 88, 89, 90, 91,
-	92, 93, 94, 95, 96, 97,
+	92, 93, 94, 95, 96, 97, 98, 99, 100, 101,
+	102, 103, 104,
 }
+
 var yyTok3 = [...]int{
 	0,
 }
@@ -903,1159 +962,1261 @@ yydefault:
 
 	case 1:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:118
+//line pkg/traceql/expr.y:120
 		{
 			yylex.(*lexer).expr = newRootExpr(yyDollar[1].spansetPipeline)
 		}
 	case 2:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:119
+//line pkg/traceql/expr.y:121
 		{
 			yylex.(*lexer).expr = newRootExpr(yyDollar[1].spansetPipelineExpression)
 		}
 	case 3:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:120
+//line pkg/traceql/expr.y:122
 		{
 			yylex.(*lexer).expr = newRootExpr(yyDollar[1].scalarPipelineExpressionFilter)
 		}
 	case 4:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:121
+//line pkg/traceql/expr.y:123
 		{
 			yylex.(*lexer).expr = newRootExprWithMetrics(yyDollar[1].spansetPipeline, yyDollar[3].metricsAggregation)
 		}
 	case 5:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:122
+//line pkg/traceql/expr.y:124
 		{
-			yylex.(*lexer).expr.withHints(yyDollar[2].hints)
+			yylex.(*lexer).expr = newRootExpr(yyDollar[2].spansetPipeline)
 		}
 	case 6:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line pkg/traceql/expr.y:125
+		{
+			yylex.(*lexer).expr = newRootExpr(yyDollar[2].spansetPipelineExpression)
+		}
+	case 7:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line pkg/traceql/expr.y:126
+		{
+			yylex.(*lexer).expr = newRootExpr(yyDollar[2].scalarPipelineExpressionFilter)
+		}
+	case 8:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line pkg/traceql/expr.y:127
+		{
+			yylex.(*lexer).expr = newRootExprWithMetrics(yyDollar[2].spansetPipeline, yyDollar[4].metricsAggregation)
+		}
+	case 9:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line pkg/traceql/expr.y:128
+		{
+			yylex.(*lexer).expr.withHints(yyDollar[2].hints)
+		}
+	case 10:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:129
+//line pkg/traceql/expr.y:135
 		{
 			yyVAL.spansetPipelineExpression = yyDollar[2].spansetPipelineExpression
 		}
-	case 7:
+	case 11:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:130
+//line pkg/traceql/expr.y:136
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetAnd, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 8:
+	case 12:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:131
+//line pkg/traceql/expr.y:137
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetChild, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 9:
+	case 13:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:132
+//line pkg/traceql/expr.y:138
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetParent, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 10:
+	case 14:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:133
+//line pkg/traceql/expr.y:139
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetDescendant, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 11:
+	case 15:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:134
+//line pkg/traceql/expr.y:140
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetAncestor, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 12:
+	case 16:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:135
+//line pkg/traceql/expr.y:141
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnion, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 13:
+	case 17:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:136
+//line pkg/traceql/expr.y:142
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetSibling, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 14:
+	case 18:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:137
+//line pkg/traceql/expr.y:143
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotChild, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 15:
+	case 19:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:138
+//line pkg/traceql/expr.y:144
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotParent, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 16:
+	case 20:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:139
+//line pkg/traceql/expr.y:145
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotDescendant, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 17:
+	case 21:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:140
+//line pkg/traceql/expr.y:146
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotAncestor, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 18:
+	case 22:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:141
+//line pkg/traceql/expr.y:147
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotSibling, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 19:
+	case 23:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:142
+//line pkg/traceql/expr.y:148
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionChild, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 20:
+	case 24:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:143
+//line pkg/traceql/expr.y:149
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionParent, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 21:
+	case 25:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:144
+//line pkg/traceql/expr.y:150
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionDescendant, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 22:
+	case 26:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:145
+//line pkg/traceql/expr.y:151
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionAncestor, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 23:
+	case 27:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:146
+//line pkg/traceql/expr.y:152
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionSibling, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 24:
+	case 28:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:147
+//line pkg/traceql/expr.y:153
 		{
 			yyVAL.spansetPipelineExpression = yyDollar[1].wrappedSpansetPipeline
 		}
-	case 25:
+	case 29:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:151
+//line pkg/traceql/expr.y:157
 		{
 			yyVAL.wrappedSpansetPipeline = yyDollar[2].spansetPipeline
 		}
-	case 26:
+	case 30:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:154
+//line pkg/traceql/expr.y:160
 		{
 			yyVAL.spansetPipeline = newPipeline(yyDollar[1].spansetExpression)
 		}
-	case 27:
+	case 31:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:155
+//line pkg/traceql/expr.y:161
 		{
 			yyVAL.spansetPipeline = newPipeline(yyDollar[1].scalarFilter)
 		}
-	case 28:
+	case 32:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:156
+//line pkg/traceql/expr.y:162
 		{
 			yyVAL.spansetPipeline = newPipeline(yyDollar[1].groupOperation)
 		}
-	case 29:
+	case 33:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:157
+//line pkg/traceql/expr.y:163
 		{
 			yyVAL.spansetPipeline = newPipeline(yyDollar[1].selectOperation)
 		}
-	case 30:
+	case 34:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:158
+//line pkg/traceql/expr.y:164
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].spansetExpression)
 		}
-	case 31:
+	case 35:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:159
+//line pkg/traceql/expr.y:165
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].scalarFilter)
 		}
-	case 32:
+	case 36:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:160
+//line pkg/traceql/expr.y:166
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].groupOperation)
 		}
-	case 33:
+	case 37:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:161
+//line pkg/traceql/expr.y:167
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].coalesceOperation)
 		}
-	case 34:
+	case 38:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:162
+//line pkg/traceql/expr.y:168
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].selectOperation)
 		}
-	case 35:
+	case 39:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:166
+//line pkg/traceql/expr.y:172
 		{
 			yyVAL.groupOperation = newGroupOperation(yyDollar[3].fieldExpression)
 		}
-	case 36:
+	case 40:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:170
+//line pkg/traceql/expr.y:176
 		{
 			yyVAL.coalesceOperation = newCoalesceOperation()
 		}
-	case 37:
+	case 41:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:174
+//line pkg/traceql/expr.y:180
 		{
 			yyVAL.selectOperation = newSelectOperation(yyDollar[3].attributeList)
 		}
-	case 38:
+	case 42:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:178
+//line pkg/traceql/expr.y:184
 		{
 			yyVAL.attribute = yyDollar[1].intrinsicField
 		}
-	case 39:
+	case 43:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:179
+//line pkg/traceql/expr.y:185
 		{
 			yyVAL.attribute = yyDollar[1].attributeField
 		}
-	case 40:
+	case 44:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:180
+//line pkg/traceql/expr.y:186
 		{
 			yyVAL.attribute = yyDollar[1].scopedIntrinsicField
 		}
-	case 41:
+	case 45:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:184
+//line pkg/traceql/expr.y:190
 		{
 			yyVAL.attributeList = []Attribute{yyDollar[1].attribute}
 		}
-	case 42:
+	case 46:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:185
+//line pkg/traceql/expr.y:191
 		{
 			yyVAL.attributeList = append(yyDollar[1].attributeList, yyDollar[3].attribute)
 		}
-	case 43:
+	case 47:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:190
+//line pkg/traceql/expr.y:196
 		{
 			yyVAL.numericList = []float64{yyDollar[1].staticFloat}
 		}
-	case 44:
+	case 48:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:191
+//line pkg/traceql/expr.y:197
 		{
 			yyVAL.numericList = []float64{float64(yyDollar[1].staticInt)}
 		}
-	case 45:
+	case 49:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:192
+//line pkg/traceql/expr.y:198
 		{
 			yyVAL.numericList = append(yyDollar[1].numericList, yyDollar[3].staticFloat)
 		}
-	case 46:
+	case 50:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:193
+//line pkg/traceql/expr.y:199
 		{
 			yyVAL.numericList = append(yyDollar[1].numericList, float64(yyDollar[3].staticInt))
 		}
-	case 47:
+	case 51:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:197
+//line pkg/traceql/expr.y:203
 		{
 			yyVAL.spansetExpression = yyDollar[2].spansetExpression
 		}
-	case 48:
+	case 52:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:198
+//line pkg/traceql/expr.y:204
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetAnd, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 49:
+	case 53:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:199
+//line pkg/traceql/expr.y:205
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetChild, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 50:
+	case 54:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:200
+//line pkg/traceql/expr.y:206
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetParent, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 51:
+	case 55:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:201
+//line pkg/traceql/expr.y:207
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetDescendant, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 52:
+	case 56:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:202
+//line pkg/traceql/expr.y:208
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetAncestor, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 53:
+	case 57:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:203
+//line pkg/traceql/expr.y:209
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnion, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 54:
+	case 58:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:204
+//line pkg/traceql/expr.y:210
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetSibling, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 55:
+	case 59:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:206
+//line pkg/traceql/expr.y:212
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotChild, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 56:
+	case 60:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:207
+//line pkg/traceql/expr.y:213
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotParent, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 57:
+	case 61:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:208
+//line pkg/traceql/expr.y:214
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotSibling, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 58:
+	case 62:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:209
+//line pkg/traceql/expr.y:215
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotAncestor, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 59:
+	case 63:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:210
+//line pkg/traceql/expr.y:216
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotDescendant, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 60:
+	case 64:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:212
+//line pkg/traceql/expr.y:218
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnionChild, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 61:
+	case 65:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:213
+//line pkg/traceql/expr.y:219
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnionParent, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 62:
+	case 66:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:214
+//line pkg/traceql/expr.y:220
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnionSibling, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 63:
+	case 67:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:215
+//line pkg/traceql/expr.y:221
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnionAncestor, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 64:
+	case 68:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:216
+//line pkg/traceql/expr.y:222
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnionDescendant, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 65:
+	case 69:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:218
+//line pkg/traceql/expr.y:224
 		{
 			yyVAL.spansetExpression = yyDollar[1].spansetFilter
 		}
-	case 66:
+	case 70:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:222
+//line pkg/traceql/expr.y:228
 		{
 			yyVAL.spansetFilter = newSpansetFilter(NewStaticBool(true))
 		}
-	case 67:
+	case 71:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:223
+//line pkg/traceql/expr.y:229
 		{
 			yyVAL.spansetFilter = newSpansetFilter(yyDollar[2].fieldExpression)
 		}
-	case 68:
+	case 72:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:227
+//line pkg/traceql/expr.y:233
 		{
 			yyVAL.scalarFilter = newScalarFilter(yyDollar[2].scalarFilterOperation, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 69:
+	case 73:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:231
+//line pkg/traceql/expr.y:237
 		{
 			yyVAL.scalarFilterOperation = OpEqual
 		}
-	case 70:
+	case 74:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:232
+//line pkg/traceql/expr.y:238
 		{
 			yyVAL.scalarFilterOperation = OpNotEqual
 		}
-	case 71:
+	case 75:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:233
+//line pkg/traceql/expr.y:239
 		{
 			yyVAL.scalarFilterOperation = OpLess
 		}
-	case 72:
+	case 76:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:234
+//line pkg/traceql/expr.y:240
 		{
 			yyVAL.scalarFilterOperation = OpLessEqual
 		}
-	case 73:
+	case 77:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:235
+//line pkg/traceql/expr.y:241
 		{
 			yyVAL.scalarFilterOperation = OpGreater
 		}
-	case 74:
+	case 78:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:236
+//line pkg/traceql/expr.y:242
 		{
 			yyVAL.scalarFilterOperation = OpGreaterEqual
 		}
-	case 75:
+	case 79:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:243
+//line pkg/traceql/expr.y:249
 		{
 			yyVAL.scalarPipelineExpressionFilter = newScalarFilter(yyDollar[2].scalarFilterOperation, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 76:
+	case 80:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:244
+//line pkg/traceql/expr.y:250
 		{
 			yyVAL.scalarPipelineExpressionFilter = newScalarFilter(yyDollar[2].scalarFilterOperation, yyDollar[1].scalarPipelineExpression, yyDollar[3].static)
 		}
-	case 77:
+	case 81:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:248
+//line pkg/traceql/expr.y:254
 		{
 			yyVAL.scalarPipelineExpression = yyDollar[2].scalarPipelineExpression
 		}
-	case 78:
+	case 82:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:249
+//line pkg/traceql/expr.y:255
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpAdd, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 79:
+	case 83:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:250
+//line pkg/traceql/expr.y:256
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpSub, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 80:
+	case 84:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:251
+//line pkg/traceql/expr.y:257
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpMult, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 81:
+	case 85:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:252
+//line pkg/traceql/expr.y:258
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpDiv, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 82:
+	case 86:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:253
+//line pkg/traceql/expr.y:259
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpMod, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 83:
+	case 87:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:254
+//line pkg/traceql/expr.y:260
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpPower, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 84:
+	case 88:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:255
+//line pkg/traceql/expr.y:261
 		{
 			yyVAL.scalarPipelineExpression = yyDollar[1].wrappedScalarPipeline
 		}
-	case 85:
+	case 89:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:259
+//line pkg/traceql/expr.y:265
 		{
 			yyVAL.wrappedScalarPipeline = yyDollar[2].scalarPipeline
 		}
-	case 86:
+	case 90:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:263
+//line pkg/traceql/expr.y:269
 		{
 			yyVAL.scalarPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].aggregate)
 		}
-	case 87:
+	case 91:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:267
+//line pkg/traceql/expr.y:273
 		{
 			yyVAL.scalarExpression = yyDollar[2].scalarExpression
 		}
-	case 88:
+	case 92:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:268
+//line pkg/traceql/expr.y:274
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpAdd, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 89:
+	case 93:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:269
+//line pkg/traceql/expr.y:275
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpSub, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 90:
+	case 94:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:270
+//line pkg/traceql/expr.y:276
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpMult, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 91:
+	case 95:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:271
+//line pkg/traceql/expr.y:277
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpDiv, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 92:
+	case 96:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:272
+//line pkg/traceql/expr.y:278
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpMod, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 93:
+	case 97:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:273
+//line pkg/traceql/expr.y:279
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpPower, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 94:
+	case 98:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:274
+//line pkg/traceql/expr.y:280
 		{
 			yyVAL.scalarExpression = yyDollar[1].aggregate
 		}
-	case 95:
+	case 99:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:275
+//line pkg/traceql/expr.y:281
 		{
 			yyVAL.scalarExpression = NewStaticInt(yyDollar[1].staticInt)
 		}
-	case 96:
+	case 100:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:276
+//line pkg/traceql/expr.y:282
 		{
 			yyVAL.scalarExpression = NewStaticFloat(yyDollar[1].staticFloat)
 		}
-	case 97:
+	case 101:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:277
+//line pkg/traceql/expr.y:283
 		{
 			yyVAL.scalarExpression = NewStaticDuration(yyDollar[1].staticDuration)
 		}
-	case 98:
+	case 102:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:278
+//line pkg/traceql/expr.y:284
 		{
 			yyVAL.scalarExpression = NewStaticInt(-yyDollar[2].staticInt)
 		}
-	case 99:
+	case 103:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:279
+//line pkg/traceql/expr.y:285
 		{
 			yyVAL.scalarExpression = NewStaticFloat(-yyDollar[2].staticFloat)
 		}
-	case 100:
+	case 104:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:280
+//line pkg/traceql/expr.y:286
 		{
 			yyVAL.scalarExpression = NewStaticDuration(-yyDollar[2].staticDuration)
 		}
-	case 101:
+	case 105:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line pkg/traceql/expr.y:287
+		{
+			yyVAL.scalarExpression = yylex.(*lexer).scalarBinding(yyDollar[1].staticStr)
+		}
+	case 106:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:284
+//line pkg/traceql/expr.y:291
 		{
 			yyVAL.aggregate = newAggregate(aggregateCount, nil)
 		}
-	case 102:
+	case 107:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:285
+//line pkg/traceql/expr.y:292
 		{
 			yyVAL.aggregate = newAggregate(aggregateMax, yyDollar[3].fieldExpression)
 		}
-	case 103:
+	case 108:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:286
+//line pkg/traceql/expr.y:293
 		{
 			yyVAL.aggregate = newAggregate(aggregateMin, yyDollar[3].fieldExpression)
 		}
-	case 104:
+	case 109:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:287
+//line pkg/traceql/expr.y:294
 		{
 			yyVAL.aggregate = newAggregate(aggregateAvg, yyDollar[3].fieldExpression)
 		}
-	case 105:
+	case 110:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:288
+//line pkg/traceql/expr.y:295
 		{
 			yyVAL.aggregate = newAggregate(aggregateSum, yyDollar[3].fieldExpression)
 		}
-	case 106:
+	case 111:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line pkg/traceql/expr.y:296
+		{
+			yyVAL.aggregate = newAggregate(aggregateStddev, yyDollar[3].fieldExpression)
+		}
+	case 112:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line pkg/traceql/expr.y:297
+		{
+			yyVAL.aggregate = newAggregatePercentile(yyDollar[1].staticFloat, yyDollar[3].fieldExpression)
+		}
+	case 113:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:295
+//line pkg/traceql/expr.y:304
 		{
 			yyVAL.metricsAggregation = newMetricsAggregate(metricsAggregateRate, nil)
 		}
-	case 107:
+	case 114:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line pkg/traceql/expr.y:296
+//line pkg/traceql/expr.y:305
 		{
 			yyVAL.metricsAggregation = newMetricsAggregate(metricsAggregateRate, yyDollar[6].attributeList)
 		}
-	case 108:
+	case 115:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:297
+//line pkg/traceql/expr.y:306
 		{
 			yyVAL.metricsAggregation = newMetricsAggregate(metricsAggregateCountOverTime, nil)
 		}
-	case 109:
+	case 116:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line pkg/traceql/expr.y:298
+//line pkg/traceql/expr.y:307
 		{
 			yyVAL.metricsAggregation = newMetricsAggregate(metricsAggregateCountOverTime, yyDollar[6].attributeList)
 		}
-	case 110:
+	case 117:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line pkg/traceql/expr.y:299
+//line pkg/traceql/expr.y:308
 		{
 			yyVAL.metricsAggregation = newMetricsAggregateQuantileOverTime(yyDollar[3].attribute, yyDollar[5].numericList, nil)
 		}
-	case 111:
+	case 118:
 		yyDollar = yyS[yypt-10 : yypt+1]
-//line pkg/traceql/expr.y:300
+//line pkg/traceql/expr.y:309
 		{
 			yyVAL.metricsAggregation = newMetricsAggregateQuantileOverTime(yyDollar[3].attribute, yyDollar[5].numericList, yyDollar[9].attributeList)
 		}
-	case 112:
+	case 119:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:301
+//line pkg/traceql/expr.y:310
 		{
 			yyVAL.metricsAggregation = newMetricsAggregateHistogramOverTime(yyDollar[3].attribute, nil)
 		}
-	case 113:
+	case 120:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line pkg/traceql/expr.y:302
+//line pkg/traceql/expr.y:311
 		{
 			yyVAL.metricsAggregation = newMetricsAggregateHistogramOverTime(yyDollar[3].attribute, yyDollar[7].attributeList)
 		}
-	case 114:
+	case 121:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:303
+//line pkg/traceql/expr.y:312
+		{
+			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateSumOverTime, yyDollar[3].attribute, nil)
+		}
+	case 122:
+		yyDollar = yyS[yypt-8 : yypt+1]
+//line pkg/traceql/expr.y:313
+		{
+			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateSumOverTime, yyDollar[3].attribute, yyDollar[7].attributeList)
+		}
+	case 123:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line pkg/traceql/expr.y:314
+		{
+			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateMinOverTime, yyDollar[3].attribute, nil)
+		}
+	case 124:
+		yyDollar = yyS[yypt-8 : yypt+1]
+//line pkg/traceql/expr.y:315
+		{
+			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateMinOverTime, yyDollar[3].attribute, yyDollar[7].attributeList)
+		}
+	case 125:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line pkg/traceql/expr.y:316
+		{
+			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateMaxOverTime, yyDollar[3].attribute, nil)
+		}
+	case 126:
+		yyDollar = yyS[yypt-8 : yypt+1]
+//line pkg/traceql/expr.y:317
+		{
+			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateMaxOverTime, yyDollar[3].attribute, yyDollar[7].attributeList)
+		}
+	case 127:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line pkg/traceql/expr.y:318
 		{
 			yyVAL.metricsAggregation = newMetricsCompare(yyDollar[3].spansetFilter, 10, 0, 0)
 		}
-	case 115:
+	case 128:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line pkg/traceql/expr.y:304
+//line pkg/traceql/expr.y:319
 		{
 			yyVAL.metricsAggregation = newMetricsCompare(yyDollar[3].spansetFilter, yyDollar[5].staticInt, 0, 0)
 		}
-	case 116:
+	case 129:
 		yyDollar = yyS[yypt-10 : yypt+1]
-//line pkg/traceql/expr.y:305
+//line pkg/traceql/expr.y:320
 		{
 			yyVAL.metricsAggregation = newMetricsCompare(yyDollar[3].spansetFilter, yyDollar[5].staticInt, yyDollar[7].staticInt, yyDollar[9].staticInt)
 		}
-	case 117:
+	case 130:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:312
+//line pkg/traceql/expr.y:327
 		{
 			yyVAL.hint = newHint(yyDollar[1].staticStr, yyDollar[3].static)
 		}
-	case 118:
+	case 131:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:316
+//line pkg/traceql/expr.y:331
 		{
 			yyVAL.hints = newHints(yyDollar[3].hintList)
 		}
-	case 119:
+	case 132:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:320
+//line pkg/traceql/expr.y:335
 		{
 			yyVAL.hintList = []*Hint{yyDollar[1].hint}
 		}
-	case 120:
+	case 133:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:321
+//line pkg/traceql/expr.y:336
 		{
 			yyVAL.hintList = append(yyDollar[1].hintList, yyDollar[3].hint)
 		}
-	case 121:
+	case 134:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:329
+//line pkg/traceql/expr.y:343
+		{
+			yylex.(*lexer).bind(yyDollar[1].staticStr, yyDollar[3].fieldExpression)
+		}
+	case 138:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line pkg/traceql/expr.y:360
 		{
 			yyVAL.fieldExpression = yyDollar[2].fieldExpression
 		}
-	case 122:
+	case 139:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:330
+//line pkg/traceql/expr.y:361
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpAdd, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 123:
+	case 140:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:331
+//line pkg/traceql/expr.y:362
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpSub, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 124:
+	case 141:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:332
+//line pkg/traceql/expr.y:363
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpMult, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 125:
+	case 142:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:333
+//line pkg/traceql/expr.y:364
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpDiv, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 126:
+	case 143:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:334
+//line pkg/traceql/expr.y:365
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpMod, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 127:
+	case 144:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:335
+//line pkg/traceql/expr.y:366
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 128:
+	case 145:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:336
+//line pkg/traceql/expr.y:367
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpNotEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 129:
+	case 146:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:337
+//line pkg/traceql/expr.y:368
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpLess, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 130:
+	case 147:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:338
+//line pkg/traceql/expr.y:369
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpLessEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 131:
+	case 148:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:339
+//line pkg/traceql/expr.y:370
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpGreater, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 132:
+	case 149:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:340
+//line pkg/traceql/expr.y:371
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpGreaterEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 133:
+	case 150:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:341
+//line pkg/traceql/expr.y:372
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpRegex, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 134:
+	case 151:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:342
+//line pkg/traceql/expr.y:373
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpNotRegex, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 135:
+	case 152:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:343
+//line pkg/traceql/expr.y:374
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpPower, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 136:
+	case 153:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:344
+//line pkg/traceql/expr.y:375
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpAnd, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 137:
+	case 154:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:345
+//line pkg/traceql/expr.y:376
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpOr, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 138:
+	case 155:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:346
+//line pkg/traceql/expr.y:377
 		{
 			yyVAL.fieldExpression = newUnaryOperation(OpSub, yyDollar[2].fieldExpression)
 		}
-	case 139:
+	case 156:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:347
+//line pkg/traceql/expr.y:378
 		{
 			yyVAL.fieldExpression = newUnaryOperation(OpNot, yyDollar[2].fieldExpression)
 		}
-	case 140:
+	case 157:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:348
+//line pkg/traceql/expr.y:379
 		{
 			yyVAL.fieldExpression = yyDollar[1].static
 		}
-	case 141:
+	case 158:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:349
+//line pkg/traceql/expr.y:380
 		{
 			yyVAL.fieldExpression = yyDollar[1].intrinsicField
 		}
-	case 142:
+	case 159:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:350
+//line pkg/traceql/expr.y:381
 		{
 			yyVAL.fieldExpression = yyDollar[1].attributeField
 		}
-	case 143:
+	case 160:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:351
+//line pkg/traceql/expr.y:382
 		{
 			yyVAL.fieldExpression = yyDollar[1].scopedIntrinsicField
 		}
-	case 144:
+	case 161:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:358
+//line pkg/traceql/expr.y:383
+		{
+			yyVAL.fieldExpression = yylex.(*lexer).binding(yyDollar[1].staticStr)
+		}
+	case 162:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line pkg/traceql/expr.y:390
 		{
 			yyVAL.static = NewStaticString(yyDollar[1].staticStr)
 		}
-	case 145:
+	case 163:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:359
+//line pkg/traceql/expr.y:391
 		{
 			yyVAL.static = NewStaticInt(yyDollar[1].staticInt)
 		}
-	case 146:
+	case 164:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:360
+//line pkg/traceql/expr.y:392
 		{
 			yyVAL.static = NewStaticFloat(yyDollar[1].staticFloat)
 		}
-	case 147:
+	case 165:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:361
+//line pkg/traceql/expr.y:393
 		{
 			yyVAL.static = NewStaticBool(true)
 		}
-	case 148:
+	case 166:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:362
+//line pkg/traceql/expr.y:394
 		{
 			yyVAL.static = NewStaticBool(false)
 		}
-	case 149:
+	case 167:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:363
+//line pkg/traceql/expr.y:395
 		{
 			yyVAL.static = NewStaticNil()
 		}
-	case 150:
+	case 168:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:364
+//line pkg/traceql/expr.y:396
 		{
 			yyVAL.static = NewStaticDuration(yyDollar[1].staticDuration)
 		}
-	case 151:
+	case 169:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:365
+//line pkg/traceql/expr.y:397
 		{
 			yyVAL.static = NewStaticStatus(StatusOk)
 		}
-	case 152:
+	case 170:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:366
+//line pkg/traceql/expr.y:398
 		{
 			yyVAL.static = NewStaticStatus(StatusError)
 		}
-	case 153:
+	case 171:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:367
+//line pkg/traceql/expr.y:399
 		{
 			yyVAL.static = NewStaticStatus(StatusUnset)
 		}
-	case 154:
+	case 172:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:368
+//line pkg/traceql/expr.y:400
 		{
 			yyVAL.static = NewStaticKind(KindUnspecified)
 		}
-	case 155:
+	case 173:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:369
+//line pkg/traceql/expr.y:401
 		{
 			yyVAL.static = NewStaticKind(KindInternal)
 		}
-	case 156:
+	case 174:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:370
+//line pkg/traceql/expr.y:402
 		{
 			yyVAL.static = NewStaticKind(KindServer)
 		}
-	case 157:
+	case 175:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:371
+//line pkg/traceql/expr.y:403
 		{
 			yyVAL.static = NewStaticKind(KindClient)
 		}
-	case 158:
+	case 176:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:372
+//line pkg/traceql/expr.y:404
 		{
 			yyVAL.static = NewStaticKind(KindProducer)
 		}
-	case 159:
+	case 177:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:373
+//line pkg/traceql/expr.y:405
 		{
 			yyVAL.static = NewStaticKind(KindConsumer)
 		}
-	case 160:
+	case 178:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:379
+//line pkg/traceql/expr.y:411
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicDuration)
 		}
-	case 161:
+	case 179:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:380
+//line pkg/traceql/expr.y:412
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicChildCount)
 		}
-	case 162:
+	case 180:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:381
+//line pkg/traceql/expr.y:413
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicName)
 		}
-	case 163:
+	case 181:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:382
+//line pkg/traceql/expr.y:414
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicStatus)
 		}
-	case 164:
+	case 182:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:383
+//line pkg/traceql/expr.y:415
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicStatusMessage)
 		}
-	case 165:
+	case 183:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:384
+//line pkg/traceql/expr.y:416
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicKind)
 		}
-	case 166:
+	case 184:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:385
+//line pkg/traceql/expr.y:417
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicParent)
 		}
-	case 167:
+	case 185:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:386
+//line pkg/traceql/expr.y:418
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicTraceRootSpan)
 		}
-	case 168:
+	case 186:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:387
+//line pkg/traceql/expr.y:419
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicTraceRootService)
 		}
-	case 169:
+	case 187:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:388
+//line pkg/traceql/expr.y:420
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicTraceDuration)
 		}
-	case 170:
+	case 188:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:389
+//line pkg/traceql/expr.y:421
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicNestedSetLeft)
 		}
-	case 171:
+	case 189:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:390
+//line pkg/traceql/expr.y:422
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicNestedSetRight)
 		}
-	case 172:
+	case 190:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:391
+//line pkg/traceql/expr.y:423
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicNestedSetParent)
 		}
-	case 173:
+	case 191:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:396
+//line pkg/traceql/expr.y:428
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicTraceDuration)
 		}
-	case 174:
+	case 192:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:397
+//line pkg/traceql/expr.y:429
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicTraceRootSpan)
 		}
-	case 175:
+	case 193:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:398
+//line pkg/traceql/expr.y:430
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicTraceRootService)
 		}
-	case 176:
+	case 194:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:399
+//line pkg/traceql/expr.y:431
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicTraceID)
 		}
-	case 177:
+	case 195:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:401
+//line pkg/traceql/expr.y:433
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicDuration)
 		}
-	case 178:
+	case 196:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:402
+//line pkg/traceql/expr.y:434
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicName)
 		}
-	case 179:
+	case 197:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:403
+//line pkg/traceql/expr.y:435
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicKind)
 		}
-	case 180:
+	case 198:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:404
+//line pkg/traceql/expr.y:436
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicStatus)
 		}
-	case 181:
+	case 199:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:405
+//line pkg/traceql/expr.y:437
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicStatusMessage)
 		}
-	case 182:
+	case 200:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:406
+//line pkg/traceql/expr.y:438
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicSpanID)
 		}
-	case 183:
+	case 201:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line pkg/traceql/expr.y:439
+		{
+			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicEventCount)
+		}
+	case 202:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line pkg/traceql/expr.y:440
+		{
+			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicLinkCount)
+		}
+	case 203:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:408
+//line pkg/traceql/expr.y:442
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicEventName)
 		}
-	case 184:
+	case 204:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:410
+//line pkg/traceql/expr.y:444
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicLinkTraceID)
 		}
-	case 185:
+	case 205:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:411
+//line pkg/traceql/expr.y:445
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicLinkSpanID)
 		}
-	case 186:
+	case 206:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:415
+//line pkg/traceql/expr.y:449
 		{
 			yyVAL.attributeField = NewAttribute(yyDollar[2].staticStr)
 		}
-	case 187:
+	case 207:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:416
+//line pkg/traceql/expr.y:450
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeResource, false, yyDollar[2].staticStr)
 		}
-	case 188:
+	case 208:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:417
+//line pkg/traceql/expr.y:451
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeSpan, false, yyDollar[2].staticStr)
 		}
-	case 189:
+	case 209:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:418
+//line pkg/traceql/expr.y:452
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeNone, true, yyDollar[2].staticStr)
 		}
-	case 190:
+	case 210:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:419
+//line pkg/traceql/expr.y:453
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeResource, true, yyDollar[3].staticStr)
 		}
-	case 191:
+	case 211:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:420
+//line pkg/traceql/expr.y:454
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeSpan, true, yyDollar[3].staticStr)
 		}
-	case 192:
+	case 212:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:421
+//line pkg/traceql/expr.y:455
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeEvent, false, yyDollar[2].staticStr)
 		}
-	case 193:
+	case 213:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:422
+//line pkg/traceql/expr.y:456
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeLink, false, yyDollar[2].staticStr)
 		}
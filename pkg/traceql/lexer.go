@@ -2,6 +2,7 @@ package traceql
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 	"text/scanner"
@@ -78,6 +79,8 @@ var tokens = map[string]int{
 	"id":                  ID,
 	"traceID":             TRACE_ID,
 	"spanID":              SPAN_ID,
+	"eventCount":          EVENT_COUNT,
+	"linkCount":           LINK_COUNT,
 	"parent":              PARENT,
 	"parent.":             PARENT_DOT,
 	"resource.":           RESOURCE_DOT,
@@ -93,6 +96,7 @@ var tokens = map[string]int{
 	"max":                 MAX,
 	"min":                 MIN,
 	"sum":                 SUM,
+	"stddev":              STDDEV,
 	"by":                  BY,
 	"coalesce":            COALESCE,
 	"select":              SELECT,
@@ -100,6 +104,9 @@ var tokens = map[string]int{
 	"count_over_time":     COUNT_OVER_TIME,
 	"quantile_over_time":  QUANTILE_OVER_TIME,
 	"histogram_over_time": HISTOGRAM_OVER_TIME,
+	"sum_over_time":       SUM_OVER_TIME,
+	"min_over_time":       MIN_OVER_TIME,
+	"max_over_time":       MAX_OVER_TIME,
 	"compare":             COMPARE,
 	"with":                WITH,
 }
@@ -112,6 +119,9 @@ type lexer struct {
 
 	parsingAttribute bool
 	currentScope     int
+
+	// bindings declared in a leading with (...) clause
+	bindings map[string]FieldExpression
 }
 
 func (l *lexer) Lex(lval *yySymType) int {
@@ -234,6 +244,14 @@ func (l *lexer) Lex(lval *yySymType) int {
 		return tok
 	}
 
+	// percentile aggregates like p90(duration)
+	if l.Peek() == '(' {
+		if q, ok := parsePercentile(l.TokenText()); ok {
+			lval.staticFloat = q
+			return PERCENTILE
+		}
+	}
+
 	// default to an identifier
 	lval.staticStr = l.TokenText()
 	return IDENTIFIER
@@ -243,6 +261,60 @@ func (l *lexer) Error(msg string) {
 	l.errs = append(l.errs, newParseError(msg, l.Line, l.Column))
 }
 
+// bind records a name declared in a with (...) clause. Every later reference to the name is replaced
+// by the bound expression while parsing.
+func (l *lexer) bind(name string, e FieldExpression) {
+	if _, ok := l.bindings[name]; ok {
+		l.Error(fmt.Sprintf("binding %s is already declared", name))
+		return
+	}
+	if l.bindings == nil {
+		l.bindings = map[string]FieldExpression{}
+	}
+	l.bindings[name] = e
+}
+
+// binding returns the expression bound to name.
+func (l *lexer) binding(name string) FieldExpression {
+	e, ok := l.bindings[name]
+	if !ok {
+		l.Error(fmt.Sprintf("unknown identifier %s", name))
+		return NewStaticNil()
+	}
+	return e
+}
+
+// scalarBinding returns the static bound to name for use in a scalar filter.
+func (l *lexer) scalarBinding(name string) ScalarExpression {
+	e := l.binding(name)
+	s, ok := e.(Static)
+	if !ok {
+		l.Error(fmt.Sprintf("binding %s must be a static value to be used in a scalar filter", name))
+		return NewStaticNil()
+	}
+	return s
+}
+
+// parsePercentile parses the name of a percentile aggregate, p followed by at least two digits, into its quantile,
+// e.g. p90 is 0.9 and p999 is 0.999.
+func parsePercentile(s string) (float64, bool) {
+	digits, ok := strings.CutPrefix(s, "p")
+	if !ok || len(digits) < 2 {
+		return 0, false
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return 0, false
+		}
+	}
+
+	q, err := strconv.ParseFloat("0."+digits, 64)
+	if err != nil {
+		return 0, false
+	}
+	return q, true
+}
+
 func parseAttribute(s *scanner.Scanner) (string, error) {
 	var sb strings.Builder
 	r := s.Peek()
@@ -98,6 +98,8 @@ func TestLexerScopedIntrinsic(t *testing.T) {
 		{`span:status`, []int{SPAN_COLON, STATUS}},
 		{`span:statusMessage`, []int{SPAN_COLON, STATUS_MESSAGE}},
 		{`span:id`, []int{SPAN_COLON, ID}},
+		{`span:eventCount`, []int{SPAN_COLON, EVENT_COUNT}},
+		{`span:linkCount`, []int{SPAN_COLON, LINK_COUNT}},
 		// event scoped intrinsics
 		{`event:name`, []int{EVENT_COLON, NAME}},
 		// link scoped intrinsics
@@ -698,6 +698,10 @@ func TestScalarExpressionOperators(t *testing.T) {
 		{in: "min(1) > 1", expected: newScalarFilter(OpGreater, newAggregate(aggregateMin, NewStaticInt(1)), NewStaticInt(1))},
 		{in: "sum(true) > 1", expected: newScalarFilter(OpGreater, newAggregate(aggregateSum, NewStaticBool(true)), NewStaticInt(1))},
 		{in: "avg(`c`) > 1", expected: newScalarFilter(OpGreater, newAggregate(aggregateAvg, NewStaticString("c")), NewStaticInt(1))},
+		{in: "stddev(duration) > 1s", expected: newScalarFilter(OpGreater, newAggregate(aggregateStddev, NewIntrinsic(IntrinsicDuration)), NewStaticDuration(time.Second))},
+		{in: "p90(duration) > 1s", expected: newScalarFilter(OpGreater, newAggregatePercentile(0.9, NewIntrinsic(IntrinsicDuration)), NewStaticDuration(time.Second))},
+		{in: "p999(.a) > 1", expected: newScalarFilter(OpGreater, newAggregatePercentile(0.999, NewAttribute("a")), NewStaticInt(1))},
+		{in: "p90(duration) > avg(duration)", expected: newScalarFilter(OpGreater, newAggregatePercentile(0.9, NewIntrinsic(IntrinsicDuration)), newAggregate(aggregateAvg, NewIntrinsic(IntrinsicDuration)))},
 	}
 
 	for _, tc := range tests {
@@ -715,7 +719,7 @@ func TestSpansetFilterErrors(t *testing.T) {
 		in  string
 		err error
 	}{
-		{in: "wharblgarbl", err: newParseError("syntax error: unexpected IDENTIFIER", 1, 1)},
+		{in: "wharblgarbl", err: newParseError("unknown identifier wharblgarbl", 1, 1)},
 		{in: "{ 2 <> 3}", err: newParseError("syntax error: unexpected >", 1, 6)},
 		{in: "{ 2 = .b ", err: newParseError("syntax error: unexpected $end", 1, 10)},
 		{in: "{ + }", err: newParseError("syntax error: unexpected +", 1, 3)},
@@ -1213,6 +1217,8 @@ func TestScopedIntrinsics(t *testing.T) {
 		{in: "span:status", expected: IntrinsicStatus},
 		{in: "span:statusMessage", expected: IntrinsicStatusMessage},
 		{in: "span:id", expected: IntrinsicSpanID},
+		{in: "span:eventCount", expected: IntrinsicEventCount},
+		{in: "span:linkCount", expected: IntrinsicLinkCount},
 		{in: "event:name", expected: IntrinsicEventName},
 		{in: "link:traceID", expected: IntrinsicLinkTraceID},
 		{in: "link:spanID", expected: IntrinsicLinkSpanID},
@@ -1319,6 +1325,80 @@ func TestHints(t *testing.T) {
 	}
 }
 
+func TestBindings(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected *RootExpr
+	}{
+		{
+			in: `with (threshold = 2s) { duration > threshold && span.retry = true }`,
+			expected: newRootExpr(newPipeline(
+				newSpansetFilter(newBinaryOperation(OpAnd,
+					newBinaryOperation(OpGreater, NewIntrinsic(IntrinsicDuration), NewStaticDuration(2*time.Second)),
+					newBinaryOperation(OpEqual, NewScopedAttribute(AttributeScopeSpan, false, "retry"), NewStaticBool(true)),
+				)),
+			)),
+		},
+		{
+			in: `with (threshold = 2s, slow = duration > threshold) { slow } && { slow && .foo = "bar" }`,
+			expected: newRootExpr(newPipeline(
+				newSpansetOperation(OpSpansetAnd,
+					newSpansetFilter(newBinaryOperation(OpGreater, NewIntrinsic(IntrinsicDuration), NewStaticDuration(2*time.Second))),
+					newSpansetFilter(newBinaryOperation(OpAnd,
+						newBinaryOperation(OpGreater, NewIntrinsic(IntrinsicDuration), NewStaticDuration(2*time.Second)),
+						newBinaryOperation(OpEqual, NewAttribute("foo"), NewStaticString("bar")),
+					)),
+				),
+			)),
+		},
+		{
+			in: `with (n = 3) { } | count() > n`,
+			expected: newRootExpr(newPipeline(
+				newSpansetFilter(NewStaticBool(true)),
+				newScalarFilter(OpGreater, newAggregate(aggregateCount, nil), NewStaticInt(3)),
+			)),
+		},
+		{
+			in: `with (n = 3) { } | rate() with(foo="bar")`,
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newMetricsAggregate(metricsAggregateRate, nil),
+			).withHints(newHints([]*Hint{
+				newHint("foo", NewStaticString("bar")),
+			})),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			actual, err := Parse(tc.in)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestBindingErrors(t *testing.T) {
+	tests := []struct {
+		in  string
+		err error
+	}{
+		{in: "{ duration > threshold }", err: newParseError("unknown identifier threshold", 1, 14)},
+		{in: "with (a = 1, a = 2) { .foo = a }", err: newParseError("binding a is already declared", 1, 19)},
+		{in: "with (a = .foo > 1) { } | count() > a", err: newParseError("binding a must be a static value to be used in a scalar filter", 1, 37)},
+		{in: "{ .foo = a } with (a = 1)", err: newParseError("unknown identifier a", 1, 10)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			_, err := Parse(tc.in)
+
+			require.Equal(t, tc.err, err)
+		})
+	}
+}
+
 func TestReallyLongQuery(t *testing.T) {
 	for i := 1000; i < 1050; i++ {
 		longVal := strings.Repeat("a", i)
@@ -1378,6 +1458,31 @@ func TestMetrics(t *testing.T) {
 					}),
 			),
 		},
+		{
+			in: `{ } | sum_over_time(span.bytes) by(name)`,
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newMetricsAggregateWithAttr(metricsAggregateSumOverTime,
+					NewScopedAttribute(AttributeScopeSpan, false, "bytes"),
+					[]Attribute{
+						NewIntrinsic(IntrinsicName),
+					}),
+			),
+		},
+		{
+			in: `{ } | min_over_time(duration)`,
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newMetricsAggregateWithAttr(metricsAggregateMinOverTime, NewIntrinsic(IntrinsicDuration), nil),
+			),
+		},
+		{
+			in: `{ } | max_over_time(.foo)`,
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newMetricsAggregateWithAttr(metricsAggregateMaxOverTime, NewAttribute("foo"), nil),
+			),
+		},
 	}
 
 	for _, tc := range tests {
@@ -0,0 +1,378 @@
+package traceql
+
+import (
+	"errors"
+	"time"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	resource_v1 "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+var errTraceFilterMetrics = errors.New("metrics queries are not supported as span filters")
+
+// TraceFilter evaluates a TraceQL query against a single trace held in memory. It is used to
+// trim trace by id responses down to the spans matching the query plus their ancestors.
+// A TraceFilter holds evaluation buffers and must not be shared between goroutines.
+type TraceFilter struct {
+	eval SpansetFilterFunc
+}
+
+// NewTraceFilter compiles the query into a TraceFilter. Metrics queries are rejected.
+func NewTraceFilter(query string) (*TraceFilter, error) {
+	_, eval, metricsPipeline, _, err := NewEngine().Compile(query)
+	if err != nil {
+		return nil, err
+	}
+	if metricsPipeline != nil {
+		return nil, errTraceFilterMetrics
+	}
+
+	return &TraceFilter{eval: eval}, nil
+}
+
+// Filter returns a copy of the trace that only contains the spans matching the query and all of their
+// ancestors. Resources and scopes left without spans are dropped. The passed trace is not modified.
+func (f *TraceFilter) Filter(t *tempopb.Trace) (*tempopb.Trace, error) {
+	if t == nil || len(t.Batches) == 0 {
+		return t, nil
+	}
+
+	ss := newFilterSpanset(t)
+	results, err := f.eval([]*Spanset{ss})
+	if err != nil {
+		return nil, err
+	}
+
+	keep := map[*v1.Span]struct{}{}
+	for _, r := range results {
+		for _, s := range r.Spans {
+			for fs := s.(*filterSpan); fs != nil; fs = fs.parent {
+				if _, ok := keep[fs.span]; ok {
+					break
+				}
+				keep[fs.span] = struct{}{}
+			}
+		}
+	}
+
+	filtered := &tempopb.Trace{}
+	for _, rs := range t.Batches {
+		var scopeSpans []*v1.ScopeSpans
+		for _, ils := range rs.ScopeSpans {
+			var spans []*v1.Span
+			for _, s := range ils.Spans {
+				if _, ok := keep[s]; ok {
+					spans = append(spans, s)
+				}
+			}
+			if len(spans) == 0 {
+				continue
+			}
+			scopeSpans = append(scopeSpans, &v1.ScopeSpans{
+				Scope:     ils.Scope,
+				Spans:     spans,
+				SchemaUrl: ils.SchemaUrl,
+			})
+		}
+		if len(scopeSpans) == 0 {
+			continue
+		}
+		filtered.Batches = append(filtered.Batches, &v1.ResourceSpans{
+			Resource:   rs.Resource,
+			ScopeSpans: scopeSpans,
+			SchemaUrl:  rs.SchemaUrl,
+		})
+	}
+
+	return filtered, nil
+}
+
+// filterTrace holds the trace level intrinsics shared by all spans of a trace.
+type filterTrace struct {
+	id          []byte
+	rootName    string
+	rootService string
+	start, end  uint64
+}
+
+var _ Span = (*filterSpan)(nil)
+
+// filterSpan implements Span on top of a proto span. Parent links are resolved up front so the
+// structural operators can be answered without nested set information.
+type filterSpan struct {
+	span     *v1.Span
+	resource *resource_v1.Resource
+	trace    *filterTrace
+	parent   *filterSpan
+}
+
+func newFilterSpanset(t *tempopb.Trace) *Spanset {
+	tr := &filterTrace{}
+	byID := map[string]*filterSpan{}
+	ss := &Spanset{}
+
+	for _, rs := range t.Batches {
+		for _, ils := range rs.ScopeSpans {
+			for _, s := range ils.Spans {
+				fs := &filterSpan{span: s, resource: rs.Resource, trace: tr}
+				byID[string(s.SpanId)] = fs
+				ss.Spans = append(ss.Spans, fs)
+
+				if tr.id == nil {
+					tr.id = s.TraceId
+				}
+				if tr.start == 0 || s.StartTimeUnixNano < tr.start {
+					tr.start = s.StartTimeUnixNano
+				}
+				if s.EndTimeUnixNano > tr.end {
+					tr.end = s.EndTimeUnixNano
+				}
+				if len(s.ParentSpanId) == 0 {
+					tr.rootName = s.Name
+					if v, ok := attributeByKey(rs.Resource.GetAttributes(), "service.name"); ok {
+						tr.rootService = v.S
+					}
+				}
+			}
+		}
+	}
+
+	for _, s := range ss.Spans {
+		fs := s.(*filterSpan)
+		if len(fs.span.ParentSpanId) == 0 {
+			continue
+		}
+		if p, ok := byID[string(fs.span.ParentSpanId)]; ok && p != fs {
+			fs.parent = p
+		}
+	}
+
+	ss.TraceID = tr.id
+	ss.RootSpanName = tr.rootName
+	ss.RootServiceName = tr.rootService
+	ss.StartTimeUnixNanos = tr.start
+	if tr.end > tr.start {
+		ss.DurationNanos = tr.end - tr.start
+	}
+
+	return ss
+}
+
+func (s *filterSpan) AttributeFor(a Attribute) (Static, bool) {
+	if a.Intrinsic != IntrinsicNone {
+		return s.intrinsic(a.Intrinsic)
+	}
+
+	switch a.Scope {
+	case AttributeScopeSpan:
+		return attributeByKey(s.span.Attributes, a.Name)
+	case AttributeScopeResource:
+		return attributeByKey(s.resource.GetAttributes(), a.Name)
+	case AttributeScopeEvent:
+		for _, e := range s.span.Events {
+			if v, ok := attributeByKey(e.Attributes, a.Name); ok {
+				return v, true
+			}
+		}
+		return Static{}, false
+	case AttributeScopeLink:
+		for _, l := range s.span.Links {
+			if v, ok := attributeByKey(l.Attributes, a.Name); ok {
+				return v, true
+			}
+		}
+		return Static{}, false
+	}
+
+	// unscoped attributes give precedence to the span
+	if v, ok := attributeByKey(s.span.Attributes, a.Name); ok {
+		return v, true
+	}
+	return attributeByKey(s.resource.GetAttributes(), a.Name)
+}
+
+func (s *filterSpan) intrinsic(i Intrinsic) (Static, bool) {
+	switch i {
+	case IntrinsicName:
+		return NewStaticString(s.span.Name), true
+	case IntrinsicDuration:
+		return NewStaticDuration(time.Duration(s.DurationNanos())), true
+	case IntrinsicStatus:
+		return NewStaticStatus(otlpStatusToStatus(s.span.Status.GetCode())), true
+	case IntrinsicStatusMessage:
+		return NewStaticString(s.span.Status.GetMessage()), true
+	case IntrinsicKind:
+		return NewStaticKind(otlpKindToKind(s.span.Kind)), true
+	case IntrinsicSpanID:
+		return NewStaticString(util.SpanIDToHexString(s.span.SpanId)), true
+	case IntrinsicSpanStartTime:
+		return NewStaticInt(int(s.span.StartTimeUnixNano)), true
+	case IntrinsicEventCount:
+		return NewStaticInt(len(s.span.Events)), true
+	case IntrinsicLinkCount:
+		return NewStaticInt(len(s.span.Links)), true
+	case IntrinsicEventName:
+		if len(s.span.Events) > 0 {
+			return NewStaticString(s.span.Events[0].Name), true
+		}
+	case IntrinsicTraceID:
+		return NewStaticString(util.TraceIDToHexString(s.trace.id)), true
+	case IntrinsicTraceRootSpan:
+		return NewStaticString(s.trace.rootName), true
+	case IntrinsicTraceRootService:
+		return NewStaticString(s.trace.rootService), true
+	case IntrinsicTraceDuration:
+		var d uint64
+		if s.trace.end > s.trace.start {
+			d = s.trace.end - s.trace.start
+		}
+		return NewStaticDuration(time.Duration(d)), true
+	}
+
+	return Static{}, false
+}
+
+func (s *filterSpan) AllAttributes() map[Attribute]Static {
+	atts := map[Attribute]Static{}
+	s.AllAttributesFunc(func(a Attribute, v Static) {
+		atts[a] = v
+	})
+	return atts
+}
+
+func (s *filterSpan) AllAttributesFunc(cb func(Attribute, Static)) {
+	for _, i := range []Intrinsic{IntrinsicName, IntrinsicDuration, IntrinsicStatus, IntrinsicKind} {
+		v, _ := s.intrinsic(i)
+		cb(NewIntrinsic(i), v)
+	}
+	for _, kv := range s.span.Attributes {
+		cb(NewScopedAttribute(AttributeScopeSpan, false, kv.Key), StaticFromAnyValue(kv.Value))
+	}
+	for _, kv := range s.resource.GetAttributes() {
+		cb(NewScopedAttribute(AttributeScopeResource, false, kv.Key), StaticFromAnyValue(kv.Value))
+	}
+}
+
+func (s *filterSpan) ID() []byte {
+	return s.span.SpanId
+}
+
+func (s *filterSpan) StartTimeUnixNanos() uint64 {
+	return s.span.StartTimeUnixNano
+}
+
+func (s *filterSpan) DurationNanos() uint64 {
+	if s.span.EndTimeUnixNano < s.span.StartTimeUnixNano {
+		return 0
+	}
+	return s.span.EndTimeUnixNano - s.span.StartTimeUnixNano
+}
+
+func (s *filterSpan) DescendantOf(lhs []Span, rhs []Span, falseForAll bool, invert bool, union bool, _ []Span) []Span {
+	// r is a descendant of l
+	return relateFilterSpans(lhs, rhs, falseForAll, invert, union, func(l, r *filterSpan) bool {
+		for p := r.parent; p != nil; p = p.parent {
+			if p == l {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (s *filterSpan) ChildOf(lhs []Span, rhs []Span, falseForAll bool, invert bool, union bool, _ []Span) []Span {
+	// r is a child of l
+	return relateFilterSpans(lhs, rhs, falseForAll, invert, union, func(l, r *filterSpan) bool {
+		return r.parent != nil && r.parent == l
+	})
+}
+
+func (s *filterSpan) SiblingOf(lhs []Span, rhs []Span, falseForAll bool, union bool, _ []Span) []Span {
+	return relateFilterSpans(lhs, rhs, falseForAll, false, union, func(l, r *filterSpan) bool {
+		return l != r && r.parent != nil && r.parent == l.parent
+	})
+}
+
+// relateFilterSpans compares all pairs of lhs and rhs spans. Traces handled by a TraceFilter have already
+// been fetched in full so a quadratic loop is acceptable here.
+func relateFilterSpans(lhs, rhs []Span, falseForAll, invert, union bool, rel func(l, r *filterSpan) bool) []Span {
+	var buffer []Span
+
+	match := func(l, r *filterSpan) bool {
+		if invert {
+			return rel(r, l)
+		}
+		return rel(l, r)
+	}
+
+	if union {
+		seen := map[*filterSpan]struct{}{}
+		add := func(s *filterSpan) {
+			if _, ok := seen[s]; !ok {
+				seen[s] = struct{}{}
+				buffer = append(buffer, s)
+			}
+		}
+		for _, r := range rhs {
+			for _, l := range lhs {
+				if match(l.(*filterSpan), r.(*filterSpan)) {
+					add(l.(*filterSpan))
+					add(r.(*filterSpan))
+				}
+			}
+		}
+		return buffer
+	}
+
+	for _, r := range rhs {
+		matches := false
+		for _, l := range lhs {
+			if match(l.(*filterSpan), r.(*filterSpan)) {
+				matches = true
+				break
+			}
+		}
+		if matches != falseForAll {
+			buffer = append(buffer, r)
+		}
+	}
+	return buffer
+}
+
+func attributeByKey(attrs []*common_v1.KeyValue, key string) (Static, bool) {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return StaticFromAnyValue(kv.Value), true
+		}
+	}
+	return Static{}, false
+}
+
+func otlpStatusToStatus(c v1.Status_StatusCode) Status {
+	switch c {
+	case v1.Status_STATUS_CODE_OK:
+		return StatusOk
+	case v1.Status_STATUS_CODE_ERROR:
+		return StatusError
+	}
+	return StatusUnset
+}
+
+func otlpKindToKind(k v1.Span_SpanKind) Kind {
+	switch k {
+	case v1.Span_SPAN_KIND_INTERNAL:
+		return KindInternal
+	case v1.Span_SPAN_KIND_SERVER:
+		return KindServer
+	case v1.Span_SPAN_KIND_CLIENT:
+		return KindClient
+	case v1.Span_SPAN_KIND_PRODUCER:
+		return KindProducer
+	case v1.Span_SPAN_KIND_CONSUMER:
+		return KindConsumer
+	}
+	return KindUnspecified
+}
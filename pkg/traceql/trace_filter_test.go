@@ -0,0 +1,134 @@
+package traceql
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	resource_v1 "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceFilter(t *testing.T) {
+	// root (frontend)
+	// ├── db (backend, db.system=postgres)
+	// │   └── query (backend, status=error)
+	// └── cache (backend)
+	tr := filterTestTrace()
+
+	tcs := []struct {
+		query    string
+		expected []string
+	}{
+		{query: `{ }`, expected: []string{"cache", "db", "query", "root"}},
+		{query: `{ span.db.system = "postgres" }`, expected: []string{"db", "root"}},
+		{query: `{ status = error }`, expected: []string{"db", "query", "root"}},
+		{query: `{ resource.service.name = "backend" && kind = client }`, expected: []string{"cache", "root"}},
+		{query: `{ .service.name = "frontend" }`, expected: []string{"root"}},
+		{query: `{ name = "root" } > { }`, expected: []string{"cache", "db", "root"}},
+		{query: `{ name = "cache" } ~ { }`, expected: []string{"db", "root"}},
+		{query: `{ } !< { }`, expected: []string{"cache", "db", "query", "root"}},
+		{query: `{ trace:rootService = "frontend" && duration > 5ns }`, expected: []string{"db", "root"}},
+		{query: `{ name = "nope" }`, expected: nil},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			f, err := NewTraceFilter(tc.query)
+			require.NoError(t, err)
+
+			actual, err := f.Filter(tr)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, filterTestSpanNames(actual))
+		})
+	}
+
+	// the input is left untouched
+	require.Equal(t, []string{"cache", "db", "query", "root"}, filterTestSpanNames(tr))
+}
+
+func TestTraceFilterEmptyTrace(t *testing.T) {
+	f, err := NewTraceFilter(`{ }`)
+	require.NoError(t, err)
+
+	actual, err := f.Filter(nil)
+	require.NoError(t, err)
+	require.Nil(t, actual)
+
+	actual, err = f.Filter(&tempopb.Trace{})
+	require.NoError(t, err)
+	require.Empty(t, actual.Batches)
+}
+
+func TestTraceFilterInvalid(t *testing.T) {
+	_, err := NewTraceFilter(`{ .foo = `)
+	require.Error(t, err)
+
+	_, err = NewTraceFilter(`{ } | rate()`)
+	require.ErrorIs(t, err, errTraceFilterMetrics)
+}
+
+func filterTestTrace() *tempopb.Trace {
+	traceID := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10}
+	span := func(id, parent byte, name string, kind v1.Span_SpanKind, start, end uint64) *v1.Span {
+		s := &v1.Span{
+			TraceId:           traceID,
+			SpanId:            []byte{0, 0, 0, 0, 0, 0, 0, id},
+			Name:              name,
+			Kind:              kind,
+			StartTimeUnixNano: start,
+			EndTimeUnixNano:   end,
+		}
+		if parent != 0 {
+			s.ParentSpanId = []byte{0, 0, 0, 0, 0, 0, 0, parent}
+		}
+		return s
+	}
+	resource := func(service string) *resource_v1.Resource {
+		return &resource_v1.Resource{
+			Attributes: []*common_v1.KeyValue{
+				{Key: "service.name", Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: service}}},
+			},
+		}
+	}
+
+	db := span(2, 1, "db", v1.Span_SPAN_KIND_SERVER, 2, 9)
+	db.Attributes = []*common_v1.KeyValue{
+		{Key: "db.system", Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: "postgres"}}},
+	}
+	query := span(3, 2, "query", v1.Span_SPAN_KIND_INTERNAL, 3, 4)
+	query.Status = &v1.Status{Code: v1.Status_STATUS_CODE_ERROR}
+
+	return &tempopb.Trace{
+		Batches: []*v1.ResourceSpans{
+			{
+				Resource: resource("frontend"),
+				ScopeSpans: []*v1.ScopeSpans{
+					{Spans: []*v1.Span{span(1, 0, "root", v1.Span_SPAN_KIND_SERVER, 1, 10)}},
+				},
+			},
+			{
+				Resource: resource("backend"),
+				ScopeSpans: []*v1.ScopeSpans{
+					{Spans: []*v1.Span{db, query}},
+					{Spans: []*v1.Span{span(4, 1, "cache", v1.Span_SPAN_KIND_CLIENT, 2, 3)}},
+				},
+			},
+		},
+	}
+}
+
+func filterTestSpanNames(tr *tempopb.Trace) []string {
+	var names []string
+	for _, rs := range tr.Batches {
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				names = append(names, s.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -39,3 +39,11 @@ func GetCounterVecValue(metric *prometheus.CounterVec, label string) (float64, e
 	}
 	return m.Counter.GetValue(), nil
 }
+
+func GetHistogramVecSampleCount(metric *prometheus.HistogramVec, labels ...string) (uint64, error) {
+	m := &dto.Metric{}
+	if err := metric.WithLabelValues(labels...).(prometheus.Histogram).Write(m); err != nil {
+		return 0, err
+	}
+	return m.Histogram.GetSampleCount(), nil
+}
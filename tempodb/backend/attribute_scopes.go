@@ -0,0 +1,58 @@
+package backend
+
+import (
+	"slices"
+)
+
+// AttributeScopes lists the names of the attributes observed in the resources and spans of a block. Queries of
+// unscoped attributes only need to read the scope an attribute is observed in.
+type AttributeScopes struct {
+	Resource []string `json:"resource,omitempty"`
+	Span     []string `json:"span,omitempty"`
+}
+
+// NewAttributeScopes returns the sorted attribute names of both scopes. It returns nil if a scope has more than
+// maxNames names, the block meta would get too large to be worth it.
+func NewAttributeScopes(resource, span map[string]struct{}, maxNames int) *AttributeScopes {
+	if len(resource) > maxNames || len(span) > maxNames {
+		return nil
+	}
+
+	sorted := func(names map[string]struct{}) []string {
+		s := make([]string, 0, len(names))
+		for n := range names {
+			s = append(s, n)
+		}
+		slices.Sort(s)
+		return s
+	}
+
+	return &AttributeScopes{
+		Resource: sorted(resource),
+		Span:     sorted(span),
+	}
+}
+
+// MergeAttributeScopes returns the attribute names observed in any of the blocks. It returns nil if the names of
+// a block weren't recorded or if a scope has more than maxNames names.
+func MergeAttributeScopes(maxNames int, metas ...*BlockMeta) *AttributeScopes {
+	if maxNames <= 0 || len(metas) == 0 {
+		return nil
+	}
+
+	resource := map[string]struct{}{}
+	span := map[string]struct{}{}
+	for _, m := range metas {
+		if m.AttributeScopes == nil {
+			return nil
+		}
+		for _, n := range m.AttributeScopes.Resource {
+			resource[n] = struct{}{}
+		}
+		for _, n := range m.AttributeScopes.Span {
+			span[n] = struct{}{}
+		}
+	}
+
+	return NewAttributeScopes(resource, span, maxNames)
+}
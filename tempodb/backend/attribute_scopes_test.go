@@ -0,0 +1,26 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeAttributeScopes(t *testing.T) {
+	a := &BlockMeta{AttributeScopes: &AttributeScopes{Resource: []string{"service.name"}, Span: []string{"http.method", "user.id"}}}
+	b := &BlockMeta{AttributeScopes: &AttributeScopes{Resource: []string{"cluster", "service.name"}, Span: []string{"db.system"}}}
+
+	require.Equal(t, &AttributeScopes{
+		Resource: []string{"cluster", "service.name"},
+		Span:     []string{"db.system", "http.method", "user.id"},
+	}, MergeAttributeScopes(10, a, b))
+
+	// too many names
+	require.Nil(t, MergeAttributeScopes(2, a, b))
+
+	// a block without recorded names
+	require.Nil(t, MergeAttributeScopes(10, a, &BlockMeta{}))
+
+	// disabled
+	require.Nil(t, MergeAttributeScopes(0, a, b))
+}
@@ -15,6 +15,8 @@ type Config struct {
 	UseManagedIdentity bool           `yaml:"use_managed_identity"`
 	UseFederatedToken  bool           `yaml:"use_federated_token"`
 	UserAssignedID     string         `yaml:"user_assigned_id"`
+	TenantID           string         `yaml:"tenant_id"`
+	FederatedTokenFile string         `yaml:"federated_token_file"`
 	ContainerName      string         `yaml:"container_name"`
 	Prefix             string         `yaml:"prefix"`
 	Endpoint           string         `yaml:"endpoint_suffix"`
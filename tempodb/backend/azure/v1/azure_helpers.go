@@ -198,13 +198,13 @@ func getServicePrincipalToken(cfg *config.Config) (*adal.ServicePrincipalToken,
 	resource := fmt.Sprintf("https://%s.%s", cfg.StorageAccountName, endpoint)
 
 	if cfg.UseFederatedToken {
-		token, err := servicePrincipalTokenFromFederatedToken(resource, defaultAuthFunctions)
+		token, err := servicePrincipalTokenFromFederatedToken(cfg, resource, defaultAuthFunctions)
 		if err != nil {
 			return nil, err
 		}
 
 		var customRefreshFunc adal.TokenRefresh = func(context context.Context, resource string) (*adal.Token, error) {
-			newToken, err := servicePrincipalTokenFromFederatedToken(resource, defaultAuthFunctions)
+			newToken, err := servicePrincipalTokenFromFederatedToken(cfg, resource, defaultAuthFunctions)
 			if err != nil {
 				return nil, err
 			}
@@ -234,16 +234,29 @@ func getServicePrincipalToken(cfg *config.Config) (*adal.ServicePrincipalToken,
 	return msiConfig.ServicePrincipalToken()
 }
 
-func servicePrincipalTokenFromFederatedToken(resource string, authFunctions authFunctions) (*adal.ServicePrincipalToken, error) {
-	azClientID := os.Getenv("AZURE_CLIENT_ID")
-	azTenantID := os.Getenv("AZURE_TENANT_ID")
+// servicePrincipalTokenFromFederatedToken exchanges the federated token for a service principal token. The client id,
+// tenant id and token file are taken from the config and fall back to the environment variables set by the Azure
+// workload identity webhook.
+func servicePrincipalTokenFromFederatedToken(cfg *config.Config, resource string, authFunctions authFunctions) (*adal.ServicePrincipalToken, error) {
+	azClientID := cfg.UserAssignedID
+	if azClientID == "" {
+		azClientID = os.Getenv("AZURE_CLIENT_ID")
+	}
+	azTenantID := cfg.TenantID
+	if azTenantID == "" {
+		azTenantID = os.Getenv("AZURE_TENANT_ID")
+	}
+	tokenFile := cfg.FederatedTokenFile
+	if tokenFile == "" {
+		tokenFile = os.Getenv("AZURE_FEDERATED_TOKEN_FILE")
+	}
 
 	azADEndpoint, ok := os.LookupEnv("AZURE_AUTHORITY_HOST")
 	if !ok {
 		azADEndpoint = azure.PublicCloud.ActiveDirectoryEndpoint
 	}
 
-	jwtBytes, err := os.ReadFile(os.Getenv("AZURE_FEDERATED_TOKEN_FILE"))
+	jwtBytes, err := os.ReadFile(tokenFile)
 	if err != nil {
 		return nil, err
 	}
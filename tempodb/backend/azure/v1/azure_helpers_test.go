@@ -150,7 +150,7 @@ func TestServicePrincipalTokenFromFederatedToken(t *testing.T) {
 		return mockedServicePrincipalToken, nil
 	}
 
-	token, err := servicePrincipalTokenFromFederatedToken("https://bar.blob.core.windows.net", authFunctions{
+	token, err := servicePrincipalTokenFromFederatedToken(&config.Config{}, "https://bar.blob.core.windows.net", authFunctions{
 		newOAuthConfigFunc,
 		servicePrincipalTokenFromFederatedTokenFunc,
 	})
@@ -158,3 +158,37 @@ func TestServicePrincipalTokenFromFederatedToken(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, mockedServicePrincipalToken == token, "should return the mocked object")
 }
+
+func TestServicePrincipalTokenFromFederatedTokenInConfig(t *testing.T) {
+	t.Setenv("AZURE_CLIENT_ID", "envClientId")
+	t.Setenv("AZURE_TENANT_ID", "envTenantId")
+	t.Setenv("AZURE_AUTHORITY_HOST", TestAzureADEndpoint)
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "/does/not/exist")
+
+	tmpDir := t.TempDir()
+	_ = os.WriteFile(tmpDir+"/jwtToken", []byte("myJwtToken"), 0o666)
+
+	cfg := &config.Config{
+		UseFederatedToken:  true,
+		UserAssignedID:     TestAzureClientID,
+		TenantID:           TestAzureTenantID,
+		FederatedTokenFile: tmpDir + "/jwtToken",
+	}
+
+	mockedServicePrincipalToken := new(adal.ServicePrincipalToken)
+
+	token, err := servicePrincipalTokenFromFederatedToken(cfg, "https://bar.blob.core.windows.net", authFunctions{
+		func(activeDirectoryEndpoint, tenantID string) (*adal.OAuthConfig, error) {
+			assert.Equal(t, TestAzureTenantID, tenantID)
+			return adal.NewOAuthConfig(activeDirectoryEndpoint, tenantID)
+		},
+		func(_ adal.OAuthConfig, clientID string, jwt string, _ string, _ ...adal.TokenRefreshCallback) (*adal.ServicePrincipalToken, error) {
+			assert.Equal(t, TestAzureClientID, clientID)
+			assert.Equal(t, "myJwtToken", jwt)
+			return mockedServicePrincipalToken, nil
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, mockedServicePrincipalToken == token, "should return the mocked object")
+}
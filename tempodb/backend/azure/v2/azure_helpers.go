@@ -85,7 +85,14 @@ func getContainerClient(ctx context.Context, cfg *config.Config, hedge bool) (*c
 
 	switch {
 	case cfg.UseFederatedToken:
-		credential, err := azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{})
+		// Empty values fall back to the AZURE_CLIENT_ID, AZURE_TENANT_ID and AZURE_FEDERATED_TOKEN_FILE
+		// environment variables. Setting them allows a different identity per backend configuration.
+		// The credential reads the token file again whenever the access token expires.
+		credential, err := azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+			ClientID:      cfg.UserAssignedID,
+			TenantID:      cfg.TenantID,
+			TokenFilePath: cfg.FederatedTokenFile,
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -111,3 +111,28 @@ func TestGetContainerClient(t *testing.T) {
 		})
 	}
 }
+
+func TestGetContainerClientFederatedToken(t *testing.T) {
+	t.Setenv("AZURE_CLIENT_ID", "")
+	t.Setenv("AZURE_TENANT_ID", "")
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "")
+
+	cfg := config.Config{
+		StorageAccountName: "devstoreaccount1",
+		ContainerName:      "traces",
+		Endpoint:           "blob.core.windows.net",
+		UseFederatedToken:  true,
+	}
+
+	// without a client id, tenant id and token file the credential can't be created
+	_, err := getContainerClient(context.Background(), &cfg, false)
+	assert.Error(t, err)
+
+	cfg.UserAssignedID = "myClientId"
+	cfg.TenantID = "myTenantId"
+	cfg.FederatedTokenFile = t.TempDir() + "/jwtToken"
+
+	client, err := getContainerClient(context.Background(), &cfg, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://devstoreaccount1.blob.core.windows.net/traces", client.URL())
+}
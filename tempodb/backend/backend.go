@@ -15,6 +15,8 @@ const (
 	GCS   = "gcs"
 	S3    = "s3"
 	Azure = "azure"
+	OSS   = "oss"
+	COS   = "cos"
 )
 
 var (
@@ -48,6 +50,16 @@ type Writer interface {
 	CloseAppend(ctx context.Context, tracker AppendTracker) error
 	// WriteTenantIndex writes the two meta slices as a tenant index
 	WriteTenantIndex(ctx context.Context, tenantID string, meta []*BlockMeta, compactedMeta []*CompactedBlockMeta) error
+	// WriteTenantDeletionMark marks all data of a tenant for deletion
+	WriteTenantDeletionMark(ctx context.Context, tenantID string, mark *TenantDeletionMark) error
+	// WriteDedicatedColumnsRecommendation writes the dedicated columns recommended for a tenant
+	WriteDedicatedColumnsRecommendation(ctx context.Context, tenantID string, rec *DedicatedColumnsRecommendation) error
+	// WriteIngesterSnapshotFile writes a file of the snapshot of a tenant taken by an ingester.
+	WriteIngesterSnapshotFile(ctx context.Context, tenantID, ingesterID, path string, data io.Reader, size int64) error
+	// WriteIngesterSnapshot writes the snapshot of a tenant taken by an ingester. It must be written after its files.
+	WriteIngesterSnapshot(ctx context.Context, tenantID, ingesterID string, snapshot *IngesterSnapshot) error
+	// DeleteIngesterSnapshotFile deletes a file of the snapshot of a tenant taken by an ingester.
+	DeleteIngesterSnapshotFile(ctx context.Context, tenantID, ingesterID, path string) error
 	// Delete deletes an object.
 	Delete(ctx context.Context, name string, keypath KeyPath) error
 }
@@ -68,6 +80,14 @@ type Reader interface {
 	BlockMeta(ctx context.Context, blockID uuid.UUID, tenantID string) (*BlockMeta, error)
 	// TenantIndex returns lists of all metas given a tenant
 	TenantIndex(ctx context.Context, tenantID string) (*TenantIndex, error)
+	// TenantDeletionMark returns the deletion mark of a tenant. Returns ErrDoesNotExist if the tenant is not marked for deletion.
+	TenantDeletionMark(ctx context.Context, tenantID string) (*TenantDeletionMark, error)
+	// DedicatedColumnsRecommendation returns the dedicated columns recommended for a tenant. Returns ErrDoesNotExist if there is none.
+	DedicatedColumnsRecommendation(ctx context.Context, tenantID string) (*DedicatedColumnsRecommendation, error)
+	// IngesterSnapshot returns the snapshot of a tenant taken by an ingester. Returns ErrDoesNotExist if there is none.
+	IngesterSnapshot(ctx context.Context, tenantID, ingesterID string) (*IngesterSnapshot, error)
+	// IngesterSnapshotFile streams a file of the snapshot of a tenant taken by an ingester.
+	IngesterSnapshotFile(ctx context.Context, tenantID, ingesterID, path string) (io.ReadCloser, int64, error)
 	// Find executes f for each object in the backend that matches the keypath.
 	Find(ctx context.Context, keypath KeyPath, f FindFunc) error
 	// Shutdown shuts...down?
@@ -83,3 +103,12 @@ type Compactor interface {
 	// CompactedBlockMeta returns the compacted blockmeta given a block and tenant id
 	CompactedBlockMeta(blockID uuid.UUID, tenantID string) (*CompactedBlockMeta, error)
 }
+
+// DeletionTagger is implemented by backends that can tag the objects of a block for deletion by a bucket lifecycle
+// rule instead of deleting them. Until the lifecycle rule purges the objects the block can be undeleted.
+type DeletionTagger interface {
+	// TagBlockForDeletion adds the tags to all objects of a compacted block and removes it from the blocklist
+	TagBlockForDeletion(blockID uuid.UUID, tenantID string, tags map[string]string) error
+	// UndeleteBlock removes the deletion tags from all objects of a tagged block and restores it as a live block
+	UndeleteBlock(blockID uuid.UUID, tenantID string) error
+}
@@ -72,6 +72,18 @@ func DedicatedColumnScopeFromTempopb(s tempopb.DedicatedColumn_Scope) (Dedicated
 	}
 }
 
+// MaxColumns returns the number of dedicated columns that are supported for the scope.
+func (s DedicatedColumnScope) MaxColumns() int {
+	switch s {
+	case DedicatedColumnScopeSpan:
+		return maxSupportedSpanColumns
+	case DedicatedColumnScopeResource:
+		return maxSupportedResourceColumns
+	default:
+		return 0
+	}
+}
+
 func (s DedicatedColumnScope) ToTempopb() (tempopb.DedicatedColumn_Scope, error) {
 	switch s {
 	case DedicatedColumnScopeSpan:
@@ -134,6 +146,11 @@ type BlockMeta struct {
 	// ReplicationFactor is the number of times the data written in this block has been replicated.
 	// It's left unset if replication factor is 3. Default is 0 (RF3).
 	ReplicationFactor uint32 `json:"replicationFactor,omitempty"`
+	// AttributeIndex is true if the block has an index of the row groups of the values of selected attributes.
+	AttributeIndex bool `json:"attributeIndex,omitempty"`
+	// AttributeScopes lists the attribute names observed in the resources and spans of the block. It's nil if
+	// the names weren't recorded.
+	AttributeScopes *AttributeScopes `json:"attributeScopes,omitempty"`
 }
 
 // DedicatedColumn contains the configuration for a single attribute with the given name that should
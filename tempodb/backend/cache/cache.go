@@ -34,6 +34,7 @@ type readerWriter struct {
 	columnIdxCache  cache.Cache
 	offsetIdxCache  cache.Cache
 	traceIDIdxCache cache.Cache
+	attrIdxCache    cache.Cache
 	pageCache       cache.Cache
 }
 
@@ -46,6 +47,7 @@ func NewCache(cfgBloom *BloomConfig, nextReader backend.RawReader, nextWriter ba
 		offsetIdxCache:  cacheProvider.CacheFor(cache.RoleParquetOffsetIdx),
 		columnIdxCache:  cacheProvider.CacheFor(cache.RoleParquetColumnIdx),
 		traceIDIdxCache: cacheProvider.CacheFor(cache.RoleTraceIDIdx),
+		attrIdxCache:    cacheProvider.CacheFor(cache.RoleAttributeIdx),
 		pageCache:       cacheProvider.CacheFor(cache.RoleParquetPage),
 
 		nextReader: nextReader,
@@ -58,6 +60,7 @@ func NewCache(cfgBloom *BloomConfig, nextReader backend.RawReader, nextWriter ba
 		"offset_idx", rw.offsetIdxCache != nil,
 		"column_idx", rw.columnIdxCache != nil,
 		"trace_id_idx", rw.traceIDIdxCache != nil,
+		"attribute_idx", rw.attrIdxCache != nil,
 		"page", rw.pageCache != nil,
 	)
 
@@ -191,6 +194,8 @@ func (r *readerWriter) cacheFor(cacheInfo *backend.CacheInfo) cache.Cache {
 		return r.pageCache
 	case cache.RoleTraceIDIdx:
 		return r.traceIDIdxCache
+	case cache.RoleAttributeIdx:
+		return r.attrIdxCache
 	case cache.RoleBloom:
 		// if there is no bloom cfg then there are no restrictions on bloom filter caching
 		if r.cfgBloom == nil {
@@ -0,0 +1,52 @@
+package cos
+
+import (
+	"flag"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+
+	"github.com/grafana/tempo/pkg/util"
+)
+
+type Config struct {
+	// Bucket is the name of the bucket including the app id, e.g. tempo-1250000000
+	Bucket string `yaml:"bucket"`
+	Prefix string `yaml:"prefix"`
+	Region string `yaml:"region"`
+	// Endpoint overrides the default endpoint of the region, cos.<region>.myqcloud.com
+	Endpoint          string         `yaml:"endpoint"`
+	SecretID          string         `yaml:"secret_id"`
+	SecretKey         flagext.Secret `yaml:"secret_key"`
+	SessionToken      flagext.Secret `yaml:"session_token"`
+	Insecure          bool           `yaml:"insecure"`
+	HedgeRequestsAt   time.Duration  `yaml:"hedge_requests_at"`
+	HedgeRequestsUpTo int            `yaml:"hedge_requests_up_to"`
+
+	ListBlocksConcurrency int `yaml:"list_blocks_concurrency"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.Bucket, util.PrefixConfig(prefix, "cos.bucket"), "", "cos bucket to store blocks in, including the app id.")
+	f.StringVar(&cfg.Prefix, util.PrefixConfig(prefix, "cos.prefix"), "", "cos root directory to store blocks in.")
+	f.StringVar(&cfg.Region, util.PrefixConfig(prefix, "cos.region"), "", "cos region of the bucket.")
+	f.StringVar(&cfg.Endpoint, util.PrefixConfig(prefix, "cos.endpoint"), "", "cos endpoint to push blocks to. Defaults to the endpoint of the region.")
+	f.StringVar(&cfg.SecretID, util.PrefixConfig(prefix, "cos.secret_id"), "", "cos secret id.")
+	f.Var(&cfg.SecretKey, util.PrefixConfig(prefix, "cos.secret_key"), "cos secret key.")
+	f.Var(&cfg.SessionToken, util.PrefixConfig(prefix, "cos.session_token"), "cos session token of temporary credentials.")
+	f.IntVar(&cfg.ListBlocksConcurrency, util.PrefixConfig(prefix, "cos.list_blocks_concurrency"), 3, "number of concurrent list calls to make to backend")
+	cfg.HedgeRequestsUpTo = 2
+}
+
+func (cfg *Config) PathMatches(other *Config) bool {
+	// COS bucket names include the app id and are globally unique
+	return cfg.Bucket == other.Bucket && cfg.Prefix == other.Prefix
+}
+
+// endpoint returns the configured endpoint or the default endpoint of the region.
+func (cfg *Config) endpoint() string {
+	if cfg.Endpoint != "" {
+		return cfg.Endpoint
+	}
+	return "cos." + cfg.Region + ".myqcloud.com"
+}
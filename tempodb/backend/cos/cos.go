@@ -0,0 +1,185 @@
+package cos
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/tencentyun/cos-go-sdk-v5"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/objstore"
+)
+
+// NewNoConfirm gets the COS backend without testing it
+func NewNoConfirm(cfg *Config) (backend.RawReader, backend.RawWriter, backend.Compactor, error) {
+	rw, err := internalNew(cfg, false)
+	return rw, rw, rw, err
+}
+
+// New gets the COS backend
+func New(cfg *Config) (backend.RawReader, backend.RawWriter, backend.Compactor, error) {
+	rw, err := internalNew(cfg, true)
+	return rw, rw, rw, err
+}
+
+func internalNew(cfg *Config, confirm bool) (*objstore.ReaderWriter, error) {
+	if cfg == nil {
+		return nil, errors.New("config is nil")
+	}
+	if cfg.Bucket == "" || (cfg.Region == "" && cfg.Endpoint == "") {
+		return nil, errors.New("cos bucket and region or endpoint are required")
+	}
+
+	return newReaderWriter(cfg, bucketURL(cfg), confirm)
+}
+
+func newReaderWriter(cfg *Config, u *url.URL, confirm bool) (*objstore.ReaderWriter, error) {
+	transport, hedgedTransport, err := objstore.NewTransports(cfg.HedgeRequestsAt, cfg.HedgeRequestsUpTo)
+	if err != nil {
+		return nil, err
+	}
+
+	return objstore.New(objstore.Config{
+		Name:                  "cos",
+		Prefix:                cfg.Prefix,
+		ListBlocksConcurrency: cfg.ListBlocksConcurrency,
+	}, &bucket{
+		host:         u.Host,
+		client:       newClient(cfg, u, transport),
+		hedgedClient: newClient(cfg, u, hedgedTransport),
+	}, confirm)
+}
+
+func newClient(cfg *Config, u *url.URL, transport http.RoundTripper) *cos.Client {
+	return cos.NewClient(&cos.BaseURL{BucketURL: u}, &http.Client{
+		Transport: &cos.AuthorizationTransport{
+			SecretID:     cfg.SecretID,
+			SecretKey:    cfg.SecretKey.String(),
+			SessionToken: cfg.SessionToken.String(),
+			Transport:    transport,
+		},
+	})
+}
+
+// bucketURL returns the virtual hosted url of the bucket.
+func bucketURL(cfg *Config) *url.URL {
+	scheme := "https"
+	if cfg.Insecure {
+		scheme = "http"
+	}
+	return &url.URL{Scheme: scheme, Host: cfg.Bucket + "." + cfg.endpoint()}
+}
+
+// bucket implements objstore.Bucket with the COS SDK. Reads are sent with the hedged client.
+type bucket struct {
+	host         string
+	client       *cos.Client
+	hedgedClient *cos.Client
+}
+
+var _ objstore.Bucket = (*bucket)(nil)
+
+func (b *bucket) Put(ctx context.Context, object string, data io.Reader, size int64) error {
+	_, err := b.client.Object.Put(ctx, object, data, &cos.ObjectPutOptions{
+		ObjectPutHeaderOptions: &cos.ObjectPutHeaderOptions{ContentLength: size},
+	})
+	return err
+}
+
+func (b *bucket) Get(ctx context.Context, object string, offset, length int64) (io.ReadCloser, objstore.ObjectAttributes, error) {
+	opt := &cos.ObjectGetOptions{}
+	if length >= 0 {
+		opt.Range = fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+	}
+
+	resp, err := b.hedgedClient.Object.Get(ctx, object, opt)
+	if err != nil {
+		return nil, objstore.ObjectAttributes{}, objectError(err)
+	}
+
+	lastModified, err := http.ParseTime(resp.Header.Get("Last-Modified"))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, objstore.ObjectAttributes{}, fmt.Errorf("error parsing last modified time: %w", err)
+	}
+	return resp.Body, objstore.ObjectAttributes{Size: resp.ContentLength, LastModified: lastModified}, nil
+}
+
+func (b *bucket) Copy(ctx context.Context, src, dst string) error {
+	_, _, err := b.client.Object.Copy(ctx, dst, b.host+"/"+src, nil)
+	return objectError(err)
+}
+
+func (b *bucket) Delete(ctx context.Context, object string) error {
+	_, err := b.client.Object.Delete(ctx, object)
+	return objectError(err)
+}
+
+func (b *bucket) List(ctx context.Context, prefix, delimiter, marker string, maxKeys int) (objstore.ListResult, error) {
+	res, _, err := b.client.Bucket.Get(ctx, &cos.BucketGetOptions{
+		Prefix:    prefix,
+		Delimiter: delimiter,
+		Marker:    marker,
+		MaxKeys:   maxKeys,
+	})
+	if err != nil {
+		return objstore.ListResult{}, err
+	}
+
+	out := objstore.ListResult{
+		Objects:        make([]objstore.ObjectInfo, 0, len(res.Contents)),
+		CommonPrefixes: res.CommonPrefixes,
+		IsTruncated:    res.IsTruncated,
+		NextMarker:     res.NextMarker,
+	}
+	for _, o := range res.Contents {
+		lastModified, err := time.Parse(time.RFC3339, o.LastModified)
+		if err != nil {
+			return objstore.ListResult{}, fmt.Errorf("error parsing last modified time of %s: %w", o.Key, err)
+		}
+		out.Objects = append(out.Objects, objstore.ObjectInfo{Key: o.Key, LastModified: lastModified})
+	}
+	return out, nil
+}
+
+func (b *bucket) InitiateMultipartUpload(ctx context.Context, object string) (string, error) {
+	res, _, err := b.client.Object.InitiateMultipartUpload(ctx, object, nil)
+	if err != nil {
+		return "", err
+	}
+	return res.UploadID, nil
+}
+
+func (b *bucket) UploadPart(ctx context.Context, object, uploadID string, partNumber int, data []byte) (string, error) {
+	resp, err := b.client.Object.UploadPart(ctx, object, uploadID, partNumber, bytes.NewReader(data), &cos.ObjectUploadPartOptions{
+		ContentLength: int64(len(data)),
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+func (b *bucket) CompleteMultipartUpload(ctx context.Context, object, uploadID string, parts []objstore.Part) error {
+	opt := &cos.CompleteMultipartUploadOptions{Parts: make([]cos.Object, 0, len(parts))}
+	for _, p := range parts {
+		opt.Parts = append(opt.Parts, cos.Object{PartNumber: p.PartNumber, ETag: p.ETag})
+	}
+
+	_, _, err := b.client.Object.CompleteMultipartUpload(ctx, object, uploadID, opt)
+	return err
+}
+
+// objectError returns errors of requests for objects that don't exist as backend.ErrDoesNotExist.
+func objectError(err error) error {
+	if cos.IsNotFoundError(err) {
+		return backend.ErrDoesNotExist
+	}
+	return err
+}
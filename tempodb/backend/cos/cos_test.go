@@ -0,0 +1,46 @@
+package cos
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend/objstore/objstoretest"
+)
+
+func TestBucketURL(t *testing.T) {
+	cfg := &Config{Bucket: "bucket-1250000000", Region: "ap-beijing"}
+	require.Equal(t, "https://bucket-1250000000.cos.ap-beijing.myqcloud.com", bucketURL(cfg).String())
+
+	cfg.Endpoint = "cos.example.com"
+	cfg.Insecure = true
+	require.Equal(t, "http://bucket-1250000000.cos.example.com", bucketURL(cfg).String())
+}
+
+func TestRoundTrip(t *testing.T) {
+	s := objstoretest.NewServer(t, "", "x-cos-copy-source", "q-sign-algorithm=sha1&q-ak=id&")
+	s.RequireHeader("x-cos-security-token", "token")
+
+	u, err := url.Parse(s.URL)
+	require.NoError(t, err)
+
+	// the bucket is addressed by the host, which can't be resolved for the test server
+	rw, err := newReaderWriter(&Config{
+		Bucket:       "bucket-1250000000",
+		Prefix:       "prefix",
+		Region:       "ap-beijing",
+		SecretID:     "id",
+		SecretKey:    flagext.SecretWithValue("secret"),
+		SessionToken: flagext.SecretWithValue("token"),
+	}, u, true)
+	require.NoError(t, err)
+
+	objstoretest.RoundTrip(t, s, rw, rw, rw)
+}
+
+func TestInvalidConfig(t *testing.T) {
+	_, _, _, err := New(&Config{Bucket: "bucket-1250000000"})
+	require.EqualError(t, err, "cos bucket and region or endpoint are required")
+}
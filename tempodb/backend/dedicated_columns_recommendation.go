@@ -0,0 +1,52 @@
+package backend
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DedicatedColumnsRecommendation is written to /<tenantid>/dedicated-columns-recommendation.json by the compactors.
+// It lists the attributes of the recent blocks of a tenant that should be stored in dedicated columns. Columns
+// uses the same format as the parquet_dedicated_columns overrides and can be copied into the overrides of the
+// tenant as is.
+type DedicatedColumnsRecommendation struct {
+	GeneratedAt    time.Time `json:"generated_at"`
+	BlocksAnalyzed int       `json:"blocks_analyzed"`
+	// TotalBytes is the size of the string values of all attributes that were analyzed
+	TotalBytes uint64                       `json:"total_bytes"`
+	Columns    []RecommendedDedicatedColumn `json:"parquet_dedicated_columns"`
+	Attributes []RecommendedAttributeSize   `json:"attributes,omitempty"`
+}
+
+// RecommendedDedicatedColumn is a recommended dedicated column. The fields are named like in the overrides.
+type RecommendedDedicatedColumn struct {
+	Scope DedicatedColumnScope `json:"scope"`
+	Name  string               `json:"name"`
+	Type  DedicatedColumnType  `json:"type"`
+}
+
+// RecommendedAttributeSize is the size of the string values of an attribute in the analyzed blocks.
+type RecommendedAttributeSize struct {
+	Scope DedicatedColumnScope `json:"scope"`
+	Name  string               `json:"name"`
+	Bytes uint64               `json:"bytes"`
+	// Share is the fraction of the total bytes of the scope
+	Share float64 `json:"share"`
+}
+
+// DedicatedColumns returns the recommended columns as dedicated columns of a block.
+func (r *DedicatedColumnsRecommendation) DedicatedColumns() DedicatedColumns {
+	dcs := make(DedicatedColumns, 0, len(r.Columns))
+	for _, c := range r.Columns {
+		dcs = append(dcs, DedicatedColumn{Scope: c.Scope, Name: c.Name, Type: c.Type})
+	}
+	return dcs
+}
+
+func (r *DedicatedColumnsRecommendation) marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func (r *DedicatedColumnsRecommendation) unmarshal(buffer []byte) error {
+	return json.Unmarshal(buffer, r)
+}
@@ -31,7 +31,7 @@ func (rw *readerWriter) MarkBlockCompacted(blockID uuid.UUID, tenantID string) e
 		return err
 	}
 
-	return src.Delete(ctx)
+	return rw.object(rw.bucket, metaFilename, rw.deleteRetry).Delete(ctx)
 }
 
 func (rw *readerWriter) ClearBlock(blockID uuid.UUID, tenantID string) error {
@@ -44,7 +44,7 @@ func (rw *readerWriter) ClearBlock(blockID uuid.UUID, tenantID string) error {
 	}
 
 	ctx := context.TODO()
-	iter := rw.bucket.Objects(ctx, &storage.Query{
+	iter := rw.listBucket().Objects(ctx, &storage.Query{
 		Prefix:   backend.RootPath(blockID, tenantID, rw.cfg.Prefix),
 		Versions: false,
 	})
@@ -58,8 +58,7 @@ func (rw *readerWriter) ClearBlock(blockID uuid.UUID, tenantID string) error {
 			return err
 		}
 
-		o := rw.bucket.Object(attrs.Name)
-		err = o.Delete(ctx)
+		err = rw.object(rw.bucket, attrs.Name, rw.deleteRetry).Delete(ctx)
 		if err != nil {
 			return err
 		}
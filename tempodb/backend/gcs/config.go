@@ -2,11 +2,21 @@ package gcs
 
 import (
 	"flag"
+	"fmt"
 	"time"
 
+	"cloud.google.com/go/storage"
+	"github.com/googleapis/gax-go/v2"
+
 	"github.com/grafana/tempo/pkg/util"
 )
 
+const (
+	RetryPolicyIdempotent = "idempotent"
+	RetryPolicyAlways     = "always"
+	RetryPolicyNever      = "never"
+)
+
 type Config struct {
 	BucketName            string            `yaml:"bucket_name"`
 	Prefix                string            `yaml:"prefix"`
@@ -18,17 +28,82 @@ type Config struct {
 	ObjectCacheControl    string            `yaml:"object_cache_control"`
 	ObjectMetadata        map[string]string `yaml:"object_metadata"`
 	ListBlocksConcurrency int               `yaml:"list_blocks_concurrency"`
+
+	Retry RetryPolicies `yaml:"retry"`
+
+	TenantIndexConsistencyChecks  int           `yaml:"tenant_index_consistency_checks"`
+	TenantIndexConsistencyBackoff time.Duration `yaml:"tenant_index_consistency_backoff"`
+}
+
+// RetryPolicies configures retries separately for each class of operation. Unset fields fall back to the
+// defaults of the GCS client library.
+type RetryPolicies struct {
+	Read   RetryConfig `yaml:"read"`
+	Write  RetryConfig `yaml:"write"`
+	List   RetryConfig `yaml:"list"`
+	Delete RetryConfig `yaml:"delete"`
+}
+
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts including the first one. 0 retries until the context is done.
+	MaxAttempts    int           `yaml:"max_attempts"`
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+	Multiplier     float64       `yaml:"multiplier"`
+	// Policy is one of idempotent, always or never. idempotent only retries operations that are safe to repeat,
+	// always also retries unconditional writes relying on the idempotency token sent with every attempt.
+	Policy string `yaml:"policy"`
 }
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	f.StringVar(&cfg.BucketName, util.PrefixConfig(prefix, "gcs.bucket"), "", "gcs bucket to store traces in.")
 	f.StringVar(&cfg.Prefix, util.PrefixConfig(prefix, "gcs.prefix"), "", "gcs bucket prefix to store traces in.")
 	f.IntVar(&cfg.ListBlocksConcurrency, util.PrefixConfig(prefix, "gcs.list_blocks_concurrency"), 3, "number of concurrent list calls to make to backend")
+	f.IntVar(&cfg.TenantIndexConsistencyChecks, util.PrefixConfig(prefix, "gcs.tenant_index_consistency_checks"), 0, "number of times to read back a written tenant index until the new generation is visible. 0 disables the check.")
 	cfg.ChunkBufferSize = 10 * 1024 * 1024
 	cfg.HedgeRequestsUpTo = 2
+	cfg.TenantIndexConsistencyBackoff = 500 * time.Millisecond
 }
 
 func (cfg *Config) PathMatches(other *Config) bool {
 	// GCS bucket names are globally unique
 	return cfg.BucketName == other.BucketName && cfg.Prefix == other.Prefix
 }
+
+// options converts the config into retry options for the GCS client. No options are returned for an empty
+// config so the client defaults apply.
+func (cfg RetryConfig) options() ([]storage.RetryOption, error) {
+	var opts []storage.RetryOption
+
+	switch cfg.Policy {
+	case "":
+	case RetryPolicyIdempotent:
+		opts = append(opts, storage.WithPolicy(storage.RetryIdempotent))
+	case RetryPolicyAlways:
+		opts = append(opts, storage.WithPolicy(storage.RetryAlways))
+	case RetryPolicyNever:
+		opts = append(opts, storage.WithPolicy(storage.RetryNever))
+	default:
+		return nil, fmt.Errorf("unknown retry policy %q, must be one of %s, %s or %s", cfg.Policy, RetryPolicyIdempotent, RetryPolicyAlways, RetryPolicyNever)
+	}
+
+	if cfg.MaxAttempts < 0 {
+		return nil, fmt.Errorf("max_attempts must not be negative")
+	}
+	if cfg.MaxAttempts > 0 {
+		opts = append(opts, storage.WithMaxAttempts(cfg.MaxAttempts))
+	}
+
+	if cfg.Multiplier != 0 && cfg.Multiplier < 1 {
+		return nil, fmt.Errorf("multiplier must be at least 1")
+	}
+	if cfg.InitialBackoff != 0 || cfg.MaxBackoff != 0 || cfg.Multiplier != 0 {
+		opts = append(opts, storage.WithBackoff(gax.Backoff{
+			Initial:    cfg.InitialBackoff,
+			Max:        cfg.MaxBackoff,
+			Multiplier: cfg.Multiplier,
+		}))
+	}
+
+	return opts, nil
+}
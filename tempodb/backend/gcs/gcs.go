@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -33,6 +34,11 @@ type readerWriter struct {
 	cfg          *Config
 	bucket       *storage.BucketHandle
 	hedgedBucket *storage.BucketHandle
+
+	readRetry   []storage.RetryOption
+	writeRetry  []storage.RetryOption
+	listRetry   []storage.RetryOption
+	deleteRetry []storage.RetryOption
 }
 
 var (
@@ -77,6 +83,26 @@ func NewVersionedReaderWriter(cfg *Config, confirmVersioning bool) (backend.Vers
 func internalNew(cfg *Config, confirm bool) (*readerWriter, error) {
 	ctx := context.Background()
 
+	rw := &readerWriter{
+		cfg: cfg,
+	}
+
+	var err error
+	for _, r := range []struct {
+		name string
+		cfg  RetryConfig
+		opts *[]storage.RetryOption
+	}{
+		{"read", cfg.Retry.Read, &rw.readRetry},
+		{"write", cfg.Retry.Write, &rw.writeRetry},
+		{"list", cfg.Retry.List, &rw.listRetry},
+		{"delete", cfg.Retry.Delete, &rw.deleteRetry},
+	} {
+		if *r.opts, err = r.cfg.options(); err != nil {
+			return nil, fmt.Errorf("invalid %s retry config: %w", r.name, err)
+		}
+	}
+
 	bucket, err := createBucket(ctx, cfg, false)
 	if err != nil {
 		return nil, fmt.Errorf("creating bucket: %w", err)
@@ -94,11 +120,8 @@ func internalNew(cfg *Config, confirm bool) (*readerWriter, error) {
 		}
 	}
 
-	rw := &readerWriter{
-		cfg:          cfg,
-		bucket:       bucket,
-		hedgedBucket: hedgedBucket,
-	}
+	rw.bucket = bucket
+	rw.hedgedBucket = hedgedBucket
 
 	return rw, nil
 }
@@ -111,7 +134,8 @@ func (rw *readerWriter) Write(ctx context.Context, name string, keypath backend.
 
 	span.SetTag("object", name)
 
-	w := rw.writer(derivedCtx, backend.ObjectFileName(keypath, name), nil)
+	objName := backend.ObjectFileName(keypath, name)
+	w := rw.writer(derivedCtx, objName, nil)
 
 	_, err := io.Copy(w, data)
 	if err != nil {
@@ -120,7 +144,16 @@ func (rw *readerWriter) Write(ctx context.Context, name string, keypath backend.
 		return fmt.Errorf("failed to write: %w", err)
 	}
 
-	return w.Close()
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	if name == backend.TenantIndexName && rw.cfg.TenantIndexConsistencyChecks > 0 {
+		return rw.confirmGeneration(derivedCtx, objName, w.Attrs().Generation)
+	}
+
+	return nil
 }
 
 // Append implements backend.Writer
@@ -157,7 +190,7 @@ func (rw *readerWriter) CloseAppend(_ context.Context, tracker backend.AppendTra
 }
 
 func (rw *readerWriter) Delete(ctx context.Context, name string, keypath backend.KeyPath, _ *backend.CacheInfo) error {
-	return readError(rw.bucket.Object(backend.ObjectFileName(keypath, name)).Delete(ctx))
+	return readError(rw.object(rw.bucket, backend.ObjectFileName(keypath, name), rw.deleteRetry).Delete(ctx))
 }
 
 // List implements backend.Reader
@@ -167,7 +200,7 @@ func (rw *readerWriter) List(ctx context.Context, keypath backend.KeyPath) ([]st
 	if len(prefix) > 0 {
 		prefix = prefix + "/"
 	}
-	iter := rw.bucket.Objects(ctx, &storage.Query{
+	iter := rw.listBucket().Objects(ctx, &storage.Query{
 		Prefix:    prefix,
 		Delimiter: "/",
 		Versions:  false,
@@ -237,7 +270,7 @@ func (rw *readerWriter) ListBlocks(ctx context.Context, tenant string) ([]uuid.U
 				query.EndOffset = prefix + max.String()
 			}
 
-			iter := rw.bucket.Objects(ctx, query)
+			iter := rw.listBucket().Objects(ctx, query)
 
 			for {
 				if ctx.Err() != nil {
@@ -316,7 +349,7 @@ func (rw *readerWriter) Find(ctx context.Context, keypath backend.KeyPath, f bac
 		prefix = prefix + "/"
 	}
 
-	iter := rw.bucket.Objects(ctx, &storage.Query{
+	iter := rw.listBucket().Objects(ctx, &storage.Query{
 		Delimiter: "",
 		Prefix:    prefix,
 		Versions:  false,
@@ -406,7 +439,7 @@ func (rw *readerWriter) WriteVersioned(ctx context.Context, name string, keypath
 }
 
 func (rw *readerWriter) DeleteVersioned(ctx context.Context, name string, keypath backend.KeyPath, version backend.Version) error {
-	o := rw.bucket.Object(backend.ObjectFileName(keypath, name))
+	o := rw.object(rw.bucket, backend.ObjectFileName(keypath, name), rw.deleteRetry)
 
 	preconditions, err := createPreconditions(version)
 	if err != nil {
@@ -437,7 +470,14 @@ func toVersion(generation int64) backend.Version {
 }
 
 func (rw *readerWriter) writer(ctx context.Context, name string, conditions *storage.Conditions) *storage.Writer {
-	o := rw.bucket.Object(name)
+	retry := rw.writeRetry
+	if rw.cfg.Retry.Write.MaxAttempts > 0 {
+		// uploads are retried by the underlying transport which ignores the max attempts option, bound them
+		// with an error func that is scoped to this writer instead
+		retry = append(retry[:len(retry):len(retry)], maxAttemptsErrorFunc(rw.cfg.Retry.Write.MaxAttempts))
+	}
+
+	o := rw.object(rw.bucket, name, retry)
 	if conditions != nil {
 		o = o.If(*conditions)
 	}
@@ -456,7 +496,7 @@ func (rw *readerWriter) writer(ctx context.Context, name string, conditions *sto
 }
 
 func (rw *readerWriter) readAll(ctx context.Context, name string) ([]byte, *storage.ReaderObjectAttrs, error) {
-	r, err := rw.hedgedBucket.Object(name).NewReader(ctx)
+	r, err := rw.object(rw.hedgedBucket, name, rw.readRetry).NewReader(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -471,7 +511,7 @@ func (rw *readerWriter) readAll(ctx context.Context, name string) ([]byte, *stor
 }
 
 func (rw *readerWriter) readRange(ctx context.Context, name string, offset int64, buffer []byte) error {
-	r, err := rw.hedgedBucket.Object(name).NewRangeReader(ctx, offset, int64(len(buffer)))
+	r, err := rw.object(rw.hedgedBucket, name, rw.readRetry).NewRangeReader(ctx, offset, int64(len(buffer)))
 	if err != nil {
 		return err
 	}
@@ -490,6 +530,62 @@ func (rw *readerWriter) readRange(ctx context.Context, name string, offset int64
 	return err
 }
 
+// object returns a handle for the named object using the given retry options.
+func (rw *readerWriter) object(bucket *storage.BucketHandle, name string, retry []storage.RetryOption) *storage.ObjectHandle {
+	o := bucket.Object(name)
+	if len(retry) > 0 {
+		o = o.Retryer(retry...)
+	}
+	return o
+}
+
+// maxAttemptsErrorFunc returns a retry option that stops retrying after the given number of attempts. The returned
+// option counts attempts and must not be shared between operations.
+func maxAttemptsErrorFunc(maxAttempts int) storage.RetryOption {
+	attempts := 0
+	return storage.WithErrorFunc(func(err error) bool {
+		attempts++
+		return attempts < maxAttempts && storage.ShouldRetry(err)
+	})
+}
+
+// listBucket returns a bucket handle to list objects with.
+func (rw *readerWriter) listBucket() *storage.BucketHandle {
+	if len(rw.listRetry) > 0 {
+		return rw.bucket.Retryer(rw.listRetry...)
+	}
+	return rw.bucket
+}
+
+// confirmGeneration polls the attributes of the object until the given generation, or a newer one, is visible.
+// This guards readers against stale tenant indexes when the bucket is replicated, i.e. dual-region buckets fronted
+// by caches or hedged requests.
+func (rw *readerWriter) confirmGeneration(ctx context.Context, name string, generation int64) error {
+	var (
+		attrs *storage.ObjectAttrs
+		err   error
+	)
+	for i := 0; i < rw.cfg.TenantIndexConsistencyChecks; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(rw.cfg.TenantIndexConsistencyBackoff):
+			}
+		}
+
+		attrs, err = rw.object(rw.hedgedBucket, name, rw.readRetry).Attrs(ctx)
+		if err == nil && attrs.Generation >= generation {
+			return nil
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("confirming generation %d of %s: %w", generation, name, err)
+	}
+	return fmt.Errorf("generation %d of %s not visible after %d checks, last seen %d", generation, name, rw.cfg.TenantIndexConsistencyChecks, attrs.Generation)
+}
+
 func createBucket(ctx context.Context, cfg *Config, hedge bool) (*storage.BucketHandle, error) {
 	// start with default transport
 	customTransport := http.DefaultTransport.(*http.Transport).Clone()
@@ -178,6 +178,178 @@ func TestRetry_MarkBlockCompacted(t *testing.T) {
 	require.Equal(t, int32(2), atomic.LoadInt32(&count))
 }
 
+func TestRetry_Write(t *testing.T) {
+	tests := []struct {
+		name          string
+		retry         RetryConfig
+		expectedCalls int32
+		expectErr     bool
+	}{
+		{
+			name:          "unconditional writes are not retried by default",
+			expectedCalls: 1,
+			expectErr:     true,
+		},
+		{
+			name:          "never",
+			retry:         RetryConfig{Policy: RetryPolicyNever},
+			expectedCalls: 1,
+			expectErr:     true,
+		},
+		{
+			name:          "always",
+			retry:         RetryConfig{Policy: RetryPolicyAlways, InitialBackoff: time.Millisecond},
+			expectedCalls: 3,
+		},
+		{
+			name:          "always with max attempts",
+			retry:         RetryConfig{Policy: RetryPolicyAlways, InitialBackoff: time.Millisecond, MaxAttempts: 2},
+			expectedCalls: 2,
+			expectErr:     true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int32
+			tokens := map[string]struct{}{}
+			server := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if !strings.HasPrefix(r.URL.Path, "/upload/") {
+					_, _ = w.Write([]byte(`{}`))
+					return
+				}
+
+				tokens[r.Header.Get("x-goog-gcs-idempotency-token")] = struct{}{}
+
+				// First two requests fail, third succeeds.
+				if atomic.AddInt32(&calls, 1) <= 2 {
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				_, _ = w.Write([]byte(`{}`))
+			})
+
+			_, w, _, err := New(&Config{
+				BucketName:      "blerg",
+				Insecure:        true,
+				Endpoint:        server.URL,
+				ChunkBufferSize: 1024,
+				Retry:           RetryPolicies{Write: tc.retry},
+			})
+			require.NoError(t, err)
+
+			// the body is buffered in a single chunk so it can be replayed on retries
+			err = w.Write(context.Background(), "object", []string{"test"}, bytes.NewReader([]byte("data")), 4, nil)
+			if tc.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expectedCalls, atomic.LoadInt32(&calls))
+			// every attempt of the same write carries the same idempotency token
+			require.Len(t, tokens, 1)
+		})
+	}
+}
+
+func TestRetryConfigInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		retry RetryPolicies
+	}{
+		{
+			name:  "unknown policy",
+			retry: RetryPolicies{Read: RetryConfig{Policy: "sometimes"}},
+		},
+		{
+			name:  "negative attempts",
+			retry: RetryPolicies{List: RetryConfig{MaxAttempts: -1}},
+		},
+		{
+			name:  "multiplier below one",
+			retry: RetryPolicies{Delete: RetryConfig{Multiplier: 0.5}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, err := NewNoConfirm(&Config{
+				BucketName: "blerg",
+				Insecure:   true,
+				Retry:      tc.retry,
+			})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestTenantIndexConsistencyCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		checks    int
+		expectErr bool
+	}{
+		{
+			name:   "disabled",
+			checks: 0,
+		},
+		{
+			name:   "visible within checks",
+			checks: 3,
+		},
+		{
+			name:      "not visible within checks",
+			checks:    2,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var attrCalls int32
+			server := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case strings.HasPrefix(r.URL.Path, "/upload/"):
+					_, _ = w.Write([]byte(`{"generation": "5"}`))
+				case r.URL.Path == "/b/blerg/o/tenant/"+backend.TenantIndexName:
+					// the new generation becomes visible on the third read
+					generation := "4"
+					if atomic.AddInt32(&attrCalls, 1) >= 3 {
+						generation = "5"
+					}
+					_, _ = w.Write([]byte(`{"generation": "` + generation + `"}`))
+				default:
+					_, _ = w.Write([]byte(`{}`))
+				}
+			})
+
+			_, w, _, err := New(&Config{
+				BucketName:                    "blerg",
+				Insecure:                      true,
+				Endpoint:                      server.URL,
+				TenantIndexConsistencyChecks:  tc.checks,
+				TenantIndexConsistencyBackoff: time.Millisecond,
+			})
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			err = w.Write(ctx, backend.TenantIndexName, []string{"tenant"}, bytes.NewReader([]byte{}), 0, nil)
+			if tc.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, int32(tc.checks), atomic.LoadInt32(&attrCalls))
+
+			// other objects are never checked
+			atomic.StoreInt32(&attrCalls, 0)
+			err = w.Write(ctx, "object", []string{"tenant"}, bytes.NewReader([]byte{}), 0, nil)
+			require.NoError(t, err)
+			require.Equal(t, int32(0), atomic.LoadInt32(&attrCalls))
+		})
+	}
+}
+
 func fakeServer(t *testing.T, returnIn time.Duration, counter *int32) *httptest.Server {
 	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		time.Sleep(returnIn)
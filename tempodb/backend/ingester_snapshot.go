@@ -0,0 +1,67 @@
+package backend
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	// Folder of the ingester snapshots of a tenant.
+	IngesterSnapshotsFolder = "ingester-snapshots"
+	// File name of the ingester snapshot manifest.
+	IngesterSnapshotName = "snapshot.json"
+)
+
+// IngesterSnapshot is written to /<tenantid>/ingester-snapshots/<ingesterid>/snapshot.json after the WAL files
+// of the tenant have been uploaded next to it. Only the files listed in the snapshot are restored so a partially
+// uploaded snapshot is never used.
+type IngesterSnapshot struct {
+	CreatedTime time.Time              `json:"created_time"`
+	Files       []IngesterSnapshotFile `json:"files"`
+}
+
+// IngesterSnapshotFile is a file of the WAL. Path is relative to the WAL folder and uses forward slashes.
+type IngesterSnapshotFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+func NewIngesterSnapshot(files []IngesterSnapshotFile) *IngesterSnapshot {
+	return &IngesterSnapshot{
+		CreatedTime: time.Now(),
+		Files:       files,
+	}
+}
+
+// Size returns the total size of the files in the snapshot.
+func (s *IngesterSnapshot) Size() int64 {
+	var size int64
+	for _, f := range s.Files {
+		size += f.Size
+	}
+	return size
+}
+
+func (s *IngesterSnapshot) marshal() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+func (s *IngesterSnapshot) unmarshal(buffer []byte) error {
+	return json.Unmarshal(buffer, s)
+}
+
+// KeyPathForIngesterSnapshot returns the keypath of the snapshot of a tenant taken by an ingester.
+func KeyPathForIngesterSnapshot(tenantID, ingesterID string) KeyPath {
+	return []string{tenantID, IngesterSnapshotsFolder, ingesterID}
+}
+
+// keyPathForIngesterSnapshotFile returns the keypath and name of a file of a snapshot.
+func keyPathForIngesterSnapshotFile(tenantID, ingesterID, filePath string) (KeyPath, string) {
+	keypath := KeyPathForIngesterSnapshot(tenantID, ingesterID)
+	if dir := path.Dir(filePath); dir != "." {
+		keypath = append(keypath, strings.Split(dir, "/")...)
+	}
+	return keypath, path.Base(filePath)
+}
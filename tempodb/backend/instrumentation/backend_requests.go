@@ -0,0 +1,174 @@
+package instrumentation
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/util/tracing"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+const (
+	operationGet    = "GET"
+	operationPut    = "PUT"
+	operationList   = "LIST"
+	operationDelete = "DELETE"
+
+	statusSuccess  = "success"
+	statusNotFound = "not_found"
+	statusError    = "error"
+)
+
+var (
+	tenantRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "backend_tenant_requests_total",
+		Help:      "Total number of backend storage requests by tenant and operation.",
+	}, []string{"backend", "tenant", "operation", "status"})
+	tenantRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tempodb",
+		Name:      "backend_tenant_request_duration_seconds",
+		Help:      "Time spent doing backend storage requests by tenant and operation.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 4, 6),
+	}, []string{"backend", "tenant", "operation"})
+	tenantRequestBytes = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tempodb",
+		Name:      "backend_tenant_request_bytes",
+		Help:      "Size of the objects read and written by backend storage requests by tenant and operation.",
+		Buckets:   prometheus.ExponentialBuckets(1024, 4, 10),
+	}, []string{"backend", "tenant", "operation"})
+)
+
+type tenantReader struct {
+	backend string
+	next    backend.RawReader
+}
+
+type tenantWriter struct {
+	backend string
+	next    backend.RawWriter
+}
+
+// NewTenantRawReaderWriter wraps the raw reader and writer of a backend with per tenant and operation request
+// metrics. The tenant is the first element of the keypath of a request. Observations carry the trace id of the
+// request as exemplar.
+func NewTenantRawReaderWriter(backendName string, r backend.RawReader, w backend.RawWriter) (backend.RawReader, backend.RawWriter) {
+	return &tenantReader{backend: backendName, next: r}, &tenantWriter{backend: backendName, next: w}
+}
+
+func (r *tenantReader) List(ctx context.Context, keypath backend.KeyPath) ([]string, error) {
+	start := time.Now()
+	objects, err := r.next.List(ctx, keypath)
+	observe(ctx, r.backend, tenantOf(keypath), operationList, start, -1, err)
+	return objects, err
+}
+
+func (r *tenantReader) ListBlocks(ctx context.Context, tenant string) ([]uuid.UUID, []uuid.UUID, error) {
+	start := time.Now()
+	blockIDs, compactedBlockIDs, err := r.next.ListBlocks(ctx, tenant)
+	observe(ctx, r.backend, tenant, operationList, start, -1, err)
+	return blockIDs, compactedBlockIDs, err
+}
+
+func (r *tenantReader) Find(ctx context.Context, keypath backend.KeyPath, f backend.FindFunc) error {
+	start := time.Now()
+	err := r.next.Find(ctx, keypath, f)
+	observe(ctx, r.backend, tenantOf(keypath), operationList, start, -1, err)
+	return err
+}
+
+func (r *tenantReader) Read(ctx context.Context, name string, keypath backend.KeyPath, cacheInfo *backend.CacheInfo) (io.ReadCloser, int64, error) {
+	start := time.Now()
+	rc, size, err := r.next.Read(ctx, name, keypath, cacheInfo)
+	observe(ctx, r.backend, tenantOf(keypath), operationGet, start, size, err)
+	return rc, size, err
+}
+
+func (r *tenantReader) ReadRange(ctx context.Context, name string, keypath backend.KeyPath, offset uint64, buffer []byte, cacheInfo *backend.CacheInfo) error {
+	start := time.Now()
+	err := r.next.ReadRange(ctx, name, keypath, offset, buffer, cacheInfo)
+	observe(ctx, r.backend, tenantOf(keypath), operationGet, start, int64(len(buffer)), err)
+	return err
+}
+
+func (r *tenantReader) Shutdown() {
+	r.next.Shutdown()
+}
+
+func (w *tenantWriter) Write(ctx context.Context, name string, keypath backend.KeyPath, data io.Reader, size int64, cacheInfo *backend.CacheInfo) error {
+	start := time.Now()
+	err := w.next.Write(ctx, name, keypath, data, size, cacheInfo)
+	observe(ctx, w.backend, tenantOf(keypath), operationPut, start, size, err)
+	return err
+}
+
+func (w *tenantWriter) Append(ctx context.Context, name string, keypath backend.KeyPath, tracker backend.AppendTracker, buffer []byte) (backend.AppendTracker, error) {
+	start := time.Now()
+	tracker, err := w.next.Append(ctx, name, keypath, tracker, buffer)
+	observe(ctx, w.backend, tenantOf(keypath), operationPut, start, int64(len(buffer)), err)
+	return tracker, err
+}
+
+func (w *tenantWriter) CloseAppend(ctx context.Context, tracker backend.AppendTracker) error {
+	return w.next.CloseAppend(ctx, tracker)
+}
+
+func (w *tenantWriter) Delete(ctx context.Context, name string, keypath backend.KeyPath, cacheInfo *backend.CacheInfo) error {
+	start := time.Now()
+	err := w.next.Delete(ctx, name, keypath, cacheInfo)
+	observe(ctx, w.backend, tenantOf(keypath), operationDelete, start, -1, err)
+	return err
+}
+
+// tenantOf returns the tenant of a keypath, the keypaths of the cluster wide objects have no tenant
+func tenantOf(keypath backend.KeyPath) string {
+	if len(keypath) == 0 {
+		return ""
+	}
+	return keypath[0]
+}
+
+// observe records a request. Sizes are only recorded for successful requests that transfer an object, a negative
+// size skips them.
+func observe(ctx context.Context, backendName, tenant, operation string, start time.Time, size int64, err error) {
+	status := statusSuccess
+	switch {
+	case errors.Is(err, backend.ErrDoesNotExist):
+		status = statusNotFound
+	case err != nil:
+		status = statusError
+	}
+
+	var exemplar prometheus.Labels
+	if traceID, ok := tracing.ExtractTraceID(ctx); ok {
+		exemplar = prometheus.Labels{"traceID": traceID}
+	}
+
+	addWithExemplar(tenantRequests.WithLabelValues(backendName, tenant, operation, status), exemplar)
+	observeWithExemplar(tenantRequestDuration.WithLabelValues(backendName, tenant, operation), time.Since(start).Seconds(), exemplar)
+	if err == nil && size >= 0 {
+		observeWithExemplar(tenantRequestBytes.WithLabelValues(backendName, tenant, operation), float64(size), exemplar)
+	}
+}
+
+func addWithExemplar(c prometheus.Counter, exemplar prometheus.Labels) {
+	if adder, ok := c.(prometheus.ExemplarAdder); ok && exemplar != nil {
+		adder.AddWithExemplar(1, exemplar)
+		return
+	}
+	c.Inc()
+}
+
+func observeWithExemplar(o prometheus.Observer, v float64, exemplar prometheus.Labels) {
+	if eo, ok := o.(prometheus.ExemplarObserver); ok && exemplar != nil {
+		eo.ObserveWithExemplar(v, exemplar)
+		return
+	}
+	o.Observe(v)
+}
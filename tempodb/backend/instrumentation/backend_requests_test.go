@@ -0,0 +1,47 @@
+package instrumentation
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+)
+
+func TestTenantRawReaderWriter(t *testing.T) {
+	rawR, rawW, _, err := local.New(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+
+	r, w := NewTenantRawReaderWriter("test", rawR, rawW)
+
+	ctx := context.Background()
+	keypath := backend.KeyPath{"tenant-1", "block"}
+	data := []byte("object")
+
+	require.NoError(t, w.Write(ctx, "object", keypath, bytes.NewReader(data), int64(len(data)), nil))
+
+	buffer := make([]byte, 3)
+	require.NoError(t, r.ReadRange(ctx, "object", keypath, 0, buffer, nil))
+
+	_, _, err = r.Read(ctx, "missing", keypath, nil)
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+
+	_, err = r.List(ctx, backend.KeyPath{"tenant-1"})
+	require.NoError(t, err)
+
+	require.NoError(t, w.Delete(ctx, "object", keypath, nil))
+
+	require.Equal(t, 1.0, testutil.ToFloat64(tenantRequests.WithLabelValues("test", "tenant-1", operationPut, statusSuccess)))
+	require.Equal(t, 1.0, testutil.ToFloat64(tenantRequests.WithLabelValues("test", "tenant-1", operationGet, statusSuccess)))
+	require.Equal(t, 1.0, testutil.ToFloat64(tenantRequests.WithLabelValues("test", "tenant-1", operationGet, statusNotFound)))
+	require.Equal(t, 1.0, testutil.ToFloat64(tenantRequests.WithLabelValues("test", "tenant-1", operationList, statusSuccess)))
+	require.Equal(t, 1.0, testutil.ToFloat64(tenantRequests.WithLabelValues("test", "tenant-1", operationDelete, statusSuccess)))
+
+	// sizes of the objects written and read, failed requests aren't recorded
+	require.Equal(t, 2, testutil.CollectAndCount(tenantRequestBytes))
+	require.Equal(t, 4, testutil.CollectAndCount(tenantRequestDuration))
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
 
 	"github.com/google/uuid"
 	"github.com/grafana/tempo/tempodb/backend"
@@ -28,10 +29,12 @@ func (rw *Backend) ClearBlock(blockID uuid.UUID, tenantID string) error {
 		return errors.New("empty block id")
 	}
 
-	path := rw.rootPath(backend.KeyPathForBlock(blockID, tenantID))
-	err := os.RemoveAll(path)
-	if err != nil {
-		return fmt.Errorf("failed to remove keypath for block %s: %w", path, err)
+	for _, root := range rw.roots {
+		path := filepath.Join(root, filepath.Join(backend.KeyPathForBlock(blockID, tenantID)...))
+		err := os.RemoveAll(path)
+		if err != nil {
+			return fmt.Errorf("failed to remove keypath for block %s: %w", path, err)
+		}
 	}
 
 	return nil
@@ -2,18 +2,44 @@ package local
 
 import (
 	"flag"
+	"slices"
+
+	"github.com/grafana/dskit/flagext"
 
 	"github.com/grafana/tempo/pkg/util"
 )
 
 type Config struct {
 	Path string `yaml:"path"`
+	// Paths are additional folders, for example on other disks, that blocks are spread across. Objects that
+	// don't belong to a block are always stored in the first folder.
+	Paths []string `yaml:"paths"`
 }
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	f.StringVar(&cfg.Path, util.PrefixConfig(prefix, "local.path"), "", "path to store traces at.")
+	f.Var((*flagext.StringSlice)(&cfg.Paths), util.PrefixConfig(prefix, "local.paths"), "additional paths to spread blocks across, for example on other disks. Can be repeated.")
 }
 
 func (cfg *Config) PathMatches(other *Config) bool {
-	return cfg.Path == other.Path
+	for _, p := range cfg.roots() {
+		if slices.Contains(other.roots(), p) {
+			return true
+		}
+	}
+	return false
+}
+
+// roots returns all folders of the backend: path followed by paths.
+func (cfg *Config) roots() []string {
+	roots := make([]string, 0, len(cfg.Paths)+1)
+	if cfg.Path != "" || len(cfg.Paths) == 0 {
+		roots = append(roots, cfg.Path)
+	}
+	for _, p := range cfg.Paths {
+		if !slices.Contains(roots, p) {
+			roots = append(roots, p)
+		}
+	}
+	return roots
 }
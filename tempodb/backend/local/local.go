@@ -6,6 +6,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/google/uuid"
@@ -15,7 +16,8 @@ import (
 )
 
 type Backend struct {
-	cfg *Config
+	cfg   *Config
+	roots []string
 }
 
 var (
@@ -26,13 +28,17 @@ var (
 )
 
 func NewBackend(cfg *Config) (*Backend, error) {
-	err := os.MkdirAll(cfg.Path, os.ModePerm)
-	if err != nil {
-		return nil, err
+	roots := cfg.roots()
+	for _, root := range roots {
+		err := os.MkdirAll(root, os.ModePerm)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	l := &Backend{
-		cfg: cfg,
+		cfg:   cfg,
+		roots: roots,
 	}
 
 	return l, nil
@@ -124,8 +130,14 @@ func (rw *Backend) Delete(ctx context.Context, name string, keypath backend.KeyP
 		return err
 	}
 
-	path := rw.rootPath(append(keypath, name))
-	return os.RemoveAll(path)
+	// the object is removed from all folders in case a block has been written to more than one
+	for _, root := range rw.roots {
+		err := os.RemoveAll(filepath.Join(root, filepath.Join(keypath...), name))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // List implements backend.Reader
@@ -134,28 +146,43 @@ func (rw *Backend) List(ctx context.Context, keypath backend.KeyPath) ([]string,
 		return nil, err
 	}
 
-	path := rw.rootPath(keypath)
-	folders, err := os.ReadDir(path)
+	var objects []string
+	err := rw.forEachRoot(keypath, func(path string) error {
+		folders, err := os.ReadDir(path)
+		if err != nil {
+			return err
+		}
+
+		for _, f := range folders {
+			if !f.IsDir() || slices.Contains(objects, f.Name()) {
+				continue
+			}
+			objects = append(objects, f.Name())
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	objects := make([]string, 0, len(folders))
-	for _, f := range folders {
-		if !f.IsDir() {
-			continue
-		}
-		objects = append(objects, f.Name())
+	if objects == nil {
+		objects = []string{}
 	}
-
 	return objects, nil
 }
 
 // ListBlocks implements backend.Reader
 func (rw *Backend) ListBlocks(_ context.Context, tenant string) (metas []uuid.UUID, compactedMetas []uuid.UUID, err error) {
-	rootPath := rw.rootPath(backend.KeyPath{tenant})
+	err = rw.forEachRoot(backend.KeyPath{tenant}, func(rootPath string) error {
+		return rw.listBlocks(rootPath, tenant, &metas, &compactedMetas)
+	})
+
+	return
+}
+
+func (rw *Backend) listBlocks(rootPath, tenant string, metas, compactedMetas *[]uuid.UUID) error {
 	fff := os.DirFS(rootPath)
-	err = fs.WalkDir(fff, ".", func(path string, d fs.DirEntry, err error) error {
+	return fs.WalkDir(fff, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -179,22 +206,25 @@ func (rw *Backend) ListBlocks(_ context.Context, tenant string) (metas []uuid.UU
 
 		switch parts[2] {
 		case backend.MetaName:
-			metas = append(metas, id)
+			*metas = append(*metas, id)
 		case backend.CompactedMetaName:
-			compactedMetas = append(compactedMetas, id)
+			*compactedMetas = append(*compactedMetas, id)
 		}
 
 		return nil
 	})
-
-	return
 }
 
 // Find implements backend.Reader
 func (rw *Backend) Find(_ context.Context, keypath backend.KeyPath, f backend.FindFunc) (err error) {
-	path := rw.rootPath(keypath)
+	return rw.forEachRoot(keypath, func(path string) error {
+		return rw.find(path, keypath, f)
+	})
+}
+
+func (rw *Backend) find(path string, keypath backend.KeyPath, f backend.FindFunc) error {
 	fff := os.DirFS(path)
-	err = fs.WalkDir(fff, ".", func(path string, d fs.DirEntry, err error) error {
+	return fs.WalkDir(fff, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -218,8 +248,6 @@ func (rw *Backend) Find(_ context.Context, keypath backend.KeyPath, f backend.Fi
 
 		return nil
 	})
-
-	return
 }
 
 // Read implements backend.Reader
@@ -290,7 +318,9 @@ func (rw *Backend) Shutdown() {
 		}
 
 		if len(blocks) == 0 {
-			_ = os.RemoveAll(rw.rootPath(backend.KeyPath{tenant}))
+			for _, root := range rw.roots {
+				_ = os.RemoveAll(filepath.Join(root, tenant))
+			}
 		}
 	}
 }
@@ -304,7 +334,7 @@ func (rw *Backend) metaFileName(blockID uuid.UUID, tenantID string) string {
 }
 
 func (rw *Backend) rootPath(keypath backend.KeyPath) string {
-	return filepath.Join(rw.cfg.Path, filepath.Join(keypath...))
+	return filepath.Join(rw.root(keypath), filepath.Join(keypath...))
 }
 
 func readError(err error) error {
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/grafana/tempo/pkg/io"
@@ -143,6 +144,86 @@ func TestShutdownRemovesTenantsWithoutBlocks(t *testing.T) {
 	require.Len(t, tenants, 0)
 }
 
+func TestMultiplePaths(t *testing.T) {
+	paths := []string{t.TempDir(), t.TempDir(), t.TempDir()}
+	r, w, c, err := New(&Config{
+		Path:  paths[0],
+		Paths: paths[1:],
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	tenant := "tenant"
+	contents := []byte("test")
+
+	blockIDs := make([]uuid.UUID, 30)
+	for i := range blockIDs {
+		blockIDs[i] = uuid.New()
+		err = w.Write(ctx, backend.MetaName, backend.KeyPathForBlock(blockIDs[i], tenant), bytes.NewReader(contents), int64(len(contents)), nil)
+		require.NoError(t, err)
+	}
+
+	// objects that don't belong to a block are written to the first path
+	err = w.Write(ctx, backend.TenantIndexName, backend.KeyPath{tenant}, bytes.NewReader(contents), int64(len(contents)), nil)
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(paths[0], tenant, backend.TenantIndexName))
+
+	// each block is written to exactly one path and the blocks are spread across all paths
+	blocksPerPath := map[string]int{}
+	for _, blockID := range blockIDs {
+		found := 0
+		for _, p := range paths {
+			if _, err := os.Stat(filepath.Join(p, tenant, blockID.String())); err == nil {
+				blocksPerPath[p]++
+				found++
+			}
+		}
+		require.Equal(t, 1, found)
+	}
+	require.Len(t, blocksPerPath, len(paths))
+
+	// listing is unified
+	tenantExists(t, tenant, r)
+
+	blocks, err := r.List(ctx, backend.KeyPath{tenant})
+	require.NoError(t, err)
+	require.Len(t, blocks, len(blockIDs))
+
+	metas, _, err := r.ListBlocks(ctx, tenant)
+	require.NoError(t, err)
+	require.ElementsMatch(t, blockIDs, metas)
+
+	found := 0
+	err = r.Find(ctx, backend.KeyPath{tenant}, func(backend.FindMatch) { found++ })
+	require.NoError(t, err)
+	require.Equal(t, len(blockIDs)+1, found)
+
+	// blocks are found after a path is added
+	paths = append(paths, t.TempDir())
+	r, _, c, err = New(&Config{
+		Path:  paths[0],
+		Paths: paths[1:],
+	})
+	require.NoError(t, err)
+
+	for _, blockID := range blockIDs {
+		reader, _, err := r.Read(ctx, backend.MetaName, backend.KeyPathForBlock(blockID, tenant), nil)
+		require.NoError(t, err)
+		actual, err := io.ReadAllWithEstimate(reader, int64(len(contents)))
+		require.NoError(t, err)
+		require.Equal(t, contents, actual)
+		require.NoError(t, reader.Close())
+	}
+
+	require.NoError(t, c.MarkBlockCompacted(blockIDs[0], tenant))
+	require.NoError(t, c.ClearBlock(blockIDs[1], tenant))
+
+	metas, compactedMetas, err := r.ListBlocks(ctx, tenant)
+	require.NoError(t, err)
+	require.ElementsMatch(t, blockIDs[2:], metas)
+	require.Equal(t, []uuid.UUID{blockIDs[0]}, compactedMetas)
+}
+
 func tenantExists(t *testing.T, tenant string, r backend.RawReader) {
 	tenants, err := r.List(context.Background(), backend.KeyPath{})
 	require.NoError(t, err)
@@ -0,0 +1,88 @@
+package local
+
+import (
+	"errors"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// root returns the folder that stores the objects of the keypath. Objects of a block are stored in the folder
+// of the block, all other objects in the first folder.
+func (rw *Backend) root(keypath backend.KeyPath) string {
+	if len(rw.roots) > 1 && len(keypath) >= 2 {
+		if blockID, err := uuid.Parse(keypath[1]); err == nil {
+			return rw.rootForBlock(blockID, keypath[0])
+		}
+	}
+	return rw.roots[0]
+}
+
+// rootForBlock returns the folder of a block. New blocks are placed with rendezvous hashing of the block ID so that
+// adding a folder only changes the placement of a share of the blocks. Existing blocks are found in any folder so
+// that blocks written before a folder was added can still be read.
+func (rw *Backend) rootForBlock(blockID uuid.UUID, tenantID string) string {
+	hashed := rw.hashedRoot(blockID)
+	if exists(filepath.Join(hashed, tenantID, blockID.String())) {
+		return hashed
+	}
+
+	for _, root := range rw.roots {
+		if root != hashed && exists(filepath.Join(root, tenantID, blockID.String())) {
+			return root
+		}
+	}
+
+	return hashed
+}
+
+func (rw *Backend) hashedRoot(blockID uuid.UUID) string {
+	var (
+		best      int
+		bestScore uint64
+	)
+	for i, root := range rw.roots {
+		h := fnv.New64a()
+		_, _ = h.Write(blockID[:])
+		_, _ = h.Write([]byte(root))
+
+		if score := h.Sum64(); i == 0 || score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return rw.roots[best]
+}
+
+// forEachRoot calls fn with the path of the keypath in each folder in which it exists. If it doesn't exist in any
+// folder fn is called with the path in the first folder, so the behavior matches the one of a single folder.
+func (rw *Backend) forEachRoot(keypath backend.KeyPath, fn func(path string) error) error {
+	found := false
+	for _, root := range rw.roots {
+		path := filepath.Join(root, filepath.Join(keypath...))
+		if len(rw.roots) > 1 {
+			if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+		}
+
+		found = true
+		if err := fn(path); err != nil {
+			return err
+		}
+	}
+
+	if !found {
+		return fn(filepath.Join(rw.roots[0], filepath.Join(keypath...)))
+	}
+	return nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -151,6 +151,8 @@ type MockReader struct {
 	M                 *BlockMeta // meta
 	BlockMetaFn       func(ctx context.Context, blockID uuid.UUID, tenantID string) (*BlockMeta, error)
 	TenantIndexFn     func(ctx context.Context, tenantID string) (*TenantIndex, error)
+	DeletionMarks     map[string]*TenantDeletionMark
+	Recommendations   map[string]*DedicatedColumnsRecommendation
 	R                 []byte // read
 	Range             []byte // ReadRange
 	ReadFn            func(name string, blockID uuid.UUID, tenantID string) ([]byte, error)
@@ -221,6 +223,36 @@ func (m *MockReader) TenantIndex(ctx context.Context, tenantID string) (*TenantI
 	return &TenantIndex{}, nil
 }
 
+func (m *MockReader) TenantDeletionMark(_ context.Context, tenantID string) (*TenantDeletionMark, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if mark, ok := m.DeletionMarks[tenantID]; ok {
+		return mark, nil
+	}
+
+	return nil, ErrDoesNotExist
+}
+
+func (m *MockReader) DedicatedColumnsRecommendation(_ context.Context, tenantID string) (*DedicatedColumnsRecommendation, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if rec, ok := m.Recommendations[tenantID]; ok {
+		return rec, nil
+	}
+
+	return nil, ErrDoesNotExist
+}
+
+func (m *MockReader) IngesterSnapshot(context.Context, string, string) (*IngesterSnapshot, error) {
+	return nil, ErrDoesNotExist
+}
+
+func (m *MockReader) IngesterSnapshotFile(context.Context, string, string, string) (io.ReadCloser, int64, error) {
+	return nil, 0, ErrDoesNotExist
+}
+
 func (m *MockReader) Shutdown() {}
 
 // MockWriter
@@ -228,6 +260,8 @@ type MockWriter struct {
 	sync.Mutex
 	IndexMeta          map[string][]*BlockMeta
 	IndexCompactedMeta map[string][]*CompactedBlockMeta
+	DeletionMarks      map[string]*TenantDeletionMark
+	Recommendations    map[string]*DedicatedColumnsRecommendation
 }
 
 func (m *MockWriter) Write(context.Context, string, uuid.UUID, string, []byte, *CacheInfo) error {
@@ -269,6 +303,40 @@ func (m *MockWriter) WriteTenantIndex(_ context.Context, tenantID string, meta [
 	return nil
 }
 
+func (m *MockWriter) WriteTenantDeletionMark(_ context.Context, tenantID string, mark *TenantDeletionMark) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.DeletionMarks == nil {
+		m.DeletionMarks = make(map[string]*TenantDeletionMark)
+	}
+	m.DeletionMarks[tenantID] = mark
+	return nil
+}
+
+func (m *MockWriter) WriteDedicatedColumnsRecommendation(_ context.Context, tenantID string, rec *DedicatedColumnsRecommendation) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.Recommendations == nil {
+		m.Recommendations = make(map[string]*DedicatedColumnsRecommendation)
+	}
+	m.Recommendations[tenantID] = rec
+	return nil
+}
+
+func (m *MockWriter) WriteIngesterSnapshotFile(context.Context, string, string, string, io.Reader, int64) error {
+	return nil
+}
+
+func (m *MockWriter) WriteIngesterSnapshot(context.Context, string, string, *IngesterSnapshot) error {
+	return nil
+}
+
+func (m *MockWriter) DeleteIngesterSnapshotFile(context.Context, string, string, string) error {
+	return nil
+}
+
 type MockBlocklist struct {
 	MetasFn          func(tenantID string) []*BlockMeta
 	CompactedMetasFn func(tenantID string) []*CompactedBlockMeta
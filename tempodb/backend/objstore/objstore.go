@@ -0,0 +1,490 @@
+// Package objstore implements the raw backend interfaces on top of a Bucket. The backends of object stores that
+// are not covered by the s3, gcs and azure backends implement the Bucket with the SDK of the store.
+package objstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cristalhq/hedgedhttp"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/grafana/tempo/pkg/blockboundary"
+	tempo_io "github.com/grafana/tempo/pkg/io"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/instrumentation"
+)
+
+// Bucket is the client of a bucket of an object store. Objects that don't exist are returned as
+// backend.ErrDoesNotExist.
+type Bucket interface {
+	// Put writes the object.
+	Put(ctx context.Context, object string, data io.Reader, size int64) error
+	// Get reads length bytes of the object starting at offset. The whole object is read if length is negative.
+	Get(ctx context.Context, object string, offset, length int64) (io.ReadCloser, ObjectAttributes, error)
+	// Copy copies the object src to dst.
+	Copy(ctx context.Context, src, dst string) error
+	// Delete deletes the object.
+	Delete(ctx context.Context, object string) error
+	// List returns a page of at most maxKeys objects with the prefix that sort after marker. If delimiter is set the
+	// objects are grouped into common prefixes up to the first delimiter after the prefix.
+	List(ctx context.Context, prefix, delimiter, marker string, maxKeys int) (ListResult, error)
+
+	// InitiateMultipartUpload starts a multipart upload of the object and returns its id.
+	InitiateMultipartUpload(ctx context.Context, object string) (string, error)
+	// UploadPart uploads a part of the multipart upload and returns its etag.
+	UploadPart(ctx context.Context, object, uploadID string, partNumber int, data []byte) (string, error)
+	// CompleteMultipartUpload completes the multipart upload from the parts.
+	CompleteMultipartUpload(ctx context.Context, object, uploadID string, parts []Part) error
+}
+
+// ObjectAttributes are the attributes of a read object.
+type ObjectAttributes struct {
+	Size         int64
+	LastModified time.Time
+}
+
+// ListResult is a page of a listing.
+type ListResult struct {
+	Objects        []ObjectInfo
+	CommonPrefixes []string
+	IsTruncated    bool
+	// NextMarker is the marker of the next page. It may be empty if no delimiter was set.
+	NextMarker string
+}
+
+// ObjectInfo is an object of a listing.
+type ObjectInfo struct {
+	Key          string
+	LastModified time.Time
+}
+
+// Part is an uploaded part of a multipart upload.
+type Part struct {
+	PartNumber int
+	ETag       string
+}
+
+// Config is the configuration shared by the backends built on this package.
+type Config struct {
+	// Name of the backend, used to name spans.
+	Name                  string
+	Prefix                string
+	ListBlocksConcurrency int
+}
+
+// NewTransports returns the instrumented transport for requests to the object store and the transport for reads,
+// which hedges requests if hedgeRequestsAt is not 0.
+func NewTransports(hedgeRequestsAt time.Duration, hedgeRequestsUpTo int) (http.RoundTripper, http.RoundTripper, error) {
+	transport := instrumentation.NewTransport(http.DefaultTransport.(*http.Transport).Clone())
+
+	// hedge if desired (0 means disabled)
+	if hedgeRequestsAt == 0 {
+		return transport, transport, nil
+	}
+
+	hedgedTransport, stats, err := hedgedhttp.NewRoundTripperAndStats(hedgeRequestsAt, hedgeRequestsUpTo, transport)
+	if err != nil {
+		return nil, nil, err
+	}
+	instrumentation.PublishHedgedMetrics(stats)
+
+	return transport, hedgedTransport, nil
+}
+
+// ReaderWriter implements backend.RawReader, backend.RawWriter and backend.Compactor.
+type ReaderWriter struct {
+	cfg           Config
+	bucket        Bucket
+	listBatchSize int
+}
+
+var (
+	_ backend.RawReader = (*ReaderWriter)(nil)
+	_ backend.RawWriter = (*ReaderWriter)(nil)
+	_ backend.Compactor = (*ReaderWriter)(nil)
+)
+
+// New returns a ReaderWriter for the bucket. If confirm is true the bucket is listed to check it is accessible.
+func New(cfg Config, bucket Bucket, confirm bool) (*ReaderWriter, error) {
+	if bucket == nil {
+		return nil, errors.New("bucket is required")
+	}
+	if cfg.ListBlocksConcurrency <= 0 {
+		cfg.ListBlocksConcurrency = 1
+	}
+
+	rw := &ReaderWriter{
+		cfg:           cfg,
+		bucket:        bucket,
+		listBatchSize: 1000,
+	}
+
+	if confirm {
+		if _, err := bucket.List(context.Background(), cfg.Prefix, "", "", 1); err != nil {
+			return nil, fmt.Errorf("listing bucket: %w", err)
+		}
+	}
+
+	return rw, nil
+}
+
+// Write implements backend.Writer
+func (rw *ReaderWriter) Write(ctx context.Context, name string, keypath backend.KeyPath, data io.Reader, size int64, _ *backend.CacheInfo) error {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	span, ctx := opentracing.StartSpanFromContext(ctx, rw.cfg.Name+".Write")
+	defer span.Finish()
+
+	span.SetTag("object", name)
+
+	err := rw.bucket.Put(ctx, backend.ObjectFileName(keypath, name), data, size)
+	if err != nil {
+		span.SetTag("error", true)
+		return fmt.Errorf("failed to write: %w", err)
+	}
+	return nil
+}
+
+type appendTracker struct {
+	object   string
+	uploadID string
+	parts    []Part
+}
+
+// Append implements backend.Writer. Every buffer is uploaded as a part of a multipart upload.
+func (rw *ReaderWriter) Append(ctx context.Context, name string, keypath backend.KeyPath, tracker backend.AppendTracker, buffer []byte) (backend.AppendTracker, error) {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	span, ctx := opentracing.StartSpanFromContext(ctx, rw.cfg.Name+".Append", opentracing.Tags{
+		"len": len(buffer),
+	})
+	defer span.Finish()
+
+	var a *appendTracker
+	if tracker != nil {
+		a = tracker.(*appendTracker)
+	} else {
+		object := backend.ObjectFileName(keypath, name)
+		uploadID, err := rw.bucket.InitiateMultipartUpload(ctx, object)
+		if err != nil {
+			return nil, err
+		}
+		a = &appendTracker{object: object, uploadID: uploadID}
+	}
+
+	partNumber := len(a.parts) + 1
+	etag, err := rw.bucket.UploadPart(ctx, a.object, a.uploadID, partNumber, buffer)
+	if err != nil {
+		return a, fmt.Errorf("error in multipart upload: %w", err)
+	}
+	a.parts = append(a.parts, Part{PartNumber: partNumber, ETag: etag})
+
+	return a, nil
+}
+
+// CloseAppend implements backend.Writer
+func (rw *ReaderWriter) CloseAppend(ctx context.Context, tracker backend.AppendTracker) error {
+	if tracker == nil {
+		return nil
+	}
+
+	a := tracker.(*appendTracker)
+	if err := rw.bucket.CompleteMultipartUpload(ctx, a.object, a.uploadID, a.parts); err != nil {
+		return fmt.Errorf("error completing multipart upload, object: %s: %w", a.object, err)
+	}
+	return nil
+}
+
+// Delete implements backend.Writer
+func (rw *ReaderWriter) Delete(ctx context.Context, name string, keypath backend.KeyPath, _ *backend.CacheInfo) error {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	return rw.bucket.Delete(ctx, backend.ObjectFileName(keypath, name))
+}
+
+// List implements backend.Reader
+func (rw *ReaderWriter) List(ctx context.Context, keypath backend.KeyPath) ([]string, error) {
+	prefix := rw.keypathPrefix(keypath)
+
+	var objects []string
+	err := rw.listAll(ctx, prefix, "/", "", func(res ListResult) bool {
+		for _, p := range res.CommonPrefixes {
+			objects = append(objects, strings.TrimSuffix(strings.TrimPrefix(p, prefix), "/"))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing objects in bucket: %w", err)
+	}
+
+	return objects, nil
+}
+
+// ListBlocks implements backend.Reader
+func (rw *ReaderWriter) ListBlocks(ctx context.Context, tenant string) ([]uuid.UUID, []uuid.UUID, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "readerWriter.ListBlocks")
+	defer span.Finish()
+
+	var (
+		wg                sync.WaitGroup
+		mtx               sync.Mutex
+		bb                = blockboundary.CreateBlockBoundaries(rw.cfg.ListBlocksConcurrency)
+		errChan           = make(chan error, len(bb))
+		prefix            = rw.keypathPrefix(backend.KeyPath{tenant})
+		blockIDs          = make([]uuid.UUID, 0, 1000)
+		compactedBlockIDs = make([]uuid.UUID, 0, 1000)
+	)
+
+	for i := 0; i < len(bb)-1; i++ {
+		min := uuid.UUID(bb[i])
+		max := uuid.UUID(bb[i+1])
+
+		wg.Add(1)
+		go func(min, max uuid.UUID) {
+			defer wg.Done()
+
+			err := rw.listAll(ctx, prefix, "", prefix+min.String(), func(res ListResult) bool {
+				for _, o := range res.Objects {
+					// i.e: <blockID>/meta.json
+					parts := strings.Split(strings.TrimPrefix(o.Key, prefix), "/")
+					if len(parts) != 2 {
+						continue
+					}
+
+					switch parts[1] {
+					case backend.MetaName:
+					case backend.CompactedMetaName:
+					default:
+						continue
+					}
+
+					id, err := uuid.Parse(parts[0])
+					if err != nil {
+						continue
+					}
+
+					if max != backend.GlobalMaxBlockID && bytes.Compare(id[:], max[:]) >= 0 {
+						return false
+					}
+
+					mtx.Lock()
+					switch parts[1] {
+					case backend.MetaName:
+						blockIDs = append(blockIDs, id)
+					case backend.CompactedMetaName:
+						compactedBlockIDs = append(compactedBlockIDs, id)
+					}
+					mtx.Unlock()
+				}
+				return true
+			})
+			if err != nil {
+				errChan <- fmt.Errorf("error listing blocks: %w", err)
+			}
+		}(min, max)
+	}
+	wg.Wait()
+	close(errChan)
+
+	errs := make([]error, 0, len(errChan))
+	for e := range errChan {
+		errs = append(errs, e)
+	}
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	return blockIDs, compactedBlockIDs, nil
+}
+
+// Find implements backend.Reader
+func (rw *ReaderWriter) Find(ctx context.Context, keypath backend.KeyPath, f backend.FindFunc) error {
+	prefix := rw.keypathPrefix(keypath)
+
+	err := rw.listAll(ctx, prefix, "", "", func(res ListResult) bool {
+		for _, o := range res.Objects {
+			f(backend.FindMatch{
+				Key:      o.Key,
+				Modified: o.LastModified,
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("error finding objects in bucket: %w", err)
+	}
+	return nil
+}
+
+// Read implements backend.Reader
+func (rw *ReaderWriter) Read(ctx context.Context, name string, keypath backend.KeyPath, _ *backend.CacheInfo) (io.ReadCloser, int64, error) {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	span, ctx := opentracing.StartSpanFromContext(ctx, rw.cfg.Name+".Read")
+	defer span.Finish()
+
+	span.SetTag("object", name)
+
+	r, attrs, err := rw.bucket.Get(ctx, backend.ObjectFileName(keypath, name), 0, -1)
+	if err != nil {
+		span.SetTag("error", true)
+		return nil, 0, err
+	}
+	return r, attrs.Size, nil
+}
+
+// ReadRange implements backend.Reader
+func (rw *ReaderWriter) ReadRange(ctx context.Context, name string, keypath backend.KeyPath, offset uint64, buffer []byte, _ *backend.CacheInfo) error {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	span, ctx := opentracing.StartSpanFromContext(ctx, rw.cfg.Name+".ReadRange", opentracing.Tags{
+		"len":    len(buffer),
+		"offset": offset,
+	})
+	defer span.Finish()
+
+	if len(buffer) == 0 {
+		return nil
+	}
+
+	r, _, err := rw.bucket.Get(ctx, backend.ObjectFileName(keypath, name), int64(offset), int64(len(buffer)))
+	if err != nil {
+		span.SetTag("error", true)
+		return err
+	}
+	defer r.Close()
+
+	_, err = io.ReadFull(r, buffer)
+	if err != nil {
+		span.SetTag("error", true)
+	}
+	return err
+}
+
+// Shutdown implements backend.Reader
+func (rw *ReaderWriter) Shutdown() {
+}
+
+// MarkBlockCompacted implements backend.Compactor
+func (rw *ReaderWriter) MarkBlockCompacted(blockID uuid.UUID, tenantID string) error {
+	if len(tenantID) == 0 {
+		return backend.ErrEmptyTenantID
+	}
+	if blockID == uuid.Nil {
+		return backend.ErrEmptyBlockID
+	}
+
+	ctx := context.TODO()
+	metaFileName := backend.MetaFileName(blockID, tenantID, rw.cfg.Prefix)
+
+	// copy meta.json to meta.compacted.json
+	err := rw.bucket.Copy(ctx, metaFileName, backend.CompactedMetaFileName(blockID, tenantID, rw.cfg.Prefix))
+	if err != nil {
+		return fmt.Errorf("error copying obj meta to compacted obj meta: %w", err)
+	}
+
+	// delete meta.json
+	return rw.bucket.Delete(ctx, metaFileName)
+}
+
+// ClearBlock implements backend.Compactor
+func (rw *ReaderWriter) ClearBlock(blockID uuid.UUID, tenantID string) error {
+	if len(tenantID) == 0 {
+		return backend.ErrEmptyTenantID
+	}
+	if blockID == uuid.Nil {
+		return backend.ErrEmptyBlockID
+	}
+
+	ctx := context.TODO()
+	prefix := backend.RootPath(blockID, tenantID, rw.cfg.Prefix) + "/"
+
+	var objects []string
+	err := rw.listAll(ctx, prefix, "", "", func(res ListResult) bool {
+		for _, o := range res.Objects {
+			objects = append(objects, o.Key)
+		}
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("error listing objects of block: %w", err)
+	}
+
+	for _, o := range objects {
+		if err := rw.bucket.Delete(ctx, o); err != nil && !errors.Is(err, backend.ErrDoesNotExist) {
+			return fmt.Errorf("error deleting obj %s: %w", o, err)
+		}
+	}
+	return nil
+}
+
+// CompactedBlockMeta implements backend.Compactor
+func (rw *ReaderWriter) CompactedBlockMeta(blockID uuid.UUID, tenantID string) (*backend.CompactedBlockMeta, error) {
+	if len(tenantID) == 0 {
+		return nil, backend.ErrEmptyTenantID
+	}
+	if blockID == uuid.Nil {
+		return nil, backend.ErrEmptyBlockID
+	}
+
+	r, attrs, err := rw.bucket.Get(context.TODO(), backend.CompactedMetaFileName(blockID, tenantID, rw.cfg.Prefix), 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	b, err := tempo_io.ReadAllWithEstimate(r, attrs.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &backend.CompactedBlockMeta{}
+	if err := json.Unmarshal(b, out); err != nil {
+		return nil, err
+	}
+	out.CompactedTime = attrs.LastModified
+
+	return out, nil
+}
+
+func (rw *ReaderWriter) keypathPrefix(keypath backend.KeyPath) string {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	prefix := path.Join(keypath...)
+	if len(prefix) > 0 {
+		prefix += "/"
+	}
+	return prefix
+}
+
+// listAll lists the objects with the prefix after the marker and passes the pages to f until f returns false.
+func (rw *ReaderWriter) listAll(ctx context.Context, prefix, delimiter, marker string, f func(ListResult) bool) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		res, err := rw.bucket.List(ctx, prefix, delimiter, marker, rw.listBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if !f(res) || !res.IsTruncated {
+			return nil
+		}
+
+		// the next marker is only returned if a delimiter is set, otherwise continue after the last key
+		marker = res.NextMarker
+		if marker == "" && len(res.Objects) > 0 {
+			marker = res.Objects[len(res.Objects)-1].Key
+		}
+		if marker == "" {
+			return errors.New("truncated list result without marker")
+		}
+	}
+}
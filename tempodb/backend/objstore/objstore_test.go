@@ -0,0 +1,287 @@
+package objstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// fakeBucket is an in-memory Bucket.
+type fakeBucket struct {
+	mtx     sync.Mutex
+	objects map[string][]byte
+	uploads map[string]map[int][]byte
+}
+
+func newFakeBucket(t *testing.T) (*fakeBucket, *ReaderWriter) {
+	b := &fakeBucket{
+		objects: map[string][]byte{},
+		uploads: map[string]map[int][]byte{},
+	}
+
+	rw, err := New(Config{
+		Name:                  "test",
+		Prefix:                "prefix",
+		ListBlocksConcurrency: 3,
+	}, b, true)
+	require.NoError(t, err)
+	rw.listBatchSize = 2 // exercise pagination
+
+	return b, rw
+}
+
+func (b *fakeBucket) Put(_ context.Context, object string, data io.Reader, _ int64) error {
+	obj, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.objects[object] = obj
+	return nil
+}
+
+func (b *fakeBucket) Get(_ context.Context, object string, offset, length int64) (io.ReadCloser, ObjectAttributes, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	obj, ok := b.objects[object]
+	if !ok {
+		return nil, ObjectAttributes{}, backend.ErrDoesNotExist
+	}
+	if length >= 0 {
+		obj = obj[offset : offset+length]
+	}
+	return io.NopCloser(bytes.NewReader(obj)), ObjectAttributes{Size: int64(len(obj)), LastModified: lastModified}, nil
+}
+
+func (b *fakeBucket) Copy(_ context.Context, src, dst string) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	obj, ok := b.objects[src]
+	if !ok {
+		return backend.ErrDoesNotExist
+	}
+	b.objects[dst] = obj
+	return nil
+}
+
+func (b *fakeBucket) Delete(_ context.Context, object string) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	delete(b.objects, object)
+	return nil
+}
+
+func (b *fakeBucket) List(_ context.Context, prefix, delimiter, marker string, maxKeys int) (ListResult, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	keys := make([]string, 0, len(b.objects))
+	for k := range b.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var (
+		res      ListResult
+		prefixes = map[string]struct{}{}
+		next     string
+	)
+	for _, k := range keys {
+		if !strings.HasPrefix(k, prefix) || k <= marker {
+			continue
+		}
+		if len(res.Objects)+len(res.CommonPrefixes) == maxKeys {
+			res.IsTruncated = true
+			break
+		}
+
+		rest := strings.TrimPrefix(k, prefix)
+		if i := strings.Index(rest, delimiter); delimiter != "" && i >= 0 {
+			p := prefix + rest[:i+1]
+			if _, ok := prefixes[p]; !ok {
+				prefixes[p] = struct{}{}
+				res.CommonPrefixes = append(res.CommonPrefixes, p)
+			}
+			next = p + string(utf8.MaxRune) // skip the remaining objects with this prefix
+			continue
+		}
+
+		res.Objects = append(res.Objects, ObjectInfo{Key: k, LastModified: lastModified})
+		next = k
+	}
+	if res.IsTruncated && delimiter != "" {
+		res.NextMarker = next
+	}
+	return res, nil
+}
+
+func (b *fakeBucket) InitiateMultipartUpload(context.Context, string) (string, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	uploadID := strconv.Itoa(len(b.uploads))
+	b.uploads[uploadID] = map[int][]byte{}
+	return uploadID, nil
+}
+
+func (b *fakeBucket) UploadPart(_ context.Context, _, uploadID string, partNumber int, data []byte) (string, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.uploads[uploadID][partNumber] = append([]byte(nil), data...)
+	return fmt.Sprintf("etag-%d", partNumber), nil
+}
+
+func (b *fakeBucket) CompleteMultipartUpload(_ context.Context, object, uploadID string, parts []Part) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	var obj []byte
+	for i, p := range parts {
+		if p.PartNumber != i+1 || p.ETag != fmt.Sprintf("etag-%d", p.PartNumber) {
+			return fmt.Errorf("unexpected part %d: %v", i, p)
+		}
+		obj = append(obj, b.uploads[uploadID][p.PartNumber]...)
+	}
+	b.objects[object] = obj
+	delete(b.uploads, uploadID)
+	return nil
+}
+
+type errBucket struct {
+	*fakeBucket
+}
+
+func (errBucket) List(context.Context, string, string, string, int) (ListResult, error) {
+	return ListResult{}, errors.New("access denied")
+}
+
+var lastModified = time.Date(2024, 5, 12, 16, 21, 24, 0, time.UTC)
+
+func TestReadWrite(t *testing.T) {
+	b, rw := newFakeBucket(t)
+	ctx := context.Background()
+
+	data := []byte("hello world")
+	require.NoError(t, rw.Write(ctx, "object", backend.KeyPath{"tenant", "block"}, bytes.NewReader(data), int64(len(data)), nil))
+	require.Contains(t, b.objects, "prefix/tenant/block/object")
+
+	r, size, err := rw.Read(ctx, "object", backend.KeyPath{"tenant", "block"}, nil)
+	require.NoError(t, err)
+	actual, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, data, actual)
+	require.Equal(t, int64(len(data)), size)
+
+	buffer := make([]byte, 5)
+	require.NoError(t, rw.ReadRange(ctx, "object", backend.KeyPath{"tenant", "block"}, 6, buffer, nil))
+	require.Equal(t, []byte("world"), buffer)
+
+	require.NoError(t, rw.Delete(ctx, "object", backend.KeyPath{"tenant", "block"}, nil))
+	_, _, err = rw.Read(ctx, "object", backend.KeyPath{"tenant", "block"}, nil)
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+	require.ErrorIs(t, rw.ReadRange(ctx, "object", backend.KeyPath{"tenant", "block"}, 0, buffer, nil), backend.ErrDoesNotExist)
+}
+
+func TestAppend(t *testing.T) {
+	b, rw := newFakeBucket(t)
+	ctx := context.Background()
+
+	var (
+		tracker backend.AppendTracker
+		err     error
+	)
+	for _, part := range []string{"a", "bb", "ccc"} {
+		tracker, err = rw.Append(ctx, "object", backend.KeyPath{"tenant"}, tracker, []byte(part))
+		require.NoError(t, err)
+	}
+	require.NoError(t, rw.CloseAppend(ctx, tracker))
+
+	require.Equal(t, []byte("abbccc"), b.objects["prefix/tenant/object"])
+	require.Empty(t, b.uploads)
+}
+
+func TestListAndFind(t *testing.T) {
+	_, rw := newFakeBucket(t)
+	ctx := context.Background()
+
+	for _, tenant := range []string{"a", "b", "c"} {
+		for _, name := range []string{"x", "y", "z"} {
+			require.NoError(t, rw.Write(ctx, name, backend.KeyPath{tenant, "dir"}, strings.NewReader(name), 1, nil))
+		}
+	}
+
+	tenants, err := rw.List(ctx, backend.KeyPath{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, tenants)
+
+	var found []string
+	require.NoError(t, rw.Find(ctx, backend.KeyPath{"b"}, func(m backend.FindMatch) {
+		found = append(found, m.Key)
+	}))
+	require.Equal(t, []string{"prefix/b/dir/x", "prefix/b/dir/y", "prefix/b/dir/z"}, found)
+}
+
+func TestListBlocksAndCompaction(t *testing.T) {
+	_, rw := newFakeBucket(t)
+	ctx := context.Background()
+	w := backend.NewWriter(rw)
+
+	var blockIDs []uuid.UUID
+	for i := 0; i < 10; i++ {
+		meta := backend.NewBlockMeta("tenant", uuid.New(), "v2", backend.EncNone, "")
+		require.NoError(t, w.WriteBlockMeta(ctx, meta))
+		require.NoError(t, w.Write(ctx, "data", meta.BlockID, meta.TenantID, []byte("data"), nil))
+		blockIDs = append(blockIDs, meta.BlockID)
+	}
+
+	live, compacted, err := rw.ListBlocks(ctx, "tenant")
+	require.NoError(t, err)
+	require.ElementsMatch(t, blockIDs, live)
+	require.Empty(t, compacted)
+
+	require.NoError(t, rw.MarkBlockCompacted(blockIDs[0], "tenant"))
+	live, compacted, err = rw.ListBlocks(ctx, "tenant")
+	require.NoError(t, err)
+	require.ElementsMatch(t, blockIDs[1:], live)
+	require.Equal(t, blockIDs[:1], compacted)
+
+	compactedMeta, err := rw.CompactedBlockMeta(blockIDs[0], "tenant")
+	require.NoError(t, err)
+	require.Equal(t, blockIDs[0], compactedMeta.BlockID)
+	require.Equal(t, lastModified, compactedMeta.CompactedTime.UTC())
+
+	require.NoError(t, rw.ClearBlock(blockIDs[0], "tenant"))
+	live, compacted, err = rw.ListBlocks(ctx, "tenant")
+	require.NoError(t, err)
+	require.ElementsMatch(t, blockIDs[1:], live)
+	require.Empty(t, compacted)
+
+	_, err = rw.CompactedBlockMeta(blockIDs[0], "tenant")
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+}
+
+func TestListError(t *testing.T) {
+	_, err := New(Config{Name: "test"}, errBucket{}, true)
+	require.EqualError(t, err, "listing bucket: access denied")
+}
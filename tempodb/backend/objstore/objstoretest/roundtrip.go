@@ -0,0 +1,85 @@
+package objstoretest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// RoundTrip writes, reads, lists and compacts blocks with a backend that is configured with the prefix "prefix"
+// and stores its objects in s.
+func RoundTrip(t *testing.T, s *Server, r backend.RawReader, w backend.RawWriter, c backend.Compactor) {
+	ctx := context.Background()
+	keypath := backend.KeyPath{"tenant", "block"}
+
+	data := []byte("hello world")
+	require.NoError(t, w.Write(ctx, "object", keypath, bytes.NewReader(data), int64(len(data)), nil))
+	require.Equal(t, data, s.Objects()["prefix/tenant/block/object"])
+
+	rc, size, err := r.Read(ctx, "object", keypath, nil)
+	require.NoError(t, err)
+	actual, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	require.Equal(t, data, actual)
+	require.Equal(t, int64(len(data)), size)
+
+	buffer := make([]byte, 5)
+	require.NoError(t, r.ReadRange(ctx, "object", keypath, 6, buffer, nil))
+	require.Equal(t, []byte("world"), buffer)
+
+	require.NoError(t, w.Delete(ctx, "object", keypath, nil))
+	_, _, err = r.Read(ctx, "object", keypath, nil)
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+	require.ErrorIs(t, r.ReadRange(ctx, "object", keypath, 0, buffer, nil), backend.ErrDoesNotExist)
+
+	var tracker backend.AppendTracker
+	for _, part := range []string{"a", "bb", "ccc"} {
+		tracker, err = w.Append(ctx, "appended", keypath, tracker, []byte(part))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.CloseAppend(ctx, tracker))
+	require.Equal(t, []byte("abbccc"), s.Objects()["prefix/tenant/block/appended"])
+	require.Zero(t, s.Uploads())
+
+	var blockIDs []uuid.UUID
+	bw := backend.NewWriter(w)
+	for i := 0; i < 3; i++ {
+		meta := backend.NewBlockMeta("tenant", uuid.New(), "v2", backend.EncNone, "")
+		require.NoError(t, bw.WriteBlockMeta(ctx, meta))
+		blockIDs = append(blockIDs, meta.BlockID)
+	}
+
+	tenants, err := r.List(ctx, backend.KeyPath{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"tenant"}, tenants)
+
+	var found []string
+	require.NoError(t, r.Find(ctx, keypath, func(m backend.FindMatch) {
+		found = append(found, m.Key)
+		require.Equal(t, LastModified, m.Modified.UTC())
+	}))
+	require.Equal(t, []string{"prefix/tenant/block/appended"}, found)
+
+	require.NoError(t, c.MarkBlockCompacted(blockIDs[0], "tenant"))
+	live, compacted, err := r.ListBlocks(ctx, "tenant")
+	require.NoError(t, err)
+	require.ElementsMatch(t, blockIDs[1:], live)
+	require.Equal(t, blockIDs[:1], compacted)
+
+	compactedMeta, err := c.CompactedBlockMeta(blockIDs[0], "tenant")
+	require.NoError(t, err)
+	require.Equal(t, blockIDs[0], compactedMeta.BlockID)
+	require.Equal(t, LastModified, compactedMeta.CompactedTime.UTC())
+
+	require.NoError(t, c.ClearBlock(blockIDs[0], "tenant"))
+	_, err = c.CompactedBlockMeta(blockIDs[0], "tenant")
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+	require.ErrorIs(t, c.MarkBlockCompacted(blockIDs[0], "tenant"), backend.ErrDoesNotExist)
+}
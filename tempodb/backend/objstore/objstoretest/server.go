@@ -0,0 +1,301 @@
+// Package objstoretest provides an in-memory emulator of the S3 style XML API of object stores to test the
+// backends built on the objstore package with the SDKs of the stores.
+package objstoretest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"hash/crc64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// LastModified is the last modified time of all objects.
+var LastModified = time.Date(2024, 5, 12, 16, 21, 24, 0, time.UTC)
+
+// Server is an in-memory object store serving the subset of the api used by the objstore backends.
+type Server struct {
+	*httptest.Server
+
+	t                *testing.T
+	bucket           string
+	copySourceHeader string
+	authPrefix       string
+
+	mtx     sync.Mutex
+	headers http.Header
+	objects map[string][]byte
+	uploads map[string]map[int][]byte
+}
+
+// NewServer starts a server that is closed at the end of the test. If bucket is set, objects are addressed path
+// style at /<bucket>/<object>, otherwise at /<object>. copySourceHeader is the header that names the source of a
+// server side copy. Every request must carry an Authorization header that starts with authPrefix.
+func NewServer(t *testing.T, bucket, copySourceHeader, authPrefix string) *Server {
+	s := &Server{
+		t:                t,
+		bucket:           bucket,
+		copySourceHeader: copySourceHeader,
+		authPrefix:       authPrefix,
+		headers:          http.Header{},
+		objects:          map[string][]byte{},
+		uploads:          map[string]map[int][]byte{},
+	}
+	s.Server = httptest.NewServer(s)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// RequireHeader makes the server reject requests that don't carry the header.
+func (s *Server) RequireHeader(key, value string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.headers.Set(key, value)
+}
+
+// Objects returns a copy of the stored objects.
+func (s *Server) Objects() map[string][]byte {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	objects := make(map[string][]byte, len(s.objects))
+	for k, v := range s.objects {
+		objects[k] = v
+	}
+	return objects
+}
+
+// Uploads returns the number of multipart uploads in progress.
+func (s *Server) Uploads() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return len(s.uploads)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if auth := r.Header.Get("Authorization"); !assert.True(s.t, auth != "" && strings.HasPrefix(auth, s.authPrefix), "request is not signed: %q", auth) {
+		writeError(w, http.StatusForbidden, "AccessDenied")
+		return
+	}
+	for k := range s.headers {
+		if !assert.Equal(s.t, s.headers.Get(k), r.Header.Get(k), "header %s", k) {
+			writeError(w, http.StatusForbidden, "AccessDenied")
+			return
+		}
+	}
+
+	object := strings.TrimPrefix(r.URL.Path, "/")
+	if s.bucket != "" {
+		if object != s.bucket && !strings.HasPrefix(object, s.bucket+"/") {
+			writeError(w, http.StatusNotFound, "NoSuchBucket")
+			return
+		}
+		object = strings.TrimPrefix(strings.TrimPrefix(object, s.bucket), "/")
+	}
+
+	query := r.URL.Query()
+	switch {
+	case object == "" && r.Method == http.MethodGet:
+		s.list(w, query)
+
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		uploadID := strconv.Itoa(len(s.uploads))
+		s.uploads[uploadID] = map[int][]byte{}
+		writeXML(s.t, w, struct {
+			XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+			Key      string   `xml:"Key"`
+			UploadID string   `xml:"UploadId"`
+		}{Key: object, UploadID: uploadID})
+
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		complete := struct {
+			Parts []struct {
+				PartNumber int    `xml:"PartNumber"`
+				ETag       string `xml:"ETag"`
+			} `xml:"Part"`
+		}{}
+		body, _ := io.ReadAll(r.Body)
+		assert.NoError(s.t, xml.Unmarshal(body, &complete))
+
+		var obj []byte
+		for i, p := range complete.Parts {
+			assert.Equal(s.t, i+1, p.PartNumber)
+			assert.Equal(s.t, etag(p.PartNumber), strings.Trim(p.ETag, `"`))
+			obj = append(obj, s.uploads[query.Get("uploadId")][p.PartNumber]...)
+		}
+		s.objects[object] = obj
+		delete(s.uploads, query.Get("uploadId"))
+		writeXML(s.t, w, struct {
+			XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+			Key     string   `xml:"Key"`
+			ETag    string   `xml:"ETag"`
+		}{Key: object, ETag: `"complete"`})
+
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+		part, _ := io.ReadAll(r.Body)
+		s.uploads[query.Get("uploadId")][partNumber] = part
+		s.setChecksum(w, part)
+		w.Header().Set("ETag", `"`+etag(partNumber)+`"`)
+
+	case r.Method == http.MethodPut && r.Header.Get(s.copySourceHeader) != "":
+		// the source is /<bucket>/<object> or <host>/<object>
+		src := strings.TrimPrefix(r.Header.Get(s.copySourceHeader), "/")
+		src, _ = url.PathUnescape(src[strings.Index(src, "/")+1:])
+		obj, ok := s.objects[src]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		s.objects[object] = obj
+		writeXML(s.t, w, struct {
+			XMLName xml.Name `xml:"CopyObjectResult"`
+			ETag    string   `xml:"ETag"`
+		}{ETag: `"copy"`})
+
+	case r.Method == http.MethodPut:
+		obj, _ := io.ReadAll(r.Body)
+		s.objects[object] = obj
+		s.setChecksum(w, obj)
+
+	case r.Method == http.MethodGet:
+		obj, ok := s.objects[object]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("Last-Modified", LastModified.Format(http.TimeFormat))
+		if rng := r.Header.Get("Range"); rng != "" {
+			var start, end int
+			_, err := fmt.Sscanf(rng, "bytes=%d-%d", &start, &end)
+			assert.NoError(s.t, err)
+			w.Header().Set("Content-Length", strconv.Itoa(end-start+1))
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write(obj[start : end+1])
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(obj)))
+		_, _ = w.Write(obj)
+
+	case r.Method == http.MethodDelete:
+		delete(s.objects, object)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeError(w, http.StatusBadRequest, "InvalidRequest")
+	}
+}
+
+type listObject struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	Size         int    `xml:"Size"`
+}
+
+type listPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+func (s *Server) list(w http.ResponseWriter, query url.Values) {
+	prefix, delimiter, marker := query.Get("prefix"), query.Get("delimiter"), query.Get("marker")
+	maxKeys, err := strconv.Atoi(query.Get("max-keys"))
+	if err != nil {
+		maxKeys = 1000
+	}
+
+	keys := make([]string, 0, len(s.objects))
+	for k := range s.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	res := struct {
+		XMLName        xml.Name     `xml:"ListBucketResult"`
+		IsTruncated    bool         `xml:"IsTruncated"`
+		NextMarker     string       `xml:"NextMarker,omitempty"`
+		Contents       []listObject `xml:"Contents"`
+		CommonPrefixes []listPrefix `xml:"CommonPrefixes"`
+	}{}
+
+	var (
+		prefixes = map[string]struct{}{}
+		next     string
+		count    int
+	)
+	for _, k := range keys {
+		if !strings.HasPrefix(k, prefix) || k <= marker {
+			continue
+		}
+		if count == maxKeys {
+			res.IsTruncated = true
+			break
+		}
+
+		rest := strings.TrimPrefix(k, prefix)
+		if i := strings.Index(rest, delimiter); delimiter != "" && i >= 0 {
+			p := prefix + rest[:i+1]
+			if _, ok := prefixes[p]; !ok {
+				prefixes[p] = struct{}{}
+				res.CommonPrefixes = append(res.CommonPrefixes, listPrefix{Prefix: p})
+				count++
+			}
+			next = p + string(utf8.MaxRune) // skip the remaining objects with this prefix
+			continue
+		}
+
+		res.Contents = append(res.Contents, listObject{
+			Key:          k,
+			LastModified: LastModified.Format(time.RFC3339),
+			Size:         len(s.objects[k]),
+		})
+		next = k
+		count++
+	}
+	// like the object stores, only return the next marker if a delimiter is set
+	if res.IsTruncated && delimiter != "" {
+		res.NextMarker = next
+	}
+
+	writeXML(s.t, w, res)
+}
+
+// setChecksum sets the crc64 of the uploaded data that the clients verify. The header has the prefix of the copy
+// source header, e.g. x-oss-hash-crc64ecma.
+func (s *Server) setChecksum(w http.ResponseWriter, data []byte) {
+	header := strings.TrimSuffix(s.copySourceHeader, "copy-source") + "hash-crc64ecma"
+	w.Header().Set(header, strconv.FormatUint(crc64.Checksum(data, crc64.MakeTable(crc64.ECMA)), 10))
+}
+
+func etag(partNumber int) string {
+	return fmt.Sprintf("etag-%d", partNumber)
+}
+
+func writeXML(t *testing.T, w http.ResponseWriter, v any) {
+	b, err := xml.Marshal(v)
+	assert.NoError(t, err)
+	w.Header().Set("Content-Type", "application/xml")
+	_, _ = w.Write(b)
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message><RequestId>test</RequestId></Error>", code, code)
+}
@@ -0,0 +1,41 @@
+package oss
+
+import (
+	"flag"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+
+	"github.com/grafana/tempo/pkg/util"
+)
+
+type Config struct {
+	Bucket string `yaml:"bucket"`
+	Prefix string `yaml:"prefix"`
+	// Endpoint is the regional endpoint of the bucket, e.g. oss-cn-hangzhou.aliyuncs.com
+	Endpoint          string         `yaml:"endpoint"`
+	AccessKeyID       string         `yaml:"access_key_id"`
+	AccessKeySecret   flagext.Secret `yaml:"access_key_secret"`
+	SecurityToken     flagext.Secret `yaml:"security_token"`
+	Insecure          bool           `yaml:"insecure"`
+	HedgeRequestsAt   time.Duration  `yaml:"hedge_requests_at"`
+	HedgeRequestsUpTo int            `yaml:"hedge_requests_up_to"`
+
+	ListBlocksConcurrency int `yaml:"list_blocks_concurrency"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.Bucket, util.PrefixConfig(prefix, "oss.bucket"), "", "oss bucket to store blocks in.")
+	f.StringVar(&cfg.Prefix, util.PrefixConfig(prefix, "oss.prefix"), "", "oss root directory to store blocks in.")
+	f.StringVar(&cfg.Endpoint, util.PrefixConfig(prefix, "oss.endpoint"), "", "oss regional endpoint of the bucket.")
+	f.StringVar(&cfg.AccessKeyID, util.PrefixConfig(prefix, "oss.access_key_id"), "", "oss access key id.")
+	f.Var(&cfg.AccessKeySecret, util.PrefixConfig(prefix, "oss.access_key_secret"), "oss access key secret.")
+	f.Var(&cfg.SecurityToken, util.PrefixConfig(prefix, "oss.security_token"), "oss security token of temporary credentials.")
+	f.IntVar(&cfg.ListBlocksConcurrency, util.PrefixConfig(prefix, "oss.list_blocks_concurrency"), 3, "number of concurrent list calls to make to backend")
+	cfg.HedgeRequestsUpTo = 2
+}
+
+func (cfg *Config) PathMatches(other *Config) bool {
+	// OSS bucket names are globally unique
+	return cfg.Bucket == other.Bucket && cfg.Prefix == other.Prefix
+}
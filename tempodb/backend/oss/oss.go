@@ -0,0 +1,198 @@
+package oss
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/objstore"
+)
+
+// NewNoConfirm gets the OSS backend without testing it
+func NewNoConfirm(cfg *Config) (backend.RawReader, backend.RawWriter, backend.Compactor, error) {
+	rw, err := internalNew(cfg, false)
+	return rw, rw, rw, err
+}
+
+// New gets the OSS backend
+func New(cfg *Config) (backend.RawReader, backend.RawWriter, backend.Compactor, error) {
+	rw, err := internalNew(cfg, true)
+	return rw, rw, rw, err
+}
+
+func internalNew(cfg *Config, confirm bool) (*objstore.ReaderWriter, error) {
+	if cfg == nil {
+		return nil, errors.New("config is nil")
+	}
+	if cfg.Bucket == "" || cfg.Endpoint == "" {
+		return nil, errors.New("oss bucket and endpoint are required")
+	}
+
+	transport, hedgedTransport, err := objstore.NewTransports(cfg.HedgeRequestsAt, cfg.HedgeRequestsUpTo)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newBucket(cfg, transport)
+	if err != nil {
+		return nil, fmt.Errorf("creating oss client: %w", err)
+	}
+	hedgedClient, err := newBucket(cfg, hedgedTransport)
+	if err != nil {
+		return nil, fmt.Errorf("creating hedged oss client: %w", err)
+	}
+
+	return objstore.New(objstore.Config{
+		Name:                  "oss",
+		Prefix:                cfg.Prefix,
+		ListBlocksConcurrency: cfg.ListBlocksConcurrency,
+	}, &bucket{
+		name:         cfg.Bucket,
+		client:       client,
+		hedgedClient: hedgedClient,
+	}, confirm)
+}
+
+func newBucket(cfg *Config, transport http.RoundTripper) (*oss.Bucket, error) {
+	options := []oss.ClientOption{oss.HTTPClient(&http.Client{Transport: transport})}
+	if token := cfg.SecurityToken.String(); token != "" {
+		options = append(options, oss.SecurityToken(token))
+	}
+
+	client, err := oss.New(endpoint(cfg), cfg.AccessKeyID, cfg.AccessKeySecret.String(), options...)
+	if err != nil {
+		return nil, err
+	}
+	return client.Bucket(cfg.Bucket)
+}
+
+// endpoint returns the endpoint with the scheme the client connects with.
+func endpoint(cfg *Config) string {
+	if cfg.Insecure {
+		return "http://" + cfg.Endpoint
+	}
+	return "https://" + cfg.Endpoint
+}
+
+// bucket implements objstore.Bucket with the OSS SDK. Reads are sent with the hedged client.
+type bucket struct {
+	name         string
+	client       *oss.Bucket
+	hedgedClient *oss.Bucket
+}
+
+var _ objstore.Bucket = (*bucket)(nil)
+
+func (b *bucket) Put(ctx context.Context, object string, data io.Reader, size int64) error {
+	return b.client.PutObject(object, data, oss.ContentLength(size), oss.WithContext(ctx))
+}
+
+func (b *bucket) Get(ctx context.Context, object string, offset, length int64) (io.ReadCloser, objstore.ObjectAttributes, error) {
+	options := []oss.Option{oss.WithContext(ctx)}
+	if length >= 0 {
+		options = append(options, oss.Range(offset, offset+length-1))
+	}
+
+	res, err := b.hedgedClient.DoGetObject(&oss.GetObjectRequest{ObjectKey: object}, options)
+	if err != nil {
+		return nil, objstore.ObjectAttributes{}, objectError(err)
+	}
+
+	attrs, err := objectAttributes(res.Response.Headers)
+	if err != nil {
+		_ = res.Response.Body.Close()
+		return nil, objstore.ObjectAttributes{}, err
+	}
+	return res.Response.Body, attrs, nil
+}
+
+func (b *bucket) Copy(ctx context.Context, src, dst string) error {
+	_, err := b.client.CopyObject(src, dst, oss.WithContext(ctx))
+	return objectError(err)
+}
+
+func (b *bucket) Delete(ctx context.Context, object string) error {
+	return objectError(b.client.DeleteObject(object, oss.WithContext(ctx)))
+}
+
+func (b *bucket) List(ctx context.Context, prefix, delimiter, marker string, maxKeys int) (objstore.ListResult, error) {
+	res, err := b.client.ListObjects(
+		oss.Prefix(prefix),
+		oss.Delimiter(delimiter),
+		oss.Marker(marker),
+		oss.MaxKeys(maxKeys),
+		oss.WithContext(ctx),
+	)
+	if err != nil {
+		return objstore.ListResult{}, err
+	}
+
+	out := objstore.ListResult{
+		Objects:        make([]objstore.ObjectInfo, 0, len(res.Objects)),
+		CommonPrefixes: res.CommonPrefixes,
+		IsTruncated:    res.IsTruncated,
+		NextMarker:     res.NextMarker,
+	}
+	for _, o := range res.Objects {
+		out.Objects = append(out.Objects, objstore.ObjectInfo{Key: o.Key, LastModified: o.LastModified})
+	}
+	return out, nil
+}
+
+func (b *bucket) InitiateMultipartUpload(ctx context.Context, object string) (string, error) {
+	res, err := b.client.InitiateMultipartUpload(object, oss.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	return res.UploadID, nil
+}
+
+func (b *bucket) UploadPart(ctx context.Context, object, uploadID string, partNumber int, data []byte) (string, error) {
+	part, err := b.client.UploadPart(b.upload(object, uploadID), bytes.NewReader(data), int64(len(data)), partNumber, oss.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	return part.ETag, nil
+}
+
+func (b *bucket) CompleteMultipartUpload(ctx context.Context, object, uploadID string, parts []objstore.Part) error {
+	uploadParts := make([]oss.UploadPart, 0, len(parts))
+	for _, p := range parts {
+		uploadParts = append(uploadParts, oss.UploadPart{PartNumber: p.PartNumber, ETag: p.ETag})
+	}
+
+	_, err := b.client.CompleteMultipartUpload(b.upload(object, uploadID), uploadParts, oss.WithContext(ctx))
+	return err
+}
+
+func (b *bucket) upload(object, uploadID string) oss.InitiateMultipartUploadResult {
+	return oss.InitiateMultipartUploadResult{Bucket: b.name, Key: object, UploadID: uploadID}
+}
+
+// objectError returns errors of requests for objects that don't exist as backend.ErrDoesNotExist.
+func objectError(err error) error {
+	var serviceErr oss.ServiceError
+	if errors.As(err, &serviceErr) && serviceErr.StatusCode == http.StatusNotFound {
+		return backend.ErrDoesNotExist
+	}
+	return err
+}
+
+func objectAttributes(header http.Header) (objstore.ObjectAttributes, error) {
+	size, err := strconv.ParseInt(header.Get(oss.HTTPHeaderContentLength), 10, 64)
+	if err != nil {
+		return objstore.ObjectAttributes{}, fmt.Errorf("error parsing content length: %w", err)
+	}
+	lastModified, err := http.ParseTime(header.Get(oss.HTTPHeaderLastModified))
+	if err != nil {
+		return objstore.ObjectAttributes{}, fmt.Errorf("error parsing last modified time: %w", err)
+	}
+	return objstore.ObjectAttributes{Size: size, LastModified: lastModified}, nil
+}
@@ -0,0 +1,47 @@
+package oss
+
+import (
+	"testing"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend/objstore/objstoretest"
+)
+
+func TestRoundTrip(t *testing.T) {
+	// the client addresses buckets path style if the endpoint is an ip
+	s := objstoretest.NewServer(t, "bucket", "x-oss-copy-source", "OSS id:")
+
+	r, w, c, err := New(&Config{
+		Bucket:          "bucket",
+		Prefix:          "prefix",
+		Endpoint:        s.Listener.Addr().String(),
+		AccessKeyID:     "id",
+		AccessKeySecret: flagext.SecretWithValue("secret"),
+		Insecure:        true,
+	})
+	require.NoError(t, err)
+
+	objstoretest.RoundTrip(t, s, r, w, c)
+}
+
+func TestSecurityToken(t *testing.T) {
+	s := objstoretest.NewServer(t, "bucket", "x-oss-copy-source", "OSS id:")
+	s.RequireHeader("x-oss-security-token", "token")
+
+	_, _, _, err := New(&Config{
+		Bucket:          "bucket",
+		Endpoint:        s.Listener.Addr().String(),
+		AccessKeyID:     "id",
+		AccessKeySecret: flagext.SecretWithValue("secret"),
+		SecurityToken:   flagext.SecretWithValue("token"),
+		Insecure:        true,
+	})
+	require.NoError(t, err)
+}
+
+func TestInvalidConfig(t *testing.T) {
+	_, _, _, err := New(&Config{Bucket: "bucket"})
+	require.EqualError(t, err, "oss bucket and endpoint are required")
+}
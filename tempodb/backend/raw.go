@@ -17,7 +17,13 @@ import (
 const (
 	MetaName          = "meta.json"
 	CompactedMetaName = "meta.compacted.json"
-	TenantIndexName   = "index.json.gz"
+	// DeletedMetaName replaces the compacted meta of a block that is tagged for deletion
+	DeletedMetaName = "meta.deleted.json"
+	TenantIndexName = "index.json.gz"
+	// File name for the tenant deletion mark.
+	TenantDeletionMarkName = "tenant-deletion-mark.json"
+	// File name for the dedicated columns recommended for a tenant.
+	DedicatedColumnsRecommendationName = "dedicated-columns-recommendation.json"
 	// File name for the cluster seed file.
 	ClusterSeedFileName = "tempo_cluster_seed.json"
 )
@@ -142,6 +148,48 @@ func (w *writer) WriteTenantIndex(ctx context.Context, tenantID string, meta []*
 	return nil
 }
 
+// WriteTenantDeletionMark implements backend.Writer
+func (w *writer) WriteTenantDeletionMark(ctx context.Context, tenantID string, mark *TenantDeletionMark) error {
+	markBytes, err := mark.marshal()
+	if err != nil {
+		return err
+	}
+
+	return w.w.Write(ctx, TenantDeletionMarkName, KeyPath([]string{tenantID}), bytes.NewReader(markBytes), int64(len(markBytes)), nil)
+}
+
+// WriteDedicatedColumnsRecommendation implements backend.Writer
+func (w *writer) WriteDedicatedColumnsRecommendation(ctx context.Context, tenantID string, rec *DedicatedColumnsRecommendation) error {
+	recBytes, err := rec.marshal()
+	if err != nil {
+		return err
+	}
+
+	return w.w.Write(ctx, DedicatedColumnsRecommendationName, KeyPath([]string{tenantID}), bytes.NewReader(recBytes), int64(len(recBytes)), nil)
+}
+
+// WriteIngesterSnapshotFile implements backend.Writer
+func (w *writer) WriteIngesterSnapshotFile(ctx context.Context, tenantID, ingesterID, path string, data io.Reader, size int64) error {
+	keypath, name := keyPathForIngesterSnapshotFile(tenantID, ingesterID, path)
+	return w.w.Write(ctx, name, keypath, data, size, nil)
+}
+
+// WriteIngesterSnapshot implements backend.Writer
+func (w *writer) WriteIngesterSnapshot(ctx context.Context, tenantID, ingesterID string, snapshot *IngesterSnapshot) error {
+	snapshotBytes, err := snapshot.marshal()
+	if err != nil {
+		return err
+	}
+
+	return w.w.Write(ctx, IngesterSnapshotName, KeyPathForIngesterSnapshot(tenantID, ingesterID), bytes.NewReader(snapshotBytes), int64(len(snapshotBytes)), nil)
+}
+
+// DeleteIngesterSnapshotFile implements backend.Writer
+func (w *writer) DeleteIngesterSnapshotFile(ctx context.Context, tenantID, ingesterID, path string) error {
+	keypath, name := keyPathForIngesterSnapshotFile(tenantID, ingesterID, path)
+	return w.w.Delete(ctx, name, keypath, nil)
+}
+
 // Delete implements backend.Writer
 func (w *writer) Delete(ctx context.Context, name string, keypath KeyPath) error {
 	return w.w.Delete(ctx, name, keypath, nil)
@@ -243,6 +291,81 @@ func (r *reader) TenantIndex(ctx context.Context, tenantID string) (*TenantIndex
 	return i, nil
 }
 
+// TenantDeletionMark implements backend.Reader
+func (r *reader) TenantDeletionMark(ctx context.Context, tenantID string) (*TenantDeletionMark, error) {
+	reader, size, err := r.r.Read(ctx, TenantDeletionMarkName, KeyPath([]string{tenantID}), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	bytes, err := tempo_io.ReadAllWithEstimate(reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &TenantDeletionMark{}
+	err = m.unmarshal(bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// DedicatedColumnsRecommendation implements backend.Reader
+func (r *reader) DedicatedColumnsRecommendation(ctx context.Context, tenantID string) (*DedicatedColumnsRecommendation, error) {
+	reader, size, err := r.r.Read(ctx, DedicatedColumnsRecommendationName, KeyPath([]string{tenantID}), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	bytes, err := tempo_io.ReadAllWithEstimate(reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &DedicatedColumnsRecommendation{}
+	err = rec.unmarshal(bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return rec, nil
+}
+
+// IngesterSnapshot implements backend.Reader
+func (r *reader) IngesterSnapshot(ctx context.Context, tenantID, ingesterID string) (*IngesterSnapshot, error) {
+	reader, size, err := r.r.Read(ctx, IngesterSnapshotName, KeyPathForIngesterSnapshot(tenantID, ingesterID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	bytes, err := tempo_io.ReadAllWithEstimate(reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &IngesterSnapshot{}
+	err = s.unmarshal(bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// IngesterSnapshotFile implements backend.Reader
+func (r *reader) IngesterSnapshotFile(ctx context.Context, tenantID, ingesterID, path string) (io.ReadCloser, int64, error) {
+	keypath, name := keyPathForIngesterSnapshotFile(tenantID, ingesterID, path)
+	return r.r.Read(ctx, name, keypath, nil)
+}
+
 // Find implements backend.Reader
 func (r *reader) Find(ctx context.Context, keypath KeyPath, f FindFunc) error {
 	return r.r.Find(ctx, keypath, f)
@@ -282,6 +405,11 @@ func CompactedMetaFileName(blockID uuid.UUID, tenantID, prefix string) string {
 	return path.Join(prefix, tenantID, blockID.String(), CompactedMetaName)
 }
 
+// DeletedMetaFileName returns the object name for the meta of a block tagged for deletion given a block id and tenantid
+func DeletedMetaFileName(blockID uuid.UUID, tenantID, prefix string) string {
+	return path.Join(prefix, tenantID, blockID.String(), DeletedMetaName)
+}
+
 // RootPath returns the root path for a block given a block id and tenantid
 func RootPath(blockID uuid.UUID, tenantID, prefix string) string {
 	return path.Join(prefix, tenantID, blockID.String())
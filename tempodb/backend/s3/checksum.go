@@ -0,0 +1,294 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	minio "github.com/minio/minio-go/v7"
+)
+
+// multipartUploadOptions returns the options to create a multipart upload with. If a checksum algorithm is
+// configured it is announced to the backend so it validates the checksums of the parts.
+func (rw *readerWriter) multipartUploadOptions(opts minio.PutObjectOptions) minio.PutObjectOptions {
+	if !rw.checksum.IsSet() {
+		return opts
+	}
+	opts.UserMetadata = withMetadata(opts.UserMetadata, "X-Amz-Checksum-Algorithm", rw.checksum.String())
+	return opts
+}
+
+// putObjectPart uploads a part of a multipart upload. If a checksum algorithm is configured the checksum of the
+// part is sent along and recorded in the returned part.
+func (rw *readerWriter) putObjectPart(ctx context.Context, objectName, uploadID string, partNum int, buffer []byte) (minio.ObjectPart, error) {
+	opts := minio.PutObjectPartOptions{}
+
+	var checksum string
+	if rw.checksum.IsSet() {
+		checksum = rw.checksum.ChecksumBytes(buffer).Encoded()
+		opts.CustomHeader = http.Header{}
+		opts.CustomHeader.Set(rw.checksum.Key(), checksum)
+	}
+
+	part, err := rw.core.PutObjectPart(
+		ctx,
+		rw.cfg.Bucket,
+		objectName,
+		uploadID,
+		partNum,
+		bytes.NewReader(buffer),
+		int64(len(buffer)),
+		opts,
+	)
+	if err != nil {
+		return part, err
+	}
+
+	// the backend rejects parts that don't match the checksum, keep ours in case it isn't echoed back
+	if checksum != "" {
+		setPartChecksum(rw.checksum, &part, checksum)
+	}
+	return part, nil
+}
+
+// completeMultipartUpload completes a multipart upload. If a checksum algorithm is configured the checksum of the
+// object is verified against the checksum reported by the backend.
+func (rw *readerWriter) completeMultipartUpload(ctx context.Context, objectName, uploadID string, parts []minio.ObjectPart) (minio.UploadInfo, error) {
+	completeParts := make([]minio.CompletePart, 0, len(parts))
+	for _, p := range parts {
+		completePart := minio.CompletePart{
+			PartNumber: p.PartNumber,
+			ETag:       p.ETag,
+		}
+		switch rw.checksum {
+		case minio.ChecksumCRC32C:
+			completePart.ChecksumCRC32C = p.ChecksumCRC32C
+		case minio.ChecksumSHA256:
+			completePart.ChecksumSHA256 = p.ChecksumSHA256
+		}
+		completeParts = append(completeParts, completePart)
+	}
+
+	opts := minio.PutObjectOptions{}
+
+	var checksum string
+	if rw.checksum.IsSet() {
+		var err error
+		checksum, err = compositeChecksum(rw.checksum, parts)
+		if err != nil {
+			return minio.UploadInfo{}, err
+		}
+		opts.UserMetadata = map[string]string{rw.checksum.Key(): checksum}
+	}
+
+	info, err := rw.core.CompleteMultipartUpload(
+		ctx,
+		rw.cfg.Bucket,
+		objectName,
+		uploadID,
+		completeParts,
+		opts,
+	)
+	if err != nil {
+		return info, err
+	}
+
+	if checksum != "" {
+		return info, verifyChecksum(rw.checksum, objectName, checksum, info)
+	}
+	return info, nil
+}
+
+// putObjectWithChecksum uploads an object with the configured checksum algorithm. Objects that fit into a single
+// part are uploaded with a single request, larger objects are uploaded in parts of which up to
+// PartUploadConcurrency are uploaded in parallel. The checksum of the object is verified on completion.
+func (rw *readerWriter) putObjectWithChecksum(ctx context.Context, objectName string, data io.Reader, size int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	partSize := int64(rw.cfg.PartSize)
+	if size < 0 || size > partSize {
+		var err error
+		_, partSize, _, err = minio.OptimalPartInfo(size, rw.cfg.PartSize)
+		if err != nil {
+			return minio.UploadInfo{}, err
+		}
+	}
+
+	if size >= 0 && size <= partSize {
+		buffer := make([]byte, size)
+		if _, err := io.ReadFull(data, buffer); err != nil {
+			return minio.UploadInfo{}, err
+		}
+
+		checksum := rw.checksum.ChecksumBytes(buffer).Encoded()
+		opts.UserMetadata = withMetadata(opts.UserMetadata, rw.checksum.Key(), checksum)
+
+		info, err := rw.core.PutObject(ctx, rw.cfg.Bucket, objectName, bytes.NewReader(buffer), size, "", "", opts)
+		if err != nil {
+			return info, err
+		}
+		return info, verifyChecksum(rw.checksum, objectName, checksum, info)
+	}
+
+	uploadID, err := rw.core.NewMultipartUpload(ctx, rw.cfg.Bucket, objectName, rw.multipartUploadOptions(opts))
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+
+	info, err := rw.putObjectParts(ctx, objectName, uploadID, data, partSize)
+	if err != nil {
+		_ = rw.core.AbortMultipartUpload(ctx, rw.cfg.Bucket, objectName, uploadID)
+		return info, err
+	}
+	return info, nil
+}
+
+// putObjectParts reads data in parts of partSize, uploads them in parallel and completes the multipart upload.
+func (rw *readerWriter) putObjectParts(ctx context.Context, objectName, uploadID string, data io.Reader, partSize int64) (minio.UploadInfo, error) {
+	concurrency := int(rw.cfg.PartUploadConcurrency)
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mtx       sync.Mutex
+		parts     []minio.ObjectPart
+		uploadErr error
+		sem       = make(chan struct{}, concurrency)
+	)
+
+	for partNum := 1; ; partNum++ {
+		sem <- struct{}{}
+
+		mtx.Lock()
+		failed := uploadErr != nil
+		mtx.Unlock()
+		if failed {
+			<-sem
+			break
+		}
+
+		buffer := make([]byte, partSize)
+		n, err := io.ReadFull(data, buffer)
+		if errors.Is(err, io.EOF) && partNum > 1 {
+			<-sem
+			break
+		}
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			<-sem
+			wg.Wait()
+			return minio.UploadInfo{}, err
+		}
+
+		wg.Add(1)
+		go func(partNum int, buffer []byte) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			part, err := rw.putObjectPart(ctx, objectName, uploadID, partNum, buffer)
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			if err != nil {
+				if uploadErr == nil {
+					uploadErr = fmt.Errorf("error in multipart upload: %w", err)
+				}
+				return
+			}
+			parts = append(parts, part)
+		}(partNum, buffer[:n])
+
+		// a short read is the last part
+		if err != nil {
+			break
+		}
+	}
+	wg.Wait()
+
+	if uploadErr != nil {
+		return minio.UploadInfo{}, uploadErr
+	}
+
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+	return rw.completeMultipartUpload(ctx, objectName, uploadID, parts)
+}
+
+// compositeChecksum returns the checksum of the concatenated raw checksums of the parts, which is what s3 reports
+// as checksum of a multipart upload.
+func compositeChecksum(t minio.ChecksumType, parts []minio.ObjectPart) (string, error) {
+	h := t.Hasher()
+	for _, p := range parts {
+		raw, err := base64.StdEncoding.DecodeString(partChecksum(t, p))
+		if err != nil {
+			return "", fmt.Errorf("invalid %s checksum of part %d: %w", t, p.PartNumber, err)
+		}
+		_, _ = h.Write(raw)
+	}
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// verifyChecksum compares the checksum reported by the backend for an upload with the expected checksum.
+func verifyChecksum(t minio.ChecksumType, objectName, expected string, info minio.UploadInfo) error {
+	actual := uploadChecksum(t, info)
+	// checksums of multipart uploads are suffixed with the number of parts
+	if i := strings.IndexByte(actual, '-'); i >= 0 {
+		actual = actual[:i]
+	}
+
+	if actual == "" {
+		return fmt.Errorf("no %s checksum returned for object %s", t, objectName)
+	}
+	if actual != expected {
+		return fmt.Errorf("%s checksum mismatch for object %s, expected %s, got %s", t, objectName, expected, actual)
+	}
+	return nil
+}
+
+func partChecksum(t minio.ChecksumType, p minio.ObjectPart) string {
+	switch t {
+	case minio.ChecksumCRC32C:
+		return p.ChecksumCRC32C
+	case minio.ChecksumSHA256:
+		return p.ChecksumSHA256
+	}
+	return ""
+}
+
+func setPartChecksum(t minio.ChecksumType, p *minio.ObjectPart, checksum string) {
+	switch t {
+	case minio.ChecksumCRC32C:
+		p.ChecksumCRC32C = checksum
+	case minio.ChecksumSHA256:
+		p.ChecksumSHA256 = checksum
+	}
+}
+
+func uploadChecksum(t minio.ChecksumType, info minio.UploadInfo) string {
+	switch t {
+	case minio.ChecksumCRC32C:
+		return info.ChecksumCRC32C
+	case minio.ChecksumSHA256:
+		return info.ChecksumSHA256
+	}
+	return ""
+}
+
+// withMetadata returns a copy of metadata with the key set, the configured metadata is shared between uploads.
+func withMetadata(metadata map[string]string, key, value string) map[string]string {
+	m := make(map[string]string, len(metadata)+1)
+	for k, v := range metadata {
+		m[k] = v
+	}
+	m[key] = value
+	return m
+}
@@ -0,0 +1,228 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+const partSize = 5 * 1024 * 1024
+
+func TestChecksum(t *testing.T) {
+	for _, algorithm := range []string{"CRC32C", "SHA256"} {
+		t.Run(algorithm, func(t *testing.T) {
+			checksumType, err := (&Config{ChecksumAlgorithm: algorithm}).checksumType()
+			require.NoError(t, err)
+
+			server := &checksumServer{t: t, checksumType: checksumType}
+			_, w, _, err := New(checksumTestConfig(t, server, algorithm))
+			require.NoError(t, err)
+
+			ctx := context.Background()
+
+			// single request
+			small := randomBytes(100)
+			require.NoError(t, w.Write(ctx, "small", backend.KeyPath{"test"}, bytes.NewReader(small), int64(len(small)), nil))
+			assert.Equal(t, small, server.objects["blerg/test/small"])
+
+			// parallel multipart upload
+			large := randomBytes(2*partSize + 100)
+			require.NoError(t, w.Write(ctx, "large", backend.KeyPath{"test"}, bytes.NewReader(large), int64(len(large)), nil))
+			assert.Equal(t, large, server.objects["blerg/test/large"])
+			assert.Equal(t, 3, server.completedParts)
+
+			// append
+			var tracker backend.AppendTracker
+			for i := 0; i < 2; i++ {
+				tracker, err = w.Append(ctx, "append", backend.KeyPath{"test"}, tracker, randomBytes(partSize))
+				require.NoError(t, err)
+			}
+			require.NoError(t, w.CloseAppend(ctx, tracker))
+			assert.Equal(t, 2, server.completedParts)
+		})
+	}
+}
+
+func TestChecksumMismatch(t *testing.T) {
+	server := &checksumServer{t: t, checksumType: minio.ChecksumSHA256, corrupt: true}
+	_, w, _, err := New(checksumTestConfig(t, server, "SHA256"))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	small := randomBytes(100)
+	err = w.Write(ctx, "small", backend.KeyPath{"test"}, bytes.NewReader(small), int64(len(small)), nil)
+	require.ErrorContains(t, err, "SHA256 checksum mismatch for object test/small")
+
+	large := randomBytes(2*partSize + 100)
+	err = w.Write(ctx, "large", backend.KeyPath{"test"}, bytes.NewReader(large), int64(len(large)), nil)
+	require.ErrorContains(t, err, "SHA256 checksum mismatch for object test/large")
+	assert.True(t, server.aborted)
+
+	tracker, err := w.Append(ctx, "append", backend.KeyPath{"test"}, nil, randomBytes(partSize))
+	require.NoError(t, err)
+	require.ErrorContains(t, w.CloseAppend(ctx, tracker), "SHA256 checksum mismatch for object test/append")
+}
+
+func TestChecksumAlgorithmInvalid(t *testing.T) {
+	_, _, _, err := NewNoConfirm(&Config{
+		Bucket:            "blerg",
+		Endpoint:          "localhost:9000",
+		ChecksumAlgorithm: "MD5",
+	})
+	require.ErrorContains(t, err, `unsupported checksum algorithm "MD5"`)
+}
+
+func checksumTestConfig(t *testing.T, server *checksumServer, algorithm string) *Config {
+	s := testServer(t, server.ServeHTTP)
+	return &Config{
+		Region:                "blerg",
+		AccessKey:             "test",
+		SecretKey:             flagext.SecretWithValue("test"),
+		Bucket:                "blerg",
+		Insecure:              true,
+		Endpoint:              s.URL[7:], // [7:] -> strip http://
+		PartSize:              partSize,
+		PartUploadConcurrency: 2,
+		ChecksumAlgorithm:     algorithm,
+	}
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return b
+}
+
+// checksumServer is a minimal s3 server that validates the checksums of uploaded objects and parts and reports
+// the checksums of completed uploads. If corrupt is set it reports wrong checksums for completed uploads.
+type checksumServer struct {
+	t            *testing.T
+	checksumType minio.ChecksumType
+	corrupt      bool
+
+	mtx            sync.Mutex
+	objects        map[string][]byte
+	parts          map[int][]byte
+	completedParts int
+	aborted        bool
+}
+
+func (s *checksumServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	query := r.URL.Query()
+	key := r.URL.Path[1:]
+
+	switch {
+	case r.Method == http.MethodGet:
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+		<ListBucketResult>
+		</ListBucketResult>`))
+
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		assert.Equal(s.t, s.checksumType.String(), r.Header.Get("X-Amz-Checksum-Algorithm"))
+		s.parts = map[int][]byte{}
+		_, _ = fmt.Fprintf(w, `<InitiateMultipartUploadResult><Bucket>blerg</Bucket><Key>%s</Key><UploadId>upload</UploadId></InitiateMultipartUploadResult>`, key)
+
+	case r.Method == http.MethodPut && query.Has("partNumber"):
+		partNumber, err := strconv.Atoi(query.Get("partNumber"))
+		require.NoError(s.t, err)
+
+		body, checksum := s.readBody(r)
+		s.parts[partNumber] = body
+		w.Header().Set("ETag", strconv.Itoa(partNumber))
+		w.Header().Set(s.checksumType.Key(), checksum.Encoded())
+
+	case r.Method == http.MethodPut:
+		body, checksum := s.readBody(r)
+		if s.objects == nil {
+			s.objects = map[string][]byte{}
+		}
+		s.objects[key] = body
+		if s.corrupt {
+			checksum = s.checksumType.ChecksumBytes([]byte("corrupt"))
+		}
+		w.Header().Set(s.checksumType.Key(), checksum.Encoded())
+
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		var complete struct {
+			Parts []minio.CompletePart `xml:"Part"`
+		}
+		require.NoError(s.t, xml.NewDecoder(r.Body).Decode(&complete))
+
+		var object, checksums []byte
+		for _, p := range complete.Parts {
+			part := s.parts[p.PartNumber]
+			checksum := s.checksumType.ChecksumBytes(part)
+			assert.Equal(s.t, checksum.Encoded(), partChecksum(s.checksumType, minio.ObjectPart{
+				ChecksumCRC32C: p.ChecksumCRC32C,
+				ChecksumSHA256: p.ChecksumSHA256,
+			}))
+			object = append(object, part...)
+			checksums = append(checksums, checksum.Raw()...)
+		}
+		if s.corrupt {
+			checksums = []byte("corrupt")
+		}
+		if s.objects == nil {
+			s.objects = map[string][]byte{}
+		}
+		s.objects[key] = object
+		s.completedParts = len(complete.Parts)
+
+		element := "Checksum" + s.checksumType.String()
+		_, _ = fmt.Fprintf(w, `<CompleteMultipartUploadResult><Bucket>blerg</Bucket><Key>%s</Key><ETag>etag</ETag><%s>%s-%d</%s></CompleteMultipartUploadResult>`,
+			key, element, s.checksumType.ChecksumBytes(checksums).Encoded(), len(complete.Parts), element)
+
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		s.aborted = true
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// readBody reads the body of an upload and asserts it matches the checksum sent along.
+func (s *checksumServer) readBody(r *http.Request) ([]byte, minio.Checksum) {
+	body, err := io.ReadAll(r.Body)
+	require.NoError(s.t, err)
+	if r.Header.Get("X-Amz-Content-Sha256") == "STREAMING-AWS4-HMAC-SHA256-PAYLOAD" {
+		body = decodeAWSChunked(s.t, body)
+	}
+
+	checksum := s.checksumType.ChecksumBytes(body)
+	assert.Equal(s.t, checksum.Encoded(), r.Header.Get(s.checksumType.Key()))
+	return body, checksum
+}
+
+// decodeAWSChunked decodes a body sent with streaming signatures, chunks are prefixed with their hex encoded size
+// and signature.
+func decodeAWSChunked(t *testing.T, body []byte) []byte {
+	var decoded []byte
+	for {
+		header, rest, ok := bytes.Cut(body, []byte("\r\n"))
+		require.True(t, ok)
+		size, _, _ := bytes.Cut(header, []byte(";"))
+		n, err := strconv.ParseInt(string(size), 16, 64)
+		require.NoError(t, err)
+		if n == 0 {
+			return decoded
+		}
+		decoded = append(decoded, rest[:n]...)
+		body = rest[n+2:]
+	}
+}
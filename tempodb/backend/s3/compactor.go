@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/tags"
 
 	"github.com/go-kit/log/level"
 	"github.com/google/uuid"
@@ -93,3 +94,127 @@ func (rw *readerWriter) CompactedBlockMeta(blockID uuid.UUID, tenantID string) (
 
 	return out, nil
 }
+
+// TagBlockForDeletion implements backend.DeletionTagger. All objects of the block are tagged with the configured
+// object tags and the deletion tags, then the compacted meta is replaced by the deleted meta so the block is no
+// longer listed. Tagging is idempotent and a failed attempt is retried on the next retention cycle.
+func (rw *readerWriter) TagBlockForDeletion(blockID uuid.UUID, tenantID string, deletionTags map[string]string) error {
+	if len(tenantID) == 0 {
+		return backend.ErrEmptyTenantID
+	}
+	if blockID == uuid.Nil {
+		return backend.ErrEmptyBlockID
+	}
+
+	merged := make(map[string]string, len(rw.cfg.Tags)+len(deletionTags))
+	for k, v := range rw.cfg.Tags {
+		merged[k] = v
+	}
+	for k, v := range deletionTags {
+		merged[k] = v
+	}
+	objectTags, err := tags.NewTags(merged, true)
+	if err != nil {
+		return fmt.Errorf("invalid deletion tags: %w", err)
+	}
+
+	compactedMetaFileName := backend.CompactedMetaFileName(blockID, tenantID, rw.cfg.Prefix)
+	deletedMetaFileName := backend.DeletedMetaFileName(blockID, tenantID, rw.cfg.Prefix)
+
+	err = rw.forEachBlockObject(blockID, tenantID, func(key string) error {
+		if key == compactedMetaFileName || key == deletedMetaFileName {
+			return nil
+		}
+		return rw.core.PutObjectTagging(context.TODO(), rw.cfg.Bucket, key, objectTags, minio.PutObjectTaggingOptions{})
+	})
+	if err != nil {
+		return fmt.Errorf("error tagging block objects for deletion: %w", err)
+	}
+
+	// copy meta.compacted.json to meta.deleted.json
+	_, err = rw.core.CopyObject(
+		context.TODO(),
+		rw.cfg.Bucket,
+		compactedMetaFileName,
+		rw.cfg.Bucket,
+		deletedMetaFileName,
+		nil,
+		minio.CopySrcOptions{},
+		getPutObjectOptions(rw),
+	)
+	if err != nil {
+		return fmt.Errorf("error copying compacted obj meta to deleted obj meta: %w", err)
+	}
+
+	err = rw.core.PutObjectTagging(context.TODO(), rw.cfg.Bucket, deletedMetaFileName, objectTags, minio.PutObjectTaggingOptions{})
+	if err != nil {
+		return fmt.Errorf("error tagging deleted obj meta: %w", err)
+	}
+
+	// delete meta.compacted.json
+	return rw.core.RemoveObject(context.TODO(), rw.cfg.Bucket, compactedMetaFileName, minio.RemoveObjectOptions{})
+}
+
+// UndeleteBlock implements backend.DeletionTagger. The tags of all objects of the block are reset to the configured
+// object tags and the deleted meta is copied to the meta so the block is picked up as a live block on the next poll.
+func (rw *readerWriter) UndeleteBlock(blockID uuid.UUID, tenantID string) error {
+	if len(tenantID) == 0 {
+		return backend.ErrEmptyTenantID
+	}
+	if blockID == uuid.Nil {
+		return backend.ErrEmptyBlockID
+	}
+
+	objectTags, err := tags.NewTags(rw.cfg.Tags, true)
+	if err != nil {
+		return fmt.Errorf("invalid object tags: %w", err)
+	}
+
+	err = rw.forEachBlockObject(blockID, tenantID, func(key string) error {
+		if len(rw.cfg.Tags) == 0 {
+			return rw.core.RemoveObjectTagging(context.TODO(), rw.cfg.Bucket, key, minio.RemoveObjectTaggingOptions{})
+		}
+		return rw.core.PutObjectTagging(context.TODO(), rw.cfg.Bucket, key, objectTags, minio.PutObjectTaggingOptions{})
+	})
+	if err != nil {
+		return fmt.Errorf("error removing deletion tags from block objects: %w", err)
+	}
+
+	deletedMetaFileName := backend.DeletedMetaFileName(blockID, tenantID, rw.cfg.Prefix)
+
+	// copy meta.deleted.json to meta.json
+	_, err = rw.core.CopyObject(
+		context.TODO(),
+		rw.cfg.Bucket,
+		deletedMetaFileName,
+		rw.cfg.Bucket,
+		backend.MetaFileName(blockID, tenantID, rw.cfg.Prefix),
+		nil,
+		minio.CopySrcOptions{},
+		getPutObjectOptions(rw),
+	)
+	if err != nil {
+		return fmt.Errorf("error copying deleted obj meta to obj meta: %w", readError(err))
+	}
+
+	// delete meta.deleted.json
+	return rw.core.RemoveObject(context.TODO(), rw.cfg.Bucket, deletedMetaFileName, minio.RemoveObjectOptions{})
+}
+
+// forEachBlockObject calls fn with the key of every object of the block
+func (rw *readerWriter) forEachBlockObject(blockID uuid.UUID, tenantID string, fn func(key string) error) error {
+	path := backend.RootPath(blockID, tenantID, rw.cfg.Prefix) + "/"
+
+	res, err := rw.core.ListObjects(rw.cfg.Bucket, path, "", "/", 0)
+	if err != nil {
+		return fmt.Errorf("error listing objects in bucket %s: %w", rw.cfg.Bucket, err)
+	}
+
+	for _, obj := range res.Contents {
+		if err := fn(obj.Key); err != nil {
+			return fmt.Errorf("%s: %w", obj.Key, err)
+		}
+	}
+
+	return nil
+}
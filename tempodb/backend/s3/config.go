@@ -2,10 +2,13 @@ package s3
 
 import (
 	"flag"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/grafana/dskit/crypto/tls"
 	"github.com/grafana/dskit/flagext"
+	minio "github.com/minio/minio-go/v7"
 
 	"github.com/grafana/tempo/pkg/util"
 )
@@ -13,17 +16,21 @@ import (
 type Config struct {
 	tls.ClientConfig `yaml:",inline"`
 
-	Bucket            string         `yaml:"bucket"`
-	Prefix            string         `yaml:"prefix"`
-	Endpoint          string         `yaml:"endpoint"`
-	Region            string         `yaml:"region"`
-	AccessKey         string         `yaml:"access_key"`
-	SecretKey         flagext.Secret `yaml:"secret_key"`
-	SessionToken      flagext.Secret `yaml:"session_token"`
-	Insecure          bool           `yaml:"insecure"`
-	PartSize          uint64         `yaml:"part_size"`
-	HedgeRequestsAt   time.Duration  `yaml:"hedge_requests_at"`
-	HedgeRequestsUpTo int            `yaml:"hedge_requests_up_to"`
+	Bucket       string         `yaml:"bucket"`
+	Prefix       string         `yaml:"prefix"`
+	Endpoint     string         `yaml:"endpoint"`
+	Region       string         `yaml:"region"`
+	AccessKey    string         `yaml:"access_key"`
+	SecretKey    flagext.Secret `yaml:"secret_key"`
+	SessionToken flagext.Secret `yaml:"session_token"`
+	Insecure     bool           `yaml:"insecure"`
+	PartSize     uint64         `yaml:"part_size"`
+	// PartUploadConcurrency is the number of parts of a multipart upload that are uploaded in parallel
+	PartUploadConcurrency uint `yaml:"part_upload_concurrency"`
+	// ChecksumAlgorithm is the additional checksum sent with every upload and verified on completion, CRC32C or SHA256
+	ChecksumAlgorithm string        `yaml:"checksum_algorithm"`
+	HedgeRequestsAt   time.Duration `yaml:"hedge_requests_at"`
+	HedgeRequestsUpTo int           `yaml:"hedge_requests_up_to"`
 	// SignatureV2 configures the object storage to use V2 signing instead of V4
 	SignatureV2      bool              `yaml:"signature_v2"`
 	ForcePathStyle   bool              `yaml:"forcepathstyle"`
@@ -47,9 +54,25 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	f.Var(&cfg.SecretKey, util.PrefixConfig(prefix, "s3.secret_key"), "s3 secret key.")
 	f.Var(&cfg.SessionToken, util.PrefixConfig(prefix, "s3.session_token"), "s3 session token.")
 	f.IntVar(&cfg.ListBlocksConcurrency, util.PrefixConfig(prefix, "s3.list_blocks_concurrency"), 3, "number of concurrent list calls to make to backend")
+	f.UintVar(&cfg.PartUploadConcurrency, util.PrefixConfig(prefix, "s3.part_upload_concurrency"), 4, "number of parts of a multipart upload to upload in parallel.")
+	f.StringVar(&cfg.ChecksumAlgorithm, util.PrefixConfig(prefix, "s3.checksum_algorithm"), "", "additional checksum to send with uploads and verify on completion. Valid values are CRC32C and SHA256, leave empty to disable.")
 	cfg.HedgeRequestsUpTo = 2
 }
 
+// checksumType returns the minio checksum type of the configured checksum algorithm.
+func (cfg *Config) checksumType() (minio.ChecksumType, error) {
+	switch strings.ToUpper(cfg.ChecksumAlgorithm) {
+	case "":
+		return minio.ChecksumNone, nil
+	case "CRC32C":
+		return minio.ChecksumCRC32C, nil
+	case "SHA256":
+		return minio.ChecksumSHA256, nil
+	default:
+		return minio.ChecksumNone, fmt.Errorf("unsupported checksum algorithm %q, valid values are CRC32C and SHA256", cfg.ChecksumAlgorithm)
+	}
+}
+
 func (cfg *Config) PathMatches(other *Config) bool {
 	// S3 bucket names are globally unique
 	return cfg.Bucket == other.Bucket && cfg.Prefix == other.Prefix
@@ -36,6 +36,7 @@ type readerWriter struct {
 	cfg        *Config
 	core       *minio.Core
 	hedgedCore *minio.Core
+	checksum   minio.ChecksumType
 }
 
 var (
@@ -100,6 +101,11 @@ func internalNew(cfg *Config, confirm bool) (*readerWriter, error) {
 
 	l := log.Logger
 
+	checksum, err := cfg.checksumType()
+	if err != nil {
+		return nil, err
+	}
+
 	core, err := createCore(cfg, false)
 	if err != nil {
 		return nil, fmt.Errorf("unexpected error creating core: %w", err)
@@ -123,6 +129,7 @@ func internalNew(cfg *Config, confirm bool) (*readerWriter, error) {
 		cfg:        cfg,
 		core:       core,
 		hedgedCore: hedgedCore,
+		checksum:   checksum,
 	}
 	return rw, nil
 }
@@ -130,6 +137,7 @@ func internalNew(cfg *Config, confirm bool) (*readerWriter, error) {
 func getPutObjectOptions(rw *readerWriter) minio.PutObjectOptions {
 	return minio.PutObjectOptions{
 		PartSize:     rw.cfg.PartSize,
+		NumThreads:   rw.cfg.PartUploadConcurrency,
 		UserTags:     rw.cfg.Tags,
 		StorageClass: rw.cfg.StorageClass,
 		UserMetadata: rw.cfg.Metadata,
@@ -148,14 +156,20 @@ func (rw *readerWriter) Write(ctx context.Context, name string, keypath backend.
 
 	putObjectOptions := getPutObjectOptions(rw)
 
-	info, err := rw.core.Client.PutObject(
-		derivedCtx,
-		rw.cfg.Bucket,
-		objName,
-		data,
-		size,
-		putObjectOptions,
-	)
+	var info minio.UploadInfo
+	var err error
+	if rw.checksum.IsSet() {
+		info, err = rw.putObjectWithChecksum(derivedCtx, objName, data, size, putObjectOptions)
+	} else {
+		info, err = rw.core.Client.PutObject(
+			derivedCtx,
+			rw.cfg.Bucket,
+			objName,
+			data,
+			size,
+			putObjectOptions,
+		)
+	}
 	if err != nil {
 		span.SetTag("error", true)
 		return fmt.Errorf("error writing object to s3 backend, object %s: %w", objName, err)
@@ -184,7 +198,7 @@ func (rw *readerWriter) Append(ctx context.Context, name string, keypath backend
 			ctx,
 			rw.cfg.Bucket,
 			objectName,
-			options,
+			rw.multipartUploadOptions(options),
 		)
 		if err != nil {
 			return nil, err
@@ -196,16 +210,7 @@ func (rw *readerWriter) Append(ctx context.Context, name string, keypath backend
 	level.Debug(rw.logger).Log("msg", "appending object to s3", "objectName", objectName)
 
 	a.partNum++
-	objPart, err := rw.core.PutObjectPart(
-		ctx,
-		rw.cfg.Bucket,
-		objectName,
-		a.uploadID,
-		a.partNum,
-		bytes.NewReader(buffer),
-		int64(len(buffer)),
-		minio.PutObjectPartOptions{},
-	)
+	objPart, err := rw.putObjectPart(ctx, objectName, a.uploadID, a.partNum, buffer)
 	if err != nil {
 		return a, fmt.Errorf("error in multipart upload: %w", err)
 	}
@@ -221,22 +226,7 @@ func (rw *readerWriter) CloseAppend(ctx context.Context, tracker backend.AppendT
 	}
 
 	a := tracker.(appendTracker)
-	completeParts := make([]minio.CompletePart, 0)
-	for _, p := range a.parts {
-		completeParts = append(completeParts, minio.CompletePart{
-			PartNumber: p.PartNumber,
-			ETag:       p.ETag,
-		})
-	}
-
-	uploadInfo, err := rw.core.CompleteMultipartUpload(
-		ctx,
-		rw.cfg.Bucket,
-		a.objectName,
-		a.uploadID,
-		completeParts,
-		minio.PutObjectOptions{},
-	)
+	uploadInfo, err := rw.completeMultipartUpload(ctx, a.objectName, a.uploadID, a.parts)
 	if err != nil {
 		return fmt.Errorf("error completing multipart upload, object: %s, obj etag: %s: %w", a.objectName, uploadInfo.ETag, err)
 	}
@@ -0,0 +1,26 @@
+package backend
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// TenantDeletionMark is written to /<tenantid>/tenant-deletion-mark.json when all data of a tenant is
+// requested to be deleted. Components that hold data of the tenant purge it once they find the mark.
+type TenantDeletionMark struct {
+	RequestedTime time.Time `json:"requested_time"`
+}
+
+func NewTenantDeletionMark() *TenantDeletionMark {
+	return &TenantDeletionMark{
+		RequestedTime: time.Now(),
+	}
+}
+
+func (m *TenantDeletionMark) marshal() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+func (m *TenantDeletionMark) unmarshal(buffer []byte) error {
+	return json.Unmarshal(buffer, m)
+}
@@ -0,0 +1,181 @@
+package tempodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	gkLog "github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/azure"
+	"github.com/grafana/tempo/tempodb/backend/cos"
+	"github.com/grafana/tempo/tempodb/backend/gcs"
+	"github.com/grafana/tempo/tempodb/backend/instrumentation"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/backend/oss"
+	"github.com/grafana/tempo/tempodb/backend/s3"
+	"github.com/grafana/tempo/tempodb/blocklist"
+)
+
+var metricColdBlocklistLength = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "tempodb",
+	Name:      "cold_blocklist_length",
+	Help:      "Total number of blocks per tenant in the cold backend.",
+}, []string{"tenant"})
+
+// coldBackend is the read-only secondary backend. Its blocklist is polled from the tenant indexes, or by listing the
+// blocks of tenants without one. Nothing is ever written to it.
+type coldBackend struct {
+	cfg    *ColdBackendConfig
+	r      backend.Reader
+	logger gkLog.Logger
+
+	mtx   sync.RWMutex
+	metas blocklist.PerTenant
+	ids   map[string]map[uuid.UUID]struct{}
+}
+
+func newColdBackend(cfg *ColdBackendConfig, logger gkLog.Logger) (*coldBackend, error) {
+	var rawR backend.RawReader
+	var err error
+
+	// the cold backend is read-only, don't confirm it can be written to
+	switch cfg.Backend {
+	case backend.Local:
+		rawR, _, _, err = local.New(cfg.Local)
+	case backend.GCS:
+		rawR, _, _, err = gcs.NewNoConfirm(cfg.GCS)
+	case backend.S3:
+		rawR, _, _, err = s3.NewNoConfirm(cfg.S3)
+	case backend.Azure:
+		rawR, _, _, err = azure.NewNoConfirm(cfg.Azure)
+	case backend.OSS:
+		rawR, _, _, err = oss.NewNoConfirm(cfg.OSS)
+	case backend.COS:
+		rawR, _, _, err = cos.NewNoConfirm(cfg.COS)
+	default:
+		err = fmt.Errorf("unknown backend %s", cfg.Backend)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cold backend: %w", err)
+	}
+	rawR, _ = instrumentation.NewTenantRawReaderWriter("cold-"+cfg.Backend, rawR, nil)
+
+	return &coldBackend{
+		cfg:    cfg,
+		r:      backend.NewReader(rawR),
+		logger: gkLog.With(logger, "backend", "cold"),
+		metas:  blocklist.PerTenant{},
+		ids:    map[string]map[uuid.UUID]struct{}{},
+	}, nil
+}
+
+// includes returns true if a query starting at start precedes the retention of the primary backend
+func (c *coldBackend) includes(start time.Time) bool {
+	return !start.IsZero() && start.Before(time.Now().Add(-c.cfg.PrimaryRetention))
+}
+
+// blockMetas returns the blocks of the tenant that are only in the cold backend
+func (c *coldBackend) blockMetas(tenantID string) []*backend.BlockMeta {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	metas := make([]*backend.BlockMeta, 0, len(c.metas[tenantID]))
+	return append(metas, c.metas[tenantID]...)
+}
+
+// contains returns true if the block is only in the cold backend
+func (c *coldBackend) contains(tenantID string, blockID uuid.UUID) bool {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	_, ok := c.ids[tenantID][blockID]
+	return ok
+}
+
+// poll updates the blocklist of the cold backend. Blocks that are also in the primary blocklist are left out, they
+// are read from the primary backend.
+func (c *coldBackend) poll(ctx context.Context, primary *blocklist.List) {
+	tenants, err := c.r.Tenants(ctx)
+	if err != nil {
+		level.Error(c.logger).Log("msg", "failed to poll cold blocklist", "err", err)
+		return
+	}
+
+	metas := make(blocklist.PerTenant, len(tenants))
+	ids := make(map[string]map[uuid.UUID]struct{}, len(tenants))
+	for _, tenantID := range tenants {
+		tenantMetas, err := c.pollTenant(ctx, tenantID)
+		if err != nil {
+			level.Error(c.logger).Log("msg", "failed to poll cold blocklist of tenant", "tenant", tenantID, "err", err)
+			// keep the previous blocks of the tenant
+			tenantMetas = c.blockMetas(tenantID)
+		}
+
+		primaryIDs := map[uuid.UUID]struct{}{}
+		for _, m := range primary.Metas(tenantID) {
+			primaryIDs[m.BlockID] = struct{}{}
+		}
+
+		tenantIDs := make(map[uuid.UUID]struct{}, len(tenantMetas))
+		coldMetas := make([]*backend.BlockMeta, 0, len(tenantMetas))
+		for _, m := range tenantMetas {
+			if _, ok := primaryIDs[m.BlockID]; ok {
+				continue
+			}
+			tenantIDs[m.BlockID] = struct{}{}
+			coldMetas = append(coldMetas, m)
+		}
+
+		metas[tenantID] = coldMetas
+		ids[tenantID] = tenantIDs
+		metricColdBlocklistLength.WithLabelValues(tenantID).Set(float64(len(coldMetas)))
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for tenantID := range c.metas {
+		if _, ok := metas[tenantID]; !ok {
+			metricColdBlocklistLength.DeleteLabelValues(tenantID)
+		}
+	}
+	c.metas = metas
+	c.ids = ids
+}
+
+func (c *coldBackend) pollTenant(ctx context.Context, tenantID string) ([]*backend.BlockMeta, error) {
+	idx, err := c.r.TenantIndex(ctx, tenantID)
+	if err == nil {
+		return idx.Meta, nil
+	}
+	if !errors.Is(err, backend.ErrDoesNotExist) {
+		level.Warn(c.logger).Log("msg", "failed to read tenant index of cold backend, listing blocks", "tenant", tenantID, "err", err)
+	}
+
+	blockIDs, _, err := c.r.Blocks(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	metas := make([]*backend.BlockMeta, 0, len(blockIDs))
+	for _, blockID := range blockIDs {
+		m, err := c.r.BlockMeta(ctx, blockID, tenantID)
+		if errors.Is(err, backend.ErrDoesNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		metas = append(metas, m)
+	}
+
+	return metas, nil
+}
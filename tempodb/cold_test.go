@@ -0,0 +1,79 @@
+package tempodb
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto" //nolint:all
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestColdBackend(t *testing.T) {
+	ctx := context.Background()
+
+	// write a block from two days ago into the archive
+	_, archiveW, _, archiveDir := testConfig(t, backend.EncNone, time.Hour)
+
+	start := time.Now().Add(-48 * time.Hour)
+	head, err := archiveW.WAL().NewBlock(&backend.BlockMeta{BlockID: uuid.New(), TenantID: testTenantID}, model.CurrentEncoding)
+	require.NoError(t, err)
+
+	id := test.ValidTraceID(nil)
+	tr := test.MakeTrace(10, id)
+	writeTraceToWal(t, head, model.MustNewSegmentDecoder(model.CurrentEncoding), id, tr, uint32(start.Unix()), uint32(start.Add(time.Minute).Unix()))
+
+	complete, err := archiveW.CompleteBlock(ctx, head)
+	require.NoError(t, err)
+	blockID := complete.BlockMeta().BlockID
+
+	r, _, _, _ := testConfig(t, backend.EncNone, time.Hour, func(cfg *Config) {
+		cfg.ColdBackend = &ColdBackendConfig{
+			Backend:          backend.Local,
+			Local:            &local.Config{Path: path.Join(archiveDir, "traces")},
+			PrimaryRetention: 24 * time.Hour,
+		}
+	})
+	r.EnablePolling(ctx, nil)
+
+	require.Empty(t, r.BlockMetas(testTenantID))
+	require.Empty(t, r.ColdBlockMetas(testTenantID, time.Now().Add(-time.Hour)))
+	metas := r.ColdBlockMetas(testTenantID, time.Now().Add(-72*time.Hour))
+	require.Len(t, metas, 1)
+	require.Equal(t, blockID, metas[0].BlockID)
+
+	// trace by id only includes the cold backend if the range precedes the primary retention
+	found, failedBlocks, err := r.Find(ctx, testTenantID, id, blockID.String(), blockID.String(), start.Add(-time.Hour).Unix(), time.Now().Add(time.Hour).Unix(), common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Nil(t, failedBlocks)
+	require.Len(t, found, 1)
+	require.True(t, proto.Equal(tr, found[0]))
+
+	found, _, err = r.Find(ctx, testTenantID, id, blockID.String(), blockID.String(), 0, 0, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Empty(t, found)
+
+	// blocks requested by id are read from the cold backend
+	rw := r.(*readerWriter)
+	require.Equal(t, rw.cold.r, rw.readerFor(&backend.BlockMeta{BlockID: blockID, TenantID: testTenantID}))
+	require.Equal(t, rw.r, rw.readerFor(&backend.BlockMeta{BlockID: uuid.New(), TenantID: testTenantID}))
+}
+
+func TestColdBackendConfigValidate(t *testing.T) {
+	cfg := &ColdBackendConfig{PrimaryRetention: time.Hour}
+	require.EqualError(t, cfg.validate(), "cold backend requires a backend")
+
+	cfg = &ColdBackendConfig{Backend: backend.Local}
+	require.EqualError(t, cfg.validate(), "cold backend primary retention must be greater than 0")
+
+	cfg = &ColdBackendConfig{Backend: backend.Local, PrimaryRetention: time.Hour}
+	require.NoError(t, cfg.validate())
+}
@@ -0,0 +1,230 @@
+package tempodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+var (
+	metricCompactionBlocksConverted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_blocks_converted_total",
+		Help:      "Total number of blocks converted to the configured block version by their previous version.",
+	}, []string{"version"})
+	metricCompactionConversionOutstandingBlocks = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_conversion_outstanding_blocks",
+		Help:      "Number of owned blocks in an older version remaining to be converted before next maintenance cycle",
+	}, []string{"tenant"})
+)
+
+// ErrConversionNotEnabled is returned by ConversionStatus unless compaction is enabled with conversion.
+var ErrConversionNotEnabled = errors.New("conversion compaction is not enabled")
+
+// ConversionStatus is the progress of converting the blocks in older versions to the configured block version.
+type ConversionStatus struct {
+	Time          time.Time `json:"time"`
+	TargetVersion string    `json:"target_version"`
+	// Done is true once none of the tenants has blocks left to convert.
+	Done       bool                     `json:"done"`
+	InProgress []CompactionJob          `json:"in_progress"`
+	Tenants    []TenantConversionStatus `json:"tenants"`
+}
+
+// TenantConversionStatus is the conversion progress of a tenant.
+type TenantConversionStatus struct {
+	TenantID string `json:"tenant_id"`
+	Blocks   int    `json:"blocks"`
+	// Versions is the number of blocks of the tenant by version.
+	Versions      map[string]int `json:"versions"`
+	PendingBlocks int            `json:"pending_blocks"`
+	PendingBytes  uint64         `json:"pending_bytes"`
+	// OwnedPendingBlocks are the pending blocks converted by this compactor.
+	OwnedPendingBlocks int `json:"owned_pending_blocks"`
+}
+
+// ConversionStatus returns the blocks left to convert. If tenantID is empty all tenants are included.
+func (rw *readerWriter) ConversionStatus(tenantID string) (*ConversionStatus, error) {
+	if rw.compactorCfg == nil || !rw.compactorCfg.ConversionEnabled {
+		return nil, ErrConversionNotEnabled
+	}
+
+	status := &ConversionStatus{
+		Time:          time.Now(),
+		TargetVersion: rw.cfg.Block.Version,
+		Done:          true,
+		InProgress:    []CompactionJob{},
+		Tenants:       []TenantConversionStatus{},
+	}
+
+	for _, j := range rw.inProgressCompactionJobs(tenantID) {
+		if j.Conversion {
+			status.InProgress = append(status.InProgress, j)
+		}
+	}
+
+	tenants := rw.blocklist.Tenants()
+	if tenantID != "" {
+		tenants = []string{tenantID}
+	}
+	sort.Strings(tenants)
+
+	for _, tenant := range tenants {
+		blocklist := rw.blocklist.Metas(tenant)
+		_, toConvert := rw.blocksToConvert(blocklist)
+
+		tenantStatus := TenantConversionStatus{
+			TenantID:      tenant,
+			Blocks:        len(blocklist),
+			Versions:      map[string]int{},
+			PendingBlocks: len(toConvert),
+		}
+		for _, m := range blocklist {
+			tenantStatus.Versions[m.Version]++
+		}
+		for _, m := range toConvert {
+			tenantStatus.PendingBytes += m.Size
+			if rw.compactorSharder.Owns(m.BlockID.String()) {
+				tenantStatus.OwnedPendingBlocks++
+			}
+		}
+
+		if tenantStatus.PendingBlocks > 0 {
+			status.Done = false
+		}
+		status.Tenants = append(status.Tenants, tenantStatus)
+	}
+
+	return status, nil
+}
+
+// blocksToConvert splits the blocklist into the blocks to compact and the blocks in an older version than the
+// configured block version, which are converted instead. The blocks to convert are ordered by version, oldest
+// first, and then by end time, most recent first. Blocks that can't be iterated, i.e. v2 blocks, are compacted
+// as usual.
+func (rw *readerWriter) blocksToConvert(blocklist []*backend.BlockMeta) (toCompact, toConvert []*backend.BlockMeta) {
+	target := versionRank(rw.cfg.Block.Version)
+
+	for _, m := range blocklist {
+		if rank := versionRank(m.Version); rank >= 0 && rank < target && rw.canConvert(m) {
+			toConvert = append(toConvert, m)
+			continue
+		}
+		toCompact = append(toCompact, m)
+	}
+
+	sort.Slice(toConvert, func(i, j int) bool {
+		ri, rj := versionRank(toConvert[i].Version), versionRank(toConvert[j].Version)
+		if ri != rj {
+			return ri < rj
+		}
+		if !toConvert[i].EndTime.Equal(toConvert[j].EndTime) {
+			return toConvert[i].EndTime.After(toConvert[j].EndTime)
+		}
+		return toConvert[i].BlockID.String() < toConvert[j].BlockID.String()
+	})
+
+	return toCompact, toConvert
+}
+
+func (rw *readerWriter) canConvert(meta *backend.BlockMeta) bool {
+	block, err := encoding.OpenBlock(meta, rw.r)
+	if err != nil {
+		return false
+	}
+	_, ok := block.(common.IterableBlock)
+	return ok
+}
+
+// versionRank returns the position of the version in encoding.AllEncodings, which are ordered from oldest to
+// newest, or -1 if the version is unknown.
+func versionRank(version string) int {
+	for i, enc := range encoding.AllEncodings() {
+		if enc.Version() == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// convertBlock re-encodes a block in the configured block version and replaces it with the new block.
+func (rw *readerWriter) convertBlock(ctx context.Context, tenantID string, meta *backend.BlockMeta) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "rw.convertBlock")
+	defer span.Finish()
+
+	startTime := time.Now()
+
+	// Make sure block still exists
+	if _, err := rw.r.BlockMeta(ctx, meta.BlockID, tenantID); err != nil {
+		return err
+	}
+
+	enc, err := encoding.FromVersion(rw.cfg.Block.Version)
+	if err != nil {
+		return err
+	}
+
+	block, err := encoding.OpenBlock(meta, rw.r)
+	if err != nil {
+		return fmt.Errorf("error opening block: %w", err)
+	}
+
+	iterable, ok := block.(common.IterableBlock)
+	if !ok {
+		return fmt.Errorf("converting %s blocks: %w", meta.Version, common.ErrUnsupported)
+	}
+
+	iter, err := iterable.Iterator(ctx)
+	if err != nil {
+		return fmt.Errorf("error iterating block: %w", err)
+	}
+	defer iter.Close()
+
+	inMeta := &backend.BlockMeta{
+		TenantID:          tenantID,
+		BlockID:           uuid.New(),
+		TotalObjects:      meta.TotalObjects,
+		StartTime:         meta.StartTime,
+		EndTime:           meta.EndTime,
+		CompactionLevel:   meta.CompactionLevel,
+		ReplicationFactor: meta.ReplicationFactor,
+		DataEncoding:      meta.DataEncoding,
+		DedicatedColumns:  meta.DedicatedColumns,
+		Encoding:          rw.cfg.Block.Encoding,
+	}
+
+	newMeta, err := enc.CreateBlock(ctx, rw.cfg.Block, inMeta, iter, rw.r, rw.w)
+	if err != nil {
+		return fmt.Errorf("error creating block: %w", err)
+	}
+
+	// mark old block compacted, so it doesn't show up in polling
+	if err := markCompacted(rw, tenantID, []*backend.BlockMeta{meta}, []*backend.BlockMeta{newMeta}); err != nil {
+		return err
+	}
+
+	metricCompactionBlocksConverted.WithLabelValues(meta.Version).Inc()
+	level.Info(rw.logger).Log("msg", "block converted", "tenantID", tenantID, "block", meta.BlockID, "version", meta.Version,
+		"newBlock", newMeta.BlockID, "newVersion", newMeta.Version, "elapsed", time.Since(startTime))
+
+	return nil
+}
+
+func newConversionJob(tenantID string, meta *backend.BlockMeta, window time.Duration) CompactionJob {
+	job := newCompactionJob(tenantID, meta.BlockID.String(), []*backend.BlockMeta{meta}, window)
+	job.Conversion = true
+	return job
+}
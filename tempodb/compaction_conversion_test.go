@@ -0,0 +1,133 @@
+package tempodb
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet3"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
+	"github.com/grafana/tempo/tempodb/pool"
+	"github.com/grafana/tempo/tempodb/wal"
+)
+
+func TestConversionCompaction(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Pool: &pool.Config{
+			MaxWorkers: 10,
+			QueueDepth: 100,
+		},
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 11,
+			BloomFP:              .01,
+			BloomShardSizeBytes:  100_000,
+			Version:              vparquet3.VersionString,
+			Encoding:             backend.EncLZ4_64k,
+			IndexPageSizeBytes:   1000,
+			RowGroupSizeBytes:    30_000_000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:       10,
+		MaxCompactionRange:   24 * time.Hour,
+		MaxCompactionObjects: 1000,
+		MaxBlockBytes:        1024 * 1024 * 1024,
+		MaxTimePerTenant:     time.Hour,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	_, err = c.ConversionStatus("")
+	require.ErrorIs(t, err, ErrConversionNotEnabled)
+
+	rw := r.(*readerWriter)
+	rw.compactorCfg.ConversionEnabled = true
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+
+	blockCount, recordCount := 2, 10
+	cutTestBlocks(t, w, testTenantID, blockCount, recordCount)
+	rw.pollBlocklist()
+
+	oldMetas := map[string]*backend.BlockMeta{}
+	for _, m := range rw.blocklist.Metas(testTenantID) {
+		oldMetas[m.BlockID.String()] = m
+	}
+
+	// upgrade the block version
+	rw.cfg.Block.Version = vparquet4.VersionString
+
+	status, err := c.ConversionStatus("")
+	require.NoError(t, err)
+	require.Equal(t, vparquet4.VersionString, status.TargetVersion)
+	require.False(t, status.Done)
+	require.Len(t, status.Tenants, 1)
+	require.Equal(t, blockCount, status.Tenants[0].Blocks)
+	require.Equal(t, blockCount, status.Tenants[0].PendingBlocks)
+	require.Equal(t, blockCount, status.Tenants[0].OwnedPendingBlocks)
+	require.Equal(t, map[string]int{vparquet3.VersionString: blockCount}, status.Tenants[0].Versions)
+
+	// blocks waiting to be converted are not compacted
+	plan, err := c.CompactionPlan(testTenantID)
+	require.NoError(t, err)
+	require.Empty(t, plan.Tenants[0].Jobs)
+	require.Len(t, plan.Tenants[0].IdleBlocks, blockCount)
+
+	rw.doCompaction(ctx)
+
+	metas := rw.blocklist.Metas(testTenantID)
+	require.Len(t, metas, blockCount)
+	for _, m := range metas {
+		require.Equal(t, vparquet4.VersionString, m.Version)
+		require.Equal(t, recordCount, m.TotalObjects)
+		require.NotContains(t, oldMetas, m.BlockID.String())
+	}
+	require.Len(t, rw.blocklist.CompactedMetas(testTenantID), blockCount)
+
+	// all traces are found in the converted blocks
+	for i := 0; i < blockCount; i++ {
+		for j := 0; j < recordCount; j++ {
+			id := makeTraceID(i, j)
+
+			found := 0
+			for _, m := range metas {
+				block, err := encoding.OpenBlock(m, rw.r)
+				require.NoError(t, err)
+
+				tr, err := block.FindTraceByID(ctx, id, common.DefaultSearchOptions())
+				require.NoError(t, err)
+				if tr != nil {
+					found++
+				}
+			}
+			require.Equal(t, 1, found)
+		}
+	}
+
+	status, err = c.ConversionStatus(testTenantID)
+	require.NoError(t, err)
+	require.True(t, status.Done)
+	require.Empty(t, status.InProgress)
+	require.Equal(t, 0, status.Tenants[0].PendingBlocks)
+	require.Equal(t, map[string]int{vparquet4.VersionString: blockCount}, status.Tenants[0].Versions)
+}
@@ -0,0 +1,135 @@
+package tempodb
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+var (
+	metricCompactionJobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_job_duration_seconds",
+		Help:      "Time spent on a compaction job by compaction level and status.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"level", "status"})
+	metricCompactionJobInputBytes = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_job_input_bytes",
+		Help:      "Total size of the input blocks of a compaction job by compaction level.",
+		Buckets:   prometheus.ExponentialBuckets(1024*1024, 4, 10),
+	}, []string{"level"})
+	metricCompactionJobOutputRowGroups = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_job_output_row_groups",
+		Help:      "Number of row groups written by a compaction job by compaction level.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"level"})
+	metricCompactionObjectsDeduped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_objects_deduped_total",
+		Help:      "Total number of identical copies of objects dropped during compaction.",
+	}, []string{"level"})
+)
+
+// compactionJobStats collects the details of a compaction job. The counters are updated by the
+// callbacks of the compactor, which can be called concurrently.
+type compactionJobStats struct {
+	level  string
+	start  time.Time
+	inputs []*backend.BlockMeta
+
+	objectsCombined atomic.Int64
+	objectsDeduped  atomic.Int64
+	bytesWritten    atomic.Int64
+	spansDiscarded  atomic.Int64
+	tracesSpilled   atomic.Int64
+	bytesSpilled    atomic.Int64
+}
+
+func newCompactionJobStats(level string, inputs []*backend.BlockMeta) *compactionJobStats {
+	return &compactionJobStats{
+		level:  level,
+		start:  time.Now(),
+		inputs: inputs,
+	}
+}
+
+// finish records the job in the span of the job and in the per job metrics.
+func (s *compactionJobStats) finish(span opentracing.Span, outputs []*backend.BlockMeta, err error) {
+	var inputBytes uint64
+	var inputObjects int
+	for _, meta := range s.inputs {
+		inputBytes += meta.Size
+		inputObjects += meta.TotalObjects
+	}
+
+	var outputBytes uint64
+	var outputObjects, outputRowGroups int
+	for _, meta := range outputs {
+		outputBytes += meta.Size
+		outputObjects += meta.TotalObjects
+		outputRowGroups += int(meta.TotalRecords)
+	}
+
+	span.SetTag("level", s.level)
+	span.SetTag("input_blocks", len(s.inputs))
+	span.SetTag("input_objects", inputObjects)
+	span.SetTag("input_bytes", inputBytes)
+	span.SetTag("objects_combined", s.objectsCombined.Load())
+	span.SetTag("objects_deduped", s.objectsDeduped.Load())
+	span.SetTag("spans_discarded", s.spansDiscarded.Load())
+	span.SetTag("traces_spilled", s.tracesSpilled.Load())
+	span.SetTag("bytes_spilled", s.bytesSpilled.Load())
+	span.SetTag("bytes_written", s.bytesWritten.Load())
+	span.SetTag("output_blocks", len(outputs))
+	span.SetTag("output_objects", outputObjects)
+	span.SetTag("output_bytes", outputBytes)
+	span.SetTag("output_row_groups", outputRowGroups)
+
+	status := "success"
+	if err != nil {
+		status = "error"
+		ext.Error.Set(span, true)
+		span.LogKV("error", err.Error())
+	}
+
+	metricCompactionJobDuration.WithLabelValues(s.level, status).Observe(time.Since(s.start).Seconds())
+	metricCompactionJobInputBytes.WithLabelValues(s.level).Observe(float64(inputBytes))
+	if err == nil {
+		metricCompactionJobOutputRowGroups.WithLabelValues(s.level).Observe(float64(outputRowGroups))
+	}
+}
+
+func (s *compactionJobStats) addObjectsCombined(compactionLevel, objs int) {
+	s.objectsCombined.Add(int64(objs))
+	metricCompactionObjectsCombined.WithLabelValues(strconv.Itoa(compactionLevel)).Add(float64(objs))
+}
+
+func (s *compactionJobStats) addObjectsDeduped(compactionLevel, objs int) {
+	s.objectsDeduped.Add(int64(objs))
+	metricCompactionObjectsDeduped.WithLabelValues(strconv.Itoa(compactionLevel)).Add(float64(objs))
+}
+
+func (s *compactionJobStats) addBytesWritten(compactionLevel, bytes int) {
+	s.bytesWritten.Add(int64(bytes))
+	metricCompactionBytesWritten.WithLabelValues(strconv.Itoa(compactionLevel)).Add(float64(bytes))
+}
+
+func (s *compactionJobStats) addSpansDiscarded(spans int) {
+	s.spansDiscarded.Add(int64(spans))
+}
+
+func (s *compactionJobStats) addTraceSpilled(bytes int) {
+	s.tracesSpilled.Add(1)
+	s.bytesSpilled.Add(int64(bytes))
+	metricCompactionTracesSpilled.Inc()
+	metricCompactionBytesSpilled.Add(float64(bytes))
+}
@@ -0,0 +1,210 @@
+package tempodb
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// ErrCompactionNotEnabled is returned by CompactionPlan until compaction has been enabled.
+var ErrCompactionNotEnabled = errors.New("compaction is not enabled")
+
+// CompactionPlan is a snapshot of the compaction jobs known to this compactor. Pending jobs are planned
+// from the current blocklist the same way the next compaction cycle would pick them.
+type CompactionPlan struct {
+	Time         time.Time              `json:"time"`
+	OwnedTenants []string               `json:"owned_tenants"`
+	InProgress   []CompactionJob        `json:"in_progress"`
+	Tenants      []TenantCompactionPlan `json:"tenants"`
+}
+
+// TenantCompactionPlan lists the pending compaction jobs of a tenant.
+type TenantCompactionPlan struct {
+	TenantID string `json:"tenant_id"`
+	// Window is the compaction range used to group blocks of the tenant.
+	Window string          `json:"window"`
+	Blocks int             `json:"blocks"`
+	Jobs   []CompactionJob `json:"jobs"`
+	// IdleBlocks are blocks that are not part of any job. They are either already large enough, have no
+	// compatible neighbours, are in the window that is being handed from active to inactive or are
+	// waiting to be converted.
+	IdleBlocks []string `json:"idle_blocks"`
+}
+
+// CompactionJob is a set of blocks that are compacted together.
+type CompactionJob struct {
+	TenantID string `json:"tenant_id"`
+	// Hash determines the compactor owning the job.
+	Hash            string    `json:"hash"`
+	Owned           bool      `json:"owned"`
+	CompactionLevel uint8     `json:"compaction_level"`
+	WindowStart     time.Time `json:"window_start"`
+	WindowEnd       time.Time `json:"window_end"`
+	InputBlocks     []string  `json:"input_blocks"`
+	InputObjects    int       `json:"input_objects"`
+	InputBytes      uint64    `json:"input_bytes"`
+	// EstimatedOutputBytes is an upper bound. Objects combined during compaction make the output smaller.
+	EstimatedOutputBytes uint64     `json:"estimated_output_bytes"`
+	Started              *time.Time `json:"started,omitempty"`
+	// Conversion jobs re-encode a single block in the configured block version.
+	Conversion bool `json:"conversion,omitempty"`
+}
+
+// CompactionPlan returns the pending and in progress compaction jobs. If tenantID is empty all tenants
+// are included.
+func (rw *readerWriter) CompactionPlan(tenantID string) (*CompactionPlan, error) {
+	if rw.compactorCfg == nil {
+		return nil, ErrCompactionNotEnabled
+	}
+
+	plan := &CompactionPlan{
+		Time:         time.Now(),
+		OwnedTenants: []string{},
+		InProgress:   rw.inProgressCompactionJobs(tenantID),
+		Tenants:      []TenantCompactionPlan{},
+	}
+
+	tenants := rw.blocklist.Tenants()
+	if tenantID != "" {
+		tenants = []string{tenantID}
+	}
+	sort.Strings(tenants)
+
+	for _, tenant := range tenants {
+		tenantPlan := rw.tenantCompactionPlan(tenant)
+		for _, j := range tenantPlan.Jobs {
+			if j.Owned {
+				plan.OwnedTenants = append(plan.OwnedTenants, tenant)
+				break
+			}
+		}
+		plan.Tenants = append(plan.Tenants, tenantPlan)
+	}
+
+	return plan, nil
+}
+
+func (rw *readerWriter) tenantCompactionPlan(tenantID string) TenantCompactionPlan {
+	blocklist := rw.blocklist.Metas(tenantID)
+	window := rw.compactionWindow(tenantID)
+
+	toCompact := blocklist
+	if rw.compactorCfg.ConversionEnabled {
+		toCompact, _ = rw.blocksToConvert(blocklist)
+	}
+
+	plan := TenantCompactionPlan{
+		TenantID:   tenantID,
+		Window:     window.String(),
+		Blocks:     len(blocklist),
+		Jobs:       []CompactionJob{},
+		IdleBlocks: []string{},
+	}
+
+	blockSelector := newTimeWindowBlockSelector(toCompact,
+		window,
+		rw.compactorCfg.MaxCompactionObjects,
+		rw.compactorCfg.MaxBlockBytes,
+		defaultMinInputBlocks,
+		defaultMaxInputBlocks)
+
+	planned := map[uuid.UUID]struct{}{}
+	for {
+		toBeCompacted, hashString := blockSelector.BlocksToCompact()
+		if len(toBeCompacted) == 0 {
+			break
+		}
+
+		job := newCompactionJob(tenantID, hashString, toBeCompacted, window)
+		job.Owned = rw.compactorSharder.Owns(hashString)
+		plan.Jobs = append(plan.Jobs, job)
+
+		for _, m := range toBeCompacted {
+			planned[m.BlockID] = struct{}{}
+		}
+	}
+
+	for _, m := range blocklist {
+		if _, ok := planned[m.BlockID]; !ok {
+			plan.IdleBlocks = append(plan.IdleBlocks, m.BlockID.String())
+		}
+	}
+	sort.Strings(plan.IdleBlocks)
+
+	return plan
+}
+
+// compactionWindow returns the compaction range of the tenant.
+func (rw *readerWriter) compactionWindow(tenantID string) time.Duration {
+	window := rw.compactorOverrides.MaxCompactionRangeForTenant(tenantID)
+	if window == 0 {
+		window = rw.compactorCfg.MaxCompactionRange
+	}
+	return window
+}
+
+func newCompactionJob(tenantID, hash string, blockMetas []*backend.BlockMeta, window time.Duration) CompactionJob {
+	job := CompactionJob{
+		TenantID:        tenantID,
+		Hash:            hash,
+		CompactionLevel: compactionLevelForBlocks(blockMetas),
+		InputBlocks:     make([]string, 0, len(blockMetas)),
+	}
+
+	if len(blockMetas) > 0 && window >= time.Second {
+		// the blocks of a job share the window of their end time, see timeWindowBlockSelector
+		seconds := int64(window / time.Second)
+		job.WindowStart = time.Unix(blockMetas[0].EndTime.Unix()/seconds*seconds, 0).UTC()
+		job.WindowEnd = job.WindowStart.Add(window)
+	}
+
+	for _, m := range blockMetas {
+		job.InputBlocks = append(job.InputBlocks, m.BlockID.String())
+		job.InputObjects += m.TotalObjects
+		job.InputBytes += m.Size
+	}
+	job.EstimatedOutputBytes = job.InputBytes
+
+	return job
+}
+
+func (rw *readerWriter) startCompactionJob(job CompactionJob) {
+	started := time.Now()
+	job.Started = &started
+	job.Owned = true
+
+	rw.compactionJobsMtx.Lock()
+	defer rw.compactionJobsMtx.Unlock()
+
+	if rw.compactionJobs == nil {
+		rw.compactionJobs = map[string]CompactionJob{}
+	}
+	rw.compactionJobs[job.Hash] = job
+}
+
+func (rw *readerWriter) finishCompactionJob(hash string) {
+	rw.compactionJobsMtx.Lock()
+	defer rw.compactionJobsMtx.Unlock()
+
+	delete(rw.compactionJobs, hash)
+}
+
+func (rw *readerWriter) inProgressCompactionJobs(tenantID string) []CompactionJob {
+	rw.compactionJobsMtx.Lock()
+	defer rw.compactionJobsMtx.Unlock()
+
+	jobs := make([]CompactionJob, 0, len(rw.compactionJobs))
+	for _, j := range rw.compactionJobs {
+		if tenantID != "" && j.TenantID != tenantID {
+			continue
+		}
+		jobs = append(jobs, j)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Started.Before(*jobs[j].Started) })
+
+	return jobs
+}
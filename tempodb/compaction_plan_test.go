@@ -0,0 +1,119 @@
+package tempodb
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/pool"
+	"github.com/grafana/tempo/tempodb/wal"
+)
+
+func TestCompactionPlan(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Pool: &pool.Config{
+			MaxWorkers: 10,
+			QueueDepth: 100,
+		},
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 11,
+			BloomFP:              .01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncLZ4_64k,
+			IndexPageSizeBytes:   1000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	_, err = c.CompactionPlan("")
+	require.ErrorIs(t, err, ErrCompactionNotEnabled)
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:       10,
+		MaxCompactionRange:   24 * time.Hour,
+		MaxCompactionObjects: 1000,
+		MaxBlockBytes:        1024 * 1024 * 1024,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+
+	cutTestBlocks(t, w, testTenantID, 2, 2)
+	cutTestBlocks(t, w, testTenantID2, 1, 2)
+
+	rw := r.(*readerWriter)
+	rw.pollBlocklist()
+
+	plan, err := c.CompactionPlan("")
+	require.NoError(t, err)
+	require.Equal(t, []string{testTenantID}, plan.OwnedTenants)
+	require.Empty(t, plan.InProgress)
+	require.Len(t, plan.Tenants, 2)
+
+	// both blocks of the first tenant are compacted together
+	tenantPlan := plan.Tenants[0]
+	require.Equal(t, testTenantID, tenantPlan.TenantID)
+	require.Equal(t, "24h0m0s", tenantPlan.Window)
+	require.Equal(t, 2, tenantPlan.Blocks)
+	require.Empty(t, tenantPlan.IdleBlocks)
+	require.Len(t, tenantPlan.Jobs, 1)
+
+	job := tenantPlan.Jobs[0]
+	require.True(t, job.Owned)
+	require.Len(t, job.InputBlocks, 2)
+	require.Equal(t, 4, job.InputObjects)
+	require.Equal(t, job.InputBytes, job.EstimatedOutputBytes)
+	require.Equal(t, 24*time.Hour, job.WindowEnd.Sub(job.WindowStart))
+	require.Nil(t, job.Started)
+
+	var expectedBytes uint64
+	for _, m := range rw.blocklist.Metas(testTenantID) {
+		require.Contains(t, job.InputBlocks, m.BlockID.String())
+		expectedBytes += m.Size
+	}
+	require.Equal(t, expectedBytes, job.InputBytes)
+
+	// a single block has nothing to be compacted with
+	tenantPlan = plan.Tenants[1]
+	require.Equal(t, testTenantID2, tenantPlan.TenantID)
+	require.Empty(t, tenantPlan.Jobs)
+	require.Equal(t, []string{rw.blocklist.Metas(testTenantID2)[0].BlockID.String()}, tenantPlan.IdleBlocks)
+
+	// in progress jobs are reported until they finish
+	rw.startCompactionJob(job)
+	plan, err = c.CompactionPlan(testTenantID)
+	require.NoError(t, err)
+	require.Len(t, plan.Tenants, 1)
+	require.Len(t, plan.InProgress, 1)
+	require.Equal(t, job.InputBlocks, plan.InProgress[0].InputBlocks)
+	require.NotNil(t, plan.InProgress[0].Started)
+
+	plan, err = c.CompactionPlan(testTenantID2)
+	require.NoError(t, err)
+	require.Empty(t, plan.InProgress)
+
+	rw.finishCompactionJob(job.Hash)
+	plan, err = c.CompactionPlan(testTenantID)
+	require.NoError(t, err)
+	require.Empty(t, plan.InProgress)
+}
@@ -62,6 +62,42 @@ var (
 		Name:      "compaction_outstanding_blocks",
 		Help:      "Number of blocks remaining to be compacted before next maintenance cycle",
 	}, []string{"tenant"})
+	metricCompactionOutstandingJobAge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_outstanding_job_age_seconds",
+		Help:      "Age of the oldest compaction job remaining before next maintenance cycle, based on the end time of its newest block",
+	}, []string{"tenant"})
+	metricCompactionCycleBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_cycle_bytes",
+		Help:      "Number of input bytes compacted for the tenant in its most recent compaction cycle",
+	}, []string{"tenant"})
+	metricCompactionCycleJobs = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_cycle_jobs",
+		Help:      "Number of compaction jobs run for the tenant in its most recent compaction cycle",
+	}, []string{"tenant"})
+	metricCompactionTracesSpilled = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_traces_spilled_total",
+		Help:      "Total number of traces spilled to disk because they exceeded the memory budget of their compaction job.",
+	})
+	metricCompactionBytesSpilled = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_spilled_bytes_total",
+		Help:      "Total estimated size of the traces spilled to disk during compaction.",
+	})
+	metricCompactionCycleBudgetExhausted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_cycle_budget_exhausted_total",
+		Help:      "Total number of compaction cycles ended early because a budget was used up.",
+	}, []string{"budget"})
+)
+
+const (
+	budgetTime  = "time"
+	budgetBytes = "bytes"
+	budgetJobs  = "jobs"
 )
 
 func (rw *readerWriter) compactionLoop(ctx context.Context) {
@@ -107,9 +143,13 @@ func (rw *readerWriter) doCompaction(ctx context.Context) {
 	// Get the meta file of all non-compacted blocks for the given tenant
 	blocklist := rw.blocklist.Metas(tenantID)
 
-	window := rw.compactorOverrides.MaxCompactionRangeForTenant(tenantID)
-	if window == 0 {
-		window = rw.compactorCfg.MaxCompactionRange
+	window := rw.compactionWindow(tenantID)
+
+	// Blocks in older versions are converted before anything is compacted. They are left out of the
+	// compaction jobs so that a block is never converted and compacted at the same time.
+	var toConvert []*backend.BlockMeta
+	if rw.compactorCfg.ConversionEnabled {
+		blocklist, toConvert = rw.blocksToConvert(blocklist)
 	}
 
 	// Select which blocks to compact.
@@ -128,7 +168,69 @@ func (rw *readerWriter) doCompaction(ctx context.Context) {
 
 	start := time.Now()
 
+	// bytes and jobs compacted for this tenant during the cycle
+	var cycleBytes uint64
+	var cycleJobs int
+	defer func() {
+		metricCompactionCycleBytes.WithLabelValues(tenantID).Set(float64(cycleBytes))
+		metricCompactionCycleJobs.WithLabelValues(tenantID).Set(float64(cycleJobs))
+	}()
+
+	limits := rw.cycleBudget(tenantID)
+
+	// after a maintenance cycle bail out
+	budgetExhausted := func() bool {
+		budget := limits.exhausted(time.Since(start), cycleBytes, cycleJobs)
+		if budget == "" {
+			return false
+		}
+		measureOutstandingBlocks(tenantID, blockSelector, rw.compactorSharder.Owns)
+		metricCompactionCycleBudgetExhausted.WithLabelValues(budget).Inc()
+
+		level.Info(rw.logger).Log("msg", "compacted blocks for a maintenance cycle, bailing out", "tenantID", tenantID, "budget", budget, "bytes", cycleBytes, "jobs", cycleJobs)
+		return true
+	}
+
 	level.Debug(rw.logger).Log("msg", "starting compaction cycle", "tenantID", tenantID, "offset", rw.compactorTenantOffset)
+
+	if rw.compactorCfg.ConversionEnabled {
+		var owned []*backend.BlockMeta
+		for _, meta := range toConvert {
+			if rw.compactorSharder.Owns(meta.BlockID.String()) {
+				owned = append(owned, meta)
+			}
+		}
+		metricCompactionConversionOutstandingBlocks.WithLabelValues(tenantID).Set(float64(len(owned)))
+
+		for i, meta := range owned {
+			if ctx.Err() != nil {
+				return
+			}
+
+			level.Info(rw.logger).Log("msg", "Converting block", "tenantID", tenantID, "blockID", meta.BlockID, "version", meta.Version)
+			job := newConversionJob(tenantID, meta, window)
+			rw.startCompactionJob(job)
+			err := rw.convertBlock(ctx, tenantID, meta)
+			rw.finishCompactionJob(job.Hash)
+
+			if errors.Is(err, backend.ErrDoesNotExist) {
+				level.Warn(rw.logger).Log("msg", "unable to find meta during conversion", "blockID", meta.BlockID, "err", err)
+			} else if err != nil {
+				level.Error(rw.logger).Log("msg", "error converting block", "blockID", meta.BlockID, "err", err)
+				metricCompactionErrors.Inc()
+			}
+
+			metricCompactionConversionOutstandingBlocks.WithLabelValues(tenantID).Set(float64(len(owned) - i - 1))
+
+			cycleJobs++
+			cycleBytes += meta.Size
+
+			if budgetExhausted() {
+				return
+			}
+		}
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -148,7 +250,9 @@ func (rw *readerWriter) doCompaction(ctx context.Context) {
 			}
 			level.Info(rw.logger).Log("msg", "Compacting hash", "hashString", hashString)
 			// Compact selected blocks into a larger one
+			rw.startCompactionJob(newCompactionJob(tenantID, hashString, toBeCompacted, window))
 			err := rw.compact(ctx, toBeCompacted, tenantID)
+			rw.finishCompactionJob(hashString)
 
 			if errors.Is(err, backend.ErrDoesNotExist) {
 				level.Warn(rw.logger).Log("msg", "unable to find meta during compaction.  trying again on this block list", "err", err)
@@ -157,23 +261,64 @@ func (rw *readerWriter) doCompaction(ctx context.Context) {
 				metricCompactionErrors.Inc()
 			}
 
-			// after a maintenance cycle bail out
-			if start.Add(rw.compactorCfg.MaxTimePerTenant).Before(time.Now()) {
-				measureOutstandingBlocks(tenantID, blockSelector, rw.compactorSharder.Owns)
+			cycleJobs++
+			for _, meta := range toBeCompacted {
+				cycleBytes += meta.Size
+			}
 
-				level.Info(rw.logger).Log("msg", "compacted blocks for a maintenance cycle, bailing out", "tenantID", tenantID)
+			if budgetExhausted() {
 				return
 			}
 		}
 	}
 }
 
-func (rw *readerWriter) compact(ctx context.Context, blockMetas []*backend.BlockMeta, tenantID string) error {
+// cycleBudget limits the work done for a tenant in a compaction cycle
+type cycleBudget struct {
+	maxTime  time.Duration
+	maxBytes uint64
+	maxJobs  int
+}
+
+// cycleBudget returns the compaction cycle budget of the tenant. The byte and job budgets of the tenant
+// overrides replace the ones of the compactor configuration.
+func (rw *readerWriter) cycleBudget(tenantID string) cycleBudget {
+	b := cycleBudget{
+		maxTime:  rw.compactorCfg.MaxTimePerTenant,
+		maxBytes: rw.compactorOverrides.MaxCompactionBytesPerCycleForTenant(tenantID),
+		maxJobs:  rw.compactorOverrides.MaxCompactionJobsPerCycleForTenant(tenantID),
+	}
+	if b.maxBytes == 0 {
+		b.maxBytes = rw.compactorCfg.MaxBytesPerTenant
+	}
+	if b.maxJobs == 0 {
+		b.maxJobs = rw.compactorCfg.MaxJobsPerTenant
+	}
+	return b
+}
+
+// exhausted returns the name of the first budget that has been used up by a compaction cycle, or an
+// empty string if the cycle can continue. Byte and job budgets are disabled when set to 0.
+func (b cycleBudget) exhausted(elapsed time.Duration, bytes uint64, jobs int) string {
+	switch {
+	case elapsed > b.maxTime:
+		return budgetTime
+	case b.maxBytes > 0 && bytes >= b.maxBytes:
+		return budgetBytes
+	case b.maxJobs > 0 && jobs >= b.maxJobs:
+		return budgetJobs
+	}
+
+	return ""
+}
+
+func (rw *readerWriter) compact(ctx context.Context, blockMetas []*backend.BlockMeta, tenantID string) (err error) {
 	level.Debug(rw.logger).Log("msg", "beginning compaction", "num blocks compacting", len(blockMetas))
 
 	// todo - add timeout?
 	span, ctx := opentracing.StartSpanFromContext(ctx, "rw.compact")
 	defer span.Finish()
+	span.SetTag("tenant", tenantID)
 
 	traceID, _ := tracing.ExtractTraceID(ctx)
 	if traceID != "" {
@@ -184,12 +329,22 @@ func (rw *readerWriter) compact(ctx context.Context, blockMetas []*backend.Block
 		return nil
 	}
 
-	var err error
 	startTime := time.Now()
 
+	compactionLevel := compactionLevelForBlocks(blockMetas)
+	compactionLevelLabel := strconv.Itoa(int(compactionLevel))
+
+	// the details of the job are recorded once it's done, whether it succeeded or not
+	stats := newCompactionJobStats(compactionLevelLabel, blockMetas)
+	var newCompactedBlocks []*backend.BlockMeta
+	defer func() {
+		stats.finish(span, newCompactedBlocks, err)
+	}()
+
 	var totalRecords int
 	for _, blockMeta := range blockMetas {
 		level.Info(rw.logger).Log("msg", "compacting block", "block", fmt.Sprintf("%+v", blockMeta))
+		span.LogKV("event", "input block", "blockID", blockMeta.BlockID.String(), "objects", blockMeta.TotalObjects, "bytes", blockMeta.Size)
 		totalRecords += blockMeta.TotalObjects
 
 		// Make sure block still exists
@@ -204,9 +359,6 @@ func (rw *readerWriter) compact(ctx context.Context, blockMetas []*backend.Block
 		return err
 	}
 
-	compactionLevel := compactionLevelForBlocks(blockMetas)
-	compactionLevelLabel := strconv.Itoa(int(compactionLevel))
-
 	combiner := instrumentedObjectCombiner{
 		tenant:               tenantID,
 		inner:                rw.compactorSharder,
@@ -218,19 +370,20 @@ func (rw *readerWriter) compact(ctx context.Context, blockMetas []*backend.Block
 		ChunkSizeBytes:     rw.compactorCfg.ChunkSizeBytes,
 		FlushSizeBytes:     rw.compactorCfg.FlushSizeBytes,
 		IteratorBufferSize: rw.compactorCfg.IteratorBufferSize,
+		Concurrency:        rw.compactorCfg.JobConcurrency,
 		OutputBlocks:       outputBlocks,
 		Combiner:           combiner,
 		MaxBytesPerTrace:   rw.compactorOverrides.MaxBytesPerTraceForTenant(tenantID),
-		BytesWritten: func(compactionLevel, bytes int) {
-			metricCompactionBytesWritten.WithLabelValues(strconv.Itoa(compactionLevel)).Add(float64(bytes))
-		},
-		ObjectsCombined: func(compactionLevel, objs int) {
-			metricCompactionObjectsCombined.WithLabelValues(strconv.Itoa(compactionLevel)).Add(float64(objs))
-		},
+		MemoryBudgetBytes:  rw.compactorCfg.MemoryBudgetBytes,
+		SpillPath:          rw.compactorCfg.SpillPath,
+		BytesWritten:       stats.addBytesWritten,
+		ObjectsCombined:    stats.addObjectsCombined,
+		ObjectsDeduped:     stats.addObjectsDeduped,
 		ObjectsWritten: func(compactionLevel, objs int) {
 			metricCompactionObjectsWritten.WithLabelValues(strconv.Itoa(compactionLevel)).Add(float64(objs))
 		},
 		SpansDiscarded: func(traceId, rootSpanName, rootServiceName string, spans int) {
+			stats.addSpansDiscarded(spans)
 			rw.compactorSharder.RecordDiscardedSpans(spans, tenantID, traceId, rootSpanName, rootServiceName)
 		},
 		DisconnectedTrace: func() {
@@ -239,12 +392,13 @@ func (rw *readerWriter) compact(ctx context.Context, blockMetas []*backend.Block
 		RootlessTrace: func() {
 			dataquality.WarnRootlessTrace(tenantID, dataquality.PhaseTraceCompactorCombine)
 		},
+		TraceSpilled: stats.addTraceSpilled,
 	}
 
 	compactor := enc.NewCompactor(opts)
 
 	// Compact selected blocks into a larger one
-	newCompactedBlocks, err := compactor.Compact(ctx, rw.logger, rw.r, rw.w, blockMetas)
+	newCompactedBlocks, err = compactor.Compact(ctx, rw.logger, rw.r, rw.w, blockMetas)
 	if err != nil {
 		return err
 	}
@@ -304,6 +458,7 @@ func markCompacted(rw *readerWriter, tenantID string, oldBlocks, newBlocks []*ba
 func measureOutstandingBlocks(tenantID string, blockSelector CompactionBlockSelector, owned func(hash string) bool) {
 	// count number of per-tenant outstanding blocks before next maintenance cycle
 	var totalOutstandingBlocks int
+	var oldestJob time.Time
 	for {
 		leftToBeCompacted, hashString := blockSelector.BlocksToCompact()
 		if len(leftToBeCompacted) == 0 {
@@ -314,8 +469,29 @@ func measureOutstandingBlocks(tenantID string, blockSelector CompactionBlockSele
 			continue
 		}
 		totalOutstandingBlocks += len(leftToBeCompacted)
+
+		// a job is ready once its newest block is written
+		if jobTime := newestEndTime(leftToBeCompacted); oldestJob.IsZero() || jobTime.Before(oldestJob) {
+			oldestJob = jobTime
+		}
 	}
 	metricCompactionOutstandingBlocks.WithLabelValues(tenantID).Set(float64(totalOutstandingBlocks))
+
+	var age time.Duration
+	if !oldestJob.IsZero() {
+		age = time.Since(oldestJob)
+	}
+	metricCompactionOutstandingJobAge.WithLabelValues(tenantID).Set(age.Seconds())
+}
+
+func newestEndTime(blockMetas []*backend.BlockMeta) time.Time {
+	var newest time.Time
+	for _, m := range blockMetas {
+		if m.EndTime.After(newest) {
+			newest = m.EndTime
+		}
+	}
+	return newest
 }
 
 func compactionLevelForBlocks(blockMetas []*backend.BlockMeta) uint8 {
@@ -49,6 +49,8 @@ type mockOverrides struct {
 	blockRetention      time.Duration
 	maxBytesPerTrace    int
 	maxCompactionWindow time.Duration
+	maxBytesPerCycle    uint64
+	maxJobsPerCycle     int
 }
 
 func (m *mockOverrides) BlockRetentionForTenant(_ string) time.Duration {
@@ -63,6 +65,14 @@ func (m *mockOverrides) MaxCompactionRangeForTenant(_ string) time.Duration {
 	return m.maxCompactionWindow
 }
 
+func (m *mockOverrides) MaxCompactionBytesPerCycleForTenant(_ string) uint64 {
+	return m.maxBytesPerCycle
+}
+
+func (m *mockOverrides) MaxCompactionJobsPerCycleForTenant(_ string) int {
+	return m.maxJobsPerCycle
+}
+
 func TestCompactionRoundtrip(t *testing.T) {
 	for _, enc := range encoding.AllEncodings() {
 		version := enc.Version()
@@ -494,6 +504,9 @@ func TestCompactionMetrics(t *testing.T) {
 	bytesStart, err := test.GetCounterVecValue(metricCompactionBytesWritten, "0")
 	assert.NoError(t, err)
 
+	jobsStart, err := test.GetHistogramVecSampleCount(metricCompactionJobDuration, "0", "success")
+	assert.NoError(t, err)
+
 	// compact everything
 	err = rw.compact(ctx, rw.blocklist.Metas(testTenantID), testTenantID)
 	assert.NoError(t, err)
@@ -510,6 +523,10 @@ func TestCompactionMetrics(t *testing.T) {
 	bytesEnd, err := test.GetCounterVecValue(metricCompactionBytesWritten, "0")
 	assert.NoError(t, err)
 	assert.Greater(t, bytesEnd, bytesStart) // calculating the exact bytes requires knowledge of the bytes as written in the blocks.  just make sure it goes up
+
+	jobsEnd, err := test.GetHistogramVecSampleCount(metricCompactionJobDuration, "0", "success")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), jobsEnd-jobsStart)
 }
 
 func TestCompactionIteratesThroughTenants(t *testing.T) {
@@ -574,6 +591,75 @@ func TestCompactionIteratesThroughTenants(t *testing.T) {
 	assert.Equal(t, 1, len(rw.blocklist.Metas(testTenantID2)))
 }
 
+func TestCompactionCycleBudgetExhausted(t *testing.T) {
+	tcs := []struct {
+		name     string
+		budget   cycleBudget
+		elapsed  time.Duration
+		bytes    uint64
+		jobs     int
+		expected string
+	}{
+		{
+			name:    "within time budget",
+			budget:  cycleBudget{maxTime: time.Minute},
+			elapsed: time.Second,
+			bytes:   1_000_000,
+			jobs:    100,
+		},
+		{
+			name:     "time budget",
+			budget:   cycleBudget{maxTime: time.Minute},
+			elapsed:  2 * time.Minute,
+			expected: budgetTime,
+		},
+		{
+			name:    "within byte budget",
+			budget:  cycleBudget{maxTime: time.Minute, maxBytes: 100},
+			elapsed: time.Second,
+			bytes:   99,
+		},
+		{
+			name:     "byte budget",
+			budget:   cycleBudget{maxTime: time.Minute, maxBytes: 100},
+			elapsed:  time.Second,
+			bytes:    100,
+			expected: budgetBytes,
+		},
+		{
+			name:     "job budget",
+			budget:   cycleBudget{maxTime: time.Minute, maxJobs: 2},
+			elapsed:  time.Second,
+			jobs:     2,
+			expected: budgetJobs,
+		},
+		{
+			name:     "time budget checked first",
+			budget:   cycleBudget{maxTime: time.Minute, maxBytes: 100, maxJobs: 2},
+			elapsed:  2 * time.Minute,
+			bytes:    100,
+			jobs:     2,
+			expected: budgetTime,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.budget.exhausted(tc.elapsed, tc.bytes, tc.jobs))
+		})
+	}
+}
+
+func TestCompactionCycleBudgetOverrides(t *testing.T) {
+	cfg := &CompactorConfig{MaxTimePerTenant: time.Minute, MaxBytesPerTenant: 100, MaxJobsPerTenant: 2}
+
+	rw := &readerWriter{compactorCfg: cfg, compactorOverrides: &mockOverrides{}}
+	require.Equal(t, cycleBudget{maxTime: time.Minute, maxBytes: 100, maxJobs: 2}, rw.cycleBudget(testTenantID))
+
+	rw.compactorOverrides = &mockOverrides{maxBytesPerCycle: 1000, maxJobsPerCycle: 20}
+	require.Equal(t, cycleBudget{maxTime: time.Minute, maxBytes: 1000, maxJobs: 20}, rw.cycleBudget(testTenantID))
+}
+
 func TestCompactionHonorsBlockStartEndTimes(t *testing.T) {
 	for _, enc := range encoding.AllEncodings() {
 		version := enc.Version()
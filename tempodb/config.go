@@ -12,8 +12,10 @@ import (
 	"github.com/grafana/tempo/pkg/cache"
 	azure "github.com/grafana/tempo/tempodb/backend/azure/config"
 	backend_cache "github.com/grafana/tempo/tempodb/backend/cache"
+	"github.com/grafana/tempo/tempodb/backend/cos"
 	"github.com/grafana/tempo/tempodb/backend/gcs"
 	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/backend/oss"
 	"github.com/grafana/tempo/tempodb/backend/s3"
 	"github.com/grafana/tempo/tempodb/encoding"
 	"github.com/grafana/tempo/tempodb/encoding/common"
@@ -23,6 +25,7 @@ import (
 
 const (
 	DefaultBlocklistPoll             = 5 * time.Minute
+	firstPollRetryInterval           = 10 * time.Second
 	DefaultMaxTimePerTenant          = 5 * time.Minute
 	DefaultBlocklistPollConcurrency  = uint(50)
 	DefaultRetentionConcurrency      = uint(10)
@@ -62,6 +65,8 @@ type Config struct {
 	GCS     *gcs.Config   `yaml:"gcs"`
 	S3      *s3.Config    `yaml:"s3"`
 	Azure   *azure.Config `yaml:"azure"`
+	OSS     *oss.Config   `yaml:"oss"`
+	COS     *cos.Config   `yaml:"cos"`
 
 	// legacy cache config. this is loaded by tempodb and added to the cache
 	// provider on construction
@@ -71,6 +76,37 @@ type Config struct {
 	Redis           *redis.Config           `yaml:"redis"`
 
 	BloomCacheCfg backend_cache.BloomConfig `yaml:",inline"`
+
+	// ColdBackend is an optional read-only backend with archived blocks
+	ColdBackend *ColdBackendConfig `yaml:"cold_backend,omitempty"`
+}
+
+// ColdBackendConfig configures a secondary read-only backend, e.g. a bucket with blocks restored from an archive
+// storage class. Its blocks are included in searches and trace by ID lookups that start before the retention of
+// the primary backend.
+type ColdBackendConfig struct {
+	Backend string        `yaml:"backend"`
+	Local   *local.Config `yaml:"local"`
+	GCS     *gcs.Config   `yaml:"gcs"`
+	S3      *s3.Config    `yaml:"s3"`
+	Azure   *azure.Config `yaml:"azure"`
+	OSS     *oss.Config   `yaml:"oss"`
+	COS     *cos.Config   `yaml:"cos"`
+
+	// PrimaryRetention is the retention of the blocks in the primary backend
+	PrimaryRetention time.Duration `yaml:"primary_retention"`
+}
+
+func (cfg *ColdBackendConfig) validate() error {
+	if cfg.Backend == "" {
+		return errors.New("cold backend requires a backend")
+	}
+
+	if cfg.PrimaryRetention <= 0 {
+		return errors.New("cold backend primary retention must be greater than 0")
+	}
+
+	return nil
 }
 
 type CacheControlConfig struct {
@@ -123,6 +159,9 @@ type CompactorConfig struct {
 	ChunkSizeBytes          uint32        `yaml:"v2_in_buffer_bytes"`
 	FlushSizeBytes          uint32        `yaml:"v2_out_buffer_bytes"`
 	IteratorBufferSize      int           `yaml:"v2_prefetch_traces_count"`
+	JobConcurrency          int           `yaml:"job_concurrency"`
+	MemoryBudgetBytes       int           `yaml:"memory_budget_bytes"`
+	SpillPath               string        `yaml:"spill_path"`
 	MaxCompactionRange      time.Duration `yaml:"compaction_window"`
 	MaxCompactionObjects    int           `yaml:"max_compaction_objects"`
 	MaxBlockBytes           uint64        `yaml:"max_block_bytes"`
@@ -130,7 +169,30 @@ type CompactorConfig struct {
 	CompactedBlockRetention time.Duration `yaml:"compacted_block_retention"`
 	RetentionConcurrency    uint          `yaml:"retention_concurrency"`
 	MaxTimePerTenant        time.Duration `yaml:"max_time_per_tenant"`
+	MaxBytesPerTenant       uint64        `yaml:"max_bytes_per_tenant"`
+	MaxJobsPerTenant        int           `yaml:"max_jobs_per_tenant"`
 	CompactionCycle         time.Duration `yaml:"compaction_cycle"`
+	// ConversionEnabled converts blocks in older versions to the configured block version before
+	// compacting, regardless of their size.
+	ConversionEnabled bool `yaml:"conversion_enabled"`
+	// DeletionTagging tags the objects of compacted blocks instead of deleting them
+	DeletionTagging DeletionTaggingConfig `yaml:"deletion_tagging"`
+}
+
+// DeletionTaggingConfig configures tagging the objects of compacted blocks for deletion by a bucket lifecycle rule
+// once the compacted block retention has passed. The lifecycle rule has to match the tags and purges the objects
+// after its own grace period. Until then a block can be restored with tempo-cli.
+type DeletionTaggingConfig struct {
+	Enabled bool              `yaml:"enabled"`
+	Tags    map[string]string `yaml:"tags"`
+}
+
+func (cfg DeletionTaggingConfig) validate() error {
+	if cfg.Enabled && len(cfg.Tags) == 0 {
+		return errors.New("deletion tagging requires at least one tag")
+	}
+
+	return nil
 }
 
 func (compactorConfig CompactorConfig) validate() error {
@@ -138,6 +200,63 @@ func (compactorConfig CompactorConfig) validate() error {
 		return errors.New("Compaction window can't be 0")
 	}
 
+	if err := compactorConfig.DeletionTagging.validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ScrubberConfig contains the options of the background block verification
+type ScrubberConfig struct {
+	Enabled        bool          `yaml:"enabled"`
+	Interval       time.Duration `yaml:"interval"`
+	BlocksPerCycle int           `yaml:"blocks_per_cycle"`
+}
+
+func (cfg ScrubberConfig) validate() error {
+	if cfg.Interval <= 0 {
+		return errors.New("scrubber interval must be greater than 0")
+	}
+
+	if cfg.BlocksPerCycle <= 0 {
+		return errors.New("scrubber blocks per cycle must be greater than 0")
+	}
+
+	return nil
+}
+
+// DedicatedColumnsConfig contains the options of the dedicated columns recommendations
+type DedicatedColumnsConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Interval time.Duration `yaml:"interval"`
+	// BlocksPerTenant is the number of the most recent blocks of a tenant that are analyzed
+	BlocksPerTenant int `yaml:"blocks_per_tenant"`
+	// MinShare is the fraction of the attribute bytes of its scope an attribute needs to be recommended
+	MinShare float64 `yaml:"min_share"`
+	// AutoApply stores the recommended columns in the user-configurable overrides of the tenant
+	AutoApply bool `yaml:"auto_apply"`
+	// MaxChanges is the maximum number of dedicated columns that are added or removed per tenant each interval
+	MaxChanges int `yaml:"max_changes"`
+}
+
+func (cfg DedicatedColumnsConfig) validate() error {
+	if cfg.Interval <= 0 {
+		return errors.New("dedicated columns interval must be greater than 0")
+	}
+
+	if cfg.BlocksPerTenant <= 0 {
+		return errors.New("dedicated columns blocks per tenant must be greater than 0")
+	}
+
+	if cfg.MinShare < 0 || cfg.MinShare >= 1 {
+		return errors.New("dedicated columns min share must be between 0 and 1")
+	}
+
+	if cfg.AutoApply && cfg.MaxChanges <= 0 {
+		return errors.New("dedicated columns max changes must be greater than 0 to auto apply")
+	}
+
 	return nil
 }
 
@@ -174,5 +293,11 @@ func validateConfig(cfg *Config) error {
 		return fmt.Errorf("block version validation failed: %w", err)
 	}
 
+	if cfg.ColdBackend != nil {
+		if err := cfg.ColdBackend.validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
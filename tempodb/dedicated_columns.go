@@ -0,0 +1,299 @@
+package tempodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+const (
+	DefaultDedicatedColumnsInterval        = time.Hour
+	DefaultDedicatedColumnsBlocksPerTenant = 5
+	DefaultDedicatedColumnsMinShare        = 0.01
+	DefaultDedicatedColumnsMaxChanges      = 2
+
+	// maxRecommendedAttributes caps the number of attributes per scope listed in a recommendation
+	maxRecommendedAttributes = 50
+
+	dedicatedColumnsResultOK      = "ok"
+	dedicatedColumnsResultError   = "error"
+	dedicatedColumnsResultSkipped = "skipped"
+)
+
+var (
+	metricDedicatedColumnsRecommendations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "dedicated_columns_recommendations_total",
+		Help:      "Total number of dedicated columns recommendations by result.",
+	}, []string{"result"})
+	metricDedicatedColumnsApplied = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "dedicated_columns_applied_changes_total",
+		Help:      "Total number of dedicated columns added to or removed from the overrides of a tenant.",
+	}, []string{"tenant"})
+)
+
+// dedicatedColumnsScopes are the attribute scopes that support dedicated columns
+var dedicatedColumnsScopes = []traceql.AttributeScope{traceql.AttributeScopeResource, traceql.AttributeScopeSpan}
+
+// DedicatedColumnsOverrides reads and updates the dedicated columns of tenants.
+type DedicatedColumnsOverrides interface {
+	DedicatedColumns(tenantID string) backend.DedicatedColumns
+	SetDedicatedColumns(ctx context.Context, tenantID string, dcs backend.DedicatedColumns) error
+}
+
+// EnableDedicatedColumnsRecommendations starts analyzing the recent blocks of the tenants owned by the sharder every
+// interval. The recommended dedicated columns are written to the backend and applied to the overrides if configured.
+func (rw *readerWriter) EnableDedicatedColumnsRecommendations(ctx context.Context, cfg *DedicatedColumnsConfig, sharder CompactorSharder, overrides DedicatedColumnsOverrides) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	if rw.cfg.BlocklistPoll == 0 {
+		level.Info(rw.logger).Log("msg", "polling cycle unset. dedicated columns recommendations disabled")
+		return nil
+	}
+
+	level.Info(rw.logger).Log("msg", "dedicated columns recommendations enabled.", "autoApply", cfg.AutoApply)
+	go rw.dedicatedColumnsLoop(ctx, cfg, sharder, overrides)
+
+	return nil
+}
+
+func (rw *readerWriter) dedicatedColumnsLoop(ctx context.Context, cfg *DedicatedColumnsConfig, sharder CompactorSharder, overrides DedicatedColumnsOverrides) {
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			rw.doDedicatedColumnsRecommendations(ctx, cfg, sharder, overrides)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// doDedicatedColumnsRecommendations updates the recommendations of all owned tenants
+func (rw *readerWriter) doDedicatedColumnsRecommendations(ctx context.Context, cfg *DedicatedColumnsConfig, sharder CompactorSharder, overrides DedicatedColumnsOverrides) {
+	tenants := rw.blocklist.Tenants()
+	sort.Strings(tenants)
+
+	for _, tenantID := range tenants {
+		if ctx.Err() != nil {
+			return
+		}
+		if !sharder.Owns(tenantID) {
+			continue
+		}
+
+		rec, err := rw.recommendDedicatedColumns(ctx, cfg, tenantID)
+		switch {
+		case err != nil:
+			metricDedicatedColumnsRecommendations.WithLabelValues(dedicatedColumnsResultError).Inc()
+			level.Error(rw.logger).Log("msg", "error recommending dedicated columns", "tenantID", tenantID, "err", err)
+			continue
+		case rec == nil:
+			metricDedicatedColumnsRecommendations.WithLabelValues(dedicatedColumnsResultSkipped).Inc()
+			continue
+		}
+		metricDedicatedColumnsRecommendations.WithLabelValues(dedicatedColumnsResultOK).Inc()
+
+		if !cfg.AutoApply {
+			continue
+		}
+
+		current := overrides.DedicatedColumns(tenantID)
+		next, changes := applyDedicatedColumnsChanges(current, rec.DedicatedColumns(), cfg.MaxChanges)
+		if changes == 0 {
+			continue
+		}
+
+		err = overrides.SetDedicatedColumns(ctx, tenantID, next)
+		if err != nil {
+			level.Error(rw.logger).Log("msg", "error applying dedicated columns", "tenantID", tenantID, "err", err)
+			continue
+		}
+		metricDedicatedColumnsApplied.WithLabelValues(tenantID).Add(float64(changes))
+		level.Info(rw.logger).Log("msg", "applied dedicated columns", "tenantID", tenantID, "changes", changes, "columns", len(next))
+	}
+}
+
+// recommendDedicatedColumns analyzes the most recent blocks of the tenant and writes the recommendation to the
+// backend. It returns nil if none of the blocks could be analyzed.
+func (rw *readerWriter) recommendDedicatedColumns(ctx context.Context, cfg *DedicatedColumnsConfig, tenantID string) (*backend.DedicatedColumnsRecommendation, error) {
+	metas := rw.blocklist.Metas(tenantID)
+	sort.Slice(metas, func(i, j int) bool {
+		return metas[i].EndTime.After(metas[j].EndTime)
+	})
+
+	sizes := map[traceql.AttributeScope]map[string]uint64{}
+	analyzed := 0
+	for _, meta := range metas {
+		if analyzed >= cfg.BlocksPerTenant {
+			break
+		}
+
+		err := rw.attributeSizes(ctx, meta, func(scope traceql.AttributeScope, name string, bytes uint64) {
+			if sizes[scope] == nil {
+				sizes[scope] = map[string]uint64{}
+			}
+			sizes[scope][name] += bytes
+		})
+		if errors.Is(err, common.ErrUnsupported) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error analyzing block %s: %w", meta.BlockID, err)
+		}
+		analyzed++
+	}
+
+	if analyzed == 0 {
+		return nil, nil
+	}
+
+	rec := recommendDedicatedColumns(sizes, cfg.MinShare)
+	rec.GeneratedAt = time.Now()
+	rec.BlocksAnalyzed = analyzed
+
+	err := rw.w.WriteDedicatedColumnsRecommendation(ctx, tenantID, rec)
+	if err != nil {
+		return nil, fmt.Errorf("error writing recommendation: %w", err)
+	}
+
+	return rec, nil
+}
+
+func (rw *readerWriter) attributeSizes(ctx context.Context, meta *backend.BlockMeta, cb common.AttributeSizeCallback) error {
+	block, err := encoding.OpenBlock(meta, rw.r)
+	if err != nil {
+		return err
+	}
+
+	sizer, ok := block.(common.AttributeSizer)
+	if !ok {
+		return fmt.Errorf("attribute sizes of %s blocks: %w", meta.Version, common.ErrUnsupported)
+	}
+
+	opts := common.DefaultSearchOptions()
+	if rw.cfg.Search != nil {
+		rw.cfg.Search.ApplyToOptions(&opts)
+	}
+	return sizer.AttributeSizes(ctx, cb, opts)
+}
+
+// recommendDedicatedColumns recommends the largest attributes of each scope up to the number of supported
+// dedicated columns. Attributes below minShare of the bytes of their scope are not recommended.
+func recommendDedicatedColumns(sizes map[traceql.AttributeScope]map[string]uint64, minShare float64) *backend.DedicatedColumnsRecommendation {
+	rec := &backend.DedicatedColumnsRecommendation{
+		Columns: []backend.RecommendedDedicatedColumn{},
+	}
+
+	for _, scope := range dedicatedColumnsScopes {
+		dcScope := backend.DedicatedColumnScope(scope.String())
+
+		var total uint64
+		attrs := make([]backend.RecommendedAttributeSize, 0, len(sizes[scope]))
+		for name, bytes := range sizes[scope] {
+			total += bytes
+			attrs = append(attrs, backend.RecommendedAttributeSize{Scope: dcScope, Name: name, Bytes: bytes})
+		}
+		rec.TotalBytes += total
+
+		sort.Slice(attrs, func(i, j int) bool {
+			if attrs[i].Bytes != attrs[j].Bytes {
+				return attrs[i].Bytes > attrs[j].Bytes
+			}
+			return attrs[i].Name < attrs[j].Name
+		})
+		if len(attrs) > maxRecommendedAttributes {
+			attrs = attrs[:maxRecommendedAttributes]
+		}
+
+		for i := range attrs {
+			if total > 0 {
+				attrs[i].Share = float64(attrs[i].Bytes) / float64(total)
+			}
+			if i < dcScope.MaxColumns() && attrs[i].Bytes > 0 && attrs[i].Share >= minShare {
+				rec.Columns = append(rec.Columns, backend.RecommendedDedicatedColumn{
+					Scope: dcScope,
+					Name:  attrs[i].Name,
+					Type:  backend.DedicatedColumnTypeString,
+				})
+			}
+		}
+		rec.Attributes = append(rec.Attributes, attrs...)
+	}
+
+	return rec
+}
+
+// applyDedicatedColumnsChanges adds the recommended columns that are missing from current, largest first. If a
+// scope has no free columns, the last column of the scope that is no longer recommended is replaced, which counts
+// as two changes. Columns that are no longer recommended are otherwise kept to avoid churn. At most maxChanges
+// columns are added or removed. It returns the new columns and the number of changes.
+func applyDedicatedColumnsChanges(current, recommended backend.DedicatedColumns, maxChanges int) (backend.DedicatedColumns, int) {
+	contains := func(dcs backend.DedicatedColumns, dc backend.DedicatedColumn) bool {
+		return slices.ContainsFunc(dcs, func(c backend.DedicatedColumn) bool {
+			return c.Scope == dc.Scope && c.Name == dc.Name
+		})
+	}
+	countScope := func(dcs backend.DedicatedColumns, scope backend.DedicatedColumnScope) int {
+		n := 0
+		for _, c := range dcs {
+			if c.Scope == scope {
+				n++
+			}
+		}
+		return n
+	}
+
+	next := slices.Clone(current)
+	changes := 0
+
+	for _, dc := range recommended {
+		if contains(next, dc) {
+			continue
+		}
+
+		if countScope(next, dc.Scope) >= dc.Scope.MaxColumns() {
+			if changes+2 > maxChanges {
+				continue
+			}
+
+			replace := -1
+			for i := len(next) - 1; i >= 0; i-- {
+				if next[i].Scope == dc.Scope && !contains(recommended, next[i]) {
+					replace = i
+					break
+				}
+			}
+			if replace < 0 {
+				continue
+			}
+
+			next = slices.Delete(next, replace, replace+1)
+			changes++
+		}
+
+		if changes+1 > maxChanges {
+			break
+		}
+		next = append(next, dc)
+		changes++
+	}
+
+	return next, changes
+}
@@ -0,0 +1,205 @@
+package tempodb
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/pool"
+	"github.com/grafana/tempo/tempodb/wal"
+)
+
+type mockDedicatedColumnsOverrides struct {
+	columns map[string]backend.DedicatedColumns
+}
+
+func (m *mockDedicatedColumnsOverrides) DedicatedColumns(tenantID string) backend.DedicatedColumns {
+	return m.columns[tenantID]
+}
+
+func (m *mockDedicatedColumnsOverrides) SetDedicatedColumns(_ context.Context, tenantID string, dcs backend.DedicatedColumns) error {
+	m.columns[tenantID] = dcs
+	return nil
+}
+
+func TestDedicatedColumnsRecommendations(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Pool: &pool.Config{
+			MaxWorkers: 10,
+			QueueDepth: 100,
+		},
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 11,
+			BloomFP:              .01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncLZ4_64k,
+			IndexPageSizeBytes:   1000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	overrides := &mockDedicatedColumnsOverrides{columns: map[string]backend.DedicatedColumns{}}
+	cfg := &DedicatedColumnsConfig{
+		Interval:        DefaultDedicatedColumnsInterval,
+		BlocksPerTenant: 2,
+		AutoApply:       true,
+		MaxChanges:      3,
+	}
+	require.Error(t, c.EnableDedicatedColumnsRecommendations(ctx, &DedicatedColumnsConfig{}, &mockSharder{}, overrides))
+	require.NoError(t, c.EnableDedicatedColumnsRecommendations(ctx, cfg, &mockSharder{}, overrides))
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+
+	cutTestBlocks(t, w, testTenantID, 3, 5)
+
+	rw := r.(*readerWriter)
+	rw.pollBlocklist()
+
+	rw.doDedicatedColumnsRecommendations(ctx, cfg, &mockSharder{}, overrides)
+
+	rec, err := rw.r.DedicatedColumnsRecommendation(ctx, testTenantID)
+	require.NoError(t, err)
+	require.Equal(t, 2, rec.BlocksAnalyzed)
+	require.NotZero(t, rec.TotalBytes)
+	require.NotEmpty(t, rec.Columns)
+	require.NotEmpty(t, rec.Attributes)
+
+	_, err = rw.r.DedicatedColumnsRecommendation(ctx, testTenantID2)
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+
+	// the columns are applied a few at a time
+	require.Equal(t, rec.DedicatedColumns()[:3], overrides.columns[testTenantID])
+
+	rw.doDedicatedColumnsRecommendations(ctx, cfg, &mockSharder{}, overrides)
+	require.Len(t, overrides.columns[testTenantID], min(6, len(rec.Columns)))
+}
+
+func TestRecommendDedicatedColumns(t *testing.T) {
+	sizes := map[traceql.AttributeScope]map[string]uint64{
+		traceql.AttributeScopeResource: {
+			"k8s.node.name": 500,
+			"host.name":     400,
+			"tiny":          1,
+		},
+		traceql.AttributeScopeSpan: {},
+	}
+	for i := 0; i < 12; i++ {
+		sizes[traceql.AttributeScopeSpan][string(rune('a'+i))] = uint64(100 + i)
+	}
+
+	rec := recommendDedicatedColumns(sizes, 0.01)
+
+	expected := []backend.RecommendedDedicatedColumn{
+		{Scope: "resource", Name: "k8s.node.name", Type: "string"},
+		{Scope: "resource", Name: "host.name", Type: "string"},
+	}
+	// the 10 largest span attributes
+	for i := 11; i >= 2; i-- {
+		expected = append(expected, backend.RecommendedDedicatedColumn{Scope: "span", Name: string(rune('a' + i)), Type: "string"})
+	}
+	require.Equal(t, expected, rec.Columns)
+
+	require.Len(t, rec.Attributes, 15)
+	require.Equal(t, backend.RecommendedAttributeSize{Scope: "resource", Name: "k8s.node.name", Bytes: 500, Share: 500.0 / 901}, rec.Attributes[0])
+	require.Equal(t, uint64(901+1266), rec.TotalBytes)
+}
+
+func TestApplyDedicatedColumnsChanges(t *testing.T) {
+	spanColumns := func(names ...string) backend.DedicatedColumns {
+		dcs := backend.DedicatedColumns{}
+		for _, n := range names {
+			dcs = append(dcs, backend.DedicatedColumn{Scope: "span", Name: n, Type: "string"})
+		}
+		return dcs
+	}
+
+	tcs := []struct {
+		name        string
+		current     backend.DedicatedColumns
+		recommended backend.DedicatedColumns
+		maxChanges  int
+		expected    backend.DedicatedColumns
+		expChanges  int
+	}{
+		{
+			name:        "nothing to change",
+			current:     spanColumns("a", "b"),
+			recommended: spanColumns("b", "a"),
+			maxChanges:  2,
+			expected:    spanColumns("a", "b"),
+		},
+		{
+			name:        "columns are added largest first",
+			current:     nil,
+			recommended: spanColumns("a", "b", "c"),
+			maxChanges:  2,
+			expected:    spanColumns("a", "b"),
+			expChanges:  2,
+		},
+		{
+			name:        "columns that are not recommended anymore are kept if there is space",
+			current:     spanColumns("x"),
+			recommended: spanColumns("a"),
+			maxChanges:  2,
+			expected:    spanColumns("x", "a"),
+			expChanges:  1,
+		},
+		{
+			name:        "columns that are not recommended anymore are replaced if the scope is full",
+			current:     spanColumns("a", "b", "c", "d", "e", "f", "g", "h", "x", "y"),
+			recommended: spanColumns("a", "b", "c", "d", "e", "f", "g", "h", "i", "j"),
+			maxChanges:  3,
+			expected:    spanColumns("a", "b", "c", "d", "e", "f", "g", "h", "x", "i"),
+			expChanges:  2,
+		},
+		{
+			name:        "a replacement needs two changes",
+			current:     spanColumns("a", "b", "c", "d", "e", "f", "g", "h", "i", "x"),
+			recommended: spanColumns("a", "b", "c", "d", "e", "f", "g", "h", "i", "j"),
+			maxChanges:  1,
+			expected:    spanColumns("a", "b", "c", "d", "e", "f", "g", "h", "i", "x"),
+		},
+		{
+			name:        "scopes are counted separately",
+			current:     spanColumns("a", "b", "c", "d", "e", "f", "g", "h", "i", "j"),
+			recommended: backend.DedicatedColumns{{Scope: "resource", Name: "a", Type: "string"}},
+			maxChanges:  1,
+			expected:    append(spanColumns("a", "b", "c", "d", "e", "f", "g", "h", "i", "j"), backend.DedicatedColumn{Scope: "resource", Name: "a", Type: "string"}),
+			expChanges:  1,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			current := append(backend.DedicatedColumns(nil), tc.current...)
+
+			next, changes := applyDedicatedColumnsChanges(tc.current, tc.recommended, tc.maxChanges)
+			require.Equal(t, tc.expected, next)
+			require.Equal(t, tc.expChanges, changes)
+			require.NoError(t, next.Validate())
+
+			// current is not modified
+			require.Equal(t, current, tc.current)
+		})
+	}
+}
@@ -7,11 +7,17 @@ import (
 
 var ErrUnsupported = errors.New("unsupported")
 
+// ErrCorrupted is wrapped by errors that are caused by the contents of a block and not by the backend
+// the block was read from.
+var ErrCorrupted = errors.New("corrupted block")
+
 const (
 	// NameObjects names the backend data object
 	NameObjects = "data"
 	// NameIndex names the backend index object
 	NameIndex = "index"
+	// NameAttributeIndex names the backend attribute index object
+	NameAttributeIndex = "attribute_index"
 	// nameBloomPrefix is the prefix used to build the bloom shards
 	nameBloomPrefix = "bloom-"
 )
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 
@@ -13,6 +14,8 @@ const (
 	DefaultBloomShardSizeBytes  = 100 * 1024
 	DefaultIndexDownSampleBytes = 1024 * 1024
 	DefaultIndexPageSizeBytes   = 250 * 1024
+
+	DefaultAttributeIndexMaxValues = 10_000
 )
 
 // BlockConfig holds configuration options for newly created blocks
@@ -33,6 +36,41 @@ type BlockConfig struct {
 
 	// vParquet3 fields
 	DedicatedColumns backend.DedicatedColumns `yaml:"parquet_dedicated_columns"`
+
+	// vParquet4 fields
+	AttributeIndex AttributeIndexConfig `yaml:"parquet_attribute_index"`
+	// AttributeScopesMaxNames is the number of attribute names per scope up to which the names observed in the
+	// resources and spans are recorded in the block meta. 0 disables recording.
+	AttributeScopesMaxNames int `yaml:"parquet_attribute_scopes_max_names"`
+}
+
+// AttributeIndexConfig selects the string attributes whose values are indexed per row group when creating blocks.
+type AttributeIndexConfig struct {
+	Attributes []AttributeIndexKey `yaml:"attributes"`
+	// MaxValues is the number of distinct values of an attribute in a block above which the attribute is left out
+	// of the index of the block.
+	MaxValues int `yaml:"max_values"`
+}
+
+type AttributeIndexKey struct {
+	// Scope is resource or span
+	Scope string `yaml:"scope"`
+	Name  string `yaml:"name"`
+}
+
+func (cfg *AttributeIndexConfig) Validate() error {
+	for _, k := range cfg.Attributes {
+		if k.Scope != "resource" && k.Scope != "span" {
+			return fmt.Errorf("parquet_attribute_index: unsupported scope %q of attribute %q", k.Scope, k.Name)
+		}
+		if k.Name == "" {
+			return errors.New("parquet_attribute_index: attribute name is required")
+		}
+	}
+	if len(cfg.Attributes) > 0 && cfg.MaxValues <= 0 {
+		return errors.New("parquet_attribute_index: positive max_values required")
+	}
+	return nil
 }
 
 func (cfg *BlockConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
@@ -45,6 +83,7 @@ func (cfg *BlockConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.Fla
 	cfg.SearchEncoding = backend.EncSnappy
 	cfg.SearchPageSizeBytes = 1024 * 1024 // 1 MB
 	cfg.RowGroupSizeBytes = 100_000_000   // 100 MB
+	cfg.AttributeIndex.MaxValues = DefaultAttributeIndexMaxValues
 }
 
 // ValidateConfig returns true if the config is valid
@@ -65,5 +104,13 @@ func ValidateConfig(b *BlockConfig) error {
 		return fmt.Errorf("positive value required for bloom-filter shard size")
 	}
 
+	if err := b.AttributeIndex.Validate(); err != nil {
+		return err
+	}
+
+	if b.AttributeScopesMaxNames < 0 {
+		return fmt.Errorf("parquet_attribute_scopes_max_names can't be negative")
+	}
+
 	return b.DedicatedColumns.Validate()
 }
@@ -16,9 +16,11 @@ type Finder interface {
 }
 
 type (
-	TagsCallback        func(t string, scope traceql.AttributeScope)
-	TagValuesCallback   func(t string) bool
-	TagValuesCallbackV2 func(traceql.Static) (stop bool)
+	TagsCallback          func(t string, scope traceql.AttributeScope)
+	TagValuesCallback     func(t string) bool
+	TagValuesCallbackV2   func(traceql.Static) (stop bool)
+	TagStatsCallback      func(scope traceql.AttributeScope, tag string, value traceql.Static)
+	AttributeSizeCallback func(scope traceql.AttributeScope, name string, bytes uint64)
 )
 
 type Searcher interface {
@@ -70,17 +72,22 @@ type CompactionOptions struct {
 	ChunkSizeBytes     uint32
 	FlushSizeBytes     uint32
 	IteratorBufferSize int // How many traces to prefetch async.
+	Concurrency        int // How many goroutines combine the traces of a job. Values below 2 combine in the calling goroutine.
 	MaxBytesPerTrace   int
+	MemoryBudgetBytes  int    // Estimated size of the copies of traces a job buffers before combining. Copies that don't fit are spilled to disk.
+	SpillPath          string // Directory of the spilled traces. Defaults to the directory for temporary files.
 	OutputBlocks       uint8
 	BlockConfig        BlockConfig
 	Combiner           model.ObjectCombiner
 
 	ObjectsCombined   func(compactionLevel, objects int)
+	ObjectsDeduped    func(compactionLevel, objects int) // Identical copies of a trace dropped without combining
 	ObjectsWritten    func(compactionLevel, objects int)
 	BytesWritten      func(compactionLevel, bytes int)
 	SpansDiscarded    func(traceID string, rootSpanName string, rootServiceName string, spans int)
 	DisconnectedTrace func()
 	RootlessTrace     func()
+	TraceSpilled      func(bytes int)
 }
 
 type Iterator interface {
@@ -95,6 +102,30 @@ type BackendBlock interface {
 	BlockMeta() *backend.BlockMeta
 }
 
+// Verifier is implemented by backend blocks that can be checked for corruption. Verify reads the whole
+// block. Problems found in the block wrap ErrCorrupted, other errors are returned as is.
+type Verifier interface {
+	Verify(ctx context.Context) error
+}
+
+// IterableBlock is implemented by backend blocks that can iterate all of their traces in trace id order,
+// i.e. to re-encode them in another version.
+type IterableBlock interface {
+	Iterator(ctx context.Context) (Iterator, error)
+}
+
+// TagStatser is implemented by backend blocks that can report the values of all of their tags, i.e. to estimate
+// the cardinality of the tags. Values are read from the dictionaries of the columns where possible.
+type TagStatser interface {
+	TagStats(ctx context.Context, scope traceql.AttributeScope, cb TagStatsCallback, opts SearchOptions) error
+}
+
+// AttributeSizer is implemented by backend blocks that can report the total size of the string values of each
+// resource and span attribute that can be stored in a dedicated column, i.e. to recommend dedicated columns.
+type AttributeSizer interface {
+	AttributeSizes(ctx context.Context, cb AttributeSizeCallback, opts SearchOptions) error
+}
+
 type WALBlock interface {
 	BackendBlock
 
@@ -0,0 +1,75 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WALReplayReport describes the parts of a WAL block that were skipped during replay because they are corrupted.
+// It is returned as warning by OpenWALBlock when the remainder of the block was salvaged.
+type WALReplayReport struct {
+	Skipped []WALSkippedRange
+}
+
+// WALSkippedRange is a byte range of a WAL file that could not be replayed. TracesAffected and SpansLost are -1
+// if they can't be determined from the corrupted data.
+type WALSkippedRange struct {
+	File           string
+	Start          int64
+	End            int64
+	TracesAffected int
+	SpansLost      int
+	Err            error
+}
+
+// Add records a skipped range.
+func (r *WALReplayReport) Add(skipped WALSkippedRange) {
+	r.Skipped = append(r.Skipped, skipped)
+}
+
+// Empty returns true if nothing was skipped.
+func (r *WALReplayReport) Empty() bool {
+	return r == nil || len(r.Skipped) == 0
+}
+
+// BytesLost returns the total size of the skipped ranges.
+func (r *WALReplayReport) BytesLost() int64 {
+	var n int64
+	for _, s := range r.Skipped {
+		n += s.End - s.Start
+	}
+	return n
+}
+
+// TracesAffected returns the number of traces with data in the skipped ranges. Ranges for which the number is
+// unknown are not included.
+func (r *WALReplayReport) TracesAffected() int {
+	n := 0
+	for _, s := range r.Skipped {
+		if s.TracesAffected > 0 {
+			n += s.TracesAffected
+		}
+	}
+	return n
+}
+
+// SpansLost returns the number of spans in the skipped ranges. Ranges for which the number is unknown are not
+// included.
+func (r *WALReplayReport) SpansLost() int {
+	n := 0
+	for _, s := range r.Skipped {
+		if s.SpansLost > 0 {
+			n += s.SpansLost
+		}
+	}
+	return n
+}
+
+// Error implements error.
+func (r *WALReplayReport) Error() string {
+	ranges := make([]string, 0, len(r.Skipped))
+	for _, s := range r.Skipped {
+		ranges = append(ranges, fmt.Sprintf("%s[%d:%d]: %v", s.File, s.Start, s.End, s.Err))
+	}
+	return fmt.Sprintf("skipped %d corrupted ranges of wal block: %s", len(r.Skipped), strings.Join(ranges, ", "))
+}
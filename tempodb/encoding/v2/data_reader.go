@@ -144,7 +144,7 @@ func (r *dataReader) NextPage(buffer []byte) ([]byte, uint32, error) {
 
 	compressedReader, err := r.getCompressedReader(page.data)
 	if err != nil {
-		return nil, 0, err
+		return nil, page.totalLength, err
 	}
 
 	// TODO: leaky abstraction. can a real programmer fix this in the future?
@@ -156,8 +156,9 @@ func (r *dataReader) NextPage(buffer []byte) ([]byte, uint32, error) {
 		buffer, err = tempo_io.ReadAllWithBuffer(compressedReader, len(page.data), buffer)
 	}
 
+	// the page was read completely, return its length so callers can skip it
 	if err != nil {
-		return nil, 0, err
+		return nil, page.totalLength, err
 	}
 	return buffer, page.totalLength, nil
 }
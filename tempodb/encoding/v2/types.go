@@ -80,6 +80,8 @@ type DataReader interface {
 	//  NextPage takes a reusable buffer to read the page into and returns it in case it needs to resize
 	//  NextPage returns the uncompressed page buffer ready for object iteration and the length of the
 	//    original page from the page header. len(page) might not equal page len!
+	//  If the page was read but can't be decompressed the length is returned with the error, the next call
+	//    continues with the following page.
 	NextPage([]byte) ([]byte, uint32, error)
 }
 
@@ -8,9 +8,11 @@ import (
 	"os"
 
 	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
-// ReplayWALAndGetRecords replays a WAL file that could contain either traces or searchdata
+// ReplayWALAndGetRecords replays a WAL file that could contain either traces or searchdata. Corrupted pages are
+// skipped and the remaining pages are replayed. The skipped pages are returned as *common.WALReplayReport warning.
 func ReplayWALAndGetRecords(file *os.File, enc backend.Encoding, handleObj func([]byte) error) ([]Record, error, error) {
 	dataReader, err := NewDataReader(backend.NewContextReaderWithAllReader(file), enc)
 	if err != nil {
@@ -19,39 +21,70 @@ func ReplayWALAndGetRecords(file *os.File, enc backend.Encoding, handleObj func(
 
 	var buffer []byte
 	var records []Record
-	var warning error
 	var pageLen uint32
 	var id []byte
+	report := &common.WALReplayReport{}
 	objectReader := NewObjectReaderWriter()
 	currentOffset := uint64(0)
+
+	skipPage := func(err error) {
+		report.Add(common.WALSkippedRange{
+			File:           file.Name(),
+			Start:          int64(currentOffset),
+			End:            int64(currentOffset) + int64(pageLen),
+			TracesAffected: 1, // one object per page
+			SpansLost:      -1,
+			Err:            err,
+		})
+		currentOffset += uint64(pageLen)
+	}
+
 	for {
 		buffer, pageLen, err = dataReader.NextPage(buffer)
 		if errors.Is(err, io.EOF) {
 			break
 		}
 		if err != nil {
-			warning = fmt.Errorf("accessing NextPage while replaying wal: %w", err)
+			if pageLen > 0 {
+				skipPage(fmt.Errorf("decompressing page while replaying wal: %w", err))
+				continue
+			}
+
+			// the page header can't be read, the remainder of the file is lost
+			end := int64(currentOffset)
+			if info, statErr := file.Stat(); statErr == nil {
+				end = info.Size()
+			}
+			report.Add(common.WALSkippedRange{
+				File:           file.Name(),
+				Start:          int64(currentOffset),
+				End:            end,
+				TracesAffected: -1,
+				SpansLost:      -1,
+				Err:            fmt.Errorf("accessing NextPage while replaying wal: %w", err),
+			})
 			break
 		}
 
 		reader := bytes.NewReader(buffer)
-		id, buffer, err = objectReader.UnmarshalObjectFromReader(reader)
+		var obj []byte
+		id, obj, err = objectReader.UnmarshalObjectFromReader(reader)
 		if err != nil {
-			warning = fmt.Errorf("unmarshalling object while replaying wal: %w", err)
-			break
+			skipPage(fmt.Errorf("unmarshalling object while replaying wal: %w", err))
+			continue
 		}
 		// wal should only ever have one object per page, test that here
 		_, _, err = objectReader.UnmarshalObjectFromReader(reader)
 		if !errors.Is(err, io.EOF) {
-			warning = fmt.Errorf("expected EOF while replaying wal: %w", err)
-			break
+			skipPage(fmt.Errorf("expected EOF while replaying wal: %w", err))
+			continue
 		}
 
 		// handleObj is primarily used by search replay to record search data in block header
-		err = handleObj(buffer)
+		err = handleObj(obj)
 		if err != nil {
-			warning = fmt.Errorf("custom obj handler while replaying wal: %w", err)
-			break
+			skipPage(fmt.Errorf("custom obj handler while replaying wal: %w", err))
+			continue
 		}
 
 		// make a copy so we don't hold onto the iterator buffer
@@ -66,5 +99,8 @@ func ReplayWALAndGetRecords(file *os.File, enc backend.Encoding, handleObj func(
 
 	SortRecords(records)
 
-	return records, warning, nil
+	if !report.Empty() {
+		return records, report, nil
+	}
+	return records, nil, nil
 }
@@ -8,6 +8,7 @@ import (
 	"io"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
 // Note: Standard wal block functionality (appending, searching, finding, etc.) is tested with all other wal blocks
@@ -231,6 +233,66 @@ func TestPartialBlock(t *testing.T) {
 	require.Equal(t, numMsgs, i)
 }
 
+func TestReplayCorruptedPage(t *testing.T) {
+	blockID := uuid.New()
+	meta := backend.NewBlockMeta(testTenantID, blockID, "v2", backend.EncSnappy, model.CurrentEncoding)
+	block, err := createWALBlock(meta, t.TempDir(), model.CurrentEncoding, 0)
+	require.NoError(t, err, "unexpected error creating block")
+
+	enc := model.MustNewSegmentDecoder(model.CurrentEncoding)
+
+	numMsgs := 10
+	for i := 0; i < numMsgs; i++ {
+		id := test.ValidTraceID(nil)
+		b1, err := enc.PrepareForWrite(test.MakeTrace(5, id), 0, 0)
+		require.NoError(t, err)
+
+		b2, err := enc.ToObject([][]byte{b1})
+		require.NoError(t, err)
+
+		require.NoError(t, block.Append(id, b2, 0, 0))
+	}
+
+	v2Block := block.(*walBlock)
+	records := v2Block.appender.Records()
+	dataLength := int64(v2Block.DataLength())
+
+	// corrupt the data of one page, its header stays intact so the following pages can be replayed
+	corrupted := records[len(records)/2]
+	f, err := os.OpenFile(v2Block.fullFilename(), os.O_RDWR, 0o600)
+	require.NoError(t, err)
+	garbage := make([]byte, corrupted.Length/2)
+	for i := range garbage {
+		garbage[i] = 0xff
+	}
+	_, err = f.WriteAt(garbage, int64(corrupted.Start)+int64(corrupted.Length/4))
+	require.NoError(t, err)
+
+	// append a partial page header, the remainder of the file is lost
+	_, err = f.WriteAt([]byte{1, 2, 3}, dataLength)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	replayed, warning, err := openWALBlock(filepath.Base(v2Block.fullFilename()), filepath.Dir(v2Block.fullFilename()), 0, 0)
+	require.NoError(t, err)
+
+	var report *common.WALReplayReport
+	require.ErrorAs(t, warning, &report)
+	require.Len(t, report.Skipped, 2)
+
+	assert.Equal(t, int64(corrupted.Start), report.Skipped[0].Start)
+	assert.Equal(t, int64(corrupted.Start)+int64(corrupted.Length), report.Skipped[0].End)
+	assert.Equal(t, 1, report.Skipped[0].TracesAffected)
+
+	assert.Equal(t, dataLength, report.Skipped[1].Start)
+	assert.Equal(t, dataLength+3, report.Skipped[1].End)
+	assert.Equal(t, -1, report.Skipped[1].TracesAffected)
+
+	assert.Equal(t, int64(corrupted.Length)+3, report.BytesLost())
+	assert.Equal(t, 1, report.TracesAffected())
+	assert.Equal(t, numMsgs-1, replayed.BlockMeta().TotalObjects)
+}
+
 func TestParseFilename(t *testing.T) {
 	tests := []struct {
 		name                 string
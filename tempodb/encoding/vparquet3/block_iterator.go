@@ -10,6 +10,8 @@ import (
 
 	tempo_io "github.com/grafana/tempo/pkg/io"
 	"github.com/grafana/tempo/pkg/parquetquery"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
@@ -93,3 +95,39 @@ func (i *rawIterator) peekNextID(context.Context) (common.ID, error) { // nolint
 func (i *rawIterator) Close() {
 	i.r.Close()
 }
+
+var _ common.IterableBlock = (*backendBlock)(nil)
+
+// Iterator returns the traces of the block in trace id order.
+func (b *backendBlock) Iterator(ctx context.Context) (common.Iterator, error) {
+	_, r, err := b.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &traceIterator{meta: b.meta, r: r}, nil
+}
+
+type traceIterator struct {
+	meta *backend.BlockMeta
+	r    *parquet.Reader //nolint:all //deprecated
+}
+
+var _ common.Iterator = (*traceIterator)(nil)
+
+func (i *traceIterator) Next(context.Context) (common.ID, *tempopb.Trace, error) {
+	tr := &Trace{}
+	err := i.r.Read(tr)
+	if errors.Is(err, io.EOF) {
+		return nil, nil, io.EOF
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("error iterating through block %s: %w", i.meta.BlockID.String(), err)
+	}
+
+	return tr.TraceID, ParquetTraceToTempopbTrace(i.meta, tr), nil
+}
+
+func (i *traceIterator) Close() {
+	i.r.Close()
+}
@@ -0,0 +1,129 @@
+package vparquet3
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/require"
+
+	tempo_io "github.com/grafana/tempo/pkg/io"
+	pq "github.com/grafana/tempo/pkg/parquetquery"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestBackendBlockVerify(t *testing.T) {
+	tcs := []struct {
+		name    string
+		corrupt func(t *testing.T, dir string, meta *backend.BlockMeta)
+	}{
+		{
+			name: "flipped byte in page",
+			corrupt: func(t *testing.T, dir string, _ *backend.BlockMeta) {
+				name := filepath.Join(dir, DataFileName)
+				data, err := os.ReadFile(name)
+				require.NoError(t, err)
+
+				pf, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+				require.NoError(t, err)
+				colIndex, _ := pq.GetColumnIndexByPath(pf, TraceIDColumnName)
+				require.GreaterOrEqual(t, colIndex, 0)
+
+				// flip the last byte of the trace id column chunk in the first row group
+				chunk := pf.Metadata().RowGroups[0].Columns[colIndex].MetaData
+				offset := chunk.DataPageOffset
+				if chunk.DictionaryPageOffset != 0 {
+					offset = chunk.DictionaryPageOffset
+				}
+				data[offset+chunk.TotalCompressedSize-1] ^= 0xff
+				require.NoError(t, os.WriteFile(name, data, 0o644))
+			},
+		},
+		{
+			name: "truncated data",
+			corrupt: func(_ *testing.T, _ string, meta *backend.BlockMeta) {
+				meta.Size += 100
+			},
+		},
+		{
+			name: "missing bloom",
+			corrupt: func(t *testing.T, dir string, _ *backend.BlockMeta) {
+				require.NoError(t, os.Remove(filepath.Join(dir, common.BloomName(0))))
+			},
+		},
+		{
+			name: "total objects disagree",
+			corrupt: func(_ *testing.T, _ string, meta *backend.BlockMeta) {
+				meta.TotalObjects++
+			},
+		},
+		{
+			name: "index disagrees",
+			corrupt: func(t *testing.T, dir string, _ *backend.BlockMeta) {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, common.NameIndex), []byte(`{"rowGroups":[]}`), 0o644))
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			b, dir := makeVerifyTestBlock(t)
+			ctx := context.Background()
+
+			require.NoError(t, b.Verify(ctx))
+
+			tc.corrupt(t, dir, b.meta)
+			require.ErrorIs(t, b.Verify(ctx), common.ErrCorrupted)
+		})
+	}
+}
+
+func makeVerifyTestBlock(t *testing.T) (*backendBlock, string) {
+	path := t.TempDir()
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: path,
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+	ctx := context.Background()
+
+	cfg := &common.BlockConfig{
+		BloomFP:             0.01,
+		BloomShardSizeBytes: 100 * 1024,
+	}
+
+	var traces []*Trace
+	for i := 0; i < 20; i++ {
+		traces = append(traces, fullyPopulatedTestTrace(test.ValidTraceID(nil)))
+	}
+	sort.Slice(traces, func(i, j int) bool {
+		return bytes.Compare(traces[i].TraceID, traces[j].TraceID) == -1
+	})
+
+	meta := backend.NewBlockMeta("fake", uuid.New(), VersionString, backend.EncNone, "")
+	meta.TotalObjects = len(traces)
+	s := newStreamingBlock(ctx, cfg, meta, r, w, tempo_io.NewBufferedWriter)
+
+	// cut a few row groups
+	for i, tr := range traces {
+		require.NoError(t, s.Add(tr, 0, 0))
+		if i%5 == 4 {
+			_, err = s.Flush()
+			require.NoError(t, err)
+		}
+	}
+	_, err = s.Complete()
+	require.NoError(t, err)
+
+	return newBackendBlock(s.meta, r), filepath.Join(path, meta.TenantID, meta.BlockID.String())
+}
@@ -90,7 +90,8 @@ func openWALBlock(filename, path string, ingestionSlack, _ time.Duration) (commo
 		return nil, nil, fmt.Errorf("error reading dir: %w", err)
 	}
 
-	var warning error
+	// corrupted pages are skipped and recorded in the report, the remainder of the block is replayed
+	report := &common.WALReplayReport{}
 	for _, f := range files {
 		if f.Name() == backend.MetaName {
 			continue
@@ -111,41 +112,93 @@ func openWALBlock(filename, path string, ingestionSlack, _ time.Duration) (commo
 
 		file, err := page.file(context.Background())
 		if err != nil {
-			warning = fmt.Errorf("error opening file info: %s: %w", page.path, err)
+			report.Add(common.WALSkippedRange{
+				File:           path,
+				End:            i.Size(),
+				TracesAffected: -1,
+				SpansLost:      -1,
+				Err:            fmt.Errorf("error opening file info: %s: %w", page.path, err),
+			})
 			continue
 		}
 
 		defer file.Close()
 		pf := file.parquetFile
 
-		// iterate the parquet file and build the meta
-		iter := makeIterFunc(context.Background(), pf.RowGroups(), pf)(columnPathTraceID, nil, columnPathTraceID)
-		defer iter.Close()
+		// iterate the parquet file and build the meta. the page is only added if all trace ids can be read
+		ids, err := walPageTraceIDs(pf)
+		if err != nil {
+			report.Add(common.WALSkippedRange{
+				File:           path,
+				End:            i.Size(),
+				TracesAffected: int(pf.NumRows()),
+				SpansLost:      walPageSpans(pf),
+				Err:            fmt.Errorf("error iterating wal page [%s %s]: %w", b.meta.BlockID.String(), f.Name(), err),
+			})
+			continue
+		}
 
-		for {
-			match, err := iter.Next()
-			if err != nil {
-				return nil, nil, fmt.Errorf("error iterating wal page [%s %d]: %w", b.meta.BlockID.String(), i, err)
-			}
-			if match == nil {
-				break
+		for _, e := range ids {
+			b.meta.ObjectAdded(e.ID, 0, 0)
+			page.ids.Set(e.ID, e.Entry) // Save rownumber for the trace ID
+		}
+
+		b.flushed = append(b.flushed, page)
+		b.flushedSize += i.Size()
+	}
+
+	if !report.Empty() {
+		return b, report, nil
+	}
+	return b, nil, nil
+}
+
+// walPageTraceIDs returns the trace ids of a wal page together with their row numbers. It fails if there isn't a
+// trace id for every row of the page.
+func walPageTraceIDs(pf *parquet.File) ([]common.IDMapEntry[int64], error) {
+	iter := makeIterFunc(context.Background(), pf.RowGroups(), pf)(columnPathTraceID, nil, columnPathTraceID)
+	defer iter.Close()
+
+	var ids []common.IDMapEntry[int64]
+	for {
+		match, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		if match == nil {
+			// corrupted pages can decode to fewer values than the file has rows
+			if int64(len(ids)) != pf.NumRows() {
+				return nil, fmt.Errorf("read %d trace ids from %d rows", len(ids), pf.NumRows())
 			}
+			return ids, nil
+		}
 
-			for _, e := range match.Entries {
-				switch e.Key {
-				case columnPathTraceID:
-					traceID := e.Value.ByteArray()
-					b.meta.ObjectAdded(traceID, 0, 0)
-					page.ids.Set(traceID, int64(match.RowNumber[0])) // Save rownumber for the trace ID
-				}
+		for _, e := range match.Entries {
+			switch e.Key {
+			case columnPathTraceID:
+				traceID := append([]byte(nil), e.Value.ByteArray()...)
+				ids = append(ids, common.IDMapEntry[int64]{ID: traceID, Entry: int64(match.RowNumber[0])})
 			}
 		}
+	}
+}
 
-		b.flushed = append(b.flushed, page)
-		b.flushedSize += i.Size()
+// walPageSpans returns the number of spans of a wal page according to the metadata of the span id column, or -1 if
+// the column can't be found. the metadata is readable even if the pages of the column are corrupted.
+func walPageSpans(pf *parquet.File) int {
+	idx, _ := parquetquery.GetColumnIndexByPath(pf, columnPathSpanID)
+	if idx < 0 {
+		return -1
 	}
 
-	return b, warning, nil
+	spans := int64(0)
+	for _, rg := range pf.Metadata().RowGroups {
+		if idx >= len(rg.Columns) {
+			return -1
+		}
+		spans += rg.Columns[idx].MetaData.NumValues
+	}
+	return int(spans)
 }
 
 // createWALBlock creates a new appendable block
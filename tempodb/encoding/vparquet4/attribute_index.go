@@ -0,0 +1,453 @@
+package vparquet4
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// attributeIndex maps the string values of selected attributes to the row groups of the traces that have them.
+type attributeIndex struct {
+	Attributes []indexedAttribute `json:"attributes"`
+}
+
+type indexedAttribute struct {
+	Scope string `json:"scope"`
+	Name  string `json:"name"`
+	// Values maps each value to the ascending row groups with traces that have it
+	Values map[string][]int `json:"values"`
+}
+
+// RowGroups returns the row groups with traces that have the attribute value. It returns false if the attribute
+// is not indexed.
+func (i *attributeIndex) RowGroups(scope traceql.AttributeScope, name, value string) ([]int, bool) {
+	for _, a := range i.Attributes {
+		if a.Name == name && traceql.AttributeScopeFromString(a.Scope) == scope {
+			return a.Values[value], true
+		}
+	}
+	return nil, false
+}
+
+func (i *attributeIndex) Marshal() ([]byte, error) {
+	return json.Marshal(i)
+}
+
+func unmarshalAttributeIndex(b []byte) (*attributeIndex, error) {
+	i := &attributeIndex{}
+	return i, json.Unmarshal(b, i)
+}
+
+// attributeIndexBuilder collects the values of the configured attributes of the traces written to a block.
+type attributeIndexBuilder struct {
+	keys      []common.AttributeIndexKey
+	maxValues int
+	dedicated [2]dedicatedColumnMapping // resource, span
+
+	// columns holds the columns of each attribute, rowColumns the values of each column of the current row
+	columns    []attributeIndexColumns
+	rowColumns [][]parquet.Value
+
+	values   []map[string][]int
+	dropped  []bool
+	rowGroup int
+	dirty    bool
+}
+
+func newAttributeIndexBuilder(cfg common.AttributeIndexConfig, dc backend.DedicatedColumns) *attributeIndexBuilder {
+	if len(cfg.Attributes) == 0 {
+		return nil
+	}
+
+	b := &attributeIndexBuilder{
+		keys:      cfg.Attributes,
+		maxValues: cfg.MaxValues,
+		dedicated: [2]dedicatedColumnMapping{
+			dedicatedColumnsToColumnMapping(dc, backend.DedicatedColumnScopeResource),
+			dedicatedColumnsToColumnMapping(dc, backend.DedicatedColumnScopeSpan),
+		},
+		rowColumns: make([][]parquet.Value, len(parquetSchema.Columns())),
+		values:     make([]map[string][]int, len(cfg.Attributes)),
+		dropped:    make([]bool, len(cfg.Attributes)),
+	}
+	for i, k := range cfg.Attributes {
+		b.values[i] = map[string][]int{}
+		b.columns = append(b.columns, b.lookupColumns(k))
+	}
+	return b
+}
+
+// Add records the attribute values of the trace in the current row group.
+func (b *attributeIndexBuilder) Add(tr *Trace) {
+	b.add(func(i int, fn func(string)) {
+		b.forEachValue(tr, b.keys[i], fn)
+	})
+}
+
+// AddRow records the attribute values of the trace in the row in the current row group. The values are read
+// from the columns of the attributes so compaction doesn't need to reconstruct the trace.
+func (b *attributeIndexBuilder) AddRow(row parquet.Row) {
+	row.Range(func(columnIndex int, columnValues []parquet.Value) bool {
+		b.rowColumns[columnIndex] = columnValues
+		return true
+	})
+	b.add(func(i int, fn func(string)) {
+		b.columns[i].forEachValue(b.rowColumns, b.keys[i].Name, fn)
+	})
+}
+
+func (b *attributeIndexBuilder) add(forEachValue func(i int, fn func(string))) {
+	b.dirty = true
+
+	for i := range b.keys {
+		if b.dropped[i] {
+			continue
+		}
+
+		values := b.values[i]
+		forEachValue(i, func(v string) {
+			rgs := values[v]
+			if len(rgs) > 0 && rgs[len(rgs)-1] == b.rowGroup {
+				return
+			}
+			values[v] = append(rgs, b.rowGroup)
+		})
+
+		// attributes with too many values would make the index too large to be worth reading
+		if len(values) > b.maxValues {
+			b.dropped[i] = true
+			b.values[i] = nil
+		}
+	}
+}
+
+// Flush starts the next row group. It must be called whenever the block flushes a row group.
+func (b *attributeIndexBuilder) Flush() {
+	if b.dirty {
+		b.rowGroup++
+		b.dirty = false
+	}
+}
+
+func (b *attributeIndexBuilder) Index() *attributeIndex {
+	idx := &attributeIndex{}
+	for i, k := range b.keys {
+		if b.dropped[i] {
+			continue
+		}
+		idx.Attributes = append(idx.Attributes, indexedAttribute{Scope: k.Scope, Name: k.Name, Values: b.values[i]})
+	}
+	return idx
+}
+
+// forEachValue calls fn for the string values of the attribute of all resources or spans of the trace. Values
+// can be repeated.
+func (b *attributeIndexBuilder) forEachValue(tr *Trace, k common.AttributeIndexKey, fn func(string)) {
+	fromAttrs := func(attrs []Attribute) {
+		for _, a := range attrs {
+			if a.Key != k.Name {
+				continue
+			}
+			for _, v := range a.Value {
+				fn(v)
+			}
+		}
+	}
+	fromOptional := func(v *string) {
+		if v != nil {
+			fn(*v)
+		}
+	}
+	fromDedicated := func(mapping dedicatedColumnMapping, attrs *DedicatedAttributes) bool {
+		col, ok := mapping.get(k.Name)
+		if !ok {
+			return false
+		}
+		if v := col.readValue(attrs); v != nil {
+			fn(v.GetStringValue())
+		}
+		return true
+	}
+
+	for _, rs := range tr.ResourceSpans {
+		if k.Scope == "resource" {
+			res := &rs.Resource
+			switch k.Name {
+			case LabelServiceName:
+				fn(res.ServiceName)
+			case LabelCluster:
+				fromOptional(res.Cluster)
+			case LabelNamespace:
+				fromOptional(res.Namespace)
+			case LabelPod:
+				fromOptional(res.Pod)
+			case LabelContainer:
+				fromOptional(res.Container)
+			case LabelK8sClusterName:
+				fromOptional(res.K8sClusterName)
+			case LabelK8sNamespaceName:
+				fromOptional(res.K8sNamespaceName)
+			case LabelK8sPodName:
+				fromOptional(res.K8sPodName)
+			case LabelK8sContainerName:
+				fromOptional(res.K8sContainerName)
+			default:
+				if !fromDedicated(b.dedicated[0], &res.DedicatedAttributes) {
+					fromAttrs(res.Attrs)
+				}
+			}
+			continue
+		}
+
+		for _, ss := range rs.ScopeSpans {
+			for i := range ss.Spans {
+				s := &ss.Spans[i]
+				switch k.Name {
+				case LabelHTTPMethod:
+					fromOptional(s.HttpMethod)
+				case LabelHTTPUrl:
+					fromOptional(s.HttpUrl)
+				default:
+					if !fromDedicated(b.dedicated[1], &s.DedicatedAttributes) {
+						fromAttrs(s.Attrs)
+					}
+				}
+			}
+		}
+	}
+}
+
+// attributeIndexColumns are the columns with the values of an indexed attribute
+type attributeIndexColumns struct {
+	value parquet.LeafColumn
+	// key is only set for generic attributes, whose values belong to the attribute in the key column
+	key    parquet.LeafColumn
+	hasKey bool
+}
+
+// lookupColumns returns the columns of the attribute. It must match the fields read by forEachValue.
+func (b *attributeIndexBuilder) lookupColumns(k common.AttributeIndexKey) attributeIndexColumns {
+	lookup := func(path string) parquet.LeafColumn {
+		col, _ := parquetSchema.Lookup(strings.Split(path, ".")...)
+		return col
+	}
+	generic := func(keyPath, valuePath string) attributeIndexColumns {
+		return attributeIndexColumns{key: lookup(keyPath), value: lookup(valuePath), hasKey: true}
+	}
+
+	if k.Scope == "resource" {
+		if path, ok := traceqlResourceLabelMappings[k.Name]; ok {
+			return attributeIndexColumns{value: lookup(path)}
+		}
+		if col, ok := b.dedicated[0].get(k.Name); ok {
+			return attributeIndexColumns{value: lookup(col.ColumnPath)}
+		}
+		return generic(FieldResourceAttrKey, FieldResourceAttrVal)
+	}
+
+	switch k.Name {
+	case LabelHTTPMethod, LabelHTTPUrl:
+		return attributeIndexColumns{value: lookup(traceqlSpanLabelMappings[k.Name])}
+	}
+	if col, ok := b.dedicated[1].get(k.Name); ok {
+		return attributeIndexColumns{value: lookup(col.ColumnPath)}
+	}
+	return generic(FieldSpanAttrKey, FieldSpanAttrVal)
+}
+
+// forEachValue calls fn for the defined values of the columns of a row. Values can be repeated.
+func (c attributeIndexColumns) forEachValue(rowColumns [][]parquet.Value, name string, fn func(string)) {
+	values := rowColumns[c.value.ColumnIndex]
+	if !c.hasKey {
+		for _, v := range values {
+			if v.DefinitionLevel() == c.value.MaxDefinitionLevel {
+				fn(string(v.ByteArray()))
+			}
+		}
+		return
+	}
+
+	// every attribute has one value in the key column, and its list of values starts at a repetition level
+	// of the attribute list or above
+	keys := rowColumns[c.key.ColumnIndex]
+	attr := -1
+	for _, v := range values {
+		if v.RepetitionLevel() <= c.key.MaxRepetitionLevel {
+			attr++
+		}
+		if v.DefinitionLevel() != c.value.MaxDefinitionLevel || attr >= len(keys) {
+			continue
+		}
+		if k := keys[attr]; k.DefinitionLevel() == c.key.MaxDefinitionLevel && string(k.ByteArray()) == name {
+			fn(string(v.ByteArray()))
+		}
+	}
+}
+
+func writeAttributeIndex(ctx context.Context, w backend.Writer, meta *backend.BlockMeta, idx *attributeIndex) error {
+	b, err := idx.Marshal()
+	if err != nil {
+		return err
+	}
+	return w.Write(ctx, common.NameAttributeIndex, meta.BlockID, meta.TenantID, b, &backend.CacheInfo{
+		Meta: meta,
+		Role: cache.RoleAttributeIdx,
+	})
+}
+
+// rowGroupsWithAttributes uses the attribute index of the block to skip the given row groups that have no traces
+// with the values of the equality conditions on indexed attributes. This is only possible if all conditions must
+// match.
+func (b *backendBlock) rowGroupsWithAttributes(ctx context.Context, pf *parquet.File, rgs []parquet.RowGroup, req traceql.FetchSpansRequest) ([]parquet.RowGroup, error) {
+	if !b.meta.AttributeIndex || !req.AllConditions || !hasIndexableCondition(req.Conditions) {
+		return rgs, nil
+	}
+
+	span, _ := opentracing.StartSpanFromContext(ctx, "parquet.rowGroupsWithAttributes")
+	defer span.Finish()
+
+	idxBytes, err := b.r.Read(ctx, common.NameAttributeIndex, b.meta.BlockID, b.meta.TenantID, &backend.CacheInfo{
+		Meta: b.meta,
+		Role: cache.RoleAttributeIdx,
+	})
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		return rgs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := unmarshalAttributeIndex(idxBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing attribute index (%s, %s): %w", b.meta.TenantID, b.meta.BlockID, err)
+	}
+
+	// allowed is nil until a condition is looked up in the index
+	var allowed []int
+	lookedUp := false
+	for _, cond := range req.Conditions {
+		if !indexableCondition(cond) {
+			continue
+		}
+
+		matches, ok := lookupCondition(idx, cond)
+		if !ok {
+			continue
+		}
+		if !lookedUp {
+			allowed, lookedUp = matches, true
+			continue
+		}
+		allowed = intersectSorted(allowed, matches)
+	}
+	if !lookedUp {
+		return rgs, nil
+	}
+
+	// rgs are a subset of the row groups of the file in the same order
+	all := pf.RowGroups()
+	matches := make([]parquet.RowGroup, 0, len(rgs))
+	next := 0
+	for i := 0; i < len(all) && next < len(rgs); i++ {
+		if all[i] != rgs[next] {
+			continue
+		}
+		next++
+
+		if _, found := slices.BinarySearch(allowed, i); found {
+			matches = append(matches, all[i])
+		}
+	}
+
+	span.SetTag("totalRowGroups", len(rgs))
+	span.SetTag("matchedRowGroups", len(matches))
+
+	return matches, nil
+}
+
+func hasIndexableCondition(conds []traceql.Condition) bool {
+	return slices.ContainsFunc(conds, indexableCondition)
+}
+
+// indexableCondition returns true for equality conditions on a string attribute of resources or spans
+func indexableCondition(cond traceql.Condition) bool {
+	a := cond.Attribute
+	if a.Intrinsic != traceql.IntrinsicNone || a.Parent {
+		return false
+	}
+	switch a.Scope {
+	case traceql.AttributeScopeNone, traceql.AttributeScopeResource, traceql.AttributeScopeSpan:
+	default:
+		return false
+	}
+	return cond.Op == traceql.OpEqual && len(cond.Operands) == 1 && cond.Operands[0].Type == traceql.TypeString
+}
+
+// lookupCondition returns the row groups that can match the condition. Unscoped attributes match resources and
+// spans, so they are only looked up if both scopes are indexed.
+func lookupCondition(idx *attributeIndex, cond traceql.Condition) ([]int, bool) {
+	name, value := cond.Attribute.Name, cond.Operands[0].S
+
+	if cond.Attribute.Scope != traceql.AttributeScopeNone {
+		return idx.RowGroups(cond.Attribute.Scope, name, value)
+	}
+
+	resource, ok := idx.RowGroups(traceql.AttributeScopeResource, name, value)
+	if !ok {
+		return nil, false
+	}
+	span, ok := idx.RowGroups(traceql.AttributeScopeSpan, name, value)
+	if !ok {
+		return nil, false
+	}
+	return unionSorted(resource, span), true
+}
+
+func intersectSorted(a, b []int) []int {
+	var out []int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			out = append(out, a[i])
+			i++
+			j++
+		}
+	}
+	return out
+}
+
+func unionSorted(a, b []int) []int {
+	out := make([]int, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			out = append(out, a[i])
+			i++
+		case a[i] > b[j]:
+			out = append(out, b[j])
+			j++
+		default:
+			out = append(out, a[i])
+			i++
+			j++
+		}
+	}
+	out = append(out, a[i:]...)
+	return append(out, b[j:]...)
+}
@@ -0,0 +1,257 @@
+package vparquet4
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	tempo_io "github.com/grafana/tempo/pkg/io"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestAttributeIndexBuilder(t *testing.T) {
+	dc := backend.DedicatedColumns{{Scope: backend.DedicatedColumnScopeSpan, Name: "dedicated", Type: backend.DedicatedColumnTypeString}}
+	b := newAttributeIndexBuilder(common.AttributeIndexConfig{
+		Attributes: []common.AttributeIndexKey{
+			{Scope: "resource", Name: LabelK8sPodName},
+			{Scope: "span", Name: "customer.id"},
+			{Scope: "span", Name: "dedicated"},
+			{Scope: "span", Name: "request.id"},
+		},
+		MaxValues: 3,
+	}, dc)
+
+	makeTrace := func(pod, customer, dedicated, request string) *Trace {
+		span := Span{
+			Attrs: []Attribute{
+				{Key: "customer.id", Value: []string{customer}},
+				{Key: "request.id", Value: []string{request}},
+			},
+			// the only dedicated span column is assigned the first spare column
+			DedicatedAttributes: DedicatedAttributes{String01: &dedicated},
+		}
+		return &Trace{ResourceSpans: []ResourceSpans{{
+			Resource:   Resource{K8sPodName: &pod},
+			ScopeSpans: []ScopeSpans{{Spans: []Span{span}}},
+		}}}
+	}
+
+	b.Add(makeTrace("pod-a", "c1", "d1", "r1"))
+	b.Add(makeTrace("pod-a", "c2", "d1", "r2"))
+	b.Flush()
+	b.Flush() // empty row groups are not counted
+	b.Add(makeTrace("pod-b", "c1", "d2", "r3"))
+	b.Add(makeTrace("pod-b", "c1", "d2", "r4"))
+	b.Flush()
+
+	idx, err := unmarshalAttributeIndex(mustMarshal(t, b.Index()))
+	require.NoError(t, err)
+
+	rgs, ok := idx.RowGroups(traceql.AttributeScopeResource, LabelK8sPodName, "pod-b")
+	require.True(t, ok)
+	require.Equal(t, []int{1}, rgs)
+
+	rgs, ok = idx.RowGroups(traceql.AttributeScopeSpan, "customer.id", "c1")
+	require.True(t, ok)
+	require.Equal(t, []int{0, 1}, rgs)
+
+	rgs, ok = idx.RowGroups(traceql.AttributeScopeSpan, "customer.id", "c3")
+	require.True(t, ok)
+	require.Empty(t, rgs)
+
+	rgs, ok = idx.RowGroups(traceql.AttributeScopeSpan, "dedicated", "d2")
+	require.True(t, ok)
+	require.Equal(t, []int{1}, rgs)
+
+	// too many values
+	_, ok = idx.RowGroups(traceql.AttributeScopeSpan, "request.id", "r1")
+	require.False(t, ok)
+
+	// not configured
+	_, ok = idx.RowGroups(traceql.AttributeScopeResource, "customer.id", "c1")
+	require.False(t, ok)
+}
+
+func TestRowGroupsWithAttributes(t *testing.T) {
+	// row groups of the block are [0], [1, 100] and [101, 200], pods are pod-0, pod-1 and pod-2
+	trs := make([]*Trace, 0, 201)
+	for i := 0; i <= 200; i++ {
+		pod := fmt.Sprintf("pod-%d", (i+99)/100)
+		trs = append(trs, &Trace{
+			TraceID: test.ValidTraceID(nil),
+			ResourceSpans: []ResourceSpans{{
+				Resource: Resource{ServiceName: "svc", K8sPodName: &pod},
+				ScopeSpans: []ScopeSpans{{Spans: []Span{{
+					SpanID: []byte{1},
+					Name:   "span",
+					Attrs:  []Attribute{{Key: "customer.id", Value: []string{fmt.Sprintf("c%d", i)}}},
+				}}}},
+			}},
+		})
+	}
+
+	ctx := context.Background()
+	b := makeBackendBlockWithAttributeIndex(t, trs, common.AttributeIndexConfig{
+		Attributes: []common.AttributeIndexKey{
+			{Scope: "resource", Name: LabelK8sPodName},
+			{Scope: "resource", Name: "customer.id"},
+			{Scope: "span", Name: "customer.id"},
+		},
+		MaxValues: 1000,
+	})
+	require.True(t, b.meta.AttributeIndex)
+
+	pf, _, err := b.openForSearch(ctx, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Len(t, pf.RowGroups(), 3)
+
+	tcs := []struct {
+		query    string
+		expected []int
+	}{
+		{query: `{ span.customer.id = "c150" }`, expected: []int{2}},
+		{query: `{ span.customer.id = "c150" && resource.k8s.pod.name = "pod-2" }`, expected: []int{2}},
+		{query: `{ span.customer.id = "c150" && resource.k8s.pod.name = "pod-1" }`, expected: []int{}},
+		{query: `{ span.customer.id = "c1000" }`, expected: []int{}},
+		{query: `{ .customer.id = "c5" }`, expected: []int{1}},
+		{query: `{ resource.k8s.pod.name = "pod-1" && span.other = "x" }`, expected: []int{1}},
+		// not indexed
+		{query: `{ span.other = "x" }`, expected: []int{0, 1, 2}},
+		{query: `{ span.customer.id != "c150" }`, expected: []int{0, 1, 2}},
+		// not all conditions have to match
+		{query: `{ span.customer.id = "c150" || span.customer.id = "c5" }`, expected: []int{0, 1, 2}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			req := traceql.MustExtractFetchSpansRequestWithMetadata(tc.query)
+
+			rgs, err := b.rowGroupsWithAttributes(ctx, pf, pf.RowGroups(), req)
+			require.NoError(t, err)
+
+			require.Len(t, rgs, len(tc.expected))
+			for i, rg := range tc.expected {
+				require.Equal(t, pf.RowGroups()[rg], rgs[i])
+			}
+		})
+	}
+
+	// the skipped row groups don't change the results
+	req := traceql.MustExtractFetchSpansRequestWithMetadata(`{ span.customer.id = "c150" }`)
+	resp, err := b.Fetch(ctx, req, common.DefaultSearchOptions())
+	require.NoError(t, err)
+
+	ss, err := resp.Results.Next(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, ss)
+	require.Equal(t, []byte(trs[150].TraceID), ss.TraceID)
+
+	ss, err = resp.Results.Next(ctx)
+	require.NoError(t, err)
+	require.Nil(t, ss)
+}
+
+func makeBackendBlockWithAttributeIndex(t *testing.T, trs []*Trace, cfg common.AttributeIndexConfig) *backendBlock {
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+	ctx := context.Background()
+
+	blockCfg := &common.BlockConfig{
+		BloomFP:             0.01,
+		BloomShardSizeBytes: 100 * 1024,
+		AttributeIndex:      cfg,
+	}
+
+	meta := backend.NewBlockMeta("fake", uuid.New(), VersionString, backend.EncNone, "")
+	meta.TotalObjects = 1
+
+	s := newStreamingBlock(ctx, blockCfg, meta, r, w, tempo_io.NewBufferedWriter)
+
+	// rows are added like during compaction
+	for i, tr := range trs {
+		err = s.AddRaw(tr.TraceID, parquetSchema.Deconstruct(nil, tr), 0, 0)
+		require.NoError(t, err)
+		if i%100 == 0 {
+			_, err := s.Flush()
+			require.NoError(t, err)
+		}
+	}
+
+	_, err = s.Complete()
+	require.NoError(t, err)
+
+	return newBackendBlock(s.meta, r)
+}
+
+func TestAttributeIndexBuilderAddRow(t *testing.T) {
+	cfg := common.AttributeIndexConfig{
+		Attributes: []common.AttributeIndexKey{
+			{Scope: "resource", Name: LabelServiceName},
+			{Scope: "resource", Name: LabelK8sPodName},
+			{Scope: "resource", Name: "dedicated.resource.2"},
+			{Scope: "resource", Name: "foo"},
+			{Scope: "resource", Name: "str-array"},
+			{Scope: "span", Name: LabelHTTPMethod},
+			{Scope: "span", Name: LabelServiceName},
+			{Scope: "span", Name: "dedicated.span.3"},
+			{Scope: "span", Name: "foo"},
+			{Scope: "span", Name: "bar"},
+			{Scope: "span", Name: "string-array"},
+		},
+		MaxValues: 100,
+	}
+	dc := test.MakeDedicatedColumns()
+
+	traces := []*Trace{
+		fullyPopulatedTestTrace(test.ValidTraceID(nil)),
+		{},
+		{ResourceSpans: []ResourceSpans{{ScopeSpans: []ScopeSpans{{Spans: []Span{{}}}}}}},
+		{ResourceSpans: []ResourceSpans{{
+			Resource: Resource{Attrs: []Attribute{attr("bar", 1), attr("foo", "xyz")}},
+			ScopeSpans: []ScopeSpans{{Spans: []Span{
+				{Attrs: []Attribute{attr("foo", "a"), attr("string-array", []string{"b", "c"})}},
+				{Attrs: []Attribute{attr("string-array", []string{}), attr("foo", "d")}},
+			}}},
+		}}},
+	}
+
+	fromTraces := newAttributeIndexBuilder(cfg, dc)
+	fromRows := newAttributeIndexBuilder(cfg, dc)
+	for i, tr := range traces {
+		fromTraces.Add(tr)
+		fromRows.AddRow(parquetSchema.Deconstruct(nil, tr))
+		if i%2 == 1 {
+			fromTraces.Flush()
+			fromRows.Flush()
+		}
+	}
+
+	want := fromTraces.Index()
+	require.Len(t, want.Attributes, len(cfg.Attributes))
+	for _, a := range want.Attributes {
+		if a.Name == "bar" { // only has int values
+			require.Empty(t, a.Values)
+			continue
+		}
+		require.NotEmpty(t, a.Values, "%s.%s", a.Scope, a.Name)
+	}
+	require.Equal(t, want, fromRows.Index())
+}
+
+func mustMarshal(t *testing.T, idx *attributeIndex) []byte {
+	b, err := idx.Marshal()
+	require.NoError(t, err)
+	return b
+}
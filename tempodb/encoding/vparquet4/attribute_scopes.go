@@ -0,0 +1,110 @@
+package vparquet4
+
+import (
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// attributeScopesBuilder collects the names of the attributes of the resources and spans of the traces written
+// to a block.
+type attributeScopesBuilder struct {
+	maxNames  int
+	dedicated [2]dedicatedColumnMapping // resource, span
+
+	resource map[string]struct{}
+	span     map[string]struct{}
+	exceeded bool
+}
+
+func newAttributeScopesBuilder(maxNames int, dc backend.DedicatedColumns) *attributeScopesBuilder {
+	if maxNames <= 0 {
+		return nil
+	}
+
+	return &attributeScopesBuilder{
+		maxNames: maxNames,
+		dedicated: [2]dedicatedColumnMapping{
+			dedicatedColumnsToColumnMapping(dc, backend.DedicatedColumnScopeResource),
+			dedicatedColumnsToColumnMapping(dc, backend.DedicatedColumnScopeSpan),
+		},
+		resource: map[string]struct{}{},
+		span:     map[string]struct{}{},
+	}
+}
+
+// Add records the attribute names of the resources and spans of the trace.
+func (b *attributeScopesBuilder) Add(tr *Trace) {
+	if b.exceeded {
+		return
+	}
+
+	for i := range tr.ResourceSpans {
+		res := &tr.ResourceSpans[i].Resource
+		addResourceAttributeNames(b.resource, res, &b.dedicated[0])
+
+		for _, ss := range tr.ResourceSpans[i].ScopeSpans {
+			for j := range ss.Spans {
+				addSpanAttributeNames(b.span, &ss.Spans[j], &b.dedicated[1])
+			}
+		}
+	}
+
+	// stop collecting, the names won't be recorded anyway
+	if len(b.resource) > b.maxNames || len(b.span) > b.maxNames {
+		b.exceeded = true
+		b.resource, b.span = nil, nil
+	}
+}
+
+// Scopes returns the collected names or nil if there were too many.
+func (b *attributeScopesBuilder) Scopes() *backend.AttributeScopes {
+	if b.exceeded {
+		return nil
+	}
+	return backend.NewAttributeScopes(b.resource, b.span, b.maxNames)
+}
+
+func addResourceAttributeNames(names map[string]struct{}, res *Resource, dedicated *dedicatedColumnMapping) {
+	names[LabelServiceName] = struct{}{}
+
+	addOptional := func(label string, v *string) {
+		if v != nil {
+			names[label] = struct{}{}
+		}
+	}
+	addOptional(LabelCluster, res.Cluster)
+	addOptional(LabelNamespace, res.Namespace)
+	addOptional(LabelPod, res.Pod)
+	addOptional(LabelContainer, res.Container)
+	addOptional(LabelK8sClusterName, res.K8sClusterName)
+	addOptional(LabelK8sNamespaceName, res.K8sNamespaceName)
+	addOptional(LabelK8sPodName, res.K8sPodName)
+	addOptional(LabelK8sContainerName, res.K8sContainerName)
+
+	addAttributeNames(names, res.Attrs, &res.DedicatedAttributes, dedicated)
+}
+
+func addSpanAttributeNames(names map[string]struct{}, s *Span, dedicated *dedicatedColumnMapping) {
+	if s.HttpMethod != nil {
+		names[LabelHTTPMethod] = struct{}{}
+	}
+	if s.HttpUrl != nil {
+		names[LabelHTTPUrl] = struct{}{}
+	}
+	if s.HttpStatusCode != nil {
+		names[LabelHTTPStatusCode] = struct{}{}
+	}
+
+	addAttributeNames(names, s.Attrs, &s.DedicatedAttributes, dedicated)
+}
+
+func addAttributeNames(names map[string]struct{}, attrs []Attribute, dedicatedAttrs *DedicatedAttributes, dedicated *dedicatedColumnMapping) {
+	for _, a := range attrs {
+		names[a.Key] = struct{}{}
+	}
+
+	dedicated.forEach(func(attr string, col dedicatedColumn) {
+		if col.readValue(dedicatedAttrs) != nil {
+			names[attr] = struct{}{}
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package vparquet4
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	tempo_io "github.com/grafana/tempo/pkg/io"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestAttributeScopesBuilder(t *testing.T) {
+	dc := backend.DedicatedColumns{
+		{Scope: backend.DedicatedColumnScopeResource, Name: "dedicated.resource", Type: backend.DedicatedColumnTypeString},
+		{Scope: backend.DedicatedColumnScopeSpan, Name: "dedicated.span", Type: backend.DedicatedColumnTypeString},
+		{Scope: backend.DedicatedColumnScopeSpan, Name: "dedicated.unset", Type: backend.DedicatedColumnTypeString},
+	}
+	b := newAttributeScopesBuilder(10, dc)
+
+	pod, method, dedicated := "pod", "GET", "value"
+	b.Add(&Trace{ResourceSpans: []ResourceSpans{{
+		Resource: Resource{
+			ServiceName:         "svc",
+			K8sPodName:          &pod,
+			Attrs:               []Attribute{{Key: "host.name"}},
+			DedicatedAttributes: DedicatedAttributes{String01: &dedicated},
+		},
+		ScopeSpans: []ScopeSpans{{Spans: []Span{
+			{HttpMethod: &method, Attrs: []Attribute{{Key: "user.id"}}},
+			{Attrs: []Attribute{{Key: "host.name"}}, DedicatedAttributes: DedicatedAttributes{String01: &dedicated}},
+		}}},
+	}}})
+
+	require.Equal(t, &backend.AttributeScopes{
+		Resource: []string{"dedicated.resource", "host.name", LabelK8sPodName, LabelServiceName},
+		Span:     []string{"dedicated.span", "host.name", LabelHTTPMethod, "user.id"},
+	}, b.Scopes())
+
+	// too many names
+	b = newAttributeScopesBuilder(1, nil)
+	b.Add(&Trace{ResourceSpans: []ResourceSpans{{Resource: Resource{ServiceName: "svc", Attrs: []Attribute{{Key: "host.name"}}}}}})
+	require.Nil(t, b.Scopes())
+
+	// disabled
+	require.Nil(t, newAttributeScopesBuilder(0, nil))
+}
+
+func TestStreamingBlockRecordsAttributeScopes(t *testing.T) {
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+	ctx := context.Background()
+
+	cfg := &common.BlockConfig{
+		BloomFP:                 0.01,
+		BloomShardSizeBytes:     100 * 1024,
+		AttributeScopesMaxNames: 100,
+	}
+
+	meta := backend.NewBlockMeta("fake", uuid.New(), VersionString, backend.EncNone, "")
+	meta.TotalObjects = 1
+
+	s := newStreamingBlock(ctx, cfg, meta, r, w, tempo_io.NewBufferedWriter)
+	tr := &Trace{
+		TraceID: test.ValidTraceID(nil),
+		ResourceSpans: []ResourceSpans{{
+			Resource: Resource{ServiceName: "svc"},
+			ScopeSpans: []ScopeSpans{{Spans: []Span{{
+				SpanID: []byte{1},
+				Attrs:  []Attribute{{Key: "customer.id", Value: []string{"c1"}}},
+			}}}},
+		}},
+	}
+	require.NoError(t, s.AddRaw(tr.TraceID, parquetSchema.Deconstruct(nil, tr), 0, 0))
+	_, err = s.Complete()
+	require.NoError(t, err)
+
+	stored, err := r.BlockMeta(ctx, meta.BlockID, meta.TenantID)
+	require.NoError(t, err)
+	require.Equal(t, &backend.AttributeScopes{Resource: []string{LabelServiceName}, Span: []string{"customer.id"}}, stored.AttributeScopes)
+
+	// the names of a compacted block are merged from its inputs and not collected again
+	meta = backend.NewBlockMeta("fake", uuid.New(), VersionString, backend.EncNone, "")
+	meta.AttributeScopes = &backend.AttributeScopes{Span: []string{"merged"}}
+	s = newStreamingBlock(ctx, cfg, meta, r, w, tempo_io.NewBufferedWriter)
+	require.Nil(t, s.attributeScopes)
+	require.Equal(t, meta.AttributeScopes, s.meta.AttributeScopes)
+}
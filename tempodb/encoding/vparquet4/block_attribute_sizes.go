@@ -0,0 +1,136 @@
+package vparquet4
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/parquet-go/parquet-go"
+
+	pq "github.com/grafana/tempo/pkg/parquetquery"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// AttributeSizes reports the total size of the string values of each resource and span attribute of the block.
+// Attributes that are already stored in dedicated columns are included, well-known attributes are not because
+// they always have their own columns.
+func (b *backendBlock) AttributeSizes(ctx context.Context, cb common.AttributeSizeCallback, opts common.SearchOptions) error {
+	span, derivedCtx := opentracing.StartSpanFromContext(ctx, "parquet.backendBlock.AttributeSizes",
+		opentracing.Tags{
+			"blockID":   b.meta.BlockID,
+			"tenantID":  b.meta.TenantID,
+			"blockSize": b.meta.Size,
+		})
+	defer span.Finish()
+
+	pf, rr, err := b.openForSearch(derivedCtx, opts)
+	if err != nil {
+		return fmt.Errorf("unexpected error opening parquet file: %w", err)
+	}
+	defer func() { span.SetTag("inspectedBytes", rr.BytesRead()) }()
+
+	return attributeSizes(derivedCtx, cb, pf, b.meta.DedicatedColumns)
+}
+
+func attributeSizes(ctx context.Context, cb common.AttributeSizeCallback, pf *parquet.File, dc backend.DedicatedColumns) error {
+	scan := func(scope traceql.AttributeScope, dedicatedScope backend.DedicatedColumnScope, definitionLevel int, keyPath, stringPath string) error {
+		sizes := map[string]uint64{}
+
+		// dedicated attributes
+		var err error
+		columnMapping := dedicatedColumnsToColumnMapping(dc, dedicatedScope)
+		columnMapping.forEach(func(lbl string, c dedicatedColumn) {
+			if err != nil || c.Type != backend.DedicatedColumnTypeString {
+				return
+			}
+			var size uint64
+			size, err = columnSize(ctx, pf, c.ColumnPath)
+			sizes[lbl] += size
+		})
+		if err != nil {
+			return fmt.Errorf("unexpected error sizing dedicated columns: %w", err)
+		}
+
+		// standard attributes
+		err = keyValueSizes(ctx, pf, sizes, definitionLevel, keyPath, stringPath)
+		if err != nil {
+			return fmt.Errorf("unexpected error sizing standard attributes: %w", err)
+		}
+
+		for name, size := range sizes {
+			cb(scope, name, size)
+		}
+		return nil
+	}
+
+	err := scan(traceql.AttributeScopeResource, backend.DedicatedColumnScopeResource, DefinitionLevelResourceAttrs, FieldResourceAttrKey, FieldResourceAttrVal)
+	if err != nil {
+		return err
+	}
+
+	return scan(traceql.AttributeScopeSpan, backend.DedicatedColumnScopeSpan, DefinitionLevelResourceSpansILSSpanAttrs, FieldSpanAttrKey, FieldSpanAttrVal)
+}
+
+// columnSize sums the length of all values of a string column
+func columnSize(ctx context.Context, pf *parquet.File, path string) (uint64, error) {
+	makeIter := makeIterFunc(ctx, pf.RowGroups(), pf)
+
+	iter := makeIter(path, pq.NewSkipNilsPredicate(), "value")
+	defer iter.Close()
+
+	var size uint64
+	for {
+		match, err := iter.Next()
+		if err != nil {
+			return 0, err
+		}
+		if match == nil {
+			break
+		}
+		for _, e := range match.Entries {
+			size += uint64(len(e.Value.ByteArray()))
+		}
+	}
+
+	return size, nil
+}
+
+// keyValueSizes adds the length of the string values of all generic attributes to the sizes of their keys
+func keyValueSizes(ctx context.Context, pf *parquet.File, sizes map[string]uint64, definitionLevel int, keyPath, stringPath string) error {
+	makeIter := makeIterFunc(ctx, pf.RowGroups(), pf)
+
+	iter := pq.NewJoinIterator(definitionLevel, []pq.Iterator{
+		makeIter(keyPath, nil, "key"),
+		makeIter(stringPath, pq.NewSkipNilsPredicate(), "string"),
+	}, nil)
+	defer iter.Close()
+
+	for {
+		match, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		if match == nil {
+			break
+		}
+
+		var key string
+		var size uint64
+		for _, e := range match.Entries {
+			switch e.Key {
+			case "key":
+				key = e.Value.String()
+			case "string":
+				size += uint64(len(e.Value.ByteArray()))
+			}
+		}
+		if key == "" {
+			continue
+		}
+		sizes[key] += size
+	}
+
+	return nil
+}
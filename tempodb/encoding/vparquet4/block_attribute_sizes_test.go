@@ -0,0 +1,53 @@
+package vparquet4
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestBackendBlockAttributeSizes(t *testing.T) {
+	traces, _, resourceAttrVals, spanAttrVals := makeTraces()
+	block := makeBackendBlockWithTraces(t, traces)
+
+	expected := map[traceql.AttributeScope]map[string]uint64{
+		traceql.AttributeScopeResource: {},
+		traceql.AttributeScopeSpan:     {},
+	}
+	for _, tr := range traces {
+		for _, rs := range tr.ResourceSpans {
+			for _, a := range rs.Resource.Attrs {
+				expected[traceql.AttributeScopeResource][a.Key] += uint64(len(a.Value[0]))
+			}
+			for _, ss := range rs.ScopeSpans {
+				for _, s := range ss.Spans {
+					for _, a := range s.Attrs {
+						expected[traceql.AttributeScopeSpan][a.Key] += uint64(len(a.Value[0]))
+					}
+				}
+			}
+		}
+	}
+	// every resource and span has the same dedicated attributes
+	for i := 1; i <= 5; i++ {
+		name := "dedicated.resource." + string(rune('0'+i))
+		expected[traceql.AttributeScopeResource][name] = uint64(30 * len(resourceAttrVals[name]))
+		name = "dedicated.span." + string(rune('0'+i))
+		expected[traceql.AttributeScopeSpan][name] = uint64(300 * len(spanAttrVals[name]))
+	}
+
+	found := map[traceql.AttributeScope]map[string]uint64{
+		traceql.AttributeScopeResource: {},
+		traceql.AttributeScopeSpan:     {},
+	}
+	err := block.AttributeSizes(context.Background(), func(scope traceql.AttributeScope, name string, bytes uint64) {
+		found[scope][name] += bytes
+	}, common.DefaultSearchOptions())
+	require.NoError(t, err)
+
+	require.Equal(t, expected, found)
+}
@@ -22,11 +22,10 @@ func (b *backendBlock) open(ctx context.Context) (*parquet.File, *parquet.Reader
 	o := []parquet.FileOption{
 		parquet.SkipBloomFilters(true),
 		parquet.SkipPageIndex(true),
-		parquet.FileSchema(parquetSchema),
 		parquet.FileReadMode(parquet.ReadModeAsync),
 	}
 
-	pf, err := parquet.OpenFile(br, int64(b.meta.Size), o...)
+	pf, err := openFile(br, int64(b.meta.Size), o...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -46,7 +45,7 @@ func (b *backendBlock) rawIter(ctx context.Context, pool *rowPool) (*rawIterator
 		return nil, fmt.Errorf("cannot find trace ID column in '%s' in block '%s'", TraceIDColumnName, b.meta.BlockID.String())
 	}
 
-	return &rawIterator{b.meta.BlockID.String(), r, traceIDIndex, pool}, nil
+	return &rawIterator{b.meta.BlockID.String(), r, traceIDIndex, pool, !hasAddedColumns(pf)}, nil
 }
 
 type rawIterator struct {
@@ -54,6 +53,8 @@ type rawIterator struct {
 	r            *parquet.Reader //nolint:all //deprecated
 	traceIDIndex int
 	pool         *rowPool
+	// setCounts is true for blocks written before the span event and link counts were added
+	setCounts bool
 }
 
 var _ RawIterator = (*rawIterator)(nil)
@@ -72,7 +73,14 @@ func (i *rawIterator) Next(context.Context) (common.ID, parquet.Row, error) {
 	rows := []parquet.Row{i.pool.Get()}
 	n, err := i.r.ReadRows(rows)
 	if n > 0 {
-		return i.getTraceID(rows[0]), rows[0], nil
+		row := rows[0]
+		if i.setCounts {
+			row, err = setEventAndLinkCountsOfRow(row)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error setting event and link counts in block %s: %w", i.blockID, err)
+			}
+		}
+		return i.getTraceID(row), row, nil
 	}
 
 	if errors.Is(err, io.EOF) {
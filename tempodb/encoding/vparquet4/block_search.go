@@ -68,7 +68,6 @@ func (b *backendBlock) openForSearch(ctx context.Context, opts common.SearchOpti
 		parquet.SkipBloomFilters(true),
 		parquet.SkipPageIndex(true),
 		parquet.FileReadMode(parquet.ReadModeAsync),
-		parquet.FileSchema(parquetSchema),
 	}
 
 	// if the read buffer size provided is <= 0 then we'll use the parquet default
@@ -84,7 +83,7 @@ func (b *backendBlock) openForSearch(ctx context.Context, opts common.SearchOpti
 
 	span, _ := opentracing.StartSpanFromContext(ctx, "parquet.OpenFile")
 	defer span.Finish()
-	pf, err := parquet.OpenFile(cachedReaderAt, int64(b.meta.Size), o...)
+	pf, err := openFile(cachedReaderAt, int64(b.meta.Size), o...)
 
 	return pf, backendReaderAt, err
 }
@@ -355,6 +354,10 @@ func makeIterFunc(ctx context.Context, rgs []parquet.RowGroup, pf *parquet.File)
 	return func(name string, predicate pq.Predicate, selectAs string) pq.Iterator {
 		index, _ := pq.GetColumnIndexByPath(pf, name)
 		if index == -1 {
+			// blocks written before a column was added don't have any values for it
+			if _, ok := addedColumns[name]; ok {
+				return &rowNumberIterator{}
+			}
 			// TODO - don't panic, error instead
 			panic("column not found in parquet file:" + name)
 		}
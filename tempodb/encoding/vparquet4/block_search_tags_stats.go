@@ -0,0 +1,156 @@
+package vparquet4
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/parquet-go/parquet-go"
+
+	pq "github.com/grafana/tempo/pkg/parquetquery"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// TagStats reports the values of all resource and span attributes of the block. Values of well-known and dedicated
+// columns are read from the dictionaries of the columns. Values of generic attributes have to be joined with their
+// keys and are read from the key/value columns.
+func (b *backendBlock) TagStats(ctx context.Context, scope traceql.AttributeScope, cb common.TagStatsCallback, opts common.SearchOptions) error {
+	span, derivedCtx := opentracing.StartSpanFromContext(ctx, "parquet.backendBlock.TagStats",
+		opentracing.Tags{
+			"blockID":   b.meta.BlockID,
+			"tenantID":  b.meta.TenantID,
+			"blockSize": b.meta.Size,
+		})
+	defer span.Finish()
+
+	pf, rr, err := b.openForSearch(derivedCtx, opts)
+	if err != nil {
+		return fmt.Errorf("unexpected error opening parquet file: %w", err)
+	}
+	defer func() { span.SetTag("inspectedBytes", rr.BytesRead()) }()
+
+	return tagStats(derivedCtx, scope, cb, pf, b.meta.DedicatedColumns)
+}
+
+func tagStats(ctx context.Context, scope traceql.AttributeScope, cb common.TagStatsCallback, pf *parquet.File, dc backend.DedicatedColumns) error {
+	scanColumns := func(scope traceql.AttributeScope, specialMappings map[string]string, columnMapping dedicatedColumnMapping, definitionLevel int, keyPath, stringPath, intPath, floatPath, boolPath string) error {
+		// special attributes
+		for lbl, col := range specialMappings {
+			err := searchSpecialTagValues(ctx, col, pf, func(v traceql.Static) bool {
+				cb(scope, lbl, v)
+				return false
+			})
+			if err != nil {
+				return fmt.Errorf("unexpected error searching special tags: %w", err)
+			}
+		}
+
+		// dedicated attributes
+		var err error
+		columnMapping.forEach(func(lbl string, c dedicatedColumn) {
+			if err != nil {
+				return
+			}
+			err = searchSpecialTagValues(ctx, c.ColumnPath, pf, func(v traceql.Static) bool {
+				cb(scope, lbl, v)
+				return false
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("unexpected error searching dedicated tags: %w", err)
+		}
+
+		// standard attributes
+		err = scanKeyValues(ctx, pf, scope, cb, definitionLevel, keyPath, stringPath, intPath, floatPath, boolPath)
+		if err != nil {
+			return fmt.Errorf("unexpected error searching standard tags: %w", err)
+		}
+
+		return nil
+	}
+
+	// resource
+	if scope == traceql.AttributeScopeNone || scope == traceql.AttributeScopeResource {
+		columnMapping := dedicatedColumnsToColumnMapping(dc, backend.DedicatedColumnScopeResource)
+		err := scanColumns(traceql.AttributeScopeResource, traceqlResourceLabelMappings, columnMapping,
+			DefinitionLevelResourceAttrs,
+			FieldResourceAttrKey,
+			FieldResourceAttrVal,
+			FieldResourceAttrValInt,
+			FieldResourceAttrValDouble,
+			FieldResourceAttrValBool)
+		if err != nil {
+			return err
+		}
+	}
+	// span
+	if scope == traceql.AttributeScopeNone || scope == traceql.AttributeScopeSpan {
+		columnMapping := dedicatedColumnsToColumnMapping(dc, backend.DedicatedColumnScopeSpan)
+		err := scanColumns(traceql.AttributeScopeSpan, traceqlSpanLabelMappings, columnMapping,
+			DefinitionLevelResourceSpansILSSpanAttrs,
+			FieldSpanAttrKey,
+			FieldSpanAttrVal,
+			FieldSpanAttrValInt,
+			FieldSpanAttrValDouble,
+			FieldSpanAttrValBool)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scanKeyValues reports the values of all generic attributes with their keys
+func scanKeyValues(ctx context.Context, pf *parquet.File, scope traceql.AttributeScope, cb common.TagStatsCallback, definitionLevel int, keyPath, stringPath, intPath, floatPath, boolPath string) error {
+	makeIter := makeIterFunc(ctx, pf.RowGroups(), pf)
+	skipNils := pq.NewSkipNilsPredicate()
+
+	iter, err := pq.NewLeftJoinIterator(definitionLevel,
+		[]pq.Iterator{makeIter(keyPath, nil, "key")},
+		[]pq.Iterator{
+			makeIter(stringPath, skipNils, "string"),
+			makeIter(intPath, skipNils, "int"),
+			makeIter(floatPath, skipNils, "float"),
+			makeIter(boolPath, skipNils, "bool"),
+		}, nil)
+	if err != nil {
+		return fmt.Errorf("pq.NewLeftJoinIterator failed: %w", err)
+	}
+	defer iter.Close()
+
+	for {
+		match, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		if match == nil {
+			break
+		}
+
+		var key string
+		for _, e := range match.Entries {
+			if e.Key == "key" {
+				key = e.Value.String()
+				break
+			}
+		}
+		if key == "" {
+			continue
+		}
+
+		for _, e := range match.Entries {
+			if e.Key == "key" {
+				continue
+			}
+			callback(func(v traceql.Static) bool {
+				cb(scope, key, v)
+				return false
+			}, e.Value)
+		}
+	}
+
+	return nil
+}
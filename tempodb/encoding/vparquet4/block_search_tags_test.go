@@ -47,6 +47,47 @@ func TestBackendBlockSearchTags(t *testing.T) {
 	testVals(traceql.AttributeScopeSpan, spanAttrVals)
 }
 
+func TestBackendBlockTagStats(t *testing.T) {
+	traces, _, resourceAttrVals, spanAttrVals := makeTraces()
+	block := makeBackendBlockWithTraces(t, traces)
+
+	testVals := func(scope traceql.AttributeScope, expected map[traceql.AttributeScope]map[string]string) {
+		// values are reported once per row group, compare the distinct values
+		found := map[traceql.AttributeScope]map[string]map[string]struct{}{}
+		cb := func(s traceql.AttributeScope, tag string, v traceql.Static) {
+			if found[s] == nil {
+				found[s] = map[string]map[string]struct{}{}
+			}
+			if found[s][tag] == nil {
+				found[s][tag] = map[string]struct{}{}
+			}
+			found[s][tag][v.EncodeToString(false)] = struct{}{}
+		}
+
+		err := block.TagStats(context.Background(), scope, cb, common.DefaultSearchOptions())
+		require.NoError(t, err)
+
+		require.Len(t, found, len(expected), "scope: %s", scope)
+		for s, attrs := range expected {
+			require.Len(t, found[s], len(attrs), "scope: %s", s)
+			for k, v := range attrs {
+				require.Equal(t, map[string]struct{}{v: {}}, found[s][k], "attr: %s, scope: %s", k, s)
+			}
+		}
+	}
+
+	testVals(traceql.AttributeScopeNone, map[traceql.AttributeScope]map[string]string{
+		traceql.AttributeScopeResource: resourceAttrVals,
+		traceql.AttributeScopeSpan:     spanAttrVals,
+	})
+	testVals(traceql.AttributeScopeResource, map[traceql.AttributeScope]map[string]string{
+		traceql.AttributeScopeResource: resourceAttrVals,
+	})
+	testVals(traceql.AttributeScopeSpan, map[traceql.AttributeScope]map[string]string{
+		traceql.AttributeScopeSpan: spanAttrVals,
+	})
+}
+
 func TestBackendBlockSearchTagValues(t *testing.T) {
 	traces, intrinsics, resourceAttrs, spanAttrs := makeTraces()
 	block := makeBackendBlockWithTraces(t, traces)
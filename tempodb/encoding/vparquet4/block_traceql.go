@@ -879,6 +879,8 @@ const (
 	columnPathSpanNestedSetLeft  = "rs.list.element.ss.list.element.Spans.list.element.NestedSetLeft"
 	columnPathSpanNestedSetRight = "rs.list.element.ss.list.element.Spans.list.element.NestedSetRight"
 	columnPathSpanParentID       = "rs.list.element.ss.list.element.Spans.list.element.ParentID"
+	columnPathSpanEventCount     = "rs.list.element.ss.list.element.Spans.list.element.EventCount"
+	columnPathSpanLinkCount      = "rs.list.element.ss.list.element.Spans.list.element.LinkCount"
 	columnPathEventName          = "rs.list.element.ss.list.element.Spans.list.element.Events.list.element.Name"
 	columnPathLinkTraceID        = "rs.list.element.ss.list.element.Spans.list.element.Links.list.element.TraceID"
 	columnPathLinkSpanID         = "rs.list.element.ss.list.element.Spans.list.element.Links.list.element.SpanID"
@@ -924,6 +926,8 @@ var intrinsicColumnLookups = map[traceql.Intrinsic]struct {
 	traceql.IntrinsicNestedSetLeft:        {intrinsicScopeSpan, traceql.TypeInt, columnPathSpanNestedSetLeft},
 	traceql.IntrinsicNestedSetRight:       {intrinsicScopeSpan, traceql.TypeInt, columnPathSpanNestedSetRight},
 	traceql.IntrinsicNestedSetParent:      {intrinsicScopeSpan, traceql.TypeInt, columnPathSpanParentID},
+	traceql.IntrinsicEventCount:           {intrinsicScopeSpan, traceql.TypeInt, columnPathSpanEventCount},
+	traceql.IntrinsicLinkCount:            {intrinsicScopeSpan, traceql.TypeInt, columnPathSpanLinkCount},
 
 	traceql.IntrinsicTraceRootService: {intrinsicScopeTrace, traceql.TypeString, columnPathRootServiceName},
 	traceql.IntrinsicTraceRootSpan:    {intrinsicScopeTrace, traceql.TypeString, columnPathRootSpanName},
@@ -987,6 +991,18 @@ func (b *backendBlock) Fetch(ctx context.Context, req traceql.FetchSpansRequest,
 		rgs = rowGroupsFromFile(pf, opts)
 	}
 
+	if req.StartTimeUnixNanos > 0 && req.EndTimeUnixNanos > 0 {
+		rgs, err = b.rowGroupsInTimeRange(ctx, pf, rgs, req.StartTimeUnixNanos, req.EndTimeUnixNanos)
+		if err != nil {
+			return traceql.FetchSpansResponse{}, err
+		}
+	}
+
+	rgs, err = b.rowGroupsWithAttributes(ctx, pf, rgs, req)
+	if err != nil {
+		return traceql.FetchSpansResponse{}, err
+	}
+
 	iter, err := fetch(ctx, req, pf, rgs, b.meta.DedicatedColumns)
 	if err != nil {
 		return traceql.FetchSpansResponse{}, fmt.Errorf("creating fetch iter: %w", err)
@@ -1875,6 +1891,23 @@ func createSpanIterator(makeIter makeIterFn, innerIterators []parquetquery.Itera
 			columnSelectAs[columnPathSpanParentID] = columnPathSpanParentID
 			continue
 
+		case traceql.IntrinsicEventCount:
+			pred, err := createIntPredicate(cond.Op, cond.Operands)
+			if err != nil {
+				return nil, err
+			}
+			addPredicate(columnPathSpanEventCount, pred)
+			columnSelectAs[columnPathSpanEventCount] = columnPathSpanEventCount
+			continue
+
+		case traceql.IntrinsicLinkCount:
+			pred, err := createIntPredicate(cond.Op, cond.Operands)
+			if err != nil {
+				return nil, err
+			}
+			addPredicate(columnPathSpanLinkCount, pred)
+			columnSelectAs[columnPathSpanLinkCount] = columnPathSpanLinkCount
+			continue
 		}
 
 		// Well-known attribute?
@@ -1946,7 +1979,9 @@ func createSpanIterator(makeIter makeIterFn, innerIterators []parquetquery.Itera
 				traceql.IntrinsicStructuralSibling,
 				traceql.IntrinsicNestedSetLeft,
 				traceql.IntrinsicNestedSetRight,
-				traceql.IntrinsicNestedSetParent:
+				traceql.IntrinsicNestedSetParent,
+				traceql.IntrinsicEventCount,
+				traceql.IntrinsicLinkCount:
 				continue
 			}
 			addPredicate(entry.columnPath, nil)
@@ -2610,6 +2645,10 @@ func (c *spanCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
 			if c.nestedSetRightExplicit {
 				sp.addSpanAttr(traceql.IntrinsicNestedSetRightAttribute, traceql.NewStaticInt(int(kv.Value.Int32())))
 			}
+		case columnPathSpanEventCount:
+			sp.addSpanAttr(traceql.IntrinsicEventCountAttribute, traceql.NewStaticInt(int(kv.Value.Int32())))
+		case columnPathSpanLinkCount:
+			sp.addSpanAttr(traceql.IntrinsicLinkCountAttribute, traceql.NewStaticInt(int(kv.Value.Int32())))
 		default:
 			// TODO - This exists for span-level dedicated columns like http.status_code
 			// Are nils possible here?
@@ -3169,6 +3208,70 @@ func (b *backendBlock) rowGroupsForShard(ctx context.Context, pf *parquet.File,
 	return matches, nil
 }
 
+// rowGroupsInTimeRange uses the time ranges of the row groups in the block trace ID index to skip
+// the given row groups that have no traces overlapping start and end. The index isn't read if the block is
+// entirely inside the range.
+func (b *backendBlock) rowGroupsInTimeRange(ctx context.Context, pf *parquet.File, rgs []parquet.RowGroup, start, end uint64) ([]parquet.RowGroup, error) {
+	if blockInTimeRange(b.meta, start, end) {
+		return rgs, nil
+	}
+
+	span, _ := opentracing.StartSpanFromContext(ctx, "parquet.rowGroupsInTimeRange")
+	defer span.Finish()
+
+	cacheInfo := &backend.CacheInfo{
+		Meta: b.meta,
+		Role: cache.RoleTraceIDIdx,
+	}
+
+	indexBytes, err := b.r.Read(ctx, common.NameIndex, b.meta.BlockID, b.meta.TenantID, cacheInfo)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		// No index, check all groups
+		return rgs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := unmarshalIndex(indexBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing index (%s, %s): %w", b.meta.TenantID, b.meta.BlockID, err)
+	}
+
+	all := pf.RowGroups()
+	if len(index.RowGroups) != len(all) {
+		return rgs, nil
+	}
+
+	// rgs are a subset of the row groups of the file in the same order
+	matches := make([]parquet.RowGroup, 0, len(rgs))
+	next := 0
+	for i := 0; i < len(all) && next < len(rgs); i++ {
+		if all[i] != rgs[next] {
+			continue
+		}
+		next++
+
+		if index.Overlaps(i, start, end) {
+			matches = append(matches, all[i])
+		}
+	}
+
+	span.SetTag("totalRowGroups", len(rgs))
+	span.SetTag("matchedRowGroups", len(matches))
+
+	return matches, nil
+}
+
+// blockInTimeRange returns true if the time range of the block meta is inside start and end. The meta times are
+// truncated to seconds, so traces can end up to a second after the end time of the meta.
+func blockInTimeRange(meta *backend.BlockMeta, start, end uint64) bool {
+	if meta.StartTime.IsZero() || meta.EndTime.IsZero() {
+		return false
+	}
+	return uint64(meta.StartTime.UnixNano()) >= start && uint64(meta.EndTime.Add(time.Second).UnixNano()) <= end
+}
+
 func otlpStatusToTraceqlStatus(v uint64) traceql.Status {
 	// Map OTLP status code back to TraceQL enum.
 	// For other values, use the raw integer.
@@ -156,6 +156,10 @@ func TestBackendBlockSearchTraceQL(t *testing.T) {
 		{"link:spanID", traceql.MustExtractFetchSpansRequestWithMetadata(`{link:spanID = "1234567890abcdef"}`)},
 		{"link:traceID", traceql.MustExtractFetchSpansRequestWithMetadata(`{link:traceID = "1234567890abcdef1234567890abcdef"}`)},
 		{"link.opentracing.ref_type", traceql.MustExtractFetchSpansRequestWithMetadata(`{link.opentracing.ref_type = "child-of"}`)},
+		// Event and link counts
+		{"span:eventCount", traceql.MustExtractFetchSpansRequestWithMetadata(`{span:eventCount > 0}`)},
+		{"span:eventCount exact", traceql.MustExtractFetchSpansRequestWithMetadata(`{span:eventCount = 2}`)},
+		{"span:linkCount", traceql.MustExtractFetchSpansRequestWithMetadata(`{span:linkCount >= 1}`)},
 		// Basic data types and operations
 		{".float = 456.78", traceql.MustExtractFetchSpansRequestWithMetadata(`{.float = 456.78}`)},             // Float ==
 		{".float != 456.79", traceql.MustExtractFetchSpansRequestWithMetadata(`{.float != 456.79}`)},           // Float !=
@@ -283,6 +287,8 @@ func TestBackendBlockSearchTraceQL(t *testing.T) {
 		{"Intrinsic: event:name", traceql.MustExtractFetchSpansRequestWithMetadata(`{event:name = "x2"}`)},
 		{"Intrinsic: link:spanID", traceql.MustExtractFetchSpansRequestWithMetadata(`{link:spanID = "ffffffffffffffff"}`)},
 		{"Intrinsic: link:traceID", traceql.MustExtractFetchSpansRequestWithMetadata(`{link:traceID = "ffffffffffffffffffffffffffffffff"}`)},
+		{"Intrinsic: span:eventCount", traceql.MustExtractFetchSpansRequestWithMetadata(`{span:eventCount > 2}`)},
+		{"Intrinsic: span:linkCount", traceql.MustExtractFetchSpansRequestWithMetadata(`{span:linkCount > 1}`)},
 		{"Well-known attribute: service.name not match", traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelServiceName + ` = "notmyservice"}`)},
 		{"Well-known attribute: http.status_code not match", traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPStatusCode + ` = 200}`)},
 		{"Well-known attribute: http.status_code not match", traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPStatusCode + ` > 600}`)},
@@ -532,7 +538,9 @@ func fullyPopulatedTestTrace(id common.ID) *Trace {
 									},
 									{TimeSinceStartNano: 2, Name: "e2", Attrs: []Attribute{}},
 								},
-								Links: links,
+								EventCount: ptr(int32(2)),
+								Links:      links,
+								LinkCount:  ptr(int32(1)),
 								DedicatedAttributes: DedicatedAttributes{
 									String01: ptr("dedicated-span-attr-value-1"),
 									String02: ptr("dedicated-span-attr-value-2"),
@@ -589,6 +597,8 @@ func fullyPopulatedTestTrace(id common.ID) *Trace {
 								Kind:                   int(v1.Span_SPAN_KIND_SERVER),
 								DroppedAttributesCount: 45,
 								DroppedEventsCount:     46,
+								EventCount:             ptr(int32(0)),
+								LinkCount:              ptr(int32(0)),
 								Attrs: []Attribute{
 									attr("foo", "ghi"),
 									attr("bar", 1234),
@@ -1022,6 +1032,114 @@ func BenchmarkBackendBlockQueryRange(b *testing.B) {
 	}
 }
 
+// TestEventAndLinkCountsBeforeColumnsAdded queries the test block, which was written before the span event and link
+// count columns were added.
+func TestEventAndLinkCountsBeforeColumnsAdded(t *testing.T) {
+	rawR, _, _, err := local.New(&local.Config{
+		Path: "./test-data",
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	ctx := context.Background()
+
+	blocks, _, err := r.Blocks(ctx, "single-tenant")
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+
+	meta, err := r.BlockMeta(ctx, blocks[0], "single-tenant")
+	require.NoError(t, err)
+
+	b := newBackendBlock(meta, r)
+
+	pf, _, err := b.openForSearch(ctx, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.False(t, hasAddedColumns(pf))
+
+	search := func(query string) int {
+		e := traceql.NewEngine()
+		resp, err := e.ExecuteSearch(ctx, &tempopb.SearchRequest{Query: query, Limit: 1000, SpansPerSpanSet: 1000}, traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+			return b.Fetch(ctx, req, common.DefaultSearchOptions())
+		}))
+		require.NoError(t, err)
+
+		spans := 0
+		for _, tr := range resp.Traces {
+			for _, ss := range tr.SpanSets {
+				spans += len(ss.Spans)
+			}
+		}
+		return spans
+	}
+
+	// the counts are missing, nothing matches
+	for _, q := range []string{
+		`{span:eventCount > 0}`,
+		`{span:eventCount = 0}`,
+		`{span:linkCount >= 0}`,
+		`{span:eventCount >= 0 && span:linkCount >= 0}`,
+	} {
+		require.Zero(t, search(q), q)
+	}
+
+	// other conditions still match
+	all := search(`{}`)
+	require.NotZero(t, all)
+	require.Equal(t, all, search(`{span:eventCount > 0 || duration >= 0}`))
+
+	// compaction sets the counts from the events and links
+	iter, err := b.rawIter(ctx, newRowPool(10))
+	require.NoError(t, err)
+	defer iter.Close()
+	require.True(t, iter.setCounts)
+
+	spans := 0
+	for {
+		_, row, err := iter.Next(ctx)
+		require.NoError(t, err)
+		if row == nil {
+			break
+		}
+
+		tr := &Trace{}
+		require.NoError(t, parquetSchema.Reconstruct(tr, row))
+		for _, rs := range tr.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, s := range ss.Spans {
+					require.Equal(t, ptr(int32(len(s.Events))), s.EventCount)
+					require.Equal(t, ptr(int32(len(s.Links))), s.LinkCount)
+					spans++
+				}
+			}
+		}
+	}
+	require.Equal(t, all, spans)
+}
+
+func TestSetEventAndLinkCountsOfRow(t *testing.T) {
+	tr := fullyPopulatedTestTrace(test.ValidTraceID(nil))
+
+	// round trip the expected trace as well, so empty slices compare equal
+	want := &Trace{}
+	require.NoError(t, parquetSchema.Reconstruct(want, parquetSchema.Deconstruct(nil, tr)))
+
+	for i := range tr.ResourceSpans {
+		for j := range tr.ResourceSpans[i].ScopeSpans {
+			for k := range tr.ResourceSpans[i].ScopeSpans[j].Spans {
+				tr.ResourceSpans[i].ScopeSpans[j].Spans[k].EventCount = nil
+				tr.ResourceSpans[i].ScopeSpans[j].Spans[k].LinkCount = nil
+			}
+		}
+	}
+
+	row, err := setEventAndLinkCountsOfRow(parquetSchema.Deconstruct(nil, tr))
+	require.NoError(t, err)
+
+	actual := &Trace{}
+	require.NoError(t, parquetSchema.Reconstruct(actual, row))
+	require.Equal(t, want, actual)
+}
+
 func TestTraceIDShardingQuality(t *testing.T) {
 	// Use debug=1 go test -v -run=TestTraceIDShardingQuality
 	if os.Getenv("debug") != "1" {
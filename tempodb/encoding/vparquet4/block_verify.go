@@ -0,0 +1,197 @@
+package vparquet4
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/willf/bloom"
+
+	pq "github.com/grafana/tempo/pkg/parquetquery"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+var _ common.Verifier = (*backendBlock)(nil)
+
+// Verify reads the whole block and checks that the parquet footer and page checksums are valid, that the
+// number of traces agrees with the block meta and that every trace id is found by the bloom filters and
+// the index.
+func (b *backendBlock) Verify(ctx context.Context) error {
+	blooms, err := b.readBlooms(ctx)
+	if err != nil {
+		return err
+	}
+
+	idx, err := b.readIndex(ctx)
+	if err != nil {
+		return err
+	}
+
+	rr := &verifyReaderAt{ReaderAt: NewBackendReaderAt(ctx, b.r, DataFileName, b.meta)}
+	pf, err := openFile(rr, int64(b.meta.Size),
+		parquet.SkipBloomFilters(true),
+		parquet.SkipPageIndex(true),
+		parquet.FileReadMode(parquet.ReadModeSync),
+	)
+	if err != nil {
+		return rr.wrap(fmt.Errorf("error opening parquet file: %w", err))
+	}
+
+	if pf.NumRows() != int64(b.meta.TotalObjects) {
+		return fmt.Errorf("%w: parquet file has %d rows but meta has %d total objects", common.ErrCorrupted, pf.NumRows(), b.meta.TotalObjects)
+	}
+
+	rowGroups := pf.RowGroups()
+	if idx != nil && len(idx.RowGroups) != len(rowGroups) {
+		return fmt.Errorf("%w: index has %d row groups but parquet file has %d", common.ErrCorrupted, len(idx.RowGroups), len(rowGroups))
+	}
+
+	traceIDIndex, _ := pq.GetColumnIndexByPath(pf, TraceIDColumnName)
+	if traceIDIndex < 0 {
+		return fmt.Errorf("%w: cannot find trace ID column '%s'", common.ErrCorrupted, TraceIDColumnName)
+	}
+
+	for rgIdx, rg := range rowGroups {
+		for colIdx, cc := range rg.ColumnChunks() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			var checkID func(common.ID) error
+			if colIdx == traceIDIndex {
+				checkID = func(id common.ID) error {
+					shardKey := common.ShardKeyForTraceID(id, len(blooms))
+					if !blooms[shardKey].Test(id) {
+						return fmt.Errorf("%w: trace id %x not found in bloom %s", common.ErrCorrupted, id, common.BloomName(shardKey))
+					}
+					if idx != nil && idx.Find(id) != rgIdx {
+						return fmt.Errorf("%w: trace id %x in row group %d is indexed in row group %d", common.ErrCorrupted, id, rgIdx, idx.Find(id))
+					}
+					return nil
+				}
+			}
+
+			if err := verifyColumnChunk(cc, checkID); err != nil {
+				return rr.wrap(fmt.Errorf("error reading row group %d column %d: %w", rgIdx, colIdx, err))
+			}
+		}
+	}
+
+	return nil
+}
+
+// verifyColumnChunk reads all pages of the column chunk. parquet-go validates the page checksums while
+// reading. If checkID is set it is called for every value in the chunk.
+func verifyColumnChunk(cc parquet.ColumnChunk, checkID func(common.ID) error) error {
+	pages := cc.Pages()
+	defer pages.Close()
+
+	buf := make([]parquet.Value, 1024)
+	for {
+		page, err := pages.ReadPage()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if checkID != nil {
+			err = checkPageIDs(page, buf, checkID)
+		}
+		parquet.Release(page)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func checkPageIDs(page parquet.Page, buf []parquet.Value, checkID func(common.ID) error) error {
+	values := page.Values()
+	for {
+		n, err := values.ReadValues(buf)
+		for _, v := range buf[:n] {
+			if checkErr := checkID(v.ByteArray()); checkErr != nil {
+				return checkErr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (b *backendBlock) readBlooms(ctx context.Context) ([]*bloom.BloomFilter, error) {
+	if b.meta.BloomShardCount <= 0 {
+		return nil, fmt.Errorf("%w: invalid bloom shard count %d", common.ErrCorrupted, b.meta.BloomShardCount)
+	}
+
+	blooms := make([]*bloom.BloomFilter, 0, b.meta.BloomShardCount)
+	for shard := 0; shard < int(b.meta.BloomShardCount); shard++ {
+		nameBloom := common.BloomName(shard)
+		bloomBytes, err := b.r.Read(ctx, nameBloom, b.meta.BlockID, b.meta.TenantID, nil)
+		if errors.Is(err, backend.ErrDoesNotExist) {
+			return nil, fmt.Errorf("%w: bloom %s does not exist", common.ErrCorrupted, nameBloom)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving bloom %s: %w", nameBloom, err)
+		}
+
+		filter := &bloom.BloomFilter{}
+		if _, err := filter.ReadFrom(bytes.NewReader(bloomBytes)); err != nil {
+			return nil, fmt.Errorf("%w: error parsing bloom %s: %w", common.ErrCorrupted, nameBloom, err)
+		}
+		blooms = append(blooms, filter)
+	}
+
+	return blooms, nil
+}
+
+// readIndex returns the trace id index of the block or nil if the block was written without one.
+func (b *backendBlock) readIndex(ctx context.Context) (*index, error) {
+	indexBytes, err := b.r.Read(ctx, common.NameIndex, b.meta.BlockID, b.meta.TenantID, nil)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving index: %w", err)
+	}
+
+	idx, err := unmarshalIndex(indexBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error parsing index: %w", common.ErrCorrupted, err)
+	}
+
+	return idx, nil
+}
+
+// verifyReaderAt remembers the last error returned by the backend so that failures reading the data
+// object can be told apart from corrupted data.
+type verifyReaderAt struct {
+	io.ReaderAt
+	err error
+}
+
+func (r *verifyReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := r.ReaderAt.ReadAt(p, off)
+	if err != nil {
+		r.err = err
+	}
+	return n, err
+}
+
+// wrap marks err as corruption unless it was caused by the backend. A data object that is missing or
+// shorter than the size in the meta is corrupted as well.
+func (r *verifyReaderAt) wrap(err error) error {
+	if r.err != nil && !errors.Is(r.err, backend.ErrDoesNotExist) && !errors.Is(r.err, io.EOF) && !errors.Is(r.err, io.ErrUnexpectedEOF) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", common.ErrCorrupted, err)
+}
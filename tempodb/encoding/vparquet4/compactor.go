@@ -72,6 +72,23 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 		sch                 = parquet.SchemaOf(new(Trace))
 	)
 
+	// Combine the rows of a trace one at a time, they are returned to the pool.
+	combineRows := func(n int, next func() (parquet.Row, error)) (parquet.Row, error) {
+		tr, connected, err := combineTraceRows(sch, pool, n, next)
+		if err != nil {
+			return nil, err
+		}
+		if !connected {
+			c.opts.DisconnectedTrace()
+		}
+		if tr != nil && tr.RootSpanName == "" {
+			c.opts.RootlessTrace()
+		}
+
+		c.opts.ObjectsCombined(int(compactionLevel), 1)
+		return sch.Deconstruct(pool.Get(), tr), nil
+	}
+
 	// Dedupe rows and also call the metrics callback.
 	combine := func(rows []parquet.Row) (parquet.Row, error) {
 		if len(rows) == 0 {
@@ -90,6 +107,9 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 			for i := 1; i < len(rows); i++ {
 				pool.Put(rows[i])
 			}
+			if c.opts.ObjectsDeduped != nil {
+				c.opts.ObjectsDeduped(int(compactionLevel), len(rows)-1)
+			}
 			return rows[0], nil
 		}
 
@@ -110,37 +130,118 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 		}
 
 		// Time to combine.
-		cmb := NewCombiner()
-		for i, row := range rows {
-			tr := new(Trace)
-			err := sch.Reconstruct(tr, row)
+		i := 0
+		return combineRows(len(rows), func() (parquet.Row, error) {
+			i++
+			return rows[i-1], nil
+		})
+	}
+
+	// Same as combine for the rows of a trace spilled to disk. The rows are read back one at a time.
+	combineSpilled := func(spill *spillFile, size int) (parquet.Row, error) {
+		it, err := spill.iter(pool)
+		if err != nil {
+			return nil, err
+		}
+		first, err := it.next()
+		if err != nil {
+			return nil, err
+		}
+
+		isEqual := true
+		for isEqual {
+			row, err := it.next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
 			if err != nil {
+				pool.Put(first)
 				return nil, err
 			}
-			cmb.ConsumeWithFinal(tr, i == len(rows)-1)
+			isEqual = first.Equal(row)
 			pool.Put(row)
 		}
-		tr, _, connected := cmb.Result()
-		if !connected {
-			c.opts.DisconnectedTrace()
+		if isEqual {
+			if c.opts.ObjectsDeduped != nil {
+				c.opts.ObjectsDeduped(int(compactionLevel), spill.rows-1)
+			}
+			return first, nil
 		}
-		if tr != nil && tr.RootSpanName == "" {
-			c.opts.RootlessTrace()
+
+		pool.Put(first)
+
+		it, err = spill.iter(pool)
+		if err != nil {
+			return nil, err
 		}
 
-		c.opts.ObjectsCombined(int(compactionLevel), 1)
-		return sch.Deconstruct(pool.Get(), tr), nil
+		if c.opts.MaxBytesPerTrace > 0 && size > c.opts.MaxBytesPerTrace {
+			// Trace too large to compact
+			first, err = it.next()
+			if err != nil {
+				return nil, err
+			}
+			for {
+				row, err := it.next()
+				if errors.Is(err, io.EOF) {
+					return first, nil
+				}
+				if err != nil {
+					pool.Put(first)
+					return nil, err
+				}
+				c.opts.SpansDiscarded(countSpans(sch, row))
+				pool.Put(row)
+			}
+		}
+
+		return combineRows(spill.rows, it.next)
 	}
 
 	var (
-		m               = newMultiblockIterator(bookmarks, combine)
+		m       = newMultiblockIterator(bookmarks, combine)
+		grouper = &rowGrouper{
+			m:       m,
+			pool:    pool,
+			budget:  &memoryBudget{limit: int64(c.opts.MemoryBudgetBytes)},
+			dir:     c.opts.SpillPath,
+			spilled: c.opts.TraceSpilled,
+		}
+		combineGroup = func(group *rowGroup) (row parquet.Row, err error) {
+			defer func() {
+				err = errors.Join(err, grouper.done(group))
+			}()
+
+			if group.spill != nil {
+				return combineSpilled(group.spill, group.size)
+			}
+			return combine(group.rows)
+		}
 		recordsPerBlock = (totalRecords / int(c.opts.OutputBlocks))
 		currentBlock    *streamingBlock
+		next            = func() (common.ID, parquet.Row, error) {
+			id, group, err := grouper.next(ctx, false)
+			if err != nil {
+				return nil, nil, err
+			}
+			row, err := combineGroup(group)
+			if err != nil {
+				return nil, nil, fmt.Errorf("combining: %w", err)
+			}
+			return id, row, nil
+		}
 	)
 	defer m.Close()
 
+	// combine traces in a pool of goroutines while this one writes the output blocks
+	if c.opts.Concurrency > 1 {
+		p := newParallelCombiner(ctx, grouper, combineGroup, c.opts.Concurrency)
+		defer p.close()
+		next = p.next
+	}
+
 	for {
-		lowestID, lowestObject, err := m.Next(ctx)
+		lowestID, lowestObject, err := next()
 		if errors.Is(err, io.EOF) {
 			break
 		}
@@ -159,6 +260,7 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 				TotalObjects:      recordsPerBlock, // Just an estimate
 				ReplicationFactor: inputs[0].ReplicationFactor,
 				DedicatedColumns:  inputs[0].DedicatedColumns,
+				AttributeScopes:   backend.MergeAttributeScopes(c.opts.BlockConfig.AttributeScopesMaxNames, inputs...),
 			}
 
 			currentBlock = newStreamingBlock(ctx, &c.opts.BlockConfig, newMeta, r, w, tempo_io.NewBufferedWriter)
@@ -265,6 +367,28 @@ func (c *Compactor) finishBlock(ctx context.Context, block *streamingBlock, l lo
 	return nil
 }
 
+// combineTraceRows reconstructs n rows of the same trace one at a time and combines them into one trace. Besides
+// the combined trace only the row that is combined is held in memory, the rows are returned to the pool. It returns
+// whether the combined trace is connected.
+func combineTraceRows(sch *parquet.Schema, pool *rowPool, n int, next func() (parquet.Row, error)) (*Trace, bool, error) {
+	cmb := NewCombiner()
+	for i := 0; i < n; i++ {
+		row, err := next()
+		if err != nil {
+			return nil, false, err
+		}
+		tr := new(Trace)
+		err = sch.Reconstruct(tr, row)
+		if err != nil {
+			return nil, false, err
+		}
+		cmb.ConsumeWithFinal(tr, i == n-1)
+		pool.Put(row)
+	}
+	tr, _, connected := cmb.Result()
+	return tr, connected, nil
+}
+
 type rowPool struct {
 	pool sync.Pool
 }
@@ -283,6 +407,16 @@ func (r *rowPool) Get() parquet.Row {
 	return r.pool.Get().(parquet.Row)
 }
 
+// clone returns a copy of the row that doesn't share memory with it. The row is returned to the pool.
+func (r *rowPool) clone(row parquet.Row) parquet.Row {
+	c := r.Get()
+	for _, v := range row {
+		c = append(c, v.Clone())
+	}
+	r.Put(row)
+	return c
+}
+
 func (r *rowPool) Put(row parquet.Row) {
 	// Clear before putting into the pool.
 	// This is important so that pool entries don't hang
@@ -0,0 +1,132 @@
+package vparquet4
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// combineJob is a group of rows of the same trace read from the input blocks
+type combineJob struct {
+	id    common.ID
+	group *rowGroup
+
+	done chan struct{}
+	row  parquet.Row
+	err  error
+}
+
+// parallelCombiner reads the rows of the input blocks and combines the rows of each trace in a pool of goroutines,
+// while the caller writes the combined rows. Combined rows are returned in the order of the input. The number of
+// traces in flight is bounded to twice the number of goroutines to limit memory.
+type parallelCombiner struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	ordered chan *combineJob
+	wg      sync.WaitGroup
+}
+
+func newParallelCombiner(ctx context.Context, g *rowGrouper, combine func(*rowGroup) (parquet.Row, error), workers int) *parallelCombiner {
+	ctx, cancel := context.WithCancel(ctx)
+
+	p := &parallelCombiner{
+		ctx:     ctx,
+		cancel:  cancel,
+		ordered: make(chan *combineJob, workers*2),
+	}
+	work := make(chan *combineJob, workers)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(p.ordered)
+		defer close(work)
+
+		for {
+			job := &combineJob{done: make(chan struct{})}
+
+			// the values of the rows point into the pages of the input blocks, which are reused once the
+			// iterators move on. the rows are detached before they are handed to another goroutine.
+			id, group, err := g.next(ctx, true)
+			if err != nil {
+				// the end of the input and errors are returned in order
+				job.err = err
+				close(job.done)
+				select {
+				case p.ordered <- job:
+				case <-ctx.Done():
+				}
+				return
+			}
+
+			job.id = id
+			job.group = group
+
+			select {
+			case p.ordered <- job:
+			case <-ctx.Done():
+				_ = g.done(group)
+				return
+			}
+			select {
+			case work <- job:
+			case <-ctx.Done():
+				_ = g.done(group)
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+
+			for job := range work {
+				job.row, job.err = combine(job.group)
+				if job.err != nil {
+					job.err = fmt.Errorf("combining: %w", job.err)
+				}
+				close(job.done)
+			}
+		}()
+	}
+
+	return p
+}
+
+// next returns the next combined row in the order of the input, or io.EOF at the end of the input.
+func (p *parallelCombiner) next() (common.ID, parquet.Row, error) {
+	var job *combineJob
+	select {
+	case j, ok := <-p.ordered:
+		if !ok {
+			return nil, nil, io.EOF
+		}
+		job = j
+	case <-p.ctx.Done():
+		return nil, nil, p.ctx.Err()
+	}
+
+	select {
+	case <-job.done:
+	case <-p.ctx.Done():
+		return nil, nil, p.ctx.Err()
+	}
+
+	if job.err != nil {
+		return nil, nil, job.err
+	}
+	return job.id, job.row, nil
+}
+
+// close stops the goroutines. It has to be called before the input iterators are closed.
+func (p *parallelCombiner) close() {
+	p.cancel()
+	p.wg.Wait()
+}
@@ -0,0 +1,259 @@
+package vparquet4
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// rowGroup holds the rows of the same trace read from the input blocks. The rows are either held in memory or, if
+// they didn't fit into the memory budget of the compaction job, spilled to a file on local disk.
+type rowGroup struct {
+	rows  []parquet.Row
+	spill *spillFile
+	size  int // estimated size of all rows
+}
+
+// memoryBudget tracks the estimated size of the rows a compaction job holds in memory for combining.
+type memoryBudget struct {
+	limit int64
+	used  atomic.Int64
+}
+
+// reserve adds size to the used memory and returns false if that exceeds the limit. The size is reserved either way.
+func (b *memoryBudget) reserve(size int) bool {
+	used := b.used.Add(int64(size))
+	return b.limit <= 0 || used <= b.limit
+}
+
+func (b *memoryBudget) release(size int) {
+	b.used.Add(-int64(size))
+}
+
+// rowGrouper reads the rows of the same trace from the input blocks. When a group of rows exceeds the memory budget
+// the rows are spilled to local disk while they are read, so the copies of huge traces are never held in memory
+// together. Combining them still builds the combined trace in memory, see combineTraceRows. Groups of a single row
+// don't need combining and are never spilled.
+type rowGrouper struct {
+	m      *MultiBlockIterator[parquet.Row]
+	pool   *rowPool
+	budget *memoryBudget
+	dir    string
+
+	spilled func(bytes int)
+}
+
+// next returns the next group of rows. If detach is set the rows in memory don't share memory with the input blocks,
+// so they stay valid once the input blocks are read further.
+func (g *rowGrouper) next(ctx context.Context, detach bool) (common.ID, *rowGroup, error) {
+	group := &rowGroup{}
+	id, err := g.m.nextGroupFunc(ctx, func(row parquet.Row) error {
+		size := estimateProtoSizeFromParquetRow(row)
+		group.size += size
+
+		if group.spill == nil {
+			if g.budget.reserve(size) || len(group.rows) == 0 {
+				if detach {
+					row = g.pool.clone(row)
+				}
+				group.rows = append(group.rows, row)
+				return nil
+			}
+
+			// Over budget, move the rows read so far to disk
+			spill, err := newSpillFile(g.dir)
+			if err != nil {
+				return err
+			}
+			group.spill = spill
+			g.budget.release(group.size)
+
+			for _, r := range group.rows {
+				if err := spill.write(r); err != nil {
+					return err
+				}
+				g.pool.Put(r)
+			}
+			group.rows = nil
+		}
+
+		err := group.spill.write(row)
+		g.pool.Put(row)
+		return err
+	})
+	if err != nil {
+		if group.spill != nil {
+			_ = group.spill.close()
+		}
+		return nil, nil, err
+	}
+
+	if group.spill != nil {
+		if err := group.spill.flush(); err != nil {
+			_ = group.spill.close()
+			return nil, nil, err
+		}
+		if g.spilled != nil {
+			g.spilled(group.size)
+		}
+	}
+
+	return id, group, nil
+}
+
+// done releases the memory of the group once it was combined.
+func (g *rowGrouper) done(group *rowGroup) error {
+	if group.spill != nil {
+		return group.spill.close()
+	}
+
+	g.budget.release(group.size)
+	return nil
+}
+
+// spillFile is a temporary file holding the rows of a trace. Rows are written one after the other and can be read
+// back any number of times.
+type spillFile struct {
+	f    *os.File
+	w    *bufio.Writer
+	rows int
+	buf  []byte
+}
+
+func newSpillFile(dir string) (*spillFile, error) {
+	f, err := os.CreateTemp(dir, "tempo-compaction-spill-")
+	if err != nil {
+		return nil, fmt.Errorf("creating spill file: %w", err)
+	}
+
+	return &spillFile{
+		f: f,
+		w: bufio.NewWriter(f),
+	}, nil
+}
+
+// write appends the row to the file. Values are written as their kind, levels, column and binary representation.
+func (s *spillFile) write(row parquet.Row) error {
+	buf := binary.AppendUvarint(s.buf[:0], uint64(len(row)))
+	for _, v := range row {
+		buf = binary.AppendVarint(buf, int64(v.Kind()))
+		buf = binary.AppendUvarint(buf, uint64(v.RepetitionLevel()))
+		buf = binary.AppendUvarint(buf, uint64(v.DefinitionLevel()))
+		buf = binary.AppendUvarint(buf, uint64(v.Column()))
+		if v.IsNull() {
+			continue
+		}
+
+		b := v.Bytes()
+		buf = binary.AppendUvarint(buf, uint64(len(b)))
+		buf = append(buf, b...)
+	}
+	s.buf = buf
+
+	if _, err := s.w.Write(buf); err != nil {
+		return fmt.Errorf("writing spill file: %w", err)
+	}
+	s.rows++
+	return nil
+}
+
+func (s *spillFile) flush() error {
+	if err := s.w.Flush(); err != nil {
+		return fmt.Errorf("writing spill file: %w", err)
+	}
+	return nil
+}
+
+// iter returns an iterator over the rows of the file, starting with the first one.
+func (s *spillFile) iter(pool *rowPool) (*spillIterator, error) {
+	if _, err := s.f.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("seeking spill file: %w", err)
+	}
+
+	return &spillIterator{
+		r:    bufio.NewReader(s.f),
+		rows: s.rows,
+		pool: pool,
+	}, nil
+}
+
+// close removes the file
+func (s *spillFile) close() error {
+	return errors.Join(s.f.Close(), os.Remove(s.f.Name()))
+}
+
+type spillIterator struct {
+	r    *bufio.Reader
+	rows int
+	pool *rowPool
+}
+
+// next returns the next row from the pool, or io.EOF after the last one. The values of the row don't share memory
+// with the other rows.
+func (it *spillIterator) next() (parquet.Row, error) {
+	if it.rows == 0 {
+		return nil, io.EOF
+	}
+	it.rows--
+
+	n, err := binary.ReadUvarint(it.r)
+	if err != nil {
+		return nil, fmt.Errorf("reading spill file: %w", err)
+	}
+
+	row := it.pool.Get()
+	for i := uint64(0); i < n; i++ {
+		v, err := it.readValue()
+		if err != nil {
+			it.pool.Put(row)
+			return nil, fmt.Errorf("reading spill file: %w", err)
+		}
+		row = append(row, v)
+	}
+
+	return row, nil
+}
+
+func (it *spillIterator) readValue() (parquet.Value, error) {
+	kind, err := binary.ReadVarint(it.r)
+	if err != nil {
+		return parquet.Value{}, err
+	}
+	rep, err := binary.ReadUvarint(it.r)
+	if err != nil {
+		return parquet.Value{}, err
+	}
+	def, err := binary.ReadUvarint(it.r)
+	if err != nil {
+		return parquet.Value{}, err
+	}
+	column, err := binary.ReadUvarint(it.r)
+	if err != nil {
+		return parquet.Value{}, err
+	}
+
+	if kind < 0 {
+		// null
+		return parquet.Value{}.Level(int(rep), int(def), int(column)), nil
+	}
+
+	size, err := binary.ReadUvarint(it.r)
+	if err != nil {
+		return parquet.Value{}, err
+	}
+	b := make([]byte, size)
+	if _, err := io.ReadFull(it.r, b); err != nil {
+		return parquet.Value{}, err
+	}
+
+	return parquet.Kind(kind).Value(b).Level(int(rep), int(def), int(column)), nil
+}
@@ -0,0 +1,235 @@
+package vparquet4
+
+import (
+	"context"
+	crand "crypto/rand"
+	"flag"
+	"io"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/require"
+
+	tempo_io "github.com/grafana/tempo/pkg/io"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestSpillFile(t *testing.T) {
+	dir := t.TempDir()
+	pool := newRowPool(0)
+	sch := parquet.SchemaOf(new(Trace))
+
+	rows := []parquet.Row{
+		sch.Deconstruct(nil, fullyPopulatedTestTrace(test.ValidTraceID(nil))),
+		sch.Deconstruct(nil, fullyPopulatedTestTrace(test.ValidTraceID(nil))),
+	}
+
+	spill, err := newSpillFile(dir)
+	require.NoError(t, err)
+	for _, row := range rows {
+		require.NoError(t, spill.write(row))
+	}
+	require.NoError(t, spill.flush())
+
+	// the rows can be read more than once
+	for i := 0; i < 2; i++ {
+		it, err := spill.iter(pool)
+		require.NoError(t, err)
+
+		for _, expected := range rows {
+			row, err := it.next()
+			require.NoError(t, err)
+			require.True(t, expected.Equal(row))
+			require.NoError(t, sch.Reconstruct(new(Trace), row))
+		}
+
+		_, err = it.next()
+		require.ErrorIs(t, err, io.EOF)
+	}
+
+	require.NoError(t, spill.close())
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+// TestCombineSpilledMemory asserts the memory used to combine the copies of a trace read back from a spill file.
+// Only the combined trace and the copy that is combined are held in memory, independent of the number of copies.
+func TestCombineSpilledMemory(t *testing.T) {
+	const copies = 20
+
+	sch := parquet.SchemaOf(new(Trace))
+	id := test.ValidTraceID(nil)
+	trp, _ := traceToParquet(&backend.BlockMeta{}, id, test.MakeTraceWithSpanCount(10, 100, id), nil)
+	row := sch.Deconstruct(nil, trp)
+
+	spill, err := newSpillFile(t.TempDir())
+	require.NoError(t, err)
+	defer spill.close()
+	for i := 0; i < copies; i++ {
+		require.NoError(t, spill.write(row))
+	}
+	require.NoError(t, spill.flush())
+	trp, row = nil, nil //nolint:ineffassign,wastedassign // release the copy for the measurements
+
+	pool := newRowPool(0)
+	liveHeap := func() int64 {
+		var m runtime.MemStats
+		// twice to also free the victim caches of the pools
+		runtime.GC()
+		runtime.GC()
+		runtime.ReadMemStats(&m)
+		return int64(m.HeapAlloc)
+	}
+
+	// the size of one copy in memory, as row and reconstructed trace
+	it, err := spill.iter(pool)
+	require.NoError(t, err)
+	base := liveHeap()
+	copyRow, err := it.next()
+	require.NoError(t, err)
+	copyTrace := new(Trace)
+	require.NoError(t, sch.Reconstruct(copyTrace, copyRow))
+	copySize := liveHeap() - base
+	require.Positive(t, copySize)
+	runtime.KeepAlive(copyRow)
+	runtime.KeepAlive(copyTrace)
+	copyRow, copyTrace = nil, nil //nolint:ineffassign,wastedassign // release the copy for the measurements
+
+	it, err = spill.iter(pool)
+	require.NoError(t, err)
+	base = liveHeap()
+
+	var peak int64
+	tr, _, err := combineTraceRows(sch, pool, copies, func() (parquet.Row, error) {
+		row, err := it.next()
+		peak = max(peak, liveHeap()-base)
+		return row, err
+	})
+	require.NoError(t, err)
+	require.Len(t, tr.ResourceSpans, 10)
+
+	require.Less(t, peak, 3*copySize, "combining %d copies of %d bytes used %d bytes", copies, copySize, peak)
+}
+
+func TestMemoryBudget(t *testing.T) {
+	b := &memoryBudget{limit: 10}
+	require.True(t, b.reserve(6))
+	require.False(t, b.reserve(6))
+
+	// the size is reserved even if it exceeds the limit
+	b.release(6)
+	require.True(t, b.reserve(4))
+	require.False(t, b.reserve(1))
+
+	// no limit
+	b = &memoryBudget{}
+	require.True(t, b.reserve(100))
+}
+
+func TestCompactSpill(t *testing.T) {
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+	ctx := context.Background()
+
+	blockConfig := common.BlockConfig{Version: VersionString}
+	blockConfig.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+	require.NoError(t, common.ValidateConfig(&blockConfig))
+
+	ids := make([][]byte, 20)
+	for i := range ids {
+		ids[i] = make([]byte, 16)
+		_, err := crand.Read(ids[i])
+		require.NoError(t, err)
+	}
+
+	// blocks with different traces of the same ids so they are combined
+	writeBlock := func() *backend.BlockMeta {
+		meta := &backend.BlockMeta{
+			TenantID:     tenantID,
+			BlockID:      uuid.New(),
+			TotalObjects: len(ids),
+		}
+		sb := newStreamingBlock(ctx, &blockConfig, meta, r, w, tempo_io.NewBufferedWriter)
+		for _, id := range ids {
+			trp, _ := traceToParquet(meta, id, test.MakeTraceWithSpanCount(2, 5, id), nil)
+			require.NoError(t, sb.Add(trp, 0, 0))
+		}
+		_, err := sb.Complete()
+		require.NoError(t, err)
+		return sb.meta
+	}
+	meta1, meta2 := writeBlock(), writeBlock()
+	inputs := []*backend.BlockMeta{meta1, meta2, meta2}
+
+	compact := func(budget, concurrency int) (map[string]*Trace, int) {
+		spillPath := t.TempDir()
+		spilled := 0
+
+		c := NewCompactor(common.CompactionOptions{
+			BlockConfig:       blockConfig,
+			OutputBlocks:      1,
+			FlushSizeBytes:    30_000_000,
+			Concurrency:       concurrency,
+			MemoryBudgetBytes: budget,
+			SpillPath:         spillPath,
+			ObjectsCombined:   func(compactionLevel, objects int) {},
+			DisconnectedTrace: func() {},
+			RootlessTrace:     func() {},
+			TraceSpilled:      func(int) { spilled++ },
+		})
+
+		newMeta, err := c.Compact(ctx, log.NewNopLogger(), r, w, inputs)
+		require.NoError(t, err)
+		require.Len(t, newMeta, 1)
+
+		// spill files are removed
+		entries, err := os.ReadDir(spillPath)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+
+		iter, err := newBackendBlock(newMeta[0], r).rawIter(ctx, newRowPool(0))
+		require.NoError(t, err)
+		defer iter.Close()
+
+		sch := parquet.SchemaOf(new(Trace))
+		traces := map[string]*Trace{}
+		for {
+			id, row, err := iter.Next(ctx)
+			require.NoError(t, err)
+			if id == nil {
+				break
+			}
+
+			tr := new(Trace)
+			require.NoError(t, sch.Reconstruct(tr, row))
+			traces[string(id)] = tr
+		}
+		return traces, spilled
+	}
+
+	expected, spilled := compact(0, 1)
+	require.Len(t, expected, len(ids))
+	require.Equal(t, 0, spilled)
+
+	// every trace is spilled once its second copy is read
+	actual, spilled := compact(1, 1)
+	require.Equal(t, len(ids), spilled)
+	require.Equal(t, expected, actual)
+
+	actual, spilled = compact(1, 4)
+	require.Equal(t, len(ids), spilled)
+	require.Equal(t, expected, actual)
+}
@@ -214,3 +214,101 @@ func TestCompact(t *testing.T) {
 	require.Equal(t, uint32(1), newMeta[0].ReplicationFactor)
 	require.Equal(t, dedicatedColumns, newMeta[0].DedicatedColumns)
 }
+
+func TestCompactDedupes(t *testing.T) {
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+
+	blockConfig := common.BlockConfig{Version: VersionString}
+	blockConfig.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+	require.NoError(t, common.ValidateConfig(&blockConfig))
+
+	var combined, deduped int
+	c := NewCompactor(common.CompactionOptions{
+		BlockConfig:     blockConfig,
+		OutputBlocks:    1,
+		FlushSizeBytes:  30_000_000,
+		ObjectsCombined: func(_, objects int) { combined += objects },
+		ObjectsDeduped:  func(_, objects int) { deduped += objects },
+	})
+
+	meta := createTestBlock(t, context.Background(), &blockConfig, r, w, 10, 10, 10, 1, nil)
+
+	// the same block is compacted three times so all copies of its traces are identical
+	newMeta, err := c.Compact(context.Background(), log.NewNopLogger(), r, w, []*backend.BlockMeta{meta, meta, meta})
+	require.NoError(t, err)
+	require.Len(t, newMeta, 1)
+	require.Equal(t, 10, newMeta[0].TotalObjects)
+	require.Equal(t, 0, combined)
+	require.Equal(t, 20, deduped)
+}
+
+func TestCompactParallel(t *testing.T) {
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+	ctx := context.Background()
+
+	blockConfig := common.BlockConfig{Version: VersionString}
+	blockConfig.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+	// small row groups so the output is flushed while traces are combined
+	blockConfig.RowGroupSizeBytes = 10_000
+	require.NoError(t, common.ValidateConfig(&blockConfig))
+
+	meta1 := createTestBlock(t, ctx, &blockConfig, r, w, 50, 5, 5, 1, nil)
+	meta2 := createTestBlock(t, ctx, &blockConfig, r, w, 50, 5, 5, 1, nil)
+
+	// the second block is compacted twice so its traces are combined
+	inputs := []*backend.BlockMeta{meta1, meta2, meta2}
+
+	compact := func(concurrency int) ([]common.ID, map[string]*Trace) {
+		c := NewCompactor(common.CompactionOptions{
+			BlockConfig:     blockConfig,
+			OutputBlocks:    1,
+			FlushSizeBytes:  30_000_000,
+			Concurrency:     concurrency,
+			ObjectsCombined: func(compactionLevel, objects int) {},
+		})
+
+		newMeta, err := c.Compact(ctx, log.NewNopLogger(), r, w, inputs)
+		require.NoError(t, err)
+		require.Len(t, newMeta, 1)
+
+		iter, err := newBackendBlock(newMeta[0], r).rawIter(ctx, newRowPool(0))
+		require.NoError(t, err)
+		defer iter.Close()
+
+		sch := parquet.SchemaOf(new(Trace))
+		traces := map[string]*Trace{}
+		var ids []common.ID
+		for {
+			id, row, err := iter.Next(ctx)
+			require.NoError(t, err)
+			if id == nil {
+				break
+			}
+			ids = append(ids, id)
+
+			tr := new(Trace)
+			require.NoError(t, sch.Reconstruct(tr, row))
+			traces[string(id)] = tr
+		}
+		return ids, traces
+	}
+
+	// the output of the parallel compaction is the same, in the same order
+	sequentialIDs, sequential := compact(1)
+	parallelIDs, parallel := compact(4)
+	require.Len(t, sequential, 100)
+	require.Equal(t, sequentialIDs, parallelIDs)
+	require.Equal(t, sequential, parallel)
+}
@@ -0,0 +1,76 @@
+package vparquet4
+
+import (
+	"io"
+
+	"github.com/parquet-go/parquet-go"
+
+	pq "github.com/grafana/tempo/pkg/parquetquery"
+)
+
+// addedColumns are columns added to the schema after the first vParquet4 blocks were written. Blocks written
+// before are missing these columns.
+var addedColumns = map[string]struct{}{
+	columnPathSpanEventCount: {},
+	columnPathSpanLinkCount:  {},
+}
+
+// openFile opens a parquet file of this encoding. The schema of the file is not read from the file if it
+// matches the current schema. Files written before the addedColumns have a different schema, it is read from
+// the file so readers convert their rows to the current schema.
+func openFile(r io.ReaderAt, size int64, opts ...parquet.FileOption) (*parquet.File, error) {
+	pf, err := parquet.OpenFile(r, size, append(opts, parquet.FileSchema(parquetSchema))...)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasAddedColumns(pf) {
+		return pf, nil
+	}
+
+	return parquet.OpenFile(r, size, opts...)
+}
+
+// hasAddedColumns returns false if the file was written before the addedColumns were added to the schema.
+func hasAddedColumns(pf *parquet.File) bool {
+	for c := range addedColumns {
+		if !pq.HasColumn(pf, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// setEventAndLinkCountsOfRow sets the span event and link counts of a row read from a file without these columns.
+func setEventAndLinkCountsOfRow(row parquet.Row) (parquet.Row, error) {
+	tr := &Trace{}
+	if err := parquetSchema.Reconstruct(tr, row); err != nil {
+		return nil, err
+	}
+	setEventAndLinkCounts(tr)
+	return parquetSchema.Deconstruct(row[:0], tr), nil
+}
+
+// setEventAndLinkCounts sets the span event and link counts of traces read from files without these
+// columns. Their values are nil after the conversion to the current schema.
+func setEventAndLinkCounts(tr *Trace) {
+	for i := range tr.ResourceSpans {
+		rs := &tr.ResourceSpans[i]
+		for j := range rs.ScopeSpans {
+			ss := &rs.ScopeSpans[j]
+			for k := range ss.Spans {
+				s := &ss.Spans[k]
+				setCount(&s.EventCount, len(s.Events))
+				setCount(&s.LinkCount, len(s.Links))
+			}
+		}
+	}
+}
+
+// setCount sets the value of an optional count. The value of a reused span is overwritten.
+func setCount(c **int32, n int) {
+	if *c == nil {
+		*c = new(int32)
+	}
+	**c = int32(n)
+}
@@ -0,0 +1,125 @@
+package vparquet4
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+// prevTrace is the schema of the first vParquet4 blocks, before the span event and link counts were added
+type prevTrace struct {
+	TraceID           []byte                  `parquet:""`
+	TraceIDText       string                  `parquet:",snappy"`
+	StartTimeUnixNano uint64                  `parquet:",delta"`
+	EndTimeUnixNano   uint64                  `parquet:",delta"`
+	DurationNano      uint64                  `parquet:",delta"`
+	RootServiceName   string                  `parquet:",dict"`
+	RootSpanName      string                  `parquet:",dict"`
+	ServiceStats      map[string]ServiceStats `parquet:""`
+	ResourceSpans     []prevResourceSpans     `parquet:"rs,list"`
+}
+
+type prevResourceSpans struct {
+	Resource   Resource         `parquet:""`
+	ScopeSpans []prevScopeSpans `parquet:"ss,list"`
+}
+
+type prevScopeSpans struct {
+	Scope InstrumentationScope `parquet:""`
+	Spans []prevSpan           `parquet:",list"`
+}
+
+type prevSpan struct {
+	SpanID                 []byte              `parquet:","`
+	ParentSpanID           []byte              `parquet:","`
+	ParentID               int32               `parquet:",delta"`
+	NestedSetLeft          int32               `parquet:",delta"`
+	NestedSetRight         int32               `parquet:",delta"`
+	Name                   string              `parquet:",snappy,dict"`
+	Kind                   int                 `parquet:",delta"`
+	TraceState             string              `parquet:",snappy"`
+	StartTimeUnixNano      uint64              `parquet:",delta"`
+	DurationNano           uint64              `parquet:",delta"`
+	StatusCode             int                 `parquet:",delta"`
+	StatusMessage          string              `parquet:",snappy"`
+	Attrs                  []Attribute         `parquet:",list"`
+	DroppedAttributesCount int32               `parquet:",snappy,delta"`
+	Events                 []Event             `parquet:",list"`
+	DroppedEventsCount     int32               `parquet:",snappy"`
+	Links                  []Link              `parquet:",list"`
+	DroppedLinksCount      int32               `parquet:",snappy"`
+	HttpMethod             *string             `parquet:",snappy,optional,dict"`
+	HttpUrl                *string             `parquet:",snappy,optional,dict"`
+	HttpStatusCode         *int64              `parquet:",snappy,optional"`
+	DedicatedAttributes    DedicatedAttributes `parquet:""`
+}
+
+func TestAddedColumnsAreLast(t *testing.T) {
+	prev := parquet.SchemaOf(&prevTrace{})
+
+	// the columns of the previous schema keep their index
+	for i, path := range prev.Columns() {
+		col, ok := parquetSchema.Lookup(path...)
+		require.True(t, ok, path)
+		require.Equal(t, i, col.ColumnIndex, path)
+	}
+	require.Len(t, parquetSchema.Columns(), len(prev.Columns())+len(addedColumns))
+
+	for c := range addedColumns {
+		col, ok := parquetSchema.Lookup(strings.Split(c, ".")...)
+		require.True(t, ok, c)
+		require.GreaterOrEqual(t, col.ColumnIndex, len(prev.Columns()), c)
+		require.True(t, col.Node.Optional(), c)
+	}
+}
+
+// TestReadWithPreviousSchema reads a block written with the current schema the way readers of the previous
+// schema do: with their schema instead of the one of the file.
+func TestReadWithPreviousSchema(t *testing.T) {
+	ctx := context.Background()
+	b := makeBackendBlockWithTraces(t, []*Trace{fullyPopulatedTestTrace(test.ValidTraceID(nil))})
+
+	data, err := b.r.Read(ctx, DataFileName, b.meta.BlockID, b.meta.TenantID, nil)
+	require.NoError(t, err)
+
+	pf, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)), parquet.FileSchema(parquet.SchemaOf(&prevTrace{})))
+	require.NoError(t, err)
+
+	prevTraces := make([]*prevTrace, 1)
+	_, err = parquet.NewGenericReader[*prevTrace](pf).Read(prevTraces)
+	require.NoError(t, err)
+
+	pf, err = parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	traces := make([]*Trace, 1)
+	_, err = parquet.NewGenericReader[*Trace](pf).Read(traces)
+	require.NoError(t, err)
+
+	// the previous schema reads all fields except the counts
+	want := traces[0]
+	for _, rs := range want.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for i := range ss.Spans {
+				require.NotNil(t, ss.Spans[i].EventCount)
+				require.NotNil(t, ss.Spans[i].LinkCount)
+				ss.Spans[i].EventCount = nil
+				ss.Spans[i].LinkCount = nil
+			}
+		}
+	}
+
+	prevJSON, err := json.Marshal(prevTraces[0])
+	require.NoError(t, err)
+	actual := &Trace{}
+	require.NoError(t, json.Unmarshal(prevJSON, actual))
+
+	require.Equal(t, want, actual)
+}
@@ -55,6 +55,14 @@ func CopyBlock(ctx context.Context, fromMeta, toMeta *backend.BlockMeta, from ba
 		return err
 	}
 
+	// Attribute index
+	if fromMeta.AttributeIndex {
+		err = cpy(common.NameAttributeIndex, &backend.CacheInfo{Role: cache.RoleAttributeIdx})
+		if err != nil {
+			return err
+		}
+	}
+
 	// Meta
 	err = to.WriteBlockMeta(ctx, toMeta)
 	return err
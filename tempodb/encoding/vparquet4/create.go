@@ -102,6 +102,11 @@ type streamingBlock struct {
 	to    backend.Writer
 	index *index
 
+	// attributeIndex is nil if no attributes are indexed
+	attributeIndex *attributeIndexBuilder
+	// attributeScopes is nil if the attribute names aren't recorded or were already known
+	attributeScopes *attributeScopesBuilder
+
 	currentBufferedTraces int
 	currentBufferedBytes  int
 }
@@ -111,6 +116,8 @@ func newStreamingBlock(ctx context.Context, cfg *common.BlockConfig, meta *backe
 	newMeta.StartTime = meta.StartTime
 	newMeta.EndTime = meta.EndTime
 	newMeta.ReplicationFactor = meta.ReplicationFactor
+	newMeta.CompactionLevel = meta.CompactionLevel
+	newMeta.AttributeScopes = meta.AttributeScopes
 
 	// TotalObjects is used here an an estimated count for the bloom filter.
 	// The real number of objects is tracked below.
@@ -120,6 +127,12 @@ func newStreamingBlock(ctx context.Context, cfg *common.BlockConfig, meta *backe
 	bw := createBufferedWriter(w)
 	pw := parquet.NewGenericWriter[*Trace](bw)
 
+	// compacted blocks start with the attribute names of their inputs
+	var attributeScopes *attributeScopesBuilder
+	if newMeta.AttributeScopes == nil {
+		attributeScopes = newAttributeScopesBuilder(cfg.AttributeScopesMaxNames, newMeta.DedicatedColumns)
+	}
+
 	return &streamingBlock{
 		ctx:   ctx,
 		meta:  newMeta,
@@ -130,6 +143,9 @@ func newStreamingBlock(ctx context.Context, cfg *common.BlockConfig, meta *backe
 		r:     r,
 		to:    to,
 		index: &index{},
+
+		attributeIndex:  newAttributeIndexBuilder(cfg.AttributeIndex, newMeta.DedicatedColumns),
+		attributeScopes: attributeScopes,
 	}
 }
 
@@ -140,7 +156,8 @@ func (b *streamingBlock) Add(tr *Trace, start, end uint32) error {
 	}
 	id := tr.TraceID
 
-	b.index.Add(id)
+	b.index.Add(id, tr.StartTimeUnixNano, tr.EndTimeUnixNano)
+	b.addAttributes(tr)
 	b.bloom.Add(id)
 	b.meta.ObjectAdded(id, start, end)
 	b.currentBufferedTraces++
@@ -155,7 +172,18 @@ func (b *streamingBlock) AddRaw(id []byte, row parquet.Row, start, end uint32) e
 		return err
 	}
 
-	b.index.Add(id)
+	traceStart, traceEnd := traceTimeRangeFromParquetRow(row)
+	b.index.Add(id, traceStart, traceEnd)
+	if b.attributeIndex != nil {
+		b.attributeIndex.AddRow(row)
+	}
+	if b.attributeScopes != nil {
+		tr := &Trace{}
+		if err := parquetSchema.Reconstruct(tr, row); err != nil {
+			return err
+		}
+		b.attributeScopes.Add(tr)
+	}
 	b.bloom.Add(id)
 	b.meta.ObjectAdded(id, start, end)
 	b.currentBufferedTraces++
@@ -164,6 +192,35 @@ func (b *streamingBlock) AddRaw(id []byte, row parquet.Row, start, end uint32) e
 	return nil
 }
 
+// addAttributes adds the attributes of the trace to the attribute index and scopes of the block
+func (b *streamingBlock) addAttributes(tr *Trace) {
+	if b.attributeIndex != nil {
+		b.attributeIndex.Add(tr)
+	}
+	if b.attributeScopes != nil {
+		b.attributeScopes.Add(tr)
+	}
+}
+
+// traceTimeRangeFromParquetRow returns the start and end time of the trace in a deconstructed parquet row
+func traceTimeRangeFromParquetRow(row parquet.Row) (start, end uint64) {
+	found := 0
+	for _, v := range row {
+		switch v.Column() {
+		case traceStartTimeColumn.ColumnIndex:
+			start = v.Uint64()
+			found++
+		case traceEndTimeColumn.ColumnIndex:
+			end = v.Uint64()
+			found++
+		}
+		if found == 2 {
+			break
+		}
+	}
+	return start, end
+}
+
 func (b *streamingBlock) EstimatedBufferedBytes() int {
 	return b.currentBufferedBytes
 }
@@ -175,6 +232,9 @@ func (b *streamingBlock) CurrentBufferedObjects() int {
 func (b *streamingBlock) Flush() (int, error) {
 	// Flush row group
 	b.index.Flush()
+	if b.attributeIndex != nil {
+		b.attributeIndex.Flush()
+	}
 	err := b.pw.Flush()
 	if err != nil {
 		return 0, err
@@ -193,6 +253,9 @@ func (b *streamingBlock) Flush() (int, error) {
 func (b *streamingBlock) Complete() (int, error) {
 	// Flush final row group
 	b.index.Flush()
+	if b.attributeIndex != nil {
+		b.attributeIndex.Flush()
+	}
 	b.meta.TotalRecords++
 	err := b.pw.Flush()
 	if err != nil {
@@ -236,6 +299,18 @@ func (b *streamingBlock) Complete() (int, error) {
 
 	b.meta.BloomShardCount = uint16(b.bloom.GetShardCount())
 
+	if b.attributeIndex != nil {
+		err = writeAttributeIndex(b.ctx, b.to, b.meta, b.attributeIndex.Index())
+		if err != nil {
+			return 0, fmt.Errorf("error writing attribute index: %w", err)
+		}
+		b.meta.AttributeIndex = true
+	}
+
+	if b.attributeScopes != nil {
+		b.meta.AttributeScopes = b.attributeScopes.Scopes()
+	}
+
 	return n, writeBlockMeta(b.ctx, b.to, b.meta, b.bloom, b.index)
 }
 
@@ -11,10 +11,22 @@ import (
 type index struct {
 	lastID    common.ID
 	dirty     bool
+	start     uint64
+	end       uint64
 	RowGroups []common.ID `json:"rowGroups"`
+	// StartTimes and EndTimes are the earliest start and latest end of the traces of each row group in
+	// nanoseconds. They are empty in indexes of blocks written before they were added.
+	StartTimes []uint64 `json:"startTimes,omitempty"`
+	EndTimes   []uint64 `json:"endTimes,omitempty"`
 }
 
-func (i *index) Add(id common.ID) {
+func (i *index) Add(id common.ID, start, end uint64) {
+	if !i.dirty || start < i.start {
+		i.start = start
+	}
+	if end > i.end {
+		i.end = end
+	}
 	i.lastID = id
 	i.dirty = true
 }
@@ -22,8 +34,21 @@ func (i *index) Add(id common.ID) {
 func (i *index) Flush() {
 	if i.dirty {
 		i.RowGroups = append(i.RowGroups, i.lastID)
+		i.StartTimes = append(i.StartTimes, i.start)
+		i.EndTimes = append(i.EndTimes, i.end)
 		i.dirty = false
+		i.start = 0
+		i.end = 0
+	}
+}
+
+// Overlaps returns true if the traces of the row group can overlap the time range in nanoseconds. This is always
+// the case if the index has no time ranges.
+func (i *index) Overlaps(rowGroup int, start, end uint64) bool {
+	if len(i.StartTimes) != len(i.RowGroups) || len(i.EndTimes) != len(i.RowGroups) {
+		return true
 	}
+	return i.StartTimes[rowGroup] <= end && i.EndTimes[rowGroup] >= start
 }
 
 func (i *index) Marshal() ([]byte, error) {
@@ -0,0 +1,122 @@
+package vparquet4
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestIndexTimeRanges(t *testing.T) {
+	i := &index{}
+	i.Add([]byte{0x01}, 200, 300)
+	i.Add([]byte{0x02}, 100, 250)
+	i.Flush()
+	i.Add([]byte{0x03}, 500, 600)
+	i.Flush()
+
+	require.Equal(t, []uint64{100, 500}, i.StartTimes)
+	require.Equal(t, []uint64{300, 600}, i.EndTimes)
+
+	require.True(t, i.Overlaps(0, 0, 100))
+	require.True(t, i.Overlaps(0, 300, 400))
+	require.False(t, i.Overlaps(0, 301, 400))
+	require.False(t, i.Overlaps(1, 301, 400))
+	require.True(t, i.Overlaps(1, 550, 560))
+
+	// indexes without time ranges never skip row groups
+	b, err := i.Marshal()
+	require.NoError(t, err)
+	old, err := unmarshalIndex([]byte(`{"rowGroups":["AQ==","Aw=="]}`))
+	require.NoError(t, err)
+	require.True(t, old.Overlaps(0, 1000, 2000))
+
+	i, err = unmarshalIndex(b)
+	require.NoError(t, err)
+	require.False(t, i.Overlaps(0, 1000, 2000))
+}
+
+func TestTraceTimeRangeFromParquetRow(t *testing.T) {
+	tr := &Trace{
+		TraceID:           test.ValidTraceID(nil),
+		StartTimeUnixNano: 100,
+		EndTimeUnixNano:   200,
+	}
+
+	start, end := traceTimeRangeFromParquetRow(parquetSchema.Deconstruct(nil, tr))
+	require.Equal(t, uint64(100), start)
+	require.Equal(t, uint64(200), end)
+}
+
+func TestRowGroupsInTimeRange(t *testing.T) {
+	// row groups of the block are [0], [1, 100] and [101, 200]
+	trs := make([]*Trace, 0, 201)
+	for i := 0; i <= 200; i++ {
+		trs = append(trs, &Trace{
+			TraceID:           test.ValidTraceID(nil),
+			StartTimeUnixNano: uint64(i * 1000),
+			EndTimeUnixNano:   uint64(i*1000 + 500),
+		})
+	}
+
+	ctx := context.Background()
+	b := makeBackendBlockWithTraces(t, trs)
+
+	pf, _, err := b.openForSearch(ctx, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Len(t, pf.RowGroups(), 3)
+
+	tcs := []struct {
+		start, end uint64
+		expected   []int
+	}{
+		{start: 0, end: 100, expected: []int{0}},
+		{start: 600, end: 900, expected: []int{}},
+		{start: 50_000, end: 60_000, expected: []int{1}},
+		{start: 100_400, end: 101_000, expected: []int{1, 2}},
+		{start: 300_000, end: 400_000, expected: []int{}},
+	}
+
+	for _, tc := range tcs {
+		rgs, err := b.rowGroupsInTimeRange(ctx, pf, pf.RowGroups(), tc.start, tc.end)
+		require.NoError(t, err)
+
+		require.Len(t, rgs, len(tc.expected))
+		for i, rg := range tc.expected {
+			require.Equal(t, pf.RowGroups()[rg], rgs[i])
+		}
+	}
+
+	// a subset of the row groups is filtered in place
+	rgs, err := b.rowGroupsInTimeRange(ctx, pf, pf.RowGroups()[1:], 0, 101_000)
+	require.NoError(t, err)
+	require.Equal(t, pf.RowGroups()[1:], rgs)
+
+	// the index isn't checked if the block meta is inside the range
+	b.meta.StartTime = time.Unix(1, 0)
+	b.meta.EndTime = time.Unix(2, 0)
+	rgs, err = b.rowGroupsInTimeRange(ctx, pf, pf.RowGroups(), uint64(time.Second), uint64(3*time.Second))
+	require.NoError(t, err)
+	require.Equal(t, pf.RowGroups(), rgs)
+
+	// the end time of the meta is truncated to seconds
+	rgs, err = b.rowGroupsInTimeRange(ctx, pf, pf.RowGroups(), uint64(time.Second), uint64(2*time.Second))
+	require.NoError(t, err)
+	require.Empty(t, rgs)
+}
+
+func TestBlockInTimeRange(t *testing.T) {
+	meta := &backend.BlockMeta{}
+	require.False(t, blockInTimeRange(meta, 0, uint64(time.Hour)))
+
+	meta.StartTime = time.Unix(10, 0)
+	meta.EndTime = time.Unix(20, 0)
+	require.True(t, blockInTimeRange(meta, uint64(10*time.Second), uint64(21*time.Second)))
+	require.False(t, blockInTimeRange(meta, uint64(11*time.Second), uint64(21*time.Second)))
+	require.False(t, blockInTimeRange(meta, uint64(10*time.Second), uint64(20*time.Second)))
+}
@@ -31,8 +31,38 @@ func newMultiblockIterator[T iteratable](bookmarks []*bookmark[T], combine combi
 }
 
 func (m *MultiBlockIterator[T]) Next(ctx context.Context) (common.ID, T, error) {
+	lowestID, lowestObjects, err := m.nextGroup(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lowestObject, err := m.combine(lowestObjects)
+	if err != nil {
+		return nil, nil, fmt.Errorf("combining: %w", err)
+	}
+
+	return lowestID, lowestObject, nil
+}
+
+// nextGroup returns the objects with the lowest id across the bookmarks without combining them.
+func (m *MultiBlockIterator[T]) nextGroup(ctx context.Context) (common.ID, []T, error) {
+	var lowestObjects []T
+	lowestID, err := m.nextGroupFunc(ctx, func(obj T) error {
+		lowestObjects = append(lowestObjects, obj)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return lowestID, lowestObjects, nil
+}
+
+// nextGroupFunc calls fn with each of the objects with the lowest id across the bookmarks, one at a time. Unlike
+// nextGroup the objects don't have to be held in memory together.
+func (m *MultiBlockIterator[T]) nextGroupFunc(ctx context.Context, fn func(T) error) (common.ID, error) {
 	if m.done(ctx) {
-		return nil, nil, io.EOF
+		return nil, io.EOF
 	}
 
 	var (
@@ -44,7 +74,7 @@ func (m *MultiBlockIterator[T]) Next(ctx context.Context) (common.ID, T, error)
 	for _, b := range m.bookmarks {
 		id, err := b.peekID(ctx)
 		if err != nil && !errors.Is(err, io.EOF) {
-			return nil, nil, err
+			return nil, err
 		}
 		if id == nil {
 			continue
@@ -64,29 +94,25 @@ func (m *MultiBlockIterator[T]) Next(ctx context.Context) (common.ID, T, error)
 	}
 
 	// now get the lowest objects from our bookmarks
-	lowestObjects := make([]T, 0, len(lowestBookmarks))
 	for _, b := range lowestBookmarks {
 		_, obj, err := b.current(ctx)
 		if err != nil && !errors.Is(err, io.EOF) {
-			return nil, nil, err
+			return nil, err
 		}
 		if obj == nil {
 			// this should never happen. id was non-nil above
-			return nil, nil, errors.New("unexpected nil object from lowest bookmark")
+			return nil, errors.New("unexpected nil object from lowest bookmark")
+		}
+		if err := fn(obj); err != nil {
+			return nil, err
 		}
-		lowestObjects = append(lowestObjects, obj)
-	}
-
-	lowestObject, err := m.combine(lowestObjects)
-	if err != nil {
-		return nil, nil, fmt.Errorf("combining: %w", err)
 	}
 
 	for _, b := range lowestBookmarks {
 		b.clear()
 	}
 
-	return lowestID, lowestObject, nil
+	return lowestID, nil
 }
 
 func (m *MultiBlockIterator[T]) Close() {
@@ -113,6 +113,9 @@ var (
 	}
 
 	parquetSchema = parquet.SchemaOf(&Trace{})
+
+	traceStartTimeColumn, _ = parquetSchema.Lookup(columnPathStartTimeUnixNano)
+	traceEndTimeColumn, _   = parquetSchema.Lookup(columnPathEndTimeUnixNano)
 )
 
 type Attribute struct {
@@ -186,6 +189,13 @@ type Span struct {
 
 	// Dynamically assignable dedicated attribute columns
 	DedicatedAttributes DedicatedAttributes `parquet:""`
+
+	// EventCount and LinkCount are the number of entries in Events and Links. They are precomputed
+	// so that queries on the presence or number of events and links don't need to read the nested lists.
+	// They were added after the first vParquet4 blocks were written: they are optional and the last
+	// columns of the schema, so the indexes of the other columns didn't change and older readers ignore them.
+	EventCount *int32 `parquet:",snappy,optional"`
+	LinkCount  *int32 `parquet:",snappy,optional"`
 }
 
 func (s *Span) IsRoot() bool {
@@ -445,6 +455,7 @@ func traceToParquet(meta *backend.BlockMeta, id common.ID, tr *tempopb.Trace, ot
 				for ie, e := range s.Events {
 					eventToParquet(e, &ss.Events[ie], s.StartTimeUnixNano)
 				}
+				setCount(&ss.EventCount, len(s.Events))
 
 				// nested set values do not come from the proto, they are calculated
 				// later. set all to 0
@@ -477,6 +488,7 @@ func traceToParquet(meta *backend.BlockMeta, id common.ID, tr *tempopb.Trace, ot
 				for ie, e := range s.Links {
 					linkToParquet(e, &ss.Links[ie])
 				}
+				setCount(&ss.LinkCount, len(s.Links))
 
 				ss.DroppedLinksCount = int32(s.DroppedLinksCount)
 
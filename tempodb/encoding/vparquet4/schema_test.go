@@ -149,11 +149,13 @@ func TestFieldsAreCleared(t *testing.T) {
 							attr("event-attr", 123),
 						},
 					}},
+					EventCount: ptr(int32(1)),
 					Links: []Link{{
 						Attrs: []Attribute{
 							attr("link-attr", 123),
 						},
 					}},
+					LinkCount: ptr(int32(1)),
 				}},
 			}},
 		}},
@@ -698,6 +700,7 @@ func TestTraceToParquet(t *testing.T) {
 									},
 									TraceState: "link trace state",
 								}},
+								LinkCount: ptr(int32(1)),
 							},
 							{
 								Name:              "span-with-event",
@@ -714,6 +717,7 @@ func TestTraceToParquet(t *testing.T) {
 										attr("event.attr", "bbb"),
 									},
 								}},
+								EventCount: ptr(int32(1)),
 							},
 						},
 					}},
@@ -724,6 +728,9 @@ func TestTraceToParquet(t *testing.T) {
 
 	for _, tt := range tsc {
 		t.Run(tt.name, func(t *testing.T) {
+			// the event and link counts of all spans are set, even if they are zero
+			setEventAndLinkCounts(&tt.expected)
+
 			var actual Trace
 			traceToParquet(&meta, tt.id, &tt.trace, &actual)
 			assertEqualEquateEmpty(t, tt.expected, actual)
@@ -103,6 +103,11 @@ func TestPartialReplay(t *testing.T) {
 	require.NoError(t, err)
 	require.ErrorContains(t, warning, "invalid magic footer of parquet file")
 
+	var report *common.WALReplayReport
+	require.ErrorAs(t, warning, &report)
+	require.Len(t, report.Skipped, 1)
+	require.Equal(t, info.Size()/2, report.BytesLost())
+
 	// Verify we iterate only the records from the first flush
 	iter, err := w2.Iterator()
 	require.NoError(t, err)
@@ -131,6 +136,57 @@ func TestPartialReplay(t *testing.T) {
 	require.Equal(t, count/2, gotCount)
 }
 
+// TestPartialReplayCorruptedData verifies that a page which can be opened but not read is skipped and reported.
+func TestPartialReplayCorruptedData(t *testing.T) {
+	decoder := model.MustNewSegmentDecoder(model.CurrentEncoding)
+	meta := backend.NewBlockMeta("fake", uuid.New(), VersionString, backend.EncNone, "")
+	w, err := createWALBlock(meta, t.TempDir(), model.CurrentEncoding, 0)
+	require.NoError(t, err)
+
+	// Flush 2 pages of 5 traces with 10 spans each
+	for i := 0; i < 10; i++ {
+		id := test.ValidTraceID(nil)
+		tr := test.MakeTraceWithSpanCount(1, 10, id)
+		trace.SortTrace(tr)
+
+		b1, err := decoder.PrepareForWrite(tr, 0, 0)
+		require.NoError(t, err)
+
+		b2, err := decoder.ToObject([][]byte{b1})
+		require.NoError(t, err)
+
+		require.NoError(t, w.Append(id, b2, 0, 0))
+		if i == 4 {
+			require.NoError(t, w.Flush())
+		}
+	}
+	require.NoError(t, w.Flush())
+
+	// Overwrite the data of page 2, the footer stays intact
+	fpath := w.filepathOf(1)
+	info, err := os.Stat(fpath)
+	require.NoError(t, err)
+	f, err := os.OpenFile(fpath, os.O_RDWR, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteAt(bytes.Repeat([]byte{0xff}, int(info.Size()/2)), 4)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	w2, warning, err := openWALBlock(filepath.Base(w.walPath()), filepath.Dir(w.walPath()), 0, 0)
+	require.NoError(t, err)
+
+	var report *common.WALReplayReport
+	require.ErrorAs(t, warning, &report)
+	require.Len(t, report.Skipped, 1)
+	assert.Equal(t, fpath, report.Skipped[0].File)
+	assert.Equal(t, info.Size(), report.BytesLost())
+	assert.Equal(t, 5, report.TracesAffected())
+	assert.Equal(t, 50, report.SpansLost())
+
+	// the traces of the first page are replayed
+	assert.Equal(t, 5, w2.BlockMeta().TotalObjects)
+}
+
 func TestParseFilename(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -0,0 +1,237 @@
+package tempodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/wal"
+)
+
+// SnapshotWAL uploads a copy of the files of the WAL blocks and local blocks of the tenant to the backend. Files
+// of the previous snapshot that are no longer in the WAL are deleted once the new snapshot is written.
+func (rw *readerWriter) SnapshotWAL(ctx context.Context, tenantID, ingesterID string, c *wal.TenantFilesCopy) (*backend.IngesterSnapshot, error) {
+	previous, err := rw.r.IngesterSnapshot(ctx, tenantID, ingesterID)
+	if err != nil && !errors.Is(err, backend.ErrDoesNotExist) {
+		return nil, fmt.Errorf("error reading previous snapshot: %w", err)
+	}
+
+	files := make([]backend.IngesterSnapshotFile, 0, len(c.Files))
+	for _, p := range c.Files {
+		size, err := rw.uploadWALFile(ctx, tenantID, ingesterID, c.Dir, p)
+		if err != nil {
+			return nil, fmt.Errorf("error uploading wal file %s: %w", p, err)
+		}
+		files = append(files, backend.IngesterSnapshotFile{Path: p, Size: size})
+	}
+
+	snapshot := backend.NewIngesterSnapshot(files)
+	err = rw.w.WriteIngesterSnapshot(ctx, tenantID, ingesterID, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("error writing snapshot: %w", err)
+	}
+
+	if previous != nil {
+		rw.deleteWALSnapshotFiles(ctx, tenantID, ingesterID, removedFiles(previous.Files, files))
+	}
+
+	level.Info(rw.logger).Log("msg", "wrote wal snapshot", "tenantID", tenantID, "ingesterID", ingesterID, "files", len(files), "size", snapshot.Size())
+	return snapshot, nil
+}
+
+func (rw *readerWriter) uploadWALFile(ctx context.Context, tenantID, ingesterID, dir, path string) (int64, error) {
+	f, err := os.Open(filepath.Join(dir, filepath.FromSlash(path)))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	size := info.Size()
+	err = rw.w.WriteIngesterSnapshotFile(ctx, tenantID, ingesterID, path, io.LimitReader(f, size), size)
+	return size, err
+}
+
+// PruneWALSnapshot removes the files of a block from the snapshot of the tenant taken by the ingester. It's
+// called once the block is flushed, so restoring the snapshot doesn't create the block a second time. The
+// snapshot is deleted once it has no files left. Returns false if there is no snapshot left.
+func (rw *readerWriter) PruneWALSnapshot(ctx context.Context, tenantID, ingesterID string, blockID uuid.UUID) (bool, error) {
+	snapshot, err := rw.r.IngesterSnapshot(ctx, tenantID, ingesterID)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return true, fmt.Errorf("error reading snapshot: %w", err)
+	}
+
+	kept := make([]backend.IngesterSnapshotFile, 0, len(snapshot.Files))
+	for _, f := range snapshot.Files {
+		if id, ok := wal.BlockIDFromPath(f.Path); !ok || id != blockID {
+			kept = append(kept, f)
+		}
+	}
+
+	if len(kept) == len(snapshot.Files) {
+		return true, nil
+	}
+	if len(kept) == 0 {
+		return false, rw.deleteWALSnapshot(ctx, tenantID, ingesterID, snapshot)
+	}
+
+	err = rw.w.WriteIngesterSnapshot(ctx, tenantID, ingesterID, &backend.IngesterSnapshot{
+		CreatedTime: snapshot.CreatedTime,
+		Files:       kept,
+	})
+	if err != nil {
+		return true, fmt.Errorf("error writing snapshot: %w", err)
+	}
+	rw.deleteWALSnapshotFiles(ctx, tenantID, ingesterID, removedFiles(snapshot.Files, kept))
+
+	level.Info(rw.logger).Log("msg", "pruned flushed block from wal snapshot", "tenantID", tenantID, "ingesterID", ingesterID, "blockID", blockID, "files", len(kept))
+	return true, nil
+}
+
+// deleteWALSnapshot deletes the snapshot before its files, so a snapshot never refers to deleted files.
+func (rw *readerWriter) deleteWALSnapshot(ctx context.Context, tenantID, ingesterID string, snapshot *backend.IngesterSnapshot) error {
+	err := rw.w.Delete(ctx, backend.IngesterSnapshotName, backend.KeyPathForIngesterSnapshot(tenantID, ingesterID))
+	if err != nil && !errors.Is(err, backend.ErrDoesNotExist) {
+		return fmt.Errorf("error deleting snapshot: %w", err)
+	}
+
+	rw.deleteWALSnapshotFiles(ctx, tenantID, ingesterID, snapshot.Files)
+
+	level.Info(rw.logger).Log("msg", "deleted wal snapshot", "tenantID", tenantID, "ingesterID", ingesterID)
+	return nil
+}
+
+// deleteWALSnapshotFiles deletes files that no snapshot refers to. Failures are only logged, the files are
+// never restored.
+func (rw *readerWriter) deleteWALSnapshotFiles(ctx context.Context, tenantID, ingesterID string, files []backend.IngesterSnapshotFile) {
+	for _, f := range files {
+		err := rw.w.DeleteIngesterSnapshotFile(ctx, tenantID, ingesterID, f.Path)
+		if err != nil && !errors.Is(err, backend.ErrDoesNotExist) {
+			level.Warn(rw.logger).Log("msg", "failed to delete file of wal snapshot", "tenantID", tenantID, "path", f.Path, "err", err)
+		}
+	}
+}
+
+// removedFiles returns the files of previous that are not in current.
+func removedFiles(previous, current []backend.IngesterSnapshotFile) []backend.IngesterSnapshotFile {
+	keep := make(map[string]struct{}, len(current))
+	for _, f := range current {
+		keep[f.Path] = struct{}{}
+	}
+
+	var removed []backend.IngesterSnapshotFile
+	for _, f := range previous {
+		if _, ok := keep[f.Path]; !ok {
+			removed = append(removed, f)
+		}
+	}
+	return removed
+}
+
+// RestoreWAL downloads the snapshots taken by the ingester into the WAL. Tenants that already have files in
+// the WAL are skipped. Restored snapshots are deleted, they are replayed from the WAL from now on. It must be
+// called before the WAL is replayed. Returns the restored snapshots by tenant.
+func (rw *readerWriter) RestoreWAL(ctx context.Context, ingesterID string) (map[string]*backend.IngesterSnapshot, error) {
+	tenants, err := rw.r.Tenants(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing tenants: %w", err)
+	}
+
+	restored := map[string]*backend.IngesterSnapshot{}
+	for _, tenantID := range tenants {
+		snapshot, err := rw.r.IngesterSnapshot(ctx, tenantID, ingesterID)
+		if errors.Is(err, backend.ErrDoesNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading snapshot of tenant %s: %w", tenantID, err)
+		}
+
+		existing, err := rw.wal.TenantFiles(tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("error listing wal files: %w", err)
+		}
+		if len(existing) > 0 {
+			level.Info(rw.logger).Log("msg", "skipping restore of wal snapshot. tenant has data in the wal", "tenantID", tenantID, "ingesterID", ingesterID)
+			continue
+		}
+
+		err = rw.restoreWALSnapshot(ctx, tenantID, ingesterID, snapshot)
+		if err != nil {
+			return nil, fmt.Errorf("error restoring snapshot of tenant %s: %w", tenantID, err)
+		}
+
+		level.Info(rw.logger).Log("msg", "restored wal snapshot", "tenantID", tenantID, "ingesterID", ingesterID, "files", len(snapshot.Files), "size", snapshot.Size(), "created", snapshot.CreatedTime)
+		restored[tenantID] = snapshot
+
+		err = rw.deleteWALSnapshot(ctx, tenantID, ingesterID, snapshot)
+		if err != nil {
+			level.Error(rw.logger).Log("msg", "failed to delete restored wal snapshot", "tenantID", tenantID, "ingesterID", ingesterID, "err", err)
+		}
+	}
+
+	return restored, nil
+}
+
+// restoreWALSnapshot downloads the files of the snapshot. Files downloaded so far are removed on error so the
+// WAL never contains a partial snapshot.
+func (rw *readerWriter) restoreWALSnapshot(ctx context.Context, tenantID, ingesterID string, snapshot *backend.IngesterSnapshot) (err error) {
+	var written []string
+	defer func() {
+		if err != nil {
+			for _, p := range written {
+				_ = os.Remove(p)
+			}
+		}
+	}()
+
+	for _, f := range snapshot.Files {
+		if !filepath.IsLocal(filepath.FromSlash(f.Path)) {
+			return fmt.Errorf("invalid path %s", f.Path)
+		}
+
+		p := filepath.Join(rw.wal.GetFilepath(), filepath.FromSlash(f.Path))
+		written = append(written, p)
+		if err = rw.downloadWALFile(ctx, tenantID, ingesterID, f.Path, p); err != nil {
+			return fmt.Errorf("error downloading wal file %s: %w", f.Path, err)
+		}
+	}
+
+	return nil
+}
+
+func (rw *readerWriter) downloadWALFile(ctx context.Context, tenantID, ingesterID, path, dst string) error {
+	r, _, err := rw.r.IngesterSnapshotFile(ctx, tenantID, ingesterID, path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	err = os.MkdirAll(filepath.Dir(dst), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
 
 	"github.com/grafana/tempo/pkg/boundedwaitgroup"
 	"github.com/grafana/tempo/tempodb/backend"
@@ -49,6 +50,16 @@ func (rw *readerWriter) retainTenant(ctx context.Context, tenantID string) {
 	start := time.Now()
 	defer func() { metricRetentionDuration.Observe(time.Since(start).Seconds()) }()
 
+	mark, err := rw.TenantDeletionMark(ctx, tenantID)
+	if err != nil {
+		level.Error(rw.logger).Log("msg", "failed to read tenant deletion mark", "tenantID", tenantID, "err", err)
+		metricRetentionErrors.Inc()
+	}
+	if mark != nil {
+		rw.purgeTenant(ctx, tenantID)
+		return
+	}
+
 	// Check for overrides
 	retention := rw.compactorCfg.BlockRetention // Default
 	if r := rw.compactorOverrides.BlockRetentionForTenant(tenantID); r != 0 {
@@ -94,8 +105,7 @@ func (rw *readerWriter) retainTenant(ctx context.Context, tenantID string) {
 		default:
 			level.Debug(rw.logger).Log("owns", rw.compactorSharder.Owns(b.BlockID.String()), "blockID", b.BlockID, "tenantID", tenantID)
 			if b.CompactedTime.Before(cutoff) && rw.compactorSharder.Owns(b.BlockID.String()) {
-				level.Info(rw.logger).Log("msg", "deleting block", "blockID", b.BlockID, "tenantID", tenantID)
-				err := rw.c.ClearBlock(b.BlockID, tenantID)
+				err := rw.clearCompactedBlock(b.BlockID, tenantID)
 				if err != nil {
 					level.Error(rw.logger).Log("msg", "failed to clear compacted block during retention", "blockID", b.BlockID, "tenantID", tenantID, "err", err)
 					metricRetentionErrors.Inc()
@@ -108,3 +118,63 @@ func (rw *readerWriter) retainTenant(ctx context.Context, tenantID string) {
 		}
 	}
 }
+
+// clearCompactedBlock deletes a compacted block or, if deletion tagging is enabled, tags its objects so a bucket
+// lifecycle rule purges them later
+func (rw *readerWriter) clearCompactedBlock(blockID uuid.UUID, tenantID string) error {
+	if rw.compactorCfg.DeletionTagging.Enabled {
+		if tagger, ok := rw.c.(backend.DeletionTagger); ok {
+			level.Info(rw.logger).Log("msg", "tagging block for deletion", "blockID", blockID, "tenantID", tenantID)
+			return tagger.TagBlockForDeletion(blockID, tenantID, rw.compactorCfg.DeletionTagging.Tags)
+		}
+	}
+
+	level.Info(rw.logger).Log("msg", "deleting block", "blockID", blockID, "tenantID", tenantID)
+	return rw.c.ClearBlock(blockID, tenantID)
+}
+
+// purgeTenant deletes all blocks owned by this compactor of a tenant that is marked for deletion. Blocks are
+// deleted right away, skipping the compacted block retention.
+func (rw *readerWriter) purgeTenant(ctx context.Context, tenantID string) {
+	level.Info(rw.logger).Log("msg", "purging tenant marked for deletion", "tenantID", tenantID)
+
+	for _, b := range rw.blocklist.Metas(tenantID) {
+		if ctx.Err() != nil {
+			return
+		}
+		if !rw.compactorSharder.Owns(b.BlockID.String()) {
+			continue
+		}
+
+		level.Info(rw.logger).Log("msg", "deleting block of deleted tenant", "blockID", b.BlockID, "tenantID", tenantID)
+		err := rw.c.ClearBlock(b.BlockID, tenantID)
+		if err != nil {
+			level.Error(rw.logger).Log("msg", "failed to clear block of deleted tenant", "blockID", b.BlockID, "tenantID", tenantID, "err", err)
+			metricRetentionErrors.Inc()
+			continue
+		}
+
+		metricDeleted.Inc()
+		rw.blocklist.Update(tenantID, nil, []*backend.BlockMeta{b}, nil, nil)
+	}
+
+	for _, b := range rw.blocklist.CompactedMetas(tenantID) {
+		if ctx.Err() != nil {
+			return
+		}
+		if !rw.compactorSharder.Owns(b.BlockID.String()) {
+			continue
+		}
+
+		level.Info(rw.logger).Log("msg", "deleting compacted block of deleted tenant", "blockID", b.BlockID, "tenantID", tenantID)
+		err := rw.c.ClearBlock(b.BlockID, tenantID)
+		if err != nil {
+			level.Error(rw.logger).Log("msg", "failed to clear compacted block of deleted tenant", "blockID", b.BlockID, "tenantID", tenantID, "err", err)
+			metricRetentionErrors.Inc()
+			continue
+		}
+
+		metricDeleted.Inc()
+		rw.blocklist.Update(tenantID, nil, nil, nil, []*backend.CompactedBlockMeta{b})
+	}
+}
@@ -217,3 +217,163 @@ func TestBlockRetentionOverride(t *testing.T) {
 	rw.pollBlocklist()
 	require.Equal(t, 0, len(rw.blocklist.Metas(testTenantID)))
 }
+
+func TestRetentionPurgesDeletedTenant(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 17,
+			BloomFP:              0.01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncLZ4_256k,
+			IndexPageSizeBytes:   1000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      time.Hour,
+		BlockRetention:          time.Hour,
+		CompactedBlockRetention: time.Hour,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+
+	cutTestBlocks(t, w, testTenantID, 5, 5)
+
+	rw := r.(*readerWriter)
+	rw.pollBlocklist()
+	require.Len(t, rw.blocklist.Metas(testTenantID), 5)
+
+	// mark a block compacted, it is deleted as well
+	compacted := rw.blocklist.Metas(testTenantID)[0]
+	require.NoError(t, rw.c.MarkBlockCompacted(compacted.BlockID, testTenantID))
+	rw.pollBlocklist()
+	require.Len(t, rw.blocklist.Metas(testTenantID), 4)
+	require.Len(t, rw.blocklist.CompactedMetas(testTenantID), 1)
+
+	// retention doesn't delete anything within the retention period
+	rw.doRetention(ctx)
+	require.Len(t, rw.blocklist.Metas(testTenantID), 4)
+
+	mark, err := rw.TenantDeletionMark(ctx, testTenantID)
+	require.NoError(t, err)
+	require.Nil(t, mark)
+
+	mark, err = w.MarkTenantDeleted(ctx, testTenantID)
+	require.NoError(t, err)
+	require.NotNil(t, mark)
+
+	// marking again returns the existing mark
+	again, err := w.MarkTenantDeleted(ctx, testTenantID)
+	require.NoError(t, err)
+	require.Equal(t, mark.RequestedTime.Unix(), again.RequestedTime.Unix())
+
+	// all blocks are deleted immediately once the tenant is marked
+	rw.doRetention(ctx)
+	require.Empty(t, rw.blocklist.Metas(testTenantID))
+	require.Empty(t, rw.blocklist.CompactedMetas(testTenantID))
+
+	blocks, compactedBlocks, err := rw.r.Blocks(ctx, testTenantID)
+	require.NoError(t, err)
+	require.Empty(t, blocks)
+	require.Empty(t, compactedBlocks)
+
+	// the mark is kept so late arriving blocks are purged too
+	mark, err = rw.TenantDeletionMark(ctx, testTenantID)
+	require.NoError(t, err)
+	require.NotNil(t, mark)
+}
+
+type mockDeletionTagger struct {
+	backend.Compactor
+	tagged map[uuid.UUID]map[string]string
+}
+
+func (m *mockDeletionTagger) TagBlockForDeletion(blockID uuid.UUID, _ string, tags map[string]string) error {
+	m.tagged[blockID] = tags
+	return nil
+}
+
+func (m *mockDeletionTagger) UndeleteBlock(uuid.UUID, string) error {
+	return nil
+}
+
+func TestRetentionTagsCompactedBlocks(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 17,
+			BloomFP:              0.01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncLZ4_256k,
+			IndexPageSizeBytes:   1000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	cfg := &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      time.Hour,
+		BlockRetention:          time.Hour,
+		CompactedBlockRetention: 0,
+		DeletionTagging: DeletionTaggingConfig{
+			Enabled: true,
+			Tags:    map[string]string{"tempo-deleted": "true"},
+		},
+	}
+
+	// the local backend doesn't support tagging
+	err = c.EnableCompaction(ctx, cfg, &mockSharder{}, &mockOverrides{})
+	require.EqualError(t, err, "deletion tagging is not supported by the configured backend")
+
+	rw := r.(*readerWriter)
+	tagger := &mockDeletionTagger{Compactor: rw.c, tagged: map[uuid.UUID]map[string]string{}}
+	rw.c = tagger
+
+	err = c.EnableCompaction(ctx, cfg, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+
+	cutTestBlocks(t, w, testTenantID, 2, 5)
+	rw.pollBlocklist()
+
+	compacted := rw.blocklist.Metas(testTenantID)[0]
+	require.NoError(t, rw.c.MarkBlockCompacted(compacted.BlockID, testTenantID))
+	rw.pollBlocklist()
+	require.Len(t, rw.blocklist.CompactedMetas(testTenantID), 1)
+
+	// the compacted block is tagged instead of deleted
+	rw.doRetention(ctx)
+	require.Empty(t, rw.blocklist.CompactedMetas(testTenantID))
+	require.Equal(t, map[uuid.UUID]map[string]string{compacted.BlockID: cfg.DeletionTagging.Tags}, tagger.tagged)
+
+	_, compactedBlocks, err := rw.r.Blocks(ctx, testTenantID)
+	require.NoError(t, err)
+	require.Len(t, compactedBlocks, 1)
+}
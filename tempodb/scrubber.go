@@ -0,0 +1,321 @@
+package tempodb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+const (
+	DefaultScrubberInterval       = 10 * time.Minute
+	DefaultScrubberBlocksPerCycle = 10
+
+	scrubResultOK        = "ok"
+	scrubResultCorrupted = "corrupted"
+	scrubResultError     = "error"
+	scrubResultSkipped   = "skipped"
+)
+
+var (
+	metricScrubberBlocksChecked = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "scrubber_blocks_checked_total",
+		Help:      "Total number of blocks verified by the scrubber by result.",
+	}, []string{"result"})
+	metricScrubberCorruptBlocks = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "scrubber_corrupted_blocks",
+		Help:      "Number of blocks in the blocklist the scrubber found to be corrupted.",
+	}, []string{"tenant"})
+)
+
+// ErrScrubberNotEnabled is returned by ScrubberStatus until scrubbing has been enabled.
+var ErrScrubberNotEnabled = errors.New("scrubber is not enabled")
+
+// errBlockGone is returned when a block left the backend before it could be verified.
+var errBlockGone = errors.New("block no longer exists")
+
+// ScrubberStatus lists the blocks the scrubber found to be corrupted. Corrupted blocks are listed until they
+// are removed from the blocklist.
+type ScrubberStatus struct {
+	LastCycle time.Time `json:"last_cycle"`
+	// VerifiedBlocks is the number of blocks verified since the scrubber last started over. Once all blocks
+	// owned by this compactor have been verified the scrubber starts over.
+	VerifiedBlocks int            `json:"verified_blocks"`
+	CorruptBlocks  []CorruptBlock `json:"corrupted_blocks"`
+}
+
+// CorruptBlock is a block that failed verification.
+type CorruptBlock struct {
+	TenantID   string    `json:"tenant_id"`
+	BlockID    string    `json:"block_id"`
+	Version    string    `json:"version"`
+	Reason     string    `json:"reason"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// EnableScrubbing starts verifying a sample of the blocks owned by the sharder every interval.
+func (rw *readerWriter) EnableScrubbing(ctx context.Context, cfg *ScrubberConfig, sharder CompactorSharder) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	rw.scrubberMtx.Lock()
+	rw.scrubberCfg = cfg
+	rw.scrubberSharder = sharder
+	rw.scrubbedBlocks = map[uuid.UUID]struct{}{}
+	rw.corruptBlocks = map[uuid.UUID]CorruptBlock{}
+	rw.scrubberMtx.Unlock()
+
+	if rw.cfg.BlocklistPoll == 0 {
+		level.Info(rw.logger).Log("msg", "polling cycle unset. scrubber disabled")
+		return nil
+	}
+
+	level.Info(rw.logger).Log("msg", "scrubber enabled.")
+	go rw.scrubberLoop(ctx)
+
+	return nil
+}
+
+// ScrubberStatus returns the corrupted blocks found by the scrubber. If tenantID is empty all tenants
+// are included.
+func (rw *readerWriter) ScrubberStatus(tenantID string) (*ScrubberStatus, error) {
+	rw.scrubberMtx.Lock()
+	defer rw.scrubberMtx.Unlock()
+
+	if rw.scrubberCfg == nil {
+		return nil, ErrScrubberNotEnabled
+	}
+
+	status := &ScrubberStatus{
+		LastCycle:      rw.scrubberLastCycle,
+		VerifiedBlocks: len(rw.scrubbedBlocks),
+		CorruptBlocks:  []CorruptBlock{},
+	}
+	for _, b := range rw.corruptBlocks {
+		if tenantID != "" && b.TenantID != tenantID {
+			continue
+		}
+		status.CorruptBlocks = append(status.CorruptBlocks, b)
+	}
+	sort.Slice(status.CorruptBlocks, func(i, j int) bool {
+		if status.CorruptBlocks[i].TenantID != status.CorruptBlocks[j].TenantID {
+			return status.CorruptBlocks[i].TenantID < status.CorruptBlocks[j].TenantID
+		}
+		return status.CorruptBlocks[i].BlockID < status.CorruptBlocks[j].BlockID
+	})
+
+	return status, nil
+}
+
+func (rw *readerWriter) scrubberLoop(ctx context.Context) {
+	ticker := time.NewTicker(rw.scrubberCfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			rw.doScrubbing(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// doScrubbing verifies a random sample of the owned blocks that have not been verified yet.
+func (rw *readerWriter) doScrubbing(ctx context.Context) {
+	for _, meta := range rw.blocksToScrub() {
+		if ctx.Err() != nil {
+			return
+		}
+		rw.scrubBlock(ctx, meta)
+	}
+
+	rw.scrubberMtx.Lock()
+	defer rw.scrubberMtx.Unlock()
+
+	rw.scrubberLastCycle = time.Now()
+
+	metricScrubberCorruptBlocks.Reset()
+	for _, b := range rw.corruptBlocks {
+		metricScrubberCorruptBlocks.WithLabelValues(b.TenantID).Inc()
+	}
+}
+
+// blocksToScrub picks the blocks to verify in this cycle. Blocks that left the blocklist are forgotten and
+// once every owned block has been verified the scrubber starts over.
+func (rw *readerWriter) blocksToScrub() []*backend.BlockMeta {
+	var owned []*backend.BlockMeta
+	live := map[uuid.UUID]struct{}{}
+	for _, tenantID := range rw.blocklist.Tenants() {
+		for _, m := range rw.blocklist.Metas(tenantID) {
+			live[m.BlockID] = struct{}{}
+			if rw.scrubberSharder.Owns(m.BlockID.String()) {
+				owned = append(owned, m)
+			}
+		}
+	}
+
+	rw.scrubberMtx.Lock()
+	defer rw.scrubberMtx.Unlock()
+
+	for id := range rw.scrubbedBlocks {
+		if _, ok := live[id]; !ok {
+			delete(rw.scrubbedBlocks, id)
+		}
+	}
+	for id := range rw.corruptBlocks {
+		if _, ok := live[id]; !ok {
+			delete(rw.corruptBlocks, id)
+		}
+	}
+
+	pending := rw.pendingScrubBlocks(owned)
+	if len(pending) == 0 && len(rw.scrubbedBlocks) > 0 {
+		rw.scrubbedBlocks = map[uuid.UUID]struct{}{}
+		pending = rw.pendingScrubBlocks(owned)
+	}
+
+	rand.Shuffle(len(pending), func(i, j int) { pending[i], pending[j] = pending[j], pending[i] })
+	if len(pending) > rw.scrubberCfg.BlocksPerCycle {
+		pending = pending[:rw.scrubberCfg.BlocksPerCycle]
+	}
+
+	return pending
+}
+
+// pendingScrubBlocks must be called with scrubberMtx held.
+func (rw *readerWriter) pendingScrubBlocks(metas []*backend.BlockMeta) []*backend.BlockMeta {
+	pending := make([]*backend.BlockMeta, 0, len(metas))
+	for _, m := range metas {
+		if _, ok := rw.scrubbedBlocks[m.BlockID]; ok {
+			continue
+		}
+		if _, ok := rw.corruptBlocks[m.BlockID]; ok {
+			continue
+		}
+		pending = append(pending, m)
+	}
+	return pending
+}
+
+func (rw *readerWriter) scrubBlock(ctx context.Context, meta *backend.BlockMeta) {
+	start := time.Now()
+	err := rw.verifyBlock(ctx, meta)
+
+	result := scrubResultOK
+	switch {
+	case err == nil:
+	case errors.Is(err, errBlockGone):
+		// compacted or deleted since the last poll
+		return
+	case errors.Is(err, common.ErrUnsupported):
+		result = scrubResultSkipped
+	case errors.Is(err, common.ErrCorrupted):
+		result = scrubResultCorrupted
+	default:
+		result = scrubResultError
+	}
+	metricScrubberBlocksChecked.WithLabelValues(result).Inc()
+
+	switch result {
+	case scrubResultError:
+		level.Error(rw.logger).Log("msg", "error verifying block", "tenantID", meta.TenantID, "blockID", meta.BlockID, "err", err)
+		return
+	case scrubResultCorrupted:
+		level.Warn(rw.logger).Log("msg", "corrupted block found", "tenantID", meta.TenantID, "blockID", meta.BlockID, "version", meta.Version, "err", err)
+	default:
+		level.Debug(rw.logger).Log("msg", "block verified", "tenantID", meta.TenantID, "blockID", meta.BlockID, "result", result, "duration", time.Since(start))
+	}
+
+	rw.scrubberMtx.Lock()
+	defer rw.scrubberMtx.Unlock()
+
+	if result == scrubResultCorrupted {
+		rw.corruptBlocks[meta.BlockID] = CorruptBlock{
+			TenantID:   meta.TenantID,
+			BlockID:    meta.BlockID.String(),
+			Version:    meta.Version,
+			Reason:     err.Error(),
+			DetectedAt: time.Now(),
+		}
+		return
+	}
+	rw.scrubbedBlocks[meta.BlockID] = struct{}{}
+}
+
+// verifyBlock checks that the meta.json in the backend agrees with the blocklist and has the encoding verify
+// the block contents.
+func (rw *readerWriter) verifyBlock(ctx context.Context, meta *backend.BlockMeta) error {
+	backendMeta, err := rw.r.BlockMeta(ctx, meta.BlockID, meta.TenantID)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		return errBlockGone
+	}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return fmt.Errorf("%w: error parsing meta: %w", common.ErrCorrupted, err)
+	}
+	if err != nil {
+		return fmt.Errorf("error reading meta: %w", err)
+	}
+
+	if err := compareScrubbedMetas(meta, backendMeta); err != nil {
+		return err
+	}
+
+	enc, err := encoding.FromVersion(backendMeta.Version)
+	if err != nil {
+		return fmt.Errorf("%w: %w", common.ErrCorrupted, err)
+	}
+
+	block, err := enc.OpenBlock(backendMeta, rw.r)
+	if err != nil {
+		return fmt.Errorf("error opening block: %w", err)
+	}
+
+	v, ok := block.(common.Verifier)
+	if !ok {
+		return fmt.Errorf("verifying %s blocks: %w", backendMeta.Version, common.ErrUnsupported)
+	}
+
+	return v.Verify(ctx)
+}
+
+// compareScrubbedMetas returns an error if the fields describing the block contents disagree between
+// the blocklist and the backend.
+func compareScrubbedMetas(blocklistMeta, backendMeta *backend.BlockMeta) error {
+	disagree := func(field string, blocklistVal, backendVal any) error {
+		return fmt.Errorf("%w: meta field %s is %v in the blocklist but %v in the backend", common.ErrCorrupted, field, blocklistVal, backendVal)
+	}
+
+	switch {
+	case blocklistMeta.BlockID != backendMeta.BlockID:
+		return disagree("blockID", blocklistMeta.BlockID, backendMeta.BlockID)
+	case blocklistMeta.TenantID != backendMeta.TenantID:
+		return disagree("tenantID", blocklistMeta.TenantID, backendMeta.TenantID)
+	case blocklistMeta.Version != backendMeta.Version:
+		return disagree("format", blocklistMeta.Version, backendMeta.Version)
+	case blocklistMeta.Size != backendMeta.Size:
+		return disagree("size", blocklistMeta.Size, backendMeta.Size)
+	case blocklistMeta.TotalObjects != backendMeta.TotalObjects:
+		return disagree("totalObjects", blocklistMeta.TotalObjects, backendMeta.TotalObjects)
+	case blocklistMeta.BloomShardCount != backendMeta.BloomShardCount:
+		return disagree("bloomShards", blocklistMeta.BloomShardCount, backendMeta.BloomShardCount)
+	}
+
+	return nil
+}
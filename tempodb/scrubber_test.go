@@ -0,0 +1,116 @@
+package tempodb
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/pool"
+	"github.com/grafana/tempo/tempodb/wal"
+)
+
+func TestScrubber(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Pool: &pool.Config{
+			MaxWorkers: 10,
+			QueueDepth: 100,
+		},
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 11,
+			BloomFP:              .01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncLZ4_64k,
+			IndexPageSizeBytes:   1000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	_, err = c.ScrubberStatus("")
+	require.ErrorIs(t, err, ErrScrubberNotEnabled)
+
+	ctx := context.Background()
+	require.Error(t, c.EnableScrubbing(ctx, &ScrubberConfig{}, &mockSharder{}))
+	require.NoError(t, c.EnableScrubbing(ctx, &ScrubberConfig{
+		Interval:       DefaultScrubberInterval,
+		BlocksPerCycle: 2,
+	}, &mockSharder{}))
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+
+	cutTestBlocks(t, w, testTenantID, 2, 2)
+	cutTestBlocks(t, w, testTenantID2, 1, 2)
+
+	rw := r.(*readerWriter)
+	rw.pollBlocklist()
+
+	// blocks are verified a few at a time
+	rw.doScrubbing(ctx)
+	status, err := c.ScrubberStatus("")
+	require.NoError(t, err)
+	require.Equal(t, 2, status.VerifiedBlocks)
+	require.Empty(t, status.CorruptBlocks)
+	require.False(t, status.LastCycle.IsZero())
+
+	rw.doScrubbing(ctx)
+	status, err = c.ScrubberStatus("")
+	require.NoError(t, err)
+	require.Equal(t, 3, status.VerifiedBlocks)
+	require.Empty(t, status.CorruptBlocks)
+
+	// corrupt a block, it is found once the scrubber starts over
+	corrupted := rw.blocklist.Metas(testTenantID2)[0]
+	err = os.Remove(path.Join(tempDir, "traces", testTenantID2, corrupted.BlockID.String(), common.BloomName(0)))
+	require.NoError(t, err)
+
+	rw.doScrubbing(ctx)
+	rw.doScrubbing(ctx)
+
+	status, err = c.ScrubberStatus("")
+	require.NoError(t, err)
+	require.Len(t, status.CorruptBlocks, 1)
+	require.Equal(t, corrupted.BlockID.String(), status.CorruptBlocks[0].BlockID)
+	require.Equal(t, testTenantID2, status.CorruptBlocks[0].TenantID)
+	require.Contains(t, status.CorruptBlocks[0].Reason, common.BloomName(0))
+
+	status, err = c.ScrubberStatus(testTenantID)
+	require.NoError(t, err)
+	require.Empty(t, status.CorruptBlocks)
+
+	// corrupted blocks are forgotten once they leave the blocklist
+	rw.blocklist.Update(testTenantID2, nil, []*backend.BlockMeta{corrupted}, nil, nil)
+	rw.doScrubbing(ctx)
+
+	status, err = c.ScrubberStatus("")
+	require.NoError(t, err)
+	require.Empty(t, status.CorruptBlocks)
+}
+
+func TestCompareScrubbedMetas(t *testing.T) {
+	meta := &backend.BlockMeta{TenantID: testTenantID, Version: "vParquet4", Size: 100, TotalObjects: 10, BloomShardCount: 1}
+	other := *meta
+	require.NoError(t, compareScrubbedMetas(meta, &other))
+
+	other.Size = 101
+	err := compareScrubbedMetas(meta, &other)
+	require.ErrorIs(t, err, common.ErrCorrupted)
+	require.Contains(t, err.Error(), "size")
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/grafana/tempo/pkg/collector"
@@ -26,8 +27,11 @@ import (
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/backend/azure"
 	backend_cache "github.com/grafana/tempo/tempodb/backend/cache"
+	"github.com/grafana/tempo/tempodb/backend/cos"
 	"github.com/grafana/tempo/tempodb/backend/gcs"
+	"github.com/grafana/tempo/tempodb/backend/instrumentation"
 	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/backend/oss"
 	"github.com/grafana/tempo/tempodb/backend/s3"
 	"github.com/grafana/tempo/tempodb/blocklist"
 	"github.com/grafana/tempo/tempodb/encoding"
@@ -65,12 +69,27 @@ var (
 		Name:      "retention_deleted_total",
 		Help:      "Total number of blocks deleted.",
 	})
+	metricBlocklistLastSuccessfulPoll = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "blocklist_last_successful_poll_timestamp_seconds",
+		Help:      "Unix timestamp of the last successful poll of the blocklist.",
+	})
 )
 
 type Writer interface {
 	WriteBlock(ctx context.Context, block WriteableBlock) error
 	CompleteBlock(ctx context.Context, block common.WALBlock) (common.BackendBlock, error)
 	CompleteBlockWithBackend(ctx context.Context, block common.WALBlock, r backend.Reader, w backend.Writer) (common.BackendBlock, error)
+	// MarkTenantDeleted marks all data of the tenant for deletion. It returns the existing mark if the tenant was already marked.
+	MarkTenantDeleted(ctx context.Context, tenantID string) (*backend.TenantDeletionMark, error)
+	// UnmarkTenantDeleted removes the deletion mark of the tenant. Data that was already purged is not restored.
+	UnmarkTenantDeleted(ctx context.Context, tenantID string) error
+	// SnapshotWAL uploads a copy of the WAL files of the tenant to the backend as a snapshot taken by the ingester.
+	SnapshotWAL(ctx context.Context, tenantID, ingesterID string, c *wal.TenantFilesCopy) (*backend.IngesterSnapshot, error)
+	// PruneWALSnapshot removes a flushed block from the snapshot taken by the ingester. Returns false if there is no snapshot left.
+	PruneWALSnapshot(ctx context.Context, tenantID, ingesterID string, blockID uuid.UUID) (bool, error)
+	// RestoreWAL downloads the snapshots taken by the ingester into the WAL of tenants without WAL files and deletes them.
+	RestoreWAL(ctx context.Context, ingesterID string) (map[string]*backend.IngesterSnapshot, error)
 	WAL() *wal.WAL
 }
 
@@ -82,18 +101,40 @@ type Reader interface {
 	SearchTags(ctx context.Context, meta *backend.BlockMeta, scope string, opts common.SearchOptions) (*tempopb.SearchTagsV2Response, error)
 	SearchTagValues(ctx context.Context, meta *backend.BlockMeta, tag string, opts common.SearchOptions) ([]string, error)
 	SearchTagValuesV2(ctx context.Context, meta *backend.BlockMeta, req *tempopb.SearchTagValuesRequest, opts common.SearchOptions) (*tempopb.SearchTagValuesV2Response, error)
+	// TagStats collects the values of the tags of the block into stats. Blocks that don't support it return an error
+	// wrapping common.ErrUnsupported.
+	TagStats(ctx context.Context, meta *backend.BlockMeta, scope string, stats *collector.TagStats, opts common.SearchOptions) error
 
 	Fetch(ctx context.Context, meta *backend.BlockMeta, req traceql.FetchSpansRequest, opts common.SearchOptions) (traceql.FetchSpansResponse, error)
 	FetchTagValues(ctx context.Context, meta *backend.BlockMeta, req traceql.FetchTagValuesRequest, cb traceql.FetchTagValuesCallback, opts common.SearchOptions) error
 
+	// Tenants returns the tenants that have blocks in the blocklist.
+	Tenants() []string
 	BlockMetas(tenantID string) []*backend.BlockMeta
+	// ColdBlockMetas returns the blocks of the cold backend if a query starting at start precedes the retention of
+	// the primary backend. It returns nil if no cold backend is configured.
+	ColdBlockMetas(tenantID string, start time.Time) []*backend.BlockMeta
+	CompactedBlockMetas(tenantID string) []*backend.CompactedBlockMeta
+	// RecentlyCompactedBlockMetas returns the compacted blocks that are still included in trace by id lookups because
+	// they were compacted within the last two blocklist polls.
+	RecentlyCompactedBlockMetas(tenantID string) []*backend.CompactedBlockMeta
+	// TenantDeletionMark returns the deletion mark of the tenant or nil if the tenant is not marked for deletion.
+	TenantDeletionMark(ctx context.Context, tenantID string) (*backend.TenantDeletionMark, error)
 	EnablePolling(ctx context.Context, sharder blocklist.JobSharder)
+	// LastBlocklistPoll returns the time of the last successful poll of the blocklist. It is zero until the first
+	// poll succeeded or if polling is not enabled.
+	LastBlocklistPoll() time.Time
 
 	Shutdown()
 }
 
 type Compactor interface {
 	EnableCompaction(ctx context.Context, cfg *CompactorConfig, sharder CompactorSharder, overrides CompactorOverrides) error
+	CompactionPlan(tenantID string) (*CompactionPlan, error)
+	ConversionStatus(tenantID string) (*ConversionStatus, error)
+	EnableScrubbing(ctx context.Context, cfg *ScrubberConfig, sharder CompactorSharder) error
+	ScrubberStatus(tenantID string) (*ScrubberStatus, error)
+	EnableDedicatedColumnsRecommendations(ctx context.Context, cfg *DedicatedColumnsConfig, sharder CompactorSharder, overrides DedicatedColumnsOverrides) error
 }
 
 type CompactorSharder interface {
@@ -106,6 +147,8 @@ type CompactorOverrides interface {
 	BlockRetentionForTenant(tenantID string) time.Duration
 	MaxBytesPerTraceForTenant(tenantID string) int
 	MaxCompactionRangeForTenant(tenantID string) time.Duration
+	MaxCompactionBytesPerCycleForTenant(tenantID string) uint64
+	MaxCompactionJobsPerCycleForTenant(tenantID string) int
 }
 
 type WriteableBlock interface {
@@ -129,10 +172,26 @@ type readerWriter struct {
 	blocklistPoller *blocklist.Poller
 	blocklist       *blocklist.List
 
+	lastPollMtx sync.Mutex
+	lastPoll    time.Time
+
+	// read-only backend with archived blocks, nil if not configured
+	cold *coldBackend
+
 	compactorCfg          *CompactorConfig
 	compactorSharder      CompactorSharder
 	compactorOverrides    CompactorOverrides
 	compactorTenantOffset uint
+
+	compactionJobsMtx sync.Mutex
+	compactionJobs    map[string]CompactionJob
+
+	scrubberCfg       *ScrubberConfig
+	scrubberSharder   CompactorSharder
+	scrubberMtx       sync.Mutex
+	scrubberLastCycle time.Time
+	scrubbedBlocks    map[uuid.UUID]struct{}
+	corruptBlocks     map[uuid.UUID]CorruptBlock
 }
 
 // New creates a new tempodb
@@ -155,6 +214,10 @@ func New(cfg *Config, cacheProvider cache.Provider, logger gkLog.Logger) (Reader
 		rawR, rawW, c, err = s3.New(cfg.S3)
 	case backend.Azure:
 		rawR, rawW, c, err = azure.New(cfg.Azure)
+	case backend.OSS:
+		rawR, rawW, c, err = oss.New(cfg.OSS)
+	case backend.COS:
+		rawR, rawW, c, err = cos.New(cfg.COS)
 	default:
 		err = fmt.Errorf("unknown backend %s", cfg.Backend)
 	}
@@ -163,6 +226,9 @@ func New(cfg *Config, cacheProvider cache.Provider, logger gkLog.Logger) (Reader
 		return nil, nil, nil, err
 	}
 
+	// requests are instrumented below the cache to only attribute the requests that reach the backend
+	rawR, rawW = instrumentation.NewTenantRawReaderWriter(cfg.Backend, rawR, rawW)
+
 	// build a caching layer if we have a provider
 	if cacheProvider != nil {
 		legacyCache, roles, err := createLegacyCache(cfg, logger)
@@ -201,6 +267,13 @@ func New(cfg *Config, cacheProvider cache.Provider, logger gkLog.Logger) (Reader
 		return nil, nil, nil, err
 	}
 
+	if cfg.ColdBackend != nil {
+		rw.cold, err = newColdBackend(cfg.ColdBackend, logger)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
 	return rw, rw, rw, nil
 }
 
@@ -267,10 +340,78 @@ func (rw *readerWriter) WAL() *wal.WAL {
 	return rw.wal
 }
 
+func (rw *readerWriter) Tenants() []string {
+	return rw.blocklist.Tenants()
+}
+
 func (rw *readerWriter) BlockMetas(tenantID string) []*backend.BlockMeta {
 	return rw.blocklist.Metas(tenantID)
 }
 
+func (rw *readerWriter) ColdBlockMetas(tenantID string, start time.Time) []*backend.BlockMeta {
+	if rw.cold == nil || !rw.cold.includes(start) {
+		return nil
+	}
+	return rw.cold.blockMetas(tenantID)
+}
+
+// readerFor returns the reader of the backend the block is stored in
+func (rw *readerWriter) readerFor(meta *backend.BlockMeta) backend.Reader {
+	if rw.cold != nil && rw.cold.contains(meta.TenantID, meta.BlockID) {
+		return rw.cold.r
+	}
+	return rw.r
+}
+
+func (rw *readerWriter) CompactedBlockMetas(tenantID string) []*backend.CompactedBlockMeta {
+	return rw.blocklist.CompactedMetas(tenantID)
+}
+
+func (rw *readerWriter) RecentlyCompactedBlockMetas(tenantID string) []*backend.CompactedBlockMeta {
+	compacted := rw.blocklist.CompactedMetas(tenantID)
+	recent := make([]*backend.CompactedBlockMeta, 0, len(compacted))
+	for _, c := range compacted {
+		if compactedWithinLookback(c, rw.cfg.BlocklistPoll) {
+			recent = append(recent, c)
+		}
+	}
+	return recent
+}
+
+func (rw *readerWriter) TenantDeletionMark(ctx context.Context, tenantID string) (*backend.TenantDeletionMark, error) {
+	mark, err := rw.r.TenantDeletionMark(ctx, tenantID)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		return nil, nil
+	}
+	return mark, err
+}
+
+func (rw *readerWriter) MarkTenantDeleted(ctx context.Context, tenantID string) (*backend.TenantDeletionMark, error) {
+	mark, err := rw.TenantDeletionMark(ctx, tenantID)
+	if err != nil || mark != nil {
+		return mark, err
+	}
+
+	mark = backend.NewTenantDeletionMark()
+	err = rw.w.WriteTenantDeletionMark(ctx, tenantID, mark)
+	if err != nil {
+		return nil, err
+	}
+
+	level.Info(rw.logger).Log("msg", "marked tenant for deletion", "tenantID", tenantID)
+	return mark, nil
+}
+
+func (rw *readerWriter) UnmarkTenantDeleted(ctx context.Context, tenantID string) error {
+	err := rw.w.Delete(ctx, backend.TenantDeletionMarkName, backend.KeyPath{tenantID})
+	if err != nil && !errors.Is(err, backend.ErrDoesNotExist) {
+		return err
+	}
+
+	level.Info(rw.logger).Log("msg", "removed tenant deletion mark", "tenantID", tenantID)
+	return nil
+}
+
 func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64, opts common.SearchOptions) ([]*tempopb.Trace, []error, error) {
 	// tracing instrumentation
 	logger := log.WithContext(ctx, log.Logger)
@@ -313,6 +454,16 @@ func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID,
 			compactedBlocksSearched++
 		}
 	}
+	// the cold backend is only searched if the time range precedes the retention of the primary backend
+	coldBlocksSearched := 0
+	if timeStart != 0 {
+		for _, b := range rw.ColdBlockMetas(tenantID, time.Unix(timeStart, 0)) {
+			if includeBlock(b, id, blockStartBytes, blockEndBytes, timeStart, timeEnd, opts.BlockReplicationFactor) {
+				copiedBlocklist = append(copiedBlocklist, b)
+				coldBlocksSearched++
+			}
+		}
+	}
 	if len(copiedBlocklist) == 0 {
 		return nil, nil, nil
 	}
@@ -323,7 +474,7 @@ func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID,
 
 	partialTraces, funcErrs, err := rw.pool.RunJobs(ctx, copiedBlocklist, func(ctx context.Context, payload interface{}) (interface{}, error) {
 		meta := payload.(*backend.BlockMeta)
-		block, err := encoding.OpenBlock(meta, rw.r)
+		block, err := encoding.OpenBlock(meta, rw.readerFor(meta))
 		if err != nil {
 			return nil, fmt.Errorf("error opening block for reading, blockID: %s: %w", meta.BlockID.String(), err)
 		}
@@ -347,6 +498,7 @@ func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID,
 	span.SetTag("liveBlocksSearched", blocksSearched)
 	span.SetTag("compactedBlocks", len(compactedBlocklist))
 	span.SetTag("compactedBlocksSearched", compactedBlocksSearched)
+	span.SetTag("coldBlocksSearched", coldBlocksSearched)
 
 	return partialTraceObjs, funcErrs, err
 }
@@ -354,7 +506,7 @@ func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID,
 // Search the given block.  This method takes the pre-loaded block meta instead of a block ID, which
 // eliminates a read per search request.
 func (rw *readerWriter) Search(ctx context.Context, meta *backend.BlockMeta, req *tempopb.SearchRequest, opts common.SearchOptions) (*tempopb.SearchResponse, error) {
-	block, err := encoding.OpenBlock(meta, rw.r)
+	block, err := encoding.OpenBlock(meta, rw.readerFor(meta))
 	if err != nil {
 		return nil, err
 	}
@@ -370,7 +522,7 @@ func (rw *readerWriter) SearchTags(ctx context.Context, meta *backend.BlockMeta,
 		return nil, fmt.Errorf("unknown scope: %s", scope)
 	}
 
-	block, err := encoding.OpenBlock(meta, rw.r)
+	block, err := encoding.OpenBlock(meta, rw.readerFor(meta))
 	if err != nil {
 		return nil, err
 	}
@@ -400,8 +552,31 @@ func (rw *readerWriter) SearchTags(ctx context.Context, meta *backend.BlockMeta,
 	return resp, nil
 }
 
+func (rw *readerWriter) TagStats(ctx context.Context, meta *backend.BlockMeta, scope string, stats *collector.TagStats, opts common.SearchOptions) error {
+	attributeScope := traceql.AttributeScopeFromString(scope)
+
+	if attributeScope == traceql.AttributeScopeUnknown {
+		return fmt.Errorf("unknown scope: %s", scope)
+	}
+
+	block, err := encoding.OpenBlock(meta, rw.readerFor(meta))
+	if err != nil {
+		return err
+	}
+
+	statser, ok := block.(common.TagStatser)
+	if !ok {
+		return fmt.Errorf("tag stats of %s blocks: %w", meta.Version, common.ErrUnsupported)
+	}
+
+	rw.cfg.Search.ApplyToOptions(&opts)
+	return statser.TagStats(ctx, attributeScope, func(scope traceql.AttributeScope, tag string, v traceql.Static) {
+		stats.Collect(scope.String(), tag, v.EncodeToString(false))
+	}, opts)
+}
+
 func (rw *readerWriter) SearchTagValues(ctx context.Context, meta *backend.BlockMeta, tag string, opts common.SearchOptions) ([]string, error) {
-	block, err := encoding.OpenBlock(meta, rw.r)
+	block, err := encoding.OpenBlock(meta, rw.readerFor(meta))
 	if err != nil {
 		return nil, err
 	}
@@ -414,7 +589,7 @@ func (rw *readerWriter) SearchTagValues(ctx context.Context, meta *backend.Block
 }
 
 func (rw *readerWriter) SearchTagValuesV2(ctx context.Context, meta *backend.BlockMeta, req *tempopb.SearchTagValuesRequest, opts common.SearchOptions) (*tempopb.SearchTagValuesV2Response, error) {
-	block, err := encoding.OpenBlock(meta, rw.r)
+	block, err := encoding.OpenBlock(meta, rw.readerFor(meta))
 	if err != nil {
 		return nil, err
 	}
@@ -442,7 +617,7 @@ func (rw *readerWriter) SearchTagValuesV2(ctx context.Context, meta *backend.Blo
 
 // Fetch only uses rw.r which has caching enabled
 func (rw *readerWriter) Fetch(ctx context.Context, meta *backend.BlockMeta, req traceql.FetchSpansRequest, opts common.SearchOptions) (traceql.FetchSpansResponse, error) {
-	block, err := encoding.OpenBlock(meta, rw.r)
+	block, err := encoding.OpenBlock(meta, rw.readerFor(meta))
 	if err != nil {
 		return traceql.FetchSpansResponse{}, err
 	}
@@ -452,7 +627,7 @@ func (rw *readerWriter) Fetch(ctx context.Context, meta *backend.BlockMeta, req
 }
 
 func (rw *readerWriter) FetchTagValues(ctx context.Context, meta *backend.BlockMeta, req traceql.FetchTagValuesRequest, cb traceql.FetchTagValuesCallback, opts common.SearchOptions) error {
-	block, err := encoding.OpenBlock(meta, rw.r)
+	block, err := encoding.OpenBlock(meta, rw.readerFor(meta))
 	if err != nil {
 		return err
 	}
@@ -465,6 +640,9 @@ func (rw *readerWriter) Shutdown() {
 	// todo: stop blocklist poll
 	rw.pool.Shutdown()
 	rw.r.Shutdown()
+	if rw.cold != nil {
+		rw.cold.r.Shutdown()
+	}
 }
 
 // EnableCompaction activates the compaction/retention loops
@@ -475,6 +653,12 @@ func (rw *readerWriter) EnableCompaction(ctx context.Context, cfg *CompactorConf
 		return err
 	}
 
+	if cfg.DeletionTagging.Enabled {
+		if _, ok := rw.c.(backend.DeletionTagger); !ok {
+			return errors.New("deletion tagging is not supported by the configured backend")
+		}
+	}
+
 	// Set default if needed. This is mainly for tests.
 	if cfg.RetentionConcurrency == 0 {
 		cfg.RetentionConcurrency = DefaultRetentionConcurrency
@@ -546,6 +730,12 @@ func (rw *readerWriter) EnablePolling(ctx context.Context, sharder blocklist.Job
 
 func (rw *readerWriter) pollingLoop(ctx context.Context) {
 	ticker := time.NewTicker(rw.cfg.BlocklistPoll)
+	defer ticker.Stop()
+
+	// until a poll succeeded, retry sooner than the poll interval. components waiting for the blocklist
+	// to become ready shouldn't be held back for a full interval by a single failure
+	retry := time.NewTicker(min(rw.cfg.BlocklistPoll, firstPollRetryInterval))
+	defer retry.Stop()
 
 	for {
 		select {
@@ -553,6 +743,12 @@ func (rw *readerWriter) pollingLoop(ctx context.Context) {
 			return
 		case <-ticker.C:
 			rw.pollBlocklist()
+		case <-retry.C:
+			if !rw.LastBlocklistPoll().IsZero() {
+				retry.Stop()
+				continue
+			}
+			rw.pollBlocklist()
 		}
 	}
 }
@@ -565,6 +761,23 @@ func (rw *readerWriter) pollBlocklist() {
 	}
 
 	rw.blocklist.ApplyPollResults(blocklist, compactedBlocklist)
+
+	if rw.cold != nil {
+		rw.cold.poll(context.Background(), rw.blocklist)
+	}
+
+	now := time.Now()
+	rw.lastPollMtx.Lock()
+	rw.lastPoll = now
+	rw.lastPollMtx.Unlock()
+	metricBlocklistLastSuccessfulPoll.Set(float64(now.Unix()))
+}
+
+func (rw *readerWriter) LastBlocklistPoll() time.Time {
+	rw.lastPollMtx.Lock()
+	defer rw.lastPollMtx.Unlock()
+
+	return rw.lastPoll
 }
 
 // includeBlock indicates whether a given block should be included in a backend search
@@ -594,13 +807,19 @@ func includeBlock(b *backend.BlockMeta, _ common.ID, blockStart, blockEnd []byte
 
 // if block is compacted within lookback period, and is within shard ranges, include it in search
 func includeCompactedBlock(c *backend.CompactedBlockMeta, id common.ID, blockStart, blockEnd []byte, poll time.Duration, timeStart, timeEnd int64, replicationFactor int) bool {
-	lookback := time.Now().Add(-(2 * poll))
-	if c.CompactedTime.Before(lookback) {
+	if !compactedWithinLookback(c, poll) {
 		return false
 	}
 	return includeBlock(&c.BlockMeta, id, blockStart, blockEnd, timeStart, timeEnd, replicationFactor)
 }
 
+// compactedWithinLookback returns true if the block was compacted within the last two polls. queriers might not
+// have polled the blocks the block was compacted into yet and keep searching it until then.
+func compactedWithinLookback(c *backend.CompactedBlockMeta, poll time.Duration) bool {
+	lookback := time.Now().Add(-(2 * poll))
+	return !c.CompactedTime.Before(lookback)
+}
+
 // createLegacyCache uses the config to return a cache and a list of roles.
 func createLegacyCache(cfg *Config, logger gkLog.Logger) (cache.Cache, []cache.Role, error) {
 	var legacyCache cache.Cache
@@ -191,6 +191,16 @@ func TestNilOnUnknownTenantID(t *testing.T) {
 	assert.Nil(t, failedBlocks)
 }
 
+func TestLastBlocklistPoll(t *testing.T) {
+	r, _, _, _ := testConfig(t, backend.EncLZ4_256k, time.Minute)
+	require.True(t, r.LastBlocklistPoll().IsZero())
+
+	// the first poll is done synchronously by EnablePolling
+	start := time.Now()
+	r.EnablePolling(context.Background(), &mockJobSharder{})
+	require.False(t, r.LastBlocklistPoll().Before(start))
+}
+
 func TestBlockCleanup(t *testing.T) {
 	r, w, c, tempDir := testConfig(t, backend.EncLZ4_256k, 0)
 
@@ -1,13 +1,23 @@
 package wal
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/encoding"
@@ -17,6 +27,30 @@ import (
 const (
 	completedDir = "completed"
 	blocksDir    = "blocks"
+	snapshotsDir = "snapshots"
+)
+
+var (
+	metricReplayPartialBlocks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "wal_replay_partial_blocks_total",
+		Help:      "The total number of wal blocks that were replayed partially because parts of them are corrupted.",
+	}, []string{"tenant"})
+	metricReplaySkippedBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "wal_replay_skipped_bytes_total",
+		Help:      "The total number of corrupted bytes skipped while replaying wal blocks.",
+	}, []string{"tenant"})
+	metricReplayAffectedTraces = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "wal_replay_affected_traces_total",
+		Help:      "The total number of traces with data in corrupted parts of wal blocks, if known.",
+	}, []string{"tenant"})
+	metricReplayLostSpans = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "wal_replay_lost_spans_total",
+		Help:      "The total number of spans in corrupted parts of wal blocks, if known.",
+	}, []string{"tenant"})
 )
 
 type WAL struct {
@@ -66,6 +100,13 @@ func New(c *Config) (*WAL, error) {
 		c.CompletedFilepath = completedFilepath
 	}
 
+	// Copies of the files of tenants are only used while snapshots are uploaded, clear what is left
+	// of a previous run.
+	err = os.RemoveAll(filepath.Join(c.Filepath, snapshotsDir))
+	if err != nil {
+		return nil, err
+	}
+
 	// Setup local backend in /blocks/
 	p := filepath.Join(c.Filepath, blocksDir)
 	err = os.MkdirAll(p, os.ModePerm)
@@ -132,7 +173,10 @@ func (w *WAL) RescanBlocks(additionalStartSlack time.Duration, log log.Logger) (
 			remove = true
 		}
 
-		if warning != nil {
+		var report *common.WALReplayReport
+		if errors.As(warning, &report) && b != nil {
+			logReplayReport(log, f.Name(), b.BlockMeta().TenantID, report)
+		} else if warning != nil {
 			level.Warn(log).Log("msg", "received warning while replaying block. partial replay likely.", "file", f.Name(), "warning", warning, "length", b.DataLength())
 		}
 
@@ -152,6 +196,21 @@ func (w *WAL) RescanBlocks(additionalStartSlack time.Duration, log log.Logger) (
 	return blocks, nil
 }
 
+// logReplayReport logs the salvage report of a partially replayed block and records it in the metrics.
+func logReplayReport(log log.Logger, file, tenantID string, report *common.WALReplayReport) {
+	for _, s := range report.Skipped {
+		level.Warn(log).Log("msg", "skipped corrupted range of wal block", "file", file, "path", s.File, "start", s.Start, "end", s.End,
+			"traces_affected", s.TracesAffected, "spans_lost", s.SpansLost, "err", s.Err)
+	}
+	level.Warn(log).Log("msg", "wal block replayed partially", "file", file, "tenant", tenantID, "skipped_ranges", len(report.Skipped),
+		"bytes_lost", report.BytesLost(), "traces_affected", report.TracesAffected(), "spans_lost", report.SpansLost())
+
+	metricReplayPartialBlocks.WithLabelValues(tenantID).Inc()
+	metricReplaySkippedBytes.WithLabelValues(tenantID).Add(float64(report.BytesLost()))
+	metricReplayAffectedTraces.WithLabelValues(tenantID).Add(float64(report.TracesAffected()))
+	metricReplayLostSpans.WithLabelValues(tenantID).Add(float64(report.SpansLost()))
+}
+
 func (w *WAL) NewBlock(meta *backend.BlockMeta, dataEncoding string) (common.WALBlock, error) {
 	v, err := encoding.FromVersion(w.c.Version)
 	if err != nil {
@@ -172,3 +231,161 @@ func (w *WAL) ClearFolder(dir string) error {
 func (w *WAL) LocalBackend() *local.Backend {
 	return w.l
 }
+
+// TenantFiles returns the paths of the files of all WAL blocks and local blocks of a tenant. The paths are
+// relative to the WAL folder and use forward slashes.
+func (w *WAL) TenantFiles(tenantID string) ([]string, error) {
+	entries, err := os.ReadDir(w.c.Filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, e := range entries {
+		if tenantFromWALEntry(e.Name()) != tenantID {
+			continue
+		}
+
+		files, err = w.appendFiles(files, e.Name())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	files, err = w.appendFiles(files, path.Join(blocksDir, tenantID))
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// appendFiles appends the paths of all files in the folder or the path of the file at p.
+func (w *WAL) appendFiles(files []string, p string) ([]string, error) {
+	root := filepath.Join(w.c.Filepath, filepath.FromSlash(p))
+	err := filepath.WalkDir(root, func(fp string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// blocks may be cleared while walking
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(w.c.Filepath, fp)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	return files, err
+}
+
+// TenantFilesCopy is a copy of the files of a tenant. Paths are relative to Dir and use forward slashes.
+type TenantFilesCopy struct {
+	Dir   string
+	Files []string
+}
+
+// Remove deletes the copy.
+func (c *TenantFilesCopy) Remove() error {
+	return os.RemoveAll(c.Dir)
+}
+
+// CopyTenantFiles copies the files of all WAL blocks and local blocks of a tenant into a new folder, so they
+// can be read while the WAL keeps changing. Files of the blocks for which skip returns true are not copied. The
+// caller must make sure the files are not modified while they are copied, and remove the copy once done.
+func (w *WAL) CopyTenantFiles(tenantID string, skip func(blockID uuid.UUID) bool) (*TenantFilesCopy, error) {
+	files, err := w.TenantFiles(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	files = slices.DeleteFunc(files, func(p string) bool {
+		blockID, ok := BlockIDFromPath(p)
+		return ok && skip(blockID)
+	})
+
+	root := filepath.Join(w.c.Filepath, snapshotsDir)
+	err = os.MkdirAll(root, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp(root, "")
+	if err != nil {
+		return nil, err
+	}
+
+	c := &TenantFilesCopy{
+		Dir:   dir,
+		Files: make([]string, 0, len(files)),
+	}
+	for _, p := range files {
+		err = copyFile(filepath.Join(w.c.Filepath, filepath.FromSlash(p)), filepath.Join(dir, filepath.FromSlash(p)))
+		if errors.Is(err, fs.ErrNotExist) {
+			// the file was removed after it was listed
+			continue
+		}
+		if err != nil {
+			_ = c.Remove()
+			return nil, fmt.Errorf("error copying %s: %w", p, err)
+		}
+		c.Files = append(c.Files, p)
+	}
+
+	return c, nil
+}
+
+// BlockIDFromPath returns the ID of the WAL block or local block of a path returned by TenantFiles.
+func BlockIDFromPath(p string) (uuid.UUID, bool) {
+	segments := strings.Split(p, "/")
+	id := strings.Split(segments[0], "+")[0]
+	if segments[0] == blocksDir && len(segments) > 2 {
+		id = segments[2]
+	}
+
+	blockID, err := uuid.Parse(id)
+	return blockID, err == nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	err = os.MkdirAll(filepath.Dir(dst), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// tenantFromWALEntry returns the tenant of a WAL block file or folder. WAL blocks of all versions are named
+// <blockID>+<tenantID>+<version>[+...]. Returns "" for other entries.
+func tenantFromWALEntry(name string) string {
+	splits := strings.Split(name, "+")
+	if len(splits) < 3 {
+		return ""
+	}
+	if _, err := uuid.Parse(splits[0]); err != nil {
+		return ""
+	}
+	return splits[1]
+}